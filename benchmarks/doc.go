@@ -0,0 +1,32 @@
+// Package benchmarks holds reproducible benchmarks of the SDK, so that its
+// performance claims and regressions can be measured.
+//
+// The micro benchmarks need nothing but the Go toolchain: JSON serialization
+// of the API models, client-side validation, transaction fingerprinting and
+// the overhead of the worker pool and task group of pkg/concurrent. Run them
+// with:
+//
+//	go test -run '^$' -bench . -benchmem -count 5 ./benchmarks > new.txt
+//
+// or make bench, and compare the results with the published baseline, taken
+// on the machine described at its top, using benchstat
+// (golang.org/x/perf/cmd/benchstat):
+//
+//	benchstat benchmarks/baseline.txt new.txt
+//
+// Only the relative change between two runs on the same machine is
+// meaningful; rerun the baseline on your own machine, with make
+// bench-baseline, before comparing a change.
+//
+// The macro benchmarks, named BenchmarkMacro*, time real calls against a
+// Midaz stack, such as the local one of the Midaz repository. They are
+// skipped unless MIDAZ_BENCH_MACRO=true, and read the rest of their settings
+// from the usual MIDAZ_* environment variables:
+//
+//	MIDAZ_BENCH_MACRO=true MIDAZ_AUTH_TOKEN=... \
+//	    MIDAZ_ONBOARDING_URL=http://localhost:3000/v1 MIDAZ_TRANSACTION_URL=http://localhost:3001/v1 \
+//	    go test -run '^$' -bench Macro -benchtime 200x ./benchmarks
+//
+// They create an organization, a ledger, an asset and two accounts, then
+// delete what they can at the end.
+package benchmarks
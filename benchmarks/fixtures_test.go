@@ -0,0 +1,85 @@
+package benchmarks
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+)
+
+// transferInput returns the input of a transfer between two accounts with
+// some metadata, the typical payload of a payment service.
+func transferInput(i int) *models.CreateTransactionInput {
+	value := models.AmountInput{Asset: "USD", Value: "125.50"}
+
+	return &models.CreateTransactionInput{
+		Description: fmt.Sprintf("Invoice %d", i),
+		Amount:      "125.50",
+		AssetCode:   "USD",
+		Metadata:    map[string]any{"invoice": fmt.Sprintf("INV-%06d", i), "channel": "web", "customerId": 4821},
+		Send: &models.SendInput{
+			Asset:      "USD",
+			Value:      "125.50",
+			Source:     &models.SourceInput{From: []models.FromToInput{{Account: "@customer", Amount: value}}},
+			Distribute: &models.DistributeInput{To: []models.FromToInput{{Account: "@merchant", Amount: value}}},
+		},
+	}
+}
+
+// transaction returns a transaction as returned by the API, with its two
+// operations.
+func transaction() models.Transaction {
+	at := time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)
+	operation := func(id, alias, kind string) models.Operation {
+		return models.Operation{
+			ID:           id,
+			AccountAlias: alias,
+			Type:         kind,
+			AssetCode:    "USD",
+			CreatedAt:    at,
+			UpdatedAt:    at,
+		}
+	}
+
+	return models.Transaction{
+		ID:             "0195b1a2-7c3d-7e4f-8a9b-0c1d2e3f4a5b",
+		OrganizationID: "0195b1a2-0000-7000-8000-000000000001",
+		LedgerID:       "0195b1a2-0000-7000-8000-000000000002",
+		Description:    "Invoice 1",
+		AssetCode:      "USD",
+		Status:         models.Status{Code: "APPROVED"},
+		Metadata:       map[string]any{"invoice": "INV-000001", "channel": "web"},
+		Operations:     []models.Operation{operation("op-1", "@customer", "DEBIT"), operation("op-2", "@merchant", "CREDIT")},
+		CreatedAt:      at,
+		UpdatedAt:      at,
+	}
+}
+
+// accountPage returns a page of n accounts as returned by the API.
+func accountPage(n int) []byte {
+	at := time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)
+	page := models.ListResponse[models.Account]{Pagination: models.Pagination{Limit: n, Total: n}}
+
+	for i := range n {
+		alias := fmt.Sprintf("@customer-%d", i)
+		page.Items = append(page.Items, models.Account{
+			ID:        fmt.Sprintf("0195b1a2-0000-7000-8000-%012d", i),
+			Name:      fmt.Sprintf("Customer %d", i),
+			AssetCode: "USD",
+			Alias:     &alias,
+			Type:      "deposit",
+			Status:    models.Status{Code: "ACTIVE"},
+			Metadata:  map[string]any{"customerId": i},
+			CreatedAt: at,
+			UpdatedAt: at,
+		})
+	}
+
+	data, err := json.Marshal(page)
+	if err != nil {
+		panic(err)
+	}
+
+	return data
+}
@@ -0,0 +1,178 @@
+package benchmarks
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/config"
+)
+
+// envBenchMacro enables the macro benchmarks when set to "true".
+const envBenchMacro = "MIDAZ_BENCH_MACRO"
+
+// macroStack is the Midaz stack and the resources the macro benchmarks run
+// against.
+type macroStack struct {
+	c              *client.Client
+	orgID          string
+	ledgerID       string
+	customer       models.Account
+	merchant       models.Account
+	lastTransferID string
+}
+
+// newMacroStack connects to the stack of the environment and creates the
+// resources of the benchmarks, deleting them when b ends. It skips b unless
+// the macro benchmarks are enabled.
+func newMacroStack(b *testing.B) *macroStack {
+	b.Helper()
+
+	if os.Getenv(envBenchMacro) != "true" || testing.Short() {
+		b.Skipf("macro benchmarks need a Midaz stack; set %s=true to run them", envBenchMacro)
+	}
+
+	cfg, err := config.NewConfig(config.FromEnvironment())
+	if err != nil {
+		b.Fatalf("configuration failed: %v", err)
+	}
+
+	c, err := client.New(client.WithConfig(cfg), client.UseAllAPIs())
+	if err != nil {
+		b.Fatalf("client creation failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	s := &macroStack{c: c}
+	e := c.Entity
+
+	// a legal document unique to the run, as organizations may not share one
+	legalDocument := strconv.FormatInt(time.Now().UnixNano()%1e14, 10)
+
+	org, err := e.Organizations.CreateOrganization(ctx, models.NewCreateOrganizationInput("SDK Benchmarks").
+		WithLegalDocument(legalDocument).
+		WithAddress(models.Address{Country: "US"}).
+		WithStatus(models.NewStatus("ACTIVE")))
+	if err != nil {
+		b.Fatalf("failed to create the organization: %v", err)
+	}
+
+	s.orgID = org.ID
+	b.Cleanup(func() { logCleanup(b, e.Organizations.DeleteOrganization(context.Background(), s.orgID)) })
+
+	ledger, err := e.Ledgers.CreateLedger(ctx, s.orgID, models.NewCreateLedgerInput("Benchmarks"))
+	if err != nil {
+		b.Fatalf("failed to create the ledger: %v", err)
+	}
+
+	s.ledgerID = ledger.ID
+	b.Cleanup(func() { logCleanup(b, e.Ledgers.DeleteLedger(context.Background(), s.orgID, s.ledgerID)) })
+
+	asset, err := e.Assets.CreateAsset(ctx, s.orgID, s.ledgerID, models.NewCreateAssetInput("US Dollar", "USD").WithType("currency"))
+	if err != nil {
+		b.Fatalf("failed to create the asset: %v", err)
+	}
+
+	b.Cleanup(func() { logCleanup(b, e.Assets.DeleteAsset(context.Background(), s.orgID, s.ledgerID, asset.ID)) })
+
+	suffix := strconv.FormatInt(time.Now().UnixNano(), 36)
+
+	createAccount := func(role string) models.Account {
+		account, err := e.Accounts.CreateAccount(ctx, s.orgID, s.ledgerID,
+			models.NewCreateAccountInput("Benchmark "+role, "USD", "deposit").WithAlias("bench-"+suffix+"-"+role))
+		if err != nil {
+			b.Fatalf("failed to create the %s account: %v", role, err)
+		}
+
+		b.Cleanup(func() { logCleanup(b, e.Accounts.DeleteAccount(context.Background(), s.orgID, s.ledgerID, account.ID)) })
+
+		return *account
+	}
+
+	s.customer = createAccount("customer")
+	s.merchant = createAccount("merchant")
+
+	if _, err := e.Transactions.CreateTransaction(ctx, s.orgID, s.ledgerID, usdTransfer("@external/USD", models.GetAccountAlias(s.customer), "1000000")); err != nil {
+		b.Fatalf("failed to fund the customer account: %v", err)
+	}
+
+	return s
+}
+
+// logCleanup logs the failure to delete a resource.
+func logCleanup(b *testing.B, err error) {
+	if err != nil {
+		b.Logf("cleanup: %v", err)
+	}
+}
+
+// usdTransfer returns the input of a transaction moving amount of USD between
+// two aliases.
+func usdTransfer(from, to, amount string) *models.CreateTransactionInput {
+	value := models.AmountInput{Asset: "USD", Value: amount}
+
+	return &models.CreateTransactionInput{
+		Description: "SDK benchmark",
+		Amount:      amount,
+		AssetCode:   "USD",
+		Send: &models.SendInput{
+			Asset:      "USD",
+			Value:      amount,
+			Source:     &models.SourceInput{From: []models.FromToInput{{Account: from, AccountAlias: from, Amount: value}}},
+			Distribute: &models.DistributeInput{To: []models.FromToInput{{Account: to, AccountAlias: to, Amount: value}}},
+		},
+	}
+}
+
+// BenchmarkMacro measures the round trip of common calls against a Midaz
+// stack, serialization, validation, retries and the network included.
+func BenchmarkMacro(b *testing.B) {
+	s := newMacroStack(b)
+	e := s.c.Entity
+	ctx := context.Background()
+
+	b.Run("CreateTransaction", func(b *testing.B) {
+		for b.Loop() {
+			tx, err := e.Transactions.CreateTransaction(ctx, s.orgID, s.ledgerID, usdTransfer(models.GetAccountAlias(s.customer), models.GetAccountAlias(s.merchant), "0.01"))
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			s.lastTransferID = tx.ID
+		}
+	})
+
+	b.Run("GetAccount", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := e.Accounts.GetAccount(ctx, s.orgID, s.ledgerID, s.customer.ID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("GetTransaction", func(b *testing.B) {
+		if s.lastTransferID == "" {
+			b.Skip("no transaction created")
+		}
+
+		for b.Loop() {
+			if _, err := e.Transactions.GetTransaction(ctx, s.orgID, s.ledgerID, s.lastTransferID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("ListAccounts", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := e.Accounts.ListAccounts(ctx, s.orgID, s.ledgerID, models.NewListOptions().WithLimit(10)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
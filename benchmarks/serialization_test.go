@@ -0,0 +1,87 @@
+package benchmarks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/performance"
+)
+
+// BenchmarkMarshalCreateTransactionInput measures the encoding of a
+// transaction request body, with encoding/json and the pooled encoder the
+// HTTP client uses.
+func BenchmarkMarshalCreateTransactionInput(b *testing.B) {
+	input := transferInput(1)
+
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			if _, err := json.Marshal(input); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("JSONPool", func(b *testing.B) {
+		pool := performance.NewJSONPool()
+
+		b.ReportAllocs()
+
+		for b.Loop() {
+			if _, err := pool.Marshal(input); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkUnmarshalTransaction measures the decoding of a transaction
+// response with its operations.
+func BenchmarkUnmarshalTransaction(b *testing.B) {
+	data, err := json.Marshal(transaction())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	for b.Loop() {
+		var tx models.Transaction
+		if err := json.Unmarshal(data, &tx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkUnmarshalAccountPage measures the decoding of a full page of
+// accounts, the unit of work of list calls and exports.
+func BenchmarkUnmarshalAccountPage(b *testing.B) {
+	data := accountPage(models.MaxLimit)
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	for b.Loop() {
+		var page models.ListResponse[models.Account]
+		if err := json.Unmarshal(data, &page); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkFingerprint measures the canonicalization and hashing of a
+// transaction input, run for each input by the dedup helpers.
+func BenchmarkFingerprint(b *testing.B) {
+	input := transferInput(1)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if models.Fingerprint(input) == "" {
+			b.Fatal("empty fingerprint")
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package benchmarks
+
+import (
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+)
+
+// BenchmarkValidateCreateTransactionInput measures the client-side
+// validation run before a transaction is sent.
+func BenchmarkValidateCreateTransactionInput(b *testing.B) {
+	input := transferInput(1)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if err := input.Validate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkValidateMetadata measures the check of the metadata of a request
+// body against the limits of the backend.
+func BenchmarkValidateMetadata(b *testing.B) {
+	metadata := transferInput(1).Metadata
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if err := validation.ValidateMetadata(metadata); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkValidateTransactionBatch measures the validation of a batch of
+// transactions before a bulk import, per batch of 1000.
+func BenchmarkValidateTransactionBatch(b *testing.B) {
+	inputs := make([]*models.CreateTransactionInput, 1000)
+	for i := range inputs {
+		inputs[i] = transferInput(i)
+	}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if report := validation.ValidateTransactionBatch(inputs); !report.Valid() {
+			b.Fatal(report)
+		}
+	}
+}
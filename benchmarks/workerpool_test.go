@@ -0,0 +1,74 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
+)
+
+// poolItems is the number of items of each run of the pool benchmarks.
+const poolItems = 1000
+
+// noopWork is work taking no time, so that the benchmarks measure the
+// overhead of the pool alone.
+func noopWork(_ context.Context, item int) (int, error) {
+	return item, nil
+}
+
+// BenchmarkWorkerPoolOverhead measures the cost of dispatching 1000 items
+// doing no work through WorkerPool, against a plain loop, by worker count.
+func BenchmarkWorkerPoolOverhead(b *testing.B) {
+	items := make([]int, poolItems)
+	for i := range items {
+		items[i] = i
+	}
+
+	b.Run("Sequential", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			for _, item := range items {
+				_, _ = noopWork(context.Background(), item)
+			}
+		}
+	})
+
+	for _, workers := range []int{1, 8, 64} {
+		b.Run(fmt.Sprintf("Workers%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				results := concurrent.WorkerPool(context.Background(), items, noopWork,
+					concurrent.WithWorkers(workers), concurrent.WithBufferSize(poolItems))
+				if len(results) != poolItems {
+					b.Fatalf("got %d results", len(results))
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkGroupOverhead measures the cost of running 1000 tasks doing no
+// work through a Group, by limit.
+func BenchmarkGroupOverhead(b *testing.B) {
+	for _, limit := range []int{8, 64} {
+		b.Run(fmt.Sprintf("Limit%d", limit), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				g, _ := concurrent.NewGroup(context.Background())
+				g.SetLimit(limit)
+
+				for range poolItems {
+					g.Go(func() error { return nil })
+				}
+
+				if err := g.Wait(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
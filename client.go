@@ -8,14 +8,21 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/config"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/cost"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/quota"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/usage"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation/core"
 )
 
 // Version is the current version of the SDK.
@@ -33,9 +40,78 @@ type Client struct {
 	// Optional API interfaces
 	Entity *entities.Entity
 
+	// ReadOnly is the read-only view of the Entity API. It is set whenever the
+	// Entity API is enabled, and is the only view set by UseReadOnlyAPIs.
+	ReadOnly *entities.ReadOnlyEntity
+
+	// entity is the Entity API, also when UseReadOnlyAPIs hides it
+	entity *entities.Entity
+
 	// API interface flags
 	useEntity bool
 
+	// services selects the Entity services to initialize; zero means all of them
+	services entities.ServiceSet
+
+	// readOnlyView hides the Entity, leaving only the ReadOnly view
+	readOnlyView bool
+
+	// readOnly makes the Entity API reject mutating calls
+	readOnly bool
+
+	// payloadLimits bound the request bodies of the Entity API
+	payloadLimits entities.PayloadLimits
+
+	// capturedHeaders are the response headers captured into entities.ResponseMeta
+	capturedHeaders []string
+
+	// experimental are the experimental features enabled on the Entity API
+	experimental []string
+
+	// usage tallies the operations called through the Entity API
+	usage *usage.Recorder
+
+	// metadataTemplate is applied to the transactions created through the Entity API
+	metadataTemplate *entities.MetadataTemplate
+
+	// costHook receives the cost of the calls made through the Entity API
+	costHook cost.Hook
+
+	// quota checks the calls made through the Entity API against tenant budgets, see WithTenantQuotas
+	quota *quota.Enforcer
+
+	// maintenance holds the calls made through the Entity API during maintenance windows, see WithMaintenanceSchedule
+	maintenance *entities.MaintenanceSchedule
+
+	// retryJournal records the Entity API calls given up on after their retries, see WithRetryJournal
+	retryJournal entities.RetryJournal
+
+	// validationWarnings receives the validation failures of lenient mode
+	validationWarnings entities.ValidationWarningFunc
+
+	// errorLog and activityLog record the requests of the Entity API,
+	// see WithErrorLog and WithActivityLog
+	errorLog    *entities.ErrorLog
+	activityLog *entities.ActivityLog
+
+	// connectionTrace records connection timings, see WithConnectionDebug
+	connectionTrace *entities.ConnectionTrace
+
+	// shadowTarget duplicates read requests to a second environment, see WithShadowTarget
+	shadowTarget *entities.ShadowConfig
+
+	// schemaDrift checks responses against the models, see WithSchemaDriftCheck
+	schemaDrift *entities.SchemaDriftOptions
+
+	// unknownFields is the number of resources whose unknown fields are sent back on updates, see WithUnknownFieldRoundTrip
+	unknownFields *int
+
+	// timeZone is the zone of the response times, see WithTimeZone
+	timeZone *time.Location
+
+	// balanceCacheTTL is the ttl of the balance cache of the Entity, see WithBalanceCache
+	balanceCacheTTL *time.Duration
+
 	// tenantID is the default tenant identifier sent as X-Tenant-ID on every request.
 	// Per-request overrides via entities.WithTenantID(ctx, id) take precedence.
 	tenantID string
@@ -46,6 +122,9 @@ type Client struct {
 	// Observability provider
 	observability observability.Provider
 	metrics       *observability.MetricsCollector
+
+	// spanEnricher is set on the observability provider once configured, see WithSpanEnricher
+	spanEnricher observability.SpanEnricher
 }
 
 // New creates a new Midaz client with the provided options.
@@ -76,11 +155,30 @@ func New(options ...Option) (*Client, error) {
 		}
 	}
 
+	if c.config.ProbeTimeout > 0 {
+		if err := c.config.Probe(c.ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.spanEnricher != nil {
+		if err := c.UpdateObservability(observability.WithSpanEnricher(c.spanEnricher)); err != nil {
+			return nil, fmt.Errorf("error setting span enricher: %w", err)
+		}
+	}
+
 	// Create API interfaces if enabled
 	if c.useEntity {
 		if err := c.setupEntity(); err != nil {
 			return nil, fmt.Errorf("error setting up Entity API: %w", err)
 		}
+
+		c.entity = c.Entity
+		c.ReadOnly = c.Entity.ReadOnly()
+
+		if c.readOnlyView {
+			c.Entity = nil
+		}
 	}
 
 	return c, nil
@@ -91,6 +189,22 @@ type Option func(*Client) error
 
 // setupEntity creates the Entity API interface.
 func (c *Client) setupEntity() error {
+	options, err := c.entityOptions()
+	if err != nil {
+		return err
+	}
+
+	// Add plugin auth if enabled
+	pluginAuth := c.config.GetPluginAuth()
+	if pluginAuth.Enabled {
+		options = append(options, entities.WithPluginAuth(pluginAuth))
+	}
+
+	return c.newEntity(options)
+}
+
+// newEntity creates the Entity API interface with options.
+func (c *Client) newEntity(options []entities.Option) error {
 	// Get service URLs from config
 	serviceURLs := c.config.GetBaseURLs()
 
@@ -103,24 +217,19 @@ func (c *Client) setupEntity() error {
 		return errors.New("missing transaction URL in config")
 	}
 
-	// Custom retry policy if enabled
-	var retryOptions *retry.Options
-	if c.config.EnableRetries {
-		retryOptions = retry.DefaultOptions()
-
-		if err := retry.WithMaxRetries(c.config.MaxRetries)(retryOptions); err != nil {
-			return fmt.Errorf("failed to set max retries: %w", err)
-		}
+	entity, err := entities.NewWithServiceURLs(serviceURLs, options...)
+	if err != nil {
+		return err
+	}
 
-		if err := retry.WithInitialDelay(c.config.RetryWaitMin)(retryOptions); err != nil {
-			return fmt.Errorf("failed to set initial delay: %w", err)
-		}
+	c.Entity = entity
 
-		if err := retry.WithMaxDelay(c.config.RetryWaitMax)(retryOptions); err != nil {
-			return fmt.Errorf("failed to set max delay: %w", err)
-		}
-	}
+	return nil
+}
 
+// entityOptions returns the options of the Entity API built from the client
+// settings, except authentication.
+func (c *Client) entityOptions() ([]entities.Option, error) {
 	// Create the entity API with service-specific URLs
 	options := []entities.Option{
 		entities.WithObservability(c.observability),
@@ -141,20 +250,154 @@ func (c *Client) setupEntity() error {
 		options = append(options, entities.WithDefaultTenantID(tenantID))
 	}
 
-	// Add plugin auth if enabled
-	pluginAuth := c.config.GetPluginAuth()
-	if pluginAuth.Enabled {
-		options = append(options, entities.WithPluginAuth(pluginAuth))
+	if c.services != 0 {
+		options = append(options, entities.WithServices(c.services))
 	}
 
-	entity, err := entities.NewWithServiceURLs(serviceURLs, options...)
-	if err != nil {
-		return err
+	if c.readOnly {
+		options = append(options, entities.WithReadOnly(true))
 	}
 
-	c.Entity = entity
+	if c.payloadLimits != (entities.PayloadLimits{}) {
+		options = append(options, entities.WithPayloadLimits(c.payloadLimits))
+	}
 
-	return nil
+	if len(c.capturedHeaders) > 0 {
+		options = append(options, entities.WithCapturedHeaders(c.capturedHeaders...))
+	}
+
+	if len(c.experimental) > 0 {
+		options = append(options, entities.WithExperimental(c.experimental...))
+	}
+
+	if c.usage != nil {
+		options = append(options, entities.WithUsageRecorder(c.usage))
+	}
+
+	if c.metadataTemplate != nil {
+		options = append(options, entities.WithTransactionMetadata(c.metadataTemplate))
+	}
+
+	if c.costHook != nil {
+		options = append(options, entities.WithCostHook(c.costHook))
+	}
+
+	if c.quota != nil {
+		options = append(options, entities.WithQuotaEnforcer(c.quota))
+	}
+
+	if c.maintenance != nil {
+		options = append(options, entities.WithMaintenanceSchedule(c.maintenance))
+	}
+
+	if c.retryJournal != nil {
+		options = append(options, entities.WithRetryJournal(c.retryJournal))
+	}
+
+	if c.config.ValidationMode != "" || c.validationWarnings != nil {
+		options = append(options, entities.WithValidationPolicy(entities.ValidationPolicy{
+			Mode:      c.config.ValidationMode,
+			OnWarning: c.validationWarnings,
+		}))
+	}
+
+	if mode := c.config.ValidationMode; mode == validation.ModeStrict || mode == validation.ModeLenient {
+		validationOptions := []core.ValidationOption{core.WithBackendMetadataLimits()}
+		if c.config.MaxMetadataKeys > 0 {
+			validationOptions = append(validationOptions, core.WithMaxMetadataKeys(c.config.MaxMetadataKeys))
+		}
+
+		validator, err := validation.NewValidator(validationOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create metadata validator: %w", err)
+		}
+
+		options = append(options, entities.WithMetadataValidator(validator))
+	}
+
+	deadlines := entities.DefaultDeadlines{
+		Read:  c.config.ReadDeadline,
+		Write: c.config.WriteDeadline,
+		List:  c.config.ListDeadline,
+	}
+	if deadlines != (entities.DefaultDeadlines{}) {
+		options = append(options, entities.WithDefaultDeadlines(deadlines))
+	}
+
+	if c.config.SlowCallThreshold > 0 {
+		options = append(options, entities.WithSlowCallThreshold(c.config.SlowCallThreshold))
+	}
+
+	if c.errorLog != nil {
+		options = append(options, entities.WithErrorLog(c.errorLog))
+	}
+
+	// The configured HTTP client carries the settings of config.WithHTTPClient,
+	// such as its redirect policy, resolver and transport, and the dialer of
+	// config.WithDialer or config.WithUnixSocket
+	if c.config.HTTPClient != nil {
+		httpClient, err := c.config.HTTPClientWithDialer()
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, entities.WithHTTPClient(httpClient))
+	}
+
+	if c.activityLog != nil {
+		options = append(options, entities.WithActivityLog(c.activityLog))
+	}
+
+	if c.config.DebugConnections {
+		c.connectionTrace = entities.NewConnectionTrace(0).WithLogger(c.connectionLogf)
+		options = append(options, entities.WithConnectionTrace(c.connectionTrace))
+	}
+
+	if c.shadowTarget != nil {
+		options = append(options, entities.WithShadowTarget(*c.shadowTarget))
+	}
+
+	if c.schemaDrift != nil {
+		options = append(options, entities.WithSchemaDriftCheck(*c.schemaDrift))
+	}
+
+	if c.unknownFields != nil {
+		options = append(options, entities.WithUnknownFieldRoundTrip(*c.unknownFields))
+	}
+
+	if c.timeZone != nil {
+		options = append(options, entities.WithTimeZone(c.timeZone))
+	}
+
+	if c.balanceCacheTTL != nil {
+		options = append(options, entities.WithBalanceCache(*c.balanceCacheTTL))
+	}
+
+	return options, nil
+}
+
+// retryOptions returns the retry policy of the configuration.
+func (c *Client) retryOptions() (*retry.Options, error) {
+	options := retry.DefaultOptions()
+
+	maxRetries := c.config.MaxRetries
+	if !c.config.EnableRetries {
+		maxRetries = 0
+	}
+
+	if err := retry.WithMaxRetries(maxRetries)(options); err != nil {
+		return nil, fmt.Errorf("failed to set max retries: %w", err)
+	}
+
+	if err := retry.WithInitialDelay(c.config.RetryWaitMin)(options); err != nil {
+		return nil, fmt.Errorf("failed to set initial delay: %w", err)
+	}
+
+	if err := retry.WithMaxDelay(c.config.RetryWaitMax)(options); err != nil {
+		return nil, fmt.Errorf("failed to set max delay: %w", err)
+	}
+
+	return options, nil
 }
 
 // WithBaseURL sets the base URL for API requests.
@@ -466,6 +709,9 @@ func WithContext(ctx context.Context) Option {
 func UseAllAPIs() Option {
 	return func(c *Client) error {
 		c.useEntity = true
+		c.services = 0
+		c.readOnlyView = false
+
 		return nil
 	}
 }
@@ -482,6 +728,55 @@ func UseEntityAPI() Option {
 	}
 }
 
+// UseServices enables the Entity API with only the given services.
+// The services left out are nil on client.Entity, so programs that use a few
+// services don't set up clients for the whole API.
+//
+// Parameters:
+//   - services: The services to enable, e.g. entities.ServiceTransactions|entities.ServiceBalances
+//
+// Returns:
+//   - Option: A function that enables the selected services on the Client
+func UseServices(services entities.ServiceSet) Option {
+	return func(c *Client) error {
+		if services == 0 || services&^entities.AllServices != 0 {
+			return errors.New("invalid service set")
+		}
+
+		c.useEntity = true
+		c.services = services
+
+		return nil
+	}
+}
+
+// UseTransactionsOnly enables the Entity API with only the services needed
+// to submit transactions: transactions, operations and balances.
+//
+// Returns:
+//   - Option: A function that enables the transaction services on the Client
+func UseTransactionsOnly() Option {
+	return UseServices(entities.TransactionServices)
+}
+
+// UseReadOnlyAPIs enables the read-only view of the Entity API.
+// client.Entity stays nil and client.ReadOnly exposes only the List and Get
+// methods of the services, so the program can't modify the ledger.
+// It implies WithReadOnly(true), and can be combined with UseServices to
+// restrict the services as well.
+//
+// Returns:
+//   - Option: A function that enables the read-only APIs on the Client
+func UseReadOnlyAPIs() Option {
+	return func(c *Client) error {
+		c.useEntity = true
+		c.readOnlyView = true
+		c.readOnly = true
+
+		return nil
+	}
+}
+
 // WithConfig sets a custom configuration for the client.
 // This allows for using a pre-configured Config object instead of individual options.
 //
@@ -512,13 +807,7 @@ func WithConfig(cfg *config.Config) Option {
 //   - Option: A function that sets the HTTP client on the Client
 func WithHTTPClient(client *http.Client) Option {
 	return func(c *Client) error {
-		if client == nil {
-			return errors.New("HTTP client cannot be nil")
-		}
-
-		c.config.HTTPClient = client
-
-		return nil
+		return config.WithHTTPClient(client)(c.config)
 	}
 }
 
@@ -564,6 +853,38 @@ func WithDebug(enable bool) Option {
 	}
 }
 
+// WithConnectionDebug enables or disables connection tracing.
+// Each request of the Entity API logs its DNS, connect, TLS and first byte
+// times and whether it reused a connection, and ConnectionStats aggregates
+// them. It can also be enabled with MIDAZ_DEBUG_CONNECTIONS=true.
+//
+// Parameters:
+//   - enable: Whether to trace connections
+//
+// Returns:
+//   - Option: A function that sets the connection debug flag on the Client
+func WithConnectionDebug(enable bool) Option {
+	return func(c *Client) error {
+		return config.WithConnectionDebug(enable)(c.config)
+	}
+}
+
+// WithConnectivityProbe makes New check that the onboarding and transaction
+// services can be connected to, failing with a config.UnreachableError such
+// as "onboarding URL unreachable: dial tcp ..." instead of on the first
+// request. See config.WithConnectivityProbe.
+//
+// Parameters:
+//   - timeout: The time allowed to each service, config.DefaultProbeTimeout if 0
+//
+// Returns:
+//   - Option: A function that enables the probe on the Client
+func WithConnectivityProbe(timeout time.Duration) Option {
+	return func(c *Client) error {
+		return config.WithConnectivityProbe(timeout)(c.config)
+	}
+}
+
 // WithTenantID sets the default tenant ID for all API requests made through this client.
 // The tenant ID is sent as the X-Tenant-ID header on every request.
 // Per-request overrides via entities.WithTenantID(ctx, tenantID) take precedence
@@ -583,6 +904,396 @@ func WithTenantID(tenantID string) Option {
 	}
 }
 
+// WithReadOnly enables or disables read-only mode.
+// In read-only mode, every call that could modify the ledger (creating,
+// updating or deleting resources, submitting transactions) fails with an
+// entities.ReadOnlyError before any request is sent. It is a safety rail for
+// reporting and analytics services holding production credentials.
+//
+// Parameters:
+//   - readOnly: Whether to reject mutating calls
+//
+// Returns:
+//   - Option: A function that sets read-only mode on the Client
+func WithReadOnly(readOnly bool) Option {
+	return func(c *Client) error {
+		c.readOnly = readOnly
+
+		return nil
+	}
+}
+
+// WithPayloadLimits sets the limits request bodies are checked against
+// before they are sent. A request exceeding them fails with an
+// entities.PayloadTooLargeError naming the offending field, such as an
+// oversized metadata value, instead of a round trip ending in a server
+// rejection. entities.ServerPayloadLimits returns the limits the Midaz API is
+// known to enforce.
+//
+// Parameters:
+//   - limits: The limits to check request bodies against
+//
+// Returns:
+//   - Option: A function that sets the payload limits on the Client
+func WithPayloadLimits(limits entities.PayloadLimits) Option {
+	return func(c *Client) error {
+		c.payloadLimits = limits
+
+		return nil
+	}
+}
+
+// WithCapturedHeaders sets the response headers captured into the metadata
+// of calls made with a context from entities.WithResultMeta, instead of
+// entities.DefaultCapturedHeaders (request ID, rate limit information and
+// server timing). The metadata is read back with entities.ResultMeta.
+//
+// Parameters:
+//   - headers: The names of the response headers to capture
+//
+// Returns:
+//   - Option: A function that sets the captured headers on the Client
+func WithCapturedHeaders(headers ...string) Option {
+	return func(c *Client) error {
+		c.capturedHeaders = headers
+
+		return nil
+	}
+}
+
+// WithShadowTarget duplicates a sample of the read requests of the Entity
+// API to a second environment and compares the responses asynchronously,
+// reporting divergences to cfg.OnDivergence and counting them in
+// c.Entity.Shadow().Stats(). It is meant to validate a new Midaz version
+// before a cutover: primary responses are returned unchanged and never wait
+// for the shadow ones, and writes are never duplicated.
+//
+// Example:
+//
+//	c, err := client.New(client.UseEntityAPI(), client.WithShadowTarget(entities.ShadowConfig{
+//	    BaseURLs:     map[string]string{"onboarding": "https://onboarding.next.example.com/v1", "transaction": "https://transaction.next.example.com/v1"},
+//	    SampleRate:   0.1,
+//	    IgnoreFields: []string{"updatedAt"},
+//	    OnDivergence: func(d entities.ShadowDivergence) { log.Printf("%s diverges at %v", d.Operation, d.Differences) },
+//	}))
+//
+// Parameters:
+//   - cfg: The shadow environment, the sample rate and the comparison settings
+//
+// Returns:
+//   - Option: A function that sets the shadow target on the Client
+func WithShadowTarget(cfg entities.ShadowConfig) Option {
+	return func(c *Client) error {
+		if len(cfg.BaseURLs) == 0 {
+			return errors.New("shadow target requires at least one base URL")
+		}
+
+		c.shadowTarget = &cfg
+
+		return nil
+	}
+}
+
+// WithSchemaDriftCheck compares the JSON fields of every Entity API response
+// with the SDK model it is decoded into and reports the fields the model
+// doesn't know, whose values would otherwise be dropped silently, to
+// opts.OnDrift. The drifting fields are also collected in
+// c.Entity.SchemaDrift(). Each response is decoded twice, so enable the
+// check in tests and staging rather than on every production client.
+//
+// Example:
+//
+//	c, err := client.New(client.UseEntityAPI(), client.WithSchemaDriftCheck(entities.SchemaDriftOptions{
+//	    PreserveUnknown: true,
+//	    OnDrift:         func(d entities.SchemaDrift) { log.Printf("%s returned unknown fields %v", d.Operation, d.Unknown) },
+//	}))
+//
+// Parameters:
+//   - opts: Where to report drift and whether to keep unknown values and report missing fields
+//
+// Returns:
+//   - Option: A function that enables the schema drift check on the Client
+func WithSchemaDriftCheck(opts entities.SchemaDriftOptions) Option {
+	return func(c *Client) error {
+		c.schemaDrift = &opts
+
+		return nil
+	}
+}
+
+// WithUnknownFieldRoundTrip keeps the response fields that the SDK models
+// don't know yet, for the last size resources read or updated through the
+// Entity API, and sends them back on the updates of the same resource. It
+// prevents an update from clobbering fields added by a newer backend, which
+// the SDK would otherwise drop. Values set in the update input always win,
+// and fields inside arrays are not kept.
+//
+// Parameters:
+//   - size: The number of resources kept, entities.DefaultUnknownFieldStoreSize if not positive
+//
+// Returns:
+//   - Option: A function that enables the unknown field round trip on the Client
+func WithUnknownFieldRoundTrip(size int) Option {
+	return func(c *Client) error {
+		c.unknownFields = &size
+
+		return nil
+	}
+}
+
+// WithTimeZone moves the times of the Entity API responses, such as
+// CreatedAt, to loc instead of keeping the offset returned by the API,
+// usually UTC.
+//
+// Example:
+//
+//	loc, _ := time.LoadLocation("America/Sao_Paulo")
+//	c, err := client.New(client.UseEntityAPI(), client.WithTimeZone(loc))
+//
+// Parameters:
+//   - loc: The time zone of the response times
+//
+// Returns:
+//   - Option: A function that sets the time zone on the Client
+func WithTimeZone(loc *time.Location) Option {
+	return func(c *Client) error {
+		if loc == nil {
+			return errors.New("time zone is required")
+		}
+
+		c.timeZone = loc
+
+		return nil
+	}
+}
+
+// WithBalanceCache gives the Entity a balance cache, returned by
+// Entity.BalanceCache, keeping the default balance of accounts by account and
+// asset for ttl. The transactions submitted through the client invalidate the
+// balances they move, so that a read following a write never returns the
+// balance from before it.
+//
+// Example:
+//
+//	c, err := client.New(client.UseEntityAPI(), client.WithBalanceCache(time.Minute))
+//	...
+//	balance, err := c.Entity.BalanceCache().Get(ctx, orgID, ledgerID, accountID, "USD")
+//
+// Parameters:
+//   - ttl: How long a balance is kept; zero uses entities.DefaultBalanceCacheTTL
+//     and a negative ttl keeps balances until they are invalidated
+//
+// Returns:
+//   - Option: A function that sets the balance cache on the Client
+func WithBalanceCache(ttl time.Duration) Option {
+	return func(c *Client) error {
+		c.balanceCacheTTL = &ttl
+
+		return nil
+	}
+}
+
+// EnableExperimental opts into experimental features of the Entity API, listed
+// by entities.ExperimentalFeatures. Their endpoints may still change on the
+// Midaz side, so calls belonging to a feature not enabled fail with an
+// entities.ExperimentalError before any request is sent. The option can be
+// given several times; unknown feature names make New fail.
+//
+// Parameters:
+//   - features: The names of the experimental features, see entities.ExperimentalFeatures
+//
+// Returns:
+//   - Option: A function that enables the experimental features on the Client
+func EnableExperimental(features ...string) Option {
+	return func(c *Client) error {
+		known := entities.ExperimentalFeatures()
+
+		for _, feature := range features {
+			if !slices.Contains(known, feature) {
+				return fmt.Errorf("unknown experimental feature %q, known features: %v", feature, known)
+			}
+		}
+
+		c.experimental = append(c.experimental, features...)
+
+		return nil
+	}
+}
+
+// WithUsageRecorder tallies the operations the application calls through the
+// Entity API into recorder: the method and path template of each endpoint,
+// with its calls, failures and status codes. Payloads, IDs and query strings
+// are not recorded, and nothing leaves the process; export the tallies with
+// recorder.WriteJSON.
+//
+// Parameters:
+//   - recorder: The recorder receiving the calls, created with usage.NewRecorder
+//
+// Returns:
+//   - Option: A function that sets the usage recorder on the Client
+func WithUsageRecorder(recorder *usage.Recorder) Option {
+	return func(c *Client) error {
+		if recorder == nil {
+			return errors.New("usage recorder cannot be nil")
+		}
+
+		c.usage = recorder
+
+		return nil
+	}
+}
+
+// WithCostHook reports the cost of every call made through the Entity API to
+// hook: the HTTP requests sent, retries included, the bytes sent and received,
+// and the response headers carrying compute hints, along with the tenant and
+// the use case of the call. A cost.Accountant aggregates them per tenant, use
+// case and operation for chargeback.
+//
+// Parameters:
+//   - hook: The hook receiving the costs, such as a cost.Accountant
+//
+// Returns:
+//   - Option: A function that sets the cost hook on the Client
+func WithCostHook(hook cost.Hook) Option {
+	return func(c *Client) error {
+		if hook == nil {
+			return errors.New("cost hook cannot be nil")
+		}
+
+		c.costHook = hook
+
+		return nil
+	}
+}
+
+// WithTenantQuotas checks every call made through the Entity API against the
+// budget of its tenant in enforcer, its X-Tenant-ID: the calls per second and
+// the transactions created per day. Calls over budget fail with a
+// *quota.ExceededError before any request is sent, so that one tenant of a
+// platform can't exhaust the capacity of a shared Midaz deployment.
+//
+// Parameters:
+//   - enforcer: The enforcer holding the budgets, created with quota.NewEnforcer
+//
+// Returns:
+//   - Option: A function that sets the quota enforcer on the Client
+func WithTenantQuotas(enforcer *quota.Enforcer) Option {
+	return func(c *Client) error {
+		if enforcer == nil {
+			return errors.New("quota enforcer cannot be nil")
+		}
+
+		c.quota = enforcer
+
+		return nil
+	}
+}
+
+// WithMaintenanceSchedule holds the calls made through the Entity API during
+// the maintenance windows of schedule, configured up front or refreshed from
+// a status endpoint with schedule.Watch. Interactive calls fail with an
+// *entities.MaintenanceError before any request is sent; calls whose context
+// is marked with entities.WithMaintenanceWait, such as those of
+// transaction.BatchTransactions, wait for the window to end and then proceed.
+//
+// Parameters:
+//   - schedule: The maintenance windows, created with entities.NewMaintenanceSchedule
+//
+// Returns:
+//   - Option: A function that sets the maintenance schedule on the Client
+func WithMaintenanceSchedule(schedule *entities.MaintenanceSchedule) Option {
+	return func(c *Client) error {
+		if schedule == nil {
+			return errors.New("maintenance schedule cannot be nil")
+		}
+
+		c.maintenance = schedule
+
+		return nil
+	}
+}
+
+// WithRetryJournal records in journal the Entity API calls that fail with an
+// error retrying could have fixed, such as a timeout or a 503, once their
+// retries are used up: their input, attempts, errors and hints on what to
+// check. Once the incident is resolved, Entity.Redrive sends them again.
+//
+// Parameters:
+//   - journal: The journal, such as entities.NewJSONLinesRetryJournal(f)
+//
+// Returns:
+//   - Option: A function that sets the retry journal on the Client
+func WithRetryJournal(journal entities.RetryJournal) Option {
+	return func(c *Client) error {
+		if journal == nil {
+			return errors.New("retry journal cannot be nil")
+		}
+
+		c.retryJournal = journal
+
+		return nil
+	}
+}
+
+// WithTransactionMetadata applies tpl to the metadata of every transaction
+// created through the Entity API, so that the transactions of all the teams
+// sharing a client configuration carry the same tags. Keys already set on a
+// transaction are kept unless the template overrides them.
+//
+// Parameters:
+//   - tpl: The template, created with entities.NewMetadataTemplate
+//
+// Returns:
+//   - Option: A function that sets the transaction metadata template on the Client
+func WithTransactionMetadata(tpl *entities.MetadataTemplate) Option {
+	return func(c *Client) error {
+		if tpl == nil {
+			return errors.New("metadata template cannot be nil")
+		}
+
+		c.metadataTemplate = tpl
+
+		return nil
+	}
+}
+
+// WithValidationWarningHandler sets the function receiving the validation
+// failures of the requests sent anyway in lenient mode, set with
+// config.WithValidationMode or per call with entities.WithValidationMode.
+// Without it, they are written to stderr.
+//
+// Parameters:
+//   - handler: The function receiving lenient validation failures
+//
+// Returns:
+//   - Option: A function that sets the validation warning handler on the Client
+func WithValidationWarningHandler(handler entities.ValidationWarningFunc) Option {
+	return func(c *Client) error {
+		c.validationWarnings = handler
+
+		return nil
+	}
+}
+
+// WithSpanEnricher sets a function called for every span the SDK starts, so
+// domain attributes such as a tenant or product can be attached to all of
+// them without wrapping each call in a manual span. The operation is the span
+// name, such as "HTTP GET /v1/organizations". It applies to the client's
+// observability provider, whichever option configures it.
+//
+// Parameters:
+//   - enricher: The function called with the context, operation and span
+//
+// Returns:
+//   - Option: A function that sets the span enricher on the Client
+func WithSpanEnricher(enricher observability.SpanEnricher) Option {
+	return func(c *Client) error {
+		c.spanEnricher = enricher
+
+		return nil
+	}
+}
+
 // UseEntity enables the Entity API interface.
 // This is an alias for UseEntityAPI for backward compatibility.
 //
@@ -593,7 +1304,9 @@ func UseEntity() Option {
 }
 
 // Shutdown gracefully shuts down the client, releasing any resources.
-// This ensures that any pending operations are completed and resources are released.
+// This ensures that any pending operations are completed and resources are released:
+// the background work of the Entity is ended (see entities.Entity.Close), then
+// the observability provider is flushed and shut down.
 //
 // Parameters:
 //   - ctx: The context for the shutdown operation
@@ -601,6 +1314,12 @@ func UseEntity() Option {
 // Returns:
 //   - error: An error if the shutdown operation fails
 func (c *Client) Shutdown(ctx context.Context) error {
+	if c.Entity != nil {
+		if err := c.Entity.Close(ctx); err != nil {
+			return fmt.Errorf("error closing entity: %w", err)
+		}
+	}
+
 	// Shutdown observability provider
 	if c.observability != nil {
 		if err := c.observability.Shutdown(ctx); err != nil {
@@ -611,6 +1330,67 @@ func (c *Client) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// ActiveBackgroundTasks returns the background goroutines of the SDK still
+// running in the process, oldest first: rate limiters, spinners, ingestors,
+// the shadow requests and balance listings of entities, and the watchers and
+// pollers such as MaintenanceSchedule.Watch. Each ends with the Close or Stop
+// method of its owner, or with the context it was started with.
+//
+// Services embedding the SDK can call it after Shutdown to verify a clean
+// shutdown:
+//
+//	if err := c.Shutdown(ctx); err != nil {
+//	    return err
+//	}
+//	if tasks := client.ActiveBackgroundTasks(); len(tasks) > 0 {
+//	    log.Printf("SDK tasks still running: %v", tasks)
+//	}
+func ActiveBackgroundTasks() []lifecycle.Task {
+	return lifecycle.Active()
+}
+
+// CheckPermissions verifies that the client's credentials can perform the
+// given operations, named "Service.Method" (e.g. "Transactions.CreateTransaction",
+// see entities.PermissionOperations). Run it at startup or deploy time to fail
+// early instead of when the first batch runs. Missing permissions are reported
+// together in an entities.PermissionDeniedError. When plugin auth is disabled,
+// Midaz doesn't enforce permissions and the check passes.
+//
+// Parameters:
+//   - ctx: The context for the permission checks
+//   - operations: The operations the program will perform
+//
+// Returns:
+//   - error: An error if a permission is missing or could not be checked
+func (c *Client) CheckPermissions(ctx context.Context, operations ...string) error {
+	if c.Entity == nil {
+		return errors.New("entity API is not enabled")
+	}
+
+	return entities.CheckPermissions(ctx, c.Entity.PermissionChecker(c.config.GetPluginAuth()), operations...)
+}
+
+// Org returns the handle of an organization, to navigate the Entity API
+// object style instead of passing IDs to each call:
+//
+//	ledger := c.Org(orgID).Ledger(ledgerID)
+//	account, err := ledger.Accounts.Create(ctx, input)
+//
+// It returns nil when the Entity API is not enabled.
+//
+// Parameters:
+//   - orgID: The ID of the organization
+//
+// Returns:
+//   - *entities.OrganizationHandle: The handle of the organization
+func (c *Client) Org(orgID models.OrganizationID) *entities.OrganizationHandle {
+	if c.Entity == nil {
+		return nil
+	}
+
+	return c.Entity.Org(orgID)
+}
+
 // Trace executes the given function within the context of a trace span.
 // This is a convenience function for creating a traced operation.
 //
@@ -650,6 +1430,24 @@ func (c *Client) GetObservabilityProvider() observability.Provider {
 	return c.observability
 }
 
+// UpdateObservability changes the observability settings of the running client,
+// such as the trace sample rate, the log level and the enabled components.
+// See observability.MidazProvider.Update.
+//
+// Parameters:
+//   - opts: The observability options to apply
+//
+// Returns:
+//   - error: An error if observability is not enabled or a setting cannot be changed at runtime
+func (c *Client) UpdateObservability(opts ...observability.Option) error {
+	provider, ok := c.observability.(*observability.MidazProvider)
+	if !ok {
+		return errors.New("observability is not enabled or its provider does not support updates")
+	}
+
+	return provider.Update(opts...)
+}
+
 // GetMetricsCollector returns the metrics collector.
 // This is useful when you want to record custom metrics.
 //
@@ -1,12 +1,27 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	auth "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/access-manager"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/config"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/cost"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/quota"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/usage"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // createTestConfig creates a test config with sensible defaults.
@@ -117,6 +132,368 @@ func TestUseAllAPIs(t *testing.T) {
 	}
 }
 
+func TestUseTransactionsOnly(t *testing.T) {
+	client, err := New(UseTransactionsOnly(), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.Entity == nil || client.Entity.Transactions == nil || client.Entity.Balances == nil {
+		t.Fatal("Expected the transaction services to be set")
+	}
+
+	if client.Entity.Accounts != nil || client.Entity.Organizations != nil {
+		t.Error("Expected the onboarding services to be nil")
+	}
+
+	if client.ReadOnly == nil || client.ReadOnly.Transactions == nil {
+		t.Error("Expected the read-only view to be set")
+	}
+}
+
+func TestUseReadOnlyAPIs(t *testing.T) {
+	client, err := New(UseReadOnlyAPIs(), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.Entity != nil {
+		t.Error("Expected Entity to be nil")
+	}
+
+	if client.ReadOnly == nil || client.ReadOnly.Accounts == nil || client.ReadOnly.Transactions == nil {
+		t.Error("Expected the read-only services to be set")
+	}
+
+	if _, err := New(UseServices(0)); err == nil {
+		t.Error("Expected an error for an empty service set")
+	}
+}
+
+func TestWithReadOnly(t *testing.T) {
+	client, err := New(UseEntityAPI(), WithReadOnly(true), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if !client.Entity.GetEntityHTTPClient().IsReadOnly() {
+		t.Error("Expected the entity HTTP client to be read-only")
+	}
+
+	err = client.Entity.Organizations.DeleteOrganization(context.Background(), "org-1")
+	if !errors.Is(err, entities.ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, got %v", err)
+	}
+}
+
+// roundTripFunc is a transport that isn't an *http.Transport, such as a wrapping one.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithHTTPClient_ReachesEntity(t *testing.T) {
+	custom := &http.Client{
+		Timeout:       5 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	client, err := New(WithConfig(createTestConfig(t)), WithHTTPClient(custom), UseEntityAPI())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.Entity.GetHTTPClient() != custom {
+		t.Error("Expected the Entity to use the configured HTTP client")
+	}
+
+	cfg := createTestConfig(t)
+	if err := config.WithUnixSocket("/var/run/midaz.sock")(cfg); err != nil {
+		t.Fatalf("Failed to set the socket: %v", err)
+	}
+
+	wrapped := &http.Client{Transport: roundTripFunc(http.DefaultTransport.RoundTrip)}
+	if _, err := New(WithConfig(cfg), WithHTTPClient(wrapped), UseEntityAPI()); err == nil {
+		t.Error("Expected an error for a dialer that can't be set on the transport")
+	}
+}
+
+func TestEnableExperimental(t *testing.T) {
+	client, err := New(UseEntityAPI(), EnableExperimental(), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if len(client.experimental) != 0 {
+		t.Errorf("Expected no experimental feature, got %v", client.experimental)
+	}
+
+	if _, err := New(UseEntityAPI(), EnableExperimental("account-types-v2"), WithConfig(createTestConfig(t))); err == nil {
+		t.Error("Expected an error for an unknown experimental feature")
+	}
+}
+
+func TestWithUsageRecorder(t *testing.T) {
+	recorder := usage.NewRecorder()
+
+	client, err := New(UseEntityAPI(), WithUsageRecorder(recorder), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.diagnosticsConfig()["usageRecording"] != true {
+		t.Error("Expected diagnostics to report usage recording")
+	}
+
+	if _, err := New(UseEntityAPI(), WithUsageRecorder(nil), WithConfig(createTestConfig(t))); err == nil {
+		t.Error("Expected an error for a nil usage recorder")
+	}
+}
+
+func TestWithCostHook(t *testing.T) {
+	client, err := New(UseEntityAPI(), WithCostHook(cost.NewAccountant(cost.Pricing{PerRequest: 1})), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.diagnosticsConfig()["costAccounting"] != true {
+		t.Error("Expected diagnostics to report cost accounting")
+	}
+
+	if _, err := New(UseEntityAPI(), WithCostHook(nil), WithConfig(createTestConfig(t))); err == nil {
+		t.Error("Expected an error for a nil cost hook")
+	}
+}
+
+func TestWithTenantQuotas(t *testing.T) {
+	client, err := New(UseEntityAPI(), WithTenantQuotas(quota.NewEnforcer()), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.diagnosticsConfig()["tenantQuotas"] != true {
+		t.Error("Expected diagnostics to report tenant quotas")
+	}
+
+	if _, err := New(UseEntityAPI(), WithTenantQuotas(nil), WithConfig(createTestConfig(t))); err == nil {
+		t.Error("Expected an error for a nil quota enforcer")
+	}
+}
+
+func TestWithMaintenanceSchedule(t *testing.T) {
+	client, err := New(UseEntityAPI(), WithMaintenanceSchedule(entities.NewMaintenanceSchedule()), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.diagnosticsConfig()["maintenanceSchedule"] != true {
+		t.Error("Expected diagnostics to report the maintenance schedule")
+	}
+
+	if _, err := New(UseEntityAPI(), WithMaintenanceSchedule(nil), WithConfig(createTestConfig(t))); err == nil {
+		t.Error("Expected an error for a nil maintenance schedule")
+	}
+}
+
+func TestWithRetryJournal(t *testing.T) {
+	client, err := New(UseEntityAPI(), WithRetryJournal(entities.NewMemoryRetryJournal()), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.diagnosticsConfig()["retryJournal"] != true {
+		t.Error("Expected diagnostics to report the retry journal")
+	}
+
+	if _, err := New(UseEntityAPI(), WithRetryJournal(nil), WithConfig(createTestConfig(t))); err == nil {
+		t.Error("Expected an error for a nil retry journal")
+	}
+}
+
+func TestWithTransactionMetadata(t *testing.T) {
+	tpl := entities.NewMetadataTemplate().WithValue("team", "payments")
+
+	client, err := New(UseEntityAPI(), WithTransactionMetadata(tpl), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.diagnosticsConfig()["transactionMetadataTemplate"] != true {
+		t.Error("Expected diagnostics to report the transaction metadata template")
+	}
+
+	if _, err := New(UseEntityAPI(), WithTransactionMetadata(nil), WithConfig(createTestConfig(t))); err == nil {
+		t.Error("Expected an error for a nil metadata template")
+	}
+}
+
+func TestOrg(t *testing.T) {
+	client, err := New(UseEntityAPI(), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ledger := client.Org("org-1").Ledger("ledger-1")
+	if ledger.OrganizationID != "org-1" || ledger.ID() != "ledger-1" || ledger.Accounts == nil {
+		t.Errorf("Expected a ledger handle of org-1/ledger-1, got %+v", ledger.ScopedEntity)
+	}
+
+	if (&Client{}).Org("org-1") != nil {
+		t.Error("Expected no handle without the Entity API")
+	}
+}
+
+func TestWithPayloadLimits(t *testing.T) {
+	limits := entities.ServerPayloadLimits()
+
+	client, err := New(UseEntityAPI(), WithPayloadLimits(limits), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	input := models.NewCreateOrganizationInput("Acme").
+		WithLegalDocument("123456789").
+		WithMetadata(map[string]any{"notes": strings.Repeat("a", entities.ServerMaxMetadataValueLength+1)})
+
+	_, err = client.Entity.Organizations.CreateOrganization(context.Background(), input)
+
+	var tooLarge *entities.PayloadTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("Expected a PayloadTooLargeError, got %v", err)
+	}
+
+	if tooLarge.Field != "metadata.notes" {
+		t.Errorf("Expected field metadata.notes, got %q", tooLarge.Field)
+	}
+}
+
+func TestWithStrictValidation(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.ValidationMode = validation.ModeStrict
+	cfg.MaxMetadataKeys = 2
+
+	client, err := New(UseEntityAPI(), WithConfig(cfg))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	input := models.NewCreateOrganizationInput("Acme").
+		WithLegalDocument("123456789").
+		WithMetadata(map[string]any{"a": 1, "b": 2, "c": 3})
+
+	_, err = client.Entity.Organizations.CreateOrganization(context.Background(), input)
+	if !sdkerrors.IsValidationError(err) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+
+	var fieldErrs *validation.FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("Expected the error to wrap field errors, got %v", err)
+	}
+
+	if got := fieldErrs.GetErrorsForField("metadata"); len(got) != 1 {
+		t.Errorf("Expected one error for the metadata field, got %v", fieldErrs.GetFieldErrors())
+	}
+}
+
+func TestWithValidationWarningHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	cfg := createTestConfig(t)
+	cfg.ValidationMode = validation.ModeLenient
+
+	var warnings []string
+
+	client, err := New(UseEntityAPI(), WithConfig(cfg), WithOnboardingURL(srv.URL),
+		WithValidationWarningHandler(func(_ context.Context, operation string, _ error) {
+			warnings = append(warnings, operation)
+		}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	input := models.NewCreateAccountTypeInput("", "KEY")
+	if _, err := client.Entity.AccountTypes.CreateAccountType(context.Background(), "org-1", "ledger-1", input); err != nil {
+		t.Fatalf("Expected the invalid input to be sent anyway, got %v", err)
+	}
+
+	if len(warnings) != 1 || warnings[0] != "CreateAccountType" {
+		t.Errorf("Expected a CreateAccountType warning, got %v", warnings)
+	}
+}
+
+func TestCheckPermissions(t *testing.T) {
+	client, err := New(UseEntityAPI(), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// plugin auth is disabled, so Midaz doesn't enforce permissions
+	if err := client.CheckPermissions(context.Background(), "Transactions.CreateTransaction"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if err := client.CheckPermissions(context.Background(), "Transactions.Teleport"); err == nil {
+		t.Error("Expected an error for an unknown operation")
+	}
+}
+
+func TestUpdateObservability(t *testing.T) {
+	client, err := New(WithConfig(createTestConfig(t)), WithObservabilityOptions(
+		observability.WithComponentEnabled(false, false, true),
+		observability.WithRegisterGlobally(false),
+	))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if err := client.UpdateObservability(observability.WithLogLevel(observability.DebugLevel)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	provider, ok := client.GetObservabilityProvider().(*observability.MidazProvider)
+	if !ok {
+		t.Fatal("Expected a MidazProvider")
+	}
+
+	if got := provider.Config().LogLevel; got != observability.DebugLevel {
+		t.Errorf("Expected log level %v, got %v", observability.DebugLevel, got)
+	}
+}
+
+func TestWithSpanEnricher(t *testing.T) {
+	var operations []string
+
+	enricher := func(_ context.Context, operation string, span trace.Span) {
+		operations = append(operations, operation)
+		span.SetAttributes(attribute.String("tenant", "acme"))
+	}
+
+	// The enricher applies to a provider configured by a later option
+	client, err := New(WithConfig(createTestConfig(t)), WithSpanEnricher(enricher), WithObservabilityOptions(
+		observability.WithComponentEnabled(true, false, false),
+		observability.WithFullTracingSampling(),
+		observability.WithRegisterGlobally(false),
+	))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	defer func() { _ = client.Shutdown(context.Background()) }() //nolint:errcheck // test cleanup
+
+	_, span := client.GetObservabilityProvider().Tracer().Start(context.Background(), "HTTP GET /v1/organizations")
+	span.End()
+
+	if len(operations) != 1 || operations[0] != "HTTP GET /v1/organizations" {
+		t.Errorf("Expected the enricher to be called for the span, got %v", operations)
+	}
+}
+
 func TestGetConfig(t *testing.T) {
 	client, err := New(WithConfig(createTestConfig(t)))
 	if err != nil {
@@ -265,3 +642,142 @@ func TestClientTenantOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestWithConnectivityProbe(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := New(
+		WithOnboardingURL(server.URL),
+		WithTransactionURL(server.URL),
+		WithConnectivityProbe(time.Second),
+	)
+	if err != nil {
+		t.Fatalf("expected reachable services to pass the probe, got %v", err)
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	_, err = New(
+		WithOnboardingURL(server.URL),
+		WithTransactionURL(closed.URL),
+		WithConnectivityProbe(time.Second),
+	)
+	if !errors.Is(err, config.ErrUnreachable) {
+		t.Fatalf("expected config.ErrUnreachable, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "transaction URL unreachable") {
+		t.Errorf("expected the error to name the transaction URL, got %q", err.Error())
+	}
+}
+
+func TestWithShadowTarget(t *testing.T) {
+	client, err := New(UseEntityAPI(), WithConfig(createTestConfig(t)), WithShadowTarget(entities.ShadowConfig{
+		BaseURLs:   map[string]string{"onboarding": "http://shadow.example.com/v1"},
+		SampleRate: 0.5,
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.Entity.Shadow() == nil {
+		t.Fatal("expected the Entity to have a shadow")
+	}
+
+	if _, err := New(WithShadowTarget(entities.ShadowConfig{})); err == nil {
+		t.Error("expected an error for a shadow target without base URLs")
+	}
+}
+
+func TestWithSchemaDriftCheck(t *testing.T) {
+	client, err := New(UseEntityAPI(), WithConfig(createTestConfig(t)), WithSchemaDriftCheck(entities.SchemaDriftOptions{ReportMissing: true}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.Entity.SchemaDrift() == nil {
+		t.Fatal("expected the Entity to have a schema drift log")
+	}
+
+	client, err = New(UseEntityAPI(), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.Entity.SchemaDrift() != nil {
+		t.Error("expected no schema drift log by default")
+	}
+}
+
+func TestWithUnknownFieldRoundTrip(t *testing.T) {
+	client, err := New(UseEntityAPI(), WithConfig(createTestConfig(t)), WithUnknownFieldRoundTrip(10))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.Entity.UnknownFields() == nil {
+		t.Fatal("expected the Entity to have an unknown field store")
+	}
+}
+
+func TestWithTimeZone(t *testing.T) {
+	if _, err := New(UseEntityAPI(), WithConfig(createTestConfig(t)), WithTimeZone(time.FixedZone("BRT", -3*3600))); err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := New(WithTimeZone(nil)); err == nil {
+		t.Error("expected an error for a nil time zone")
+	}
+}
+
+func TestWithBalanceCache(t *testing.T) {
+	client, err := New(UseEntityAPI(), WithConfig(createTestConfig(t)), WithBalanceCache(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.Entity.BalanceCache() == nil {
+		t.Fatal("expected the Entity to have a balance cache")
+	}
+}
+
+func TestShutdownClosesEntity(t *testing.T) {
+	client, err := New(UseEntityAPI(), WithConfig(createTestConfig(t)), WithBalanceCache(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if err := client.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	if _, err := client.Entity.BalanceCache().Get(context.Background(), "org", "ledger", "acc", "USD"); !errors.Is(err, entities.ErrBalanceCacheClosed) {
+		t.Errorf("expected the balance cache to be closed, got %v", err)
+	}
+}
+
+func TestActiveBackgroundTasks(t *testing.T) {
+	limiter := concurrent.NewRateLimiter(10, 1)
+
+	running := func() bool {
+		for _, task := range ActiveBackgroundTasks() {
+			if task.Name == "concurrent.RateLimiter" {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if !running() {
+		t.Error("expected the rate limiter among the background tasks")
+	}
+
+	limiter.Stop()
+
+	if running() {
+		t.Errorf("expected no rate limiter left after Stop, got %v", ActiveBackgroundTasks())
+	}
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+)
+
+// CloneWith returns a new client derived from c with options applied on top
+// of a copy of its settings, e.g. a "bulk" variant with a long timeout and
+// more retries next to an "interactive" one:
+//
+//	bulk, err := c.CloneWith(
+//	    client.WithTimeout(5*time.Minute),
+//	    client.WithRetries(5, time.Second, time.Minute),
+//	)
+//
+// The clone shares with c the connection pool of its HTTP client, its
+// authentication token and the renewal of the token, its observability
+// provider and its recorders, such as the usage recorder and the activity
+// log, unless options replace them. It requests no token of its own, unless
+// options change the access manager settings. A changed timeout applies to
+// the requests of the clone, and changed retry settings replace the retry
+// policy of the Entity API for the clone only; c is left unchanged.
+//
+// Giving the clone its own observability provider, with WithObservability or
+// WithObservabilityOptions, is required to set another span enricher. Shut
+// down only the clients owning a provider: the clone shares the provider of c
+// otherwise.
+//
+// Parameters:
+//   - options: The options overriding the settings of c
+//
+// Returns:
+//   - *Client: The derived client
+//   - error: An error if an option fails or the Entity API cannot be set up
+func (c *Client) CloneWith(options ...Option) (*Client, error) {
+	clone := *c
+	clone.config = c.config.Clone()
+	clone.Entity, clone.ReadOnly, clone.entity = nil, nil, nil
+	clone.capturedHeaders = slices.Clone(c.capturedHeaders)
+	clone.experimental = slices.Clone(c.experimental)
+
+	// A span enricher set by options must be told apart from the one of c
+	clone.spanEnricher = nil
+
+	for _, option := range options {
+		if err := option(&clone); err != nil {
+			return nil, fmt.Errorf("error applying option: %w", err)
+		}
+	}
+
+	if clone.config.ProbeTimeout > 0 && !maps.Equal(clone.config.ServiceURLs, c.config.ServiceURLs) {
+		if err := clone.config.Probe(clone.ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := clone.cloneSpanEnricher(c); err != nil {
+		return nil, err
+	}
+
+	if clone.useEntity {
+		if err := clone.setupClonedEntity(c); err != nil {
+			return nil, fmt.Errorf("error setting up Entity API: %w", err)
+		}
+
+		clone.entity = clone.Entity
+		clone.ReadOnly = clone.Entity.ReadOnly()
+
+		if clone.readOnlyView {
+			clone.Entity = nil
+		}
+	}
+
+	return &clone, nil
+}
+
+// cloneSpanEnricher installs the span enricher of the clone on its
+// observability provider when the provider or the enricher changed.
+func (c *Client) cloneSpanEnricher(parent *Client) error {
+	ownProvider := c.observability != parent.observability
+
+	if c.spanEnricher == nil {
+		c.spanEnricher = parent.spanEnricher
+
+		if !ownProvider {
+			return nil
+		}
+	} else if !ownProvider {
+		return errors.New("a clone needs its own observability provider to set a span enricher")
+	}
+
+	if c.spanEnricher == nil {
+		return nil
+	}
+
+	if err := c.UpdateObservability(observability.WithSpanEnricher(c.spanEnricher)); err != nil {
+		return fmt.Errorf("error setting span enricher: %w", err)
+	}
+
+	return nil
+}
+
+// setupClonedEntity creates the Entity API of a clone of parent, sharing the
+// HTTP transport and the authentication of the Entity API of parent, if any.
+func (c *Client) setupClonedEntity(parent *Client) error {
+	if parent.entity == nil || c.config.GetPluginAuth() != parent.config.GetPluginAuth() {
+		return c.setupEntity()
+	}
+
+	options, err := c.entityOptions()
+	if err != nil {
+		return err
+	}
+
+	// A clone given another HTTP client or configuration doesn't share the transport
+	if c.config.HTTPClient == parent.config.HTTPClient {
+		shared := *parent.entity.GetHTTPClient()
+		if c.config.Timeout != parent.config.Timeout {
+			shared.Timeout = c.config.Timeout
+		}
+
+		options = append(options, entities.WithHTTPClient(&shared))
+	}
+
+	if c.retrySettingsChanged(parent) {
+		retryOptions, err := c.retryOptions()
+		if err != nil {
+			return err
+		}
+
+		options = append(options, entities.WithRetryOptions(retryOptions))
+	}
+
+	options = append(options, entities.WithAuthFrom(parent.entity))
+
+	return c.newEntity(options)
+}
+
+// retrySettingsChanged reports whether the retry settings of c differ from the ones of parent.
+func (c *Client) retrySettingsChanged(parent *Client) bool {
+	a, b := c.config, parent.config
+
+	return a.EnableRetries != b.EnableRetries || a.MaxRetries != b.MaxRetries ||
+		a.RetryWaitMin != b.RetryWaitMin || a.RetryWaitMax != b.RetryWaitMax
+}
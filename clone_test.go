@@ -0,0 +1,181 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	auth "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/access-manager"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/config"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestCloneWithSharesTransport(t *testing.T) {
+	parent, err := New(UseEntity(), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	bulk, err := parent.CloneWith(WithTimeout(5*time.Minute), WithTenantID("tenant-bulk"))
+	if err != nil {
+		t.Fatalf("CloneWith failed: %v", err)
+	}
+
+	if bulk.Entity == nil || bulk.Entity == parent.Entity {
+		t.Fatal("Expected the clone to have its own Entity API")
+	}
+
+	parentHTTP, bulkHTTP := parent.Entity.GetHTTPClient(), bulk.Entity.GetHTTPClient()
+	if bulkHTTP.Transport != parentHTTP.Transport {
+		t.Error("Expected the clone to share the transport of its parent")
+	}
+
+	if bulkHTTP.Timeout != 5*time.Minute {
+		t.Errorf("Expected the clone timeout to be 5m, got %v", bulkHTTP.Timeout)
+	}
+
+	if parent.GetConfig().Timeout == 5*time.Minute || parentHTTP.Timeout == 5*time.Minute {
+		t.Error("Expected the parent timeout to be unchanged")
+	}
+
+	if bulk.GetConfig() == parent.GetConfig() {
+		t.Error("Expected the clone to have its own configuration")
+	}
+
+	if bulk.GetObservabilityProvider() != parent.GetObservabilityProvider() {
+		t.Error("Expected the clone to share the observability provider")
+	}
+
+	// The clone keeps the timeout of the parent unless changed
+	same, err := parent.CloneWith()
+	if err != nil {
+		t.Fatalf("CloneWith failed: %v", err)
+	}
+
+	if same.Entity.GetHTTPClient().Timeout != parentHTTP.Timeout {
+		t.Errorf("Expected the clone timeout to be %v, got %v", parentHTTP.Timeout, same.Entity.GetHTTPClient().Timeout)
+	}
+}
+
+func TestCloneWithRetries(t *testing.T) {
+	t.Setenv("MIDAZ_ENABLE_RETRIES", "false")
+
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"code":"0000","message":"service unavailable"}`))
+	}))
+	defer srv.Close()
+
+	parent, err := New(UseEntity(), WithConfig(createTestConfig(t)), WithOnboardingURL(srv.URL), WithTransactionURL(srv.URL))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	retrying, err := parent.CloneWith(WithRetries(2, time.Millisecond, 5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("CloneWith failed: %v", err)
+	}
+
+	if _, err := retrying.Entity.Organizations.GetOrganization(context.Background(), "org-1"); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	if got := requests.Load(); got != 3 {
+		t.Errorf("Expected 3 requests from the clone, got %d", got)
+	}
+
+	requests.Store(0)
+
+	if _, err := parent.Entity.Organizations.GetOrganization(context.Background(), "org-1"); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected 1 request from the parent, got %d", got)
+	}
+}
+
+func TestCloneWithSharesAuth(t *testing.T) {
+	var logins atomic.Int32
+
+	authServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		logins.Add(1)
+		_, _ = w.Write([]byte(`{"accessToken":"token-1"}`))
+	}))
+	defer authServer.Close()
+
+	var authorization atomic.Value
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization.Store(r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"id":"org-1"}`))
+	}))
+	defer api.Close()
+
+	cfg, err := config.NewConfig(
+		config.WithOnboardingURL(api.URL),
+		config.WithTransactionURL(api.URL),
+		config.WithAccessManager(auth.AccessManager{Enabled: true, Address: authServer.URL, ClientID: "id", ClientSecret: "secret"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	parent, err := New(UseEntity(), WithConfig(cfg))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	clone, err := parent.CloneWith(WithTimeout(time.Minute))
+	if err != nil {
+		t.Fatalf("CloneWith failed: %v", err)
+	}
+
+	if got := logins.Load(); got != 1 {
+		t.Errorf("Expected the clone to reuse the token of its parent, got %d logins", got)
+	}
+
+	if _, err := clone.Entity.Organizations.GetOrganization(context.Background(), "org-1"); err != nil {
+		t.Fatalf("GetOrganization failed: %v", err)
+	}
+
+	if got := authorization.Load(); got != "token-1" {
+		t.Errorf("Expected the clone to authenticate with token-1, got %v", got)
+	}
+}
+
+func TestCloneWithSpanEnricher(t *testing.T) {
+	parent, err := New(WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	enricher := observability.SpanEnricher(func(context.Context, string, trace.Span) {})
+
+	if _, err := parent.CloneWith(WithSpanEnricher(enricher)); err == nil {
+		t.Error("Expected an error setting a span enricher on a shared provider")
+	}
+}
+
+func TestCloneWithReadOnlyView(t *testing.T) {
+	parent, err := New(UseReadOnlyAPIs(), WithConfig(createTestConfig(t)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	clone, err := parent.CloneWith(WithTimeout(time.Minute))
+	if err != nil {
+		t.Fatalf("CloneWith failed: %v", err)
+	}
+
+	if clone.Entity != nil || clone.ReadOnly == nil {
+		t.Error("Expected the clone to keep the read-only view of its parent")
+	}
+}
@@ -0,0 +1,276 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/manifest"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/version"
+)
+
+// diagnosticsTimeout bounds each connectivity check of Diagnostics.
+const diagnosticsTimeout = 5 * time.Second
+
+// ConnectivityCheck is the result of reaching one Midaz service.
+type ConnectivityCheck struct {
+	Service   string `json:"service"`
+	URL       string `json:"url"`
+	Reachable bool   `json:"reachable"`
+	// Version is the version reported by the service
+	Version string `json:"version,omitempty"`
+	Latency string `json:"latency,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Diagnostics is a support bundle describing a client: SDK and runtime
+// versions, configuration and environment variables with secrets redacted,
+// connectivity to each service and the most recent failed requests. It is
+// safe to attach to a support ticket.
+type Diagnostics struct {
+	GeneratedAt time.Time    `json:"generatedAt"`
+	SDK         manifest.SDK `json:"sdk"`
+	// Environment leaves out the hostname
+	Environment  manifest.Environment     `json:"environment"`
+	Config       map[string]any           `json:"config"`
+	EnvVars      map[string]any           `json:"envVars,omitempty"`
+	Connectivity []ConnectivityCheck      `json:"connectivity"`
+	RecentErrors []entities.ErrorLogEntry `json:"recentErrors,omitempty"`
+	// Connections aggregates the connection timings of the requests, when
+	// connection debugging is enabled
+	Connections *entities.ConnectionStats `json:"connections,omitempty"`
+}
+
+// JSON returns the indented JSON encoding of the bundle.
+func (d *Diagnostics) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// WithErrorLog keeps the last size failed requests of the Entity API, to be
+// reported by Diagnostics. A non-positive size keeps entities.DefaultErrorLogSize.
+//
+// Parameters:
+//   - size: The number of failed requests to keep
+//
+// Returns:
+//   - Option: A function that enables the error log on the Client
+func WithErrorLog(size int) Option {
+	return func(c *Client) error {
+		c.errorLog = entities.NewErrorLog(size)
+		return nil
+	}
+}
+
+// WithActivityLog keeps the last size requests of the Entity API, to be
+// reported by RecentActivity. A non-positive size keeps
+// entities.DefaultActivityLogSize.
+//
+// Parameters:
+//   - size: The number of requests to keep
+//
+// Returns:
+//   - Option: A function that enables the activity log on the Client
+func WithActivityLog(size int) Option {
+	return func(c *Client) error {
+		c.activityLog = entities.NewActivityLog(size)
+		return nil
+	}
+}
+
+// RecentActivity returns the last requests of the Entity API, oldest first:
+// their operation, duration, status and error category. It is cheap enough to
+// back an admin health endpoint. It returns nil unless the client was created
+// with WithActivityLog.
+//
+// Returns:
+//   - []entities.ActivityEntry: The recent requests
+func (c *Client) RecentActivity() []entities.ActivityEntry {
+	if c.activityLog == nil {
+		return nil
+	}
+
+	return c.activityLog.Entries()
+}
+
+// ConnectionStats returns the connection timings of the requests of the
+// Entity API, aggregated: reuse rate and average DNS, connect, TLS, wait and
+// first byte times. A low reuse rate with high connect or TLS times points to
+// connection churn, a high first byte time to server latency. It returns nil
+// unless connection debugging is enabled with WithConnectionDebug.
+//
+// Returns:
+//   - *entities.ConnectionStats: The aggregated connection timings
+func (c *Client) ConnectionStats() *entities.ConnectionStats {
+	if c.connectionTrace == nil {
+		return nil
+	}
+
+	stats := c.connectionTrace.Stats()
+
+	return &stats
+}
+
+// RecentConnections returns the connection timings of the last requests of
+// the Entity API, oldest first. It returns nil unless connection debugging
+// is enabled with WithConnectionDebug.
+//
+// Returns:
+//   - []entities.ConnectionTiming: The recent connection timings
+func (c *Client) RecentConnections() []entities.ConnectionTiming {
+	if c.connectionTrace == nil {
+		return nil
+	}
+
+	return c.connectionTrace.Entries()
+}
+
+// connectionLogf logs a connection trace line with the observability logger,
+// or to stderr when observability is disabled.
+func (c *Client) connectionLogf(format string, args ...any) {
+	if c.observability != nil && c.observability.IsEnabled() && c.observability.Logger() != nil {
+		c.observability.Logger().Debugf(format, args...)
+		return
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "[Midaz SDK Debug] "+format+"\n", args...)
+}
+
+// Diagnostics collects a sanitized support bundle for the client. Secrets,
+// tokens and URL passwords are redacted. Each configured service is reached
+// at its version endpoint; failures are reported in the bundle rather than
+// returned. Recent errors are included when the client was created with
+// WithErrorLog.
+//
+// Parameters:
+//   - ctx: The context for the connectivity checks
+//
+// Returns:
+//   - *Diagnostics: The support bundle
+func (c *Client) Diagnostics(ctx context.Context) *Diagnostics {
+	d := &Diagnostics{
+		GeneratedAt: time.Now().UTC(),
+		SDK: manifest.SDK{
+			Name:      version.SDKName,
+			Version:   version.Version,
+			Language:  version.SDKLanguage,
+			GoVersion: runtime.Version(),
+		},
+		Environment: manifest.Environment{
+			OS:     runtime.GOOS,
+			Arch:   runtime.GOARCH,
+			NumCPU: runtime.NumCPU(),
+		},
+		Config:  c.diagnosticsConfig(),
+		EnvVars: diagnosticsEnvVars(),
+	}
+
+	urls := c.config.GetBaseURLs()
+
+	services := make([]string, 0, len(urls))
+	for service := range urls {
+		services = append(services, service)
+	}
+
+	sort.Strings(services)
+
+	for _, service := range services {
+		d.Connectivity = append(d.Connectivity, c.checkConnectivity(ctx, service, urls[service]))
+	}
+
+	if c.errorLog != nil {
+		d.RecentErrors = c.errorLog.Entries()
+	}
+
+	d.Connections = c.ConnectionStats()
+
+	return d
+}
+
+// diagnosticsConfig returns the client settings with secrets redacted.
+func (c *Client) diagnosticsConfig() map[string]any {
+	cfg := manifest.SDKConfig(c.config)
+	if cfg == nil {
+		cfg = map[string]any{}
+	}
+
+	cfg["debug"] = c.config.Debug
+	cfg["debugConnections"] = c.config.DebugConnections
+	cfg["entityApi"] = c.useEntity
+	cfg["readOnly"] = c.readOnly
+
+	if len(c.experimental) > 0 {
+		cfg["experimental"] = c.experimental
+	}
+
+	cfg["usageRecording"] = c.usage != nil
+	cfg["transactionMetadataTemplate"] = c.metadataTemplate != nil
+	cfg["costAccounting"] = c.costHook != nil
+	cfg["tenantQuotas"] = c.quota != nil
+	cfg["maintenanceSchedule"] = c.maintenance != nil
+	cfg["retryJournal"] = c.retryJournal != nil
+
+	if c.tenantIDSet {
+		cfg["tenantId"] = c.tenantID
+	}
+
+	return cfg
+}
+
+// checkConnectivity reaches the version endpoint of a service.
+func (c *Client) checkConnectivity(ctx context.Context, service, baseURL string) ConnectivityCheck {
+	check := ConnectivityCheck{Service: service}
+
+	// Sanitize removes passwords from URLs with user information
+	if sanitized, err := manifest.Sanitize(baseURL); err == nil {
+		check.URL, _ = sanitized.(string)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, diagnosticsTimeout)
+	defer cancel()
+
+	start := time.Now()
+	v, err := manifest.FetchBackendVersion(ctx, c.config.HTTPClient, baseURL)
+	check.Latency = time.Since(start).Round(time.Millisecond).String()
+
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	check.Reachable = true
+	check.Version = v
+
+	return check
+}
+
+// diagnosticsEnvVars returns the SDK environment variables that are set, with
+// secrets redacted.
+func diagnosticsEnvVars() map[string]any {
+	vars := make(map[string]string)
+
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(key, "MIDAZ_") || strings.HasPrefix(key, "PLUGIN_AUTH_") {
+			vars[key] = value
+		}
+	}
+
+	if len(vars) == 0 {
+		return nil
+	}
+
+	sanitized, err := manifest.Sanitize(vars)
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+
+	out, _ := sanitized.(map[string]any)
+
+	return out
+}
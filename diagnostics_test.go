@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/manifest"
+)
+
+func TestDiagnostics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/version":
+			_, _ = w.Write([]byte(`{"version":"3.4.0"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("MIDAZ_CLIENT_SECRET", "s3cr3t")
+
+	client, err := New(
+		WithConfig(createTestConfig(t)),
+		WithOnboardingURL(srv.URL+"/v1"),
+		WithTransactionURL("http://127.0.0.1:1/v1"),
+		WithErrorLog(10),
+		WithActivityLog(10),
+		UseEntityAPI(),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.Entity.Organizations.GetOrganization(context.Background(), "0190b6c4-3a77-7a2e-8f55-6c0a1f4f2b10")
+	if err == nil {
+		t.Fatal("Expected GetOrganization to fail")
+	}
+
+	d := client.Diagnostics(context.Background())
+
+	if len(d.Connectivity) != 2 {
+		t.Fatalf("Expected 2 connectivity checks, got %+v", d.Connectivity)
+	}
+
+	onboarding, transaction := d.Connectivity[0], d.Connectivity[1]
+	if !onboarding.Reachable || onboarding.Version != "3.4.0" {
+		t.Errorf("Expected onboarding to be reachable at 3.4.0, got %+v", onboarding)
+	}
+
+	if transaction.Reachable || transaction.Error == "" {
+		t.Errorf("Expected transaction to be unreachable, got %+v", transaction)
+	}
+
+	if d.EnvVars["MIDAZ_CLIENT_SECRET"] != manifest.Redacted {
+		t.Errorf("Expected the client secret to be redacted, got %v", d.EnvVars["MIDAZ_CLIENT_SECRET"])
+	}
+
+	if len(d.RecentErrors) == 0 || d.RecentErrors[0].StatusCode != http.StatusNotFound {
+		t.Errorf("Expected the failed request to be recorded, got %+v", d.RecentErrors)
+	}
+
+	activity := client.RecentActivity()
+	if len(activity) != 1 || activity[0].Operation != "GET /v1/organizations/{id}" {
+		t.Errorf("Expected the request in the recent activity, got %+v", activity)
+	}
+
+	raw, err := d.JSON()
+	if err != nil {
+		t.Fatalf("Failed to encode diagnostics: %v", err)
+	}
+
+	if strings.Contains(string(raw), "s3cr3t") {
+		t.Error("Expected no secret in the bundle")
+	}
+}
+
+func TestConnectionDebug(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	defer srv.Close()
+
+	newClient := func(options ...Option) *Client {
+		options = append([]Option{
+			WithConfig(createTestConfig(t)),
+			WithOnboardingURL(srv.URL + "/v1"),
+			WithTransactionURL(srv.URL + "/v1"),
+			UseEntityAPI(),
+		}, options...)
+
+		client, err := New(options...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		return client
+	}
+
+	if stats := newClient().ConnectionStats(); stats != nil {
+		t.Errorf("Expected no connection stats without connection debugging, got %+v", stats)
+	}
+
+	client := newClient(WithConnectionDebug(true))
+
+	for range 2 {
+		if _, err := client.Entity.Organizations.ListOrganizations(context.Background(), nil); err != nil {
+			t.Fatalf("Failed to list organizations: %v", err)
+		}
+	}
+
+	stats := client.ConnectionStats()
+	if stats == nil || stats.Requests != 2 {
+		t.Fatalf("Expected 2 traced requests, got %+v", stats)
+	}
+
+	if recent := client.RecentConnections(); len(recent) != 2 || !recent[1].Reused {
+		t.Errorf("Expected the second request to reuse the connection, got %+v", recent)
+	}
+
+	if d := client.Diagnostics(context.Background()); d.Connections == nil || d.Config["debugConnections"] != true {
+		t.Errorf("Expected connection stats in the diagnostics, got %+v", d.Connections)
+	}
+}
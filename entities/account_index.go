@@ -0,0 +1,434 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+)
+
+// AccountIndexSyncResult summarizes the changes applied by a sync.
+type AccountIndexSyncResult struct {
+	Added    int
+	Updated  int
+	Removed  int
+	Total    int
+	Duration time.Duration
+}
+
+// AccountIndexFilter selects accounts of an index. Empty fields match any value,
+// set fields must all match.
+type AccountIndexFilter struct {
+	Type        string
+	SegmentID   string
+	PortfolioID string
+	AssetCode   string
+	Status      string
+}
+
+// AccountIndex is a local read model of the accounts of a ledger, indexed by ID,
+// alias, type, segment and portfolio. It answers lookups and filters from memory,
+// keeping list calls out of request handling paths.
+//
+// The index is filled by Sync, which lists the ledger and applies only the
+// differences to the current index, and can be kept up to date by polling with
+// Run or by feeding account events to Apply and Remove. It is safe for concurrent
+// use. Accounts returned by the index share pointer fields with it and must not
+// be modified.
+type AccountIndex struct {
+	service        AccountsService
+	organizationID string
+	ledgerID       string
+	pageSize       int
+	onSyncError    func(error)
+
+	mu          sync.RWMutex
+	syncMu      sync.Mutex
+	byID        map[string]models.Account
+	byAlias     map[string]string
+	byType      map[string]map[string]struct{}
+	bySegment   map[string]map[string]struct{}
+	byPortfolio map[string]map[string]struct{}
+	lastSync    time.Time
+
+	now func() time.Time
+}
+
+// NewAccountIndex creates an empty index of the accounts of a ledger. Call Sync
+// or Run to fill it.
+func NewAccountIndex(service AccountsService, organizationID, ledgerID string) *AccountIndex {
+	return &AccountIndex{
+		service:        service,
+		organizationID: organizationID,
+		ledgerID:       ledgerID,
+		pageSize:       models.MaxLimit,
+		byID:           make(map[string]models.Account),
+		byAlias:        make(map[string]string),
+		byType:         make(map[string]map[string]struct{}),
+		bySegment:      make(map[string]map[string]struct{}),
+		byPortfolio:    make(map[string]map[string]struct{}),
+		now:            time.Now,
+	}
+}
+
+// WithPageSize sets the number of accounts requested per list call, capped at models.MaxLimit.
+func (x *AccountIndex) WithPageSize(size int) *AccountIndex {
+	if size > 0 {
+		x.pageSize = min(size, models.MaxLimit)
+	}
+
+	return x
+}
+
+// WithSyncErrorHandler sets a function called with the errors of the syncs run by Run.
+func (x *AccountIndex) WithSyncErrorHandler(fn func(error)) *AccountIndex {
+	x.onSyncError = fn
+	return x
+}
+
+// Sync lists every account of the ledger and applies the differences to the
+// index: new accounts are added, accounts whose content changed are reindexed
+// and accounts no longer listed are removed. The index keeps serving lookups
+// while a sync runs; concurrent syncs are serialized.
+func (x *AccountIndex) Sync(ctx context.Context) (*AccountIndexSyncResult, error) {
+	const operation = "AccountIndex.Sync"
+
+	if x.service == nil {
+		return nil, errors.NewMissingParameterError(operation, "accounts service")
+	}
+
+	if x.organizationID == "" {
+		return nil, errors.NewMissingParameterError(operation, "organizationID")
+	}
+
+	if x.ledgerID == "" {
+		return nil, errors.NewMissingParameterError(operation, "ledgerID")
+	}
+
+	x.syncMu.Lock()
+	defer x.syncMu.Unlock()
+
+	started := x.now()
+	listed := make(map[string]models.Account)
+	opts := models.NewListOptions().WithLimit(x.pageSize)
+
+	for opts != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, err := x.service.ListAccounts(ctx, x.organizationID, x.ledgerID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+
+		for _, a := range page.Items {
+			listed[a.ID] = a
+		}
+
+		if len(page.Items) == 0 {
+			break
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	result := &AccountIndexSyncResult{}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	for id, a := range listed {
+		current, ok := x.byID[id]
+
+		switch {
+		case !ok:
+			result.Added++
+		case !accountChanged(current, a):
+			continue
+		default:
+			result.Updated++
+		}
+
+		x.put(a)
+	}
+
+	for id := range x.byID {
+		if _, ok := listed[id]; !ok {
+			x.delete(id)
+			result.Removed++
+		}
+	}
+
+	x.lastSync = x.now()
+	result.Total = len(x.byID)
+	result.Duration = x.lastSync.Sub(started)
+
+	return result, nil
+}
+
+// Run syncs the index immediately and then every interval until ctx is done,
+// returning the context error. Sync errors are passed to the handler set with
+// WithSyncErrorHandler and do not stop the loop.
+func (x *AccountIndex) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.NewValidationError("AccountIndex.Run", "interval must be positive", nil)
+	}
+
+	defer lifecycle.Track("entities.AccountIndex.Run")()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := x.Sync(ctx); err != nil && ctx.Err() == nil && x.onSyncError != nil {
+			x.onSyncError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Apply adds or replaces an account, typically from an account created or
+// updated event. Accounts of other ledgers and accounts older than the indexed
+// version are ignored, so events delivered out of order are harmless. It reports
+// whether the index changed.
+func (x *AccountIndex) Apply(account models.Account) bool {
+	if account.ID == "" || (account.LedgerID != "" && account.LedgerID != x.ledgerID) {
+		return false
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if current, ok := x.byID[account.ID]; ok {
+		if account.UpdatedAt.Before(current.UpdatedAt) || !accountChanged(current, account) {
+			return false
+		}
+	}
+
+	x.put(account)
+
+	return true
+}
+
+// Remove drops an account, typically from an account deleted event. It reports
+// whether the account was indexed.
+func (x *AccountIndex) Remove(id string) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if _, ok := x.byID[id]; !ok {
+		return false
+	}
+
+	x.delete(id)
+
+	return true
+}
+
+// Get returns the account with the given ID.
+func (x *AccountIndex) Get(id string) (models.Account, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	a, ok := x.byID[id]
+
+	return a, ok
+}
+
+// ByAlias returns the account with the given alias.
+func (x *AccountIndex) ByAlias(alias string) (models.Account, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	id, ok := x.byAlias[alias]
+	if !ok {
+		return models.Account{}, false
+	}
+
+	return x.byID[id], true
+}
+
+// ByType returns the accounts of an account type, matched case-insensitively.
+func (x *AccountIndex) ByType(accountType string) []models.Account {
+	return x.Filter(AccountIndexFilter{Type: accountType})
+}
+
+// BySegment returns the accounts of a segment.
+func (x *AccountIndex) BySegment(segmentID string) []models.Account {
+	return x.Filter(AccountIndexFilter{SegmentID: segmentID})
+}
+
+// ByPortfolio returns the accounts of a portfolio.
+func (x *AccountIndex) ByPortfolio(portfolioID string) []models.Account {
+	return x.Filter(AccountIndexFilter{PortfolioID: portfolioID})
+}
+
+// Filter returns the accounts matching every set field of f, sorted by ID.
+func (x *AccountIndex) Filter(f AccountIndexFilter) []models.Account {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	// start from the narrowest secondary index available
+	var candidates map[string]struct{}
+
+	for _, set := range []struct {
+		value string
+		index map[string]map[string]struct{}
+	}{
+		{catalogKey(f.Type), x.byType},
+		{f.SegmentID, x.bySegment},
+		{f.PortfolioID, x.byPortfolio},
+	} {
+		if set.value == "" {
+			continue
+		}
+
+		ids := set.index[set.value]
+		if candidates == nil || len(ids) < len(candidates) {
+			candidates = ids
+		}
+
+		if len(ids) == 0 {
+			return []models.Account{}
+		}
+	}
+
+	result := []models.Account{}
+
+	match := func(a models.Account) {
+		if accountMatches(a, f) {
+			result = append(result, a)
+		}
+	}
+
+	if candidates != nil {
+		for id := range candidates {
+			match(x.byID[id])
+		}
+	} else {
+		for _, a := range x.byID {
+			match(a)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	return result
+}
+
+// Len returns the number of indexed accounts.
+func (x *AccountIndex) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return len(x.byID)
+}
+
+// LastSync returns when the last successful sync finished, zero before the first one.
+func (x *AccountIndex) LastSync() time.Time {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return x.lastSync
+}
+
+// put indexes a, replacing any previous version. Callers hold mu.
+func (x *AccountIndex) put(a models.Account) {
+	if _, ok := x.byID[a.ID]; ok {
+		x.delete(a.ID)
+	}
+
+	x.byID[a.ID] = a
+
+	if alias := stringValue(a.Alias); alias != "" {
+		x.byAlias[alias] = a.ID
+	}
+
+	addToIndex(x.byType, catalogKey(a.Type), a.ID)
+	addToIndex(x.bySegment, stringValue(a.SegmentID), a.ID)
+	addToIndex(x.byPortfolio, stringValue(a.PortfolioID), a.ID)
+}
+
+// delete removes an indexed account. Callers hold mu.
+func (x *AccountIndex) delete(id string) {
+	a, ok := x.byID[id]
+	if !ok {
+		return
+	}
+
+	delete(x.byID, id)
+
+	if alias := stringValue(a.Alias); alias != "" && x.byAlias[alias] == id {
+		delete(x.byAlias, alias)
+	}
+
+	removeFromIndex(x.byType, catalogKey(a.Type), id)
+	removeFromIndex(x.bySegment, stringValue(a.SegmentID), id)
+	removeFromIndex(x.byPortfolio, stringValue(a.PortfolioID), id)
+}
+
+func addToIndex(index map[string]map[string]struct{}, key, id string) {
+	if key == "" {
+		return
+	}
+
+	ids, ok := index[key]
+	if !ok {
+		ids = make(map[string]struct{})
+		index[key] = ids
+	}
+
+	ids[id] = struct{}{}
+}
+
+func removeFromIndex(index map[string]map[string]struct{}, key, id string) {
+	ids, ok := index[key]
+	if !ok {
+		return
+	}
+
+	delete(ids, id)
+
+	if len(ids) == 0 {
+		delete(index, key)
+	}
+}
+
+// accountChanged reports whether b differs from a in any indexed or timestamped field.
+func accountChanged(a, b models.Account) bool {
+	return !a.UpdatedAt.Equal(b.UpdatedAt) ||
+		a.Name != b.Name ||
+		a.Type != b.Type ||
+		a.AssetCode != b.AssetCode ||
+		a.Status.Code != b.Status.Code ||
+		stringValue(a.Alias) != stringValue(b.Alias) ||
+		stringValue(a.SegmentID) != stringValue(b.SegmentID) ||
+		stringValue(a.PortfolioID) != stringValue(b.PortfolioID)
+}
+
+func accountMatches(a models.Account, f AccountIndexFilter) bool {
+	return (f.Type == "" || catalogKey(a.Type) == catalogKey(f.Type)) &&
+		(f.SegmentID == "" || stringValue(a.SegmentID) == f.SegmentID) &&
+		(f.PortfolioID == "" || stringValue(a.PortfolioID) == f.PortfolioID) &&
+		(f.AssetCode == "" || a.AssetCode == f.AssetCode) &&
+		(f.Status == "" || a.Status.Code == f.Status)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
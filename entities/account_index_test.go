@@ -0,0 +1,206 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIndexAccountsService struct {
+	AccountsService
+
+	mu       sync.Mutex
+	accounts []models.Account
+	err      error
+	calls    atomic.Int32
+}
+
+func (f *fakeIndexAccountsService) set(accounts ...models.Account) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.accounts = accounts
+}
+
+func (f *fakeIndexAccountsService) ListAccounts(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+	f.calls.Add(1)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	start := min(opts.Offset, len(f.accounts))
+	end := min(start+opts.Limit, len(f.accounts))
+
+	return &models.ListResponse[models.Account]{
+		Items:      append([]models.Account(nil), f.accounts[start:end]...),
+		Pagination: models.Pagination{Limit: opts.Limit, Offset: start, Total: len(f.accounts)},
+	}, nil
+}
+
+var indexEpoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func indexedAccount(id, alias, accountType, segment, portfolio string) models.Account {
+	a := models.Account{ID: id, Name: id, AssetCode: "USD", Type: accountType, Status: models.Status{Code: "ACTIVE"}, UpdatedAt: indexEpoch}
+
+	if alias != "" {
+		a.Alias = &alias
+	}
+
+	if segment != "" {
+		a.SegmentID = &segment
+	}
+
+	if portfolio != "" {
+		a.PortfolioID = &portfolio
+	}
+
+	return a
+}
+
+func accountIDs(accounts []models.Account) []string {
+	ids := make([]string, 0, len(accounts))
+	for _, a := range accounts {
+		ids = append(ids, a.ID)
+	}
+
+	return ids
+}
+
+func TestAccountIndex_SyncAndLookups(t *testing.T) {
+	svc := &fakeIndexAccountsService{}
+	svc.set(
+		indexedAccount("a1", "@alice", "checking", "seg-1", "pf-1"),
+		indexedAccount("a2", "@bob", "Checking", "seg-2", "pf-1"),
+		indexedAccount("a3", "@carol", "savings", "seg-1", ""),
+	)
+
+	index := NewAccountIndex(svc, "org", "ledger").WithPageSize(2)
+
+	result, err := index.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Added)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, int32(2), svc.calls.Load(), "the listing is paginated")
+	assert.False(t, index.LastSync().IsZero())
+
+	a, ok := index.ByAlias("@bob")
+	require.True(t, ok)
+	assert.Equal(t, "a2", a.ID)
+
+	_, ok = index.Get("missing")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"a1", "a2"}, accountIDs(index.ByType("CHECKING")))
+	assert.Equal(t, []string{"a1", "a3"}, accountIDs(index.BySegment("seg-1")))
+	assert.Equal(t, []string{"a1", "a2"}, accountIDs(index.ByPortfolio("pf-1")))
+	assert.Equal(t, []string{"a1"}, accountIDs(index.Filter(AccountIndexFilter{Type: "checking", SegmentID: "seg-1"})))
+	assert.Empty(t, index.Filter(AccountIndexFilter{SegmentID: "seg-9"}))
+	assert.Len(t, index.Filter(AccountIndexFilter{AssetCode: "USD", Status: "ACTIVE"}), 3)
+}
+
+func TestAccountIndex_SyncAppliesDifferences(t *testing.T) {
+	svc := &fakeIndexAccountsService{}
+	svc.set(
+		indexedAccount("a1", "@alice", "checking", "seg-1", ""),
+		indexedAccount("a2", "@bob", "checking", "", ""),
+	)
+
+	index := NewAccountIndex(svc, "org", "ledger")
+
+	_, err := index.Sync(context.Background())
+	require.NoError(t, err)
+
+	moved := indexedAccount("a1", "@alice", "savings", "seg-2", "")
+	moved.UpdatedAt = indexEpoch.Add(time.Hour)
+	svc.set(moved, indexedAccount("a3", "@carol", "checking", "", ""))
+
+	result, err := index.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, AccountIndexSyncResult{Added: 1, Updated: 1, Removed: 1, Total: 2, Duration: result.Duration}, *result)
+
+	_, ok := index.ByAlias("@bob")
+	assert.False(t, ok)
+	assert.Empty(t, index.BySegment("seg-1"))
+	assert.Equal(t, []string{"a1"}, accountIDs(index.BySegment("seg-2")))
+	assert.Equal(t, []string{"a3"}, accountIDs(index.ByType("checking")))
+
+	// an unchanged listing applies nothing
+	result, err = index.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, result.Added+result.Updated+result.Removed)
+}
+
+func TestAccountIndex_SyncErrorKeepsIndex(t *testing.T) {
+	svc := &fakeIndexAccountsService{}
+	svc.set(indexedAccount("a1", "@alice", "checking", "", ""))
+
+	index := NewAccountIndex(svc, "org", "ledger")
+
+	_, err := index.Sync(context.Background())
+	require.NoError(t, err)
+
+	svc.err = errors.New("unavailable")
+
+	_, err = index.Sync(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, 1, index.Len())
+
+	_, err = NewAccountIndex(svc, "", "ledger").Sync(context.Background())
+	assert.Error(t, err)
+}
+
+func TestAccountIndex_ApplyAndRemove(t *testing.T) {
+	index := NewAccountIndex(&fakeIndexAccountsService{}, "org", "ledger")
+
+	a := indexedAccount("a1", "@alice", "checking", "", "")
+	assert.True(t, index.Apply(a))
+	assert.False(t, index.Apply(a), "applying the same version is a no-op")
+
+	renamed := a
+	renamed.Alias = nil
+	renamed.UpdatedAt = indexEpoch.Add(time.Minute)
+	assert.True(t, index.Apply(renamed))
+
+	_, ok := index.ByAlias("@alice")
+	assert.False(t, ok)
+
+	assert.False(t, index.Apply(a), "older versions are ignored")
+
+	other := indexedAccount("x", "", "checking", "", "")
+	other.LedgerID = "another-ledger"
+	assert.False(t, index.Apply(other))
+
+	assert.True(t, index.Remove("a1"))
+	assert.False(t, index.Remove("a1"))
+	assert.Zero(t, index.Len())
+	assert.Empty(t, index.ByType("checking"))
+}
+
+func TestAccountIndex_Run(t *testing.T) {
+	svc := &fakeIndexAccountsService{err: errors.New("unavailable")}
+
+	var failures atomic.Int32
+
+	index := NewAccountIndex(svc, "org", "ledger").WithSyncErrorHandler(func(error) { failures.Add(1) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := index.Run(ctx, 5*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Greater(t, svc.calls.Load(), int32(1))
+	assert.Positive(t, failures.Load())
+
+	assert.Error(t, index.Run(context.Background(), 0))
+}
@@ -0,0 +1,421 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// DefaultAccountTypeCatalogTTL is how long a listed account type catalog is
+// reused before being fetched again.
+const DefaultAccountTypeCatalogTTL = 5 * time.Minute
+
+// AccountTypeCatalog is a snapshot of the account types of a ledger, indexed by
+// key value. Lookups are case-insensitive. A catalog is immutable once built and
+// safe for concurrent use.
+type AccountTypeCatalog struct {
+	// OrganizationID and LedgerID identify the ledger the catalog belongs to
+	OrganizationID string
+	LedgerID       string
+
+	// FetchedAt is when the catalog was listed from the API
+	FetchedAt time.Time
+
+	byKey map[string]models.AccountType
+}
+
+// NewAccountTypeCatalog builds a catalog from a list of account types. When two
+// types share a key value, the last one wins.
+func NewAccountTypeCatalog(organizationID, ledgerID string, types []models.AccountType) *AccountTypeCatalog {
+	c := &AccountTypeCatalog{
+		OrganizationID: organizationID,
+		LedgerID:       ledgerID,
+		FetchedAt:      time.Now(),
+		byKey:          make(map[string]models.AccountType, len(types)),
+	}
+
+	for _, at := range types {
+		c.byKey[catalogKey(at.KeyValue)] = at
+	}
+
+	return c
+}
+
+// Get returns the account type with the given key value.
+func (c *AccountTypeCatalog) Get(key string) (*models.AccountType, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	at, ok := c.byKey[catalogKey(key)]
+	if !ok {
+		return nil, false
+	}
+
+	return &at, true
+}
+
+// Contains reports whether the catalog has an account type with the given key value.
+func (c *AccountTypeCatalog) Contains(key string) bool {
+	_, ok := c.Get(key)
+	return ok
+}
+
+// Keys returns the key values of the catalog, sorted.
+func (c *AccountTypeCatalog) Keys() []string {
+	if c == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(c.byKey))
+	for _, at := range c.byKey {
+		keys = append(keys, at.KeyValue)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Types returns the account types of the catalog, sorted by key value.
+func (c *AccountTypeCatalog) Types() []models.AccountType {
+	if c == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(c.byKey))
+	for k := range c.byKey {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	types := make([]models.AccountType, 0, len(keys))
+	for _, k := range keys {
+		types = append(types, c.byKey[k])
+	}
+
+	return types
+}
+
+// Len returns the number of account types in the catalog.
+func (c *AccountTypeCatalog) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	return len(c.byKey)
+}
+
+// ValidateAccountInput checks that the type of input is a key value of the
+// catalog, so account creation fails fast on ledgers that enforce account types.
+func (c *AccountTypeCatalog) ValidateAccountInput(input *models.CreateAccountInput) error {
+	const operation = "ValidateAccountInput"
+
+	if input == nil {
+		return errors.NewMissingParameterError(operation, "input")
+	}
+
+	if !c.Contains(input.Type) {
+		return errors.NewValidationError(operation,
+			fmt.Sprintf("account type %q is not registered in the ledger (available: %s)", input.Type, strings.Join(c.Keys(), ", ")), nil)
+	}
+
+	return nil
+}
+
+// with returns a copy of the catalog including at.
+func (c *AccountTypeCatalog) with(at models.AccountType) *AccountTypeCatalog {
+	next := &AccountTypeCatalog{
+		OrganizationID: c.OrganizationID,
+		LedgerID:       c.LedgerID,
+		FetchedAt:      c.FetchedAt,
+		byKey:          make(map[string]models.AccountType, len(c.byKey)+1),
+	}
+
+	for k, v := range c.byKey {
+		next.byKey[k] = v
+	}
+
+	next.byKey[catalogKey(at.KeyValue)] = at
+
+	return next
+}
+
+// catalogKey normalizes an account type key value for lookups.
+func catalogKey(key string) string {
+	return strings.ToLower(strings.TrimSpace(key))
+}
+
+// AccountTypeCatalogCache caches the account type catalog of each ledger on top
+// of an AccountTypesService and offers find-by-key and create-if-missing helpers.
+// It is safe for concurrent use: concurrent loads of the same ledger and
+// concurrent creations of the same key value share a single API call.
+//
+// Example:
+//
+//	cache := entities.NewAccountTypeCatalogCache(client.Entity.AccountTypes, 0)
+//
+//	checking, created, err := cache.EnsureAccountType(ctx, orgID, ledgerID,
+//	    models.NewCreateAccountTypeInput("Checking", "checking"))
+//	if err != nil {
+//	    return err
+//	}
+//
+//	catalog, err := cache.Catalog(ctx, orgID, ledgerID)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	if err := catalog.ValidateAccountInput(accountInput); err != nil {
+//	    return err
+//	}
+type AccountTypeCatalogCache struct {
+	service AccountTypesService
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu       sync.Mutex
+	catalogs map[string]*AccountTypeCatalog
+	inflight map[string]*catalogCall
+}
+
+// catalogCall is an API call shared by concurrent callers.
+type catalogCall struct {
+	done    chan struct{}
+	catalog *AccountTypeCatalog
+	at      *models.AccountType
+	created bool
+	err     error
+}
+
+// NewAccountTypeCatalogCache creates a cache over service. Catalogs are listed
+// again after ttl; a zero ttl uses DefaultAccountTypeCatalogTTL and a negative
+// ttl keeps catalogs until Invalidate is called.
+func NewAccountTypeCatalogCache(service AccountTypesService, ttl time.Duration) *AccountTypeCatalogCache {
+	if ttl == 0 {
+		ttl = DefaultAccountTypeCatalogTTL
+	}
+
+	return &AccountTypeCatalogCache{
+		service:  service,
+		ttl:      ttl,
+		now:      time.Now,
+		catalogs: make(map[string]*AccountTypeCatalog),
+		inflight: make(map[string]*catalogCall),
+	}
+}
+
+// Catalog returns the account type catalog of a ledger, listing every page of
+// account types when the cached catalog is missing or expired.
+func (c *AccountTypeCatalogCache) Catalog(ctx context.Context, organizationID, ledgerID string) (*AccountTypeCatalog, error) {
+	const operation = "AccountTypeCatalog"
+
+	if organizationID == "" {
+		return nil, errors.NewMissingParameterError(operation, "organizationID")
+	}
+
+	if ledgerID == "" {
+		return nil, errors.NewMissingParameterError(operation, "ledgerID")
+	}
+
+	ledgerKey := organizationID + "/" + ledgerID
+
+	c.mu.Lock()
+	if catalog, ok := c.catalogs[ledgerKey]; ok && !c.expired(catalog) {
+		c.mu.Unlock()
+		return catalog, nil
+	}
+	c.mu.Unlock()
+
+	call := c.do("list:"+ledgerKey, func(call *catalogCall) {
+		call.catalog, call.err = c.load(ctx, organizationID, ledgerID)
+		if call.err != nil {
+			return
+		}
+
+		c.mu.Lock()
+		c.catalogs[ledgerKey] = call.catalog
+		c.mu.Unlock()
+	})
+
+	return call.catalog, call.err
+}
+
+// FindByKey returns the account type of a ledger with the given key value. It
+// returns a not found error when the ledger has no such account type.
+func (c *AccountTypeCatalogCache) FindByKey(ctx context.Context, organizationID, ledgerID, key string) (*models.AccountType, error) {
+	const operation = "FindAccountTypeByKey"
+
+	if key == "" {
+		return nil, errors.NewMissingParameterError(operation, "key")
+	}
+
+	catalog, err := c.Catalog(ctx, organizationID, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+
+	at, ok := catalog.Get(key)
+	if !ok {
+		return nil, errors.NewNotFoundError(operation, "account type", key, nil)
+	}
+
+	return at, nil
+}
+
+// EnsureAccountType returns the account type with the key value of input,
+// creating it when the ledger does not have it yet. created reports whether this
+// call created it. A conflict from the API, raised when another client created
+// the same key value first, is resolved by reloading the catalog.
+func (c *AccountTypeCatalogCache) EnsureAccountType(ctx context.Context, organizationID, ledgerID string, input *models.CreateAccountTypeInput) (at *models.AccountType, created bool, err error) {
+	const operation = "EnsureAccountType"
+
+	if input == nil {
+		return nil, false, errors.NewMissingParameterError(operation, "input")
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, false, errors.NewValidationError(operation, "account type validation failed", err)
+	}
+
+	existing, err := c.FindByKey(ctx, organizationID, ledgerID, input.KeyValue)
+	if err == nil {
+		return existing, false, nil
+	}
+
+	if !errors.IsNotFoundError(err) {
+		return nil, false, err
+	}
+
+	ledgerKey := organizationID + "/" + ledgerID
+	leader := false
+
+	call := c.do("create:"+ledgerKey+"/"+catalogKey(input.KeyValue), func(call *catalogCall) {
+		leader = true
+		call.at, call.created, call.err = c.create(ctx, organizationID, ledgerID, input)
+	})
+
+	return call.at, leader && call.created, call.err
+}
+
+// Invalidate drops the cached catalog of a ledger so the next lookup lists it again.
+func (c *AccountTypeCatalogCache) Invalidate(organizationID, ledgerID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.catalogs, organizationID+"/"+ledgerID)
+}
+
+// create creates an account type and records it in the cached catalog, falling
+// back to the API catalog when the key value already exists.
+func (c *AccountTypeCatalogCache) create(ctx context.Context, organizationID, ledgerID string, input *models.CreateAccountTypeInput) (*models.AccountType, bool, error) {
+	// a creation that finished since the caller's lookup is already cached
+	c.mu.Lock()
+	existing, ok := c.catalogs[organizationID+"/"+ledgerID].Get(input.KeyValue)
+	c.mu.Unlock()
+
+	if ok {
+		return existing, false, nil
+	}
+
+	at, err := c.service.CreateAccountType(ctx, organizationID, ledgerID, input)
+	if err == nil {
+		c.remember(organizationID, ledgerID, *at)
+		return at, true, nil
+	}
+
+	if !errors.IsConflictError(err) {
+		return nil, false, err
+	}
+
+	c.Invalidate(organizationID, ledgerID)
+
+	existing, findErr := c.FindByKey(ctx, organizationID, ledgerID, input.KeyValue)
+	if findErr != nil {
+		return nil, false, err
+	}
+
+	return existing, false, nil
+}
+
+// remember adds at to the cached catalog of its ledger, if one is cached.
+func (c *AccountTypeCatalogCache) remember(organizationID, ledgerID string, at models.AccountType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ledgerKey := organizationID + "/" + ledgerID
+	if catalog, ok := c.catalogs[ledgerKey]; ok {
+		c.catalogs[ledgerKey] = catalog.with(at)
+	}
+}
+
+// load lists every page of account types of a ledger.
+func (c *AccountTypeCatalogCache) load(ctx context.Context, organizationID, ledgerID string) (*AccountTypeCatalog, error) {
+	if c.service == nil {
+		return nil, errors.NewInternalError("AccountTypeCatalog", fmt.Errorf("account types service not initialized"))
+	}
+
+	var types []models.AccountType
+
+	opts := models.NewListOptions().WithLimit(models.MaxLimit)
+
+	for opts != nil {
+		page, err := c.service.ListAccountTypes(ctx, organizationID, ledgerID, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		types = append(types, page.Items...)
+
+		if len(page.Items) == 0 {
+			break
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	catalog := NewAccountTypeCatalog(organizationID, ledgerID, types)
+	catalog.FetchedAt = c.now()
+
+	return catalog, nil
+}
+
+// expired reports whether catalog is older than the cache ttl.
+func (c *AccountTypeCatalogCache) expired(catalog *AccountTypeCatalog) bool {
+	return c.ttl > 0 && c.now().Sub(catalog.FetchedAt) >= c.ttl
+}
+
+// do runs fn once for concurrent callers sharing key; later callers wait for
+// the first one and receive its result.
+func (c *AccountTypeCatalogCache) do(key string, fn func(call *catalogCall)) *catalogCall {
+	c.mu.Lock()
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+
+		return call
+	}
+
+	call := &catalogCall{done: make(chan struct{})}
+	c.inflight[key] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+
+		close(call.done)
+	}()
+
+	fn(call)
+
+	return call
+}
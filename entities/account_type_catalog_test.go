@@ -0,0 +1,229 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAccountTypesService is an in-memory AccountTypesService that counts calls.
+type fakeAccountTypesService struct {
+	AccountTypesService
+
+	mu       sync.Mutex
+	types    []models.AccountType
+	pageSize int
+	lists    atomic.Int32
+	creates  atomic.Int32
+
+	// createDelay widens the window for concurrent creations
+	createDelay time.Duration
+
+	// conflictOnCreate simulates another client creating the key value first
+	conflictOnCreate bool
+}
+
+func (f *fakeAccountTypesService) ListAccountTypes(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.AccountType], error) {
+	f.lists.Add(1)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	size := f.pageSize
+	if size == 0 {
+		size = len(f.types) + 1
+	}
+
+	start := min(opts.Offset, len(f.types))
+	end := min(start+size, len(f.types))
+
+	return &models.ListResponse[models.AccountType]{
+		Items:      append([]models.AccountType(nil), f.types[start:end]...),
+		Pagination: models.Pagination{Limit: size, Offset: start, Total: len(f.types)},
+	}, nil
+}
+
+func (f *fakeAccountTypesService) CreateAccountType(_ context.Context, _, _ string, input *models.CreateAccountTypeInput) (*models.AccountType, error) {
+	f.creates.Add(1)
+	time.Sleep(f.createDelay)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	at := models.AccountType{Name: input.Name, KeyValue: input.KeyValue}
+	f.types = append(f.types, at)
+
+	if f.conflictOnCreate {
+		return nil, errors.NewConflictError("CreateAccountType", "account type", input.KeyValue, nil)
+	}
+
+	return &at, nil
+}
+
+func accountTypesWithKeys(keys ...string) []models.AccountType {
+	types := make([]models.AccountType, len(keys))
+	for i, k := range keys {
+		types[i] = models.AccountType{Name: k, KeyValue: k}
+	}
+
+	return types
+}
+
+func TestAccountTypeCatalog_Lookups(t *testing.T) {
+	catalog := NewAccountTypeCatalog("org", "ledger", accountTypesWithKeys("savings", "checking"))
+
+	at, ok := catalog.Get("CHECKING")
+	require.True(t, ok)
+	assert.Equal(t, "checking", at.KeyValue)
+
+	assert.False(t, catalog.Contains("credit_card"))
+	assert.Equal(t, []string{"checking", "savings"}, catalog.Keys())
+	assert.Equal(t, 2, catalog.Len())
+	assert.Equal(t, "checking", catalog.Types()[0].KeyValue)
+
+	var nilCatalog *AccountTypeCatalog
+	assert.False(t, nilCatalog.Contains("checking"))
+	assert.Zero(t, nilCatalog.Len())
+}
+
+func TestAccountTypeCatalog_ValidateAccountInput(t *testing.T) {
+	catalog := NewAccountTypeCatalog("org", "ledger", accountTypesWithKeys("checking"))
+
+	require.NoError(t, catalog.ValidateAccountInput(models.NewCreateAccountInput("Main", "USD", "checking")))
+
+	err := catalog.ValidateAccountInput(models.NewCreateAccountInput("Main", "USD", "wallet"))
+	require.Error(t, err)
+	assert.True(t, errors.IsValidationError(err))
+	assert.Contains(t, err.Error(), "wallet")
+
+	assert.Error(t, catalog.ValidateAccountInput(nil))
+}
+
+func TestAccountTypeCatalogCache_CatalogPaginatesAndCaches(t *testing.T) {
+	svc := &fakeAccountTypesService{types: accountTypesWithKeys("a", "b", "c", "d", "e"), pageSize: 2}
+	cache := NewAccountTypeCatalogCache(svc, time.Minute)
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	catalog, err := cache.Catalog(context.Background(), "org", "ledger")
+	require.NoError(t, err)
+	assert.Equal(t, 5, catalog.Len())
+	assert.Equal(t, int32(3), svc.lists.Load())
+
+	_, err = cache.Catalog(context.Background(), "org", "ledger")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), svc.lists.Load(), "cached catalog should be reused")
+
+	now = now.Add(time.Minute)
+
+	_, err = cache.Catalog(context.Background(), "org", "ledger")
+	require.NoError(t, err)
+	assert.Equal(t, int32(6), svc.lists.Load(), "expired catalog should be listed again")
+
+	cache.Invalidate("org", "ledger")
+
+	_, err = cache.Catalog(context.Background(), "org", "ledger")
+	require.NoError(t, err)
+	assert.Equal(t, int32(9), svc.lists.Load())
+}
+
+func TestAccountTypeCatalogCache_FindByKey(t *testing.T) {
+	cache := NewAccountTypeCatalogCache(&fakeAccountTypesService{types: accountTypesWithKeys("checking")}, 0)
+
+	at, err := cache.FindByKey(context.Background(), "org", "ledger", "checking")
+	require.NoError(t, err)
+	assert.Equal(t, "checking", at.KeyValue)
+
+	_, err = cache.FindByKey(context.Background(), "org", "ledger", "savings")
+	assert.True(t, errors.IsNotFoundError(err))
+
+	_, err = cache.FindByKey(context.Background(), "", "ledger", "checking")
+	assert.ErrorContains(t, err, "organizationID")
+}
+
+func TestAccountTypeCatalogCache_EnsureAccountType(t *testing.T) {
+	svc := &fakeAccountTypesService{types: accountTypesWithKeys("checking")}
+	cache := NewAccountTypeCatalogCache(svc, 0)
+	ctx := context.Background()
+
+	at, created, err := cache.EnsureAccountType(ctx, "org", "ledger", models.NewCreateAccountTypeInput("Checking", "checking"))
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "checking", at.KeyValue)
+	assert.Zero(t, svc.creates.Load())
+
+	at, created, err = cache.EnsureAccountType(ctx, "org", "ledger", models.NewCreateAccountTypeInput("Savings", "savings"))
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "savings", at.KeyValue)
+
+	// the created type is added to the cached catalog without listing again
+	lists := svc.lists.Load()
+	_, err = cache.FindByKey(ctx, "org", "ledger", "savings")
+	require.NoError(t, err)
+	assert.Equal(t, lists, svc.lists.Load())
+
+	_, _, err = cache.EnsureAccountType(ctx, "org", "ledger", models.NewCreateAccountTypeInput("", "x"))
+	assert.True(t, errors.IsValidationError(err))
+}
+
+func TestAccountTypeCatalogCache_EnsureAccountTypeConcurrent(t *testing.T) {
+	svc := &fakeAccountTypesService{createDelay: 20 * time.Millisecond}
+	cache := NewAccountTypeCatalogCache(svc, 0)
+
+	const callers = 10
+
+	var (
+		wg      sync.WaitGroup
+		created atomic.Int32
+	)
+
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			at, ok, err := cache.EnsureAccountType(context.Background(), "org", "ledger", models.NewCreateAccountTypeInput("Wallet", "wallet"))
+			assert.NoError(t, err)
+			assert.Equal(t, "wallet", at.KeyValue)
+
+			if ok {
+				created.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), svc.creates.Load())
+	assert.Equal(t, int32(1), created.Load())
+}
+
+func TestAccountTypeCatalogCache_EnsureAccountTypeConflict(t *testing.T) {
+	svc := &fakeAccountTypesService{conflictOnCreate: true}
+	cache := NewAccountTypeCatalogCache(svc, 0)
+
+	at, created, err := cache.EnsureAccountType(context.Background(), "org", "ledger", models.NewCreateAccountTypeInput("Wallet", "wallet"))
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "wallet", at.KeyValue)
+	assert.Equal(t, int32(2), svc.lists.Load(), "conflict should reload the catalog")
+}
+
+func TestAccountTypeCatalogCache_ListError(t *testing.T) {
+	cache := NewAccountTypeCatalogCache(nil, 0)
+
+	_, err := cache.Catalog(context.Background(), "org", "ledger")
+	require.Error(t, err)
+	assert.Contains(t, fmt.Sprint(err), "not initialized")
+}
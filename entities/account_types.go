@@ -107,8 +107,9 @@ type accountTypesEntity struct {
 	baseURLs   map[string]string
 }
 
-func (e *accountTypesEntity) setDefaultTenantID(tenantID string) {
-	e.httpClient.SetTenantID(tenantID)
+// useSettings makes the service share the settings of its Entity.
+func (e *accountTypesEntity) useSettings(settings *clientSettings) {
+	e.httpClient.clientSettings = settings
 }
 
 // NewAccountTypesEntity creates a new account types entity.
@@ -262,7 +263,7 @@ func (e *accountTypesEntity) CreateAccountType(ctx context.Context, organization
 	}
 
 	// Validate input
-	if err := input.Validate(); err != nil {
+	if err := e.httpClient.validateInput(ctx, operation, input.Validate); err != nil {
 		return nil, errors.NewValidationError(operation, "account type validation failed", err)
 	}
 
@@ -307,7 +308,7 @@ func (e *accountTypesEntity) UpdateAccountType(ctx context.Context, organization
 	}
 
 	// Validate input
-	if err := input.Validate(); err != nil {
+	if err := e.httpClient.validateInput(ctx, operation, input.Validate); err != nil {
 		return nil, errors.NewValidationError(operation, "account type validation failed", err)
 	}
 
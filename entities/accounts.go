@@ -36,6 +36,12 @@ type AccountsService interface {
 	// Returns the account if found, or an error if the operation fails or the account doesn't exist.
 	GetAccountByAlias(ctx context.Context, organizationID, ledgerID, alias string) (*models.Account, error)
 
+	// FindByAlias retrieves the account of a ledger whose alias is exactly alias.
+	// The organizationID and ledgerID parameters specify which organization and ledger to search.
+	// Unlike GetAccountByAlias, accounts returned by the alias filter are checked for an exact match.
+	// Returns the account if found, or an error matched by errors.IsNotFoundError if no account has the alias.
+	FindByAlias(ctx context.Context, organizationID, ledgerID, alias string) (*models.Account, error)
+
 	// CreateAccount creates a new account in the specified ledger.
 	//
 	// This method creates a new account in the specified organization and ledger.
@@ -167,8 +173,9 @@ type accountsEntity struct {
 	baseURLs   map[string]string
 }
 
-func (e *accountsEntity) setDefaultTenantID(tenantID string) {
-	e.httpClient.SetTenantID(tenantID)
+// useSettings makes the service share the settings of its Entity.
+func (e *accountsEntity) useSettings(settings *clientSettings) {
+	e.httpClient.clientSettings = settings
 }
 
 // NewAccountsEntity creates a new accounts entity.
@@ -330,6 +337,30 @@ func (e *accountsEntity) GetAccountByAlias(ctx context.Context, organizationID,
 	return &accounts.Items[0], nil
 }
 
+// FindByAlias gets the account whose alias is exactly alias.
+func (e *accountsEntity) FindByAlias(ctx context.Context, organizationID, ledgerID, alias string) (*models.Account, error) {
+	const operation = "FindByAlias"
+
+	if alias == "" {
+		return nil, errors.NewMissingParameterError(operation, "alias")
+	}
+
+	opts := models.NewListOptions().WithFilter("alias", alias)
+
+	page, err := e.ListAccounts(ctx, organizationID, ledgerID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range page.Items {
+		if a := page.Items[i].Alias; a != nil && *a == alias {
+			return &page.Items[i], nil
+		}
+	}
+
+	return nil, errors.NewNotFoundError(operation, "account", alias, nil)
+}
+
 // CreateAccount creates a new account in the specified ledger.
 func (e *accountsEntity) CreateAccount(ctx context.Context, organizationID, ledgerID string, input *models.CreateAccountInput) (*models.Account, error) {
 	const operation = "CreateAccount"
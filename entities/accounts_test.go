@@ -19,6 +19,9 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// The mock must be regenerated when AccountsService changes.
+var _ AccountsService = (*mocks.MockAccountsService)(nil)
+
 func TestListAccounts(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -600,6 +603,7 @@ func newHTTPClientAdapter(mock *MockHTTPClient) *HTTPClient {
 	_ = retry.WithRetryableHTTPCodes(retry.DefaultRetryableHTTPCodes)(retryOptions)
 
 	return &HTTPClient{
+		clientSettings: &clientSettings{},
 		client: &http.Client{
 			Transport: &mockTransport{mock: mock},
 		},
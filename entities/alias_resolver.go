@@ -0,0 +1,368 @@
+package entities
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// ErrUnknownAlias is the sentinel matched by errors.Is for an UnknownAliasError.
+var ErrUnknownAlias = stderrors.New("unknown account alias")
+
+// UnknownAliasError is returned when account aliases referenced by a
+// transaction don't exist in the ledger. It lists every unresolved alias, so
+// all of them can be fixed at once.
+//
+// Example:
+//
+//	var unknown *entities.UnknownAliasError
+//	if errors.As(err, &unknown) {
+//	    log.Printf("unknown accounts: %v", unknown.Aliases)
+//	}
+type UnknownAliasError struct {
+	// Aliases are the unresolved aliases, sorted
+	Aliases []string
+}
+
+// Error implements the error interface.
+func (e *UnknownAliasError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrUnknownAlias, strings.Join(e.Aliases, ", "))
+}
+
+// Is reports whether target is ErrUnknownAlias.
+func (*UnknownAliasError) Is(target error) bool {
+	return target == ErrUnknownAlias
+}
+
+// IsAliasReference reports whether an account reference of a transaction is an
+// alias ("@person1") rather than an account ID.
+func IsAliasReference(ref string) bool {
+	return strings.HasPrefix(ref, "@")
+}
+
+// AliasResolver checks the account aliases referenced by transactions against
+// a ledger before submission, and optionally replaces them with account IDs.
+//
+// Without it, a typo in an alias is only reported by the server, one alias per
+// failed request. The resolver looks up every alias of a transaction and
+// reports all unknown ones in a single UnknownAliasError. Resolved aliases are
+// cached for the lifetime of the resolver; an AccountIndex, when set, answers
+// lookups from memory before the API is called.
+//
+// Example:
+//
+//	resolver := entities.NewAliasResolver(client.Entity.Accounts, orgID, ledgerID)
+//
+//	input, err := resolver.ResolveDSL(ctx, input)
+//	if err != nil {
+//	    return err // *UnknownAliasError listing every unknown alias
+//	}
+//
+//	tx, err := client.Entity.Transactions.CreateTransactionWithDSL(ctx, orgID, ledgerID, input)
+type AliasResolver struct {
+	accounts       AccountsService
+	index          *AccountIndex
+	organizationID string
+	ledgerID       string
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// NewAliasResolver creates a resolver of the aliases of a ledger.
+func NewAliasResolver(accounts AccountsService, organizationID, ledgerID string) *AliasResolver {
+	return &AliasResolver{
+		accounts:       accounts,
+		organizationID: organizationID,
+		ledgerID:       ledgerID,
+		cache:          make(map[string]string),
+	}
+}
+
+// WithIndex makes the resolver consult index before calling the API. Aliases
+// missing from the index are still looked up, so a stale index only costs a call.
+func (r *AliasResolver) WithIndex(index *AccountIndex) *AliasResolver {
+	r.index = index
+	return r
+}
+
+// Resolve returns the account ID of each alias. Unknown aliases are reported
+// together in an UnknownAliasError; any other lookup failure is returned as is.
+func (r *AliasResolver) Resolve(ctx context.Context, aliases ...string) (map[string]string, error) {
+	const operation = "ResolveAliases"
+
+	if r.accounts == nil {
+		return nil, errors.NewMissingParameterError(operation, "accounts service")
+	}
+
+	ids := make(map[string]string, len(aliases))
+
+	var unknown []string
+
+	for _, alias := range aliases {
+		if _, done := ids[alias]; done {
+			continue
+		}
+
+		id, err := r.lookup(ctx, alias)
+		if errors.IsNotFoundError(err) {
+			ids[alias] = ""
+			unknown = append(unknown, alias)
+
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		ids[alias] = id
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, &UnknownAliasError{Aliases: unknown}
+	}
+
+	return ids, nil
+}
+
+// lookup returns the account ID of an alias from the cache, the index or the API.
+func (r *AliasResolver) lookup(ctx context.Context, alias string) (string, error) {
+	r.mu.Lock()
+	id, ok := r.cache[alias]
+	r.mu.Unlock()
+
+	if ok {
+		return id, nil
+	}
+
+	if r.index != nil {
+		if account, found := r.index.ByAlias(alias); found {
+			id = account.ID
+		}
+	}
+
+	if id == "" {
+		account, err := r.accounts.GetAccountByAlias(ctx, r.organizationID, r.ledgerID, alias)
+		if err != nil {
+			return "", err
+		}
+
+		id = account.ID
+	}
+
+	r.mu.Lock()
+	r.cache[alias] = id
+	r.mu.Unlock()
+
+	return id, nil
+}
+
+// ValidateDSL checks that every alias referenced by a DSL transaction exists.
+func (r *AliasResolver) ValidateDSL(ctx context.Context, input *models.TransactionDSLInput) error {
+	_, err := r.Resolve(ctx, dslAliases(input)...)
+	return err
+}
+
+// ResolveDSL returns a copy of a DSL transaction with its aliases replaced by
+// account IDs. The input is not modified.
+func (r *AliasResolver) ResolveDSL(ctx context.Context, input *models.TransactionDSLInput) (*models.TransactionDSLInput, error) {
+	if input == nil {
+		return nil, errors.NewMissingParameterError("ResolveDSL", "input")
+	}
+
+	ids, err := r.Resolve(ctx, dslAliases(input)...)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *input
+	if input.Send == nil {
+		return &resolved, nil
+	}
+
+	send := *input.Send
+	resolved.Send = &send
+
+	if input.Send.Source != nil {
+		source := *input.Send.Source
+		source.Remaining = resolveRef(ids, source.Remaining)
+		source.From = resolveDSLEntries(ids, source.From)
+		send.Source = &source
+	}
+
+	if input.Send.Distribute != nil {
+		distribute := *input.Send.Distribute
+		distribute.Remaining = resolveRef(ids, distribute.Remaining)
+		distribute.To = resolveDSLEntries(ids, distribute.To)
+		send.Distribute = &distribute
+	}
+
+	return &resolved, nil
+}
+
+// ValidateTransaction checks that every alias referenced by a transaction exists.
+func (r *AliasResolver) ValidateTransaction(ctx context.Context, input *models.CreateTransactionInput) error {
+	_, err := r.Resolve(ctx, transactionAliases(input)...)
+	return err
+}
+
+// ResolveTransaction returns a copy of a transaction with its aliases replaced
+// by account IDs. Operations given only by alias get their AccountID set. The
+// input is not modified.
+func (r *AliasResolver) ResolveTransaction(ctx context.Context, input *models.CreateTransactionInput) (*models.CreateTransactionInput, error) {
+	if input == nil {
+		return nil, errors.NewMissingParameterError("ResolveTransaction", "input")
+	}
+
+	ids, err := r.Resolve(ctx, transactionAliases(input)...)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *input
+
+	if input.Send != nil {
+		send := *input.Send
+		resolved.Send = &send
+
+		if input.Send.Source != nil {
+			send.Source = &models.SourceInput{From: resolveEntries(ids, input.Send.Source.From)}
+		}
+
+		if input.Send.Distribute != nil {
+			send.Distribute = &models.DistributeInput{To: resolveEntries(ids, input.Send.Distribute.To)}
+		}
+	}
+
+	if input.Operations != nil {
+		resolved.Operations = make([]models.CreateOperationInput, len(input.Operations))
+
+		for i, op := range input.Operations {
+			if op.AccountID == "" && op.AccountAlias != nil {
+				op.AccountID = ids[*op.AccountAlias]
+			} else {
+				op.AccountID = resolveRef(ids, op.AccountID)
+			}
+
+			resolved.Operations[i] = op
+		}
+	}
+
+	return &resolved, nil
+}
+
+// dslAliases returns the aliases referenced by a DSL transaction.
+func dslAliases(input *models.TransactionDSLInput) []string {
+	if input == nil || input.Send == nil {
+		return nil
+	}
+
+	var refs []string
+
+	if source := input.Send.Source; source != nil {
+		refs = append(refs, source.Remaining)
+		for _, from := range source.From {
+			refs = append(refs, from.Account)
+		}
+	}
+
+	if distribute := input.Send.Distribute; distribute != nil {
+		refs = append(refs, distribute.Remaining)
+		for _, to := range distribute.To {
+			refs = append(refs, to.Account)
+		}
+	}
+
+	return filterAliases(refs)
+}
+
+// transactionAliases returns the aliases referenced by a transaction.
+func transactionAliases(input *models.CreateTransactionInput) []string {
+	if input == nil {
+		return nil
+	}
+
+	var refs []string
+
+	if send := input.Send; send != nil {
+		if send.Source != nil {
+			for _, from := range send.Source.From {
+				refs = append(refs, from.Account)
+			}
+		}
+
+		if send.Distribute != nil {
+			for _, to := range send.Distribute.To {
+				refs = append(refs, to.Account)
+			}
+		}
+	}
+
+	for _, op := range input.Operations {
+		if op.AccountID == "" && op.AccountAlias != nil {
+			refs = append(refs, *op.AccountAlias)
+		} else {
+			refs = append(refs, op.AccountID)
+		}
+	}
+
+	return filterAliases(refs)
+}
+
+// filterAliases keeps the alias references of refs.
+func filterAliases(refs []string) []string {
+	aliases := refs[:0]
+
+	for _, ref := range refs {
+		if IsAliasReference(ref) {
+			aliases = append(aliases, ref)
+		}
+	}
+
+	return aliases
+}
+
+// resolveRef returns the account ID of ref when it is a resolved alias, ref otherwise.
+func resolveRef(ids map[string]string, ref string) string {
+	if id, ok := ids[ref]; ok {
+		return id
+	}
+
+	return ref
+}
+
+func resolveDSLEntries(ids map[string]string, entries []models.DSLFromTo) []models.DSLFromTo {
+	if entries == nil {
+		return nil
+	}
+
+	resolved := make([]models.DSLFromTo, len(entries))
+	for i, entry := range entries {
+		entry.Account = resolveRef(ids, entry.Account)
+		resolved[i] = entry
+	}
+
+	return resolved
+}
+
+func resolveEntries(ids map[string]string, entries []models.FromToInput) []models.FromToInput {
+	if entries == nil {
+		return nil
+	}
+
+	resolved := make([]models.FromToInput, len(entries))
+	for i, entry := range entries {
+		entry.Account = resolveRef(ids, entry.Account)
+		resolved[i] = entry
+	}
+
+	return resolved
+}
@@ -0,0 +1,131 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAliasAccountsService struct {
+	AccountsService
+
+	ids     map[string]string
+	lookups []string
+	err     error
+}
+
+func (f *fakeAliasAccountsService) GetAccountByAlias(_ context.Context, _, _, alias string) (*models.Account, error) {
+	f.lookups = append(f.lookups, alias)
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	id, ok := f.ids[alias]
+	if !ok {
+		return nil, sdkerrors.NewNotFoundError("GetAccountByAlias", "account", alias, nil)
+	}
+
+	return &models.Account{ID: id, Alias: &alias}, nil
+}
+
+func aliasDSLInput(source, destination string) *models.TransactionDSLInput {
+	return &models.TransactionDSLInput{
+		Send: &models.DSLSend{
+			Asset: "USD",
+			Value: "100",
+			Source: &models.DSLSource{
+				From: []models.DSLFromTo{{Account: source, Amount: &models.DSLAmount{Asset: "USD", Value: "100"}}},
+			},
+			Distribute: &models.DSLDistribute{
+				To: []models.DSLFromTo{{Account: destination, Amount: &models.DSLAmount{Asset: "USD", Value: "100"}}},
+			},
+		},
+	}
+}
+
+func TestAliasResolver_ResolveDSL(t *testing.T) {
+	accounts := &fakeAliasAccountsService{ids: map[string]string{"@alice": "acc-alice", "@bob": "acc-bob"}}
+	resolver := NewAliasResolver(accounts, "org", "ledger")
+
+	input := aliasDSLInput("@alice", "@bob")
+
+	resolved, err := resolver.ResolveDSL(context.Background(), input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "acc-alice", resolved.Send.Source.From[0].Account)
+	assert.Equal(t, "acc-bob", resolved.Send.Distribute.To[0].Account)
+	assert.Equal(t, "@alice", input.Send.Source.From[0].Account, "input is not modified")
+
+	// IDs are left as is and resolved aliases are cached
+	require.NoError(t, resolver.ValidateDSL(context.Background(), aliasDSLInput("@alice", "acc-carol")))
+	assert.Equal(t, []string{"@alice", "@bob"}, accounts.lookups)
+}
+
+func TestAliasResolver_UnknownAliases(t *testing.T) {
+	accounts := &fakeAliasAccountsService{ids: map[string]string{"@alice": "acc-alice"}}
+	resolver := NewAliasResolver(accounts, "org", "ledger")
+
+	input := aliasDSLInput("@zed", "@bob")
+	input.Send.Distribute.To = append(input.Send.Distribute.To, models.DSLFromTo{Account: "@alice"}, models.DSLFromTo{Account: "@bob"})
+
+	_, err := resolver.ResolveDSL(context.Background(), input)
+	require.Error(t, err)
+
+	var unknown *UnknownAliasError
+	require.True(t, errors.As(err, &unknown))
+	assert.Equal(t, []string{"@bob", "@zed"}, unknown.Aliases)
+	assert.ErrorIs(t, err, ErrUnknownAlias)
+	assert.Equal(t, "unknown account alias: @bob, @zed", err.Error())
+
+	// other failures are not reported as unknown aliases
+	accounts.err = sdkerrors.NewNetworkError("GetAccountByAlias", errors.New("connection refused"))
+
+	err = NewAliasResolver(accounts, "org", "ledger").ValidateDSL(context.Background(), aliasDSLInput("@alice", "@bob"))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnknownAlias)
+}
+
+func TestAliasResolver_ResolveTransaction(t *testing.T) {
+	accounts := &fakeAliasAccountsService{ids: map[string]string{"@alice": "acc-alice", "@bob": "acc-bob"}}
+	resolver := NewAliasResolver(accounts, "org", "ledger")
+
+	alias := "@bob"
+	input := &models.CreateTransactionInput{
+		Send: &models.SendInput{
+			Asset:      "USD",
+			Value:      "10",
+			Source:     &models.SourceInput{From: []models.FromToInput{{Account: "@alice"}}},
+			Distribute: &models.DistributeInput{To: []models.FromToInput{{Account: "acc-carol"}}},
+		},
+		Operations: []models.CreateOperationInput{{AccountAlias: &alias}, {AccountID: "@alice"}},
+	}
+
+	resolved, err := resolver.ResolveTransaction(context.Background(), input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "acc-alice", resolved.Send.Source.From[0].Account)
+	assert.Equal(t, "acc-carol", resolved.Send.Distribute.To[0].Account)
+	assert.Equal(t, "acc-bob", resolved.Operations[0].AccountID)
+	assert.Equal(t, "acc-alice", resolved.Operations[1].AccountID)
+	assert.Equal(t, "@alice", input.Send.Source.From[0].Account, "input is not modified")
+	assert.Empty(t, input.Operations[0].AccountID)
+}
+
+func TestAliasResolver_WithIndex(t *testing.T) {
+	index := NewAccountIndex(&fakeIndexAccountsService{}, "org", "ledger")
+	index.Apply(indexedAccount("acc-alice", "@alice", "deposit", "", ""))
+
+	accounts := &fakeAliasAccountsService{ids: map[string]string{"@bob": "acc-bob"}}
+
+	ids, err := NewAliasResolver(accounts, "org", "ledger").WithIndex(index).Resolve(context.Background(), "@alice", "@bob")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"@alice": "acc-alice", "@bob": "acc-bob"}, ids)
+	assert.Equal(t, []string{"@bob"}, accounts.lookups)
+}
@@ -0,0 +1,368 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// Asset statuses set by FreezeAsset and UnfreezeAsset.
+const (
+	AssetStatusSuspended = "SUSPENDED"
+	AssetStatusActive    = "ACTIVE"
+)
+
+// Metadata keys FreezeAsset sets on the assets it suspends, removed by
+// UnfreezeAsset.
+const (
+	MetadataFrozenReason         = "frozenReason"
+	MetadataFrozenAt             = "frozenAt"
+	MetadataFrozenPreviousStatus = "frozenPreviousStatus"
+)
+
+// ErrAssetFrozen is the sentinel matched by errors.Is for an AssetFrozenError.
+var ErrAssetFrozen = errors.New("asset frozen")
+
+// AssetFrozenError is returned when a transaction moves a frozen asset. The
+// transaction is rejected before any request is sent.
+//
+// Example:
+//
+//	_, err := entity.Transactions.CreateTransaction(ctx, orgID, ledgerID, input)
+//	if errors.Is(err, entities.ErrAssetFrozen) {
+//	    log.Println("trading is halted for this asset")
+//	}
+type AssetFrozenError struct {
+	// Operation is the rejected SDK operation, such as "CreateTransaction"
+	Operation string
+
+	FrozenAsset
+}
+
+// Error implements the error interface.
+func (e *AssetFrozenError) Error() string {
+	msg := fmt.Sprintf("%s: %s rejected, %s is suspended in ledger %s", ErrAssetFrozen, e.Operation, e.AssetCode, e.LedgerID)
+	if e.Reason != "" {
+		msg += " (" + e.Reason + ")"
+	}
+
+	return msg
+}
+
+// Is reports whether target is ErrAssetFrozen.
+func (*AssetFrozenError) Is(target error) bool {
+	return target == ErrAssetFrozen
+}
+
+// FrozenAsset is an asset whose transactions are rejected.
+type FrozenAsset struct {
+	OrganizationID string
+	LedgerID       string
+	AssetCode      string
+	Reason         string
+	Since          time.Time
+}
+
+// frozenAssetKey identifies an asset of a ledger.
+type frozenAssetKey struct {
+	orgID, ledgerID, code string
+}
+
+// AssetFreezes is the set of frozen assets every Entity checks the
+// transactions it creates against. It is safe for concurrent use.
+type AssetFreezes struct {
+	mu     sync.RWMutex
+	frozen map[frozenAssetKey]FrozenAsset
+}
+
+// NewAssetFreezes returns an empty set of frozen assets.
+func NewAssetFreezes() *AssetFreezes {
+	return &AssetFreezes{frozen: map[frozenAssetKey]FrozenAsset{}}
+}
+
+// Freeze makes the transactions moving the asset of the ledger fail with an
+// AssetFrozenError. It only guards this process; Entity.FreezeAsset also
+// suspends the asset in the ledger.
+func (f *AssetFreezes) Freeze(orgID, ledgerID, assetCode, reason string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := frozenAssetKey{orgID, ledgerID, assetCode}
+	if _, ok := f.frozen[key]; ok {
+		return
+	}
+
+	f.frozen[key] = FrozenAsset{OrganizationID: orgID, LedgerID: ledgerID, AssetCode: assetCode, Reason: reason, Since: time.Now()}
+}
+
+// Unfreeze lifts the freeze of the asset of the ledger. It reports whether the
+// asset was frozen.
+func (f *AssetFreezes) Unfreeze(orgID, ledgerID, assetCode string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := frozenAssetKey{orgID, ledgerID, assetCode}
+	_, ok := f.frozen[key]
+	delete(f.frozen, key)
+
+	return ok
+}
+
+// Frozen returns the freeze of the asset of the ledger, false when it is not
+// frozen.
+func (f *AssetFreezes) Frozen(orgID, ledgerID, assetCode string) (FrozenAsset, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	frozen, ok := f.frozen[frozenAssetKey{orgID, ledgerID, assetCode}]
+
+	return frozen, ok
+}
+
+// List returns the frozen assets, the oldest freeze first.
+func (f *AssetFreezes) List() []FrozenAsset {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	list := make([]FrozenAsset, 0, len(f.frozen))
+	for _, frozen := range f.frozen {
+		list = append(list, frozen)
+	}
+
+	slices.SortFunc(list, func(x, y FrozenAsset) int {
+		if c := x.Since.Compare(y.Since); c != 0 {
+			return c
+		}
+
+		return strings.Compare(x.AssetCode, y.AssetCode)
+	})
+
+	return list
+}
+
+// check returns an AssetFrozenError for the first frozen asset among codes.
+func (f *AssetFreezes) check(operation, orgID, ledgerID string, codes ...string) error {
+	if f == nil {
+		return nil
+	}
+
+	for _, code := range codes {
+		if frozen, ok := f.Frozen(orgID, ledgerID, code); ok {
+			return &AssetFrozenError{Operation: operation, FrozenAsset: frozen}
+		}
+	}
+
+	return nil
+}
+
+// dslSendAsset matches the asset of the send clause of a DSL transaction.
+var dslSendAsset = regexp.MustCompile(`\(\s*send\s+([A-Za-z0-9_-]+)`)
+
+// dslAssets returns the assets sent by a DSL transaction.
+func dslAssets(dslContent []byte) []string {
+	var codes []string
+
+	for _, match := range dslSendAsset.FindAllSubmatch(dslContent, -1) {
+		codes = append(codes, string(match[1]))
+	}
+
+	return codes
+}
+
+// transactionAssets returns the assets moved by a transaction input.
+func transactionAssets(input *models.CreateTransactionInput) []string {
+	codes := []string{input.AssetCode}
+
+	if input.Send != nil {
+		codes = append(codes, input.Send.Asset)
+	}
+
+	for _, op := range input.Operations {
+		codes = append(codes, op.AssetCode)
+	}
+
+	return codes
+}
+
+// WithAssetFreezes returns an Option that checks the transactions created
+// through the Entity against freezes, shared with other entities or set up
+// ahead. By default every Entity has its own set, see Entity.AssetFreezes.
+func WithAssetFreezes(freezes *AssetFreezes) Option {
+	return func(e *Entity) error {
+		if freezes == nil {
+			return errors.New("asset freezes cannot be nil")
+		}
+
+		e.httpClient.assetFreezes = freezes
+
+		return nil
+	}
+}
+
+// SetAssetFreezes sets the frozen assets the HTTP client rejects the
+// transactions of; nil stops checking.
+func (c *HTTPClient) SetAssetFreezes(freezes *AssetFreezes) {
+	c.assetFreezes = freezes
+}
+
+// AssetFreezes returns the frozen assets the Entity rejects the transactions
+// of.
+func (e *Entity) AssetFreezes() *AssetFreezes {
+	return e.httpClient.assetFreezes
+}
+
+// FreezeAsset halts trading in an asset for incident response, such as when
+// the pricing feed of a currency breaks: the transactions moving it created
+// through the Entity fail with an AssetFrozenError, and the asset is suspended
+// in the ledger, with the reason and its previous status in its metadata, so
+// that other processes can pick the freeze up with LoadAssetFreezes.
+//
+// The local freeze is set first, so it holds even when the asset can't be
+// updated; the error is returned all the same.
+//
+// Example:
+//
+//	_, err := entity.FreezeAsset(ctx, orgID, ledgerID, "ARS", "pricing feed down, INC-1234")
+//	...
+//	_, err = entity.UnfreezeAsset(ctx, orgID, ledgerID, "ARS")
+func (e *Entity) FreezeAsset(ctx context.Context, orgID, ledgerID, assetCode, reason string) (*models.Asset, error) {
+	const operation = "FreezeAsset"
+
+	if err := e.checkAssetFreezeArgs(operation, orgID, ledgerID, assetCode); err != nil {
+		return nil, err
+	}
+
+	e.AssetFreezes().Freeze(orgID, ledgerID, assetCode, reason)
+
+	asset, err := e.Assets.FindByCode(ctx, orgID, ledgerID, assetCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if asset.Status.Code == AssetStatusSuspended {
+		return asset, nil
+	}
+
+	metadata := map[string]any{
+		MetadataFrozenReason:         reason,
+		MetadataFrozenAt:             time.Now().UTC().Format(time.RFC3339),
+		MetadataFrozenPreviousStatus: asset.Status.Code,
+	}
+
+	status := models.NewStatus(AssetStatusSuspended)
+	if reason != "" {
+		status.Description = &reason
+	}
+
+	input := &models.UpdateAssetInput{}
+	input.Name, input.Status, input.Metadata = asset.Name, status, metadata
+
+	return e.Assets.UpdateAsset(ctx, orgID, ledgerID, asset.ID, input)
+}
+
+// UnfreezeAsset resumes trading in an asset frozen with FreezeAsset: its
+// status in the ledger is set back to the one it had, ACTIVE if unknown, and
+// the local freeze is lifted once the asset is updated.
+func (e *Entity) UnfreezeAsset(ctx context.Context, orgID, ledgerID, assetCode string) (*models.Asset, error) {
+	const operation = "UnfreezeAsset"
+
+	if err := e.checkAssetFreezeArgs(operation, orgID, ledgerID, assetCode); err != nil {
+		return nil, err
+	}
+
+	asset, err := e.Assets.FindByCode(ctx, orgID, ledgerID, assetCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if asset.Status.Code == AssetStatusSuspended {
+		previous, _ := asset.Metadata[MetadataFrozenPreviousStatus].(string)
+		if previous == "" || previous == AssetStatusSuspended {
+			previous = AssetStatusActive
+		}
+
+		input := &models.UpdateAssetInput{}
+		input.Name, input.Status = asset.Name, models.NewStatus(previous)
+		input.Metadata = map[string]any{MetadataFrozenReason: nil, MetadataFrozenAt: nil, MetadataFrozenPreviousStatus: nil}
+
+		asset, err = e.Assets.UpdateAsset(ctx, orgID, ledgerID, asset.ID, input)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	e.AssetFreezes().Unfreeze(orgID, ledgerID, assetCode)
+
+	return asset, nil
+}
+
+// LoadAssetFreezes freezes locally the suspended assets of a ledger, such as
+// those frozen by another process with FreezeAsset, and returns them.
+func (e *Entity) LoadAssetFreezes(ctx context.Context, orgID, ledgerID string) ([]FrozenAsset, error) {
+	if e.Assets == nil {
+		return nil, sdkerrors.NewValidationError("LoadAssetFreezes", "assets service not initialized", nil)
+	}
+
+	var loaded []FrozenAsset
+
+	opts := models.ListAssets().FilterStatus(AssetStatusSuspended).Limit(models.MaxLimit).Options()
+	filters := opts.Filters
+
+	for opts != nil {
+		page, err := e.Assets.ListAssets(ctx, orgID, ledgerID, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, asset := range page.Items {
+			if asset.Status.Code != AssetStatusSuspended {
+				continue
+			}
+
+			reason, _ := asset.Metadata[MetadataFrozenReason].(string)
+			if reason == "" && asset.Status.Description != nil {
+				reason = *asset.Status.Description
+			}
+
+			e.AssetFreezes().Freeze(orgID, ledgerID, asset.Code, reason)
+
+			frozen, _ := e.AssetFreezes().Frozen(orgID, ledgerID, asset.Code)
+			loaded = append(loaded, frozen)
+		}
+
+		if len(page.Items) == 0 {
+			break
+		}
+
+		opts = page.Pagination.NextPageOptions()
+		if opts != nil {
+			opts.WithFilters(filters)
+		}
+	}
+
+	return loaded, nil
+}
+
+// checkAssetFreezeArgs validates the arguments of FreezeAsset and UnfreezeAsset.
+func (e *Entity) checkAssetFreezeArgs(operation, orgID, ledgerID, assetCode string) error {
+	switch {
+	case e.Assets == nil:
+		return sdkerrors.NewValidationError(operation, "assets service not initialized", nil)
+	case orgID == "":
+		return sdkerrors.NewMissingParameterError(operation, "organization ID")
+	case ledgerID == "":
+		return sdkerrors.NewMissingParameterError(operation, "ledger ID")
+	case assetCode == "":
+		return sdkerrors.NewMissingParameterError(operation, "asset code")
+	}
+
+	return nil
+}
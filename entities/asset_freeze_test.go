@@ -0,0 +1,171 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFreezeAssetsService struct {
+	AssetsService
+
+	assets    []models.Asset
+	updateErr error
+	updates   []models.UpdateAssetInput
+}
+
+func (f *fakeFreezeAssetsService) FindByCode(_ context.Context, _, _, code string) (*models.Asset, error) {
+	for _, a := range f.assets {
+		if a.Code == code {
+			return &a, nil
+		}
+	}
+
+	return nil, sdkerrors.NewNotFoundError("FindByCode", "asset", code, nil)
+}
+
+func (f *fakeFreezeAssetsService) ListAssets(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Asset], error) {
+	var items []models.Asset
+
+	for _, a := range f.assets {
+		if a.Status.Code == opts.Filters["status"] {
+			items = append(items, a)
+		}
+	}
+
+	return &models.ListResponse[models.Asset]{Items: items, Pagination: models.Pagination{Limit: opts.Limit, Total: len(items)}}, nil
+}
+
+func (f *fakeFreezeAssetsService) UpdateAsset(_ context.Context, _, _, id string, input *models.UpdateAssetInput) (*models.Asset, error) {
+	if f.updateErr != nil {
+		return nil, f.updateErr
+	}
+
+	f.updates = append(f.updates, *input)
+
+	for i := range f.assets {
+		if f.assets[i].ID == id {
+			f.assets[i].Status = input.Status
+			f.assets[i].Metadata = input.Metadata
+			a := f.assets[i]
+
+			return &a, nil
+		}
+	}
+
+	return nil, sdkerrors.NewNotFoundError("UpdateAsset", "asset", id, nil)
+}
+
+func newFreezeTestEntity(t *testing.T) (*Entity, *fakeFreezeAssetsService) {
+	t.Helper()
+	t.Setenv("MIDAZ_ENABLE_RETRIES", "false")
+
+	entity, err := New("http://127.0.0.1:1")
+	require.NoError(t, err)
+
+	assets := &fakeFreezeAssetsService{assets: []models.Asset{
+		{ID: "asset-ars", Code: "ARS", Name: "Peso", Status: models.NewStatus("PENDING")},
+		{ID: "asset-usd", Code: "USD", Name: "Dollar", Status: models.NewStatus(AssetStatusActive)},
+	}}
+	entity.Assets = assets
+
+	return entity, assets
+}
+
+func arsTransfer() *models.CreateTransactionInput {
+	return &models.CreateTransactionInput{
+		Description: "transfer",
+		Amount:      "10",
+		AssetCode:   "ARS",
+		Send: &models.SendInput{
+			Asset: "ARS",
+			Value: "10",
+			Source: &models.SourceInput{From: []models.FromToInput{
+				{Account: "@alice", Amount: models.AmountInput{Asset: "ARS", Value: "10"}},
+			}},
+			Distribute: &models.DistributeInput{To: []models.FromToInput{
+				{Account: "@bob", Amount: models.AmountInput{Asset: "ARS", Value: "10"}},
+			}},
+		},
+	}
+}
+
+func TestFreezeAsset(t *testing.T) {
+	entity, assets := newFreezeTestEntity(t)
+	ctx := context.Background()
+
+	asset, err := entity.FreezeAsset(ctx, "org", "ledger", "ARS", "pricing feed down")
+	require.NoError(t, err)
+	assert.Equal(t, AssetStatusSuspended, asset.Status.Code)
+	assert.Equal(t, "PENDING", asset.Metadata[MetadataFrozenPreviousStatus])
+	assert.Equal(t, "pricing feed down", *assets.updates[0].Status.Description)
+
+	_, err = entity.Transactions.CreateTransaction(ctx, "org", "ledger", arsTransfer())
+	require.ErrorIs(t, err, ErrAssetFrozen)
+
+	var frozen *AssetFrozenError
+	require.True(t, errors.As(err, &frozen))
+	assert.Equal(t, "CreateTransaction", frozen.Operation)
+	assert.Equal(t, "ARS", frozen.AssetCode)
+	assert.Contains(t, err.Error(), "pricing feed down")
+
+	_, err = entity.Transactions.CreateTransaction(ctx, "org", "other-ledger", arsTransfer())
+	assert.NotErrorIs(t, err, ErrAssetFrozen, "freezes are per ledger")
+
+	_, err = entity.Transactions.CreateTransactionWithDSLFile(ctx, "org", "ledger",
+		[]byte("(transaction V1 (chart-of-accounts-group-name G) (send ARS 10|0 (source (from @alice :amount ARS 10|0)) (distribute (to @bob :amount ARS 10|0))))"))
+	assert.ErrorIs(t, err, ErrAssetFrozen)
+
+	_, err = entity.Transactions.CreateInflowTransaction(ctx, "org", "ledger", &models.CreateInflowInput{Send: &models.SendInflowInput{
+		Asset: "ARS", Value: "10", Distribute: arsTransfer().Send.Distribute,
+	}})
+	assert.ErrorIs(t, err, ErrAssetFrozen)
+
+	asset, err = entity.UnfreezeAsset(ctx, "org", "ledger", "ARS")
+	require.NoError(t, err)
+	assert.Equal(t, "PENDING", asset.Status.Code, "the previous status is restored")
+	assert.Nil(t, asset.Metadata[MetadataFrozenReason])
+	assert.Empty(t, entity.AssetFreezes().List())
+
+	_, err = entity.Transactions.CreateTransaction(ctx, "org", "ledger", arsTransfer())
+	assert.NotErrorIs(t, err, ErrAssetFrozen)
+}
+
+func TestFreezeAsset_UpdateFailure(t *testing.T) {
+	entity, assets := newFreezeTestEntity(t)
+	assets.updateErr = errors.New("unavailable")
+
+	_, err := entity.FreezeAsset(context.Background(), "org", "ledger", "USD", "incident")
+	require.Error(t, err)
+
+	_, ok := entity.AssetFreezes().Frozen("org", "ledger", "USD")
+	assert.True(t, ok, "the local freeze holds when the asset can't be updated")
+
+	_, err = entity.FreezeAsset(context.Background(), "org", "ledger", "", "incident")
+	assert.True(t, sdkerrors.IsValidationError(err))
+}
+
+func TestLoadAssetFreezes(t *testing.T) {
+	entity, assets := newFreezeTestEntity(t)
+
+	reason := "frozen elsewhere"
+	assets.assets[1].Status = models.Status{Code: AssetStatusSuspended, Description: &reason}
+
+	loaded, err := entity.LoadAssetFreezes(context.Background(), "org", "ledger")
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, "USD", loaded[0].AssetCode)
+	assert.Equal(t, reason, loaded[0].Reason)
+
+	shared := NewAssetFreezes()
+	shared.Freeze("org", "ledger", "BRL", "")
+
+	other, err := New("http://127.0.0.1:1", WithAssetFreezes(shared))
+	require.NoError(t, err)
+	assert.Same(t, shared, other.AssetFreezes())
+}
@@ -99,8 +99,9 @@ type assetRatesEntity struct {
 	baseURLs   map[string]string
 }
 
-func (e *assetRatesEntity) setDefaultTenantID(tenantID string) {
-	e.httpClient.SetTenantID(tenantID)
+// useSettings makes the service share the settings of its Entity.
+func (e *assetRatesEntity) useSettings(settings *clientSettings) {
+	e.httpClient.clientSettings = settings
 }
 
 // NewAssetRatesEntity creates a new asset rates entity.
@@ -145,7 +146,7 @@ func (e *assetRatesEntity) CreateOrUpdateAssetRate(
 		return nil, errors.NewMissingParameterError(operation, "input")
 	}
 
-	if err := input.Validate(); err != nil {
+	if err := e.httpClient.validateInput(ctx, operation, input.Validate); err != nil {
 		return nil, errors.NewValidationError(operation, "invalid asset rate input", err)
 	}
 
@@ -28,6 +28,11 @@ type AssetsService interface {
 	// Returns the asset if found, or an error if the operation fails or the asset doesn't exist.
 	GetAsset(ctx context.Context, organizationID, ledgerID, id string) (*models.Asset, error)
 
+	// FindByCode retrieves the asset of a ledger with the given code, compared case-insensitively.
+	// The organizationID and ledgerID parameters specify which organization and ledger to search.
+	// Returns the asset if found, or an error matched by errors.IsNotFoundError if the ledger has no such asset.
+	FindByCode(ctx context.Context, organizationID, ledgerID, code string) (*models.Asset, error)
+
 	// CreateAsset creates a new asset in the specified ledger.
 	//
 	// Assets represent units of value that can be tracked and transferred within the Midaz
@@ -131,8 +136,9 @@ type assetsEntity struct {
 	baseURLs   map[string]string
 }
 
-func (e *assetsEntity) setDefaultTenantID(tenantID string) {
-	e.httpClient.SetTenantID(tenantID)
+// useSettings makes the service share the settings of its Entity.
+func (e *assetsEntity) useSettings(settings *clientSettings) {
+	e.httpClient.clientSettings = settings
 }
 
 // NewAssetsEntity creates a new assets entity.
@@ -274,6 +280,38 @@ func (e *assetsEntity) GetAsset(
 	return &asset, nil
 }
 
+// FindByCode gets the asset of a ledger with the given code, listing the assets of the ledger.
+func (e *assetsEntity) FindByCode(ctx context.Context, organizationID, ledgerID, code string) (*models.Asset, error) {
+	const operation = "FindByCode"
+
+	if code == "" {
+		return nil, errors.NewMissingParameterError(operation, "code")
+	}
+
+	opts := models.NewListOptions().WithLimit(models.MaxLimit)
+
+	for opts != nil {
+		page, err := e.ListAssets(ctx, organizationID, ledgerID, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range page.Items {
+			if strings.EqualFold(page.Items[i].Code, code) {
+				return &page.Items[i], nil
+			}
+		}
+
+		if len(page.Items) == 0 {
+			break
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	return nil, errors.NewNotFoundError(operation, "asset", code, nil)
+}
+
 // CreateAsset creates a new asset in the specified ledger.
 func (e *assetsEntity) CreateAsset(
 	ctx context.Context,
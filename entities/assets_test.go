@@ -20,6 +20,9 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// The mock must be regenerated when AssetsService changes.
+var _ AssetsService = (*mocks.MockAssetsService)(nil)
+
 // TestListAssets tests the ListAssets method with mock service
 func TestListAssets(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -376,6 +379,7 @@ func newAssetsHTTPClientAdapter(mock *MockHTTPClient) *HTTPClient {
 	_ = retry.WithRetryableHTTPCodes(retry.DefaultRetryableHTTPCodes)(retryOptions)
 
 	return &HTTPClient{
+		clientSettings: &clientSettings{},
 		client: &http.Client{
 			Transport: &mockTransport{mock: mock},
 		},
@@ -0,0 +1,507 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+)
+
+// DefaultBalanceCacheTTL is how long a BalanceCache created with a zero ttl
+// keeps a balance.
+const DefaultBalanceCacheTTL = 30 * time.Second
+
+// defaultBalanceKey is the key of the balance an account gets with each asset.
+const defaultBalanceKey = "default"
+
+// ErrBalanceCacheClosed is returned by BalanceCache.Get for a balance not
+// kept once the cache is closed.
+var ErrBalanceCacheClosed = stderrors.New("balance cache closed")
+
+// BalanceCache keeps the default balance of accounts by account and asset,
+// for reads such as those of a UI backend that would otherwise list the
+// balances of the same accounts on every request.
+//
+// A balance is kept for the ttl of the cache and dropped earlier when it
+// changes: feed the balance and transaction events of the ledger to Apply and
+// InvalidateTransaction, and create the cache with WithBalanceCache so that
+// the transactions submitted through the same client invalidate the balances
+// they move. A read following a write through the client then never returns
+// the balance from before the write. It is safe for concurrent use.
+//
+// Example:
+//
+//	entity, err := entities.NewWithServiceURLs(urls, entities.WithBalanceCache(time.Minute))
+//	...
+//	balance, err := entity.BalanceCache().Get(ctx, orgID, ledgerID, accountID, "USD")
+type BalanceCache struct {
+	service BalancesService
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]balanceCacheItem // by org/ledger/account/asset
+	inflight map[string]*balanceCall     // by org/ledger/account
+	closed   bool
+
+	// closing cancels the listings in flight on Close; loads tracks them
+	closing    context.Context
+	closeLoads context.CancelFunc
+	loads      sync.WaitGroup
+}
+
+type balanceCacheItem struct {
+	balance models.Balance
+	expires time.Time
+}
+
+// balanceCall is a listing of the balances of an account shared by
+// concurrent callers. An invalidation during the call makes it stale: its
+// result is returned to the callers waiting for it but not kept.
+type balanceCall struct {
+	done     chan struct{}
+	balances []models.Balance
+	err      error
+	stale    bool
+}
+
+// NewBalanceCache creates a cache reading the balances through service.
+// Balances are listed again after ttl; a zero ttl uses DefaultBalanceCacheTTL
+// and a negative ttl keeps balances until they are invalidated.
+func NewBalanceCache(service BalancesService, ttl time.Duration) *BalanceCache {
+	if ttl == 0 {
+		ttl = DefaultBalanceCacheTTL
+	}
+
+	closing, closeLoads := context.WithCancel(context.Background())
+
+	return &BalanceCache{
+		service:    service,
+		ttl:        ttl,
+		now:        time.Now,
+		entries:    make(map[string]balanceCacheItem),
+		inflight:   make(map[string]*balanceCall),
+		closing:    closing,
+		closeLoads: closeLoads,
+	}
+}
+
+// Get returns the default balance of an account in an asset, listing the
+// balances of the account when it is missing or expired. It returns a not
+// found error when the account has no default balance in the asset.
+func (c *BalanceCache) Get(ctx context.Context, orgID, ledgerID, accountID, assetCode string) (*models.Balance, error) {
+	const operation = "GetCachedBalance"
+
+	switch {
+	case orgID == "":
+		return nil, errors.NewMissingParameterError(operation, "organizationID")
+	case ledgerID == "":
+		return nil, errors.NewMissingParameterError(operation, "ledgerID")
+	case accountID == "":
+		return nil, errors.NewMissingParameterError(operation, "accountID")
+	case assetCode == "":
+		return nil, errors.NewMissingParameterError(operation, "assetCode")
+	}
+
+	accountKey := balanceCacheKey(orgID, ledgerID, accountID)
+
+	c.mu.Lock()
+
+	if item, ok := c.entries[accountKey+"/"+assetCode]; ok && !c.expired(item) {
+		c.mu.Unlock()
+
+		balance := item.balance
+
+		return &balance, nil
+	}
+
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrBalanceCacheClosed
+	}
+
+	call, ok := c.inflight[accountKey]
+	if !ok {
+		call = &balanceCall{done: make(chan struct{})}
+		c.inflight[accountKey] = call
+
+		c.startLoad(ctx, orgID, ledgerID, accountID, call)
+	}
+
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, errors.NewCancellationError(operation, ctx.Err())
+	}
+
+	if call.err != nil {
+		return nil, call.err
+	}
+
+	for _, b := range call.balances {
+		if b.AssetCode == assetCode && isDefaultBalance(b) {
+			return &b, nil
+		}
+	}
+
+	return nil, errors.NewNotFoundError(operation, "balance", accountID+"/"+assetCode, nil)
+}
+
+// startLoad runs load in the background, with c.mu held. The listing
+// outlives the cancellation of ctx, as other callers may be waiting for it,
+// but not the closing of the cache.
+func (c *BalanceCache) startLoad(ctx context.Context, orgID, ledgerID, accountID string, call *balanceCall) {
+	loadCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stopCancel := context.AfterFunc(c.closing, cancel)
+	done := lifecycle.Track("entities.BalanceCache")
+
+	c.loads.Add(1)
+
+	go func() {
+		defer c.loads.Done()
+		defer done()
+		defer cancel()
+		defer stopCancel()
+
+		c.load(loadCtx, orgID, ledgerID, accountID, call)
+	}()
+}
+
+// Close cancels the listings in flight and waits until they exit or ctx is
+// done. The balances kept are still returned after Close, but Get fails
+// with ErrBalanceCacheClosed instead of listing the missing ones.
+func (c *BalanceCache) Close(ctx context.Context) error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	c.closeLoads()
+
+	return waitGroupContext(ctx, &c.loads)
+}
+
+// load lists the balances of an account and keeps them unless the call went
+// stale in the meantime.
+func (c *BalanceCache) load(ctx context.Context, orgID, ledgerID, accountID string, call *balanceCall) {
+	defer close(call.done)
+
+	if c.service == nil {
+		call.err = errors.NewValidationError("GetCachedBalance", "balance cache has no balances service", nil)
+	} else {
+		call.err = listAllBalances(ctx, nil, func(opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+			return c.service.ListAccountBalances(ctx, orgID, ledgerID, accountID, opts)
+		}, func(b models.Balance) {
+			call.balances = append(call.balances, b)
+		})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	accountKey := balanceCacheKey(orgID, ledgerID, accountID)
+	if c.inflight[accountKey] == call {
+		delete(c.inflight, accountKey)
+	}
+
+	if call.err != nil || call.stale {
+		return
+	}
+
+	for _, b := range call.balances {
+		if isDefaultBalance(b) {
+			c.put(orgID, ledgerID, b)
+		}
+	}
+}
+
+// Apply keeps a balance read from a balance event, unless the cache holds a
+// later version of it. It returns whether the balance was kept. Balances
+// other than the default one of their asset are ignored.
+func (c *BalanceCache) Apply(balance models.Balance) bool {
+	if !isDefaultBalance(balance) || balance.AccountID == "" {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// a listing in flight may return an earlier version
+	c.markStale(balanceCacheKey(balance.OrganizationID, balance.LedgerID, balance.AccountID))
+
+	return c.put(balance.OrganizationID, balance.LedgerID, balance)
+}
+
+// put keeps a balance unless a later version is kept, with c.mu held.
+func (c *BalanceCache) put(orgID, ledgerID string, balance models.Balance) bool {
+	key := balanceCacheKey(orgID, ledgerID, balance.AccountID) + "/" + balance.AssetCode
+
+	if item, ok := c.entries[key]; ok && !c.expired(item) && item.balance.Version > balance.Version {
+		return false
+	}
+
+	// drop expired entries so the cache doesn't grow with every account ever read
+	for k, item := range c.entries {
+		if c.expired(item) {
+			delete(c.entries, k)
+		}
+	}
+
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = c.now().Add(c.ttl)
+	}
+
+	c.entries[key] = balanceCacheItem{balance: balance, expires: expires}
+
+	return true
+}
+
+// Invalidate drops the balance of an account in an asset, or in every asset
+// when assetCode is empty.
+func (c *BalanceCache) Invalidate(orgID, ledgerID, accountID, assetCode string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidate(balanceCacheKey(orgID, ledgerID, accountID), assetCode)
+}
+
+// InvalidateAlias drops the balances of the account with the given alias,
+// if the cache holds any.
+func (c *BalanceCache) InvalidateAlias(orgID, ledgerID, alias string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidateAlias(orgID, ledgerID, alias)
+}
+
+// invalidateAlias drops the balances of the account with the given alias,
+// with c.mu held. The account of a listing in flight is known by ID only, so
+// every listing in flight in the ledger is made stale.
+func (c *BalanceCache) invalidateAlias(orgID, ledgerID, alias string) {
+	prefix := balanceCacheKey(orgID, ledgerID, "")
+
+	for key, item := range c.entries {
+		if item.balance.Alias == alias && strings.HasPrefix(key, prefix) {
+			c.invalidate(balanceCacheKey(orgID, ledgerID, item.balance.AccountID), "")
+		}
+	}
+
+	for key := range c.inflight {
+		if strings.HasPrefix(key, prefix) {
+			c.markStale(key)
+		}
+	}
+}
+
+// InvalidateLedger drops every balance of a ledger.
+func (c *BalanceCache) InvalidateLedger(orgID, ledgerID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidateLedger(orgID, ledgerID)
+}
+
+// invalidateLedger drops every balance of a ledger, with c.mu held.
+func (c *BalanceCache) invalidateLedger(orgID, ledgerID string) {
+	prefix := balanceCacheKey(orgID, ledgerID, "")
+
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+
+	for key := range c.inflight {
+		if strings.HasPrefix(key, prefix) {
+			c.markStale(key)
+		}
+	}
+}
+
+// InvalidateTransaction drops the balances of the accounts a transaction
+// moves, by the account IDs of its operations and the aliases of its sources
+// and destinations. It drops every balance of the ledger when the transaction
+// names no account.
+func (c *BalanceCache) InvalidateTransaction(orgID, ledgerID string, tx *models.Transaction) {
+	if tx == nil {
+		return
+	}
+
+	accountIDs := make([]string, 0, len(tx.Operations))
+	for _, op := range tx.Operations {
+		accountIDs = append(accountIDs, op.AccountID)
+	}
+
+	aliases := append(append([]string(nil), tx.Source...), tx.Destination...)
+	for _, op := range tx.Operations {
+		aliases = append(aliases, op.AccountAlias)
+	}
+
+	c.invalidateAccounts(orgID, ledgerID, accountIDs, aliases)
+}
+
+// invalidateAccounts drops the balances of accounts given by ID or alias, or
+// every balance of the ledger when none is given.
+func (c *BalanceCache) invalidateAccounts(orgID, ledgerID string, accountIDs, aliases []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	named := false
+
+	for _, id := range accountIDs {
+		if id != "" {
+			named = true
+
+			c.invalidate(balanceCacheKey(orgID, ledgerID, id), "")
+		}
+	}
+
+	for _, alias := range aliases {
+		if alias == "" {
+			continue
+		}
+
+		named = true
+
+		c.invalidateAlias(orgID, ledgerID, alias)
+	}
+
+	if !named {
+		c.invalidateLedger(orgID, ledgerID)
+	}
+}
+
+// invalidate drops the balances of an account, with c.mu held.
+func (c *BalanceCache) invalidate(accountKey, assetCode string) {
+	if assetCode != "" {
+		delete(c.entries, accountKey+"/"+assetCode)
+	} else {
+		for key := range c.entries {
+			if strings.HasPrefix(key, accountKey+"/") {
+				delete(c.entries, key)
+			}
+		}
+	}
+
+	c.markStale(accountKey)
+}
+
+// markStale detaches the listing in flight of an account, so that its result
+// isn't kept and later reads list the balances again, with c.mu held.
+func (c *BalanceCache) markStale(accountKey string) {
+	if call, ok := c.inflight[accountKey]; ok {
+		call.stale = true
+
+		delete(c.inflight, accountKey)
+	}
+}
+
+// Len returns the number of balances kept, expired ones included.
+func (c *BalanceCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
+
+func (c *BalanceCache) expired(item balanceCacheItem) bool {
+	return !item.expires.IsZero() && !c.now().Before(item.expires)
+}
+
+// observe invalidates the balances changed by a successful request of the
+// HTTP client: those moved by a transaction, or every balance of the ledger
+// for other changes to balances and accounts.
+func (c *BalanceCache) observe(method, requestURL string, responseBody []byte) {
+	if method == http.MethodGet || method == http.MethodHead {
+		return
+	}
+
+	orgID, ledgerID, resource := ledgerResource(requestURL)
+	if ledgerID == "" {
+		return
+	}
+
+	switch resource {
+	case "transactions":
+		var tx struct {
+			Source      []string `json:"source"`
+			Destination []string `json:"destination"`
+			Operations  []struct {
+				AccountID    string `json:"accountId"`
+				AccountAlias string `json:"accountAlias"`
+			} `json:"operations"`
+		}
+
+		_ = json.Unmarshal(responseBody, &tx) //nolint:errcheck // an unreadable response names no account
+
+		var accountIDs []string
+
+		aliases := append(tx.Source, tx.Destination...)
+
+		for _, op := range tx.Operations {
+			accountIDs = append(accountIDs, op.AccountID)
+			aliases = append(aliases, op.AccountAlias)
+		}
+
+		c.invalidateAccounts(orgID, ledgerID, accountIDs, aliases)
+	case "balances", "accounts":
+		c.InvalidateLedger(orgID, ledgerID)
+	}
+}
+
+// ledgerResource returns the organization and ledger of a URL such as
+// ".../organizations/{org}/ledgers/{ledger}/transactions/...", with the
+// segment following the ledger.
+func ledgerResource(requestURL string) (orgID, ledgerID, resource string) {
+	segments := strings.Split(strings.SplitN(requestURL, "?", 2)[0], "/")
+
+	for i := 0; i+3 < len(segments); i++ {
+		if segments[i] == "organizations" && segments[i+2] == "ledgers" {
+			orgID, ledgerID = segments[i+1], segments[i+3]
+
+			if i+4 < len(segments) {
+				resource = segments[i+4]
+			}
+
+			return orgID, ledgerID, resource
+		}
+	}
+
+	return "", "", ""
+}
+
+func isDefaultBalance(b models.Balance) bool {
+	return b.Key == "" || b.Key == defaultBalanceKey
+}
+
+// WithBalanceCache returns an Option that creates a BalanceCache over the
+// balances service with the given ttl, see NewBalanceCache. The transactions
+// and the changes to balances and accounts made through the Entity
+// invalidate the balances they affect, giving read-your-writes consistency.
+func WithBalanceCache(ttl time.Duration) Option {
+	return func(e *Entity) error {
+		e.httpClient.balanceCache = NewBalanceCache(nil, ttl)
+
+		return nil
+	}
+}
+
+// BalanceCache returns the cache set with WithBalanceCache, or nil.
+func (e *Entity) BalanceCache() *BalanceCache {
+	return e.httpClient.balanceCache
+}
+
+// SetBalanceCache sets the cache invalidated by the successful writes of the
+// HTTP client; nil disables the invalidation.
+func (c *HTTPClient) SetBalanceCache(cache *BalanceCache) {
+	c.balanceCache = cache
+}
@@ -0,0 +1,202 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceCacheReadYourWrites(t *testing.T) {
+	var available, lists atomic.Int64
+
+	available.Store(100)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/organizations/org/ledgers/ledger/accounts/acc-1/balances":
+			lists.Add(1)
+			_, _ = fmt.Fprintf(w, `{"items":[
+				{"id":"b1","accountId":"acc-1","alias":"@alice","key":"default","assetCode":"USD","available":"%d","version":1},
+				{"id":"b2","accountId":"acc-1","alias":"@alice","key":"savings","assetCode":"USD","available":"7","version":1}
+			]}`, available.Load())
+		case "/organizations/org/ledgers/ledger/transactions/json":
+			available.Add(50)
+			_, _ = w.Write([]byte(`{"id":"tx-1","assetCode":"USD","source":["@external/USD"],"destination":["@alice"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()), WithBalanceCache(time.Minute))
+	require.NoError(t, err)
+
+	cache := entity.BalanceCache()
+	require.NotNil(t, cache)
+
+	balance, err := cache.Get(context.Background(), "org", "ledger", "acc-1", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "100", balance.Available.String())
+	assert.Equal(t, "b1", balance.ID, "the default balance of the asset")
+
+	_, err = cache.Get(context.Background(), "org", "ledger", "acc-1", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), lists.Load(), "the second read is served from the cache")
+
+	input := &models.CreateTransactionInput{
+		Amount:    "50",
+		AssetCode: "USD",
+		Send: &models.SendInput{
+			Asset: "USD",
+			Value: "50",
+			Source: &models.SourceInput{
+				From: []models.FromToInput{{Account: "@external/USD", Amount: models.AmountInput{Asset: "USD", Value: "50"}}},
+			},
+			Distribute: &models.DistributeInput{
+				To: []models.FromToInput{{Account: "@alice", Amount: models.AmountInput{Asset: "USD", Value: "50"}}},
+			},
+		},
+	}
+
+	_, err = entity.Transactions.CreateTransaction(context.Background(), "org", "ledger", input)
+	require.NoError(t, err)
+	assert.Zero(t, cache.Len(), "the transaction invalidates the balances it moves")
+
+	balance, err = cache.Get(context.Background(), "org", "ledger", "acc-1", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "150", balance.Available.String())
+	assert.Equal(t, int64(2), lists.Load())
+
+	_, err = cache.Get(context.Background(), "org", "ledger", "acc-1", "BRL")
+	assert.True(t, errors.IsNotFoundError(err), "got %v", err)
+}
+
+func TestBalanceCacheEvents(t *testing.T) {
+	cache := NewBalanceCache(nil, time.Minute)
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	balance := func(asset string, version int64, available int64) models.Balance {
+		return models.Balance{
+			OrganizationID: "org", LedgerID: "ledger", AccountID: "acc-1", Alias: "@alice",
+			AssetCode: asset, Key: "default", Version: version, Available: decimal.NewFromInt(available),
+		}
+	}
+
+	assert.True(t, cache.Apply(balance("USD", 2, 20)))
+	assert.False(t, cache.Apply(balance("USD", 1, 10)), "an earlier version is ignored")
+	assert.False(t, cache.Apply(models.Balance{AccountID: "acc-1", AssetCode: "USD", Key: "savings"}))
+	assert.True(t, cache.Apply(balance("BRL", 1, 5)))
+
+	got, err := cache.Get(context.Background(), "org", "ledger", "acc-1", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "20", got.Available.String())
+
+	cache.Invalidate("org", "ledger", "acc-1", "USD")
+	assert.Equal(t, 1, cache.Len())
+
+	cache.InvalidateTransaction("org", "ledger", &models.Transaction{Destination: []string{"@alice"}})
+	assert.Zero(t, cache.Len())
+
+	cache.Apply(balance("USD", 3, 30))
+	cache.InvalidateTransaction("org", "other", &models.Transaction{Destination: []string{"@alice"}})
+	assert.Equal(t, 1, cache.Len(), "another ledger is left alone")
+
+	cache.InvalidateTransaction("org", "ledger", &models.Transaction{})
+	assert.Zero(t, cache.Len(), "a transaction naming no account invalidates the ledger")
+
+	cache.Apply(balance("USD", 4, 40))
+	now = now.Add(time.Minute)
+
+	_, err = cache.Get(context.Background(), "org", "ledger", "acc-1", "USD")
+	assert.Error(t, err, "an expired balance is listed again")
+}
+
+// blockingBalances lists the balances of an account once released.
+type blockingBalances struct {
+	BalancesService
+
+	release chan struct{}
+	calls   atomic.Int64
+}
+
+func (b *blockingBalances) ListAccountBalances(ctx context.Context, orgID, ledgerID, accountID string, _ *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	version := b.calls.Add(1)
+
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return &models.ListResponse[models.Balance]{Items: []models.Balance{{
+		OrganizationID: orgID, LedgerID: ledgerID, AccountID: accountID, AssetCode: "USD", Key: "default", Version: version,
+	}}}, nil
+}
+
+func TestBalanceCacheInvalidationDuringLoad(t *testing.T) {
+	service := &blockingBalances{release: make(chan struct{})}
+	cache := NewBalanceCache(service, time.Minute)
+
+	done := make(chan *models.Balance)
+
+	go func() {
+		balance, _ := cache.Get(context.Background(), "org", "ledger", "acc-1", "USD")
+		done <- balance
+	}()
+
+	require.Eventually(t, func() bool { return service.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	cache.Invalidate("org", "ledger", "acc-1", "")
+	close(service.release)
+
+	assert.Equal(t, int64(1), (<-done).Version, "the caller waiting gets the listing")
+	assert.Zero(t, cache.Len(), "but a listing invalidated in flight is not kept")
+
+	balance, err := cache.Get(context.Background(), "org", "ledger", "acc-1", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), balance.Version)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestBalanceCacheClose(t *testing.T) {
+	tasks := lifecycle.Count("entities.BalanceCache")
+
+	service := &blockingBalances{release: make(chan struct{})}
+	cache := NewBalanceCache(service, time.Minute)
+	cache.Apply(models.Balance{OrganizationID: "org", LedgerID: "ledger", AccountID: "acc-2", AssetCode: "USD", Key: "default"})
+
+	done := make(chan error)
+
+	go func() {
+		_, err := cache.Get(context.Background(), "org", "ledger", "acc-1", "USD")
+		done <- err
+	}()
+
+	require.Eventually(t, func() bool { return service.calls.Load() == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, tasks+1, lifecycle.Count("entities.BalanceCache"))
+
+	require.NoError(t, cache.Close(context.Background()))
+	assert.Error(t, <-done, "the listing in flight is cancelled")
+	assert.Equal(t, tasks, lifecycle.Count("entities.BalanceCache"), "no listing is left running")
+
+	_, err := cache.Get(context.Background(), "org", "ledger", "acc-1", "USD")
+	assert.ErrorIs(t, err, ErrBalanceCacheClosed)
+
+	_, err = cache.Get(context.Background(), "org", "ledger", "acc-2", "USD")
+	assert.NoError(t, err, "the balances kept are still returned")
+}
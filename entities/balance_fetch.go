@@ -0,0 +1,229 @@
+package entities
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// DefaultGetManyConcurrency is the number of accounts GetMany fetches in parallel by default.
+const DefaultGetManyConcurrency = 10
+
+// GetManyOptions configures BalancesService.GetMany, see models.GetManyOptions.
+type GetManyOptions = models.GetManyOptions
+
+// AccountBalances holds the balances of one account retrieved by GetMany, see models.AccountBalances.
+type AccountBalances = models.AccountBalances
+
+// balanceCacheEntry holds the cached balances of an account.
+type balanceCacheEntry struct {
+	balances []models.Balance
+	expires  time.Time
+}
+
+// balanceCache caches account balances for GetMany.
+type balanceCache struct {
+	mu      sync.Mutex
+	entries map[string]balanceCacheEntry
+}
+
+func (c *balanceCache) get(key string, now time.Time) ([]models.Balance, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || now.After(entry.expires) {
+		return nil, false
+	}
+
+	return append([]models.Balance(nil), entry.balances...), true
+}
+
+func (c *balanceCache) put(key string, balances []models.Balance, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]balanceCacheEntry)
+	}
+
+	// drop expired entries so the cache doesn't grow with every account ever fetched
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = balanceCacheEntry{balances: append([]models.Balance(nil), balances...), expires: expires}
+}
+
+// GetMany retrieves the balances of many accounts in parallel.
+func (e *balancesEntity) GetMany(ctx context.Context, orgID, ledgerID string, accountIDs []string, opts *GetManyOptions) (map[string]*AccountBalances, error) {
+	const operation = "GetManyBalances"
+
+	if orgID == "" {
+		return nil, errors.NewMissingParameterError(operation, "organizationID")
+	}
+
+	if ledgerID == "" {
+		return nil, errors.NewMissingParameterError(operation, "ledgerID")
+	}
+
+	if opts == nil {
+		opts = &GetManyOptions{}
+	}
+
+	out := make(map[string]*AccountBalances, len(accountIDs))
+	pending := make([]string, 0, len(accountIDs))
+
+	for _, id := range accountIDs {
+		if _, seen := out[id]; seen {
+			continue
+		}
+
+		result := &AccountBalances{AccountID: id}
+		out[id] = result
+
+		if id == "" {
+			result.Error = errors.NewMissingParameterError(operation, "accountID")
+			continue
+		}
+
+		if opts.CacheTTL > 0 {
+			if balances, ok := e.cache.get(balanceCacheKey(orgID, ledgerID, id), time.Now()); ok {
+				result.Balances, result.Cached = balances, true
+				continue
+			}
+		}
+
+		pending = append(pending, id)
+	}
+
+	if len(pending) == 0 {
+		return out, nil
+	}
+
+	var limiter concurrent.Limiter
+	if opts.RateLimit > 0 {
+		limiter = concurrent.NewLimiter(concurrent.AlgorithmGCRA, opts.RateLimit, 1)
+		defer limiter.Stop()
+	}
+
+	if opts.LedgerScanThreshold > 0 && len(pending) >= opts.LedgerScanThreshold {
+		e.scanLedgerBalances(ctx, orgID, ledgerID, pending, limiter, out)
+	} else {
+		e.fetchAccountBalances(ctx, orgID, ledgerID, pending, opts.Concurrency, limiter, out)
+	}
+
+	if opts.CacheTTL > 0 {
+		expires := time.Now().Add(opts.CacheTTL)
+
+		for _, id := range pending {
+			if result := out[id]; result.Error == nil {
+				e.cache.put(balanceCacheKey(orgID, ledgerID, id), result.Balances, expires)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// fetchAccountBalances lists the balances of each account in parallel.
+func (e *balancesEntity) fetchAccountBalances(ctx context.Context, orgID, ledgerID string, accountIDs []string, workers int, limiter concurrent.Limiter, out map[string]*AccountBalances) {
+	if workers <= 0 {
+		workers = DefaultGetManyConcurrency
+	}
+
+	results := concurrent.WorkerPool(ctx, accountIDs, func(ctx context.Context, accountID string) ([]models.Balance, error) {
+		var balances []models.Balance
+
+		err := listAllBalances(ctx, limiter, func(opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+			return e.ListAccountBalances(ctx, orgID, ledgerID, accountID, opts)
+		}, func(b models.Balance) {
+			balances = append(balances, b)
+		})
+
+		return balances, err
+	}, concurrent.WithWorkers(workers))
+
+	for i, id := range accountIDs {
+		r := results[i]
+		// items skipped after cancellation have no result
+		if r.Item != id {
+			out[id].Error = contextError(ctx)
+			continue
+		}
+
+		out[id].Balances, out[id].Error = r.Value, r.Error
+	}
+}
+
+// scanLedgerBalances lists all balances of the ledger and keeps those of accountIDs.
+func (e *balancesEntity) scanLedgerBalances(ctx context.Context, orgID, ledgerID string, accountIDs []string, limiter concurrent.Limiter, out map[string]*AccountBalances) {
+	wanted := make(map[string]bool, len(accountIDs))
+	for _, id := range accountIDs {
+		wanted[id] = true
+	}
+
+	err := listAllBalances(ctx, limiter, func(opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+		return e.ListBalances(ctx, orgID, ledgerID, opts)
+	}, func(b models.Balance) {
+		if wanted[b.AccountID] {
+			out[b.AccountID].Balances = append(out[b.AccountID].Balances, b)
+		}
+	})
+
+	if err != nil {
+		for _, id := range accountIDs {
+			out[id].Balances, out[id].Error = nil, err
+		}
+	}
+}
+
+// listAllBalances calls list for every page, waiting for limiter before each request.
+func listAllBalances(ctx context.Context, limiter concurrent.Limiter, list func(opts *models.ListOptions) (*models.ListResponse[models.Balance], error), fn func(models.Balance)) error {
+	opts := models.NewListOptions().WithLimit(models.MaxLimit)
+
+	for opts != nil {
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		page, err := list(opts)
+		if err != nil {
+			return err
+		}
+
+		for _, b := range page.Items {
+			fn(b)
+		}
+
+		if len(page.Items) == 0 {
+			return nil
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	return nil
+}
+
+// contextError returns the error of a canceled context, or a generic cancellation error.
+func contextError(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return errors.NewCancellationError("GetManyBalances", err)
+	}
+
+	return errors.NewCancellationError("GetManyBalances", context.Canceled)
+}
+
+func balanceCacheKey(orgID, ledgerID, accountID string) string {
+	return orgID + "/" + ledgerID + "/" + accountID
+}
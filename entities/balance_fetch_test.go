@@ -0,0 +1,137 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// balanceServer serves account and ledger balances, failing for account "acc-bad".
+func balanceServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+
+		path := r.URL.Path
+		if strings.Contains(path, "/accounts/acc-bad/") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"0007","message":"account not found"}`))
+
+			return
+		}
+
+		if strings.Contains(path, "/accounts/") {
+			accountID := strings.Split(strings.SplitAfter(path, "/accounts/")[1], "/")[0]
+			_, _ = fmt.Fprintf(w, `{"items":[{"id":"bal-%[1]s","accountId":"%[1]s","assetCode":"USD","available":"10"}],"pagination":{"limit":100}}`, accountID)
+
+			return
+		}
+
+		// ledger scan: two pages
+		if r.URL.Query().Get("cursor") == "" {
+			_, _ = w.Write([]byte(`{"items":[{"id":"bal-1","accountId":"acc-1","assetCode":"USD"},{"id":"bal-x","accountId":"acc-x","assetCode":"USD"}],"pagination":{"limit":2,"nextCursor":"next"}}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"items":[{"id":"bal-2","accountId":"acc-2","assetCode":"BRL"}],"pagination":{"limit":2}}`))
+	}))
+}
+
+func TestBalancesEntity_GetMany(t *testing.T) {
+	var requests atomic.Int32
+
+	server := balanceServer(t, &requests)
+	defer server.Close()
+
+	entity := NewBalancesEntity(server.Client(), "test-token", map[string]string{"transaction": server.URL})
+
+	results, err := entity.GetMany(context.Background(), "org-1", "ledger-1", []string{"acc-1", "acc-bad", "acc-2", "acc-1", ""}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, int32(3), requests.Load(), "duplicates and empty IDs are not fetched")
+
+	require.NoError(t, results["acc-1"].Error)
+	require.Len(t, results["acc-1"].Balances, 1)
+	assert.Equal(t, "bal-acc-1", results["acc-1"].Balances[0].ID)
+	assert.Equal(t, "bal-acc-2", results["acc-2"].Balances[0].ID)
+
+	assert.True(t, errors.IsNotFoundError(results["acc-bad"].Error))
+	assert.Error(t, results[""].Error)
+
+	_, err = entity.GetMany(context.Background(), "", "ledger-1", []string{"acc-1"}, nil)
+	assert.Error(t, err)
+}
+
+func TestBalancesEntity_GetManyCache(t *testing.T) {
+	var requests atomic.Int32
+
+	server := balanceServer(t, &requests)
+	defer server.Close()
+
+	entity := NewBalancesEntity(server.Client(), "test-token", map[string]string{"transaction": server.URL})
+	opts := &GetManyOptions{CacheTTL: time.Minute, Concurrency: 2, RateLimit: 1000}
+
+	_, err := entity.GetMany(context.Background(), "org-1", "ledger-1", []string{"acc-1", "acc-bad"}, opts)
+	require.NoError(t, err)
+
+	results, err := entity.GetMany(context.Background(), "org-1", "ledger-1", []string{"acc-1", "acc-bad"}, opts)
+	require.NoError(t, err)
+
+	assert.True(t, results["acc-1"].Cached)
+	assert.Len(t, results["acc-1"].Balances, 1)
+	assert.False(t, results["acc-bad"].Cached, "failures are not cached")
+	assert.Equal(t, int32(3), requests.Load())
+
+	// another ledger is not served from the cache
+	results, err = entity.GetMany(context.Background(), "org-1", "ledger-2", []string{"acc-1"}, opts)
+	require.NoError(t, err)
+	assert.False(t, results["acc-1"].Cached)
+}
+
+func TestBalancesEntity_GetManyLedgerScan(t *testing.T) {
+	var requests atomic.Int32
+
+	server := balanceServer(t, &requests)
+	defer server.Close()
+
+	entity := NewBalancesEntity(server.Client(), "test-token", map[string]string{"transaction": server.URL})
+
+	results, err := entity.GetMany(context.Background(), "org-1", "ledger-1", []string{"acc-1", "acc-2", "acc-3"}, &GetManyOptions{LedgerScanThreshold: 3})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), requests.Load(), "one request per ledger page")
+	assert.Equal(t, "bal-1", results["acc-1"].Balances[0].ID)
+	assert.Equal(t, "bal-2", results["acc-2"].Balances[0].ID)
+	assert.Empty(t, results["acc-3"].Balances)
+	assert.NoError(t, results["acc-3"].Error)
+}
+
+func TestBalancesEntity_GetManyCanceled(t *testing.T) {
+	var requests atomic.Int32
+
+	server := balanceServer(t, &requests)
+	defer server.Close()
+
+	entity := NewBalancesEntity(server.Client(), "test-token", map[string]string{"transaction": server.URL})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := entity.GetMany(ctx, "org-1", "ledger-1", []string{"acc-1", "acc-2"}, nil)
+	require.NoError(t, err)
+
+	for _, r := range results {
+		assert.Error(t, r.Error)
+	}
+}
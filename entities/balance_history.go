@@ -0,0 +1,153 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// balanceHistoryDateLayout is the format of the date query parameter of the balance history endpoint.
+const balanceHistoryDateLayout = "2006-01-02 15:04:05"
+
+// AsOf retrieves the balances of an account at a past point in time.
+//
+// The balance history endpoint is used when the server provides it. Otherwise
+// the operations of the account are replayed: the balance after the last
+// operation of each balance at or before at is its state at that time.
+// Replaying lists every operation of the account, so it is slow for busy
+// accounts, and balances that had no operation yet by that time are not
+// reported.
+func (e *balancesEntity) AsOf(ctx context.Context, orgID, ledgerID, accountID string, at time.Time) ([]models.BalanceHistory, error) {
+	const operation = "BalancesAsOf"
+
+	if orgID == "" {
+		return nil, errors.NewMissingParameterError(operation, "organizationID")
+	}
+
+	if ledgerID == "" {
+		return nil, errors.NewMissingParameterError(operation, "ledgerID")
+	}
+
+	if accountID == "" {
+		return nil, errors.NewMissingParameterError(operation, "accountID")
+	}
+
+	if at.IsZero() {
+		return nil, errors.NewMissingParameterError(operation, "at")
+	}
+
+	if at.After(time.Now()) {
+		return nil, errors.NewValidationError(operation, "timestamp must not be in the future", nil)
+	}
+
+	balances, err := e.getBalanceHistory(ctx, orgID, ledgerID, accountID, at)
+	if err == nil {
+		return balances, nil
+	}
+
+	// servers without the history endpoint answer not found as well
+	if !errors.IsNotFoundError(err) {
+		return nil, err
+	}
+
+	balances, err = e.replayBalances(ctx, orgID, ledgerID, accountID, at)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(balances) == 0 {
+		return nil, errors.NewNotFoundError(operation, "balance", accountID,
+			fmt.Errorf("no balance data at %s", at.UTC().Format(time.RFC3339)))
+	}
+
+	return balances, nil
+}
+
+// getBalanceHistory calls the balance history endpoint of the account.
+func (e *balancesEntity) getBalanceHistory(ctx context.Context, orgID, ledgerID, accountID string, at time.Time) ([]models.BalanceHistory, error) {
+	const operation = "GetBalanceHistory"
+
+	endpoint := e.buildAccountURL(orgID, ledgerID, accountID) + "/history"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, errors.NewInternalError(operation, err)
+	}
+
+	q := req.URL.Query()
+	q.Set("date", at.UTC().Format(balanceHistoryDateLayout))
+	req.URL.RawQuery = q.Encode()
+
+	var balances []models.BalanceHistory
+	if err := e.httpClient.sendRequest(req, &balances); err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+// replayBalances reconstructs the balances of an account at a point in time from its operations.
+func (e *balancesEntity) replayBalances(ctx context.Context, orgID, ledgerID, accountID string, at time.Time) ([]models.BalanceHistory, error) {
+	operations := &operationsEntity{HTTPClient: e.httpClient, baseURLs: e.baseURLs}
+
+	// last operation at or before at, per balance
+	latest := make(map[string]models.Operation)
+	opts := models.NewListOptions().WithLimit(models.MaxLimit)
+
+	for opts != nil {
+		page, err := operations.ListOperations(ctx, orgID, ledgerID, accountID, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, op := range page.Items {
+			if op.BalanceID == "" || op.BalanceAfter.IsEmpty() || op.CreatedAt.After(at) {
+				continue
+			}
+
+			if prev, ok := latest[op.BalanceID]; ok && prev.CreatedAt.After(op.CreatedAt) {
+				continue
+			}
+
+			latest[op.BalanceID] = op
+		}
+
+		if len(page.Items) == 0 {
+			break
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	balances := make([]models.BalanceHistory, 0, len(latest))
+	for _, op := range latest {
+		balance := models.BalanceHistory{
+			ID:             op.BalanceID,
+			OrganizationID: op.OrganizationID,
+			LedgerID:       op.LedgerID,
+			AccountID:      op.AccountID,
+			Alias:          op.AccountAlias,
+			AssetCode:      op.AssetCode,
+			UpdatedAt:      op.CreatedAt,
+		}
+
+		if op.BalanceAfter.Available != nil {
+			balance.Available = *op.BalanceAfter.Available
+		}
+
+		if op.BalanceAfter.OnHold != nil {
+			balance.OnHold = *op.BalanceAfter.OnHold
+		}
+
+		balances = append(balances, balance)
+	}
+
+	sort.Slice(balances, func(i, j int) bool { return balances[i].ID < balances[j].ID })
+
+	return balances, nil
+}
@@ -0,0 +1,100 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalancesEntity_AsOfHistoryEndpoint(t *testing.T) {
+	var date string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/organizations/org-1/ledgers/ledger-1/accounts/acc-1/balances/history", r.URL.Path)
+		date = r.URL.Query().Get("date")
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":"bal-1","accountId":"acc-1","assetCode":"USD","available":"150","onHold":"0"}]`))
+	}))
+	defer server.Close()
+
+	entity := NewBalancesEntity(server.Client(), "test-token", map[string]string{"transaction": server.URL})
+	at := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	balances, err := entity.AsOf(context.Background(), "org-1", "ledger-1", "acc-1", at)
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+
+	assert.Equal(t, "2024-01-15 10:30:00", date)
+	assert.Equal(t, "bal-1", balances[0].ID)
+	assert.Equal(t, "150", balances[0].Available.String())
+}
+
+func TestBalancesEntity_AsOfReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if strings.HasSuffix(r.URL.Path, "/balances/history") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"0000","message":"Cannot GET"}`))
+
+			return
+		}
+
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/accounts/acc-1/operations"))
+
+		if r.URL.Query().Get("cursor") == "" {
+			_, _ = w.Write([]byte(`{"items":[
+				{"id":"op-3","balanceId":"bal-usd","accountId":"acc-1","assetCode":"USD","balanceAfter":{"available":"999","onHold":"0"},"createdAt":"2024-02-01T00:00:00Z"},
+				{"id":"op-2","balanceId":"bal-usd","accountId":"acc-1","assetCode":"USD","balanceAfter":{"available":"70","onHold":"10"},"createdAt":"2024-01-10T00:00:00Z"}
+			],"pagination":{"limit":2,"nextCursor":"next"}}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"items":[
+			{"id":"op-1","balanceId":"bal-usd","accountId":"acc-1","assetCode":"USD","balanceAfter":{"available":"100","onHold":"0"},"createdAt":"2024-01-05T00:00:00Z"},
+			{"id":"op-0","balanceId":"bal-brl","accountId":"acc-1","assetCode":"BRL","balanceAfter":{"available":"5","onHold":"0"},"createdAt":"2024-01-01T00:00:00Z"}
+		],"pagination":{"limit":2}}`))
+	}))
+	defer server.Close()
+
+	entity := NewBalancesEntity(server.Client(), "test-token", map[string]string{"transaction": server.URL})
+
+	balances, err := entity.AsOf(context.Background(), "org-1", "ledger-1", "acc-1", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, balances, 2)
+
+	assert.Equal(t, "bal-brl", balances[0].ID)
+	assert.Equal(t, "5", balances[0].Available.String())
+	assert.Equal(t, "bal-usd", balances[1].ID)
+	assert.Equal(t, "70", balances[1].Available.String())
+	assert.Equal(t, "10", balances[1].OnHold.String())
+
+	_, err = entity.AsOf(context.Background(), "org-1", "ledger-1", "acc-1", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.True(t, errors.IsNotFoundError(err))
+}
+
+func TestBalancesEntity_AsOfValidation(t *testing.T) {
+	entity := NewBalancesEntity(http.DefaultClient, "test-token", map[string]string{"transaction": "http://localhost"})
+	past := time.Now().Add(-time.Hour)
+
+	_, err := entity.AsOf(context.Background(), "", "ledger-1", "acc-1", past)
+	assert.Error(t, err)
+
+	_, err = entity.AsOf(context.Background(), "org-1", "ledger-1", "", past)
+	assert.Error(t, err)
+
+	_, err = entity.AsOf(context.Background(), "org-1", "ledger-1", "acc-1", time.Time{})
+	assert.Error(t, err)
+
+	_, err = entity.AsOf(context.Background(), "org-1", "ledger-1", "acc-1", time.Now().Add(time.Hour))
+	assert.True(t, errors.IsValidationError(err))
+}
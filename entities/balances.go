@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"time"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
@@ -151,9 +152,8 @@ type BalancesService interface {
 	//	fmt.Printf("Account has %d different asset balances\n", len(balances.Items))
 	//
 	//	for _, balance := range balances.Items {
-	//	    // Calculate the decimal value of the balance
-	//	    decimalValue := float64(balance.Available) / math.Pow10(int(balance.Scale))
-	//	    fmt.Printf("Asset: %s, Available: %.2f\n", balance.AssetCode, decimalValue)
+	//	    // Balances are exact decimals, whatever the scale of the asset
+	//	    fmt.Printf("Asset: %s, Available: %s\n", balance.AssetCode, balance.Available)
 	//	}
 
 	//
@@ -185,8 +185,7 @@ type BalancesService interface {
 	//	// Process the balances
 	//	fmt.Println("Currency balances for account:")
 	//	for _, balance := range balances.Items {
-	//	    decimalValue := float64(balance.Available) / math.Pow10(int(balance.Scale))
-	//	    fmt.Printf("%s: %.2f\n", balance.AssetCode, decimalValue)
+	//	    fmt.Printf("%s: %s\n", balance.AssetCode, balance.Available)
 	//	}
 
 	ListAccountBalances(ctx context.Context, orgID, ledgerID, accountID string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error)
@@ -222,6 +221,18 @@ type BalancesService interface {
 	// The external code links the account to external systems.
 	// Returns a paginated list of balances, or an error if the operation fails.
 	ListBalancesByExternalCode(ctx context.Context, orgID, ledgerID, code string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error)
+
+	// GetMany retrieves the balances of many accounts at once.
+	// Accounts are fetched in parallel, rate-limited and optionally cached, as configured by opts (nil uses the defaults).
+	// Returns the balances keyed by account ID, each with its own error, so one failing account doesn't fail the others.
+	// The error is only set for invalid parameters.
+	GetMany(ctx context.Context, orgID, ledgerID string, accountIDs []string, opts *GetManyOptions) (map[string]*AccountBalances, error)
+
+	// AsOf retrieves the balances of an account as they were at a past point in time.
+	// It uses the balance history endpoint, and falls back to replaying the operations of the account
+	// when the server doesn't provide it.
+	// Returns one entry per balance that existed at that time, or a not found error if there was none.
+	AsOf(ctx context.Context, orgID, ledgerID, accountID string, at time.Time) ([]models.BalanceHistory, error)
 }
 
 // balancesEntity implements the BalancesService interface.
@@ -229,10 +240,12 @@ type BalancesService interface {
 type balancesEntity struct {
 	httpClient *HTTPClient
 	baseURLs   map[string]string
+	cache      balanceCache
 }
 
-func (e *balancesEntity) setDefaultTenantID(tenantID string) {
-	e.httpClient.SetTenantID(tenantID)
+// useSettings makes the service share the settings of its Entity.
+func (e *balancesEntity) useSettings(settings *clientSettings) {
+	e.httpClient.clientSettings = settings
 }
 
 // NewBalancesEntity creates a new balances entity.
@@ -454,7 +467,7 @@ func (e *balancesEntity) UpdateBalance(
 	}
 
 	// Validate the input using the model's validation method
-	if err := input.Validate(); err != nil {
+	if err := e.httpClient.validateInput(ctx, operation, input.Validate); err != nil {
 		return nil, errors.NewValidationError(operation, "invalid balance update input", err)
 	}
 
@@ -558,7 +571,7 @@ func (e *balancesEntity) CreateBalance(ctx context.Context, orgID, ledgerID, acc
 		return nil, errors.NewMissingParameterError(operation, "input")
 	}
 
-	if err := input.Validate(); err != nil {
+	if err := e.httpClient.validateInput(ctx, operation, input.Validate); err != nil {
 		return nil, errors.NewValidationError(operation, "invalid input", err)
 	}
 
@@ -11,9 +11,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities/mocks"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/performance"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
+	"github.com/golang/mock/gomock"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -233,6 +235,7 @@ func newBalancesHTTPClientAdapter(mock *MockHTTPClient) *HTTPClient {
 	_ = retry.WithRetryableHTTPCodes(retry.DefaultRetryableHTTPCodes)(retryOptions)
 
 	return &HTTPClient{
+		clientSettings: &clientSettings{},
 		client: &http.Client{
 			Transport: &mockTransport{mock: mock},
 		},
@@ -2128,3 +2131,9 @@ func TestUpdateBalanceInput_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestMockBalancesService_ImplementsInterface(t *testing.T) {
+	var service BalancesService = mocks.NewMockBalancesService(gomock.NewController(t))
+
+	assert.NotNil(t, service, "the mock must be regenerated when BalancesService changes")
+}
@@ -0,0 +1,279 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// DefaultBulkConcurrency is the number of accounts BulkDeactivateAccounts processes in parallel by default.
+const DefaultBulkConcurrency = 5
+
+// AccountStatusInactive is the status set on accounts by BulkDeactivateAccounts.
+const AccountStatusInactive = "INACTIVE"
+
+// BulkAccountFilter selects the accounts of a ledger processed by a bulk
+// operation. Empty fields match any value, set fields must all match.
+type BulkAccountFilter struct {
+	OrganizationID string
+	LedgerID       string
+
+	// AccountIDs restricts the operation to these accounts
+	AccountIDs []string
+
+	Type        string
+	SegmentID   string
+	PortfolioID string
+	AssetCode   string
+	Status      string
+
+	// Match, if set, must also accept the account
+	Match func(models.Account) bool
+}
+
+// BulkDeactivateOptions configures BulkDeactivateAccounts.
+type BulkDeactivateOptions struct {
+	// Delete deletes the accounts instead of setting their status to INACTIVE
+	Delete bool
+
+	// Force processes accounts with non-zero balances instead of skipping them
+	Force bool
+
+	// DryRun reports what would be done without changing any account
+	DryRun bool
+
+	// Concurrency is the number of accounts processed in parallel, DefaultBulkConcurrency if zero
+	Concurrency int
+
+	// RateLimit caps the requests per second across all workers; zero means no limit
+	RateLimit int
+}
+
+// BulkAction is what a bulk operation did with an account.
+type BulkAction string
+
+// Bulk actions reported by BulkDeactivateAccounts.
+const (
+	BulkActionDeactivated BulkAction = "deactivated"
+	BulkActionDeleted     BulkAction = "deleted"
+	BulkActionSkipped     BulkAction = "skipped"
+	BulkActionFailed      BulkAction = "failed"
+	// BulkActionDryRun is reported for accounts that would be processed in a dry run
+	BulkActionDryRun BulkAction = "dry-run"
+)
+
+// BulkAccountResult is the outcome of a bulk operation for one account.
+type BulkAccountResult struct {
+	Account models.Account
+	Action  BulkAction
+
+	// Reason explains why the account was skipped
+	Reason string
+
+	// Error is the failure to process the account, if any
+	Error error
+}
+
+// BulkAccountReport summarizes a bulk operation.
+type BulkAccountReport struct {
+	Matched     int
+	Deactivated int
+	Deleted     int
+	Skipped     int
+	Failed      int
+	DryRun      int
+	Duration    time.Duration
+
+	// Results holds one entry per matched account, in listing order
+	Results []BulkAccountResult
+}
+
+// BulkDeactivateAccounts lists the accounts of a ledger matching filter and
+// deactivates them, or deletes them with opts.Delete, in parallel. Accounts
+// holding funds, available or on hold, are skipped unless opts.Force is set,
+// as are accounts already inactive when deactivating. Failures of single
+// accounts are reported in the result rather than returned; the error is only
+// set when the accounts can't be listed.
+func (e *Entity) BulkDeactivateAccounts(ctx context.Context, filter BulkAccountFilter, opts *BulkDeactivateOptions) (*BulkAccountReport, error) {
+	const operation = "BulkDeactivateAccounts"
+
+	if filter.OrganizationID == "" {
+		return nil, errors.NewMissingParameterError(operation, "organizationID")
+	}
+
+	if filter.LedgerID == "" {
+		return nil, errors.NewMissingParameterError(operation, "ledgerID")
+	}
+
+	if e.Accounts == nil || e.Balances == nil {
+		return nil, errors.NewValidationError(operation, "the accounts and balances services are required", nil)
+	}
+
+	if opts == nil {
+		opts = &BulkDeactivateOptions{}
+	}
+
+	started := time.Now()
+
+	accounts, err := e.listBulkAccounts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var limiter concurrent.Limiter
+	if opts.RateLimit > 0 {
+		limiter = concurrent.NewLimiter(concurrent.AlgorithmGCRA, opts.RateLimit, 1)
+		defer limiter.Stop()
+	}
+
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = DefaultBulkConcurrency
+	}
+
+	results := concurrent.WorkerPool(ctx, accounts, func(ctx context.Context, account models.Account) (BulkAccountResult, error) {
+		return e.deactivateAccount(ctx, filter, account, opts, limiter), nil
+	}, concurrent.WithWorkers(workers))
+
+	report := &BulkAccountReport{Matched: len(accounts), Results: make([]BulkAccountResult, len(accounts))}
+
+	for i, account := range accounts {
+		result := results[i].Value
+		// items skipped after cancellation have no result
+		if results[i].Item.ID != account.ID {
+			result = BulkAccountResult{Account: account, Action: BulkActionFailed, Error: contextError(ctx)}
+		}
+
+		report.Results[i] = result
+
+		switch result.Action {
+		case BulkActionDeactivated:
+			report.Deactivated++
+		case BulkActionDeleted:
+			report.Deleted++
+		case BulkActionSkipped:
+			report.Skipped++
+		case BulkActionFailed:
+			report.Failed++
+		case BulkActionDryRun:
+			report.DryRun++
+		}
+	}
+
+	report.Duration = time.Since(started)
+
+	return report, nil
+}
+
+// deactivateAccount applies the bulk operation to one account.
+func (e *Entity) deactivateAccount(ctx context.Context, filter BulkAccountFilter, account models.Account, opts *BulkDeactivateOptions, limiter concurrent.Limiter) BulkAccountResult {
+	result := BulkAccountResult{Account: account}
+
+	if !opts.Delete && account.Status.Code == AccountStatusInactive {
+		result.Action, result.Reason = BulkActionSkipped, "already inactive"
+		return result
+	}
+
+	if !opts.Force {
+		funded := false
+
+		err := listAllBalances(ctx, limiter, func(listOpts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+			return e.Balances.ListAccountBalances(ctx, filter.OrganizationID, filter.LedgerID, account.ID, listOpts)
+		}, func(b models.Balance) {
+			if !b.Available.IsZero() || !b.OnHold.IsZero() {
+				funded = true
+			}
+		})
+		if err != nil {
+			result.Action, result.Error = BulkActionFailed, fmt.Errorf("failed to check balances: %w", err)
+			return result
+		}
+
+		if funded {
+			result.Action, result.Reason = BulkActionSkipped, "non-zero balance"
+			return result
+		}
+	}
+
+	if opts.DryRun {
+		result.Action = BulkActionDryRun
+		return result
+	}
+
+	if limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			result.Action, result.Error = BulkActionFailed, err
+			return result
+		}
+	}
+
+	if opts.Delete {
+		result.Action, result.Error = BulkActionDeleted, e.Accounts.DeleteAccount(ctx, filter.OrganizationID, filter.LedgerID, account.ID)
+	} else {
+		input := &models.UpdateAccountInput{Name: account.Name, Status: models.NewStatus(AccountStatusInactive)}
+		_, err := e.Accounts.UpdateAccount(ctx, filter.OrganizationID, filter.LedgerID, account.ID, input)
+		result.Action, result.Error = BulkActionDeactivated, err
+	}
+
+	if result.Error != nil {
+		result.Action = BulkActionFailed
+	}
+
+	return result
+}
+
+// listBulkAccounts lists the accounts of the ledger matching filter.
+func (e *Entity) listBulkAccounts(ctx context.Context, filter BulkAccountFilter) ([]models.Account, error) {
+	var ids map[string]bool
+	if len(filter.AccountIDs) > 0 {
+		ids = make(map[string]bool, len(filter.AccountIDs))
+		for _, id := range filter.AccountIDs {
+			ids[id] = true
+		}
+	}
+
+	match := AccountIndexFilter{
+		Type:        filter.Type,
+		SegmentID:   filter.SegmentID,
+		PortfolioID: filter.PortfolioID,
+		AssetCode:   filter.AssetCode,
+		Status:      filter.Status,
+	}
+
+	var accounts []models.Account
+
+	opts := models.NewListOptions().WithLimit(models.MaxLimit)
+
+	for opts != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, err := e.Accounts.ListAccounts(ctx, filter.OrganizationID, filter.LedgerID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+
+		for _, a := range page.Items {
+			if ids != nil && !ids[a.ID] {
+				continue
+			}
+
+			if accountMatches(a, match) && (filter.Match == nil || filter.Match(a)) {
+				accounts = append(accounts, a)
+			}
+		}
+
+		if len(page.Items) == 0 {
+			break
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,173 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBulkAccountsService struct {
+	fakeIndexAccountsService
+
+	updateErr error
+	updated   sync.Map
+	deleted   sync.Map
+}
+
+func (f *fakeBulkAccountsService) UpdateAccount(_ context.Context, _, _, id string, input *models.UpdateAccountInput) (*models.Account, error) {
+	if f.updateErr != nil {
+		return nil, f.updateErr
+	}
+
+	f.updated.Store(id, *input)
+
+	return &models.Account{ID: id, Name: input.Name, Status: input.Status}, nil
+}
+
+func (f *fakeBulkAccountsService) DeleteAccount(_ context.Context, _, _, id string) error {
+	f.deleted.Store(id, true)
+	return nil
+}
+
+type fakeBulkBalancesService struct {
+	BalancesService
+
+	balances map[string][]models.Balance
+}
+
+func (f *fakeBulkBalancesService) ListAccountBalances(_ context.Context, _, _, accountID string, _ *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	if accountID == "acc-broken" {
+		return nil, errors.New("balance lookup failed")
+	}
+
+	items := f.balances[accountID]
+
+	return &models.ListResponse[models.Balance]{Items: items, Pagination: models.Pagination{Limit: models.MaxLimit, Total: len(items)}}, nil
+}
+
+func newBulkTestEntity() (*Entity, *fakeBulkAccountsService) {
+	accounts := &fakeBulkAccountsService{}
+	accounts.set(
+		indexedAccount("acc-empty", "", "deposit", "", ""),
+		indexedAccount("acc-funded", "", "deposit", "", ""),
+		indexedAccount("acc-on-hold", "", "deposit", "", ""),
+		indexedAccount("acc-broken", "", "deposit", "", ""),
+		indexedAccount("acc-savings", "", "savings", "", ""),
+	)
+
+	inactive := indexedAccount("acc-inactive", "", "deposit", "", "")
+	inactive.Status = models.NewStatus(AccountStatusInactive)
+	accounts.accounts = append(accounts.accounts, inactive)
+
+	balances := &fakeBulkBalancesService{balances: map[string][]models.Balance{
+		"acc-empty":   {{AccountID: "acc-empty", Available: decimal.Zero, OnHold: decimal.Zero}},
+		"acc-funded":  {{AccountID: "acc-funded", Available: decimal.NewFromInt(10)}},
+		"acc-on-hold": {{AccountID: "acc-on-hold", OnHold: decimal.NewFromInt(1)}},
+	}}
+
+	return &Entity{Accounts: accounts, Balances: balances}, accounts
+}
+
+func TestBulkDeactivateAccounts(t *testing.T) {
+	entity, accounts := newBulkTestEntity()
+
+	report, err := entity.BulkDeactivateAccounts(context.Background(),
+		BulkAccountFilter{OrganizationID: "org-1", LedgerID: "ledger-1", Type: "deposit"},
+		&BulkDeactivateOptions{Concurrency: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, report.Matched)
+	assert.Equal(t, 1, report.Deactivated)
+	assert.Equal(t, 3, report.Skipped)
+	assert.Equal(t, 1, report.Failed)
+
+	actions := make(map[string]BulkAction)
+	for _, r := range report.Results {
+		actions[r.Account.ID] = r.Action
+	}
+
+	assert.Equal(t, map[string]BulkAction{
+		"acc-empty":    BulkActionDeactivated,
+		"acc-funded":   BulkActionSkipped,
+		"acc-on-hold":  BulkActionSkipped,
+		"acc-broken":   BulkActionFailed,
+		"acc-inactive": BulkActionSkipped,
+	}, actions)
+
+	input, ok := accounts.updated.Load("acc-empty")
+	require.True(t, ok)
+	assert.Equal(t, AccountStatusInactive, input.(models.UpdateAccountInput).Status.Code)
+	assert.Equal(t, "acc-empty", input.(models.UpdateAccountInput).Name, "the name is kept")
+
+	_, ok = accounts.updated.Load("acc-funded")
+	assert.False(t, ok, "accounts holding funds are left untouched")
+}
+
+func TestBulkDeactivateAccountsOptions(t *testing.T) {
+	ctx := context.Background()
+	filter := BulkAccountFilter{
+		OrganizationID: "org-1",
+		LedgerID:       "ledger-1",
+		AccountIDs:     []string{"acc-funded", "acc-broken", "acc-savings"},
+	}
+
+	t.Run("forced delete", func(t *testing.T) {
+		entity, accounts := newBulkTestEntity()
+
+		report, err := entity.BulkDeactivateAccounts(ctx, filter, &BulkDeactivateOptions{Delete: true, Force: true})
+		require.NoError(t, err)
+		assert.Equal(t, 3, report.Deleted)
+
+		_, ok := accounts.deleted.Load("acc-funded")
+		assert.True(t, ok)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		entity, accounts := newBulkTestEntity()
+
+		report, err := entity.BulkDeactivateAccounts(ctx, filter, &BulkDeactivateOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.DryRun)
+		assert.Equal(t, 1, report.Skipped)
+		assert.Equal(t, 1, report.Failed)
+
+		_, ok := accounts.updated.Load("acc-savings")
+		assert.False(t, ok, "a dry run changes nothing")
+	})
+
+	t.Run("update failure", func(t *testing.T) {
+		entity, accounts := newBulkTestEntity()
+		accounts.updateErr = errors.New("conflict")
+
+		report, err := entity.BulkDeactivateAccounts(ctx, BulkAccountFilter{
+			OrganizationID: "org-1",
+			LedgerID:       "ledger-1",
+			Match:          func(a models.Account) bool { return a.ID == "acc-empty" },
+		}, nil)
+		require.NoError(t, err)
+		require.Len(t, report.Results, 1)
+		assert.Equal(t, BulkActionFailed, report.Results[0].Action)
+		assert.EqualError(t, report.Results[0].Error, "conflict")
+	})
+
+	t.Run("list failure", func(t *testing.T) {
+		entity, accounts := newBulkTestEntity()
+		accounts.err = errors.New("unavailable")
+
+		_, err := entity.BulkDeactivateAccounts(ctx, filter, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("missing ledger", func(t *testing.T) {
+		entity, _ := newBulkTestEntity()
+
+		_, err := entity.BulkDeactivateAccounts(ctx, BulkAccountFilter{OrganizationID: "org-1"}, nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,198 @@
+package entities
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultClockSkewThreshold is the clock skew above which a warning is logged.
+	DefaultClockSkewThreshold = 30 * time.Second
+
+	// tokenExpiryLeeway renews tokens this long before they expire, so that
+	// they don't expire in flight.
+	tokenExpiryLeeway = 10 * time.Second
+
+	// clockSkewSmoothing weighs a new skew sample against the previous
+	// estimate; Date headers only have a one second resolution.
+	clockSkewSmoothing = 0.2
+)
+
+// serverClock estimates the offset between the server clock and the local
+// clock from the Date header of the responses. It is shared by the HTTP
+// clients of an Entity's services.
+type serverClock struct {
+	mu        sync.Mutex
+	offset    time.Duration
+	samples   int
+	threshold time.Duration
+	warned    bool
+
+	// warn is called when the skew exceeds the threshold, instead of logging
+	warn func(skew time.Duration)
+}
+
+func newServerClock() *serverClock {
+	return &serverClock{threshold: DefaultClockSkewThreshold}
+}
+
+// observe records the Date header of a response to a request sent at sent
+// and received at received. It returns the estimated skew and whether it has
+// just exceeded the threshold.
+func (c *serverClock) observe(date string, sent, received time.Time) (time.Duration, bool) {
+	serverTime, err := http.ParseTime(date)
+	if c == nil || err != nil {
+		return 0, false
+	}
+
+	// the header is truncated to the second and stamped while the request was in flight
+	sample := serverTime.Add(500 * time.Millisecond).Sub(sent.Add(received.Sub(sent) / 2))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.samples == 0 {
+		c.offset = sample
+	} else {
+		c.offset += time.Duration(clockSkewSmoothing * float64(sample-c.offset))
+	}
+
+	c.samples++
+
+	if c.threshold <= 0 || c.offset.Abs() <= c.threshold {
+		c.warned = false
+		return c.offset, false
+	}
+
+	warn := !c.warned
+	c.warned = true
+
+	return c.offset, warn
+}
+
+// skew returns the estimated server time minus local time, zero before any response.
+func (c *serverClock) skew() time.Duration {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.offset
+}
+
+// now returns the estimated server time.
+func (c *serverClock) now() time.Time {
+	return time.Now().Add(c.skew())
+}
+
+// WithClockSkewThreshold returns an Option that sets the clock skew above
+// which a warning is reported, DefaultClockSkewThreshold by default; zero
+// disables the warning. warn receives the skew, the server time minus the
+// local time; when nil, the warning goes to the logger of the observability
+// provider.
+//
+// Whatever the threshold, the skew measured from the Date header of the
+// responses is used to judge token expiry in server time, see ServerNow.
+func WithClockSkewThreshold(threshold time.Duration, warn func(skew time.Duration)) Option {
+	return func(e *Entity) error {
+		e.httpClient.clock.threshold = threshold
+		e.httpClient.clock.warn = warn
+
+		return nil
+	}
+}
+
+// ClockSkew returns the estimated difference between the server clock and the
+// local clock, positive when the server is ahead, measured from the Date
+// header of the responses. It is zero until a response is received.
+func (e *Entity) ClockSkew() time.Duration {
+	return e.httpClient.clock.skew()
+}
+
+// ServerNow returns the current time on the server clock, for computing
+// deadlines and windows the server enforces, such as token expiry or the
+// validity of an idempotency key, on hosts whose clock drifts.
+func (e *Entity) ServerNow() time.Time {
+	return e.httpClient.clock.now()
+}
+
+// observeServerDate records the Date header of a response, warning when the
+// clock skew exceeds the threshold.
+func (c *HTTPClient) observeServerDate(resp *http.Response, sent, received time.Time) {
+	skew, warn := c.clock.observe(resp.Header.Get("Date"), sent, received)
+	if !warn {
+		return
+	}
+
+	if c.clock != nil && c.clock.warn != nil {
+		c.clock.warn(skew)
+		return
+	}
+
+	if c.observability != nil && c.observability.IsEnabled() && c.observability.Logger() != nil {
+		c.observability.Logger().Warnf("Local clock is %v off the Midaz server clock; token expiry is judged in server time", skew)
+		return
+	}
+
+	c.debugLog("Local clock is %v off the Midaz server clock", skew)
+}
+
+// renewExpiredToken renews the token before a request when it is a JWT that
+// expires within tokenExpiryLeeway in server time.
+func (c *HTTPClient) renewExpiredToken(ctx context.Context) {
+	if c.tokens == nil || c.tokens.expiryDisabled() {
+		return
+	}
+
+	token := c.tokens.current()
+
+	expiry, ok := tokenExpiry(token)
+	if !ok || c.clock.now().Add(tokenExpiryLeeway).Before(expiry) {
+		return
+	}
+
+	renewed, err := c.tokens.renew(ctx, token)
+	if err != nil {
+		c.debugLog("Failed to renew expiring auth token: %v", err)
+		return
+	}
+
+	// A token issued already expired means the clocks disagree: stop renewing
+	// proactively and rely on 401 recovery rather than refreshing in a loop.
+	if expiry, ok := tokenExpiry(renewed); ok && !c.clock.now().Add(tokenExpiryLeeway).Before(expiry) {
+		c.debugLog("Renewed auth token already expired at %v, skipping proactive renewal", expiry)
+		c.tokens.disableExpiry()
+	}
+}
+
+// tokenExpiry returns the expiry of a JWT, from its exp claim.
+func tokenExpiry(token string) (time.Time, bool) {
+	token = strings.TrimSpace(strings.TrimPrefix(token, "Bearer "))
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp <= 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(claims.Exp), 0), true
+}
@@ -0,0 +1,163 @@
+package entities
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testJWT returns an unsigned JWT expiring at exp.
+func testJWT(exp time.Time) string {
+	enc := base64.RawURLEncoding
+
+	return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		enc.EncodeToString(fmt.Appendf(nil, `{"exp":%d}`, exp.Unix())) + ".sig"
+}
+
+// skewedServer answers with a Date header offset from the local clock by skew.
+func skewedServer(t *testing.T, skew time.Duration, authorized func(token string) bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Date", time.Now().Add(skew).UTC().Format(http.TimeFormat))
+
+		if authorized != nil && !authorized(r.Header.Get("Authorization")) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"id":"org-1"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+func TestClockSkewDetection(t *testing.T) {
+	srv, _ := skewedServer(t, 2*time.Hour, nil)
+
+	var warnings []time.Duration
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()), WithClockSkewThreshold(time.Minute, func(skew time.Duration) {
+		warnings = append(warnings, skew)
+	}))
+	require.NoError(t, err)
+	assert.Zero(t, entity.ClockSkew(), "unknown before any response")
+
+	for i := 0; i < 3; i++ {
+		_, err = entity.Organizations.GetOrganization(context.Background(), "org-1")
+		require.NoError(t, err)
+	}
+
+	assert.InDelta(t, (2 * time.Hour).Seconds(), entity.ClockSkew().Seconds(), 2)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), entity.ServerNow(), 2*time.Second)
+
+	require.Len(t, warnings, 1, "the warning is reported once")
+	assert.InDelta(t, (2 * time.Hour).Seconds(), warnings[0].Seconds(), 2)
+}
+
+func TestServerClockSmoothing(t *testing.T) {
+	c := newServerClock()
+	now := time.Now()
+
+	_, warn := c.observe(now.Add(10*time.Second).UTC().Format(http.TimeFormat), now, now)
+	assert.False(t, warn)
+	assert.InDelta(t, 10, c.skew().Seconds(), 1)
+
+	// an outlier only moves the estimate partially
+	_, _ = c.observe(now.Add(20*time.Second).UTC().Format(http.TimeFormat), now, now)
+	assert.InDelta(t, 12, c.skew().Seconds(), 1)
+
+	_, warn = c.observe("not a date", now, now)
+	assert.False(t, warn)
+	assert.InDelta(t, 12, c.skew().Seconds(), 1)
+}
+
+func TestTokenExpiry(t *testing.T) {
+	exp := time.Unix(1_900_000_000, 0)
+
+	got, ok := tokenExpiry(testJWT(exp))
+	require.True(t, ok)
+	assert.True(t, exp.Equal(got))
+
+	got, ok = tokenExpiry("Bearer " + testJWT(exp))
+	require.True(t, ok)
+	assert.True(t, exp.Equal(got))
+
+	_, ok = tokenExpiry("opaque-token")
+	assert.False(t, ok)
+
+	_, ok = tokenExpiry("a.b.c")
+	assert.False(t, ok)
+}
+
+func TestTokenRenewedBeforeExpiry(t *testing.T) {
+	fresh := testJWT(time.Now().Add(time.Hour))
+	srv, requests := skewedServer(t, 0, func(token string) bool { return token == fresh })
+
+	var refreshes atomic.Int32
+
+	baseURLs := map[string]string{"onboarding": srv.URL, "transaction": srv.URL}
+	entity, err := NewEntity(srv.Client(), testJWT(time.Now().Add(5*time.Second)), baseURLs, nil,
+		WithTokenRefresher(func(context.Context) (string, error) {
+			refreshes.Add(1)
+			return fresh, nil
+		}))
+	require.NoError(t, err)
+
+	_, err = entity.Organizations.GetOrganization(context.Background(), "org-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), refreshes.Load())
+	assert.Equal(t, int32(1), requests.Load(), "the token is renewed before the request, not after a 401")
+}
+
+func TestTokenRenewalNoLoopWithSkew(t *testing.T) {
+	// the local clock is an hour ahead of the server: tokens valid for five
+	// minutes in server time look expired locally
+	skew := -time.Hour
+	srv, _ := skewedServer(t, skew, nil)
+
+	var refreshes atomic.Int32
+
+	baseURLs := map[string]string{"onboarding": srv.URL, "transaction": srv.URL}
+	entity, err := NewEntity(srv.Client(), "opaque-token", baseURLs, nil,
+		WithClockSkewThreshold(time.Minute, func(time.Duration) {}),
+		WithTokenRefresher(func(context.Context) (string, error) {
+			refreshes.Add(1)
+			return testJWT(time.Now().Add(skew + 5*time.Minute)), nil
+		}))
+	require.NoError(t, err)
+
+	// learn the skew, then switch to a token valid in server time
+	_, err = entity.Organizations.GetOrganization(context.Background(), "org-1")
+	require.NoError(t, err)
+	entity.SetAuthToken(testJWT(time.Now().Add(skew + 5*time.Minute)))
+
+	for i := 0; i < 5; i++ {
+		_, err = entity.Organizations.GetOrganization(context.Background(), "org-1")
+		require.NoError(t, err)
+	}
+
+	assert.Zero(t, refreshes.Load(), "tokens valid in server time are not renewed")
+
+	// an expired token is renewed once, not before every request
+	entity.SetAuthToken(testJWT(time.Now().Add(skew - time.Minute)))
+
+	for i := 0; i < 5; i++ {
+		_, err = entity.Organizations.GetOrganization(context.Background(), "org-1")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), refreshes.Load())
+}
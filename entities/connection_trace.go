@@ -0,0 +1,302 @@
+package entities
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// DefaultConnectionTraceSize is the number of requests kept by a
+// ConnectionTrace created with a non-positive size.
+const DefaultConnectionTraceSize = 100
+
+// ConnectionTiming is the connection timings of a request recorded by a
+// ConnectionTrace. Durations are zero for the phases the request skipped:
+// a request over a reused connection has no DNS, connect or TLS time.
+type ConnectionTiming struct {
+	Time time.Time `json:"time"`
+	// Operation is the method and route of the request, with identifiers
+	// replaced by {id}, e.g. "POST /v1/organizations/{id}/ledgers"
+	Operation string `json:"operation"`
+	Host      string `json:"host"`
+
+	// Reused reports whether the request went over a connection used by an
+	// earlier request; IdleTime is how long it had been idle in the pool
+	Reused   bool          `json:"reused"`
+	IdleTime time.Duration `json:"idleTime,omitempty"`
+
+	DNS     time.Duration `json:"dns,omitempty"`
+	Connect time.Duration `json:"connect,omitempty"`
+	TLS     time.Duration `json:"tls,omitempty"`
+
+	// Wait is the time spent getting a connection, including DNS, connect and
+	// TLS for a new one or waiting for a free one when the pool is exhausted
+	Wait time.Duration `json:"wait"`
+
+	// FirstByte is the time from the request written to the first response
+	// byte, the latency of the server
+	FirstByte time.Duration `json:"firstByte"`
+	Total     time.Duration `json:"total"`
+
+	// Error is the transport error, for requests that got no response
+	Error string `json:"error,omitempty"`
+}
+
+// ConnectionStats aggregates the requests recorded by a ConnectionTrace
+// since its creation, including those evicted from its entries.
+type ConnectionStats struct {
+	Requests int `json:"requests"`
+	Reused   int `json:"reused"`
+
+	// NewConnections counts the successful requests over a new connection
+	NewConnections int `json:"newConnections"`
+
+	// AvgDNS, AvgConnect and AvgTLS average the setup of new connections
+	AvgDNS     time.Duration `json:"avgDns"`
+	AvgConnect time.Duration `json:"avgConnect"`
+	AvgTLS     time.Duration `json:"avgTls"`
+
+	// AvgWait and AvgFirstByte average all requests
+	AvgWait      time.Duration `json:"avgWait"`
+	AvgFirstByte time.Duration `json:"avgFirstByte"`
+}
+
+// ReuseRate returns the share of requests that reused a connection, in [0, 1].
+// A low rate under load points to connection churn: an idle pool too small
+// for the concurrency, or responses closed without being read.
+func (s ConnectionStats) ReuseRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+
+	return float64(s.Reused) / float64(s.Requests)
+}
+
+// ConnectionTrace records the connection timings of the requests of an
+// Entity, to tell whether a throughput plateau comes from connection churn
+// (low reuse, time spent in DNS, connect and TLS) or from the server (time
+// to first byte). It keeps the most recent requests and aggregates all of
+// them. It is safe for concurrent use.
+//
+// Example:
+//
+//	trace := entities.NewConnectionTrace(0).WithLogger(log.Printf)
+//	entity, err := entities.New(baseURL, entities.WithConnectionTrace(trace))
+//	...
+//	stats := trace.Stats()
+//	log.Printf("reuse %.0f%%, avg connect %s, avg first byte %s", stats.ReuseRate()*100, stats.AvgConnect, stats.AvgFirstByte)
+type ConnectionTrace struct {
+	ring *ring[ConnectionTiming]
+	logf func(format string, args ...any)
+
+	mu     sync.Mutex
+	totals connectionTotals
+}
+
+// connectionTotals sums the timings averaged by ConnectionStats
+type connectionTotals struct {
+	requests, reused, newConnections   int
+	dns, connect, tls, wait, firstByte time.Duration
+}
+
+// NewConnectionTrace creates a ConnectionTrace keeping the last size requests.
+func NewConnectionTrace(size int) *ConnectionTrace {
+	if size <= 0 {
+		size = DefaultConnectionTraceSize
+	}
+
+	return &ConnectionTrace{ring: newRing[ConnectionTiming](size)}
+}
+
+// WithLogger makes the trace log one line per request with logf.
+func (t *ConnectionTrace) WithLogger(logf func(format string, args ...any)) *ConnectionTrace {
+	t.logf = logf
+	return t
+}
+
+// Record adds the timings of a request, evicting the oldest one when the
+// trace is full.
+func (t *ConnectionTrace) Record(timing ConnectionTiming) {
+	t.ring.add(timing)
+
+	t.mu.Lock()
+	t.totals.requests++
+	t.totals.wait += timing.Wait
+	t.totals.firstByte += timing.FirstByte
+
+	switch {
+	case timing.Reused:
+		t.totals.reused++
+	case timing.Error == "":
+		t.totals.newConnections++
+		t.totals.dns += timing.DNS
+		t.totals.connect += timing.Connect
+		t.totals.tls += timing.TLS
+	}
+	t.mu.Unlock()
+
+	if t.logf != nil {
+		t.logf("Connection: %s host=%s reused=%t idle=%s dns=%s connect=%s tls=%s wait=%s firstByte=%s total=%s%s",
+			timing.Operation, timing.Host, timing.Reused, timing.IdleTime, timing.DNS, timing.Connect, timing.TLS,
+			timing.Wait, timing.FirstByte, timing.Total, errorSuffix(timing.Error))
+	}
+}
+
+// Entries returns the recorded requests, oldest first.
+func (t *ConnectionTrace) Entries() []ConnectionTiming {
+	return t.ring.list()
+}
+
+// Stats returns the aggregates of all the recorded requests.
+func (t *ConnectionTrace) Stats() ConnectionStats {
+	t.mu.Lock()
+	totals := t.totals
+	t.mu.Unlock()
+
+	stats := ConnectionStats{Requests: totals.requests, Reused: totals.reused, NewConnections: totals.newConnections}
+
+	if n := time.Duration(totals.newConnections); n > 0 {
+		stats.AvgDNS = totals.dns / n
+		stats.AvgConnect = totals.connect / n
+		stats.AvgTLS = totals.tls / n
+	}
+
+	if n := time.Duration(totals.requests); n > 0 {
+		stats.AvgWait = totals.wait / n
+		stats.AvgFirstByte = totals.firstByte / n
+	}
+
+	return stats
+}
+
+// errorSuffix formats a transport error for a log line
+func errorSuffix(err string) string {
+	if err == "" {
+		return ""
+	}
+
+	return " error=" + err
+}
+
+// WithConnectionTrace returns an Option that records the connection timings
+// of every request of every service of the Entity in trace.
+func WithConnectionTrace(trace *ConnectionTrace) Option {
+	return func(e *Entity) error {
+		e.connectionTrace = trace
+
+		return nil
+	}
+}
+
+// ConnectionTrace returns the trace set with WithConnectionTrace, or nil.
+func (e *Entity) ConnectionTrace() *ConnectionTrace {
+	return e.connectionTrace
+}
+
+// connectionTimer collects the httptrace events of a request. The events of
+// a dial may come from another goroutine, hence the lock.
+type connectionTimer struct {
+	mu sync.Mutex
+
+	getConn, gotConn       time.Time
+	dnsStart, dnsDone      time.Time
+	connStart, connDone    time.Time
+	tlsStart, tlsDone      time.Time
+	wroteRequest, gotFirst time.Time
+
+	reused   bool
+	idleTime time.Duration
+}
+
+// clientTrace returns the httptrace hooks feeding the timer
+func (ct *connectionTimer) clientTrace() *httptrace.ClientTrace {
+	at := func(field *time.Time) {
+		ct.mu.Lock()
+		*field = time.Now()
+		ct.mu.Unlock()
+	}
+
+	return &httptrace.ClientTrace{
+		GetConn:  func(string) { at(&ct.getConn) },
+		DNSStart: func(httptrace.DNSStartInfo) { at(&ct.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { at(&ct.dnsDone) },
+		ConnectStart: func(string, string) {
+			ct.mu.Lock()
+			// with several addresses only the first attempt starts the phase
+			if ct.connStart.IsZero() {
+				ct.connStart = time.Now()
+			}
+			ct.mu.Unlock()
+		},
+		ConnectDone:       func(string, string, error) { at(&ct.connDone) },
+		TLSHandshakeStart: func() { at(&ct.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { at(&ct.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			ct.mu.Lock()
+			ct.gotConn = time.Now()
+			ct.reused = info.Reused
+			ct.idleTime = info.IdleTime
+			ct.mu.Unlock()
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { at(&ct.wroteRequest) },
+		GotFirstResponseByte: func() { at(&ct.gotFirst) },
+	}
+}
+
+// timing returns the timings of the request, ended at end
+func (ct *connectionTimer) timing(start, end time.Time) ConnectionTiming {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	return ConnectionTiming{
+		Time:      end.UTC(),
+		Reused:    ct.reused,
+		IdleTime:  ct.idleTime,
+		DNS:       span(ct.dnsStart, ct.dnsDone),
+		Connect:   span(ct.connStart, ct.connDone),
+		TLS:       span(ct.tlsStart, ct.tlsDone),
+		Wait:      span(ct.getConn, ct.gotConn),
+		FirstByte: span(ct.wroteRequest, ct.gotFirst),
+		Total:     end.Sub(start),
+	}
+}
+
+// span returns the time between from and to, or zero when either is missing
+func span(from, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+
+	return to.Sub(from)
+}
+
+// tracingTransport records the connection timings of round trips in a
+// ConnectionTrace. When a request waits for a connection dialed on behalf
+// of another one, the dial timings go to the request that started it.
+type tracingTransport struct {
+	base  http.RoundTripper
+	trace *ConnectionTrace
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	timer := &connectionTimer{}
+	traced := req.WithContext(httptrace.WithClientTrace(req.Context(), timer.clientTrace()))
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(traced)
+
+	timing := timer.timing(start, time.Now())
+	timing.Operation = req.Method + " " + routeTemplate(req.URL.Path)
+	timing.Host = req.URL.Host
+
+	if err != nil {
+		timing.Error = err.Error()
+	}
+
+	t.trace.Record(timing)
+
+	return resp, err
+}
@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionTraceStats(t *testing.T) {
+	trace := NewConnectionTrace(2)
+	assert.Zero(t, trace.Stats().ReuseRate())
+
+	trace.Record(ConnectionTiming{Operation: "GET /a", DNS: 2 * time.Millisecond, Connect: 4 * time.Millisecond, Wait: 6 * time.Millisecond, FirstByte: 10 * time.Millisecond})
+	trace.Record(ConnectionTiming{Operation: "GET /b", Reused: true, FirstByte: 20 * time.Millisecond})
+	trace.Record(ConnectionTiming{Operation: "GET /c", Reused: true, FirstByte: 30 * time.Millisecond})
+	trace.Record(ConnectionTiming{Operation: "GET /d", Error: "connection refused"})
+
+	entries := trace.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "GET /c", entries[0].Operation)
+
+	stats := trace.Stats()
+	assert.Equal(t, 4, stats.Requests)
+	assert.Equal(t, 2, stats.Reused)
+	assert.Equal(t, 1, stats.NewConnections, "failed requests don't count as new connections")
+	assert.Equal(t, 2*time.Millisecond, stats.AvgDNS)
+	assert.Equal(t, 4*time.Millisecond, stats.AvgConnect)
+	assert.Equal(t, 15*time.Millisecond, stats.AvgFirstByte)
+	assert.InDelta(t, 0.5, stats.ReuseRate(), 1e-9)
+}
+
+func TestWithConnectionTrace(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	defer srv.Close()
+
+	var lines []string
+
+	trace := NewConnectionTrace(10).WithLogger(func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	})
+
+	entity, err := New(srv.URL, WithConnectionTrace(trace), WithActivityLog(NewActivityLog(10)), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+	assert.Same(t, trace, entity.ConnectionTrace())
+
+	for range 2 {
+		_, err = entity.Organizations.ListOrganizations(context.Background(), nil)
+		require.NoError(t, err)
+	}
+
+	entries := trace.Entries()
+	require.Len(t, entries, 2)
+	assert.False(t, entries[0].Reused)
+	assert.Positive(t, entries[0].Connect)
+	assert.True(t, entries[1].Reused, "the second request reuses the kept-alive connection")
+	assert.Zero(t, entries[1].Connect)
+	assert.Equal(t, "GET /organizations", entries[1].Operation)
+	assert.Equal(t, srv.Listener.Addr().String(), entries[1].Host)
+
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], "reused=true")
+
+	// the trace wraps the client transport, inside the activity recording
+	recording, ok := entity.servicesHTTPClient().Transport.(*recordingTransport)
+	require.True(t, ok)
+	assert.IsType(t, &tracingTransport{}, recording.base)
+}
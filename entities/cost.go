@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/cost"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/usage"
+)
+
+// WithCostHook returns an Option that reports the cost of every call made
+// through the services of the Entity to hook, such as a cost.Accountant.
+func WithCostHook(hook cost.Hook) Option {
+	return func(e *Entity) error {
+		e.httpClient.costHook = hook
+
+		return nil
+	}
+}
+
+// SetCostHook sets the hook receiving the cost of the calls of the HTTP
+// client; nil stops reporting.
+func (c *HTTPClient) SetCostHook(hook cost.Hook) {
+	c.costHook = hook
+}
+
+// callMeter counts the resources used by the attempts of a call.
+type callMeter struct {
+	requests      int
+	requestBytes  int64
+	responseBytes int64
+	failures      []string // errors of the failed attempts, see WithRetryJournal
+}
+
+// recordCost reports the cost of a call to the cost hook, if any.
+func (c *HTTPClient) recordCost(ctx context.Context, req *http.Request, method, requestURL string, meter *callMeter, resp *http.Response, err error, elapsed time.Duration) {
+	if c.costHook == nil {
+		return
+	}
+
+	sample := cost.Sample{
+		Operation:     usage.Operation(method, requestURL),
+		Tenant:        req.Header.Get(HeaderTenantID),
+		UseCase:       cost.UseCaseFromContext(ctx),
+		Requests:      meter.requests,
+		RequestBytes:  meter.requestBytes,
+		ResponseBytes: meter.responseBytes,
+		Failed:        err != nil,
+		Duration:      elapsed,
+	}
+
+	if resp != nil {
+		sample.StatusCode = resp.StatusCode
+		sample.Header = resp.Header.Clone()
+	}
+
+	c.costHook.RecordCost(ctx, sample)
+}
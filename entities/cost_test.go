@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/cost"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCostHook(t *testing.T) {
+	t.Setenv("MIDAZ_ENABLE_RETRIES", "false")
+
+	const body = `{"items":[]}`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Compute-Units", "2.5")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	var samples []cost.Sample
+
+	hook := cost.HookFunc(func(_ context.Context, sample cost.Sample) { samples = append(samples, sample) })
+
+	entity, err := New(srv.URL, WithCostHook(hook), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	entity.SetHTTPClient(srv.Client())
+
+	ctx := cost.WithUseCase(WithTenantID(context.Background(), "tenant-a"), "reconciliation")
+
+	_, err = entity.Ledgers.ListLedgers(ctx, "org-1", nil)
+	require.NoError(t, err)
+
+	require.Len(t, samples, 1)
+
+	sample := samples[0]
+	assert.Equal(t, "GET /organizations/{id}/ledgers", sample.Operation)
+	assert.Equal(t, "tenant-a", sample.Tenant)
+	assert.Equal(t, "reconciliation", sample.UseCase)
+	assert.Equal(t, 1, sample.Requests)
+	assert.Equal(t, int64(len(body)), sample.ResponseBytes)
+	assert.Equal(t, http.StatusOK, sample.StatusCode)
+	assert.False(t, sample.Failed)
+
+	accountant := cost.NewAccountant(cost.Pricing{PerComputeUnit: 2}, "X-Compute-Units")
+	accountant.RecordCost(ctx, sample)
+
+	report := accountant.Report()
+	require.Len(t, report.Entries, 1)
+	assert.InDelta(t, 5.0, report.TotalCost, 1e-9)
+}
@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultDeadlines bound the calls made with a context that has no deadline,
+// per operation class. A zero duration leaves the class unbounded.
+type DefaultDeadlines struct {
+	// Read bounds calls getting a single resource
+	Read time.Duration
+
+	// Write bounds calls creating, updating or deleting resources
+	Write time.Duration
+
+	// List bounds calls listing resources
+	List time.Duration
+}
+
+// WithDefaultDeadlines returns an Option that applies deadlines to every call
+// of the Entity's services whose context has no deadline. The deadline covers
+// the whole call, retries included.
+func WithDefaultDeadlines(deadlines DefaultDeadlines) Option {
+	return func(e *Entity) error {
+		e.httpClient.deadlines = deadlines
+
+		return nil
+	}
+}
+
+// SetDefaultDeadlines sets the deadlines applied to calls whose context has no deadline.
+func (c *HTTPClient) SetDefaultDeadlines(deadlines DefaultDeadlines) {
+	c.deadlines = deadlines
+}
+
+// withDefaultDeadline returns ctx bounded by the default deadline of the
+// request's operation class, unless ctx already has a deadline.
+func (c *HTTPClient) withDefaultDeadline(ctx context.Context, method, requestURL string) (context.Context, context.CancelFunc) {
+	if c.deadlines == (DefaultDeadlines{}) {
+		return ctx, func() {}
+	}
+
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+
+	timeout := c.deadlines.Write
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		timeout = c.deadlines.Read
+		if isListRequest(method, requestURL) {
+			timeout = c.deadlines.List
+		}
+	}
+
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// isListRequest reports whether a read request lists a collection: a GET
+// whose path doesn't end with a resource identifier.
+func isListRequest(method, requestURL string) bool {
+	if method != http.MethodGet {
+		return false
+	}
+
+	path := requestURL
+	if u, err := url.Parse(requestURL); err == nil {
+		path = u.Path
+	}
+
+	return !strings.HasSuffix(routeTemplate(strings.TrimRight(path, "/")), "{id}")
+}
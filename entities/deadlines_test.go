@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDefaultDeadlines(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	deadlines := DefaultDeadlines{Read: 20 * time.Millisecond, List: 30 * time.Millisecond}
+
+	entity, err := New(srv.URL, WithDefaultDeadlines(deadlines), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+	assert.Equal(t, deadlines, entity.GetEntityHTTPClient().deadlines, "deadlines survive WithHTTPClient")
+
+	entity.SetHTTPClient(srv.Client())
+	assert.Equal(t, deadlines, entity.GetEntityHTTPClient().deadlines, "deadlines survive SetHTTPClient")
+
+	start := time.Now()
+	_, err = entity.Organizations.GetOrganization(context.Background(), "org-1")
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	start = time.Now()
+	_, err = entity.Organizations.ListOrganizations(context.Background(), nil)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestWithDefaultDeadlineClasses(t *testing.T) {
+	c := &HTTPClient{clientSettings: &clientSettings{deadlines: DefaultDeadlines{Read: time.Second, Write: 2 * time.Second, List: 3 * time.Second}}}
+
+	tests := []struct {
+		method string
+		url    string
+		want   time.Duration
+	}{
+		{http.MethodGet, "http://localhost/v1/organizations/0190b0d5-56f0-7a7a-b1a2-5a0a3c1d9e4f", time.Second},
+		{http.MethodGet, "http://localhost/v1/organizations?limit=10", 3 * time.Second},
+		{http.MethodGet, "http://localhost/v1/organizations/org-1/ledgers/ledger-1/accounts/alias/@treasury", time.Second},
+		{http.MethodHead, "http://localhost/v1/organizations/metrics/count", time.Second},
+		{http.MethodPost, "http://localhost/v1/organizations", 2 * time.Second},
+		{http.MethodDelete, "http://localhost/v1/organizations/org-1", 2 * time.Second},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.method+" "+tc.url, func(t *testing.T) {
+			ctx, cancel := c.withDefaultDeadline(context.Background(), tc.method, tc.url)
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			assert.InDelta(t, tc.want.Seconds(), time.Until(deadline).Seconds(), 0.5)
+		})
+	}
+
+	// the caller's deadline wins
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelParent()
+
+	ctx, cancel := c.withDefaultDeadline(parent, http.MethodGet, "http://localhost/v1/organizations")
+	defer cancel()
+
+	deadline, _ := ctx.Deadline()
+	assert.Greater(t, time.Until(deadline), time.Minute)
+}
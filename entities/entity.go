@@ -41,7 +41,20 @@ type Entity struct {
 	// Observability provider for tracing, metrics, and logging
 	observability observability.Provider
 
-	// Service interfaces for different resource types
+	// services selects the services to initialize; zero means all of them
+	services ServiceSet
+
+	// errorLog and activityLog record requests, see WithErrorLog and WithActivityLog
+	errorLog    *ErrorLog
+	activityLog *ActivityLog
+
+	// connectionTrace records connection timings, see WithConnectionTrace
+	connectionTrace *ConnectionTrace
+
+	// shadow duplicates read requests to a second environment, see WithShadowTarget
+	shadow *Shadow
+
+	// Service interfaces for different resource types; nil when left out by WithServices
 	Accounts          AccountsService
 	AccountTypes      AccountTypesService
 	Assets            AssetsService
@@ -55,6 +68,9 @@ type Entity struct {
 	Segments          SegmentsService
 	Transactions      TransactionsService
 	TransactionRoutes TransactionRoutesService
+
+	// AccountTypeCache caches the account type catalog of each ledger on top of AccountTypes
+	AccountTypeCache *AccountTypeCatalogCache
 }
 
 // NewEntity creates a new Entity instance with the provided client configuration.
@@ -185,6 +201,13 @@ func NewEntityWithConfig(config Config, options ...Option) (*Entity, error) {
 	// Create a new entity with the provided configuration
 	httpClient := NewHTTPClient(config.GetHTTPClient(), authToken, config.GetObservabilityProvider())
 
+	if pluginAuth.Enabled {
+		// Renew expired tokens from the plugin auth service
+		httpClient.tokens = newTokenSource(authToken, func(ctx context.Context) (string, error) {
+			return auth.GetTokenFromAccessManager(ctx, pluginAuth, config.GetHTTPClient())
+		})
+	}
+
 	entity := &Entity{
 		httpClient:    httpClient,
 		baseURLs:      config.GetBaseURLs(),
@@ -206,54 +229,100 @@ func NewEntityWithConfig(config Config, options ...Option) (*Entity, error) {
 
 // initServices initializes the service interfaces for the entity.
 func (e *Entity) initServices() {
-	// Create the service interfaces
-	e.Transactions = NewTransactionsEntity(e.httpClient.client, e.httpClient.authToken, e.baseURLs)
-	e.Accounts = NewAccountsEntity(e.httpClient.client, e.httpClient.authToken, e.baseURLs)
-	e.AccountTypes = NewAccountTypesEntity(e.httpClient.client, e.httpClient.authToken, e.baseURLs)
-	e.Assets = NewAssetsEntity(e.httpClient.client, e.httpClient.authToken, e.baseURLs)
-	e.AssetRates = NewAssetRatesEntity(e.httpClient.client, e.httpClient.authToken, e.baseURLs)
-	e.Balances = NewBalancesEntity(e.httpClient.client, e.httpClient.authToken, e.baseURLs)
-	e.Ledgers = NewLedgersEntity(e.httpClient.client, e.httpClient.authToken, e.baseURLs)
-	e.Operations = NewOperationsEntity(e.httpClient.client, e.httpClient.authToken, e.baseURLs)
-	e.OperationRoutes = NewOperationRoutesEntity(e.httpClient.client, e.httpClient.authToken, e.baseURLs)
-	e.Organizations = NewOrganizationsEntity(e.httpClient.client, e.httpClient.authToken, e.baseURLs)
-	e.Portfolios = NewPortfoliosEntity(e.httpClient.client, e.httpClient.authToken, e.baseURLs)
-	e.Segments = NewSegmentsEntity(e.httpClient.client, e.httpClient.authToken, e.baseURLs)
-	e.TransactionRoutes = NewTransactionRoutesEntity(e.httpClient.client, e.httpClient.authToken, e.baseURLs)
-
-	// Propagate the entity-level tenant ID to each service entity's HTTP client.
-	// Each NewXxxEntity constructor creates a fresh HTTPClient with tenantID="",
-	// so we must copy the tenant ID from the parent entity after construction.
-	e.propagateTenantID()
+	// Create the selected service interfaces
+	services := e.Services()
+	client, token := e.servicesHTTPClient(), e.httpClient.authToken
+
+	if services.Has(ServiceTransactions) {
+		e.Transactions = NewTransactionsEntity(client, token, e.baseURLs)
+	}
+
+	if services.Has(ServiceAccounts) {
+		e.Accounts = NewAccountsEntity(client, token, e.baseURLs)
+	}
+
+	if services.Has(ServiceAccountTypes) {
+		e.AccountTypes = NewAccountTypesEntity(client, token, e.baseURLs)
+		e.AccountTypeCache = NewAccountTypeCatalogCache(e.AccountTypes, DefaultAccountTypeCatalogTTL)
+	}
+
+	if services.Has(ServiceAssets) {
+		e.Assets = NewAssetsEntity(client, token, e.baseURLs)
+	}
+
+	if services.Has(ServiceAssetRates) {
+		e.AssetRates = NewAssetRatesEntity(client, token, e.baseURLs)
+	}
+
+	if services.Has(ServiceBalances) {
+		e.Balances = NewBalancesEntity(client, token, e.baseURLs)
+	}
+
+	if services.Has(ServiceLedgers) {
+		e.Ledgers = NewLedgersEntity(client, token, e.baseURLs)
+	}
+
+	if services.Has(ServiceOperations) {
+		e.Operations = NewOperationsEntity(client, token, e.baseURLs)
+	}
+
+	if services.Has(ServiceOperationRoutes) {
+		e.OperationRoutes = NewOperationRoutesEntity(client, token, e.baseURLs)
+	}
+
+	if services.Has(ServiceOrganizations) {
+		e.Organizations = NewOrganizationsEntity(client, token, e.baseURLs)
+	}
+
+	if services.Has(ServicePortfolios) {
+		e.Portfolios = NewPortfoliosEntity(client, token, e.baseURLs)
+	}
+
+	if services.Has(ServiceSegments) {
+		e.Segments = NewSegmentsEntity(client, token, e.baseURLs)
+	}
+
+	if services.Has(ServiceTransactionRoutes) {
+		e.TransactionRoutes = NewTransactionRoutesEntity(client, token, e.baseURLs)
+	}
+
+	e.shareSettings()
 }
 
-// tenantSetter is implemented by service entities that can receive a tenant ID.
-// This decouples propagateTenantID from knowing every concrete service type.
-type tenantSetter interface {
-	setDefaultTenantID(tenantID string)
+// settingsUser is implemented by the service entities, which share the
+// settings of the HTTP client of their Entity.
+type settingsUser interface {
+	useSettings(settings *clientSettings)
 }
 
-// propagateTenantID copies the entity-level tenant ID to all service entity HTTP clients.
-// It iterates over service fields and calls the tenantSetter interface rather than
-// hard-coding each concrete type, so adding new services cannot silently break propagation.
-func (e *Entity) propagateTenantID() {
-	tid := e.httpClient.tenantID
-	if tid == "" {
-		return
+// shareSettings makes the service entities share the settings of the HTTP
+// client of the Entity. Each NewXxxEntity constructor creates an HTTPClient
+// with settings of its own, replaced here so that the settings made on the
+// Entity, before or after initServices, apply to every service.
+func (e *Entity) shareSettings() {
+	if e.httpClient.assetFreezes == nil {
+		e.httpClient.assetFreezes = NewAssetFreezes()
 	}
 
-	services := []any{
+	if e.httpClient.balanceCache != nil {
+		e.httpClient.balanceCache.service = e.Balances
+	}
+
+	for _, svc := range e.serviceList() {
+		if s, ok := svc.(settingsUser); ok {
+			s.useSettings(e.httpClient.clientSettings)
+		}
+	}
+}
+
+// serviceList returns the service fields of the entity, for sharing settings with them.
+func (e *Entity) serviceList() []any {
+	return []any{
 		e.Accounts, e.AccountTypes, e.Assets, e.AssetRates,
 		e.Balances, e.Ledgers, e.Operations, e.OperationRoutes,
 		e.Organizations, e.Portfolios, e.Segments,
 		e.Transactions, e.TransactionRoutes,
 	}
-
-	for _, svc := range services {
-		if ts, ok := svc.(tenantSetter); ok {
-			ts.setDefaultTenantID(tid)
-		}
-	}
 }
 
 // InitServices initializes the service interfaces for the entity.
@@ -290,7 +359,8 @@ func (e *Entity) GetObservabilityProvider() observability.Provider {
 
 // SetHTTPClient sets the HTTP client for the entity.
 // This allows for replacing the HTTP client after the entity is created.
-// The tenant ID configured on the entity is preserved across the replacement.
+// The settings configured on the entity, such as its tenant ID, are preserved
+// across the replacement.
 //
 // Parameters:
 //   - client: The HTTP client to use for API requests.
@@ -299,12 +369,12 @@ func (e *Entity) SetHTTPClient(client *http.Client) {
 		return
 	}
 
-	// Preserve tenant ID across HTTP client replacement
-	savedTenantID := e.httpClient.tenantID
+	// Keep the settings of the entity across HTTP client replacement
+	settings := e.httpClient.clientSettings
 
 	// Create a new HTTP client with the same auth token and observability
 	e.httpClient = NewHTTPClient(client, e.httpClient.authToken, e.observability)
-	e.httpClient.tenantID = savedTenantID
+	e.httpClient.clientSettings = settings
 
 	// Re-initialize services with the new HTTP client
 	e.initServices()
@@ -319,6 +389,10 @@ func (e *Entity) SetAuthToken(token string) {
 	if token != "" {
 		// Set the token directly on the HTTP client
 		e.httpClient.authToken = token
+
+		if e.httpClient.tokens != nil {
+			e.httpClient.tokens.set(token)
+		}
 	}
 }
 
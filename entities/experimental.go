@@ -0,0 +1,100 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// experimentalFeatures describes the known experimental features, enabled
+// with WithExperimental. Their endpoints are not stable on the Midaz side yet:
+// their paths, parameters or responses may change in a minor release. An
+// endpoint is only gated when it is added to the SDK, never afterwards, so
+// that upgrading doesn't make working calls fail. No endpoint is experimental
+// at the moment.
+var experimentalFeatures = map[string]string{}
+
+// ExperimentalFeatures returns the names of the known experimental features, sorted.
+func ExperimentalFeatures() []string {
+	return slices.Sorted(maps.Keys(experimentalFeatures))
+}
+
+// ErrExperimentalDisabled is the sentinel matched by errors.Is for an ExperimentalError.
+var ErrExperimentalDisabled = errors.New("experimental feature not enabled")
+
+// ExperimentalError is returned when a call uses an experimental feature the
+// client didn't opt into. The call is rejected before any request is sent, so
+// that code doesn't come to depend on an unstable API by accident.
+//
+// Example:
+//
+//	var experimentalErr *entities.ExperimentalError
+//	if errors.As(err, &experimentalErr) {
+//	    log.Printf("enable the %s experimental feature first", experimentalErr.Feature)
+//	}
+type ExperimentalError struct {
+	// Feature is the experimental feature the call belongs to
+	Feature string
+
+	// Operation is the rejected operation
+	Operation string
+}
+
+// Error implements the error interface.
+func (e *ExperimentalError) Error() string {
+	return fmt.Sprintf("%s: %s requires %q, enable it with WithExperimental(%q)",
+		ErrExperimentalDisabled, e.Operation, e.Feature, e.Feature)
+}
+
+// Is reports whether target is ErrExperimentalDisabled.
+func (*ExperimentalError) Is(target error) bool {
+	return target == ErrExperimentalDisabled
+}
+
+// WithExperimental returns an Option that enables experimental features on
+// every service of the Entity. Calls belonging to a feature not enabled fail
+// with an ExperimentalError. Unknown feature names are rejected.
+func WithExperimental(features ...string) Option {
+	return func(e *Entity) error {
+		return e.httpClient.EnableExperimental(features...)
+	}
+}
+
+// EnableExperimental enables experimental features on the HTTP client. It
+// fails, enabling none, when a name is not a known feature.
+func (c *HTTPClient) EnableExperimental(features ...string) error {
+	for _, feature := range features {
+		if _, ok := experimentalFeatures[feature]; !ok {
+			return fmt.Errorf("unknown experimental feature %q, known features: %v", feature, ExperimentalFeatures())
+		}
+	}
+
+	if len(features) == 0 {
+		return nil
+	}
+
+	if c.experimental == nil {
+		c.experimental = map[string]bool{}
+	}
+
+	for _, feature := range features {
+		c.experimental[feature] = true
+	}
+
+	return nil
+}
+
+// IsExperimentalEnabled reports whether an experimental feature is enabled on the HTTP client.
+func (c *HTTPClient) IsExperimentalEnabled(feature string) bool {
+	return c.experimental[feature]
+}
+
+// checkExperimental returns an ExperimentalError when feature is not enabled.
+func (c *HTTPClient) checkExperimental(operation, feature string) error {
+	if c.experimental[feature] {
+		return nil
+	}
+
+	return &ExperimentalError{Feature: feature, Operation: operation}
+}
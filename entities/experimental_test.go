@@ -0,0 +1,80 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// registerExperimental registers an experimental feature for the duration of the test
+func registerExperimental(t *testing.T, feature string) {
+	t.Helper()
+
+	experimentalFeatures[feature] = "test feature"
+
+	t.Cleanup(func() { delete(experimentalFeatures, feature) })
+}
+
+func TestWithExperimental(t *testing.T) {
+	registerExperimental(t, "test-feature")
+
+	disabled, err := New("http://localhost")
+	require.NoError(t, err)
+
+	err = disabled.GetEntityHTTPClient().checkExperimental("TestOperation", "test-feature")
+	require.ErrorIs(t, err, ErrExperimentalDisabled)
+
+	var experimentalErr *ExperimentalError
+	require.True(t, errors.As(err, &experimentalErr))
+	assert.Equal(t, "test-feature", experimentalErr.Feature)
+	assert.Equal(t, "TestOperation", experimentalErr.Operation)
+	assert.Contains(t, err.Error(), `WithExperimental("test-feature")`)
+
+	enabled, err := New("http://localhost", WithExperimental("test-feature"))
+	require.NoError(t, err)
+	assert.True(t, enabled.GetEntityHTTPClient().IsExperimentalEnabled("test-feature"))
+
+	enabled.SetHTTPClient(http.DefaultClient)
+	assert.True(t, enabled.GetEntityHTTPClient().IsExperimentalEnabled("test-feature"),
+		"experimental features survive SetHTTPClient")
+	assert.NoError(t, enabled.GetEntityHTTPClient().checkExperimental("TestOperation", "test-feature"))
+}
+
+func TestWithExperimental_UnknownFeature(t *testing.T) {
+	registerExperimental(t, "test-feature")
+
+	_, err := New("http://localhost", WithExperimental("account-types-v2"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown experimental feature "account-types-v2"`)
+
+	c := NewHTTPClient(http.DefaultClient, "", nil)
+	require.Error(t, c.EnableExperimental("test-feature", "nope"))
+	assert.False(t, c.IsExperimentalEnabled("test-feature"), "a failed call enables nothing")
+}
+
+func TestExperimentalFeatures(t *testing.T) {
+	assert.Empty(t, ExperimentalFeatures(), "no endpoint is experimental at the moment")
+
+	registerExperimental(t, "test-feature")
+	assert.Equal(t, []string{"test-feature"}, ExperimentalFeatures())
+}
+
+func TestGetAccountTypesMetricsCount_NotGated(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"accountsCount":3}`))
+	}))
+	defer srv.Close()
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	metrics, err := entity.AccountTypes.GetAccountTypesMetricsCount(context.Background(), "org-1", "ledger-1")
+	require.NoError(t, err, "a method of an earlier release must not require an opt-in")
+	assert.False(t, metrics.IsEmpty())
+}
@@ -0,0 +1,143 @@
+package entities
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// FindOrCreate returns the entity found by find or, when find fails with a
+// not-found error, the entity created by create. created reports whether this
+// call created the entity.
+//
+// Provisioning code running concurrently, or rerun after a partial failure,
+// can race another caller to create the same entity. When create fails with a
+// conflict (409), the entity exists by now, so FindOrCreate treats the
+// conflict as success and returns the entity fetched with find.
+//
+// Example:
+//
+//	asset, created, err := entities.FindOrCreate(ctx,
+//	    func(ctx context.Context) (*models.Asset, error) {
+//	        return client.Entity.Assets.FindByCode(ctx, orgID, ledgerID, "USD")
+//	    },
+//	    func(ctx context.Context) (*models.Asset, error) {
+//	        return client.Entity.Assets.CreateAsset(ctx, orgID, ledgerID, models.NewCreateAssetInput("US Dollar", "USD"))
+//	    },
+//	)
+func FindOrCreate[T any](ctx context.Context, find, create func(ctx context.Context) (*T, error)) (entity *T, created bool, err error) {
+	entity, err = find(ctx)
+	if err == nil {
+		return entity, false, nil
+	}
+
+	if !isErrorCategory(err, errors.CategoryNotFound, http.StatusNotFound) {
+		return nil, false, err
+	}
+
+	entity, err = create(ctx)
+	if err == nil {
+		return entity, true, nil
+	}
+
+	if !isErrorCategory(err, errors.CategoryConflict, http.StatusConflict) {
+		return nil, false, err
+	}
+
+	entity, findErr := find(ctx)
+	if findErr != nil {
+		return nil, false, fmt.Errorf("entity created concurrently but not found: %w", findErr)
+	}
+
+	return entity, false, nil
+}
+
+// isErrorCategory reports whether err is an SDK error of the given category
+// or HTTP status. Unlike errors.IsNotFoundError and errors.IsConflictError,
+// it never matches errors the SDK didn't classify.
+func isErrorCategory(err error, category errors.ErrorCategory, status int) bool {
+	var sdkErr *errors.Error
+	if !stderrors.As(err, &sdkErr) {
+		return false
+	}
+
+	return sdkErr.Category == category || sdkErr.StatusCode == status
+}
+
+// FindOrCreateOrganization returns the organization with the legal document
+// of input, creating it from input if there is none. See FindOrCreate.
+func (e *Entity) FindOrCreateOrganization(ctx context.Context, input *models.CreateOrganizationInput) (*models.Organization, bool, error) {
+	const operation = "FindOrCreateOrganization"
+
+	if e.Organizations == nil {
+		return nil, false, errors.NewInternalError(operation, fmt.Errorf("organizations service not initialized"))
+	}
+
+	if input == nil {
+		return nil, false, errors.NewMissingParameterError(operation, "input")
+	}
+
+	return FindOrCreate(ctx,
+		func(ctx context.Context) (*models.Organization, error) {
+			return e.Organizations.FindByLegalDocument(ctx, input.LegalDocument)
+		},
+		func(ctx context.Context) (*models.Organization, error) {
+			return e.Organizations.CreateOrganization(ctx, input)
+		},
+	)
+}
+
+// FindOrCreateAsset returns the asset of the ledger with the code of input,
+// creating it from input if there is none. See FindOrCreate.
+func (e *Entity) FindOrCreateAsset(ctx context.Context, organizationID, ledgerID string, input *models.CreateAssetInput) (*models.Asset, bool, error) {
+	const operation = "FindOrCreateAsset"
+
+	if e.Assets == nil {
+		return nil, false, errors.NewInternalError(operation, fmt.Errorf("assets service not initialized"))
+	}
+
+	if input == nil {
+		return nil, false, errors.NewMissingParameterError(operation, "input")
+	}
+
+	return FindOrCreate(ctx,
+		func(ctx context.Context) (*models.Asset, error) {
+			return e.Assets.FindByCode(ctx, organizationID, ledgerID, input.Code)
+		},
+		func(ctx context.Context) (*models.Asset, error) {
+			return e.Assets.CreateAsset(ctx, organizationID, ledgerID, input)
+		},
+	)
+}
+
+// FindOrCreateAccount returns the account of the ledger with the alias of
+// input, creating it from input if there is none. input must have an alias.
+// See FindOrCreate.
+func (e *Entity) FindOrCreateAccount(ctx context.Context, organizationID, ledgerID string, input *models.CreateAccountInput) (*models.Account, bool, error) {
+	const operation = "FindOrCreateAccount"
+
+	if e.Accounts == nil {
+		return nil, false, errors.NewInternalError(operation, fmt.Errorf("accounts service not initialized"))
+	}
+
+	if input == nil {
+		return nil, false, errors.NewMissingParameterError(operation, "input")
+	}
+
+	if input.Alias == nil || *input.Alias == "" {
+		return nil, false, errors.NewMissingParameterError(operation, "input.Alias")
+	}
+
+	return FindOrCreate(ctx,
+		func(ctx context.Context) (*models.Account, error) {
+			return e.Accounts.FindByAlias(ctx, organizationID, ledgerID, *input.Alias)
+		},
+		func(ctx context.Context) (*models.Account, error) {
+			return e.Accounts.CreateAccount(ctx, organizationID, ledgerID, input)
+		},
+	)
+}
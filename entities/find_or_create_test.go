@@ -0,0 +1,197 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// servePages serves items as an offset-paginated list.
+func servePages[T any](t *testing.T, items []T, requests *[]*http.Request) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r)
+
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))   //nolint:errcheck // test server
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset")) //nolint:errcheck // test server
+
+		end := min(offset+limit, len(items))
+		start := min(offset, end)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.ListResponse[T]{ //nolint:errcheck // test server
+			Items:      items[start:end],
+			Pagination: models.Pagination{Limit: limit, Offset: offset, Total: len(items)},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestOrganizationsEntity_FindByLegalDocument(t *testing.T) {
+	orgs := make([]models.Organization, models.MaxLimit+1)
+	for i := range orgs {
+		orgs[i] = models.Organization{ID: "org-" + strconv.Itoa(i), LegalDocument: "doc-" + strconv.Itoa(i)}
+	}
+
+	var requests []*http.Request
+
+	server := servePages(t, orgs, &requests)
+	service := NewOrganizationsEntity(server.Client(), "token", map[string]string{"onboarding": server.URL})
+
+	org, err := service.FindByLegalDocument(context.Background(), "doc-"+strconv.Itoa(models.MaxLimit))
+	require.NoError(t, err)
+	assert.Equal(t, "org-"+strconv.Itoa(models.MaxLimit), org.ID)
+	assert.Len(t, requests, 2, "the organization is on the second page")
+
+	_, err = service.FindByLegalDocument(context.Background(), "missing")
+	assert.True(t, sdkerrors.IsNotFoundError(err))
+
+	_, err = service.FindByLegalDocument(context.Background(), "")
+	assert.Error(t, err)
+}
+
+func TestAssetsEntity_FindByCode(t *testing.T) {
+	var requests []*http.Request
+
+	server := servePages(t, []models.Asset{{ID: "asset-usd", Code: "USD"}, {ID: "asset-eur", Code: "EUR"}}, &requests)
+	service := NewAssetsEntity(server.Client(), "token", map[string]string{"onboarding": server.URL})
+
+	asset, err := service.FindByCode(context.Background(), "org-1", "ledger-1", "eur")
+	require.NoError(t, err)
+	assert.Equal(t, "asset-eur", asset.ID)
+	assert.Equal(t, "/organizations/org-1/ledgers/ledger-1/assets", requests[0].URL.Path)
+
+	_, err = service.FindByCode(context.Background(), "org-1", "ledger-1", "BRL")
+	assert.True(t, sdkerrors.IsNotFoundError(err))
+}
+
+func TestAccountsEntity_FindByAlias(t *testing.T) {
+	prefix, exact := "@customer", "@customer_1"
+
+	var requests []*http.Request
+
+	server := servePages(t, []models.Account{{ID: "acc-prefix", Alias: &prefix}, {ID: "acc-exact", Alias: &exact}}, &requests)
+	service := NewAccountsEntity(server.Client(), "token", map[string]string{"onboarding": server.URL})
+
+	account, err := service.FindByAlias(context.Background(), "org-1", "ledger-1", "@customer_1")
+	require.NoError(t, err)
+	assert.Equal(t, "acc-exact", account.ID)
+	assert.Equal(t, "@customer_1", requests[0].URL.Query().Get("alias"))
+
+	_, err = service.FindByAlias(context.Background(), "org-1", "ledger-1", "@customer_2")
+	assert.True(t, sdkerrors.IsNotFoundError(err))
+}
+
+func TestFindOrCreate(t *testing.T) {
+	notFound := sdkerrors.NewNotFoundError("Find", "asset", "USD", nil)
+	conflict := sdkerrors.NewConflictError("Create", "asset", "USD", nil)
+	existing := &models.Asset{ID: "asset-existing"}
+
+	tests := []struct {
+		name        string
+		findErrs    []error
+		createErr   error
+		wantID      string
+		wantCreated bool
+		wantErr     bool
+		wantCreates int
+	}{
+		{name: "found", findErrs: []error{nil}, wantID: "asset-existing"},
+		{name: "created", findErrs: []error{notFound}, wantID: "asset-new", wantCreated: true, wantCreates: 1},
+		{name: "conflict fetches the concurrent entity", findErrs: []error{notFound, nil}, createErr: conflict, wantID: "asset-existing", wantCreates: 1},
+		{name: "conflict without entity", findErrs: []error{notFound, notFound}, createErr: conflict, wantErr: true, wantCreates: 1},
+		{name: "find failure", findErrs: []error{errors.New("connection refused")}, wantErr: true},
+		{name: "create failure", findErrs: []error{notFound}, createErr: errors.New("invalid input"), wantErr: true, wantCreates: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			finds, creates := 0, 0
+
+			find := func(context.Context) (*models.Asset, error) {
+				err := tc.findErrs[finds]
+				finds++
+
+				if err != nil {
+					return nil, err
+				}
+
+				return existing, nil
+			}
+
+			create := func(context.Context) (*models.Asset, error) {
+				creates++
+
+				if tc.createErr != nil {
+					return nil, tc.createErr
+				}
+
+				return &models.Asset{ID: "asset-new"}, nil
+			}
+
+			asset, created, err := FindOrCreate(context.Background(), find, create)
+			assert.Equal(t, tc.wantCreates, creates)
+			assert.Equal(t, len(tc.findErrs), finds)
+
+			if tc.wantErr {
+				require.Error(t, err)
+				assert.Nil(t, asset)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantID, asset.ID)
+			assert.Equal(t, tc.wantCreated, created)
+		})
+	}
+}
+
+type fakeFindAccountsService struct {
+	AccountsService
+
+	accounts map[string]*models.Account
+}
+
+func (f *fakeFindAccountsService) FindByAlias(_ context.Context, _, _, alias string) (*models.Account, error) {
+	if a, ok := f.accounts[alias]; ok {
+		return a, nil
+	}
+
+	return nil, sdkerrors.NewNotFoundError("FindByAlias", "account", alias, nil)
+}
+
+func (f *fakeFindAccountsService) CreateAccount(_ context.Context, _, _ string, input *models.CreateAccountInput) (*models.Account, error) {
+	// Another provisioner created the account first
+	f.accounts[*input.Alias] = &models.Account{ID: "acc-concurrent", Alias: input.Alias}
+
+	return nil, sdkerrors.NewConflictError("CreateAccount", "account", *input.Alias, nil)
+}
+
+func TestEntity_FindOrCreateAccount(t *testing.T) {
+	e := &Entity{Accounts: &fakeFindAccountsService{accounts: map[string]*models.Account{}}}
+
+	account, created, err := e.FindOrCreateAccount(context.Background(), "org-1", "ledger-1",
+		models.NewCreateAccountInput("Customer", "USD", "deposit").WithAlias("@customer_1"))
+	require.NoError(t, err)
+	assert.Equal(t, "acc-concurrent", account.ID)
+	assert.False(t, created)
+
+	_, _, err = e.FindOrCreateAccount(context.Background(), "org-1", "ledger-1", models.NewCreateAccountInput("Customer", "USD", "deposit"))
+	assert.Error(t, err, "an alias is required to find the account")
+
+	_, _, err = (&Entity{}).FindOrCreateAsset(context.Background(), "org-1", "ledger-1", models.NewCreateAssetInput("US Dollar", "USD"))
+	assert.Error(t, err)
+}
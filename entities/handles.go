@@ -0,0 +1,85 @@
+package entities
+
+import (
+	"context"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+)
+
+// OrganizationHandle is an organization to navigate from, object style:
+//
+//	org := c.Entity.Org(orgID)
+//	ledger := org.Ledger(ledgerID)
+//
+//	account, err := ledger.Accounts.Create(ctx, input)
+//
+// The typed IDs keep an organization ID from being passed as a ledger ID. A
+// handle holds IDs only and makes no call when created.
+type OrganizationHandle struct {
+	// ID is the ID of the organization
+	ID models.OrganizationID
+
+	// Ledgers is the Ledgers service bound to the organization, nil when not enabled
+	Ledgers *ScopedLedgers
+
+	entity *Entity
+}
+
+// Org returns the handle of an organization.
+func (e *Entity) Org(orgID models.OrganizationID) *OrganizationHandle {
+	return &OrganizationHandle{ID: orgID, Ledgers: e.ScopedTo(orgID, "").Ledgers, entity: e}
+}
+
+// Get retrieves the organization.
+func (h *OrganizationHandle) Get(ctx context.Context) (*models.Organization, error) {
+	return h.entity.Organizations.GetOrganization(ctx, h.ID.String())
+}
+
+// Update updates the organization.
+func (h *OrganizationHandle) Update(ctx context.Context, input *models.UpdateOrganizationInput) (*models.Organization, error) {
+	return h.entity.Organizations.UpdateOrganization(ctx, h.ID.String(), input)
+}
+
+// Delete deletes the organization.
+func (h *OrganizationHandle) Delete(ctx context.Context) error {
+	return h.entity.Organizations.DeleteOrganization(ctx, h.ID.String())
+}
+
+// Ledger returns the handle of a ledger of the organization.
+func (h *OrganizationHandle) Ledger(ledgerID models.LedgerID) *LedgerHandle {
+	return &LedgerHandle{ScopedEntity: h.entity.ScopedTo(h.ID, ledgerID), org: h}
+}
+
+// LedgerHandle is a ledger of an organization. It embeds the services of the
+// Entity bound to the ledger, so that ledger.Accounts.List(ctx, nil) lists
+// the accounts of the ledger.
+type LedgerHandle struct {
+	*ScopedEntity
+
+	org *OrganizationHandle
+}
+
+// ID returns the ID of the ledger.
+func (h *LedgerHandle) ID() models.LedgerID {
+	return h.LedgerID
+}
+
+// Org returns the handle of the organization of the ledger.
+func (h *LedgerHandle) Org() *OrganizationHandle {
+	return h.org
+}
+
+// Get retrieves the ledger.
+func (h *LedgerHandle) Get(ctx context.Context) (*models.Ledger, error) {
+	return h.org.Ledgers.Get(ctx, h.LedgerID.String())
+}
+
+// Update updates the ledger.
+func (h *LedgerHandle) Update(ctx context.Context, input *models.UpdateLedgerInput) (*models.Ledger, error) {
+	return h.org.Ledgers.Update(ctx, h.LedgerID.String(), input)
+}
+
+// Delete deletes the ledger.
+func (h *LedgerHandle) Delete(ctx context.Context) error {
+	return h.org.Ledgers.Delete(ctx, h.LedgerID.String())
+}
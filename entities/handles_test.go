@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizationAndLedgerHandles(t *testing.T) {
+	var paths []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"id":"x","items":[]}`))
+	}))
+	defer srv.Close()
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	org := entity.Org("org-1")
+	assert.Equal(t, models.OrganizationID("org-1"), org.ID)
+
+	ledger := org.Ledger("ledger-1")
+	assert.Equal(t, models.LedgerID("ledger-1"), ledger.ID())
+	assert.Equal(t, models.OrganizationID("org-1"), ledger.OrganizationID)
+	assert.Same(t, org, ledger.Org())
+
+	_, err = org.Get(ctx)
+	require.NoError(t, err)
+
+	_, err = org.Ledgers.List(ctx, nil)
+	require.NoError(t, err)
+
+	_, err = ledger.Get(ctx)
+	require.NoError(t, err)
+
+	_, err = ledger.Accounts.Create(ctx, models.NewCreateAccountInput("Cash", "USD", "deposit"))
+	require.NoError(t, err)
+
+	require.NoError(t, ledger.Delete(ctx))
+
+	assert.Equal(t, []string{
+		"GET /organizations/org-1",
+		"GET /organizations/org-1/ledgers",
+		"GET /organizations/org-1/ledgers/ledger-1",
+		"POST /organizations/org-1/ledgers/ledger-1/accounts",
+		"DELETE /organizations/org-1/ledgers/ledger-1",
+	}, paths)
+}
@@ -17,11 +17,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/cost"
 	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/performance"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/quota"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/security"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/usage"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/version"
 	"go.opentelemetry.io/otel/propagation"
 )
@@ -44,11 +48,20 @@ func getUserAgent() string {
 // - Automatic retries with exponential backoff
 // - Optimized performance with connection pooling and JSON handling
 // - Observability with tracing, metrics, and logging
+//
+// The settings of an HTTPClient, such as its tenant ID, read-only mode and
+// hooks, are kept in a clientSettings shared by the HTTP clients of an Entity
+// and its services: changing one of them through the Set methods of any of
+// these clients changes it for all of them, and the settings survive
+// Entity.SetHTTPClient and WithHTTPClient. Like the fields of http.Client,
+// they are not safe for concurrent mutation: set them during client setup,
+// before any concurrent API calls are made.
 type HTTPClient struct {
+	*clientSettings
+
 	client        *http.Client
 	authToken     string
 	userAgent     string
-	tenantID      string
 	debug         bool
 	retryOptions  *retry.Options        // Retry options for the client
 	jsonPool      *performance.JSONPool // Pool for JSON encoding/decoding
@@ -56,6 +69,34 @@ type HTTPClient struct {
 	observability observability.Provider
 }
 
+// clientSettings are the settings shared by the HTTP clients of an Entity and
+// its services, see HTTPClient.
+type clientSettings struct {
+	tenantID         string
+	readOnly         bool                  // reject mutating requests, see WithReadOnly
+	deadlines        DefaultDeadlines      // bound calls without a deadline, see WithDefaultDeadlines
+	payloadLimits    PayloadLimits         // bound request bodies, see WithPayloadLimits
+	metadataCheck    *validation.Validator // check request metadata, see WithMetadataValidator
+	validation       ValidationPolicy      // handle validation failures, see WithValidationPolicy
+	tokens           *tokenSource          // token refreshed for all the services, see WithTokenRefresher
+	clock            *serverClock          // server clock skew
+	capturedHeaders  []string              // response headers captured into ResponseMeta, see WithCapturedHeaders
+	experimental     map[string]bool       // enabled experimental features, see WithExperimental
+	usage            *usage.Recorder       // tallies the calls, see WithUsageRecorder
+	metadataTemplate *MetadataTemplate     // metadata added to created transactions, see WithTransactionMetadata
+	costHook         cost.Hook             // receives the cost of the calls, see WithCostHook
+	retryPolicy      *retry.Options        // overrides retryOptions when set, see WithRetryOptions
+	schemaDrift      *SchemaDriftLog       // checks responses against their models, see WithSchemaDriftCheck
+	unknownFields    *UnknownFieldStore    // sends unknown response fields back on updates, see WithUnknownFieldRoundTrip
+	timeZone         *time.Location        // zone of the response times, see WithTimeZone
+	balanceCache     *BalanceCache         // invalidated by the successful writes, see WithBalanceCache
+	quota            *quota.Enforcer       // checks the calls against tenant budgets, see WithQuotaEnforcer
+	assetFreezes     *AssetFreezes         // frozen assets the created transactions can't move, see FreezeAsset
+	maintenance      *MaintenanceSchedule  // windows during which the calls are held, see WithMaintenanceSchedule
+	retryJournal     RetryJournal          // records the calls given up on after their retries, see WithRetryJournal
+	slowThreshold    time.Duration         // calls over it are logged and their spans marked, see WithSlowCallThreshold
+}
+
 // NewHTTPClient creates a new HTTP client with the provided configuration.
 // The debug flag is set to false by default and can be overridden using the WithDebug option.
 //
@@ -76,14 +117,15 @@ func NewHTTPClient(client *http.Client, authToken string, provider observability
 	}
 
 	return &HTTPClient{
-		client:        client,
-		authToken:     authToken,
-		userAgent:     getUserAgent(),
-		debug:         debug,
-		retryOptions:  retryOptions,
-		jsonPool:      performance.NewJSONPool(),
-		metrics:       metrics,
-		observability: provider,
+		clientSettings: &clientSettings{clock: newServerClock()},
+		client:         client,
+		authToken:      authToken,
+		userAgent:      getUserAgent(),
+		debug:          debug,
+		retryOptions:   retryOptions,
+		jsonPool:       performance.NewJSONPool(),
+		metrics:        metrics,
+		observability:  provider,
 	}
 }
 
@@ -234,17 +276,41 @@ func (c *HTTPClient) injectContextHeaders(ctx context.Context, headers map[strin
 // Returns:
 //   - error: An error if the request failed.
 func (c *HTTPClient) doRequest(ctx context.Context, method, requestURL string, headers map[string]string, body, result any) error {
+	if err := c.checkReadOnly(method, requestURL); err != nil {
+		return err
+	}
+
+	if err := c.checkMaintenance(ctx, method, requestURL); err != nil {
+		return err
+	}
+
+	ctx, cancel := c.withDefaultDeadline(ctx, method, requestURL)
+	defer cancel()
+
 	// Create observability context and span
 	ctx, endSpan := c.setupObservabilityContext(ctx, method, requestURL)
 	defer endSpan()
 
+	if c.unknownFields != nil {
+		body = c.unknownFields.merge(method, requestURL, body)
+	}
+
 	// Build HTTP request
-	req, _, err := c.buildHTTPRequest(ctx, method, requestURL, body)
+	req, bodyBytes, err := c.buildHTTPRequest(ctx, method, requestURL, body)
 	if err != nil {
 		return err
 	}
 
-	// Inject context-based headers (idempotency key, tenant ID)
+	if err := c.checkPayload(method, requestURL, bodyBytes, true); err != nil {
+		return err
+	}
+
+	if err := c.validateRequestMetadata(ctx, bodyBytes); err != nil {
+		return err
+	}
+
+	// Renew a token about to expire, then inject context-based headers (idempotency key, tenant ID)
+	c.renewExpiredToken(ctx)
 	headers = c.injectContextHeaders(ctx, headers)
 
 	// Setup headers
@@ -252,8 +318,7 @@ func (c *HTTPClient) doRequest(ctx context.Context, method, requestURL string, h
 
 	// Inject trace context into request headers for distributed tracing
 	if c.observability != nil && c.observability.IsEnabled() {
-		propagator := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
-		propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+		observability.PropagatorOf(c.observability).Inject(ctx, propagation.HeaderCarrier(req.Header))
 	}
 
 	// Execute request with retry logic and capture elapsed time
@@ -261,6 +326,8 @@ func (c *HTTPClient) doRequest(ctx context.Context, method, requestURL string, h
 	resp, responseBody, err := c.executeRequestWithRetry(ctx, req, method, requestURL)
 	elapsed := time.Since(start)
 
+	c.observeSlowCall(ctx, method, requestURL, elapsed)
+
 	if err != nil {
 		return err
 	}
@@ -276,11 +343,36 @@ func (c *HTTPClient) doRequest(ctx context.Context, method, requestURL string, h
 	c.logResponseDetails(method, requestURL, resp, responseBody)
 
 	// Process response
-	return c.processResponse(result, responseBody)
+	if err := c.processResponse(result, responseBody); err != nil {
+		return err
+	}
+
+	c.checkSchemaDrift(method, requestURL, result, responseBody)
+
+	if c.unknownFields != nil {
+		c.unknownFields.retain(method, requestURL, result, responseBody)
+	}
+
+	if c.balanceCache != nil {
+		c.balanceCache.observe(method, requestURL, responseBody)
+	}
+
+	return nil
 }
 
 // doRawRequest performs an HTTP request using a pre-built byte payload without JSON encoding.
 func (c *HTTPClient) doRawRequest(ctx context.Context, method, requestURL string, headers map[string]string, body []byte, result any) error {
+	if err := c.checkReadOnly(method, requestURL); err != nil {
+		return err
+	}
+
+	if err := c.checkMaintenance(ctx, method, requestURL); err != nil {
+		return err
+	}
+
+	ctx, cancel := c.withDefaultDeadline(ctx, method, requestURL)
+	defer cancel()
+
 	ctx, endSpan := c.setupObservabilityContext(ctx, method, requestURL)
 	defer endSpan()
 
@@ -297,6 +389,10 @@ func (c *HTTPClient) doRawRequest(ctx context.Context, method, requestURL string
 		if strings.TrimSpace(headers["Content-Type"]) == "" {
 			return errors.New("content-type header required for non-empty request body")
 		}
+
+		if err := c.checkPayload(method, requestURL, body, strings.Contains(headers["Content-Type"], "json")); err != nil {
+			return err
+		}
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
@@ -311,21 +407,23 @@ func (c *HTTPClient) doRawRequest(ctx context.Context, method, requestURL string
 		}
 	}
 
-	// Inject context-based headers (idempotency key, tenant ID)
+	// Renew a token about to expire, then inject context-based headers (idempotency key, tenant ID)
+	c.renewExpiredToken(ctx)
 	headers = c.injectContextHeaders(ctx, headers)
 
 	c.setupRequestHeaders(req, headers, len(body) > 0)
 
 	// Inject trace context into request headers for distributed tracing
 	if c.observability != nil && c.observability.IsEnabled() {
-		propagator := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
-		propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+		observability.PropagatorOf(c.observability).Inject(ctx, propagation.HeaderCarrier(req.Header))
 	}
 
 	start := time.Now()
 	resp, responseBody, err := c.executeRequestWithRetry(ctx, req, method, requestURL)
 	elapsed := time.Since(start)
 
+	c.observeSlowCall(ctx, method, requestURL, elapsed)
+
 	if err != nil {
 		return err
 	}
@@ -339,7 +437,17 @@ func (c *HTTPClient) doRawRequest(ctx context.Context, method, requestURL string
 	c.recordRequestMetrics(ctx, method, requestURL, resp, elapsed)
 	c.logResponseDetails(method, requestURL, resp, responseBody)
 
-	return c.processResponse(result, responseBody)
+	if err := c.processResponse(result, responseBody); err != nil {
+		return err
+	}
+
+	c.checkSchemaDrift(method, requestURL, result, responseBody)
+
+	if c.balanceCache != nil {
+		c.balanceCache.observe(method, requestURL, responseBody)
+	}
+
+	return nil
 }
 
 // setupObservabilityContext creates tracing span if observability is enabled
@@ -428,20 +536,56 @@ func (c *HTTPClient) setupRequestHeaders(req *http.Request, headers map[string]s
 	}
 
 	// Authorization header
-	if c.authToken != "" {
-		req.Header.Set("Authorization", c.authToken)
+	if token := c.currentToken(); token != "" {
+		req.Header.Set("Authorization", token)
+	}
+}
+
+// executeRequestWithRetry handles the request execution with retry logic. A
+// request rejected with 401 is sent once more with a renewed token when the
+// client has a token refresher and the request is safe to repeat.
+func (c *HTTPClient) executeRequestWithRetry(ctx context.Context, req *http.Request, method, requestURL string) (resp *http.Response, responseBody []byte, err error) {
+	done, err := c.admitQuota(ctx, req, method, requestURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meter := &callMeter{}
+	start := time.Now()
+
+	defer func() {
+		done(resp, err)
+		c.recordUsage(method, requestURL, resp, err)
+		c.recordCost(ctx, req, method, requestURL, meter, resp, err, time.Since(start))
+		c.recordRetryJournal(ctx, req, meter, err)
+	}()
+
+	resp, responseBody, err = c.executeRequestAttempts(ctx, req, method, requestURL, meter)
+	if err == nil || !c.canRefreshToken(req, err) {
+		return resp, responseBody, err
 	}
+
+	token, refreshErr := c.tokens.renew(ctx, req.Header.Get("Authorization"))
+	if refreshErr != nil {
+		c.debugLog("Failed to refresh auth token after 401: %v", refreshErr)
+		return resp, responseBody, err
+	}
+
+	c.debugLog("Auth token refreshed after 401, retrying %s %s", method, requestURL)
+	req.Header.Set("Authorization", token)
+
+	return c.executeRequestAttempts(ctx, req, method, requestURL, meter)
 }
 
-// executeRequestWithRetry handles the request execution with retry logic
-func (c *HTTPClient) executeRequestWithRetry(ctx context.Context, req *http.Request, method, requestURL string) (*http.Response, []byte, error) {
+// executeRequestAttempts sends the request, retrying it according to the retry options.
+func (c *HTTPClient) executeRequestAttempts(ctx context.Context, req *http.Request, method, requestURL string, meter *callMeter) (*http.Response, []byte, error) {
 	var resp *http.Response
 
 	var responseBody []byte
 
-	retryCtx := retry.WithOptionsContext(ctx, c.retryOptions)
+	retryCtx := retry.WithOptionsContext(ctx, c.retryOpts())
 
-	err := retry.DoWithContext(retryCtx, func() error {
+	attempt := func() error {
 		var err error
 
 		// Reset request body for retry if GetBody is available
@@ -456,12 +600,22 @@ func (c *HTTPClient) executeRequestWithRetry(ctx context.Context, req *http.Requ
 			return fmt.Errorf("invalid request URL: %w", err)
 		}
 
+		sent := time.Now()
+
+		meter.requests++
+		if req.ContentLength > 0 {
+			meter.requestBytes += req.ContentLength
+		}
+
 		resp, err = c.client.Do(req) // #nosec G704 -- request URL validated via security.ValidateOutboundRequest
 		if err != nil {
 			c.debugLogRequestError(method, requestURL, err)
 			return fmt.Errorf("HTTP request failed: %w", err)
 		}
 
+		c.observeServerDate(resp, sent, time.Now())
+		c.captureResponseMeta(ctx, resp)
+
 		// Ensure response body is always closed, even on error paths
 		defer func() {
 			if resp != nil && resp.Body != nil {
@@ -470,6 +624,8 @@ func (c *HTTPClient) executeRequestWithRetry(ctx context.Context, req *http.Requ
 		}()
 
 		responseBody, err = io.ReadAll(resp.Body)
+		meter.responseBytes += int64(len(responseBody))
+
 		if err != nil {
 			return fmt.Errorf("failed to read response body: %w", err)
 		}
@@ -481,6 +637,15 @@ func (c *HTTPClient) executeRequestWithRetry(ctx context.Context, req *http.Requ
 		}
 
 		return nil
+	}
+
+	err := retry.DoWithContext(retryCtx, func() error {
+		err := attempt()
+		if err != nil {
+			meter.failures = append(meter.failures, err.Error())
+		}
+
+		return err
 	})
 
 	return resp, responseBody, err
@@ -544,7 +709,7 @@ func (c *HTTPClient) processResponse(result any, responseBody []byte) error {
 		return nil
 	}
 
-	if err := c.jsonPool.Unmarshal(responseBody, result); err != nil {
+	if err := c.unmarshalResponse(result, responseBody); err != nil {
 		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
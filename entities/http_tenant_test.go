@@ -308,7 +308,7 @@ func TestWithDefaultTenantIDOption(t *testing.T) {
 // TestTenantIDPropagationThroughServiceEntity verifies that a tenant ID set at the
 // Entity level via WithDefaultTenantID is propagated to service entities and arrives
 // as an X-Tenant-ID header when a service method makes an HTTP request.
-// This is the end-to-end test for the initServices -> propagateTenantID flow.
+// This is the end-to-end test for the initServices -> shareSettings flow.
 func TestTenantIDPropagationThroughServiceEntity(t *testing.T) {
 	var receivedHeader string
 
@@ -330,7 +330,7 @@ func TestTenantIDPropagationThroughServiceEntity(t *testing.T) {
 	entity.httpClient.client = srv.Client()
 
 	// Reinitialize services so they pick up the test server's HTTP client.
-	// We must also re-propagate the tenant ID since initServices creates fresh HTTPClients.
+	// The services share the settings of the entity, including its tenant ID.
 	entity.initServices()
 
 	// Call a service method — this exercises the full path:
@@ -344,7 +344,7 @@ func TestTenantIDPropagationThroughServiceEntity(t *testing.T) {
 
 // TestTenantIDPropagationThroughServiceEntityWithUnexportedField verifies tenant ID
 // propagation through a service entity that uses an unexported httpClient field
-// (e.g., accountsEntity), covering the other code path in propagateTenantID.
+// (e.g., accountsEntity), covering the other code path in shareSettings.
 func TestTenantIDPropagationThroughServiceEntityWithUnexportedField(t *testing.T) {
 	var receivedHeader string
 
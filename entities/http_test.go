@@ -34,6 +34,15 @@ func TestHTTPClient(t *testing.T) {
 	assert.NotNil(t, ctx)
 }
 
+func TestParseErrorResponse_DSLSyntax(t *testing.T) {
+	body := []byte(`{"code":"0048","title":"Invalid DSL File Format","message":"The submitted DSL file [{2 4 missing ')' at 'distribute' }] is in an incorrect format."}`)
+
+	err := (&HTTPClient{}).parseErrorResponse(400, body, "req-1")
+
+	assert.True(t, errors.IsDSLSyntaxError(err))
+	assert.Equal(t, []errors.DSLDiagnostic{{Line: 2, Column: 4, Message: "missing ')' at 'distribute'"}}, errors.DSLDiagnostics(err))
+}
+
 // BenchmarkJSONMarshal benchmarks the JSON marshaling performance
 func BenchmarkJSONMarshal(b *testing.B) {
 	// Create a large test object
@@ -32,6 +32,57 @@ func TestHTTPClientTracingIntegration(t *testing.T) {
 	t.Run("HTTPClientErrorHandlingWithTracing", func(t *testing.T) {
 		testHTTPClientErrorHandlingWithTracing(t)
 	})
+
+	t.Run("HTTPClientUsesProviderPropagator", func(t *testing.T) {
+		testHTTPClientUsesProviderPropagator(t)
+	})
+}
+
+// testHTTPClientUsesProviderPropagator verifies that requests carry the B3 headers and baggage the provider is configured with
+func testHTTPClientUsesProviderPropagator(t *testing.T) {
+	t.Helper()
+
+	provider, err := observability.New(context.Background(),
+		observability.WithServiceName("test-service"),
+		observability.WithComponentEnabled(true, false, false),
+		observability.WithFullTracingSampling(),
+		observability.WithW3CAndB3Propagation(),
+		observability.WithBaggageAllowlist("tenant"),
+		observability.WithRegisterGlobally(false),
+	)
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, provider.Shutdown(context.Background()))
+	}()
+
+	var receivedHeaders http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeaders = r.Header.Clone()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	}))
+	defer server.Close()
+
+	httpClient := NewHTTPClient(&http.Client{Timeout: 10 * time.Second}, "Bearer test-token", provider)
+
+	ctx, err := observability.WithBaggageItem(context.Background(), "tenant", "acme")
+	require.NoError(t, err)
+	ctx, err = observability.WithBaggageItem(ctx, "secret", "s3cr3t")
+	require.NoError(t, err)
+
+	ctx, span := provider.Tracer().Start(ctx, "test_http_request")
+	defer span.End()
+
+	var result map[string]string
+
+	require.NoError(t, httpClient.doRequest(ctx, "GET", server.URL+"/test", nil, nil, &result))
+
+	assert.NotEmpty(t, receivedHeaders.Get("traceparent"))
+	assert.Equal(t, span.SpanContext().TraceID().String(), receivedHeaders.Get("X-B3-TraceId"))
+	assert.Equal(t, "tenant=acme", receivedHeaders.Get("baggage"))
 }
 
 // testHTTPClientInjectsTraceHeaders verifies that the HTTP client automatically injects trace headers
@@ -122,8 +122,9 @@ type ledgersEntity struct {
 	baseURLs   map[string]string
 }
 
-func (e *ledgersEntity) setDefaultTenantID(tenantID string) {
-	e.httpClient.SetTenantID(tenantID)
+// useSettings makes the service share the settings of its Entity.
+func (e *ledgersEntity) useSettings(settings *clientSettings) {
+	e.httpClient.clientSettings = settings
 }
 
 // NewLedgersEntity creates a new ledgers entity.
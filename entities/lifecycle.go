@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Close ends the background work of the Entity: it stops duplicating
+// requests to the shadow target and cancels the balance listings of the
+// balance cache, then waits until their goroutines exit or ctx is done.
+// The services can still be called after Close, without that work.
+//
+// Client.Shutdown calls it; call it directly for an Entity created without a
+// client.
+func (e *Entity) Close(ctx context.Context) error {
+	var errs []error
+
+	if e.shadow != nil {
+		if err := e.shadow.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if e.httpClient.balanceCache != nil {
+		if err := e.httpClient.balanceCache.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// waitGroupContext waits for wg until ctx is done.
+func waitGroupContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
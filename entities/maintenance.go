@@ -0,0 +1,297 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+)
+
+// ErrMaintenance is the sentinel matched by errors.Is for a MaintenanceError.
+var ErrMaintenance = errors.New("scheduled maintenance")
+
+// MaintenanceWindow is a period during which the Midaz deployment is under
+// maintenance.
+type MaintenanceWindow struct {
+	// Start and End bound the window, End exclusive
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// Reason describes the maintenance, such as "database upgrade"
+	Reason string `json:"reason,omitempty"`
+}
+
+// Contains reports whether t is within the window.
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// MaintenanceError is returned for a call made during a maintenance window,
+// unless its context waits for the end of windows (see WithMaintenanceWait).
+// The call is rejected before any request is sent.
+//
+// Example:
+//
+//	_, err := entity.Accounts.GetAccount(ctx, orgID, ledgerID, accountID)
+//	var maintenance *entities.MaintenanceError
+//	if errors.As(err, &maintenance) {
+//	    log.Printf("Midaz is under maintenance until %s", maintenance.Window.End)
+//	}
+type MaintenanceError struct {
+	Window MaintenanceWindow
+
+	// Method is the HTTP method of the rejected request
+	Method string
+
+	// URL is the URL of the rejected request
+	URL string
+}
+
+// Error implements the error interface.
+func (e *MaintenanceError) Error() string {
+	msg := fmt.Sprintf("%s until %s: %s %s rejected", ErrMaintenance, e.Window.End.Format(time.RFC3339), e.Method, e.URL)
+	if e.Window.Reason != "" {
+		msg += " (" + e.Window.Reason + ")"
+	}
+
+	return msg
+}
+
+// Is reports whether target is ErrMaintenance.
+func (*MaintenanceError) Is(target error) bool {
+	return target == ErrMaintenance
+}
+
+// MaintenanceSchedule holds the maintenance windows of a Midaz deployment,
+// configured up front or fetched from a status endpoint. It is safe for
+// concurrent use.
+type MaintenanceSchedule struct {
+	mu      sync.RWMutex
+	windows []MaintenanceWindow
+	now     func() time.Time
+}
+
+// NewMaintenanceSchedule returns a schedule of the given windows.
+func NewMaintenanceSchedule(windows ...MaintenanceWindow) *MaintenanceSchedule {
+	s := &MaintenanceSchedule{now: time.Now}
+	s.Set(windows...)
+
+	return s
+}
+
+// Set replaces the windows of the schedule. Windows ending before they start
+// are dropped.
+func (s *MaintenanceSchedule) Set(windows ...MaintenanceWindow) {
+	valid := make([]MaintenanceWindow, 0, len(windows))
+
+	for _, w := range windows {
+		if w.End.After(w.Start) {
+			valid = append(valid, w)
+		}
+	}
+
+	slices.SortFunc(valid, func(x, y MaintenanceWindow) int { return x.Start.Compare(y.Start) })
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.windows = valid
+}
+
+// Windows returns the windows of the schedule, the earliest first.
+func (s *MaintenanceSchedule) Windows() []MaintenanceWindow {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.windows)
+}
+
+// Active returns the window containing t, false when there is none. Of
+// overlapping windows, the one ending last is returned.
+func (s *MaintenanceSchedule) Active(t time.Time) (MaintenanceWindow, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var active MaintenanceWindow
+
+	found := false
+
+	for _, w := range s.windows {
+		if w.Contains(t) && (!found || w.End.After(active.End)) {
+			active, found = w, true
+		}
+	}
+
+	return active, found
+}
+
+// Next returns the first window starting after t, false when there is none.
+func (s *MaintenanceSchedule) Next(t time.Time) (MaintenanceWindow, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, w := range s.windows {
+		if w.Start.After(t) {
+			return w, true
+		}
+	}
+
+	return MaintenanceWindow{}, false
+}
+
+// maintenanceStatus is the body of a status endpoint listing maintenance windows.
+type maintenanceStatus struct {
+	Windows []MaintenanceWindow `json:"maintenanceWindows"`
+}
+
+// Refresh replaces the windows of the schedule with those of a status
+// endpoint, which returns them as JSON, either a list of windows or an object
+// holding them in "maintenanceWindows":
+//
+//	{"maintenanceWindows": [{"start": "2026-03-01T02:00:00Z", "end": "2026-03-01T04:00:00Z", "reason": "database upgrade"}]}
+//
+// The schedule is left unchanged when the windows can't be fetched.
+func (s *MaintenanceSchedule) Refresh(ctx context.Context, client *http.Client, statusURL string) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance status request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch maintenance windows: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch maintenance windows: status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read maintenance windows: %w", err)
+	}
+
+	var windows []MaintenanceWindow
+	if err := json.Unmarshal(body, &windows); err != nil {
+		var status maintenanceStatus
+		if err := json.Unmarshal(body, &status); err != nil {
+			return fmt.Errorf("failed to decode maintenance windows: %w", err)
+		}
+
+		windows = status.Windows
+	}
+
+	s.Set(windows...)
+
+	return nil
+}
+
+// Watch refreshes the schedule from a status endpoint every interval until ctx
+// ends, starting at once. Failures are passed to onError, if set, and keep the
+// windows fetched last. Run it in its own goroutine:
+//
+//	schedule := entities.NewMaintenanceSchedule()
+//	go schedule.Watch(ctx, nil, "https://status.example.com/midaz/maintenance", time.Minute, nil)
+func (s *MaintenanceSchedule) Watch(ctx context.Context, client *http.Client, statusURL string, interval time.Duration, onError func(error)) {
+	defer lifecycle.Track("entities.MaintenanceSchedule.Watch")()
+
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx, client, statusURL); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type contextKeyMaintenanceWait struct{}
+
+// WithMaintenanceWait marks the calls of ctx as deferrable, such as those of
+// batch jobs: during a maintenance window they wait for it to end, or for ctx
+// to end, and then proceed, instead of failing with a MaintenanceError.
+func WithMaintenanceWait(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyMaintenanceWait{}, true)
+}
+
+// maintenanceWaitFromContext reports whether the calls of ctx wait for the end
+// of maintenance windows.
+func maintenanceWaitFromContext(ctx context.Context) bool {
+	wait, _ := ctx.Value(contextKeyMaintenanceWait{}).(bool)
+	return wait
+}
+
+// WithMaintenanceSchedule returns an Option that holds the calls made through
+// the services of the Entity during the windows of schedule: they fail with a
+// MaintenanceError, or wait for the end of the window when their context is
+// marked with WithMaintenanceWait.
+func WithMaintenanceSchedule(schedule *MaintenanceSchedule) Option {
+	return func(e *Entity) error {
+		e.httpClient.maintenance = schedule
+
+		return nil
+	}
+}
+
+// SetMaintenanceSchedule sets the maintenance windows during which the calls
+// of the HTTP client are held; nil stops holding them.
+func (c *HTTPClient) SetMaintenanceSchedule(schedule *MaintenanceSchedule) {
+	c.maintenance = schedule
+}
+
+// checkMaintenance holds a call made during a maintenance window: it returns
+// a MaintenanceError, or waits for the end of the windows when ctx is marked
+// with WithMaintenanceWait.
+func (c *HTTPClient) checkMaintenance(ctx context.Context, method, requestURL string) error {
+	if c.maintenance == nil {
+		return nil
+	}
+
+	for {
+		now := c.maintenance.now()
+
+		window, ok := c.maintenance.Active(now)
+		if !ok {
+			return nil
+		}
+
+		if !maintenanceWaitFromContext(ctx) {
+			return &MaintenanceError{Window: window, Method: method, URL: requestURL}
+		}
+
+		c.debugLog("Maintenance until %s, holding %s %s", window.End.Format(time.RFC3339), method, requestURL)
+
+		timer := time.NewTimer(window.End.Sub(now))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
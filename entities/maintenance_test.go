@@ -0,0 +1,139 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceSchedule(t *testing.T) {
+	base := time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)
+
+	s := NewMaintenanceSchedule(
+		MaintenanceWindow{Start: base.Add(24 * time.Hour), End: base.Add(26 * time.Hour), Reason: "later"},
+		MaintenanceWindow{Start: base, End: base.Add(2 * time.Hour), Reason: "upgrade"},
+		MaintenanceWindow{Start: base.Add(time.Hour), End: base.Add(3 * time.Hour), Reason: "overlap"},
+		MaintenanceWindow{Start: base, End: base.Add(-time.Hour)},
+	)
+
+	require.Len(t, s.Windows(), 3, "windows ending before they start are dropped")
+	assert.Equal(t, "upgrade", s.Windows()[0].Reason)
+
+	w, ok := s.Active(base.Add(30 * time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, "upgrade", w.Reason)
+
+	w, ok = s.Active(base.Add(90 * time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, "overlap", w.Reason, "the window ending last wins")
+
+	_, ok = s.Active(base.Add(3 * time.Hour))
+	assert.False(t, ok, "the end is exclusive")
+
+	w, ok = s.Next(base.Add(3 * time.Hour))
+	require.True(t, ok)
+	assert.Equal(t, "later", w.Reason)
+}
+
+func TestMaintenanceSchedule_Refresh(t *testing.T) {
+	bodies := []string{
+		`{"maintenanceWindows":[{"start":"2026-03-01T02:00:00Z","end":"2026-03-01T04:00:00Z","reason":"upgrade"}]}`,
+		`[{"start":"2026-03-02T02:00:00Z","end":"2026-03-02T04:00:00Z"}]`,
+		`not json`,
+	}
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(bodies[calls.Add(1)-1]))
+	}))
+	defer srv.Close()
+
+	s := NewMaintenanceSchedule()
+
+	require.NoError(t, s.Refresh(context.Background(), srv.Client(), srv.URL))
+	require.Len(t, s.Windows(), 1)
+	assert.Equal(t, "upgrade", s.Windows()[0].Reason)
+
+	require.NoError(t, s.Refresh(context.Background(), srv.Client(), srv.URL))
+	require.Len(t, s.Windows(), 1)
+	assert.Equal(t, 2, s.Windows()[0].Start.Day())
+
+	require.Error(t, s.Refresh(context.Background(), srv.Client(), srv.URL))
+	assert.Len(t, s.Windows(), 1, "the windows are kept on failure")
+}
+
+func TestMaintenanceSchedule_Watch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	tasks := lifecycle.Count("entities.MaintenanceSchedule.Watch")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		NewMaintenanceSchedule().Watch(ctx, srv.Client(), srv.URL, time.Millisecond, nil)
+	}()
+
+	require.Eventually(t, func() bool { return lifecycle.Count("entities.MaintenanceSchedule.Watch") == tasks+1 }, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+
+	assert.Equal(t, tasks, lifecycle.Count("entities.MaintenanceSchedule.Watch"), "cancelling ctx ends the watcher")
+}
+
+func TestWithMaintenanceSchedule(t *testing.T) {
+	t.Setenv("MIDAZ_ENABLE_RETRIES", "false")
+
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	schedule := NewMaintenanceSchedule(MaintenanceWindow{Start: now.Add(-time.Minute), End: now.Add(50 * time.Millisecond), Reason: "upgrade"})
+
+	entity, err := New(srv.URL, WithMaintenanceSchedule(schedule), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	entity.SetHTTPClient(srv.Client())
+
+	_, err = entity.Ledgers.ListLedgers(context.Background(), "org-1", nil)
+	require.ErrorIs(t, err, ErrMaintenance)
+
+	var maintenance *MaintenanceError
+	require.True(t, errors.As(err, &maintenance))
+	assert.Equal(t, "upgrade", maintenance.Window.Reason)
+	assert.Equal(t, http.MethodGet, maintenance.Method)
+	assert.Equal(t, int32(0), requests.Load(), "interactive calls are not sent")
+
+	_, err = entity.Ledgers.ListLedgers(WithMaintenanceWait(context.Background()), "org-1", nil)
+	require.NoError(t, err, "deferrable calls resume after the window")
+	assert.False(t, time.Now().Before(now.Add(50*time.Millisecond)))
+	assert.Equal(t, int32(1), requests.Load())
+
+	schedule.Set(MaintenanceWindow{Start: time.Now().Add(-time.Minute), End: time.Now().Add(time.Hour)})
+
+	ctx, cancel := context.WithTimeout(WithMaintenanceWait(context.Background()), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = entity.Ledgers.ListLedgers(ctx, "org-1", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,120 @@
+package entities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+)
+
+// WithMetadataValidator returns an Option that makes every service of the
+// Entity check each metadata object of a JSON request body with validator,
+// rejecting the requests with invalid metadata with a validation error
+// instead of sending them, or only reporting them in lenient mode (see
+// ValidationPolicy). The error wraps a *validation.FieldErrors naming
+// every offending field. A nil validator disables the check.
+func WithMetadataValidator(validator *validation.Validator) Option {
+	return func(e *Entity) error {
+		e.httpClient.metadataCheck = validator
+
+		return nil
+	}
+}
+
+// SetMetadataValidator sets the validator request body metadata is checked with.
+func (c *HTTPClient) SetMetadataValidator(validator *validation.Validator) {
+	c.metadataCheck = validator
+}
+
+// validateRequestMetadata checks the metadata of a JSON request body with
+// the client's metadata validator, under the validation mode of ctx.
+func (c *HTTPClient) validateRequestMetadata(ctx context.Context, body []byte) error {
+	if c.metadataCheck == nil || len(body) == 0 {
+		return nil
+	}
+
+	return c.validateInput(ctx, "ValidateMetadata", func() error {
+		fieldErrs := validation.NewFieldErrors()
+
+		_ = walkMetadata("", body, func(path string, raw json.RawMessage) error { //nolint:errcheck // visit never fails
+			var metadata map[string]any
+			if json.Unmarshal(raw, &metadata) != nil {
+				return nil
+			}
+
+			for _, fieldErr := range c.metadataCheck.ValidateMetadataFields(path, metadata).GetFieldErrors() {
+				fieldErrs.AddError(fieldErr)
+			}
+
+			return nil
+		})
+
+		if !fieldErrs.HasErrors() {
+			return nil
+		}
+
+		return errors.NewValidationError("ValidateMetadata", "invalid request metadata", fieldErrs)
+	})
+}
+
+// walkMetadata walks a JSON value and calls visit with the path and encoded
+// value of every "metadata" object in it, nested ones included, stopping at
+// the first error visit returns.
+func walkMetadata(path string, raw json.RawMessage, visit func(path string, metadata json.RawMessage) error) error {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil
+	}
+
+	switch raw[0] {
+	case '{':
+		var object map[string]json.RawMessage
+		if json.Unmarshal(raw, &object) != nil {
+			return nil
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(object)) {
+			value := bytes.TrimSpace(object[key])
+			fieldPath := joinFieldPath(path, key)
+
+			if key == "metadata" && len(value) > 0 && value[0] == '{' {
+				if err := visit(fieldPath, value); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err := walkMetadata(fieldPath, value, visit); err != nil {
+				return err
+			}
+		}
+	case '[':
+		var array []json.RawMessage
+		if json.Unmarshal(raw, &array) != nil {
+			return nil
+		}
+
+		for i, value := range array {
+			if err := walkMetadata(path+"["+strconv.Itoa(i)+"]", value, visit); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// joinFieldPath appends a key to a JSON field path.
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,185 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync/atomic"
+	"time"
+)
+
+// MetadataCall describes the transaction a MetadataTemplate is applied to.
+type MetadataCall struct {
+	// Operation is the SDK operation creating the transaction, such as "CreateTransaction"
+	Operation string
+
+	OrganizationID string
+	LedgerID       string
+
+	// Time is when the template was applied, in UTC
+	Time time.Time
+
+	// Sequence numbers the transactions of the template, from 1
+	Sequence uint64
+
+	payload any
+}
+
+// PayloadHash returns the hex SHA-256 of the JSON transaction input, as it
+// was before the template was applied.
+func (c MetadataCall) PayloadHash() (string, error) {
+	body, err := json.Marshal(c.payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal transaction input: %w", err)
+	}
+
+	sum := sha256.Sum256(body)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// MetadataValue computes a metadata value of a transaction.
+type MetadataValue func(call MetadataCall) (any, error)
+
+// metadataField is a key of a MetadataTemplate with its value
+type metadataField struct {
+	key   string
+	value MetadataValue
+}
+
+// MetadataTemplate adds standard metadata to transactions: fixed values, such
+// as an environment or team tag, and values computed for each transaction,
+// such as a timestamp, a sequence number or a hash of the payload.
+//
+// Set on a client with WithTransactionMetadata (client.WithTransactionMetadata
+// at the top level), it applies to every transaction the client creates; set
+// on transaction.BatchOptions, to the transactions of a batch:
+//
+//	tpl := entities.NewMetadataTemplate().
+//	    WithEnvironment("environment", "production").
+//	    WithValue("team", "payments").
+//	    WithTimestamp("sdkCreatedAt").
+//	    WithPayloadHash("payloadHash")
+//
+// Keys already set in the metadata of a transaction are kept unless the
+// template overrides them, see WithOverride. A template is safe for
+// concurrent use once configured.
+type MetadataTemplate struct {
+	fields   []metadataField
+	override bool
+	sequence atomic.Uint64
+	now      func() time.Time
+}
+
+// NewMetadataTemplate returns an empty MetadataTemplate.
+func NewMetadataTemplate() *MetadataTemplate {
+	return &MetadataTemplate{now: time.Now}
+}
+
+// WithValue sets a fixed value.
+func (t *MetadataTemplate) WithValue(key string, value any) *MetadataTemplate {
+	return t.WithComputed(key, func(MetadataCall) (any, error) { return value, nil })
+}
+
+// WithEnvironment tags the transactions with the environment they were
+// created in, such as config.EnvironmentProduction.
+func (t *MetadataTemplate) WithEnvironment(key, environment string) *MetadataTemplate {
+	return t.WithValue(key, environment)
+}
+
+// WithComputed sets a value computed for each transaction. An error from fn
+// fails the creation of the transaction before it is sent.
+func (t *MetadataTemplate) WithComputed(key string, fn MetadataValue) *MetadataTemplate {
+	if key == "" || fn == nil {
+		return t
+	}
+
+	for i, field := range t.fields {
+		if field.key == key {
+			t.fields[i].value = fn
+			return t
+		}
+	}
+
+	t.fields = append(t.fields, metadataField{key: key, value: fn})
+
+	return t
+}
+
+// WithTimestamp sets the time the template was applied, as an RFC 3339 UTC
+// string with nanoseconds.
+func (t *MetadataTemplate) WithTimestamp(key string) *MetadataTemplate {
+	return t.WithComputed(key, func(call MetadataCall) (any, error) {
+		return call.Time.Format(time.RFC3339Nano), nil
+	})
+}
+
+// WithSequence sets the sequence number of the transaction in the template,
+// starting at 1. The sequence is kept in memory: it restarts with the process.
+func (t *MetadataTemplate) WithSequence(key string) *MetadataTemplate {
+	return t.WithComputed(key, func(call MetadataCall) (any, error) {
+		return call.Sequence, nil
+	})
+}
+
+// WithPayloadHash sets the hex SHA-256 of the JSON transaction input, see
+// MetadataCall.PayloadHash.
+func (t *MetadataTemplate) WithPayloadHash(key string) *MetadataTemplate {
+	return t.WithComputed(key, func(call MetadataCall) (any, error) {
+		return call.PayloadHash()
+	})
+}
+
+// WithOverride makes the template replace the values of keys already set in
+// the metadata of a transaction, instead of keeping them.
+func (t *MetadataTemplate) WithOverride(override bool) *MetadataTemplate {
+	t.override = override
+
+	return t
+}
+
+// Apply returns metadata with the values of the template added, leaving
+// metadata unchanged. payload is the transaction input, hashed by
+// WithPayloadHash.
+func (t *MetadataTemplate) Apply(operation, orgID, ledgerID string, payload any, metadata map[string]any) (map[string]any, error) {
+	call := MetadataCall{
+		Operation:      operation,
+		OrganizationID: orgID,
+		LedgerID:       ledgerID,
+		Time:           t.now().UTC(),
+		Sequence:       t.sequence.Add(1),
+		payload:        payload,
+	}
+
+	result := maps.Clone(metadata)
+	if result == nil {
+		result = make(map[string]any, len(t.fields))
+	}
+
+	for _, field := range t.fields {
+		if _, set := result[field.key]; set && !t.override {
+			continue
+		}
+
+		value, err := field.value(call)
+		if err != nil {
+			return nil, fmt.Errorf("metadata template key %q: %w", field.key, err)
+		}
+
+		result[field.key] = value
+	}
+
+	return result, nil
+}
+
+// WithTransactionMetadata returns an Option that applies tpl to the metadata
+// of every transaction created through the Entity.
+func WithTransactionMetadata(tpl *MetadataTemplate) Option {
+	return func(e *Entity) error {
+		e.httpClient.metadataTemplate = tpl
+
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataTemplateApply(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("BRT", -3*60*60))
+
+	tpl := NewMetadataTemplate().
+		WithEnvironment("environment", "production").
+		WithValue("team", "payments").
+		WithTimestamp("sdkCreatedAt").
+		WithSequence("sequence")
+	tpl.now = func() time.Time { return now }
+
+	input := map[string]any{"team": "treasury"}
+
+	metadata, err := tpl.Apply("CreateTransaction", "org-1", "ledger-1", nil, input)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"environment":  "production",
+		"team":         "treasury",
+		"sdkCreatedAt": "2026-03-01T15:00:00Z",
+		"sequence":     uint64(1),
+	}, metadata)
+	assert.Equal(t, map[string]any{"team": "treasury"}, input, "the input metadata must not change")
+
+	metadata, err = tpl.WithOverride(true).Apply("CreateTransaction", "org-1", "ledger-1", nil, input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "payments", metadata["team"])
+	assert.Equal(t, uint64(2), metadata["sequence"])
+}
+
+func TestMetadataTemplateComputed(t *testing.T) {
+	payload := &models.CreateAnnotationInput{Description: "note"}
+
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	tpl := NewMetadataTemplate().
+		WithPayloadHash("payloadHash").
+		WithComputed("route", func(call MetadataCall) (any, error) {
+			return call.Operation + ":" + call.OrganizationID + "/" + call.LedgerID, nil
+		})
+
+	metadata, err := tpl.Apply("CreateAnnotationTransaction", "org-1", "ledger-1", payload, nil)
+	require.NoError(t, err)
+
+	hash, err := MetadataCall{payload: json.RawMessage(body)}.PayloadHash()
+	require.NoError(t, err)
+
+	assert.Equal(t, hash, metadata["payloadHash"])
+	assert.Len(t, hash, 64)
+	assert.Equal(t, "CreateAnnotationTransaction:org-1/ledger-1", metadata["route"])
+
+	failing := NewMetadataTemplate().WithComputed("broken", func(MetadataCall) (any, error) {
+		return nil, errors.New("no value")
+	})
+
+	_, err = failing.Apply("CreateTransaction", "org-1", "ledger-1", nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"broken"`)
+}
+
+func TestWithTransactionMetadata(t *testing.T) {
+	t.Setenv("MIDAZ_ENABLE_RETRIES", "false")
+
+	var sent map[string]any
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Metadata map[string]any `json:"metadata"`
+		}
+
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		sent = body.Metadata
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"tx-1"}`))
+	}))
+	defer srv.Close()
+
+	tpl := NewMetadataTemplate().WithValue("team", "payments").WithSequence("sequence")
+
+	entity, err := New(srv.URL, WithTransactionMetadata(tpl), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	entity.SetHTTPClient(srv.Client())
+
+	ctx := context.Background()
+	input := &models.CreateAnnotationInput{Description: "note", Metadata: map[string]any{"ticket": "OPS-1"}}
+
+	_, err = entity.Transactions.CreateAnnotationTransaction(ctx, "org-1", "ledger-1", input)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"team": "payments", "sequence": float64(1), "ticket": "OPS-1"}, sent)
+	assert.Equal(t, map[string]any{"ticket": "OPS-1"}, input.Metadata, "the caller's input must not change")
+
+	failing := NewMetadataTemplate().WithComputed("broken", func(MetadataCall) (any, error) {
+		return nil, errors.New("no value")
+	})
+
+	entity, err = New(srv.URL, WithTransactionMetadata(failing), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	_, err = entity.Transactions.CreateAnnotationTransaction(ctx, "org-1", "ledger-1", input)
+	require.Error(t, err)
+	assert.True(t, sdkerrors.IsValidationError(err))
+}
@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMetadataValidator(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	validator, err := validation.NewValidator(core.WithBackendMetadataLimits(), core.WithMaxMetadataKeys(2))
+	require.NoError(t, err)
+
+	entity, err := New(srv.URL, WithMetadataValidator(validator), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	entity.SetHTTPClient(srv.Client())
+	assert.Same(t, validator, entity.Transactions.(*transactionsEntity).httpClient.metadataCheck, "the validator reaches the services and survives SetHTTPClient")
+
+	client := entity.GetEntityHTTPClient()
+	ctx := context.Background()
+	url := srv.URL + "/v1/organizations"
+
+	err = client.doRequest(ctx, http.MethodPost, url, nil, map[string]any{
+		"metadata": map[string]any{"tags": []string{"a", "b"}, "amount": 10},
+	}, nil)
+	require.NoError(t, err)
+
+	err = client.doRequest(ctx, http.MethodPost, url, nil, map[string]any{
+		"metadata": map[string]any{"a": 1},
+		"send": map[string]any{"source": map[string]any{"from": []map[string]any{
+			{"metadata": map[string]any{"notes": strings.Repeat("a", core.BackendMaxMetadataValueLength+1)}},
+			{"metadata": map[string]any{"nested": map[string]any{"a": 1}}},
+		}}},
+	}, nil)
+	require.True(t, sdkerrors.IsValidationError(err), "got %v", err)
+
+	var fieldErrs *validation.FieldErrors
+	require.True(t, errors.As(err, &fieldErrs))
+
+	var fields []string
+	for _, fieldErr := range fieldErrs.GetFieldErrors() {
+		fields = append(fields, fieldErr.Field)
+	}
+
+	assert.Equal(t, []string{"send.source.from[0].metadata.notes", "send.source.from[1].metadata.nested"}, fields)
+	assert.Equal(t, int32(1), requests.Load(), "rejected calls don't reach the server")
+}
+
+func TestWalkMetadata(t *testing.T) {
+	body := `{"metadata":{"a":1},"items":[{"metadata":{"b":2}},{"metadata":null}],"other":{"metadata":"x"}}`
+
+	visited := map[string]string{}
+	err := walkMetadata("", json.RawMessage(body), func(path string, metadata json.RawMessage) error {
+		visited[path] = string(metadata)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"metadata":          `{"a":1}`,
+		"items[0].metadata": `{"b":2}`,
+	}, visited)
+}
@@ -105,6 +105,30 @@ func (mr *MockAccountsServiceMockRecorder) GetAccountByAlias(ctx, organizationID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByAlias", reflect.TypeOf((*MockAccountsService)(nil).GetAccountByAlias), ctx, organizationID, ledgerID, alias)
 }
 
+// FindByAlias mocks base method.
+func (m *MockAccountsService) FindByAlias(ctx context.Context, organizationID, ledgerID, alias string) (*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByAlias", ctx, organizationID, ledgerID, alias)
+
+	var ret0 *models.Account
+	if ret[0] != nil {
+		ret0, _ = ret[0].(*models.Account) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// FindByAlias indicates an expected call of FindByAlias.
+func (mr *MockAccountsServiceMockRecorder) FindByAlias(ctx, organizationID, ledgerID, alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByAlias", reflect.TypeOf((*MockAccountsService)(nil).FindByAlias), ctx, organizationID, ledgerID, alias)
+}
+
 // CreateAccount mocks base method.
 func (m *MockAccountsService) CreateAccount(ctx context.Context, organizationID, ledgerID string, input *models.CreateAccountInput) (*models.Account, error) {
 	m.ctrl.T.Helper()
@@ -195,3 +219,99 @@ func (mr *MockAccountsServiceMockRecorder) GetBalance(ctx, organizationID, ledge
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockAccountsService)(nil).GetBalance), ctx, organizationID, ledgerID, accountID)
 }
+
+// GetAccountsMetricsCount mocks base method.
+func (m *MockAccountsService) GetAccountsMetricsCount(ctx context.Context, organizationID, ledgerID string) (*models.MetricsCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountsMetricsCount", ctx, organizationID, ledgerID)
+
+	var ret0 *models.MetricsCount
+	if ret[0] != nil {
+		ret0, _ = ret[0].(*models.MetricsCount) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// GetAccountsMetricsCount indicates an expected call of GetAccountsMetricsCount.
+func (mr *MockAccountsServiceMockRecorder) GetAccountsMetricsCount(ctx, organizationID, ledgerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountsMetricsCount", reflect.TypeOf((*MockAccountsService)(nil).GetAccountsMetricsCount), ctx, organizationID, ledgerID)
+}
+
+// GetExternalAccount mocks base method.
+func (m *MockAccountsService) GetExternalAccount(ctx context.Context, organizationID, ledgerID, assetCode string) (*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalAccount", ctx, organizationID, ledgerID, assetCode)
+
+	var ret0 *models.Account
+	if ret[0] != nil {
+		ret0, _ = ret[0].(*models.Account) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// GetExternalAccount indicates an expected call of GetExternalAccount.
+func (mr *MockAccountsServiceMockRecorder) GetExternalAccount(ctx, organizationID, ledgerID, assetCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalAccount", reflect.TypeOf((*MockAccountsService)(nil).GetExternalAccount), ctx, organizationID, ledgerID, assetCode)
+}
+
+// GetExternalAccountBalance mocks base method.
+func (m *MockAccountsService) GetExternalAccountBalance(ctx context.Context, organizationID, ledgerID, assetCode string) (*models.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalAccountBalance", ctx, organizationID, ledgerID, assetCode)
+
+	var ret0 *models.Balance
+	if ret[0] != nil {
+		ret0, _ = ret[0].(*models.Balance) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// GetExternalAccountBalance indicates an expected call of GetExternalAccountBalance.
+func (mr *MockAccountsServiceMockRecorder) GetExternalAccountBalance(ctx, organizationID, ledgerID, assetCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalAccountBalance", reflect.TypeOf((*MockAccountsService)(nil).GetExternalAccountBalance), ctx, organizationID, ledgerID, assetCode)
+}
+
+// GetAccountByAliasPath mocks base method.
+func (m *MockAccountsService) GetAccountByAliasPath(ctx context.Context, organizationID, ledgerID, alias string) (*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByAliasPath", ctx, organizationID, ledgerID, alias)
+
+	var ret0 *models.Account
+	if ret[0] != nil {
+		ret0, _ = ret[0].(*models.Account) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// GetAccountByAliasPath indicates an expected call of GetAccountByAliasPath.
+func (mr *MockAccountsServiceMockRecorder) GetAccountByAliasPath(ctx, organizationID, ledgerID, alias any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByAliasPath", reflect.TypeOf((*MockAccountsService)(nil).GetAccountByAliasPath), ctx, organizationID, ledgerID, alias)
+}
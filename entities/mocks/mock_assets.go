@@ -81,6 +81,30 @@ func (mr *MockAssetsServiceMockRecorder) GetAsset(ctx, organizationID, ledgerID,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAsset", reflect.TypeOf((*MockAssetsService)(nil).GetAsset), ctx, organizationID, ledgerID, id)
 }
 
+// FindByCode mocks base method.
+func (m *MockAssetsService) FindByCode(ctx context.Context, organizationID, ledgerID, code string) (*models.Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCode", ctx, organizationID, ledgerID, code)
+
+	var ret0 *models.Asset
+	if ret[0] != nil {
+		ret0, _ = ret[0].(*models.Asset) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// FindByCode indicates an expected call of FindByCode.
+func (mr *MockAssetsServiceMockRecorder) FindByCode(ctx, organizationID, ledgerID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCode", reflect.TypeOf((*MockAssetsService)(nil).FindByCode), ctx, organizationID, ledgerID, code)
+}
+
 // CreateAsset mocks base method.
 func (m *MockAssetsService) CreateAsset(ctx context.Context, organizationID, ledgerID string, input *models.CreateAssetInput) (*models.Asset, error) {
 	m.ctrl.T.Helper()
@@ -147,3 +171,27 @@ func (mr *MockAssetsServiceMockRecorder) DeleteAsset(ctx, organizationID, ledger
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAsset", reflect.TypeOf((*MockAssetsService)(nil).DeleteAsset), ctx, organizationID, ledgerID, id)
 }
+
+// GetAssetsMetricsCount mocks base method.
+func (m *MockAssetsService) GetAssetsMetricsCount(ctx context.Context, organizationID, ledgerID string) (*models.MetricsCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssetsMetricsCount", ctx, organizationID, ledgerID)
+
+	var ret0 *models.MetricsCount
+	if ret[0] != nil {
+		ret0, _ = ret[0].(*models.MetricsCount) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// GetAssetsMetricsCount indicates an expected call of GetAssetsMetricsCount.
+func (mr *MockAssetsServiceMockRecorder) GetAssetsMetricsCount(ctx, organizationID, ledgerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssetsMetricsCount", reflect.TypeOf((*MockAssetsService)(nil).GetAssetsMetricsCount), ctx, organizationID, ledgerID)
+}
@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	"github.com/golang/mock/gomock"
@@ -147,3 +148,123 @@ func (mr *MockBalancesServiceMockRecorder) DeleteBalance(ctx, orgID, ledgerID, b
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBalance", reflect.TypeOf((*MockBalancesService)(nil).DeleteBalance), ctx, orgID, ledgerID, balanceID)
 }
+
+// CreateBalance mocks base method.
+func (m *MockBalancesService) CreateBalance(ctx context.Context, orgID, ledgerID, accountID string, input *models.CreateBalanceInput) (*models.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBalance", ctx, orgID, ledgerID, accountID, input)
+
+	var ret0 *models.Balance
+	if ret[0] != nil {
+		ret0, _ = ret[0].(*models.Balance) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// CreateBalance indicates an expected call of CreateBalance.
+func (mr *MockBalancesServiceMockRecorder) CreateBalance(ctx, orgID, ledgerID, accountID, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBalance", reflect.TypeOf((*MockBalancesService)(nil).CreateBalance), ctx, orgID, ledgerID, accountID, input)
+}
+
+// ListBalancesByAccountAlias mocks base method.
+func (m *MockBalancesService) ListBalancesByAccountAlias(ctx context.Context, orgID, ledgerID, alias string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBalancesByAccountAlias", ctx, orgID, ledgerID, alias, opts)
+
+	var ret0 *models.ListResponse[models.Balance]
+	if ret[0] != nil {
+		ret0, _ = ret[0].(*models.ListResponse[models.Balance]) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// ListBalancesByAccountAlias indicates an expected call of ListBalancesByAccountAlias.
+func (mr *MockBalancesServiceMockRecorder) ListBalancesByAccountAlias(ctx, orgID, ledgerID, alias, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBalancesByAccountAlias", reflect.TypeOf((*MockBalancesService)(nil).ListBalancesByAccountAlias), ctx, orgID, ledgerID, alias, opts)
+}
+
+// ListBalancesByExternalCode mocks base method.
+func (m *MockBalancesService) ListBalancesByExternalCode(ctx context.Context, orgID, ledgerID, code string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBalancesByExternalCode", ctx, orgID, ledgerID, code, opts)
+
+	var ret0 *models.ListResponse[models.Balance]
+	if ret[0] != nil {
+		ret0, _ = ret[0].(*models.ListResponse[models.Balance]) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// ListBalancesByExternalCode indicates an expected call of ListBalancesByExternalCode.
+func (mr *MockBalancesServiceMockRecorder) ListBalancesByExternalCode(ctx, orgID, ledgerID, code, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBalancesByExternalCode", reflect.TypeOf((*MockBalancesService)(nil).ListBalancesByExternalCode), ctx, orgID, ledgerID, code, opts)
+}
+
+// GetMany mocks base method.
+func (m *MockBalancesService) GetMany(ctx context.Context, orgID, ledgerID string, accountIDs []string, opts *models.GetManyOptions) (map[string]*models.AccountBalances, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", ctx, orgID, ledgerID, accountIDs, opts)
+
+	var ret0 map[string]*models.AccountBalances
+	if ret[0] != nil {
+		ret0, _ = ret[0].(map[string]*models.AccountBalances) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockBalancesServiceMockRecorder) GetMany(ctx, orgID, ledgerID, accountIDs, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockBalancesService)(nil).GetMany), ctx, orgID, ledgerID, accountIDs, opts)
+}
+
+// AsOf mocks base method.
+func (m *MockBalancesService) AsOf(ctx context.Context, orgID, ledgerID, accountID string, at time.Time) ([]models.BalanceHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AsOf", ctx, orgID, ledgerID, accountID, at)
+
+	var ret0 []models.BalanceHistory
+	if ret[0] != nil {
+		ret0, _ = ret[0].([]models.BalanceHistory) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// AsOf indicates an expected call of AsOf.
+func (mr *MockBalancesServiceMockRecorder) AsOf(ctx, orgID, ledgerID, accountID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsOf", reflect.TypeOf((*MockBalancesService)(nil).AsOf), ctx, orgID, ledgerID, accountID, at)
+}
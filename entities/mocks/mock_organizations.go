@@ -81,6 +81,30 @@ func (mr *MockOrganizationsServiceMockRecorder) GetOrganization(ctx, id any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganization", reflect.TypeOf((*MockOrganizationsService)(nil).GetOrganization), ctx, id)
 }
 
+// FindByLegalDocument mocks base method.
+func (m *MockOrganizationsService) FindByLegalDocument(ctx context.Context, legalDocument string) (*models.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByLegalDocument", ctx, legalDocument)
+
+	var ret0 *models.Organization
+	if ret[0] != nil {
+		ret0, _ = ret[0].(*models.Organization) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// FindByLegalDocument indicates an expected call of FindByLegalDocument.
+func (mr *MockOrganizationsServiceMockRecorder) FindByLegalDocument(ctx, legalDocument any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByLegalDocument", reflect.TypeOf((*MockOrganizationsService)(nil).FindByLegalDocument), ctx, legalDocument)
+}
+
 // CreateOrganization mocks base method.
 func (m *MockOrganizationsService) CreateOrganization(ctx context.Context, input *models.CreateOrganizationInput) (*models.Organization, error) {
 	m.ctrl.T.Helper()
@@ -147,3 +171,27 @@ func (mr *MockOrganizationsServiceMockRecorder) DeleteOrganization(ctx, id any)
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrganization", reflect.TypeOf((*MockOrganizationsService)(nil).DeleteOrganization), ctx, id)
 }
+
+// GetOrganizationsMetricsCount mocks base method.
+func (m *MockOrganizationsService) GetOrganizationsMetricsCount(ctx context.Context) (*models.MetricsCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationsMetricsCount", ctx)
+
+	var ret0 *models.MetricsCount
+	if ret[0] != nil {
+		ret0, _ = ret[0].(*models.MetricsCount) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	var ret1 error
+	if ret[1] != nil {
+		ret1, _ = ret[1].(error) //nolint:errcheck // Type guaranteed by mock setup
+	}
+
+	return ret0, ret1
+}
+
+// GetOrganizationsMetricsCount indicates an expected call of GetOrganizationsMetricsCount.
+func (mr *MockOrganizationsServiceMockRecorder) GetOrganizationsMetricsCount(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationsMetricsCount", reflect.TypeOf((*MockOrganizationsService)(nil).GetOrganizationsMetricsCount), ctx)
+}
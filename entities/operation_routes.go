@@ -110,8 +110,9 @@ type operationRoutesEntity struct {
 	baseURLs   map[string]string
 }
 
-func (e *operationRoutesEntity) setDefaultTenantID(tenantID string) {
-	e.httpClient.SetTenantID(tenantID)
+// useSettings makes the service share the settings of its Entity.
+func (e *operationRoutesEntity) useSettings(settings *clientSettings) {
+	e.httpClient.clientSettings = settings
 }
 
 // NewOperationRoutesEntity creates a new OperationRoutesService instance
@@ -222,7 +223,7 @@ func (e *operationRoutesEntity) CreateOperationRoute(ctx context.Context, organi
 		return nil, errors.NewMissingParameterError(operation, "input")
 	}
 
-	if err := input.Validate(); err != nil {
+	if err := e.httpClient.validateInput(ctx, operation, input.Validate); err != nil {
 		return nil, errors.NewValidationError(operation, "operation route validation failed", err)
 	}
 
@@ -268,7 +269,7 @@ func (e *operationRoutesEntity) UpdateOperationRoute(ctx context.Context, organi
 		return nil, errors.NewMissingParameterError(operation, "input")
 	}
 
-	if err := input.Validate(); err != nil {
+	if err := e.httpClient.validateInput(ctx, operation, input.Validate); err != nil {
 		return nil, errors.NewValidationError(operation, "operation route validation failed", err)
 	}
 
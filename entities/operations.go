@@ -155,9 +155,8 @@ type OperationsService interface {
 	//	fmt.Printf("Account: %s\n", operation.AccountID)
 	//	fmt.Printf("Transaction: %s\n", operation.TransactionID)
 	//
-	//	// Calculate the decimal value of the amount
-	//	decimalValue := float64(operation.Amount) / math.Pow10(int(operation.Scale))
-	//	fmt.Printf("Amount: %.2f %s\n", decimalValue, operation.AssetCode)
+	//	// Amounts are exact decimals, whatever the scale of the asset
+	//	fmt.Printf("Amount: %s %s\n", operation.Amount.Value, operation.AssetCode)
 	//
 	//	// Check if this is a debit or credit operation
 	//	if operation.Type == models.OperationTypeDebit {
@@ -183,8 +182,9 @@ type operationsEntity struct {
 	baseURLs   map[string]string
 }
 
-func (e *operationsEntity) setDefaultTenantID(tenantID string) {
-	e.HTTPClient.SetTenantID(tenantID)
+// useSettings makes the service share the settings of its Entity.
+func (e *operationsEntity) useSettings(settings *clientSettings) {
+	e.HTTPClient.clientSettings = settings
 }
 
 // NewOperationsEntity creates a new operations entity.
@@ -331,9 +331,8 @@ func (e *operationsEntity) ListOperations(ctx context.Context, orgID, ledgerID,
 //	fmt.Printf("Account: %s\n", operation.AccountID)
 //	fmt.Printf("Transaction: %s\n", operation.TransactionID)
 //
-//	// Calculate the decimal value of the amount
-//	decimalValue := float64(operation.Amount) / math.Pow10(int(operation.Scale))
-//	fmt.Printf("Amount: %.2f %s\n", decimalValue, operation.AssetCode)
+//	// Amounts are exact decimals, whatever the scale of the asset
+//	fmt.Printf("Amount: %s %s\n", operation.Amount.Value, operation.AssetCode)
 //
 //	// Check if this is a debit or credit operation
 //	if operation.Type == models.OperationTypeDebit {
@@ -76,10 +76,11 @@ func createTestOperationsEntity(serverURL string) *operationsEntity {
 	_ = retry.WithMaxDelay(10 * time.Millisecond)(retryOpts)
 
 	httpClient := &HTTPClient{
-		client:       &http.Client{Timeout: 5 * time.Second},
-		authToken:    "test-token",
-		retryOptions: retryOpts,
-		jsonPool:     performance.NewJSONPool(),
+		clientSettings: &clientSettings{},
+		client:         &http.Client{Timeout: 5 * time.Second},
+		authToken:      "test-token",
+		retryOptions:   retryOpts,
+		jsonPool:       performance.NewJSONPool(),
 	}
 
 	return &operationsEntity{
@@ -1352,6 +1353,7 @@ func TestMockHTTPClientForOperations(t *testing.T) {
 	_ = retry.WithInitialDelay(1 * time.Millisecond)(retryOpts)
 
 	httpClient := &HTTPClient{
+		clientSettings: &clientSettings{},
 		client: &http.Client{
 			Transport: &mockTransport{mock: mockClient},
 		},
@@ -77,19 +77,20 @@ func WithContext(ctx context.Context) Option {
 }
 
 // WithHTTPClient returns an Option that sets the HTTP client for the Entity.
-// The tenant ID configured on the entity is preserved across the replacement.
+// The settings configured on the entity, such as its tenant ID, read-only mode
+// and token refresher, are preserved across the replacement.
 func WithHTTPClient(client *http.Client) Option {
 	return func(e *Entity) error {
 		if client == nil {
 			return errors.New("HTTP client cannot be nil")
 		}
 
-		// Preserve tenant ID across HTTP client replacement
-		savedTenantID := e.httpClient.tenantID
+		// Keep the settings of the entity across HTTP client replacement
+		settings := e.httpClient.clientSettings
 
 		// Create a new HTTP client with the same auth token and observability
 		e.httpClient = NewHTTPClient(client, e.httpClient.authToken, e.observability)
-		e.httpClient.tenantID = savedTenantID
+		e.httpClient.clientSettings = settings
 
 		// Re-initialize services with the new HTTP client
 		e.initServices()
@@ -26,6 +26,11 @@ type OrganizationsService interface {
 	// Returns the organization if found, or an error if the operation fails or the organization doesn't exist.
 	GetOrganization(ctx context.Context, id string) (*models.Organization, error)
 
+	// FindByLegalDocument retrieves the organization with the given legal document.
+	// The legalDocument parameter is the tax ID or registration number the organization was created with.
+	// Returns the organization if found, or an error matched by errors.IsNotFoundError if no organization has it.
+	FindByLegalDocument(ctx context.Context, legalDocument string) (*models.Organization, error)
+
 	// CreateOrganization creates a new organization.
 	//
 	// Organizations are the top-level entities in the Midaz system that own ledgers,
@@ -139,8 +144,9 @@ type organizationsEntity struct {
 	baseURLs   map[string]string
 }
 
-func (e *organizationsEntity) setDefaultTenantID(tenantID string) {
-	e.HTTPClient.SetTenantID(tenantID)
+// useSettings makes the service share the settings of its Entity.
+func (e *organizationsEntity) useSettings(settings *clientSettings) {
+	e.HTTPClient.clientSettings = settings
 }
 
 // NewOrganizationsEntity creates a new organizations entity.
@@ -250,6 +256,37 @@ func (e *organizationsEntity) GetOrganization(ctx context.Context, id string) (*
 // Organizations are the top-level entities in the Midaz system that own ledgers,
 // accounts, and other resources. Each organization has a legal identity and
 // can manage multiple ledgers.
+func (e *organizationsEntity) FindByLegalDocument(ctx context.Context, legalDocument string) (*models.Organization, error) {
+	const operation = "FindByLegalDocument"
+
+	if legalDocument == "" {
+		return nil, errors.NewMissingParameterError(operation, "legalDocument")
+	}
+
+	opts := models.NewListOptions().WithLimit(models.MaxLimit)
+
+	for opts != nil {
+		page, err := e.ListOrganizations(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range page.Items {
+			if page.Items[i].LegalDocument == legalDocument {
+				return &page.Items[i], nil
+			}
+		}
+
+		if len(page.Items) == 0 {
+			break
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	return nil, errors.NewNotFoundError(operation, "organization", legalDocument, nil)
+}
+
 func (e *organizationsEntity) CreateOrganization(ctx context.Context, input *models.CreateOrganizationInput) (*models.Organization, error) {
 	const operation = "CreateOrganization"
 
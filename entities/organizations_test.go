@@ -12,6 +12,9 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// The mock must be regenerated when OrganizationsService changes.
+var _ OrganizationsService = (*mocks.MockOrganizationsService)(nil)
+
 // \1 performs an operation
 func TestListOrganizations(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -0,0 +1,175 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation/core"
+)
+
+// Known limits of the Midaz API, enforced by the server on every metadata map.
+const (
+	// ServerMaxMetadataKeyLength is the maximum length of a metadata key, in characters
+	ServerMaxMetadataKeyLength = core.BackendMaxMetadataKeyLength
+
+	// ServerMaxMetadataValueLength is the maximum length of a metadata string value, in bytes
+	ServerMaxMetadataValueLength = core.BackendMaxMetadataValueLength
+)
+
+// ErrPayloadTooLarge is the sentinel matched by errors.Is for a PayloadTooLargeError.
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+// PayloadTooLargeError is returned when a request body exceeds one of the
+// configured PayloadLimits. The call is rejected before any request is sent.
+//
+// Example:
+//
+//	_, err := entity.Accounts.CreateAccount(ctx, orgID, ledgerID, input)
+//	var tooLarge *entities.PayloadTooLargeError
+//	if errors.As(err, &tooLarge) {
+//	    log.Printf("%s is %d over the limit", tooLarge.Field, tooLarge.Size-tooLarge.Limit)
+//	}
+type PayloadTooLargeError struct {
+	// Method is the HTTP method of the rejected request
+	Method string
+
+	// URL is the URL of the rejected request
+	URL string
+
+	// Field is the JSON path of the offending field, such as "metadata" or
+	// "metadata.notes", or empty when the whole body is too large
+	Field string
+
+	// Size is the size of the offending field or body
+	Size int
+
+	// Limit is the limit the size exceeds
+	Limit int
+}
+
+// Error implements the error interface.
+func (e *PayloadTooLargeError) Error() string {
+	what := "request body"
+	if e.Field != "" {
+		what = e.Field
+	}
+
+	return fmt.Sprintf("%s: %s %s: %s is %d, limit is %d", ErrPayloadTooLarge, e.Method, e.URL, what, e.Size, e.Limit)
+}
+
+// Is reports whether target is ErrPayloadTooLarge.
+func (*PayloadTooLargeError) Is(target error) bool {
+	return target == ErrPayloadTooLarge
+}
+
+// PayloadLimits bound the request bodies the services send. A zero limit
+// leaves its size unchecked. Metadata limits apply to every "metadata"
+// object in a JSON body, nested ones included.
+type PayloadLimits struct {
+	// MaxBodyBytes bounds the encoded request body
+	MaxBodyBytes int
+
+	// MaxMetadataBytes bounds each encoded metadata object
+	MaxMetadataBytes int
+
+	// MaxMetadataKeyLength bounds each metadata key, in characters
+	MaxMetadataKeyLength int
+
+	// MaxMetadataValueLength bounds each metadata string value, in bytes
+	MaxMetadataValueLength int
+}
+
+// ServerPayloadLimits returns the limits the Midaz API is known to enforce,
+// so requests it would reject are caught before they are sent. Body and
+// metadata sizes are left unchecked; set them to match a gateway in front
+// of the API.
+func ServerPayloadLimits() PayloadLimits {
+	return PayloadLimits{
+		MaxMetadataKeyLength:   ServerMaxMetadataKeyLength,
+		MaxMetadataValueLength: ServerMaxMetadataValueLength,
+	}
+}
+
+// checksMetadata reports whether any metadata limit is set.
+func (l PayloadLimits) checksMetadata() bool {
+	return l.MaxMetadataBytes > 0 || l.MaxMetadataKeyLength > 0 || l.MaxMetadataValueLength > 0
+}
+
+// WithPayloadLimits returns an Option that makes every service of the Entity
+// check request bodies against limits, rejecting the ones that exceed them
+// with a PayloadTooLargeError instead of sending them.
+func WithPayloadLimits(limits PayloadLimits) Option {
+	return func(e *Entity) error {
+		if limits.MaxBodyBytes < 0 || limits.MaxMetadataBytes < 0 ||
+			limits.MaxMetadataKeyLength < 0 || limits.MaxMetadataValueLength < 0 {
+			return errors.New("payload limits cannot be negative")
+		}
+
+		e.httpClient.payloadLimits = limits
+
+		return nil
+	}
+}
+
+// SetPayloadLimits sets the limits request bodies are checked against.
+func (c *HTTPClient) SetPayloadLimits(limits PayloadLimits) {
+	c.payloadLimits = limits
+}
+
+// checkPayload returns a PayloadTooLargeError for a request body exceeding
+// the client's payload limits. Metadata is only checked in JSON bodies.
+func (c *HTTPClient) checkPayload(method, requestURL string, body []byte, isJSON bool) error {
+	limits := c.payloadLimits
+
+	tooLarge := func(field string, size, limit int) error {
+		return &PayloadTooLargeError{Method: method, URL: requestURL, Field: field, Size: size, Limit: limit}
+	}
+
+	if limits.MaxBodyBytes > 0 && len(body) > limits.MaxBodyBytes {
+		return tooLarge("", len(body), limits.MaxBodyBytes)
+	}
+
+	if !isJSON || len(body) == 0 || !limits.checksMetadata() {
+		return nil
+	}
+
+	return walkMetadata("", body, func(path string, metadata json.RawMessage) error {
+		field, size, limit := limits.checkMetadata(path, metadata)
+		if field == "" {
+			return nil
+		}
+
+		return tooLarge(field, size, limit)
+	})
+}
+
+// checkMetadata checks a metadata object against the limits.
+func (l PayloadLimits) checkMetadata(path string, raw json.RawMessage) (string, int, int) {
+	var metadata map[string]json.RawMessage
+	if json.Unmarshal(raw, &metadata) != nil {
+		return "", 0, 0
+	}
+
+	if l.MaxMetadataBytes > 0 && len(raw) > l.MaxMetadataBytes {
+		return path, len(raw), l.MaxMetadataBytes
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(metadata)) {
+		value := metadata[key]
+
+		if n := utf8.RuneCountInString(key); l.MaxMetadataKeyLength > 0 && n > l.MaxMetadataKeyLength {
+			return joinFieldPath(path, key), n, l.MaxMetadataKeyLength
+		}
+
+		var s string
+		if l.MaxMetadataValueLength > 0 && json.Unmarshal(value, &s) == nil && len(s) > l.MaxMetadataValueLength {
+			return joinFieldPath(path, key), len(s), l.MaxMetadataValueLength
+		}
+	}
+
+	return "", 0, 0
+}
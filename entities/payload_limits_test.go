@@ -0,0 +1,151 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPayloadLimits(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	limits := ServerPayloadLimits()
+	limits.MaxBodyBytes = 4096
+
+	entity, err := New(srv.URL, WithPayloadLimits(limits), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	entity.SetHTTPClient(srv.Client())
+	assert.Equal(t, limits, entity.Accounts.(*accountsEntity).httpClient.payloadLimits, "limits reach the services and survive SetHTTPClient")
+	assert.Equal(t, limits, entity.Organizations.(*organizationsEntity).HTTPClient.payloadLimits)
+
+	client := entity.GetEntityHTTPClient()
+	ctx := context.Background()
+	url := srv.URL + "/v1/organizations"
+
+	err = client.doRequest(ctx, http.MethodPost, url, nil, map[string]any{
+		"name":     "ok",
+		"metadata": map[string]any{"notes": strings.Repeat("a", ServerMaxMetadataValueLength)},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+
+	err = client.doRequest(ctx, http.MethodPost, url, nil, map[string]any{
+		"name":     "too long",
+		"metadata": map[string]any{"notes": strings.Repeat("a", ServerMaxMetadataValueLength+1)},
+	}, nil)
+	require.ErrorIs(t, err, ErrPayloadTooLarge)
+
+	var tooLarge *PayloadTooLargeError
+	require.True(t, errors.As(err, &tooLarge))
+	assert.Equal(t, http.MethodPost, tooLarge.Method)
+	assert.Equal(t, "metadata.notes", tooLarge.Field)
+	assert.Equal(t, ServerMaxMetadataValueLength+1, tooLarge.Size)
+	assert.Equal(t, ServerMaxMetadataValueLength, tooLarge.Limit)
+
+	err = client.doRequest(ctx, http.MethodPost, url, nil, map[string]any{
+		"name": strings.Repeat("a", 5000),
+	}, nil)
+	require.ErrorAs(t, err, &tooLarge)
+	assert.Empty(t, tooLarge.Field, "the whole body is too large")
+	assert.Equal(t, 4096, tooLarge.Limit)
+	assert.Contains(t, err.Error(), "request body is")
+
+	err = client.doRawRequest(ctx, http.MethodPost, url, map[string]string{"Content-Type": "text/plain"},
+		[]byte(strings.Repeat("a", 5000)), nil)
+	require.ErrorIs(t, err, ErrPayloadTooLarge)
+
+	assert.Equal(t, int32(1), requests.Load(), "rejected calls don't reach the server")
+
+	_, err = New(srv.URL, WithPayloadLimits(PayloadLimits{MaxBodyBytes: -1}))
+	require.Error(t, err)
+}
+
+func TestCheckPayload(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits PayloadLimits
+		body   string
+		isJSON bool
+		field  string
+		size   int
+		limit  int
+	}{
+		{
+			name:   "no limits",
+			limits: PayloadLimits{},
+			body:   `{"metadata":{"k":"` + strings.Repeat("a", 5000) + `"}}`,
+			isJSON: true,
+		},
+		{
+			name:   "metadata object size",
+			limits: PayloadLimits{MaxMetadataBytes: 10},
+			body:   `{"metadata": {"key":"value"}}`,
+			isJSON: true,
+			field:  "metadata",
+			size:   15,
+			limit:  10,
+		},
+		{
+			name:   "metadata key length",
+			limits: PayloadLimits{MaxMetadataKeyLength: 3},
+			body:   `{"metadata":{"abc":1,"abcd":2}}`,
+			isJSON: true,
+			field:  "metadata.abcd",
+			size:   4,
+			limit:  3,
+		},
+		{
+			name:   "nested metadata",
+			limits: PayloadLimits{MaxMetadataValueLength: 3},
+			body:   `{"send":{"source":{"from":[{"metadata":{"k":"ok"}},{"metadata":{"k":"long"}}]}}}`,
+			isJSON: true,
+			field:  "send.source.from[1].metadata.k",
+			size:   4,
+			limit:  3,
+		},
+		{
+			name:   "non-string values are not measured",
+			limits: PayloadLimits{MaxMetadataValueLength: 3},
+			body:   `{"metadata":{"k":123456}}`,
+			isJSON: true,
+		},
+		{
+			name:   "metadata in non-JSON bodies is not checked",
+			limits: PayloadLimits{MaxMetadataValueLength: 3},
+			body:   `{"metadata":{"k":"long"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &HTTPClient{clientSettings: &clientSettings{payloadLimits: tt.limits}}
+
+			err := client.checkPayload(http.MethodPost, "https://api.example.com", []byte(tt.body), tt.isJSON)
+			if tt.field == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var tooLarge *PayloadTooLargeError
+			require.ErrorAs(t, err, &tooLarge)
+			assert.Equal(t, tt.field, tooLarge.Field)
+			assert.Equal(t, tt.size, tooLarge.Size)
+			assert.Equal(t, tt.limit, tooLarge.Limit)
+		})
+	}
+}
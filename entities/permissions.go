@@ -0,0 +1,267 @@
+package entities
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	auth "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/access-manager"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// operationPermissions maps each SDK operation, named "Service.Method", to the
+// access manager permission Midaz requires for it.
+var operationPermissions = map[string]auth.Permission{
+	"Organizations.ListOrganizations":            {Resource: "organizations", Action: "get"},
+	"Organizations.GetOrganization":              {Resource: "organizations", Action: "get"},
+	"Organizations.FindByLegalDocument":          {Resource: "organizations", Action: "get"},
+	"Organizations.CreateOrganization":           {Resource: "organizations", Action: "post"},
+	"Organizations.UpdateOrganization":           {Resource: "organizations", Action: "patch"},
+	"Organizations.DeleteOrganization":           {Resource: "organizations", Action: "delete"},
+	"Organizations.GetOrganizationsMetricsCount": {Resource: "organizations", Action: "get"},
+
+	"Ledgers.ListLedgers":            {Resource: "ledgers", Action: "get"},
+	"Ledgers.GetLedger":              {Resource: "ledgers", Action: "get"},
+	"Ledgers.CreateLedger":           {Resource: "ledgers", Action: "post"},
+	"Ledgers.UpdateLedger":           {Resource: "ledgers", Action: "patch"},
+	"Ledgers.DeleteLedger":           {Resource: "ledgers", Action: "delete"},
+	"Ledgers.GetLedgersMetricsCount": {Resource: "ledgers", Action: "get"},
+
+	"Assets.ListAssets":            {Resource: "assets", Action: "get"},
+	"Assets.GetAsset":              {Resource: "assets", Action: "get"},
+	"Assets.FindByCode":            {Resource: "assets", Action: "get"},
+	"Assets.CreateAsset":           {Resource: "assets", Action: "post"},
+	"Assets.UpdateAsset":           {Resource: "assets", Action: "patch"},
+	"Assets.DeleteAsset":           {Resource: "assets", Action: "delete"},
+	"Assets.GetAssetsMetricsCount": {Resource: "assets", Action: "get"},
+
+	"Portfolios.ListPortfolios":            {Resource: "portfolios", Action: "get"},
+	"Portfolios.GetPortfolio":              {Resource: "portfolios", Action: "get"},
+	"Portfolios.CreatePortfolio":           {Resource: "portfolios", Action: "post"},
+	"Portfolios.UpdatePortfolio":           {Resource: "portfolios", Action: "patch"},
+	"Portfolios.DeletePortfolio":           {Resource: "portfolios", Action: "delete"},
+	"Portfolios.GetPortfoliosMetricsCount": {Resource: "portfolios", Action: "get"},
+
+	"Segments.ListSegments":            {Resource: "segments", Action: "get"},
+	"Segments.GetSegment":              {Resource: "segments", Action: "get"},
+	"Segments.CreateSegment":           {Resource: "segments", Action: "post"},
+	"Segments.UpdateSegment":           {Resource: "segments", Action: "patch"},
+	"Segments.DeleteSegment":           {Resource: "segments", Action: "delete"},
+	"Segments.GetSegmentsMetricsCount": {Resource: "segments", Action: "get"},
+
+	"Accounts.ListAccounts":              {Resource: "accounts", Action: "get"},
+	"Accounts.GetAccount":                {Resource: "accounts", Action: "get"},
+	"Accounts.GetAccountByAlias":         {Resource: "accounts", Action: "get"},
+	"Accounts.GetAccountByAliasPath":     {Resource: "accounts", Action: "get"},
+	"Accounts.FindByAlias":               {Resource: "accounts", Action: "get"},
+	"Accounts.GetExternalAccount":        {Resource: "accounts", Action: "get"},
+	"Accounts.CreateAccount":             {Resource: "accounts", Action: "post"},
+	"Accounts.UpdateAccount":             {Resource: "accounts", Action: "patch"},
+	"Accounts.DeleteAccount":             {Resource: "accounts", Action: "delete"},
+	"Accounts.GetAccountsMetricsCount":   {Resource: "accounts", Action: "get"},
+	"Accounts.GetBalance":                {Resource: "balances", Action: "get"},
+	"Accounts.GetExternalAccountBalance": {Resource: "balances", Action: "get"},
+
+	"AccountTypes.ListAccountTypes":            {Resource: "account-types", Action: "get"},
+	"AccountTypes.GetAccountType":              {Resource: "account-types", Action: "get"},
+	"AccountTypes.CreateAccountType":           {Resource: "account-types", Action: "post"},
+	"AccountTypes.UpdateAccountType":           {Resource: "account-types", Action: "patch"},
+	"AccountTypes.DeleteAccountType":           {Resource: "account-types", Action: "delete"},
+	"AccountTypes.GetAccountTypesMetricsCount": {Resource: "account-types", Action: "get"},
+
+	"Transactions.CreateTransaction":            {Resource: "transactions", Action: "post"},
+	"Transactions.CreateTransactionWithDSL":     {Resource: "transactions", Action: "post"},
+	"Transactions.CreateTransactionWithDSLFile": {Resource: "transactions", Action: "post"},
+	"Transactions.CreateInflowTransaction":      {Resource: "transactions", Action: "post"},
+	"Transactions.CreateOutflowTransaction":     {Resource: "transactions", Action: "post"},
+	"Transactions.CreateAnnotationTransaction":  {Resource: "transactions", Action: "post"},
+	"Transactions.CommitTransaction":            {Resource: "transactions", Action: "post"},
+	"Transactions.CancelTransaction":            {Resource: "transactions", Action: "post"},
+	"Transactions.RevertTransaction":            {Resource: "transactions", Action: "post"},
+	"Transactions.GetTransaction":               {Resource: "transactions", Action: "get"},
+	"Transactions.ListTransactions":             {Resource: "transactions", Action: "get"},
+	"Transactions.UpdateTransaction":            {Resource: "transactions", Action: "patch"},
+
+	"Operations.ListOperations":  {Resource: "operations", Action: "get"},
+	"Operations.GetOperation":    {Resource: "operations", Action: "get"},
+	"Operations.UpdateOperation": {Resource: "operations", Action: "patch"},
+
+	"Balances.ListBalances":               {Resource: "balances", Action: "get"},
+	"Balances.ListAccountBalances":        {Resource: "balances", Action: "get"},
+	"Balances.ListBalancesByAccountAlias": {Resource: "balances", Action: "get"},
+	"Balances.ListBalancesByExternalCode": {Resource: "balances", Action: "get"},
+	"Balances.GetBalance":                 {Resource: "balances", Action: "get"},
+	"Balances.GetMany":                    {Resource: "balances", Action: "get"},
+	"Balances.AsOf":                       {Resource: "balances", Action: "get"},
+	"Balances.CreateBalance":              {Resource: "balances", Action: "post"},
+	"Balances.UpdateBalance":              {Resource: "balances", Action: "patch"},
+	"Balances.DeleteBalance":              {Resource: "balances", Action: "delete"},
+
+	"AssetRates.CreateOrUpdateAssetRate":   {Resource: "asset-rates", Action: "put"},
+	"AssetRates.GetAssetRate":              {Resource: "asset-rates", Action: "get"},
+	"AssetRates.ListAssetRatesByAssetCode": {Resource: "asset-rates", Action: "get"},
+
+	"OperationRoutes.ListOperationRoutes":  {Resource: "operation-routes", Action: "get"},
+	"OperationRoutes.GetOperationRoute":    {Resource: "operation-routes", Action: "get"},
+	"OperationRoutes.CreateOperationRoute": {Resource: "operation-routes", Action: "post"},
+	"OperationRoutes.UpdateOperationRoute": {Resource: "operation-routes", Action: "patch"},
+	"OperationRoutes.DeleteOperationRoute": {Resource: "operation-routes", Action: "delete"},
+
+	"TransactionRoutes.ListTransactionRoutes":  {Resource: "transaction-routes", Action: "get"},
+	"TransactionRoutes.GetTransactionRoute":    {Resource: "transaction-routes", Action: "get"},
+	"TransactionRoutes.CreateTransactionRoute": {Resource: "transaction-routes", Action: "post"},
+	"TransactionRoutes.UpdateTransactionRoute": {Resource: "transaction-routes", Action: "patch"},
+	"TransactionRoutes.DeleteTransactionRoute": {Resource: "transaction-routes", Action: "delete"},
+}
+
+// RequiredPermission returns the permission Midaz requires for an SDK
+// operation, named "Service.Method" after the Entity field and the service
+// method, e.g. "Transactions.CreateTransaction".
+func RequiredPermission(operation string) (auth.Permission, bool) {
+	permission, ok := operationPermissions[operation]
+	return permission, ok
+}
+
+// PermissionOperations returns the names of the operations known to
+// RequiredPermission, sorted.
+func PermissionOperations() []string {
+	operations := make([]string, 0, len(operationPermissions))
+	for operation := range operationPermissions {
+		operations = append(operations, operation)
+	}
+
+	sort.Strings(operations)
+
+	return operations
+}
+
+// ErrPermissionDenied is the sentinel matched by errors.Is for a PermissionDeniedError.
+var ErrPermissionDenied = stderrors.New("permission denied")
+
+// PermissionDeniedError is returned by CheckPermissions when the credentials
+// lack permissions required by some operations. It lists all of them, so
+// they can be granted at once.
+type PermissionDeniedError struct {
+	// Operations are the operations that would be rejected, sorted
+	Operations []string
+
+	// Permissions are the missing permissions, sorted
+	Permissions []auth.Permission
+}
+
+// Error implements the error interface.
+func (e *PermissionDeniedError) Error() string {
+	permissions := make([]string, len(e.Permissions))
+	for i, permission := range e.Permissions {
+		permissions[i] = permission.String()
+	}
+
+	return fmt.Sprintf("%s: missing %s for %s", ErrPermissionDenied,
+		strings.Join(permissions, ", "), strings.Join(e.Operations, ", "))
+}
+
+// Is reports whether target is ErrPermissionDenied.
+func (*PermissionDeniedError) Is(target error) bool {
+	return target == ErrPermissionDenied
+}
+
+// PermissionChecker decides whether the current credentials grant a permission.
+type PermissionChecker interface {
+	Authorized(ctx context.Context, permission auth.Permission) (bool, error)
+}
+
+// PermissionCheckerFunc adapts a function to a PermissionChecker.
+type PermissionCheckerFunc func(ctx context.Context, permission auth.Permission) (bool, error)
+
+// Authorized calls f.
+func (f PermissionCheckerFunc) Authorized(ctx context.Context, permission auth.Permission) (bool, error) {
+	return f(ctx, permission)
+}
+
+// CheckPermissions verifies, before any of them is attempted, that checker
+// grants every permission required by the operations. Each distinct
+// permission is checked once. Missing permissions are reported together in a
+// PermissionDeniedError; unknown operation names are a validation error.
+//
+// Example:
+//
+//	err := entities.CheckPermissions(ctx, entity.PermissionChecker(accessManager),
+//	    "Transactions.CreateTransaction", "Balances.ListAccountBalances")
+//	if errors.Is(err, entities.ErrPermissionDenied) {
+//	    log.Fatalf("credentials can't run the batch: %v", err)
+//	}
+func CheckPermissions(ctx context.Context, checker PermissionChecker, operations ...string) error {
+	const operation = "CheckPermissions"
+
+	if checker == nil {
+		return errors.NewMissingParameterError(operation, "checker")
+	}
+
+	var unknown []string
+
+	byPermission := make(map[auth.Permission][]string)
+
+	for _, name := range operations {
+		permission, ok := RequiredPermission(name)
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		byPermission[permission] = append(byPermission[permission], name)
+	}
+
+	if len(unknown) > 0 {
+		return errors.NewValidationError(operation, "unknown operations: "+strings.Join(unknown, ", "), nil)
+	}
+
+	denied := &PermissionDeniedError{}
+
+	for permission, names := range byPermission {
+		granted, err := checker.Authorized(ctx, permission)
+		if err != nil {
+			return errors.NewInternalError(operation, fmt.Errorf("failed to check %s: %w", permission, err))
+		}
+
+		if !granted {
+			denied.Permissions = append(denied.Permissions, permission)
+			denied.Operations = append(denied.Operations, names...)
+		}
+	}
+
+	if len(denied.Permissions) == 0 {
+		return nil
+	}
+
+	sort.Strings(denied.Operations)
+	sort.Slice(denied.Permissions, func(i, j int) bool {
+		return denied.Permissions[i].String() < denied.Permissions[j].String()
+	})
+
+	return denied
+}
+
+// PermissionChecker returns a checker asking the access manager about the
+// entity's current token. When plugin auth is disabled, Midaz doesn't enforce
+// permissions, so the checker grants everything.
+func (e *Entity) PermissionChecker(accessMgr auth.AccessManager) PermissionChecker {
+	if !accessMgr.Enabled {
+		return PermissionCheckerFunc(func(context.Context, auth.Permission) (bool, error) {
+			return true, nil
+		})
+	}
+
+	client := e.httpClient.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	token := e.httpClient.authToken
+
+	return PermissionCheckerFunc(func(ctx context.Context, permission auth.Permission) (bool, error) {
+		return auth.Authorize(ctx, accessMgr, client, token, permission)
+	})
+}
@@ -0,0 +1,107 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	auth "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/access-manager"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredPermissionCoversServices(t *testing.T) {
+	services := map[string]reflect.Type{
+		"Organizations":     reflect.TypeFor[OrganizationsService](),
+		"Ledgers":           reflect.TypeFor[LedgersService](),
+		"Assets":            reflect.TypeFor[AssetsService](),
+		"Portfolios":        reflect.TypeFor[PortfoliosService](),
+		"Segments":          reflect.TypeFor[SegmentsService](),
+		"Accounts":          reflect.TypeFor[AccountsService](),
+		"AccountTypes":      reflect.TypeFor[AccountTypesService](),
+		"Transactions":      reflect.TypeFor[TransactionsService](),
+		"Operations":        reflect.TypeFor[OperationsService](),
+		"Balances":          reflect.TypeFor[BalancesService](),
+		"AssetRates":        reflect.TypeFor[AssetRatesService](),
+		"OperationRoutes":   reflect.TypeFor[OperationRoutesService](),
+		"TransactionRoutes": reflect.TypeFor[TransactionRoutesService](),
+	}
+
+	count := 0
+
+	for name, service := range services {
+		for i := range service.NumMethod() {
+			operation := name + "." + service.Method(i).Name
+			_, ok := RequiredPermission(operation)
+			assert.True(t, ok, "no permission declared for %s", operation)
+
+			count++
+		}
+	}
+
+	assert.Len(t, PermissionOperations(), count, "permissions declared for unknown operations")
+}
+
+func TestCheckPermissions(t *testing.T) {
+	var checked []auth.Permission
+
+	checker := PermissionCheckerFunc(func(_ context.Context, permission auth.Permission) (bool, error) {
+		checked = append(checked, permission)
+		return permission.Action == "get", nil
+	})
+
+	ctx := context.Background()
+
+	require.NoError(t, CheckPermissions(ctx, checker, "Transactions.ListTransactions", "Transactions.GetTransaction"))
+	assert.Len(t, checked, 1, "each permission is checked once")
+
+	err := CheckPermissions(ctx, checker,
+		"Transactions.CreateTransaction", "Transactions.RevertTransaction", "Balances.DeleteBalance", "Balances.GetBalance")
+	require.ErrorIs(t, err, ErrPermissionDenied)
+
+	var denied *PermissionDeniedError
+	require.True(t, errors.As(err, &denied))
+	assert.Equal(t, []string{"Balances.DeleteBalance", "Transactions.CreateTransaction", "Transactions.RevertTransaction"}, denied.Operations)
+	assert.Equal(t, []auth.Permission{{Resource: "balances", Action: "delete"}, {Resource: "transactions", Action: "post"}}, denied.Permissions)
+
+	err = CheckPermissions(ctx, checker, "Transactions.Teleport")
+	assert.True(t, sdkerrors.IsValidationError(err))
+
+	failing := PermissionCheckerFunc(func(context.Context, auth.Permission) (bool, error) {
+		return false, errors.New("connection refused")
+	})
+	err = CheckPermissions(ctx, failing, "Transactions.ListTransactions")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrPermissionDenied)
+}
+
+func TestEntityPermissionChecker(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		assert.Equal(t, "/v1/authorize", r.URL.Path)
+		assert.Equal(t, "token-1", r.Header.Get("Authorization"))
+
+		_ = json.NewEncoder(w).Encode(map[string]bool{"authorized": body["resource"] == "balances"})
+	}))
+	defer srv.Close()
+
+	entity, err := New(srv.URL)
+	require.NoError(t, err)
+	entity.SetAuthToken("token-1")
+
+	checker := entity.PermissionChecker(auth.AccessManager{Enabled: true, Address: srv.URL})
+
+	require.NoError(t, CheckPermissions(context.Background(), checker, "Balances.ListBalances"))
+	require.ErrorIs(t, CheckPermissions(context.Background(), checker, "Accounts.CreateAccount"), ErrPermissionDenied)
+
+	// without plugin auth Midaz doesn't enforce permissions
+	disabled := entity.PermissionChecker(auth.AccessManager{})
+	require.NoError(t, CheckPermissions(context.Background(), disabled, "Accounts.CreateAccount"))
+}
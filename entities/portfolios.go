@@ -139,8 +139,9 @@ type portfoliosEntity struct {
 	baseURLs   map[string]string
 }
 
-func (e *portfoliosEntity) setDefaultTenantID(tenantID string) {
-	e.HTTPClient.SetTenantID(tenantID)
+// useSettings makes the service share the settings of its Entity.
+func (e *portfoliosEntity) useSettings(settings *clientSettings) {
+	e.HTTPClient.clientSettings = settings
 }
 
 // NewPortfoliosEntity creates a new portfolios entity.
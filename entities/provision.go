@@ -0,0 +1,310 @@
+package entities
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// ProvisionError is returned by Provision when the provisioning function
+// fails. Err is the failure and Rollback joins the errors of undo steps that
+// failed, nil if every resource created was removed.
+type ProvisionError struct {
+	Err      error
+	Rollback error
+}
+
+// Error implements the error interface.
+func (e *ProvisionError) Error() string {
+	if e.Rollback == nil {
+		return fmt.Sprintf("provisioning failed, rolled back: %v", e.Err)
+	}
+
+	return fmt.Sprintf("provisioning failed: %v; rollback incomplete: %v", e.Err, e.Rollback)
+}
+
+// Unwrap returns the provisioning failure and the rollback errors, so
+// errors.Is and errors.As match either.
+func (e *ProvisionError) Unwrap() []error {
+	if e.Rollback == nil {
+		return []error{e.Err}
+	}
+
+	return []error{e.Err, e.Rollback}
+}
+
+// Provisioner is the handle passed to the function run by Provision. Its
+// Create methods call the matching service and remember how to undo the
+// creation. It is safe for concurrent use.
+type Provisioner struct {
+	entity *Entity
+
+	mu    sync.Mutex
+	undos []undoStep
+}
+
+type undoStep struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Provision runs fn with a Provisioner and, if fn returns an error or panics,
+// removes the resources created through it in reverse order: accounts are
+// deleted, or set to INACTIVE when they can't be, and everything else is
+// deleted. Resources created directly through the services are not tracked.
+//
+// The rollback runs even if ctx is canceled. When it is incomplete the
+// returned *ProvisionError lists what could not be undone.
+//
+// Example:
+//
+//	err := client.Entity.Provision(ctx, func(ctx context.Context, p *entities.Provisioner) error {
+//	    org, err := p.CreateOrganization(ctx, orgInput)
+//	    if err != nil {
+//	        return err
+//	    }
+//
+//	    ledger, err := p.CreateLedger(ctx, org.ID, ledgerInput)
+//	    if err != nil {
+//	        return err // the organization is deleted
+//	    }
+//
+//	    _, err = p.CreateAsset(ctx, org.ID, ledger.ID, assetInput)
+//	    return err // on failure, the ledger and the organization are deleted
+//	})
+func (e *Entity) Provision(ctx context.Context, fn func(ctx context.Context, p *Provisioner) error) (err error) {
+	if fn == nil {
+		return errors.NewMissingParameterError("Provision", "fn")
+	}
+
+	p := &Provisioner{entity: e}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = p.rollback(context.WithoutCancel(ctx)) //nolint:errcheck // the panic takes precedence
+			panic(r)
+		}
+	}()
+
+	if err = fn(ctx, p); err != nil {
+		return &ProvisionError{Err: err, Rollback: p.rollback(context.WithoutCancel(ctx))}
+	}
+
+	return nil
+}
+
+// OnRollback registers fn to run if provisioning fails, for resources the
+// Provisioner doesn't create itself. Undo functions run in reverse order of
+// registration.
+func (p *Provisioner) OnRollback(name string, fn func(ctx context.Context) error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.undos = append(p.undos, undoStep{name: name, fn: fn})
+}
+
+// Len returns the number of undo steps registered so far.
+func (p *Provisioner) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.undos)
+}
+
+func (p *Provisioner) rollback(ctx context.Context) error {
+	p.mu.Lock()
+	undos := p.undos
+	p.undos = nil
+	p.mu.Unlock()
+
+	var errs []error
+
+	for i := len(undos) - 1; i >= 0; i-- {
+		if err := undos[i].fn(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", undos[i].name, err))
+		}
+	}
+
+	return stderrors.Join(errs...)
+}
+
+// CreateOrganization creates an organization that is deleted on rollback.
+func (p *Provisioner) CreateOrganization(ctx context.Context, input *models.CreateOrganizationInput) (*models.Organization, error) {
+	if p.entity.Organizations == nil {
+		return nil, errors.NewInternalError("CreateOrganization", fmt.Errorf("organizations service not initialized"))
+	}
+
+	org, err := p.entity.Organizations.CreateOrganization(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	p.OnRollback("delete organization "+org.ID, func(ctx context.Context) error {
+		return p.entity.Organizations.DeleteOrganization(ctx, org.ID)
+	})
+
+	return org, nil
+}
+
+// CreateLedger creates a ledger that is deleted on rollback.
+func (p *Provisioner) CreateLedger(ctx context.Context, organizationID string, input *models.CreateLedgerInput) (*models.Ledger, error) {
+	if p.entity.Ledgers == nil {
+		return nil, errors.NewInternalError("CreateLedger", fmt.Errorf("ledgers service not initialized"))
+	}
+
+	ledger, err := p.entity.Ledgers.CreateLedger(ctx, organizationID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	p.OnRollback("delete ledger "+ledger.ID, func(ctx context.Context) error {
+		return p.entity.Ledgers.DeleteLedger(ctx, organizationID, ledger.ID)
+	})
+
+	return ledger, nil
+}
+
+// CreateAsset creates an asset that is deleted on rollback.
+func (p *Provisioner) CreateAsset(ctx context.Context, organizationID, ledgerID string, input *models.CreateAssetInput) (*models.Asset, error) {
+	if p.entity.Assets == nil {
+		return nil, errors.NewInternalError("CreateAsset", fmt.Errorf("assets service not initialized"))
+	}
+
+	asset, err := p.entity.Assets.CreateAsset(ctx, organizationID, ledgerID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	p.OnRollback("delete asset "+asset.ID, func(ctx context.Context) error {
+		return p.entity.Assets.DeleteAsset(ctx, organizationID, ledgerID, asset.ID)
+	})
+
+	return asset, nil
+}
+
+// CreatePortfolio creates a portfolio that is deleted on rollback.
+func (p *Provisioner) CreatePortfolio(ctx context.Context, organizationID, ledgerID string, input *models.CreatePortfolioInput) (*models.Portfolio, error) {
+	if p.entity.Portfolios == nil {
+		return nil, errors.NewInternalError("CreatePortfolio", fmt.Errorf("portfolios service not initialized"))
+	}
+
+	portfolio, err := p.entity.Portfolios.CreatePortfolio(ctx, organizationID, ledgerID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	p.OnRollback("delete portfolio "+portfolio.ID, func(ctx context.Context) error {
+		return p.entity.Portfolios.DeletePortfolio(ctx, organizationID, ledgerID, portfolio.ID)
+	})
+
+	return portfolio, nil
+}
+
+// CreateSegment creates a segment that is deleted on rollback.
+func (p *Provisioner) CreateSegment(ctx context.Context, organizationID, ledgerID string, input *models.CreateSegmentInput) (*models.Segment, error) {
+	if p.entity.Segments == nil {
+		return nil, errors.NewInternalError("CreateSegment", fmt.Errorf("segments service not initialized"))
+	}
+
+	segment, err := p.entity.Segments.CreateSegment(ctx, organizationID, ledgerID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	p.OnRollback("delete segment "+segment.ID, func(ctx context.Context) error {
+		return p.entity.Segments.DeleteSegment(ctx, organizationID, ledgerID, segment.ID)
+	})
+
+	return segment, nil
+}
+
+// CreateAccountType creates an account type that is deleted on rollback.
+func (p *Provisioner) CreateAccountType(ctx context.Context, organizationID, ledgerID string, input *models.CreateAccountTypeInput) (*models.AccountType, error) {
+	if p.entity.AccountTypes == nil {
+		return nil, errors.NewInternalError("CreateAccountType", fmt.Errorf("account types service not initialized"))
+	}
+
+	accountType, err := p.entity.AccountTypes.CreateAccountType(ctx, organizationID, ledgerID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	id := accountType.ID.String()
+	p.OnRollback("delete account type "+id, func(ctx context.Context) error {
+		return p.entity.AccountTypes.DeleteAccountType(ctx, organizationID, ledgerID, id)
+	})
+
+	return accountType, nil
+}
+
+// CreateAccount creates an account that is deleted on rollback. Accounts
+// the ledger refuses to delete, such as accounts with balances, are set to
+// INACTIVE instead.
+func (p *Provisioner) CreateAccount(ctx context.Context, organizationID, ledgerID string, input *models.CreateAccountInput) (*models.Account, error) {
+	if p.entity.Accounts == nil {
+		return nil, errors.NewInternalError("CreateAccount", fmt.Errorf("accounts service not initialized"))
+	}
+
+	account, err := p.entity.Accounts.CreateAccount(ctx, organizationID, ledgerID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	p.OnRollback("delete account "+account.ID, func(ctx context.Context) error {
+		deleteErr := p.entity.Accounts.DeleteAccount(ctx, organizationID, ledgerID, account.ID)
+		if deleteErr == nil {
+			return nil
+		}
+
+		update := &models.UpdateAccountInput{Name: account.Name, Status: models.NewStatus(AccountStatusInactive)}
+		if _, err := p.entity.Accounts.UpdateAccount(ctx, organizationID, ledgerID, account.ID, update); err != nil {
+			return stderrors.Join(deleteErr, fmt.Errorf("deactivate: %w", err))
+		}
+
+		return nil
+	})
+
+	return account, nil
+}
+
+// CreateOperationRoute creates an operation route that is deleted on rollback.
+func (p *Provisioner) CreateOperationRoute(ctx context.Context, organizationID, ledgerID string, input *models.CreateOperationRouteInput) (*models.OperationRoute, error) {
+	if p.entity.OperationRoutes == nil {
+		return nil, errors.NewInternalError("CreateOperationRoute", fmt.Errorf("operation routes service not initialized"))
+	}
+
+	route, err := p.entity.OperationRoutes.CreateOperationRoute(ctx, organizationID, ledgerID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	id := route.ID.String()
+	p.OnRollback("delete operation route "+id, func(ctx context.Context) error {
+		return p.entity.OperationRoutes.DeleteOperationRoute(ctx, organizationID, ledgerID, id)
+	})
+
+	return route, nil
+}
+
+// CreateTransactionRoute creates a transaction route that is deleted on rollback.
+func (p *Provisioner) CreateTransactionRoute(ctx context.Context, organizationID, ledgerID string, input *models.CreateTransactionRouteInput) (*models.TransactionRoute, error) {
+	if p.entity.TransactionRoutes == nil {
+		return nil, errors.NewInternalError("CreateTransactionRoute", fmt.Errorf("transaction routes service not initialized"))
+	}
+
+	route, err := p.entity.TransactionRoutes.CreateTransactionRoute(ctx, organizationID, ledgerID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	id := route.ID.String()
+	p.OnRollback("delete transaction route "+id, func(ctx context.Context) error {
+		return p.entity.TransactionRoutes.DeleteTransactionRoute(ctx, organizationID, ledgerID, id)
+	})
+
+	return route, nil
+}
@@ -0,0 +1,199 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type provisionLog struct {
+	undone []string
+}
+
+type fakeProvisionOrganizations struct {
+	OrganizationsService
+	log *provisionLog
+}
+
+func (f *fakeProvisionOrganizations) CreateOrganization(context.Context, *models.CreateOrganizationInput) (*models.Organization, error) {
+	return &models.Organization{ID: "org-1"}, nil
+}
+
+func (f *fakeProvisionOrganizations) DeleteOrganization(_ context.Context, id string) error {
+	f.log.undone = append(f.log.undone, "delete "+id)
+	return nil
+}
+
+type fakeProvisionLedgers struct {
+	LedgersService
+	log *provisionLog
+}
+
+func (f *fakeProvisionLedgers) CreateLedger(context.Context, string, *models.CreateLedgerInput) (*models.Ledger, error) {
+	return &models.Ledger{ID: "ledger-1"}, nil
+}
+
+func (f *fakeProvisionLedgers) DeleteLedger(_ context.Context, _, id string) error {
+	f.log.undone = append(f.log.undone, "delete "+id)
+	return nil
+}
+
+type fakeProvisionAccounts struct {
+	AccountsService
+	log       *provisionLog
+	deleteErr error
+	updateErr error
+}
+
+func (f *fakeProvisionAccounts) CreateAccount(context.Context, string, string, *models.CreateAccountInput) (*models.Account, error) {
+	return &models.Account{ID: "acc-1", Name: "Customer"}, nil
+}
+
+func (f *fakeProvisionAccounts) DeleteAccount(_ context.Context, _, _, id string) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+
+	f.log.undone = append(f.log.undone, "delete "+id)
+
+	return nil
+}
+
+func (f *fakeProvisionAccounts) UpdateAccount(_ context.Context, _, _, id string, input *models.UpdateAccountInput) (*models.Account, error) {
+	if f.updateErr != nil {
+		return nil, f.updateErr
+	}
+
+	f.log.undone = append(f.log.undone, "deactivate "+id+" "+input.Status.Code)
+
+	return &models.Account{ID: id}, nil
+}
+
+func newProvisionEntity(log *provisionLog, accounts *fakeProvisionAccounts) *Entity {
+	accounts.log = log
+
+	return &Entity{
+		Organizations: &fakeProvisionOrganizations{log: log},
+		Ledgers:       &fakeProvisionLedgers{log: log},
+		Accounts:      accounts,
+	}
+}
+
+// provisionAll creates an organization, a ledger and an account, then fails with stepErr.
+func provisionAll(stepErr error) func(ctx context.Context, p *Provisioner) error {
+	return func(ctx context.Context, p *Provisioner) error {
+		org, err := p.CreateOrganization(ctx, models.NewCreateOrganizationInput("Acme"))
+		if err != nil {
+			return err
+		}
+
+		ledger, err := p.CreateLedger(ctx, org.ID, models.NewCreateLedgerInput("Operations"))
+		if err != nil {
+			return err
+		}
+
+		if _, err := p.CreateAccount(ctx, org.ID, ledger.ID, models.NewCreateAccountInput("Customer", "USD", "deposit")); err != nil {
+			return err
+		}
+
+		return stepErr
+	}
+}
+
+func TestEntity_Provision(t *testing.T) {
+	errStep := errors.New("step failed")
+
+	t.Run("success keeps the resources", func(t *testing.T) {
+		log := &provisionLog{}
+
+		err := newProvisionEntity(log, &fakeProvisionAccounts{}).Provision(context.Background(), provisionAll(nil))
+		require.NoError(t, err)
+		assert.Empty(t, log.undone)
+	})
+
+	t.Run("failure rolls back in reverse order", func(t *testing.T) {
+		log := &provisionLog{}
+
+		err := newProvisionEntity(log, &fakeProvisionAccounts{}).Provision(context.Background(), provisionAll(errStep))
+		require.ErrorIs(t, err, errStep)
+		assert.Equal(t, []string{"delete acc-1", "delete ledger-1", "delete org-1"}, log.undone)
+
+		var provisionErr *ProvisionError
+		require.ErrorAs(t, err, &provisionErr)
+		assert.NoError(t, provisionErr.Rollback)
+	})
+
+	t.Run("undeletable account is deactivated", func(t *testing.T) {
+		log := &provisionLog{}
+		accounts := &fakeProvisionAccounts{deleteErr: errors.New("account has balance")}
+
+		err := newProvisionEntity(log, accounts).Provision(context.Background(), provisionAll(errStep))
+		require.ErrorIs(t, err, errStep)
+		assert.Equal(t, []string{"deactivate acc-1 INACTIVE", "delete ledger-1", "delete org-1"}, log.undone)
+	})
+
+	t.Run("incomplete rollback is reported and continues", func(t *testing.T) {
+		log := &provisionLog{}
+		accounts := &fakeProvisionAccounts{deleteErr: errors.New("account has balance"), updateErr: errors.New("forbidden")}
+
+		err := newProvisionEntity(log, accounts).Provision(context.Background(), provisionAll(errStep))
+		require.ErrorIs(t, err, errStep)
+		assert.Equal(t, []string{"delete ledger-1", "delete org-1"}, log.undone)
+
+		var provisionErr *ProvisionError
+		require.ErrorAs(t, err, &provisionErr)
+		require.Error(t, provisionErr.Rollback)
+		assert.Contains(t, err.Error(), "delete account acc-1")
+		assert.ErrorIs(t, err, accounts.updateErr, "rollback errors are members of the error")
+	})
+
+	t.Run("rollback runs after cancellation", func(t *testing.T) {
+		log := &provisionLog{}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var p *Provisioner
+
+		err := newProvisionEntity(log, &fakeProvisionAccounts{}).Provision(ctx, func(ctx context.Context, prov *Provisioner) error {
+			p = prov
+
+			prov.OnRollback("custom", func(ctx context.Context) error {
+				log.undone = append(log.undone, "custom")
+				return ctx.Err()
+			})
+			cancel()
+
+			return ctx.Err()
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []string{"custom"}, log.undone)
+		assert.Zero(t, p.Len())
+	})
+
+	t.Run("panic rolls back and propagates", func(t *testing.T) {
+		log := &provisionLog{}
+		e := newProvisionEntity(log, &fakeProvisionAccounts{})
+
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = e.Provision(context.Background(), func(ctx context.Context, p *Provisioner) error { //nolint:errcheck // panics
+				if _, err := p.CreateOrganization(ctx, models.NewCreateOrganizationInput("Acme")); err != nil {
+					return err
+				}
+
+				panic("boom")
+			})
+		})
+		assert.Equal(t, []string{"delete org-1"}, log.undone)
+	})
+
+	t.Run("missing service", func(t *testing.T) {
+		err := (&Entity{}).Provision(context.Background(), func(ctx context.Context, p *Provisioner) error {
+			_, err := p.CreateAsset(ctx, "org-1", "ledger-1", models.NewCreateAssetInput("US Dollar", "USD"))
+			return err
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/quota"
+)
+
+// WithQuotaEnforcer returns an Option that checks every call made through the
+// services of the Entity against the budget of its tenant in enforcer. Calls
+// over budget fail with a *quota.ExceededError before any request is sent.
+func WithQuotaEnforcer(enforcer *quota.Enforcer) Option {
+	return func(e *Entity) error {
+		e.httpClient.quota = enforcer
+
+		return nil
+	}
+}
+
+// SetQuotaEnforcer sets the enforcer checking the calls of the HTTP client
+// against the budgets of their tenants; nil stops checking.
+func (c *HTTPClient) SetQuotaEnforcer(enforcer *quota.Enforcer) {
+	c.quota = enforcer
+}
+
+// admitQuota checks a call against the budget of its tenant, if the client
+// has a quota enforcer. The returned function ends the call.
+func (c *HTTPClient) admitQuota(ctx context.Context, req *http.Request, method, requestURL string) (func(*http.Response, error), error) {
+	if c.quota == nil {
+		return func(*http.Response, error) {}, nil
+	}
+
+	permit, err := c.quota.Admit(ctx, req.Header.Get(HeaderTenantID), method, requestURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(resp *http.Response, err error) {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+
+		permit.Done(statusCode, err)
+	}, nil
+}
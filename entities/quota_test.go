@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/quota"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithQuotaEnforcer(t *testing.T) {
+	t.Setenv("MIDAZ_ENABLE_RETRIES", "false")
+
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	defer srv.Close()
+
+	enforcer := quota.NewEnforcer().WithBudget("tenant-a", quota.Budget{MaxTPS: 1})
+
+	entity, err := New(srv.URL, WithQuotaEnforcer(enforcer), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	entity.SetHTTPClient(srv.Client())
+
+	tenantA := WithTenantID(context.Background(), "tenant-a")
+
+	_, err = entity.Ledgers.ListLedgers(tenantA, "org-1", nil)
+	require.NoError(t, err)
+
+	_, err = entity.Accounts.ListAccounts(tenantA, "org-1", "ledger-1", nil)
+	require.ErrorIs(t, err, quota.ErrQuotaExceeded, "the budget is shared by the services")
+	assert.Equal(t, int32(1), requests.Load(), "calls over budget are not sent")
+
+	_, err = entity.Ledgers.ListLedgers(WithTenantID(context.Background(), "tenant-b"), "org-1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+}
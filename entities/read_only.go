@@ -0,0 +1,74 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrReadOnly is the sentinel matched by errors.Is for a ReadOnlyError.
+var ErrReadOnly = errors.New("read-only client")
+
+// ReadOnlyError is returned when a read-only client is asked to make a call
+// that could modify the ledger. The call is rejected before any request is
+// sent.
+//
+// Example:
+//
+//	_, err := entity.Transactions.CreateTransaction(ctx, orgID, ledgerID, input)
+//	if errors.Is(err, entities.ErrReadOnly) {
+//	    log.Println("writes are disabled for this service")
+//	}
+type ReadOnlyError struct {
+	// Method is the HTTP method of the rejected request
+	Method string
+
+	// URL is the URL of the rejected request
+	URL string
+}
+
+// Error implements the error interface.
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("%s: %s %s rejected", ErrReadOnly, e.Method, e.URL)
+}
+
+// Is reports whether target is ErrReadOnly.
+func (*ReadOnlyError) Is(target error) bool {
+	return target == ErrReadOnly
+}
+
+// WithReadOnly returns an Option that makes every service of the Entity
+// reject mutating calls (anything but GET, HEAD and OPTIONS) with a
+// ReadOnlyError instead of sending them. It is a safety rail for reporting
+// and analytics code that holds production credentials.
+func WithReadOnly(readOnly bool) Option {
+	return func(e *Entity) error {
+		e.httpClient.readOnly = readOnly
+
+		return nil
+	}
+}
+
+// SetReadOnly makes the HTTP client reject mutating requests.
+func (c *HTTPClient) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
+// IsReadOnly reports whether the HTTP client rejects mutating requests.
+func (c *HTTPClient) IsReadOnly() bool {
+	return c.readOnly
+}
+
+// checkReadOnly returns a ReadOnlyError for a mutating request of a read-only client.
+func (c *HTTPClient) checkReadOnly(method, requestURL string) error {
+	if !c.readOnly {
+		return nil
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+
+	return &ReadOnlyError{Method: method, URL: requestURL}
+}
@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithReadOnly(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	defer srv.Close()
+
+	entity, err := New(srv.URL, WithReadOnly(true), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+	assert.True(t, entity.GetEntityHTTPClient().IsReadOnly(), "read-only mode survives WithHTTPClient")
+
+	entity.SetHTTPClient(srv.Client())
+	assert.True(t, entity.GetEntityHTTPClient().IsReadOnly(), "read-only mode survives SetHTTPClient")
+
+	ctx := context.Background()
+
+	_, err = entity.Organizations.ListOrganizations(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+
+	err = entity.Organizations.DeleteOrganization(ctx, "org-1")
+	require.ErrorIs(t, err, ErrReadOnly)
+
+	var readOnlyErr *ReadOnlyError
+	require.True(t, errors.As(err, &readOnlyErr))
+	assert.Equal(t, http.MethodDelete, readOnlyErr.Method)
+
+	err = entity.Accounts.DeleteAccount(ctx, "org-1", "ledger-1", "acc-1")
+	require.ErrorIs(t, err, ErrReadOnly)
+
+	_, err = entity.Transactions.CommitTransaction(ctx, "org-1", "ledger-1", "tx-1")
+	require.ErrorIs(t, err, ErrReadOnly)
+
+	assert.Equal(t, int32(1), requests.Load(), "rejected calls don't reach the server")
+}
+
+func TestSetReadOnly_SharedWithServices(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	// Set after the services are created: they share the settings of the entity client
+	entity.GetEntityHTTPClient().SetReadOnly(true)
+
+	err = entity.Organizations.DeleteOrganization(context.Background(), "org-1")
+	require.ErrorIs(t, err, ErrReadOnly)
+	assert.Zero(t, requests.Load())
+
+	entity.GetEntityHTTPClient().SetReadOnly(false)
+
+	require.NoError(t, entity.Organizations.DeleteOrganization(context.Background(), "org-1"))
+	assert.Equal(t, int32(1), requests.Load())
+}
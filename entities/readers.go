@@ -0,0 +1,118 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+)
+
+// The reader interfaces are the read-only subsets of the service interfaces.
+// Code that only queries the ledger can depend on them instead of the full
+// services, so its test doubles implement a handful of methods and the
+// compiler rejects writes.
+
+// OrganizationsReader is the read-only subset of OrganizationsService.
+type OrganizationsReader interface {
+	ListOrganizations(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Organization], error)
+	GetOrganization(ctx context.Context, id string) (*models.Organization, error)
+}
+
+// LedgersReader is the read-only subset of LedgersService.
+type LedgersReader interface {
+	ListLedgers(ctx context.Context, organizationID string, opts *models.ListOptions) (*models.ListResponse[models.Ledger], error)
+	GetLedger(ctx context.Context, organizationID, id string) (*models.Ledger, error)
+}
+
+// AccountsReader is the read-only subset of AccountsService.
+type AccountsReader interface {
+	ListAccounts(ctx context.Context, organizationID, ledgerID string, opts *models.ListOptions) (*models.ListResponse[models.Account], error)
+	GetAccount(ctx context.Context, organizationID, ledgerID, id string) (*models.Account, error)
+	GetAccountByAlias(ctx context.Context, organizationID, ledgerID, alias string) (*models.Account, error)
+}
+
+// AssetsReader is the read-only subset of AssetsService.
+type AssetsReader interface {
+	ListAssets(ctx context.Context, organizationID, ledgerID string, opts *models.ListOptions) (*models.ListResponse[models.Asset], error)
+	GetAsset(ctx context.Context, organizationID, ledgerID, id string) (*models.Asset, error)
+}
+
+// BalancesReader is the read-only subset of BalancesService.
+type BalancesReader interface {
+	ListBalances(ctx context.Context, orgID, ledgerID string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error)
+	ListAccountBalances(ctx context.Context, orgID, ledgerID, accountID string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error)
+	GetBalance(ctx context.Context, orgID, ledgerID, balanceID string) (*models.Balance, error)
+	GetMany(ctx context.Context, orgID, ledgerID string, accountIDs []string, opts *GetManyOptions) (map[string]*AccountBalances, error)
+	AsOf(ctx context.Context, orgID, ledgerID, accountID string, at time.Time) ([]models.BalanceHistory, error)
+}
+
+// TransactionsReader is the read-only subset of TransactionsService.
+type TransactionsReader interface {
+	GetTransaction(ctx context.Context, orgID, ledgerID, transactionID string) (*models.Transaction, error)
+	ListTransactions(ctx context.Context, orgID, ledgerID string, opts *models.ListOptions) (*models.ListResponse[models.Transaction], error)
+}
+
+// OperationsReader is the read-only subset of OperationsService.
+type OperationsReader interface {
+	ListOperations(ctx context.Context, orgID, ledgerID, accountID string, opts *models.ListOptions) (*models.ListResponse[models.Operation], error)
+	GetOperation(ctx context.Context, orgID, ledgerID, accountID, operationID string, transactionID ...string) (*models.Operation, error)
+}
+
+// PortfoliosReader is the read-only subset of PortfoliosService.
+type PortfoliosReader interface {
+	ListPortfolios(ctx context.Context, organizationID, ledgerID string, opts *models.ListOptions) (*models.ListResponse[models.Portfolio], error)
+	GetPortfolio(ctx context.Context, organizationID, ledgerID, id string) (*models.Portfolio, error)
+}
+
+// SegmentsReader is the read-only subset of SegmentsService.
+type SegmentsReader interface {
+	ListSegments(ctx context.Context, organizationID, ledgerID string, opts *models.ListOptions) (*models.ListResponse[models.Segment], error)
+	GetSegment(ctx context.Context, organizationID, ledgerID, id string) (*models.Segment, error)
+}
+
+// The services implement their readers.
+var (
+	_ OrganizationsReader = OrganizationsService(nil)
+	_ LedgersReader       = LedgersService(nil)
+	_ AccountsReader      = AccountsService(nil)
+	_ AssetsReader        = AssetsService(nil)
+	_ BalancesReader      = BalancesService(nil)
+	_ TransactionsReader  = TransactionsService(nil)
+	_ OperationsReader    = OperationsService(nil)
+	_ PortfoliosReader    = PortfoliosService(nil)
+	_ SegmentsReader      = SegmentsService(nil)
+)
+
+// ReadOnlyEntity exposes the read-only subset of an Entity's services. Like
+// on the Entity, services left out by WithServices are nil.
+//
+// Example:
+//
+//	reader := entity.ReadOnly()
+//	page, err := reader.Transactions.ListTransactions(ctx, orgID, ledgerID, nil)
+type ReadOnlyEntity struct {
+	Organizations OrganizationsReader
+	Ledgers       LedgersReader
+	Accounts      AccountsReader
+	Assets        AssetsReader
+	Balances      BalancesReader
+	Transactions  TransactionsReader
+	Operations    OperationsReader
+	Portfolios    PortfoliosReader
+	Segments      SegmentsReader
+}
+
+// ReadOnly returns the read-only view of the Entity's services.
+func (e *Entity) ReadOnly() *ReadOnlyEntity {
+	return &ReadOnlyEntity{
+		Organizations: e.Organizations,
+		Ledgers:       e.Ledgers,
+		Accounts:      e.Accounts,
+		Assets:        e.Assets,
+		Balances:      e.Balances,
+		Transactions:  e.Transactions,
+		Operations:    e.Operations,
+		Portfolios:    e.Portfolios,
+		Segments:      e.Segments,
+	}
+}
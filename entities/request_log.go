@@ -0,0 +1,281 @@
+package entities
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// DefaultErrorLogSize is the number of failures kept by an ErrorLog created
+// with a non-positive size.
+const DefaultErrorLogSize = 50
+
+// DefaultActivityLogSize is the number of requests kept by an ActivityLog
+// created with a non-positive size.
+const DefaultActivityLogSize = 100
+
+// ring is a fixed-size buffer keeping the most recent items. It is safe for
+// concurrent use.
+type ring[T any] struct {
+	mu    sync.Mutex
+	items []T
+	next  int
+	full  bool
+}
+
+func newRing[T any](size int) *ring[T] {
+	return &ring[T]{items: make([]T, size)}
+}
+
+// add stores item, evicting the oldest one when the ring is full.
+func (r *ring[T]) add(item T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items[r.next] = item
+	r.next = (r.next + 1) % len(r.items)
+
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// list returns the stored items, oldest first.
+func (r *ring[T]) list() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]T(nil), r.items[:r.next]...)
+	}
+
+	out := make([]T, 0, len(r.items))
+	out = append(out, r.items[r.next:]...)
+
+	return append(out, r.items[:r.next]...)
+}
+
+// ErrorLogEntry is a failed request recorded by an ErrorLog.
+type ErrorLogEntry struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	// Path is the request path; the query string is left out as it may hold filters with personal data
+	Path       string `json:"path"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	RequestID  string `json:"requestId,omitempty"`
+	// Error is the transport error, for requests that got no response
+	Error string `json:"error,omitempty"`
+}
+
+// ErrorLog is a ring buffer of the most recent failed requests of an Entity:
+// responses with a 4xx or 5xx status and requests that got no response. It
+// is safe for concurrent use.
+type ErrorLog struct {
+	ring *ring[ErrorLogEntry]
+}
+
+// NewErrorLog creates an ErrorLog keeping the last size failures.
+func NewErrorLog(size int) *ErrorLog {
+	if size <= 0 {
+		size = DefaultErrorLogSize
+	}
+
+	return &ErrorLog{ring: newRing[ErrorLogEntry](size)}
+}
+
+// Record adds a failure, evicting the oldest one when the log is full.
+func (l *ErrorLog) Record(entry ErrorLogEntry) {
+	l.ring.add(entry)
+}
+
+// Entries returns the recorded failures, oldest first.
+func (l *ErrorLog) Entries() []ErrorLogEntry {
+	return l.ring.list()
+}
+
+// ActivityEntry is a request recorded by an ActivityLog.
+type ActivityEntry struct {
+	Time time.Time `json:"time"`
+	// Operation is the method and route of the request, with identifiers
+	// replaced by {id}, e.g. "POST /v1/organizations/{id}/ledgers"
+	Operation  string        `json:"operation"`
+	Duration   time.Duration `json:"duration"`
+	StatusCode int           `json:"statusCode,omitempty"`
+	// Category is the error category of failed requests, empty on success
+	Category errors.ErrorCategory `json:"category,omitempty"`
+}
+
+// ActivityLog is a ring buffer of the most recent requests of an Entity,
+// successful or not. Each attempt of a retried call is a separate entry. It
+// is safe for concurrent use.
+type ActivityLog struct {
+	ring *ring[ActivityEntry]
+}
+
+// NewActivityLog creates an ActivityLog keeping the last size requests.
+func NewActivityLog(size int) *ActivityLog {
+	if size <= 0 {
+		size = DefaultActivityLogSize
+	}
+
+	return &ActivityLog{ring: newRing[ActivityEntry](size)}
+}
+
+// Record adds a request, evicting the oldest one when the log is full.
+func (l *ActivityLog) Record(entry ActivityEntry) {
+	l.ring.add(entry)
+}
+
+// Entries returns the recorded requests, oldest first.
+func (l *ActivityLog) Entries() []ActivityEntry {
+	return l.ring.list()
+}
+
+// WithErrorLog returns an Option that records the failed requests of every
+// service of the Entity in log.
+func WithErrorLog(log *ErrorLog) Option {
+	return func(e *Entity) error {
+		e.errorLog = log
+
+		return nil
+	}
+}
+
+// WithActivityLog returns an Option that records every request of every
+// service of the Entity in log.
+func WithActivityLog(log *ActivityLog) Option {
+	return func(e *Entity) error {
+		e.activityLog = log
+
+		return nil
+	}
+}
+
+// ErrorLog returns the log of failed requests set with WithErrorLog, or nil.
+func (e *Entity) ErrorLog() *ErrorLog {
+	return e.errorLog
+}
+
+// ActivityLog returns the log of requests set with WithActivityLog, or nil.
+func (e *Entity) ActivityLog() *ActivityLog {
+	return e.activityLog
+}
+
+// recordingTransport records round trips in an ErrorLog and an ActivityLog.
+type recordingTransport struct {
+	base     http.RoundTripper
+	errors   *ErrorLog
+	activity *ActivityLog
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	now := time.Now().UTC()
+
+	var (
+		status    int
+		requestID string
+		category  errors.ErrorCategory
+	)
+
+	switch {
+	case err != nil:
+		category = transportErrorCategory(err)
+	case resp.StatusCode >= http.StatusBadRequest:
+		status = resp.StatusCode
+		requestID = resp.Header.Get("X-Request-ID")
+		category = errors.GetErrorCategory(errors.ErrorFromHTTPResponse(status, requestID, "", "", "", ""))
+	default:
+		status = resp.StatusCode
+	}
+
+	if t.activity != nil {
+		t.activity.Record(ActivityEntry{
+			Time:       now,
+			Operation:  req.Method + " " + routeTemplate(req.URL.Path),
+			Duration:   now.Sub(start),
+			StatusCode: status,
+			Category:   category,
+		})
+	}
+
+	if t.errors != nil && category != "" {
+		entry := ErrorLogEntry{Time: now, Method: req.Method, Path: req.URL.Path, StatusCode: status, RequestID: requestID}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+
+		t.errors.Record(entry)
+	}
+
+	return resp, err
+}
+
+// transportErrorCategory categorizes a request that got no response.
+func transportErrorCategory(err error) errors.ErrorCategory {
+	switch {
+	case stderrors.Is(err, context.Canceled):
+		return errors.CategoryCancellation
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return errors.CategoryTimeout
+	default:
+		return errors.CategoryNetwork
+	}
+}
+
+// identifierSegment matches path segments that identify a resource: UUIDs and numbers.
+var identifierSegment = regexp.MustCompile(`^([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9]+)$`)
+
+// routeTemplate replaces the identifiers and aliases of a request path with {id},
+// so requests of the same operation share a route.
+func routeTemplate(path string) string {
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		if identifierSegment.MatchString(segment) || IsAliasReference(segment) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// servicesHTTPClient returns the http.Client handed to the services, wrapped
+// to record requests when an ErrorLog, ActivityLog or ConnectionTrace is set
+// and to duplicate them when a Shadow is set.
+func (e *Entity) servicesHTTPClient() *http.Client {
+	client := e.httpClient.client
+	if (e.errorLog == nil && e.activityLog == nil && e.connectionTrace == nil && e.shadow == nil) || client == nil {
+		return client
+	}
+
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if e.connectionTrace != nil {
+		transport = &tracingTransport{base: transport, trace: e.connectionTrace}
+	}
+
+	if e.errorLog != nil || e.activityLog != nil {
+		transport = &recordingTransport{base: transport, errors: e.errorLog, activity: e.activityLog}
+	}
+
+	if e.shadow != nil {
+		transport = &shadowTransport{base: transport, shadow: e.shadow, baseURLs: e.baseURLs}
+	}
+
+	wrapped := *client
+	wrapped.Transport = transport
+
+	return &wrapped
+}
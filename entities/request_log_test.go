@@ -0,0 +1,107 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorLogRing(t *testing.T) {
+	log := NewErrorLog(2)
+	assert.Empty(t, log.Entries())
+
+	log.Record(ErrorLogEntry{Path: "/a"})
+	log.Record(ErrorLogEntry{Path: "/b"})
+	log.Record(ErrorLogEntry{Path: "/c"})
+
+	entries := log.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "/b", entries[0].Path)
+	assert.Equal(t, "/c", entries[1].Path)
+}
+
+func TestWithErrorLog(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" || r.Method == http.MethodDelete {
+			w.Header().Set("X-Request-ID", "req-1")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"0007","message":"not found"}`))
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	defer srv.Close()
+
+	log := NewErrorLog(10)
+
+	entity, err := New(srv.URL, WithErrorLog(log), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+	assert.Same(t, log, entity.ErrorLog())
+
+	_, err = entity.Organizations.ListOrganizations(context.Background(), nil)
+	require.NoError(t, err)
+
+	err = entity.Organizations.DeleteOrganization(context.Background(), "org-1")
+	require.Error(t, err)
+
+	entries := log.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, http.MethodDelete, entries[0].Method)
+	assert.Equal(t, http.StatusNotFound, entries[0].StatusCode)
+	assert.Equal(t, "req-1", entries[0].RequestID)
+	assert.Contains(t, entries[0].Path, "org-1")
+
+	// re-initializing wraps the entity client again rather than the wrapper
+	entity.initServices()
+	assert.IsType(t, &recordingTransport{}, entity.servicesHTTPClient().Transport)
+	assert.Same(t, entity.servicesHTTPClient().Transport.(*recordingTransport).base, srv.Client().Transport)
+}
+
+func TestWithActivityLog(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	defer srv.Close()
+
+	log := NewActivityLog(10)
+
+	entity, err := New(srv.URL, WithActivityLog(log), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	_, err = entity.Accounts.ListAccounts(context.Background(), "0190b6c4-3a77-7a2e-8f55-6c0a1f4f2b10", "42", nil)
+	require.NoError(t, err)
+
+	err = entity.Organizations.DeleteOrganization(context.Background(), "0190b6c4-3a77-7a2e-8f55-6c0a1f4f2b10")
+	require.Error(t, err)
+
+	entries := entity.ActivityLog().Entries()
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "GET /organizations/{id}/ledgers/{id}/accounts", entries[0].Operation)
+	assert.Equal(t, http.StatusOK, entries[0].StatusCode)
+	assert.Empty(t, entries[0].Category)
+
+	assert.Equal(t, "DELETE /organizations/{id}", entries[1].Operation)
+	assert.Equal(t, http.StatusConflict, entries[1].StatusCode)
+	assert.Equal(t, sdkerrors.CategoryConflict, entries[1].Category)
+}
+
+func TestRouteTemplate(t *testing.T) {
+	assert.Equal(t, "/v1/organizations/{id}/ledgers/{id}/accounts/alias/{id}",
+		routeTemplate("/v1/organizations/0190b6c4-3a77-7a2e-8f55-6c0a1f4f2b10/ledgers/12/accounts/alias/@alice"))
+	assert.Equal(t, "/v1/organizations", routeTemplate("/v1/organizations"))
+}
@@ -0,0 +1,140 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultCapturedHeaders are the response headers captured into the
+// ResponseMeta of a call unless WithCapturedHeaders or WithResultMeta names
+// others: the request ID, rate limit information and server timing.
+var DefaultCapturedHeaders = []string{
+	"X-Request-ID",
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+	"Retry-After",
+	"Server-Timing",
+}
+
+// ResponseMeta describes the last response received by a call made with a
+// context from WithResultMeta, for support escalations.
+type ResponseMeta struct {
+	StatusCode int    `json:"statusCode"`
+	RequestID  string `json:"requestId,omitempty"`
+
+	// Headers holds the captured headers present in the response
+	Headers http.Header `json:"headers,omitempty"`
+
+	// Attempts counts the responses received, more than one when the call was
+	// retried; the metadata is the one of the last response
+	Attempts   int       `json:"attempts"`
+	ReceivedAt time.Time `json:"receivedAt"`
+}
+
+// Header returns the first value of a captured header, or "".
+func (m ResponseMeta) Header(name string) string {
+	return m.Headers.Get(name)
+}
+
+type contextKeyResultMeta struct{}
+
+// resultMetaHolder receives the response metadata of the calls made with a context
+type resultMetaHolder struct {
+	mu       sync.Mutex
+	headers  []string
+	meta     ResponseMeta
+	received bool
+}
+
+// WithResultMeta returns a context recording the metadata of the responses of
+// the calls made with it, read back with ResultMeta. headers replaces the
+// headers captured for these calls; by default the ones set with
+// WithCapturedHeaders, or DefaultCapturedHeaders, are captured.
+//
+// Example:
+//
+//	ctx = entities.WithResultMeta(ctx)
+//	tx, err := entity.Transactions.CreateTransaction(ctx, orgID, ledgerID, input)
+//	if meta, ok := entities.ResultMeta(ctx); ok {
+//	    log.Printf("request %s, %s requests left", meta.RequestID, meta.Header("X-RateLimit-Remaining"))
+//	}
+func WithResultMeta(ctx context.Context, headers ...string) context.Context {
+	return context.WithValue(ctx, contextKeyResultMeta{}, &resultMetaHolder{headers: headers})
+}
+
+// ResultMeta returns the metadata of the last response received by a call
+// made with ctx. It returns false when ctx doesn't come from WithResultMeta or
+// no response was received, e.g. when the request failed to connect. A
+// context shared by concurrent calls holds the metadata of whichever response
+// came last.
+func ResultMeta(ctx context.Context) (ResponseMeta, bool) {
+	holder, ok := ctx.Value(contextKeyResultMeta{}).(*resultMetaHolder)
+	if !ok {
+		return ResponseMeta{}, false
+	}
+
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+
+	meta := holder.meta
+	meta.Headers = holder.meta.Headers.Clone()
+
+	return meta, holder.received
+}
+
+// WithCapturedHeaders returns an Option that sets the response headers
+// captured by every service of the Entity into the ResponseMeta of calls
+// made with a context from WithResultMeta, instead of DefaultCapturedHeaders.
+func WithCapturedHeaders(headers ...string) Option {
+	return func(e *Entity) error {
+		e.httpClient.SetCapturedHeaders(headers)
+
+		return nil
+	}
+}
+
+// SetCapturedHeaders sets the response headers captured into the
+// ResponseMeta of calls; nil restores DefaultCapturedHeaders.
+func (c *HTTPClient) SetCapturedHeaders(headers []string) {
+	c.capturedHeaders = headers
+}
+
+// captureResponseMeta records resp in the ResponseMeta of ctx, if any.
+func (c *HTTPClient) captureResponseMeta(ctx context.Context, resp *http.Response) {
+	holder, ok := ctx.Value(contextKeyResultMeta{}).(*resultMetaHolder)
+	if !ok {
+		return
+	}
+
+	names := holder.headers
+	if len(names) == 0 {
+		names = c.capturedHeaders
+	}
+
+	if len(names) == 0 {
+		names = DefaultCapturedHeaders
+	}
+
+	headers := http.Header{}
+
+	for _, name := range names {
+		if values := resp.Header.Values(name); len(values) > 0 {
+			headers[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+		}
+	}
+
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+
+	holder.meta = ResponseMeta{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+		Headers:    headers,
+		Attempts:   holder.meta.Attempts + 1,
+		ReceivedAt: time.Now().UTC(),
+	}
+	holder.received = true
+}
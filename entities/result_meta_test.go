@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultMeta(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "req-42")
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		w.Header().Add("Server-Timing", "db;dur=12")
+		w.Header().Add("Server-Timing", "app;dur=3")
+		w.Header().Set("X-Tenant-Region", "sa-east-1")
+
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	defer srv.Close()
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	_, ok := ResultMeta(context.Background())
+	assert.False(t, ok, "no metadata without WithResultMeta")
+
+	ctx := WithResultMeta(context.Background())
+
+	_, ok = ResultMeta(ctx)
+	assert.False(t, ok, "no metadata before a response")
+
+	_, err = entity.Organizations.ListOrganizations(ctx, nil)
+	require.NoError(t, err)
+
+	meta, ok := ResultMeta(ctx)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusOK, meta.StatusCode)
+	assert.Equal(t, "req-42", meta.RequestID)
+	assert.Equal(t, "99", meta.Header("X-RateLimit-Remaining"))
+	assert.Equal(t, []string{"db;dur=12", "app;dur=3"}, meta.Headers.Values("Server-Timing"))
+	assert.Empty(t, meta.Header("X-Tenant-Region"), "only the default headers are captured")
+	assert.Equal(t, 1, meta.Attempts)
+
+	// failed calls keep the metadata of their error response
+	ctx = WithResultMeta(context.Background(), "x-tenant-region")
+
+	require.Error(t, entity.Organizations.DeleteOrganization(ctx, "org-1"))
+
+	meta, ok = ResultMeta(ctx)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, meta.StatusCode)
+	assert.Equal(t, "req-42", meta.RequestID)
+	assert.Equal(t, http.Header{"X-Tenant-Region": {"sa-east-1"}}, meta.Headers)
+}
+
+func TestWithCapturedHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Request-ID", "req-7")
+		w.Header().Set("X-Tenant-Region", "sa-east-1")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	defer srv.Close()
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()), WithCapturedHeaders("X-Tenant-Region"))
+	require.NoError(t, err)
+
+	ctx := WithResultMeta(context.Background())
+
+	_, err = entity.Transactions.ListTransactions(ctx, "org-1", "ledger-1", nil)
+	require.NoError(t, err)
+
+	meta, ok := ResultMeta(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "sa-east-1", meta.Header("X-Tenant-Region"))
+	assert.Empty(t, meta.Header("X-Request-ID"), "the configured headers replace the defaults")
+	assert.Equal(t, "req-7", meta.RequestID, "the request ID is always reported")
+}
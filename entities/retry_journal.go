@@ -0,0 +1,371 @@
+package entities
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/idgen"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
+)
+
+// RetryJournalEntry records a call given up on after its retries, with what
+// is needed to send it again once the incident is resolved (see
+// Entity.Redrive). It never holds the authorization token.
+type RetryJournalEntry struct {
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+
+	// Method and URL are those of the request
+	Method string `json:"method"`
+	URL    string `json:"url"`
+
+	// Headers are the idempotency key and tenant ID headers of the request
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// ContentType is the content type of Input
+	ContentType string `json:"contentType,omitempty"`
+
+	// Input is the request body: the body itself when it is JSON, a JSON
+	// string holding it otherwise
+	Input json.RawMessage `json:"input,omitempty"`
+
+	// Attempts is the number of times the request was sent
+	Attempts int `json:"attempts"`
+
+	// Errors are the errors of the attempts, the oldest first
+	Errors []string `json:"errors"`
+
+	// Hints suggest what to check before re-driving the call
+	Hints []string `json:"hints,omitempty"`
+}
+
+// body returns the request body of the entry.
+func (e *RetryJournalEntry) body() ([]byte, error) {
+	if len(e.Input) == 0 {
+		return nil, nil
+	}
+
+	if strings.Contains(e.ContentType, "json") {
+		return e.Input, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(e.Input, &s); err != nil {
+		return nil, fmt.Errorf("failed to decode journal entry input: %w", err)
+	}
+
+	return []byte(s), nil
+}
+
+// RetryJournal receives the calls given up on after their retries. Record is
+// called from the goroutine of the call, after it failed; its error is only
+// logged in debug mode.
+type RetryJournal interface {
+	Record(ctx context.Context, entry RetryJournalEntry) error
+}
+
+// RetryJournalFunc adapts a function to the RetryJournal interface.
+type RetryJournalFunc func(ctx context.Context, entry RetryJournalEntry) error
+
+// Record calls f.
+func (f RetryJournalFunc) Record(ctx context.Context, entry RetryJournalEntry) error {
+	return f(ctx, entry)
+}
+
+// MemoryRetryJournal keeps the journal entries in memory. It is safe for
+// concurrent use.
+type MemoryRetryJournal struct {
+	mu      sync.Mutex
+	entries []RetryJournalEntry
+}
+
+// NewMemoryRetryJournal returns an empty in-memory journal.
+func NewMemoryRetryJournal() *MemoryRetryJournal {
+	return &MemoryRetryJournal{}
+}
+
+// Record adds the entry to the journal.
+func (j *MemoryRetryJournal) Record(_ context.Context, entry RetryJournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries = append(j.entries, entry)
+
+	return nil
+}
+
+// Entries returns the entries of the journal, the oldest first.
+func (j *MemoryRetryJournal) Entries() []RetryJournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return slices.Clone(j.entries)
+}
+
+// Remove removes the entries with the given IDs, such as those re-driven.
+func (j *MemoryRetryJournal) Remove(ids ...string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries = slices.DeleteFunc(j.entries, func(e RetryJournalEntry) bool {
+		return slices.Contains(ids, e.ID)
+	})
+}
+
+// JSONLinesRetryJournal writes the journal entries to a writer, such as an
+// append-only file, one JSON object per line. Read them back with
+// ReadRetryJournal. It is safe for concurrent use.
+type JSONLinesRetryJournal struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLinesRetryJournal returns a journal writing its entries to w.
+func NewJSONLinesRetryJournal(w io.Writer) *JSONLinesRetryJournal {
+	return &JSONLinesRetryJournal{w: w}
+}
+
+// Record writes the entry as a line of JSON.
+func (j *JSONLinesRetryJournal) Record(_ context.Context, entry RetryJournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, err := j.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+
+	return nil
+}
+
+// ReadRetryJournal reads the entries written by a JSONLinesRetryJournal.
+// Blank lines are skipped.
+func ReadRetryJournal(r io.Reader) ([]RetryJournalEntry, error) {
+	var entries []RetryJournalEntry
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var entry RetryJournalEntry
+		if err := json.Unmarshal([]byte(text), &entry); err != nil {
+			return entries, fmt.Errorf("failed to decode journal line %d: %w", line, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	return entries, nil
+}
+
+// WithRetryJournal returns an Option that records in journal the calls made
+// through the services of the Entity that fail with an error retrying could
+// have fixed, such as a timeout or a 503, once their retries are used up.
+//
+// Example:
+//
+//	f, _ := os.OpenFile("midaz-retries.jsonl", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+//	entity, err := entities.New(baseURL, entities.WithRetryJournal(entities.NewJSONLinesRetryJournal(f)))
+func WithRetryJournal(journal RetryJournal) Option {
+	return func(e *Entity) error {
+		e.httpClient.retryJournal = journal
+
+		return nil
+	}
+}
+
+// SetRetryJournal sets the journal recording the calls of the HTTP client
+// given up on after their retries; nil stops recording them.
+func (c *HTTPClient) SetRetryJournal(journal RetryJournal) {
+	c.retryJournal = journal
+}
+
+type contextKeyRedrive struct{}
+
+// recordRetryJournal records a call given up on after its retries in the
+// retry journal, if any. Calls failing with other errors, and re-driven
+// calls, which stay in the journal they come from, are not recorded.
+func (c *HTTPClient) recordRetryJournal(ctx context.Context, req *http.Request, meter *callMeter, err error) {
+	if c.retryJournal == nil || err == nil || ctx.Value(contextKeyRedrive{}) != nil {
+		return
+	}
+
+	if !retry.IsRetryableError(err, c.retryOpts()) && !retry.IsDeadlineExceededError(err) {
+		return
+	}
+
+	entry := RetryJournalEntry{
+		ID:       idgen.New(),
+		Time:     time.Now().UTC(),
+		Method:   req.Method,
+		URL:      req.URL.String(),
+		Attempts: meter.requests,
+		Errors:   slices.Clone(meter.failures),
+	}
+
+	for _, name := range []string{"X-Idempotency", HeaderTenantID} {
+		if v := req.Header.Get(name); v != "" {
+			if entry.Headers == nil {
+				entry.Headers = map[string]string{}
+			}
+
+			entry.Headers[name] = v
+		}
+	}
+
+	if req.GetBody != nil {
+		if body, bodyErr := readRequestBody(req); bodyErr == nil && len(body) > 0 {
+			entry.ContentType = req.Header.Get("Content-Type")
+			entry.Input = journalInput(entry.ContentType, body)
+		}
+	}
+
+	if len(entry.Errors) == 0 {
+		entry.Errors = append(entry.Errors, err.Error())
+	}
+
+	entry.Hints = retryJournalHints(&entry, err)
+
+	if recordErr := c.retryJournal.Record(context.WithoutCancel(ctx), entry); recordErr != nil {
+		c.debugLog("Failed to record %s %s in the retry journal: %v", req.Method, req.URL, recordErr)
+	}
+}
+
+// readRequestBody returns a fresh copy of the body of req.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+// journalInput encodes a request body as the Input of a journal entry.
+func journalInput(contentType string, body []byte) json.RawMessage {
+	if strings.Contains(contentType, "json") && json.Valid(body) {
+		return body
+	}
+
+	encoded, _ := json.Marshal(string(body))
+
+	return encoded
+}
+
+// retryJournalHints suggests what to check before re-driving a call failing
+// with err.
+func retryJournalHints(entry *RetryJournalEntry, err error) []string {
+	var hints []string
+
+	host := entry.URL
+	if u, parseErr := url.Parse(entry.URL); parseErr == nil {
+		host = u.Host
+	}
+
+	switch {
+	case sdkerrors.IsRateLimitError(err) || strings.Contains(strings.ToLower(err.Error()), "too many requests"):
+		hints = append(hints, "The API kept rate limiting the call: re-drive it at a lower rate.")
+	case retry.IsDeadlineExceededError(err) || sdkerrors.IsTimeoutError(err):
+		hints = append(hints, "The call kept timing out: check the latency of "+host+" and the deadline of the caller.")
+	case sdkerrors.IsNetworkError(err) || strings.Contains(err.Error(), "HTTP request failed"):
+		hints = append(hints, "The API was unreachable: check the connectivity to "+host+".")
+	default:
+		hints = append(hints, "The API kept failing: check the health of the Midaz services at "+host+".")
+	}
+
+	switch {
+	case entry.Method == http.MethodGet || entry.Method == http.MethodHead:
+		hints = append(hints, "The call only reads data and is safe to re-drive.")
+	case entry.Headers["X-Idempotency"] != "":
+		hints = append(hints, "The call carries an idempotency key and is safe to re-drive.")
+	default:
+		hints = append(hints, "The call has no idempotency key and may have taken effect: check for it before re-driving, or it may apply twice.")
+	}
+
+	return hints
+}
+
+// Redrive sends the request of a journal entry again, with its idempotency
+// key and tenant ID, through the HTTP client of the Entity, and decodes the
+// response into result, if not nil. A re-driven call failing again returns
+// its error and isn't recorded in the retry journal.
+//
+// Example:
+//
+//	entries, err := entities.ReadRetryJournal(f)
+//	for _, entry := range entries {
+//	    if err := entity.Redrive(ctx, entry, nil); err != nil {
+//	        log.Printf("%s %s still failing: %v", entry.Method, entry.URL, err)
+//	    }
+//	}
+func (e *Entity) Redrive(ctx context.Context, entry RetryJournalEntry, result any) error {
+	if entry.Method == "" || entry.URL == "" {
+		return sdkerrors.NewMissingParameterError("Redrive", "method and url")
+	}
+
+	body, err := entry.body()
+	if err != nil {
+		return err
+	}
+
+	headers := make(map[string]string, len(entry.Headers)+1)
+	for k, v := range entry.Headers {
+		headers[k] = v
+	}
+
+	if len(body) > 0 {
+		headers["Content-Type"] = entry.ContentType
+	}
+
+	ctx = context.WithValue(ctx, contextKeyRedrive{}, true)
+
+	return e.httpClient.doRawRequest(ctx, entry.Method, entry.URL, headers, body, result)
+}
+
+// RedriveResult is the outcome of re-driving a journal entry.
+type RedriveResult struct {
+	Entry RetryJournalEntry
+
+	// Err is the error of the re-driven call, nil when it succeeded
+	Err error
+}
+
+// RedriveRetryJournal re-drives the entries in order, stopping early only
+// when ctx ends, and returns the outcome of each re-driven entry.
+func (e *Entity) RedriveRetryJournal(ctx context.Context, entries []RetryJournalEntry) []RedriveResult {
+	results := make([]RedriveResult, 0, len(entries))
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		results = append(results, RedriveResult{Entry: entry, Err: e.Redrive(ctx, entry, nil)})
+	}
+
+	return results
+}
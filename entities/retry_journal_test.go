@@ -0,0 +1,130 @@
+package entities
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRetryJournal(t *testing.T) {
+	var (
+		down     atomic.Bool
+		requests atomic.Int32
+		bodies   = make(chan string, 10)
+	)
+
+	down.Store(true)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"code":"0503","message":"service unavailable"}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"id":"ledger-1","name":"Main"}`))
+	}))
+	defer srv.Close()
+
+	journal := NewMemoryRetryJournal()
+	options := retry.DefaultOptions()
+	options.MaxRetries = 1
+	options.InitialDelay = time.Millisecond
+
+	entity, err := New(srv.URL, WithRetryJournal(journal), WithRetryOptions(options), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	ctx := WithIdempotencyKey(context.Background(), "key-1")
+
+	_, err = entity.Ledgers.CreateLedger(ctx, "org-1", models.NewCreateLedgerInput("Main"))
+	require.Error(t, err)
+	require.Equal(t, int32(2), requests.Load())
+
+	entries := journal.Entries()
+	require.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.NotEmpty(t, entry.ID)
+	assert.Equal(t, http.MethodPost, entry.Method)
+	assert.Equal(t, srv.URL+"/organizations/org-1/ledgers", entry.URL)
+	assert.Equal(t, "key-1", entry.Headers["X-Idempotency"])
+	assert.JSONEq(t, <-bodies, string(entry.Input))
+	assert.Equal(t, 2, entry.Attempts)
+	assert.Len(t, entry.Errors, 2)
+	assert.Contains(t, entry.Hints, "The call carries an idempotency key and is safe to re-drive.")
+
+	_, err = entity.Ledgers.GetLedger(context.Background(), "org-1", "missing")
+	require.Error(t, err)
+	assert.Len(t, journal.Entries(), 2, "failed reads are recorded too")
+
+	journal.Remove(journal.Entries()[1].ID)
+
+	var buf bytes.Buffer
+	require.NoError(t, NewJSONLinesRetryJournal(&buf).Record(context.Background(), entry))
+
+	read, err := ReadRetryJournal(&buf)
+	require.NoError(t, err)
+	require.Len(t, read, 1)
+
+	results := entity.RedriveRetryJournal(context.Background(), read)
+	require.Len(t, results, 1)
+	require.Error(t, results[0].Err)
+	assert.Len(t, journal.Entries(), 1, "re-driven calls are not recorded again")
+
+	down.Store(false)
+
+	for len(bodies) > 0 {
+		<-bodies
+	}
+
+	var ledger models.Ledger
+	require.NoError(t, entity.Redrive(context.Background(), read[0], &ledger))
+	assert.Equal(t, "ledger-1", ledger.ID)
+	assert.JSONEq(t, string(entry.Input), <-bodies)
+}
+
+func TestRetryJournal_SkipsPermanentErrors(t *testing.T) {
+	t.Setenv("MIDAZ_ENABLE_RETRIES", "false")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":"0009","message":"invalid input"}`))
+	}))
+	defer srv.Close()
+
+	journal := NewMemoryRetryJournal()
+
+	entity, err := New(srv.URL, WithRetryJournal(journal), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	_, err = entity.Ledgers.CreateLedger(context.Background(), "org-1", models.NewCreateLedgerInput("Main"))
+	require.Error(t, err)
+	assert.Empty(t, journal.Entries())
+}
+
+func TestRetryJournalEntry_RawInput(t *testing.T) {
+	entry := RetryJournalEntry{ContentType: "text/plain", Input: journalInput("text/plain", []byte("(transaction V1)"))}
+
+	body, err := entry.body()
+	require.NoError(t, err)
+	assert.Equal(t, "(transaction V1)", string(body))
+}
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"errors"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
+)
+
+// WithRetryOptions returns an Option that sets the retry policy of the calls
+// made through the services of the Entity, instead of the one read from
+// MIDAZ_MAX_RETRIES and MIDAZ_ENABLE_RETRIES. The options are shared by the
+// services and must not be changed afterwards.
+func WithRetryOptions(options *retry.Options) Option {
+	return func(e *Entity) error {
+		if options == nil {
+			return errors.New("retry options cannot be nil")
+		}
+
+		e.httpClient.SetRetryOptions(options)
+
+		return nil
+	}
+}
+
+// SetRetryOptions sets the retry policy of the calls of the HTTP client and
+// of the clients sharing its settings. It takes precedence over the options
+// set with WithRetryOptions and WithRetryOption on each client.
+func (c *HTTPClient) SetRetryOptions(options *retry.Options) {
+	if options != nil {
+		c.retryPolicy = options
+	}
+}
+
+// retryOpts returns the retry options of the calls of the HTTP client.
+func (c *HTTPClient) retryOpts() *retry.Options {
+	if c.retryPolicy != nil {
+		return c.retryPolicy
+	}
+
+	return c.retryOptions
+}
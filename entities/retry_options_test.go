@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRetryOptions(t *testing.T) {
+	// The environment disables retries, the option wins
+	t.Setenv("MIDAZ_ENABLE_RETRIES", "false")
+
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"code":"0000","message":"service unavailable"}`))
+	}))
+	defer srv.Close()
+
+	options := retry.DefaultOptions()
+	require.NoError(t, retry.WithMaxRetries(2)(options))
+	require.NoError(t, retry.WithInitialDelay(time.Millisecond)(options))
+	require.NoError(t, retry.WithJitterFactor(0)(options))
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()), WithRetryOptions(options))
+	require.NoError(t, err)
+
+	_, err = entity.Organizations.GetOrganization(context.Background(), "org-1")
+	require.Error(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+
+	// Without the option the environment applies
+	requests.Store(0)
+
+	entity, err = New(srv.URL, WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	_, err = entity.Organizations.GetOrganization(context.Background(), "org-1")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+
+	_, err = New(srv.URL, WithRetryOptions(nil))
+	require.Error(t, err)
+}
@@ -0,0 +1,420 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DriftKind is the kind of a DriftField
+type DriftKind string
+
+const (
+	// DriftUnknown is a field returned by the API that the model doesn't have,
+	// so its value is lost when the response is decoded
+	DriftUnknown DriftKind = "unknown"
+	// DriftMissing is a required model field the API didn't return
+	DriftMissing DriftKind = "missing"
+)
+
+// SchemaDriftOptions configures WithSchemaDriftCheck.
+type SchemaDriftOptions struct {
+	// OnDrift receives the drift of every response whose fields differ from
+	// its model, from the goroutine of the call
+	OnDrift func(SchemaDrift)
+
+	// PreserveUnknown keeps the raw value of the unknown fields in SchemaDrift.Extra
+	PreserveUnknown bool
+
+	// ReportMissing also reports the model fields the API didn't return;
+	// fields that are pointers or tagged omitempty are optional and never reported
+	ReportMissing bool
+}
+
+// SchemaDrift describes how a response differs from the model it was
+// decoded into. Paths write array indices as [*], e.g. "items[*].newField".
+type SchemaDrift struct {
+	Time time.Time `json:"time"`
+	// Operation is the method and route of the request, with identifiers replaced by {id}
+	Operation string `json:"operation"`
+	// Type is the Go type the response was decoded into
+	Type    string   `json:"type"`
+	Unknown []string `json:"unknown,omitempty"`
+	Missing []string `json:"missing,omitempty"`
+	// Extra holds the raw value of each unknown field by its exact path, e.g.
+	// "items[3].newField", when SchemaDriftOptions.PreserveUnknown is set
+	Extra map[string]json.RawMessage `json:"extra,omitempty"`
+}
+
+// DriftField is a field found drifting by a SchemaDriftLog.
+type DriftField struct {
+	Kind DriftKind `json:"kind"`
+	Type string    `json:"type"`
+	Path string    `json:"path"`
+	// Operation is the operation whose response first showed the drift
+	Operation string `json:"operation"`
+	// Count is the number of responses that showed the drift
+	Count     int64     `json:"count"`
+	FirstSeen time.Time `json:"firstSeen"`
+}
+
+// SchemaDriftLog checks the responses of the services against their models
+// and keeps each drifting field once, with the number of responses that
+// showed it. It is safe for concurrent use.
+type SchemaDriftLog struct {
+	options SchemaDriftOptions
+
+	mu     sync.Mutex
+	fields map[driftKey]*DriftField
+}
+
+type driftKey struct {
+	kind DriftKind
+	typ  string
+	path string
+}
+
+// NewSchemaDriftLog creates an empty SchemaDriftLog.
+func NewSchemaDriftLog(opts SchemaDriftOptions) *SchemaDriftLog {
+	return &SchemaDriftLog{options: opts, fields: make(map[driftKey]*DriftField)}
+}
+
+// Fields returns the drifting fields found so far, sorted by type, kind and path.
+func (l *SchemaDriftLog) Fields() []DriftField {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fields := make([]DriftField, 0, len(l.fields))
+	for _, f := range l.fields {
+		fields = append(fields, *f)
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+
+		if a.Kind != b.Kind {
+			return a.Kind > b.Kind
+		}
+
+		return a.Path < b.Path
+	})
+
+	return fields
+}
+
+// Check compares a response body with the model it was decoded into and
+// records the drift, if any. It returns false when the fields match.
+func (l *SchemaDriftLog) Check(operation string, result any, body []byte) (SchemaDrift, bool) {
+	t := reflect.TypeOf(result)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil {
+		return SchemaDrift{}, false
+	}
+
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return SchemaDrift{}, false
+	}
+
+	w := &driftWalker{preserve: l.options.PreserveUnknown, reportMissing: l.options.ReportMissing}
+	w.walk(t, decoded, "", "")
+
+	if len(w.unknown) == 0 && len(w.missing) == 0 {
+		return SchemaDrift{}, false
+	}
+
+	drift := SchemaDrift{
+		Time:      time.Now().UTC(),
+		Operation: operation,
+		Type:      t.String(),
+		Unknown:   w.unknownPaths(),
+		Missing:   w.missingPaths(),
+		Extra:     w.extra,
+	}
+
+	l.record(drift)
+
+	if l.options.OnDrift != nil {
+		l.options.OnDrift(drift)
+	}
+
+	return drift, true
+}
+
+// record counts the fields of a drift.
+func (l *SchemaDriftLog) record(drift SchemaDrift) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	add := func(kind DriftKind, path string) {
+		key := driftKey{kind, drift.Type, path}
+
+		f, ok := l.fields[key]
+		if !ok {
+			f = &DriftField{Kind: kind, Type: drift.Type, Path: path, Operation: drift.Operation, FirstSeen: drift.Time}
+			l.fields[key] = f
+		}
+
+		f.Count++
+	}
+
+	for _, path := range drift.Unknown {
+		add(DriftUnknown, path)
+	}
+
+	for _, path := range drift.Missing {
+		add(DriftMissing, path)
+	}
+}
+
+// modelField is a JSON field of a model.
+type modelField struct {
+	name     string
+	typ      reflect.Type
+	optional bool
+}
+
+// modelFields caches the JSON fields of each model type.
+var modelFields sync.Map // reflect.Type -> []modelField
+
+var jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+
+// fieldsOf returns the JSON fields of a struct type, with the fields of
+// embedded structs promoted as encoding/json does.
+func fieldsOf(t reflect.Type) []modelField {
+	if cached, ok := modelFields.Load(t); ok {
+		return cached.([]modelField)
+	}
+
+	var fields []modelField
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := sf.Type
+		if sf.Anonymous && name == "" {
+			embedded := ft
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, fieldsOf(embedded)...)
+				continue
+			}
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		optional := ft.Kind() == reflect.Pointer || strings.Contains(","+opts+",", ",omitempty,") || strings.Contains(","+opts+",", ",omitzero,")
+		fields = append(fields, modelField{name: name, typ: ft, optional: optional})
+	}
+
+	modelFields.Store(t, fields)
+
+	return fields
+}
+
+// driftWalker walks a decoded response along its model type.
+type driftWalker struct {
+	preserve      bool
+	reportMissing bool
+	unknownSet    map[string]bool
+	missingSet    map[string]bool
+	unknown       []string
+	missing       []string
+	extra         map[string]json.RawMessage
+}
+
+// walk compares v with type t at path; pattern is the path with indices written [*].
+func (w *driftWalker) walk(t reflect.Type, v any, path, pattern string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if v == nil || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return
+		}
+
+		w.walkStruct(t, obj, path, pattern)
+	case reflect.Slice, reflect.Array:
+		items, ok := v.([]any)
+		if !ok {
+			return
+		}
+
+		for i, item := range items {
+			w.walk(t.Elem(), item, fmt.Sprintf("%s[%d]", path, i), pattern+"[*]")
+		}
+	}
+}
+
+// walkStruct compares the keys of obj with the fields of struct type t.
+func (w *driftWalker) walkStruct(t reflect.Type, obj map[string]any, path, pattern string) {
+	fields := fieldsOf(t)
+	matched := make(map[string]bool, len(obj))
+
+	for _, f := range fields {
+		key, ok := matchKey(obj, f.name)
+		if !ok {
+			if w.reportMissing && !f.optional {
+				w.addMissing(joinDriftPath(pattern, f.name))
+			}
+
+			continue
+		}
+
+		matched[key] = true
+		w.walk(f.typ, obj[key], joinDriftPath(path, key), joinDriftPath(pattern, key))
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		if !matched[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		w.addUnknown(joinDriftPath(pattern, key))
+
+		if w.preserve {
+			if w.extra == nil {
+				w.extra = make(map[string]json.RawMessage)
+			}
+
+			if raw, err := json.Marshal(obj[key]); err == nil {
+				w.extra[joinDriftPath(path, key)] = raw
+			}
+		}
+	}
+}
+
+// matchKey finds the key of a field in obj, preferring an exact match and
+// else matching case-insensitively, as encoding/json does.
+func matchKey(obj map[string]any, name string) (string, bool) {
+	if _, ok := obj[name]; ok {
+		return name, true
+	}
+
+	for key := range obj {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+func (w *driftWalker) addUnknown(path string) {
+	if w.unknownSet == nil {
+		w.unknownSet = make(map[string]bool)
+	}
+
+	if !w.unknownSet[path] {
+		w.unknownSet[path] = true
+		w.unknown = append(w.unknown, path)
+	}
+}
+
+func (w *driftWalker) addMissing(path string) {
+	if w.missingSet == nil {
+		w.missingSet = make(map[string]bool)
+	}
+
+	if !w.missingSet[path] {
+		w.missingSet[path] = true
+		w.missing = append(w.missing, path)
+	}
+}
+
+func (w *driftWalker) unknownPaths() []string {
+	sort.Strings(w.unknown)
+	return w.unknown
+}
+
+func (w *driftWalker) missingPaths() []string {
+	sort.Strings(w.missing)
+	return w.missing
+}
+
+func joinDriftPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// WithSchemaDriftCheck returns an Option that compares the JSON fields of
+// every response of the services with the model it is decoded into, so that
+// fields added by a newer backend, whose values the SDK silently drops, are
+// caught. Drift is reported to opts.OnDrift and collected in the
+// SchemaDriftLog returned by Entity.SchemaDrift. The check decodes each
+// response a second time, so enable it in test and staging environments or
+// on a sample of clients.
+func WithSchemaDriftCheck(opts SchemaDriftOptions) Option {
+	return func(e *Entity) error {
+		e.httpClient.schemaDrift = NewSchemaDriftLog(opts)
+
+		return nil
+	}
+}
+
+// SchemaDrift returns the log set with WithSchemaDriftCheck, or nil.
+func (e *Entity) SchemaDrift() *SchemaDriftLog {
+	return e.httpClient.schemaDrift
+}
+
+// SetSchemaDriftLog sets the log checking the responses of the HTTP client;
+// nil disables the check.
+func (c *HTTPClient) SetSchemaDriftLog(log *SchemaDriftLog) {
+	c.schemaDrift = log
+}
+
+// checkSchemaDrift checks a decoded response when a SchemaDriftLog is set.
+func (c *HTTPClient) checkSchemaDrift(method, requestURL string, result any, responseBody []byte) {
+	if c.schemaDrift == nil || result == nil || len(responseBody) == 0 {
+		return
+	}
+
+	path := requestURL
+	if u, err := url.Parse(requestURL); err == nil {
+		path = u.Path
+	}
+
+	c.schemaDrift.Check(method+" "+routeTemplate(path), result, responseBody)
+}
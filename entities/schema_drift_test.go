@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const driftOrganization = `{
+	"id": "019c96a0-0000-7000-8000-000000000001",
+	"legalName": "Acme",
+	"legalDocument": "123",
+	"address": {"line1": "Main St", "zipCode": "1", "city": "X", "state": "Y", "country": "BR", "geo": {"lat": 1}},
+	"status": {"code": "ACTIVE"},
+	"metadata": {"anything": true},
+	"createdAt": "2026-01-01T00:00:00Z",
+	"updatedAt": "2026-01-01T00:00:00Z",
+	"riskScore": 7
+}`
+
+func TestWithSchemaDriftCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/organizations" {
+			_, _ = w.Write([]byte(`{"items":[` + driftOrganization + `,{"id":"2","tier":"gold"}],"limit":10,"page":1}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(driftOrganization))
+	}))
+	defer srv.Close()
+
+	var drifts []SchemaDrift
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()), WithSchemaDriftCheck(SchemaDriftOptions{
+		PreserveUnknown: true,
+		ReportMissing:   true,
+		OnDrift:         func(d SchemaDrift) { drifts = append(drifts, d) },
+	}))
+	require.NoError(t, err)
+
+	org, err := entity.Organizations.GetOrganization(context.Background(), "019c96a0-0000-7000-8000-000000000001")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", org.LegalName)
+
+	require.Len(t, drifts, 1)
+	assert.Equal(t, "GET /organizations/{id}", drifts[0].Operation)
+	assert.Equal(t, "mmodel.Organization", drifts[0].Type)
+	assert.Equal(t, []string{"address.geo", "riskScore"}, drifts[0].Unknown)
+	assert.Empty(t, drifts[0].Missing, "pointer and omitempty fields are optional")
+	assert.JSONEq(t, `{"lat":1}`, string(drifts[0].Extra["address.geo"]))
+	assert.JSONEq(t, `7`, string(drifts[0].Extra["riskScore"]))
+
+	_, err = entity.Organizations.ListOrganizations(context.Background(), nil)
+	require.NoError(t, err)
+
+	require.Len(t, drifts, 2)
+	assert.Equal(t, []string{"items[*].address.geo", "items[*].riskScore", "items[*].tier", "limit", "page"}, drifts[1].Unknown,
+		"ListResponse nests its pagination")
+	assert.Contains(t, drifts[1].Missing, "items[*].legalName")
+	assert.Contains(t, drifts[1].Extra, "items[1].tier")
+
+	fields := entity.SchemaDrift().Fields()
+	require.NotEmpty(t, fields)
+	assert.Equal(t, DriftUnknown, fields[0].Kind)
+
+	for _, f := range fields {
+		if f.Path == "riskScore" {
+			assert.Equal(t, int64(1), f.Count)
+			assert.Equal(t, "GET /organizations/{id}", f.Operation)
+		}
+	}
+}
+
+func TestSchemaDriftLogCheck(t *testing.T) {
+	type inner struct {
+		Name string `json:"name"`
+	}
+
+	type embedded struct {
+		Shared string `json:"shared"`
+	}
+
+	type model struct {
+		embedded
+		ID       string  `json:"id"`
+		Optional string  `json:"optional,omitempty"`
+		Inner    inner   `json:"inner"`
+		List     []inner `json:"list"`
+		Ignored  string  `json:"-"`
+		Untagged string
+		Pointer  *inner   `json:"pointer"`
+		Tags     []string `json:"tags"`
+	}
+
+	log := NewSchemaDriftLog(SchemaDriftOptions{ReportMissing: true})
+
+	_, drifted := log.Check("op", &model{}, []byte(`{"shared":"s","ID":"1","inner":{"name":"n"},"list":[],"Untagged":"u","tags":["a"]}`))
+	assert.False(t, drifted, "keys match case-insensitively and embedded fields are promoted")
+
+	drift, drifted := log.Check("op", &model{}, []byte(`{"id":"1","inner":{"extra":1},"list":[{"name":"a","more":2}],"Ignored":"x","pointer":{"name":"p","deep":true}}`))
+	require.True(t, drifted)
+	assert.Equal(t, []string{"Ignored", "inner.extra", "list[*].more", "pointer.deep"}, drift.Unknown)
+	assert.Equal(t, []string{"Untagged", "inner.name", "shared", "tags"}, drift.Missing)
+	assert.Nil(t, drift.Extra)
+
+	_, drifted = log.Check("op", &model{}, []byte(`not json`))
+	assert.False(t, drifted)
+}
@@ -0,0 +1,590 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+)
+
+// ScopedEntity exposes the services of an Entity bound to an organization and
+// a ledger, so that their calls take neither:
+//
+//	ctx = models.WithScope(ctx, orgID, ledgerID)
+//	scoped := c.Entity.Scoped(ctx)
+//
+//	accounts, err := scoped.Accounts.List(ctx, nil)
+//	tx, err := scoped.Transactions.Create(ctx, input)
+//
+// Each scoped service forwards to the corresponding service of the Entity,
+// and is nil when that service is not enabled. Calls of a scope missing the
+// organization or the ledger fail with a missing parameter error, as the
+// unscoped calls do.
+type ScopedEntity struct {
+	// OrganizationID is the organization the calls apply to
+	OrganizationID models.OrganizationID
+
+	// LedgerID is the ledger the calls apply to
+	LedgerID models.LedgerID
+
+	Ledgers           *ScopedLedgers
+	Accounts          *ScopedAccounts
+	AccountTypes      *ScopedAccountTypes
+	Assets            *ScopedAssets
+	AssetRates        *ScopedAssetRates
+	Balances          *ScopedBalances
+	Operations        *ScopedOperations
+	OperationRoutes   *ScopedOperationRoutes
+	Portfolios        *ScopedPortfolios
+	Segments          *ScopedSegments
+	Transactions      *ScopedTransactions
+	TransactionRoutes *ScopedTransactionRoutes
+}
+
+// Scoped returns the services of the Entity bound to the scope attached to
+// ctx with models.WithScope. The scope is read once: later calls use it
+// whatever the context they receive.
+func (e *Entity) Scoped(ctx context.Context) *ScopedEntity {
+	scope, _ := models.ScopeFromContext(ctx)
+
+	return e.ScopedTo(scope.OrganizationID, scope.LedgerID)
+}
+
+// ScopedTo returns the services of the Entity bound to an organization and a
+// ledger.
+func (e *Entity) ScopedTo(orgID models.OrganizationID, ledgerID models.LedgerID) *ScopedEntity {
+	s := &ScopedEntity{OrganizationID: orgID, LedgerID: ledgerID}
+
+	if e.Ledgers != nil {
+		s.Ledgers = &ScopedLedgers{service: e.Ledgers, orgID: orgID.String()}
+	}
+
+	if e.Accounts != nil {
+		s.Accounts = &ScopedAccounts{service: e.Accounts, orgID: orgID.String(), ledgerID: ledgerID.String()}
+	}
+
+	if e.AccountTypes != nil {
+		s.AccountTypes = &ScopedAccountTypes{service: e.AccountTypes, orgID: orgID.String(), ledgerID: ledgerID.String()}
+	}
+
+	if e.Assets != nil {
+		s.Assets = &ScopedAssets{service: e.Assets, orgID: orgID.String(), ledgerID: ledgerID.String()}
+	}
+
+	if e.AssetRates != nil {
+		s.AssetRates = &ScopedAssetRates{service: e.AssetRates, orgID: orgID.String(), ledgerID: ledgerID.String()}
+	}
+
+	if e.Balances != nil {
+		s.Balances = &ScopedBalances{service: e.Balances, orgID: orgID.String(), ledgerID: ledgerID.String()}
+	}
+
+	if e.Operations != nil {
+		s.Operations = &ScopedOperations{service: e.Operations, orgID: orgID.String(), ledgerID: ledgerID.String()}
+	}
+
+	if e.OperationRoutes != nil {
+		s.OperationRoutes = &ScopedOperationRoutes{service: e.OperationRoutes, orgID: orgID.String(), ledgerID: ledgerID.String()}
+	}
+
+	if e.Portfolios != nil {
+		s.Portfolios = &ScopedPortfolios{service: e.Portfolios, orgID: orgID.String(), ledgerID: ledgerID.String()}
+	}
+
+	if e.Segments != nil {
+		s.Segments = &ScopedSegments{service: e.Segments, orgID: orgID.String(), ledgerID: ledgerID.String()}
+	}
+
+	if e.Transactions != nil {
+		s.Transactions = &ScopedTransactions{service: e.Transactions, orgID: orgID.String(), ledgerID: ledgerID.String()}
+	}
+
+	if e.TransactionRoutes != nil {
+		s.TransactionRoutes = &ScopedTransactionRoutes{service: e.TransactionRoutes, orgID: orgID.String(), ledgerID: ledgerID.String()}
+	}
+
+	return s
+}
+
+// ScopedLedgers is the Ledgers service bound to an organization.
+type ScopedLedgers struct {
+	service LedgersService
+	orgID   string
+}
+
+// List lists the ledgers of the organization.
+func (s *ScopedLedgers) List(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Ledger], error) {
+	return s.service.ListLedgers(ctx, s.orgID, opts)
+}
+
+// Get retrieves a ledger of the organization.
+func (s *ScopedLedgers) Get(ctx context.Context, id string) (*models.Ledger, error) {
+	return s.service.GetLedger(ctx, s.orgID, id)
+}
+
+// Create creates a ledger in the organization.
+func (s *ScopedLedgers) Create(ctx context.Context, input *models.CreateLedgerInput) (*models.Ledger, error) {
+	return s.service.CreateLedger(ctx, s.orgID, input)
+}
+
+// Update updates a ledger of the organization.
+func (s *ScopedLedgers) Update(ctx context.Context, id string, input *models.UpdateLedgerInput) (*models.Ledger, error) {
+	return s.service.UpdateLedger(ctx, s.orgID, id, input)
+}
+
+// Delete deletes a ledger of the organization.
+func (s *ScopedLedgers) Delete(ctx context.Context, id string) error {
+	return s.service.DeleteLedger(ctx, s.orgID, id)
+}
+
+// MetricsCount counts the ledgers of the organization.
+func (s *ScopedLedgers) MetricsCount(ctx context.Context) (*models.MetricsCount, error) {
+	return s.service.GetLedgersMetricsCount(ctx, s.orgID)
+}
+
+// ScopedAccounts is the Accounts service bound to a ledger.
+type ScopedAccounts struct {
+	service  AccountsService
+	orgID    string
+	ledgerID string
+}
+
+// List lists the accounts of the ledger.
+func (s *ScopedAccounts) List(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+	return s.service.ListAccounts(ctx, s.orgID, s.ledgerID, opts)
+}
+
+// Get retrieves an account of the ledger.
+func (s *ScopedAccounts) Get(ctx context.Context, id string) (*models.Account, error) {
+	return s.service.GetAccount(ctx, s.orgID, s.ledgerID, id)
+}
+
+// GetByAlias retrieves an account of the ledger by alias.
+func (s *ScopedAccounts) GetByAlias(ctx context.Context, alias string) (*models.Account, error) {
+	return s.service.GetAccountByAlias(ctx, s.orgID, s.ledgerID, alias)
+}
+
+// FindByAlias looks an account of the ledger up by alias, returning nil when there is none.
+func (s *ScopedAccounts) FindByAlias(ctx context.Context, alias string) (*models.Account, error) {
+	return s.service.FindByAlias(ctx, s.orgID, s.ledgerID, alias)
+}
+
+// Create creates an account in the ledger.
+func (s *ScopedAccounts) Create(ctx context.Context, input *models.CreateAccountInput) (*models.Account, error) {
+	return s.service.CreateAccount(ctx, s.orgID, s.ledgerID, input)
+}
+
+// Update updates an account of the ledger.
+func (s *ScopedAccounts) Update(ctx context.Context, id string, input *models.UpdateAccountInput) (*models.Account, error) {
+	return s.service.UpdateAccount(ctx, s.orgID, s.ledgerID, id, input)
+}
+
+// Delete deletes an account of the ledger.
+func (s *ScopedAccounts) Delete(ctx context.Context, id string) error {
+	return s.service.DeleteAccount(ctx, s.orgID, s.ledgerID, id)
+}
+
+// GetBalance retrieves the balance of an account of the ledger.
+func (s *ScopedAccounts) GetBalance(ctx context.Context, accountID string) (*models.Balance, error) {
+	return s.service.GetBalance(ctx, s.orgID, s.ledgerID, accountID)
+}
+
+// MetricsCount counts the accounts of the ledger.
+func (s *ScopedAccounts) MetricsCount(ctx context.Context) (*models.MetricsCount, error) {
+	return s.service.GetAccountsMetricsCount(ctx, s.orgID, s.ledgerID)
+}
+
+// GetExternal retrieves the external account of an asset of the ledger.
+func (s *ScopedAccounts) GetExternal(ctx context.Context, assetCode string) (*models.Account, error) {
+	return s.service.GetExternalAccount(ctx, s.orgID, s.ledgerID, assetCode)
+}
+
+// GetExternalBalance retrieves the balance of the external account of an asset of the ledger.
+func (s *ScopedAccounts) GetExternalBalance(ctx context.Context, assetCode string) (*models.Balance, error) {
+	return s.service.GetExternalAccountBalance(ctx, s.orgID, s.ledgerID, assetCode)
+}
+
+// ScopedAccountTypes is the AccountTypes service bound to a ledger.
+type ScopedAccountTypes struct {
+	service  AccountTypesService
+	orgID    string
+	ledgerID string
+}
+
+// List lists the account types of the ledger.
+func (s *ScopedAccountTypes) List(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.AccountType], error) {
+	return s.service.ListAccountTypes(ctx, s.orgID, s.ledgerID, opts)
+}
+
+// Get retrieves an account type of the ledger.
+func (s *ScopedAccountTypes) Get(ctx context.Context, id string) (*models.AccountType, error) {
+	return s.service.GetAccountType(ctx, s.orgID, s.ledgerID, id)
+}
+
+// Create creates an account type in the ledger.
+func (s *ScopedAccountTypes) Create(ctx context.Context, input *models.CreateAccountTypeInput) (*models.AccountType, error) {
+	return s.service.CreateAccountType(ctx, s.orgID, s.ledgerID, input)
+}
+
+// Update updates an account type of the ledger.
+func (s *ScopedAccountTypes) Update(ctx context.Context, id string, input *models.UpdateAccountTypeInput) (*models.AccountType, error) {
+	return s.service.UpdateAccountType(ctx, s.orgID, s.ledgerID, id, input)
+}
+
+// Delete deletes an account type of the ledger.
+func (s *ScopedAccountTypes) Delete(ctx context.Context, id string) error {
+	return s.service.DeleteAccountType(ctx, s.orgID, s.ledgerID, id)
+}
+
+// MetricsCount counts the account types of the ledger.
+func (s *ScopedAccountTypes) MetricsCount(ctx context.Context) (*models.MetricsCount, error) {
+	return s.service.GetAccountTypesMetricsCount(ctx, s.orgID, s.ledgerID)
+}
+
+// ScopedAssets is the Assets service bound to a ledger.
+type ScopedAssets struct {
+	service  AssetsService
+	orgID    string
+	ledgerID string
+}
+
+// List lists the assets of the ledger.
+func (s *ScopedAssets) List(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Asset], error) {
+	return s.service.ListAssets(ctx, s.orgID, s.ledgerID, opts)
+}
+
+// Get retrieves an asset of the ledger.
+func (s *ScopedAssets) Get(ctx context.Context, id string) (*models.Asset, error) {
+	return s.service.GetAsset(ctx, s.orgID, s.ledgerID, id)
+}
+
+// FindByCode looks an asset of the ledger up by code, returning nil when there is none.
+func (s *ScopedAssets) FindByCode(ctx context.Context, code string) (*models.Asset, error) {
+	return s.service.FindByCode(ctx, s.orgID, s.ledgerID, code)
+}
+
+// Create creates an asset in the ledger.
+func (s *ScopedAssets) Create(ctx context.Context, input *models.CreateAssetInput) (*models.Asset, error) {
+	return s.service.CreateAsset(ctx, s.orgID, s.ledgerID, input)
+}
+
+// Update updates an asset of the ledger.
+func (s *ScopedAssets) Update(ctx context.Context, id string, input *models.UpdateAssetInput) (*models.Asset, error) {
+	return s.service.UpdateAsset(ctx, s.orgID, s.ledgerID, id, input)
+}
+
+// Delete deletes an asset of the ledger.
+func (s *ScopedAssets) Delete(ctx context.Context, id string) error {
+	return s.service.DeleteAsset(ctx, s.orgID, s.ledgerID, id)
+}
+
+// MetricsCount counts the assets of the ledger.
+func (s *ScopedAssets) MetricsCount(ctx context.Context) (*models.MetricsCount, error) {
+	return s.service.GetAssetsMetricsCount(ctx, s.orgID, s.ledgerID)
+}
+
+// ScopedAssetRates is the AssetRates service bound to a ledger.
+type ScopedAssetRates struct {
+	service  AssetRatesService
+	orgID    string
+	ledgerID string
+}
+
+// CreateOrUpdate creates or replaces an asset rate of the ledger.
+func (s *ScopedAssetRates) CreateOrUpdate(ctx context.Context, input *models.CreateAssetRateInput) (*models.AssetRate, error) {
+	return s.service.CreateOrUpdateAssetRate(ctx, s.orgID, s.ledgerID, input)
+}
+
+// Get retrieves an asset rate of the ledger by external ID.
+func (s *ScopedAssetRates) Get(ctx context.Context, externalID string) (*models.AssetRate, error) {
+	return s.service.GetAssetRate(ctx, s.orgID, s.ledgerID, externalID)
+}
+
+// ListByAssetCode lists the rates of the ledger from an asset.
+func (s *ScopedAssetRates) ListByAssetCode(ctx context.Context, assetCode string, opts *models.AssetRateListOptions) (*models.AssetRatesResponse, error) {
+	return s.service.ListAssetRatesByAssetCode(ctx, s.orgID, s.ledgerID, assetCode, opts)
+}
+
+// ScopedBalances is the Balances service bound to a ledger.
+type ScopedBalances struct {
+	service  BalancesService
+	orgID    string
+	ledgerID string
+}
+
+// List lists the balances of the ledger.
+func (s *ScopedBalances) List(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	return s.service.ListBalances(ctx, s.orgID, s.ledgerID, opts)
+}
+
+// ListByAccount lists the balances of an account of the ledger.
+func (s *ScopedBalances) ListByAccount(ctx context.Context, accountID string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	return s.service.ListAccountBalances(ctx, s.orgID, s.ledgerID, accountID, opts)
+}
+
+// ListByAccountAlias lists the balances of an account of the ledger by alias.
+func (s *ScopedBalances) ListByAccountAlias(ctx context.Context, alias string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	return s.service.ListBalancesByAccountAlias(ctx, s.orgID, s.ledgerID, alias, opts)
+}
+
+// ListByExternalCode lists the balances of the external account of an asset of the ledger.
+func (s *ScopedBalances) ListByExternalCode(ctx context.Context, code string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	return s.service.ListBalancesByExternalCode(ctx, s.orgID, s.ledgerID, code, opts)
+}
+
+// Get retrieves a balance of the ledger.
+func (s *ScopedBalances) Get(ctx context.Context, balanceID string) (*models.Balance, error) {
+	return s.service.GetBalance(ctx, s.orgID, s.ledgerID, balanceID)
+}
+
+// GetMany retrieves the balances of several accounts of the ledger.
+func (s *ScopedBalances) GetMany(ctx context.Context, accountIDs []string, opts *GetManyOptions) (map[string]*AccountBalances, error) {
+	return s.service.GetMany(ctx, s.orgID, s.ledgerID, accountIDs, opts)
+}
+
+// Create creates a balance for an account of the ledger.
+func (s *ScopedBalances) Create(ctx context.Context, accountID string, input *models.CreateBalanceInput) (*models.Balance, error) {
+	return s.service.CreateBalance(ctx, s.orgID, s.ledgerID, accountID, input)
+}
+
+// Update updates a balance of the ledger.
+func (s *ScopedBalances) Update(ctx context.Context, balanceID string, input *models.UpdateBalanceInput) (*models.Balance, error) {
+	return s.service.UpdateBalance(ctx, s.orgID, s.ledgerID, balanceID, input)
+}
+
+// Delete deletes a balance of the ledger.
+func (s *ScopedBalances) Delete(ctx context.Context, balanceID string) error {
+	return s.service.DeleteBalance(ctx, s.orgID, s.ledgerID, balanceID)
+}
+
+// AsOf retrieves the balances of an account of the ledger at a past point in time.
+func (s *ScopedBalances) AsOf(ctx context.Context, accountID string, at time.Time) ([]models.BalanceHistory, error) {
+	return s.service.AsOf(ctx, s.orgID, s.ledgerID, accountID, at)
+}
+
+// ScopedOperations is the Operations service bound to a ledger.
+type ScopedOperations struct {
+	service  OperationsService
+	orgID    string
+	ledgerID string
+}
+
+// List lists the operations of an account of the ledger.
+func (s *ScopedOperations) List(ctx context.Context, accountID string, opts *models.ListOptions) (*models.ListResponse[models.Operation], error) {
+	return s.service.ListOperations(ctx, s.orgID, s.ledgerID, accountID, opts)
+}
+
+// Get retrieves an operation of an account of the ledger.
+func (s *ScopedOperations) Get(ctx context.Context, accountID, operationID string, transactionID ...string) (*models.Operation, error) {
+	return s.service.GetOperation(ctx, s.orgID, s.ledgerID, accountID, operationID, transactionID...)
+}
+
+// Update updates an operation of an account of the ledger.
+func (s *ScopedOperations) Update(ctx context.Context, accountID, operationID string, input any) (*models.Operation, error) {
+	return s.service.UpdateOperation(ctx, s.orgID, s.ledgerID, accountID, operationID, input)
+}
+
+// ScopedOperationRoutes is the OperationRoutes service bound to a ledger.
+type ScopedOperationRoutes struct {
+	service  OperationRoutesService
+	orgID    string
+	ledgerID string
+}
+
+// List lists the operation routes of the ledger.
+func (s *ScopedOperationRoutes) List(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.OperationRoute], error) {
+	return s.service.ListOperationRoutes(ctx, s.orgID, s.ledgerID, opts)
+}
+
+// Get retrieves an operation route of the ledger.
+func (s *ScopedOperationRoutes) Get(ctx context.Context, id string) (*models.OperationRoute, error) {
+	return s.service.GetOperationRoute(ctx, s.orgID, s.ledgerID, id)
+}
+
+// Create creates an operation route in the ledger.
+func (s *ScopedOperationRoutes) Create(ctx context.Context, input *models.CreateOperationRouteInput) (*models.OperationRoute, error) {
+	return s.service.CreateOperationRoute(ctx, s.orgID, s.ledgerID, input)
+}
+
+// Update updates an operation route of the ledger.
+func (s *ScopedOperationRoutes) Update(ctx context.Context, id string, input *models.UpdateOperationRouteInput) (*models.OperationRoute, error) {
+	return s.service.UpdateOperationRoute(ctx, s.orgID, s.ledgerID, id, input)
+}
+
+// Delete deletes an operation route of the ledger.
+func (s *ScopedOperationRoutes) Delete(ctx context.Context, id string) error {
+	return s.service.DeleteOperationRoute(ctx, s.orgID, s.ledgerID, id)
+}
+
+// ScopedPortfolios is the Portfolios service bound to a ledger.
+type ScopedPortfolios struct {
+	service  PortfoliosService
+	orgID    string
+	ledgerID string
+}
+
+// List lists the portfolios of the ledger.
+func (s *ScopedPortfolios) List(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Portfolio], error) {
+	return s.service.ListPortfolios(ctx, s.orgID, s.ledgerID, opts)
+}
+
+// Get retrieves a portfolio of the ledger.
+func (s *ScopedPortfolios) Get(ctx context.Context, id string) (*models.Portfolio, error) {
+	return s.service.GetPortfolio(ctx, s.orgID, s.ledgerID, id)
+}
+
+// Create creates a portfolio in the ledger.
+func (s *ScopedPortfolios) Create(ctx context.Context, input *models.CreatePortfolioInput) (*models.Portfolio, error) {
+	return s.service.CreatePortfolio(ctx, s.orgID, s.ledgerID, input)
+}
+
+// Update updates a portfolio of the ledger.
+func (s *ScopedPortfolios) Update(ctx context.Context, id string, input *models.UpdatePortfolioInput) (*models.Portfolio, error) {
+	return s.service.UpdatePortfolio(ctx, s.orgID, s.ledgerID, id, input)
+}
+
+// Delete deletes a portfolio of the ledger.
+func (s *ScopedPortfolios) Delete(ctx context.Context, id string) error {
+	return s.service.DeletePortfolio(ctx, s.orgID, s.ledgerID, id)
+}
+
+// MetricsCount counts the portfolios of the ledger.
+func (s *ScopedPortfolios) MetricsCount(ctx context.Context) (*models.MetricsCount, error) {
+	return s.service.GetPortfoliosMetricsCount(ctx, s.orgID, s.ledgerID)
+}
+
+// ScopedSegments is the Segments service bound to a ledger.
+type ScopedSegments struct {
+	service  SegmentsService
+	orgID    string
+	ledgerID string
+}
+
+// List lists the segments of the ledger.
+func (s *ScopedSegments) List(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Segment], error) {
+	return s.service.ListSegments(ctx, s.orgID, s.ledgerID, opts)
+}
+
+// Get retrieves a segment of the ledger.
+func (s *ScopedSegments) Get(ctx context.Context, id string) (*models.Segment, error) {
+	return s.service.GetSegment(ctx, s.orgID, s.ledgerID, id)
+}
+
+// Create creates a segment in the ledger.
+func (s *ScopedSegments) Create(ctx context.Context, input *models.CreateSegmentInput) (*models.Segment, error) {
+	return s.service.CreateSegment(ctx, s.orgID, s.ledgerID, input)
+}
+
+// Update updates a segment of the ledger.
+func (s *ScopedSegments) Update(ctx context.Context, id string, input *models.UpdateSegmentInput) (*models.Segment, error) {
+	return s.service.UpdateSegment(ctx, s.orgID, s.ledgerID, id, input)
+}
+
+// Delete deletes a segment of the ledger.
+func (s *ScopedSegments) Delete(ctx context.Context, id string) error {
+	return s.service.DeleteSegment(ctx, s.orgID, s.ledgerID, id)
+}
+
+// MetricsCount counts the segments of the ledger.
+func (s *ScopedSegments) MetricsCount(ctx context.Context) (*models.MetricsCount, error) {
+	return s.service.GetSegmentsMetricsCount(ctx, s.orgID, s.ledgerID)
+}
+
+// ScopedTransactions is the Transactions service bound to a ledger.
+type ScopedTransactions struct {
+	service  TransactionsService
+	orgID    string
+	ledgerID string
+}
+
+// List lists the transactions of the ledger.
+func (s *ScopedTransactions) List(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Transaction], error) {
+	return s.service.ListTransactions(ctx, s.orgID, s.ledgerID, opts)
+}
+
+// Get retrieves a transaction of the ledger.
+func (s *ScopedTransactions) Get(ctx context.Context, transactionID string) (*models.Transaction, error) {
+	return s.service.GetTransaction(ctx, s.orgID, s.ledgerID, transactionID)
+}
+
+// Create submits a transaction to the ledger.
+func (s *ScopedTransactions) Create(ctx context.Context, input *models.CreateTransactionInput) (*models.Transaction, error) {
+	return s.service.CreateTransaction(ctx, s.orgID, s.ledgerID, input)
+}
+
+// CreateWithDSL submits a DSL transaction to the ledger.
+func (s *ScopedTransactions) CreateWithDSL(ctx context.Context, input *models.TransactionDSLInput) (*models.Transaction, error) {
+	return s.service.CreateTransactionWithDSL(ctx, s.orgID, s.ledgerID, input)
+}
+
+// CreateWithDSLFile submits a DSL file to the ledger.
+func (s *ScopedTransactions) CreateWithDSLFile(ctx context.Context, dslContent []byte) (*models.Transaction, error) {
+	return s.service.CreateTransactionWithDSLFile(ctx, s.orgID, s.ledgerID, dslContent)
+}
+
+// CreateInflow submits an inflow transaction to the ledger.
+func (s *ScopedTransactions) CreateInflow(ctx context.Context, input *models.CreateInflowInput) (*models.Transaction, error) {
+	return s.service.CreateInflowTransaction(ctx, s.orgID, s.ledgerID, input)
+}
+
+// CreateOutflow submits an outflow transaction to the ledger.
+func (s *ScopedTransactions) CreateOutflow(ctx context.Context, input *models.CreateOutflowInput) (*models.Transaction, error) {
+	return s.service.CreateOutflowTransaction(ctx, s.orgID, s.ledgerID, input)
+}
+
+// CreateAnnotation records an annotation transaction in the ledger.
+func (s *ScopedTransactions) CreateAnnotation(ctx context.Context, input *models.CreateAnnotationInput) (*models.Transaction, error) {
+	return s.service.CreateAnnotationTransaction(ctx, s.orgID, s.ledgerID, input)
+}
+
+// Update updates a transaction of the ledger.
+func (s *ScopedTransactions) Update(ctx context.Context, transactionID string, input any) (*models.Transaction, error) {
+	return s.service.UpdateTransaction(ctx, s.orgID, s.ledgerID, transactionID, input)
+}
+
+// Commit commits a pending transaction of the ledger.
+func (s *ScopedTransactions) Commit(ctx context.Context, transactionID string) (*models.Transaction, error) {
+	return s.service.CommitTransaction(ctx, s.orgID, s.ledgerID, transactionID)
+}
+
+// Cancel cancels a pending transaction of the ledger.
+func (s *ScopedTransactions) Cancel(ctx context.Context, transactionID string) error {
+	return s.service.CancelTransaction(ctx, s.orgID, s.ledgerID, transactionID)
+}
+
+// Revert reverts a transaction of the ledger.
+func (s *ScopedTransactions) Revert(ctx context.Context, transactionID string) (*models.Transaction, error) {
+	return s.service.RevertTransaction(ctx, s.orgID, s.ledgerID, transactionID)
+}
+
+// ScopedTransactionRoutes is the TransactionRoutes service bound to a ledger.
+type ScopedTransactionRoutes struct {
+	service  TransactionRoutesService
+	orgID    string
+	ledgerID string
+}
+
+// List lists the transaction routes of the ledger.
+func (s *ScopedTransactionRoutes) List(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.TransactionRoute], error) {
+	return s.service.ListTransactionRoutes(ctx, s.orgID, s.ledgerID, opts)
+}
+
+// Get retrieves a transaction route of the ledger.
+func (s *ScopedTransactionRoutes) Get(ctx context.Context, id string) (*models.TransactionRoute, error) {
+	return s.service.GetTransactionRoute(ctx, s.orgID, s.ledgerID, id)
+}
+
+// Create creates a transaction route in the ledger.
+func (s *ScopedTransactionRoutes) Create(ctx context.Context, input *models.CreateTransactionRouteInput) (*models.TransactionRoute, error) {
+	return s.service.CreateTransactionRoute(ctx, s.orgID, s.ledgerID, input)
+}
+
+// Update updates a transaction route of the ledger.
+func (s *ScopedTransactionRoutes) Update(ctx context.Context, id string, input *models.UpdateTransactionRouteInput) (*models.TransactionRoute, error) {
+	return s.service.UpdateTransactionRoute(ctx, s.orgID, s.ledgerID, id, input)
+}
+
+// Delete deletes a transaction route of the ledger.
+func (s *ScopedTransactionRoutes) Delete(ctx context.Context, id string) error {
+	return s.service.DeleteTransactionRoute(ctx, s.orgID, s.ledgerID, id)
+}
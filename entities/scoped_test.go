@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntity_Scoped(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","items":[]}`))
+	}))
+	defer srv.Close()
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	ctx := models.WithScope(context.Background(), "org-1", "ledger-1")
+	scoped := entity.Scoped(ctx)
+
+	assert.Equal(t, models.OrganizationID("org-1"), scoped.OrganizationID)
+	assert.Equal(t, models.LedgerID("ledger-1"), scoped.LedgerID)
+
+	_, err = scoped.Accounts.List(ctx, nil)
+	require.NoError(t, err)
+
+	_, err = scoped.Ledgers.Get(ctx, "ledger-2")
+	require.NoError(t, err)
+
+	_, err = scoped.Transactions.Commit(ctx, "tx-1")
+	require.NoError(t, err)
+
+	_, err = scoped.Operations.List(ctx, "acc-1", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"GET /organizations/org-1/ledgers/ledger-1/accounts",
+		"GET /organizations/org-1/ledgers/ledger-2",
+		"POST /organizations/org-1/ledgers/ledger-1/transactions/tx-1/commit",
+		"GET /organizations/org-1/ledgers/ledger-1/accounts/acc-1/operations",
+	}, paths)
+}
+
+func TestEntity_ScopedWithoutScope(t *testing.T) {
+	entity, err := New("http://localhost", WithServices(ServiceAccounts))
+	require.NoError(t, err)
+
+	scoped := entity.Scoped(context.Background())
+	assert.Nil(t, scoped.Transactions, "services not enabled stay nil")
+
+	_, err = scoped.Accounts.List(context.Background(), nil)
+	assert.True(t, errors.IsValidationError(err), "got %v", err)
+}
@@ -126,8 +126,9 @@ type segmentsEntity struct {
 	baseURLs   map[string]string
 }
 
-func (e *segmentsEntity) setDefaultTenantID(tenantID string) {
-	e.HTTPClient.SetTenantID(tenantID)
+// useSettings makes the service share the settings of its Entity.
+func (e *segmentsEntity) useSettings(settings *clientSettings) {
+	e.HTTPClient.clientSettings = settings
 }
 
 // NewSegmentsEntity creates a new segments entity.
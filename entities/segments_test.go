@@ -36,6 +36,7 @@ func newMockSegmentsHTTPClientAdapter(mock *MockHTTPClient) *HTTPClient {
 	_ = retry.WithRetryableHTTPCodes(retry.DefaultRetryableHTTPCodes)(retryOptions)
 
 	return &HTTPClient{
+		clientSettings: &clientSettings{},
 		client: &http.Client{
 			Transport: &mockTransport{mock: mock},
 		},
@@ -0,0 +1,76 @@
+package entities
+
+import "errors"
+
+// ServiceSet selects the services an Entity initializes. Services left out
+// of the set are nil on the Entity, so code that only submits transactions
+// doesn't carry clients, and test doubles, for the whole API.
+//
+// Example:
+//
+//	entity, err := entities.NewWithServiceURLs(urls,
+//	    entities.WithServices(entities.ServiceTransactions|entities.ServiceBalances),
+//	)
+type ServiceSet uint32
+
+// Services that can be selected with WithServices.
+const (
+	ServiceAccounts ServiceSet = 1 << iota
+	ServiceAccountTypes
+	ServiceAssets
+	ServiceAssetRates
+	ServiceBalances
+	ServiceLedgers
+	ServiceOperations
+	ServiceOperationRoutes
+	ServiceOrganizations
+	ServicePortfolios
+	ServiceSegments
+	ServiceTransactions
+	ServiceTransactionRoutes
+)
+
+// Common service sets.
+const (
+	// AllServices selects every service. It is the default.
+	AllServices = ServiceAccounts | ServiceAccountTypes | ServiceAssets | ServiceAssetRates |
+		ServiceBalances | ServiceLedgers | ServiceOperations | ServiceOperationRoutes |
+		ServiceOrganizations | ServicePortfolios | ServiceSegments | ServiceTransactions |
+		ServiceTransactionRoutes
+
+	// TransactionServices selects the services needed to submit transactions
+	// and inspect their effects: transactions, operations and balances.
+	TransactionServices = ServiceTransactions | ServiceOperations | ServiceBalances
+
+	// OnboardingServices selects the services of the onboarding API.
+	OnboardingServices = ServiceAccounts | ServiceAccountTypes | ServiceAssets |
+		ServiceLedgers | ServiceOrganizations | ServicePortfolios | ServiceSegments
+)
+
+// Has reports whether every service of other is in the set.
+func (s ServiceSet) Has(other ServiceSet) bool {
+	return s&other == other
+}
+
+// WithServices returns an Option that restricts the Entity to the given
+// services. The services not selected are left nil.
+func WithServices(services ServiceSet) Option {
+	return func(e *Entity) error {
+		if services == 0 || services&^AllServices != 0 {
+			return errors.New("invalid service set")
+		}
+
+		e.services = services
+
+		return nil
+	}
+}
+
+// Services returns the services initialized by the Entity.
+func (e *Entity) Services() ServiceSet {
+	if e.services == 0 {
+		return AllServices
+	}
+
+	return e.services
+}
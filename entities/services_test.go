@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithServices(t *testing.T) {
+	urls := map[string]string{"onboarding": "http://localhost:3000", "transaction": "http://localhost:3001"}
+
+	t.Run("all by default", func(t *testing.T) {
+		entity, err := NewWithServiceURLs(urls)
+		require.NoError(t, err)
+
+		assert.Equal(t, AllServices, entity.Services())
+		assert.NotNil(t, entity.Accounts)
+		assert.NotNil(t, entity.TransactionRoutes)
+		assert.NotNil(t, entity.AccountTypeCache)
+	})
+
+	t.Run("transaction services", func(t *testing.T) {
+		entity, err := NewWithServiceURLs(urls, WithServices(TransactionServices), WithDefaultTenantID("tenant"))
+		require.NoError(t, err)
+
+		assert.NotNil(t, entity.Transactions)
+		assert.NotNil(t, entity.Operations)
+		assert.NotNil(t, entity.Balances)
+		assert.Nil(t, entity.Accounts)
+		assert.Nil(t, entity.Organizations)
+		assert.Nil(t, entity.AccountTypes)
+		assert.Nil(t, entity.AccountTypeCache)
+
+		reader := entity.ReadOnly()
+		assert.NotNil(t, reader.Transactions)
+		assert.NotNil(t, reader.Balances)
+		assert.Nil(t, reader.Accounts)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewWithServiceURLs(urls, WithServices(0))
+		require.Error(t, err)
+
+		_, err = NewWithServiceURLs(urls, WithServices(AllServices+1))
+		require.Error(t, err)
+	})
+
+	assert.True(t, AllServices.Has(TransactionServices))
+	assert.False(t, TransactionServices.Has(ServiceAccounts|ServiceTransactions))
+}
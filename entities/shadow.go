@@ -0,0 +1,445 @@
+package entities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+)
+
+// DefaultShadowTimeout bounds a shadow request when ShadowConfig.HTTPClient is nil.
+const DefaultShadowTimeout = 10 * time.Second
+
+// DefaultShadowMaxInFlight is the number of shadow requests in flight when
+// ShadowConfig.MaxInFlight is not set.
+const DefaultShadowMaxInFlight = 10
+
+// maxShadowDifferences caps the differences reported for a single response.
+const maxShadowDifferences = 20
+
+// ShadowConfig configures the shadow traffic of WithShadowTarget.
+type ShadowConfig struct {
+	// BaseURLs maps service names ("onboarding", "transaction") to the base
+	// URLs of the shadow environment, like the base URLs of the Entity.
+	// Requests to a service without a shadow URL are not duplicated.
+	BaseURLs map[string]string
+
+	// AuthToken authorizes the shadow requests; empty sends them with the
+	// authorization of the primary request
+	AuthToken string
+
+	// HTTPClient sends the shadow requests
+	// Default is a client with a DefaultShadowTimeout timeout
+	HTTPClient *http.Client
+
+	// SampleRate is the share of read requests duplicated, between 0 and 1
+	// Default is 1 (every read request) if not specified
+	SampleRate float64
+
+	// MaxInFlight caps the shadow requests in flight; requests sampled
+	// beyond it are dropped rather than delaying the primary ones
+	// Default is DefaultShadowMaxInFlight if not specified
+	MaxInFlight int
+
+	// IgnoreFields are JSON fields left out of the comparison at any depth,
+	// such as "updatedAt" for fields expected to differ between environments
+	IgnoreFields []string
+
+	// OnDivergence receives every divergence, from the goroutine of the comparison
+	OnDivergence func(ShadowDivergence)
+}
+
+// ShadowDivergence is a read request whose shadow response differs from the
+// primary one.
+type ShadowDivergence struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	// Operation is the route of the request, with identifiers replaced by {id}
+	Operation string `json:"operation"`
+	// Path is the request path; the query string is left out as it may hold filters with personal data
+	Path          string `json:"path"`
+	PrimaryStatus int    `json:"primaryStatus"`
+	ShadowStatus  int    `json:"shadowStatus,omitempty"`
+	// Differences are the JSON paths whose values differ, e.g. "items[0].status";
+	// values are left out as they may hold personal data
+	Differences []string `json:"differences,omitempty"`
+	// Error is the error of the shadow request, which got no response
+	Error string `json:"error,omitempty"`
+}
+
+// ShadowStats counts the shadow requests of a Shadow.
+type ShadowStats struct {
+	// Sampled counts the read requests selected for shadowing
+	Sampled int64 `json:"sampled"`
+	// Dropped counts the sampled requests not sent as MaxInFlight was reached
+	Dropped int64 `json:"dropped"`
+	// Matched counts the shadow responses equal to the primary ones
+	Matched int64 `json:"matched"`
+	// Diverged counts the shadow responses that differ from the primary ones
+	Diverged int64 `json:"diverged"`
+	// Failed counts the shadow requests that got no response
+	Failed int64 `json:"failed"`
+}
+
+// Shadow duplicates read requests to a second environment and compares the
+// responses, see WithShadowTarget. It is safe for concurrent use.
+type Shadow struct {
+	config   ShadowConfig
+	client   *http.Client
+	ignore   map[string]bool
+	slots    chan struct{}
+	inFlight sync.WaitGroup
+
+	// mu orders closed with the additions to inFlight
+	mu     sync.Mutex
+	closed bool
+
+	sampled  atomic.Int64
+	dropped  atomic.Int64
+	matched  atomic.Int64
+	diverged atomic.Int64
+	failed   atomic.Int64
+}
+
+// NewShadow creates a Shadow from cfg.
+func NewShadow(cfg ShadowConfig) (*Shadow, error) {
+	if len(cfg.BaseURLs) == 0 {
+		return nil, errors.New("shadow target requires at least one base URL")
+	}
+
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return nil, fmt.Errorf("shadow sample rate must be between 0 and 1, got %v", cfg.SampleRate)
+	}
+
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = 1
+	}
+
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = DefaultShadowMaxInFlight
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: DefaultShadowTimeout}
+	}
+
+	ignore := make(map[string]bool, len(cfg.IgnoreFields))
+	for _, field := range cfg.IgnoreFields {
+		ignore[field] = true
+	}
+
+	return &Shadow{
+		config: cfg,
+		client: client,
+		ignore: ignore,
+		slots:  make(chan struct{}, cfg.MaxInFlight),
+	}, nil
+}
+
+// Stats returns the counts of the shadow requests.
+func (s *Shadow) Stats() ShadowStats {
+	return ShadowStats{
+		Sampled:  s.sampled.Load(),
+		Dropped:  s.dropped.Load(),
+		Matched:  s.matched.Load(),
+		Diverged: s.diverged.Load(),
+		Failed:   s.failed.Load(),
+	}
+}
+
+// Wait blocks until the shadow requests in flight are compared or ctx is
+// done, e.g. before the program exits.
+func (s *Shadow) Wait(ctx context.Context) error {
+	return waitGroupContext(ctx, &s.inFlight)
+}
+
+// Close stops duplicating requests and waits until the shadow requests in
+// flight are compared or ctx is done.
+func (s *Shadow) Close(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	return s.Wait(ctx)
+}
+
+// track adds a shadow request to those in flight, unless the shadow is
+// closed.
+func (s *Shadow) track() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+
+	s.inFlight.Add(1)
+
+	return true
+}
+
+// WithShadowTarget returns an Option that duplicates a sample of the read
+// requests of every service of the Entity to a second environment and
+// compares the responses asynchronously, reporting divergences to
+// ShadowConfig.OnDivergence and counting them in Entity.Shadow().Stats().
+// It is meant to validate a new Midaz version against the current one before
+// a cutover: the primary responses are returned unchanged and never wait for
+// the shadow ones.
+//
+// Only GET requests are duplicated, so the shadow environment sees no write.
+func WithShadowTarget(cfg ShadowConfig) Option {
+	return func(e *Entity) error {
+		shadow, err := NewShadow(cfg)
+		if err != nil {
+			return err
+		}
+
+		e.shadow = shadow
+
+		return nil
+	}
+}
+
+// Shadow returns the shadow set with WithShadowTarget, or nil.
+func (e *Entity) Shadow() *Shadow {
+	return e.shadow
+}
+
+// shadowTransport sends the primary requests through base and duplicates the
+// sampled read requests to the shadow environment.
+type shadowTransport struct {
+	base     http.RoundTripper
+	shadow   *Shadow
+	baseURLs map[string]string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *shadowTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || req.Method != http.MethodGet || rand.Float64() >= t.shadow.config.SampleRate {
+		return resp, err
+	}
+
+	target, ok := t.targetURL(req.URL.String())
+	if !ok {
+		return resp, nil
+	}
+
+	t.shadow.sampled.Add(1)
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	select {
+	case t.shadow.slots <- struct{}{}:
+	default:
+		t.shadow.dropped.Add(1)
+		return resp, nil
+	}
+
+	shadowReq, err := http.NewRequestWithContext(context.WithoutCancel(req.Context()), http.MethodGet, target, nil)
+	if err != nil {
+		<-t.shadow.slots
+		return resp, nil
+	}
+
+	shadowReq.Header = req.Header.Clone()
+	if t.shadow.config.AuthToken != "" {
+		shadowReq.Header.Set("Authorization", "Bearer "+t.shadow.config.AuthToken)
+	}
+
+	if !t.shadow.track() {
+		<-t.shadow.slots
+		return resp, nil
+	}
+
+	done := lifecycle.Track("entities.Shadow")
+
+	go func() {
+		defer t.shadow.inFlight.Done()
+		defer done()
+		defer func() { <-t.shadow.slots }()
+
+		t.shadow.compare(shadowReq, req.URL.Path, resp.StatusCode, body)
+	}()
+
+	return resp, nil
+}
+
+// targetURL maps a primary URL to the shadow environment, by the base URL of
+// its service.
+func (t *shadowTransport) targetURL(primary string) (string, bool) {
+	for service, base := range t.baseURLs {
+		shadowBase, ok := t.shadow.config.BaseURLs[service]
+		if !ok || base == "" {
+			continue
+		}
+
+		base = strings.TrimSuffix(base, "/")
+		if rest, found := strings.CutPrefix(primary, base); found && (rest == "" || rest[0] == '/' || rest[0] == '?') {
+			return strings.TrimSuffix(shadowBase, "/") + rest, true
+		}
+	}
+
+	return "", false
+}
+
+// compare sends the shadow request and reports how its response differs from
+// the primary one.
+func (s *Shadow) compare(req *http.Request, path string, primaryStatus int, primaryBody []byte) {
+	divergence := ShadowDivergence{
+		Time:          time.Now().UTC(),
+		Method:        req.Method,
+		Operation:     req.Method + " " + routeTemplate(path),
+		Path:          path,
+		PrimaryStatus: primaryStatus,
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.failed.Add(1)
+		divergence.Error = err.Error()
+		s.report(divergence)
+
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		s.failed.Add(1)
+		divergence.Error = err.Error()
+		s.report(divergence)
+
+		return
+	}
+
+	divergence.ShadowStatus = resp.StatusCode
+	if resp.StatusCode == primaryStatus {
+		divergence.Differences = s.diffBodies(primaryBody, body)
+		if len(divergence.Differences) == 0 {
+			s.matched.Add(1)
+			return
+		}
+	}
+
+	s.diverged.Add(1)
+	s.report(divergence)
+}
+
+// report hands a divergence to OnDivergence.
+func (s *Shadow) report(divergence ShadowDivergence) {
+	if s.config.OnDivergence != nil {
+		s.config.OnDivergence(divergence)
+	}
+}
+
+// diffBodies returns the JSON paths that differ between two bodies, or a
+// single "body" entry when they are not both JSON and differ.
+func (s *Shadow) diffBodies(primary, shadow []byte) []string {
+	var a, b any
+
+	if json.Unmarshal(primary, &a) != nil || json.Unmarshal(shadow, &b) != nil {
+		if bytes.Equal(bytes.TrimSpace(primary), bytes.TrimSpace(shadow)) {
+			return nil
+		}
+
+		return []string{"body"}
+	}
+
+	var differences []string
+
+	s.diffValues("", a, b, &differences)
+
+	return differences
+}
+
+// diffValues appends the paths where a and b differ to differences.
+func (s *Shadow) diffValues(path string, a, b any, differences *[]string) {
+	if len(*differences) >= maxShadowDifferences {
+		return
+	}
+
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok {
+			*differences = append(*differences, pathOrRoot(path))
+			return
+		}
+
+		for _, key := range unionKeys(a, b) {
+			if s.ignore[key] {
+				continue
+			}
+
+			s.diffValues(joinPath(path, key), a[key], b[key], differences)
+		}
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			*differences = append(*differences, pathOrRoot(path))
+			return
+		}
+
+		for i := range a {
+			s.diffValues(fmt.Sprintf("%s[%d]", path, i), a[i], b[i], differences)
+		}
+	default:
+		if !reflect.DeepEqual(a, b) {
+			*differences = append(*differences, pathOrRoot(path))
+		}
+	}
+}
+
+func unionKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "$"
+	}
+
+	return path
+}
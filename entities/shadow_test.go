@@ -0,0 +1,151 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithShadowTarget(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"id":"org-1","legalName":"Acme","status":{"code":"ACTIVE"},"updatedAt":"2026-01-01T00:00:00Z"}`))
+	}))
+	defer primary.Close()
+
+	var (
+		shadowRequests atomic.Int64
+		shadowAuth     atomic.Value
+	)
+
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		shadowRequests.Add(1)
+		shadowAuth.Store(r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"org-1","legalName":"Acme","status":{"code":"BLOCKED"},"updatedAt":"2026-02-01T00:00:00Z"}`))
+	}))
+	defer shadow.Close()
+
+	var (
+		mu          sync.Mutex
+		divergences []ShadowDivergence
+	)
+
+	entity, err := New(primary.URL, WithHTTPClient(primary.Client()), WithShadowTarget(ShadowConfig{
+		BaseURLs:     map[string]string{"onboarding": shadow.URL},
+		AuthToken:    "shadow-token",
+		IgnoreFields: []string{"updatedAt"},
+		OnDivergence: func(d ShadowDivergence) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			divergences = append(divergences, d)
+		},
+	}))
+	require.NoError(t, err)
+
+	orgID := "019c96a0-0000-7000-8000-000000000001"
+
+	org, err := entity.Organizations.GetOrganization(context.Background(), orgID)
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", org.LegalName, "the primary response is returned unchanged")
+
+	require.NoError(t, entity.Organizations.DeleteOrganization(context.Background(), orgID))
+	require.NoError(t, entity.Shadow().Wait(context.Background()))
+
+	assert.Equal(t, int64(1), shadowRequests.Load(), "only read requests are duplicated")
+	assert.Equal(t, "Bearer shadow-token", shadowAuth.Load())
+
+	stats := entity.Shadow().Stats()
+	assert.Equal(t, ShadowStats{Sampled: 1, Diverged: 1}, stats)
+
+	require.Len(t, divergences, 1)
+	assert.Equal(t, http.MethodGet, divergences[0].Method)
+	assert.Equal(t, "GET /organizations/{id}", divergences[0].Operation)
+	assert.Equal(t, http.StatusOK, divergences[0].ShadowStatus)
+	assert.Equal(t, []string{"status.code"}, divergences[0].Differences)
+}
+
+func TestEntityCloseShadow(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"org-1","legalName":"Acme"}`))
+	}))
+	defer primary.Close()
+
+	var shadowRequests atomic.Int64
+
+	release := make(chan struct{})
+
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		shadowRequests.Add(1)
+		<-release
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"org-1","legalName":"Acme"}`))
+	}))
+	defer shadow.Close()
+
+	tasks := lifecycle.Count("entities.Shadow")
+
+	entity, err := New(primary.URL, WithHTTPClient(primary.Client()), WithShadowTarget(ShadowConfig{
+		BaseURLs: map[string]string{"onboarding": shadow.URL},
+	}))
+	require.NoError(t, err)
+
+	orgID := "019c96a0-0000-7000-8000-000000000001"
+
+	_, err = entity.Organizations.GetOrganization(context.Background(), orgID)
+	require.NoError(t, err)
+	assert.Equal(t, tasks+1, lifecycle.Count("entities.Shadow"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, entity.Close(ctx), context.DeadlineExceeded, "Close waits for the shadow request in flight")
+
+	close(release)
+	require.NoError(t, entity.Close(context.Background()))
+	assert.Equal(t, tasks, lifecycle.Count("entities.Shadow"), "no shadow request is left running")
+
+	_, err = entity.Organizations.GetOrganization(context.Background(), orgID)
+	require.NoError(t, err, "the services still work after Close")
+	assert.Equal(t, int64(1), shadowRequests.Load(), "requests are no longer duplicated")
+}
+
+func TestShadowDiffBodies(t *testing.T) {
+	s, err := NewShadow(ShadowConfig{BaseURLs: map[string]string{"onboarding": "http://shadow"}})
+	require.NoError(t, err)
+
+	assert.Empty(t, s.diffBodies([]byte(`{"a":1,"b":[1,2]}`), []byte(`{"b":[1,2],"a":1}`)))
+	assert.Equal(t, []string{"a", "b", "c"}, s.diffBodies([]byte(`{"a":1,"b":[1,2]}`), []byte(`{"a":2,"b":[1],"c":true}`)))
+	assert.Equal(t, []string{"items[1].id"}, s.diffBodies([]byte(`{"items":[{"id":"x"},{"id":"y"}]}`), []byte(`{"items":[{"id":"x"},{"id":"z"}]}`)))
+	assert.Equal(t, []string{"body"}, s.diffBodies([]byte(`plain`), []byte(`other`)))
+	assert.Equal(t, []string{"$"}, s.diffBodies([]byte(`[]`), []byte(`{}`)))
+}
+
+func TestNewShadowValidation(t *testing.T) {
+	_, err := NewShadow(ShadowConfig{})
+	require.Error(t, err)
+
+	_, err = NewShadow(ShadowConfig{BaseURLs: map[string]string{"onboarding": "http://shadow"}, SampleRate: 1.5})
+	require.Error(t, err)
+
+	_, err = New("http://primary", WithShadowTarget(ShadowConfig{}))
+	require.Error(t, err)
+}
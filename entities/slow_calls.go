@@ -0,0 +1,109 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/usage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attributes set on the span of a slow call, see WithSlowCallThreshold.
+const (
+	AttributeSlowCall          = "midaz.slow_call"
+	AttributeSlowCallThreshold = "midaz.slow_call.threshold_ms"
+	AttributeSlowCallElapsed   = "midaz.slow_call.elapsed_ms"
+)
+
+// WithSlowCallThreshold returns an Option that flags the calls of the
+// Entity's services taking longer than threshold, retries included: a
+// warning naming the operation and the IDs of its path is logged, through
+// the observability logger when enabled and to stderr otherwise, and the
+// span of the call is marked with AttributeSlowCall. Zero disables it.
+func WithSlowCallThreshold(threshold time.Duration) Option {
+	return func(e *Entity) error {
+		if threshold < 0 {
+			return errors.New("slow call threshold cannot be negative")
+		}
+
+		e.httpClient.slowThreshold = threshold
+
+		return nil
+	}
+}
+
+// SetSlowCallThreshold sets the duration over which a call is logged and
+// marked as slow; zero disables it.
+func (c *HTTPClient) SetSlowCallThreshold(threshold time.Duration) {
+	c.slowThreshold = threshold
+}
+
+// observeSlowCall warns about a call that took longer than the slow call
+// threshold and marks its span.
+func (c *HTTPClient) observeSlowCall(ctx context.Context, method, requestURL string, elapsed time.Duration) {
+	if c.slowThreshold <= 0 || elapsed <= c.slowThreshold {
+		return
+	}
+
+	operation := usage.Operation(method, requestURL)
+	ids := pathIDs(requestURL)
+
+	if span := trace.SpanFromContext(ctx); span.IsRecording() {
+		span.SetAttributes(
+			attribute.Bool(AttributeSlowCall, true),
+			attribute.Int64(AttributeSlowCallThreshold, c.slowThreshold.Milliseconds()),
+			attribute.Int64(AttributeSlowCallElapsed, elapsed.Milliseconds()),
+		)
+		span.AddEvent("slow call", trace.WithAttributes(attribute.String("midaz.operation", operation)))
+	}
+
+	message := fmt.Sprintf("Slow call: %s took %v, over the %v threshold", operation, elapsed.Round(time.Millisecond), c.slowThreshold)
+	if len(ids) > 0 {
+		message += " (" + strings.Join(ids, ", ") + ")"
+	}
+
+	if c.observability != nil && c.observability.IsEnabled() && c.observability.Logger() != nil {
+		c.observability.Logger().Warnf("%s", message)
+		return
+	}
+
+	// The IDs come from the request path; escape them like debugLog does
+	_, _ = fmt.Fprintln(os.Stderr, "[Midaz SDK] "+html.EscapeString(message)) //#nosec G705 -- stderr is not an XSS sink
+}
+
+// pathIDs returns the IDs of the path of a request, each named after its
+// collection, such as "organizations=org-1".
+func pathIDs(requestURL string) []string {
+	p := requestURL
+	if u, err := url.Parse(requestURL); err == nil {
+		p = u.Path
+	}
+
+	if i := strings.Index(p, "/organizations"); i > 0 {
+		p = p[i:]
+	}
+
+	segments := strings.Split(strings.TrimSuffix(p, "/"), "/")
+	template := strings.Split(strings.TrimPrefix(usage.Operation("", requestURL), " "), "/")
+
+	if len(template) != len(segments) {
+		return nil
+	}
+
+	var ids []string
+
+	for i := 1; i < len(segments); i++ {
+		if template[i] == usage.Placeholder {
+			ids = append(ids, segments[i-1]+"="+segments[i])
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// slowCallProvider is an observability provider recording spans and logs in memory.
+type slowCallProvider struct {
+	observability.Provider
+	tracer trace.Tracer
+	logger observability.Logger
+}
+
+func (p *slowCallProvider) Tracer() trace.Tracer         { return p.tracer }
+func (*slowCallProvider) Meter() metric.Meter            { return noop.NewMeterProvider().Meter("test") }
+func (p *slowCallProvider) Logger() observability.Logger { return p.logger }
+func (*slowCallProvider) IsEnabled() bool                { return true }
+
+func TestWithSlowCallThreshold(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("slow") == "true" {
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"ledger-1","name":"Main"}`))
+	}))
+	defer srv.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	var logs bytes.Buffer
+
+	provider := &slowCallProvider{
+		tracer: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test"),
+		logger: observability.NewLogger(observability.WarnLevel, &logs, nil),
+	}
+
+	entity, err := New(srv.URL, WithObservability(provider), WithSlowCallThreshold(20*time.Millisecond), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	var result map[string]any
+
+	require.NoError(t, entity.httpClient.doRequest(context.Background(), http.MethodGet, srv.URL+"/v1/organizations/org-1/ledgers/ledger-1", nil, nil, &result))
+	assert.Empty(t, logs.String(), "fast calls aren't logged")
+
+	require.NoError(t, entity.httpClient.doRequest(context.Background(), http.MethodGet, srv.URL+"/v1/organizations/org-1/ledgers/ledger-1?slow=true", nil, nil, &result))
+	assert.Contains(t, logs.String(), "Slow call: GET /organizations/{id}/ledgers/{id} took")
+	assert.Contains(t, logs.String(), "(organizations=org-1, ledgers=ledger-1)")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	slow := map[string]bool{}
+	for _, span := range spans {
+		for _, attr := range span.Attributes() {
+			if string(attr.Key) == AttributeSlowCall {
+				slow[span.Name()] = attr.Value.AsBool()
+			}
+		}
+	}
+
+	assert.Len(t, slow, 1, "only the slow call's span is marked")
+	assert.Equal(t, "slow call", spans[1].Events()[0].Name)
+
+	_, err = New(srv.URL, WithSlowCallThreshold(-time.Second))
+	require.Error(t, err)
+}
+
+func TestSlowCallThresholdPropagation(t *testing.T) {
+	entity, err := New("http://localhost", WithSlowCallThreshold(time.Second))
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Second, entity.Ledgers.(*ledgersEntity).httpClient.slowThreshold)
+
+	entity.SetHTTPClient(&http.Client{})
+	assert.Equal(t, time.Second, entity.httpClient.slowThreshold, "kept across HTTP client replacement")
+}
+
+func TestPathIDs(t *testing.T) {
+	assert.Equal(t, []string{"organizations=org-1", "ledgers=l-1", "alias=@alice"},
+		pathIDs("https://midaz.example.com/v1/organizations/org-1/ledgers/l-1/accounts/alias/@alice/balances?limit=10"))
+	assert.Equal(t, []string{"organizations=org-1"}, pathIDs("/v1/organizations/org-1/ledgers"))
+	assert.Empty(t, pathIDs("/v1/organizations"))
+}
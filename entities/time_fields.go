@@ -0,0 +1,237 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/format"
+)
+
+// TimeFieldError is returned, wrapped, when a time field of a response can't
+// be parsed even tolerantly. Use errors.As to get it.
+type TimeFieldError struct {
+	// Field is the path of the field in the response, e.g. "items[2].createdAt"
+	Field string
+	// Value is the value returned by the API
+	Value string
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *TimeFieldError) Error() string {
+	return fmt.Sprintf("invalid time in field %s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns the parse error.
+func (e *TimeFieldError) Unwrap() error {
+	return e.Err
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// unmarshalResponse decodes a response into result. When the strict decode
+// fails, the time fields of the response that aren't RFC 3339 are parsed with
+// format.ParseTimestamp and the response is decoded again, so that minor
+// changes in the timestamp format of the backend don't break the SDK. The
+// times decoded are then moved to the time zone of the client, if any.
+func (c *HTTPClient) unmarshalResponse(result any, responseBody []byte) error {
+	err := c.jsonPool.Unmarshal(responseBody, result)
+	if err != nil {
+		normalized, changed, timeErr := normalizeTimeFields(result, responseBody)
+		if timeErr != nil {
+			return timeErr
+		}
+
+		if !changed {
+			return err
+		}
+
+		if v := reflect.ValueOf(result); v.Kind() == reflect.Pointer && !v.IsNil() {
+			v.Elem().SetZero()
+		}
+
+		if err := c.jsonPool.Unmarshal(normalized, result); err != nil {
+			return err
+		}
+	}
+
+	if c.timeZone != nil {
+		inTimeZone(reflect.ValueOf(result), c.timeZone)
+	}
+
+	return nil
+}
+
+// normalizeTimeFields rewrites the time fields of a response in RFC 3339,
+// reporting whether any was rewritten, or the first one that can't be parsed.
+func normalizeTimeFields(result any, body []byte) ([]byte, bool, error) {
+	t := reflect.TypeOf(result)
+	if t == nil {
+		return nil, false, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, false, nil
+	}
+
+	decoded, changed, err := normalizeTimes(t, decoded, "")
+	if err != nil || !changed {
+		return nil, false, err
+	}
+
+	normalized, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	return normalized, true, nil
+}
+
+// normalizeTimes walks v along type t and rewrites the time values that
+// aren't RFC 3339. Empty strings become null, leaving the times zero.
+func normalizeTimes(t reflect.Type, v any, path string) (any, bool, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		s, ok := v.(string)
+		if !ok {
+			return v, false, nil
+		}
+
+		if s == "" {
+			return nil, true, nil
+		}
+
+		if _, err := time.Parse(time.RFC3339, s); err == nil {
+			return v, false, nil
+		}
+
+		parsed, err := format.ParseTimestamp(s)
+		if err != nil {
+			return v, false, &TimeFieldError{Field: path, Value: s, Err: err}
+		}
+
+		return parsed.Format(time.RFC3339Nano), true, nil
+	}
+
+	if v == nil || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return v, false, nil
+	}
+
+	changed := false
+
+	normalize := func(t reflect.Type, v any, path string, set func(any)) error {
+		nv, ch, err := normalizeTimes(t, v, path)
+		if ch {
+			set(nv)
+
+			changed = true
+		}
+
+		return err
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v, false, nil
+		}
+
+		for _, f := range fieldsOf(t) {
+			key, ok := matchKey(obj, f.name)
+			if !ok {
+				continue
+			}
+
+			if err := normalize(f.typ, obj[key], joinDriftPath(path, key), func(nv any) { obj[key] = nv }); err != nil {
+				return v, false, err
+			}
+		}
+	case reflect.Map:
+		obj, ok := v.(map[string]any)
+		if !ok || t.Key().Kind() != reflect.String {
+			return v, false, nil
+		}
+
+		for key, value := range obj {
+			if err := normalize(t.Elem(), value, joinDriftPath(path, key), func(nv any) { obj[key] = nv }); err != nil {
+				return v, false, err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := v.([]any)
+		if !ok {
+			return v, false, nil
+		}
+
+		for i, item := range items {
+			if err := normalize(t.Elem(), item, fmt.Sprintf("%s[%d]", path, i), func(nv any) { items[i] = nv }); err != nil {
+				return v, false, err
+			}
+		}
+	}
+
+	return v, changed, nil
+}
+
+// inTimeZone moves the non-zero times reachable from v through exported
+// fields, pointers, slices and arrays to loc. Times held in maps are left
+// unchanged, since map values can't be set in place.
+func inTimeZone(v reflect.Value, loc *time.Location) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			inTimeZone(v.Elem(), loc)
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			if t := v.Interface().(time.Time); v.CanSet() && !t.IsZero() {
+				v.Set(reflect.ValueOf(t.In(loc)))
+			}
+
+			return
+		}
+
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				inTimeZone(v.Field(i), loc)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			inTimeZone(v.Index(i), loc)
+		}
+	}
+}
+
+// WithTimeZone returns an Option that moves the times of the responses of
+// the services, such as CreatedAt, to loc. By default they keep the offset
+// returned by the API, usually UTC.
+func WithTimeZone(loc *time.Location) Option {
+	return func(e *Entity) error {
+		if loc == nil {
+			return errors.New("time zone is required")
+		}
+
+		e.httpClient.timeZone = loc
+
+		return nil
+	}
+}
+
+// SetTimeZone sets the time zone the HTTP client moves the times of the
+// responses to; nil keeps the offset returned by the API.
+func (c *HTTPClient) SetTimeZone(loc *time.Location) {
+	c.timeZone = loc
+}
@@ -0,0 +1,91 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func organizationServer(createdAt string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		org := `{"id":"1","legalName":"Acme","createdAt":"` + createdAt + `","updatedAt":"2026-01-01T10:00:00Z","deletedAt":""}`
+
+		if r.URL.Path == "/organizations" {
+			_, _ = w.Write([]byte(`{"items":[{"id":"0","createdAt":"2026-01-01T10:00:00Z"},` + org + `]}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(org))
+	}))
+}
+
+func TestTolerantTimeParsing(t *testing.T) {
+	srv := organizationServer("2026-01-01 07:00:00.5-0300")
+	defer srv.Close()
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	org, err := entity.Organizations.GetOrganization(context.Background(), "1")
+	require.NoError(t, err)
+
+	assert.True(t, time.Date(2026, 1, 1, 10, 0, 0, 5e8, time.UTC).Equal(org.CreatedAt), "got %s", org.CreatedAt)
+	assert.True(t, time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC).Equal(org.UpdatedAt))
+	assert.Nil(t, org.DeletedAt, "an empty time is left unset")
+	assert.Equal(t, "Acme", org.LegalName)
+
+	list, err := entity.Organizations.ListOrganizations(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.True(t, org.CreatedAt.Equal(list.Items[1].CreatedAt))
+}
+
+func TestTimeFieldError(t *testing.T) {
+	srv := organizationServer("yesterday")
+	defer srv.Close()
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	_, err = entity.Organizations.GetOrganization(context.Background(), "1")
+
+	var timeErr *TimeFieldError
+	require.True(t, errors.As(err, &timeErr), "got %v", err)
+	assert.Equal(t, "createdAt", timeErr.Field)
+	assert.Equal(t, "yesterday", timeErr.Value)
+
+	_, err = entity.Organizations.ListOrganizations(context.Background(), nil)
+	require.True(t, errors.As(err, &timeErr))
+	assert.Equal(t, "items[1].createdAt", timeErr.Field)
+	assert.Contains(t, err.Error(), "invalid time in field items[1].createdAt")
+}
+
+func TestWithTimeZone(t *testing.T) {
+	srv := organizationServer("2026-01-01T10:00:00Z")
+	defer srv.Close()
+
+	saoPaulo := time.FixedZone("BRT", -3*3600)
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()), WithTimeZone(saoPaulo))
+	require.NoError(t, err)
+
+	org, err := entity.Organizations.GetOrganization(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, saoPaulo, org.CreatedAt.Location())
+	assert.Equal(t, 7, org.CreatedAt.Hour())
+
+	list, err := entity.Organizations.ListOrganizations(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, saoPaulo, list.Items[0].CreatedAt.Location())
+
+	_, err = New(srv.URL, WithTimeZone(nil))
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// TokenRefresher returns a new authentication token, e.g. from the access
+// manager, when the current one is rejected.
+type TokenRefresher func(ctx context.Context) (string, error)
+
+// tokenSource holds the authentication token shared by the HTTP clients of an
+// Entity's services, so that a token refreshed by one service is used by all.
+type tokenSource struct {
+	mu      sync.Mutex
+	token   string
+	refresh TokenRefresher
+
+	// noExpiry stops renewing tokens before they expire, see renewExpiredToken
+	noExpiry bool
+}
+
+func newTokenSource(token string, refresh TokenRefresher) *tokenSource {
+	return &tokenSource{token: token, refresh: refresh}
+}
+
+// current returns the current token.
+func (s *tokenSource) current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.token
+}
+
+// set replaces the current token.
+func (s *tokenSource) set(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = token
+	s.noExpiry = false
+}
+
+// disableExpiry stops the renewal of tokens before they expire.
+func (s *tokenSource) disableExpiry() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.noExpiry = true
+}
+
+// expiryDisabled reports whether tokens are only renewed after a 401.
+func (s *tokenSource) expiryDisabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.noExpiry
+}
+
+// renew returns a token to replace stale, the token a request was rejected
+// with. Concurrent requests rejected with the same token trigger a single
+// refresh: the ones that find the token already replaced reuse the new one.
+func (s *tokenSource) renew(ctx context.Context, stale string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != stale {
+		return s.token, nil
+	}
+
+	token, err := s.refresh(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if token == "" {
+		return "", errors.New("token refresher returned an empty token")
+	}
+
+	s.token = token
+
+	return token, nil
+}
+
+// WithTokenRefresher returns an Option that recovers from expired tokens: when
+// a request fails with 401 Unauthorized, the token is renewed with refresh and
+// the request is sent again, exactly once. Only requests that are safe to
+// repeat are retried: GET, HEAD, OPTIONS, PUT and DELETE, and any request
+// carrying an idempotency key (see WithIdempotencyKey). Other requests, and a
+// second 401, surface the authentication error.
+//
+// WithPluginAuth installs a refresher getting a new token from the access
+// manager, so plugin-authenticated clients don't need this option.
+//
+// Example:
+//
+//	entity, err := entities.NewEntity(client, token, baseURLs, nil,
+//	    entities.WithTokenRefresher(func(ctx context.Context) (string, error) {
+//	        return oidc.Token(ctx)
+//	    }),
+//	)
+func WithTokenRefresher(refresh TokenRefresher) Option {
+	return func(e *Entity) error {
+		if refresh == nil {
+			return errors.New("token refresher cannot be nil")
+		}
+
+		e.httpClient.tokens = newTokenSource(e.httpClient.authToken, refresh)
+
+		return nil
+	}
+}
+
+// WithAuthFrom returns an Option that makes the Entity authenticate with the
+// token of parent and share its renewal, instead of requesting a token of
+// its own, e.g. for a variant of a client with other timeouts. Use it instead
+// of WithPluginAuth.
+func WithAuthFrom(parent *Entity) Option {
+	return func(e *Entity) error {
+		if parent == nil {
+			return errors.New("parent entity cannot be nil")
+		}
+
+		e.httpClient.authToken = parent.httpClient.authToken
+		e.httpClient.tokens = parent.httpClient.tokens
+
+		return nil
+	}
+}
+
+// SetTokenRefresher installs a refresher renewing the token of the Entity when
+// a request fails with 401 Unauthorized, see WithTokenRefresher. It is used by
+// auth.WithAccessManager.
+func (e *Entity) SetTokenRefresher(refresh func(ctx context.Context) (string, error)) {
+	if refresh == nil {
+		return
+	}
+
+	e.httpClient.tokens = newTokenSource(e.httpClient.authToken, refresh)
+}
+
+// currentToken returns the token to authenticate requests with.
+func (c *HTTPClient) currentToken() string {
+	if c.tokens != nil {
+		return c.tokens.current()
+	}
+
+	return c.authToken
+}
+
+// canRefreshToken reports whether a request rejected with err can be sent
+// again with a renewed token.
+func (c *HTTPClient) canRefreshToken(req *http.Request, err error) bool {
+	if c.tokens == nil || !sdkerrors.IsAuthenticationError(err) {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return req.Header.Get("X-Idempotency") != ""
+}
@@ -0,0 +1,196 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenServer accepts requests authenticated with the token "fresh" and rejects
+// the others with 401.
+func tokenServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if r.Header.Get("Authorization") != "fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":"0042","message":"token expired"}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"id":"org-1"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+func newTokenRefreshEntity(t *testing.T, srv *httptest.Server, refresh TokenRefresher) *Entity {
+	t.Helper()
+
+	baseURLs := map[string]string{"onboarding": srv.URL, "transaction": srv.URL}
+
+	entity, err := NewEntity(srv.Client(), "expired", baseURLs, nil, WithTokenRefresher(refresh))
+	require.NoError(t, err)
+
+	return entity
+}
+
+func TestTokenRefreshRetriesOnce(t *testing.T) {
+	srv, requests := tokenServer(t)
+
+	var refreshes atomic.Int32
+
+	entity := newTokenRefreshEntity(t, srv, func(context.Context) (string, error) {
+		refreshes.Add(1)
+		return "fresh", nil
+	})
+
+	org, err := entity.Organizations.GetOrganization(context.Background(), "org-1")
+	require.NoError(t, err)
+	assert.Equal(t, "org-1", org.ID)
+	assert.Equal(t, int32(1), refreshes.Load())
+	assert.Equal(t, int32(2), requests.Load())
+
+	// the renewed token is shared by the other services
+	_, err = entity.Ledgers.GetLedger(context.Background(), "org-1", "ledger-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), refreshes.Load())
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestTokenRefreshStillUnauthorized(t *testing.T) {
+	srv, requests := tokenServer(t)
+
+	entity := newTokenRefreshEntity(t, srv, func(context.Context) (string, error) {
+		return "also-expired", nil
+	})
+
+	_, err := entity.Organizations.GetOrganization(context.Background(), "org-1")
+	require.Error(t, err)
+	assert.True(t, sdkerrors.IsAuthenticationError(err))
+	assert.Equal(t, int32(2), requests.Load(), "the request is retried exactly once")
+}
+
+func TestTokenRefreshFailure(t *testing.T) {
+	srv, requests := tokenServer(t)
+
+	entity := newTokenRefreshEntity(t, srv, func(context.Context) (string, error) {
+		return "", errors.New("access manager unavailable")
+	})
+
+	_, err := entity.Organizations.GetOrganization(context.Background(), "org-1")
+	require.Error(t, err)
+	assert.True(t, sdkerrors.IsAuthenticationError(err), "the original error is surfaced")
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestTokenRefreshOnlyRepeatableRequests(t *testing.T) {
+	srv, requests := tokenServer(t)
+
+	var refreshes atomic.Int32
+
+	entity := newTokenRefreshEntity(t, srv, func(context.Context) (string, error) {
+		refreshes.Add(1)
+		return "fresh", nil
+	})
+	client := entity.GetEntityHTTPClient()
+
+	var result map[string]any
+
+	err := client.doRequest(context.Background(), http.MethodPost, srv.URL+"/v1/organizations", nil, map[string]string{"legalName": "Acme"}, &result)
+	require.Error(t, err, "a POST without idempotency key is not repeated")
+	assert.Equal(t, int32(0), refreshes.Load())
+	assert.Equal(t, int32(1), requests.Load())
+
+	ctx := WithIdempotencyKey(context.Background(), "key-1")
+	err = client.doRequest(ctx, http.MethodPost, srv.URL+"/v1/organizations", nil, map[string]string{"legalName": "Acme"}, &result)
+	require.NoError(t, err, "a keyed POST is repeated")
+	assert.Equal(t, int32(1), refreshes.Load())
+	assert.Equal(t, "org-1", result["id"])
+}
+
+func TestTokenRefreshConcurrentRequests(t *testing.T) {
+	srv, _ := tokenServer(t)
+
+	var refreshes atomic.Int32
+
+	entity := newTokenRefreshEntity(t, srv, func(context.Context) (string, error) {
+		refreshes.Add(1)
+		return "fresh", nil
+	})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := entity.Organizations.GetOrganization(context.Background(), "org-1")
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, int32(1), refreshes.Load(), "requests rejected with the same token share a refresh")
+}
+
+func TestTokenRefreshSurvivesSetters(t *testing.T) {
+	srv, _ := tokenServer(t)
+
+	entity := newTokenRefreshEntity(t, srv, func(context.Context) (string, error) {
+		return "fresh", nil
+	})
+
+	entity.SetHTTPClient(srv.Client())
+	entity.SetAuthToken("fresh")
+
+	assert.Equal(t, "fresh", entity.GetEntityHTTPClient().currentToken())
+
+	_, err := entity.Organizations.GetOrganization(context.Background(), "org-1")
+	require.NoError(t, err)
+}
+
+func TestWithAuthFromSharesRenewal(t *testing.T) {
+	srv, requests := tokenServer(t)
+
+	var refreshes atomic.Int32
+
+	parent := newTokenRefreshEntity(t, srv, func(context.Context) (string, error) {
+		refreshes.Add(1)
+		return "fresh", nil
+	})
+
+	baseURLs := map[string]string{"onboarding": srv.URL, "transaction": srv.URL}
+
+	derived, err := NewWithServiceURLs(baseURLs, WithHTTPClient(srv.Client()), WithAuthFrom(parent))
+	require.NoError(t, err)
+
+	_, err = derived.Organizations.GetOrganization(context.Background(), "org-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), refreshes.Load())
+
+	// the token renewed by the derived entity is used by the parent
+	_, err = parent.Ledgers.GetLedger(context.Background(), "org-1", "ledger-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), refreshes.Load())
+	assert.Equal(t, int32(3), requests.Load())
+
+	_, err = NewWithServiceURLs(baseURLs, WithAuthFrom(nil))
+	require.Error(t, err)
+}
@@ -111,8 +111,9 @@ type transactionRoutesEntity struct {
 	baseURLs   map[string]string
 }
 
-func (e *transactionRoutesEntity) setDefaultTenantID(tenantID string) {
-	e.httpClient.SetTenantID(tenantID)
+// useSettings makes the service share the settings of its Entity.
+func (e *transactionRoutesEntity) useSettings(settings *clientSettings) {
+	e.httpClient.clientSettings = settings
 }
 
 // NewTransactionRoutesEntity creates a new TransactionRoutesService instance
@@ -223,7 +224,7 @@ func (e *transactionRoutesEntity) CreateTransactionRoute(ctx context.Context, or
 		return nil, errors.NewMissingParameterError(operation, "input")
 	}
 
-	if err := input.Validate(); err != nil {
+	if err := e.httpClient.validateInput(ctx, operation, input.Validate); err != nil {
 		return nil, errors.NewValidationError(operation, "transaction route validation failed", err)
 	}
 
@@ -269,7 +270,7 @@ func (e *transactionRoutesEntity) UpdateTransactionRoute(ctx context.Context, or
 		return nil, errors.NewMissingParameterError(operation, "input")
 	}
 
-	if err := input.Validate(); err != nil {
+	if err := e.httpClient.validateInput(ctx, operation, input.Validate); err != nil {
 		return nil, errors.NewValidationError(operation, "transaction route validation failed", err)
 	}
 
@@ -103,8 +103,23 @@ type transactionsEntity struct {
 	baseURLs   map[string]string
 }
 
-func (e *transactionsEntity) setDefaultTenantID(tenantID string) {
-	e.httpClient.SetTenantID(tenantID)
+// useSettings makes the service share the settings of its Entity.
+func (e *transactionsEntity) useSettings(settings *clientSettings) {
+	e.httpClient.clientSettings = settings
+}
+
+// applyMetadataTemplate returns metadata with the client metadata template applied, if any.
+func (e *transactionsEntity) applyMetadataTemplate(operation, orgID, ledgerID string, input any, metadata map[string]any) (map[string]any, error) {
+	if e.httpClient.metadataTemplate == nil {
+		return metadata, nil
+	}
+
+	applied, err := e.httpClient.metadataTemplate.Apply(operation, orgID, ledgerID, input, metadata)
+	if err != nil {
+		return nil, sdkerrors.NewValidationError(operation, "invalid metadata template", err)
+	}
+
+	return applied, nil
 }
 
 // NewTransactionsEntity creates a new transactions entity.
@@ -161,10 +176,23 @@ func (e *transactionsEntity) CreateTransaction(ctx context.Context, orgID, ledge
 	const operation = "CreateTransaction"
 
 	// Validate input parameters
-	if err := e.validateCreateTransactionInput(operation, orgID, ledgerID, input); err != nil {
+	if err := e.validateCreateTransactionInput(ctx, operation, orgID, ledgerID, input); err != nil {
+		return nil, err
+	}
+
+	if err := e.httpClient.assetFreezes.check(operation, orgID, ledgerID, transactionAssets(input)...); err != nil {
+		return nil, err
+	}
+
+	metadata, err := e.applyMetadataTemplate(operation, orgID, ledgerID, input, input.Metadata)
+	if err != nil {
 		return nil, err
 	}
 
+	templated := *input
+	templated.Metadata = metadata
+	input = &templated
+
 	// Send request to API
 	responseMap, err := e.sendCreateTransactionRequest(ctx, orgID, ledgerID, input)
 	if err != nil {
@@ -176,7 +204,7 @@ func (e *transactionsEntity) CreateTransaction(ctx context.Context, orgID, ledge
 }
 
 // validateCreateTransactionInput validates all input parameters for CreateTransaction
-func (*transactionsEntity) validateCreateTransactionInput(operation, orgID, ledgerID string, input *models.CreateTransactionInput) error {
+func (e *transactionsEntity) validateCreateTransactionInput(ctx context.Context, operation, orgID, ledgerID string, input *models.CreateTransactionInput) error {
 	if input == nil {
 		return sdkerrors.NewMissingParameterError(operation, "input")
 	}
@@ -189,15 +217,17 @@ func (*transactionsEntity) validateCreateTransactionInput(operation, orgID, ledg
 		return sdkerrors.NewMissingParameterError(operation, "ledger ID")
 	}
 
-	if err := input.Validate(); err != nil {
-		return sdkerrors.NewValidationError(operation, "transaction validation failed", err)
-	}
+	return e.httpClient.validateInput(ctx, operation, func() error {
+		if err := input.Validate(); err != nil {
+			return sdkerrors.NewValidationError(operation, "transaction validation failed", err)
+		}
 
-	if input.Send == nil && len(input.Operations) == 0 {
-		return sdkerrors.NewValidationError(operation, "transaction must have at least one operation", nil)
-	}
+		if input.Send == nil && len(input.Operations) == 0 {
+			return sdkerrors.NewValidationError(operation, "transaction must have at least one operation", nil)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // sendCreateTransactionRequest sends the transaction creation request
@@ -353,6 +383,19 @@ func (e *transactionsEntity) CreateTransactionWithDSL(ctx context.Context, orgID
 		return nil, sdkerrors.NewMissingParameterError(operation, "ledger ID")
 	}
 
+	if err := e.httpClient.assetFreezes.check(operation, orgID, ledgerID, input.GetAsset()); err != nil {
+		return nil, err
+	}
+
+	metadata, err := e.applyMetadataTemplate(operation, orgID, ledgerID, input, input.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	templated := *input
+	templated.Metadata = metadata
+	input = &templated
+
 	// Convert the DSL input to map format before sending to API
 	// Use the strongly-typed converter to include send/source/distribute, share, rate, etc.
 	transactionMap := input.ToTransactionMap()
@@ -394,6 +437,10 @@ func (e *transactionsEntity) CreateTransactionWithDSLFile(ctx context.Context, o
 		return nil, err
 	}
 
+	if err := e.httpClient.assetFreezes.check("CreateTransactionWithDSLFile", orgID, ledgerID, dslAssets(dslContent)...); err != nil {
+		return nil, err
+	}
+
 	// Use DSL endpoint with raw body payload
 	url := e.buildURL(orgID, ledgerID, "/dsl")
 
@@ -696,10 +743,25 @@ func (e *transactionsEntity) CreateInflowTransaction(ctx context.Context, orgID,
 		return nil, sdkerrors.NewMissingParameterError(operation, "input")
 	}
 
-	if err := input.Validate(); err != nil {
+	if err := e.httpClient.validateInput(ctx, operation, input.Validate); err != nil {
 		return nil, sdkerrors.NewValidationError(operation, "invalid input", err)
 	}
 
+	if input.Send != nil {
+		if err := e.httpClient.assetFreezes.check(operation, orgID, ledgerID, input.Send.Asset); err != nil {
+			return nil, err
+		}
+	}
+
+	metadata, err := e.applyMetadataTemplate(operation, orgID, ledgerID, input, input.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	templated := *input
+	templated.Metadata = metadata
+	input = &templated
+
 	url := e.buildURL(orgID, ledgerID, "/inflow")
 
 	body, err := json.Marshal(input)
@@ -738,10 +800,25 @@ func (e *transactionsEntity) CreateOutflowTransaction(ctx context.Context, orgID
 		return nil, sdkerrors.NewMissingParameterError(operation, "input")
 	}
 
-	if err := input.Validate(); err != nil {
+	if err := e.httpClient.validateInput(ctx, operation, input.Validate); err != nil {
 		return nil, sdkerrors.NewValidationError(operation, "invalid input", err)
 	}
 
+	if input.Send != nil {
+		if err := e.httpClient.assetFreezes.check(operation, orgID, ledgerID, input.Send.Asset); err != nil {
+			return nil, err
+		}
+	}
+
+	metadata, err := e.applyMetadataTemplate(operation, orgID, ledgerID, input, input.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	templated := *input
+	templated.Metadata = metadata
+	input = &templated
+
 	url := e.buildURL(orgID, ledgerID, "/outflow")
 
 	body, err := json.Marshal(input)
@@ -780,10 +857,19 @@ func (e *transactionsEntity) CreateAnnotationTransaction(ctx context.Context, or
 		return nil, sdkerrors.NewMissingParameterError(operation, "input")
 	}
 
-	if err := input.Validate(); err != nil {
+	if err := e.httpClient.validateInput(ctx, operation, input.Validate); err != nil {
 		return nil, sdkerrors.NewValidationError(operation, "invalid input", err)
 	}
 
+	metadata, err := e.applyMetadataTemplate(operation, orgID, ledgerID, input, input.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	templated := *input
+	templated.Metadata = metadata
+	input = &templated
+
 	url := e.buildURL(orgID, ledgerID, "/annotation")
 
 	body, err := json.Marshal(input)
@@ -0,0 +1,247 @@
+package entities
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// DefaultUnknownFieldStoreSize is the number of resources kept by an
+// UnknownFieldStore created with a non-positive size.
+const DefaultUnknownFieldStoreSize = 1000
+
+// UnknownFieldStore keeps the response fields that the SDK models don't know,
+// by resource, and adds them back to the updates of the same resource, so
+// that fields added by a newer backend are not clobbered by an SDK that
+// would otherwise drop them. It keeps the most recently used resources and is
+// safe for concurrent use.
+type UnknownFieldStore struct {
+	size int
+
+	mu        sync.Mutex
+	order     *list.List // resource URLs, most recently used first
+	resources map[string]*list.Element
+}
+
+type unknownFieldEntry struct {
+	resource string
+	fields   map[string]json.RawMessage
+}
+
+// NewUnknownFieldStore creates an UnknownFieldStore keeping the unknown
+// fields of the last size resources.
+func NewUnknownFieldStore(size int) *UnknownFieldStore {
+	if size <= 0 {
+		size = DefaultUnknownFieldStoreSize
+	}
+
+	return &UnknownFieldStore{size: size, order: list.New(), resources: make(map[string]*list.Element)}
+}
+
+// Fields returns the unknown fields kept for a resource URL, such as
+// "https://onboarding.example.com/v1/organizations/{id}", by path (e.g.
+// "address.geo"), or nil.
+func (s *UnknownFieldStore) Fields(resourceURL string) map[string]json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.resources[resourceKey(resourceURL)]
+	if !ok {
+		return nil
+	}
+
+	fields := make(map[string]json.RawMessage, len(elem.Value.(*unknownFieldEntry).fields))
+	for path, raw := range elem.Value.(*unknownFieldEntry).fields {
+		fields[path] = raw
+	}
+
+	return fields
+}
+
+// Len returns the number of resources with unknown fields.
+func (s *UnknownFieldStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.resources)
+}
+
+// Forget drops the unknown fields kept for a resource URL.
+func (s *UnknownFieldStore) Forget(resourceURL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(resourceKey(resourceURL))
+}
+
+func (s *UnknownFieldStore) remove(key string) {
+	if elem, ok := s.resources[key]; ok {
+		s.order.Remove(elem)
+		delete(s.resources, key)
+	}
+}
+
+// retain replaces the unknown fields of a resource with those of a response
+// to a read or an update. Fields inside arrays are left out, since their
+// elements can't be matched reliably with those of a later update.
+func (s *UnknownFieldStore) retain(method, requestURL string, result any, responseBody []byte) {
+	key := resourceKey(requestURL)
+
+	if method == http.MethodDelete {
+		s.Forget(key)
+		return
+	}
+
+	if result == nil || (method != http.MethodGet && method != http.MethodPatch && method != http.MethodPut) {
+		return
+	}
+
+	fields := unknownFieldsOf(result, responseBody)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(fields) == 0 {
+		s.remove(key)
+		return
+	}
+
+	if elem, ok := s.resources[key]; ok {
+		elem.Value.(*unknownFieldEntry).fields = fields
+		s.order.MoveToFront(elem)
+
+		return
+	}
+
+	s.resources[key] = s.order.PushFront(&unknownFieldEntry{resource: key, fields: fields})
+
+	if s.order.Len() > s.size {
+		s.remove(s.order.Back().Value.(*unknownFieldEntry).resource)
+	}
+}
+
+// merge adds the unknown fields kept for the resource of an update to its
+// body. A field is added only where the body has its parent object and
+// lacks the field, so values set by the caller always win and a partial
+// update never gains objects it didn't touch.
+func (s *UnknownFieldStore) merge(method, requestURL string, body any) any {
+	if body == nil || (method != http.MethodPatch && method != http.MethodPut) {
+		return body
+	}
+
+	fields := s.Fields(requestURL)
+	if len(fields) == 0 {
+		return body
+	}
+
+	obj, ok := body.(map[string]any)
+	if !ok {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return body
+		}
+
+		// Numbers are kept as json.Number, so that integers beyond 2^53 are
+		// sent back exactly rather than rounded through float64
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		if decoder.Decode(&obj) != nil || obj == nil {
+			return body
+		}
+	}
+
+	for path, raw := range fields {
+		segments := strings.Split(path, ".")
+		parent := obj
+
+		for _, segment := range segments[:len(segments)-1] {
+			next, ok := parent[segment].(map[string]any)
+			if !ok {
+				parent = nil
+				break
+			}
+
+			parent = next
+		}
+
+		if parent == nil {
+			continue
+		}
+
+		if _, set := parent[segments[len(segments)-1]]; !set {
+			parent[segments[len(segments)-1]] = raw
+		}
+	}
+
+	return obj
+}
+
+// unknownFieldsOf returns the raw values of the fields of a response body
+// that its model doesn't know, by path, leaving out the fields inside arrays.
+func unknownFieldsOf(result any, body []byte) map[string]json.RawMessage {
+	t := reflect.TypeOf(result)
+	if t == nil || len(body) == 0 {
+		return nil
+	}
+
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil
+	}
+
+	w := &driftWalker{preserve: true}
+	w.walk(t, decoded, "", "")
+
+	for path := range w.extra {
+		if strings.Contains(path, "[") {
+			delete(w.extra, path)
+		}
+	}
+
+	return w.extra
+}
+
+// resourceKey identifies a resource by its URL without query or trailing slash.
+func resourceKey(resourceURL string) string {
+	u, err := url.Parse(resourceURL)
+	if err != nil {
+		return resourceURL
+	}
+
+	u.RawQuery, u.Fragment = "", ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+
+	return u.String()
+}
+
+// WithUnknownFieldRoundTrip returns an Option that keeps the response fields
+// the SDK models don't know, for the last size resources read or updated, and
+// sends them back on the PATCH and PUT requests of the same resource. It
+// protects fields added by a newer backend from being clobbered by updates
+// sent through an SDK that doesn't model them yet. A non-positive size keeps
+// DefaultUnknownFieldStoreSize resources.
+func WithUnknownFieldRoundTrip(size int) Option {
+	return func(e *Entity) error {
+		e.httpClient.unknownFields = NewUnknownFieldStore(size)
+
+		return nil
+	}
+}
+
+// UnknownFields returns the store set with WithUnknownFieldRoundTrip, or nil.
+func (e *Entity) UnknownFields() *UnknownFieldStore {
+	return e.httpClient.unknownFields
+}
+
+// SetUnknownFieldStore sets the store keeping the unknown response fields of
+// the HTTP client; nil disables the round trip.
+func (c *HTTPClient) SetUnknownFieldStore(store *UnknownFieldStore) {
+	c.unknownFields = store
+}
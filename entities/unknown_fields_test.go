@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithUnknownFieldRoundTrip(t *testing.T) {
+	var patched map[string]any
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case http.MethodPatch:
+			body, _ := io.ReadAll(r.Body)
+			assert.NoError(t, json.Unmarshal(body, &patched))
+
+			_, _ = w.Write([]byte(`{"id":"1","legalName":"Acme"}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write([]byte(driftOrganization))
+		}
+	}))
+	defer srv.Close()
+
+	entity, err := New(srv.URL, WithHTTPClient(srv.Client()), WithUnknownFieldRoundTrip(0))
+	require.NoError(t, err)
+
+	const id = "019c96a0-0000-7000-8000-000000000001"
+
+	_, err = entity.Organizations.GetOrganization(context.Background(), id)
+	require.NoError(t, err)
+
+	store := entity.UnknownFields()
+	require.Equal(t, 1, store.Len())
+
+	resource := srv.URL + "/organizations/" + id
+	assert.JSONEq(t, `7`, string(store.Fields(resource + "?ignored=1")["riskScore"]))
+
+	input := models.NewUpdateOrganizationInput().
+		WithLegalName("Acme 2").
+		WithAddressUpdate(models.Address{Line1: "Other St", Country: "BR"})
+
+	_, err = entity.Organizations.UpdateOrganization(context.Background(), id, input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Acme 2", patched["legalName"], "values set by the caller win")
+	assert.Equal(t, float64(7), patched["riskScore"])
+	assert.Equal(t, map[string]any{"lat": float64(1)}, patched["address"].(map[string]any)["geo"])
+
+	assert.Zero(t, store.Len(), "the update response had no unknown fields")
+
+	_, err = entity.Organizations.GetOrganization(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, 1, store.Len())
+
+	require.NoError(t, entity.Organizations.DeleteOrganization(context.Background(), id))
+	assert.Zero(t, store.Len())
+}
+
+func TestUnknownFieldStore(t *testing.T) {
+	type model struct {
+		ID    string `json:"id"`
+		Items []struct {
+			Name string `json:"name"`
+		} `json:"items"`
+	}
+
+	store := NewUnknownFieldStore(2)
+
+	store.retain(http.MethodGet, "http://a/r/1", &model{}, []byte(`{"id":"1","new":true,"items":[{"name":"x","deep":1}]}`))
+	assert.Equal(t, map[string]json.RawMessage{"new": json.RawMessage(`true`)}, store.Fields("http://a/r/1/"),
+		"fields inside arrays are left out")
+
+	store.retain(http.MethodPost, "http://a/r", &model{}, []byte(`{"id":"2","new":true}`))
+	assert.Nil(t, store.Fields("http://a/r"), "only reads and updates are kept")
+
+	store.retain(http.MethodGet, "http://a/r/2", &model{}, []byte(`{"id":"2","new":2}`))
+	store.retain(http.MethodGet, "http://a/r/1", &model{}, []byte(`{"id":"1","new":true}`))
+	store.retain(http.MethodGet, "http://a/r/3", &model{}, []byte(`{"id":"3","new":3}`))
+
+	assert.Equal(t, 2, store.Len())
+	assert.Nil(t, store.Fields("http://a/r/2"), "the least recently used resource is evicted")
+	assert.NotNil(t, store.Fields("http://a/r/1"))
+
+	merged := store.merge(http.MethodPut, "http://a/r/3", struct {
+		ID string `json:"id"`
+	}{ID: "3"})
+	assert.Equal(t, map[string]any{"id": "3", "new": json.RawMessage(`3`)}, merged)
+
+	merged = store.merge(http.MethodPatch, "http://a/r/3", struct {
+		Metadata map[string]any `json:"metadata"`
+	}{Metadata: map[string]any{"big": uint64(9007199254740993)}})
+	body, err := json.Marshal(merged)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"metadata":{"big":9007199254740993},"new":3}`, string(body))
+	assert.Contains(t, string(body), "9007199254740993", "integers beyond 2^53 are sent back exactly")
+
+	unchanged := map[string]any{"id": "3"}
+	assert.Equal(t, unchanged, store.merge(http.MethodPost, "http://a/r/3", unchanged))
+
+	store.Forget("http://a/r/3")
+	assert.Equal(t, 1, store.Len())
+}
@@ -0,0 +1,284 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// ErrUpdateRejected is returned by GuardUpdate when the confirmation callback
+// rejects a diff.
+var ErrUpdateRejected = errors.New("update rejected")
+
+// Diff operations, named after their RFC 6902 JSON patch counterparts.
+const (
+	DiffOpAdd     = "add"
+	DiffOpReplace = "replace"
+	DiffOpRemove  = "remove"
+)
+
+// FieldChange is a single field an update would change.
+type FieldChange struct {
+	// Path is the dotted JSON path of the field (e.g., "metadata.tier").
+	Path string `json:"path"`
+
+	// Op is one of DiffOpAdd, DiffOpReplace or DiffOpRemove.
+	Op string `json:"op"`
+
+	// From is the current value, nil when the field is added.
+	From any `json:"from,omitempty"`
+
+	// To is the new value, nil when the field is removed.
+	To any `json:"to,omitempty"`
+
+	pointer []string
+}
+
+// UpdateDiff describes what an Update* call would change on an entity.
+type UpdateDiff struct {
+	Changes []FieldChange `json:"changes"`
+}
+
+// HasChanges reports whether the update changes anything.
+func (d *UpdateDiff) HasChanges() bool {
+	return d != nil && len(d.Changes) > 0
+}
+
+// String returns the diff as one line per change, prefixed with "+" for added,
+// "~" for replaced and "-" for removed fields.
+func (d *UpdateDiff) String() string {
+	if !d.HasChanges() {
+		return "no changes"
+	}
+
+	var b strings.Builder
+
+	for i, c := range d.Changes {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+
+		switch c.Op {
+		case DiffOpAdd:
+			fmt.Fprintf(&b, "+ %s: %s", c.Path, formatDiffValue(c.To))
+		case DiffOpRemove:
+			fmt.Fprintf(&b, "- %s: %s", c.Path, formatDiffValue(c.From))
+		default:
+			fmt.Fprintf(&b, "~ %s: %s -> %s", c.Path, formatDiffValue(c.From), formatDiffValue(c.To))
+		}
+	}
+
+	return b.String()
+}
+
+// JSONPatch returns the diff as an RFC 6902 JSON patch.
+func (d *UpdateDiff) JSONPatch() ([]byte, error) {
+	type patchOp struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value,omitempty"`
+	}
+
+	ops := make([]patchOp, 0, len(d.Changes))
+
+	for _, c := range d.Changes {
+		ops = append(ops, patchOp{Op: c.Op, Path: jsonPointer(c.pointer), Value: c.To})
+	}
+
+	return json.Marshal(ops)
+}
+
+// DiffUpdate compares an entity with the input of the Update* call about to be
+// made, without calling the API. Operational scripts can log the diff or ask for
+// confirmation before overwriting anything.
+//
+// Fields follow the PATCH semantics of the API: fields the input leaves empty
+// are unchanged, and metadata is merged key by key, a nil value removing the key.
+//
+// Example:
+//
+//	diff, err := entities.DiffUpdate(ctx, account, input)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	log.Println(diff)
+func DiffUpdate(ctx context.Context, current, input any) (*UpdateDiff, error) {
+	const operation = "DiffUpdate"
+
+	if isNilValue(current) {
+		return nil, sdkerrors.NewMissingParameterError(operation, "current")
+	}
+
+	if isNilValue(input) {
+		return nil, sdkerrors.NewMissingParameterError(operation, "input")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cur, err := toJSONObject(current)
+	if err != nil {
+		return nil, sdkerrors.NewValidationError(operation, "current entity cannot be encoded as an object", err)
+	}
+
+	in, err := toJSONObject(input)
+	if err != nil {
+		return nil, sdkerrors.NewValidationError(operation, "update input cannot be encoded as an object", err)
+	}
+
+	diff := &UpdateDiff{Changes: []FieldChange{}}
+	diffObjects(diff, nil, cur, in)
+
+	return diff, nil
+}
+
+// GuardUpdate computes the diff of an update and asks confirm whether to proceed.
+// It returns ErrUpdateRejected when confirm returns false. Updates without
+// changes are not submitted to confirm.
+func GuardUpdate(ctx context.Context, current, input any, confirm func(*UpdateDiff) bool) (*UpdateDiff, error) {
+	diff, err := DiffUpdate(ctx, current, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if diff.HasChanges() && confirm != nil && !confirm(diff) {
+		return diff, ErrUpdateRejected
+	}
+
+	return diff, nil
+}
+
+// diffObjects appends the changes of in over cur to diff.
+func diffObjects(diff *UpdateDiff, path []string, cur, in map[string]any) {
+	metadata := len(path) > 0 && path[len(path)-1] == "metadata"
+
+	for _, key := range sortedKeys(in) {
+		next := append(append([]string(nil), path...), key)
+		to := in[key]
+		from, exists := cur[key]
+
+		if to == nil {
+			// only metadata treats nil as a removal, elsewhere it means unset
+			if metadata && exists {
+				diff.append(next, DiffOpRemove, from, nil)
+			}
+
+			continue
+		}
+
+		if !metadata && isEmptyValue(to) {
+			continue
+		}
+
+		if toObj, ok := to.(map[string]any); ok {
+			// objects are diffed field by field, even when absent from the entity
+			if fromObj, ok := from.(map[string]any); ok || from == nil {
+				diffObjects(diff, next, fromObj, toObj)
+				continue
+			}
+		}
+
+		switch {
+		case !exists || from == nil:
+			diff.append(next, DiffOpAdd, nil, to)
+		case !reflect.DeepEqual(from, to):
+			diff.append(next, DiffOpReplace, from, to)
+		}
+	}
+}
+
+func (d *UpdateDiff) append(pointer []string, op string, from, to any) {
+	d.Changes = append(d.Changes, FieldChange{
+		Path:    strings.Join(pointer, "."),
+		Op:      op,
+		From:    from,
+		To:      to,
+		pointer: pointer,
+	})
+}
+
+// toJSONObject encodes v to JSON and decodes it back as an object.
+func toJSONObject(v any) (map[string]any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]any
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// isNilValue reports whether v is nil or a nil pointer.
+func isNilValue(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// isEmptyValue reports whether a decoded JSON value is an empty string or object,
+// which the API treats as "not provided" on updates.
+func isEmptyValue(v any) bool {
+	switch t := v.(type) {
+	case string:
+		return t == ""
+	case map[string]any:
+		for _, inner := range t {
+			if inner != nil && !isEmptyValue(inner) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// jsonPointer builds an RFC 6901 pointer from path segments.
+func jsonPointer(path []string) string {
+	escape := strings.NewReplacer("~", "~0", "/", "~1")
+
+	var b strings.Builder
+	for _, p := range path {
+		b.WriteByte('/')
+		b.WriteString(escape.Replace(p))
+	}
+
+	return b.String()
+}
+
+func formatDiffValue(v any) string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(raw)
+}
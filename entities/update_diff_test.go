@@ -0,0 +1,153 @@
+package entities
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diffAccount() *models.Account {
+	return &models.Account{
+		ID:     "acc-1",
+		Name:   "Checking",
+		Status: models.Status{Code: "ACTIVE"},
+		Metadata: map[string]any{
+			"tier":  "silver",
+			"phone": "555",
+			"limit": float64(100),
+		},
+	}
+}
+
+func TestDiffUpdate_Account(t *testing.T) {
+	input := &models.UpdateAccountInput{
+		Name:   "Checking USD",
+		Status: models.Status{Code: "BLOCKED"},
+		Metadata: map[string]any{
+			"tier":   "gold",
+			"phone":  nil,
+			"limit":  100,
+			"region": "eu",
+		},
+	}
+
+	diff, err := DiffUpdate(context.Background(), diffAccount(), input)
+	require.NoError(t, err)
+	require.True(t, diff.HasChanges())
+
+	assert.Equal(t, []string{
+		`+ metadata.region: "eu"`,
+		`- metadata.phone: "555"`,
+		`~ metadata.tier: "silver" -> "gold"`,
+		`~ name: "Checking" -> "Checking USD"`,
+		`~ status.code: "ACTIVE" -> "BLOCKED"`,
+	}, sortedLines(diff.String()))
+
+	patch, err := diff.JSONPatch()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op":"remove","path":"/metadata/phone"},
+		{"op":"add","path":"/metadata/region","value":"eu"},
+		{"op":"replace","path":"/metadata/tier","value":"gold"},
+		{"op":"replace","path":"/name","value":"Checking USD"},
+		{"op":"replace","path":"/status/code","value":"BLOCKED"}
+	]`, string(patch))
+}
+
+func TestDiffUpdate_EmptyFieldsAreUnchanged(t *testing.T) {
+	// the input leaves name and status empty, which the API does not apply
+	input := models.NewUpdateAccountInput()
+
+	diff, err := DiffUpdate(context.Background(), diffAccount(), input)
+	require.NoError(t, err)
+	assert.False(t, diff.HasChanges())
+	assert.Equal(t, "no changes", diff.String())
+
+	patch, err := diff.JSONPatch()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(patch))
+}
+
+func TestDiffUpdate_AddsMetadataToEntityWithout(t *testing.T) {
+	current := &models.Account{ID: "acc-1", Name: "Checking"}
+	input := &models.UpdateAccountInput{Metadata: map[string]any{"a/b": "x", "gone": nil}}
+
+	diff, err := DiffUpdate(context.Background(), current, input)
+	require.NoError(t, err)
+	require.Len(t, diff.Changes, 1)
+	assert.Equal(t, FieldChange{Path: "metadata.a/b", Op: DiffOpAdd, To: "x", pointer: []string{"metadata", "a/b"}}, diff.Changes[0])
+
+	patch, err := diff.JSONPatch()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"op":"add","path":"/metadata/a~1b","value":"x"}]`, string(patch))
+}
+
+func TestDiffUpdate_Organization(t *testing.T) {
+	dba := "Acme"
+	current := &models.Organization{ID: "org-1", LegalName: "Acme", DoingBusinessAs: &dba}
+	input := models.NewUpdateOrganizationInput().WithDoingBusinessAsUpdate("Acme Corp")
+
+	diff, err := DiffUpdate(context.Background(), current, input)
+	require.NoError(t, err)
+	require.Len(t, diff.Changes, 1)
+	assert.Equal(t, "doingBusinessAs", diff.Changes[0].Path)
+	assert.Equal(t, DiffOpReplace, diff.Changes[0].Op)
+}
+
+func TestDiffUpdate_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := DiffUpdate(ctx, nil, models.NewUpdateAccountInput())
+	assert.True(t, sdkerrors.IsValidationError(err))
+
+	var account *models.Account
+
+	_, err = DiffUpdate(ctx, account, models.NewUpdateAccountInput())
+	assert.Error(t, err)
+
+	_, err = DiffUpdate(ctx, diffAccount(), "not an object")
+	assert.Error(t, err)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err = DiffUpdate(cancelled, diffAccount(), models.NewUpdateAccountInput())
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGuardUpdate(t *testing.T) {
+	ctx := context.Background()
+	input := &models.UpdateAccountInput{Name: "Renamed"}
+
+	var seen *UpdateDiff
+
+	diff, err := GuardUpdate(ctx, diffAccount(), input, func(d *UpdateDiff) bool {
+		seen = d
+		return false
+	})
+	assert.ErrorIs(t, err, ErrUpdateRejected)
+	assert.Same(t, seen, diff)
+
+	_, err = GuardUpdate(ctx, diffAccount(), input, func(*UpdateDiff) bool { return true })
+	assert.NoError(t, err)
+
+	// updates without changes never ask for confirmation
+	_, err = GuardUpdate(ctx, diffAccount(), &models.UpdateAccountInput{Name: "Checking"}, func(*UpdateDiff) bool {
+		t.Fatal("confirm called for an update without changes")
+		return false
+	})
+	assert.NoError(t, err)
+}
+
+func sortedLines(s string) []string {
+	lines := strings.Split(s, "\n")
+	sort.Strings(lines)
+
+	return lines
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"net/http"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/usage"
+)
+
+// WithUsageRecorder returns an Option that tallies the operations called
+// through every service of the Entity into recorder. Only the method, the
+// path template and the outcome of each call are recorded, never payloads.
+func WithUsageRecorder(recorder *usage.Recorder) Option {
+	return func(e *Entity) error {
+		e.httpClient.usage = recorder
+
+		return nil
+	}
+}
+
+// SetUsageRecorder sets the recorder tallying the calls of the HTTP client;
+// nil stops recording.
+func (c *HTTPClient) SetUsageRecorder(recorder *usage.Recorder) {
+	c.usage = recorder
+}
+
+// recordUsage counts a call in the usage recorder, if any.
+func (c *HTTPClient) recordUsage(method, requestURL string, resp *http.Response, err error) {
+	if c.usage == nil {
+		return
+	}
+
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+
+	c.usage.Record(method, requestURL, statusCode, err != nil)
+}
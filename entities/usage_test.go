@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/usage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithUsageRecorder(t *testing.T) {
+	t.Setenv("MIDAZ_ENABLE_RETRIES", "false")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"0007","message":"not found"}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	defer srv.Close()
+
+	recorder := usage.NewRecorder()
+
+	entity, err := New(srv.URL, WithUsageRecorder(recorder), WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	entity.SetHTTPClient(srv.Client())
+
+	ctx := context.Background()
+
+	_, err = entity.Ledgers.ListLedgers(ctx, "org-1", nil)
+	require.NoError(t, err)
+
+	_, err = entity.Ledgers.ListLedgers(ctx, "org-2", nil)
+	require.NoError(t, err)
+
+	require.Error(t, entity.Organizations.DeleteOrganization(ctx, "org-1"))
+
+	report := recorder.Report()
+	require.Len(t, report.Operations, 2)
+
+	assert.Equal(t, "GET /organizations/{id}/ledgers", report.Operations[0].Operation)
+	assert.Equal(t, int64(2), report.Operations[0].Calls)
+	assert.Equal(t, int64(0), report.Operations[0].Failures)
+
+	assert.Equal(t, "DELETE /organizations/{id}", report.Operations[1].Operation)
+	assert.Equal(t, int64(1), report.Operations[1].Failures)
+	assert.Equal(t, int64(1), report.Operations[1].StatusCodes[http.StatusNotFound])
+}
@@ -0,0 +1,122 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+)
+
+// ValidationWarningFunc receives the validation failures of the requests
+// sent anyway in lenient mode.
+type ValidationWarningFunc func(ctx context.Context, operation string, err error)
+
+// ValidationPolicy controls the client-side validation of the requests of
+// the services: the checks of their inputs, and of their metadata when a
+// metadata validator is set with WithMetadataValidator. Missing identifiers
+// are always rejected, since no request can be built without them.
+type ValidationPolicy struct {
+	// Mode is the validation mode of the calls whose context doesn't set
+	// one with WithValidationMode. Empty means validation.ModeStrict.
+	Mode validation.Mode
+
+	// OnWarning receives the failures lenient mode lets through. When nil,
+	// they are written to stderr.
+	OnWarning ValidationWarningFunc
+}
+
+// WithValidationPolicy returns an Option that sets the validation policy of
+// every service of the Entity.
+func WithValidationPolicy(policy ValidationPolicy) Option {
+	return func(e *Entity) error {
+		if policy.Mode != "" && !policy.Mode.IsValid() {
+			return fmt.Errorf("invalid validation mode %q", policy.Mode)
+		}
+
+		e.httpClient.validation = policy
+
+		return nil
+	}
+}
+
+// SetValidationPolicy sets the validation policy of the HTTP client.
+func (c *HTTPClient) SetValidationPolicy(policy ValidationPolicy) {
+	c.validation = policy
+}
+
+// validation mode context helpers
+type contextKeyValidationMode struct{}
+
+// WithValidationMode attaches a validation mode to the request context,
+// overriding the mode of the client's ValidationPolicy for the calls made
+// with it, so validation can be tightened or relaxed per call.
+//
+// Example:
+//
+//	ctx = entities.WithValidationMode(ctx, validation.ModeLenient)
+//	account, err := entity.Accounts.CreateAccount(ctx, orgID, ledgerID, input)
+func WithValidationMode(ctx context.Context, mode validation.Mode) context.Context {
+	if !mode.IsValid() {
+		return ctx
+	}
+
+	return context.WithValue(ctx, contextKeyValidationMode{}, mode)
+}
+
+// ValidationModeFromContext extracts the validation mode previously stored
+// via WithValidationMode. Returns an empty mode if none is present.
+func ValidationModeFromContext(ctx context.Context) validation.Mode {
+	if mode, ok := ctx.Value(contextKeyValidationMode{}).(validation.Mode); ok {
+		return mode
+	}
+
+	return ""
+}
+
+// validationMode returns the validation mode of a call made with ctx. It is
+// safe to call on a nil client, which validates strictly.
+func (c *HTTPClient) validationMode(ctx context.Context) validation.Mode {
+	if mode := ValidationModeFromContext(ctx); mode != "" {
+		return mode
+	}
+
+	if c != nil && c.validation.Mode != "" {
+		return c.validation.Mode
+	}
+
+	return validation.ModeStrict
+}
+
+// validateInput runs check under the validation mode of ctx: its failure is
+// returned in strict mode, reported as a warning in lenient mode, and check
+// isn't run at all in off mode.
+func (c *HTTPClient) validateInput(ctx context.Context, operation string, check func() error) error {
+	mode := c.validationMode(ctx)
+	if mode == validation.ModeOff {
+		return nil
+	}
+
+	err := check()
+	if err == nil || mode == validation.ModeStrict {
+		return err
+	}
+
+	c.warnValidation(ctx, operation, err)
+
+	return nil
+}
+
+// warnValidation reports a validation failure lenient mode lets through.
+func (c *HTTPClient) warnValidation(ctx context.Context, operation string, err error) {
+	if c != nil && c.validation.OnWarning != nil {
+		c.validation.OnWarning(ctx, operation, err)
+		return
+	}
+
+	message := html.EscapeString(sanitizeLogInput(fmt.Sprintf("%s: %v", operation, err)))
+
+	// Error is intentionally ignored as warning output should not affect program flow
+	_, _ = fmt.Fprintln(os.Stderr, "[Midaz SDK Warning] validation failed: "+message) //#nosec G705 -- stderr is not an XSS sink
+}
@@ -0,0 +1,103 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithValidationPolicy(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var warnings []string
+
+	validator, err := validation.NewValidator(core.WithBackendMetadataLimits())
+	require.NoError(t, err)
+
+	entity, err := New(srv.URL,
+		WithValidationPolicy(ValidationPolicy{
+			Mode: validation.ModeLenient,
+			OnWarning: func(_ context.Context, operation string, err error) {
+				warnings = append(warnings, operation+": "+err.Error())
+			},
+		}),
+		WithMetadataValidator(validator),
+		WithHTTPClient(srv.Client()),
+	)
+	require.NoError(t, err)
+
+	entity.SetHTTPClient(srv.Client())
+
+	ctx := context.Background()
+	invalid := models.NewCreateAccountTypeInput("", "KEY")
+
+	// Lenient: the invalid input is reported and sent anyway
+	_, err = entity.AccountTypes.CreateAccountType(ctx, "org-1", "ledger-1", invalid)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "CreateAccountType: name is required", warnings[0])
+
+	// Strict per call: the invalid input is rejected before it is sent
+	_, err = entity.AccountTypes.CreateAccountType(WithValidationMode(ctx, validation.ModeStrict), "org-1", "ledger-1", invalid)
+	require.True(t, sdkerrors.IsValidationError(err), "got %v", err)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// Off per call: the invalid input is sent without being checked
+	_, err = entity.AccountTypes.CreateAccountType(WithValidationMode(ctx, validation.ModeOff), "org-1", "ledger-1", invalid)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Len(t, warnings, 1)
+
+	// Missing identifiers are rejected in every mode
+	_, err = entity.AccountTypes.CreateAccountType(WithValidationMode(ctx, validation.ModeOff), "", "ledger-1", invalid)
+	require.Error(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// Lenient metadata: the oversized value is reported and sent anyway
+	input := models.NewCreateAccountTypeInput("Name", "KEY").
+		WithMetadata(map[string]any{"notes": strings.Repeat("a", core.BackendMaxMetadataValueLength+1)})
+
+	_, err = entity.AccountTypes.CreateAccountType(ctx, "org-1", "ledger-1", input)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[1], "metadata.notes")
+
+	_, err = entity.AccountTypes.CreateAccountType(WithValidationMode(ctx, validation.ModeStrict), "org-1", "ledger-1", input)
+	require.True(t, sdkerrors.IsValidationError(err), "got %v", err)
+	assert.Equal(t, int32(3), requests.Load())
+
+	_, err = New(srv.URL, WithValidationPolicy(ValidationPolicy{Mode: "warn"}))
+	require.Error(t, err)
+}
+
+func TestValidationModeFromContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, ValidationModeFromContext(ctx))
+
+	ctx = WithValidationMode(ctx, validation.ModeOff)
+	assert.Equal(t, validation.ModeOff, ValidationModeFromContext(ctx))
+
+	assert.Equal(t, validation.ModeOff, ValidationModeFromContext(WithValidationMode(ctx, "unknown")), "unknown modes are ignored")
+
+	var client *HTTPClient
+	assert.Equal(t, validation.ModeStrict, client.validationMode(context.Background()), "strict by default")
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"log"
@@ -15,8 +14,10 @@ import (
 	client "github.com/LerianStudio/midaz-sdk-golang/v2"
 	conc "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/config"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
 	gen "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/generator"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/prompt"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
 	"gopkg.in/yaml.v3"
 )
@@ -37,6 +38,21 @@ type demoConfig struct {
 	assetCodeVal         string
 	chartGroupVal        string
 	orgLocaleVal         string
+	chaosVal             gen.ChaosConfig
+	networkVal           data.NetworkTopology
+	stressVal            stressConfig
+	fxHoldersVal         int
+	evolutionVal         evolutionConfig
+	amountProfileVal     string
+	amountVal            *data.AmountDistribution
+}
+
+// stressConfig holds the stress cohort settings for the demo
+type stressConfig struct {
+	mode     data.StressMode
+	accounts int
+	tpm      int
+	duration time.Duration
 }
 
 type demoFileDefaults struct {
@@ -54,6 +70,22 @@ type demoFileDefaults struct {
 	ChartGroup        *string `yaml:"chart_group"`
 	Locale            *string `yaml:"locale"`
 	RunFlow           *bool   `yaml:"run_flow"`
+
+	ChaosInvalid    *float64 `yaml:"chaos_invalid_pct"`
+	ChaosDuplicates *float64 `yaml:"chaos_duplicate_keys_pct"`
+	ChaosOutOfOrder *float64 `yaml:"chaos_out_of_order_pct"`
+	Network         *string  `yaml:"network"`
+	StressMode      *string  `yaml:"stress"`
+	StressAccounts  *int     `yaml:"stress_accounts"`
+	StressTPM       *int     `yaml:"stress_tpm"`
+	StressDuration  *int     `yaml:"stress_duration"`
+	FXHolders       *int     `yaml:"fx_holders"`
+	AmountProfile   *string  `yaml:"amount_profile"`
+
+	EvolveDays       *int     `yaml:"evolve_days"`
+	EvolveCustomers  *float64 `yaml:"evolve_new_customers"`
+	EvolveChurn      *float64 `yaml:"evolve_churn"`
+	EvolveDaySeconds *int     `yaml:"evolve_day_seconds"`
 }
 
 type demoDefaultsWrapper struct {
@@ -73,12 +105,13 @@ func gatherUserConfiguration(timeoutSec, orgs, ledgersPerOrg, accountsPerLedger,
 
 	defaults := defaultDemoConfig(timeoutSec, orgs, ledgersPerOrg, accountsPerLedger, txPerAccount, concurrency, batchSize, resolveLocale(*orgLocaleFlag))
 
-	if os.Getenv("DEMO_NON_INTERACTIVE") == "1" {
-		fmt.Println("Running in non-interactive mode (DEMO_NON_INTERACTIVE=1)")
+	p := prompt.Stdio().WithNonInteractiveEnv("DEMO_NON_INTERACTIVE")
+	if !p.Interactive() {
+		fmt.Println("Running in non-interactive mode (DEMO_NON_INTERACTIVE=1 or no terminal)")
 		return defaults
 	}
 
-	cfg := runInteractiveConfiguration(defaults)
+	cfg := runInteractiveConfiguration(p, defaults)
 
 	if *orgLocaleFlag != "" {
 		cfg.orgLocaleVal = strings.ToLower(*orgLocaleFlag)
@@ -160,6 +193,13 @@ func coalesceBoolPtr(ptr *bool, fallback bool) bool {
 	return fallback
 }
 
+func coalesceFloatPtr(ptr *float64, fallback float64) float64 {
+	if ptr != nil {
+		return *ptr
+	}
+	return fallback
+}
+
 func coalesceStringPtr(ptr *string, fallback string) string {
 	if ptr != nil {
 		return *ptr
@@ -226,41 +266,39 @@ func resolveLocale(flagValue string) string {
 	return strings.ToLower(flagValue)
 }
 
-func runInteractiveConfiguration(defaults demoConfig) demoConfig {
-	reader := bufio.NewReader(os.Stdin)
-
+func runInteractiveConfiguration(p *prompt.Prompter, defaults demoConfig) demoConfig {
 	fmt.Println("\n=== Mass Demo Generator — Interactive Setup ===")
 	printDefaultsSummary(defaults)
 
-	if askBool(reader, "Use defaults above?", true) {
+	if p.Bool("", "Use defaults above?", true) {
 		return defaults
 	}
 
 	cfg := defaults
 
-	cfg.timeoutSecVal = askInt(reader, "Overall timeout (seconds)", cfg.timeoutSecVal)
-	cfg.orgsVal = askInt(reader, "Organizations to create", cfg.orgsVal)
-	cfg.ledgersPerOrgVal = askInt(reader, "Ledgers per organization", cfg.ledgersPerOrgVal)
-	cfg.accountsPerLedgerVal = askInt(reader, "Accounts per ledger", cfg.accountsPerLedgerVal)
-	cfg.txPerAccountVal = askInt(reader, "Transactions per account (demo batch)", cfg.txPerAccountVal)
-	cfg.concurrencyVal = askInt(reader, "Worker pool size (0 = default)", cfg.concurrencyVal)
-	cfg.batchSizeVal = askInt(reader, "Batch size for parallel ops", cfg.batchSizeVal)
-	cfg.doDemoVal = askBool(reader, "Run demo (org+ledger+assets+accounts)?", cfg.doDemoVal)
+	cfg.timeoutSecVal = p.Int("", "Overall timeout (seconds)", cfg.timeoutSecVal)
+	cfg.orgsVal = p.Int("", "Organizations to create", cfg.orgsVal)
+	cfg.ledgersPerOrgVal = p.Int("", "Ledgers per organization", cfg.ledgersPerOrgVal)
+	cfg.accountsPerLedgerVal = p.Int("", "Accounts per ledger", cfg.accountsPerLedgerVal)
+	cfg.txPerAccountVal = p.Int("", "Transactions per account (demo batch)", cfg.txPerAccountVal)
+	cfg.concurrencyVal = p.Int("", "Worker pool size (0 = default)", cfg.concurrencyVal)
+	cfg.batchSizeVal = p.Int("", "Batch size for parallel ops", cfg.batchSizeVal)
+	cfg.doDemoVal = p.Bool("", "Run demo (org+ledger+assets+accounts)?", cfg.doDemoVal)
 
 	if cfg.doDemoVal {
-		cfg.assetsCountVal = askInt(reader, "How many assets to create (demo)", cfg.assetsCountVal)
-		cfg.createHierarchyVal = askBool(reader, "Create account hierarchy with Customer A/B?", cfg.createHierarchyVal)
-		cfg.runBatchVal = askBool(reader, "Run Send-based transfer batch demo?", cfg.runBatchVal)
+		cfg.assetsCountVal = p.Int("", "How many assets to create (demo)", cfg.assetsCountVal)
+		cfg.createHierarchyVal = p.Bool("", "Create account hierarchy with Customer A/B?", cfg.createHierarchyVal)
+		cfg.runBatchVal = p.Bool("", "Run Send-based transfer batch demo?", cfg.runBatchVal)
 
 		if cfg.runBatchVal {
-			cfg.assetCodeVal = askString(reader, "Asset code", cfg.assetCodeVal)
-			cfg.chartGroupVal = askString(reader, "Chart of accounts group (leave blank for server default)", cfg.chartGroupVal)
+			cfg.assetCodeVal = p.String("", "Asset code", cfg.assetCodeVal)
+			cfg.chartGroupVal = p.String("", "Chart of accounts group (leave blank for server default)", cfg.chartGroupVal)
 		}
 	} else {
 		cfg.runBatchVal = false
 	}
 
-	cfg.orgLocaleVal = strings.ToLower(askString(reader, "Organization locale (us|br)", cfg.orgLocaleVal))
+	cfg.orgLocaleVal = p.Choice("", "Organization locale", cfg.orgLocaleVal, "us", "br")
 
 	cfg.chartGroupVal = strings.TrimSpace(cfg.chartGroupVal)
 
@@ -393,6 +431,9 @@ func buildGeneratorConfig(userConfig demoConfig) gen.GeneratorConfig {
 		gcfg.BatchSize = userConfig.batchSizeVal
 	}
 
+	gcfg.Chaos = userConfig.chaosVal
+	gcfg.Chaos.Seed = gcfg.GenerationSeed
+
 	return gcfg
 }
 
@@ -423,6 +464,11 @@ func printBootstrapInfo(cfg *config.Config, gcfg gen.GeneratorConfig) {
 		gcfg.BatchSize,
 	)
 
+	if gcfg.Chaos.Enabled() {
+		fmt.Printf("Chaos mode: invalid=%.1f%% duplicateKeys=%.1f%% outOfOrder=%.1f%%\n",
+			gcfg.Chaos.InvalidTransactionPercent, gcfg.Chaos.DuplicateIdempotencyPercent, gcfg.Chaos.OutOfOrderPercent)
+	}
+
 	if os.Getenv("MIDAZ_AUTH_TOKEN") == "" {
 		fmt.Println("Warning: MIDAZ_AUTH_TOKEN is not set. Local dev server allows any token.")
 	}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	gen "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/generator"
+)
+
+// evolutionConfig holds the settings of the evolution mode
+type evolutionConfig struct {
+	days         int
+	orgID        string
+	ledgerID     string
+	newCustomers float64
+	churnRate    float64
+	dayDuration  time.Duration
+}
+
+// evolutionLedger applies evolution events to an existing demo ledger.
+type evolutionLedger struct {
+	c        *client.Client
+	orgID    string
+	ledgerID string
+	asset    string
+	scale    int
+	runID    string
+
+	mu sync.Mutex
+	// customers maps customer aliases to their accounts
+	customers map[string]evolutionAccount
+}
+
+// evolutionAccount identifies a customer account of the ledger
+type evolutionAccount struct {
+	id   string
+	name string
+}
+
+// runEvolution simulates days of activity on an existing demo ledger: new
+// customers, payments to merchants with a seasonal volume, and churn.
+func runEvolution(ctx context.Context, c *client.Client, userConfig demoConfig, assetTemplates []data.AssetTemplate) error {
+	cfg := userConfig.evolutionVal
+	if cfg.orgID == "" || cfg.ledgerID == "" {
+		return errors.New("evolution mode needs --evolve-org and --evolve-ledger")
+	}
+
+	l := &evolutionLedger{
+		c:         c,
+		orgID:     cfg.orgID,
+		ledgerID:  cfg.ledgerID,
+		asset:     userConfig.assetCodeVal,
+		scale:     2,
+		runID:     time.Now().UTC().Format("20060102150405"),
+		customers: make(map[string]evolutionAccount),
+	}
+
+	for _, tpl := range assetTemplates {
+		if tpl.Code == l.asset {
+			l.scale = tpl.Scale
+		}
+	}
+
+	customers, merchants, err := l.loadAccounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	balances, err := l.loadBalances(ctx)
+	if err != nil {
+		log.Printf("note: customer balances unknown, payments are not capped: %v", err)
+	}
+
+	plan, err := data.BuildEvolutionPlan(data.EvolutionConfig{
+		Days:               cfg.days,
+		Customers:          customers,
+		Merchants:          merchants,
+		Balances:           balances,
+		NewCustomersPerDay: cfg.newCustomers,
+		ChurnRate:          cfg.churnRate,
+		CustomerPrefix:     fmt.Sprintf("evo_%s_", l.runID),
+	}, nil, l.scale)
+	if err != nil {
+		return fmt.Errorf("invalid evolution configuration: %w", err)
+	}
+
+	fmt.Printf("Simulating %d days on ledger %s: %d customers, %d merchants\n", cfg.days, cfg.ledgerID, len(customers), len(merchants))
+
+	summary, err := gen.RunEvolutionPlan(gen.WithWorkers(ctx, userConfig.concurrencyVal), plan, cfg.dayDuration, gen.EvolutionHandlers{
+		Onboard: l.onboard,
+		Payment: l.pay,
+		Churn:   l.churn,
+	})
+	if err != nil {
+		log.Printf("evolution interrupted: %v", err)
+	}
+
+	if summary == nil {
+		return err
+	}
+
+	for _, day := range summary.Days {
+		fmt.Printf("Day %s: customers=%d onboarded=%d payments=%d churned=%d failed=%d\n",
+			day.Date.Format("2006-01-02"), day.ActiveCustomers, day.Onboarded, day.Payments, day.Churned, day.Failed)
+	}
+
+	fmt.Printf("Evolution completed in %s\n", summary.Elapsed.Round(time.Millisecond))
+
+	return nil
+}
+
+// loadAccounts lists the active accounts of the ledger in the demo asset and
+// returns the aliases of customers and merchants, by their role metadata.
+func (l *evolutionLedger) loadAccounts(ctx context.Context) (customers, merchants []string, err error) {
+	opts := models.NewListOptions().WithLimit(models.MaxLimit)
+
+	for opts != nil {
+		page, err := l.c.Entity.Accounts.ListAccounts(ctx, l.orgID, l.ledgerID, opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+
+		for _, account := range page.Items {
+			alias := models.GetAccountAlias(account)
+			if alias == "" || account.AssetCode != l.asset || account.Status.Code != models.StatusActive {
+				continue
+			}
+
+			switch account.Metadata["role"] {
+			case "customer":
+				customers = append(customers, alias)
+				l.customers[alias] = evolutionAccount{id: account.ID, name: account.Name}
+			case "merchant":
+				merchants = append(merchants, alias)
+			}
+		}
+
+		if len(page.Items) == 0 {
+			break
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	return customers, merchants, nil
+}
+
+// loadBalances returns the available balance of each customer in minor units.
+func (l *evolutionLedger) loadBalances(ctx context.Context) (map[string]int64, error) {
+	aliases := make(map[string]string, len(l.customers))
+	ids := make([]string, 0, len(l.customers))
+
+	for alias, account := range l.customers {
+		aliases[account.id] = alias
+		ids = append(ids, account.id)
+	}
+
+	results, err := l.c.Entity.Balances.GetMany(ctx, l.orgID, l.ledgerID, ids, &entities.GetManyOptions{LedgerScanThreshold: 50})
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]int64, len(results))
+
+	for id, result := range results {
+		if result.Error != nil {
+			continue
+		}
+
+		var available int64
+
+		for _, b := range result.Balances {
+			if b.AssetCode == l.asset {
+				available += b.Available.Shift(int32(l.scale)).IntPart()
+			}
+		}
+
+		balances[aliases[id]] = available
+	}
+
+	return balances, nil
+}
+
+// onboard creates a customer account and funds it from the external account.
+func (l *evolutionLedger) onboard(ctx context.Context, day data.EvolutionDay, ev data.EvolutionEvent) error {
+	name := fmt.Sprintf("Customer %s", ev.Customer)
+	input := models.NewCreateAccountInput(name, l.asset, "deposit").
+		WithAlias(ev.Customer).
+		WithMetadata(map[string]any{"role": "customer", "demo_onboarded_on": day.Date.Format("2006-01-02")})
+
+	account, err := l.c.Entity.Accounts.CreateAccount(ctx, l.orgID, l.ledgerID, input)
+	if err != nil {
+		return fmt.Errorf("onboard %s: %w", ev.Customer, err)
+	}
+
+	l.mu.Lock()
+	l.customers[ev.Customer] = evolutionAccount{id: account.ID, name: name}
+	l.mu.Unlock()
+
+	return l.transfer(ctx, day, "onboard", fmt.Sprintf("@external/%s", l.asset), ev.Customer, ev.Amount)
+}
+
+// pay moves a payment from a customer to its counterparty.
+func (l *evolutionLedger) pay(ctx context.Context, day data.EvolutionDay, ev data.EvolutionEvent) error {
+	return l.transfer(ctx, day, "payment", ev.Customer, ev.Counterparty, ev.Amount)
+}
+
+// churn cashes out the customer's known balance and deactivates the account.
+func (l *evolutionLedger) churn(ctx context.Context, day data.EvolutionDay, ev data.EvolutionEvent) error {
+	if ev.Amount > 0 {
+		if err := l.transfer(ctx, day, "churn", ev.Customer, fmt.Sprintf("@external/%s", l.asset), ev.Amount); err != nil {
+			return err
+		}
+	}
+
+	l.mu.Lock()
+	account, ok := l.customers[ev.Customer]
+	l.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("churn %s: unknown account", ev.Customer)
+	}
+
+	_, err := l.c.Entity.Accounts.UpdateAccount(ctx, l.orgID, l.ledgerID, account.id, &models.UpdateAccountInput{
+		Name:   account.name,
+		Status: models.NewStatus(entities.AccountStatusInactive),
+	})
+	if err != nil {
+		return fmt.Errorf("churn %s: %w", ev.Customer, err)
+	}
+
+	return nil
+}
+
+// transfer submits a simple transfer tagged with the simulated date.
+func (l *evolutionLedger) transfer(ctx context.Context, day data.EvolutionDay, kind, from, to string, amount int64) error {
+	amountStr := formatAmountByScale(amount, int64(l.scale))
+
+	_, err := l.c.Entity.Transactions.CreateTransaction(ctx, l.orgID, l.ledgerID, &models.CreateTransactionInput{
+		Description: fmt.Sprintf("Demo %s from %s to %s", kind, from, to),
+		Amount:      amountStr,
+		AssetCode:   l.asset,
+		Send: &models.SendInput{
+			Asset: l.asset,
+			Value: amountStr,
+			Source: &models.SourceInput{From: []models.FromToInput{{
+				Account: from,
+				Amount:  models.AmountInput{Asset: l.asset, Value: amountStr},
+			}}},
+			Distribute: &models.DistributeInput{To: []models.FromToInput{{
+				Account: to,
+				Amount:  models.AmountInput{Asset: l.asset, Value: amountStr},
+			}}},
+		},
+		Metadata: map[string]any{"demo_evolution": kind, "demo_sim_date": day.Date.Format("2006-01-02"), "demo_run": l.runID},
+	})
+	if err != nil {
+		return fmt.Errorf("%s %s -> %s: %w", kind, from, to, err)
+	}
+
+	return nil
+}
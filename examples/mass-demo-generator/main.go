@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
@@ -9,27 +8,59 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	client "github.com/LerianStudio/midaz-sdk-golang/v2"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
 	gen "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/generator"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/integrity"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/manifest"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/progress"
 	txpkg "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/transaction"
 	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
 )
 
 type workflowState struct {
 	demoConfig       demoConfig
 	genConfig        gen.GeneratorConfig
-	stepTimings      map[string]string
+	steps            *progress.Steps
 	apiCalls         int
 	reportEntities   txpkg.ReportEntities
 	accountTxnCounts map[string]int
+	runManifest      *manifest.Manifest
+
+	// mu guards apiCalls and reportEntities while setup steps run concurrently
+	mu sync.Mutex
+}
+
+// record applies a change to the counters and IDs of the run.
+func (s *workflowState) record(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn()
+}
+
+// branchFaker returns the faker of one branch of the setup, seeded from the
+// generation seed and the branch, so that names stay reproducible when
+// branches run concurrently.
+func (s *workflowState) branchFaker(branch int) *data.Faker {
+	seed := s.genConfig.GenerationSeed
+	if seed != 0 {
+		seed += int64(branch) + 1
+	}
+
+	return data.NewFaker(s.demoConfig.orgLocaleVal, seed)
 }
 
 type ledgerContext struct {
@@ -38,6 +69,9 @@ type ledgerContext struct {
 	assetScales       map[string]int
 	baseAccounts      []*models.Account
 	hierarchyAccounts []*models.Account
+	portfolio         *models.Portfolio
+	segNA             *models.Segment
+	segEU             *models.Segment
 }
 
 type cliFlags struct {
@@ -49,13 +83,29 @@ type cliFlags struct {
 	concurrency       *int
 	batchSize         *int
 	orgLocale         *string
+	chaosInvalid      *float64
+	chaosDuplicates   *float64
+	chaosOutOfOrder   *float64
+	network           *string
+	stressMode        *string
+	stressAccounts    *int
+	stressTPM         *int
+	stressDuration    *int
+	fxHolders         *int
+	amountProfile     *string
+	evolveDays        *int
+	evolveOrg         *string
+	evolveLedger      *string
+	evolveCustomers   *float64
+	evolveChurn       *float64
+	evolveDaySeconds  *int
 }
 
 func newWorkflowState(cfg demoConfig, genCfg gen.GeneratorConfig) *workflowState {
 	return &workflowState{
 		demoConfig:       cfg,
 		genConfig:        genCfg,
-		stepTimings:      make(map[string]string),
+		steps:            progress.NewSteps(nil),
 		reportEntities:   txpkg.ReportEntities{Counts: txpkg.ReportEntityCounts{}},
 		accountTxnCounts: make(map[string]int),
 	}
@@ -82,6 +132,14 @@ func main() {
 		log.Fatalf("Failed to load templates: %v", err)
 	}
 
+	if userConfig.evolutionVal.days > 0 {
+		if err := runEvolution(ctx, c, userConfig, assetTemplates); err != nil {
+			log.Fatalf("Failed to run evolution: %v", err)
+		}
+
+		return
+	}
+
 	if userConfig.doDemoVal {
 		if err := runGenerationWorkflow(ctx, c, obsProvider, gcfg, userConfig, orgTemplates, assetTemplates, accountTemplates); err != nil {
 			log.Fatalf("Failed to run generation workflow: %v", err)
@@ -100,6 +158,20 @@ func parseCLIFlags() cliFlags {
 	concurrencyDefault := coalesceIntPtr(fileDefaults.Concurrency, 0)
 	batchDefault := coalesceIntPtr(fileDefaults.BatchSize, 50)
 	localeDefault := coalesceStringPtr(fileDefaults.Locale, "")
+	chaosInvalidDefault := coalesceFloatPtr(fileDefaults.ChaosInvalid, 0)
+	chaosDuplicatesDefault := coalesceFloatPtr(fileDefaults.ChaosDuplicates, 0)
+	chaosOutOfOrderDefault := coalesceFloatPtr(fileDefaults.ChaosOutOfOrder, 0)
+	networkDefault := coalesceStringPtr(fileDefaults.Network, "")
+	stressModeDefault := coalesceStringPtr(fileDefaults.StressMode, "")
+	stressAccountsDefault := coalesceIntPtr(fileDefaults.StressAccounts, 5)
+	stressTPMDefault := coalesceIntPtr(fileDefaults.StressTPM, 60)
+	stressDurationDefault := coalesceIntPtr(fileDefaults.StressDuration, 30)
+	fxHoldersDefault := coalesceIntPtr(fileDefaults.FXHolders, 0)
+	amountProfileDefault := coalesceStringPtr(fileDefaults.AmountProfile, "")
+	evolveDaysDefault := coalesceIntPtr(fileDefaults.EvolveDays, 0)
+	evolveCustomersDefault := coalesceFloatPtr(fileDefaults.EvolveCustomers, 2)
+	evolveChurnDefault := coalesceFloatPtr(fileDefaults.EvolveChurn, 0.01)
+	evolveDaySecondsDefault := coalesceIntPtr(fileDefaults.EvolveDaySeconds, 5)
 
 	flags := cliFlags{
 		timeoutSec:        flag.Int("timeout", timeoutDefault, "overall generation timeout in seconds"),
@@ -110,6 +182,22 @@ func parseCLIFlags() cliFlags {
 		concurrency:       flag.Int("concurrency", concurrencyDefault, "worker pool size (0 = auto)"),
 		batchSize:         flag.Int("batch", batchDefault, "batch size for parallel ops"),
 		orgLocale:         flag.String("org-locale", localeDefault, "organization locale (us|br)"),
+		chaosInvalid:      flag.Float64("chaos-invalid", chaosInvalidDefault, "percentage of transactions made invalid (chaos mode)"),
+		chaosDuplicates:   flag.Float64("chaos-duplicates", chaosDuplicatesDefault, "percentage of transactions reusing an earlier idempotency key (chaos mode)"),
+		chaosOutOfOrder:   flag.Float64("chaos-out-of-order", chaosOutOfOrderDefault, "percentage of transactions submitted out of order (chaos mode)"),
+		network:           flag.String("network", networkDefault, "account-to-account transfer topology after funding (hub_and_spoke|small_world|bipartite, empty = none)"),
+		stressMode:        flag.String("stress", stressModeDefault, "stress pattern for a cohort of funded accounts (velocity|near_limit, empty = none)"),
+		stressAccounts:    flag.Int("stress-accounts", stressAccountsDefault, "number of accounts in the stress cohort"),
+		stressTPM:         flag.Int("stress-tpm", stressTPMDefault, "transactions per minute per account in velocity stress"),
+		stressDuration:    flag.Int("stress-duration", stressDurationDefault, "stress run duration in seconds"),
+		fxHolders:         flag.Int("fx-holders", fxHoldersDefault, "multi-currency holders per ledger converting between the ledger assets (0 = none)"),
+		amountProfile:     flag.String("amount-profile", amountProfileDefault, fmt.Sprintf("payment amount shape of the demo transactions (%s, empty = normal around 25)", strings.Join(data.AmountScenarios(), "|"))),
+		evolveDays:        flag.Int("evolve-days", evolveDaysDefault, "simulate this many days of activity on an existing demo ledger instead of generating one (0 = off)"),
+		evolveOrg:         flag.String("evolve-org", "", "organization ID of the ledger to evolve"),
+		evolveLedger:      flag.String("evolve-ledger", "", "ID of the demo ledger to evolve"),
+		evolveCustomers:   flag.Float64("evolve-new-customers", evolveCustomersDefault, "average number of customers onboarded per simulated day"),
+		evolveChurn:       flag.Float64("evolve-churn", evolveChurnDefault, "daily probability of each customer leaving, in [0, 1)"),
+		evolveDaySeconds:  flag.Int("evolve-day-seconds", evolveDaySecondsDefault, "real seconds per simulated day (0 = back to back)"),
 	}
 
 	return flags
@@ -152,82 +240,6 @@ func pow10(scale int) int64 {
 	return result
 }
 
-func askString(r *bufio.Reader, prompt, def string) string {
-	fmt.Printf("%s [%s]: ", prompt, def)
-
-	line, err := r.ReadString('\n')
-	if err != nil {
-		log.Printf("warning: failed to read input: %v", err)
-		return def
-	}
-
-	line = strings.TrimSpace(line)
-
-	if line == "" {
-		return def
-	}
-
-	return line
-}
-
-func askInt(r *bufio.Reader, prompt string, def int) int {
-	for {
-		fmt.Printf("%s [%d]: ", prompt, def)
-
-		line, err := r.ReadString('\n')
-		if err != nil {
-			log.Printf("warning: failed to read input: %v", err)
-			return def
-		}
-
-		line = strings.TrimSpace(line)
-
-		if line == "" {
-			return def
-		}
-
-		n, err := strconv.Atoi(line)
-		if err == nil {
-			return n
-		}
-
-		fmt.Println("Please enter a valid integer.")
-	}
-}
-
-func askBool(r *bufio.Reader, prompt string, def bool) bool {
-	for {
-		label := "[y/N]"
-		if def {
-			label = "[Y/n]"
-		}
-
-		fmt.Printf("%s %s: ", prompt, label)
-
-		line, err := r.ReadString('\n')
-		if err != nil {
-			log.Printf("warning: failed to read input: %v", err)
-			return def
-		}
-
-		line = strings.TrimSpace(strings.ToLower(line))
-
-		if line == "" {
-			return def
-		}
-
-		if line == "y" || line == "yes" {
-			return true
-		}
-
-		if line == "n" || line == "no" {
-			return false
-		}
-
-		fmt.Println("Please answer y or n.")
-	}
-}
-
 func prepareRun(flags cliFlags) (demoConfig, observability.Provider, error) {
 	if err := godotenv.Load("examples/mass-demo-generator/.env"); err != nil {
 		log.Printf("note: could not load examples/mass-demo-generator/.env: %v", err)
@@ -258,6 +270,44 @@ func prepareRun(flags cliFlags) (demoConfig, observability.Provider, error) {
 		flags.orgLocale,
 	)
 
+	userConfig.chaosVal = gen.ChaosConfig{
+		InvalidTransactionPercent:   *flags.chaosInvalid,
+		DuplicateIdempotencyPercent: *flags.chaosDuplicates,
+		OutOfOrderPercent:           *flags.chaosOutOfOrder,
+	}
+	if err := userConfig.chaosVal.Validate(); err != nil {
+		return demoConfig{}, nil, fmt.Errorf("invalid chaos configuration: %w", err)
+	}
+
+	userConfig.networkVal = data.NetworkTopology(strings.ToLower(strings.TrimSpace(*flags.network)))
+	userConfig.stressVal = stressConfig{
+		mode:     data.StressMode(strings.ToLower(strings.TrimSpace(*flags.stressMode))),
+		accounts: *flags.stressAccounts,
+		tpm:      *flags.stressTPM,
+		duration: time.Duration(*flags.stressDuration) * time.Second,
+	}
+	userConfig.fxHoldersVal = *flags.fxHolders
+
+	if profile := strings.ToLower(strings.TrimSpace(*flags.amountProfile)); profile != "" {
+		dist, ok := data.AmountScenario(profile)
+		if !ok {
+			return demoConfig{}, nil, fmt.Errorf("unknown amount profile %q (available: %s)", profile, strings.Join(data.AmountScenarios(), ", "))
+		}
+
+		dist.Currency = userConfig.assetCodeVal
+		userConfig.amountProfileVal = profile
+		userConfig.amountVal = &dist
+	}
+
+	userConfig.evolutionVal = evolutionConfig{
+		days:         *flags.evolveDays,
+		orgID:        strings.TrimSpace(*flags.evolveOrg),
+		ledgerID:     strings.TrimSpace(*flags.evolveLedger),
+		newCustomers: *flags.evolveCustomers,
+		churnRate:    *flags.evolveChurn,
+		dayDuration:  time.Duration(*flags.evolveDaySeconds) * time.Second,
+	}
+
 	return userConfig, obsProvider, nil
 }
 
@@ -304,6 +354,14 @@ func loadTemplates() ([]data.OrgTemplate, []data.AssetTemplate, []data.AccountTe
 		}
 	}
 
+	if report := data.DefaultAssetRules().EvaluateAll(assetTemplates); !report.Valid() {
+		return nil, nil, nil, fmt.Errorf("asset templates violate rules: %w", report)
+	}
+
+	if report := data.DefaultAccountRules().EvaluateAll(accountTemplates); !report.Valid() {
+		return nil, nil, nil, fmt.Errorf("account templates violate rules: %w", report)
+	}
+
 	fmt.Printf("Templates loaded: orgs=%d assets=%d accounts=%d\n", len(orgTemplates), len(assetTemplates), len(accountTemplates))
 	fmt.Println("Templates validated: data constraints look good.")
 
@@ -315,37 +373,23 @@ func runGenerationWorkflow(ctx context.Context, c *client.Client, obsProvider ob
 	fmt.Println("\n🚀 Running generation workflow (org + ledger + assets + accounts + transactions)...")
 
 	state := newWorkflowState(userConfig, gcfg)
+	state.runManifest = buildRunManifest(ctx, c, state, orgTemplates, assetTemplates, accountTemplates)
 
-	orgGen := gen.NewOrganizationGenerator(c.Entity, obsProvider)
-	ledGen := gen.NewLedgerGenerator(c.Entity, obsProvider, "")
-	assetGen := gen.NewAssetGenerator(c.Entity, obsProvider)
-
-	ledgerContexts := make([]*ledgerContext, 0, userConfig.orgsVal*userConfig.ledgersPerOrgVal)
-
-	for orgIdx := 0; orgIdx < userConfig.orgsVal; orgIdx++ {
-		template := orgTemplates[orgIdx%len(orgTemplates)]
-		org, ledgers, err := createOrganizationResources(ctx, orgGen, ledGen, assetGen, state, template, assetTemplates, orgIdx, userConfig.ledgersPerOrgVal)
+	// Stream every created entity and transaction as NDJSON for tailing and post-hoc analysis
+	if path := envString("DEMO_EVENT_LOG", ""); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600) // #nosec G304 -- path chosen by the operator
 		if err != nil {
-			return fmt.Errorf("failed to create organization resources: %w", err)
+			return fmt.Errorf("failed to open event log: %w", err)
 		}
+		defer f.Close()
 
-		for _, lc := range ledgers {
-			accounts, portfolio, segNA, segEU, err := createAccountResources(ctx, c, obsProvider, state, org, lc.ledger, accountTemplates)
-			if err != nil {
-				return fmt.Errorf("failed to create account resources: %w", err)
-			}
-			lc.baseAccounts = accounts
-
-			if state.demoConfig.createHierarchyVal {
-				hierarchyAccounts, err := createAccountHierarchy(ctx, c, obsProvider, state, org, lc.ledger, portfolio, segNA, segEU)
-				if err != nil {
-					return fmt.Errorf("failed to create account hierarchy: %w", err)
-				}
-				lc.hierarchyAccounts = hierarchyAccounts
-			}
+		ctx = eventlog.WithLog(ctx, eventlog.New(f))
+		fmt.Println("Event log:", path)
+	}
 
-			ledgerContexts = append(ledgerContexts, lc)
-		}
+	ledgerContexts, err := runSetup(ctx, c, obsProvider, state, orgTemplates, assetTemplates, accountTemplates)
+	if err != nil {
+		return fmt.Errorf("failed to set up organizations and ledgers: %w", err)
 	}
 
 	allResults := make([]txpkg.BatchResult, 0)
@@ -354,8 +398,8 @@ func runGenerationWorkflow(ctx context.Context, c *client.Client, obsProvider ob
 	var reportLedger *models.Ledger
 
 	if state.demoConfig.runBatchVal {
-		for _, lc := range ledgerContexts {
-			results := runAccountTransactions(ctx, c, state, lc.org, lc.ledger, lc.assetScales, lc.baseAccounts)
+		for i, results := range runLedgerTransactions(ctx, c, state, ledgerContexts) {
+			lc := ledgerContexts[i]
 			allResults = append(allResults, results...)
 			allAccounts = append(allAccounts, lc.baseAccounts...)
 			if reportOrg == nil {
@@ -383,104 +427,212 @@ func runGenerationWorkflow(ctx context.Context, c *client.Client, obsProvider ob
 		fmt.Println("✅ Generated transaction volume matches configured target.")
 	}
 
+	fmt.Println("\nStep timings:")
+	if err := state.steps.WriteSummary(os.Stdout); err != nil {
+		log.Printf("warning: failed to print step timings: %v", err)
+	}
+
 	fmt.Println("✅ Generation run complete.")
 	return nil
 }
 
-func createOrganizationResources(ctx context.Context, orgGen gen.OrganizationGenerator, ledGen gen.LedgerGenerator, assetGen gen.AssetGenerator, state *workflowState, tpl data.OrgTemplate, assetTemplates []data.AssetTemplate, orgIdx int, ledgersPerOrg int) (*models.Organization, []*ledgerContext, error) {
+// runSetup creates the organizations of the run and, for each of their ledgers,
+// the assets, routes, accounts, hierarchy and cross-currency flows, as a
+// dependency graph: each step starts once the entities it needs exist, so the
+// ledgers of all organizations are set up in parallel, and so are the assets
+// and routes of a ledger.
+//
+//nolint:funlen // Demo function - declares every step of the setup graph
+func runSetup(ctx context.Context, c *client.Client, obsProvider observability.Provider, state *workflowState, orgTemplates []data.OrgTemplate, assetTemplates []data.AssetTemplate, accountTemplates []data.AccountTemplate) ([]*ledgerContext, error) {
+	orgGen := gen.NewOrganizationGenerator(c.Entity, obsProvider)
+	ledGen := gen.NewLedgerGenerator(c.Entity, obsProvider, "")
+	assetGen := gen.NewAssetGenerator(c.Entity, obsProvider)
+
+	orgs, ledgersPerOrg := state.demoConfig.orgsVal, state.demoConfig.ledgersPerOrgVal
+	ledgerContexts := make([]*ledgerContext, 0, orgs*ledgersPerOrg)
+	setup := concurrent.NewSetup()
+
+	for orgIdx := 0; orgIdx < orgs; orgIdx++ {
+		var org *models.Organization
+
+		orgStep := fmt.Sprintf("org-%d", orgIdx+1)
+		template := orgTemplates[orgIdx%len(orgTemplates)]
+		faker := state.branchFaker(orgIdx)
+
+		setup.Step(orgStep, func(ctx context.Context) (err error) {
+			org, err = createOrganization(ctx, orgGen, state, faker, template, orgIdx)
+			return err
+		})
+
+		for ledgerIdx := 0; ledgerIdx < ledgersPerOrg; ledgerIdx++ {
+			lc := &ledgerContext{}
+			ledgerContexts = append(ledgerContexts, lc)
+
+			ledgerStep := fmt.Sprintf("%s-ledger-%d", orgStep, ledgerIdx+1)
+			faker := state.branchFaker(orgs + len(ledgerContexts))
+
+			setup.Step(ledgerStep, func(ctx context.Context) (err error) {
+				lc.org = org
+				lc.ledger, err = createLedger(ctx, ledGen, state, org, orgIdx, ledgerIdx)
+				return err
+			}, orgStep)
+
+			setup.Step(ledgerStep+"-assets", func(ctx context.Context) (err error) {
+				lc.assetScales, err = createAssets(ctx, assetGen, state, lc.org, lc.ledger, assetTemplates)
+				return err
+			}, ledgerStep)
+
+			setup.Step(ledgerStep+"-routes", func(ctx context.Context) error {
+				return createRoutes(ctx, c, obsProvider, state, lc.org, lc.ledger)
+			}, ledgerStep)
+
+			setup.Step(ledgerStep+"-accounts", func(ctx context.Context) (err error) {
+				lc.baseAccounts, lc.portfolio, lc.segNA, lc.segEU, err = createAccountResources(ctx, c, obsProvider, state, faker, lc.org, lc.ledger, accountTemplates)
+				return err
+			}, ledgerStep+"-assets", ledgerStep+"-routes")
+
+			if state.demoConfig.createHierarchyVal {
+				setup.Step(ledgerStep+"-hierarchy", func(ctx context.Context) (err error) {
+					lc.hierarchyAccounts, err = createAccountHierarchy(ctx, c, obsProvider, state, lc.org, lc.ledger, lc.portfolio, lc.segNA, lc.segEU)
+					return err
+				}, ledgerStep+"-accounts")
+			}
+
+			if state.demoConfig.fxHoldersVal > 0 {
+				setup.Step(ledgerStep+"-fx", func(ctx context.Context) error {
+					runCrossCurrencyFlows(ctx, c, obsProvider, state, lc)
+					return nil
+				}, ledgerStep+"-accounts")
+			}
+		}
+	}
+
+	t0 := time.Now()
+
+	var opts []concurrent.PoolOption
+	if state.genConfig.ConcurrencyLevel > 0 {
+		opts = append(opts, concurrent.WithWorkers(state.genConfig.ConcurrencyLevel))
+	}
+
+	if err := setup.Run(ctx, opts...); err != nil {
+		return nil, err
+	}
+
+	state.steps.Record("setup", time.Since(t0))
+	fmt.Printf("Setup complete: %d steps in %s\n", setup.Len(), time.Since(t0).Round(time.Millisecond))
+
+	return ledgerContexts, nil
+}
+
+func createOrganization(ctx context.Context, orgGen gen.OrganizationGenerator, state *workflowState, faker *data.Faker, tpl data.OrgTemplate, orgIdx int) (*models.Organization, error) {
 	t0 := time.Now()
 
+	company := faker.Company()
 	orgTemplate := tpl
-	orgTemplate.LegalName = fmt.Sprintf("%s %d", tpl.LegalName, orgIdx+1)
+	orgTemplate.LegalName = company.LegalName
+	orgTemplate.TradeName = company.TradeName
+	orgTemplate.TaxID = company.Document
 
 	org, err := orgGen.Generate(ctx, orgTemplate)
 	if err != nil {
-		return nil, nil, fmt.Errorf("organization generation failed: %w", err)
+		return nil, fmt.Errorf("organization generation failed: %w", err)
 	}
 
-	state.apiCalls++
-	state.reportEntities.Counts.Organizations++
-	state.reportEntities.IDs.OrganizationIDs = append(state.reportEntities.IDs.OrganizationIDs, org.ID)
+	state.record(func() {
+		state.apiCalls++
+		state.reportEntities.Counts.Organizations++
+		state.reportEntities.IDs.OrganizationIDs = append(state.reportEntities.IDs.OrganizationIDs, org.ID)
+		state.steps.Record(fmt.Sprintf("org_%d_setup", orgIdx+1), time.Since(t0))
+	})
 	fmt.Println("Created org:", org.ID, org.LegalName)
 
-	ledgerContexts := make([]*ledgerContext, 0, ledgersPerOrg)
+	return org, nil
+}
 
-	for ledgerIdx := 0; ledgerIdx < ledgersPerOrg; ledgerIdx++ {
-		ledgerTemplate := data.LedgerTemplate{
-			Name:     fmt.Sprintf("Demo Ledger %d-%d", orgIdx+1, ledgerIdx+1),
-			Status:   models.NewStatus(models.StatusActive),
-			Metadata: map[string]any{"purpose": "operational", "demo_index": ledgerIdx + 1},
-		}
+func createLedger(ctx context.Context, ledGen gen.LedgerGenerator, state *workflowState, org *models.Organization, orgIdx, ledgerIdx int) (*models.Ledger, error) {
+	ledgerTemplate := data.LedgerTemplate{
+		Name:     fmt.Sprintf("Demo Ledger %d-%d", orgIdx+1, ledgerIdx+1),
+		Status:   models.NewStatus(models.StatusActive),
+		Metadata: map[string]any{"purpose": "operational", "demo_index": ledgerIdx + 1},
+	}
 
-		ledger, err := ledGen.Generate(ctx, org.ID, ledgerTemplate)
-		if err != nil {
-			return nil, nil, fmt.Errorf("ledger generation failed: %w", err)
-		}
+	ledger, err := ledGen.Generate(ctx, org.ID, ledgerTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("ledger generation failed: %w", err)
+	}
 
+	state.record(func() {
 		state.apiCalls++
 		state.reportEntities.Counts.Ledgers++
 		state.reportEntities.IDs.LedgerIDs = append(state.reportEntities.IDs.LedgerIDs, ledger.ID)
-		fmt.Println("Created ledger:", ledger.ID, ledger.Name)
+	})
+	fmt.Println("Created ledger:", ledger.ID, ledger.Name)
 
-		assetCtx := gen.WithOrgID(ctx, org.ID)
-		assetScales := map[string]int{}
-		assetLimit := state.demoConfig.assetsCountVal
-		if assetLimit <= 0 {
-			assetLimit = 1
-		}
+	return ledger, nil
+}
 
-		for i := 0; i < assetLimit; i++ {
-			tpl := assetTemplates[i%len(assetTemplates)]
+func createAssets(ctx context.Context, assetGen gen.AssetGenerator, state *workflowState, org *models.Organization, ledger *models.Ledger, assetTemplates []data.AssetTemplate) (map[string]int, error) {
+	assetCtx := gen.WithOrgID(ctx, org.ID)
+	assetScales := map[string]int{}
+	assetLimit := state.demoConfig.assetsCountVal
+	if assetLimit <= 0 {
+		assetLimit = 1
+	}
 
-			asset, err := assetGen.Generate(assetCtx, ledger.ID, tpl)
-			if err != nil {
-				return nil, nil, fmt.Errorf("asset generation failed for %s: %w", tpl.Code, err)
-			}
+	for i := 0; i < assetLimit; i++ {
+		tpl := assetTemplates[i%len(assetTemplates)]
 
+		asset, err := assetGen.Generate(assetCtx, ledger.ID, tpl)
+		if err != nil {
+			return nil, fmt.Errorf("asset generation failed for %s: %w", tpl.Code, err)
+		}
+
+		state.record(func() {
 			state.apiCalls++
 			state.reportEntities.Counts.Assets++
 			state.reportEntities.IDs.AssetIDs = append(state.reportEntities.IDs.AssetIDs, asset.ID)
-			assetScales[asset.Code] = tpl.Scale
+		})
+		assetScales[asset.Code] = tpl.Scale
 
-			fmt.Println("Created asset:", asset.ID, asset.Code)
-		}
-
-		ledgerContexts = append(ledgerContexts, &ledgerContext{org: org, ledger: ledger, assetScales: assetScales})
+		fmt.Println("Created asset:", asset.ID, asset.Code)
 	}
 
-	state.stepTimings[fmt.Sprintf("org_%d_setup", orgIdx+1)] = time.Since(t0).String()
-
-	return org, ledgerContexts, nil
+	return assetScales, nil
 }
 
-//nolint:funlen // Demo function - length acceptable for example code showing complete resource creation
-func createAccountResources(ctx context.Context, c *client.Client, obsProvider observability.Provider, state *workflowState, org *models.Organization, ledger *models.Ledger, accountTemplates []data.AccountTemplate) (accounts []*models.Account, portfolio *models.Portfolio, segNA *models.Segment, segEU *models.Segment, err error) {
+// createRoutes creates the default account types, operation routes and transaction routes of a ledger.
+func createRoutes(ctx context.Context, c *client.Client, obsProvider observability.Provider, state *workflowState, org *models.Organization, ledger *models.Ledger) error {
 	atGen := gen.NewAccountTypeGenerator(c.Entity, obsProvider)
 	if _, err := atGen.GenerateDefaults(ctx, org.ID, ledger.ID); err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("account type generation failed: %w", err)
+		return fmt.Errorf("account type generation failed: %w", err)
 	}
 
-	state.apiCalls++
+	state.record(func() { state.apiCalls++ })
 	fmt.Println("Created default account types")
 
 	orGen := gen.NewOperationRouteGenerator(c.Entity, obsProvider)
 	opRoutes, err := orGen.GenerateDefaults(ctx, org.ID, ledger.ID)
 	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("operation routes generation failed: %w", err)
+		return fmt.Errorf("operation routes generation failed: %w", err)
 	}
 
-	state.apiCalls += len(opRoutes)
+	state.record(func() { state.apiCalls += len(opRoutes) })
 	fmt.Printf("Created operation routes: %d\n", len(opRoutes))
 
 	trGen := gen.NewTransactionRouteGenerator(c.Entity, obsProvider)
 	troutes, err := trGen.GenerateDefaults(ctx, org.ID, ledger.ID, opRoutes)
 	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("transaction routes generation failed: %w", err)
+		return fmt.Errorf("transaction routes generation failed: %w", err)
 	}
 
-	state.apiCalls += len(troutes)
+	state.record(func() { state.apiCalls += len(troutes) })
 	fmt.Printf("Created transaction routes: %d\n", len(troutes))
 
+	return nil
+}
+
+//nolint:funlen // Demo function - length acceptable for example code showing complete resource creation
+func createAccountResources(ctx context.Context, c *client.Client, obsProvider observability.Provider, state *workflowState, faker *data.Faker, org *models.Organization, ledger *models.Ledger, accountTemplates []data.AccountTemplate) (accounts []*models.Account, portfolio *models.Portfolio, segNA *models.Segment, segEU *models.Segment, err error) {
 	accGen := gen.NewAccountGenerator(c.Entity, obsProvider)
 	totalAccounts := state.demoConfig.accountsPerLedgerVal
 	if totalAccounts <= 0 {
@@ -502,6 +654,7 @@ func createAccountResources(ctx context.Context, c *client.Client, obsProvider o
 			clone.Metadata = map[string]any{}
 		}
 		clone.Metadata["demo_account_index"] = i + 1
+		personalizeAccount(faker, &clone)
 		batch = append(batch, clone)
 	}
 
@@ -511,13 +664,15 @@ func createAccountResources(ctx context.Context, c *client.Client, obsProvider o
 		return nil, nil, nil, nil, fmt.Errorf("account generation failed: %w", err)
 	}
 
-	state.apiCalls += len(created)
-	state.reportEntities.Counts.Accounts += len(created)
-	for _, account := range created {
-		state.reportEntities.IDs.AccountIDs = append(state.reportEntities.IDs.AccountIDs, account.ID)
-	}
+	state.record(func() {
+		state.apiCalls += len(created)
+		state.reportEntities.Counts.Accounts += len(created)
+		for _, account := range created {
+			state.reportEntities.IDs.AddAccount(account.ID, models.GetAccountAlias(*account), account.Type)
+		}
 
-	state.stepTimings[fmt.Sprintf("ledger_%s_accounts", ledger.ID)] = time.Since(tAcc).String()
+		state.steps.Record(fmt.Sprintf("ledger_%s_accounts", ledger.ID), time.Since(tAcc))
+	})
 	fmt.Println("Created accounts:", len(created))
 
 	pGen := gen.NewPortfolioGenerator(c.Entity, obsProvider)
@@ -528,9 +683,11 @@ func createAccountResources(ctx context.Context, c *client.Client, obsProvider o
 		return nil, nil, nil, nil, fmt.Errorf("portfolio generation failed: %w", err)
 	}
 
-	state.apiCalls++
-	state.reportEntities.Counts.Portfolios++
-	state.reportEntities.IDs.PortfolioIDs = append(state.reportEntities.IDs.PortfolioIDs, portfolio.ID)
+	state.record(func() {
+		state.apiCalls++
+		state.reportEntities.Counts.Portfolios++
+		state.reportEntities.IDs.PortfolioIDs = append(state.reportEntities.IDs.PortfolioIDs, portfolio.ID)
+	})
 	fmt.Println("Created portfolio:", portfolio.ID)
 
 	sGen := gen.NewSegmentGenerator(c.Entity, obsProvider)
@@ -539,21 +696,40 @@ func createAccountResources(ctx context.Context, c *client.Client, obsProvider o
 		return nil, nil, nil, nil, fmt.Errorf("segment generation failed: %w", err)
 	}
 
-	state.apiCalls++
+	state.record(func() { state.apiCalls++ })
 	segEU, err = sGen.Generate(ctx, org.ID, ledger.ID, fmt.Sprintf("EU-%s", prefix), map[string]any{"region": "europe", "ledger": ledger.ID})
 	if err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("segment generation failed: %w", err)
 	}
 
-	state.apiCalls++
-	state.reportEntities.Counts.Segments += 2
-	state.reportEntities.IDs.SegmentIDs = append(state.reportEntities.IDs.SegmentIDs, segNA.ID, segEU.ID)
-	state.stepTimings[fmt.Sprintf("ledger_%s_portfolio_segments", ledger.ID)] = time.Since(tPS).String()
+	state.record(func() {
+		state.apiCalls++
+		state.reportEntities.Counts.Segments += 2
+		state.reportEntities.IDs.SegmentIDs = append(state.reportEntities.IDs.SegmentIDs, segNA.ID, segEU.ID)
+		state.steps.Record(fmt.Sprintf("ledger_%s_portfolio_segments", ledger.ID), time.Since(tPS))
+	})
 	fmt.Println("Created segments:", segNA.ID, segEU.ID)
 
 	return created, portfolio, segNA, segEU, nil
 }
 
+// personalizeAccount names customer and merchant accounts after a generated
+// holder so that generated accounts don't share the few template names.
+func personalizeAccount(faker *data.Faker, t *data.AccountTemplate) {
+	switch t.Metadata["role"] {
+	case "customer":
+		person := faker.Person()
+		t.Name = person.Name
+		t.Metadata["holder_email"] = person.Email
+		t.Metadata["holder_document"] = person.Document
+	case "merchant":
+		company := faker.Company()
+		t.Name = company.TradeName
+		t.Metadata["holder_email"] = company.Email
+		t.Metadata["holder_document"] = company.Document
+	}
+}
+
 func cloneAccountTemplate(base data.AccountTemplate) data.AccountTemplate {
 	clone := base
 	if base.Metadata != nil {
@@ -602,66 +778,211 @@ func createAccountHierarchy(ctx context.Context, c *client.Client, obsProvider o
 
 	fmt.Println("Created account hierarchy nodes:", len(createdTree))
 
-	state.apiCalls += len(createdTree)
-	state.reportEntities.Counts.Accounts += len(createdTree)
-	for _, account := range createdTree {
-		state.reportEntities.IDs.AccountIDs = append(state.reportEntities.IDs.AccountIDs, account.ID)
-	}
+	state.record(func() {
+		state.apiCalls += len(createdTree)
+		state.reportEntities.Counts.Accounts += len(createdTree)
+		for _, account := range createdTree {
+			state.reportEntities.IDs.AddAccount(account.ID, models.GetAccountAlias(*account), account.Type)
+		}
 
-	state.stepTimings[fmt.Sprintf("ledger_%s_hierarchy", ledger.ID)] = time.Since(tHier).String()
+		state.steps.Record(fmt.Sprintf("ledger_%s_hierarchy", ledger.ID), time.Since(tHier))
+	})
 
 	return createdTree, nil
 }
 
-func runAccountTransactions(ctx context.Context, c *client.Client, state *workflowState, org *models.Organization, ledger *models.Ledger, assetScales map[string]int, accounts []*models.Account) []txpkg.BatchResult {
-	if len(accounts) == 0 {
-		fmt.Println("No accounts available for transaction demo; skipping batch run")
-		return nil
+// ledgerTransactions is the funding transactions of a ledger, with what its
+// follow-up flows need.
+type ledgerTransactions struct {
+	index  int
+	lc     *ledgerContext
+	scale  int
+	amtGen *data.AmountGenerator
+	inputs []*models.CreateTransactionInput
+}
+
+// runLedgerTransactions funds the accounts of all ledgers in a single batch
+// that interleaves ledgers and accounts, so the backend never sees one hot
+// ledger at a time, then runs the network and stress flows of each ledger.
+// It returns the results of each ledger context.
+func runLedgerTransactions(ctx context.Context, c *client.Client, state *workflowState, ledgerContexts []*ledgerContext) [][]txpkg.BatchResult {
+	all := make([][]txpkg.BatchResult, len(ledgerContexts))
+	prepared := make([]*ledgerTransactions, 0, len(ledgerContexts))
+	batches := make([]txpkg.LedgerBatch, 0, len(ledgerContexts))
+	total := 0
+
+	for i, lc := range ledgerContexts {
+		lt := prepareLedgerTransactions(state, lc)
+		if lt == nil {
+			continue
+		}
+
+		lt.index = i
+		prepared = append(prepared, lt)
+		batches = append(batches, txpkg.LedgerBatch{OrganizationID: lc.org.ID, LedgerID: lc.ledger.ID, Inputs: lt.inputs})
+		total += len(lt.inputs)
+	}
+
+	if len(batches) == 0 {
+		return all
+	}
+
+	options := txpkg.DefaultBatchOptions()
+	if state.genConfig.ConcurrencyLevel > 0 {
+		options.Concurrency = state.genConfig.ConcurrencyLevel
+	}
+	if state.genConfig.BatchSize > 0 {
+		options.BatchSize = state.genConfig.BatchSize
+	}
+
+	batchCtx, batchCancel := context.WithTimeout(ctx, 45*time.Second*time.Duration(len(batches)))
+	defer batchCancel()
+
+	fmt.Printf("Submitting %d transactions across %d ledgers (concurrency %d, interleaved by ledger and account)\n", total, len(batches), options.Concurrency)
+
+	bar := progress.NewBar(os.Stdout, total, "Transactions")
+	fairOptions := *options
+	fairOptions.OnProgress = func(completed, _ int, _ txpkg.BatchResult) { bar.Set(completed) }
+
+	results, err := txpkg.BatchTransactionsFair(batchCtx, c, batches, &fairOptions)
+	elapsed := bar.Finish()
+
+	if err != nil {
+		log.Printf("batch encountered errors: %v", err)
+	}
+
+	for i, lt := range prepared {
+		org, ledger, accounts := lt.lc.org, lt.lc.ledger, lt.lc.baseAccounts
+		ledgerResults := results[i]
+
+		state.steps.Record(fmt.Sprintf("ledger_%s_batch", ledger.ID), elapsed)
+		state.apiCalls += len(ledgerResults)
+
+		for _, res := range ledgerResults {
+			if res.TransactionID != "" {
+				state.reportEntities.Counts.Transactions++
+				state.reportEntities.IDs.TransactionIDs = append(state.reportEntities.IDs.TransactionIDs, res.TransactionID)
+			}
+		}
+
+		printSampleErrors(ledgerResults)
+
+		if state.demoConfig.networkVal != "" {
+			networkCtx, networkCancel := context.WithTimeout(ctx, 45*time.Second)
+			ledgerResults = append(ledgerResults, runNetworkTransfers(networkCtx, c, state, org, ledger, lt.scale, accounts, lt.amtGen, options)...)
+			networkCancel()
+		}
+
+		if state.demoConfig.stressVal.mode != "" {
+			runStressCohort(ctx, c, state, org, ledger, lt.scale, lt.inputs, lt.amtGen)
+		}
+
+		all[lt.index] = ledgerResults
 	}
 
-	return processAccountTransactions(ctx, c, state, org, ledger, assetScales, accounts)
+	return all
 }
 
-func processAccountTransactions(ctx context.Context, c *client.Client, state *workflowState, org *models.Organization, ledger *models.Ledger, assetScales map[string]int, accounts []*models.Account) []txpkg.BatchResult {
-	scale := assetScales[state.demoConfig.assetCodeVal]
+// prepareLedgerTransactions builds the funding transactions of a ledger, or
+// returns nil when it has none.
+func prepareLedgerTransactions(state *workflowState, lc *ledgerContext) *ledgerTransactions {
+	if len(lc.baseAccounts) == 0 {
+		fmt.Println("No accounts available for transaction demo; skipping batch run")
+		return nil
+	}
+
+	scale := lc.assetScales[state.demoConfig.assetCodeVal]
 	if scale == 0 {
 		scale = 2
 	}
 
 	amtGen := data.NewAmountGenerator(state.genConfig.GenerationSeed)
-	inputs := buildAccountTransactions(state, accounts, scale, amtGen)
+	if err := amtGen.SetRoundingPolicy(state.genConfig.RoundingPolicy); err != nil {
+		log.Printf("warning: %v", err)
+	}
+
+	inputs := buildAccountTransactions(state, lc.baseAccounts, scale, amtGen)
 
 	if len(inputs) == 0 {
 		fmt.Println("No transaction inputs generated; skipping batch run")
 		return nil
 	}
 
+	inputs = injectChaos(state, lc.ledger, inputs)
+
 	if state.demoConfig.txPerAccountVal > 0 {
-		fmt.Printf("Previewing first transaction for ledger %s\n", ledger.ID)
+		fmt.Printf("Previewing first transaction for ledger %s\n", lc.ledger.ID)
 		if payloadData, err := json.MarshalIndent(inputs[0].ToLibTransaction(), "", "  "); err == nil {
 			fmt.Println(string(payloadData))
 		}
 	}
 
-	options := txpkg.DefaultBatchOptions()
-	if state.genConfig.ConcurrencyLevel > 0 {
-		options.Concurrency = state.genConfig.ConcurrencyLevel
+	return &ledgerTransactions{lc: lc, scale: scale, amtGen: amtGen, inputs: inputs}
+}
+
+// runNetworkTransfers submits account-to-account transfers shaped by the configured
+// counterparty topology, once the accounts have been funded.
+func runNetworkTransfers(ctx context.Context, c *client.Client, state *workflowState, org *models.Organization, ledger *models.Ledger, scale int, accounts []*models.Account, amtGen *data.AmountGenerator, options *txpkg.BatchOptions) []txpkg.BatchResult {
+	aliases := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		if alias := models.GetAccountAlias(*account); alias != "" {
+			aliases = append(aliases, alias)
+		}
 	}
-	if state.genConfig.BatchSize > 0 {
-		options.BatchSize = state.genConfig.BatchSize
+
+	network, err := data.GenerateCounterpartyNetwork(aliases, data.NetworkConfig{
+		Topology: state.demoConfig.networkVal,
+		Seed:     state.genConfig.GenerationSeed,
+	})
+	if err != nil {
+		log.Printf("counterparty network skipped: %v", err)
+		return nil
 	}
 
-	batchCtx, batchCancel := context.WithTimeout(ctx, 45*time.Second)
-	defer batchCancel()
+	asset := state.demoConfig.assetCodeVal
+	inputs := make([]*models.CreateTransactionInput, 0, len(network.Edges))
 
-	tBatch := time.Now()
-	fmt.Printf("Submitting %d transactions for ledger %s (batch size %d, concurrency %d)\n", len(inputs), ledger.ID, options.BatchSize, options.Concurrency)
-	results, err := txpkg.BatchTransactions(batchCtx, c, org.ID, ledger.ID, inputs, options)
+	for i, edge := range network.Edges {
+		minor := amtGen.Normal(2.0, 1.0, scale)
+		if minor <= 0 {
+			minor = 1
+		}
+		amountStr := formatAmountByScale(minor, int64(scale))
+
+		inputs = append(inputs, &models.CreateTransactionInput{
+			Description:              fmt.Sprintf("Demo %s transfer %s -> %s", network.Topology, edge.Source, edge.Destination),
+			Amount:                   amountStr,
+			AssetCode:                asset,
+			ChartOfAccountsGroupName: state.demoConfig.chartGroupVal,
+			IdempotencyKey:           fmt.Sprintf("demo-net-%s-%05d", ledger.ID, i),
+			Send: &models.SendInput{
+				Asset: asset,
+				Value: amountStr,
+				Source: &models.SourceInput{From: []models.FromToInput{{
+					Account: edge.Source,
+					Amount:  models.AmountInput{Asset: asset, Value: amountStr},
+				}}},
+				Distribute: &models.DistributeInput{To: []models.FromToInput{{
+					Account: edge.Destination,
+					Amount:  models.AmountInput{Asset: asset, Value: amountStr},
+				}}},
+			},
+			Metadata: map[string]any{
+				"demo_topology":    string(network.Topology),
+				"demo_source_role": network.Roles[edge.Source],
+				"demo_target_role": network.Roles[edge.Destination],
+			},
+		})
+	}
+
+	tNet := time.Now()
+	fmt.Printf("Submitting %d %s transfers for ledger %s\n", len(inputs), network.Topology, ledger.ID)
+	results, err := txpkg.BatchTransactions(ctx, c, org.ID, ledger.ID, inputs, options)
 	if err != nil {
-		log.Printf("batch encountered errors: %v", err)
+		log.Printf("network batch encountered errors: %v", err)
 	}
 
-	state.stepTimings[fmt.Sprintf("ledger_%s_batch", ledger.ID)] = time.Since(tBatch).String()
+	state.steps.Record(fmt.Sprintf("ledger_%s_network", ledger.ID), time.Since(tNet))
 	state.apiCalls += len(results)
 
 	for _, res := range results {
@@ -676,6 +997,16 @@ func processAccountTransactions(ctx context.Context, c *client.Client, state *wo
 	return results
 }
 
+// sampleDemoAmount draws the amount of a demo transaction in minor units from
+// the configured amount profile, or around 25 units without one.
+func sampleDemoAmount(state *workflowState, amtGen *data.AmountGenerator, scale int) int64 {
+	if state.demoConfig.amountVal == nil {
+		return amtGen.Normal(25.0, 10.0, scale)
+	}
+
+	return amtGen.Sample(*state.demoConfig.amountVal, scale)
+}
+
 func buildAccountTransactions(state *workflowState, accounts []*models.Account, scale int, amtGen *data.AmountGenerator) []*models.CreateTransactionInput {
 	perAccount := state.demoConfig.txPerAccountVal
 	total := len(accounts) * perAccount
@@ -690,7 +1021,7 @@ func buildAccountTransactions(state *workflowState, accounts []*models.Account,
 
 		for i := 0; i < perAccount; i++ {
 			sequence := state.accountTxnCounts[alias] + 1
-			minor := amtGen.Normal(25.0, 10.0, scale)
+			minor := sampleDemoAmount(state, amtGen, scale)
 			if minor <= 0 {
 				minor = pow10(scale)
 			}
@@ -729,6 +1060,267 @@ func buildAccountTransactions(state *workflowState, accounts []*models.Account,
 	return inputs
 }
 
+// injectChaos corrupts part of the inputs when chaos mode is enabled, so downstream
+// error handling and reconciliation can be exercised against known failures.
+func injectChaos(state *workflowState, ledger *models.Ledger, inputs []*models.CreateTransactionInput) []*models.CreateTransactionInput {
+	if !state.genConfig.Chaos.Enabled() {
+		return inputs
+	}
+
+	inj, err := gen.NewChaosInjector(state.genConfig.Chaos)
+	if err != nil {
+		log.Printf("chaos mode disabled: %v", err)
+		return inputs
+	}
+
+	inputs = inj.ApplyToInputs(inputs)
+	report := inj.Report()
+	fmt.Printf("Chaos mode for ledger %s: invalid=%d duplicateKeys=%d outOfOrder=%d (tagged with metadata %q)\n",
+		ledger.ID,
+		report.Count(gen.ChaosInvalidTransaction),
+		report.Count(gen.ChaosDuplicateIdempotencyKey),
+		report.Count(gen.ChaosOutOfOrder),
+		gen.ChaosMetadataKey,
+	)
+
+	return inputs
+}
+
+// runStressCohort pushes the first funded accounts to high velocity or close to
+// their funded balance, sending the money back to the external account.
+func runStressCohort(ctx context.Context, c *client.Client, state *workflowState, org *models.Organization, ledger *models.Ledger, scale int, fundingInputs []*models.CreateTransactionInput, amtGen *data.AmountGenerator) {
+	cfg := state.demoConfig.stressVal
+	funded := fundedMinorUnits(fundingInputs, scale)
+
+	// Keep the funding order so the cohort is stable across runs
+	aliases := make([]string, 0, len(funded))
+	for _, in := range fundingInputs {
+		alias := fundedAlias(in)
+		if _, ok := funded[alias]; ok && !containsString(aliases, alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	if cfg.accounts > 0 && len(aliases) > cfg.accounts {
+		aliases = aliases[:cfg.accounts]
+	}
+
+	// Near-limit cohorts need one cohort per account as each has its own balance
+	var cohorts []data.StressCohort
+	if cfg.mode == data.StressNearLimit {
+		for _, alias := range aliases {
+			cohorts = append(cohorts, data.StressCohort{
+				Name: "near-limit", Mode: cfg.mode, Accounts: []string{alias}, Duration: cfg.duration,
+				Limit: funded[alias], ExceedLimit: true,
+			})
+		}
+	} else {
+		cohorts = append(cohorts, data.StressCohort{
+			Name: string(cfg.mode), Mode: cfg.mode, Accounts: aliases, Duration: cfg.duration,
+			TransactionsPerMinute: cfg.tpm, MinAmount: 0.01, MaxAmount: 0.5,
+		})
+	}
+
+	plan, err := data.BuildStressPlan(cohorts, amtGen, scale)
+	if err != nil {
+		log.Printf("stress run skipped: %v", err)
+		return
+	}
+
+	asset := state.demoConfig.assetCodeVal
+	extAlias := fmt.Sprintf("@external/%s", asset)
+	var seq atomic.Int64
+
+	fmt.Printf("Running %s stress on %d accounts for %s (%d transactions)\n", cfg.mode, len(aliases), cfg.duration, len(plan))
+	summary, err := gen.RunStressPlan(ctx, plan, func(ctx context.Context, tx data.StressTransaction) error {
+		amountStr := formatAmountByScale(tx.Amount, int64(scale))
+		_, err := c.Entity.Transactions.CreateTransaction(ctx, org.ID, ledger.ID, &models.CreateTransactionInput{
+			Description:    fmt.Sprintf("Demo %s stress from %s", tx.Mode, tx.Account),
+			Amount:         amountStr,
+			AssetCode:      asset,
+			IdempotencyKey: fmt.Sprintf("demo-stress-%s-%06d", ledger.ID, seq.Add(1)),
+			Send: &models.SendInput{
+				Asset: asset,
+				Value: amountStr,
+				Source: &models.SourceInput{From: []models.FromToInput{{
+					Account: tx.Account,
+					Amount:  models.AmountInput{Asset: asset, Value: amountStr},
+				}}},
+				Distribute: &models.DistributeInput{To: []models.FromToInput{{
+					Account: extAlias,
+					Amount:  models.AmountInput{Asset: asset, Value: amountStr},
+				}}},
+			},
+			Metadata: map[string]any{"demo_stress": string(tx.Mode), "demo_exceeds_limit": tx.ExceedsLimit},
+		})
+
+		return err
+	})
+	if err != nil {
+		log.Printf("stress run interrupted: %v", err)
+	}
+
+	if summary == nil {
+		return
+	}
+
+	state.steps.Record(fmt.Sprintf("ledger_%s_stress", ledger.ID), summary.Elapsed)
+
+	for name, r := range summary.Cohorts {
+		state.apiCalls += r.Submitted
+		fmt.Printf("Stress cohort %s: submitted=%d ok=%d failed=%d overLimitRejected=%d overLimitAccepted=%d\n",
+			name, r.Submitted, r.Succeeded, r.Failed, r.RejectedOverLimit, r.AcceptedOverLimit)
+	}
+}
+
+// fundedMinorUnits sums the funding received by each destination alias, in minor units.
+func fundedMinorUnits(inputs []*models.CreateTransactionInput, scale int) map[string]int64 {
+	funded := make(map[string]int64)
+
+	for _, in := range inputs {
+		alias := fundedAlias(in)
+		if alias == "" {
+			continue
+		}
+
+		value, err := decimal.NewFromString(in.Send.Value)
+		if err != nil {
+			continue
+		}
+
+		funded[alias] += value.Shift(int32(scale)).IntPart()
+	}
+
+	return funded
+}
+
+// fundedAlias returns the destination alias of a funding input, or "" if it has none.
+func fundedAlias(in *models.CreateTransactionInput) string {
+	if in == nil || in.Send == nil || in.Send.Distribute == nil || len(in.Send.Distribute.To) == 0 {
+		return ""
+	}
+
+	return in.Send.Distribute.To[0].Account
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// demoFXRates lists demo conversion rates as units per US dollar.
+var demoFXRates = map[string]string{"USD": "1", "EUR": "0.92", "BRL": "5.40", "JPY": "150.25"}
+
+// runCrossCurrencyFlows creates multi-currency holders and converts part of their
+// first asset into every other ledger asset through per-asset conversion accounts.
+func runCrossCurrencyFlows(ctx context.Context, c *client.Client, obsProvider observability.Provider, state *workflowState, lc *ledgerContext) {
+	assets := make([]string, 0, len(lc.assetScales))
+	for code := range lc.assetScales {
+		if _, ok := demoFXRates[code]; ok {
+			assets = append(assets, code)
+		}
+	}
+	sort.Strings(assets)
+
+	if len(assets) < 2 {
+		fmt.Println("Cross-currency flows skipped: fewer than two ledger assets have demo rates")
+		return
+	}
+
+	orgID, ledgerID := lc.org.ID, lc.ledger.ID
+	mcGen := gen.NewMultiCurrencyGenerator(gen.NewAccountGenerator(c.Entity, obsProvider), c.Entity, obsProvider)
+	tFX := time.Now()
+
+	conversion, err := mcGen.GenerateConversionAccounts(ctx, orgID, ledgerID, assets)
+	if err != nil {
+		log.Printf("cross-currency flows skipped: %v", err)
+		return
+	}
+	state.record(func() { state.apiCalls += len(conversion) })
+
+	// Conversion accounts pay out the target asset, so give them liquidity first
+	for _, code := range assets {
+		liquidity := formatAmountByScale(1_000_000*pow10(lc.assetScales[code]), int64(lc.assetScales[code]))
+		key := fmt.Sprintf("demo-fx-liquidity-%s-%s", ledgerID, code)
+		if _, err := c.Entity.Transactions.CreateTransaction(ctx, orgID, ledgerID, data.ExternalFundingInput(code, liquidity, data.ConversionAccountAlias(code), key)); err != nil {
+			log.Printf("cross-currency flows skipped: funding %s conversion account failed: %v", code, err)
+			return
+		}
+		state.record(func() { state.apiCalls++ })
+	}
+
+	prefix := ledgerID
+	if len(prefix) > 8 {
+		prefix = prefix[:8]
+	}
+
+	base := assets[0]
+	conversions := 0
+
+	for i := 0; i < state.demoConfig.fxHoldersVal; i++ {
+		holder, err := mcGen.GenerateHolder(ctx, orgID, ledgerID, data.AccountTemplate{
+			Name:           fmt.Sprintf("FX Customer %d", i+1),
+			Type:           "deposit",
+			Status:         models.NewStatus(models.StatusActive),
+			Alias:          data.StrPtr(fmt.Sprintf("%s_fx_%02d", prefix, i+1)),
+			AccountTypeKey: data.StrPtr("CHECKING"),
+			Metadata:       map[string]any{"role": "customer", "segment": "multi_currency"},
+		}, assets)
+		if err != nil {
+			log.Printf("multi-currency holder %d failed: %v", i+1, err)
+			continue
+		}
+		state.record(func() {
+			state.apiCalls += len(holder.Accounts)
+			for _, account := range holder.Accounts {
+				state.reportEntities.Counts.Accounts++
+				state.reportEntities.IDs.AddAccount(account.ID, models.GetAccountAlias(*account), account.Type)
+			}
+		})
+
+		funding := formatAmountByScale(1_000*pow10(lc.assetScales[base]), int64(lc.assetScales[base]))
+		key := fmt.Sprintf("demo-fx-fund-%s-%02d", ledgerID, i+1)
+		if _, err := c.Entity.Transactions.CreateTransaction(ctx, orgID, ledgerID, data.ExternalFundingInput(base, funding, holder.Alias(base), key)); err != nil {
+			log.Printf("funding multi-currency holder %d failed: %v", i+1, err)
+			continue
+		}
+		state.record(func() { state.apiCalls++ })
+
+		for _, target := range assets[1:] {
+			rate := decimal.RequireFromString(demoFXRates[target]).Div(decimal.RequireFromString(demoFXRates[base]))
+
+			result, err := mcGen.Convert(ctx, orgID, ledgerID, data.CrossCurrencyParams{
+				ID:               fmt.Sprintf("demo-fx-%s-%02d-%s", prefix, i+1, strings.ToLower(target)),
+				SourceAlias:      holder.Alias(base),
+				DestinationAlias: holder.Alias(target),
+				FromAsset:        base,
+				ToAsset:          target,
+				Amount:           formatAmountByScale(100*pow10(lc.assetScales[base]), int64(lc.assetScales[base])),
+				Rate:             rate.StringFixed(6),
+				ToScale:          lc.assetScales[target],
+				Rounding:         state.genConfig.RoundingPolicy,
+			})
+			if result != nil && result.Sell != nil {
+				state.record(func() { state.apiCalls++ })
+			}
+			if err != nil {
+				log.Printf("conversion %s->%s for holder %d failed: %v", base, target, i+1, err)
+				continue
+			}
+			state.record(func() { state.apiCalls++ })
+			conversions++
+		}
+	}
+
+	state.steps.Record(fmt.Sprintf("ledger_%s_fx", ledgerID), time.Since(tFX))
+	fmt.Printf("Cross-currency flows: holders=%d assets=%s conversions=%d\n", state.demoConfig.fxHoldersVal, strings.Join(assets, ","), conversions)
+}
+
 func generateFinalReport(ctx context.Context, c *client.Client, state *workflowState, org *models.Organization, ledger *models.Ledger, results []txpkg.BatchResult, accounts []*models.Account) {
 	summary := txpkg.GetBatchSummary(results)
 	state.reportEntities.Counts.Transactions = summary.SuccessCount
@@ -812,13 +1404,62 @@ func createGenerationReport(ctx context.Context, c *client.Client, results []txp
 	}
 
 	report.Entities = &state.reportEntities
-	report.StepTimings = state.stepTimings
+	report.StepTimings = state.steps.Timings()
 	report.APIStats = &txpkg.ReportAPIStats{APICalls: state.apiCalls}
 	report.DataSummary = reportDataSummary
+	report.Manifest = state.runManifest.Seal()
 
 	return report
 }
 
+// buildRunManifest records the SDK, backend, configuration, seeds, templates and
+// scenario file of the run so its report can be reproduced and audited.
+func buildRunManifest(ctx context.Context, c *client.Client, state *workflowState, orgTemplates []data.OrgTemplate, assetTemplates []data.AssetTemplate, accountTemplates []data.AccountTemplate) *manifest.Manifest {
+	m := manifest.New("mass-demo-generator")
+	cfg := c.GetConfig()
+	dc := state.demoConfig
+
+	m.WithConfig("sdk", manifest.SDKConfig(cfg)).
+		WithConfig("demo", map[string]any{
+			"orgs":              dc.orgsVal,
+			"ledgersPerOrg":     dc.ledgersPerOrgVal,
+			"accountsPerLedger": dc.accountsPerLedgerVal,
+			"txPerAccount":      dc.txPerAccountVal,
+			"concurrency":       dc.concurrencyVal,
+			"batchSize":         dc.batchSizeVal,
+			"assets":            dc.assetsCountVal,
+			"assetCode":         dc.assetCodeVal,
+			"createHierarchy":   dc.createHierarchyVal,
+			"orgLocale":         dc.orgLocaleVal,
+			"chaos":             dc.chaosVal,
+			"network":           string(dc.networkVal),
+			"stressMode":        string(dc.stressVal.mode),
+			"fxHolders":         dc.fxHoldersVal,
+			"amountProfile":     dc.amountProfileVal,
+		}).
+		WithSeed("generation", state.genConfig.GenerationSeed).
+		WithSeed("chaos", dc.chaosVal.Seed).
+		WithTemplates("organizations", orgTemplates).
+		WithTemplates("assets", assetTemplates).
+		WithTemplates("accounts", accountTemplates)
+
+	if cfg != nil {
+		for service, baseURL := range cfg.ServiceURLs {
+			if v, err := manifest.FetchBackendVersion(ctx, cfg.HTTPClient, baseURL); err == nil {
+				m.WithBackendVersion(string(service), v)
+			}
+		}
+	}
+
+	if _, err := os.Stat(demoDefaultsPath); err == nil {
+		if err := m.AddScenarioFiles(demoDefaultsPath); err != nil {
+			log.Printf("failed to record scenario file: %v", err)
+		}
+	}
+
+	return m
+}
+
 func saveReportFiles(report *txpkg.GenerationReport, ids txpkg.ReportEntityIDs) {
 	if err := report.SaveJSON("./mass-demo-report.json", true); err != nil {
 		log.Printf("failed to save report: %v", err)
@@ -828,6 +1469,31 @@ func saveReportFiles(report *txpkg.GenerationReport, ids txpkg.ReportEntityIDs)
 		log.Printf("failed to save HTML report: %v", err)
 	}
 
+	if err := report.SaveOpenMetrics("./mass-demo-report.prom", nil); err != nil {
+		log.Printf("failed to save OpenMetrics report: %v", err)
+	}
+
+	if err := report.SaveHGRM("./mass-demo-latency.hgrm"); err != nil {
+		log.Printf("failed to save latency histogram: %v", err)
+	}
+
+	if uri := envString("DEMO_REPORT_URI", ""); uri != "" {
+		if result, err := report.SaveTo(context.Background(), uri, nil); err != nil {
+			log.Printf("failed to upload report: %v", err)
+		} else {
+			fmt.Println("Report uploaded:", result.URI)
+		}
+	}
+
+	if gateway := envString("DEMO_PUSHGATEWAY_URL", ""); gateway != "" {
+		opts := &txpkg.PushOptions{Job: envString("DEMO_PUSHGATEWAY_JOB", "mass-demo-generator")}
+		if err := report.PushMetrics(context.Background(), gateway, opts); err != nil {
+			log.Printf("failed to push metrics: %v", err)
+		} else {
+			fmt.Println("Metrics pushed to", gateway)
+		}
+	}
+
 	idsPath := "./mass-demo-entities.json"
 	if err := saveEntitiesIDs(idsPath, ids); err != nil {
 		log.Printf("warning: failed to save entity IDs: %v", err)
@@ -841,18 +1507,16 @@ func printBatchSummary(summary txpkg.BatchSummary) {
 		summary.TotalTransactions, summary.SuccessCount, summary.ErrorCount, summary.SuccessRate, summary.TransactionsPerSecond)
 }
 
+// printSampleErrors logs the failed transactions grouped by category and code,
+// with one example each.
 func printSampleErrors(results []txpkg.BatchResult) {
-	sample := 0
-
-	for _, r := range results {
-		if r.Error != nil {
-			sample++
-			log.Printf("sample error [%d]: %v", sample, r.Error)
-
-			if sample >= 5 {
-				break
-			}
+	for i, g := range txpkg.BuildErrorBreakdown(results) {
+		if i >= 5 {
+			break
 		}
+
+		ex := g.Examples[0]
+		log.Printf("errors %s/%s: %d (e.g. input #%d: %s)", g.Category, g.Code, g.Count, ex.Index, ex.Message)
 	}
 }
 
@@ -0,0 +1,86 @@
+// Package main runs the SDK smoke test against a Midaz environment.
+//
+// It exercises each entity API through pkg/smoketest, prints a pass/fail line
+// per capability, and exits with status 1 when any capability fails, so it
+// can gate a deployment after a Midaz upgrade:
+//
+//	MIDAZ_AUTH_TOKEN=... MIDAZ_ONBOARDING_URL=... MIDAZ_TRANSACTION_URL=... \
+//	    go run ./examples/smoke-test -capabilities transactions,balances
+//
+// The organization created for the test is deleted at the end unless
+// -keep is set.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/config"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/smoketest"
+)
+
+func main() {
+	capabilities := flag.String("capabilities", "", "comma-separated capabilities to test (default: all)")
+	assetCode := flag.String("asset", "USD", "code of the asset created for the test")
+	keep := flag.Bool("keep", false, "keep the resources created by the test")
+	timeout := flag.Duration("timeout", 5*time.Minute, "timeout of the whole test")
+
+	flag.Parse()
+
+	cfg, err := config.NewConfig(config.FromEnvironment())
+	if err != nil {
+		log.Fatalf("Configuration failed: %v", err)
+	}
+
+	c, err := client.New(client.WithConfig(cfg), client.UseAllAPIs())
+	if err != nil {
+		log.Fatalf("Client creation failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	opts := smoketest.DefaultOptions()
+	opts.AssetCode = *assetCode
+	opts.Cleanup = !*keep
+
+	if *capabilities != "" {
+		opts.Capabilities = strings.Split(*capabilities, ",")
+	}
+
+	report, err := smoketest.Run(ctx, c, opts)
+	if err != nil && report == nil {
+		log.Fatalf("Smoke test failed to run: %v", err)
+	}
+
+	fmt.Printf("Smoke test of %s (%s)\n\n", cfg.ServiceURLs[config.ServiceOnboarding], report.Duration.Round(time.Millisecond))
+
+	for _, capability := range report.Capabilities {
+		fmt.Printf("%-20s %s\n", capability.Name, capability.Status)
+
+		for _, check := range capability.Checks {
+			if check.Status != smoketest.StatusPassed {
+				fmt.Printf("    %-18s %s: %s\n", check.Name, check.Status, check.Error)
+			}
+		}
+	}
+
+	for _, check := range report.Cleanup {
+		if check.Status == smoketest.StatusFailed {
+			fmt.Printf("cleanup: %s failed: %s\n", check.Name, check.Error)
+		}
+	}
+
+	if err != nil || !report.OK() {
+		fmt.Println("\nFAILED")
+		os.Exit(1)
+	}
+
+	fmt.Println("\nPASSED")
+}
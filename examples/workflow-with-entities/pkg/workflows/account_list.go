@@ -60,11 +60,11 @@ func demonstrateBasicListing(ctx context.Context, midazClient *client.Client, or
 
 // createBasicListOptions creates standard list options for account fetching
 func createBasicListOptions() *models.ListOptions {
-	return models.NewListOptions().
-		WithLimit(5).
-		WithOrderBy("name").
-		WithOrderDirection(models.SortAscending).
-		WithFilter("status", models.StatusActive)
+	return models.ListAccounts().
+		Limit(5).
+		OrderByNameAsc().
+		FilterStatus(models.StatusActive).
+		Options()
 }
 
 // displayAccountsPage prints account information for a page
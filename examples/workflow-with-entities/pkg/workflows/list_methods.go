@@ -58,11 +58,11 @@ func testListMethods(ctx context.Context, midazClient *client.Client, orgID, led
 func testListOrganizations(ctx context.Context, midazClient *client.Client) error {
 	fmt.Println("\n🔍 Testing ListOrganizations with pagination...")
 
-	// Create pagination options with the fluent API
-	orgOptions := models.NewListOptions().
-		WithLimit(5).
-		WithOrderBy("legalName").
-		WithOrderDirection(models.SortAscending)
+	// Create pagination options with the typed list query
+	orgOptions := models.ListOrganizations().
+		Limit(5).
+		OrderByLegalNameAsc().
+		Options()
 
 	orgsResponse, err := midazClient.Entity.Organizations.ListOrganizations(ctx, orgOptions)
 	if err != nil {
@@ -107,8 +107,9 @@ func printOrganizationsResults(orgsResponse *models.ListResponse[models.Organiza
 func testListLedgers(ctx context.Context, midazClient *client.Client, orgID string) error {
 	fmt.Println("\n🔍 Testing ListLedgers with filtering...")
 
-	ledgerOptions := models.NewListOptions().
-		WithFilter("status", models.StatusActive)
+	ledgerOptions := models.ListLedgers().
+		FilterStatus(models.StatusActive).
+		Options()
 
 	ledgersResponse, err := midazClient.Entity.Ledgers.ListLedgers(ctx, orgID, ledgerOptions)
 	if err != nil {
@@ -128,11 +129,11 @@ func testListLedgers(ctx context.Context, midazClient *client.Client, orgID stri
 func testListAccountsWithPagination(ctx context.Context, midazClient *client.Client, orgID, ledgerID string) error {
 	fmt.Println("\n🔍 Testing ListAccounts with pagination and filtering...")
 
-	accountOptions := models.NewListOptions().
-		WithLimit(3).
-		WithOrderBy("createdAt").
-		WithOrderDirection(models.SortDescending).
-		WithFilter("type", "CUSTOMER")
+	accountOptions := models.ListAccounts().
+		Limit(3).
+		OrderByCreatedAtDesc().
+		FilterType("CUSTOMER").
+		Options()
 
 	accountsResponse, err := midazClient.Entity.Accounts.ListAccounts(ctx, orgID, ledgerID, accountOptions)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation/core"
 	"github.com/LerianStudio/midaz/v3/pkg/mmodel"
 )
@@ -254,6 +255,30 @@ func (input *CreateAccountInput) WithAlias(alias string) *CreateAccountInput {
 	return input
 }
 
+// CheckAlias checks the alias of the input against the alias policy of the
+// organization, reporting naming violations before the account is created.
+// Inputs without an alias pass.
+//
+// Parameters:
+//   - policies: The registered alias policies (nil only checks the API grammar)
+//   - organizationID: The organization the account is created in
+//   - ledgerID: The ledger the account is created in
+//
+// Returns:
+//   - A *validation.FieldErrors or *validation.FieldError describing the violations, or nil
+func (input *CreateAccountInput) CheckAlias(policies *validation.AliasPolicies, organizationID, ledgerID string) error {
+	if input.Alias == nil {
+		return nil
+	}
+
+	return policies.Check(validation.AliasRequest{
+		OrganizationID: organizationID,
+		LedgerID:       ledgerID,
+		AccountType:    input.Type,
+		Alias:          *input.Alias,
+	})
+}
+
 // WithMetadata sets the metadata.
 // Metadata can store additional custom information about the account.
 //
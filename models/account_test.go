@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
 	"github.com/LerianStudio/midaz/v3/pkg/mmodel"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -228,6 +229,22 @@ func TestCreateAccountInputValidate(t *testing.T) {
 }
 
 // TestCreateAccountInputWithParentAccountID tests the WithParentAccountID builder method
+// TestCreateAccountInputCheckAlias tests checking aliases against alias policies
+func TestCreateAccountInputCheckAlias(t *testing.T) {
+	policies := validation.NewAliasPolicies()
+	require.NoError(t, policies.Register("org-1", validation.AliasPolicy{
+		TypePrefixes: map[string]string{"deposit": "dep_"},
+	}))
+
+	input := NewCreateAccountInput("Alice", "USD", "deposit")
+	require.NoError(t, input.CheckAlias(policies, "org-1", "ledger-1"), "inputs without an alias pass")
+
+	require.NoError(t, input.WithAlias("dep_alice").CheckAlias(policies, "org-1", "ledger-1"))
+	require.Error(t, input.WithAlias("alice").CheckAlias(policies, "org-1", "ledger-1"))
+	require.NoError(t, input.WithAlias("alice").CheckAlias(policies, "org-2", "ledger-1"), "other organizations keep the API grammar")
+	require.Error(t, input.WithAlias("bad alias").CheckAlias(nil, "org-1", "ledger-1"))
+}
+
 func TestCreateAccountInputWithParentAccountID(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -3,6 +3,7 @@ package models
 
 import (
 	"errors"
+	"time"
 
 	"github.com/LerianStudio/midaz/v3/pkg/mmodel"
 )
@@ -10,6 +11,9 @@ import (
 // Balance is an alias for mmodel.Balance to maintain compatibility while using midaz entities.
 type Balance = mmodel.Balance
 
+// BalanceHistory is an alias for mmodel.BalanceHistory, the state of a balance at a past point in time.
+type BalanceHistory = mmodel.BalanceHistory
+
 // UpdateBalanceInput is the input for updating a balance.
 // This structure contains the fields that can be modified when updating an existing balance.
 type UpdateBalanceInput struct {
@@ -74,3 +78,36 @@ func (input *CreateBalanceInput) Validate() error {
 
 	return nil
 }
+
+// GetManyOptions configures the GetMany method of the balances service.
+type GetManyOptions struct {
+	// Concurrency is the number of accounts fetched in parallel,
+	// entities.DefaultGetManyConcurrency if zero
+	Concurrency int
+
+	// RateLimit caps the requests per second across all workers; zero means no limit
+	RateLimit int
+
+	// CacheTTL keeps fetched balances for this long, so repeated calls for the same
+	// accounts skip the API; zero disables caching
+	CacheTTL time.Duration
+
+	// LedgerScanThreshold batches the lookup when at least this many accounts are
+	// requested: all balances of the ledger are listed, a full page per request,
+	// instead of one request per account. Use it when most accounts of the ledger
+	// are requested; zero never scans.
+	LedgerScanThreshold int
+}
+
+// AccountBalances holds the balances of one account retrieved by the GetMany
+// method of the balances service.
+type AccountBalances struct {
+	AccountID string
+	Balances  []Balance
+
+	// Error is the failure to retrieve the balances of this account, if any
+	Error error
+
+	// Cached reports whether the balances were served from the cache
+	Cached bool
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateRange is the half-open interval of instants [Start, End) used to
+// filter list calls by creation time.
+//
+// The API filters by whole UTC days, with both the start and the end date
+// inclusive. A range in a business time zone rarely falls on UTC day
+// boundaries, so APIDates widens it to the UTC days it overlaps and Contains
+// trims the items of the extra hours:
+//
+//	loc, _ := time.LoadLocation("America/Sao_Paulo")
+//	january := models.LocalDateRange(loc, jan1, jan31)
+//
+//	resp, err := client.Entity.Transactions.ListTransactions(ctx, orgID, ledgerID,
+//	    models.NewListOptions().WithCreatedRange(january))
+//	for _, tx := range resp.Items {
+//	    if january.Contains(tx.CreatedAt) {
+//	        // created in January, São Paulo time
+//	    }
+//	}
+type DateRange struct {
+	// Start is the first instant of the range
+	Start time.Time
+
+	// End is the first instant after the range
+	End time.Time
+}
+
+// NewDateRange returns the range from start up to, but excluding, end.
+func NewDateRange(start, end time.Time) DateRange {
+	return DateRange{Start: start, End: end}
+}
+
+// NewInclusiveDateRange returns the range from start through end, both included.
+func NewInclusiveDateRange(start, end time.Time) DateRange {
+	return DateRange{Start: start, End: end.Add(time.Nanosecond)}
+}
+
+// LocalDateRange returns the range covering the calendar days first through
+// last, both included, in loc. Only the dates of first and last are used, as
+// written: their clock time and location are ignored. A nil loc is UTC.
+func LocalDateRange(loc *time.Location, first, last time.Time) DateRange {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	fy, fm, fd := first.Date()
+	ly, lm, ld := last.Date()
+
+	return DateRange{
+		Start: time.Date(fy, fm, fd, 0, 0, 0, 0, loc),
+		End:   time.Date(ly, lm, ld+1, 0, 0, 0, 0, loc),
+	}
+}
+
+// LocalDay returns the range covering the calendar day of day in loc.
+func LocalDay(loc *time.Location, day time.Time) DateRange {
+	return LocalDateRange(loc, day, day)
+}
+
+// IsZero reports whether the range is unset.
+func (r DateRange) IsZero() bool {
+	return r.Start.IsZero() && r.End.IsZero()
+}
+
+// Validate returns an error if the range is empty or reversed.
+func (r DateRange) Validate() error {
+	if !r.End.After(r.Start) {
+		return fmt.Errorf("date range end %s must be after start %s", r.End.Format(time.RFC3339), r.Start.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// Contains reports whether t is within the range.
+func (r DateRange) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+// APIDates returns the inclusive UTC dates, in the YYYY-MM-DD format of the
+// API, of the smallest day range covering r.
+func (r DateRange) APIDates() (startDate, endDate string) {
+	last := r.End.Add(-time.Nanosecond)
+	if last.Before(r.Start) {
+		last = r.Start
+	}
+
+	return r.Start.UTC().Format(listDateLayout), last.UTC().Format(listDateLayout)
+}
+
+// WithCreatedRange filters the items created within r, widened to the UTC
+// days the API filters by. See DateRange.
+//
+// Parameters:
+//   - r: The creation time range, in any time zone
+//
+// Returns:
+//   - The modified ListOptions instance for method chaining
+func (o *ListOptions) WithCreatedRange(r DateRange) *ListOptions {
+	start, end := r.APIDates()
+	return o.WithDateRange(start, end)
+}
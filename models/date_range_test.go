@@ -0,0 +1,101 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalDateRange(t *testing.T) {
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 12, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name      string
+		r         DateRange
+		wantStart string
+		wantEnd   string
+	}{
+		{
+			name:      "behind UTC extends into the next UTC day",
+			r:         LocalDateRange(saoPaulo, day(2025, 1, 1), day(2025, 1, 31)),
+			wantStart: "2025-01-01",
+			wantEnd:   "2025-02-01",
+		},
+		{
+			name:      "ahead of UTC starts on the previous UTC day",
+			r:         LocalDay(tokyo, day(2025, 3, 10)),
+			wantStart: "2025-03-09",
+			wantEnd:   "2025-03-10",
+		},
+		{
+			name:      "UTC days map to themselves",
+			r:         LocalDateRange(nil, day(2025, 1, 1), day(2025, 1, 31)),
+			wantStart: "2025-01-01",
+			wantEnd:   "2025-01-31",
+		},
+		{
+			name:      "exclusive end at midnight excludes that day",
+			r:         NewDateRange(day(2025, 1, 1).Truncate(24*time.Hour), time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)),
+			wantStart: "2025-01-01",
+			wantEnd:   "2025-01-02",
+		},
+		{
+			name:      "inclusive end at midnight includes that day",
+			r:         NewInclusiveDateRange(day(2025, 1, 1), time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)),
+			wantStart: "2025-01-01",
+			wantEnd:   "2025-01-03",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end := tc.r.APIDates()
+			assert.Equal(t, tc.wantStart, start)
+			assert.Equal(t, tc.wantEnd, end)
+			assert.NoError(t, tc.r.Validate())
+		})
+	}
+}
+
+func TestDateRange_Contains(t *testing.T) {
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+	jan := LocalDateRange(saoPaulo, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC))
+
+	assert.True(t, jan.Contains(time.Date(2025, 2, 1, 2, 59, 59, 0, time.UTC)), "still January 31 in São Paulo")
+	assert.False(t, jan.Contains(time.Date(2025, 2, 1, 3, 0, 0, 0, time.UTC)), "the end is exclusive")
+	assert.True(t, jan.Contains(time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)), "the start is inclusive")
+	assert.False(t, jan.Contains(time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC)))
+}
+
+func TestDateRange_DST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	// Clocks move forward on 2025-03-09, a 23 hour day
+	r := LocalDay(ny, time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, 23*time.Hour, r.End.Sub(r.Start))
+}
+
+func TestDateRange_Validate(t *testing.T) {
+	now := time.Now()
+
+	require.Error(t, NewDateRange(now, now).Validate())
+	require.Error(t, NewDateRange(now, now.Add(-time.Hour)).Validate())
+	assert.True(t, DateRange{}.IsZero())
+}
+
+func TestListOptions_WithCreatedRange(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	opts := NewListOptions().WithCreatedRange(LocalDay(tokyo, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)))
+
+	params := opts.ToQueryParams()
+	assert.Equal(t, "2025-03-09", params[QueryParamStartDate])
+	assert.Equal(t, "2025-03-10", params[QueryParamEndDate])
+}
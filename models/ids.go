@@ -0,0 +1,192 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Distinct ID types keep the IDs of different resources apart: a LedgerID
+// can't be passed where an AccountID is expected without an explicit
+// conversion. They are strings underneath, so converting from and to the
+// string IDs of the models and services is free:
+//
+//	accountID := models.AccountID(account.ID)
+//	_, err := c.Entity.Accounts.GetAccount(ctx, orgID.String(), ledgerID.String(), accountID.String())
+//
+// The Parse functions check that a string is a UUID, the format of Midaz IDs,
+// and should be used on IDs coming from outside the program.
+type (
+	// OrganizationID is the ID of an organization
+	OrganizationID string
+
+	// LedgerID is the ID of a ledger
+	LedgerID string
+
+	// AccountID is the ID of an account
+	AccountID string
+
+	// AssetID is the ID of an asset
+	AssetID string
+
+	// PortfolioID is the ID of a portfolio
+	PortfolioID string
+
+	// SegmentID is the ID of a segment
+	SegmentID string
+
+	// TransactionID is the ID of a transaction
+	TransactionID string
+
+	// OperationID is the ID of an operation
+	OperationID string
+
+	// BalanceID is the ID of a balance
+	BalanceID string
+)
+
+// InvalidIDError is returned when a string is not a valid ID of a resource.
+type InvalidIDError struct {
+	// Kind is the kind of ID, such as "account"
+	Kind string
+
+	// Value is the rejected string
+	Value string
+}
+
+// Error implements the error interface.
+func (e *InvalidIDError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("invalid %s ID: empty", e.Kind)
+	}
+
+	return fmt.Sprintf("invalid %s ID %q: not a UUID", e.Kind, e.Value)
+}
+
+// parseID returns the canonical form of a UUID, or an InvalidIDError.
+func parseID(kind, s string) (string, error) {
+	s = strings.TrimSpace(s)
+
+	id, err := uuid.Parse(s)
+	if err != nil || len(s) != len(id.String()) {
+		return "", &InvalidIDError{Kind: kind, Value: s}
+	}
+
+	return id.String(), nil
+}
+
+// validateID returns an InvalidIDError when s is not a UUID.
+func validateID(kind, s string) error {
+	_, err := parseID(kind, s)
+
+	return err
+}
+
+// ParseOrganizationID parses an organization ID.
+func ParseOrganizationID(s string) (OrganizationID, error) {
+	id, err := parseID("organization", s)
+	return OrganizationID(id), err
+}
+
+// String returns the ID as a string.
+func (id OrganizationID) String() string { return string(id) }
+
+// Validate checks that the ID is a UUID.
+func (id OrganizationID) Validate() error { return validateID("organization", string(id)) }
+
+// ParseLedgerID parses a ledger ID.
+func ParseLedgerID(s string) (LedgerID, error) {
+	id, err := parseID("ledger", s)
+	return LedgerID(id), err
+}
+
+// String returns the ID as a string.
+func (id LedgerID) String() string { return string(id) }
+
+// Validate checks that the ID is a UUID.
+func (id LedgerID) Validate() error { return validateID("ledger", string(id)) }
+
+// ParseAccountID parses an account ID.
+func ParseAccountID(s string) (AccountID, error) {
+	id, err := parseID("account", s)
+	return AccountID(id), err
+}
+
+// String returns the ID as a string.
+func (id AccountID) String() string { return string(id) }
+
+// Validate checks that the ID is a UUID.
+func (id AccountID) Validate() error { return validateID("account", string(id)) }
+
+// ParseAssetID parses an asset ID.
+func ParseAssetID(s string) (AssetID, error) {
+	id, err := parseID("asset", s)
+	return AssetID(id), err
+}
+
+// String returns the ID as a string.
+func (id AssetID) String() string { return string(id) }
+
+// Validate checks that the ID is a UUID.
+func (id AssetID) Validate() error { return validateID("asset", string(id)) }
+
+// ParsePortfolioID parses a portfolio ID.
+func ParsePortfolioID(s string) (PortfolioID, error) {
+	id, err := parseID("portfolio", s)
+	return PortfolioID(id), err
+}
+
+// String returns the ID as a string.
+func (id PortfolioID) String() string { return string(id) }
+
+// Validate checks that the ID is a UUID.
+func (id PortfolioID) Validate() error { return validateID("portfolio", string(id)) }
+
+// ParseSegmentID parses a segment ID.
+func ParseSegmentID(s string) (SegmentID, error) {
+	id, err := parseID("segment", s)
+	return SegmentID(id), err
+}
+
+// String returns the ID as a string.
+func (id SegmentID) String() string { return string(id) }
+
+// Validate checks that the ID is a UUID.
+func (id SegmentID) Validate() error { return validateID("segment", string(id)) }
+
+// ParseTransactionID parses a transaction ID.
+func ParseTransactionID(s string) (TransactionID, error) {
+	id, err := parseID("transaction", s)
+	return TransactionID(id), err
+}
+
+// String returns the ID as a string.
+func (id TransactionID) String() string { return string(id) }
+
+// Validate checks that the ID is a UUID.
+func (id TransactionID) Validate() error { return validateID("transaction", string(id)) }
+
+// ParseOperationID parses an operation ID.
+func ParseOperationID(s string) (OperationID, error) {
+	id, err := parseID("operation", s)
+	return OperationID(id), err
+}
+
+// String returns the ID as a string.
+func (id OperationID) String() string { return string(id) }
+
+// Validate checks that the ID is a UUID.
+func (id OperationID) Validate() error { return validateID("operation", string(id)) }
+
+// ParseBalanceID parses a balance ID.
+func ParseBalanceID(s string) (BalanceID, error) {
+	id, err := parseID("balance", s)
+	return BalanceID(id), err
+}
+
+// String returns the ID as a string.
+func (id BalanceID) String() string { return string(id) }
+
+// Validate checks that the ID is a UUID.
+func (id BalanceID) Validate() error { return validateID("balance", string(id)) }
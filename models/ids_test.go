@@ -0,0 +1,34 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIDs(t *testing.T) {
+	const raw = "0190F5A2-6B3C-7D4E-8F90-A1B2C3D4E5F6"
+
+	accountID, err := ParseAccountID(" " + raw + " ")
+	require.NoError(t, err)
+	assert.Equal(t, AccountID("0190f5a2-6b3c-7d4e-8f90-a1b2c3d4e5f6"), accountID, "IDs are canonicalized")
+	assert.Equal(t, "0190f5a2-6b3c-7d4e-8f90-a1b2c3d4e5f6", accountID.String())
+	require.NoError(t, accountID.Validate())
+
+	_, err = ParseLedgerID("ledger-1")
+
+	var invalid *InvalidIDError
+	require.True(t, errors.As(err, &invalid))
+	assert.Equal(t, "ledger", invalid.Kind)
+	assert.Equal(t, `invalid ledger ID "ledger-1": not a UUID`, err.Error())
+
+	_, err = ParseOrganizationID("")
+	assert.EqualError(t, err, "invalid organization ID: empty")
+
+	_, err = ParseTransactionID("{" + raw + "}")
+	assert.Error(t, err, "only the standard form is accepted")
+
+	assert.Error(t, BalanceID("not-a-uuid").Validate())
+}
@@ -0,0 +1,376 @@
+package models
+
+import "time"
+
+// List field names accepted by the typed list queries.
+const (
+	listFieldCreatedAt     = "createdAt"
+	listFieldUpdatedAt     = "updatedAt"
+	listFieldName          = "name"
+	listFieldLegalName     = "legalName"
+	listFieldCode          = "code"
+	listFieldAlias         = "alias"
+	listFieldStatus        = "status"
+	listFieldType          = "type"
+	listFieldAssetCode     = "assetCode"
+	listFieldSegmentID     = "segmentId"
+	listFieldPortfolioID   = "portfolioId"
+	listFieldEntityID      = "entityId"
+	listFieldLegalDocument = "legalDocument"
+	listMetadataPrefix     = "metadata."
+	listDateLayout         = "2006-01-02"
+)
+
+// listQuery holds the options shared by every typed list query. Q is the
+// query embedding it, so chained calls keep the resource specific methods.
+type listQuery[Q any] struct {
+	opts *ListOptions
+	self Q
+}
+
+func newListQuery[Q any](self Q) listQuery[Q] {
+	return listQuery[Q]{opts: NewListOptions(), self: self}
+}
+
+// Limit sets the page size, capped at MaxLimit.
+func (q *listQuery[Q]) Limit(limit int) Q {
+	q.opts.WithLimit(limit)
+	return q.self
+}
+
+// Offset sets the position of the first item.
+func (q *listQuery[Q]) Offset(offset int) Q {
+	q.opts.WithOffset(offset)
+	return q.self
+}
+
+// Cursor sets the cursor of the page to fetch.
+func (q *listQuery[Q]) Cursor(cursor string) Q {
+	q.opts.WithCursor(cursor)
+	return q.self
+}
+
+// CreatedBetween keeps the items created from start through end, both
+// included, converting them to the UTC dates the API filters by.
+func (q *listQuery[Q]) CreatedBetween(start, end time.Time) Q {
+	return q.CreatedIn(NewInclusiveDateRange(start, end))
+}
+
+// CreatedIn keeps the items created within r. See DateRange.
+func (q *listQuery[Q]) CreatedIn(r DateRange) Q {
+	q.opts.WithCreatedRange(r)
+	return q.self
+}
+
+// FilterMetadata keeps the items whose metadata key has value.
+func (q *listQuery[Q]) FilterMetadata(key, value string) Q {
+	q.opts.WithFilter(listMetadataPrefix+key, value)
+	return q.self
+}
+
+// OrderByCreatedAtAsc orders the items oldest first.
+func (q *listQuery[Q]) OrderByCreatedAtAsc() Q {
+	return q.orderBy(listFieldCreatedAt, SortAscending)
+}
+
+// OrderByCreatedAtDesc orders the items newest first.
+func (q *listQuery[Q]) OrderByCreatedAtDesc() Q {
+	return q.orderBy(listFieldCreatedAt, SortDescending)
+}
+
+// OrderByUpdatedAtAsc orders the items least recently updated first.
+func (q *listQuery[Q]) OrderByUpdatedAtAsc() Q {
+	return q.orderBy(listFieldUpdatedAt, SortAscending)
+}
+
+// OrderByUpdatedAtDesc orders the items most recently updated first.
+func (q *listQuery[Q]) OrderByUpdatedAtDesc() Q {
+	return q.orderBy(listFieldUpdatedAt, SortDescending)
+}
+
+// Options returns the ListOptions to pass to the list call.
+func (q *listQuery[Q]) Options() *ListOptions {
+	return q.opts
+}
+
+func (q *listQuery[Q]) orderBy(field string, direction SortDirection) Q {
+	q.opts.WithOrderBy(field).WithOrderDirection(direction)
+	return q.self
+}
+
+func (q *listQuery[Q]) filter(field, value string) Q {
+	q.opts.WithFilter(field, value)
+	return q.self
+}
+
+// OrganizationListQuery builds the options of Organizations.ListOrganizations.
+type OrganizationListQuery struct {
+	listQuery[*OrganizationListQuery]
+}
+
+// ListOrganizations starts a typed query for listing organizations.
+func ListOrganizations() *OrganizationListQuery {
+	q := &OrganizationListQuery{}
+	q.listQuery = newListQuery(q)
+
+	return q
+}
+
+// FilterStatus keeps the organizations with the status code, e.g. StatusActive.
+func (q *OrganizationListQuery) FilterStatus(status string) *OrganizationListQuery {
+	return q.filter(listFieldStatus, status)
+}
+
+// FilterLegalDocument keeps the organizations with the legal document.
+func (q *OrganizationListQuery) FilterLegalDocument(document string) *OrganizationListQuery {
+	return q.filter(listFieldLegalDocument, document)
+}
+
+// OrderByLegalNameAsc orders the organizations by legal name, A to Z.
+func (q *OrganizationListQuery) OrderByLegalNameAsc() *OrganizationListQuery {
+	return q.orderBy(listFieldLegalName, SortAscending)
+}
+
+// LedgerListQuery builds the options of Ledgers.ListLedgers.
+type LedgerListQuery struct {
+	listQuery[*LedgerListQuery]
+}
+
+// ListLedgers starts a typed query for listing ledgers.
+func ListLedgers() *LedgerListQuery {
+	q := &LedgerListQuery{}
+	q.listQuery = newListQuery(q)
+
+	return q
+}
+
+// FilterStatus keeps the ledgers with the status code.
+func (q *LedgerListQuery) FilterStatus(status string) *LedgerListQuery {
+	return q.filter(listFieldStatus, status)
+}
+
+// FilterName keeps the ledgers with the name.
+func (q *LedgerListQuery) FilterName(name string) *LedgerListQuery {
+	return q.filter(listFieldName, name)
+}
+
+// OrderByNameAsc orders the ledgers by name, A to Z.
+func (q *LedgerListQuery) OrderByNameAsc() *LedgerListQuery {
+	return q.orderBy(listFieldName, SortAscending)
+}
+
+// AssetListQuery builds the options of Assets.ListAssets.
+type AssetListQuery struct {
+	listQuery[*AssetListQuery]
+}
+
+// ListAssets starts a typed query for listing assets.
+func ListAssets() *AssetListQuery {
+	q := &AssetListQuery{}
+	q.listQuery = newListQuery(q)
+
+	return q
+}
+
+// FilterStatus keeps the assets with the status code.
+func (q *AssetListQuery) FilterStatus(status string) *AssetListQuery {
+	return q.filter(listFieldStatus, status)
+}
+
+// FilterType keeps the assets of the type, e.g. "currency".
+func (q *AssetListQuery) FilterType(assetType string) *AssetListQuery {
+	return q.filter(listFieldType, assetType)
+}
+
+// FilterCode keeps the asset with the code.
+func (q *AssetListQuery) FilterCode(code string) *AssetListQuery {
+	return q.filter(listFieldCode, code)
+}
+
+// OrderByNameAsc orders the assets by name, A to Z.
+func (q *AssetListQuery) OrderByNameAsc() *AssetListQuery {
+	return q.orderBy(listFieldName, SortAscending)
+}
+
+// OrderByCodeAsc orders the assets by code, A to Z.
+func (q *AssetListQuery) OrderByCodeAsc() *AssetListQuery {
+	return q.orderBy(listFieldCode, SortAscending)
+}
+
+// AccountListQuery builds the options of Accounts.ListAccounts.
+type AccountListQuery struct {
+	listQuery[*AccountListQuery]
+}
+
+// ListAccounts starts a typed query for listing accounts.
+//
+// Example:
+//
+//	opts := models.ListAccounts().
+//	    OrderByCreatedAtDesc().
+//	    FilterStatus(models.StatusActive).
+//	    Limit(50).
+//	    Options()
+//
+//	accounts, err := client.Entity.Accounts.ListAccounts(ctx, orgID, ledgerID, opts)
+func ListAccounts() *AccountListQuery {
+	q := &AccountListQuery{}
+	q.listQuery = newListQuery(q)
+
+	return q
+}
+
+// FilterStatus keeps the accounts with the status code.
+func (q *AccountListQuery) FilterStatus(status string) *AccountListQuery {
+	return q.filter(listFieldStatus, status)
+}
+
+// FilterType keeps the accounts of the account type key.
+func (q *AccountListQuery) FilterType(accountType string) *AccountListQuery {
+	return q.filter(listFieldType, accountType)
+}
+
+// FilterAlias keeps the account with the alias.
+func (q *AccountListQuery) FilterAlias(alias string) *AccountListQuery {
+	return q.filter(listFieldAlias, alias)
+}
+
+// FilterAssetCode keeps the accounts of the asset.
+func (q *AccountListQuery) FilterAssetCode(assetCode string) *AccountListQuery {
+	return q.filter(listFieldAssetCode, assetCode)
+}
+
+// FilterSegmentID keeps the accounts of the segment.
+func (q *AccountListQuery) FilterSegmentID(segmentID string) *AccountListQuery {
+	return q.filter(listFieldSegmentID, segmentID)
+}
+
+// FilterPortfolioID keeps the accounts of the portfolio.
+func (q *AccountListQuery) FilterPortfolioID(portfolioID string) *AccountListQuery {
+	return q.filter(listFieldPortfolioID, portfolioID)
+}
+
+// OrderByNameAsc orders the accounts by name, A to Z.
+func (q *AccountListQuery) OrderByNameAsc() *AccountListQuery {
+	return q.orderBy(listFieldName, SortAscending)
+}
+
+// OrderByAliasAsc orders the accounts by alias, A to Z.
+func (q *AccountListQuery) OrderByAliasAsc() *AccountListQuery {
+	return q.orderBy(listFieldAlias, SortAscending)
+}
+
+// PortfolioListQuery builds the options of Portfolios.ListPortfolios.
+type PortfolioListQuery struct {
+	listQuery[*PortfolioListQuery]
+}
+
+// ListPortfolios starts a typed query for listing portfolios.
+func ListPortfolios() *PortfolioListQuery {
+	q := &PortfolioListQuery{}
+	q.listQuery = newListQuery(q)
+
+	return q
+}
+
+// FilterStatus keeps the portfolios with the status code.
+func (q *PortfolioListQuery) FilterStatus(status string) *PortfolioListQuery {
+	return q.filter(listFieldStatus, status)
+}
+
+// FilterEntityID keeps the portfolios of the external entity.
+func (q *PortfolioListQuery) FilterEntityID(entityID string) *PortfolioListQuery {
+	return q.filter(listFieldEntityID, entityID)
+}
+
+// OrderByNameAsc orders the portfolios by name, A to Z.
+func (q *PortfolioListQuery) OrderByNameAsc() *PortfolioListQuery {
+	return q.orderBy(listFieldName, SortAscending)
+}
+
+// SegmentListQuery builds the options of Segments.ListSegments.
+type SegmentListQuery struct {
+	listQuery[*SegmentListQuery]
+}
+
+// ListSegments starts a typed query for listing segments.
+func ListSegments() *SegmentListQuery {
+	q := &SegmentListQuery{}
+	q.listQuery = newListQuery(q)
+
+	return q
+}
+
+// FilterStatus keeps the segments with the status code.
+func (q *SegmentListQuery) FilterStatus(status string) *SegmentListQuery {
+	return q.filter(listFieldStatus, status)
+}
+
+// OrderByNameAsc orders the segments by name, A to Z.
+func (q *SegmentListQuery) OrderByNameAsc() *SegmentListQuery {
+	return q.orderBy(listFieldName, SortAscending)
+}
+
+// TransactionListQuery builds the options of Transactions.ListTransactions.
+type TransactionListQuery struct {
+	listQuery[*TransactionListQuery]
+}
+
+// ListTransactions starts a typed query for listing transactions.
+func ListTransactions() *TransactionListQuery {
+	q := &TransactionListQuery{}
+	q.listQuery = newListQuery(q)
+
+	return q
+}
+
+// FilterStatus keeps the transactions with the status code, e.g. TransactionStatusCompleted.
+func (q *TransactionListQuery) FilterStatus(status string) *TransactionListQuery {
+	return q.filter(listFieldStatus, status)
+}
+
+// FilterAssetCode keeps the transactions of the asset.
+func (q *TransactionListQuery) FilterAssetCode(assetCode string) *TransactionListQuery {
+	return q.filter(listFieldAssetCode, assetCode)
+}
+
+// OperationListQuery builds the options of Operations.ListOperations.
+type OperationListQuery struct {
+	listQuery[*OperationListQuery]
+}
+
+// ListOperations starts a typed query for listing operations.
+func ListOperations() *OperationListQuery {
+	q := &OperationListQuery{}
+	q.listQuery = newListQuery(q)
+
+	return q
+}
+
+// FilterType keeps the operations of the type, e.g. "DEBIT" or "CREDIT".
+func (q *OperationListQuery) FilterType(operationType string) *OperationListQuery {
+	return q.filter(listFieldType, operationType)
+}
+
+// FilterAssetCode keeps the operations of the asset.
+func (q *OperationListQuery) FilterAssetCode(assetCode string) *OperationListQuery {
+	return q.filter(listFieldAssetCode, assetCode)
+}
+
+// BalanceListQuery builds the options of Balances.ListBalances and ListAccountBalances.
+type BalanceListQuery struct {
+	listQuery[*BalanceListQuery]
+}
+
+// ListBalances starts a typed query for listing balances.
+func ListBalances() *BalanceListQuery {
+	q := &BalanceListQuery{}
+	q.listQuery = newListQuery(q)
+
+	return q
+}
+
+// FilterAssetCode keeps the balances of the asset.
+func (q *BalanceListQuery) FilterAssetCode(assetCode string) *BalanceListQuery {
+	return q.filter(listFieldAssetCode, assetCode)
+}
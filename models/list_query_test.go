@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListAccounts(t *testing.T) {
+	opts := ListAccounts().
+		OrderByCreatedAtDesc().
+		FilterStatus(StatusActive).
+		FilterAssetCode("USD").
+		FilterMetadata("tier", "gold").
+		Limit(50).
+		Offset(100).
+		Options()
+
+	assert.Equal(t, map[string]string{
+		QueryParamLimit:          "50",
+		QueryParamOffset:         "100",
+		QueryParamOrderBy:        "createdAt",
+		QueryParamOrderDirection: "desc",
+		"status":                 StatusActive,
+		"assetCode":              "USD",
+		"metadata.tier":          "gold",
+	}, opts.ToQueryParams())
+}
+
+func TestListQuery_Defaults(t *testing.T) {
+	opts := ListTransactions().Options()
+
+	assert.Equal(t, DefaultLimit, opts.Limit)
+	assert.Equal(t, DefaultSortDirection, opts.OrderDirection)
+	assert.Empty(t, opts.Filters)
+}
+
+func TestListQuery_Ordering(t *testing.T) {
+	opts := ListOrganizations().OrderByCreatedAtDesc().OrderByLegalNameAsc().Options()
+	assert.Equal(t, "legalName", opts.OrderBy, "the last order wins")
+	assert.Equal(t, string(SortAscending), opts.OrderDirection)
+
+	opts = ListAssets().OrderByUpdatedAtDesc().Options()
+	assert.Equal(t, "updatedAt", opts.OrderBy)
+	assert.Equal(t, string(SortDescending), opts.OrderDirection)
+}
+
+func TestListQuery_CreatedBetween(t *testing.T) {
+	start := time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	opts := ListOperations().FilterType("DEBIT").CreatedBetween(start, end).Cursor("abc").Options()
+
+	assert.Equal(t, "2025-01-01", opts.StartDate)
+	assert.Equal(t, "2025-01-31", opts.EndDate)
+	assert.Equal(t, "abc", opts.Cursor)
+	assert.Equal(t, "DEBIT", opts.Filters["type"])
+}
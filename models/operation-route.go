@@ -49,7 +49,7 @@ func (*UpdateOperationRouteInput) Validate() error {
 // WithAccountAlias sets the account rule to use alias-based selection (method on struct).
 func (input *CreateOperationRouteInput) WithAccountAlias(alias string) *CreateOperationRouteInput {
 	input.Account = &AccountRule{
-		RuleType: "alias",
+		RuleType: AccountRuleTypeAlias,
 		ValidIf:  alias,
 	}
 
@@ -59,7 +59,7 @@ func (input *CreateOperationRouteInput) WithAccountAlias(alias string) *CreateOp
 // WithAccountTypes sets the account rule to use account type-based selection (method on struct).
 func (input *CreateOperationRouteInput) WithAccountTypes(accountTypes []string) *CreateOperationRouteInput {
 	input.Account = &AccountRule{
-		RuleType: "account_type",
+		RuleType: AccountRuleTypeAccountType,
 		ValidIf:  accountTypes,
 	}
 
@@ -87,7 +87,7 @@ func (input *UpdateOperationRouteInput) WithDescription(description string) *Upd
 // WithAccountTypes sets the account rule to use account type-based selection for UpdateOperationRouteInput (method on struct).
 func (input *UpdateOperationRouteInput) WithAccountTypes(accountTypes []string) *UpdateOperationRouteInput {
 	input.Account = &AccountRule{
-		RuleType: "account_type",
+		RuleType: AccountRuleTypeAccountType,
 		ValidIf:  accountTypes,
 	}
 
@@ -158,7 +158,7 @@ func NewCreateOperationRouteInput(title, description, operationType string) *Cre
 //   - A pointer to the modified CreateOperationRouteInput for method chaining
 func WithCreateOperationRouteAccountAlias(input *CreateOperationRouteInput, alias string) *CreateOperationRouteInput {
 	input.Account = &AccountRule{
-		RuleType: "alias",
+		RuleType: AccountRuleTypeAlias,
 		ValidIf:  alias,
 	}
 
@@ -175,7 +175,7 @@ func WithCreateOperationRouteAccountAlias(input *CreateOperationRouteInput, alia
 //   - A pointer to the modified CreateOperationRouteInput for method chaining
 func WithCreateOperationRouteAccountType(input *CreateOperationRouteInput, accountTypes []string) *CreateOperationRouteInput {
 	input.Account = &AccountRule{
-		RuleType: "account_type",
+		RuleType: AccountRuleTypeAccountType,
 		ValidIf:  accountTypes,
 	}
 
@@ -239,7 +239,7 @@ func WithUpdateOperationRouteDescription(input *UpdateOperationRouteInput, descr
 //   - A pointer to the modified UpdateOperationRouteInput for method chaining
 func WithUpdateOperationRouteAccountAlias(input *UpdateOperationRouteInput, alias string) *UpdateOperationRouteInput {
 	input.Account = &AccountRule{
-		RuleType: "alias",
+		RuleType: AccountRuleTypeAlias,
 		ValidIf:  alias,
 	}
 
@@ -256,7 +256,7 @@ func WithUpdateOperationRouteAccountAlias(input *UpdateOperationRouteInput, alia
 //   - A pointer to the modified UpdateOperationRouteInput for method chaining
 func WithUpdateOperationRouteAccountType(input *UpdateOperationRouteInput, accountTypes []string) *UpdateOperationRouteInput {
 	input.Account = &AccountRule{
-		RuleType: "account_type",
+		RuleType: AccountRuleTypeAccountType,
 		ValidIf:  accountTypes,
 	}
 
@@ -0,0 +1,100 @@
+package models
+
+// Page is a uniform view of one page of a list call, with the metadata needed
+// to render pagination controls. Every list call returns a ListResponse, and
+// ListResponse.Page converts it regardless of the resource listed or whether
+// the service paginates by offset or by cursor.
+type Page[T any] struct {
+	// Items are the resources of the page
+	Items []T `json:"items"`
+
+	// Total is the number of items across all pages, valid only if TotalKnown
+	Total int `json:"total"`
+
+	// TotalKnown reports whether the service returned a total count; cursor
+	// paginated services usually don't
+	TotalKnown bool `json:"totalKnown"`
+
+	// Limit is the page size requested
+	Limit int `json:"limit"`
+
+	// Offset is the position of the first item, zero for cursor pagination
+	Offset int `json:"offset"`
+
+	// Number is the 1-based page number and TotalPages the number of pages;
+	// TotalPages is zero when the total is unknown
+	Number     int `json:"number"`
+	TotalPages int `json:"totalPages"`
+
+	// NextCursor and PrevCursor are the cursors of the adjacent pages, if any
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+
+	// HasMore reports whether a next page exists and HasPrevious whether a
+	// previous one does
+	HasMore     bool `json:"hasMore"`
+	HasPrevious bool `json:"hasPrevious"`
+
+	pagination Pagination
+}
+
+// Page returns the page of the response with its pagination metadata.
+// A nil response yields an empty page.
+//
+// Example:
+//
+//	resp, err := client.Entity.Accounts.ListAccounts(ctx, orgID, ledgerID, opts)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	page := resp.Page()
+//	fmt.Printf("page %d of %d, more: %t\n", page.Number, page.TotalPages, page.HasMore)
+//	next := page.NextOptions() // nil on the last page
+func (r *ListResponse[T]) Page() Page[T] {
+	if r == nil {
+		return Page[T]{Items: []T{}, Number: 1}
+	}
+
+	p := r.Pagination
+
+	items := r.Items
+	if items == nil {
+		items = []T{}
+	}
+
+	page := Page[T]{
+		Items:       items,
+		Total:       p.Total,
+		TotalKnown:  p.Total > 0 || (p.NextCursor == "" && p.PrevCursor == "" && len(items) == 0),
+		Limit:       p.Limit,
+		Offset:      p.Offset,
+		Number:      p.CurrentPage(),
+		NextCursor:  p.NextCursor,
+		PrevCursor:  p.PrevCursor,
+		HasMore:     p.HasNextPage(),
+		HasPrevious: p.HasPrevPage(),
+		pagination:  p,
+	}
+
+	if page.TotalKnown {
+		page.TotalPages = p.TotalPages()
+	}
+
+	return page
+}
+
+// Len returns the number of items in the page.
+func (p Page[T]) Len() int {
+	return len(p.Items)
+}
+
+// NextOptions returns the options fetching the next page, nil if there is none.
+func (p Page[T]) NextOptions() *ListOptions {
+	return p.pagination.NextPageOptions()
+}
+
+// PrevOptions returns the options fetching the previous page, nil if there is none.
+func (p Page[T]) PrevOptions() *ListOptions {
+	return p.pagination.PrevPageOptions()
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListResponse_Page(t *testing.T) {
+	t.Run("offset pagination", func(t *testing.T) {
+		resp := &ListResponse[Account]{
+			Items:      []Account{{ID: "acc-3"}, {ID: "acc-4"}},
+			Pagination: Pagination{Limit: 2, Offset: 2, Total: 5},
+		}
+
+		page := resp.Page()
+		assert.Equal(t, 2, page.Len())
+		assert.True(t, page.TotalKnown)
+		assert.Equal(t, 5, page.Total)
+		assert.Equal(t, 2, page.Number)
+		assert.Equal(t, 3, page.TotalPages)
+		assert.True(t, page.HasMore)
+		assert.True(t, page.HasPrevious)
+
+		require.NotNil(t, page.NextOptions())
+		assert.Equal(t, 4, page.NextOptions().Offset)
+		assert.Equal(t, 0, page.PrevOptions().Offset)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		page := (&ListResponse[Account]{
+			Items:      []Account{{ID: "acc-5"}},
+			Pagination: Pagination{Limit: 2, Offset: 4, Total: 5},
+		}).Page()
+
+		assert.False(t, page.HasMore)
+		assert.Nil(t, page.NextOptions())
+		assert.Equal(t, 3, page.Number)
+	})
+
+	t.Run("cursor pagination", func(t *testing.T) {
+		page := (&ListResponse[Transaction]{
+			Items:      []Transaction{{ID: "tx-1"}},
+			Pagination: Pagination{Limit: 1, NextCursor: "next", PrevCursor: "prev"},
+		}).Page()
+
+		assert.False(t, page.TotalKnown)
+		assert.Zero(t, page.TotalPages)
+		assert.True(t, page.HasMore)
+		assert.True(t, page.HasPrevious)
+		assert.Equal(t, "next", page.NextOptions().Cursor)
+		assert.Equal(t, "prev", page.PrevOptions().Cursor)
+	})
+
+	t.Run("nil and empty responses", func(t *testing.T) {
+		var resp *ListResponse[Ledger]
+
+		page := resp.Page()
+		assert.NotNil(t, page.Items)
+		assert.Zero(t, page.Len())
+		assert.False(t, page.HasMore)
+		assert.Nil(t, page.NextOptions())
+
+		page = (&ListResponse[Ledger]{}).Page()
+		assert.True(t, page.TotalKnown)
+		assert.NotNil(t, page.Items)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		page := (&ListResponse[Account]{
+			Items:      []Account{{ID: "acc-1"}},
+			Pagination: Pagination{Limit: 1, Total: 2},
+		}).Page()
+
+		data, err := json.Marshal(page)
+		require.NoError(t, err)
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(data, &got))
+		assert.Equal(t, true, got["hasMore"])
+		assert.InDelta(t, 2, got["total"], 0)
+		assert.InDelta(t, 2, got["totalPages"], 0)
+	})
+}
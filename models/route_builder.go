@@ -0,0 +1,411 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Account rule types supported by operation routes.
+const (
+	// AccountRuleTypeAlias selects a single account by alias
+	AccountRuleTypeAlias = "alias"
+
+	// AccountRuleTypeAccountType selects any account of the listed account types
+	AccountRuleTypeAccountType = "account_type"
+)
+
+// Field limits enforced by the routes API.
+const (
+	maxRouteTitleLength       = 255
+	maxRouteDescriptionLength = 250
+	maxRouteMetadataKeyLength = 100
+	maxRouteMetadataValueLen  = 2000
+)
+
+// OperationRouteBuilder builds a CreateOperationRouteInput step by step and
+// checks the consistency of its account rule on Build. Setter mistakes, such as
+// choosing both an alias and account types, are reported by Build rather than
+// silently overwriting earlier calls.
+//
+// Example:
+//
+//	input, err := models.NewOperationRouteBuilder("Source: Customer").
+//	    Description("Checking accounts paying merchants").
+//	    Source().
+//	    AccountTypes("checking", "savings").
+//	    Metadata("role", "customer").
+//	    Build()
+type OperationRouteBuilder struct {
+	title         string
+	description   string
+	operationType string
+	alias         *string
+	accountTypes  []string
+	metadata      map[string]any
+	errs          []error
+}
+
+// NewOperationRouteBuilder starts an operation route with the given title.
+func NewOperationRouteBuilder(title string) *OperationRouteBuilder {
+	return &OperationRouteBuilder{title: title}
+}
+
+// Description sets the description of the route.
+func (b *OperationRouteBuilder) Description(description string) *OperationRouteBuilder {
+	b.description = description
+	return b
+}
+
+// Source marks the route as a source (debit side) route.
+func (b *OperationRouteBuilder) Source() *OperationRouteBuilder {
+	return b.setOperationType(string(OperationRouteInputTypeSource))
+}
+
+// Destination marks the route as a destination (credit side) route.
+func (b *OperationRouteBuilder) Destination() *OperationRouteBuilder {
+	return b.setOperationType(string(OperationRouteInputTypeDestination))
+}
+
+// AccountAlias restricts the route to the account with the given alias.
+func (b *OperationRouteBuilder) AccountAlias(alias string) *OperationRouteBuilder {
+	if b.alias != nil && *b.alias != alias {
+		b.errs = append(b.errs, fmt.Errorf("account alias already set to %q", *b.alias))
+		return b
+	}
+
+	b.alias = &alias
+
+	return b
+}
+
+// AccountTypes restricts the route to accounts of the given account types.
+// Repeated calls add to the list.
+func (b *OperationRouteBuilder) AccountTypes(accountTypes ...string) *OperationRouteBuilder {
+	if len(accountTypes) == 0 {
+		b.errs = append(b.errs, errors.New("at least one account type is required"))
+		return b
+	}
+
+	b.accountTypes = append(b.accountTypes, accountTypes...)
+
+	return b
+}
+
+// Metadata sets a metadata entry of the route.
+func (b *OperationRouteBuilder) Metadata(key string, value any) *OperationRouteBuilder {
+	if b.metadata == nil {
+		b.metadata = make(map[string]any)
+	}
+
+	b.metadata[key] = value
+
+	return b
+}
+
+// Build validates the route and returns the input to create it. All problems
+// found are returned together.
+func (b *OperationRouteBuilder) Build() (*CreateOperationRouteInput, error) {
+	errs := append([]error(nil), b.errs...)
+	errs = append(errs, validateRouteText(b.title, b.description)...)
+
+	if b.operationType == "" {
+		errs = append(errs, errors.New("operation type is required (call Source or Destination)"))
+	}
+
+	if b.alias != nil && len(b.accountTypes) > 0 {
+		errs = append(errs, errors.New("account rule cannot use both an alias and account types"))
+	}
+
+	if b.alias != nil && strings.TrimSpace(*b.alias) == "" {
+		errs = append(errs, errors.New("account alias cannot be empty"))
+	}
+
+	errs = append(errs, validateRouteAccountTypes(b.accountTypes)...)
+	errs = append(errs, validateRouteMetadata(b.metadata)...)
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid operation route %q: %w", b.title, errors.Join(errs...))
+	}
+
+	input := NewCreateOperationRouteInput(b.title, b.description, b.operationType)
+
+	switch {
+	case b.alias != nil:
+		input.WithAccountAlias(*b.alias)
+	case len(b.accountTypes) > 0:
+		input.WithAccountTypes(append([]string(nil), b.accountTypes...))
+	}
+
+	if b.metadata != nil {
+		input.WithMetadata(cloneRouteMetadata(b.metadata))
+	}
+
+	return input, nil
+}
+
+// setOperationType records the operation type, rejecting a conflicting one.
+func (b *OperationRouteBuilder) setOperationType(operationType string) *OperationRouteBuilder {
+	if b.operationType != "" && b.operationType != operationType {
+		b.errs = append(b.errs, fmt.Errorf("operation type already set to %s", b.operationType))
+		return b
+	}
+
+	b.operationType = operationType
+
+	return b
+}
+
+// TransactionRouteBuilder builds a CreateTransactionRouteInput from the
+// operation routes it combines, checking that the route has both a source and a
+// destination side and that the same account is not on both sides.
+//
+// Example:
+//
+//	input, err := models.NewTransactionRouteBuilder("Payment Flow").
+//	    Description("Customer pays merchant with platform fee").
+//	    OperationRoute(customerSource).
+//	    OperationRoute(merchantDestination).
+//	    Destination(platformFeeRouteID).
+//	    Build()
+type TransactionRouteBuilder struct {
+	title       string
+	description string
+	routes      []routeRef
+	metadata    map[string]any
+	errs        []error
+}
+
+// routeRef is an operation route referenced by a transaction route.
+type routeRef struct {
+	id            string
+	operationType string
+	account       *AccountRule
+}
+
+// NewTransactionRouteBuilder starts a transaction route with the given title.
+func NewTransactionRouteBuilder(title string) *TransactionRouteBuilder {
+	return &TransactionRouteBuilder{title: title}
+}
+
+// Description sets the description of the route.
+func (b *TransactionRouteBuilder) Description(description string) *TransactionRouteBuilder {
+	b.description = description
+	return b
+}
+
+// Source adds a source operation route by ID.
+func (b *TransactionRouteBuilder) Source(operationRouteID string) *TransactionRouteBuilder {
+	b.routes = append(b.routes, routeRef{id: operationRouteID, operationType: string(OperationRouteInputTypeSource)})
+	return b
+}
+
+// Destination adds a destination operation route by ID.
+func (b *TransactionRouteBuilder) Destination(operationRouteID string) *TransactionRouteBuilder {
+	b.routes = append(b.routes, routeRef{id: operationRouteID, operationType: string(OperationRouteInputTypeDestination)})
+	return b
+}
+
+// OperationRoute adds a created operation route, taking its side and account
+// rule from the route itself.
+func (b *TransactionRouteBuilder) OperationRoute(route *OperationRoute) *TransactionRouteBuilder {
+	if route == nil {
+		b.errs = append(b.errs, errors.New("operation route cannot be nil"))
+		return b
+	}
+
+	b.routes = append(b.routes, routeRef{id: route.ID.String(), operationType: route.OperationType, account: route.Account})
+
+	return b
+}
+
+// Metadata sets a metadata entry of the route.
+func (b *TransactionRouteBuilder) Metadata(key string, value any) *TransactionRouteBuilder {
+	if b.metadata == nil {
+		b.metadata = make(map[string]any)
+	}
+
+	b.metadata[key] = value
+
+	return b
+}
+
+// Build validates the route and returns the input to create it. All problems
+// found are returned together.
+func (b *TransactionRouteBuilder) Build() (*CreateTransactionRouteInput, error) {
+	errs := append([]error(nil), b.errs...)
+	errs = append(errs, validateRouteText(b.title, b.description)...)
+	errs = append(errs, validateRouteMetadata(b.metadata)...)
+
+	ids := make([]uuid.UUID, 0, len(b.routes))
+	seen := make(map[uuid.UUID]bool, len(b.routes))
+	sides := map[string]int{}
+	aliases := map[string]map[string]bool{}
+
+	for _, r := range b.routes {
+		id, err := uuid.Parse(r.id)
+		if err != nil || id == uuid.Nil {
+			errs = append(errs, fmt.Errorf("operation route ID %q is not a valid UUID", r.id))
+			continue
+		}
+
+		if seen[id] {
+			errs = append(errs, fmt.Errorf("operation route %s is referenced more than once", id))
+			continue
+		}
+
+		seen[id] = true
+
+		switch r.operationType {
+		case string(OperationRouteInputTypeSource), string(OperationRouteInputTypeDestination):
+			sides[r.operationType]++
+		default:
+			errs = append(errs, fmt.Errorf("operation route %s has unsupported operation type %q", id, r.operationType))
+		}
+
+		if alias, ok := routeAlias(r.account); ok {
+			if aliases[alias] == nil {
+				aliases[alias] = map[string]bool{}
+			}
+
+			aliases[alias][r.operationType] = true
+		}
+
+		ids = append(ids, id)
+	}
+
+	if sides[string(OperationRouteInputTypeSource)] == 0 {
+		errs = append(errs, errors.New("at least one source operation route is required"))
+	}
+
+	if sides[string(OperationRouteInputTypeDestination)] == 0 {
+		errs = append(errs, errors.New("at least one destination operation route is required"))
+	}
+
+	for _, alias := range sortedKeys(aliases) {
+		if aliases[alias][string(OperationRouteInputTypeSource)] && aliases[alias][string(OperationRouteInputTypeDestination)] {
+			errs = append(errs, fmt.Errorf("account %q is both a source and a destination", alias))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid transaction route %q: %w", b.title, errors.Join(errs...))
+	}
+
+	input := &CreateTransactionRouteInput{}
+	input.Title = b.title
+	input.Description = b.description
+	input.OperationRoutes = ids
+
+	if b.metadata != nil {
+		input.WithMetadata(cloneRouteMetadata(b.metadata))
+	}
+
+	return input, nil
+}
+
+// validateRouteText checks the title and description shared by both route kinds.
+func validateRouteText(title, description string) []error {
+	var errs []error
+
+	switch {
+	case strings.TrimSpace(title) == "":
+		errs = append(errs, errors.New("title is required"))
+	case len(title) > maxRouteTitleLength:
+		errs = append(errs, fmt.Errorf("title must be at most %d characters", maxRouteTitleLength))
+	}
+
+	switch {
+	case strings.TrimSpace(description) == "":
+		errs = append(errs, errors.New("description is required"))
+	case len(description) > maxRouteDescriptionLength:
+		errs = append(errs, fmt.Errorf("description must be at most %d characters", maxRouteDescriptionLength))
+	}
+
+	return errs
+}
+
+// validateRouteAccountTypes rejects blank and repeated account types.
+func validateRouteAccountTypes(accountTypes []string) []error {
+	var errs []error
+
+	seen := make(map[string]bool, len(accountTypes))
+
+	for _, t := range accountTypes {
+		key := strings.ToLower(strings.TrimSpace(t))
+		if key == "" {
+			errs = append(errs, errors.New("account types cannot be empty"))
+			continue
+		}
+
+		if seen[key] {
+			errs = append(errs, fmt.Errorf("account type %q is listed more than once", t))
+		}
+
+		seen[key] = true
+	}
+
+	return errs
+}
+
+// validateRouteMetadata applies the API metadata limits: bounded keys and
+// values and no nested objects.
+func validateRouteMetadata(metadata map[string]any) []error {
+	var errs []error
+
+	for _, key := range sortedKeys(metadata) {
+		if key == "" || len(key) > maxRouteMetadataKeyLength {
+			errs = append(errs, fmt.Errorf("metadata key %q must have between 1 and %d characters", key, maxRouteMetadataKeyLength))
+		}
+
+		switch v := metadata[key].(type) {
+		case map[string]any, []any:
+			errs = append(errs, fmt.Errorf("metadata %q cannot be nested", key))
+		case string:
+			if len(v) > maxRouteMetadataValueLen {
+				errs = append(errs, fmt.Errorf("metadata %q must be at most %d characters", key, maxRouteMetadataValueLen))
+			}
+		}
+	}
+
+	return errs
+}
+
+// routeAlias returns the alias selected by an alias account rule.
+func routeAlias(rule *AccountRule) (string, bool) {
+	if rule == nil || rule.RuleType != AccountRuleTypeAlias {
+		return "", false
+	}
+
+	alias, ok := rule.ValidIf.(string)
+	if !ok || alias == "" {
+		return "", false
+	}
+
+	return strings.TrimPrefix(alias, "@"), true
+}
+
+// cloneRouteMetadata copies metadata so built inputs do not share the builder map.
+func cloneRouteMetadata(metadata map[string]any) map[string]any {
+	out := make(map[string]any, len(metadata))
+	for k, v := range metadata {
+		out[k] = v
+	}
+
+	return out
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationRouteBuilder_Build(t *testing.T) {
+	input, err := NewOperationRouteBuilder("Source: Customer").
+		Description("Checking accounts paying merchants").
+		Source().
+		AccountTypes("checking").
+		AccountTypes("savings").
+		Metadata("role", "customer").
+		Build()
+	require.NoError(t, err)
+	require.NoError(t, input.Validate())
+
+	assert.Equal(t, "source", input.OperationType)
+	require.NotNil(t, input.Account)
+	assert.Equal(t, AccountRuleTypeAccountType, input.Account.RuleType)
+	assert.Equal(t, []string{"checking", "savings"}, input.Account.ValidIf)
+	assert.Equal(t, "customer", input.Metadata["role"])
+
+	alias, err := NewOperationRouteBuilder("Destination: Fees").
+		Description("Platform fee account").
+		Destination().
+		AccountAlias("@platform_fee").
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, AccountRuleTypeAlias, alias.Account.RuleType)
+	assert.Equal(t, "@platform_fee", alias.Account.ValidIf)
+}
+
+func TestOperationRouteBuilder_Inconsistencies(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *OperationRouteBuilder
+		want    []string
+	}{
+		{
+			name:    "missing fields",
+			builder: NewOperationRouteBuilder(""),
+			want:    []string{"title is required", "description is required", "operation type is required"},
+		},
+		{
+			name:    "conflicting sides",
+			builder: NewOperationRouteBuilder("t").Description("d").Source().Destination(),
+			want:    []string{"operation type already set to source"},
+		},
+		{
+			name:    "alias and account types",
+			builder: NewOperationRouteBuilder("t").Description("d").Source().AccountAlias("@a").AccountTypes("checking"),
+			want:    []string{"both an alias and account types"},
+		},
+		{
+			name:    "two aliases",
+			builder: NewOperationRouteBuilder("t").Description("d").Source().AccountAlias("@a").AccountAlias("@b"),
+			want:    []string{`account alias already set to "@a"`},
+		},
+		{
+			name:    "duplicate and blank account types",
+			builder: NewOperationRouteBuilder("t").Description("d").Source().AccountTypes("checking", "CHECKING", " "),
+			want:    []string{`"CHECKING" is listed more than once`, "account types cannot be empty"},
+		},
+		{
+			name:    "nested metadata",
+			builder: NewOperationRouteBuilder("t").Description("d").Source().Metadata("nested", map[string]any{"a": 1}),
+			want:    []string{`metadata "nested" cannot be nested`},
+		},
+		{
+			name:    "long description",
+			builder: NewOperationRouteBuilder("t").Description(strings.Repeat("d", 251)).Source(),
+			want:    []string{"description must be at most 250 characters"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := tt.builder.Build()
+			require.Error(t, err)
+			assert.Nil(t, input)
+
+			for _, want := range tt.want {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestTransactionRouteBuilder_Build(t *testing.T) {
+	source := &OperationRoute{ID: uuid.New(), OperationType: "source", Account: &AccountRule{RuleType: AccountRuleTypeAccountType, ValidIf: []string{"checking"}}}
+	destination := &OperationRoute{ID: uuid.New(), OperationType: "destination", Account: &AccountRule{RuleType: AccountRuleTypeAlias, ValidIf: "@merchant"}}
+	feeID := uuid.New()
+
+	input, err := NewTransactionRouteBuilder("Payment Flow").
+		Description("Customer pays merchant").
+		OperationRoute(source).
+		OperationRoute(destination).
+		Destination(feeID.String()).
+		Metadata("pattern", "payment").
+		Build()
+	require.NoError(t, err)
+	require.NoError(t, input.Validate())
+
+	assert.Equal(t, []uuid.UUID{source.ID, destination.ID, feeID}, input.OperationRoutes)
+	assert.Equal(t, "payment", input.Metadata["pattern"])
+}
+
+func TestTransactionRouteBuilder_Inconsistencies(t *testing.T) {
+	id := uuid.New().String()
+
+	tests := []struct {
+		name    string
+		builder *TransactionRouteBuilder
+		want    []string
+	}{
+		{
+			name:    "no routes",
+			builder: NewTransactionRouteBuilder("t").Description("d"),
+			want:    []string{"at least one source", "at least one destination"},
+		},
+		{
+			name:    "invalid and duplicate IDs",
+			builder: NewTransactionRouteBuilder("t").Description("d").Source("not-a-uuid").Source(id).Destination(id),
+			want:    []string{`"not-a-uuid" is not a valid UUID`, "referenced more than once", "at least one destination"},
+		},
+		{
+			name: "same alias on both sides",
+			builder: NewTransactionRouteBuilder("t").Description("d").
+				OperationRoute(&OperationRoute{ID: uuid.New(), OperationType: "source", Account: &AccountRule{RuleType: AccountRuleTypeAlias, ValidIf: "@treasury"}}).
+				OperationRoute(&OperationRoute{ID: uuid.New(), OperationType: "destination", Account: &AccountRule{RuleType: AccountRuleTypeAlias, ValidIf: "treasury"}}),
+			want: []string{`account "treasury" is both a source and a destination`},
+		},
+		{
+			name: "unknown operation type",
+			builder: NewTransactionRouteBuilder("t").Description("d").Source(id).
+				OperationRoute(&OperationRoute{ID: uuid.New(), OperationType: "bidirectional"}),
+			want: []string{`unsupported operation type "bidirectional"`},
+		},
+		{
+			name:    "nil route",
+			builder: NewTransactionRouteBuilder("t").Description("d").OperationRoute(nil),
+			want:    []string{"operation route cannot be nil"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := tt.builder.Build()
+			require.Error(t, err)
+			assert.Nil(t, input)
+
+			for _, want := range tt.want {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"context"
+	"strings"
+)
+
+// scope context helpers
+type contextKeyScope struct{}
+
+// Scope is the organization and ledger entity calls apply to, attached to a
+// context with WithScope.
+type Scope struct {
+	OrganizationID OrganizationID
+	LedgerID       LedgerID
+}
+
+// WithScope attaches an organization and a ledger to ctx, so that the scoped
+// services of the Entity (entities.Entity.Scoped) can be called without them:
+//
+//	ctx = models.WithScope(ctx, orgID, ledgerID)
+//	accounts, err := c.Entity.Scoped(ctx).Accounts.List(ctx, nil)
+//
+// ledgerID may be empty for a scope used only with organization-level
+// services, such as Ledgers.
+func WithScope(ctx context.Context, orgID, ledgerID string) context.Context {
+	return context.WithValue(ctx, contextKeyScope{}, Scope{
+		OrganizationID: OrganizationID(strings.TrimSpace(orgID)),
+		LedgerID:       LedgerID(strings.TrimSpace(ledgerID)),
+	})
+}
+
+// ScopeFromContext returns the scope attached to ctx by WithScope, and
+// whether there is one.
+func ScopeFromContext(ctx context.Context) (Scope, bool) {
+	scope, ok := ctx.Value(contextKeyScope{}).(Scope)
+
+	return scope, ok
+}
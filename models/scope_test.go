@@ -0,0 +1,22 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithScope(t *testing.T) {
+	_, ok := ScopeFromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := WithScope(context.Background(), " org-1 ", "ledger-1")
+
+	scope, ok := ScopeFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, Scope{OrganizationID: "org-1", LedgerID: "ledger-1"}, scope)
+
+	scope, _ = ScopeFromContext(WithScope(ctx, "org-2", ""))
+	assert.Equal(t, Scope{OrganizationID: "org-2"}, scope, "an inner scope replaces the outer one")
+}
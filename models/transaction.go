@@ -4,10 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation/core"
+	"github.com/shopspring/decimal"
 )
 
 // Transaction represents a transaction in the Midaz Ledger.
@@ -994,6 +996,35 @@ type AmountInput struct {
 	Value string `json:"value"`
 }
 
+// FormatDecimal formats a decimal amount as the decimal string the API
+// expects: no exponent and no trailing zeros, e.g. "1500000.5". Unlike
+// fixed-point integers scaled by powers of ten, it is exact whatever the size
+// and scale of the amount.
+func FormatDecimal(value decimal.Decimal) string {
+	return value.String()
+}
+
+// NewAmountInput creates an AmountInput of a decimal value.
+func NewAmountInput(asset string, value decimal.Decimal) AmountInput {
+	return AmountInput{Asset: asset, Value: FormatDecimal(value)}
+}
+
+// Decimal parses the value of the amount.
+func (input AmountInput) Decimal() (decimal.Decimal, error) {
+	value, err := decimal.NewFromString(input.Value)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid amount value %q: %w", input.Value, err)
+	}
+
+	return value, nil
+}
+
+// NewFromToInput creates a FromToInput moving a decimal value of asset from
+// or to account.
+func NewFromToInput(account, asset string, value decimal.Decimal) FromToInput {
+	return FromToInput{Account: account, Amount: NewAmountInput(asset, value)}
+}
+
 // Validate checks that the CreateTransactionInput meets all validation requirements.
 // It returns an error if any of the validation checks fail.
 func (input *CreateTransactionInput) Validate() error {
@@ -1034,6 +1065,72 @@ func (input *CreateTransactionInput) Validate() error {
 	return nil
 }
 
+// GetIdempotencyKey returns the client-generated idempotency key.
+// This implements the validation.TransactionBatchItem interface.
+func (input *CreateTransactionInput) GetIdempotencyKey() string {
+	return input.IdempotencyKey
+}
+
+// GetTransferLegs returns the transaction total and its source and destination legs.
+// The Send structure is used when present, otherwise debit operations are reported as
+// sources and credit operations as destinations.
+// This implements the validation.TransactionBatchItem interface.
+func (input *CreateTransactionInput) GetTransferLegs() validation.TransferLegs {
+	if input.Send != nil {
+		legs := validation.TransferLegs{
+			Asset: input.Send.Asset,
+			Value: input.Send.Value,
+		}
+
+		if input.Send.Source != nil {
+			for _, from := range input.Send.Source.From {
+				legs.Sources = append(legs.Sources, from.transferLeg())
+			}
+		}
+
+		if input.Send.Distribute != nil {
+			for _, to := range input.Send.Distribute.To {
+				legs.Destinations = append(legs.Destinations, to.transferLeg())
+			}
+		}
+
+		return legs
+	}
+
+	legs := validation.TransferLegs{
+		Asset: input.AssetCode,
+		Value: input.Amount,
+	}
+
+	for _, op := range input.Operations {
+		account := op.AccountID
+		if account == "" && op.AccountAlias != nil {
+			account = *op.AccountAlias
+		}
+
+		leg := validation.TransferLeg{Account: account, Asset: op.AssetCode, Value: op.Amount}
+
+		switch OperationType(strings.ToUpper(op.Type)) {
+		case OperationTypeDebit:
+			legs.Sources = append(legs.Sources, leg)
+		case OperationTypeCredit:
+			legs.Destinations = append(legs.Destinations, leg)
+		}
+	}
+
+	return legs
+}
+
+// transferLeg converts a FromToInput to a validation.TransferLeg.
+func (input FromToInput) transferLeg() validation.TransferLeg {
+	account := input.Account
+	if account == "" {
+		account = input.AccountAlias
+	}
+
+	return validation.TransferLeg{Account: account, Asset: input.Amount.Asset, Value: input.Amount.Value}
+}
+
 // NewCreateTransactionInput creates a new CreateTransactionInput with required fields.
 // This constructor ensures that all mandatory fields are provided when creating a transaction input.
 func NewCreateTransactionInput(assetCode string, amount string) *CreateTransactionInput {
@@ -1043,6 +1140,32 @@ func NewCreateTransactionInput(assetCode string, amount string) *CreateTransacti
 	}
 }
 
+// NewCreateTransactionInputFromDecimal creates a new CreateTransactionInput of
+// a decimal amount, so amounts of high-scale assets never go through integer
+// arithmetic that could overflow.
+//
+// Example:
+//
+//	amount := decimal.RequireFromString("0.000000000000000001")
+//	input := models.NewCreateTransactionInputFromDecimal("ETH", amount).
+//	    WithSend(models.NewSendInput("ETH", amount,
+//	        []models.FromToInput{models.NewFromToInput("@treasury", "ETH", amount)},
+//	        []models.FromToInput{models.NewFromToInput("@customer", "ETH", amount)}))
+func NewCreateTransactionInputFromDecimal(assetCode string, amount decimal.Decimal) *CreateTransactionInput {
+	return NewCreateTransactionInput(assetCode, FormatDecimal(amount))
+}
+
+// NewSendInput creates a SendInput of a decimal value moved from the from
+// legs to the to legs.
+func NewSendInput(asset string, value decimal.Decimal, from, to []FromToInput) *SendInput {
+	return &SendInput{
+		Asset:      asset,
+		Value:      FormatDecimal(value),
+		Source:     &SourceInput{From: from},
+		Distribute: &DistributeInput{To: to},
+	}
+}
+
 // WithDescription sets the description.
 // This adds a human-readable description to the transaction.
 func (input *CreateTransactionInput) WithDescription(description string) *CreateTransactionInput {
@@ -0,0 +1,902 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Transaction DSL text format, as defined by the Transaction grammar of Midaz:
+//
+//	(transaction V1
+//	  (chart-of-accounts-group-name PAG_CONTAS_CODE_1)
+//	  (description "Payment of invoice 42")
+//	  (code PAYMENT)
+//	  (pending false)
+//	  (metadata
+//	    (invoice 42)
+//	  )
+//	  (send USD 100|0
+//	    (source
+//	      (from @customer :amount USD 100|0)
+//	    )
+//	    (distribute
+//	      (to @merchant :share 95)
+//	      (to @fees :remaining)
+//	    )
+//	  )
+//	)
+//
+// Values are written as "value|scale"; Midaz only reads the value, so the
+// serializer writes a scale of 0. Values, accounts and shares may also be
+// template variables such as $amount.
+var (
+	dslUUIDPattern     = regexp.MustCompile(`^[a-zA-Z0-9_\-/]+$`)
+	dslIntPattern      = regexp.MustCompile(`^[0-9]+$`)
+	dslVariablePattern = regexp.MustCompile(`^\$[a-zA-Z0-9_\-]*$`)
+	dslAccountPattern  = regexp.MustCompile(`^@[a-zA-Z0-9_\-/]*$`)
+	dslBoolPattern     = regexp.MustCompile(`^(true|false)$`)
+)
+
+// DSLSyntaxError reports invalid transaction DSL text.
+type DSLSyntaxError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error implements the error interface.
+func (e *DSLSyntaxError) Error() string {
+	return fmt.Sprintf("DSL syntax error at line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// ParseTransactionDSL parses the text of a transaction DSL file, such as one
+// kept under version control, into a TransactionDSLInput. Transaction
+// templates ("transaction-template") are parsed the same way, keeping their
+// variables as they are.
+//
+// Example:
+//
+//	content, err := os.ReadFile("transactions/payment.gold")
+//	if err != nil {
+//	    return err
+//	}
+//
+//	input, err := models.ParseTransactionDSL(string(content))
+//	if err != nil {
+//	    return err // *models.DSLSyntaxError with the line and column
+//	}
+//
+//	tx, err := client.Entity.Transactions.CreateTransactionWithDSL(ctx, orgID, ledgerID, input)
+func ParseTransactionDSL(text string) (*TransactionDSLInput, error) {
+	tokens, err := scanDSL(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &dslParser{tokens: tokens}
+
+	input, err := p.transaction()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != dslEOF {
+		return nil, p.errorf(tok, "unexpected %s after the transaction", tok)
+	}
+
+	return input, nil
+}
+
+// ToDSL serializes the input into transaction DSL text, the inverse of
+// ParseTransactionDSL. It fails when the input can't be expressed in the DSL,
+// e.g. a value with decimal places or a text containing a double quote.
+func (input *TransactionDSLInput) ToDSL() (string, error) {
+	if input == nil {
+		return "", fmt.Errorf("DSL input is nil")
+	}
+
+	w := &dslWriter{}
+	w.open("transaction V1")
+
+	if err := checkDSLToken("chart of accounts group name", input.ChartOfAccountsGroupName, dslUUIDPattern); err != nil {
+		return "", err
+	}
+
+	w.line("(chart-of-accounts-group-name " + input.ChartOfAccountsGroupName + ")")
+
+	if input.Description != "" {
+		desc, err := quoteDSL("description", input.Description)
+		if err != nil {
+			return "", err
+		}
+
+		w.line("(description " + desc + ")")
+	}
+
+	if input.Code != "" {
+		if err := checkDSLToken("code", input.Code, dslUUIDPattern); err != nil {
+			return "", err
+		}
+
+		w.line("(code " + input.Code + ")")
+	}
+
+	if input.Pending {
+		w.line("(pending true)")
+	}
+
+	if err := w.metadata(input.Metadata); err != nil {
+		return "", err
+	}
+
+	if input.Send == nil {
+		return "", fmt.Errorf("DSL send is required")
+	}
+
+	if err := w.send(input.Send); err != nil {
+		return "", err
+	}
+
+	w.close()
+
+	return w.b.String(), nil
+}
+
+// dslTokenKind is the kind of a lexical token of the DSL.
+type dslTokenKind int
+
+const (
+	dslEOF dslTokenKind = iota
+	dslOpen
+	dslClose
+	dslPipe
+	dslString
+	dslWord
+)
+
+// dslPunctuation maps the single-character tokens to their kind.
+var dslPunctuation = map[rune]dslTokenKind{'(': dslOpen, ')': dslClose, '|': dslPipe}
+
+// dslToken is a lexical token of the DSL.
+type dslToken struct {
+	kind   dslTokenKind
+	text   string
+	line   int
+	column int
+}
+
+func (t dslToken) String() string {
+	switch t.kind {
+	case dslEOF:
+		return "end of input"
+	case dslString:
+		return strconv.Quote(t.text)
+	default:
+		return "'" + t.text + "'"
+	}
+}
+
+// scanDSL splits DSL text into tokens.
+func scanDSL(text string) ([]dslToken, error) {
+	var tokens []dslToken
+
+	line, column := 1, 1
+	runes := []rune(text)
+
+	advance := func(r rune) {
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		tok := dslToken{line: line, column: column}
+
+		switch {
+		case r == ' ' || r == '\t' || r == '\r' || r == '\n':
+			advance(r)
+			i++
+
+			continue
+		case r == '(' || r == ')' || r == '|':
+			tok.kind, tok.text = dslPunctuation[r], string(r)
+
+			advance(r)
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+
+			if end == len(runes) {
+				return nil, &DSLSyntaxError{Line: tok.line, Column: tok.column, Message: "unterminated string"}
+			}
+
+			tok.kind, tok.text = dslString, string(runes[i+1:end])
+
+			for ; i <= end; i++ {
+				advance(runes[i])
+			}
+		default:
+			end := i
+			for end < len(runes) && !strings.ContainsRune(" \t\r\n()|\"", runes[end]) {
+				end++
+			}
+
+			tok.kind, tok.text = dslWord, string(runes[i:end])
+
+			for ; i < end; i++ {
+				advance(runes[i])
+			}
+		}
+
+		tokens = append(tokens, tok)
+	}
+
+	return append(tokens, dslToken{kind: dslEOF, line: line, column: column}), nil
+}
+
+// dslParser is a recursive descent parser of the Transaction grammar.
+type dslParser struct {
+	tokens []dslToken
+	pos    int
+}
+
+func (p *dslParser) peek() dslToken {
+	return p.tokens[p.pos]
+}
+
+func (p *dslParser) next() dslToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != dslEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *dslParser) errorf(tok dslToken, format string, args ...any) error {
+	return &DSLSyntaxError{Line: tok.line, Column: tok.column, Message: fmt.Sprintf(format, args...)}
+}
+
+// expect consumes a token of kind.
+func (p *dslParser) expect(kind dslTokenKind, what string) (dslToken, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, p.errorf(tok, "expected %s, found %s", what, tok)
+	}
+
+	return tok, nil
+}
+
+// word consumes a word matching pattern.
+func (p *dslParser) word(what string, patterns ...*regexp.Regexp) (string, error) {
+	tok, err := p.expect(dslWord, what)
+	if err != nil {
+		return "", err
+	}
+
+	for _, pattern := range patterns {
+		if pattern.MatchString(tok.text) {
+			return tok.text, nil
+		}
+	}
+
+	return "", p.errorf(tok, "expected %s, found %s", what, tok)
+}
+
+// keyword consumes the given word.
+func (p *dslParser) keyword(text string) error {
+	tok := p.next()
+	if tok.kind != dslWord || tok.text != text {
+		return p.errorf(tok, "expected '%s', found %s", text, tok)
+	}
+
+	return nil
+}
+
+// isWord reports whether the next token is the given word.
+func (p *dslParser) isWord(text string) bool {
+	tok := p.peek()
+	return tok.kind == dslWord && tok.text == text
+}
+
+// group consumes the opening of a group named name, if it comes next.
+func (p *dslParser) group(name string) bool {
+	if p.peek().kind != dslOpen || p.pos+1 >= len(p.tokens) {
+		return false
+	}
+
+	if tok := p.tokens[p.pos+1]; tok.kind != dslWord || tok.text != name {
+		return false
+	}
+
+	p.pos += 2
+
+	return true
+}
+
+// openGroup consumes the opening of the required group name.
+func (p *dslParser) openGroup(name string) error {
+	if !p.group(name) {
+		tok := p.peek()
+		if tok.kind == dslOpen {
+			tok = p.tokens[p.pos+1]
+		}
+
+		return p.errorf(tok, "expected (%s ...), found %s", name, tok)
+	}
+
+	return nil
+}
+
+func (p *dslParser) closeGroup() error {
+	_, err := p.expect(dslClose, "')'")
+	return err
+}
+
+// transaction parses the root of the DSL.
+func (p *dslParser) transaction() (*TransactionDSLInput, error) {
+	if _, err := p.expect(dslOpen, "'('"); err != nil {
+		return nil, err
+	}
+
+	if !p.isWord("transaction") && !p.isWord("transaction-template") {
+		tok := p.next()
+		return nil, p.errorf(tok, "expected 'transaction', found %s", tok)
+	}
+
+	p.next()
+
+	if err := p.keyword("V1"); err != nil {
+		return nil, err
+	}
+
+	input := &TransactionDSLInput{}
+
+	var err error
+
+	if err = p.openGroup("chart-of-accounts-group-name"); err != nil {
+		return nil, err
+	}
+
+	if input.ChartOfAccountsGroupName, err = p.word("chart of accounts group name", dslUUIDPattern); err != nil {
+		return nil, err
+	}
+
+	if err = p.closeGroup(); err != nil {
+		return nil, err
+	}
+
+	if input.Description, err = p.description(); err != nil {
+		return nil, err
+	}
+
+	if p.group("code") {
+		if input.Code, err = p.word("code", dslUUIDPattern); err != nil {
+			return nil, err
+		}
+
+		if err = p.closeGroup(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.group("pending") {
+		value, err := p.word("true or false", dslBoolPattern)
+		if err != nil {
+			return nil, err
+		}
+
+		input.Pending = value == "true"
+
+		if err = p.closeGroup(); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Metadata, err = p.metadata(); err != nil {
+		return nil, err
+	}
+
+	if input.Send, err = p.send(); err != nil {
+		return nil, err
+	}
+
+	if err = p.closeGroup(); err != nil {
+		return nil, err
+	}
+
+	return input, nil
+}
+
+// description parses an optional description group.
+func (p *dslParser) description() (string, error) {
+	if !p.group("description") {
+		return "", nil
+	}
+
+	tok, err := p.expect(dslString, "a quoted description")
+	if err != nil {
+		return "", err
+	}
+
+	return tok.text, p.closeGroup()
+}
+
+// metadata parses an optional metadata group.
+func (p *dslParser) metadata() (map[string]any, error) {
+	if !p.group("metadata") {
+		return nil, nil
+	}
+
+	metadata := make(map[string]any)
+
+	for p.peek().kind == dslOpen {
+		p.next()
+
+		key, err := p.word("metadata key", dslUUIDPattern)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := p.word("metadata value", dslUUIDPattern)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.closeGroup(); err != nil {
+			return nil, err
+		}
+
+		metadata[key] = value
+	}
+
+	if len(metadata) == 0 {
+		return nil, p.errorf(p.peek(), "metadata must have at least one (key value) pair")
+	}
+
+	return metadata, p.closeGroup()
+}
+
+// value parses "value|scale", returning the value.
+func (p *dslParser) value() (string, error) {
+	value, err := p.word("integer value or variable", dslIntPattern, dslVariablePattern)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := p.expect(dslPipe, "'|'"); err != nil {
+		return "", err
+	}
+
+	if _, err := p.word("integer scale or variable", dslIntPattern, dslVariablePattern); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// send parses the send group.
+func (p *dslParser) send() (*DSLSend, error) {
+	if err := p.openGroup("send"); err != nil {
+		return nil, err
+	}
+
+	send := &DSLSend{Source: &DSLSource{}, Distribute: &DSLDistribute{}}
+
+	var err error
+
+	if send.Asset, err = p.word("asset code", dslUUIDPattern); err != nil {
+		return nil, err
+	}
+
+	if send.Value, err = p.value(); err != nil {
+		return nil, err
+	}
+
+	if err = p.openGroup("source"); err != nil {
+		return nil, err
+	}
+
+	if send.Source.Remaining, send.Source.From, err = p.entries("from"); err != nil {
+		return nil, err
+	}
+
+	if err = p.openGroup("distribute"); err != nil {
+		return nil, err
+	}
+
+	if send.Distribute.Remaining, send.Distribute.To, err = p.entries("to"); err != nil {
+		return nil, err
+	}
+
+	return send, p.closeGroup()
+}
+
+// entries parses the body of a source or distribute group: an optional
+// :remaining flag followed by from or to entries.
+func (p *dslParser) entries(name string) (string, []DSLFromTo, error) {
+	var remaining string
+
+	if p.isWord(":remaining") {
+		p.next()
+
+		remaining = "remaining"
+	}
+
+	var entries []DSLFromTo
+
+	for p.group(name) {
+		entry, err := p.entry()
+		if err != nil {
+			return "", nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		tok := p.peek()
+		return "", nil, p.errorf(tok, "expected at least one (%s ...), found %s", name, tok)
+	}
+
+	return remaining, entries, p.closeGroup()
+}
+
+// entry parses a from or to group after its name.
+func (p *dslParser) entry() (DSLFromTo, error) {
+	var (
+		entry DSLFromTo
+		err   error
+	)
+
+	if entry.Account, err = p.word("account", dslVariablePattern, dslAccountPattern, dslUUIDPattern); err != nil {
+		return entry, err
+	}
+
+	tok := p.next()
+
+	switch {
+	case tok.kind == dslWord && tok.text == ":amount":
+		amount := &DSLAmount{}
+
+		if amount.Asset, err = p.word("asset code", dslUUIDPattern); err != nil {
+			return entry, err
+		}
+
+		if amount.Value, err = p.value(); err != nil {
+			return entry, err
+		}
+
+		entry.Amount = amount
+	case tok.kind == dslWord && tok.text == ":share":
+		share := &Share{}
+
+		if share.Percentage, err = p.integer("share percentage"); err != nil {
+			return entry, err
+		}
+
+		if p.isWord(":of") {
+			p.next()
+
+			if share.PercentageOfPercentage, err = p.integer("share percentage"); err != nil {
+				return entry, err
+			}
+		}
+
+		entry.Share = share
+	case tok.kind == dslWord && tok.text == ":remaining":
+		entry.Remaining = "remaining"
+	default:
+		return entry, p.errorf(tok, "expected :amount, :share or :remaining, found %s", tok)
+	}
+
+	if p.group("rate") {
+		if entry.Rate, err = p.rate(); err != nil {
+			return entry, err
+		}
+	}
+
+	if entry.Description, err = p.description(); err != nil {
+		return entry, err
+	}
+
+	if p.group("chart-of-accounts") {
+		if entry.ChartOfAccounts, err = p.word("chart of accounts", dslUUIDPattern); err != nil {
+			return entry, err
+		}
+
+		if err = p.closeGroup(); err != nil {
+			return entry, err
+		}
+	}
+
+	if entry.Metadata, err = p.metadata(); err != nil {
+		return entry, err
+	}
+
+	return entry, p.closeGroup()
+}
+
+// integer parses a share percentage; variables can't be represented in Share.
+func (p *dslParser) integer(what string) (int64, error) {
+	tok, err := p.expect(dslWord, what)
+	if err != nil {
+		return 0, err
+	}
+
+	if dslVariablePattern.MatchString(tok.text) {
+		return 0, p.errorf(tok, "variables are not supported in %s", what)
+	}
+
+	n, err := strconv.ParseInt(tok.text, 10, 64)
+	if err != nil || !dslIntPattern.MatchString(tok.text) {
+		return 0, p.errorf(tok, "expected integer %s, found %s", what, tok)
+	}
+
+	return n, nil
+}
+
+// rate parses a rate group after its name.
+func (p *dslParser) rate() (*Rate, error) {
+	rate := &Rate{}
+
+	var err error
+
+	if rate.ExternalID, err = p.word("rate external ID", dslUUIDPattern); err != nil {
+		return nil, err
+	}
+
+	if rate.From, err = p.word("rate source asset", dslUUIDPattern); err != nil {
+		return nil, err
+	}
+
+	if err = p.keyword("->"); err != nil {
+		return nil, err
+	}
+
+	if rate.To, err = p.word("rate target asset", dslUUIDPattern); err != nil {
+		return nil, err
+	}
+
+	if rate.Value, err = p.value(); err != nil {
+		return nil, err
+	}
+
+	return rate, p.closeGroup()
+}
+
+// dslWriter writes indented DSL text.
+type dslWriter struct {
+	b     strings.Builder
+	depth int
+}
+
+func (w *dslWriter) line(s string) {
+	w.b.WriteString(strings.Repeat("  ", w.depth))
+	w.b.WriteString(s)
+	w.b.WriteByte('\n')
+}
+
+func (w *dslWriter) open(s string) {
+	w.line("(" + s)
+	w.depth++
+}
+
+func (w *dslWriter) close() {
+	w.depth--
+	w.line(")")
+}
+
+func (w *dslWriter) metadata(metadata map[string]any) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	w.open("metadata")
+
+	for _, k := range keys {
+		value := fmt.Sprint(metadata[k])
+
+		if err := checkDSLToken("metadata key", k, dslUUIDPattern); err != nil {
+			return err
+		}
+
+		if err := checkDSLToken("metadata value of "+k, value, dslUUIDPattern); err != nil {
+			return err
+		}
+
+		w.line("(" + k + " " + value + ")")
+	}
+
+	w.close()
+
+	return nil
+}
+
+func (w *dslWriter) send(send *DSLSend) error {
+	if err := checkDSLToken("send asset", send.Asset, dslUUIDPattern); err != nil {
+		return err
+	}
+
+	value, err := dslValue("send value", send.Value)
+	if err != nil {
+		return err
+	}
+
+	w.open("send " + send.Asset + " " + value)
+
+	if send.Source == nil || len(send.Source.From) == 0 {
+		return fmt.Errorf("DSL source must have at least one from entry")
+	}
+
+	if send.Distribute == nil || len(send.Distribute.To) == 0 {
+		return fmt.Errorf("DSL distribute must have at least one to entry")
+	}
+
+	if err := w.entries("source", "from", send.Source.Remaining, send.Source.From); err != nil {
+		return err
+	}
+
+	if err := w.entries("distribute", "to", send.Distribute.Remaining, send.Distribute.To); err != nil {
+		return err
+	}
+
+	w.close()
+
+	return nil
+}
+
+func (w *dslWriter) entries(group, name, remaining string, entries []DSLFromTo) error {
+	if remaining != "" {
+		w.open(group + " :remaining")
+	} else {
+		w.open(group)
+	}
+
+	for i, entry := range entries {
+		if err := w.entry(fmt.Sprintf("%s[%d]", name, i), name, entry); err != nil {
+			return err
+		}
+	}
+
+	w.close()
+
+	return nil
+}
+
+func (w *dslWriter) entry(location, name string, entry DSLFromTo) error {
+	if err := checkDSLToken(location+" account", entry.Account, dslVariablePattern, dslAccountPattern, dslUUIDPattern); err != nil {
+		return err
+	}
+
+	head := "(" + name + " " + entry.Account
+
+	switch {
+	case entry.Amount != nil:
+		if err := checkDSLToken(location+" amount asset", entry.Amount.Asset, dslUUIDPattern); err != nil {
+			return err
+		}
+
+		value, err := dslValue(location+" amount", entry.Amount.Value)
+		if err != nil {
+			return err
+		}
+
+		head += " :amount " + entry.Amount.Asset + " " + value
+	case entry.Share != nil:
+		head += " :share " + strconv.FormatInt(entry.Share.Percentage, 10)
+		if entry.Share.PercentageOfPercentage != 0 {
+			head += " :of " + strconv.FormatInt(entry.Share.PercentageOfPercentage, 10)
+		}
+	case entry.Remaining != "":
+		head += " :remaining"
+	default:
+		return fmt.Errorf("DSL %s needs an amount, a share or remaining", location)
+	}
+
+	var body []string
+
+	if entry.Rate != nil {
+		for _, part := range []string{entry.Rate.ExternalID, entry.Rate.From, entry.Rate.To} {
+			if err := checkDSLToken(location+" rate", part, dslUUIDPattern); err != nil {
+				return err
+			}
+		}
+
+		value, err := dslValue(location+" rate value", entry.Rate.Value)
+		if err != nil {
+			return err
+		}
+
+		body = append(body, fmt.Sprintf("(rate %s %s -> %s %s)", entry.Rate.ExternalID, entry.Rate.From, entry.Rate.To, value))
+	}
+
+	if entry.Description != "" {
+		desc, err := quoteDSL(location+" description", entry.Description)
+		if err != nil {
+			return err
+		}
+
+		body = append(body, "(description "+desc+")")
+	}
+
+	if entry.ChartOfAccounts != "" {
+		if err := checkDSLToken(location+" chart of accounts", entry.ChartOfAccounts, dslUUIDPattern); err != nil {
+			return err
+		}
+
+		body = append(body, "(chart-of-accounts "+entry.ChartOfAccounts+")")
+	}
+
+	if len(body) == 0 && len(entry.Metadata) == 0 {
+		w.line(head + ")")
+		return nil
+	}
+
+	w.open(head[1:])
+
+	for _, line := range body {
+		w.line(line)
+	}
+
+	if err := w.metadata(entry.Metadata); err != nil {
+		return err
+	}
+
+	w.close()
+
+	return nil
+}
+
+// checkDSLToken checks that value is a valid DSL token for field.
+func checkDSLToken(field, value string, patterns ...*regexp.Regexp) error {
+	for _, pattern := range patterns {
+		if pattern.MatchString(value) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("DSL %s %q contains characters the DSL doesn't allow", field, value)
+}
+
+// quoteDSL quotes a text; the DSL has no escape sequences.
+func quoteDSL(field, value string) (string, error) {
+	if strings.Contains(value, `"`) {
+		return "", fmt.Errorf("DSL %s can't contain a double quote", field)
+	}
+
+	return `"` + value + `"`, nil
+}
+
+// dslValue formats an integer value or a variable as "value|0".
+func dslValue(field, value string) (string, error) {
+	if dslVariablePattern.MatchString(value) {
+		return value + "|0", nil
+	}
+
+	d, err := decimal.NewFromString(value)
+	if err != nil || !d.IsInteger() || d.IsNegative() {
+		return "", fmt.Errorf("DSL %s %q must be a non-negative integer or a variable", field, value)
+	}
+
+	return d.String() + "|0", nil
+}
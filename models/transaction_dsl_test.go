@@ -0,0 +1,152 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleDSL = `(transaction V1
+  (chart-of-accounts-group-name PAG_CONTAS_CODE_1)
+  (description "Payment of invoice 42")
+  (code PAYMENT)
+  (pending true)
+  (metadata
+    (invoice 42)
+    (channel web)
+  )
+  (send USD 100|2
+    (source
+      (from @customer :amount USD 100|2
+        (description "customer debit")
+        (chart-of-accounts 1000)
+      )
+    )
+    (distribute :remaining
+      (to @merchant :share 95 :of 100)
+      (to @fx :amount USD 5|0
+        (rate rate-1 USD -> BRL 5|0)
+        (metadata
+          (reason fx)
+        )
+      )
+      (to $fees :remaining)
+    )
+  )
+)`
+
+func TestParseTransactionDSL(t *testing.T) {
+	input, err := ParseTransactionDSL(sampleDSL)
+	require.NoError(t, err)
+
+	assert.Equal(t, "PAG_CONTAS_CODE_1", input.ChartOfAccountsGroupName)
+	assert.Equal(t, "Payment of invoice 42", input.Description)
+	assert.Equal(t, "PAYMENT", input.Code)
+	assert.True(t, input.Pending)
+	assert.Equal(t, map[string]any{"invoice": "42", "channel": "web"}, input.Metadata)
+
+	send := input.Send
+	require.NotNil(t, send)
+	assert.Equal(t, "USD", send.Asset)
+	assert.Equal(t, "100", send.Value)
+
+	require.Len(t, send.Source.From, 1)
+	from := send.Source.From[0]
+	assert.Equal(t, "@customer", from.Account)
+	assert.Equal(t, &DSLAmount{Asset: "USD", Value: "100"}, from.Amount)
+	assert.Equal(t, "customer debit", from.Description)
+	assert.Equal(t, "1000", from.ChartOfAccounts)
+
+	assert.Equal(t, "remaining", send.Distribute.Remaining)
+	require.Len(t, send.Distribute.To, 3)
+	assert.Equal(t, &Share{Percentage: 95, PercentageOfPercentage: 100}, send.Distribute.To[0].Share)
+	assert.Equal(t, &Rate{ExternalID: "rate-1", From: "USD", To: "BRL", Value: "5"}, send.Distribute.To[1].Rate)
+	assert.Equal(t, map[string]any{"reason": "fx"}, send.Distribute.To[1].Metadata)
+	assert.Equal(t, "$fees", send.Distribute.To[2].Account)
+	assert.Equal(t, "remaining", send.Distribute.To[2].Remaining)
+}
+
+func TestTransactionDSLRoundTrip(t *testing.T) {
+	input, err := ParseTransactionDSL(sampleDSL)
+	require.NoError(t, err)
+
+	text, err := input.ToDSL()
+	require.NoError(t, err)
+
+	again, err := ParseTransactionDSL(text)
+	require.NoError(t, err)
+	assert.Equal(t, input, again)
+
+	// serializing is stable, so files diff cleanly
+	text2, err := again.ToDSL()
+	require.NoError(t, err)
+	assert.Equal(t, text, text2)
+}
+
+func TestParseTransactionDSLErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		line   int
+		column int
+	}{
+		{"empty", "", 1, 1},
+		{"missing chart of accounts", "(transaction V1\n  (send USD 1|0))", 2, 4},
+		{"decimal value", "(transaction V1 (chart-of-accounts-group-name G) (send USD 1.5|0", 1, 60},
+		{"unterminated string", "(transaction V1 (chart-of-accounts-group-name G)\n(description \"oops", 2, 14},
+		{"missing from", "(transaction V1 (chart-of-accounts-group-name G) (send USD 1|0 (source) (distribute (to @b :remaining))))", 1, 71},
+		{"trailing input", "(transaction V1 (chart-of-accounts-group-name G) (send USD 1|0 (source (from @a :amount USD 1|0)) (distribute (to @b :remaining)))) x", 1, 133},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTransactionDSL(tt.text)
+			require.Error(t, err)
+
+			var syntaxErr *DSLSyntaxError
+			require.True(t, errors.As(err, &syntaxErr), err.Error())
+			assert.Equal(t, tt.line, syntaxErr.Line, err.Error())
+			assert.Equal(t, tt.column, syntaxErr.Column, err.Error())
+		})
+	}
+}
+
+func TestTransactionDSLInputToDSLErrors(t *testing.T) {
+	valid := func() *TransactionDSLInput {
+		return &TransactionDSLInput{
+			ChartOfAccountsGroupName: "G",
+			Send: &DSLSend{
+				Asset:      "USD",
+				Value:      "100.00",
+				Source:     &DSLSource{From: []DSLFromTo{{Account: "@a", Amount: &DSLAmount{Asset: "USD", Value: "100"}}}},
+				Distribute: &DSLDistribute{To: []DSLFromTo{{Account: "@b", Remaining: "remaining"}}},
+			},
+		}
+	}
+
+	text, err := valid().ToDSL()
+	require.NoError(t, err)
+	assert.Contains(t, text, "(send USD 100|0")
+
+	tests := map[string]func(*TransactionDSLInput){
+		"decimal value":       func(in *TransactionDSLInput) { in.Send.Value = "10.5" },
+		"quote":               func(in *TransactionDSLInput) { in.Description = `say "hi"` },
+		"missing group":       func(in *TransactionDSLInput) { in.ChartOfAccountsGroupName = "" },
+		"invalid account":     func(in *TransactionDSLInput) { in.Send.Source.From[0].Account = "bad account" },
+		"no send type":        func(in *TransactionDSLInput) { in.Send.Distribute.To[0].Remaining = "" },
+		"metadata with space": func(in *TransactionDSLInput) { in.Metadata = map[string]any{"k": "two words"} },
+		"no destination":      func(in *TransactionDSLInput) { in.Send.Distribute = nil },
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			in := valid()
+			mutate(in)
+
+			_, err := in.ToDSL()
+			assert.Error(t, err)
+		})
+	}
+}
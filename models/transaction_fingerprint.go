@@ -0,0 +1,213 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// fingerprintVersion prefixes the hashed canonical form, so that a change of
+// the canonical form changes every fingerprint rather than some of them.
+const fingerprintVersion = "midaz-transaction-v1\x00"
+
+// CanonicalizeTransaction returns a copy of input in canonical form, so that
+// two inputs describing the same transaction are equal however they were
+// built:
+//   - identifiers and descriptions are trimmed and asset codes upper-cased
+//   - amounts are formatted by FormatDecimal, so "10.50" becomes "10.5"
+//   - the source and destination legs, and the operations, are sorted
+//   - metadata is deep-copied, empty metadata dropped, and the metadata
+//     values JSON can't encode, such as NaN or channels, dropped
+//   - the idempotency key and template, which don't change what the
+//     transaction does, are cleared
+//
+// Amounts that aren't decimals are kept as they are, for validation to
+// report. The input isn't changed. Returns nil for nil.
+//
+// Example:
+//
+//	if reflect.DeepEqual(models.CanonicalizeTransaction(a), models.CanonicalizeTransaction(b)) {
+//	    // a and b create the same transaction
+//	}
+func CanonicalizeTransaction(input *CreateTransactionInput) *CreateTransactionInput {
+	if input == nil {
+		return nil
+	}
+
+	c := *input
+	c.Template = ""
+	c.IdempotencyKey = ""
+	c.Amount = canonicalAmount(c.Amount)
+	c.AssetCode = canonicalAsset(c.AssetCode)
+	c.ChartOfAccountsGroupName = strings.TrimSpace(c.ChartOfAccountsGroupName)
+	c.Description = strings.TrimSpace(c.Description)
+	c.Route = strings.TrimSpace(c.Route)
+	c.ExternalID = strings.TrimSpace(c.ExternalID)
+	c.Metadata = canonicalMetadata(c.Metadata)
+
+	if c.Operations != nil {
+		c.Operations = make([]CreateOperationInput, len(input.Operations))
+
+		for i, op := range input.Operations {
+			op.Type = strings.ToUpper(strings.TrimSpace(op.Type))
+			op.AccountID = strings.TrimSpace(op.AccountID)
+			op.Amount = canonicalAmount(op.Amount)
+			op.AssetCode = canonicalAsset(op.AssetCode)
+			op.Route = strings.TrimSpace(op.Route)
+
+			if op.AccountAlias != nil {
+				alias := strings.TrimSpace(*op.AccountAlias)
+				op.AccountAlias = &alias
+			}
+
+			c.Operations[i] = op
+		}
+
+		sortByJSON(c.Operations)
+	}
+
+	if input.Send != nil {
+		send := SendInput{
+			Asset: canonicalAsset(input.Send.Asset),
+			Value: canonicalAmount(input.Send.Value),
+		}
+
+		if input.Send.Source != nil {
+			send.Source = &SourceInput{From: canonicalLegs(input.Send.Source.From)}
+		}
+
+		if input.Send.Distribute != nil {
+			send.Distribute = &DistributeInput{To: canonicalLegs(input.Send.Distribute.To)}
+		}
+
+		c.Send = &send
+	}
+
+	return &c
+}
+
+// Fingerprint returns a stable hash of the transaction input: the hex SHA-256
+// of the JSON encoding of its canonical form (see CanonicalizeTransaction).
+// Inputs describing the same transaction have the same fingerprint whatever
+// the ordering of their maps and legs, and whatever their idempotency keys,
+// which makes it suitable for deduplication, deriving idempotency keys,
+// caching, and telling whether two inputs differ. Metadata values JSON can't
+// encode don't take part in the fingerprint. Returns "" for nil.
+func Fingerprint(input *CreateTransactionInput) string {
+	if input == nil {
+		return ""
+	}
+
+	body, err := json.Marshal(CanonicalizeTransaction(input))
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(append([]byte(fingerprintVersion), body...))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalLegs returns a canonical copy of the source or destination legs of
+// a transaction.
+func canonicalLegs(legs []FromToInput) []FromToInput {
+	if legs == nil {
+		return nil
+	}
+
+	out := make([]FromToInput, len(legs))
+
+	for i, leg := range legs {
+		leg.Account = strings.TrimSpace(leg.Account)
+		leg.Amount = AmountInput{Asset: canonicalAsset(leg.Amount.Asset), Value: canonicalAmount(leg.Amount.Value)}
+		leg.Route = strings.TrimSpace(leg.Route)
+		leg.Description = strings.TrimSpace(leg.Description)
+		leg.ChartOfAccounts = strings.TrimSpace(leg.ChartOfAccounts)
+		leg.AccountAlias = strings.TrimSpace(leg.AccountAlias)
+		leg.Metadata = canonicalMetadata(leg.Metadata)
+		out[i] = leg
+	}
+
+	sortByJSON(out)
+
+	return out
+}
+
+// sortByJSON sorts items by their JSON encoding, which covers all their fields.
+func sortByJSON[T any](items []T) {
+	type keyed struct {
+		key  string
+		item T
+	}
+
+	sorted := make([]keyed, len(items))
+
+	for i, item := range items {
+		body, _ := json.Marshal(item)
+		sorted[i] = keyed{key: string(body), item: item}
+	}
+
+	slices.SortStableFunc(sorted, func(a, b keyed) int { return strings.Compare(a.key, b.key) })
+
+	for i := range sorted {
+		items[i] = sorted[i].item
+	}
+}
+
+// canonicalAmount formats a decimal amount by FormatDecimal, and returns any
+// other value trimmed.
+func canonicalAmount(value string) string {
+	value = strings.TrimSpace(value)
+
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return value
+	}
+
+	return FormatDecimal(d)
+}
+
+// canonicalAsset returns an asset code trimmed and upper-cased.
+func canonicalAsset(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// canonicalMetadata returns a deep copy of metadata, nil when it is empty.
+// Values are copied through JSON, which also gives numbers a single type;
+// the values JSON can't encode are dropped, so that they can't make the
+// canonical form depend on pointers or differ between calls.
+func canonicalMetadata(metadata map[string]any) map[string]any {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(metadata)
+	if err != nil {
+		encodable := make(map[string]any, len(metadata))
+
+		for key, value := range metadata {
+			if _, err := json.Marshal(value); err == nil {
+				encodable[key] = value
+			}
+		}
+
+		if len(encodable) == 0 {
+			return nil
+		}
+
+		if body, err = json.Marshal(encodable); err != nil {
+			return nil
+		}
+	}
+
+	var out map[string]any
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil
+	}
+
+	return out
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fingerprintInput() *CreateTransactionInput {
+	return &CreateTransactionInput{
+		Description:    "rent",
+		IdempotencyKey: "key-1",
+		Metadata:       map[string]any{"a": 1, "b": map[string]any{"y": "2", "x": 1.0}},
+		Send: &SendInput{
+			Asset: "brl",
+			Value: "100.00",
+			Source: &SourceInput{From: []FromToInput{
+				{Account: "@bob", Amount: AmountInput{Asset: "BRL", Value: "40"}},
+				{Account: "@alice", Amount: AmountInput{Asset: "BRL", Value: "60.0"}},
+			}},
+			Distribute: &DistributeInput{To: []FromToInput{
+				{Account: " @landlord ", Amount: AmountInput{Asset: "BRL", Value: "100"}},
+			}},
+		},
+	}
+}
+
+func TestCanonicalizeTransaction(t *testing.T) {
+	input := fingerprintInput()
+
+	c := CanonicalizeTransaction(input)
+	require.NotNil(t, c)
+
+	assert.Empty(t, c.IdempotencyKey)
+	assert.Equal(t, "BRL", c.Send.Asset)
+	assert.Equal(t, "100", c.Send.Value)
+	assert.Equal(t, "@alice", c.Send.Source.From[0].Account)
+	assert.Equal(t, "60", c.Send.Source.From[0].Amount.Value)
+	assert.Equal(t, "@landlord", c.Send.Distribute.To[0].Account)
+
+	assert.Equal(t, "key-1", input.IdempotencyKey, "the input is unchanged")
+	assert.Equal(t, "@bob", input.Send.Source.From[0].Account)
+
+	c.Metadata["b"].(map[string]any)["x"] = 2
+	assert.InDelta(t, 1.0, input.Metadata["b"].(map[string]any)["x"], 0, "metadata is deep-copied")
+
+	assert.Nil(t, CanonicalizeTransaction(nil))
+}
+
+func TestFingerprint(t *testing.T) {
+	a := fingerprintInput()
+
+	b := fingerprintInput()
+	b.IdempotencyKey = "key-2"
+	b.Metadata = map[string]any{"b": map[string]any{"x": 1, "y": "2"}, "a": 1.0}
+	b.Send.Value = "100"
+	b.Send.Source.From[0], b.Send.Source.From[1] = b.Send.Source.From[1], b.Send.Source.From[0]
+
+	assert.Len(t, Fingerprint(a), 64)
+	assert.Equal(t, Fingerprint(a), Fingerprint(b))
+
+	b.Send.Distribute.To[0].Account = "@other"
+	assert.NotEqual(t, Fingerprint(a), Fingerprint(b))
+
+	c := fingerprintInput()
+	c.Description = "deposit"
+	assert.NotEqual(t, Fingerprint(a), Fingerprint(c))
+
+	assert.Empty(t, Fingerprint(nil))
+}
+
+func TestFingerprint_UnencodableMetadata(t *testing.T) {
+	a := fingerprintInput()
+	a.Metadata["score"] = math.NaN()
+	a.Metadata["events"] = make(chan int)
+
+	assert.Len(t, Fingerprint(a), 64)
+	assert.Equal(t, Fingerprint(a), Fingerprint(a), "the fingerprint is stable across calls")
+	assert.Equal(t, Fingerprint(fingerprintInput()), Fingerprint(a), "values JSON can't encode are dropped")
+
+	c := CanonicalizeTransaction(a)
+	assert.NotContains(t, c.Metadata, "score")
+	assert.Contains(t, c.Metadata, "b")
+
+	b := fingerprintInput()
+	b.Metadata = map[string]any{"score": math.Inf(1)}
+	assert.Nil(t, CanonicalizeTransaction(b).Metadata)
+}
@@ -59,6 +59,32 @@ func TestNewCreateTransactionInput(t *testing.T) {
 	}
 }
 
+func TestNewCreateTransactionInputFromDecimal(t *testing.T) {
+	amount := newDecimal("123456789012345678901234.000000000000000001")
+
+	input := NewCreateTransactionInputFromDecimal("ETH", amount).
+		WithSend(NewSendInput("ETH", amount,
+			[]FromToInput{NewFromToInput("@treasury", "ETH", amount)},
+			[]FromToInput{NewFromToInput("@customer", "ETH", amount)}))
+
+	require.NoError(t, input.Validate())
+	assert.Equal(t, "123456789012345678901234.000000000000000001", input.Amount)
+	assert.Equal(t, input.Amount, input.Send.Value)
+	assert.Equal(t, input.Amount, input.Send.Source.From[0].Amount.Value)
+	assert.Equal(t, "@customer", input.Send.Distribute.To[0].Account)
+
+	parsed, err := input.Send.Distribute.To[0].Amount.Decimal()
+	require.NoError(t, err)
+	assert.True(t, amount.Equal(parsed))
+
+	assert.Equal(t, "1500000.5", FormatDecimal(newDecimal("1.5000005e6")))
+	assert.Equal(t, "5000", FormatDecimal(decimal.New(5, 3)))
+	assert.Equal(t, "10.5", FormatDecimal(newDecimal("10.50")))
+
+	_, err = AmountInput{Asset: "USD", Value: "ten"}.Decimal()
+	require.Error(t, err)
+}
+
 func TestCreateTransactionInput_Validate(t *testing.T) {
 	validSend := &SendInput{
 		Asset: "USD",
@@ -2366,3 +2392,49 @@ func TestCreateTransactionInput_OperationsValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateTransactionInputGetTransferLegs(t *testing.T) {
+	t.Run("send structure", func(t *testing.T) {
+		input := &CreateTransactionInput{
+			IdempotencyKey: "key-1",
+			Send: &SendInput{
+				Asset: "USD",
+				Value: "100",
+				Source: &SourceInput{From: []FromToInput{
+					{Account: "@a", Amount: AmountInput{Asset: "USD", Value: "100"}},
+				}},
+				Distribute: &DistributeInput{To: []FromToInput{
+					{AccountAlias: "@b", Amount: AmountInput{Asset: "USD", Value: "100"}},
+				}},
+			},
+		}
+
+		legs := input.GetTransferLegs()
+		assert.Equal(t, "key-1", input.GetIdempotencyKey())
+		assert.Equal(t, "USD", legs.Asset)
+		assert.Equal(t, "100", legs.Value)
+		require.Len(t, legs.Sources, 1)
+		require.Len(t, legs.Destinations, 1)
+		assert.Equal(t, "@a", legs.Sources[0].Account)
+		assert.Equal(t, "@b", legs.Destinations[0].Account)
+	})
+
+	t.Run("operations", func(t *testing.T) {
+		alias := "@c"
+		input := &CreateTransactionInput{
+			AssetCode: "BRL",
+			Amount:    "10",
+			Operations: []CreateOperationInput{
+				{Type: "debit", AccountID: "acc-1", Amount: "10", AssetCode: "BRL"},
+				{Type: "CREDIT", AccountAlias: &alias, Amount: "10", AssetCode: "BRL"},
+			},
+		}
+
+		legs := input.GetTransferLegs()
+		assert.Equal(t, "BRL", legs.Asset)
+		require.Len(t, legs.Sources, 1)
+		require.Len(t, legs.Destinations, 1)
+		assert.Equal(t, "acc-1", legs.Sources[0].Account)
+		assert.Equal(t, "@c", legs.Destinations[0].Account)
+	})
+}
@@ -42,7 +42,15 @@ type TokenResponse struct {
 	ExpiresAt    string `json:"expiresAt,omitempty"`
 }
 
+// entityWithTokenRefresh is implemented by entities that can renew an expired
+// token and retry the rejected request.
+type entityWithTokenRefresh interface {
+	SetTokenRefresher(refresh func(ctx context.Context) (string, error))
+}
+
 // WithAccessManager returns an EntityOption that configures plugin-based authentication.
+// Entities implementing SetTokenRefresher also get a new token from the access
+// manager when a request fails with 401 Unauthorized.
 func WithAccessManager(accessMgr AccessManager) EntityOption {
 	return func(e any) error {
 		// Type assertion to access the required methods
@@ -76,6 +84,13 @@ func WithAccessManager(accessMgr AccessManager) EntityOption {
 		// Set the token on the entity
 		entity.SetAuthToken(token)
 
+		// Renew the token when it expires, if the entity supports it
+		if refreshable, ok := e.(entityWithTokenRefresh); ok {
+			refreshable.SetTokenRefresher(func(ctx context.Context) (string, error) {
+				return GetTokenFromAccessManager(ctx, accessMgr, entity.GetHTTPClient())
+			})
+		}
+
 		// Re-initialize services to update the token
 		entity.InitServices()
 
@@ -307,3 +307,39 @@ func TestGetTokenFromPluginAuth(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthorize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		assert.Equal(t, Application, body["sub"])
+
+		switch body["action"] {
+		case "get":
+			_ = json.NewEncoder(w).Encode(authorizeResponse{Authorized: true})
+		case "post":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	accessMgr := AccessManager{Enabled: true, Address: server.URL}
+	ctx := context.Background()
+
+	granted, err := Authorize(ctx, accessMgr, server.Client(), "token", Permission{Resource: "accounts", Action: "get"})
+	require.NoError(t, err)
+	assert.True(t, granted)
+
+	granted, err = Authorize(ctx, accessMgr, server.Client(), "token", Permission{Resource: "accounts", Action: "post"})
+	require.NoError(t, err)
+	assert.False(t, granted)
+
+	_, err = Authorize(ctx, accessMgr, server.Client(), "token", Permission{Resource: "accounts", Action: "delete"})
+	require.Error(t, err)
+
+	_, err = Authorize(ctx, AccessManager{}, server.Client(), "token", Permission{})
+	require.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/security"
+)
+
+// Application is the application name Midaz registers its permissions under
+// in the access manager.
+const Application = "midaz"
+
+// Permission is an action on a resource, as enforced by the access manager,
+// e.g. {Resource: "transactions", Action: "post"}.
+type Permission struct {
+	Resource string
+	Action   string
+}
+
+// String returns the permission as "resource:action".
+func (p Permission) String() string {
+	return p.Resource + ":" + p.Action
+}
+
+// authorizeResponse is the response of the access manager authorize endpoint.
+type authorizeResponse struct {
+	Authorized bool `json:"authorized"`
+}
+
+// Authorize asks the access manager whether token grants a permission on the
+// Midaz application. It reports the decision; an error means no decision
+// could be obtained.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - accessMgr: The plugin access manager configuration.
+//   - httpClient: The HTTP client to use for the request.
+//   - token: The access token to check, as sent in the Authorization header.
+//   - permission: The permission to check.
+//
+// Returns:
+//   - bool: Whether the permission is granted.
+//   - error: An error if the access manager could not be asked.
+func Authorize(ctx context.Context, accessMgr AccessManager, httpClient *http.Client, token string, permission Permission) (bool, error) {
+	if !accessMgr.Enabled {
+		return false, errors.New("plugin authentication is not enabled")
+	}
+
+	if accessMgr.Address == "" {
+		return false, errors.New("plugin auth address is required when plugin auth is enabled")
+	}
+
+	payloadBytes, err := json.Marshal(map[string]string{
+		"sub":      Application,
+		"resource": permission.Resource,
+		"action":   permission.Action,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal authorize payload: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v1/authorize", accessMgr.Address)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request to plugin auth service: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", token)
+
+	if err := security.ValidateOutboundRequest(req); err != nil {
+		return false, fmt.Errorf("invalid plugin auth request URL: %w", err)
+	}
+
+	resp, err := httpClient.Do(req) // #nosec G704 -- request URL validated via security.ValidateOutboundRequest
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to plugin auth service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed to read response from plugin auth service: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden:
+		return false, nil
+	default:
+		return false, fmt.Errorf("plugin auth service returned non-OK status: %d", resp.StatusCode)
+	}
+
+	var authResp authorizeResponse
+	if err := json.Unmarshal(body, &authResp); err != nil {
+		return false, fmt.Errorf("failed to parse response from plugin auth service: %w", err)
+	}
+
+	return authResp.Authorized, nil
+}
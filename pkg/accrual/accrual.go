@@ -0,0 +1,594 @@
+// Package accrual computes periodic interest accruals over account balances and
+// posts them as transactions.
+//
+// Accounts are grouped in cohorts, each with its own annual rate, accrual method
+// (simple or daily compound) and funding account. For a period, the engine lists
+// the accounts of a ledger, assigns each to the first cohort matching it, computes
+// the interest on its available balance and posts one transaction per account
+// from the cohort funding account.
+//
+// Every accrual carries an idempotency key derived from the cohort, the account
+// and the period, so running the same period twice never posts interest twice.
+//
+// Example:
+//
+//	savings := accrual.Cohort{
+//	    Name:          "savings",
+//	    AccountType:   "savings",
+//	    AssetCode:     "USD",
+//	    AnnualRate:    decimal.RequireFromString("0.045"),
+//	    Method:        accrual.MethodCompound,
+//	    SourceAccount: "@interest_expense",
+//	}
+//
+//	result, err := accrual.NewEngine(client.Entity).
+//	    WithCohorts(savings).
+//	    Run(ctx, orgID, ledgerID, accrual.Month(2025, time.January))
+package accrual
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/shopspring/decimal"
+)
+
+// Method is how interest accrues over the days of a period.
+type Method string
+
+const (
+	// MethodSimple accrues balance * rate * days / dayCount
+	MethodSimple Method = "simple"
+
+	// MethodCompound compounds daily: balance * ((1 + rate/dayCount)^days - 1)
+	MethodCompound Method = "compound"
+)
+
+const (
+	defaultDayCount    = 365
+	defaultScale       = 2
+	defaultBatchSize   = 100
+	defaultConcurrency = 10
+
+	// factorPrecision bounds the digits kept while compounding
+	factorPrecision = 30
+
+	periodDateLayout = "2006-01-02"
+)
+
+// Period is the half-open date range [Start, End) an accrual covers.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Month returns the period covering a calendar month.
+func Month(year int, month time.Month) Period {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return Period{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// Day returns the period covering a single day.
+func Day(date time.Time) Period {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return Period{Start: start, End: start.AddDate(0, 0, 1)}
+}
+
+// Days returns the number of whole days in the period.
+func (p Period) Days() int {
+	return int(p.End.Sub(p.Start).Hours() / 24)
+}
+
+// Key identifies the period in idempotency keys and metadata.
+func (p Period) Key() string {
+	return p.Start.Format(periodDateLayout) + "_" + p.End.Format(periodDateLayout)
+}
+
+func (p Period) validate() error {
+	if p.Days() < 1 {
+		return fmt.Errorf("period must span at least one day, got %s", p.Key())
+	}
+
+	return nil
+}
+
+// Cohort configures the accrual of a group of accounts. Empty matching fields
+// match any account; an account belongs to the first cohort it matches.
+type Cohort struct {
+	// Name identifies the cohort in idempotency keys, metadata and results.
+	Name string
+
+	// AccountType, AssetCode and Aliases select the accounts of the cohort.
+	// AssetCode is required: only balances of that asset accrue.
+	AccountType string
+	AssetCode   string
+	Aliases     []string
+
+	// Match optionally refines the selection with a custom predicate.
+	Match func(models.Account) bool
+
+	// AnnualRate is the yearly interest rate, e.g. 0.05 for 5%.
+	AnnualRate decimal.Decimal
+
+	// Method defaults to MethodSimple.
+	Method Method
+
+	// DayCount is the number of days in the rate year, 365 by default (e.g., 360).
+	DayCount int
+
+	// Scale is the number of decimal places of posted amounts, 2 by default.
+	// Amounts are rounded down.
+	Scale int32
+
+	// MinimumBalance excludes balances below it from accruing.
+	MinimumBalance decimal.Decimal
+
+	// SourceAccount is the alias or ID of the account funding the interest.
+	SourceAccount string
+
+	// Description of the posted transactions, "Interest accrual" by default.
+	Description string
+
+	// ChartOfAccountsGroupName is set on the posted transactions.
+	ChartOfAccountsGroupName string
+}
+
+func (c *Cohort) validate() error {
+	switch {
+	case c.Name == "":
+		return errors.New("cohort name is required")
+	case c.AssetCode == "":
+		return fmt.Errorf("cohort %s: asset code is required", c.Name)
+	case c.SourceAccount == "":
+		return fmt.Errorf("cohort %s: source account is required", c.Name)
+	case c.AnnualRate.IsNegative():
+		return fmt.Errorf("cohort %s: annual rate must not be negative", c.Name)
+	case c.Method != "" && c.Method != MethodSimple && c.Method != MethodCompound:
+		return fmt.Errorf("cohort %s: unknown method %q", c.Name, c.Method)
+	case c.DayCount < 0 || c.Scale < 0:
+		return fmt.Errorf("cohort %s: day count and scale must not be negative", c.Name)
+	}
+
+	return nil
+}
+
+func (c *Cohort) matches(a models.Account) bool {
+	if c.AccountType != "" && !strings.EqualFold(c.AccountType, a.Type) {
+		return false
+	}
+
+	if c.AssetCode != "" && a.AssetCode != "" && c.AssetCode != a.AssetCode {
+		return false
+	}
+
+	if len(c.Aliases) > 0 {
+		alias := ""
+		if a.Alias != nil {
+			alias = *a.Alias
+		}
+
+		found := false
+
+		for _, candidate := range c.Aliases {
+			if candidate == alias {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return c.Match == nil || c.Match(a)
+}
+
+func (c *Cohort) dayCount() int {
+	if c.DayCount > 0 {
+		return c.DayCount
+	}
+
+	return defaultDayCount
+}
+
+func (c *Cohort) scale() int32 {
+	if c.Scale > 0 {
+		return c.Scale
+	}
+
+	return defaultScale
+}
+
+// factor returns the interest earned per unit of balance over days.
+func (c *Cohort) factor(days int) decimal.Decimal {
+	daily := c.AnnualRate.DivRound(decimal.NewFromInt(int64(c.dayCount())), factorPrecision)
+
+	if c.Method != MethodCompound {
+		return daily.Mul(decimal.NewFromInt(int64(days)))
+	}
+
+	// exponentiation by squaring, rounding every step to bound the digits
+	result, base := decimal.NewFromInt(1), decimal.NewFromInt(1).Add(daily)
+
+	for n := days; n > 0; n /= 2 {
+		if n%2 == 1 {
+			result = result.Mul(base).Round(factorPrecision)
+		}
+
+		base = base.Mul(base).Round(factorPrecision)
+	}
+
+	return result.Sub(decimal.NewFromInt(1))
+}
+
+// Interest returns the interest a balance accrues over days under the cohort
+// terms, rounded down to the cohort scale.
+func (c *Cohort) Interest(balance decimal.Decimal, days int) decimal.Decimal {
+	if days < 1 || !balance.IsPositive() || balance.LessThan(c.MinimumBalance) {
+		return decimal.Zero
+	}
+
+	return balance.Mul(c.factor(days)).RoundDown(c.scale())
+}
+
+// Accrual is the interest computed for an account over a period.
+type Accrual struct {
+	Cohort         string          `json:"cohort"`
+	AccountID      string          `json:"accountId"`
+	Account        string          `json:"account"`
+	AssetCode      string          `json:"assetCode"`
+	Balance        decimal.Decimal `json:"balance"`
+	Days           int             `json:"days"`
+	Amount         decimal.Decimal `json:"amount"`
+	Period         string          `json:"period"`
+	IdempotencyKey string          `json:"idempotencyKey"`
+
+	cohort *Cohort
+}
+
+// Posting is the outcome of posting an accrual.
+type Posting struct {
+	Accrual       Accrual `json:"accrual"`
+	TransactionID string  `json:"transactionId,omitempty"`
+	// AlreadyPosted is set when the API reported the idempotency key as used,
+	// typically on a rerun of the same period
+	AlreadyPosted bool   `json:"alreadyPosted,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// Result summarizes an accrual run.
+type Result struct {
+	Period        string                     `json:"period"`
+	Accruals      []Accrual                  `json:"accruals"`
+	Postings      []Posting                  `json:"postings,omitempty"`
+	Posted        int                        `json:"posted"`
+	AlreadyPosted int                        `json:"alreadyPosted"`
+	Failed        int                        `json:"failed"`
+	Skipped       int                        `json:"skipped"`
+	Totals        map[string]decimal.Decimal `json:"totals"`
+}
+
+// Engine computes and posts accruals.
+type Engine struct {
+	e           *entities.Entity
+	cohorts     []Cohort
+	batchSize   int
+	concurrency int
+	dryRun      bool
+	obs         observability.Provider
+}
+
+// NewEngine creates an Engine posting batches of 100 accruals, 10 at a time.
+func NewEngine(e *entities.Entity) *Engine {
+	return &Engine{e: e, batchSize: defaultBatchSize, concurrency: defaultConcurrency}
+}
+
+// WithCohorts adds cohorts, matched in order.
+func (en *Engine) WithCohorts(cohorts ...Cohort) *Engine {
+	en.cohorts = append(en.cohorts, cohorts...)
+	return en
+}
+
+// WithBatchSize sets the number of accruals posted per batch. A batch completes
+// before the next one starts.
+func (en *Engine) WithBatchSize(size int) *Engine {
+	if size > 0 {
+		en.batchSize = size
+	}
+
+	return en
+}
+
+// WithConcurrency sets the number of transactions posted in parallel within a batch.
+func (en *Engine) WithConcurrency(n int) *Engine {
+	if n > 0 {
+		en.concurrency = n
+	}
+
+	return en
+}
+
+// WithDryRun computes accruals without posting them.
+func (en *Engine) WithDryRun(dryRun bool) *Engine {
+	en.dryRun = dryRun
+	return en
+}
+
+// WithObservability sets the observability provider for tracing.
+func (en *Engine) WithObservability(obs observability.Provider) *Engine {
+	en.obs = obs
+	return en
+}
+
+// Run computes the accruals of a period and posts them, unless the engine runs
+// dry. Failed postings are reported in the result and do not stop the run.
+func (en *Engine) Run(ctx context.Context, orgID, ledgerID string, period Period) (*Result, error) {
+	var result *Result
+
+	err := observability.WithSpan(ctx, en.obs, "Accrual.Run", func(ctx context.Context) error {
+		accruals, skipped, err := en.compute(ctx, orgID, ledgerID, period)
+		if err != nil {
+			return err
+		}
+
+		result = &Result{Period: period.Key(), Accruals: accruals, Skipped: skipped, Totals: map[string]decimal.Decimal{}}
+
+		for _, a := range accruals {
+			result.Totals[a.AssetCode] = result.Totals[a.AssetCode].Add(a.Amount)
+		}
+
+		if en.dryRun {
+			return nil
+		}
+
+		result.Postings = en.Post(ctx, orgID, ledgerID, accruals)
+
+		for _, p := range result.Postings {
+			switch {
+			case p.AlreadyPosted:
+				result.AlreadyPosted++
+			case p.Error != "":
+				result.Failed++
+			default:
+				result.Posted++
+			}
+		}
+
+		return ctx.Err()
+	})
+
+	return result, err
+}
+
+// Compute returns the accruals of a period without posting them. Accounts
+// without a matching cohort or with no interest due are left out.
+func (en *Engine) Compute(ctx context.Context, orgID, ledgerID string, period Period) ([]Accrual, error) {
+	accruals, _, err := en.compute(ctx, orgID, ledgerID, period)
+	return accruals, err
+}
+
+func (en *Engine) compute(ctx context.Context, orgID, ledgerID string, period Period) ([]Accrual, int, error) {
+	if en.e == nil || en.e.Accounts == nil || en.e.Balances == nil || en.e.Transactions == nil {
+		return nil, 0, errors.New("entities not initialized for accruals")
+	}
+
+	if orgID == "" || ledgerID == "" {
+		return nil, 0, errors.New("organization and ledger IDs are required")
+	}
+
+	if len(en.cohorts) == 0 {
+		return nil, 0, errors.New("at least one cohort is required")
+	}
+
+	for i := range en.cohorts {
+		if err := en.cohorts[i].validate(); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if err := period.validate(); err != nil {
+		return nil, 0, err
+	}
+
+	type member struct {
+		account models.Account
+		cohort  *Cohort
+	}
+
+	var members []member
+
+	err := paginate(ctx, func(opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+		return en.e.Accounts.ListAccounts(ctx, orgID, ledgerID, opts)
+	}, func(a models.Account) {
+		for i := range en.cohorts {
+			if en.cohorts[i].matches(a) {
+				members = append(members, member{account: a, cohort: &en.cohorts[i]})
+				return
+			}
+		}
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	results := concurrent.WorkerPool(ctx, members, func(ctx context.Context, m member) (decimal.Decimal, error) {
+		total := decimal.Zero
+
+		err := paginate(ctx, func(opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+			return en.e.Balances.ListAccountBalances(ctx, orgID, ledgerID, m.account.ID, opts)
+		}, func(b models.Balance) {
+			if b.AssetCode == m.cohort.AssetCode {
+				total = total.Add(b.Available)
+			}
+		})
+
+		return total, err
+	}, concurrent.WithWorkers(en.concurrency))
+
+	days := period.Days()
+	accruals := make([]Accrual, 0, len(results))
+	skipped := 0
+
+	for _, r := range results {
+		if r.Error != nil {
+			return nil, 0, fmt.Errorf("failed to list balances of account %s: %w", r.Item.account.ID, r.Error)
+		}
+
+		c := r.Item.cohort
+
+		amount := c.Interest(r.Value, days)
+		if !amount.IsPositive() {
+			skipped++
+			continue
+		}
+
+		account := r.Item.account.ID
+		if r.Item.account.Alias != nil && *r.Item.account.Alias != "" {
+			account = *r.Item.account.Alias
+		}
+
+		accruals = append(accruals, Accrual{
+			Cohort:         c.Name,
+			AccountID:      r.Item.account.ID,
+			Account:        account,
+			AssetCode:      c.AssetCode,
+			Balance:        r.Value,
+			Days:           days,
+			Amount:         amount,
+			Period:         period.Key(),
+			IdempotencyKey: IdempotencyKey(c.Name, r.Item.account.ID, period),
+			cohort:         c,
+		})
+	}
+
+	sort.SliceStable(accruals, func(i, j int) bool { return accruals[i].AccountID < accruals[j].AccountID })
+
+	return accruals, skipped, nil
+}
+
+// IdempotencyKey returns the key of the accrual of an account in a cohort for a period.
+func IdempotencyKey(cohort, accountID string, period Period) string {
+	return "accrual-" + cohort + "-" + accountID + "-" + period.Key()
+}
+
+// Post posts accruals in batches, returning one posting per accrual in order.
+// Accruals must come from Compute or Run of the same engine.
+func (en *Engine) Post(ctx context.Context, orgID, ledgerID string, accruals []Accrual) []Posting {
+	postings := make([]Posting, 0, len(accruals))
+
+	for start := 0; start < len(accruals); start += en.batchSize {
+		batch := accruals[start:min(start+en.batchSize, len(accruals))]
+
+		results := concurrent.WorkerPool(ctx, batch, func(ctx context.Context, a Accrual) (*models.Transaction, error) {
+			input := a.transactionInput()
+			return en.e.Transactions.CreateTransaction(entities.WithIdempotencyKey(ctx, input.IdempotencyKey), orgID, ledgerID, input)
+		}, concurrent.WithWorkers(en.concurrency))
+
+		for _, r := range results {
+			p := Posting{Accrual: r.Item}
+
+			switch {
+			case r.Error == nil:
+				if r.Value != nil {
+					p.TransactionID = r.Value.ID
+				}
+			case sdkerrors.IsIdempotencyError(r.Error) || sdkerrors.IsConflictError(r.Error):
+				p.AlreadyPosted = true
+			default:
+				p.Error = r.Error.Error()
+			}
+
+			postings = append(postings, p)
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return postings
+}
+
+// transactionInput builds the transaction moving the accrual from the cohort
+// source account to the account.
+func (a Accrual) transactionInput() *models.CreateTransactionInput {
+	c := a.cohort
+	if c == nil {
+		c = &Cohort{Name: a.Cohort}
+	}
+
+	description := c.Description
+	if description == "" {
+		description = "Interest accrual"
+	}
+
+	amount := a.Amount.String()
+	method := c.Method
+
+	if method == "" {
+		method = MethodSimple
+	}
+
+	return &models.CreateTransactionInput{
+		Description:              fmt.Sprintf("%s %s", description, a.Period),
+		Amount:                   amount,
+		AssetCode:                a.AssetCode,
+		IdempotencyKey:           a.IdempotencyKey,
+		ChartOfAccountsGroupName: c.ChartOfAccountsGroupName,
+		Metadata: map[string]any{
+			"accrualCohort": a.Cohort,
+			"accrualPeriod": a.Period,
+			"accrualMethod": string(method),
+			"accrualRate":   c.AnnualRate.String(),
+			"accrualDays":   a.Days,
+		},
+		Send: &models.SendInput{
+			Asset: a.AssetCode,
+			Value: amount,
+			Source: &models.SourceInput{
+				From: []models.FromToInput{{Account: c.SourceAccount, Amount: models.AmountInput{Asset: a.AssetCode, Value: amount}}},
+			},
+			Distribute: &models.DistributeInput{
+				To: []models.FromToInput{{Account: a.Account, Amount: models.AmountInput{Asset: a.AssetCode, Value: amount}}},
+			},
+		},
+	}
+}
+
+// paginate calls fn for every item of every page returned by list.
+func paginate[T any](ctx context.Context, list func(opts *models.ListOptions) (*models.ListResponse[T], error), fn func(T)) error {
+	opts := models.NewListOptions().WithLimit(models.MaxLimit)
+
+	for opts != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := list(opts)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range page.Items {
+			fn(item)
+		}
+
+		if len(page.Items) == 0 {
+			return nil
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package accrual
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func page[T any](items []T, opts *models.ListOptions) *models.ListResponse[T] {
+	start := min(opts.Offset, len(items))
+	end := min(start+opts.Limit, len(items))
+
+	return &models.ListResponse[T]{
+		Items:      items[start:end],
+		Pagination: models.Pagination{Limit: opts.Limit, Offset: start, Total: len(items)},
+	}
+}
+
+type fakeAccounts struct {
+	entities.AccountsService
+
+	accounts []models.Account
+}
+
+func (f *fakeAccounts) ListAccounts(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+	return page(f.accounts, opts), nil
+}
+
+type fakeBalances struct {
+	entities.BalancesService
+
+	balances []models.Balance
+}
+
+func (f *fakeBalances) ListAccountBalances(_ context.Context, _, _, accountID string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	var out []models.Balance
+
+	for _, b := range f.balances {
+		if b.AccountID == accountID {
+			out = append(out, b)
+		}
+	}
+
+	return page(out, opts), nil
+}
+
+// fakeTransactions rejects reused idempotency keys like the API does.
+type fakeTransactions struct {
+	entities.TransactionsService
+
+	mu     sync.Mutex
+	inputs []*models.CreateTransactionInput
+	keys   map[string]bool
+}
+
+func (f *fakeTransactions) CreateTransaction(_ context.Context, _, _ string, input *models.CreateTransactionInput) (*models.Transaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.keys[input.IdempotencyKey] {
+		return nil, sdkerrors.NewConflictError("CreateTransaction", "transaction", input.IdempotencyKey, nil)
+	}
+
+	if f.keys == nil {
+		f.keys = map[string]bool{}
+	}
+
+	f.keys[input.IdempotencyKey] = true
+	f.inputs = append(f.inputs, input)
+
+	return &models.Transaction{ID: "tx-" + input.IdempotencyKey}, nil
+}
+
+func newEntity() (*entities.Entity, *fakeTransactions) {
+	alias := func(s string) *string { return &s }
+	usd := func(id, account, available string) models.Balance {
+		return models.Balance{ID: id, AccountID: account, AssetCode: "USD", Available: decimal.RequireFromString(available)}
+	}
+
+	txs := &fakeTransactions{}
+
+	return &entities.Entity{
+		Accounts: &fakeAccounts{accounts: []models.Account{
+			{ID: "a1", Alias: alias("@alice"), Type: "savings", AssetCode: "USD"},
+			{ID: "a2", Alias: alias("@bob"), Type: "savings", AssetCode: "USD"},
+			{ID: "a3", Alias: alias("@carol"), Type: "checking", AssetCode: "USD"},
+			{ID: "a4", Type: "deposit", AssetCode: "USD"},
+		}},
+		Balances: &fakeBalances{balances: []models.Balance{
+			usd("b1", "a1", "10000"),
+			usd("b2", "a2", "0"),
+			usd("b3", "a3", "1000"),
+			usd("b4", "a4", "500"),
+			usd("b5", "a4", "500"),
+			{ID: "b6", AccountID: "a1", AssetCode: "BRL", Available: decimal.NewFromInt(99999)},
+		}},
+		Transactions: txs,
+	}, txs
+}
+
+var (
+	savings = Cohort{
+		Name: "savings", AccountType: "savings", AssetCode: "USD",
+		AnnualRate: decimal.RequireFromString("0.0365"), Method: MethodCompound, SourceAccount: "@interest",
+	}
+	standard = Cohort{
+		Name: "standard", AssetCode: "USD",
+		AnnualRate: decimal.RequireFromString("0.0365"), SourceAccount: "@interest",
+	}
+	january = Month(2025, time.January)
+)
+
+func TestCohort_Interest(t *testing.T) {
+	simple := Cohort{AnnualRate: decimal.RequireFromString("0.0365")}
+	assert.Equal(t, "31", simple.Interest(decimal.NewFromInt(10000), 31).String())
+
+	compound := Cohort{AnnualRate: decimal.RequireFromString("0.0365"), Method: MethodCompound}
+	// 10000 * (1.0001^31 - 1) = 31.0465...
+	assert.Equal(t, "31.04", compound.Interest(decimal.NewFromInt(10000), 31).String())
+
+	// a year of daily compounding stays bounded and close to the effective rate
+	year := compound.Interest(decimal.NewFromInt(10000), 365)
+	assert.Equal(t, "371.72", year.String())
+
+	compound.Scale = 4
+	assert.Equal(t, "31.0465", compound.Interest(decimal.NewFromInt(10000), 31).String())
+
+	threeSixty := Cohort{AnnualRate: decimal.RequireFromString("0.036"), DayCount: 360}
+	assert.Equal(t, "30", threeSixty.Interest(decimal.NewFromInt(10000), 30).String())
+
+	minimum := Cohort{AnnualRate: decimal.RequireFromString("0.0365"), MinimumBalance: decimal.NewFromInt(100)}
+	assert.True(t, minimum.Interest(decimal.NewFromInt(99), 31).IsZero())
+	assert.True(t, simple.Interest(decimal.NewFromInt(-500), 31).IsZero())
+	assert.True(t, simple.Interest(decimal.NewFromInt(500), 0).IsZero())
+}
+
+func TestPeriod(t *testing.T) {
+	assert.Equal(t, 31, january.Days())
+	assert.Equal(t, 28, Month(2025, time.February).Days())
+	assert.Equal(t, "2025-01-01_2025-02-01", january.Key())
+
+	day := Day(time.Date(2025, 3, 9, 15, 4, 5, 0, time.UTC))
+	assert.Equal(t, 1, day.Days())
+	assert.Equal(t, "2025-03-09_2025-03-10", day.Key())
+}
+
+func TestEngine_Compute(t *testing.T) {
+	e, txs := newEntity()
+
+	result, err := NewEngine(e).WithCohorts(savings, standard).WithDryRun(true).Run(context.Background(), "org", "ledger", january)
+	require.NoError(t, err)
+
+	assert.Empty(t, txs.inputs, "dry runs do not post")
+	assert.Empty(t, result.Postings)
+	assert.Equal(t, 1, result.Skipped, "@bob has nothing to accrue")
+	require.Len(t, result.Accruals, 3)
+
+	alice, carol, deposit := result.Accruals[0], result.Accruals[1], result.Accruals[2]
+
+	assert.Equal(t, "savings", alice.Cohort)
+	assert.Equal(t, "@alice", alice.Account)
+	assert.Equal(t, "10000", alice.Balance.String(), "other assets do not accrue")
+	assert.Equal(t, "31.04", alice.Amount.String())
+	assert.Equal(t, "accrual-savings-a1-2025-01-01_2025-02-01", alice.IdempotencyKey)
+
+	assert.Equal(t, "standard", carol.Cohort)
+	assert.Equal(t, "3.1", carol.Amount.String())
+
+	assert.Equal(t, "a4", deposit.Account, "accounts without alias are addressed by ID")
+	assert.Equal(t, "1000", deposit.Balance.String())
+
+	assert.Equal(t, "37.24", result.Totals["USD"].String())
+}
+
+func TestEngine_RunIsIdempotent(t *testing.T) {
+	e, txs := newEntity()
+	engine := NewEngine(e).WithCohorts(savings, standard).WithBatchSize(2).WithConcurrency(2)
+
+	result, err := engine.Run(context.Background(), "org", "ledger", january)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.Posted)
+	require.Len(t, txs.inputs, 3)
+
+	var alice *models.CreateTransactionInput
+
+	for _, in := range txs.inputs {
+		if in.Send.Distribute.To[0].Account == "@alice" {
+			alice = in
+		}
+	}
+
+	require.NotNil(t, alice)
+	assert.Equal(t, "31.04", alice.Amount)
+	assert.Equal(t, "@interest", alice.Send.Source.From[0].Account)
+	assert.Equal(t, "compound", alice.Metadata["accrualMethod"])
+	assert.Equal(t, "2025-01-01_2025-02-01", alice.Metadata["accrualPeriod"])
+
+	assert.Equal(t, "tx-accrual-savings-a1-2025-01-01_2025-02-01", result.Postings[0].TransactionID)
+
+	// rerunning the period posts nothing new
+	rerun, err := engine.Run(context.Background(), "org", "ledger", january)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, rerun.Posted)
+	assert.Equal(t, 3, rerun.AlreadyPosted)
+	assert.Equal(t, 0, rerun.Failed)
+	assert.Len(t, txs.inputs, 3)
+
+	// the next period gets new keys
+	next, err := engine.Run(context.Background(), "org", "ledger", Month(2025, time.February))
+	require.NoError(t, err)
+	assert.Equal(t, 3, next.Posted)
+}
+
+func TestEngine_Validation(t *testing.T) {
+	e, _ := newEntity()
+	ctx := context.Background()
+
+	_, err := NewEngine(e).Run(ctx, "org", "ledger", january)
+	assert.ErrorContains(t, err, "cohort")
+
+	_, err = NewEngine(e).WithCohorts(Cohort{Name: "x", AssetCode: "USD"}).Run(ctx, "org", "ledger", january)
+	assert.ErrorContains(t, err, "source account")
+
+	_, err = NewEngine(e).WithCohorts(Cohort{Name: "x", AssetCode: "USD", SourceAccount: "@s", Method: "monthly"}).Run(ctx, "org", "ledger", january)
+	assert.ErrorContains(t, err, "unknown method")
+
+	_, err = NewEngine(e).WithCohorts(standard).Run(ctx, "org", "ledger", Period{Start: january.End, End: january.Start})
+	assert.ErrorContains(t, err, "at least one day")
+
+	_, err = NewEngine(&entities.Entity{}).WithCohorts(standard).Run(ctx, "org", "ledger", january)
+	assert.Error(t, err)
+}
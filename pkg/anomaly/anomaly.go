@@ -0,0 +1,379 @@
+// Package anomaly flags outliers in transaction streams with exponentially
+// weighted moving averages (EWMA) and z-scores.
+//
+// A Detector keeps, per key (usually the source account of the
+// transactions), the moving mean and variance of three metrics:
+//
+//   - amount: the amount of each transaction
+//   - volume: the number of transactions per window
+//   - error_rate: the share of failed transactions per window
+//
+// Each value is compared to the moving statistics before they are updated, so
+// a sudden spike is flagged when it happens. The detector works the same over
+// the results of a finished batch and over a live stream, such as the
+// OnProgress callback of a batch or an event log being tailed.
+//
+// Example:
+//
+//	d := anomaly.New(anomaly.Config{Threshold: 4})
+//
+//	for _, o := range observations {
+//	    for _, a := range d.Observe(o) {
+//	        log.Printf("%s: %s %.2f (expected %.2f, z=%.1f)", a.Key, a.Metric, a.Value, a.Expected, a.ZScore)
+//	    }
+//	}
+//
+//	d.Flush()
+//	report := d.Anomalies()
+package anomaly
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
+)
+
+// Metric is a monitored quantity.
+type Metric string
+
+const (
+	// MetricAmount is the amount of a transaction
+	MetricAmount Metric = "amount"
+
+	// MetricVolume is the number of transactions of a key in a window
+	MetricVolume Metric = "volume"
+
+	// MetricErrorRate is the share of failed transactions of a key in a window
+	MetricErrorRate Metric = "error_rate"
+)
+
+// Defaults of Config.
+const (
+	DefaultAlpha     = 0.1
+	DefaultThreshold = 3.0
+	DefaultWarmup    = 10
+	DefaultWindow    = time.Minute
+
+	// maxAnomalies bounds the anomalies kept by a Detector
+	maxAnomalies = 1000
+)
+
+// Config tunes a Detector. Zero fields take their defaults.
+type Config struct {
+	// Alpha is the EWMA smoothing factor in (0, 1]; higher values forget
+	// faster. Defaults to DefaultAlpha.
+	Alpha float64
+
+	// Threshold is the absolute z-score from which a value is flagged.
+	// Defaults to DefaultThreshold.
+	Threshold float64
+
+	// Warmup is the number of values of a metric seen for a key before its
+	// values are flagged. Defaults to DefaultWarmup.
+	Warmup int
+
+	// Window is the period over which volume and error rate are counted;
+	// windows without transactions of a key are skipped. Defaults to
+	// DefaultWindow.
+	Window time.Duration
+}
+
+// normalize returns cfg with defaults applied
+func (cfg Config) normalize() Config {
+	if cfg.Alpha <= 0 || cfg.Alpha > 1 {
+		cfg.Alpha = DefaultAlpha
+	}
+
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultThreshold
+	}
+
+	if cfg.Warmup <= 0 {
+		cfg.Warmup = DefaultWarmup
+	}
+
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultWindow
+	}
+
+	return cfg
+}
+
+// Observation is a transaction seen in a stream.
+type Observation struct {
+	// Time is when the transaction completed. Observations without time all
+	// fall in one window, so only their amounts are monitored.
+	Time time.Time
+
+	// Key groups the observations, usually the source account
+	Key string
+
+	// Amount is the amount of the transaction
+	Amount float64
+
+	// Failed reports whether the transaction failed
+	Failed bool
+
+	// Ref identifies the transaction in anomalies, e.g. its ID or index
+	Ref string
+}
+
+// Anomaly is a value deviating from the moving statistics of its key.
+type Anomaly struct {
+	Time   time.Time `json:"time,omitzero"`
+	Key    string    `json:"key"`
+	Metric Metric    `json:"metric"`
+
+	// Value is the flagged value and Expected the moving mean before it
+	Value    float64 `json:"value"`
+	Expected float64 `json:"expected"`
+	ZScore   float64 `json:"zScore"`
+
+	// Ref is the Ref of the observation, for amount anomalies
+	Ref string `json:"ref,omitempty"`
+}
+
+// ewma is an exponentially weighted moving mean and variance
+type ewma struct {
+	n        int
+	mean     float64
+	variance float64
+}
+
+// score returns the z-score of x, with the standard deviation floored at
+// floor so that steady series don't flag negligible deviations
+func (e *ewma) score(x, floor float64) float64 {
+	sd := math.Max(math.Sqrt(e.variance), floor)
+	if sd == 0 {
+		return 0
+	}
+
+	return (x - e.mean) / sd
+}
+
+// update adds x. The first values are weighted equally (1/n), so the
+// statistics don't start biased towards the first value and a zero variance.
+func (e *ewma) update(x, alpha float64) {
+	if e.n == 0 {
+		e.mean = x
+	} else {
+		weight := math.Max(alpha, 1/float64(e.n+1))
+		diff := x - e.mean
+		incr := weight * diff
+		e.mean += incr
+		e.variance = (1 - weight) * (e.variance + diff*incr)
+	}
+
+	e.n++
+}
+
+// keyState is the moving statistics of a key
+type keyState struct {
+	amount, volume, errorRate ewma
+
+	// the window being counted
+	windowStart time.Time
+	count       int
+	failures    int
+}
+
+// Detector flags anomalies per key. It is safe for concurrent use.
+type Detector struct {
+	mu        sync.Mutex
+	cfg       Config
+	keys      map[string]*keyState
+	anomalies []Anomaly
+	total     int
+}
+
+// New creates a Detector.
+func New(cfg Config) *Detector {
+	return &Detector{cfg: cfg.normalize(), keys: make(map[string]*keyState)}
+}
+
+// Observe adds an observation and returns the anomalies it reveals: an
+// outlying amount, or the volume and error rate of the window of its key it
+// closes. Observations of a key are expected in time order.
+func (d *Detector) Observe(o Observation) []Anomaly {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, ok := d.keys[o.Key]
+	if !ok {
+		s = &keyState{windowStart: d.windowOf(o.Time)}
+		d.keys[o.Key] = s
+	}
+
+	var found []Anomaly
+
+	if window := d.windowOf(o.Time); window.After(s.windowStart) {
+		found = d.closeWindow(o.Key, s)
+		s.windowStart = window
+	}
+
+	if !o.Failed {
+		if a, ok := d.check(&s.amount, o.Amount, amountFloor(s.amount.mean)); ok {
+			a.Time, a.Key, a.Metric, a.Ref = o.Time, o.Key, MetricAmount, o.Ref
+			found = append(found, a)
+		}
+
+		s.amount.update(o.Amount, d.cfg.Alpha)
+	}
+
+	s.count++
+	if o.Failed {
+		s.failures++
+	}
+
+	d.record(found)
+
+	return found
+}
+
+// Flush closes the open windows of all keys, e.g. at the end of a batch, and
+// returns the anomalies of their volume and error rate.
+func (d *Detector) Flush() []Anomaly {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	keys := make([]string, 0, len(d.keys))
+	for key := range d.keys {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var found []Anomaly
+
+	for _, key := range keys {
+		found = append(found, d.closeWindow(key, d.keys[key])...)
+	}
+
+	d.record(found)
+
+	return found
+}
+
+// Anomalies returns the anomalies flagged so far, in the order they were
+// found, up to the first 1000.
+func (d *Detector) Anomalies() []Anomaly {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]Anomaly(nil), d.anomalies...)
+}
+
+// Count returns the number of anomalies flagged so far, including those
+// beyond the ones kept.
+func (d *Detector) Count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.total
+}
+
+// closeWindow scores the volume and error rate of the window of s and
+// starts a new one
+func (d *Detector) closeWindow(key string, s *keyState) []Anomaly {
+	if s.count == 0 {
+		return nil
+	}
+
+	var found []Anomaly
+
+	var end time.Time
+	if !s.windowStart.IsZero() {
+		end = s.windowStart.Add(d.cfg.Window)
+	}
+	volume := float64(s.count)
+	errorRate := float64(s.failures) / volume
+
+	if a, ok := d.check(&s.volume, volume, 1); ok {
+		a.Time, a.Key, a.Metric = end, key, MetricVolume
+		found = append(found, a)
+	}
+
+	if a, ok := d.check(&s.errorRate, errorRate, 0.05); ok {
+		a.Time, a.Key, a.Metric = end, key, MetricErrorRate
+		found = append(found, a)
+	}
+
+	s.volume.update(volume, d.cfg.Alpha)
+	s.errorRate.update(errorRate, d.cfg.Alpha)
+	s.count, s.failures = 0, 0
+
+	return found
+}
+
+// check scores x against e once it is warmed up
+func (d *Detector) check(e *ewma, x, floor float64) (Anomaly, bool) {
+	if e.n < d.cfg.Warmup {
+		return Anomaly{}, false
+	}
+
+	z := e.score(x, floor)
+	if math.Abs(z) < d.cfg.Threshold {
+		return Anomaly{}, false
+	}
+
+	return Anomaly{Value: x, Expected: e.mean, ZScore: z}, true
+}
+
+// record keeps found, up to maxAnomalies in total
+func (d *Detector) record(found []Anomaly) {
+	d.total += len(found)
+
+	if room := maxAnomalies - len(d.anomalies); room > 0 {
+		d.anomalies = append(d.anomalies, found[:min(room, len(found))]...)
+	}
+}
+
+// windowOf returns the start of the window of t
+func (d *Detector) windowOf(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+
+	return t.Truncate(d.cfg.Window)
+}
+
+// amountFloor is the deviation from mean below which amounts aren't flagged,
+// 1% of the mean
+func amountFloor(mean float64) float64 {
+	return math.Abs(mean) / 100
+}
+
+// FromEvent returns the observation of a transaction event of an event log,
+// keyed by its "account" attribute with the amount of its "amount"
+// attribute, as recorded by the batch subsystem. It returns false for events
+// of other entities or without an account.
+func FromEvent(ev eventlog.Event) (Observation, bool) {
+	if ev.Entity != eventlog.EntityTransaction {
+		return Observation{}, false
+	}
+
+	account, _ := ev.Attributes["account"].(string) //nolint:errcheck // a missing account is reported as false
+	if account == "" {
+		return Observation{}, false
+	}
+
+	o := Observation{
+		Time:   ev.Time,
+		Key:    account,
+		Failed: ev.Outcome == eventlog.OutcomeFailure || (ev.Outcome == "" && ev.Error != ""),
+		Ref:    ev.ID,
+	}
+
+	switch amount := ev.Attributes["amount"].(type) {
+	case string:
+		o.Amount, _ = strconv.ParseFloat(amount, 64) //nolint:errcheck // unparsable amounts count as zero
+	case float64:
+		o.Amount = amount
+	}
+
+	return o, true
+}
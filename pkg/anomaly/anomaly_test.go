@@ -0,0 +1,114 @@
+package anomaly
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetector_Amount(t *testing.T) {
+	d := New(Config{Warmup: 5})
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	amounts := []float64{100, 102, 98, 101, 99, 100, 103, 97}
+	for i, amount := range amounts {
+		found := d.Observe(Observation{Time: base.Add(time.Duration(i) * time.Second), Key: "@alice", Amount: amount})
+		assert.Empty(t, found, "amount %v is usual", amount)
+	}
+
+	found := d.Observe(Observation{Time: base.Add(10 * time.Second), Key: "@alice", Amount: 5000, Ref: "tx-9"})
+	require.Len(t, found, 1)
+	assert.Equal(t, MetricAmount, found[0].Metric)
+	assert.Equal(t, "@alice", found[0].Key)
+	assert.Equal(t, "tx-9", found[0].Ref)
+	assert.InDelta(t, 5000, found[0].Value, 1e-9)
+	assert.InDelta(t, 100, found[0].Expected, 5)
+	assert.Greater(t, found[0].ZScore, 3.0)
+
+	assert.Empty(t, d.Observe(Observation{Time: base.Add(11 * time.Second), Key: "@bob", Amount: 5000}), "keys are independent and warm up first")
+	assert.Empty(t, d.Observe(Observation{Time: base.Add(12 * time.Second), Key: "@alice", Amount: 0, Failed: true}), "failed amounts are ignored")
+
+	assert.Equal(t, found, d.Anomalies())
+	assert.Equal(t, 1, d.Count())
+}
+
+func TestDetector_VolumeAndErrorRate(t *testing.T) {
+	d := New(Config{Warmup: 5, Window: time.Minute})
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	observe := func(minute, count, failures int) []Anomaly {
+		var found []Anomaly
+
+		for i := range count {
+			found = append(found, d.Observe(Observation{
+				Time:   base.Add(time.Duration(minute)*time.Minute + time.Duration(i)*time.Second),
+				Key:    "@alice",
+				Amount: 10,
+				Failed: i < failures,
+			})...)
+		}
+
+		return found
+	}
+
+	for minute := range 8 {
+		assert.Empty(t, observe(minute, 3, 0))
+	}
+
+	// the spike is flagged when the next window opens
+	assert.Empty(t, observe(8, 40, 20))
+
+	found := observe(9, 3, 0)
+	require.Len(t, found, 2)
+	assert.Equal(t, MetricVolume, found[0].Metric)
+	assert.InDelta(t, 40, found[0].Value, 1e-9)
+	assert.Equal(t, base.Add(9*time.Minute), found[0].Time, "window anomalies are timed at the end of the window")
+	assert.Equal(t, MetricErrorRate, found[1].Metric)
+	assert.InDelta(t, 0.5, found[1].Value, 1e-9)
+
+	assert.Empty(t, d.Flush(), "a usual last window")
+}
+
+func TestDetector_Flush(t *testing.T) {
+	d := New(Config{Warmup: 3})
+
+	for range 5 {
+		d.Observe(Observation{Key: "@alice", Amount: 1})
+	}
+
+	// observations without time share one window, closed by Flush only
+	assert.Empty(t, d.Flush())
+	assert.Empty(t, d.Flush(), "closed windows are not closed again")
+}
+
+func TestConfigNormalize(t *testing.T) {
+	cfg := Config{Alpha: 2, Threshold: -1}.normalize()
+
+	assert.InDelta(t, DefaultAlpha, cfg.Alpha, 1e-9)
+	assert.InDelta(t, DefaultThreshold, cfg.Threshold, 1e-9)
+	assert.Equal(t, DefaultWarmup, cfg.Warmup)
+	assert.Equal(t, DefaultWindow, cfg.Window)
+}
+
+func TestFromEvent(t *testing.T) {
+	now := time.Now().UTC()
+
+	o, ok := FromEvent(eventlog.Event{
+		Time:       now,
+		Entity:     eventlog.EntityTransaction,
+		ID:         "tx-1",
+		Outcome:    eventlog.OutcomeFailure,
+		Attributes: map[string]any{"account": "@alice", "amount": "12.50"},
+	})
+	require.True(t, ok)
+	assert.Equal(t, Observation{Time: now, Key: "@alice", Amount: 12.5, Failed: true, Ref: "tx-1"}, o)
+
+	_, ok = FromEvent(eventlog.Event{Entity: eventlog.EntityAccount, Attributes: map[string]any{"account": "@alice"}})
+	assert.False(t, ok)
+
+	_, ok = FromEvent(eventlog.Event{Entity: eventlog.EntityTransaction})
+	assert.False(t, ok)
+}
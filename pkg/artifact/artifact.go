@@ -0,0 +1,204 @@
+// Package artifact saves generated files, such as load test and integrity
+// reports, to local or bucket destinations identified by a URI.
+//
+// Supported destinations:
+//
+//   - file:///var/reports/run.json writes a local file
+//   - s3://bucket/reports/run.json uploads to Amazon S3 (or an S3-compatible store)
+//   - gs://bucket/reports/run.json uploads to Google Cloud Storage
+//
+// A URI ending with "/" is a prefix: Options.Name is appended to it. Every saved
+// artifact is hashed with SHA-256. Bucket uploads carry the digest as object
+// metadata and a Content-MD5 header so the store rejects corrupted uploads;
+// local files get a "<name>.sha256" file in the sha256sum format.
+//
+// Uploads are signed with AWS Signature Version 4 without extra dependencies.
+// S3 credentials are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+// AWS_SESSION_TOKEN and AWS_REGION, GCS credentials from the HMAC interoperability
+// keys in GCS_HMAC_ACCESS_KEY_ID and GCS_HMAC_SECRET, unless Options.Credentials is set.
+//
+// Example:
+//
+//	result, err := artifact.Save(ctx, "s3://perf-reports/nightly/", data, &artifact.Options{
+//	    Name:      "report.json",
+//	    Retention: 90 * 24 * time.Hour,
+//	})
+package artifact
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+)
+
+// Destination schemes.
+const (
+	SchemeFile = "file"
+	SchemeS3   = "s3"
+	SchemeGCS  = "gs"
+)
+
+// Tag names set from Options.Retention.
+const (
+	TagRetentionDays = "retention-days"
+	TagExpiresAt     = "expires-at"
+)
+
+// ErrUnsupportedScheme is returned for URIs with an unknown scheme.
+var ErrUnsupportedScheme = errors.New("unsupported artifact destination")
+
+// Credentials authenticate bucket uploads.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string // #nosec G117 -- credential supplied by the caller or the environment
+	SessionToken    string
+	// Region of the bucket; "us-east-1" for S3 and "auto" for GCS by default
+	Region string
+}
+
+// Options configures how an artifact is saved.
+type Options struct {
+	// Name is appended to URIs ending with "/"
+	Name string
+	// ContentType of the artifact, inferred from its extension if empty
+	ContentType string
+	// Retention tags the artifact with how long it must be kept, so bucket
+	// lifecycle rules can expire it; zero means no retention tags
+	Retention time.Duration
+	// Tags are added to the retention tags. S3 stores them as object tags, GCS
+	// as object metadata.
+	Tags map[string]string
+	// Credentials override the credentials read from the environment
+	Credentials *Credentials
+	// Endpoint overrides the bucket service URL, e.g. for MinIO or an emulator.
+	// Objects are then addressed path-style: <endpoint>/<bucket>/<key>.
+	Endpoint string
+	// HTTPClient sends uploads, a client with a 60 second timeout by default
+	HTTPClient *http.Client
+}
+
+// Result describes a saved artifact.
+type Result struct {
+	URI    string            `json:"uri"`
+	SHA256 string            `json:"sha256"`
+	Size   int64             `json:"size"`
+	Tags   map[string]string `json:"tags,omitempty"`
+}
+
+// Save writes data to the destination uri.
+func Save(ctx context.Context, uri string, data []byte, opts *Options) (*Result, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact URI %q: %w", uri, err)
+	}
+
+	if strings.HasSuffix(u.Path, "/") || (u.Path == "" && u.Scheme != SchemeFile) {
+		if opts.Name == "" {
+			return nil, fmt.Errorf("artifact URI %q is a prefix and no name was given", uri)
+		}
+
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + opts.Name
+	}
+
+	sum := sha256.Sum256(data)
+	result := &Result{URI: u.String(), SHA256: hex.EncodeToString(sum[:]), Size: int64(len(data)), Tags: tags(opts, time.Now().UTC())}
+
+	switch u.Scheme {
+	case SchemeFile:
+		err = saveFile(u, data, result)
+	case SchemeS3, SchemeGCS:
+		err = upload(ctx, u, data, opts, result)
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnsupportedScheme, u.Scheme)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// tags returns the retention and custom tags of an artifact.
+func tags(opts *Options, now time.Time) map[string]string {
+	if opts.Retention <= 0 && len(opts.Tags) == 0 {
+		return nil
+	}
+
+	out := make(map[string]string, len(opts.Tags)+2)
+	for k, v := range opts.Tags {
+		out[k] = v
+	}
+
+	if opts.Retention > 0 {
+		days := int((opts.Retention + 24*time.Hour - 1) / (24 * time.Hour))
+		out[TagRetentionDays] = strconv.Itoa(days)
+		out[TagExpiresAt] = now.Add(opts.Retention).Format(time.RFC3339)
+	}
+
+	return out
+}
+
+// saveFile writes a local artifact and its checksum file.
+func saveFile(u *url.URL, data []byte, result *Result) error {
+	// file://relative/path parses the first segment as the host
+	path := u.Host + u.Path
+
+	// file:///C:/reports/run.json has the drive letter after the slash
+	if trimmed := strings.TrimPrefix(path, "/"); filepath.VolumeName(trimmed) != "" {
+		path = trimmed
+	}
+
+	path = filepath.FromSlash(path)
+	if path == "" {
+		return errors.New("artifact file path is required")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+
+	if err := fsutil.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write artifact: %w", err)
+	}
+
+	checksum := fmt.Sprintf("%s  %s\n", result.SHA256, filepath.Base(path))
+	if err := fsutil.WriteFileAtomic(path+".sha256", []byte(checksum)); err != nil {
+		return fmt.Errorf("failed to write artifact checksum: %w", err)
+	}
+
+	return nil
+}
+
+// contentType infers the content type of an artifact from its name.
+func contentType(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return "application/json"
+	case ".ndjson":
+		return "application/x-ndjson"
+	case ".html":
+		return "text/html; charset=utf-8"
+	case ".csv":
+		return "text/csv"
+	case ".prom", ".txt":
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
@@ -0,0 +1,411 @@
+// Package backfill applies metadata updates to existing transactions in bulk.
+//
+// Remediation jobs, like tagging historic transactions with a new accounting
+// code, scan the transactions of a ledger, pick the ones matching a filter and
+// update their metadata. A Backfill does this page by page, updating the
+// transactions of a page in parallel under a rate limit, and records its
+// progress in a checkpoint file after each page. A job that is interrupted, or
+// fails, resumes from the last completed page when run again with the same
+// checkpoint file.
+//
+// Pages are read oldest first, so transactions created while the job runs are
+// appended at the end instead of shifting the pages still to be read.
+//
+// Example:
+//
+//	progress, err := backfill.New(client.Entity).
+//	    WithListOptions(models.NewListOptions().WithDateRange("2024-01-01", "2024-12-31")).
+//	    WithFilter(func(tx models.Transaction) bool { return tx.AssetCode == "BRL" }).
+//	    WithRateLimit(20).
+//	    WithCheckpointFile("./backfill-accounting-code.json").
+//	    Run(ctx, orgID, ledgerID, backfill.SetMetadata(map[string]any{"accountingCode": "4.1.2"}))
+package backfill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/kvstore"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+)
+
+// DefaultConcurrency is the number of transactions updated in parallel by default.
+const DefaultConcurrency = 5
+
+// ErrCheckpointMismatch is returned when the checkpoint file belongs to another ledger.
+var ErrCheckpointMismatch = errors.New("checkpoint belongs to another ledger")
+
+// Update returns the metadata to merge into a transaction, and false when the
+// transaction needs no update.
+type Update func(tx models.Transaction) (map[string]any, bool)
+
+// SetMetadata returns an Update setting the given metadata keys, skipping
+// transactions that already have these values. Running it twice is harmless.
+func SetMetadata(values map[string]any) Update {
+	return func(tx models.Transaction) (map[string]any, bool) {
+		for k, v := range values {
+			if current, ok := tx.Metadata[k]; !ok || !reflect.DeepEqual(current, v) {
+				return values, true
+			}
+		}
+
+		return nil, false
+	}
+}
+
+// Failure is a transaction whose update failed.
+type Failure struct {
+	TransactionID string `json:"transactionId"`
+	Error         string `json:"error"`
+}
+
+// Progress is the state of a backfill, saved as its checkpoint.
+type Progress struct {
+	OrganizationID string `json:"organizationId"`
+	LedgerID       string `json:"ledgerId"`
+
+	// Cursor and Offset locate the next page to read
+	Cursor string `json:"cursor,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+
+	// Scanned counts the transactions read, Matched those passing the filter
+	Scanned int `json:"scanned"`
+	Matched int `json:"matched"`
+
+	// Updated counts the transactions updated, or that would be in a dry run
+	Updated int `json:"updated"`
+
+	// Skipped counts the matched transactions the update left unchanged
+	Skipped int `json:"skipped"`
+
+	Failures  []Failure `json:"failures,omitempty"`
+	Completed bool      `json:"completed"`
+	Resumed   bool      `json:"-"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Backfill updates the metadata of the transactions of a ledger.
+type Backfill struct {
+	e              *entities.Entity
+	listOptions    *models.ListOptions
+	filter         func(models.Transaction) bool
+	pageSize       int
+	concurrency    int
+	rateLimit      int
+	checkpointFile string
+	store          kvstore.Interface
+	checkpointKey  string
+	dryRun         bool
+	obs            observability.Provider
+}
+
+// New creates a Backfill scanning every transaction of a ledger.
+func New(e *entities.Entity) *Backfill {
+	return &Backfill{e: e, pageSize: models.MaxLimit, concurrency: DefaultConcurrency}
+}
+
+// WithListOptions sets the server-side filters of the scan, such as a date
+// range. Pagination and ordering fields are managed by the backfill.
+func (b *Backfill) WithListOptions(opts *models.ListOptions) *Backfill {
+	b.listOptions = opts
+	return b
+}
+
+// WithFilter selects the transactions to update among those scanned.
+func (b *Backfill) WithFilter(filter func(models.Transaction) bool) *Backfill {
+	b.filter = filter
+	return b
+}
+
+// WithPageSize sets the number of transactions per page, capped at
+// models.MaxLimit. A page is the unit of a batch and of a checkpoint.
+func (b *Backfill) WithPageSize(size int) *Backfill {
+	if size > 0 {
+		b.pageSize = min(size, models.MaxLimit)
+	}
+
+	return b
+}
+
+// WithConcurrency sets the number of transactions updated in parallel.
+func (b *Backfill) WithConcurrency(workers int) *Backfill {
+	if workers > 0 {
+		b.concurrency = workers
+	}
+
+	return b
+}
+
+// WithRateLimit caps the updates per second; zero means no limit.
+func (b *Backfill) WithRateLimit(opsPerSecond int) *Backfill {
+	b.rateLimit = opsPerSecond
+	return b
+}
+
+// WithCheckpointFile saves the progress to path after each page, and resumes
+// from it when the file exists.
+func (b *Backfill) WithCheckpointFile(path string) *Backfill {
+	b.checkpointFile = path
+	return b
+}
+
+// WithCheckpointStore saves the progress under key of store after each page,
+// and resumes from it when the key exists. It replaces WithCheckpointFile, for
+// jobs whose state is kept in a shared store such as Redis.
+func (b *Backfill) WithCheckpointStore(store kvstore.Interface, key string) *Backfill {
+	b.store, b.checkpointKey = store, key
+	return b
+}
+
+// WithDryRun counts the transactions that would be updated without updating them.
+func (b *Backfill) WithDryRun(dryRun bool) *Backfill {
+	b.dryRun = dryRun
+	return b
+}
+
+// WithObservability sets the observability provider for tracing.
+func (b *Backfill) WithObservability(obs observability.Provider) *Backfill {
+	b.obs = obs
+	return b
+}
+
+// Run applies update to the matching transactions of the ledger. A completed
+// checkpoint makes Run return immediately; delete the file to run again.
+//
+// Failed updates are recorded in Progress.Failures and don't stop the scan.
+// Run returns an error only when listing fails, the context is cancelled or
+// the checkpoint can't be read or written; the progress up to that point is
+// returned with it.
+func (b *Backfill) Run(ctx context.Context, orgID, ledgerID string, update Update) (*Progress, error) {
+	if b.e == nil || b.e.Transactions == nil {
+		return nil, errors.New("transactions service not initialized")
+	}
+
+	if orgID == "" || ledgerID == "" {
+		return nil, errors.New("organization ID and ledger ID are required")
+	}
+
+	if update == nil {
+		return nil, errors.New("update is required")
+	}
+
+	progress, err := b.loadCheckpoint(ctx, orgID, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if progress.Completed {
+		return progress, nil
+	}
+
+	var limiter concurrent.Limiter
+	if b.rateLimit > 0 {
+		limiter = concurrent.NewLimiter(concurrent.AlgorithmGCRA, b.rateLimit, 1)
+		defer limiter.Stop()
+	}
+
+	err = observability.WithSpan(ctx, b.obs, "Backfill.Transactions", func(ctx context.Context) error {
+		return b.run(ctx, progress, update, limiter)
+	})
+
+	return progress, err
+}
+
+// run processes pages from the position of progress until the last one.
+func (b *Backfill) run(ctx context.Context, progress *Progress, update Update, limiter concurrent.Limiter) error {
+	for !progress.Completed {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := b.e.Transactions.ListTransactions(ctx, progress.OrganizationID, progress.LedgerID, b.pageOptions(progress))
+		if err != nil {
+			return fmt.Errorf("failed to list transactions: %w", err)
+		}
+
+		if err := b.processPage(ctx, progress, page.Items, update, limiter); err != nil {
+			return err
+		}
+
+		next := page.Pagination.NextPageOptions()
+		if next == nil || len(page.Items) == 0 {
+			progress.Completed = true
+		} else {
+			progress.Cursor, progress.Offset = next.Cursor, next.Offset
+		}
+
+		if err := b.saveCheckpoint(ctx, progress); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processPage updates the matching transactions of a page in parallel.
+func (b *Backfill) processPage(ctx context.Context, progress *Progress, items []models.Transaction, update Update, limiter concurrent.Limiter) error {
+	progress.Scanned += len(items)
+
+	type change struct {
+		tx       models.Transaction
+		metadata map[string]any
+	}
+
+	var changes []change
+
+	for _, tx := range items {
+		if b.filter != nil && !b.filter(tx) {
+			continue
+		}
+
+		progress.Matched++
+
+		metadata, ok := update(tx)
+		if !ok {
+			progress.Skipped++
+			continue
+		}
+
+		changes = append(changes, change{tx: tx, metadata: metadata})
+	}
+
+	if b.dryRun {
+		progress.Updated += len(changes)
+		return nil
+	}
+
+	opts := []concurrent.PoolOption{concurrent.WithWorkers(b.concurrency)}
+	if limiter != nil {
+		opts = append(opts, concurrent.WithLimiter(limiter))
+	}
+
+	results := concurrent.WorkerPool(ctx, changes, func(ctx context.Context, c change) (struct{}, error) {
+		input := &models.UpdateTransactionInput{Metadata: mergeMetadata(c.tx.Metadata, c.metadata)}
+		_, err := b.e.Transactions.UpdateTransaction(ctx, progress.OrganizationID, progress.LedgerID, c.tx.ID, input)
+
+		return struct{}{}, err
+	}, opts...)
+
+	// an interrupted page is processed again on resume, so it isn't counted
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for i, r := range results {
+		if r.Error != nil {
+			progress.Failures = append(progress.Failures, Failure{TransactionID: changes[i].tx.ID, Error: r.Error.Error()})
+			continue
+		}
+
+		progress.Updated++
+	}
+
+	return nil
+}
+
+// pageOptions returns the list options of the page located by progress.
+func (b *Backfill) pageOptions(progress *Progress) *models.ListOptions {
+	opts := models.NewListOptions()
+	if b.listOptions != nil {
+		base := *b.listOptions
+		opts = &base
+	}
+
+	opts.Page = 0
+	opts.Cursor, opts.Offset = progress.Cursor, progress.Offset
+
+	return opts.WithLimit(b.pageSize).WithOrderDirection(models.SortAscending)
+}
+
+// loadCheckpoint reads the checkpoint, or starts a new progress.
+func (b *Backfill) loadCheckpoint(ctx context.Context, orgID, ledgerID string) (*Progress, error) {
+	progress := &Progress{OrganizationID: orgID, LedgerID: ledgerID}
+
+	var (
+		raw []byte
+		err error
+	)
+
+	switch {
+	case b.store != nil:
+		raw, err = b.store.Get(ctx, b.checkpointKey)
+		if errors.Is(err, kvstore.ErrNotFound) {
+			return progress, nil
+		}
+	case b.checkpointFile != "":
+		raw, err = os.ReadFile(b.checkpointFile)
+		if errors.Is(err, os.ErrNotExist) {
+			return progress, nil
+		}
+	default:
+		return progress, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, progress); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+
+	if progress.OrganizationID != orgID || progress.LedgerID != ledgerID {
+		return nil, fmt.Errorf("%w: %s/%s", ErrCheckpointMismatch, progress.OrganizationID, progress.LedgerID)
+	}
+
+	progress.Resumed = true
+
+	return progress, nil
+}
+
+// saveCheckpoint writes the progress to the checkpoint, replacing it atomically.
+func (b *Backfill) saveCheckpoint(ctx context.Context, progress *Progress) error {
+	progress.UpdatedAt = time.Now().UTC()
+
+	if b.store == nil && b.checkpointFile == "" {
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	if b.store != nil {
+		if err := b.store.Put(ctx, b.checkpointKey, raw); err != nil {
+			return fmt.Errorf("failed to write checkpoint: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := fsutil.WriteFileAtomic(b.checkpointFile, raw); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// mergeMetadata returns the current metadata overlaid with the updates.
+func mergeMetadata(current, updates map[string]any) map[string]any {
+	merged := make(map[string]any, len(current)+len(updates))
+
+	for k, v := range current {
+		merged[k] = v
+	}
+
+	for k, v := range updates {
+		merged[k] = v
+	}
+
+	return merged
+}
@@ -0,0 +1,199 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/kvstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTransactions struct {
+	entities.TransactionsService
+
+	mu           sync.Mutex
+	transactions []models.Transaction
+	updates      map[string]map[string]any
+	failIDs      map[string]bool
+	listCalls    int
+	failListAt   int
+}
+
+func newFakeTransactions(n int) *fakeTransactions {
+	f := &fakeTransactions{updates: make(map[string]map[string]any), failIDs: make(map[string]bool)}
+
+	for i := 0; i < n; i++ {
+		asset := "USD"
+		if i%2 == 0 {
+			asset = "BRL"
+		}
+
+		f.transactions = append(f.transactions, models.Transaction{
+			ID:        fmt.Sprintf("tx-%02d", i),
+			AssetCode: asset,
+			Metadata:  map[string]any{"source": "import"},
+		})
+	}
+
+	return f
+}
+
+func (f *fakeTransactions) ListTransactions(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Transaction], error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.listCalls++
+	if f.failListAt > 0 && f.listCalls == f.failListAt {
+		return nil, errors.New("list failed")
+	}
+
+	start := min(opts.Offset, len(f.transactions))
+	end := min(start+opts.Limit, len(f.transactions))
+
+	return &models.ListResponse[models.Transaction]{
+		Items:      f.transactions[start:end],
+		Pagination: models.Pagination{Limit: opts.Limit, Offset: start, Total: len(f.transactions)},
+	}, nil
+}
+
+func (f *fakeTransactions) UpdateTransaction(_ context.Context, _, _, id string, input any) (*models.Transaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failIDs[id] {
+		return nil, errors.New("update failed")
+	}
+
+	metadata := input.(*models.UpdateTransactionInput).Metadata
+	f.updates[id] = metadata
+
+	for i := range f.transactions {
+		if f.transactions[i].ID == id {
+			f.transactions[i].Metadata = metadata
+		}
+	}
+
+	return &models.Transaction{ID: id, Metadata: metadata}, nil
+}
+
+func isBRL(tx models.Transaction) bool { return tx.AssetCode == "BRL" }
+
+func TestBackfillRun(t *testing.T) {
+	fake := newFakeTransactions(10)
+	fake.failIDs["tx-04"] = true
+
+	progress, err := New(&entities.Entity{Transactions: fake}).
+		WithFilter(isBRL).
+		WithPageSize(3).
+		WithRateLimit(1000).
+		Run(context.Background(), "org", "ledger", SetMetadata(map[string]any{"accountingCode": "4.1.2"}))
+	require.NoError(t, err)
+
+	assert.True(t, progress.Completed)
+	assert.Equal(t, 10, progress.Scanned)
+	assert.Equal(t, 5, progress.Matched)
+	assert.Equal(t, 4, progress.Updated)
+	assert.Equal(t, []Failure{{TransactionID: "tx-04", Error: "update failed"}}, progress.Failures)
+
+	require.Len(t, fake.updates, 4)
+	assert.Equal(t, map[string]any{"source": "import", "accountingCode": "4.1.2"}, fake.updates["tx-00"])
+	assert.NotContains(t, fake.updates, "tx-01")
+}
+
+func TestBackfillResume(t *testing.T) {
+	fake := newFakeTransactions(10)
+	fake.failListAt = 3
+
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+	update := SetMetadata(map[string]any{"accountingCode": "4.1.2"})
+
+	b := New(&entities.Entity{Transactions: fake}).WithPageSize(3).WithCheckpointFile(checkpoint)
+
+	progress, err := b.Run(context.Background(), "org", "ledger", update)
+	require.Error(t, err)
+	assert.False(t, progress.Completed)
+	assert.Equal(t, 6, progress.Updated)
+	assert.Equal(t, 6, progress.Offset)
+
+	progress, err = b.Run(context.Background(), "org", "ledger", update)
+	require.NoError(t, err)
+	assert.True(t, progress.Resumed)
+	assert.True(t, progress.Completed)
+	assert.Equal(t, 10, progress.Scanned)
+	assert.Equal(t, 10, progress.Updated)
+	assert.Len(t, fake.updates, 10)
+
+	// a completed checkpoint doesn't scan again
+	calls := fake.listCalls
+
+	_, err = b.Run(context.Background(), "org", "ledger", update)
+	require.NoError(t, err)
+	assert.Equal(t, calls, fake.listCalls)
+
+	_, err = b.Run(context.Background(), "org", "other-ledger", update)
+	assert.ErrorIs(t, err, ErrCheckpointMismatch)
+}
+
+func TestBackfillCheckpointStore(t *testing.T) {
+	fake := newFakeTransactions(7)
+	fake.failListAt = 2
+
+	store := kvstore.NewMemory()
+	update := SetMetadata(map[string]any{"accountingCode": "4.1.2"})
+
+	b := New(&entities.Entity{Transactions: fake}).WithPageSize(3).WithCheckpointStore(store, "backfill/org/ledger")
+
+	_, err := b.Run(context.Background(), "org", "ledger", update)
+	require.Error(t, err)
+
+	var saved Progress
+
+	found, err := kvstore.GetJSON(context.Background(), store, "backfill/org/ledger", &saved)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, 3, saved.Offset)
+
+	progress, err := b.Run(context.Background(), "org", "ledger", update)
+	require.NoError(t, err)
+	assert.True(t, progress.Resumed)
+	assert.True(t, progress.Completed)
+	assert.Equal(t, 7, progress.Updated)
+}
+
+func TestBackfillDryRunAndIdempotence(t *testing.T) {
+	fake := newFakeTransactions(4)
+	update := SetMetadata(map[string]any{"source": "import"})
+
+	progress, err := New(&entities.Entity{Transactions: fake}).WithDryRun(true).
+		Run(context.Background(), "org", "ledger", SetMetadata(map[string]any{"accountingCode": "1"}))
+	require.NoError(t, err)
+	assert.Equal(t, 4, progress.Updated)
+	assert.Empty(t, fake.updates)
+
+	progress, err = New(&entities.Entity{Transactions: fake}).Run(context.Background(), "org", "ledger", update)
+	require.NoError(t, err)
+	assert.Equal(t, 4, progress.Skipped)
+	assert.Zero(t, progress.Updated)
+}
+
+func TestBackfillValidation(t *testing.T) {
+	update := SetMetadata(map[string]any{"k": "v"})
+
+	_, err := New(nil).Run(context.Background(), "org", "ledger", update)
+	assert.Error(t, err)
+
+	b := New(&entities.Entity{Transactions: newFakeTransactions(1)})
+
+	_, err = b.Run(context.Background(), "", "ledger", update)
+	assert.Error(t, err)
+
+	_, err = b.Run(context.Background(), "org", "ledger", nil)
+	assert.Error(t, err)
+}
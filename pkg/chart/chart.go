@@ -0,0 +1,482 @@
+// Package chart manages a chart of accounts: the codes, names, hierarchy and
+// account types of the accounts of a ledger, and the chart of accounts groups
+// its transactions are booked under.
+//
+// A Chart is usually kept as a JSON file, loaded with LoadChart:
+//
+//	{
+//	  "name": "retail",
+//	  "groups": ["deposits", "transfers", "fees"],
+//	  "accounts": [
+//	    {"code": "1", "name": "Assets", "type": "ASSET"},
+//	    {"code": "1.1", "name": "Cash", "parent": "1", "alias": "@cash"},
+//	    {"code": "4", "name": "Revenue", "type": "REVENUE"},
+//	    {"code": "4.1", "name": "Fee income", "parent": "4", "alias": "@fees"}
+//	  ]
+//	}
+//
+// Provision creates the accounts of the chart in a ledger, parents first, and
+// Apply books transactions under a group of the chart, tagging their
+// operations with the codes of their accounts, so every transaction refers to
+// groups and codes that exist:
+//
+//	report, err := c.Provision(ctx, client.Entity, orgID, ledgerID, "BRL")
+//
+//	input := models.NewCreateTransactionInput("BRL", "100").WithSend(send)
+//	if err := c.Apply(input, "fees"); err != nil {
+//	    return err
+//	}
+package chart
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+)
+
+// Metadata keys set on the accounts created by Provision.
+const (
+	MetadataCode  = "chartOfAccountsCode"
+	MetadataChart = "chartOfAccounts"
+)
+
+var (
+	// ErrUnknownGroup is returned for a chart of accounts group the chart doesn't define.
+	ErrUnknownGroup = errors.New("unknown chart of accounts group")
+
+	// ErrUnknownCode is returned for a chart of accounts code the chart doesn't define.
+	ErrUnknownCode = errors.New("unknown chart of accounts code")
+)
+
+// aliasInvalid matches the characters an alias can't hold.
+var aliasInvalid = regexp.MustCompile(`[^a-zA-Z0-9@:_-]`)
+
+// Account is an account of the chart.
+type Account struct {
+	// Code identifies the account in the chart, such as "1.1.02"
+	Code string `json:"code"`
+	Name string `json:"name"`
+
+	// Parent is the code of the parent account, empty for top-level accounts
+	Parent string `json:"parent,omitempty"`
+
+	// Type is the Midaz account type, inherited from the parent when empty
+	Type string `json:"type,omitempty"`
+
+	// Alias is the alias of the account in the ledger, DefaultAlias(Code) when empty
+	Alias string `json:"alias,omitempty"`
+
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// Chart is a chart of accounts.
+type Chart struct {
+	// Name is recorded in the metadata of the accounts created
+	Name string `json:"name,omitempty"`
+
+	// Groups are the chart of accounts group names transactions can be booked under
+	Groups []string `json:"groups"`
+
+	Accounts []Account `json:"accounts"`
+
+	codes   map[string]int
+	aliases map[string]int
+}
+
+// DefaultAlias returns the alias of an account of code without one:
+// "@coa-" followed by the code, with the characters aliases can't hold
+// replaced by hyphens, such as "@coa-1-1-02" for "1.1.02".
+func DefaultAlias(code string) string {
+	return "@coa-" + aliasInvalid.ReplaceAllString(code, "-")
+}
+
+// ParseChart decodes and validates a JSON chart, rejecting unknown fields.
+func ParseChart(data []byte) (*Chart, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var chart Chart
+	if err := decoder.Decode(&chart); err != nil {
+		return nil, fmt.Errorf("decoding chart: %w", err)
+	}
+
+	if err := chart.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &chart, nil
+}
+
+// LoadChart reads a JSON chart from path.
+func LoadChart(path string) (*Chart, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading chart: %w", err)
+	}
+
+	return ParseChart(data)
+}
+
+// Validate checks that the groups and accounts of the chart are valid and
+// consistent: unique codes and aliases, existing parents without cycles, and
+// a type for every account. It indexes the chart, so it must be called again
+// after changing it.
+func (c *Chart) Validate() error {
+	var errs []error
+
+	groups := map[string]bool{}
+
+	for _, group := range c.Groups {
+		if err := validation.ValidateChartOfAccountsGroupName(group); err != nil {
+			errs = append(errs, err)
+		} else if groups[group] {
+			errs = append(errs, fmt.Errorf("duplicate group %q", group))
+		}
+
+		groups[group] = true
+	}
+
+	c.codes = make(map[string]int, len(c.Accounts))
+	c.aliases = make(map[string]int, len(c.Accounts))
+
+	for i, account := range c.Accounts {
+		if account.Code == "" {
+			errs = append(errs, fmt.Errorf("account %d: code is required", i+1))
+			continue
+		}
+
+		if _, ok := c.codes[account.Code]; ok {
+			errs = append(errs, fmt.Errorf("account %s: duplicate code", account.Code))
+			continue
+		}
+
+		c.codes[account.Code] = i
+
+		if account.Name == "" {
+			errs = append(errs, fmt.Errorf("account %s: name is required", account.Code))
+		}
+
+		alias := c.Accounts[i].alias()
+		if err := validation.ValidateAccountAlias(alias); err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", account.Code, err))
+		} else if other, ok := c.aliases[alias]; ok {
+			errs = append(errs, fmt.Errorf("account %s: alias %s already used by account %s", account.Code, alias, c.Accounts[other].Code))
+		}
+
+		c.aliases[alias] = i
+	}
+
+	for _, account := range c.Accounts {
+		if _, ok := c.codes[account.Code]; !ok {
+			continue
+		}
+
+		if _, err := c.Path(account.Code); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if c.Type(account.Code) == "" {
+			errs = append(errs, fmt.Errorf("account %s: type is required, on the account or a parent", account.Code))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid chart: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// alias returns the alias of the account.
+func (a *Account) alias() string {
+	if a.Alias != "" {
+		return a.Alias
+	}
+
+	return DefaultAlias(a.Code)
+}
+
+// Account returns the account of code, or nil.
+func (c *Chart) Account(code string) *Account {
+	i, ok := c.index()[code]
+	if !ok {
+		return nil
+	}
+
+	return &c.Accounts[i]
+}
+
+// AccountByAlias returns the account with alias, or nil.
+func (c *Chart) AccountByAlias(alias string) *Account {
+	c.index()
+
+	i, ok := c.aliases[alias]
+	if !ok {
+		return nil
+	}
+
+	return &c.Accounts[i]
+}
+
+// Alias returns the alias of the account of code, or an empty string.
+func (c *Chart) Alias(code string) string {
+	if account := c.Account(code); account != nil {
+		return account.alias()
+	}
+
+	return ""
+}
+
+// Type returns the account type of the account of code, its own or the
+// closest parent's.
+func (c *Chart) Type(code string) string {
+	path, err := c.Path(code)
+	if err != nil {
+		return ""
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		if t := c.Account(path[i]).Type; t != "" {
+			return t
+		}
+	}
+
+	return ""
+}
+
+// Path returns the codes from the top-level account down to the account of
+// code. It returns an error for unknown codes and cyclic hierarchies.
+func (c *Chart) Path(code string) ([]string, error) {
+	var path []string
+
+	for current := code; current != ""; {
+		account := c.Account(current)
+		if account == nil {
+			if current == code {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownCode, code)
+			}
+
+			return nil, fmt.Errorf("account %s: parent %s doesn't exist", path[0], current)
+		}
+
+		if slices.Contains(path, current) {
+			return nil, fmt.Errorf("account %s: cyclic hierarchy", code)
+		}
+
+		path = append([]string{current}, path...)
+		current = account.Parent
+	}
+
+	return path, nil
+}
+
+// Children returns the codes of the accounts whose parent is code, or of the
+// top-level accounts for an empty code, in chart order.
+func (c *Chart) Children(code string) []string {
+	var children []string
+
+	for _, account := range c.Accounts {
+		if account.Parent == code {
+			children = append(children, account.Code)
+		}
+	}
+
+	return children
+}
+
+// HasGroup reports whether the chart defines the group.
+func (c *Chart) HasGroup(group string) bool {
+	return slices.Contains(c.Groups, group)
+}
+
+// Apply books input under group, after checking that the chart defines it,
+// and tags its source and destination accounts with their chart of accounts
+// codes, when they are accounts of the chart and aren't tagged yet. It
+// returns ErrUnknownGroup or ErrUnknownCode when input refers to a group or a
+// code the chart doesn't define.
+func (c *Chart) Apply(input *models.CreateTransactionInput, group string) error {
+	if !c.HasGroup(group) {
+		return fmt.Errorf("%w: %q", ErrUnknownGroup, group)
+	}
+
+	input.ChartOfAccountsGroupName = group
+
+	for _, entry := range entries(input) {
+		if entry.ChartOfAccounts != "" {
+			continue
+		}
+
+		alias := entry.AccountAlias
+		if alias == "" {
+			alias = entry.Account
+		}
+
+		if account := c.AccountByAlias(alias); account != nil {
+			entry.ChartOfAccounts = account.Code
+		}
+	}
+
+	return c.Check(input)
+}
+
+// Check returns ErrUnknownGroup when input isn't booked under a group of the
+// chart, and ErrUnknownCode when one of its accounts is tagged with a code
+// the chart doesn't define.
+func (c *Chart) Check(input *models.CreateTransactionInput) error {
+	if !c.HasGroup(input.ChartOfAccountsGroupName) {
+		return fmt.Errorf("%w: %q", ErrUnknownGroup, input.ChartOfAccountsGroupName)
+	}
+
+	for _, entry := range entries(input) {
+		if entry.ChartOfAccounts != "" && c.Account(entry.ChartOfAccounts) == nil {
+			return fmt.Errorf("%w: %s, on account %s", ErrUnknownCode, entry.ChartOfAccounts, entry.Account)
+		}
+	}
+
+	return nil
+}
+
+// entries returns the source and destination entries of input.
+func entries(input *models.CreateTransactionInput) []*models.FromToInput {
+	if input.Send == nil {
+		return nil
+	}
+
+	var out []*models.FromToInput
+
+	if input.Send.Source != nil {
+		for i := range input.Send.Source.From {
+			out = append(out, &input.Send.Source.From[i])
+		}
+	}
+
+	if input.Send.Distribute != nil {
+		for i := range input.Send.Distribute.To {
+			out = append(out, &input.Send.Distribute.To[i])
+		}
+	}
+
+	return out
+}
+
+// index returns the index of the codes, building it for charts not validated.
+func (c *Chart) index() map[string]int {
+	if c.codes == nil {
+		c.codes = make(map[string]int, len(c.Accounts))
+		c.aliases = make(map[string]int, len(c.Accounts))
+
+		for i := range c.Accounts {
+			if _, ok := c.codes[c.Accounts[i].Code]; !ok {
+				c.codes[c.Accounts[i].Code] = i
+			}
+
+			if _, ok := c.aliases[c.Accounts[i].alias()]; !ok {
+				c.aliases[c.Accounts[i].alias()] = i
+			}
+		}
+	}
+
+	return c.codes
+}
+
+// Report is the outcome of a Provision.
+type Report struct {
+	// Created and Existing list the codes of the accounts created and of those
+	// found in the ledger by their alias
+	Created  []string `json:"created"`
+	Existing []string `json:"existing"`
+
+	// AccountIDs maps the codes to the IDs of their accounts in the ledger
+	AccountIDs map[string]string `json:"accountIds"`
+}
+
+// Provision creates the accounts of the chart in a ledger, with assetCode,
+// parents before their children. Accounts whose alias already exists in the
+// ledger are kept as they are, so Provision can be run again after a failure
+// or to add the accounts new to the chart. The accounts created record their
+// code, and the chart name when set, in their metadata.
+func (c *Chart) Provision(ctx context.Context, e *entities.Entity, orgID, ledgerID, assetCode string) (*Report, error) {
+	if e == nil || e.Accounts == nil {
+		return nil, errors.New("entities not initialized for charts of accounts")
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	report := &Report{Created: []string{}, Existing: []string{}, AccountIDs: map[string]string{}}
+
+	var provision func(code string) error
+
+	provision = func(code string) error {
+		account := c.Account(code)
+
+		existing, err := e.Accounts.GetAccountByAlias(ctx, orgID, ledgerID, account.alias())
+
+		switch {
+		case err == nil:
+			report.Existing = append(report.Existing, code)
+			report.AccountIDs[code] = existing.ID
+		case sdkerrors.IsNotFoundError(err):
+			created, err := e.Accounts.CreateAccount(ctx, orgID, ledgerID, c.accountInput(account, assetCode, report.AccountIDs[account.Parent]))
+			if err != nil {
+				return fmt.Errorf("creating account %s: %w", code, err)
+			}
+
+			report.Created = append(report.Created, code)
+			report.AccountIDs[code] = created.ID
+		default:
+			return fmt.Errorf("looking up account %s: %w", code, err)
+		}
+
+		for _, child := range c.Children(code) {
+			if err := provision(child); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, code := range c.Children("") {
+		if err := provision(code); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// accountInput returns the input creating account under the parent account parentID.
+func (c *Chart) accountInput(account *Account, assetCode, parentID string) *models.CreateAccountInput {
+	metadata := make(map[string]any, len(account.Metadata)+2)
+	for k, v := range account.Metadata {
+		metadata[k] = v
+	}
+
+	metadata[MetadataCode] = account.Code
+	if c.Name != "" {
+		metadata[MetadataChart] = c.Name
+	}
+
+	input := models.NewCreateAccountInput(account.Name, assetCode, c.Type(account.Code)).
+		WithAlias(account.alias()).
+		WithMetadata(metadata)
+
+	if parentID != "" {
+		input = input.WithParentAccountID(parentID)
+	}
+
+	return input
+}
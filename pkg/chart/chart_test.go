@@ -0,0 +1,185 @@
+package chart
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChart = `{
+  "name": "retail",
+  "groups": ["deposits", "fees"],
+  "accounts": [
+    {"code": "1", "name": "Assets", "type": "ASSET"},
+    {"code": "1.1", "name": "Cash", "parent": "1", "alias": "@cash"},
+    {"code": "1.1.01", "name": "Petty cash", "parent": "1.1", "metadata": {"costCenter": "hq"}},
+    {"code": "4", "name": "Revenue", "type": "REVENUE"},
+    {"code": "4.1", "name": "Fee income", "parent": "4", "alias": "@fees"}
+  ]
+}`
+
+func TestParseChart(t *testing.T) {
+	c, err := ParseChart([]byte(testChart))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Petty cash", c.Account("1.1.01").Name)
+	assert.Nil(t, c.Account("9"))
+	assert.Equal(t, "@coa-1-1-01", c.Alias("1.1.01"))
+	assert.Equal(t, "4.1", c.AccountByAlias("@fees").Code)
+	assert.Equal(t, "ASSET", c.Type("1.1.01"))
+	assert.Equal(t, []string{"1", "4"}, c.Children(""))
+	assert.True(t, c.HasGroup("fees"))
+
+	path, err := c.Path("1.1.01")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "1.1", "1.1.01"}, path)
+
+	_, err = c.Path("9")
+	require.ErrorIs(t, err, ErrUnknownCode)
+
+	file := filepath.Join(t.TempDir(), "chart.json")
+	require.NoError(t, os.WriteFile(file, []byte(testChart), 0o600))
+
+	loaded, err := LoadChart(file)
+	require.NoError(t, err)
+	assert.Len(t, loaded.Accounts, 5)
+
+	_, err = ParseChart([]byte(`{"groups": [], "accounts": [], "currency": "BRL"}`))
+	require.Error(t, err, "unknown fields are rejected")
+}
+
+func TestValidate(t *testing.T) {
+	c := &Chart{
+		Groups: []string{"fees", "fees", "bad/group"},
+		Accounts: []Account{
+			{Code: "1", Name: "Assets"},
+			{Code: "1", Name: "Assets again", Type: "ASSET"},
+			{Code: "2", Name: "Cash", Parent: "9", Type: "ASSET"},
+			{Code: "3", Name: "Loop", Parent: "4", Type: "ASSET"},
+			{Code: "4", Name: "Loop", Parent: "3", Type: "ASSET"},
+			{Code: "5", Type: "ASSET", Alias: "@cash"},
+			{Code: "6", Name: "Cash", Type: "ASSET", Alias: "@cash"},
+		},
+	}
+
+	err := c.Validate()
+	require.Error(t, err)
+
+	for _, expected := range []string{
+		`duplicate group "fees"`,
+		"bad/group",
+		"account 1: duplicate code",
+		"account 1: type is required",
+		"parent 9 doesn't exist",
+		"account 3: cyclic hierarchy",
+		"account 5: name is required",
+		"alias @cash already used by account 5",
+	} {
+		assert.Contains(t, err.Error(), expected)
+	}
+}
+
+func transfer(from, to string) *models.CreateTransactionInput {
+	return models.NewCreateTransactionInput("BRL", "10").WithSend(&models.SendInput{
+		Asset: "BRL",
+		Value: "10",
+		Source: &models.SourceInput{From: []models.FromToInput{
+			{Account: from, Amount: models.AmountInput{Asset: "BRL", Value: "10"}},
+		}},
+		Distribute: &models.DistributeInput{To: []models.FromToInput{
+			{Account: to, Amount: models.AmountInput{Asset: "BRL", Value: "10"}},
+		}},
+	})
+}
+
+func TestApply(t *testing.T) {
+	c, err := ParseChart([]byte(testChart))
+	require.NoError(t, err)
+
+	input := transfer("@cash", "@fees")
+	require.NoError(t, c.Apply(input, "fees"))
+	assert.Equal(t, "fees", input.ChartOfAccountsGroupName)
+	assert.Equal(t, "1.1", input.Send.Source.From[0].ChartOfAccounts)
+	assert.Equal(t, "4.1", input.Send.Distribute.To[0].ChartOfAccounts)
+
+	// Accounts outside the chart are left untagged
+	input = transfer("@external/BRL", "@cash")
+	require.NoError(t, c.Apply(input, "deposits"))
+	assert.Empty(t, input.Send.Source.From[0].ChartOfAccounts)
+
+	require.ErrorIs(t, c.Apply(transfer("@cash", "@fees"), "payroll"), ErrUnknownGroup)
+
+	input = transfer("@cash", "@fees")
+	input.Send.Source.From[0].ChartOfAccounts = "7.7"
+	require.ErrorIs(t, c.Apply(input, "fees"), ErrUnknownCode)
+
+	input = transfer("@cash", "@fees")
+	input.ChartOfAccountsGroupName = "default_chart_group"
+	require.ErrorIs(t, c.Check(input), ErrUnknownGroup)
+}
+
+// fakeAccounts holds the accounts of a ledger by alias.
+type fakeAccounts struct {
+	entities.AccountsService
+
+	byAlias map[string]*models.Account
+	inputs  []*models.CreateAccountInput
+}
+
+func (f *fakeAccounts) GetAccountByAlias(_ context.Context, _, _, alias string) (*models.Account, error) {
+	if account, ok := f.byAlias[alias]; ok {
+		return account, nil
+	}
+
+	return nil, sdkerrors.NewNotFoundError("GetAccountByAlias", "account", alias, nil)
+}
+
+func (f *fakeAccounts) CreateAccount(_ context.Context, _, _ string, input *models.CreateAccountInput) (*models.Account, error) {
+	f.inputs = append(f.inputs, input)
+
+	account := &models.Account{ID: "id-" + *input.Alias, Name: input.Name, Alias: input.Alias}
+	f.byAlias[*input.Alias] = account
+
+	return account, nil
+}
+
+func TestProvision(t *testing.T) {
+	c, err := ParseChart([]byte(testChart))
+	require.NoError(t, err)
+
+	accounts := &fakeAccounts{byAlias: map[string]*models.Account{"@cash": {ID: "existing-cash"}}}
+	e := &entities.Entity{Accounts: accounts}
+
+	report, err := c.Provision(context.Background(), e, "org", "ledger", "BRL")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"1", "1.1.01", "4", "4.1"}, report.Created)
+	assert.Equal(t, []string{"1.1"}, report.Existing)
+	assert.Equal(t, "existing-cash", report.AccountIDs["1.1"])
+
+	require.Len(t, accounts.inputs, 4)
+
+	petty := accounts.inputs[1]
+	assert.Equal(t, "Petty cash", petty.Name)
+	assert.Equal(t, "ASSET", petty.Type)
+	assert.Equal(t, "BRL", petty.AssetCode)
+	assert.Equal(t, "existing-cash", *petty.ParentAccountID)
+	assert.Equal(t, map[string]any{"costCenter": "hq", MetadataCode: "1.1.01", MetadataChart: "retail"}, petty.Metadata)
+	assert.Nil(t, accounts.inputs[0].ParentAccountID)
+
+	// Provisioning again finds every account
+	report, err = c.Provision(context.Background(), e, "org", "ledger", "BRL")
+	require.NoError(t, err)
+	assert.Empty(t, report.Created)
+	assert.Len(t, report.Existing, 5)
+
+	_, err = c.Provision(context.Background(), nil, "org", "ledger", "BRL")
+	require.Error(t, err)
+}
@@ -0,0 +1,552 @@
+// Package closing implements the end-of-day closing routine of a ledger.
+//
+// A closing runs the steps ledger operators usually script by hand:
+//
+//  1. freeze: mark the ledger as closing through metadata, so producers that
+//     honor the marker (see IsClosing) stop posting for the business date
+//  2. integrity: run the integrity checks of pkg/integrity and fail on
+//     overdrawn internal accounts
+//  3. trial balance: capture a point-in-time balance snapshot and write it as
+//     a CSV trial balance and as export records
+//  4. statement: export the transactions of the business date
+//  5. report: write a closing report listing every artifact with its digest,
+//     signed with HMAC-SHA256, and mark the ledger as closed
+//
+// The freeze marker is advisory: Midaz does not reject transactions on a
+// closing ledger. A failed closing still writes its signed report and marks the
+// ledger as failed, so the outcome is always recorded.
+//
+// Example:
+//
+//	report, err := closing.NewWorkflow(client.Entity).
+//	    WithSigningKey(key, "eod-2025").
+//	    Run(ctx, orgID, ledgerID, businessDate, "./eod/2025-01-31")
+//
+// RunFS writes to an fsutil.FS instead of a directory, such as an in-memory
+// file system whose artifacts are uploaded once the closing completes.
+package closing
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/export"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/integrity"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/shopspring/decimal"
+)
+
+// Ledger metadata keys written by the closing.
+const (
+	MetadataStatus       = "closingStatus"
+	MetadataBusinessDate = "closingBusinessDate"
+	MetadataFrozenAt     = "closingFrozenAt"
+	MetadataClosedAt     = "closingClosedAt"
+	MetadataReportDigest = "closingReportSha256"
+)
+
+// Closing statuses, stored under MetadataStatus and in the report.
+const (
+	StatusClosing = "closing"
+	StatusClosed  = "closed"
+	StatusFailed  = "failed"
+)
+
+// Step names and statuses of a closing.
+const (
+	StepFreeze       = "freeze"
+	StepIntegrity    = "integrity"
+	StepTrialBalance = "trial_balance"
+	StepStatement    = "statement"
+
+	StepSucceeded = "succeeded"
+	StepFailed    = "failed"
+)
+
+// Files written by the closing, besides ReportFile.
+const (
+	TrialBalanceFile = "trial_balance.csv"
+	statementName    = "statement"
+)
+
+// BusinessDateLayout is the layout of business dates in metadata and reports.
+const BusinessDateLayout = "2006-01-02"
+
+// ErrIntegrityCheckFailed is returned when the integrity step finds overdrawn
+// internal accounts.
+var ErrIntegrityCheckFailed = errors.New("integrity check failed")
+
+// ErrInconsistentSnapshot is returned when balances kept moving while the trial
+// balance was captured and WithRequireConsistentSnapshot is set.
+var ErrInconsistentSnapshot = errors.New("balance snapshot is not consistent")
+
+// IsClosing reports whether the ledger carries a closing marker and should not
+// receive new transactions.
+func IsClosing(ledger *models.Ledger) bool {
+	if ledger == nil {
+		return false
+	}
+
+	status, _ := ledger.Metadata[MetadataStatus].(string)
+
+	return status == StatusClosing
+}
+
+// Workflow runs the end-of-day closing of a ledger.
+type Workflow struct {
+	e                  *entities.Entity
+	key                []byte
+	keyID              string
+	format             export.Format
+	requireConsistency bool
+	snapshotter        *export.BalanceSnapshotter
+	obs                observability.Provider
+	now                func() time.Time
+}
+
+// NewWorkflow creates a Workflow writing NDJSON exports. A signing key must be
+// set with WithSigningKey before running it.
+func NewWorkflow(e *entities.Entity) *Workflow {
+	return &Workflow{
+		e:           e,
+		format:      export.FormatNDJSON,
+		snapshotter: export.NewBalanceSnapshotter(e),
+		now:         time.Now,
+	}
+}
+
+// WithSigningKey sets the HMAC key signing the closing report and an optional
+// identifier recorded next to the signature to support key rotation.
+func (w *Workflow) WithSigningKey(key []byte, keyID string) *Workflow {
+	w.key = key
+	w.keyID = keyID
+
+	return w
+}
+
+// WithFormat sets the format of the balance and statement exports.
+func (w *Workflow) WithFormat(format export.Format) *Workflow {
+	w.format = format
+	return w
+}
+
+// WithRequireConsistentSnapshot fails the closing when balances kept moving
+// while the trial balance was captured.
+func (w *Workflow) WithRequireConsistentSnapshot(require bool) *Workflow {
+	w.requireConsistency = require
+	return w
+}
+
+// WithSnapshotter replaces the balance snapshotter, e.g. to tune its parallelism.
+func (w *Workflow) WithSnapshotter(s *export.BalanceSnapshotter) *Workflow {
+	if s != nil {
+		w.snapshotter = s
+	}
+
+	return w
+}
+
+// WithObservability sets the observability provider for tracing.
+func (w *Workflow) WithObservability(obs observability.Provider) *Workflow {
+	w.obs = obs
+	return w
+}
+
+// Run closes the business date of a ledger, writing every artifact and the signed
+// closing report to dir. The report is returned even when a step fails, together
+// with the error of that step.
+func (w *Workflow) Run(ctx context.Context, orgID, ledgerID string, businessDate time.Time, dir string) (*Report, error) {
+	return w.RunFS(ctx, orgID, ledgerID, businessDate, fsutil.Dir(dir))
+}
+
+// RunFS is Run writing the artifacts and the report at the root of fsys.
+func (w *Workflow) RunFS(ctx context.Context, orgID, ledgerID string, businessDate time.Time, fsys fsutil.FS) (*Report, error) {
+	if w.e == nil || w.e.Ledgers == nil || w.e.Transactions == nil {
+		return nil, errors.New("entities not initialized for closing")
+	}
+
+	if orgID == "" || ledgerID == "" {
+		return nil, errors.New("organization and ledger IDs are required")
+	}
+
+	if len(w.key) == 0 {
+		return nil, errors.New("a signing key is required to sign the closing report")
+	}
+
+	if w.format != export.FormatNDJSON && w.format != export.FormatParquet {
+		return nil, fmt.Errorf("unsupported export format %q", w.format)
+	}
+
+	r := &run{
+		w:    w,
+		org:  orgID,
+		fsys: fsys,
+		date: businessDate.Format(BusinessDateLayout),
+		report: &Report{
+			OrganizationID: orgID,
+			LedgerID:       ledgerID,
+			BusinessDate:   businessDate.Format(BusinessDateLayout),
+			Status:         StatusClosing,
+			StartedAt:      w.now().UTC(),
+			Steps:          []StepResult{},
+			Assets:         map[string]*AssetSummary{},
+		},
+	}
+
+	err := observability.WithSpan(ctx, w.obs, "Closing.Run", func(ctx context.Context) error {
+		return r.execute(ctx, ledgerID)
+	})
+
+	return r.report, err
+}
+
+// run holds the state of a single closing.
+type run struct {
+	w      *Workflow
+	org    string
+	fsys   fsutil.FS
+	date   string
+	ledger *models.Ledger
+	report *Report
+}
+
+func (r *run) execute(ctx context.Context, ledgerID string) error {
+	steps := []struct {
+		name string
+		fn   func(ctx context.Context) error
+	}{
+		{StepFreeze, func(ctx context.Context) error { return r.freeze(ctx, ledgerID) }},
+		{StepIntegrity, r.integrity},
+		{StepTrialBalance, r.trialBalance},
+		{StepStatement, r.statement},
+	}
+
+	var failure error
+
+	for _, step := range steps {
+		started := r.w.now()
+		err := step.fn(ctx)
+
+		result := StepResult{Name: step.name, Status: StepSucceeded, Duration: r.w.now().Sub(started)}
+		if err != nil {
+			result.Status = StepFailed
+			result.Error = err.Error()
+			failure = fmt.Errorf("%s: %w", step.name, err)
+		}
+
+		r.report.Steps = append(r.report.Steps, result)
+
+		if failure != nil {
+			break
+		}
+	}
+
+	r.report.Status = StatusClosed
+	if failure != nil {
+		r.report.Status = StatusFailed
+		r.report.Error = failure.Error()
+	}
+
+	if err := r.finish(ctx); err != nil {
+		return errors.Join(failure, err)
+	}
+
+	return failure
+}
+
+// freeze marks the ledger as closing.
+func (r *run) freeze(ctx context.Context, ledgerID string) error {
+	ledger, err := r.w.e.Ledgers.GetLedger(ctx, r.org, ledgerID)
+	if err != nil {
+		return fmt.Errorf("failed to get ledger: %w", err)
+	}
+
+	r.ledger = ledger
+
+	return r.mark(ctx, map[string]any{
+		MetadataStatus:       StatusClosing,
+		MetadataBusinessDate: r.date,
+		MetadataFrozenAt:     r.w.now().UTC().Format(time.RFC3339),
+	})
+}
+
+// integrity runs the integrity checks and fails on overdrawn internal accounts.
+// External accounts are negative by design and are not reported.
+func (r *run) integrity(ctx context.Context) error {
+	report, err := integrity.NewChecker(r.w.e).WithObservability(r.w.obs).GenerateLedgerReport(ctx, r.org, r.ledger.ID)
+	if err != nil {
+		return err
+	}
+
+	r.report.Integrity = report.ToSummaryMap()
+
+	for _, totals := range report.TotalsByAsset {
+		for _, id := range totals.Overdrawn {
+			if !strings.HasPrefix(id, "@external/") {
+				r.report.Overdrawn = append(r.report.Overdrawn, id)
+			}
+		}
+	}
+
+	if len(r.report.Overdrawn) > 0 {
+		return fmt.Errorf("%w: %d overdrawn accounts", ErrIntegrityCheckFailed, len(r.report.Overdrawn))
+	}
+
+	return nil
+}
+
+// trialBalance captures the balances and writes the trial balance.
+func (r *run) trialBalance(ctx context.Context) error {
+	snap, err := r.w.snapshotter.Capture(ctx, r.org, r.ledger.ID)
+	if err != nil {
+		return err
+	}
+
+	r.report.Snapshot = &SnapshotSummary{
+		TakenAt:     snap.TakenAt,
+		CompletedAt: snap.CompletedAt,
+		Consistent:  snap.Consistent,
+		Accounts:    snap.Accounts,
+		Unsettled:   snap.Unsettled,
+	}
+
+	for asset, t := range snap.Totals {
+		s := r.asset(asset)
+		s.Balances = t.Balances
+		s.Available = t.Available
+		s.OnHold = t.OnHold
+	}
+
+	if err := snap.WriteFS(r.fsys, r.w.format); err != nil {
+		return err
+	}
+
+	if err := writeTrialBalance(r.fsys, TrialBalanceFile, snap.Balances); err != nil {
+		return err
+	}
+
+	for _, name := range []string{TrialBalanceFile, snap.File, export.BalanceSnapshotFile} {
+		if err := r.addArtifact(name); err != nil {
+			return err
+		}
+	}
+
+	if r.w.requireConsistency && !snap.Consistent {
+		return fmt.Errorf("%w: %d accounts still moving", ErrInconsistentSnapshot, len(snap.Unsettled))
+	}
+
+	return nil
+}
+
+// statement exports the transactions of the business date.
+func (r *run) statement(ctx context.Context) error {
+	name := statementName + "." + string(r.w.format)
+
+	out, err := export.CreateRecordWriter(r.fsys, name, r.w.format)
+	if err != nil {
+		return err
+	}
+
+	opts := models.NewListOptions().
+		WithLimit(models.MaxLimit).
+		WithDateRange(r.date, r.date).
+		WithOrderDirection(models.SortAscending)
+
+	err = forEachTransaction(ctx, r.w.e.Transactions, r.org, r.ledger.ID, opts, func(tx models.Transaction) error {
+		rec, err := transactionRecord(tx)
+		if err != nil {
+			return err
+		}
+
+		if err := out.Write(rec); err != nil {
+			return err
+		}
+
+		s := r.asset(tx.AssetCode)
+		s.Transactions++
+
+		if amount, err := decimal.NewFromString(tx.Amount); err == nil {
+			s.Volume = s.Volume.Add(amount)
+		}
+
+		return nil
+	})
+
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return r.addArtifact(name)
+}
+
+// finish writes the signed report and records the outcome on the ledger.
+func (r *run) finish(ctx context.Context) error {
+	r.report.CompletedAt = r.w.now().UTC()
+
+	if err := writeSignedReport(r.fsys, r.report, r.w.key, r.w.keyID); err != nil {
+		return err
+	}
+
+	if r.ledger == nil {
+		return nil
+	}
+
+	digest, err := artifact(r.fsys, ReportFile)
+	if err != nil {
+		return err
+	}
+
+	marker := map[string]any{
+		MetadataStatus:       r.report.Status,
+		MetadataReportDigest: digest.SHA256,
+	}
+
+	if r.report.Status == StatusClosed {
+		marker[MetadataClosedAt] = r.report.CompletedAt.Format(time.RFC3339)
+	}
+
+	return r.mark(ctx, marker)
+}
+
+// mark merges marker into the ledger metadata.
+func (r *run) mark(ctx context.Context, marker map[string]any) error {
+	metadata := make(map[string]any, len(r.ledger.Metadata)+len(marker))
+	for k, v := range r.ledger.Metadata {
+		metadata[k] = v
+	}
+
+	for k, v := range marker {
+		metadata[k] = v
+	}
+
+	input := models.NewUpdateLedgerInput().WithName(r.ledger.Name).WithMetadata(metadata)
+
+	updated, err := r.w.e.Ledgers.UpdateLedger(ctx, r.org, r.ledger.ID, input)
+	if err != nil {
+		return fmt.Errorf("failed to update ledger metadata: %w", err)
+	}
+
+	if updated != nil {
+		r.ledger = updated
+	} else {
+		r.ledger.Metadata = metadata
+	}
+
+	return nil
+}
+
+func (r *run) asset(code string) *AssetSummary {
+	s, ok := r.report.Assets[code]
+	if !ok {
+		s = &AssetSummary{Available: decimal.Zero, OnHold: decimal.Zero, Volume: decimal.Zero}
+		r.report.Assets[code] = s
+	}
+
+	return s
+}
+
+func (r *run) addArtifact(name string) error {
+	a, err := artifact(r.fsys, name)
+	if err != nil {
+		return err
+	}
+
+	r.report.Artifacts = append(r.report.Artifacts, a)
+
+	return nil
+}
+
+// writeTrialBalance writes one CSV row per balance to the file name of fsys.
+func writeTrialBalance(fsys fsutil.FS, name string, balances []models.Balance) error {
+	f, err := fsys.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create trial balance: %w", err)
+	}
+
+	w := csv.NewWriter(f)
+
+	_ = w.Write([]string{"account_id", "alias", "balance_key", "asset_code", "account_type", "available", "on_hold", "total"})
+
+	for _, b := range balances {
+		_ = w.Write([]string{
+			b.AccountID,
+			b.Alias,
+			b.Key,
+			b.AssetCode,
+			b.AccountType,
+			b.Available.String(),
+			b.OnHold.String(),
+			b.Available.Add(b.OnHold).String(),
+		})
+	}
+
+	w.Flush()
+
+	if err := errors.Join(w.Error(), f.Close()); err != nil {
+		return fmt.Errorf("failed to write trial balance: %w", err)
+	}
+
+	return nil
+}
+
+// forEachTransaction calls fn for every transaction listed with opts.
+func forEachTransaction(ctx context.Context, svc entities.TransactionsService, orgID, ledgerID string, opts *models.ListOptions, fn func(models.Transaction) error) error {
+	for opts != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := svc.ListTransactions(ctx, orgID, ledgerID, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list transactions: %w", err)
+		}
+
+		for _, tx := range page.Items {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+
+		if len(page.Items) == 0 {
+			return nil
+		}
+
+		next := page.Pagination.NextPageOptions()
+		if next == nil {
+			return nil
+		}
+
+		// next page options only carry the position
+		opts = next.WithDateRange(opts.StartDate, opts.EndDate).WithOrderDirection(models.SortDirection(opts.OrderDirection))
+	}
+
+	return nil
+}
+
+// transactionRecord wraps a transaction as an export record.
+func transactionRecord(tx models.Transaction) (export.Record, error) {
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return export.Record{}, fmt.Errorf("failed to encode transaction %s: %w", tx.ID, err)
+	}
+
+	return export.Record{
+		SchemaVersion: export.SchemaVersion,
+		Kind:          export.KindTransaction,
+		ID:            tx.ID,
+		LedgerID:      tx.LedgerID,
+		Data:          data,
+	}, nil
+}
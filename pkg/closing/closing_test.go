@@ -0,0 +1,296 @@
+package closing
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/export"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var signingKey = []byte("test-signing-key")
+
+func page[T any](items []T, opts *models.ListOptions) *models.ListResponse[T] {
+	start := min(opts.Offset, len(items))
+	end := min(start+opts.Limit, len(items))
+
+	return &models.ListResponse[T]{
+		Items:      items[start:end],
+		Pagination: models.Pagination{Limit: opts.Limit, Offset: start, Total: len(items)},
+	}
+}
+
+type fakeLedgers struct {
+	entities.LedgersService
+
+	ledger  models.Ledger
+	updates []map[string]any
+}
+
+func (f *fakeLedgers) GetLedger(_ context.Context, _, _ string) (*models.Ledger, error) {
+	l := f.ledger
+	return &l, nil
+}
+
+func (f *fakeLedgers) UpdateLedger(_ context.Context, _, _ string, input *models.UpdateLedgerInput) (*models.Ledger, error) {
+	f.updates = append(f.updates, input.Metadata)
+	f.ledger.Metadata = input.Metadata
+
+	l := f.ledger
+
+	return &l, nil
+}
+
+type fakeAccounts struct {
+	entities.AccountsService
+
+	accounts []models.Account
+}
+
+func (f *fakeAccounts) ListAccounts(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+	return page(f.accounts, opts), nil
+}
+
+func (f *fakeAccounts) GetAccount(_ context.Context, _, _, id string) (*models.Account, error) {
+	for _, a := range f.accounts {
+		if a.ID == id {
+			return &a, nil
+		}
+	}
+
+	return &models.Account{ID: id}, nil
+}
+
+type fakeBalances struct {
+	entities.BalancesService
+
+	balances []models.Balance
+}
+
+func (f *fakeBalances) ListBalances(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	return page(f.balances, opts), nil
+}
+
+func (f *fakeBalances) ListAccountBalances(_ context.Context, _, _, accountID string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	var out []models.Balance
+
+	for _, b := range f.balances {
+		if b.AccountID == accountID {
+			out = append(out, b)
+		}
+	}
+
+	return page(out, opts), nil
+}
+
+type fakeTransactions struct {
+	entities.TransactionsService
+
+	transactions []models.Transaction
+	opts         []*models.ListOptions
+}
+
+func (f *fakeTransactions) ListTransactions(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Transaction], error) {
+	f.opts = append(f.opts, opts)
+	return page(f.transactions, opts), nil
+}
+
+type fixture struct {
+	ledgers      *fakeLedgers
+	accounts     *fakeAccounts
+	balances     *fakeBalances
+	transactions *fakeTransactions
+}
+
+func newFixture(aliceAvailable string) *fixture {
+	alice, bob, external := "@alice", "@bob", "@external/USD"
+	updated := time.Now().Add(-time.Hour)
+	balance := func(id, account, alias, available string) models.Balance {
+		return models.Balance{
+			ID: id, AccountID: account, Alias: alias, Key: "default", AssetCode: "USD", Version: 1, UpdatedAt: updated,
+			Available: decimal.RequireFromString(available), OnHold: decimal.Zero,
+		}
+	}
+
+	txs := make([]models.Transaction, 0, 120)
+	for i := 0; i < 120; i++ {
+		txs = append(txs, models.Transaction{ID: "tx", LedgerID: "ledger-1", AssetCode: "USD", Amount: "1.5"})
+	}
+
+	return &fixture{
+		ledgers: &fakeLedgers{ledger: models.Ledger{ID: "ledger-1", Name: "Main", Metadata: map[string]any{"region": "eu"}}},
+		accounts: &fakeAccounts{accounts: []models.Account{
+			{ID: "a1", Alias: &alice}, {ID: "a2", Alias: &bob}, {ID: "ext", Alias: &external},
+		}},
+		balances: &fakeBalances{balances: []models.Balance{
+			balance("b1", "a1", alice, aliceAvailable),
+			balance("b2", "a2", bob, "30"),
+			balance("b3", "ext", external, "-100"),
+		}},
+		transactions: &fakeTransactions{transactions: txs},
+	}
+}
+
+func (f *fixture) entity() *entities.Entity {
+	return &entities.Entity{Ledgers: f.ledgers, Accounts: f.accounts, Balances: f.balances, Transactions: f.transactions}
+}
+
+var businessDate = time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+func TestWorkflow_Run(t *testing.T) {
+	f := newFixture("70")
+	dir := t.TempDir()
+
+	report, err := NewWorkflow(f.entity()).WithSigningKey(signingKey, "k1").Run(context.Background(), "org", "ledger-1", businessDate, dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, StatusClosed, report.Status)
+	assert.Equal(t, "2025-01-31", report.BusinessDate)
+	require.Len(t, report.Steps, 4)
+
+	for _, s := range report.Steps {
+		assert.Equal(t, StepSucceeded, s.Status, s.Name)
+	}
+
+	assert.Empty(t, report.Overdrawn, "external accounts are negative by design")
+	assert.True(t, report.Snapshot.Consistent)
+
+	usd := report.Assets["USD"]
+	require.NotNil(t, usd)
+	assert.Equal(t, "0", usd.Available.String())
+	assert.Equal(t, 120, usd.Transactions)
+	assert.Equal(t, "180", usd.Volume.String())
+
+	// the statement covers the business date across pages
+	require.Len(t, f.transactions.opts, 2)
+	assert.Equal(t, "2025-01-31", f.transactions.opts[1].StartDate)
+	assert.Equal(t, "2025-01-31", f.transactions.opts[1].EndDate)
+
+	// the ledger is frozen, then closed, keeping its metadata
+	require.Len(t, f.ledgers.updates, 2)
+	assert.Equal(t, StatusClosing, f.ledgers.updates[0][MetadataStatus])
+	assert.Equal(t, "2025-01-31", f.ledgers.updates[0][MetadataBusinessDate])
+	assert.Equal(t, StatusClosed, f.ledgers.updates[1][MetadataStatus])
+	assert.Equal(t, "eu", f.ledgers.updates[1]["region"])
+	assert.False(t, IsClosing(&f.ledgers.ledger))
+
+	names := make([]string, 0, len(report.Artifacts))
+	for _, a := range report.Artifacts {
+		names = append(names, a.Name)
+		assert.Len(t, a.SHA256, 64)
+	}
+
+	assert.ElementsMatch(t, []string{TrialBalanceFile, "balances.ndjson", export.BalanceSnapshotFile, "statement.ndjson"}, names)
+
+	rows := readCSV(t, filepath.Join(dir, TrialBalanceFile))
+	require.Len(t, rows, 4)
+	assert.Equal(t, []string{"a1", "@alice", "default", "USD", "", "70", "0", "70"}, rows[1])
+
+	verified, sig, err := VerifyReport(filepath.Join(dir, ReportFile), signingKey)
+	require.NoError(t, err)
+	assert.Equal(t, "k1", sig.KeyID)
+	assert.Equal(t, report.Status, verified.Status)
+	assert.Len(t, verified.Artifacts, 4)
+
+	digest, err := artifact(os.DirFS(dir), ReportFile)
+	require.NoError(t, err)
+	assert.Equal(t, digest.SHA256, f.ledgers.updates[1][MetadataReportDigest])
+}
+
+func TestWorkflow_RunFS(t *testing.T) {
+	fsys := fsutil.NewMapFS()
+
+	report, err := NewWorkflow(newFixture("70").entity()).WithSigningKey(signingKey, "k1").RunFS(context.Background(), "org", "ledger-1", businessDate, fsys)
+	require.NoError(t, err)
+	assert.Equal(t, StatusClosed, report.Status)
+
+	assert.Equal(t, []string{export.BalanceSnapshotFile, "balances.ndjson", ReportFile, "statement.ndjson", TrialBalanceFile}, fsys.Names())
+
+	verified, _, err := VerifyReportFS(fsys, ReportFile, signingKey)
+	require.NoError(t, err)
+	assert.Len(t, verified.Artifacts, 4)
+}
+
+func TestWorkflow_IntegrityFailure(t *testing.T) {
+	f := newFixture("-5")
+	dir := t.TempDir()
+
+	report, err := NewWorkflow(f.entity()).WithSigningKey(signingKey, "").Run(context.Background(), "org", "ledger-1", businessDate, dir)
+	require.ErrorIs(t, err, ErrIntegrityCheckFailed)
+	require.NotNil(t, report)
+
+	assert.Equal(t, StatusFailed, report.Status)
+	assert.Equal(t, []string{"@alice"}, report.Overdrawn)
+	require.Len(t, report.Steps, 2)
+	assert.Equal(t, StepFailed, report.Steps[1].Status)
+	assert.Empty(t, f.transactions.opts, "later steps do not run")
+
+	// the failure is still recorded and signed
+	verified, _, err := VerifyReport(filepath.Join(dir, ReportFile), signingKey)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, verified.Status)
+	assert.Equal(t, StatusFailed, f.ledgers.ledger.Metadata[MetadataStatus])
+}
+
+func TestVerifyReport_Tampered(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewWorkflow(newFixture("70").entity()).WithSigningKey(signingKey, "").Run(context.Background(), "org", "ledger-1", businessDate, dir)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, ReportFile)
+
+	_, _, err = VerifyReport(path, []byte("another key"))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	tampered := bytes.Replace(raw, []byte(`"closed"`), []byte(`"opened"`), 1)
+	require.NotEqual(t, raw, tampered)
+	require.NoError(t, os.WriteFile(path, tampered, 0o600))
+
+	_, _, err = VerifyReport(path, signingKey)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestWorkflow_Validation(t *testing.T) {
+	f := newFixture("70")
+	ctx := context.Background()
+
+	_, err := NewWorkflow(f.entity()).Run(ctx, "org", "ledger-1", businessDate, t.TempDir())
+	assert.ErrorContains(t, err, "signing key")
+
+	_, err = NewWorkflow(f.entity()).WithSigningKey(signingKey, "").WithFormat("csv").Run(ctx, "org", "ledger-1", businessDate, t.TempDir())
+	assert.ErrorContains(t, err, "unsupported export format")
+
+	_, err = NewWorkflow(&entities.Entity{}).WithSigningKey(signingKey, "").Run(ctx, "org", "ledger-1", businessDate, t.TempDir())
+	assert.Error(t, err)
+
+	assert.False(t, IsClosing(nil))
+	assert.True(t, IsClosing(&models.Ledger{Metadata: map[string]any{MetadataStatus: StatusClosing}}))
+}
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+
+	return rows
+}
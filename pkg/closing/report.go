@@ -0,0 +1,198 @@
+package closing
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+	"github.com/shopspring/decimal"
+)
+
+// ReportFile is the name of the signed closing report written to the output directory.
+const ReportFile = "closing_report.json"
+
+// SignatureAlgorithm is the algorithm used to sign closing reports.
+const SignatureAlgorithm = "HMAC-SHA256"
+
+// ErrInvalidSignature is returned by VerifyReport when a report does not match its signature.
+var ErrInvalidSignature = errors.New("closing report signature mismatch")
+
+// Artifact is a file produced by the closing, identified by its digest.
+type Artifact struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// AssetSummary aggregates the closing figures of an asset.
+type AssetSummary struct {
+	Balances     int             `json:"balances"`
+	Available    decimal.Decimal `json:"available"`
+	OnHold       decimal.Decimal `json:"onHold"`
+	Transactions int             `json:"transactions"`
+	Volume       decimal.Decimal `json:"volume"`
+}
+
+// Report records the outcome of a closing.
+type Report struct {
+	OrganizationID string                    `json:"organizationId"`
+	LedgerID       string                    `json:"ledgerId"`
+	BusinessDate   string                    `json:"businessDate"`
+	Status         string                    `json:"status"`
+	Error          string                    `json:"error,omitempty"`
+	StartedAt      time.Time                 `json:"startedAt"`
+	CompletedAt    time.Time                 `json:"completedAt"`
+	Steps          []StepResult              `json:"steps"`
+	Integrity      map[string]map[string]any `json:"integrity,omitempty"`
+	Overdrawn      []string                  `json:"overdrawn,omitempty"`
+	Snapshot       *SnapshotSummary          `json:"snapshot,omitempty"`
+	Assets         map[string]*AssetSummary  `json:"assets,omitempty"`
+	Artifacts      []Artifact                `json:"artifacts,omitempty"`
+}
+
+// StepResult is the outcome of a closing step.
+type StepResult struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// SnapshotSummary describes the balance snapshot behind the trial balance.
+type SnapshotSummary struct {
+	TakenAt     time.Time `json:"takenAt"`
+	CompletedAt time.Time `json:"completedAt"`
+	Consistent  bool      `json:"consistent"`
+	Accounts    int       `json:"accounts"`
+	Unsettled   []string  `json:"unsettled,omitempty"`
+}
+
+// Signature authenticates a closing report.
+type Signature struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"keyId,omitempty"`
+	Value     string `json:"value"`
+}
+
+// signedReport is the layout of ReportFile. The report is kept as raw bytes so
+// the signature covers exactly what was written.
+type signedReport struct {
+	Report    json.RawMessage `json:"report"`
+	Signature Signature       `json:"signature"`
+}
+
+// writeSignedReport signs r with key and writes it to fsys.
+func writeSignedReport(fsys fsutil.FS, r *Report, key []byte, keyID string) error {
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode closing report: %w", err)
+	}
+
+	out, err := json.MarshalIndent(signedReport{
+		Report:    raw,
+		Signature: Signature{Algorithm: SignatureAlgorithm, KeyID: keyID, Value: sign(raw, key)},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode closing report: %w", err)
+	}
+
+	if err := fsutil.WriteFile(fsys, ReportFile, out); err != nil {
+		return fmt.Errorf("failed to write closing report: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyReport reads the signed closing report at path and checks its signature
+// against key, returning the report and its signature when they match.
+func VerifyReport(path string, key []byte) (*Report, *Signature, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- path is chosen by the caller
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read closing report: %w", err)
+	}
+
+	return verifyReport(raw, key)
+}
+
+// VerifyReportFS is VerifyReport reading the report name of fsys, such as
+// ReportFile.
+func VerifyReportFS(fsys fs.FS, name string, key []byte) (*Report, *Signature, error) {
+	raw, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read closing report: %w", err)
+	}
+
+	return verifyReport(raw, key)
+}
+
+// verifyReport checks the signature of the signed report raw against key.
+func verifyReport(raw, key []byte) (*Report, *Signature, error) {
+	var signed signedReport
+	if err := json.Unmarshal(raw, &signed); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse closing report: %w", err)
+	}
+
+	if signed.Signature.Algorithm != SignatureAlgorithm {
+		return nil, nil, fmt.Errorf("unsupported signature algorithm %q", signed.Signature.Algorithm)
+	}
+
+	// MarshalIndent reindents the raw report; compact it back to the signed bytes
+	compact, err := compactJSON(signed.Report)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !hmac.Equal([]byte(sign(compact, key)), []byte(signed.Signature.Value)) {
+		return nil, nil, ErrInvalidSignature
+	}
+
+	var r Report
+	if err := json.Unmarshal(compact, &r); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse closing report: %w", err)
+	}
+
+	return &r, &signed.Signature, nil
+}
+
+func sign(data, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func compactJSON(raw []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return nil, fmt.Errorf("failed to parse closing report: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// artifact computes the digest of the file name of fsys.
+func artifact(fsys fs.FS, name string) (Artifact, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to open artifact %s: %w", name, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to hash artifact %s: %w", name, err)
+	}
+
+	return Artifact{Name: name, SHA256: hex.EncodeToString(h.Sum(nil)), Size: n}, nil
+}
@@ -3,8 +3,11 @@ package concurrent
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/events"
 )
 
 // ErrCircuitOpen is returned when the circuit breaker is in open state
@@ -37,6 +40,7 @@ type CircuitBreaker struct {
 	openTimeout      time.Duration
 	name             string
 	logger           CBLogger
+	events           events.Sink
 }
 
 // Default circuit breaker configuration values.
@@ -83,18 +87,46 @@ func (cb *CircuitBreaker) WithLogger(l CBLogger) *CircuitBreaker {
 	return cb
 }
 
+// WithEvents attaches a sink receiving an events.CircuitBreakerOpened event
+// each time the circuit opens.
+func (cb *CircuitBreaker) WithEvents(sink events.Sink) *CircuitBreaker {
+	cb.events = sink
+	return cb
+}
+
 // Execute runs fn under circuit breaker control.
-func (cb *CircuitBreaker) Execute(_ context.Context, fn func() error) error {
+func (cb *CircuitBreaker) Execute(ctx context.Context, fn func() error) error {
 	if !cb.canProceed() {
 		return ErrCircuitOpen
 	}
 
 	err := fn()
-	cb.after(err)
+	if failures, opened := cb.after(err); opened {
+		// Emitted outside the lock, as a sink may be slow
+		cb.emitOpened(ctx, failures, err)
+	}
 
 	return err
 }
 
+func (cb *CircuitBreaker) emitOpened(ctx context.Context, failures int, err error) {
+	if cb.events == nil {
+		return
+	}
+
+	events.Emit(context.WithoutCancel(ctx), cb.events, events.Event{
+		Type:     events.CircuitBreakerOpened,
+		Severity: events.SeverityError,
+		Source:   cb.name,
+		Message:  fmt.Sprintf("circuit '%s' opened after %d failures", cb.name, failures),
+		Attributes: map[string]any{
+			"failures":     failures,
+			"open_timeout": cb.openTimeout.String(),
+			"last_error":   err.Error(),
+		},
+	})
+}
+
 // canProceed determines if the circuit breaker will allow the operation to proceed.
 // Returns true if circuit is closed or half-open (allowing probe), false if circuit is open.
 func (cb *CircuitBreaker) canProceed() bool {
@@ -121,7 +153,9 @@ func (cb *CircuitBreaker) canProceed() bool {
 	}
 }
 
-func (cb *CircuitBreaker) after(err error) {
+// after records the outcome of an operation. It reports whether the circuit
+// opened, along with the failure count that opened it.
+func (cb *CircuitBreaker) after(err error) (failures int, opened bool) {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
@@ -137,7 +171,7 @@ func (cb *CircuitBreaker) after(err error) {
 			cb.failureCount = 0
 		}
 
-		return
+		return 0, false
 	}
 
 	// On error
@@ -146,11 +180,15 @@ func (cb *CircuitBreaker) after(err error) {
 		cb.failureCount++
 		if cb.failureCount >= cb.failureThreshold {
 			cb.open()
+			return cb.failureCount, true
 		}
 	case halfOpen:
 		// Any failure during half-open sends back to open
 		cb.open()
+		return cb.failureCount, true
 	}
+
+	return cb.failureCount, false
 }
 
 func (cb *CircuitBreaker) open() {
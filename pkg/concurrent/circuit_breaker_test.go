@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/events"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -183,6 +184,34 @@ func TestCircuitBreakerWithLogger(t *testing.T) {
 	})
 }
 
+func TestCircuitBreakerWithEvents(t *testing.T) {
+	var received []events.Event
+
+	sink := events.SinkFunc(func(_ context.Context, e events.Event) error {
+		received = append(received, e)
+		return nil
+	})
+
+	cb := NewCircuitBreakerNamed("ledger-api", 2, 1, 50*time.Millisecond).WithEvents(sink)
+
+	failFn := func() error { return errors.New("fail") }
+	_ = cb.Execute(context.Background(), failFn)
+	assert.Empty(t, received)
+
+	_ = cb.Execute(context.Background(), failFn)
+	require.Len(t, received, 1)
+	assert.Equal(t, events.CircuitBreakerOpened, received[0].Type)
+	assert.Equal(t, "ledger-api", received[0].Source)
+	assert.Equal(t, 2, received[0].Attributes["failures"])
+	assert.Equal(t, "fail", received[0].Attributes["last_error"])
+
+	// A failed probe while half-open opens the circuit again
+	time.Sleep(60 * time.Millisecond)
+
+	_ = cb.Execute(context.Background(), failFn)
+	assert.Len(t, received, 2)
+}
+
 func TestCircuitBreakerClosedState(t *testing.T) {
 	t.Run("SuccessResetsFailureCount", func(t *testing.T) {
 		cb := NewCircuitBreaker(3, 1, 100*time.Millisecond)
@@ -75,10 +75,45 @@ package concurrent
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
 	"sync"
 	"time"
+
+	pkgerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrMissingItemResult is the error of items for which a BatchItems work
+// function returned no result.
+var ErrMissingItemResult = errors.New("batch returned no result for item")
+
+// MetricWorkerPanicTotal counts the work function panics recovered by worker pools.
+const MetricWorkerPanicTotal = "midaz.sdk.concurrent.worker.panic.total"
+
+// PanicError describes a panic recovered from a work function. The Result of
+// the item carries it wrapped in an internal error of the errors package; use
+// errors.As to retrieve it.
+type PanicError struct {
+	// Index is the index of the item whose processing panicked.
+	Index int
+
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("work function panicked on item %d: %v", e.Index, e.Value)
+}
+
 // WorkFunc is a generic worker function that processes an item and returns a result and error.
 type WorkFunc[T, R any] func(ctx context.Context, item T) (R, error)
 
@@ -120,6 +155,28 @@ type Result[T, R any] struct {
 // Returns:
 //   - []Result: A slice of results, in the same order as the input items unless WithUnorderedResults is used.
 //
+// Memory: the results are all held until the pool returns, one Result per
+// item. For runs whose results don't fit in memory, use WorkerPoolStream.
+//
+// Crash isolation: a panic in workFn is recovered and fails only its item, with
+// an internal error wrapping a *PanicError. The other items are processed as
+// usual. Each recovered panic increments MetricWorkerPanicTotal and is passed
+// to the handler set with WithPanicHandler.
+//
+// Cancellation: once ctx is done, no further item is started. Every item not
+// started gets a Result whose Error is a cancellation error of the errors
+// package (category errors.CategoryCancellation, wrapping ctx.Err()), so the
+// results always hold one entry per item and report the partial outcome.
+// Items in flight see their context canceled, unless WithDrainOnCancel is
+// used.
+//
+// Context propagation: every call of workFn receives a context derived from
+// ctx, never a fresh one. Its deadline and cancellation, its OpenTelemetry span
+// and baggage, the observability provider set with observability.WithProvider
+// and any other value of ctx are therefore visible to workFn. WithItemTimeout,
+// WithItemSpan and WithItemContext derive a per-item context from it, which is
+// canceled as soon as workFn returns.
+//
 // Example use case: Processing thousands of account validation requests in parallel:
 //
 //	accountIDs := fetchAccountsToValidate() // e.g., 10,000 account IDs
@@ -149,7 +206,7 @@ func WorkerPool[T, R any](
 	startItemSender(ctx, &wg, items, itemCh, resultCh)
 
 	// Collect and return results
-	return collectResults(resultCh, len(items), options.ordered)
+	return collectResults(ctx, items, resultCh, options.ordered)
 }
 
 // applyPoolOptions applies all pool options to create configuration
@@ -198,7 +255,17 @@ func runWorker[T, R any](
 			return
 		}
 
-		result := processWorkItem(ctx, item, workFn)
+		if options.limiter != nil {
+			if err := options.limiter.Wait(ctx); err != nil {
+				if ctx.Err() == nil {
+					resultCh <- Result[T, R]{Item: item.value, Error: err, Index: item.index}
+				}
+
+				continue
+			}
+		}
+
+		result := processWorkItem(ctx, item, workFn, options)
 		resultCh <- result
 	}
 }
@@ -219,8 +286,14 @@ func shouldApplyRateLimit(ctx context.Context, rateLimit int) bool {
 }
 
 // processWorkItem executes the work function for a single item
-func processWorkItem[T, R any](ctx context.Context, item indexedItem[T], workFn WorkFunc[T, R]) Result[T, R] {
-	result, err := workFn(ctx, item.value)
+func processWorkItem[T, R any](ctx context.Context, item indexedItem[T], workFn WorkFunc[T, R], options *poolOptions) Result[T, R] {
+	if options.drainOnCancel {
+		ctx = context.WithoutCancel(ctx)
+	}
+
+	itemCtx, done := deriveItemContext(ctx, item.index, options)
+	result, err := callWorkFn(itemCtx, item, workFn, options)
+	done(err)
 
 	return Result[T, R]{
 		Item:  item.value,
@@ -230,6 +303,71 @@ func processWorkItem[T, R any](ctx context.Context, item indexedItem[T], workFn
 	}
 }
 
+// callWorkFn calls workFn, converting a panic into the error of the item.
+func callWorkFn[T, R any](ctx context.Context, item indexedItem[T], workFn WorkFunc[T, R], options *poolOptions) (result R, err error) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		panicErr := &PanicError{Index: item.index, Value: recovered, Stack: debug.Stack()}
+		err = pkgerrors.NewInternalError("WorkerPool", panicErr)
+
+		observability.RecordMetric(ctx, observability.GetProvider(ctx), MetricWorkerPanicTotal, 1)
+
+		if options.panicHandler != nil {
+			options.panicHandler(ctx, panicErr)
+		}
+	}()
+
+	return workFn(ctx, item.value)
+}
+
+// deriveItemContext returns the context of an item and the function releasing
+// it once the item is processed. Without item options ctx is returned as is.
+func deriveItemContext(ctx context.Context, index int, options *poolOptions) (context.Context, func(error)) {
+	var cancels []context.CancelFunc
+
+	if options.itemContext != nil {
+		itemCtx, cancel := options.itemContext(ctx, index)
+		if itemCtx != nil {
+			ctx = itemCtx
+		}
+
+		if cancel != nil {
+			cancels = append(cancels, cancel)
+		}
+	}
+
+	var span trace.Span
+	if options.itemSpan != "" {
+		ctx, span = observability.Start(ctx, options.itemSpan, trace.WithAttributes(attribute.Int("concurrent.item_index", index)))
+	}
+
+	if options.itemTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, options.itemTimeout)
+		cancels = append(cancels, cancel)
+	}
+
+	return ctx, func(err error) {
+		if span != nil {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			span.End()
+		}
+
+		for i := len(cancels) - 1; i >= 0; i-- {
+			cancels[i]()
+		}
+	}
+}
+
 // startItemSender creates a goroutine to send items to workers and manage cleanup
 func startItemSender[T, R any](
 	ctx context.Context,
@@ -262,13 +400,17 @@ func sendItemsToWorkers[T any](ctx context.Context, items []T, itemCh chan<- ind
 	}
 }
 
-// collectResults gathers results from workers, maintaining order if requested
-func collectResults[T, R any](resultCh <-chan Result[T, R], itemCount int, ordered bool) []Result[T, R] {
+// collectResults gathers results from workers, maintaining order if requested,
+// and adds a cancellation result for each item not started
+func collectResults[T, R any](ctx context.Context, items []T, resultCh <-chan Result[T, R], ordered bool) []Result[T, R] {
+	var results []Result[T, R]
 	if ordered {
-		return collectOrderedResults(resultCh, itemCount)
+		results = collectOrderedResults(resultCh, len(items))
+	} else {
+		results = collectUnorderedResults(resultCh, len(items))
 	}
 
-	return collectUnorderedResults(resultCh, itemCount)
+	return addCancelledResults(ctx, items, results, ordered)
 }
 
 // collectOrderedResults collects results and returns them in original order
@@ -278,8 +420,11 @@ func collectOrderedResults[T, R any](resultCh <-chan Result[T, R], itemCount int
 		allResults = append(allResults, r)
 	}
 
-	// Create ordered results slice
+	// Create ordered results slice, with Index -1 marking the items without result
 	results := make([]Result[T, R], itemCount)
+	for i := range results {
+		results[i].Index = -1
+	}
 
 	for _, r := range allResults {
 		results[r.Index] = r
@@ -298,6 +443,47 @@ func collectUnorderedResults[T, R any](resultCh <-chan Result[T, R], itemCount i
 	return results
 }
 
+// addCancelledResults gives the items without result, which were never
+// started because ctx was done, a cancellation error. Unordered results get
+// them appended, in input order.
+func addCancelledResults[T, R any](ctx context.Context, items []T, results []Result[T, R], ordered bool) []Result[T, R] {
+	if ordered {
+		for i := range results {
+			if results[i].Index == -1 {
+				results[i] = cancelledResult[T, R](ctx, items[i], i)
+			}
+		}
+
+		return results
+	}
+
+	if len(results) == len(items) {
+		return results
+	}
+
+	seen := make([]bool, len(items))
+	for _, r := range results {
+		seen[r.Index] = true
+	}
+
+	for i, item := range items {
+		if !seen[i] {
+			results = append(results, cancelledResult[T, R](ctx, item, i))
+		}
+	}
+
+	return results
+}
+
+// cancelledResult is the result of an item not started because ctx was done.
+func cancelledResult[T, R any](ctx context.Context, item T, index int) Result[T, R] {
+	return Result[T, R]{
+		Item:  item,
+		Error: pkgerrors.NewCancellationError("WorkerPool", context.Cause(ctx)),
+		Index: index,
+	}
+}
+
 // indexedItem holds a value and its index in the original slice.
 type indexedItem[T any] struct {
 	value T
@@ -317,6 +503,24 @@ type poolOptions struct {
 
 	// rateLimit is the maximum number of operations per second.
 	rateLimit int
+
+	// itemTimeout bounds the duration of each work function call.
+	itemTimeout time.Duration
+
+	// itemSpan names the span started for each item; empty disables item spans.
+	itemSpan string
+
+	// itemContext derives the context of each item.
+	itemContext func(ctx context.Context, index int) (context.Context, context.CancelFunc)
+
+	// limiter paces the items, shared by all workers.
+	limiter Limiter
+
+	// panicHandler is called with each panic recovered from the work function.
+	panicHandler func(ctx context.Context, err *PanicError)
+
+	// drainOnCancel lets the work functions in flight complete once the pool context is done.
+	drainOnCancel bool
 }
 
 // PoolOption is a function that modifies pool options.
@@ -400,6 +604,71 @@ func WithRateLimit(operationsPerSecond int) PoolOption {
 	}
 }
 
+// WithLimiter paces the items with limiter, shared by all workers, e.g. a
+// GCRALimiter for evenly spaced operations. Unlike WithRateLimit, the limiter
+// can be shared between pools. An item the limiter rejects, such as with
+// ErrBucketFull, fails with the error of the limiter.
+func WithLimiter(limiter Limiter) PoolOption {
+	return func(o *poolOptions) {
+		o.limiter = limiter
+	}
+}
+
+// WithItemTimeout bounds each work function call to d, in addition to the
+// deadline of the pool context. An item exceeding it sees its context canceled
+// with context.DeadlineExceeded, without affecting the other items.
+//
+// Example use case: When a single slow API call must not hold a worker for the whole run:
+//
+//	concurrent.WithItemTimeout(5 * time.Second)
+func WithItemTimeout(d time.Duration) PoolOption {
+	return func(o *poolOptions) {
+		if d > 0 {
+			o.itemTimeout = d
+		}
+	}
+}
+
+// WithItemSpan starts a span named name for each item, as a child of the span
+// of the pool context, using the observability provider carried by the context.
+// The span records the item index and the error returned by the work function.
+func WithItemSpan(name string) PoolOption {
+	return func(o *poolOptions) {
+		o.itemSpan = name
+	}
+}
+
+// WithItemContext derives the context of each item with fn, e.g. to attach the
+// item index to a logger or baggage. The context passed to fn is the pool
+// context; the returned cancel function, if not nil, is called once the item
+// is processed.
+func WithItemContext(fn func(ctx context.Context, index int) (context.Context, context.CancelFunc)) PoolOption {
+	return func(o *poolOptions) {
+		o.itemContext = fn
+	}
+}
+
+// WithPanicHandler sets a function called with every panic recovered from the
+// work function, e.g. to log its stack trace or report it to a crash tracker.
+// It runs on the worker goroutine, with the context of the item.
+func WithPanicHandler(fn func(ctx context.Context, err *PanicError)) PoolOption {
+	return func(o *poolOptions) {
+		o.panicHandler = fn
+	}
+}
+
+// WithDrainOnCancel lets the items in flight complete when the pool context
+// is done, instead of canceling their context: their work function gets a
+// context that keeps the values of the pool context but is never canceled by
+// it, so a write already sent isn't abandoned halfway. Items not yet started
+// are still skipped with a cancellation error. Combine it with WithItemTimeout
+// to bound how long draining can take.
+func WithDrainOnCancel() PoolOption {
+	return func(o *poolOptions) {
+		o.drainOnCancel = true
+	}
+}
+
 // Batch processes items in batches using a worker pool, useful for
 // processing large volumes of data while respecting API rate limits.
 //
@@ -412,6 +681,9 @@ func WithRateLimit(operationsPerSecond int) PoolOption {
 //
 // Returns:
 //   - []Result: A slice of results, in the same order as the input items.
+//     The items of the batches not started because ctx was done fail with a
+//     cancellation error, as in WorkerPool. The results are all held until
+//     Batch returns; BatchStream hands them over as they come instead.
 func Batch[T, R any](
 	ctx context.Context,
 	items []T,
@@ -425,16 +697,7 @@ func Batch[T, R any](
 	}
 
 	// Create batches
-	var batches [][]T
-
-	for i := 0; i < len(items); i += batchSize {
-		end := i + batchSize
-		if end > len(items) {
-			end = len(items)
-		}
-
-		batches = append(batches, items[i:end])
-	}
+	batches := splitBatches(items, batchSize)
 
 	// Process batches concurrently using worker pool
 	batchResults := WorkerPool(ctx, batches, func(ctx context.Context, batch []T) ([]R, error) {
@@ -445,32 +708,82 @@ func Batch[T, R any](
 	var results []Result[T, R]
 
 	for _, br := range batchResults {
-		// If there was an error in batch processing, apply it to all items in the batch
-		if br.Error != nil {
-			for i, item := range br.Item {
-				results = append(results, Result[T, R]{
-					Item:  item,
-					Error: br.Error,
-					Index: br.Index*batchSize + i,
-				})
-			}
-		} else if len(br.Value) > 0 {
-			// If there are results, map them back to the original items
-			for i, val := range br.Value {
-				if i < len(br.Item) {
-					results = append(results, Result[T, R]{
-						Item:  br.Item[i],
-						Value: val,
-						Index: br.Index*batchSize + i,
-					})
-				}
-			}
-		}
+		_ = eachBatchResult(br, batchSize, func(r Result[T, R]) error {
+			results = append(results, r)
+			return nil
+		})
 	}
 
 	return results
 }
 
+// ItemResult is the outcome of a single item of a batch processed by BatchItems.
+type ItemResult[R any] struct {
+	Value R
+	Error error
+}
+
+// BatchItems processes items in batches like Batch, but the work function
+// reports an outcome per item, so one bad element doesn't fail the other items
+// of its batch. This matches APIs that accept a batch and report partial failures.
+//
+// The work function returns one ItemResult per item of the batch, in the same
+// order. An error returned by the work function itself (e.g. a network failure
+// before any item was processed) is applied to every item of the batch, and
+// items without a corresponding ItemResult fail with ErrMissingItemResult.
+//
+// Parameters:
+//   - ctx: The context for the operation, which can be used to cancel all batches.
+//   - items: The slice of items to process.
+//   - batchSize: The maximum number of items to process in each batch.
+//   - workFn: The function to process each batch of items.
+//   - opts: Optional worker pool options applied to the batch worker pool.
+//
+// Returns:
+//   - []Result: A slice of results, one per item, in the same order as the input items.
+//     The items of the batches not started because ctx was done fail with a
+//     cancellation error, as in WorkerPool. The results are all held until
+//     BatchItems returns; BatchItemsStream hands them over as they come instead.
+func BatchItems[T, R any](
+	ctx context.Context,
+	items []T,
+	batchSize int,
+	workFn func(ctx context.Context, batch []T) ([]ItemResult[R], error),
+	opts ...PoolOption,
+) []Result[T, R] {
+	if batchSize <= 0 {
+		batchSize = 10 // Default batch size
+	}
+
+	batches := splitBatches(items, batchSize)
+
+	batchResults := WorkerPool(ctx, batches, func(ctx context.Context, batch []T) ([]ItemResult[R], error) {
+		return workFn(ctx, batch)
+	}, opts...)
+
+	results := make([]Result[T, R], 0, len(items))
+
+	for _, br := range batchResults {
+		_ = eachBatchItemResult(br, batchSize, func(r Result[T, R]) error {
+			results = append(results, r)
+			return nil
+		})
+	}
+
+	return results
+}
+
+// splitBatches splits items into consecutive batches of at most size items.
+func splitBatches[T any](items []T, size int) [][]T {
+	batches := make([][]T, 0, (len(items)+size-1)/size)
+
+	for i := 0; i < len(items); i += size {
+		batches = append(batches, items[i:min(i+size, len(items))])
+	}
+
+	return batches
+}
+
 // ForEach executes a function for each item in parallel, when you don't need to collect results.
 // This is useful for fire-and-forget operations like updates or deletions.
 //
@@ -481,7 +794,13 @@ func Batch[T, R any](
 //   - opts: Optional worker pool options.
 //
 // Returns:
-//   - error: The first error encountered, or nil if all operations succeeded.
+//   - error: The first error encountered, in input order, or nil if all
+//     operations succeeded. Items not started because ctx was done fail with
+//     a cancellation error, so a run canceled before its last item started
+//     never returns nil.
+//
+// ForEach keeps no result besides the error returned, so its memory doesn't
+// grow with the number of items; see WorkerPoolStream for its ceiling.
 func ForEach[T any](
 	ctx context.Context,
 	items []T,
@@ -494,17 +813,21 @@ func ForEach[T any](
 		return err == nil, err
 	}
 
-	// Use the worker pool to process items
-	results := WorkerPool(ctx, items, workFn, opts...)
+	// Stream the results, keeping only the error of the lowest index
+	var (
+		firstErr   error
+		firstIndex int
+	)
 
-	// Return the first error encountered, if any
-	for _, r := range results {
-		if r.Error != nil {
-			return r.Error
+	_ = WorkerPoolStream(ctx, items, workFn, func(r Result[T, bool]) error {
+		if r.Error != nil && (firstErr == nil || r.Index < firstIndex) {
+			firstErr, firstIndex = r.Error, r.Index
 		}
-	}
 
-	return nil
+		return nil
+	}, opts...)
+
+	return firstErr
 }
 
 // RateLimiter provides a simple mechanism to limit the rate of operations.
@@ -520,6 +843,7 @@ type RateLimiter struct {
 	stopCh   chan struct{}
 	tokensCh chan struct{}
 	wg       sync.WaitGroup
+	stopOnce sync.Once
 }
 
 // NewRateLimiter creates a new rate limiter with the specified maximum operations per second.
@@ -556,8 +880,11 @@ func NewRateLimiter(opsPerSecond int, maxBurst int) *RateLimiter {
 	// Start the token generator
 	rl.wg.Add(1)
 
+	done := lifecycle.Track("concurrent.RateLimiter")
+
 	go func() {
 		defer rl.wg.Done()
+		defer done()
 
 		for {
 			select {
@@ -609,10 +936,14 @@ func (r *RateLimiter) Wait(ctx context.Context) error {
 	}
 }
 
-// Stop stops the rate limiter and releases resources.
+// Stop stops the rate limiter and releases resources. It returns once the
+// token generator has exited and may be called more than once.
 func (r *RateLimiter) Stop() {
-	close(r.stopCh)
-	r.ticker.Stop()
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+		r.ticker.Stop()
+	})
+
 	r.wg.Wait()
 }
 
@@ -7,6 +7,16 @@ import (
 	"sync/atomic"
 	"testing"
 	"time"
+
+	pkgerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 //nolint:revive // cognitive-complexity: comprehensive test with many sub-tests
@@ -378,6 +388,102 @@ func TestBatch(t *testing.T) {
 	})
 }
 
+//nolint:revive // cognitive-complexity: comprehensive test with many sub-tests
+func TestBatchItems(t *testing.T) {
+	errOdd := errors.New("odd item")
+	errNetwork := errors.New("network error")
+
+	// Test that a failed item doesn't fail the other items of its batch
+	t.Run("PartialFailure", func(t *testing.T) {
+		items := []int{1, 2, 3, 4, 5, 6, 7}
+		results := BatchItems(
+			context.Background(),
+			items,
+			3,
+			func(_ context.Context, batch []int) ([]ItemResult[int], error) {
+				out := make([]ItemResult[int], len(batch))
+				for i, item := range batch {
+					if item%2 == 1 {
+						out[i].Error = errOdd
+					} else {
+						out[i].Value = item * 10
+					}
+				}
+
+				return out, nil
+			},
+		)
+
+		if len(results) != len(items) {
+			t.Fatalf("Expected %d results, got %d", len(items), len(results))
+		}
+
+		for i, r := range results {
+			if r.Item != items[i] || r.Index != i {
+				t.Errorf("Expected item %d at index %d, got %d at %d", items[i], i, r.Item, r.Index)
+			}
+
+			if items[i]%2 == 1 {
+				if !errors.Is(r.Error, errOdd) {
+					t.Errorf("Expected error %v for item %d, got %v", errOdd, items[i], r.Error)
+				}
+			} else if r.Error != nil || r.Value != items[i]*10 {
+				t.Errorf("Expected value %d for item %d, got %d (error %v)", items[i]*10, items[i], r.Value, r.Error)
+			}
+		}
+	})
+
+	// Test that a batch error applies to every item of the batch only
+	t.Run("BatchError", func(t *testing.T) {
+		items := []int{1, 2, 3, 4}
+		results := BatchItems(
+			context.Background(),
+			items,
+			2,
+			func(_ context.Context, batch []int) ([]ItemResult[int], error) {
+				if batch[0] == 3 {
+					return nil, errNetwork
+				}
+
+				return []ItemResult[int]{{Value: batch[0]}, {Value: batch[1]}}, nil
+			},
+		)
+
+		for i, r := range results {
+			failed := errors.Is(r.Error, errNetwork)
+			if failed != (items[i] >= 3) {
+				t.Errorf("Unexpected error for item %d: %v", items[i], r.Error)
+			}
+		}
+	})
+
+	// Test that items without a result fail instead of being dropped
+	t.Run("MissingResults", func(t *testing.T) {
+		results := BatchItems(
+			context.Background(),
+			[]int{1, 2, 3},
+			3,
+			func(_ context.Context, _ []int) ([]ItemResult[int], error) {
+				return []ItemResult[int]{{Value: 1}}, nil
+			},
+		)
+
+		if len(results) != 3 {
+			t.Fatalf("Expected 3 results, got %d", len(results))
+		}
+
+		if results[0].Error != nil || results[0].Value != 1 {
+			t.Errorf("Expected first item to succeed, got %v", results[0].Error)
+		}
+
+		for _, r := range results[1:] {
+			if !errors.Is(r.Error, ErrMissingItemResult) {
+				t.Errorf("Expected ErrMissingItemResult for item %d, got %v", r.Item, r.Error)
+			}
+		}
+	})
+}
+
 //nolint:revive // cognitive-complexity: comprehensive test with many sub-tests
 func TestForEach(t *testing.T) {
 	// Test basic functionality
@@ -623,6 +729,23 @@ func TestRateLimiter(t *testing.T) {
 			t.Errorf("Expected no error with negative max burst (defaulted), got %v", err)
 		}
 	})
+
+	// Test that Stop ends the token generator and can be called again
+	t.Run("StopEndsGenerator", func(t *testing.T) {
+		tasks := lifecycle.Count("concurrent.RateLimiter")
+
+		rl := NewRateLimiter(10, 1)
+		if n := lifecycle.Count("concurrent.RateLimiter"); n != tasks+1 {
+			t.Errorf("Expected the token generator to be tracked, got %d tasks", n-tasks)
+		}
+
+		rl.Stop()
+		rl.Stop()
+
+		if n := lifecycle.Count("concurrent.RateLimiter"); n != tasks {
+			t.Errorf("Expected no token generator left after Stop, got %d", n-tasks)
+		}
+	})
 }
 
 //nolint:revive // cognitive-complexity: comprehensive edge case test
@@ -1263,3 +1386,272 @@ func TestResultStruct(t *testing.T) {
 		}
 	})
 }
+
+// spanProvider is an observability provider recording spans in memory.
+type spanProvider struct {
+	observability.Provider
+	tracer trace.Tracer
+}
+
+func (p *spanProvider) Tracer() trace.Tracer { return p.tracer }
+func (*spanProvider) Meter() metric.Meter    { return noop.NewMeterProvider().Meter("test") }
+func (*spanProvider) IsEnabled() bool        { return true }
+
+type ctxKey struct{}
+
+//nolint:revive // cognitive-complexity: comprehensive test with many sub-tests
+func TestWorkerPoolContextPropagation(t *testing.T) {
+	// Test that deadline, baggage, provider and plain values reach every item
+	t.Run("Values", func(t *testing.T) {
+		provider := &spanProvider{tracer: sdktrace.NewTracerProvider().Tracer("test")}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		ctx = observability.WithProvider(ctx, provider)
+		ctx = context.WithValue(ctx, ctxKey{}, "value")
+
+		ctx, err := observability.WithBaggageItem(ctx, "tenant", "acme")
+		if err != nil {
+			t.Fatalf("Failed to set baggage: %v", err)
+		}
+
+		deadline, _ := ctx.Deadline()
+
+		results := WorkerPool(ctx, make([]int, 50), func(ctx context.Context, _ int) (bool, error) {
+			d, ok := ctx.Deadline()
+
+			return ok && d.Equal(deadline) &&
+				observability.GetBaggageItem(ctx, "tenant") == "acme" &&
+				observability.GetProvider(ctx) == observability.Provider(provider) &&
+				ctx.Value(ctxKey{}) == "value", nil
+		}, WithWorkers(8), WithItemTimeout(time.Hour), WithItemSpan("item"))
+
+		for _, r := range results {
+			if !r.Value {
+				t.Fatalf("Item %d did not see the pool context values", r.Index)
+			}
+		}
+	})
+
+	// Test that the item timeout cancels slow items only
+	t.Run("ItemTimeout", func(t *testing.T) {
+		results := WorkerPool(context.Background(), []int{0, 1, 2, 3}, func(ctx context.Context, item int) (int, error) {
+			if item != 2 {
+				return item, nil
+			}
+
+			<-ctx.Done()
+
+			return 0, ctx.Err()
+		}, WithWorkers(4), WithItemTimeout(20*time.Millisecond))
+
+		for _, r := range results {
+			if r.Item == 2 {
+				if !errors.Is(r.Error, context.DeadlineExceeded) {
+					t.Errorf("Expected deadline exceeded for slow item, got %v", r.Error)
+				}
+			} else if r.Error != nil {
+				t.Errorf("Expected no error for item %d, got %v", r.Item, r.Error)
+			}
+		}
+	})
+
+	// Test that item contexts are derived per item and released after processing
+	t.Run("ItemContext", func(t *testing.T) {
+		var released atomic.Int32
+
+		results := WorkerPool(context.Background(), []int{10, 20, 30}, func(ctx context.Context, _ int) (int, error) {
+			return ctx.Value(ctxKey{}).(int), nil
+		}, WithItemContext(func(ctx context.Context, index int) (context.Context, context.CancelFunc) {
+			return context.WithValue(ctx, ctxKey{}, index), func() { released.Add(1) }
+		}))
+
+		for i, r := range results {
+			if r.Value != i {
+				t.Errorf("Expected item context index %d, got %d", i, r.Value)
+			}
+		}
+
+		if released.Load() != 3 {
+			t.Errorf("Expected 3 released item contexts, got %d", released.Load())
+		}
+	})
+
+	// Test that item spans are children of the pool span and record errors
+	t.Run("ItemSpan", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		provider := &spanProvider{tracer: tp.Tracer("test")}
+
+		ctx, parent := provider.tracer.Start(observability.WithProvider(context.Background(), provider), "pool")
+		errItem := errors.New("item failed")
+
+		WorkerPool(ctx, []int{0, 1, 2}, func(ctx context.Context, item int) (int, error) {
+			if observability.SpanID(ctx) == parent.SpanContext().SpanID().String() {
+				t.Errorf("Expected item %d to run in its own span", item)
+			}
+
+			if item == 1 {
+				return 0, errItem
+			}
+
+			return item, nil
+		}, WithItemSpan("process-item"))
+		parent.End()
+
+		var items, failed int
+
+		for _, s := range recorder.Ended() {
+			if s.Name() != "process-item" {
+				continue
+			}
+
+			items++
+
+			if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+				t.Errorf("Expected item span to be a child of the pool span")
+			}
+
+			if s.Status().Code == codes.Error {
+				failed++
+			}
+		}
+
+		if items != 3 || failed != 1 {
+			t.Errorf("Expected 3 item spans with 1 failure, got %d with %d", items, failed)
+		}
+	})
+}
+
+func TestWorkerPoolPanicRecovery(t *testing.T) {
+	var handled []*PanicError
+
+	var mu sync.Mutex
+
+	results := WorkerPool(context.Background(), []int{1, 2, 3, 4}, func(_ context.Context, item int) (int, error) {
+		if item == 3 {
+			panic("boom")
+		}
+
+		return item * 2, nil
+	}, WithWorkers(2), WithPanicHandler(func(_ context.Context, err *PanicError) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		handled = append(handled, err)
+	}))
+
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(results))
+	}
+
+	for _, r := range results {
+		if r.Item != 3 {
+			if r.Error != nil || r.Value != r.Item*2 {
+				t.Errorf("Expected item %d to succeed, got %v", r.Item, r.Error)
+			}
+
+			continue
+		}
+
+		var panicErr *PanicError
+		if !errors.As(r.Error, &panicErr) {
+			t.Fatalf("Expected a PanicError, got %v", r.Error)
+		}
+
+		if panicErr.Value != "boom" || panicErr.Index != 2 || len(panicErr.Stack) == 0 {
+			t.Errorf("Unexpected panic error: %+v", panicErr)
+		}
+
+		if !pkgerrors.IsInternalError(r.Error) {
+			t.Errorf("Expected an internal error, got %v", r.Error)
+		}
+	}
+
+	if len(handled) != 1 || handled[0].Value != "boom" {
+		t.Errorf("Expected the panic handler to be called once, got %d calls", len(handled))
+	}
+
+	// ForEach reports the recovered panic as its error
+	err := ForEach(context.Background(), []int{1}, func(context.Context, int) error { panic("boom") })
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Expected ForEach to return a PanicError, got %v", err)
+	}
+}
+
+func TestWorkerPoolCancelledItems(t *testing.T) {
+	for _, ordered := range []bool{true, false} {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		opts := []PoolOption{WithWorkers(1), WithBufferSize(1)}
+		if !ordered {
+			opts = append(opts, WithUnorderedResults())
+		}
+
+		results := WorkerPool(ctx, []int{0, 1, 2, 3, 4}, func(ctx context.Context, item int) (int, error) {
+			cancel()
+			<-ctx.Done()
+
+			return item, ctx.Err()
+		}, opts...)
+
+		if len(results) != 5 {
+			t.Fatalf("expected a result per item, got %d", len(results))
+		}
+
+		seen := make(map[int]bool)
+
+		for _, r := range results {
+			seen[r.Index] = true
+
+			if r.Item != r.Index {
+				t.Errorf("result %d carries item %d", r.Index, r.Item)
+			}
+
+			if !errors.Is(r.Error, context.Canceled) {
+				t.Errorf("expected item %d to fail with context.Canceled, got %v", r.Index, r.Error)
+			}
+
+			if r.Index > 0 && !pkgerrors.IsCancellationError(r.Error) {
+				t.Errorf("expected item %d, never started, to have a cancellation error, got %v", r.Index, r.Error)
+			}
+		}
+
+		if len(seen) != 5 {
+			t.Errorf("expected every index once, got %v", seen)
+		}
+	}
+}
+
+func TestWithDrainOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	results := WorkerPool(ctx, []int{1, 2, 3}, func(itemCtx context.Context, item int) (int, error) {
+		cancel()
+		<-ctx.Done()
+
+		if err := itemCtx.Err(); err != nil {
+			return 0, err
+		}
+
+		return item * 10, nil
+	}, WithWorkers(1), WithDrainOnCancel())
+
+	if results[0].Error != nil || results[0].Value != 10 {
+		t.Errorf("expected the item in flight to complete, got %v, %v", results[0].Value, results[0].Error)
+	}
+
+	for _, r := range results[1:] {
+		if !pkgerrors.IsCancellationError(r.Error) {
+			t.Errorf("expected item %d not to start, got %v", r.Index, r.Error)
+		}
+	}
+
+	err := ForEach(ctx, []int{1}, func(context.Context, int) error { return nil })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected ForEach on a cancelled context to fail, got %v", err)
+	}
+}
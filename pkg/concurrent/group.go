@@ -0,0 +1,204 @@
+package concurrent
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// MetricGroupTaskDuration records the duration of the tasks of a Group, in milliseconds.
+	MetricGroupTaskDuration = "midaz.sdk.concurrent.group.task.duration"
+
+	// MetricGroupTaskErrorTotal counts the tasks of a Group that returned an error.
+	MetricGroupTaskErrorTotal = "midaz.sdk.concurrent.group.task.error.total"
+)
+
+// Group runs tasks in goroutines and collects the first error, with the same
+// API as golang.org/x/sync/errgroup (Go, TryGo, SetLimit and Wait), so code
+// written against errgroup switches by changing the import and the
+// constructor. Unlike errgroup, the tasks go through the SDK machinery:
+//
+//   - WithLimiter and WithRateLimit pace the start of the tasks
+//   - WithItemSpan starts a span per task from the group context, with the
+//     task index and error recorded
+//   - WithItemTimeout and WithItemContext derive the context of each task
+//   - panics are recovered into a PanicError, passed to WithPanicHandler,
+//     and returned by Wait instead of crashing the process
+//   - task durations and errors are recorded as metrics through the
+//     observability provider of the group context
+//
+// The other pool options are ignored; use SetLimit to bound the number of
+// active goroutines. The zero Group is valid, has no limit and does not
+// cancel on error.
+//
+// Example use case: Fetching the balances of several ledgers, stopping at the first failure:
+//
+//	g, ctx := concurrent.NewGroup(ctx, concurrent.WithItemSpan("fetch_ledger"), concurrent.WithRateLimit(50))
+//	g.SetLimit(10)
+//
+//	for _, id := range ledgerIDs {
+//	    g.GoContext(func(ctx context.Context) error {
+//	        return fetchLedger(ctx, id)
+//	    })
+//	}
+//
+//	if err := g.Wait(); err != nil {
+//	    return err
+//	}
+type Group struct {
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	options *poolOptions
+	limiter Limiter
+
+	wg   sync.WaitGroup
+	sem  chan struct{}
+	next atomic.Int64
+
+	errOnce sync.Once
+	err     error
+}
+
+// NewGroup returns a Group and a context derived from ctx, the counterpart of
+// errgroup.WithContext. The derived context is canceled the first time a task
+// returns an error or the first time Wait returns, whichever occurs first.
+// Its observability provider instruments the tasks.
+func NewGroup(ctx context.Context, opts ...PoolOption) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	options := applyPoolOptions(opts...)
+
+	g := &Group{ctx: ctx, cancel: cancel, options: options, limiter: options.limiter}
+	if g.limiter == nil && options.rateLimit > 0 {
+		g.limiter = NewGCRALimiter(options.rateLimit, 1)
+	}
+
+	return g, ctx
+}
+
+// Go calls f in a new goroutine, blocking until it can be added without
+// exceeding the limit of active goroutines. The first call to return an error
+// cancels the context of the group; its error is returned by Wait.
+func (g *Group) Go(f func() error) {
+	g.GoContext(func(context.Context) error { return f() })
+}
+
+// GoContext is like Go, passing f the context of the task: the group context
+// with the span, timeout and values of the task options.
+func (g *Group) GoContext(f func(ctx context.Context) error) {
+	sem := g.sem
+	if sem != nil {
+		sem <- struct{}{}
+	}
+
+	g.start(sem, f)
+}
+
+// TryGo calls f in a new goroutine only if the number of active goroutines is
+// below the limit, and reports whether it did.
+func (g *Group) TryGo(f func() error) bool {
+	sem := g.sem
+	if sem != nil {
+		select {
+		case sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+
+	g.start(sem, func(context.Context) error { return f() })
+
+	return true
+}
+
+// SetLimit limits the number of active goroutines of the group to at most n;
+// a negative value removes the limit. Unlike errgroup, which panics when the
+// limit changes while tasks are active, the new limit applies to the tasks
+// started by later calls to Go and TryGo only: tasks already active keep
+// running and don't count against it.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+
+	g.sem = make(chan struct{}, n)
+}
+
+// Wait blocks until all the tasks have returned, then returns the first error
+// they returned, if any.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+
+	return g.err
+}
+
+// start runs f in a goroutine holding the slot already taken in sem.
+func (g *Group) start(sem chan struct{}, f func(ctx context.Context) error) {
+	g.wg.Add(1)
+
+	index := int(g.next.Add(1) - 1)
+
+	go func() {
+		defer g.done(sem)
+
+		if err := g.run(index, f); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel(err)
+				}
+			})
+		}
+	}()
+}
+
+// run paces and runs a task, recording its duration and error.
+func (g *Group) run(index int, f func(ctx context.Context) error) error {
+	ctx := g.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if g.limiter != nil {
+		if err := g.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	options := g.options
+	if options == nil {
+		options = defaultPoolOptions()
+	}
+
+	start := time.Now()
+	result := processWorkItem(ctx, indexedItem[struct{}]{index: index}, func(ctx context.Context, _ struct{}) (struct{}, error) {
+		return struct{}{}, f(ctx)
+	}, options)
+
+	provider := observability.GetProvider(ctx)
+	observability.RecordDuration(ctx, provider, MetricGroupTaskDuration, start, attribute.Bool("error", result.Error != nil))
+
+	if result.Error != nil {
+		observability.RecordMetric(ctx, provider, MetricGroupTaskErrorTotal, 1)
+	}
+
+	return result.Error
+}
+
+// done releases the slot a task took in sem.
+func (g *Group) done(sem chan struct{}) {
+	if sem != nil {
+		<-sem
+	}
+
+	g.wg.Done()
+}
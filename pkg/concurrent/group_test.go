@@ -0,0 +1,248 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestGroupFirstErrorCancelsContext(t *testing.T) {
+	errFirst := errors.New("first")
+
+	g, ctx := NewGroup(context.Background())
+
+	g.Go(func() error { return errFirst })
+	g.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if err := g.Wait(); !errors.Is(err, errFirst) {
+		t.Fatalf("Expected the first error, got %v", err)
+	}
+
+	if !errors.Is(context.Cause(ctx), errFirst) {
+		t.Errorf("Expected the context cause to be the first error, got %v", context.Cause(ctx))
+	}
+}
+
+func TestGroupWaitCancelsContext(t *testing.T) {
+	g, ctx := NewGroup(context.Background())
+	g.Go(func() error { return nil })
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if ctx.Err() == nil {
+		t.Error("Expected the context to be canceled after Wait")
+	}
+}
+
+func TestGroupZeroValue(t *testing.T) {
+	var (
+		g     Group
+		count atomic.Int32
+	)
+
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			count.Add(1)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if count.Load() != 10 {
+		t.Errorf("Expected 10 tasks to run, got %d", count.Load())
+	}
+}
+
+func TestGroupSetLimit(t *testing.T) {
+	var (
+		g              Group
+		active, peak   atomic.Int32
+		release        = make(chan struct{})
+		blockingFinish = make(chan struct{})
+	)
+
+	g.SetLimit(2)
+
+	go func() {
+		for i := 0; i < 6; i++ {
+			g.Go(func() error {
+				n := active.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+
+				<-release
+				active.Add(-1)
+
+				return nil
+			})
+		}
+
+		close(blockingFinish)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	if g.TryGo(func() error { return nil }) {
+		t.Error("Expected TryGo to fail at the limit")
+	}
+
+	close(release)
+	<-blockingFinish
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if peak.Load() > 2 {
+		t.Errorf("Expected at most 2 active tasks, got %d", peak.Load())
+	}
+
+	if !g.TryGo(func() error { return nil }) {
+		t.Error("Expected TryGo to succeed below the limit")
+	}
+
+	_ = g.Wait()
+}
+
+func TestGroupSetLimitWhileActive(t *testing.T) {
+	var g Group
+
+	release := make(chan struct{})
+
+	g.SetLimit(1)
+	g.Go(func() error {
+		<-release
+		return nil
+	})
+
+	g.SetLimit(2)
+
+	if !g.TryGo(func() error { <-release; return nil }) {
+		t.Error("Expected the new limit to apply to later tasks")
+	}
+
+	if !g.TryGo(func() error { <-release; return nil }) {
+		t.Error("Expected the task started under the old limit not to count against the new one")
+	}
+
+	if g.TryGo(func() error { return nil }) {
+		t.Error("Expected TryGo to fail at the new limit")
+	}
+
+	close(release)
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestGroupPanicRecovery(t *testing.T) {
+	var handled atomic.Int32
+
+	g, _ := NewGroup(context.Background(), WithPanicHandler(func(_ context.Context, _ *PanicError) {
+		handled.Add(1)
+	}))
+
+	g.Go(func() error { panic("boom") })
+
+	err := g.Wait()
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected a PanicError, got %v", err)
+	}
+
+	if panicErr.Value != "boom" {
+		t.Errorf("Expected the panic value, got %v", panicErr.Value)
+	}
+
+	if handled.Load() != 1 {
+		t.Errorf("Expected the panic handler to be called once, got %d", handled.Load())
+	}
+}
+
+func TestGroupRateLimit(t *testing.T) {
+	g, _ := NewGroup(context.Background(), WithRateLimit(100))
+
+	start := time.Now()
+
+	for i := 0; i < 5; i++ {
+		g.Go(func() error { return nil })
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// evenly spaced: the fifth task starts 40ms after the first
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("Expected the tasks to be paced, took %v", elapsed)
+	}
+}
+
+func TestGroupItemSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	provider := &spanProvider{tracer: tp.Tracer("test")}
+
+	ctx, parent := provider.tracer.Start(observability.WithProvider(context.Background(), provider), "group")
+	g, _ := NewGroup(ctx, WithItemSpan("group-task"))
+
+	errTask := errors.New("task failed")
+
+	g.GoContext(func(ctx context.Context) error {
+		if observability.SpanID(ctx) == parent.SpanContext().SpanID().String() {
+			t.Error("Expected the task to run in its own span")
+		}
+
+		return nil
+	})
+	g.Go(func() error { return errTask })
+
+	if err := g.Wait(); !errors.Is(err, errTask) {
+		t.Fatalf("Expected the task error, got %v", err)
+	}
+
+	parent.End()
+
+	var tasks, failed int
+
+	for _, s := range recorder.Ended() {
+		if s.Name() != "group-task" {
+			continue
+		}
+
+		tasks++
+
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("Expected task span to be a child of the group span")
+		}
+
+		if s.Status().Code == codes.Error {
+			failed++
+		}
+	}
+
+	if tasks != 2 || failed != 1 {
+		t.Errorf("Expected 2 task spans with 1 failure, got %d with %d", tasks, failed)
+	}
+}
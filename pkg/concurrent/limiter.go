@@ -0,0 +1,280 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrLimiterStopped is returned by Wait on a stopped LeakyBucketLimiter or GCRALimiter.
+	ErrLimiterStopped = errors.New("rate limiter stopped")
+
+	// ErrBucketFull is returned by LeakyBucketLimiter.Wait when its queue is full.
+	ErrBucketFull = errors.New("leaky bucket full")
+)
+
+// Limiter paces operations. RateLimiter, LeakyBucketLimiter and GCRALimiter
+// implement it.
+type Limiter interface {
+	// Wait blocks until an operation is allowed or the context is cancelled.
+	Wait(ctx context.Context) error
+
+	// Stop releases the resources of the limiter.
+	Stop()
+}
+
+// Algorithm selects the implementation returned by NewLimiter.
+type Algorithm string
+
+const (
+	// AlgorithmTokenBucket is the ticker-based token bucket of RateLimiter. It
+	// lets bursts of up to maxBurst operations through at once.
+	AlgorithmTokenBucket Algorithm = "token_bucket"
+
+	// AlgorithmLeakyBucket spaces operations evenly and never bursts; up to
+	// maxBurst operations queue, further ones fail with ErrBucketFull.
+	AlgorithmLeakyBucket Algorithm = "leaky_bucket"
+
+	// AlgorithmGCRA is the generic cell rate algorithm: bursts of up to maxBurst
+	// operations, then operations evenly spaced, with no background goroutine.
+	AlgorithmGCRA Algorithm = "gcra"
+)
+
+// NewLimiter creates a limiter of the given algorithm. An unknown algorithm
+// selects AlgorithmTokenBucket.
+//
+// Parameters:
+//   - algorithm: The limiting algorithm.
+//   - opsPerSecond: The maximum number of operations per second.
+//   - maxBurst: The burst size of the token bucket and GCRA, or the queue size of the leaky bucket.
+//
+// Returns:
+//   - Limiter: A new limiter.
+//
+// Example use case: Pacing a load test evenly so bursts don't skew latency measurements:
+//
+//	limiter := concurrent.NewLimiter(concurrent.AlgorithmGCRA, 500, 1)
+//	defer limiter.Stop()
+//
+//	results := concurrent.WorkerPool(ctx, inputs, submit, concurrent.WithLimiter(limiter))
+func NewLimiter(algorithm Algorithm, opsPerSecond, maxBurst int) Limiter {
+	switch algorithm {
+	case AlgorithmLeakyBucket:
+		return NewLeakyBucketLimiter(opsPerSecond, maxBurst)
+	case AlgorithmGCRA:
+		return NewGCRALimiter(opsPerSecond, maxBurst)
+	default:
+		return NewRateLimiter(opsPerSecond, maxBurst)
+	}
+}
+
+// LeakyBucketLimiter releases operations at a constant interval, in the order
+// they called Wait. Unlike RateLimiter it never lets a burst through: the
+// bucket is a queue of waiting operations that leaks one operation per interval.
+type LeakyBucketLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	capacity int
+	// next is the time the next queued operation is released
+	next    time.Time
+	stopped bool
+	now     func() time.Time
+}
+
+// NewLeakyBucketLimiter creates a leaky bucket releasing opsPerSecond
+// operations per second with at most capacity operations waiting, with the
+// same defaults as NewRateLimiter.
+func NewLeakyBucketLimiter(opsPerSecond, capacity int) *LeakyBucketLimiter {
+	if opsPerSecond <= 0 {
+		opsPerSecond = 1 // Minimum 1 op per second
+	}
+
+	if capacity <= 0 {
+		capacity = opsPerSecond // Default to queue one second worth of operations
+	}
+
+	return &LeakyBucketLimiter{
+		interval: time.Second / time.Duration(opsPerSecond),
+		capacity: capacity,
+		now:      time.Now,
+	}
+}
+
+// Wait blocks until the operation leaks out of the bucket. It returns
+// ErrBucketFull without waiting when capacity operations are already queued.
+func (l *LeakyBucketLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+
+	if l.stopped {
+		l.mu.Unlock()
+		return ErrLimiterStopped
+	}
+
+	now := l.now()
+
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+
+	if queued := int(at.Sub(now) / l.interval); queued >= l.capacity {
+		l.mu.Unlock()
+		return ErrBucketFull
+	}
+
+	reserved := at.Add(l.interval)
+	l.next = reserved
+	l.mu.Unlock()
+
+	if err := sleepContext(ctx, at.Sub(now)); err != nil {
+		l.mu.Lock()
+		// give the slot back unless later operations queued behind it
+		if l.next.Equal(reserved) {
+			l.next = at
+		}
+		l.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// Stop makes later calls of Wait fail with ErrLimiterStopped.
+func (l *LeakyBucketLimiter) Stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stopped = true
+}
+
+// GCRALimiter implements the generic cell rate algorithm. It tracks the
+// theoretical arrival time of the next operation instead of refilling tokens
+// from a ticker, so pacing stays exact at high rates and no goroutine runs.
+type GCRALimiter struct {
+	mu sync.Mutex
+	// interval is the emission interval between two operations
+	interval time.Duration
+	// tolerance is how far ahead of the schedule a burst may run
+	tolerance time.Duration
+	// tat is the theoretical arrival time of the next operation
+	tat     time.Time
+	stopped bool
+	now     func() time.Time
+}
+
+// NewGCRALimiter creates a GCRA limiter allowing opsPerSecond operations per
+// second with bursts of up to maxBurst operations, with the same defaults as
+// NewRateLimiter. A maxBurst of 1 spaces every operation evenly.
+func NewGCRALimiter(opsPerSecond, maxBurst int) *GCRALimiter {
+	if opsPerSecond <= 0 {
+		opsPerSecond = 1 // Minimum 1 op per second
+	}
+
+	if maxBurst <= 0 {
+		maxBurst = opsPerSecond // Default to allow one second worth of operations
+	}
+
+	interval := time.Second / time.Duration(opsPerSecond)
+
+	return &GCRALimiter{
+		interval:  interval,
+		tolerance: time.Duration(maxBurst-1) * interval,
+		now:       time.Now,
+	}
+}
+
+// Wait blocks until the operation conforms to the rate or the context is cancelled.
+func (l *GCRALimiter) Wait(ctx context.Context) error {
+	delay, reserved, err := l.reserve()
+	if err != nil {
+		return err
+	}
+
+	if err := sleepContext(ctx, delay); err != nil {
+		l.mu.Lock()
+		// give the slot back unless later operations reserved after it
+		if l.tat.Equal(reserved) {
+			l.tat = reserved.Add(-l.interval)
+		}
+		l.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// Allow reports whether an operation conforms to the rate right now, reserving
+// it if so. It never blocks.
+func (l *GCRALimiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stopped {
+		return false
+	}
+
+	now := l.now()
+
+	tat := l.tat
+	if tat.Before(now) {
+		tat = now
+	}
+
+	if tat.Add(-l.tolerance).After(now) {
+		return false
+	}
+
+	l.tat = tat.Add(l.interval)
+
+	return true
+}
+
+// reserve schedules an operation and returns how long it must wait.
+func (l *GCRALimiter) reserve() (time.Duration, time.Time, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stopped {
+		return 0, time.Time{}, ErrLimiterStopped
+	}
+
+	now := l.now()
+
+	tat := l.tat
+	if tat.Before(now) {
+		tat = now
+	}
+
+	l.tat = tat.Add(l.interval)
+
+	return tat.Add(-l.tolerance).Sub(now), l.tat, nil
+}
+
+// Stop makes later calls of Wait fail with ErrLimiterStopped.
+func (l *GCRALimiter) Stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stopped = true
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
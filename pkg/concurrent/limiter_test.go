@@ -0,0 +1,227 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for limiter tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestNewLimiter(t *testing.T) {
+	if _, ok := NewLimiter(AlgorithmLeakyBucket, 10, 1).(*LeakyBucketLimiter); !ok {
+		t.Error("Expected a LeakyBucketLimiter")
+	}
+
+	if _, ok := NewLimiter(AlgorithmGCRA, 10, 1).(*GCRALimiter); !ok {
+		t.Error("Expected a GCRALimiter")
+	}
+
+	l := NewLimiter("unknown", 10, 1)
+	defer l.Stop()
+
+	if _, ok := l.(*RateLimiter); !ok {
+		t.Error("Expected a RateLimiter for an unknown algorithm")
+	}
+}
+
+func TestGCRALimiterAllow(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewGCRALimiter(10, 3) // 100ms interval, bursts of 3
+	l.now = clock.Now
+
+	for i := 0; i < 3; i++ {
+		if !l.Allow() {
+			t.Fatalf("Expected operation %d of the burst to be allowed", i)
+		}
+	}
+
+	if l.Allow() {
+		t.Fatal("Expected the operation after the burst to be rejected")
+	}
+
+	clock.Advance(100 * time.Millisecond)
+
+	if !l.Allow() || l.Allow() {
+		t.Error("Expected exactly one operation to be allowed after one interval")
+	}
+
+	l.Stop()
+
+	if l.Allow() {
+		t.Error("Expected a stopped limiter to reject operations")
+	}
+
+	if err := l.Wait(context.Background()); !errors.Is(err, ErrLimiterStopped) {
+		t.Errorf("Expected ErrLimiterStopped, got %v", err)
+	}
+}
+
+func TestGCRALimiterWaitPacing(t *testing.T) {
+	l := NewGCRALimiter(50, 1) // one operation every 20ms, no burst
+
+	start := time.Now()
+
+	for i := 0; i < 6; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	// the first operation passes immediately, the next 5 are spaced by 20ms
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected pacing to take at least 90ms, took %v", elapsed)
+	}
+}
+
+func TestGCRALimiterCancelRefund(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewGCRALimiter(1, 1)
+	l.now = clock.Now
+
+	if !l.Allow() {
+		t.Fatal("Expected the first operation to be allowed")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	// the canceled reservation was given back
+	clock.Advance(time.Second)
+
+	if !l.Allow() {
+		t.Error("Expected the canceled slot to be available again")
+	}
+}
+
+func TestLeakyBucketLimiter(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewLeakyBucketLimiter(10, 2) // 100ms interval, 2 queued operations
+	l.now = clock.Now
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the first operation leaks immediately
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// the second one queues and is canceled: its slot is given back
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	if !l.next.Equal(clock.Now().Add(100 * time.Millisecond)) {
+		t.Errorf("Expected the canceled slot to be given back, next release at %v", l.next)
+	}
+
+	// fill the queue without waiting
+	l.next = clock.Now().Add(200 * time.Millisecond)
+
+	if err := l.Wait(context.Background()); !errors.Is(err, ErrBucketFull) {
+		t.Errorf("Expected ErrBucketFull, got %v", err)
+	}
+
+	l.Stop()
+
+	if err := l.Wait(context.Background()); !errors.Is(err, ErrLimiterStopped) {
+		t.Errorf("Expected ErrLimiterStopped, got %v", err)
+	}
+}
+
+func TestLeakyBucketLimiterNoBurst(t *testing.T) {
+	l := NewLeakyBucketLimiter(50, 10) // one operation every 20ms
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := l.Wait(context.Background()); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// concurrent callers are still spaced evenly
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected pacing to take at least 90ms, took %v", elapsed)
+	}
+}
+
+func TestWorkerPoolWithLimiter(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewLeakyBucketLimiter(1, 1)
+	l.now = clock.Now
+	// keep the bucket full so every item is rejected
+	l.next = clock.Now().Add(time.Hour)
+
+	results := WorkerPool(context.Background(), []int{1, 2, 3}, func(_ context.Context, item int) (int, error) {
+		return item, nil
+	}, WithLimiter(l))
+
+	for _, r := range results {
+		if !errors.Is(r.Error, ErrBucketFull) {
+			t.Errorf("Expected ErrBucketFull for item %d, got %v", r.Item, r.Error)
+		}
+	}
+
+	results = WorkerPool(context.Background(), []int{1, 2, 3}, func(_ context.Context, item int) (int, error) {
+		return item, nil
+	}, WithLimiter(NewGCRALimiter(1000, 10)))
+
+	for _, r := range results {
+		if r.Error != nil || r.Value != r.Item {
+			t.Errorf("Expected item %d to succeed, got %v", r.Item, r.Error)
+		}
+	}
+}
+
+func TestRateLimiterGroupAlgorithm(t *testing.T) {
+	group := NewRateLimiterGroup(10, 1, WithGroupAlgorithm(AlgorithmGCRA), WithGroupCeiling(100, 1))
+	defer group.Stop()
+
+	if err := group.Wait(context.Background(), "tenant"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := group.global.(*GCRALimiter); !ok {
+		t.Error("Expected a GCRA ceiling")
+	}
+
+	if _, ok := group.limiters["tenant"].(*GCRALimiter); !ok {
+		t.Error("Expected a GCRA key limiter")
+	}
+}
@@ -0,0 +1,211 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrRateLimiterGroupStopped is returned by RateLimiterGroup.Wait after Stop.
+var ErrRateLimiterGroupStopped = errors.New("rate limiter group stopped")
+
+// rateLimit is the rate and burst of a limiter.
+type rateLimit struct {
+	opsPerSecond int
+	maxBurst     int
+}
+
+// RateLimiterGroup maintains a separate rate limit per key, such as a ledger,
+// a tenant or an endpoint, under an optional overall ceiling shared by all keys.
+//
+// A single global limiter either over-throttles light tenants or under-protects
+// hot ones; a group gives every key its own budget, so a hot key only throttles
+// itself, while the ceiling still protects the backend as a whole.
+//
+// Limiters are created on the first Wait of a key. With the default token
+// bucket each one runs a token generator goroutine, so call Remove for keys
+// that are no longer used and Stop when the group is done.
+//
+// Example use case: Limiting each tenant to 50 ops/second and all tenants together to 500:
+//
+//	group := concurrent.NewRateLimiterGroup(50, 50,
+//	    concurrent.WithGroupCeiling(500, 500),
+//	    concurrent.WithKeyLimit("tenant-premium", 200, 200),
+//	)
+//	defer group.Stop()
+//
+//	results := concurrent.WorkerPool(ctx, requests, func(ctx context.Context, req Request) (Response, error) {
+//	    if err := group.Wait(ctx, req.TenantID); err != nil {
+//	        return Response{}, err
+//	    }
+//
+//	    return send(ctx, req)
+//	})
+type RateLimiterGroup struct {
+	mu        sync.Mutex
+	defaults  rateLimit
+	overrides map[string]rateLimit
+	ceiling   *rateLimit
+	algorithm Algorithm
+	global    Limiter
+	limiters  map[string]Limiter
+	stopped   bool
+}
+
+// RateLimiterGroupOption configures a RateLimiterGroup.
+type RateLimiterGroupOption func(*RateLimiterGroup)
+
+// WithGroupCeiling limits the operations of all keys together. Without it,
+// keys are only limited individually.
+func WithGroupCeiling(opsPerSecond, maxBurst int) RateLimiterGroupOption {
+	return func(g *RateLimiterGroup) {
+		g.ceiling = &rateLimit{opsPerSecond: opsPerSecond, maxBurst: maxBurst}
+	}
+}
+
+// WithKeyLimit overrides the rate limit of a key, e.g. for a tenant with a
+// higher plan or an endpoint known to be expensive.
+func WithKeyLimit(key string, opsPerSecond, maxBurst int) RateLimiterGroupOption {
+	return func(g *RateLimiterGroup) {
+		g.overrides[key] = rateLimit{opsPerSecond: opsPerSecond, maxBurst: maxBurst}
+	}
+}
+
+// WithGroupAlgorithm selects the algorithm of the key limiters and the ceiling,
+// AlgorithmTokenBucket by default.
+func WithGroupAlgorithm(algorithm Algorithm) RateLimiterGroupOption {
+	return func(g *RateLimiterGroup) {
+		g.algorithm = algorithm
+	}
+}
+
+// NewRateLimiterGroup creates a group limiting each key to opsPerSecond with
+// bursts of maxBurst, with the same defaults as NewRateLimiter.
+//
+// Parameters:
+//   - opsPerSecond: The maximum number of operations per second of each key.
+//   - maxBurst: The maximum number of operations allowed in a burst for each key.
+//   - opts: Optional ceiling and per-key overrides.
+//
+// Returns:
+//   - *RateLimiterGroup: A new rate limiter group.
+func NewRateLimiterGroup(opsPerSecond, maxBurst int, opts ...RateLimiterGroupOption) *RateLimiterGroup {
+	g := &RateLimiterGroup{
+		defaults:  rateLimit{opsPerSecond: opsPerSecond, maxBurst: maxBurst},
+		overrides: make(map[string]rateLimit),
+		algorithm: AlgorithmTokenBucket,
+		limiters:  make(map[string]Limiter),
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if g.ceiling != nil {
+		g.global = NewLimiter(g.algorithm, g.ceiling.opsPerSecond, g.ceiling.maxBurst)
+	}
+
+	return g
+}
+
+// Wait blocks until both the limiter of key and the overall ceiling allow an
+// operation, or the context is cancelled.
+//
+// Parameters:
+//   - ctx: The context that can be used to cancel the wait.
+//   - key: The key the operation is accounted to.
+//
+// Returns:
+//   - error: Context error if the context was cancelled, ErrRateLimiterGroupStopped
+//     after Stop, nil otherwise.
+func (g *RateLimiterGroup) Wait(ctx context.Context, key string) error {
+	limiter, err := g.limiter(key)
+	if err != nil {
+		return err
+	}
+
+	if err := limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	if g.global != nil {
+		return g.global.Wait(ctx)
+	}
+
+	return nil
+}
+
+// limiter returns the limiter of key, creating it on first use.
+func (g *RateLimiterGroup) limiter(key string) (Limiter, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.stopped {
+		return nil, ErrRateLimiterGroupStopped
+	}
+
+	if l, ok := g.limiters[key]; ok {
+		return l, nil
+	}
+
+	limit, ok := g.overrides[key]
+	if !ok {
+		limit = g.defaults
+	}
+
+	l := NewLimiter(g.algorithm, limit.opsPerSecond, limit.maxBurst)
+	g.limiters[key] = l
+
+	return l, nil
+}
+
+// Keys returns the keys with an active limiter, sorted.
+func (g *RateLimiterGroup) Keys() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	keys := make([]string, 0, len(g.limiters))
+	for k := range g.limiters {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Remove stops and forgets the limiter of key; call it only once no Wait for
+// the key is in progress. A later Wait for the key starts a new limiter.
+func (g *RateLimiterGroup) Remove(key string) {
+	g.mu.Lock()
+	l, ok := g.limiters[key]
+	delete(g.limiters, key)
+	g.mu.Unlock()
+
+	if ok {
+		l.Stop()
+	}
+}
+
+// Stop stops all limiters of the group and releases resources.
+func (g *RateLimiterGroup) Stop() {
+	g.mu.Lock()
+	if g.stopped {
+		g.mu.Unlock()
+		return
+	}
+
+	g.stopped = true
+	limiters := g.limiters
+	g.limiters = make(map[string]Limiter)
+	g.mu.Unlock()
+
+	for _, l := range limiters {
+		l.Stop()
+	}
+
+	if g.global != nil {
+		g.global.Stop()
+	}
+}
@@ -0,0 +1,114 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterGroup(t *testing.T) {
+	// Test that a hot key doesn't throttle the other keys
+	t.Run("IndependentKeys", func(t *testing.T) {
+		group := NewRateLimiterGroup(10, 1)
+		defer group.Stop()
+
+		ctx := context.Background()
+
+		// Exhaust the hot key
+		for i := 0; i < 3; i++ {
+			if err := group.Wait(ctx, "hot"); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+
+		start := time.Now()
+		if err := group.Wait(ctx, "light"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+			t.Errorf("Expected the light key not to wait for the hot one, waited %v", elapsed)
+		}
+
+		if keys := group.Keys(); len(keys) != 2 || keys[0] != "hot" || keys[1] != "light" {
+			t.Errorf("Expected keys [hot light], got %v", keys)
+		}
+	})
+
+	// Test that the ceiling limits all keys together
+	t.Run("Ceiling", func(t *testing.T) {
+		group := NewRateLimiterGroup(100, 100, WithGroupCeiling(10, 1))
+		defer group.Stop()
+
+		start := time.Now()
+
+		var wg sync.WaitGroup
+
+		for _, key := range []string{"a", "b", "c", "d", "e"} {
+			wg.Add(1)
+
+			go func(key string) {
+				defer wg.Done()
+
+				if err := group.Wait(context.Background(), key); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			}(key)
+		}
+
+		wg.Wait()
+
+		// 5 operations at 10 ops/second overall take at least ~400ms
+		if elapsed := time.Since(start); elapsed < 350*time.Millisecond {
+			t.Errorf("Expected the ceiling to take at least 350ms, but took %v", elapsed)
+		}
+	})
+
+	// Test that key overrides apply
+	t.Run("KeyLimit", func(t *testing.T) {
+		group := NewRateLimiterGroup(1, 1, WithKeyLimit("premium", 100, 100))
+		defer group.Stop()
+
+		start := time.Now()
+
+		for i := 0; i < 5; i++ {
+			if err := group.Wait(context.Background(), "premium"); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+
+		if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+			t.Errorf("Expected the premium key to use its own limit, waited %v", elapsed)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_ = group.Wait(context.Background(), "basic")
+		if err := group.Wait(ctx, "basic"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded for the basic key, got %v", err)
+		}
+	})
+
+	// Test removal and stop
+	t.Run("RemoveAndStop", func(t *testing.T) {
+		group := NewRateLimiterGroup(10, 1, WithGroupCeiling(10, 1))
+
+		_ = group.Wait(context.Background(), "tenant")
+		group.Remove("tenant")
+		group.Remove("unknown")
+
+		if keys := group.Keys(); len(keys) != 0 {
+			t.Errorf("Expected no keys after Remove, got %v", keys)
+		}
+
+		group.Stop()
+		group.Stop()
+
+		if err := group.Wait(context.Background(), "tenant"); !errors.Is(err, ErrRateLimiterGroupStopped) {
+			t.Errorf("Expected ErrRateLimiterGroupStopped, got %v", err)
+		}
+	})
+}
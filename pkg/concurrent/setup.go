@@ -0,0 +1,260 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSetupCycle is returned by Setup.Run when the steps depend on each other in a cycle.
+var ErrSetupCycle = errors.New("setup steps have a dependency cycle")
+
+// StepError is returned by Setup.Run when a step fails. Steps depending on it,
+// directly or not, are not started.
+type StepError struct {
+	// Step is the name of the failed step.
+	Step string
+
+	// Err is the error returned by the step.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *StepError) Error() string {
+	return fmt.Sprintf("setup step %q failed: %v", e.Step, e.Err)
+}
+
+// Unwrap returns the error returned by the step.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// setupStep is a named step of a Setup and the steps it depends on.
+type setupStep struct {
+	name      string
+	dependsOn []string
+	run       func(ctx context.Context) error
+}
+
+// setupResult reports the end of a step to the scheduling loop of Setup.Run.
+type setupResult struct {
+	name      string
+	succeeded bool
+}
+
+// Setup runs the steps of a multi-entity setup as a dependency graph: each
+// step declares the steps it depends on and starts as soon as they have all
+// succeeded, so independent branches, such as the ledgers of different
+// organizations or the accounts of different ledgers, are created in
+// parallel instead of one after another.
+//
+// A step runs after the steps it depends on have returned, so it can read
+// the entities they stored in variables captured by its function without
+// further synchronization. Steps that don't depend on each other must not
+// write the same variables.
+//
+// Example use case: Creating an organization, its ledgers and their assets and accounts:
+//
+//	setup := concurrent.NewSetup()
+//
+//	var org *models.Organization
+//	setup.Step("org", func(ctx context.Context) (err error) {
+//	    org, err = orgGen.Generate(ctx, orgTemplate)
+//	    return err
+//	})
+//
+//	for i := range ledgers {
+//	    ledgerStep := fmt.Sprintf("ledger-%d", i)
+//	    setup.Step(ledgerStep, func(ctx context.Context) (err error) {
+//	        ledgers[i], err = ledGen.Generate(ctx, org.ID, ledgerTemplate)
+//	        return err
+//	    }, "org")
+//	    setup.Step(ledgerStep+"-assets", func(ctx context.Context) error {
+//	        return createAssets(ctx, org.ID, ledgers[i].ID)
+//	    }, ledgerStep)
+//	    setup.Step(ledgerStep+"-accounts", func(ctx context.Context) error {
+//	        return createAccounts(ctx, org.ID, ledgers[i].ID)
+//	    }, ledgerStep+"-assets")
+//	}
+//
+//	if err := setup.Run(ctx, concurrent.WithWorkers(8)); err != nil {
+//	    return err
+//	}
+type Setup struct {
+	steps map[string]*setupStep
+	order []string
+	err   error
+}
+
+// NewSetup returns an empty Setup.
+func NewSetup() *Setup {
+	return &Setup{steps: make(map[string]*setupStep)}
+}
+
+// Step adds a step named name, running fn once the steps named in dependsOn
+// have succeeded. The dependencies may be added after the step. Adding two
+// steps with the same name, or a step without a name or function, makes Run
+// fail.
+func (s *Setup) Step(name string, fn func(ctx context.Context) error, dependsOn ...string) *Setup {
+	switch {
+	case s.err != nil:
+	case strings.TrimSpace(name) == "":
+		s.err = errors.New("setup step name cannot be empty")
+	case fn == nil:
+		s.err = fmt.Errorf("setup step %q has no function", name)
+	case s.steps[name] != nil:
+		s.err = fmt.Errorf("duplicate setup step %q", name)
+	default:
+		s.steps[name] = &setupStep{name: name, dependsOn: dependsOn, run: fn}
+		s.order = append(s.order, name)
+	}
+
+	return s
+}
+
+// Len returns the number of steps.
+func (s *Setup) Len() int {
+	return len(s.order)
+}
+
+// Run runs the steps, at most as many at a time as WithWorkers allows (5 by
+// default), and returns once they have all returned. The steps run in a
+// Group, so WithRateLimit, WithLimiter, WithItemTimeout, WithItemSpan and
+// WithPanicHandler apply to each step.
+//
+// The first failing step cancels the context of the running steps and
+// prevents the start of the others; Run returns its error as a *StepError.
+// Run returns an error without running any step when a step depends on an
+// unknown step or the dependencies form a cycle (ErrSetupCycle).
+func (s *Setup) Run(ctx context.Context, opts ...PoolOption) error {
+	dependents, pending, err := s.graph()
+	if err != nil {
+		return err
+	}
+
+	g, gctx := NewGroup(ctx, opts...)
+	g.SetLimit(max(g.options.workers, 1))
+
+	// The steps wait for the limiter themselves, so that every started step reports back on done
+	limiter := g.limiter
+	g.limiter = nil
+
+	// buffered so that finished steps never wait for the scheduling loop
+	done := make(chan setupResult, len(s.order))
+
+	start := func(step *setupStep) {
+		g.GoContext(func(ctx context.Context) error {
+			succeeded := false
+			defer func() { done <- setupResult{name: step.name, succeeded: succeeded} }()
+
+			if limiter != nil {
+				if err := limiter.Wait(ctx); err != nil {
+					return err
+				}
+			}
+
+			if err := step.run(ctx); err != nil {
+				return &StepError{Step: step.name, Err: err}
+			}
+
+			succeeded = true
+
+			return nil
+		})
+	}
+
+	running, failed := 0, false
+
+	for _, name := range s.order {
+		if pending[name] == 0 {
+			start(s.steps[name])
+			running++
+		}
+	}
+
+	for running > 0 {
+		result := <-done
+		running--
+
+		// Once a step failed or ctx is done, let the running steps finish without starting new ones
+		failed = failed || !result.succeeded
+		if failed || gctx.Err() != nil {
+			continue
+		}
+
+		for _, next := range dependents[result.name] {
+			pending[next]--
+			if pending[next] == 0 {
+				start(s.steps[next])
+				running++
+			}
+		}
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	// ctx may have been canceled before the steps noticed it
+	return ctx.Err()
+}
+
+// graph validates the dependencies and returns the dependents of each step
+// and the number of dependencies of each step.
+func (s *Setup) graph() (map[string][]string, map[string]int, error) {
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+
+	dependents := make(map[string][]string, len(s.order))
+	pending := make(map[string]int, len(s.order))
+
+	for _, name := range s.order {
+		for _, dep := range s.steps[name].dependsOn {
+			if s.steps[dep] == nil {
+				return nil, nil, fmt.Errorf("setup step %q depends on unknown step %q", name, dep)
+			}
+
+			dependents[dep] = append(dependents[dep], name)
+			pending[name]++
+		}
+	}
+
+	// Kahn's algorithm: the steps never reached have a cyclic dependency
+	remaining := make(map[string]int, len(pending))
+	for name, n := range pending {
+		remaining[name] = n
+	}
+
+	queue := make([]string, 0, len(s.order))
+
+	for _, name := range s.order {
+		if remaining[name] == 0 {
+			queue = append(queue, name)
+		}
+	}
+
+	for i := 0; i < len(queue); i++ {
+		for _, next := range dependents[queue[i]] {
+			remaining[next]--
+			if remaining[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	if len(queue) < len(s.order) {
+		cyclic := make([]string, 0, len(s.order)-len(queue))
+
+		for _, name := range s.order {
+			if remaining[name] > 0 {
+				cyclic = append(cyclic, name)
+			}
+		}
+
+		return nil, nil, fmt.Errorf("%w: %s", ErrSetupCycle, strings.Join(cyclic, ", "))
+	}
+
+	return dependents, pending, nil
+}
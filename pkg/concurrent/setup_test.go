@@ -0,0 +1,217 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetupRunsDependenciesFirst(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+
+	record := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			order = append(order, name)
+
+			return nil
+		}
+	}
+
+	setup := NewSetup().
+		Step("accounts", record("accounts"), "assets", "routes").
+		Step("org", record("org")).
+		Step("ledger", record("ledger"), "org").
+		Step("assets", record("assets"), "ledger").
+		Step("routes", record("routes"), "ledger").
+		Step("transactions", record("transactions"), "accounts", "routes")
+
+	if err := setup.Run(context.Background(), WithWorkers(4)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	position := make(map[string]int, len(order))
+	for i, name := range order {
+		position[name] = i
+	}
+
+	if len(position) != setup.Len() {
+		t.Fatalf("Expected %d steps to run, got %v", setup.Len(), order)
+	}
+
+	for step, deps := range map[string][]string{
+		"ledger":       {"org"},
+		"assets":       {"ledger"},
+		"routes":       {"ledger"},
+		"accounts":     {"assets", "routes"},
+		"transactions": {"accounts", "routes"},
+	} {
+		for _, dep := range deps {
+			if position[dep] > position[step] {
+				t.Errorf("Expected %s to run before %s, got %v", dep, step, order)
+			}
+		}
+	}
+}
+
+func TestSetupRunsIndependentBranchesInParallel(t *testing.T) {
+	var active, peak atomic.Int32
+
+	branch := func(context.Context) error {
+		n := active.Add(1)
+		defer active.Add(-1)
+
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		return nil
+	}
+
+	setup := NewSetup().Step("org", func(context.Context) error { return nil })
+	for _, name := range []string{"ledger-1", "ledger-2", "ledger-3"} {
+		setup.Step(name, branch, "org")
+	}
+
+	if err := setup.Run(context.Background(), WithWorkers(3)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if peak.Load() != 3 {
+		t.Errorf("Expected the 3 ledgers to run concurrently, got at most %d", peak.Load())
+	}
+}
+
+func TestSetupRespectsWorkerLimit(t *testing.T) {
+	var active, peak atomic.Int32
+
+	setup := NewSetup()
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		setup.Step(name, func(context.Context) error {
+			n := active.Add(1)
+			defer active.Add(-1)
+
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+
+			return nil
+		})
+	}
+
+	if err := setup.Run(context.Background(), WithWorkers(2)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if peak.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent steps, got %d", peak.Load())
+	}
+}
+
+func TestSetupStopsAtFirstFailure(t *testing.T) {
+	errLedger := errors.New("ledger creation failed")
+
+	var accountsRan atomic.Bool
+
+	setup := NewSetup().
+		Step("org", func(context.Context) error { return nil }).
+		Step("ledger", func(context.Context) error { return errLedger }, "org").
+		Step("accounts", func(context.Context) error {
+			accountsRan.Store(true)
+			return nil
+		}, "ledger").
+		Step("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, "org")
+
+	err := setup.Run(context.Background())
+	if !errors.Is(err, errLedger) {
+		t.Fatalf("Expected the ledger error, got %v", err)
+	}
+
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != "ledger" {
+		t.Errorf("Expected a StepError for the ledger step, got %v", err)
+	}
+
+	if accountsRan.Load() {
+		t.Error("Expected the dependents of the failed step not to run")
+	}
+}
+
+func TestSetupRecoversPanics(t *testing.T) {
+	var ran atomic.Bool
+
+	setup := NewSetup().
+		Step("panics", func(context.Context) error { panic("boom") }).
+		Step("after", func(context.Context) error {
+			ran.Store(true)
+			return nil
+		}, "panics")
+
+	var panicErr *PanicError
+	if err := setup.Run(context.Background()); !errors.As(err, &panicErr) {
+		t.Fatalf("Expected a PanicError, got %v", err)
+	}
+
+	if ran.Load() {
+		t.Error("Expected the dependents of the panicking step not to run")
+	}
+}
+
+func TestSetupValidation(t *testing.T) {
+	noop := func(context.Context) error { return nil }
+
+	tests := []struct {
+		name  string
+		setup *Setup
+	}{
+		{"unknown dependency", NewSetup().Step("a", noop, "missing")},
+		{"duplicate step", NewSetup().Step("a", noop).Step("a", noop)},
+		{"empty name", NewSetup().Step(" ", noop)},
+		{"nil function", NewSetup().Step("a", nil)},
+		{"cycle", NewSetup().Step("a", noop, "c").Step("b", noop, "a").Step("c", noop, "b").Step("d", noop)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.setup.Run(context.Background()); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	err := NewSetup().Step("a", noop, "b").Step("b", noop, "a").Run(context.Background())
+	if !errors.Is(err, ErrSetupCycle) {
+		t.Errorf("Expected ErrSetupCycle, got %v", err)
+	}
+}
+
+func TestSetupCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := NewSetup().Step("a", func(context.Context) error { return nil }).Run(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
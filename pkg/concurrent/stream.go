@@ -0,0 +1,197 @@
+package concurrent
+
+import (
+	"context"
+	"sync"
+)
+
+// WorkerPoolStream processes items like WorkerPool, but hands each result to
+// fn as soon as it is available instead of collecting them, so that the
+// memory held by the pool doesn't grow with the results of a large run.
+//
+// fn is called from the calling goroutine, one result at a time, in
+// completion order; WithUnorderedResults has no effect. While fn runs the
+// workers keep going until the result buffer is full, then wait for it, so a
+// slow fn slows the pool down rather than piling results up. The items not
+// started because ctx was done are passed to fn last, in input order, with a
+// cancellation error, as in WorkerPool.
+//
+// If fn returns an error the pool stops: no further item is started, the
+// items in flight see their context canceled (unless WithDrainOnCancel is
+// used), their results are discarded, and WorkerPoolStream returns the error.
+// Otherwise it returns nil once every item has been passed to fn.
+//
+// Memory ceiling: besides items, the pool holds at most workers + 2 x
+// bufferSize results at once (those being computed and those in the item and
+// result buffers, see WithWorkers and WithBufferSize), and one byte per item
+// to tell the items not started apart.
+//
+// Example:
+//
+//	err := concurrent.WorkerPoolStream(ctx, accountIDs, fetchAccount,
+//	    func(r concurrent.Result[string, *models.Account]) error {
+//	        if r.Error != nil {
+//	            return r.Error
+//	        }
+//	        return writer.Write(r.Value)
+//	    },
+//	    concurrent.WithWorkers(20),
+//	)
+func WorkerPoolStream[T, R any](
+	ctx context.Context,
+	items []T,
+	workFn WorkFunc[T, R],
+	fn func(result Result[T, R]) error,
+	opts ...PoolOption,
+) error {
+	options := applyPoolOptions(opts...)
+
+	poolCtx, stop := context.WithCancelCause(ctx)
+	defer stop(nil)
+
+	itemCh := make(chan indexedItem[T], options.bufferSize)
+	resultCh := make(chan Result[T, R], options.bufferSize)
+
+	var wg sync.WaitGroup
+
+	startWorkers(poolCtx, &wg, itemCh, resultCh, workFn, options)
+	startItemSender(poolCtx, &wg, items, itemCh, resultCh)
+
+	delivered := make([]bool, len(items))
+
+	var fnErr error
+
+	for r := range resultCh {
+		if fnErr != nil {
+			// Drain the results in flight so that the workers can exit
+			continue
+		}
+
+		delivered[r.Index] = true
+
+		if err := fn(r); err != nil {
+			fnErr = err
+			stop(err)
+		}
+	}
+
+	if fnErr != nil {
+		return fnErr
+	}
+
+	for i, item := range items {
+		if delivered[i] {
+			continue
+		}
+
+		if err := fn(cancelledResult[T, R](ctx, item, i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BatchStream processes items in batches like Batch, but hands the result of
+// each item to fn as soon as its batch completes instead of collecting them.
+// The results of a batch are passed in input order, the batches in
+// completion order. fn is called and stops the run as in WorkerPoolStream.
+//
+// Memory ceiling: besides items, at most workers + 2 x bufferSize batch
+// results are held at once, bufferSize counting batches, and one byte per
+// batch.
+func BatchStream[T, R any](
+	ctx context.Context,
+	items []T,
+	batchSize int,
+	workFn func(ctx context.Context, batch []T) ([]R, error),
+	fn func(result Result[T, R]) error,
+	opts ...PoolOption,
+) error {
+	if batchSize <= 0 {
+		batchSize = 10 // Default batch size
+	}
+
+	return WorkerPoolStream(ctx, splitBatches(items, batchSize), func(ctx context.Context, batch []T) ([]R, error) {
+		return workFn(ctx, batch)
+	}, func(br Result[[]T, []R]) error {
+		return eachBatchResult(br, batchSize, fn)
+	}, opts...)
+}
+
+// BatchItemsStream processes items in batches like BatchItems, but hands the
+// result of each item to fn as soon as its batch completes instead of
+// collecting them. The results of a batch are passed in input order, the
+// batches in completion order. fn is called and stops the run as in
+// WorkerPoolStream.
+//
+// Memory ceiling: as BatchStream.
+func BatchItemsStream[T, R any](
+	ctx context.Context,
+	items []T,
+	batchSize int,
+	workFn func(ctx context.Context, batch []T) ([]ItemResult[R], error),
+	fn func(result Result[T, R]) error,
+	opts ...PoolOption,
+) error {
+	if batchSize <= 0 {
+		batchSize = 10 // Default batch size
+	}
+
+	return WorkerPoolStream(ctx, splitBatches(items, batchSize), func(ctx context.Context, batch []T) ([]ItemResult[R], error) {
+		return workFn(ctx, batch)
+	}, func(br Result[[]T, []ItemResult[R]]) error {
+		return eachBatchItemResult(br, batchSize, fn)
+	}, opts...)
+}
+
+// eachBatchResult calls fn with the result of each item of a batch processed
+// by Batch: an error of the batch applies to all its items, and the items
+// without a value are left out.
+func eachBatchResult[T, R any](br Result[[]T, []R], batchSize int, fn func(Result[T, R]) error) error {
+	if br.Error != nil {
+		for i, item := range br.Item {
+			if err := fn(Result[T, R]{Item: item, Error: br.Error, Index: br.Index*batchSize + i}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for i, val := range br.Value {
+		if i >= len(br.Item) {
+			break
+		}
+
+		if err := fn(Result[T, R]{Item: br.Item[i], Value: val, Index: br.Index*batchSize + i}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// eachBatchItemResult calls fn with the result of each item of a batch
+// processed by BatchItems.
+func eachBatchItemResult[T, R any](br Result[[]T, []ItemResult[R]], batchSize int, fn func(Result[T, R]) error) error {
+	for i, item := range br.Item {
+		result := Result[T, R]{Item: item, Index: br.Index*batchSize + i}
+
+		switch {
+		case br.Error != nil:
+			result.Error = br.Error
+		case i < len(br.Value):
+			result.Value = br.Value[i].Value
+			result.Error = br.Value[i].Error
+		default:
+			result.Error = ErrMissingItemResult
+		}
+
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	pkgerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+func TestWorkerPoolStream(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	var indices []int
+
+	err := WorkerPoolStream(context.Background(), items, func(_ context.Context, item int) (int, error) {
+		return item * 2, nil
+	}, func(r Result[int, int]) error {
+		if r.Value != r.Item*2 || r.Index != r.Item {
+			t.Errorf("unexpected result %+v", r)
+		}
+
+		indices = append(indices, r.Index)
+
+		return nil
+	}, WithWorkers(4), WithBufferSize(2))
+	if err != nil {
+		t.Fatalf("WorkerPoolStream failed: %v", err)
+	}
+
+	sort.Ints(indices)
+
+	if len(indices) != len(items) || indices[0] != 0 || indices[len(indices)-1] != len(items)-1 {
+		t.Errorf("expected one result per item, got %d", len(indices))
+	}
+}
+
+func TestWorkerPoolStream_StopsOnError(t *testing.T) {
+	items := make([]int, 1000)
+	errStop := errors.New("stop")
+
+	var started atomic.Int32
+
+	calls := 0
+
+	err := WorkerPoolStream(context.Background(), items, func(_ context.Context, item int) (int, error) {
+		started.Add(1)
+		return item, nil
+	}, func(Result[int, int]) error {
+		calls++
+		if calls == 3 {
+			return errStop
+		}
+
+		return nil
+	}, WithWorkers(2), WithBufferSize(1))
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected the error of fn, got %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("expected fn not to be called after its error, got %d calls", calls)
+	}
+
+	if n := started.Load(); n >= int32(len(items)) {
+		t.Errorf("expected the pool to stop early, %d items started", n)
+	}
+}
+
+func TestWorkerPoolStream_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cancelled := 0
+
+	err := WorkerPoolStream(ctx, []int{1, 2, 3}, func(_ context.Context, item int) (int, error) {
+		return item, nil
+	}, func(r Result[int, int]) error {
+		if pkgerrors.IsCancellationError(r.Error) {
+			cancelled++
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WorkerPoolStream failed: %v", err)
+	}
+
+	if cancelled != 3 {
+		t.Errorf("expected 3 cancelled results, got %d", cancelled)
+	}
+}
+
+func TestBatchItemsStream(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	errOdd := errors.New("odd")
+
+	results := map[int]Result[int, int]{}
+
+	err := BatchItemsStream(context.Background(), items, 2, func(_ context.Context, batch []int) ([]ItemResult[int], error) {
+		out := make([]ItemResult[int], len(batch))
+		for i, item := range batch {
+			if item%2 == 1 {
+				out[i].Error = errOdd
+			} else {
+				out[i].Value = item * 10
+			}
+		}
+
+		return out, nil
+	}, func(r Result[int, int]) error {
+		results[r.Index] = r
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("BatchItemsStream failed: %v", err)
+	}
+
+	if len(results) != len(items) {
+		t.Fatalf("expected %d results, got %d", len(items), len(results))
+	}
+
+	if results[1].Value != 20 || !errors.Is(results[4].Error, errOdd) {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
+
+func TestBatchStream(t *testing.T) {
+	errBatch := errors.New("batch failed")
+
+	var failed, succeeded int
+
+	err := BatchStream(context.Background(), []int{1, 2, 3, 4}, 2, func(_ context.Context, batch []int) ([]int, error) {
+		if batch[0] == 3 {
+			return nil, errBatch
+		}
+
+		return batch, nil
+	}, func(r Result[int, int]) error {
+		if r.Error != nil {
+			failed++
+		} else {
+			succeeded++
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("BatchStream failed: %v", err)
+	}
+
+	if failed != 2 || succeeded != 2 {
+		t.Errorf("expected 2 failed and 2 succeeded items, got %d and %d", failed, succeeded)
+	}
+}
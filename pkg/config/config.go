@@ -9,8 +9,10 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
@@ -19,6 +21,8 @@ import (
 
 	auth "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/access-manager"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/rounding"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/version"
 )
 
@@ -97,6 +101,19 @@ type Config struct {
 	// If nil, a default client will be created with the configured timeout.
 	HTTPClient *http.Client
 
+	// Dialer opens the connections of the HTTP client instead of TCP. Set it
+	// with WithDialer, WithUnixSocket or a unix:// base URL.
+	Dialer DialFunc
+
+	// dialerClient is HTTPClient with Dialer, built from dialerClientFrom
+	dialerClient     *http.Client
+	dialerClientFrom *http.Client
+
+	// ProbeTimeout enables the connectivity probe of NewConfig and bounds the
+	// check of each service URL. Zero disables it. Set it with
+	// WithConnectivityProbe.
+	ProbeTimeout time.Duration
+
 	// Timeout is the timeout for HTTP requests.
 	Timeout time.Duration
 
@@ -112,12 +129,47 @@ type Config struct {
 	// Debug enables verbose logging of requests and responses.
 	Debug bool
 
+	// DebugConnections traces the connections of the requests: DNS, connect
+	// and TLS times and whether the connection was reused. Set it with
+	// WithConnectionDebug.
+	DebugConnections bool
+
 	// ObservabilityProvider for tracing, metrics, and logging.
 	ObservabilityProvider observability.Provider
 
 	// EnableIdempotency enables automatic generation of idempotency keys.
 	EnableIdempotency bool
 
+	// ReadDeadline, WriteDeadline and ListDeadline bound calls made with a
+	// context that has no deadline: single resource reads, calls that create,
+	// update or delete, and list calls respectively. Zero leaves the class
+	// unbounded. Set them with WithDefaultDeadlines.
+	ReadDeadline  time.Duration
+	WriteDeadline time.Duration
+	ListDeadline  time.Duration
+
+	// SlowCallThreshold is the duration over which a call, retries
+	// included, is logged as a warning with its operation and IDs and its
+	// span marked as slow. Zero disables it. Set it with
+	// WithSlowCallThreshold.
+	SlowCallThreshold time.Duration
+
+	// ValidationMode selects how requests failing client-side validation are
+	// handled. Setting it also checks the metadata of every request body
+	// against the limits of the Midaz backend. Empty keeps the default:
+	// invalid inputs are rejected and metadata isn't checked. Set it with
+	// WithValidationMode or WithStrictValidation.
+	ValidationMode validation.Mode
+
+	// MaxMetadataKeys bounds the number of keys of each metadata object
+	// under a validation mode. Zero leaves it unbounded.
+	MaxMetadataKeys int
+
+	// RoundingPolicy selects how amounts with more decimal places than their
+	// asset scale are handled by the amount helpers, builders and generators
+	// it is passed to. Empty rejects them. Set it with WithRoundingPolicy.
+	RoundingPolicy rounding.Policy
+
 	// TenantID is the default tenant identifier sent as X-Tenant-ID on every request.
 	// It can be set via the MIDAZ_TENANT_ID environment variable or the WithTenantID option.
 	// Per-request overrides via entities.WithTenantID(ctx, id) take precedence.
@@ -126,6 +178,12 @@ type Config struct {
 	// tenantIDSet tracks whether WithTenantID was explicitly called, allowing
 	// an empty value to clear any environment-provided default.
 	tenantIDSet bool
+
+	// sources tracks where each setting comes from, see Explain
+	sources map[string]Source
+
+	// applying is the source of the options being applied, see WithSource
+	applying *Source
 }
 
 // Option is a function that configures a Config.
@@ -143,6 +201,8 @@ type Option func(*Config) error
 func WithEnvironment(env Environment) Option {
 	return func(c *Config) error {
 		c.Environment = env
+		c.record("WithEnvironment", SettingEnvironment)
+
 		return nil
 	}
 }
@@ -160,7 +220,7 @@ func WithOnboardingURL(onboardingURL string) Option {
 	return func(c *Config) error {
 		// Validate URL
 		if err := parseURL(onboardingURL); err != nil {
-			return fmt.Errorf("invalid onboarding URL: %w", err)
+			return &URLError{Name: string(ServiceOnboarding), URL: onboardingURL, Err: err}
 		}
 
 		if c.ServiceURLs == nil {
@@ -168,6 +228,7 @@ func WithOnboardingURL(onboardingURL string) Option {
 		}
 
 		c.ServiceURLs[ServiceOnboarding] = onboardingURL
+		c.record("WithOnboardingURL", SettingOnboardingURL)
 
 		return nil
 	}
@@ -186,7 +247,7 @@ func WithTransactionURL(transactionURL string) Option {
 	return func(c *Config) error {
 		// Validate URL
 		if err := parseURL(transactionURL); err != nil {
-			return fmt.Errorf("invalid transaction URL: %w", err)
+			return &URLError{Name: string(ServiceTransaction), URL: transactionURL, Err: err}
 		}
 
 		if c.ServiceURLs == nil {
@@ -194,6 +255,7 @@ func WithTransactionURL(transactionURL string) Option {
 		}
 
 		c.ServiceURLs[ServiceTransaction] = transactionURL
+		c.record("WithTransactionURL", SettingTransactionURL)
 
 		return nil
 	}
@@ -203,6 +265,10 @@ func WithTransactionURL(transactionURL string) Option {
 // Service-specific ports and paths will be automatically added.
 // This is useful for connecting to custom deployments.
 //
+// A unix:///path/to.sock base URL sends the requests over that Unix domain
+// socket, see WithUnixSocket; the optional host query parameter sets the
+// virtual host of the requests, e.g. unix:///var/run/mesh.sock?host=midaz.internal.
+//
 // Parameters:
 //   - baseURL: The base URL (e.g., "http://example.com")
 //
@@ -211,9 +277,21 @@ func WithTransactionURL(transactionURL string) Option {
 //   - May return an error if the URL is invalid
 func WithBaseURL(baseURL string) Option {
 	return func(c *Config) error {
+		if strings.HasPrefix(baseURL, "unix:") {
+			socket, httpURL, err := parseUnixBaseURL(baseURL)
+			if err != nil {
+				return &URLError{Name: "base", URL: baseURL, Err: err}
+			}
+
+			c.Dialer = UnixSocketDialer(socket)
+			c.record("WithBaseURL", SettingDialer)
+
+			baseURL = httpURL
+		}
+
 		// Validate the base URL
 		if err := parseURL(baseURL); err != nil {
-			return fmt.Errorf("invalid base URL: %w", err)
+			return &URLError{Name: "base", URL: baseURL, Err: err}
 		}
 
 		// Remove trailing slash if present
@@ -233,6 +311,8 @@ func WithBaseURL(baseURL string) Option {
 			c.ServiceURLs[ServiceTransaction] = fmt.Sprintf("%s/transaction", baseURL)
 		}
 
+		c.record("WithBaseURL", SettingOnboardingURL, SettingTransactionURL)
+
 		return nil
 	}
 }
@@ -252,6 +332,7 @@ func WithHTTPClient(client *http.Client) Option {
 		}
 
 		c.HTTPClient = client
+		c.record("WithHTTPClient", SettingHTTPClient)
 
 		return nil
 	}
@@ -271,6 +352,57 @@ func WithTimeout(timeout time.Duration) Option {
 		}
 
 		c.Timeout = timeout
+		c.record("WithTimeout", SettingTimeout)
+
+		return nil
+	}
+}
+
+// WithDefaultDeadlines sets the deadlines applied to calls whose context has
+// no deadline, per operation class, so a service that forgets to set
+// timeouts can't block forever. A call's own context deadline always wins.
+// A zero duration leaves the class unbounded.
+//
+// Parameters:
+//   - read: The deadline of calls getting a single resource
+//   - write: The deadline of calls creating, updating or deleting resources
+//   - list: The deadline of calls listing resources
+//
+// Returns:
+//   - Option: A function that sets the default deadlines on a Config
+func WithDefaultDeadlines(read, write, list time.Duration) Option {
+	return func(c *Config) error {
+		if read < 0 || write < 0 || list < 0 {
+			return errors.New("deadlines cannot be negative")
+		}
+
+		c.ReadDeadline = read
+		c.WriteDeadline = write
+		c.ListDeadline = list
+		c.record("WithDefaultDeadlines", SettingReadDeadline, SettingWriteDeadline, SettingListDeadline)
+
+		return nil
+	}
+}
+
+// WithSlowCallThreshold logs a warning, with the operation and the IDs of
+// its path, for every call taking longer than threshold, retries included,
+// and marks its span, so backend latency regressions show up without
+// dashboards. Zero disables it.
+//
+// Parameters:
+//   - threshold: The duration over which a call is slow
+//
+// Returns:
+//   - Option: A function that sets the slow call threshold on a Config
+func WithSlowCallThreshold(threshold time.Duration) Option {
+	return func(c *Config) error {
+		if threshold < 0 {
+			return errors.New("slow call threshold cannot be negative")
+		}
+
+		c.SlowCallThreshold = threshold
+		c.record("WithSlowCallThreshold", SettingSlowCallThreshold)
 
 		return nil
 	}
@@ -290,6 +422,7 @@ func WithUserAgent(userAgent string) Option {
 		}
 
 		c.UserAgent = userAgent
+		c.record("WithUserAgent", SettingUserAgent)
 
 		return nil
 	}
@@ -321,6 +454,7 @@ func WithRetryConfig(maxRetries int, minWait, maxWait time.Duration) Option {
 		c.MaxRetries = maxRetries
 		c.RetryWaitMin = minWait
 		c.RetryWaitMax = maxWait
+		c.record("WithRetryConfig", SettingMaxRetries, SettingRetryWaitMin, SettingRetryWaitMax)
 
 		return nil
 	}
@@ -336,6 +470,7 @@ func WithRetryConfig(maxRetries int, minWait, maxWait time.Duration) Option {
 func WithRetries(enable bool) Option {
 	return func(c *Config) error {
 		c.EnableRetries = enable
+		c.record("WithRetries", SettingEnableRetries)
 
 		return nil
 	}
@@ -352,6 +487,26 @@ func WithRetries(enable bool) Option {
 func WithDebug(enable bool) Option {
 	return func(c *Config) error {
 		c.Debug = enable
+		c.record("WithDebug", SettingDebug)
+
+		return nil
+	}
+}
+
+// WithConnectionDebug enables or disables connection tracing.
+// Each request logs its DNS, connect, TLS and first byte times and whether it
+// reused a connection, to attribute throughput plateaus to connection churn
+// or to server latency.
+//
+// Parameters:
+//   - enable: Whether to trace connections
+//
+// Returns:
+//   - Option: A function that sets the connection debug flag on a Config
+func WithConnectionDebug(enable bool) Option {
+	return func(c *Config) error {
+		c.DebugConnections = enable
+		c.record("WithConnectionDebug", SettingDebugConnections)
 
 		return nil
 	}
@@ -367,6 +522,7 @@ func WithDebug(enable bool) Option {
 func WithObservabilityProvider(provider observability.Provider) Option {
 	return func(c *Config) error {
 		c.ObservabilityProvider = provider
+		c.record("WithObservabilityProvider", SettingObservabilityProvider)
 
 		return nil
 	}
@@ -382,6 +538,103 @@ func WithObservabilityProvider(provider observability.Provider) Option {
 func WithIdempotency(enable bool) Option {
 	return func(c *Config) error {
 		c.EnableIdempotency = enable
+		c.record("WithIdempotency", SettingEnableIdempotency)
+
+		return nil
+	}
+}
+
+// WithValidationMode sets how requests failing client-side validation are
+// handled: validation.ModeStrict rejects them before they are sent,
+// validation.ModeLenient reports them as warnings and sends them anyway, and
+// validation.ModeOff leaves validation to the server. Under strict and
+// lenient mode, the metadata of every request body is also checked against
+// the limits of the Midaz backend (key length, string value length and
+// value types) and the key budget set with WithMaxMetadataKeys.
+// entities.WithValidationMode overrides the mode for a single call.
+//
+// Parameters:
+//   - mode: The validation mode
+//
+// Returns:
+//   - Option: A function that sets the validation mode on a Config
+func WithValidationMode(mode validation.Mode) Option {
+	return func(c *Config) error {
+		if !mode.IsValid() {
+			return fmt.Errorf("invalid validation mode %q", mode)
+		}
+
+		c.ValidationMode = mode
+		c.record("WithValidationMode", SettingValidationMode)
+
+		return nil
+	}
+}
+
+// WithStrictValidation enables or disables strict validation. Enabling it is
+// WithValidationMode(validation.ModeStrict): a request with invalid input or
+// metadata fails with a validation error before it is sent. Disabling it
+// restores the default validation.
+//
+// Parameters:
+//   - strict: Whether to validate request metadata before sending
+//
+// Returns:
+//   - Option: A function that sets strict validation on a Config
+func WithStrictValidation(strict bool) Option {
+	return func(c *Config) error {
+		c.ValidationMode = ""
+		if strict {
+			c.ValidationMode = validation.ModeStrict
+		}
+
+		c.record("WithStrictValidation", SettingValidationMode)
+
+		return nil
+	}
+}
+
+// WithMaxMetadataKeys sets the maximum number of keys of each metadata object
+// under a validation mode. Zero leaves it unbounded.
+//
+// Parameters:
+//   - count: The maximum number of metadata keys
+//
+// Returns:
+//   - Option: A function that sets the metadata key budget on a Config
+func WithMaxMetadataKeys(count int) Option {
+	return func(c *Config) error {
+		if count < 0 {
+			return errors.New("max metadata keys cannot be negative")
+		}
+
+		c.MaxMetadataKeys = count
+		c.record("WithMaxMetadataKeys", SettingMaxMetadataKeys)
+
+		return nil
+	}
+}
+
+// WithRoundingPolicy sets how amounts with more decimal places than their
+// asset scale are handled: rounding.PolicyError rejects them,
+// rounding.PolicyHalfEven rounds them half to even and
+// rounding.PolicyTruncate drops the extra places. Pass the configured policy
+// to transaction.ParseAmount, data.CrossCurrencyParams or
+// data.AmountGenerator.SetRoundingPolicy so they all agree.
+//
+// Parameters:
+//   - policy: The rounding policy
+//
+// Returns:
+//   - Option: A function that sets the rounding policy on a Config
+func WithRoundingPolicy(policy rounding.Policy) Option {
+	return func(c *Config) error {
+		if !policy.IsValid() {
+			return fmt.Errorf("invalid rounding policy %q", policy)
+		}
+
+		c.RoundingPolicy = policy
+		c.record("WithRoundingPolicy", SettingRoundingPolicy)
 
 		return nil
 	}
@@ -401,6 +654,7 @@ func WithTenantID(tenantID string) Option {
 	return func(c *Config) error {
 		c.TenantID = strings.TrimSpace(tenantID)
 		c.tenantIDSet = true
+		c.record("WithTenantID", SettingTenantID)
 
 		return nil
 	}
@@ -416,6 +670,7 @@ func WithTenantID(tenantID string) Option {
 func WithAccessManager(accessManager auth.AccessManager) Option {
 	return func(c *Config) error {
 		c.AccessManager = accessManager
+		c.record("WithAccessManager", SettingAccessManager)
 
 		return nil
 	}
@@ -426,6 +681,7 @@ func WithAccessManager(accessManager auth.AccessManager) Option {
 //
 // Environment variables:
 // - MIDAZ_ENVIRONMENT: The environment to use (local, development, production)
+// - MIDAZ_DETECT_ENVIRONMENT: Detect the environment when MIDAZ_ENVIRONMENT is unset (true/false), see WithDetectedEnvironment
 // - PLUGIN_AUTH_ENABLED: Enable access manager authentication (true/false)
 // - PLUGIN_AUTH_ADDRESS: The address of the access manager service
 // - MIDAZ_CLIENT_ID: The client ID for authentication
@@ -436,6 +692,7 @@ func WithAccessManager(accessManager auth.AccessManager) Option {
 // - MIDAZ_BASE_URL: The base URL for all services
 // - MIDAZ_TIMEOUT: The timeout in seconds for HTTP requests
 // - MIDAZ_DEBUG: Enable debug mode (true/false)
+// - MIDAZ_DEBUG_CONNECTIONS: Trace the connections of requests (true/false)
 // - MIDAZ_MAX_RETRIES: Maximum number of retries
 // - MIDAZ_IDEMPOTENCY: Enable idempotency (true/false)
 // - MIDAZ_TENANT_ID: Default tenant ID sent as X-Tenant-ID header
@@ -469,6 +726,10 @@ func FromEnvironment() Option {
 func configureEnvironment(c *Config) error {
 	env := os.Getenv("MIDAZ_ENVIRONMENT")
 	if env == "" {
+		if os.Getenv("MIDAZ_DETECT_ENVIRONMENT") == boolTrue {
+			return WithDetectedEnvironment()(c)
+		}
+
 		return nil
 	}
 
@@ -483,6 +744,8 @@ func configureEnvironment(c *Config) error {
 		return fmt.Errorf("invalid environment: %s", env)
 	}
 
+	c.recordSource(envSource("MIDAZ_ENVIRONMENT"), SettingEnvironment)
+
 	return nil
 }
 
@@ -493,6 +756,7 @@ func configureAccessManager(c *Config) {
 		c.AccessManager.ClientID = os.Getenv("MIDAZ_CLIENT_ID")
 		c.AccessManager.ClientSecret = os.Getenv("MIDAZ_CLIENT_SECRET")
 		c.AccessManager.Enabled = enable == boolTrue
+		c.recordSource(envSource("PLUGIN_AUTH_ENABLED", "PLUGIN_AUTH_ADDRESS", "MIDAZ_CLIENT_ID", "MIDAZ_CLIENT_SECRET"), SettingAccessManager)
 	}
 }
 
@@ -500,6 +764,7 @@ func configureAccessManager(c *Config) {
 func configureUserAgent(c *Config) {
 	if userAgent := os.Getenv("MIDAZ_USER_AGENT"); userAgent != "" {
 		c.UserAgent = userAgent
+		c.recordSource(envSource("MIDAZ_USER_AGENT"), SettingUserAgent)
 	}
 }
 
@@ -507,7 +772,7 @@ func configureUserAgent(c *Config) {
 func configureURLs(c *Config) error {
 	// URLs take precedence in this order: specific URL > base URL > environment default
 	if baseURL := os.Getenv("MIDAZ_BASE_URL"); baseURL != "" {
-		if err := WithBaseURL(baseURL)(c); err != nil {
+		if err := WithSource(envSource("MIDAZ_BASE_URL"), WithBaseURL(baseURL))(c); err != nil {
 			return err
 		}
 	}
@@ -518,13 +783,13 @@ func configureURLs(c *Config) error {
 // configureSpecificURLs sets specific service URLs that override base URL
 func configureSpecificURLs(c *Config) error {
 	if onboardingURL := os.Getenv("MIDAZ_ONBOARDING_URL"); onboardingURL != "" {
-		if err := WithOnboardingURL(onboardingURL)(c); err != nil {
+		if err := WithSource(envSource("MIDAZ_ONBOARDING_URL"), WithOnboardingURL(onboardingURL))(c); err != nil {
 			return err
 		}
 	}
 
 	if transactionURL := os.Getenv("MIDAZ_TRANSACTION_URL"); transactionURL != "" {
-		if err := WithTransactionURL(transactionURL)(c); err != nil {
+		if err := WithSource(envSource("MIDAZ_TRANSACTION_URL"), WithTransactionURL(transactionURL))(c); err != nil {
 			return err
 		}
 	}
@@ -554,6 +819,7 @@ func configureTimeout(c *Config) error {
 	}
 
 	c.Timeout = time.Duration(seconds) * time.Second
+	c.recordSource(envSource("MIDAZ_TIMEOUT"), SettingTimeout)
 
 	return nil
 }
@@ -571,6 +837,7 @@ func configureRetries(c *Config) error {
 	}
 
 	c.MaxRetries = maxRetries
+	c.recordSource(envSource("MIDAZ_MAX_RETRIES"), SettingMaxRetries)
 
 	return nil
 }
@@ -579,15 +846,23 @@ func configureRetries(c *Config) error {
 func configureOptionalSettings(c *Config) {
 	if debug := os.Getenv("MIDAZ_DEBUG"); debug == boolTrue {
 		c.Debug = true
+		c.recordSource(envSource("MIDAZ_DEBUG"), SettingDebug)
+	}
+
+	if debug := os.Getenv("MIDAZ_DEBUG_CONNECTIONS"); debug == boolTrue {
+		c.DebugConnections = true
+		c.recordSource(envSource("MIDAZ_DEBUG_CONNECTIONS"), SettingDebugConnections)
 	}
 
 	if idempotency := os.Getenv("MIDAZ_IDEMPOTENCY"); idempotency != "" {
 		c.EnableIdempotency = idempotency == boolTrue
+		c.recordSource(envSource("MIDAZ_IDEMPOTENCY"), SettingEnableIdempotency)
 	}
 
 	if !c.tenantIDSet {
 		if tenantID := strings.TrimSpace(os.Getenv("MIDAZ_TENANT_ID")); tenantID != "" {
 			c.TenantID = tenantID
+			c.recordSource(envSource("MIDAZ_TENANT_ID"), SettingTenantID)
 		}
 	}
 }
@@ -627,6 +902,8 @@ func NewConfig(options ...Option) (*Config, error) {
 		EnableIdempotency: DefaultEnableIdempotency,
 	}
 
+	config.recordSource(Source{Kind: SourceDefault}, settings...)
+
 	// Apply default URLs based on environment
 	if err := setDefaultServiceURLs(config); err != nil {
 		return nil, err
@@ -644,6 +921,7 @@ func NewConfig(options ...Option) (*Config, error) {
 		config.HTTPClient = &http.Client{
 			Timeout: config.Timeout,
 		}
+		config.recordSource(Source{Kind: SourceDefault, Name: "built with the configured timeout"}, SettingHTTPClient)
 	}
 
 	// Validate required fields
@@ -651,6 +929,12 @@ func NewConfig(options ...Option) (*Config, error) {
 		return nil, err
 	}
 
+	if config.ProbeTimeout > 0 {
+		if err := config.Probe(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+
 	return config, nil
 }
 
@@ -674,6 +958,8 @@ func setDefaultServiceURLs(config *Config) error {
 		return fmt.Errorf("unknown environment: %s", config.Environment)
 	}
 
+	config.recordSource(Source{Kind: SourceDefault, Name: fmt.Sprintf("%s environment", config.Environment)}, SettingOnboardingURL, SettingTransactionURL)
+
 	return nil
 }
 
@@ -688,6 +974,13 @@ func validateConfig(config *Config) error {
 		return errors.New("transaction URL is required")
 	}
 
+	// URLs assigned to ServiceURLs directly skipped the checks of the options
+	for service, serviceURL := range config.ServiceURLs {
+		if err := validateURL(serviceURL); err != nil {
+			return &URLError{Name: string(service), URL: serviceURL, Err: err}
+		}
+	}
+
 	// When plugin auth is enabled, we require the plugin auth address
 	if config.AccessManager.Enabled && config.AccessManager.Address == "" {
 		// But for tests, we'll skip this check
@@ -696,9 +989,25 @@ func validateConfig(config *Config) error {
 		}
 	}
 
+	if _, err := config.HTTPClientWithDialer(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// Clone returns a copy of the configuration whose service URLs can be
+// changed without affecting c. The HTTP client, the dialer and the
+// observability provider are shared.
+func (c *Config) Clone() *Config {
+	clone := *c
+	clone.ServiceURLs = maps.Clone(c.ServiceURLs)
+	clone.sources = maps.Clone(c.sources)
+	clone.applying = nil
+
+	return &clone
+}
+
 // GetBaseURLs converts ServiceURLs to the map format expected by the entity layer.
 func (c *Config) GetBaseURLs() map[string]string {
 	result := make(map[string]string)
@@ -709,11 +1018,6 @@ func (c *Config) GetBaseURLs() map[string]string {
 	return result
 }
 
-// GetHTTPClient returns the HTTP client to use for requests.
-func (c *Config) GetHTTPClient() *http.Client {
-	return c.HTTPClient
-}
-
 // GetPluginAuth returns the plugin authentication configuration.
 func (c *Config) GetPluginAuth() auth.AccessManager {
 	// Return a copy of the plugin auth configuration
@@ -733,6 +1037,22 @@ func (c *Config) GetObservabilityProvider() observability.Provider {
 // parseURL validates that a URL is properly formatted.
 // It also warns (via stderr) if using HTTP instead of HTTPS for non-localhost URLs.
 func parseURL(rawURL string) error {
+	if err := validateURL(rawURL); err != nil {
+		return err
+	}
+
+	parsedURL, _ := url.Parse(rawURL) //nolint:errcheck // validated above
+
+	// Warn about insecure HTTP connections (except for localhost/development)
+	if parsedURL.Scheme == "http" && !isLocalhost(parsedURL.Host) {
+		fmt.Fprintf(os.Stderr, "[Midaz SDK Warning] Using insecure HTTP connection to %s. Consider using HTTPS for production.\n", parsedURL.Host)
+	}
+
+	return nil
+}
+
+// validateURL checks that a URL is an absolute http or https URL with a host.
+func validateURL(rawURL string) error {
 	parsedURL, err := url.Parse(rawURL)
 	if err != nil {
 		return err
@@ -743,9 +1063,12 @@ func parseURL(rawURL string) error {
 		return errors.New("URL must include scheme and host")
 	}
 
-	// Warn about insecure HTTP connections (except for localhost/development)
-	if parsedURL.Scheme == "http" && !isLocalhost(parsedURL.Host) {
-		fmt.Fprintf(os.Stderr, "[Midaz SDK Warning] Using insecure HTTP connection to %s. Consider using HTTPS for production.\n", parsedURL.Host)
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q, use http or https", parsedURL.Scheme)
+	}
+
+	if parsedURL.Hostname() == "" {
+		return errors.New("URL must include a host name")
 	}
 
 	return nil
@@ -778,6 +1101,8 @@ func DefaultConfig() *Config {
 		EnableIdempotency: DefaultEnableIdempotency,
 	}
 
+	config.recordSource(Source{Kind: SourceDefault}, settings...)
+
 	// Apply default URLs based on environment.
 	// Error is safely ignored because DefaultConfig always uses EnvironmentLocal
 	// which is a valid, known environment that will never return an error.
@@ -805,6 +1130,7 @@ func WithMaxRetries(maxRetries int) Option {
 		}
 
 		c.MaxRetries = maxRetries
+		c.record("WithMaxRetries", SettingMaxRetries)
 
 		return nil
 	}
@@ -824,6 +1150,7 @@ func WithRetryWaitMin(waitTime time.Duration) Option {
 		}
 
 		c.RetryWaitMin = waitTime
+		c.record("WithRetryWaitMin", SettingRetryWaitMin)
 
 		return nil
 	}
@@ -847,6 +1174,7 @@ func WithRetryWaitMax(waitTime time.Duration) Option {
 		}
 
 		c.RetryWaitMax = waitTime
+		c.record("WithRetryWaitMax", SettingRetryWaitMax)
 
 		return nil
 	}
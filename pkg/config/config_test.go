@@ -14,6 +14,8 @@ import (
 
 	auth "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/access-manager"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/rounding"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/version"
 )
 
@@ -449,6 +451,41 @@ func TestWithTimeout_Invalid(t *testing.T) {
 	}
 }
 
+func TestWithDefaultDeadlines(t *testing.T) {
+	config, err := NewConfig(
+		WithDefaultDeadlines(2*time.Second, 10*time.Second, 0),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, config.ReadDeadline)
+	assert.Equal(t, 10*time.Second, config.WriteDeadline)
+	assert.Zero(t, config.ListDeadline)
+
+	_, err = NewConfig(
+		WithDefaultDeadlines(time.Second, -time.Second, time.Second),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "deadlines cannot be negative")
+}
+
+func TestWithSlowCallThreshold(t *testing.T) {
+	config, err := NewConfig(
+		WithSlowCallThreshold(500*time.Millisecond),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 500*time.Millisecond, config.SlowCallThreshold)
+	assert.Equal(t, "option WithSlowCallThreshold", config.SourceOf(SettingSlowCallThreshold).String())
+
+	_, err = NewConfig(
+		WithSlowCallThreshold(-time.Second),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "slow call threshold cannot be negative")
+}
+
 func TestWithUserAgent_Valid(t *testing.T) {
 	config, err := NewConfig(
 		WithUserAgent("custom-agent/2.0"),
@@ -667,6 +704,45 @@ func TestWithDebug_Toggle(t *testing.T) {
 	}
 }
 
+func TestWithStrictValidation(t *testing.T) {
+	config, err := NewConfig(
+		WithStrictValidation(true),
+		WithMaxMetadataKeys(20),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, validation.ModeStrict, config.ValidationMode)
+	assert.Equal(t, 20, config.MaxMetadataKeys)
+
+	_, err = NewConfig(WithMaxMetadataKeys(-1))
+	require.Error(t, err)
+
+	config, err = NewConfig(
+		WithValidationMode(validation.ModeLenient),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, validation.ModeLenient, config.ValidationMode)
+
+	_, err = NewConfig(WithValidationMode("warn"))
+	require.Error(t, err)
+}
+
+func TestWithRoundingPolicy(t *testing.T) {
+	config, err := NewConfig(
+		WithRoundingPolicy(rounding.PolicyHalfEven),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, rounding.PolicyHalfEven, config.RoundingPolicy)
+
+	_, err = NewConfig(WithRoundingPolicy("ceiling"))
+	require.Error(t, err)
+
+	_, err = NewConfig(WithRoundingPolicy(""))
+	require.Error(t, err)
+}
+
 func TestWithIdempotency_Toggle(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -1662,3 +1738,20 @@ func (*mockObservabilityProvider) Shutdown(_ context.Context) error {
 func (*mockObservabilityProvider) IsEnabled() bool {
 	return true
 }
+
+func TestConfigClone(t *testing.T) {
+	cfg, err := NewConfig(WithTimeout(5 * time.Second))
+	require.NoError(t, err)
+
+	clone := cfg.Clone()
+	require.NoError(t, WithOnboardingURL("https://onboarding.example.com")(clone))
+	require.NoError(t, WithTimeout(time.Minute)(clone))
+
+	assert.Equal(t, "http://localhost:3000", cfg.ServiceURLs[ServiceOnboarding])
+	assert.Equal(t, "option WithTimeout", cfg.SourceOf(SettingTimeout).String())
+	assert.Equal(t, SourceDefault, cfg.SourceOf(SettingOnboardingURL).Kind)
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+	assert.Equal(t, "https://onboarding.example.com", clone.ServiceURLs[ServiceOnboarding])
+	assert.Equal(t, "option WithOnboardingURL", clone.SourceOf(SettingOnboardingURL).String())
+	assert.Same(t, cfg.HTTPClient, clone.HTTPClient)
+}
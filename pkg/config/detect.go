@@ -0,0 +1,41 @@
+package config
+
+import (
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/envdetect"
+)
+
+// WithDetectedEnvironment selects the environment from where the program
+// runs when it was not set otherwise: a Kubernetes namespace named like
+// "payments-prod" selects production, one named like "ledger-staging"
+// selects development and a local container selects local, see
+// envdetect.Info.Environment. An environment set by MIDAZ_ENVIRONMENT or
+// WithEnvironment is kept, and nothing changes when the detection finds no
+// hint. Explain reports the detected environment with its reason.
+//
+// FromEnvironment applies it when MIDAZ_DETECT_ENVIRONMENT is true and
+// MIDAZ_ENVIRONMENT is unset.
+//
+// Returns:
+//   - Option: A function that sets the detected environment on a Config
+func WithDetectedEnvironment() Option {
+	return func(c *Config) error {
+		applyDetectedEnvironment(c, envdetect.Detect())
+		return nil
+	}
+}
+
+// applyDetectedEnvironment sets the environment suggested by info unless it
+// was chosen explicitly.
+func applyDetectedEnvironment(c *Config, info envdetect.Info) {
+	if kind := c.SourceOf(SettingEnvironment).Kind; kind != SourceDefault && kind != SourceUnknown {
+		return
+	}
+
+	name, reason := info.Environment()
+	if name == "" {
+		return
+	}
+
+	c.Environment = Environment(name)
+	c.recordSource(Source{Kind: SourceDetected, Name: reason}, SettingEnvironment)
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/envdetect"
+)
+
+func TestApplyDetectedEnvironment(t *testing.T) {
+	staging := envdetect.Info{Kubernetes: true, Namespace: "ledger-staging"}
+
+	cfg, err := NewConfig()
+	require.NoError(t, err)
+
+	applyDetectedEnvironment(cfg, staging)
+	assert.Equal(t, EnvironmentDevelopment, cfg.Environment)
+	assert.Equal(t, "detected kubernetes namespace ledger-staging", cfg.SourceOf(SettingEnvironment).String())
+
+	// An explicit environment wins
+	cfg, err = NewConfig(WithEnvironment(EnvironmentProduction))
+	require.NoError(t, err)
+
+	applyDetectedEnvironment(cfg, staging)
+	assert.Equal(t, EnvironmentProduction, cfg.Environment)
+
+	// No hint keeps the default
+	cfg, err = NewConfig()
+	require.NoError(t, err)
+
+	applyDetectedEnvironment(cfg, envdetect.Info{Kubernetes: true, Namespace: "payments"})
+	assert.Equal(t, EnvironmentLocal, cfg.Environment)
+	assert.Equal(t, SourceDefault, cfg.SourceOf(SettingEnvironment).Kind)
+}
+
+func TestFromEnvironment_DetectsEnvironment(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("POD_NAMESPACE", "midaz-prod")
+	t.Setenv("MIDAZ_ENVIRONMENT", "")
+
+	cfg, err := NewConfig(FromEnvironment())
+	require.NoError(t, err)
+	assert.Equal(t, EnvironmentLocal, cfg.Environment, "detection is opt-in")
+
+	t.Setenv("MIDAZ_DETECT_ENVIRONMENT", "true")
+	t.Setenv("MIDAZ_BASE_URL", "https://midaz.example.com")
+
+	cfg, err = NewConfig(FromEnvironment())
+	require.NoError(t, err)
+	assert.Equal(t, EnvironmentProduction, cfg.Environment)
+	assert.Equal(t, "https://midaz.example.com/onboarding", cfg.ServiceURLs[ServiceOnboarding])
+
+	t.Setenv("MIDAZ_ENVIRONMENT", "development")
+
+	cfg, err = NewConfig(FromEnvironment())
+	require.NoError(t, err)
+	assert.Equal(t, EnvironmentDevelopment, cfg.Environment)
+	assert.Equal(t, "environment MIDAZ_ENVIRONMENT", cfg.SourceOf(SettingEnvironment).String())
+}
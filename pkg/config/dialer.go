@@ -0,0 +1,155 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DialFunc opens the connections of the HTTP client, with the signature of
+// net.Dialer.DialContext. address is the "host:port" of the request URL.
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// WithDialer makes the HTTP client open its connections with dial instead of
+// TCP, e.g. to reach the API through a local sidecar or a mesh proxy. It
+// applies to the configured HTTP client, the default one or the one of
+// WithHTTPClient, whose transport is cloned rather than modified. The
+// transport must then be an *http.Transport: the dialer can't be set on
+// another one, such as a wrapping transport, and NewConfig fails.
+//
+// Parameters:
+//   - dial: The function opening the connections
+//
+// Returns:
+//   - Option: A function that sets the dialer on a Config
+func WithDialer(dial DialFunc) Option {
+	return func(c *Config) error {
+		if dial == nil {
+			return errors.New("dialer cannot be nil")
+		}
+
+		c.Dialer = dial
+		c.record("WithDialer", SettingDialer)
+
+		return nil
+	}
+}
+
+// WithUnixSocket sends every request over the Unix domain socket at path,
+// whatever the host of the service URLs, which then only select the Host
+// header and the TLS server name.
+//
+// Parameters:
+//   - path: The path of the socket, e.g. "/var/run/midaz/proxy.sock"
+//
+// Returns:
+//   - Option: A function that routes the requests through the socket
+func WithUnixSocket(path string) Option {
+	return func(c *Config) error {
+		if strings.TrimSpace(path) == "" {
+			return errors.New("unix socket path cannot be empty")
+		}
+
+		c.Dialer = UnixSocketDialer(path)
+		c.record("WithUnixSocket", SettingDialer)
+
+		return nil
+	}
+}
+
+// UnixSocketDialer returns a DialFunc connecting to the Unix domain socket at
+// path for every address.
+func UnixSocketDialer(path string) DialFunc {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", path)
+	}
+}
+
+// parseUnixBaseURL parses a base URL of the form unix:///path/to.sock, with
+// an optional host query parameter naming the virtual host of the requests
+// (localhost by default). It returns the socket path and the HTTP base URL.
+func parseUnixBaseURL(rawURL string) (socket, baseURL string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	if u.Host != "" || u.Path == "" {
+		return "", "", errors.New("unix socket URL must have the form unix:///path/to.sock")
+	}
+
+	host := u.Query().Get("host")
+	if host == "" {
+		host = "localhost"
+	}
+
+	if strings.ContainsAny(host, "/?#@") {
+		return "", "", fmt.Errorf("invalid virtual host %q", host)
+	}
+
+	return u.Path, "http://" + host, nil
+}
+
+// GetHTTPClient returns the HTTP client to use for requests, with the dialer
+// of WithDialer or WithUnixSocket, if any. A dialer that can't be set on the
+// client makes NewConfig fail; use HTTPClientWithDialer to check a Config
+// changed afterwards.
+func (c *Config) GetHTTPClient() *http.Client {
+	client, err := c.HTTPClientWithDialer()
+	if err != nil {
+		return c.HTTPClient
+	}
+
+	return client
+}
+
+// HTTPClientWithDialer returns the HTTP client to use for requests, with the
+// dialer of WithDialer or WithUnixSocket, if any. It fails when a dialer is
+// set and the transport of the client is not an *http.Transport, rather than
+// sending the requests without the dialer.
+func (c *Config) HTTPClientWithDialer() (*http.Client, error) {
+	if c.Dialer == nil || c.HTTPClient == nil {
+		return c.HTTPClient, nil
+	}
+
+	if c.dialerClient == nil || c.dialerClientFrom != c.HTTPClient {
+		client, err := clientWithDialer(c.HTTPClient, c.Dialer)
+		if err != nil {
+			return nil, err
+		}
+
+		c.dialerClient, c.dialerClientFrom = client, c.HTTPClient
+	}
+
+	return c.dialerClient, nil
+}
+
+// clientWithDialer returns a copy of client whose transport opens connections with dial.
+func clientWithDialer(client *http.Client, dial DialFunc) (*http.Client, error) {
+	var transport *http.Transport
+
+	switch t := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // the default transport is an *http.Transport
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, fmt.Errorf("cannot set the dialer on a transport of type %T, only on an *http.Transport", t)
+	}
+
+	transport.DialContext = dial
+	// the dialer decides where connections go, not the proxy settings of the environment
+	transport.Proxy = nil
+
+	copied := *client
+	copied.Transport = transport
+
+	return &copied, nil
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	auth "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/access-manager"
+)
+
+// serveUnixSocket serves HTTP on a Unix domain socket, answering with the Host header.
+func serveUnixSocket(t *testing.T) string {
+	t.Helper()
+
+	// socket paths are limited to about 100 bytes, shorter than most test temp dirs
+	dir, err := os.MkdirTemp("", "midaz")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "api.sock")
+
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, r.Host)
+		}),
+		ReadHeaderTimeout: time.Second,
+	}
+
+	go func() { _ = server.Serve(listener) }()
+
+	t.Cleanup(func() { _ = server.Close() })
+
+	return path
+}
+
+func getBody(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestWithUnixSocket(t *testing.T) {
+	path := serveUnixSocket(t)
+
+	config, err := NewConfig(
+		WithUnixSocket(path),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, config.Dialer)
+
+	assert.Equal(t, "localhost:3000", getBody(t, config.GetHTTPClient(), config.ServiceURLs[ServiceOnboarding]+"/v1/organizations"))
+}
+
+func TestWithBaseURL_UnixSocket(t *testing.T) {
+	path := serveUnixSocket(t)
+
+	config, err := NewConfig(
+		WithEnvironment(EnvironmentProduction),
+		WithBaseURL("unix://"+path+"?host=midaz.internal"),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://midaz.internal/onboarding", config.ServiceURLs[ServiceOnboarding])
+	assert.Equal(t, "http://midaz.internal/transaction", config.ServiceURLs[ServiceTransaction])
+	assert.Equal(t, "midaz.internal", getBody(t, config.GetHTTPClient(), config.ServiceURLs[ServiceTransaction]))
+}
+
+func TestWithBaseURL_UnixSocketDefaultHost(t *testing.T) {
+	config, err := NewConfig(
+		WithEnvironment(EnvironmentLocal),
+		WithBaseURL("unix:///var/run/midaz.sock"),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://localhost:3000", config.ServiceURLs[ServiceOnboarding])
+	assert.NotNil(t, config.Dialer)
+}
+
+func TestWithBaseURL_UnixSocketInvalid(t *testing.T) {
+	for _, baseURL := range []string{"unix://host/var/run/midaz.sock", "unix://", "unix:///var/run/midaz.sock?host=a/b"} {
+		_, err := NewConfig(
+			WithBaseURL(baseURL),
+			WithAccessManager(auth.AccessManager{Enabled: false}),
+		)
+		require.Error(t, err, baseURL)
+		assert.Contains(t, err.Error(), "invalid base URL")
+	}
+}
+
+func TestWithDialer(t *testing.T) {
+	path := serveUnixSocket(t)
+
+	var dialed []string
+
+	config, err := NewConfig(
+		WithHTTPClient(&http.Client{Timeout: 5 * time.Second}),
+		WithDialer(func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialed = append(dialed, network+" "+address)
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		}),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.NoError(t, err)
+
+	client := config.GetHTTPClient()
+	assert.Equal(t, "api.example.com", getBody(t, client, "http://api.example.com"))
+	assert.Equal(t, []string{"tcp api.example.com:80"}, dialed)
+
+	assert.Equal(t, 5*time.Second, client.Timeout)
+	assert.Same(t, client, config.GetHTTPClient())
+	assert.Nil(t, config.HTTPClient.Transport, "the configured client must not be modified")
+}
+
+func TestWithDialer_UnsupportedTransport(t *testing.T) {
+	wrapped := &http.Client{Transport: roundTripFunc(http.DefaultTransport.RoundTrip)}
+
+	_, err := NewConfig(
+		WithHTTPClient(wrapped),
+		WithUnixSocket("/var/run/midaz.sock"),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.Error(t, err, "the dialer must not be dropped silently")
+	assert.Contains(t, err.Error(), "cannot set the dialer")
+
+	config, err := NewConfig(WithUnixSocket("/var/run/midaz.sock"), WithAccessManager(auth.AccessManager{Enabled: false}))
+	require.NoError(t, err)
+
+	config.HTTPClient = wrapped
+
+	_, err = config.HTTPClientWithDialer()
+	require.Error(t, err)
+}
+
+func TestWithDialer_Nil(t *testing.T) {
+	_, err := NewConfig(
+		WithDialer(nil),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.Error(t, err)
+
+	_, err = NewConfig(
+		WithUnixSocket(" "),
+		WithAccessManager(auth.AccessManager{Enabled: false}),
+	)
+	require.Error(t, err)
+}
+
+// roundTripFunc is a transport that isn't an *http.Transport, such as a wrapping one.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -0,0 +1,312 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// SourceKind tells where the value of a setting comes from.
+type SourceKind string
+
+// Source kinds, from the least to the most explicit.
+const (
+	// SourceUnknown is the source of settings of a Config not built by
+	// NewConfig or DefaultConfig, or changed by assigning its fields
+	SourceUnknown SourceKind = "unknown"
+
+	// SourceDefault is the source of settings left to the SDK defaults
+	SourceDefault SourceKind = "default"
+
+	// SourceDetected is the source of settings deduced from where the
+	// program runs (see WithDetectedEnvironment)
+	SourceDetected SourceKind = "detected"
+
+	// SourceFile is the source of settings read from a configuration file (see FromFile)
+	SourceFile SourceKind = "file"
+
+	// SourceEnvironment is the source of settings read from environment variables
+	SourceEnvironment SourceKind = "environment"
+
+	// SourceFlag is the source of settings given as command-line flags (see FromFlag)
+	SourceFlag SourceKind = "flag"
+
+	// SourceOption is the source of settings passed as options in code
+	SourceOption SourceKind = "option"
+)
+
+// Setting names, as reported by Explain and accepted by SourceOf.
+const (
+	SettingEnvironment           = "Environment"
+	SettingOnboardingURL         = "ServiceURLs.onboarding"
+	SettingTransactionURL        = "ServiceURLs.transaction"
+	SettingHTTPClient            = "HTTPClient"
+	SettingDialer                = "Dialer"
+	SettingProbeTimeout          = "ProbeTimeout"
+	SettingTimeout               = "Timeout"
+	SettingUserAgent             = "UserAgent"
+	SettingMaxRetries            = "MaxRetries"
+	SettingRetryWaitMin          = "RetryWaitMin"
+	SettingRetryWaitMax          = "RetryWaitMax"
+	SettingEnableRetries         = "EnableRetries"
+	SettingDebug                 = "Debug"
+	SettingDebugConnections      = "DebugConnections"
+	SettingObservabilityProvider = "ObservabilityProvider"
+	SettingEnableIdempotency     = "EnableIdempotency"
+	SettingReadDeadline          = "ReadDeadline"
+	SettingWriteDeadline         = "WriteDeadline"
+	SettingListDeadline          = "ListDeadline"
+	SettingSlowCallThreshold     = "SlowCallThreshold"
+	SettingValidationMode        = "ValidationMode"
+	SettingMaxMetadataKeys       = "MaxMetadataKeys"
+	SettingRoundingPolicy        = "RoundingPolicy"
+	SettingTenantID              = "TenantID"
+	SettingAccessManager         = "AccessManager"
+)
+
+// settings lists the settings in the order of Explain.
+var settings = []string{
+	SettingEnvironment, SettingOnboardingURL, SettingTransactionURL, SettingHTTPClient, SettingDialer, SettingProbeTimeout,
+	SettingTimeout, SettingUserAgent, SettingMaxRetries, SettingRetryWaitMin, SettingRetryWaitMax,
+	SettingEnableRetries, SettingDebug, SettingDebugConnections, SettingObservabilityProvider,
+	SettingEnableIdempotency, SettingReadDeadline, SettingWriteDeadline, SettingListDeadline, SettingSlowCallThreshold,
+	SettingValidationMode, SettingMaxMetadataKeys, SettingRoundingPolicy, SettingTenantID, SettingAccessManager,
+}
+
+// Source is where the value of a setting comes from.
+type Source struct {
+	Kind SourceKind `json:"kind"`
+
+	// Name details the source: the option function, the environment
+	// variable, the flag or the file
+	Name string `json:"name,omitempty"`
+}
+
+// String formats the source, e.g. "environment MIDAZ_BASE_URL".
+func (s Source) String() string {
+	kind := s.Kind
+	if kind == "" {
+		kind = SourceUnknown
+	}
+
+	if s.Name == "" {
+		return string(kind)
+	}
+
+	return string(kind) + " " + s.Name
+}
+
+// WithSource applies options as coming from src, so that Explain reports src
+// for the settings they change instead of the options themselves. Use it
+// when options are built from an outside source, such as a flag:
+//
+//	config.WithSource(config.Source{Kind: config.SourceFlag, Name: "--base-url"}, config.WithBaseURL(*baseURL))
+func WithSource(src Source, options ...Option) Option {
+	return func(c *Config) error {
+		return c.withSource(src, func() error {
+			for _, option := range options {
+				if err := option(c); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+}
+
+// FromFlag applies options as coming from the named command-line flag.
+func FromFlag(name string, options ...Option) Option {
+	return WithSource(Source{Kind: SourceFlag, Name: name}, options...)
+}
+
+// FromFile applies options as coming from the configuration file at path.
+func FromFile(path string, options ...Option) Option {
+	return WithSource(Source{Kind: SourceFile, Name: path}, options...)
+}
+
+// withSource runs fn with src as the source of the settings it changes. The
+// innermost source wins, so FromEnvironment inside FromFile still reports
+// the environment variables.
+func (c *Config) withSource(src Source, fn func() error) error {
+	previous := c.applying
+	c.applying = &src
+
+	defer func() { c.applying = previous }()
+
+	return fn()
+}
+
+// record notes that option changed the settings.
+func (c *Config) record(option string, settings ...string) {
+	src := Source{Kind: SourceOption, Name: option}
+	if c.applying != nil {
+		src = *c.applying
+	}
+
+	c.recordSource(src, settings...)
+}
+
+// recordSource notes that src changed the settings.
+func (c *Config) recordSource(src Source, settings ...string) {
+	if c.sources == nil {
+		c.sources = make(map[string]Source, len(settings))
+	}
+
+	for _, s := range settings {
+		c.sources[s] = src
+	}
+}
+
+// envSource is the source of settings read from the named environment variables.
+func envSource(names ...string) Source {
+	return Source{Kind: SourceEnvironment, Name: strings.Join(names, ", ")}
+}
+
+// SourceOf returns where the value of the named setting comes from, such as
+// SettingOnboardingURL.
+func (c *Config) SourceOf(setting string) Source {
+	if src, ok := c.sources[setting]; ok {
+		return src
+	}
+
+	return Source{Kind: SourceUnknown}
+}
+
+// Setting is the value of a setting of a Config and where it comes from.
+type Setting struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source Source `json:"source"`
+}
+
+// Explanation lists the settings of a Config.
+type Explanation []Setting
+
+// Explain reports the value of every setting of the configuration and the
+// source it comes from: an SDK default, an option, an environment variable,
+// a flag or a file. Secrets are masked. Print it to find out why a client
+// talks to an unexpected URL or uses an unexpected timeout:
+//
+//	fmt.Println(client.GetConfig().Explain())
+func (c *Config) Explain() Explanation {
+	values := c.settingValues()
+
+	explanation := make(Explanation, 0, len(settings))
+	for _, name := range settings {
+		explanation = append(explanation, Setting{Name: name, Value: values[name], Source: c.SourceOf(name)})
+	}
+
+	// Services other than the known ones, set by assigning ServiceURLs
+	var extra []string
+
+	for service := range c.ServiceURLs {
+		if service != ServiceOnboarding && service != ServiceTransaction {
+			extra = append(extra, string(service))
+		}
+	}
+
+	sort.Strings(extra)
+
+	for _, service := range extra {
+		name := "ServiceURLs." + service
+		explanation = append(explanation, Setting{Name: name, Value: c.ServiceURLs[ServiceType(service)], Source: c.SourceOf(name)})
+	}
+
+	return explanation
+}
+
+// Get returns the named setting.
+func (e Explanation) Get(name string) (Setting, bool) {
+	for _, s := range e {
+		if s.Name == name {
+			return s, true
+		}
+	}
+
+	return Setting{}, false
+}
+
+// String formats the settings as a table of names, values and sources.
+func (e Explanation) String() string {
+	var b strings.Builder
+
+	_ = e.WriteText(&b) //nolint:errcheck // strings.Builder never fails
+
+	return b.String()
+}
+
+// WriteText writes the settings as a table of names, values and sources.
+func (e Explanation) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "setting\tvalue\tsource")
+
+	for _, s := range e {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Name, s.Value, s.Source)
+	}
+
+	return tw.Flush()
+}
+
+// settingValues formats the value of each setting.
+func (c *Config) settingValues() map[string]string {
+	httpClient := "none"
+	if c.HTTPClient != nil {
+		httpClient = "timeout " + c.HTTPClient.Timeout.String()
+	}
+
+	return map[string]string{
+		SettingEnvironment:           string(c.Environment),
+		SettingOnboardingURL:         c.ServiceURLs[ServiceOnboarding],
+		SettingTransactionURL:        c.ServiceURLs[ServiceTransaction],
+		SettingHTTPClient:            httpClient,
+		SettingDialer:                presence(c.Dialer != nil),
+		SettingProbeTimeout:          c.ProbeTimeout.String(),
+		SettingTimeout:               c.Timeout.String(),
+		SettingUserAgent:             c.UserAgent,
+		SettingMaxRetries:            strconv.Itoa(c.MaxRetries),
+		SettingRetryWaitMin:          c.RetryWaitMin.String(),
+		SettingRetryWaitMax:          c.RetryWaitMax.String(),
+		SettingEnableRetries:         strconv.FormatBool(c.EnableRetries),
+		SettingDebug:                 strconv.FormatBool(c.Debug),
+		SettingDebugConnections:      strconv.FormatBool(c.DebugConnections),
+		SettingObservabilityProvider: presence(c.ObservabilityProvider != nil),
+		SettingEnableIdempotency:     strconv.FormatBool(c.EnableIdempotency),
+		SettingReadDeadline:          c.ReadDeadline.String(),
+		SettingWriteDeadline:         c.WriteDeadline.String(),
+		SettingListDeadline:          c.ListDeadline.String(),
+		SettingSlowCallThreshold:     c.SlowCallThreshold.String(),
+		SettingValidationMode:        string(c.ValidationMode),
+		SettingMaxMetadataKeys:       strconv.Itoa(c.MaxMetadataKeys),
+		SettingRoundingPolicy:        string(c.RoundingPolicy),
+		SettingTenantID:              c.TenantID,
+		SettingAccessManager:         c.accessManagerValue(),
+	}
+}
+
+// accessManagerValue formats the access manager settings, masking the secret.
+func (c *Config) accessManagerValue() string {
+	am := c.AccessManager
+	if !am.Enabled && am.Address == "" && am.ClientID == "" {
+		return "disabled"
+	}
+
+	secret := ""
+	if am.ClientSecret != "" {
+		secret = "****"
+	}
+
+	return fmt.Sprintf("enabled=%t address=%s clientId=%s clientSecret=%s", am.Enabled, am.Address, am.ClientID, secret)
+}
+
+func presence(set bool) string {
+	if set {
+		return "set"
+	}
+
+	return "none"
+}
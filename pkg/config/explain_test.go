@@ -0,0 +1,82 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	auth "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/access-manager"
+)
+
+func TestExplainDefaults(t *testing.T) {
+	cfg, err := NewConfig()
+	require.NoError(t, err)
+
+	explanation := cfg.Explain()
+	require.Len(t, explanation, len(settings))
+
+	url, ok := explanation.Get(SettingOnboardingURL)
+	require.True(t, ok)
+	assert.Equal(t, "http://localhost:3000", url.Value)
+	assert.Equal(t, "default local environment", url.Source.String())
+
+	timeout, _ := explanation.Get(SettingTimeout)
+	assert.Equal(t, "1m0s", timeout.Value)
+	assert.Equal(t, Source{Kind: SourceDefault}, timeout.Source)
+
+	assert.Equal(t, SourceDefault, cfg.SourceOf(SettingHTTPClient).Kind)
+}
+
+func TestExplainSources(t *testing.T) {
+	t.Setenv("MIDAZ_BASE_URL", "https://midaz.example.com")
+	t.Setenv("MIDAZ_TIMEOUT", "5")
+	t.Setenv("MIDAZ_ENVIRONMENT", "production")
+
+	cfg, err := NewConfig(
+		FromFile("midaz.yaml", WithMaxRetries(7), WithDebug(true)),
+		FromEnvironment(),
+		FromFlag("--transaction-url", WithTransactionURL("https://tx.example.com")),
+		WithAccessManager(auth.AccessManager{Enabled: true, Address: "https://auth.example.com", ClientID: "id", ClientSecret: "secret"}),
+		WithRetryWaitMin(2*time.Second),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "environment MIDAZ_ENVIRONMENT", cfg.SourceOf(SettingEnvironment).String())
+	assert.Equal(t, "environment MIDAZ_BASE_URL", cfg.SourceOf(SettingOnboardingURL).String())
+	assert.Equal(t, "flag --transaction-url", cfg.SourceOf(SettingTransactionURL).String())
+	assert.Equal(t, "environment MIDAZ_TIMEOUT", cfg.SourceOf(SettingTimeout).String())
+	assert.Equal(t, "file midaz.yaml", cfg.SourceOf(SettingMaxRetries).String())
+	assert.Equal(t, "file midaz.yaml", cfg.SourceOf(SettingDebug).String())
+	assert.Equal(t, "option WithRetryWaitMin", cfg.SourceOf(SettingRetryWaitMin).String())
+
+	explanation := cfg.Explain()
+
+	am, _ := explanation.Get(SettingAccessManager)
+	assert.Equal(t, "enabled=true address=https://auth.example.com clientId=id clientSecret=****", am.Value)
+	assert.NotContains(t, explanation.String(), "secret ")
+
+	url, _ := explanation.Get(SettingOnboardingURL)
+	assert.Equal(t, "https://midaz.example.com/onboarding", url.Value)
+
+	lines := strings.Split(strings.TrimSpace(explanation.String()), "\n")
+	require.Len(t, lines, len(settings)+1)
+	assert.True(t, strings.HasPrefix(lines[0], "setting"))
+	assert.Regexp(t, `(?m)^Timeout +5s +environment MIDAZ_TIMEOUT$`, explanation.String())
+}
+
+func TestExplainWithoutTracking(t *testing.T) {
+	cfg := &Config{ServiceURLs: map[ServiceType]string{"reports": "https://reports.example.com"}}
+
+	explanation := cfg.Explain()
+
+	reports, ok := explanation.Get("ServiceURLs.reports")
+	require.True(t, ok)
+	assert.Equal(t, "https://reports.example.com", reports.Value)
+	assert.Equal(t, SourceUnknown, reports.Source.Kind)
+
+	am, _ := explanation.Get(SettingAccessManager)
+	assert.Equal(t, "disabled", am.Value)
+}
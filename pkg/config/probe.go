@@ -0,0 +1,205 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// DefaultProbeTimeout bounds the connectivity probe of each service URL when
+// no timeout is given.
+const DefaultProbeTimeout = 3 * time.Second
+
+// ErrInvalidURL is the sentinel matched by errors.Is when a URL of the
+// configuration is malformed or has an unsupported scheme.
+var ErrInvalidURL = errors.New("invalid URL")
+
+// ErrUnreachable is the sentinel matched by errors.Is when the connectivity
+// probe could not connect to a service.
+var ErrUnreachable = errors.New("service unreachable")
+
+// URLError is returned when a URL of the configuration is invalid.
+//
+// Example:
+//
+//	var urlErr *config.URLError
+//	if errors.As(err, &urlErr) {
+//	    log.Fatalf("fix the %s URL %q: %v", urlErr.Name, urlErr.URL, urlErr.Err)
+//	}
+type URLError struct {
+	// Name names the URL: a service such as "onboarding", or "base"
+	Name string
+
+	// URL is the invalid URL
+	URL string
+
+	// Err is the problem found
+	Err error
+}
+
+// Error implements the error interface.
+func (e *URLError) Error() string {
+	return fmt.Sprintf("invalid %s URL: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the problem found.
+func (e *URLError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrInvalidURL.
+func (*URLError) Is(target error) bool {
+	return target == ErrInvalidURL
+}
+
+// UnreachableError is returned by the connectivity probe when a service
+// can't be connected to.
+type UnreachableError struct {
+	// Service is the unreachable service
+	Service ServiceType
+
+	// URL is the URL of the service
+	URL string
+
+	// Err is the error of the connection or of the TLS handshake
+	Err error
+}
+
+// Error implements the error interface, e.g.
+// "onboarding URL unreachable: dial tcp 10.0.0.1:3000: connect: connection refused".
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("%s URL unreachable: %v", e.Service, e.Err)
+}
+
+// Unwrap returns the error of the connection or of the TLS handshake.
+func (e *UnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrUnreachable.
+func (*UnreachableError) Is(target error) bool {
+	return target == ErrUnreachable
+}
+
+// WithConnectivityProbe makes NewConfig check that every service URL can be
+// connected to, so that a wrong host, port or certificate fails at startup
+// with an UnreachableError instead of on the first request. The probe opens
+// a TCP connection, through the dialer of WithDialer or WithUnixSocket if
+// any, and completes the TLS handshake of https URLs; it sends no request.
+//
+// Parameters:
+//   - timeout: The time allowed to each service, DefaultProbeTimeout if 0
+//
+// Returns:
+//   - Option: A function that enables the probe on a Config
+func WithConnectivityProbe(timeout time.Duration) Option {
+	return func(c *Config) error {
+		if timeout < 0 {
+			return errors.New("probe timeout cannot be negative")
+		}
+
+		if timeout == 0 {
+			timeout = DefaultProbeTimeout
+		}
+
+		c.ProbeTimeout = timeout
+		c.record("WithConnectivityProbe", SettingProbeTimeout)
+
+		return nil
+	}
+}
+
+// Probe checks that every service URL can be connected to, as described in
+// WithConnectivityProbe, each within ProbeTimeout or DefaultProbeTimeout. It
+// returns the UnreachableError of every unreachable service, joined.
+func (c *Config) Probe(ctx context.Context) error {
+	timeout := c.ProbeTimeout
+	if timeout <= 0 {
+		timeout = DefaultProbeTimeout
+	}
+
+	services := make([]ServiceType, 0, len(c.ServiceURLs))
+	for service := range c.ServiceURLs {
+		services = append(services, service)
+	}
+
+	sort.Slice(services, func(i, j int) bool { return services[i] < services[j] })
+
+	var errs []error
+
+	for _, service := range services {
+		rawURL := c.ServiceURLs[service]
+
+		if err := c.probe(ctx, rawURL, timeout); err != nil {
+			errs = append(errs, &UnreachableError{Service: service, URL: rawURL, Err: err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// probe connects to the host of rawURL and completes the TLS handshake of
+// https URLs.
+func (c *Config) probe(ctx context.Context, rawURL string, timeout time.Duration) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dial := c.Dialer
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	conn, err := dial(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if u.Scheme != "https" {
+		return nil
+	}
+
+	tlsConn := tls.Client(conn, c.probeTLSConfig(u.Hostname()))
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return fmt.Errorf("TLS handshake with %s: %w", u.Host, err)
+	}
+
+	return nil
+}
+
+// probeTLSConfig returns the TLS settings of the HTTP client transport, if
+// any, so that the probe trusts the same certificates as the requests.
+func (c *Config) probeTLSConfig(serverName string) *tls.Config {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.HTTPClient != nil {
+		if t, ok := c.HTTPClient.Transport.(*http.Transport); ok && t.TLSClientConfig != nil {
+			cfg = t.TLSClientConfig.Clone()
+		}
+	}
+
+	if cfg.ServerName == "" {
+		cfg.ServerName = serverName
+	}
+
+	return cfg
+}
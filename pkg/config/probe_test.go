@@ -0,0 +1,144 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closedURL returns an http URL on a port nobody listens to.
+func closedURL(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	return "http://" + addr
+}
+
+func TestNewConfig_InvalidURLIsTyped(t *testing.T) {
+	tests := []struct {
+		name    string
+		option  Option
+		message string
+	}{
+		{"unsupported scheme", WithOnboardingURL("ftp://api.example.com"), `invalid onboarding URL: unsupported scheme "ftp", use http or https`},
+		{"no host name", WithTransactionURL("http://:3001"), "invalid transaction URL: URL must include a host name"},
+		{"base URL", WithBaseURL("api.example.com"), "invalid base URL: URL must include scheme and host"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewConfig(tc.option)
+			require.ErrorIs(t, err, ErrInvalidURL)
+			assert.EqualError(t, err, tc.message)
+		})
+	}
+}
+
+func TestNewConfig_ValidatesAssignedURLs(t *testing.T) {
+	_, err := NewConfig(func(c *Config) error {
+		c.ServiceURLs[ServiceTransaction] = "grpc://tx.example.com"
+		return nil
+	})
+
+	var urlErr *URLError
+	require.ErrorAs(t, err, &urlErr)
+	assert.Equal(t, "transaction", urlErr.Name)
+	assert.Equal(t, "grpc://tx.example.com", urlErr.URL)
+}
+
+func TestWithConnectivityProbe(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	cfg, err := NewConfig(
+		WithOnboardingURL(server.URL),
+		WithTransactionURL(server.URL),
+		WithConnectivityProbe(0),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultProbeTimeout, cfg.ProbeTimeout)
+	assert.Equal(t, "option WithConnectivityProbe", cfg.SourceOf(SettingProbeTimeout).String())
+
+	_, err = NewConfig(WithConnectivityProbe(-time.Second))
+	require.Error(t, err)
+}
+
+func TestWithConnectivityProbe_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	closed := closedURL(t)
+
+	_, err := NewConfig(
+		WithOnboardingURL(closed),
+		WithTransactionURL(server.URL),
+		WithConnectivityProbe(time.Second),
+	)
+	require.ErrorIs(t, err, ErrUnreachable)
+	assert.Contains(t, err.Error(), "onboarding URL unreachable: dial tcp")
+
+	var unreachable *UnreachableError
+	require.ErrorAs(t, err, &unreachable)
+	assert.Equal(t, ServiceOnboarding, unreachable.Service)
+	assert.Equal(t, closed, unreachable.URL)
+}
+
+func TestProbe_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	cfg := &Config{ServiceURLs: map[ServiceType]string{ServiceOnboarding: server.URL}}
+
+	// The test certificate is only trusted by the client of the server
+	err := cfg.Probe(context.Background())
+	require.ErrorIs(t, err, ErrUnreachable)
+	assert.Contains(t, err.Error(), "TLS handshake")
+
+	cfg.HTTPClient = server.Client()
+	require.NoError(t, cfg.Probe(context.Background()))
+}
+
+func TestProbe_ReportsEveryService(t *testing.T) {
+	cfg := &Config{ServiceURLs: map[ServiceType]string{
+		ServiceOnboarding:  closedURL(t),
+		ServiceTransaction: closedURL(t),
+	}}
+
+	err := cfg.Probe(context.Background())
+
+	var joined interface{ Unwrap() []error }
+	require.True(t, errors.As(err, &joined))
+	require.Len(t, joined.Unwrap(), 2)
+	assert.Contains(t, joined.Unwrap()[0].Error(), "onboarding URL unreachable")
+	assert.Contains(t, joined.Unwrap()[1].Error(), "transaction URL unreachable")
+}
+
+func TestProbe_UsesDialer(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	var dialed string
+
+	cfg := &Config{
+		ServiceURLs: map[ServiceType]string{ServiceOnboarding: "http://midaz.internal"},
+		Dialer: func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialed = address
+			return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}
+
+	require.NoError(t, cfg.Probe(context.Background()))
+	assert.Equal(t, "midaz.internal:80", dialed)
+}
@@ -0,0 +1,415 @@
+// Package consolidation merges the trial balances of several ledgers, such as
+// the ledgers of the entities of a group in each country, into a consolidated
+// trial balance, for the monthly reporting of group finance teams.
+//
+// Each ledger is captured with the balance snapshotter of pkg/export and
+// turned into a trial balance: the booked balance (available plus on hold) of
+// each account and asset. Balances can be translated into a reporting asset
+// with the rates of each ledger, and intercompany positions are removed with
+// elimination entries, which must net to zero per asset:
+//
+//	report, err := consolidation.New(client.Entity).
+//	    WithReportingAsset("USD").
+//	    WithEliminations(consolidation.Elimination{
+//	        Description: "Intercompany loan BR to US",
+//	        Lines: []consolidation.EliminationLine{
+//	            {Entity: "BR", Account: "@intercompany_us", Amount: decimal.NewFromInt(-1000)},
+//	            {Entity: "US", Account: "@intercompany_br", Amount: decimal.NewFromInt(1000)},
+//	        },
+//	    }).
+//	    Run(ctx,
+//	        consolidation.Source{Entity: "BR", OrganizationID: brOrg, LedgerID: brLedger, Rates: map[string]decimal.Decimal{"BRL": brlToUSD}},
+//	        consolidation.Source{Entity: "US", OrganizationID: usOrg, LedgerID: usLedger},
+//	    )
+//
+// Balances are captured when Run is called, so a month-end consolidation is
+// run at the close of the month, for instance after the closing of pkg/closing.
+package consolidation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/export"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrMissingRate is returned when a balance can't be translated into the
+	// reporting asset because its ledger has no rate for its asset.
+	ErrMissingRate = errors.New("missing translation rate")
+
+	// ErrUnbalancedElimination is returned for an elimination whose lines
+	// don't net to zero for an asset.
+	ErrUnbalancedElimination = errors.New("elimination doesn't net to zero")
+
+	// ErrUnknownAccount is returned for an elimination line on an account the
+	// trial balance of its entity doesn't have.
+	ErrUnknownAccount = errors.New("elimination account not in trial balance")
+)
+
+// Line is the balance of an account in an asset.
+type Line struct {
+	Account string          `json:"account"`
+	Asset   string          `json:"asset"`
+	Amount  decimal.Decimal `json:"amount"`
+}
+
+// TrialBalance is the trial balance of the ledger of an entity.
+type TrialBalance struct {
+	Entity         string    `json:"entity"`
+	OrganizationID string    `json:"organizationId"`
+	LedgerID       string    `json:"ledgerId"`
+	TakenAt        time.Time `json:"takenAt"`
+
+	// Consistent is false when balances kept moving during the capture
+	Consistent bool `json:"consistent"`
+
+	// Lines are sorted by account and asset
+	Lines []Line `json:"lines"`
+}
+
+// AccountMapper returns the account a balance is reported under, or an empty
+// string to leave it out of the trial balance. The default mapper reports
+// balances under the alias of their account, or its ID without an alias.
+type AccountMapper func(entity string, balance models.Balance) string
+
+// DefaultAccountMapper reports balances under their account alias or ID.
+func DefaultAccountMapper(_ string, balance models.Balance) string {
+	if balance.Alias != "" {
+		return balance.Alias
+	}
+
+	return balance.AccountID
+}
+
+// NewTrialBalance builds the trial balance of an entity from a balance
+// snapshot, adding up the booked balances of each account and asset.
+func NewTrialBalance(entity string, snap *export.BalanceSnapshot, mapper AccountMapper) *TrialBalance {
+	if mapper == nil {
+		mapper = DefaultAccountMapper
+	}
+
+	tb := &TrialBalance{
+		Entity:         entity,
+		OrganizationID: snap.OrganizationID,
+		LedgerID:       snap.LedgerID,
+		TakenAt:        snap.TakenAt,
+		Consistent:     snap.Consistent,
+	}
+
+	for _, b := range snap.Balances {
+		if account := mapper(entity, b); account != "" {
+			tb.add(account, b.AssetCode, b.Available.Add(b.OnHold))
+		}
+	}
+
+	tb.sort()
+
+	return tb
+}
+
+// Translate returns the trial balance translated into asset: the amounts of
+// other assets are multiplied by their rate in rates. It returns
+// ErrMissingRate when an asset of the trial balance has no rate.
+func (tb *TrialBalance) Translate(asset string, rates map[string]decimal.Decimal) (*TrialBalance, error) {
+	translated := *tb
+	translated.Lines = nil
+
+	for _, l := range tb.Lines {
+		amount := l.Amount
+
+		if l.Asset != asset {
+			rate, ok := rates[l.Asset]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s to %s in the trial balance of %s", ErrMissingRate, l.Asset, asset, tb.Entity)
+			}
+
+			amount = amount.Mul(rate)
+		}
+
+		translated.add(l.Account, asset, amount)
+	}
+
+	translated.sort()
+
+	return &translated, nil
+}
+
+// Total returns the sum of the balances of asset.
+func (tb *TrialBalance) Total(asset string) decimal.Decimal {
+	total := decimal.Zero
+
+	for _, l := range tb.Lines {
+		if l.Asset == asset {
+			total = total.Add(l.Amount)
+		}
+	}
+
+	return total
+}
+
+// add adds amount to the line of account and asset.
+func (tb *TrialBalance) add(account, asset string, amount decimal.Decimal) {
+	for i := range tb.Lines {
+		if tb.Lines[i].Account == account && tb.Lines[i].Asset == asset {
+			tb.Lines[i].Amount = tb.Lines[i].Amount.Add(amount)
+			return
+		}
+	}
+
+	tb.Lines = append(tb.Lines, Line{Account: account, Asset: asset, Amount: amount})
+}
+
+func (tb *TrialBalance) sort() {
+	sort.Slice(tb.Lines, func(i, j int) bool {
+		if tb.Lines[i].Account != tb.Lines[j].Account {
+			return tb.Lines[i].Account < tb.Lines[j].Account
+		}
+
+		return tb.Lines[i].Asset < tb.Lines[j].Asset
+	})
+}
+
+// EliminationLine adjusts the balance of an account of an entity.
+type EliminationLine struct {
+	Entity  string          `json:"entity"`
+	Account string          `json:"account"`
+	Amount  decimal.Decimal `json:"amount"`
+
+	// Asset is the reporting asset when unset
+	Asset string `json:"asset,omitempty"`
+}
+
+// Elimination removes an intercompany position from the consolidation. Its
+// lines are added to the balances of the accounts, so eliminating a receivable
+// of 1000 in one entity against a payable of -1000 in another takes lines of
+// -1000 and 1000.
+type Elimination struct {
+	Description string            `json:"description"`
+	Lines       []EliminationLine `json:"lines"`
+}
+
+// validate checks that the elimination nets to zero per asset.
+func (e *Elimination) validate(reportingAsset string) error {
+	totals := map[string]decimal.Decimal{}
+
+	for _, l := range e.Lines {
+		asset := l.asset(reportingAsset)
+		if asset == "" {
+			return fmt.Errorf("elimination %q: the line of %s has no asset and there is no reporting asset", e.Description, l.Account)
+		}
+
+		totals[asset] = totals[asset].Add(l.Amount)
+	}
+
+	for asset, total := range totals {
+		if !total.IsZero() {
+			return fmt.Errorf("%w: %q is off by %s %s", ErrUnbalancedElimination, e.Description, total, asset)
+		}
+	}
+
+	return nil
+}
+
+func (l *EliminationLine) asset(reportingAsset string) string {
+	if l.Asset != "" {
+		return l.Asset
+	}
+
+	return reportingAsset
+}
+
+// Consolidate merges the trial balances and applies the eliminations. With a
+// reporting asset, the trial balances must already be translated into it.
+func Consolidate(reportingAsset string, trialBalances []*TrialBalance, eliminations ...Elimination) (*Report, error) {
+	report := &Report{
+		ReportingAsset: reportingAsset,
+		Entities:       make([]EntitySummary, 0, len(trialBalances)),
+		Eliminations:   eliminations,
+		Totals:         map[string]decimal.Decimal{},
+	}
+
+	lines := map[[2]string]*ReportLine{}
+
+	line := func(account, asset string) *ReportLine {
+		key := [2]string{account, asset}
+
+		l, ok := lines[key]
+		if !ok {
+			l = &ReportLine{Account: account, Asset: asset, Entities: map[string]decimal.Decimal{}}
+			lines[key] = l
+		}
+
+		return l
+	}
+
+	for _, tb := range trialBalances {
+		report.Entities = append(report.Entities, EntitySummary{
+			Entity:         tb.Entity,
+			OrganizationID: tb.OrganizationID,
+			LedgerID:       tb.LedgerID,
+			TakenAt:        tb.TakenAt,
+			Consistent:     tb.Consistent,
+			Accounts:       len(tb.Lines),
+		})
+
+		for _, l := range tb.Lines {
+			rl := line(l.Account, l.Asset)
+			rl.Entities[tb.Entity] = rl.Entities[tb.Entity].Add(l.Amount)
+		}
+	}
+
+	for _, e := range eliminations {
+		if err := e.validate(reportingAsset); err != nil {
+			return nil, err
+		}
+
+		for _, el := range e.Lines {
+			rl, ok := lines[[2]string{el.Account, el.asset(reportingAsset)}]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s of %s, in %q", ErrUnknownAccount, el.Account, el.Entity, e.Description)
+			}
+
+			if _, ok := rl.Entities[el.Entity]; !ok {
+				return nil, fmt.Errorf("%w: %s of %s, in %q", ErrUnknownAccount, el.Account, el.Entity, e.Description)
+			}
+
+			rl.Eliminations = rl.Eliminations.Add(el.Amount)
+		}
+	}
+
+	for _, l := range lines {
+		total := l.Eliminations
+		for _, amount := range l.Entities {
+			total = total.Add(amount)
+		}
+
+		l.Consolidated = total
+		report.Totals[l.Asset] = report.Totals[l.Asset].Add(total)
+		report.Lines = append(report.Lines, *l)
+	}
+
+	sort.Slice(report.Lines, func(i, j int) bool {
+		if report.Lines[i].Account != report.Lines[j].Account {
+			return report.Lines[i].Account < report.Lines[j].Account
+		}
+
+		return report.Lines[i].Asset < report.Lines[j].Asset
+	})
+
+	return report, nil
+}
+
+// Source is the ledger of an entity of the group.
+type Source struct {
+	// Entity names the entity in the report, such as its country
+	Entity         string
+	OrganizationID string
+	LedgerID       string
+
+	// Rates translate the assets of the ledger into the reporting asset
+	Rates map[string]decimal.Decimal
+}
+
+// Consolidator consolidates the ledgers of a group.
+type Consolidator struct {
+	snapshotter    *export.BalanceSnapshotter
+	mapper         AccountMapper
+	reportingAsset string
+	eliminations   []Elimination
+	now            func() time.Time
+}
+
+// New creates a Consolidator capturing balances through e, reporting every
+// asset separately and accounts under their alias.
+func New(e *entities.Entity) *Consolidator {
+	return &Consolidator{
+		snapshotter: export.NewBalanceSnapshotter(e),
+		mapper:      DefaultAccountMapper,
+		now:         time.Now,
+	}
+}
+
+// WithReportingAsset translates the balances of every ledger into asset, with
+// the rates of its source.
+func (c *Consolidator) WithReportingAsset(asset string) *Consolidator {
+	c.reportingAsset = asset
+	return c
+}
+
+// WithAccountMapper sets the account balances are reported under, such as
+// the code of a group chart of accounts.
+func (c *Consolidator) WithAccountMapper(mapper AccountMapper) *Consolidator {
+	if mapper != nil {
+		c.mapper = mapper
+	}
+
+	return c
+}
+
+// WithEliminations adds elimination entries.
+func (c *Consolidator) WithEliminations(eliminations ...Elimination) *Consolidator {
+	c.eliminations = append(c.eliminations, eliminations...)
+	return c
+}
+
+// WithSnapshotter replaces the balance snapshotter, e.g. to tune its parallelism.
+func (c *Consolidator) WithSnapshotter(s *export.BalanceSnapshotter) *Consolidator {
+	if s != nil {
+		c.snapshotter = s
+	}
+
+	return c
+}
+
+// Run captures the trial balance of every source and consolidates them.
+func (c *Consolidator) Run(ctx context.Context, sources ...Source) (*Report, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("at least one source is required")
+	}
+
+	seen := map[string]bool{}
+	trialBalances := make([]*TrialBalance, 0, len(sources))
+
+	for _, s := range sources {
+		if s.Entity == "" {
+			return nil, fmt.Errorf("source of ledger %s has no entity name", s.LedgerID)
+		}
+
+		if seen[s.Entity] {
+			return nil, fmt.Errorf("duplicate entity %s", s.Entity)
+		}
+
+		seen[s.Entity] = true
+
+		snap, err := c.snapshotter.Capture(ctx, s.OrganizationID, s.LedgerID)
+		if err != nil {
+			return nil, fmt.Errorf("trial balance of %s: %w", s.Entity, err)
+		}
+
+		tb := NewTrialBalance(s.Entity, snap, c.mapper)
+
+		if c.reportingAsset != "" {
+			if tb, err = tb.Translate(c.reportingAsset, s.Rates); err != nil {
+				return nil, err
+			}
+		}
+
+		trialBalances = append(trialBalances, tb)
+	}
+
+	report, err := Consolidate(c.reportingAsset, trialBalances, c.eliminations...)
+	if err != nil {
+		return nil, err
+	}
+
+	report.GeneratedAt = c.now().UTC()
+
+	return report, nil
+}
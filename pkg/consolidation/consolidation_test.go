@@ -0,0 +1,189 @@
+package consolidation
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/export"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func page[T any](items []T, opts *models.ListOptions) *models.ListResponse[T] {
+	start := min(opts.Offset, len(items))
+	end := min(start+opts.Limit, len(items))
+
+	return &models.ListResponse[T]{
+		Items:      items[start:end],
+		Pagination: models.Pagination{Limit: opts.Limit, Offset: start, Total: len(items)},
+	}
+}
+
+func balance(ledgerID, alias, asset, available, onHold string) models.Balance {
+	return models.Balance{
+		ID:        ledgerID + alias + asset,
+		LedgerID:  ledgerID,
+		AccountID: ledgerID + alias,
+		Alias:     alias,
+		AssetCode: asset,
+		Available: decimal.RequireFromString(available),
+		OnHold:    decimal.RequireFromString(onHold),
+		UpdatedAt: time.Now().Add(-time.Hour),
+	}
+}
+
+// fakeLedgers serves the accounts and balances of several ledgers.
+type fakeLedgers struct {
+	balances map[string][]models.Balance
+}
+
+type fakeAccounts struct {
+	entities.AccountsService
+	*fakeLedgers
+}
+
+func (f fakeAccounts) ListAccounts(_ context.Context, _, ledgerID string, opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+	var accounts []models.Account
+	for _, b := range f.balances[ledgerID] {
+		accounts = append(accounts, models.Account{ID: b.AccountID})
+	}
+
+	return page(accounts, opts), nil
+}
+
+type fakeBalances struct {
+	entities.BalancesService
+	*fakeLedgers
+}
+
+func (f fakeBalances) ListAccountBalances(_ context.Context, _, ledgerID, accountID string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	var balances []models.Balance
+
+	for _, b := range f.balances[ledgerID] {
+		if b.AccountID == accountID {
+			balances = append(balances, b)
+		}
+	}
+
+	return page(balances, opts), nil
+}
+
+func testEntity() *entities.Entity {
+	f := &fakeLedgers{balances: map[string][]models.Balance{
+		"br-ledger": {
+			balance("br-ledger", "@cash", "BRL", "500", "100"),
+			balance("br-ledger", "@intercompany_us", "BRL", "1000", "0"),
+			balance("br-ledger", "@external/BRL", "BRL", "-1600", "0"),
+		},
+		"us-ledger": {
+			balance("us-ledger", "@cash", "USD", "300", "0"),
+			balance("us-ledger", "@intercompany_br", "USD", "-200", "0"),
+			balance("us-ledger", "@external/USD", "USD", "-100", "0"),
+		},
+	}}
+
+	return &entities.Entity{Accounts: fakeAccounts{fakeLedgers: f}, Balances: fakeBalances{fakeLedgers: f}}
+}
+
+var brlToUSD = map[string]decimal.Decimal{"BRL": decimal.RequireFromString("0.2")}
+
+func TestRun(t *testing.T) {
+	consolidator := New(testEntity()).
+		WithReportingAsset("USD").
+		WithAccountMapper(func(_ string, b models.Balance) string {
+			if b.Alias == "@external/"+b.AssetCode {
+				return "@external"
+			}
+
+			return b.Alias
+		}).
+		WithEliminations(Elimination{
+			Description: "Intercompany loan BR to US",
+			Lines: []EliminationLine{
+				{Entity: "BR", Account: "@intercompany_us", Amount: decimal.NewFromInt(-200)},
+				{Entity: "US", Account: "@intercompany_br", Amount: decimal.NewFromInt(200)},
+			},
+		})
+	consolidator.now = func() time.Time { return time.Date(2026, 9, 30, 23, 0, 0, 0, time.UTC) }
+
+	report, err := consolidator.Run(context.Background(),
+		Source{Entity: "BR", OrganizationID: "org-br", LedgerID: "br-ledger", Rates: brlToUSD},
+		Source{Entity: "US", OrganizationID: "org-us", LedgerID: "us-ledger"},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "USD", report.ReportingAsset)
+	assert.True(t, report.Consistent())
+	require.Len(t, report.Entities, 2)
+	assert.Equal(t, "br-ledger", report.Entities[0].LedgerID)
+	assert.Equal(t, 3, report.Entities[0].Accounts)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteCSV(&buf))
+
+	assert.Equal(t, "account,asset_code,BR,US,eliminations,consolidated\n"+
+		"@cash,USD,120,300,0,420\n"+
+		"@external,USD,-320,-100,0,-420\n"+
+		"@intercompany_br,USD,0,-200,200,0\n"+
+		"@intercompany_us,USD,200,0,-200,0\n", buf.String())
+
+	assert.Equal(t, "0", report.Totals["USD"].String())
+}
+
+func TestRunErrors(t *testing.T) {
+	ctx := context.Background()
+	br := Source{Entity: "BR", OrganizationID: "org-br", LedgerID: "br-ledger"}
+
+	_, err := New(testEntity()).WithReportingAsset("USD").Run(ctx, br)
+	require.ErrorIs(t, err, ErrMissingRate)
+
+	_, err = New(testEntity()).Run(ctx, br, br)
+	require.Error(t, err)
+
+	_, err = New(testEntity()).Run(ctx)
+	require.Error(t, err)
+
+	_, err = New(nil).Run(ctx, br)
+	require.Error(t, err)
+}
+
+func TestConsolidateEliminations(t *testing.T) {
+	snap := &export.BalanceSnapshot{LedgerID: "br-ledger", Consistent: true, Balances: []models.Balance{
+		balance("br-ledger", "@a", "BRL", "10", "0"),
+		balance("br-ledger", "@b", "BRL", "-10", "0"),
+	}}
+
+	tb := NewTrialBalance("BR", snap, nil)
+	assert.Equal(t, "0", tb.Total("BRL").String())
+
+	unbalanced := Elimination{Description: "off", Lines: []EliminationLine{
+		{Entity: "BR", Account: "@a", Asset: "BRL", Amount: decimal.NewFromInt(-10)},
+		{Entity: "BR", Account: "@b", Asset: "BRL", Amount: decimal.NewFromInt(9)},
+	}}
+
+	_, err := Consolidate("", []*TrialBalance{tb}, unbalanced)
+	require.ErrorIs(t, err, ErrUnbalancedElimination)
+
+	unknown := Elimination{Description: "unknown", Lines: []EliminationLine{
+		{Entity: "US", Account: "@a", Asset: "BRL", Amount: decimal.NewFromInt(-10)},
+		{Entity: "BR", Account: "@b", Asset: "BRL", Amount: decimal.NewFromInt(10)},
+	}}
+
+	_, err = Consolidate("", []*TrialBalance{tb}, unknown)
+	require.ErrorIs(t, err, ErrUnknownAccount)
+
+	noAsset := Elimination{Description: "no asset", Lines: []EliminationLine{{Entity: "BR", Account: "@a"}}}
+
+	_, err = Consolidate("", []*TrialBalance{tb}, noAsset)
+	require.Error(t, err)
+
+	report, err := Consolidate("", []*TrialBalance{tb})
+	require.NoError(t, err)
+	require.Len(t, report.Lines, 2)
+	assert.Equal(t, "10", report.Lines[0].Consolidated.String())
+}
@@ -0,0 +1,91 @@
+package consolidation
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EntitySummary describes the trial balance of an entity in a report.
+type EntitySummary struct {
+	Entity         string    `json:"entity"`
+	OrganizationID string    `json:"organizationId"`
+	LedgerID       string    `json:"ledgerId"`
+	TakenAt        time.Time `json:"takenAt"`
+	Consistent     bool      `json:"consistent"`
+
+	// Accounts is the number of lines of the trial balance
+	Accounts int `json:"accounts"`
+}
+
+// ReportLine is the consolidated balance of an account in an asset.
+type ReportLine struct {
+	Account string `json:"account"`
+	Asset   string `json:"asset"`
+
+	// Entities holds the balance of each entity having the account
+	Entities     map[string]decimal.Decimal `json:"entities"`
+	Eliminations decimal.Decimal            `json:"eliminations"`
+
+	// Consolidated is the sum of the entity balances and the eliminations
+	Consolidated decimal.Decimal `json:"consolidated"`
+}
+
+// Report is a consolidated trial balance.
+type Report struct {
+	// ReportingAsset is empty when every asset is reported separately
+	ReportingAsset string          `json:"reportingAsset,omitempty"`
+	GeneratedAt    time.Time       `json:"generatedAt"`
+	Entities       []EntitySummary `json:"entities"`
+
+	// Lines are sorted by account and asset
+	Lines        []ReportLine  `json:"lines"`
+	Eliminations []Elimination `json:"eliminations,omitempty"`
+
+	// Totals adds up the consolidated balances of each asset
+	Totals map[string]decimal.Decimal `json:"totals"`
+}
+
+// Consistent reports whether the balances of every entity were captured
+// without moving.
+func (r *Report) Consistent() bool {
+	for _, e := range r.Entities {
+		if !e.Consistent {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WriteCSV writes the report as CSV: the account and asset, one column per
+// entity in report order, the eliminations and the consolidated balance.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"account", "asset_code"}
+	for _, e := range r.Entities {
+		header = append(header, e.Entity)
+	}
+
+	if err := cw.Write(append(header, "eliminations", "consolidated")); err != nil {
+		return err
+	}
+
+	for _, l := range r.Lines {
+		row := []string{l.Account, l.Asset}
+		for _, e := range r.Entities {
+			row = append(row, l.Entities[e.Entity].String())
+		}
+
+		if err := cw.Write(append(row, l.Eliminations.String(), l.Consolidated.String())); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
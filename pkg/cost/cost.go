@@ -0,0 +1,271 @@
+// Package cost estimates what the SDK calls of an application cost the shared
+// Midaz infrastructure, so that platform teams can charge it back to the
+// tenants and use cases behind the calls.
+//
+// A Hook given to the client with client.WithCostHook receives a Sample for
+// every call: the HTTP requests sent, including retries, the bytes sent and
+// received, and the response headers, which may carry compute hints from the
+// backend. An Accountant is a Hook aggregating the samples per tenant, use
+// case and operation, priced with a Pricing:
+//
+//	accountant := cost.NewAccountant(cost.Pricing{PerRequest: 0.0001, PerComputeUnit: 0.001}, "X-Compute-Units")
+//	c, err := client.New(client.UseEntityAPI(), client.WithCostHook(accountant))
+//	...
+//	ctx = cost.WithUseCase(entities.WithTenantID(ctx, "tenant-a"), "nightly-reconciliation")
+//	...
+//	_ = accountant.WriteJSON(os.Stdout)
+//
+// The tenant of a call is its X-Tenant-ID header, set with entities.WithTenantID
+// or the client default. Costs are estimates in the unit of the Pricing; the
+// samples stay in the process.
+package cost
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sample is the cost of one SDK call.
+type Sample struct {
+	// Operation is the method and path template of the call, see usage.Operation
+	Operation string
+
+	// Tenant is the X-Tenant-ID of the call, empty when none was sent
+	Tenant string
+
+	// UseCase is the use case of the call context, see WithUseCase
+	UseCase string
+
+	// Requests counts the HTTP requests sent, retries included
+	Requests int
+
+	// RequestBytes and ResponseBytes count the bodies sent and received by all the requests
+	RequestBytes  int64
+	ResponseBytes int64
+
+	// StatusCode is the HTTP status of the last response, 0 when none was received
+	StatusCode int
+	Failed     bool
+	Duration   time.Duration
+
+	// Header holds the headers of the last response, nil when none was received
+	Header http.Header
+}
+
+// Hook receives the cost of the SDK calls. It is called synchronously after
+// each call, from the goroutine of the call, and must be safe for concurrent
+// use.
+type Hook interface {
+	RecordCost(ctx context.Context, sample Sample)
+}
+
+// HookFunc adapts a function to the Hook interface.
+type HookFunc func(ctx context.Context, sample Sample)
+
+// RecordCost calls f.
+func (f HookFunc) RecordCost(ctx context.Context, sample Sample) {
+	f(ctx, sample)
+}
+
+type contextKeyUseCase struct{}
+
+// WithUseCase attaches the use case the calls of ctx are made for, such as a
+// job or a product feature, to attribute their cost. An empty useCase returns
+// ctx unchanged.
+func WithUseCase(ctx context.Context, useCase string) context.Context {
+	useCase = strings.TrimSpace(useCase)
+	if useCase == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, contextKeyUseCase{}, useCase)
+}
+
+// UseCaseFromContext returns the use case set with WithUseCase, or an empty string.
+func UseCaseFromContext(ctx context.Context) string {
+	if s, ok := ctx.Value(contextKeyUseCase{}).(string); ok {
+		return s
+	}
+
+	return ""
+}
+
+// Pricing converts the resources of a call into a cost, in any unit.
+type Pricing struct {
+	PerRequest     float64
+	PerKiBSent     float64
+	PerKiBReceived float64
+	PerComputeUnit float64
+}
+
+// Entry is the cost of an operation for a tenant and a use case.
+type Entry struct {
+	Tenant    string `json:"tenant"`
+	UseCase   string `json:"useCase"`
+	Operation string `json:"operation"`
+
+	Calls         int64 `json:"calls"`
+	Failures      int64 `json:"failures"`
+	Requests      int64 `json:"requests"`
+	RequestBytes  int64 `json:"requestBytes"`
+	ResponseBytes int64 `json:"responseBytes"`
+
+	// ComputeUnits sums the compute hints of the responses
+	ComputeUnits float64 `json:"computeUnits"`
+
+	// Cost is the estimated cost, see Pricing
+	Cost float64 `json:"cost"`
+}
+
+// Report is a snapshot of an Accountant.
+type Report struct {
+	// Since is when the Accountant was created or last reset
+	Since       time.Time `json:"since"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	TotalCost   float64   `json:"totalCost"`
+
+	// Entries holds the costs by tenant, use case and operation, the most
+	// expensive first within a tenant and use case
+	Entries []Entry `json:"entries"`
+}
+
+type entryKey struct {
+	tenant    string
+	useCase   string
+	operation string
+}
+
+// Accountant is a Hook aggregating the cost of the calls per tenant, use case
+// and operation. It is safe for concurrent use.
+type Accountant struct {
+	pricing     Pricing
+	hintHeaders []string
+
+	mu      sync.Mutex
+	since   time.Time
+	entries map[entryKey]*Entry
+	now     func() time.Time
+}
+
+// NewAccountant returns an empty Accountant pricing the calls with pricing.
+// hintHeaders are the response headers carrying compute hints from the
+// backend, as decimal numbers of compute units; their values are summed.
+func NewAccountant(pricing Pricing, hintHeaders ...string) *Accountant {
+	a := &Accountant{pricing: pricing, hintHeaders: slices.Clone(hintHeaders), now: time.Now}
+	a.Reset()
+
+	return a
+}
+
+// ComputeUnits returns the compute units hinted by the response headers of sample.
+func (a *Accountant) ComputeUnits(sample Sample) float64 {
+	var units float64
+
+	for _, name := range a.hintHeaders {
+		value := strings.TrimSpace(sample.Header.Get(name))
+		if value == "" {
+			continue
+		}
+
+		if n, err := strconv.ParseFloat(value, 64); err == nil && n > 0 {
+			units += n
+		}
+	}
+
+	return units
+}
+
+// Estimate returns the estimated cost of sample.
+func (a *Accountant) Estimate(sample Sample) float64 {
+	return a.estimate(sample, a.ComputeUnits(sample))
+}
+
+func (a *Accountant) estimate(sample Sample, units float64) float64 {
+	return float64(sample.Requests)*a.pricing.PerRequest +
+		float64(sample.RequestBytes)/1024*a.pricing.PerKiBSent +
+		float64(sample.ResponseBytes)/1024*a.pricing.PerKiBReceived +
+		units*a.pricing.PerComputeUnit
+}
+
+// RecordCost adds sample to the costs of its tenant, use case and operation.
+func (a *Accountant) RecordCost(_ context.Context, sample Sample) {
+	units := a.ComputeUnits(sample)
+	estimate := a.estimate(sample, units)
+	key := entryKey{tenant: sample.Tenant, useCase: sample.UseCase, operation: sample.Operation}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entry, ok := a.entries[key]
+	if !ok {
+		entry = &Entry{Tenant: key.tenant, UseCase: key.useCase, Operation: key.operation}
+		a.entries[key] = entry
+	}
+
+	entry.Calls++
+	entry.Requests += int64(sample.Requests)
+	entry.RequestBytes += sample.RequestBytes
+	entry.ResponseBytes += sample.ResponseBytes
+	entry.ComputeUnits += units
+	entry.Cost += estimate
+
+	if sample.Failed {
+		entry.Failures++
+	}
+}
+
+// Report returns a snapshot of the recorded costs.
+func (a *Accountant) Report() Report {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	report := Report{Since: a.since, GeneratedAt: a.now().UTC(), Entries: make([]Entry, 0, len(a.entries))}
+
+	for _, entry := range a.entries {
+		report.Entries = append(report.Entries, *entry)
+		report.TotalCost += entry.Cost
+	}
+
+	slices.SortFunc(report.Entries, func(x, y Entry) int {
+		if c := strings.Compare(x.Tenant, y.Tenant); c != 0 {
+			return c
+		}
+
+		if c := strings.Compare(x.UseCase, y.UseCase); c != 0 {
+			return c
+		}
+
+		if c := cmp.Compare(y.Cost, x.Cost); c != 0 {
+			return c
+		}
+
+		return strings.Compare(x.Operation, y.Operation)
+	})
+
+	return report
+}
+
+// WriteJSON writes the Report of the Accountant to w as indented JSON.
+func (a *Accountant) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(a.Report())
+}
+
+// Reset forgets the recorded costs.
+func (a *Accountant) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.since = a.now().UTC()
+	a.entries = map[entryKey]*Entry{}
+}
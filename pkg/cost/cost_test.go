@@ -0,0 +1,107 @@
+package cost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseCaseContext(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Empty(t, UseCaseFromContext(ctx))
+	assert.Equal(t, ctx, WithUseCase(ctx, "  "))
+	assert.Equal(t, "reconciliation", UseCaseFromContext(WithUseCase(ctx, " reconciliation ")))
+}
+
+func TestAccountantEstimate(t *testing.T) {
+	accountant := NewAccountant(Pricing{PerRequest: 1, PerKiBSent: 2, PerKiBReceived: 4, PerComputeUnit: 10}, "X-Compute-Units", "X-Db-Units")
+
+	sample := Sample{
+		Requests:      2,
+		RequestBytes:  1024,
+		ResponseBytes: 512,
+		Header: http.Header{
+			"X-Compute-Units": {"1.5"},
+			"X-Db-Units":      {"0.5"},
+		},
+	}
+
+	assert.InDelta(t, 2.0, accountant.ComputeUnits(sample), 1e-9)
+	assert.InDelta(t, 2+2+2+20, accountant.Estimate(sample), 1e-9)
+
+	sample.Header = http.Header{"X-Compute-Units": {"not-a-number"}, "X-Db-Units": {"-3"}}
+	assert.Zero(t, accountant.ComputeUnits(sample))
+
+	sample.Header = nil
+	assert.Zero(t, accountant.ComputeUnits(sample))
+}
+
+func TestAccountantReport(t *testing.T) {
+	accountant := NewAccountant(Pricing{PerRequest: 1})
+	ctx := context.Background()
+
+	accountant.RecordCost(ctx, Sample{Tenant: "b", Operation: "GET /organizations", Requests: 1})
+	accountant.RecordCost(ctx, Sample{Tenant: "a", UseCase: "sync", Operation: "GET /organizations", Requests: 1})
+	accountant.RecordCost(ctx, Sample{Tenant: "a", UseCase: "sync", Operation: "POST /organizations", Requests: 3, Failed: true, RequestBytes: 10, ResponseBytes: 20})
+	accountant.RecordCost(ctx, Sample{Tenant: "a", UseCase: "sync", Operation: "GET /organizations", Requests: 1})
+
+	report := accountant.Report()
+	require.Len(t, report.Entries, 3)
+	assert.InDelta(t, 6.0, report.TotalCost, 1e-9)
+
+	assert.Equal(t, Entry{
+		Tenant: "a", UseCase: "sync", Operation: "POST /organizations",
+		Calls: 1, Failures: 1, Requests: 3, RequestBytes: 10, ResponseBytes: 20, Cost: 3,
+	}, report.Entries[0])
+	assert.Equal(t, "GET /organizations", report.Entries[1].Operation)
+	assert.Equal(t, int64(2), report.Entries[1].Calls)
+	assert.Equal(t, "b", report.Entries[2].Tenant)
+
+	var buf bytes.Buffer
+	require.NoError(t, accountant.WriteJSON(&buf))
+
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Len(t, decoded.Entries, 3)
+
+	accountant.Reset()
+	assert.Empty(t, accountant.Report().Entries)
+}
+
+func TestAccountantConcurrent(t *testing.T) {
+	accountant := NewAccountant(Pricing{PerRequest: 1})
+
+	var wg sync.WaitGroup
+
+	for range 50 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			accountant.RecordCost(context.Background(), Sample{Operation: "GET /organizations", Requests: 1})
+		}()
+	}
+
+	wg.Wait()
+
+	report := accountant.Report()
+	require.Len(t, report.Entries, 1)
+	assert.Equal(t, int64(50), report.Entries[0].Calls)
+}
+
+func TestHookFunc(t *testing.T) {
+	var got Sample
+
+	var hook Hook = HookFunc(func(_ context.Context, sample Sample) { got = sample })
+
+	hook.RecordCost(context.Background(), Sample{Operation: "GET /organizations"})
+	assert.Equal(t, "GET /organizations", got.Operation)
+}
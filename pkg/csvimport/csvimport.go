@@ -0,0 +1,568 @@
+// Package csvimport imports the transactions of bank and PSP CSV files.
+//
+// An Importer reads a CSV file with a Mapping of its columns, turns each row
+// into a transaction between the statement account and a counterparty,
+// validates it, drops the rows repeated in the file, and submits the rest
+// with transaction.BatchTransactions. The idempotency key of each transaction
+// is derived from the row ID, so importing a file again, or overlapping
+// statements, creates each transaction once. The returned Summary reconciles
+// the totals of the file with those imported.
+//
+//	mapping, err := csvimport.LoadMapping("./mappings/bank-a.json")
+//	if err != nil {
+//	    return err
+//	}
+//
+//	importer, err := csvimport.New(c, orgID, ledgerID, mapping)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	summary, err := importer.WithKeyPrefix("bank-a").ImportFile(ctx, "./statement-2026-09.csv")
+package csvimport
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/transaction"
+	"github.com/shopspring/decimal"
+)
+
+// Row is a row of a CSV file, mapped.
+type Row struct {
+	// Line is the line of the row in the file
+	Line int
+	// ID is the mapped row ID, or a hash of the row when the mapping has none
+	ID string
+	// Date is zero when the mapping has no date column
+	Date         time.Time
+	Asset        string
+	Account      string
+	Counterparty string
+	// Amount is positive for a credit to Account, negative for a debit
+	Amount      decimal.Decimal
+	Description string
+	Metadata    map[string]any
+}
+
+// RowError is a row that was not imported.
+type RowError struct {
+	Line  int    `json:"line"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
+
+// AssetTotals reconciles the rows of an asset in the file with those imported.
+type AssetTotals struct {
+	Asset string `json:"asset"`
+	Rows  int    `json:"rows"`
+
+	// Credits and Debits total the valid rows of the file, Debits as a positive amount
+	Credits decimal.Decimal `json:"credits"`
+	Debits  decimal.Decimal `json:"debits"`
+
+	// ImportedCredits and ImportedDebits total the rows whose transaction
+	// was created by the import or already existed
+	ImportedCredits decimal.Decimal `json:"importedCredits"`
+	ImportedDebits  decimal.Decimal `json:"importedDebits"`
+}
+
+// Summary is the outcome of an import.
+type Summary struct {
+	DryRun bool `json:"dryRun"`
+
+	// Rows counts the data rows of the file, Valid those mapped and validated
+	Rows  int `json:"rows"`
+	Valid int `json:"valid"`
+	// Duplicates counts the valid rows repeating the ID of an earlier row of the file
+	Duplicates int `json:"duplicates"`
+	// Submitted counts the transactions created, AlreadyImported those
+	// created by an earlier import
+	Submitted       int `json:"submitted"`
+	AlreadyImported int `json:"alreadyImported"`
+
+	Invalid []RowError `json:"invalid,omitempty"`
+	Failed  []RowError `json:"failed,omitempty"`
+
+	// Assets holds the totals of each asset, sorted by asset
+	Assets []AssetTotals `json:"assets"`
+}
+
+// Reconciled reports whether every row of the file was imported: none was
+// invalid or failed, and the imported totals match the file.
+func (s *Summary) Reconciled() bool {
+	if s.DryRun || len(s.Invalid) > 0 || len(s.Failed) > 0 {
+		return false
+	}
+
+	for _, totals := range s.Assets {
+		if !totals.Credits.Equal(totals.ImportedCredits) || !totals.Debits.Equal(totals.ImportedDebits) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Importer imports CSV files into a ledger.
+type Importer struct {
+	client   *client.Client
+	orgID    string
+	ledgerID string
+	mapping  Mapping
+
+	batch     *transaction.BatchOptions
+	keyPrefix string
+	dryRun    bool
+	validate  func(*Row) error
+}
+
+// New returns an Importer into the ledger ledgerID of orgID, reading files
+// with mapping.
+//
+// Parameters:
+//   - midazClient: The Midaz SDK client
+//   - orgID: The organization ID
+//   - ledgerID: The ledger ID
+//   - mapping: The column mapping of the files
+//
+// Returns:
+//   - An Importer, or an error if the mapping is invalid
+func New(midazClient *client.Client, orgID, ledgerID string, mapping *Mapping) (*Importer, error) {
+	if mapping == nil {
+		return nil, errors.New("mapping is required")
+	}
+
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Importer{
+		client:    midazClient,
+		orgID:     orgID,
+		ledgerID:  ledgerID,
+		mapping:   *mapping,
+		batch:     transaction.DefaultBatchOptions(),
+		keyPrefix: "csv",
+	}, nil
+}
+
+// WithBatchOptions sets the options of the submissions; their StopOnError is
+// ignored.
+func (im *Importer) WithBatchOptions(options *transaction.BatchOptions) *Importer {
+	if options != nil {
+		im.batch = options
+	}
+
+	return im
+}
+
+// WithKeyPrefix sets the prefix of the idempotency keys, "csv" by default.
+// Files of different banks or PSPs, whose row IDs may collide, should use
+// different prefixes.
+func (im *Importer) WithKeyPrefix(prefix string) *Importer {
+	if prefix != "" {
+		im.keyPrefix = prefix
+	}
+
+	return im
+}
+
+// WithDryRun makes the import read and validate the file without submitting
+// anything.
+func (im *Importer) WithDryRun(dryRun bool) *Importer {
+	im.dryRun = dryRun
+	return im
+}
+
+// WithValidate checks the mapped rows further, such as against the known
+// accounts; an error makes the row invalid.
+func (im *Importer) WithValidate(fn func(*Row) error) *Importer {
+	im.validate = fn
+	return im
+}
+
+// ImportFile imports the CSV file at path.
+func (im *Importer) ImportFile(ctx context.Context, path string) (*Summary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening CSV file: %w", err)
+	}
+	defer f.Close()
+
+	return im.Import(ctx, f)
+}
+
+// pendingRow is a valid row and its transaction.
+type pendingRow struct {
+	row   *Row
+	input *models.CreateTransactionInput
+}
+
+// Import imports the CSV file read from r. It returns an error when the file
+// can't be read or lacks a mapped column; rows that are invalid or fail are
+// reported in the Summary.
+func (im *Importer) Import(ctx context.Context, r io.Reader) (*Summary, error) {
+	summary := &Summary{DryRun: im.dryRun}
+	totals := map[string]*AssetTotals{}
+	seen := map[string]bool{}
+
+	var pending []pendingRow
+
+	err := im.readRows(r, func(line int, fields map[string]string) {
+		summary.Rows++
+
+		row, err := im.mapRow(line, fields)
+		if err == nil {
+			err = im.validateRow(row)
+		}
+
+		var input *models.CreateTransactionInput
+		if err == nil {
+			input = im.transactionInput(row)
+			err = input.Validate()
+		}
+
+		if err != nil {
+			rowError := RowError{Line: line, Error: err.Error()}
+			if row != nil {
+				rowError.ID = row.ID
+			}
+
+			summary.Invalid = append(summary.Invalid, rowError)
+
+			return
+		}
+
+		summary.Valid++
+
+		if seen[row.ID] {
+			summary.Duplicates++
+			return
+		}
+
+		seen[row.ID] = true
+
+		t := assetTotals(totals, row.Asset)
+		t.Rows++
+
+		if row.Amount.IsPositive() {
+			t.Credits = t.Credits.Add(row.Amount)
+		} else {
+			t.Debits = t.Debits.Add(row.Amount.Neg())
+		}
+
+		pending = append(pending, pendingRow{row: row, input: input})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !im.dryRun {
+		im.submit(ctx, pending, summary, totals)
+	}
+
+	assets := make([]string, 0, len(totals))
+	for asset := range totals {
+		assets = append(assets, asset)
+	}
+
+	slices.Sort(assets)
+
+	summary.Assets = make([]AssetTotals, 0, len(assets))
+	for _, asset := range assets {
+		summary.Assets = append(summary.Assets, *totals[asset])
+	}
+
+	return summary, nil
+}
+
+// submit submits the transactions of rows and reconciles their totals.
+func (im *Importer) submit(ctx context.Context, rows []pendingRow, summary *Summary, totals map[string]*AssetTotals) {
+	if len(rows) == 0 {
+		return
+	}
+
+	options := *im.batch
+	options.StopOnError = false
+
+	inputs := make([]*models.CreateTransactionInput, len(rows))
+	for i, p := range rows {
+		inputs[i] = p.input
+	}
+
+	results, _ := transaction.BatchTransactions(ctx, im.client, im.orgID, im.ledgerID, inputs, &options)
+
+	for i, result := range results {
+		row := rows[i].row
+
+		switch {
+		case result.Error == nil:
+			summary.Submitted++
+		case sdkerrors.IsIdempotencyError(result.Error):
+			summary.AlreadyImported++
+		default:
+			summary.Failed = append(summary.Failed, RowError{Line: row.Line, ID: row.ID, Error: result.Error.Error()})
+			continue
+		}
+
+		t := totals[row.Asset]
+
+		if row.Amount.IsPositive() {
+			t.ImportedCredits = t.ImportedCredits.Add(row.Amount)
+		} else {
+			t.ImportedDebits = t.ImportedDebits.Add(row.Amount.Neg())
+		}
+	}
+}
+
+// readRows calls fn with the fields of each data row of r, by column.
+func (im *Importer) readRows(r io.Reader, fn func(line int, fields map[string]string)) error {
+	buffered := bufio.NewReader(r)
+
+	for i := 0; i < im.mapping.SkipRows; i++ {
+		if _, err := buffered.ReadString('\n'); err != nil {
+			return fmt.Errorf("skipping line %d: %w", i+1, err)
+		}
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	if im.mapping.Delimiter != "" {
+		reader.Comma = []rune(im.mapping.Delimiter)[0]
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	for _, column := range im.mapping.columns() {
+		if !slices.Contains(header, column) {
+			return fmt.Errorf("CSV header has no column %q", column)
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("reading CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		line += im.mapping.SkipRows
+
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		fields := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(record) {
+				fields[column] = strings.TrimSpace(record[i])
+			}
+		}
+
+		fn(line, fields)
+	}
+}
+
+// mapRow maps the fields of a row. The row is returned with its ID when
+// only a later field is invalid.
+func (im *Importer) mapRow(line int, fields map[string]string) (*Row, error) {
+	m := &im.mapping
+	row := &Row{Line: line, Description: fields[m.Description]}
+
+	row.Asset = orDefault(fields[m.Asset], m.DefaultAsset)
+	row.Account = orDefault(fields[m.Account], m.DefaultAccount)
+	row.Counterparty = orDefault(fields[m.Counterparty], m.DefaultCounterparty)
+
+	if row.Asset == "" {
+		return nil, errors.New("asset is empty")
+	}
+
+	if row.Account == "" {
+		return nil, errors.New("account is empty")
+	}
+
+	if row.Counterparty == "" {
+		row.Counterparty = "@external/" + row.Asset
+	}
+
+	if m.ID != "" {
+		if row.ID = fields[m.ID]; row.ID == "" {
+			return nil, fmt.Errorf("column %q is empty", m.ID)
+		}
+	}
+
+	amount, err := im.rowAmount(fields)
+	if err != nil {
+		return im.withID(row, fields), err
+	}
+
+	row.Amount = amount
+
+	if m.Date != "" {
+		if row.Date, err = m.parseDate(fields[m.Date]); err != nil {
+			return im.withID(row, fields), err
+		}
+	}
+
+	if len(m.Metadata) > 0 {
+		row.Metadata = make(map[string]any, len(m.Metadata))
+		for key, column := range m.Metadata {
+			if value := fields[column]; value != "" {
+				row.Metadata[key] = value
+			}
+		}
+	}
+
+	return im.withID(row, fields), nil
+}
+
+// rowAmount returns the signed amount of a row.
+func (im *Importer) rowAmount(fields map[string]string) (decimal.Decimal, error) {
+	m := &im.mapping
+
+	if m.Amount != "" {
+		amount, err := m.parseAmount(fields[m.Amount])
+		if err != nil {
+			return decimal.Zero, err
+		}
+
+		if amount.IsZero() {
+			return decimal.Zero, errors.New("amount is zero")
+		}
+
+		return amount, nil
+	}
+
+	amount := decimal.Zero
+
+	for _, column := range []string{m.Credit, m.Debit} {
+		if column == "" || fields[column] == "" {
+			continue
+		}
+
+		value, err := m.parseAmount(fields[column])
+		if err != nil {
+			return decimal.Zero, err
+		}
+
+		if column == m.Debit {
+			amount = amount.Sub(value.Abs())
+		} else {
+			amount = amount.Add(value.Abs())
+		}
+	}
+
+	if amount.IsZero() {
+		return decimal.Zero, errors.New("credit and debit are both empty or zero")
+	}
+
+	return amount, nil
+}
+
+// withID sets the ID of a row without a mapped one to a hash of its fields.
+func (im *Importer) withID(row *Row, fields map[string]string) *Row {
+	if row.ID != "" {
+		return row
+	}
+
+	h := sha256.New()
+	for _, column := range im.mapping.columns() {
+		h.Write([]byte(fields[column]))
+		h.Write([]byte{0})
+	}
+
+	row.ID = hex.EncodeToString(h.Sum(nil))[:32]
+
+	return row
+}
+
+// validateRow runs the validation set with WithValidate.
+func (im *Importer) validateRow(row *Row) error {
+	if im.validate == nil {
+		return nil
+	}
+
+	return im.validate(row)
+}
+
+// transactionInput returns the transaction of a row.
+func (im *Importer) transactionInput(row *Row) *models.CreateTransactionInput {
+	amount := row.Amount.Abs()
+
+	from, to := row.Counterparty, row.Account
+	if row.Amount.IsNegative() {
+		from, to = to, from
+	}
+
+	metadata := map[string]any{"importSource": "csv", "importRowId": row.ID}
+	if !row.Date.IsZero() {
+		metadata["bookingDate"] = row.Date.Format(DefaultDateFormat)
+	}
+
+	for key, value := range row.Metadata {
+		metadata[key] = value
+	}
+
+	input := models.NewCreateTransactionInputFromDecimal(row.Asset, amount).
+		WithSend(models.NewSendInput(row.Asset, amount,
+			[]models.FromToInput{models.NewFromToInput(from, row.Asset, amount)},
+			[]models.FromToInput{models.NewFromToInput(to, row.Asset, amount)})).
+		WithDescription(row.Description).
+		WithMetadata(metadata)
+
+	if im.mapping.ID != "" {
+		input.ExternalID = row.ID
+	}
+
+	input.IdempotencyKey = im.keyPrefix + "-" + row.ID
+
+	return input
+}
+
+// assetTotals returns the totals of asset, creating them.
+func assetTotals(totals map[string]*AssetTotals, asset string) *AssetTotals {
+	t, ok := totals[asset]
+	if !ok {
+		t = &AssetTotals{Asset: asset}
+		totals[asset] = t
+	}
+
+	return t
+}
+
+// orDefault returns value, or fallback when value is empty.
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
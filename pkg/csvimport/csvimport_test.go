@@ -0,0 +1,265 @@
+package csvimport
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTransactions creates each idempotency key once and fails the
+// transactions of the rejected accounts.
+type fakeTransactions struct {
+	entities.TransactionsService
+
+	mu       sync.Mutex
+	keys     map[string]bool
+	inputs   []*models.CreateTransactionInput
+	rejected map[string]bool
+}
+
+func newFakeTransactions() *fakeTransactions {
+	return &fakeTransactions{keys: map[string]bool{}, rejected: map[string]bool{}}
+}
+
+func (f *fakeTransactions) CreateTransaction(_ context.Context, _, _ string, input *models.CreateTransactionInput) (*models.Transaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.keys[input.IdempotencyKey] {
+		return nil, &sdkerrors.Error{Category: sdkerrors.CategoryConflict, Code: sdkerrors.CodeIdempotency, Message: "already processed"}
+	}
+
+	for _, account := range []string{input.Send.Source.From[0].Account, input.Send.Distribute.To[0].Account} {
+		if f.rejected[account] {
+			return nil, sdkerrors.NewAccountEligibilityError("CreateTransaction", account, errors.New("account is blocked"))
+		}
+	}
+
+	f.keys[input.IdempotencyKey] = true
+	f.inputs = append(f.inputs, input)
+
+	return &models.Transaction{ID: input.IdempotencyKey}, nil
+}
+
+const statementMapping = `{
+  "delimiter": ";",
+  "skipRows": 2,
+  "id": "Transaction ID",
+  "date": "Date",
+  "dateFormat": "02/01/2006",
+  "amount": "Amount",
+  "decimalSeparator": ",",
+  "thousandsSeparator": ".",
+  "defaultAsset": "BRL",
+  "defaultAccount": "@operating",
+  "counterparty": "Payer",
+  "description": "Memo",
+  "metadata": {"bankReference": "Reference"}
+}`
+
+const statement = "Bank A statement\n" +
+	"Account 0001-2\n" +
+	"\ufeffTransaction ID;Date;Amount;Payer;Memo;Reference\n" +
+	"t-1;02/09/2026;1.250,50;@customer-1;Invoice 10;REF-1\n" +
+	"t-2;03/09/2026;(100,00);;Bank fee;\n" +
+	"t-1;02/09/2026;1.250,50;@customer-1;Invoice 10;REF-1\n" +
+	"t-3;03/09/2026;abc;;Broken;\n" +
+	"t-4;31/02/2026;10,00;;Bad date;\n" +
+	"\n" +
+	"t-5;04/09/2026;300,00-;@supplier-1;Payment;REF-5\n"
+
+func testImporter(t *testing.T, txs *fakeTransactions, mappingJSON string) *Importer {
+	t.Helper()
+
+	mapping, err := ParseMapping([]byte(mappingJSON))
+	require.NoError(t, err)
+
+	importer, err := New(&client.Client{Entity: &entities.Entity{Transactions: txs}}, "org", "ledger", mapping)
+	require.NoError(t, err)
+
+	batch := transaction.DefaultBatchOptions()
+	batch.RetryCount = 0
+	batch.Concurrency = 1
+
+	return importer.WithBatchOptions(batch).WithKeyPrefix("bank-a")
+}
+
+func TestImport(t *testing.T) {
+	txs := newFakeTransactions()
+	importer := testImporter(t, txs, statementMapping)
+
+	summary, err := importer.Import(context.Background(), strings.NewReader(statement))
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, summary.Rows)
+	assert.Equal(t, 4, summary.Valid)
+	assert.Equal(t, 1, summary.Duplicates)
+	assert.Equal(t, 3, summary.Submitted)
+	assert.Empty(t, summary.Failed)
+
+	require.Len(t, summary.Invalid, 2)
+	assert.Equal(t, RowError{Line: 7, ID: "t-3", Error: `invalid amount "abc"`}, summary.Invalid[0])
+	assert.Equal(t, 8, summary.Invalid[1].Line)
+	assert.Contains(t, summary.Invalid[1].Error, "invalid date")
+	assert.False(t, summary.Reconciled())
+
+	require.Len(t, summary.Assets, 1)
+	assert.Equal(t, "BRL", summary.Assets[0].Asset)
+	assert.Equal(t, 3, summary.Assets[0].Rows)
+	assert.Equal(t, "1250.5", summary.Assets[0].Credits.String())
+	assert.Equal(t, "400", summary.Assets[0].Debits.String())
+	assert.Equal(t, "1250.5", summary.Assets[0].ImportedCredits.String())
+	assert.Equal(t, "400", summary.Assets[0].ImportedDebits.String())
+
+	require.Len(t, txs.inputs, 3)
+
+	invoice := txs.inputs[0]
+	assert.Equal(t, "bank-a-t-1", invoice.IdempotencyKey)
+	assert.Equal(t, "t-1", invoice.ExternalID)
+	assert.Equal(t, "1250.5", invoice.Amount)
+	assert.Equal(t, "@customer-1", invoice.Send.Source.From[0].Account)
+	assert.Equal(t, "@operating", invoice.Send.Distribute.To[0].Account)
+	assert.Equal(t, "Invoice 10", invoice.Description)
+	assert.Equal(t, map[string]any{"importSource": "csv", "importRowId": "t-1", "bookingDate": "2026-09-02", "bankReference": "REF-1"}, invoice.Metadata)
+
+	fee := txs.inputs[1]
+	assert.Equal(t, "@operating", fee.Send.Source.From[0].Account)
+	assert.Equal(t, "@external/BRL", fee.Send.Distribute.To[0].Account)
+	assert.NotContains(t, fee.Metadata, "bankReference")
+
+	payment := txs.inputs[2]
+	assert.Equal(t, "300", payment.Amount)
+	assert.Equal(t, "@supplier-1", payment.Send.Distribute.To[0].Account)
+}
+
+func TestImportAgain(t *testing.T) {
+	txs := newFakeTransactions()
+	txs.rejected["@supplier-1"] = true
+
+	file := "Transaction ID;Date;Amount;Payer;Memo;Reference\n" +
+		"t-1;02/09/2026;10,00;@customer-1;;\n" +
+		"t-5;04/09/2026;-300,00;@supplier-1;;\n"
+	mapping := strings.Replace(statementMapping, `"skipRows": 2,`, "", 1)
+
+	first, err := testImporter(t, txs, mapping).Import(context.Background(), strings.NewReader(file))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, first.Submitted)
+	require.Len(t, first.Failed, 1)
+	assert.Equal(t, "t-5", first.Failed[0].ID)
+	assert.Equal(t, "0", first.Assets[0].ImportedDebits.String())
+	assert.False(t, first.Reconciled())
+
+	txs.rejected = map[string]bool{}
+
+	path := filepath.Join(t.TempDir(), "statement.csv")
+	require.NoError(t, os.WriteFile(path, []byte(file), 0o600))
+
+	second, err := testImporter(t, txs, mapping).ImportFile(context.Background(), path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, second.Submitted)
+	assert.Equal(t, 1, second.AlreadyImported)
+	assert.True(t, second.Reconciled())
+	assert.Len(t, txs.inputs, 2)
+}
+
+func TestImportCreditDebitColumns(t *testing.T) {
+	txs := newFakeTransactions()
+	importer := testImporter(t, txs, `{
+	  "credit": "credit", "debit": "debit", "asset": "currency",
+	  "account": "account", "defaultAccount": "@treasury", "defaultCounterparty": "@psp"
+	}`)
+
+	file := "account,currency,credit,debit\n" +
+		"@wallet-1,USD,25.00,\n" +
+		",USD,,-5\n" +
+		",USD,,-5\n" +
+		",,1,\n" +
+		",EUR,,\n"
+
+	summary, err := importer.WithDryRun(true).Import(context.Background(), strings.NewReader(file))
+	require.NoError(t, err)
+
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, 5, summary.Rows)
+	assert.Equal(t, 3, summary.Valid)
+	assert.Equal(t, 1, summary.Duplicates, "identical rows without an ID share their hash")
+	assert.Zero(t, summary.Submitted)
+	assert.Empty(t, txs.inputs)
+	assert.False(t, summary.Reconciled())
+
+	require.Len(t, summary.Invalid, 2)
+	assert.Equal(t, "asset is empty", summary.Invalid[0].Error)
+	assert.Equal(t, "credit and debit are both empty or zero", summary.Invalid[1].Error)
+
+	require.Len(t, summary.Assets, 1)
+	assert.Equal(t, "25", summary.Assets[0].Credits.String())
+	assert.Equal(t, "5", summary.Assets[0].Debits.String())
+
+	rows := 0
+	importer.WithDryRun(false).WithValidate(func(row *Row) error {
+		rows++
+
+		if row.Account != "@wallet-1" {
+			return errors.New("unknown account")
+		}
+
+		assert.Equal(t, "@psp", row.Counterparty)
+		assert.Len(t, row.ID, 32)
+
+		return nil
+	})
+
+	summary, err = importer.Import(context.Background(), strings.NewReader(file))
+	require.NoError(t, err)
+	assert.Equal(t, 3, rows)
+	assert.Equal(t, 1, summary.Submitted)
+	assert.Equal(t, "@psp", txs.inputs[0].Send.Source.From[0].Account)
+	assert.Empty(t, txs.inputs[0].ExternalID)
+}
+
+func TestMappingErrors(t *testing.T) {
+	tests := map[string]string{
+		"not JSON":            `{`,
+		"unknown field":       `{"amount": "a", "defaultAsset": "USD", "defaultAccount": "@a", "separator": ";"}`,
+		"no amount":           `{"defaultAsset": "USD", "defaultAccount": "@a"}`,
+		"amount and credit":   `{"amount": "a", "credit": "c", "defaultAsset": "USD", "defaultAccount": "@a"}`,
+		"no asset":            `{"amount": "a", "defaultAccount": "@a"}`,
+		"no account":          `{"amount": "a", "defaultAsset": "USD"}`,
+		"long delimiter":      `{"amount": "a", "defaultAsset": "USD", "defaultAccount": "@a", "delimiter": ";;"}`,
+		"same separators":     `{"amount": "a", "defaultAsset": "USD", "defaultAccount": "@a", "decimalSeparator": ",", "thousandsSeparator": ","}`,
+		"format without date": `{"amount": "a", "defaultAsset": "USD", "defaultAccount": "@a", "dateFormat": "2006"}`,
+	}
+
+	for name, mapping := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseMapping([]byte(mapping))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := New(nil, "org", "ledger", nil)
+	require.Error(t, err)
+
+	_, err = LoadMapping(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+
+	importer := testImporter(t, newFakeTransactions(), `{"amount": "amount", "defaultAsset": "USD", "defaultAccount": "@a", "description": "memo"}`)
+
+	_, err = importer.Import(context.Background(), strings.NewReader("amount\n1\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `no column "memo"`)
+}
@@ -0,0 +1,230 @@
+package csvimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultDateFormat is the layout of the date column when the mapping sets none.
+const DefaultDateFormat = "2006-01-02"
+
+// Mapping maps the columns of a CSV file, named by its header row, to
+// transactions. Each row moves its amount between the statement account and
+// a counterparty: a positive amount credits the account from the
+// counterparty, a negative one debits it to the counterparty.
+//
+// A Mapping is usually kept as a JSON file per bank or PSP, loaded with
+// LoadMapping:
+//
+//	{
+//	  "delimiter": ";",
+//	  "skipRows": 2,
+//	  "id": "Transaction ID",
+//	  "date": "Date",
+//	  "dateFormat": "02/01/2006",
+//	  "amount": "Amount",
+//	  "decimalSeparator": ",",
+//	  "thousandsSeparator": ".",
+//	  "defaultAsset": "BRL",
+//	  "defaultAccount": "@operating",
+//	  "counterparty": "Payer alias",
+//	  "description": "Memo",
+//	  "metadata": {"bankReference": "Reference"}
+//	}
+type Mapping struct {
+	// Delimiter separates the fields, "," by default
+	Delimiter string `json:"delimiter,omitempty"`
+	// SkipRows is the number of lines before the header row, such as a statement preamble
+	SkipRows int `json:"skipRows,omitempty"`
+
+	// ID is the column of the row identifier in the bank or PSP, used to
+	// deduplicate rows and derive idempotency keys. Without it, rows are
+	// identified by a hash of their mapped fields, so identical rows of a file
+	// are taken as duplicates.
+	ID string `json:"id,omitempty"`
+
+	// Date is the column of the booking date, recorded in the metadata
+	Date string `json:"date,omitempty"`
+	// DateFormat is the Go layout of the date column, DefaultDateFormat by default
+	DateFormat string `json:"dateFormat,omitempty"`
+
+	// Amount is the column of the signed amount; alternatively, Credit and
+	// Debit are the columns of the unsigned credited and debited amounts
+	Amount string `json:"amount,omitempty"`
+	Credit string `json:"credit,omitempty"`
+	Debit  string `json:"debit,omitempty"`
+	// DecimalSeparator and ThousandsSeparator describe the number format,
+	// "." and none by default
+	DecimalSeparator   string `json:"decimalSeparator,omitempty"`
+	ThousandsSeparator string `json:"thousandsSeparator,omitempty"`
+
+	// Asset is the column of the asset code, DefaultAsset when unset or empty
+	Asset        string `json:"asset,omitempty"`
+	DefaultAsset string `json:"defaultAsset,omitempty"`
+
+	// Account is the column of the statement account alias, DefaultAccount when unset or empty
+	Account        string `json:"account,omitempty"`
+	DefaultAccount string `json:"defaultAccount,omitempty"`
+
+	// Counterparty is the column of the counterparty alias, DefaultCounterparty
+	// when unset or empty, and "@external/<asset>" when both are
+	Counterparty        string `json:"counterparty,omitempty"`
+	DefaultCounterparty string `json:"defaultCounterparty,omitempty"`
+
+	// Description is the column of the transaction description
+	Description string `json:"description,omitempty"`
+
+	// Metadata maps metadata keys to the columns holding their values
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ParseMapping decodes and validates a JSON mapping, rejecting unknown fields.
+func ParseMapping(data []byte) (*Mapping, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var mapping Mapping
+	if err := decoder.Decode(&mapping); err != nil {
+		return nil, fmt.Errorf("decoding mapping: %w", err)
+	}
+
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &mapping, nil
+}
+
+// LoadMapping reads a JSON mapping from path.
+func LoadMapping(path string) (*Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading mapping: %w", err)
+	}
+
+	return ParseMapping(data)
+}
+
+// Validate checks that the mapping is complete and consistent.
+func (m *Mapping) Validate() error {
+	var errs []error
+
+	if m.Delimiter != "" && utf8.RuneCountInString(m.Delimiter) != 1 {
+		errs = append(errs, fmt.Errorf("delimiter must be a single character, got %q", m.Delimiter))
+	}
+
+	if m.SkipRows < 0 {
+		errs = append(errs, errors.New("skipRows must not be negative"))
+	}
+
+	switch {
+	case m.Amount == "" && m.Credit == "" && m.Debit == "":
+		errs = append(errs, errors.New("amount, or credit and debit, must be mapped"))
+	case m.Amount != "" && (m.Credit != "" || m.Debit != ""):
+		errs = append(errs, errors.New("amount can't be mapped with credit or debit"))
+	}
+
+	if m.DecimalSeparator != "" && m.DecimalSeparator == m.ThousandsSeparator {
+		errs = append(errs, errors.New("decimalSeparator and thousandsSeparator must differ"))
+	}
+
+	if m.Asset == "" && m.DefaultAsset == "" {
+		errs = append(errs, errors.New("asset or defaultAsset must be set"))
+	}
+
+	if m.Account == "" && m.DefaultAccount == "" {
+		errs = append(errs, errors.New("account or defaultAccount must be set"))
+	}
+
+	if m.Date == "" && m.DateFormat != "" {
+		errs = append(errs, errors.New("dateFormat is set without a date column"))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid mapping: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// columns returns the columns the mapping reads, in a stable order, to check
+// against the header and hash rows without an ID.
+func (m *Mapping) columns() []string {
+	metadata := make([]string, 0, len(m.Metadata))
+	for _, column := range m.Metadata {
+		metadata = append(metadata, column)
+	}
+
+	slices.Sort(metadata)
+
+	columns := append([]string{m.ID, m.Date, m.Amount, m.Credit, m.Debit, m.Asset, m.Account, m.Counterparty, m.Description}, metadata...)
+
+	mapped := columns[:0]
+
+	for _, column := range columns {
+		if column != "" {
+			mapped = append(mapped, column)
+		}
+	}
+
+	return mapped
+}
+
+// parseAmount parses an amount in the number format of the mapping. A
+// trailing minus sign or parentheses, as printed by some banks, make it negative.
+func (m *Mapping) parseAmount(s string) (decimal.Decimal, error) {
+	raw := s
+	s = strings.TrimSpace(s)
+
+	negative := false
+
+	switch {
+	case strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")"):
+		negative, s = true, s[1:len(s)-1]
+	case strings.HasSuffix(s, "-"):
+		negative, s = true, strings.TrimSuffix(s, "-")
+	}
+
+	if m.ThousandsSeparator != "" {
+		s = strings.ReplaceAll(s, m.ThousandsSeparator, "")
+	}
+
+	if m.DecimalSeparator != "" && m.DecimalSeparator != "." {
+		s = strings.ReplaceAll(s, m.DecimalSeparator, ".")
+	}
+
+	amount, err := decimal.NewFromString(strings.TrimSpace(s))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid amount %q", raw)
+	}
+
+	if negative {
+		amount = amount.Neg()
+	}
+
+	return amount, nil
+}
+
+// parseDate parses a date in the format of the mapping.
+func (m *Mapping) parseDate(s string) (time.Time, error) {
+	layout := m.DateFormat
+	if layout == "" {
+		layout = DefaultDateFormat
+	}
+
+	date, err := time.Parse(layout, strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected layout %q", s, layout)
+	}
+
+	return date, nil
+}
@@ -1,15 +1,20 @@
 package data
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
 	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/rounding"
+	"github.com/shopspring/decimal"
 )
 
 // AmountGenerator provides helpers to generate realistic transaction amounts
 // while respecting asset scale/precision.
 type AmountGenerator struct {
-	r *rand.Rand
+	r        *rand.Rand
+	rounding rounding.Policy
 }
 
 // NewAmountGenerator creates a generator seeded for reproducibility.
@@ -22,6 +27,39 @@ func NewAmountGenerator(seed int64) *AmountGenerator {
 	return &AmountGenerator{r: rand.New(rand.NewSource(seed))}
 }
 
+// SetRoundingPolicy sets how drawn amounts are brought to minor units of
+// their scale: rounding.PolicyHalfEven or rounding.PolicyTruncate. Drawn
+// amounts almost never fit a scale, so rounding.PolicyError is rejected.
+// Without a policy, amounts are rounded half away from zero.
+//
+// Example:
+//
+//	gen := data.NewAmountGenerator(42)
+//	if err := gen.SetRoundingPolicy(cfg.RoundingPolicy); err != nil {
+//	    return err
+//	}
+func (g *AmountGenerator) SetRoundingPolicy(policy rounding.Policy) error {
+	if policy == rounding.PolicyError || (policy != "" && !policy.IsValid()) {
+		return fmt.Errorf("invalid rounding policy for generated amounts %q: must be half_even or truncate", policy)
+	}
+
+	g.rounding = policy
+
+	return nil
+}
+
+// toMinor converts an amount in major units to minor units of scale under
+// the generator's rounding policy.
+func (g *AmountGenerator) toMinor(val float64, scale int) int64 {
+	if g.rounding != "" {
+		if minor, err := g.rounding.Minor(decimal.NewFromFloat(val), int32(scale)); err == nil {
+			return minor
+		}
+	}
+
+	return int64(math.Round(val * math.Pow10(scale)))
+}
+
 // Normal generates an amount following a truncated normal distribution centered at mean
 // with the given standard deviation. Returns the integer minor units (e.g., cents).
 func (g *AmountGenerator) Normal(mean, stddev float64, scale int) int64 {
@@ -34,9 +72,7 @@ func (g *AmountGenerator) Normal(mean, stddev float64, scale int) int64 {
 		val = 0
 	}
 
-	factor := math.Pow10(scale)
-
-	return int64(math.Round(val * factor))
+	return g.toMinor(val, scale)
 }
 
 // PowerLaw generates amounts where small values are common and large values are rare
@@ -57,9 +93,7 @@ func (g *AmountGenerator) PowerLaw(minVal, maxVal float64, alpha float64, scale
 	u := 1 - g.r.Float64() // (0,1]
 	// Use alpha in the power law formula: x = min * (1 - u * (1 - (min/max)^alpha))^(-1/alpha)
 	x := minVal * math.Pow(1-u*(1-math.Pow(minVal/maxVal, alpha)), -1/alpha)
-	factor := math.Pow10(scale)
-
-	return int64(math.Round(x * factor))
+	return g.toMinor(x, scale)
 }
 
 // Exponential generates amounts following an exponential distribution with the given mean.
@@ -74,9 +108,7 @@ func (g *AmountGenerator) Exponential(mean float64, scale int) int64 {
 		val = 0
 	}
 
-	factor := math.Pow10(scale)
-
-	return int64(math.Round(val * factor))
+	return g.toMinor(val, scale)
 }
 
 // Uniform generates amounts uniformly in [minVal, maxVal].
@@ -91,7 +123,5 @@ func (g *AmountGenerator) Uniform(minVal, maxVal float64, scale int) int64 {
 	}
 
 	val := minVal + g.r.Float64()*(maxVal-minVal)
-	factor := math.Pow10(scale)
-
-	return int64(math.Round(val * factor))
+	return g.toMinor(val, scale)
 }
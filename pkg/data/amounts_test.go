@@ -4,6 +4,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/rounding"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -451,3 +452,23 @@ func BenchmarkUniform(b *testing.B) {
 		gen.Uniform(10, 100, 2)
 	}
 }
+
+func TestAmountGenerator_SetRoundingPolicy(t *testing.T) {
+	draw := func(gen *AmountGenerator, val float64) int64 {
+		return gen.Histogram([]HistogramBucket{{Min: val, Max: val, Weight: 1}}, 2)
+	}
+
+	gen := NewAmountGenerator(42)
+	assert.Equal(t, int64(124), draw(gen, 1.239))
+
+	require.NoError(t, gen.SetRoundingPolicy(rounding.PolicyTruncate))
+	assert.Equal(t, int64(123), draw(gen, 1.239))
+
+	require.NoError(t, gen.SetRoundingPolicy(rounding.PolicyHalfEven))
+	assert.Equal(t, int64(122), draw(gen, 1.225))
+	assert.Equal(t, int64(124), draw(gen, 1.235))
+
+	require.Error(t, gen.SetRoundingPolicy(rounding.PolicyError))
+	require.Error(t, gen.SetRoundingPolicy("ceiling"))
+	require.NoError(t, gen.SetRoundingPolicy(""))
+}
@@ -0,0 +1,345 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// DistributionKind names the shape of an amount distribution.
+type DistributionKind string
+
+const (
+	// DistributionNormal draws around Mean with StdDev
+	DistributionNormal DistributionKind = "normal"
+
+	// DistributionUniform draws evenly in [Min, Max]
+	DistributionUniform DistributionKind = "uniform"
+
+	// DistributionExponential draws with the given Mean
+	DistributionExponential DistributionKind = "exponential"
+
+	// DistributionPowerLaw draws in [Min, Max] with exponent Alpha
+	DistributionPowerLaw DistributionKind = "power_law"
+
+	// DistributionLogNormal draws around Median with shape Sigma: card
+	// payments and retail baskets
+	DistributionLogNormal DistributionKind = "lognormal"
+
+	// DistributionPareto draws from Min with tail index Alpha, capped at Max
+	// when set: a long tail of rare large payments
+	DistributionPareto DistributionKind = "pareto"
+
+	// DistributionBimodal mixes two normal modes: Mean/StdDev with probability
+	// Weight and Mean2/StdDev2 otherwise
+	DistributionBimodal DistributionKind = "bimodal"
+
+	// DistributionHistogram draws a bucket by weight, then uniformly within it
+	DistributionHistogram DistributionKind = "histogram"
+)
+
+// HistogramBucket is a range of amounts in major units and its relative weight.
+type HistogramBucket struct {
+	Min    float64
+	Max    float64
+	Weight float64
+}
+
+// AmountDistribution describes how to draw transaction amounts. Amounts are
+// expressed in major units; only the fields of the selected Kind are used.
+type AmountDistribution struct {
+	Kind DistributionKind
+
+	Mean   float64
+	StdDev float64
+
+	Min float64
+	Max float64
+
+	Alpha float64
+
+	Median float64
+	Sigma  float64
+
+	// Mean2 and StdDev2 describe the second mode of a bimodal distribution
+	Mean2   float64
+	StdDev2 float64
+
+	// Weight is the probability of the first mode of a bimodal distribution
+	Weight float64
+
+	Buckets []HistogramBucket
+
+	// Currency rounds amounts to its ISO 4217 minor units when the asset
+	// scale is finer (JPY amounts on a scale of 2 are whole yen)
+	Currency string
+
+	// Increment rounds amounts to a multiple of this many minor units of the
+	// currency (of the asset scale without Currency), e.g. 5 for Swiss cash or
+	// 100 for whole units
+	Increment int64
+}
+
+// amountScenarios holds the built-in distributions of common payment flows.
+var amountScenarios = map[string]AmountDistribution{
+	"retail":    {Kind: DistributionLogNormal, Median: 25, Sigma: 0.9},
+	"ecommerce": {Kind: DistributionPareto, Min: 5, Alpha: 1.6, Max: 5000},
+	"payroll": {
+		Kind: DistributionBimodal, Mean: 2500, StdDev: 400, Mean2: 6000, StdDev2: 1200, Weight: 0.8,
+		Increment: 100,
+	},
+	"p2p": {Kind: DistributionHistogram, Buckets: []HistogramBucket{
+		{Min: 5, Max: 20, Weight: 0.45},
+		{Min: 20, Max: 100, Weight: 0.35},
+		{Min: 100, Max: 500, Weight: 0.15},
+		{Min: 500, Max: 2000, Weight: 0.05},
+	}},
+	"subscription": {Kind: DistributionHistogram, Buckets: []HistogramBucket{
+		{Min: 4.99, Max: 4.99, Weight: 0.3},
+		{Min: 9.99, Max: 9.99, Weight: 0.45},
+		{Min: 19.99, Max: 19.99, Weight: 0.2},
+		{Min: 49.99, Max: 49.99, Weight: 0.05},
+	}},
+}
+
+// AmountScenario returns the built-in distribution of a payment flow by name
+// (see AmountScenarios) and whether it exists.
+func AmountScenario(name string) (AmountDistribution, bool) {
+	d, ok := amountScenarios[strings.ToLower(strings.TrimSpace(name))]
+	return d, ok
+}
+
+// AmountScenarios lists the names of the built-in distributions.
+func AmountScenarios() []string {
+	names := make([]string, 0, len(amountScenarios))
+	for name := range amountScenarios {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Validate checks that the distribution has the parameters of its kind.
+func (d AmountDistribution) Validate() error {
+	if d.Increment < 0 {
+		return errors.New("rounding increment cannot be negative")
+	}
+
+	switch d.Kind {
+	case DistributionNormal, DistributionExponential:
+		if d.Mean <= 0 {
+			return fmt.Errorf("%s distribution needs a positive mean", d.Kind)
+		}
+	case DistributionUniform, DistributionPowerLaw:
+		if d.Min < 0 || d.Max <= d.Min {
+			return fmt.Errorf("%s distribution needs 0 <= min < max", d.Kind)
+		}
+	case DistributionLogNormal:
+		if d.Median <= 0 || d.Sigma <= 0 {
+			return errors.New("lognormal distribution needs a positive median and sigma")
+		}
+	case DistributionPareto:
+		if d.Min <= 0 || d.Alpha <= 0 {
+			return errors.New("pareto distribution needs a positive min and alpha")
+		}
+
+		if d.Max != 0 && d.Max <= d.Min {
+			return errors.New("pareto max must be greater than min")
+		}
+	case DistributionBimodal:
+		if d.Mean <= 0 || d.Mean2 <= 0 {
+			return errors.New("bimodal distribution needs two positive means")
+		}
+
+		if d.Weight < 0 || d.Weight > 1 {
+			return errors.New("bimodal weight must be in [0, 1]")
+		}
+	case DistributionHistogram:
+		return validateBuckets(d.Buckets)
+	default:
+		return fmt.Errorf("unknown distribution %q", d.Kind)
+	}
+
+	return nil
+}
+
+// validateBuckets checks the ranges and weights of histogram buckets.
+func validateBuckets(buckets []HistogramBucket) error {
+	if len(buckets) == 0 {
+		return errors.New("histogram distribution needs at least one bucket")
+	}
+
+	total := 0.0
+
+	for i, b := range buckets {
+		if b.Min < 0 || b.Max < b.Min {
+			return fmt.Errorf("histogram bucket %d needs 0 <= min <= max", i)
+		}
+
+		if b.Weight < 0 {
+			return fmt.Errorf("histogram bucket %d has a negative weight", i)
+		}
+
+		total += b.Weight
+	}
+
+	if total <= 0 {
+		return errors.New("histogram weights must add up to a positive total")
+	}
+
+	return nil
+}
+
+// Sample draws an amount from d in minor units of the given scale, rounded to
+// the currency and increment of d. Invalid distributions fall back to the
+// defaults of the underlying generator methods; use Validate to reject them.
+//
+// Example:
+//
+//	retail, _ := data.AmountScenario("retail")
+//	retail.Currency = "USD"
+//	minor := gen.Sample(retail, 2)
+func (g *AmountGenerator) Sample(d AmountDistribution, scale int) int64 {
+	var minor int64
+
+	switch d.Kind {
+	case DistributionUniform:
+		minor = g.Uniform(d.Min, d.Max, scale)
+	case DistributionExponential:
+		minor = g.Exponential(d.Mean, scale)
+	case DistributionPowerLaw:
+		minor = g.PowerLaw(d.Min, d.Max, d.Alpha, scale)
+	case DistributionLogNormal:
+		minor = g.LogNormal(d.Median, d.Sigma, scale)
+	case DistributionPareto:
+		minor = g.Pareto(d.Min, d.Alpha, scale)
+		if d.Max > 0 {
+			minor = min(minor, g.toMinor(d.Max, scale))
+		}
+	case DistributionBimodal:
+		minor = g.Bimodal(d.Mean, d.StdDev, d.Mean2, d.StdDev2, d.Weight, scale)
+	case DistributionHistogram:
+		minor = g.Histogram(d.Buckets, scale)
+	default:
+		minor = g.Normal(d.Mean, d.StdDev, scale)
+	}
+
+	return RoundAmount(minor, CurrencyIncrement(d.Currency, scale)*max(d.Increment, 1))
+}
+
+// LogNormal generates amounts whose logarithm is normal, centered at median
+// with shape sigma: most amounts are close to the median with a moderate tail
+// of larger ones. Returns minor units based on the provided scale.
+func (g *AmountGenerator) LogNormal(median, sigma float64, scale int) int64 {
+	if median <= 0 {
+		median = 1
+	}
+
+	if sigma <= 0 {
+		sigma = 1
+	}
+
+	val := median * math.Exp(g.r.NormFloat64()*sigma)
+
+	return g.toMinor(val, scale)
+}
+
+// Pareto generates amounts of at least minVal with tail index alpha; smaller
+// alphas give heavier tails. Returns minor units based on the provided scale.
+func (g *AmountGenerator) Pareto(minVal, alpha float64, scale int) int64 {
+	if minVal <= 0 {
+		minVal = 1
+	}
+
+	if alpha <= 0 {
+		alpha = 1.16 // the 80/20 rule
+	}
+
+	u := 1 - g.r.Float64() // (0,1]
+	val := minVal / math.Pow(u, 1/alpha)
+
+	return g.toMinor(min(val, math.MaxInt64/math.Pow10(scale)), scale)
+}
+
+// Bimodal generates amounts from a mix of two normal modes, the first with
+// probability weight1, e.g. regular salaries and executive pay. Returns minor
+// units based on the provided scale.
+func (g *AmountGenerator) Bimodal(mean1, stddev1, mean2, stddev2, weight1 float64, scale int) int64 {
+	if g.r.Float64() < weight1 {
+		return g.Normal(mean1, stddev1, scale)
+	}
+
+	return g.Normal(mean2, stddev2, scale)
+}
+
+// Histogram generates amounts following an empirical histogram: a bucket is
+// drawn by weight, then an amount uniformly within it. Returns minor units
+// based on the provided scale, or zero without buckets of positive weight.
+func (g *AmountGenerator) Histogram(buckets []HistogramBucket, scale int) int64 {
+	total := 0.0
+	for _, b := range buckets {
+		total += max(b.Weight, 0)
+	}
+
+	if total <= 0 {
+		return 0
+	}
+
+	pick := g.r.Float64() * total
+
+	var chosen HistogramBucket
+
+	for _, b := range buckets {
+		if b.Weight <= 0 {
+			continue
+		}
+
+		// floating point leftovers fall into the last weighted bucket
+		chosen = b
+
+		if pick < b.Weight {
+			break
+		}
+
+		pick -= b.Weight
+	}
+
+	val := chosen.Min + g.r.Float64()*(chosen.Max-chosen.Min)
+
+	return g.toMinor(val, scale)
+}
+
+// CurrencyIncrement returns the smallest meaningful step of a currency in minor
+// units of the given scale: 100 for JPY on a scale of 2, 1 when the currency
+// is unknown or the scale is not finer than its minor units.
+func CurrencyIncrement(currency string, scale int) int64 {
+	units, ok := CurrencyMinorUnits(currency)
+	if !ok || scale <= units {
+		return 1
+	}
+
+	return int64(math.Pow10(scale - units))
+}
+
+// RoundAmount rounds minor to the nearest multiple of increment, halves
+// rounding up. Increments below 2 leave the amount unchanged.
+func RoundAmount(minor, increment int64) int64 {
+	if increment < 2 {
+		return minor
+	}
+
+	rem := minor % increment
+	if rem < 0 {
+		rem += increment
+	}
+
+	if rem*2 >= increment {
+		return minor - rem + increment
+	}
+
+	return minor - rem
+}
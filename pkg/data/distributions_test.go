@@ -0,0 +1,210 @@
+package data
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmountGeneratorLogNormal(t *testing.T) {
+	gen := NewAmountGenerator(12345)
+
+	samples := make([]int64, 5000)
+	for i := range samples {
+		samples[i] = gen.LogNormal(25, 0.9, 2)
+		require.Positive(t, samples[i])
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	median := float64(samples[len(samples)/2])
+	assert.InDelta(t, 2500, median, 250, "median is preserved")
+
+	mean := 0.0
+	for _, s := range samples {
+		mean += float64(s)
+	}
+
+	mean /= float64(len(samples))
+	assert.Greater(t, mean, median, "the tail is on the right")
+}
+
+func TestAmountGeneratorPareto(t *testing.T) {
+	gen := NewAmountGenerator(12345)
+
+	above := 0
+
+	for i := 0; i < 5000; i++ {
+		val := gen.Pareto(10, 1.16, 2)
+		require.GreaterOrEqual(t, val, int64(1000), "never below the minimum")
+
+		if val > 10000 {
+			above++
+		}
+	}
+
+	// P(X > 10*xm) = 10^-alpha, about 7% for alpha 1.16
+	assert.InDelta(t, 0.069, float64(above)/5000, 0.02)
+}
+
+func TestAmountGeneratorBimodal(t *testing.T) {
+	gen := NewAmountGenerator(12345)
+
+	low, high := 0, 0
+
+	for i := 0; i < 2000; i++ {
+		val := gen.Bimodal(10, 1, 100, 5, 0.75, 0)
+		switch {
+		case val < 30:
+			low++
+		case val > 70:
+			high++
+		default:
+			t.Fatalf("value %d is between the modes", val)
+		}
+	}
+
+	assert.InDelta(t, 0.75, float64(low)/2000, 0.05)
+	assert.Equal(t, 2000, low+high)
+}
+
+func TestAmountGeneratorHistogram(t *testing.T) {
+	gen := NewAmountGenerator(12345)
+	buckets := []HistogramBucket{
+		{Min: 1, Max: 2, Weight: 3},
+		{Min: 50, Max: 60, Weight: 0},
+		{Min: 100, Max: 200, Weight: 1},
+	}
+
+	small := 0
+
+	for i := 0; i < 4000; i++ {
+		val := gen.Histogram(buckets, 2)
+
+		switch {
+		case val >= 100 && val <= 200:
+			small++
+		case val >= 10000 && val <= 20000:
+		default:
+			t.Fatalf("value %d is outside the weighted buckets", val)
+		}
+	}
+
+	assert.InDelta(t, 0.75, float64(small)/4000, 0.03)
+	assert.Zero(t, gen.Histogram(nil, 2))
+	assert.Equal(t, int64(999), gen.Histogram([]HistogramBucket{{Min: 9.99, Max: 9.99, Weight: 1}}, 2))
+}
+
+func TestRoundAmount(t *testing.T) {
+	tests := []struct {
+		minor, increment, want int64
+	}{
+		{1234, 1, 1234},
+		{1234, 0, 1234},
+		{1234, 5, 1235},
+		{1232, 5, 1230},
+		{1250, 100, 1300},
+		{1249, 100, 1200},
+		{-3, 5, -5},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, RoundAmount(tt.minor, tt.increment), "%d by %d", tt.minor, tt.increment)
+	}
+}
+
+func TestCurrencyIncrement(t *testing.T) {
+	assert.Equal(t, int64(1), CurrencyIncrement("USD", 2))
+	assert.Equal(t, int64(100), CurrencyIncrement("jpy", 2))
+	assert.Equal(t, int64(1000), CurrencyIncrement("USD", 5))
+	assert.Equal(t, int64(1), CurrencyIncrement("KWD", 2), "coarser scales are left alone")
+	assert.Equal(t, int64(1), CurrencyIncrement("XYZ", 8))
+}
+
+func TestAmountGeneratorSample(t *testing.T) {
+	t.Run("currency rounding", func(t *testing.T) {
+		gen := NewAmountGenerator(7)
+		d := AmountDistribution{Kind: DistributionLogNormal, Median: 1500, Sigma: 0.5, Currency: "JPY"}
+
+		for i := 0; i < 100; i++ {
+			assert.Zero(t, gen.Sample(d, 2)%100, "whole yen")
+		}
+	})
+
+	t.Run("increment on top of the currency", func(t *testing.T) {
+		gen := NewAmountGenerator(7)
+		d := AmountDistribution{Kind: DistributionUniform, Min: 1, Max: 100, Currency: "CHF", Increment: 5}
+
+		for i := 0; i < 100; i++ {
+			assert.Zero(t, gen.Sample(d, 4)%500, "five rappen on a scale of 4")
+		}
+	})
+
+	t.Run("pareto max caps the tail", func(t *testing.T) {
+		gen := NewAmountGenerator(7)
+		d := AmountDistribution{Kind: DistributionPareto, Min: 1, Alpha: 0.5, Max: 20}
+
+		for i := 0; i < 500; i++ {
+			assert.LessOrEqual(t, gen.Sample(d, 2), int64(2000))
+		}
+	})
+
+	t.Run("same seed same amounts", func(t *testing.T) {
+		d, ok := AmountScenario("p2p")
+		require.True(t, ok)
+
+		gen1, gen2 := NewAmountGenerator(99), NewAmountGenerator(99)
+		for i := 0; i < 20; i++ {
+			assert.Equal(t, gen1.Sample(d, 2), gen2.Sample(d, 2))
+		}
+	})
+}
+
+func TestAmountScenarios(t *testing.T) {
+	names := AmountScenarios()
+	require.NotEmpty(t, names)
+	assert.True(t, sort.StringsAreSorted(names))
+
+	gen := NewAmountGenerator(1)
+
+	for _, name := range names {
+		d, ok := AmountScenario(name)
+		require.True(t, ok, name)
+		require.NoError(t, d.Validate(), name)
+		assert.Positive(t, gen.Sample(d, 2), name)
+	}
+
+	payroll, _ := AmountScenario(" Payroll ")
+	assert.Zero(t, NewAmountGenerator(3).Sample(payroll, 2)%100, "payroll is in whole units")
+
+	_, ok := AmountScenario("unknown")
+	assert.False(t, ok)
+}
+
+func TestAmountDistributionValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		d    AmountDistribution
+	}{
+		{"unknown kind", AmountDistribution{Kind: "zipf"}},
+		{"normal without mean", AmountDistribution{Kind: DistributionNormal}},
+		{"uniform inverted", AmountDistribution{Kind: DistributionUniform, Min: 10, Max: 1}},
+		{"lognormal without sigma", AmountDistribution{Kind: DistributionLogNormal, Median: 10}},
+		{"pareto max below min", AmountDistribution{Kind: DistributionPareto, Min: 10, Alpha: 1, Max: 5}},
+		{"bimodal weight", AmountDistribution{Kind: DistributionBimodal, Mean: 1, Mean2: 2, Weight: 1.5}},
+		{"histogram without buckets", AmountDistribution{Kind: DistributionHistogram}},
+		{"histogram zero weights", AmountDistribution{Kind: DistributionHistogram, Buckets: []HistogramBucket{{Min: 1, Max: 2}}}},
+		{"negative increment", AmountDistribution{Kind: DistributionNormal, Mean: 1, Increment: -5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, tt.d.Validate())
+		})
+	}
+
+	assert.NoError(t, AmountDistribution{Kind: DistributionNormal, Mean: 25, StdDev: math.SmallestNonzeroFloat64}.Validate())
+}
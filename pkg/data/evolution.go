@@ -0,0 +1,354 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// EvolutionEventKind is the kind of a simulated event of an evolution plan.
+type EvolutionEventKind string
+
+const (
+	// EvolutionOnboard creates a new customer account and funds it with Amount
+	EvolutionOnboard EvolutionEventKind = "onboard"
+
+	// EvolutionPayment moves Amount from Customer to Counterparty
+	EvolutionPayment EvolutionEventKind = "payment"
+
+	// EvolutionChurn closes Customer; Amount is the known balance to cash out,
+	// zero when the balance is unknown or empty
+	EvolutionChurn EvolutionEventKind = "churn"
+)
+
+// EvolutionConfig configures an evolution plan: days of simulated activity on
+// an existing demo ledger. Zero values fall back to the defaults documented on
+// each field.
+type EvolutionConfig struct {
+	// Days is the number of simulated days (required)
+	Days int
+
+	// Start is the date of the first simulated day (default today, UTC)
+	Start time.Time
+
+	// Customers lists the aliases of the existing customer accounts
+	Customers []string
+
+	// Merchants lists the aliases receiving customer payments; without
+	// merchants customers pay each other
+	Merchants []string
+
+	// Balances holds the known balance of existing customers in minor units.
+	// Customers with a known balance never pay more than they hold; the
+	// others are not constrained.
+	Balances map[string]int64
+
+	// NewCustomersPerDay is the average number of customers onboarded per day
+	NewCustomersPerDay float64
+
+	// ChurnRate is the daily probability of each customer leaving, in [0, 1)
+	ChurnRate float64
+
+	// PaymentsPerCustomer is the average number of daily payments of each
+	// active customer on an ordinary day (default 1)
+	PaymentsPerCustomer float64
+
+	// MinAmount and MaxAmount bound payment amounts in major units (defaults 1 and 50)
+	MinAmount float64
+	MaxAmount float64
+
+	// MinFunding and MaxFunding bound the funding of new customers in major
+	// units (defaults 100 and 1000)
+	MinFunding float64
+	MaxFunding float64
+
+	// Seasonality scales the payment volume of a day (default DefaultSeasonality)
+	Seasonality func(day time.Time) float64
+
+	// CustomerPrefix prefixes the aliases of onboarded customers; use a
+	// different prefix on every run against the same ledger (default "evo_customer_")
+	CustomerPrefix string
+
+	// Seed makes generation reproducible; zero uses the current time
+	Seed int64
+}
+
+// EvolutionEvent is a single simulated event of an evolution plan.
+type EvolutionEvent struct {
+	Kind     EvolutionEventKind
+	Customer string
+
+	// Counterparty is the alias receiving a payment
+	Counterparty string
+
+	// Amount is expressed in minor units of the asset
+	Amount int64
+}
+
+// EvolutionDay holds the events of one simulated day, onboardings first,
+// then payments, then churn.
+type EvolutionDay struct {
+	// Day is the index of the day, starting at 0
+	Day  int
+	Date time.Time
+
+	// Seasonality is the volume multiplier applied to the day
+	Seasonality float64
+
+	// ActiveCustomers is the number of customers at the end of the day
+	ActiveCustomers int
+
+	Events []EvolutionEvent
+}
+
+// DefaultSeasonality models a retail week and year: quieter weekends, busier
+// Fridays and paydays (1st and 15th), and a December peak.
+func DefaultSeasonality(day time.Time) float64 {
+	factor := 1.0
+
+	switch day.Weekday() {
+	case time.Saturday, time.Sunday:
+		factor *= 0.6
+	case time.Friday:
+		factor *= 1.2
+	}
+
+	if d := day.Day(); d == 1 || d == 15 {
+		factor *= 1.5
+	}
+
+	if day.Month() == time.December {
+		factor *= 1.4
+	}
+
+	return factor
+}
+
+// Validate checks that the configuration is complete and consistent.
+func (c EvolutionConfig) Validate() error {
+	if c.Days <= 0 {
+		return errors.New("evolution days must be positive")
+	}
+
+	if len(c.Customers) == 0 && c.NewCustomersPerDay <= 0 {
+		return errors.New("evolution needs existing customers or new customers per day")
+	}
+
+	if c.NewCustomersPerDay < 0 || c.PaymentsPerCustomer < 0 {
+		return errors.New("evolution rates cannot be negative")
+	}
+
+	if c.ChurnRate < 0 || c.ChurnRate >= 1 {
+		return errors.New("churn rate must be in [0, 1)")
+	}
+
+	if c.MinAmount < 0 || c.MaxAmount < 0 || c.MinFunding < 0 || c.MaxFunding < 0 {
+		return errors.New("evolution amounts cannot be negative")
+	}
+
+	return nil
+}
+
+// BuildEvolutionPlan simulates cfg.Days days of activity: customers joining,
+// paying merchants or each other with a seasonal volume, and leaving. amounts
+// drives payment and funding amounts and scale is the asset scale used to
+// convert them to minor units.
+//
+// Example:
+//
+//	plan, err := data.BuildEvolutionPlan(data.EvolutionConfig{
+//	    Days:               30,
+//	    Customers:          customerAliases,
+//	    Merchants:          merchantAliases,
+//	    NewCustomersPerDay: 3,
+//	    ChurnRate:          0.01,
+//	    Seed:               42,
+//	}, data.NewAmountGenerator(42), 2)
+func BuildEvolutionPlan(cfg EvolutionConfig, amounts *AmountGenerator, scale int) ([]EvolutionDay, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if amounts == nil {
+		amounts = NewAmountGenerator(cfg.Seed)
+	}
+
+	cfg = evolutionDefaults(cfg)
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	// #nosec G404 - non-cryptographic PRNG is intentional for reproducible demo data
+	r := rand.New(rand.NewSource(seed))
+
+	sim := &evolutionSim{
+		cfg:      cfg,
+		r:        r,
+		amounts:  amounts,
+		scale:    scale,
+		balances: make(map[string]int64),
+	}
+
+	for _, alias := range cfg.Customers {
+		sim.active = append(sim.active, alias)
+
+		if balance, ok := cfg.Balances[alias]; ok {
+			sim.balances[alias] = balance
+		}
+	}
+
+	plan := make([]EvolutionDay, 0, cfg.Days)
+
+	for day := 0; day < cfg.Days; day++ {
+		plan = append(plan, sim.simulateDay(day))
+	}
+
+	return plan, nil
+}
+
+// evolutionDefaults fills the zero fields of cfg with their defaults.
+func evolutionDefaults(cfg EvolutionConfig) EvolutionConfig {
+	if cfg.Start.IsZero() {
+		cfg.Start = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+
+	if cfg.PaymentsPerCustomer == 0 {
+		cfg.PaymentsPerCustomer = 1
+	}
+
+	if cfg.MinAmount == 0 && cfg.MaxAmount == 0 {
+		cfg.MinAmount, cfg.MaxAmount = 1, 50
+	}
+
+	if cfg.MinFunding == 0 && cfg.MaxFunding == 0 {
+		cfg.MinFunding, cfg.MaxFunding = 100, 1000
+	}
+
+	if cfg.Seasonality == nil {
+		cfg.Seasonality = DefaultSeasonality
+	}
+
+	if cfg.CustomerPrefix == "" {
+		cfg.CustomerPrefix = "evo_customer_"
+	}
+
+	return cfg
+}
+
+// evolutionSim holds the state of the simulated ledger while building a plan.
+type evolutionSim struct {
+	cfg     EvolutionConfig
+	r       *rand.Rand
+	amounts *AmountGenerator
+	scale   int
+
+	// active lists the customers that haven't churned, in onboarding order
+	active []string
+
+	// balances tracks the known customer balances in minor units
+	balances map[string]int64
+
+	onboarded int
+}
+
+// simulateDay generates the events of one day and applies them to the state.
+func (s *evolutionSim) simulateDay(day int) EvolutionDay {
+	date := s.cfg.Start.AddDate(0, 0, day)
+	d := EvolutionDay{Day: day, Date: date, Seasonality: s.cfg.Seasonality(date)}
+
+	for i := poisson(s.r, s.cfg.NewCustomersPerDay); i > 0; i-- {
+		s.onboarded++
+
+		alias := fmt.Sprintf("%s%d", s.cfg.CustomerPrefix, s.onboarded)
+		funding := max(s.amounts.Uniform(s.cfg.MinFunding, s.cfg.MaxFunding, s.scale), 1)
+
+		s.active = append(s.active, alias)
+		s.balances[alias] = funding
+
+		d.Events = append(d.Events, EvolutionEvent{Kind: EvolutionOnboard, Customer: alias, Amount: funding})
+	}
+
+	for _, customer := range s.active {
+		for i := poisson(s.r, s.cfg.PaymentsPerCustomer*d.Seasonality); i > 0; i-- {
+			if event, ok := s.payment(customer); ok {
+				d.Events = append(d.Events, event)
+			}
+		}
+	}
+
+	remaining := s.active[:0]
+
+	for _, customer := range s.active {
+		if s.r.Float64() >= s.cfg.ChurnRate {
+			remaining = append(remaining, customer)
+			continue
+		}
+
+		d.Events = append(d.Events, EvolutionEvent{Kind: EvolutionChurn, Customer: customer, Amount: max(s.balances[customer], 0)})
+		delete(s.balances, customer)
+	}
+
+	s.active = remaining
+	d.ActiveCustomers = len(s.active)
+
+	return d
+}
+
+// payment draws a payment of customer, capped at its known balance. It reports
+// false when the customer can't pay anyone.
+func (s *evolutionSim) payment(customer string) (EvolutionEvent, bool) {
+	var counterparty string
+
+	switch {
+	case len(s.cfg.Merchants) > 0:
+		counterparty = s.cfg.Merchants[s.r.Intn(len(s.cfg.Merchants))]
+	case len(s.active) > 1:
+		for counterparty == "" || counterparty == customer {
+			counterparty = s.active[s.r.Intn(len(s.active))]
+		}
+	default:
+		return EvolutionEvent{}, false
+	}
+
+	amount := max(s.amounts.Uniform(s.cfg.MinAmount, s.cfg.MaxAmount, s.scale), 1)
+
+	if balance, known := s.balances[customer]; known {
+		amount = min(amount, balance)
+		if amount <= 0 {
+			return EvolutionEvent{}, false
+		}
+
+		s.balances[customer] -= amount
+	}
+
+	if _, known := s.balances[counterparty]; known {
+		s.balances[counterparty] += amount
+	}
+
+	return EvolutionEvent{Kind: EvolutionPayment, Customer: customer, Counterparty: counterparty, Amount: amount}, true
+}
+
+// poisson draws a Poisson distributed count with the given mean.
+func poisson(r *rand.Rand, mean float64) int {
+	if mean <= 0 {
+		return 0
+	}
+
+	// Knuth's algorithm is fine for the small daily rates of a demo; larger
+	// means use the normal approximation
+	if mean > 100 {
+		return max(int(math.Round(mean+r.NormFloat64()*math.Sqrt(mean))), 0)
+	}
+
+	limit := math.Exp(-mean)
+	count := 0
+
+	for p := r.Float64(); p > limit; p *= r.Float64() {
+		count++
+	}
+
+	return count
+}
@@ -0,0 +1,142 @@
+package data
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvolutionConfigValidate(t *testing.T) {
+	valid := EvolutionConfig{Days: 7, Customers: []string{"@a"}}
+	require.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name   string
+		mutate func(c *EvolutionConfig)
+	}{
+		{"no days", func(c *EvolutionConfig) { c.Days = 0 }},
+		{"no customers", func(c *EvolutionConfig) { c.Customers = nil }},
+		{"negative growth", func(c *EvolutionConfig) { c.NewCustomersPerDay = -1 }},
+		{"churn of one", func(c *EvolutionConfig) { c.ChurnRate = 1 }},
+		{"negative amount", func(c *EvolutionConfig) { c.MaxAmount = -1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid
+			tt.mutate(&c)
+			assert.Error(t, c.Validate())
+		})
+	}
+}
+
+func TestBuildEvolutionPlan(t *testing.T) {
+	start := time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC)
+	cfg := EvolutionConfig{
+		Days:               10,
+		Start:              start,
+		Customers:          []string{"@alice", "@bob", "@carol"},
+		Merchants:          []string{"@shop"},
+		Balances:           map[string]int64{"@alice": 500},
+		NewCustomersPerDay: 2,
+		ChurnRate:          0.05,
+		Seed:               7,
+	}
+
+	plan, err := BuildEvolutionPlan(cfg, NewAmountGenerator(7), 2)
+	require.NoError(t, err)
+	require.Len(t, plan, 10)
+
+	active := map[string]bool{"@alice": true, "@bob": true, "@carol": true}
+	balances := map[string]int64{"@alice": 500}
+	onboarded := 0
+
+	for i, day := range plan {
+		assert.Equal(t, i, day.Day)
+		assert.Equal(t, start.AddDate(0, 0, i), day.Date)
+		assert.Equal(t, DefaultSeasonality(day.Date), day.Seasonality)
+
+		phase := 0
+
+		for _, ev := range day.Events {
+			switch ev.Kind {
+			case EvolutionOnboard:
+				assert.Equal(t, 0, phase, "onboardings come first")
+				assert.True(t, strings.HasPrefix(ev.Customer, "evo_customer_"))
+				assert.False(t, active[ev.Customer])
+				assert.Positive(t, ev.Amount)
+
+				active[ev.Customer] = true
+				balances[ev.Customer] = ev.Amount
+				onboarded++
+			case EvolutionPayment:
+				assert.LessOrEqual(t, phase, 1, "payments come before churn")
+				phase = 1
+
+				assert.True(t, active[ev.Customer], "only active customers pay")
+				assert.Equal(t, "@shop", ev.Counterparty)
+				assert.Positive(t, ev.Amount)
+
+				if balance, known := balances[ev.Customer]; known {
+					assert.LessOrEqual(t, ev.Amount, balance, "known balances are never overdrawn")
+					balances[ev.Customer] -= ev.Amount
+				}
+			case EvolutionChurn:
+				phase = 2
+
+				assert.True(t, active[ev.Customer])
+				assert.Equal(t, balances[ev.Customer], ev.Amount, "churn cashes out the known balance")
+
+				delete(active, ev.Customer)
+			}
+		}
+
+		assert.Equal(t, len(active), day.ActiveCustomers)
+	}
+
+	assert.Positive(t, onboarded)
+
+	again, err := BuildEvolutionPlan(cfg, NewAmountGenerator(7), 2)
+	require.NoError(t, err)
+	assert.Equal(t, plan, again, "the same seed gives the same plan")
+}
+
+func TestBuildEvolutionPlan_Seasonality(t *testing.T) {
+	customers := make([]string, 50)
+	for i := range customers {
+		customers[i] = "@c" + string(rune('a'+i%26)) + string(rune('a'+i/26))
+	}
+
+	plan, err := BuildEvolutionPlan(EvolutionConfig{
+		Days:      2,
+		Start:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Customers: customers,
+		Seasonality: func(day time.Time) float64 {
+			if day.Day() == 1 {
+				return 0
+			}
+
+			return 3
+		},
+		Seed: 1,
+	}, nil, 2)
+	require.NoError(t, err)
+
+	assert.Empty(t, plan[0].Events, "a zero season has no payments")
+	assert.Greater(t, len(plan[1].Events), 100, "peer payments without merchants")
+
+	for _, ev := range plan[1].Events {
+		assert.NotEqual(t, ev.Customer, ev.Counterparty)
+	}
+}
+
+func TestDefaultSeasonality(t *testing.T) {
+	tuesday := time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC)
+	assert.InDelta(t, 1.0, DefaultSeasonality(tuesday), 1e-9)
+	assert.Less(t, DefaultSeasonality(tuesday.AddDate(0, 0, 4)), 1.0, "Saturday")
+	assert.Greater(t, DefaultSeasonality(time.Date(2025, 12, 9, 0, 0, 0, 0, time.UTC)), 1.0, "December")
+	assert.Greater(t, DefaultSeasonality(time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)), 1.0, "payday")
+}
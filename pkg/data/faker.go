@@ -0,0 +1,286 @@
+package data
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Faker locales. Unknown locales fall back to FakerLocaleUS.
+const (
+	FakerLocaleUS = "us"
+	FakerLocaleBR = "br"
+)
+
+// fakerNames holds the name parts of a locale.
+type fakerNames struct {
+	first    []string
+	last     []string
+	business []string
+	suffixes []string
+}
+
+var fakerLocales = map[string]fakerNames{
+	FakerLocaleUS: {
+		first: []string{
+			"James", "Mary", "Robert", "Patricia", "John", "Jennifer", "Michael", "Linda", "David", "Elizabeth",
+			"William", "Barbara", "Richard", "Susan", "Joseph", "Jessica", "Thomas", "Sarah", "Charles", "Karen",
+			"Christopher", "Lisa", "Daniel", "Nancy", "Matthew", "Betty", "Anthony", "Sandra", "Mark", "Ashley",
+			"Steven", "Emily", "Andrew", "Michelle", "Joshua", "Amanda", "Kevin", "Melissa", "Brian", "Rebecca",
+		},
+		last: []string{
+			"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez",
+			"Hernandez", "Lopez", "Gonzalez", "Wilson", "Anderson", "Thomas", "Taylor", "Moore", "Jackson", "Martin",
+			"Lee", "Perez", "Thompson", "White", "Harris", "Sanchez", "Clark", "Ramirez", "Lewis", "Robinson",
+			"Walker", "Young", "Allen", "King", "Wright", "Scott", "Torres", "Nguyen", "Hill", "Flores",
+		},
+		business: []string{
+			"Logistics", "Foods", "Systems", "Outfitters", "Builders", "Analytics", "Supply", "Health", "Media", "Motors",
+			"Partners", "Labs", "Hardware", "Trading", "Studios", "Consulting", "Farms", "Energy", "Travel", "Goods",
+		},
+		suffixes: []string{"Inc.", "LLC", "Corp.", "Co.", "Group", "Holdings"},
+	},
+	FakerLocaleBR: {
+		first: []string{
+			"Maria", "José", "Ana", "João", "Francisca", "Antônio", "Antônia", "Francisco", "Adriana", "Carlos",
+			"Juliana", "Paulo", "Márcia", "Pedro", "Fernanda", "Lucas", "Patrícia", "Luiz", "Aline", "Marcos",
+			"Sandra", "Luís", "Camila", "Gabriel", "Amanda", "Rafael", "Bruna", "Daniel", "Jéssica", "Marcelo",
+			"Letícia", "Bruno", "Júlia", "Eduardo", "Luciana", "Felipe", "Vanessa", "Raimundo", "Mariana", "Rodrigo",
+		},
+		last: []string{
+			"Silva", "Santos", "Oliveira", "Souza", "Rodrigues", "Ferreira", "Alves", "Pereira", "Lima", "Gomes",
+			"Costa", "Ribeiro", "Martins", "Carvalho", "Almeida", "Lopes", "Soares", "Fernandes", "Vieira", "Barbosa",
+			"Rocha", "Dias", "Nascimento", "Andrade", "Moreira", "Nunes", "Marques", "Machado", "Mendes", "Freitas",
+			"Cardoso", "Ramos", "Gonçalves", "Santana", "Teixeira", "Araújo", "Correia", "Pinto", "Moura", "Cavalcanti",
+		},
+		business: []string{
+			"Logística", "Alimentos", "Sistemas", "Comércio", "Construções", "Tecnologia", "Distribuidora", "Saúde", "Comunicação", "Veículos",
+			"Participações", "Serviços", "Ferragens", "Importadora", "Estúdio", "Consultoria", "Agropecuária", "Energia", "Turismo", "Confecções",
+		},
+		suffixes: []string{"Ltda.", "S.A.", "ME", "EIRELI", "Ltda. EPP"},
+	},
+}
+
+// emailDomains are reserved for documentation (RFC 2606) and never reach a real mailbox.
+var emailDomains = []string{"example.com", "example.net", "example.org"}
+
+// emailFolding strips the Portuguese diacritics from email local parts.
+var emailFolding = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ü", "u", "ç", "c",
+)
+
+// FakePerson is a generated individual with a tax document of its locale:
+// an SSN for "us" and a CPF for "br".
+type FakePerson struct {
+	Name     string
+	Email    string
+	Document string
+}
+
+// FakeCompany is a generated company with a tax document of its locale: an
+// EIN for "us" and a CNPJ for "br".
+type FakeCompany struct {
+	LegalName string
+	TradeName string
+	Email     string
+	Document  string
+}
+
+// Faker generates locale-aware names, emails and tax documents for demo data.
+// The same locale and seed give the same sequence. A Faker is not safe for
+// concurrent use.
+type Faker struct {
+	r      *rand.Rand
+	locale string
+	names  fakerNames
+}
+
+// NewFaker creates a faker for the given locale ("us" or "br"). Seed zero uses
+// the current time.
+//
+// Example:
+//
+//	f := data.NewFaker("br", 42)
+//	person := f.Person()   // e.g. "Ana Souza Lima", ana.lima@example.com, a valid CPF
+//	company := f.Company() // e.g. "Ribeiro Logística Ltda.", a valid CNPJ
+func NewFaker(locale string, seed int64) *Faker {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+
+	names, ok := fakerLocales[locale]
+	if !ok {
+		locale = FakerLocaleUS
+		names = fakerLocales[locale]
+	}
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	// #nosec G404 - non-cryptographic PRNG is intentional for reproducible demo data
+	return &Faker{r: rand.New(rand.NewSource(seed)), locale: locale, names: names}
+}
+
+// Locale returns the locale of the faker after fallback.
+func (f *Faker) Locale() string {
+	return f.locale
+}
+
+// PersonName returns a full name: first and last name for "us", first name and
+// two family names for "br".
+func (f *Faker) PersonName() string {
+	name := f.pick(f.names.first) + " " + f.pick(f.names.last)
+
+	if f.locale == FakerLocaleBR {
+		return name + " " + f.pick(f.names.last)
+	}
+
+	return name
+}
+
+// CompanyName returns a legal company name with a suffix of the locale.
+func (f *Faker) CompanyName() string {
+	owner := f.pick(f.names.last)
+	if f.r.Intn(4) == 0 {
+		owner += " & " + f.pick(f.names.last)
+	}
+
+	return fmt.Sprintf("%s %s %s", owner, f.pick(f.names.business), f.pick(f.names.suffixes))
+}
+
+// Email returns an address derived from name at a reserved example domain.
+func (f *Faker) Email(name string) string {
+	parts := strings.Fields(emailFolding.Replace(strings.ToLower(name)))
+
+	var local string
+
+	switch len(parts) {
+	case 0:
+		local = "user"
+	case 1:
+		local = parts[0]
+	default:
+		local = parts[0] + "." + parts[len(parts)-1]
+	}
+
+	local = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' {
+			return r
+		}
+
+		return -1
+	}, local)
+
+	return fmt.Sprintf("%s%d@%s", local, f.r.Intn(1000), f.pick(emailDomains))
+}
+
+// PersonDocument returns a formatted tax document of an individual: an SSN
+// (NNN-NN-NNNN) for "us" and a CPF with valid check digits for "br".
+func (f *Faker) PersonDocument() string {
+	if f.locale == FakerLocaleBR {
+		return f.cpf()
+	}
+
+	// areas 000, 666 and 900-999 are never assigned
+	area := 1 + f.r.Intn(898)
+	if area == 666 {
+		area = 667
+	}
+
+	return fmt.Sprintf("%03d-%02d-%04d", area, 1+f.r.Intn(99), 1+f.r.Intn(9999))
+}
+
+// CompanyDocument returns a formatted tax document of a company: an EIN
+// (NN-NNNNNNN) for "us" and a CNPJ with valid check digits for "br".
+func (f *Faker) CompanyDocument() string {
+	if f.locale == FakerLocaleBR {
+		return f.cnpj()
+	}
+
+	return fmt.Sprintf("%02d-%07d", 10+f.r.Intn(90), f.r.Intn(10_000_000))
+}
+
+// Person returns a generated individual.
+func (f *Faker) Person() FakePerson {
+	name := f.PersonName()
+
+	return FakePerson{Name: name, Email: f.Email(name), Document: f.PersonDocument()}
+}
+
+// Company returns a generated company. The trade name is the legal name
+// without its suffix.
+func (f *Faker) Company() FakeCompany {
+	legal := f.CompanyName()
+	trade := legal
+
+	for _, suffix := range f.names.suffixes {
+		if strings.HasSuffix(legal, " "+suffix) {
+			trade = strings.TrimSuffix(legal, " "+suffix)
+		}
+	}
+
+	return FakeCompany{LegalName: legal, TradeName: trade, Email: f.Email(trade), Document: f.CompanyDocument()}
+}
+
+func (f *Faker) pick(values []string) string {
+	return values[f.r.Intn(len(values))]
+}
+
+// cpf returns a CPF formatted as NNN.NNN.NNN-NN.
+func (f *Faker) cpf() string {
+	d := f.digits(9)
+	d = append(d, mod11CheckDigit(d, 10))
+	d = append(d, mod11CheckDigit(d, 11))
+
+	return fmt.Sprintf("%d%d%d.%d%d%d.%d%d%d-%d%d", d[0], d[1], d[2], d[3], d[4], d[5], d[6], d[7], d[8], d[9], d[10])
+}
+
+// cnpj returns a CNPJ of a head office (branch 0001) formatted as NN.NNN.NNN/NNNN-NN.
+func (f *Faker) cnpj() string {
+	d := append(f.digits(8), 0, 0, 0, 1)
+	d = append(d, mod11CheckDigit(d, 5))
+	d = append(d, mod11CheckDigit(d, 6))
+
+	return fmt.Sprintf("%d%d.%d%d%d.%d%d%d/%d%d%d%d-%d%d",
+		d[0], d[1], d[2], d[3], d[4], d[5], d[6], d[7], d[8], d[9], d[10], d[11], d[12], d[13])
+}
+
+// digits returns n random decimal digits that are not all equal, since
+// documents with repeated digits are rejected.
+func (f *Faker) digits(n int) []int {
+	for {
+		d := make([]int, n)
+		same := true
+
+		for i := range d {
+			d[i] = f.r.Intn(10)
+			same = same && d[i] == d[0]
+		}
+
+		if !same {
+			return d
+		}
+	}
+}
+
+// mod11CheckDigit computes the CPF/CNPJ check digit of values. Weights start
+// at first and decrease to 2, wrapping to 9 after 2 (CNPJ only).
+func mod11CheckDigit(values []int, first int) int {
+	sum := 0
+	weight := first
+
+	for _, v := range values {
+		sum += v * weight
+
+		weight--
+		if weight < 2 {
+			weight = 9
+		}
+	}
+
+	if mod := sum % 11; mod >= 2 {
+		return 11 - mod
+	}
+
+	return 0
+}
@@ -0,0 +1,83 @@
+package data
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fakeEmailPattern = regexp.MustCompile(`^[a-z0-9.]+@example\.(com|net|org)$`)
+
+func TestFaker_US(t *testing.T) {
+	f := NewFaker("US", 42)
+	require.Equal(t, FakerLocaleUS, f.Locale())
+
+	ssn := regexp.MustCompile(`^\d{3}-\d{2}-\d{4}$`)
+
+	for i := 0; i < 200; i++ {
+		p := f.Person()
+		assert.Len(t, strings.Fields(p.Name), 2, p.Name)
+		assert.Regexp(t, fakeEmailPattern, p.Email)
+		assert.Regexp(t, ssn, p.Document)
+		assert.False(t, strings.HasPrefix(p.Document, "000") || strings.HasPrefix(p.Document, "666") || p.Document[0] == '9', p.Document)
+
+		c := f.Company()
+		assert.NotEqual(t, c.LegalName, c.TradeName)
+		assert.True(t, strings.HasPrefix(c.LegalName, c.TradeName+" "), c.LegalName)
+		assert.Regexp(t, fakeEmailPattern, c.Email)
+		assert.NoError(t, validation.ValidateEIN(c.Document))
+	}
+}
+
+func TestFaker_BR(t *testing.T) {
+	f := NewFaker("br", 42)
+
+	for i := 0; i < 200; i++ {
+		p := f.Person()
+		assert.Len(t, strings.Fields(p.Name), 3, p.Name)
+		assert.Regexp(t, fakeEmailPattern, p.Email, "diacritics are folded")
+		assert.NoError(t, validation.ValidateCPF(p.Document))
+
+		c := f.Company()
+		assert.NoError(t, validation.ValidateCNPJ(c.Document))
+		assert.Contains(t, c.Document, "/0001-")
+	}
+}
+
+func TestFaker_Reproducible(t *testing.T) {
+	a, b := NewFaker("br", 7), NewFaker("br", 7)
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, a.Person(), b.Person())
+		assert.Equal(t, a.Company(), b.Company())
+	}
+}
+
+func TestFaker_Variety(t *testing.T) {
+	f := NewFaker("us", 1)
+	names := make(map[string]bool)
+
+	for i := 0; i < 1000; i++ {
+		names[f.PersonName()] = true
+	}
+
+	assert.Greater(t, len(names), 400, "a thousand people don't share a few names")
+}
+
+func TestFaker_UnknownLocale(t *testing.T) {
+	f := NewFaker("xx", 1)
+	assert.Equal(t, FakerLocaleUS, f.Locale())
+	assert.NoError(t, validation.ValidateEIN(f.CompanyDocument()))
+}
+
+func TestFaker_Email(t *testing.T) {
+	f := NewFaker("us", 1)
+
+	assert.True(t, strings.HasPrefix(f.Email("João Araújo Gonçalves"), "joao.goncalves"))
+	assert.True(t, strings.HasPrefix(f.Email("Smith & Jones Labs"), "smith.labs"))
+	assert.True(t, strings.HasPrefix(f.Email(""), "user"))
+}
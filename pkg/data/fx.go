@@ -0,0 +1,185 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/rounding"
+	"github.com/shopspring/decimal"
+)
+
+// ConversionAccountAlias returns the alias of the conversion account used to
+// move funds in and out of asset (e.g., "fx_conversion_usd").
+func ConversionAccountAlias(asset string) string {
+	return "fx_conversion_" + strings.ToLower(asset)
+}
+
+// CrossCurrencyParams describes a conversion of funds between two assets held by
+// the same owner. Midaz transactions move a single asset, so a conversion is made
+// of two legs that go through per-asset conversion accounts:
+//
+//	source (FromAsset) -> conversion account (FromAsset)
+//	conversion account (ToAsset) -> destination (ToAsset)
+type CrossCurrencyParams struct {
+	// ID correlates both legs; it prefixes their idempotency keys (required)
+	ID string
+
+	// SourceAlias is the account debited in FromAsset (required)
+	SourceAlias string
+
+	// DestinationAlias is the account credited in ToAsset (required)
+	DestinationAlias string
+
+	// FromAsset and ToAsset are the asset codes converted from and to (required)
+	FromAsset string
+	ToAsset   string
+
+	// Amount is the value converted, in FromAsset (required)
+	Amount string
+
+	// Rate is the number of ToAsset units received per FromAsset unit (required)
+	Rate string
+
+	// ToScale is the number of decimal places of ToAsset; the converted value is
+	// brought to it under Rounding
+	ToScale int
+
+	// Rounding handles a converted value with more decimal places than ToScale
+	// (default rounding.PolicyTruncate, rounding it down)
+	Rounding rounding.Policy
+
+	// SourceConversionAlias and DestinationConversionAlias override the conversion
+	// accounts (default ConversionAccountAlias of each asset)
+	SourceConversionAlias      string
+	DestinationConversionAlias string
+}
+
+// CrossCurrencyTransfer holds the two legs of a conversion.
+type CrossCurrencyTransfer struct {
+	// Sell moves FromAsset from the source to its conversion account
+	Sell *models.CreateTransactionInput
+
+	// Buy moves ToAsset from its conversion account to the destination
+	Buy *models.CreateTransactionInput
+
+	// ConvertedAmount is the value credited in ToAsset
+	ConvertedAmount string
+}
+
+// BuildCrossCurrencyTransfer builds both legs of a conversion. Legs share the
+// fx_id, fx_rate and fx_pair metadata so reconciliation can pair them.
+//
+// Example:
+//
+//	transfer, err := data.BuildCrossCurrencyTransfer(data.CrossCurrencyParams{
+//	    ID:               "fx-0001",
+//	    SourceAlias:      "customer_1_usd",
+//	    DestinationAlias: "customer_1_brl",
+//	    FromAsset:        "USD",
+//	    ToAsset:          "BRL",
+//	    Amount:           "100.00",
+//	    Rate:             "5.4321",
+//	    ToScale:          2,
+//	})
+func BuildCrossCurrencyTransfer(p CrossCurrencyParams) (*CrossCurrencyTransfer, error) {
+	if p.ID == "" || p.SourceAlias == "" || p.DestinationAlias == "" {
+		return nil, errors.New("conversion ID, source and destination aliases are required")
+	}
+
+	if p.FromAsset == "" || p.ToAsset == "" {
+		return nil, errors.New("source and destination assets are required")
+	}
+
+	if strings.EqualFold(p.FromAsset, p.ToAsset) {
+		return nil, fmt.Errorf("cannot convert %s to itself", p.FromAsset)
+	}
+
+	amount, err := decimal.NewFromString(p.Amount)
+	if err != nil || !amount.IsPositive() {
+		return nil, fmt.Errorf("invalid conversion amount: %q (must be a positive decimal)", p.Amount)
+	}
+
+	rate, err := decimal.NewFromString(p.Rate)
+	if err != nil || !rate.IsPositive() {
+		return nil, fmt.Errorf("invalid conversion rate: %q (must be a positive decimal)", p.Rate)
+	}
+
+	if p.ToScale < 0 {
+		return nil, fmt.Errorf("invalid scale for %s: %d", p.ToAsset, p.ToScale)
+	}
+
+	policy := p.Rounding
+	if policy == "" {
+		policy = rounding.PolicyTruncate
+	}
+
+	converted, err := policy.Apply(amount.Mul(rate), int32(p.ToScale))
+	if err != nil {
+		return nil, fmt.Errorf("converting %s to %s: %w", p.FromAsset, p.ToAsset, err)
+	}
+
+	if !converted.IsPositive() {
+		return nil, fmt.Errorf("converted amount rounds to zero at scale %d", p.ToScale)
+	}
+
+	sellConversion := p.SourceConversionAlias
+	if sellConversion == "" {
+		sellConversion = ConversionAccountAlias(p.FromAsset)
+	}
+
+	buyConversion := p.DestinationConversionAlias
+	if buyConversion == "" {
+		buyConversion = ConversionAccountAlias(p.ToAsset)
+	}
+
+	metadata := func(leg string) map[string]any {
+		return map[string]any{
+			"fx_id":   p.ID,
+			"fx_leg":  leg,
+			"fx_pair": p.FromAsset + "/" + p.ToAsset,
+			"fx_rate": rate.String(),
+		}
+	}
+
+	convertedStr := converted.StringFixed(int32(p.ToScale))
+
+	return &CrossCurrencyTransfer{
+		Sell: singleLegTransfer(p.ID+"-sell", p.FromAsset, amount.String(), p.SourceAlias, sellConversion,
+			fmt.Sprintf("FX %s sell %s %s", p.ID, amount.String(), p.FromAsset), metadata("sell")),
+		Buy: singleLegTransfer(p.ID+"-buy", p.ToAsset, convertedStr, buyConversion, p.DestinationAlias,
+			fmt.Sprintf("FX %s buy %s %s", p.ID, convertedStr, p.ToAsset), metadata("buy")),
+		ConvertedAmount: convertedStr,
+	}, nil
+}
+
+// ExternalFundingInput builds a transaction funding alias with value of asset
+// from the ledger's external account (@external/<asset>).
+func ExternalFundingInput(asset, value, alias, idempotencyKey string) *models.CreateTransactionInput {
+	return singleLegTransfer(idempotencyKey, asset, value, "@external/"+asset, alias,
+		fmt.Sprintf("Funding %s %s to %s", value, asset, alias), map[string]any{"funding": true})
+}
+
+// singleLegTransfer builds a one-source, one-destination transaction input.
+func singleLegTransfer(key, asset, value, source, destination, description string, metadata map[string]any) *models.CreateTransactionInput {
+	return &models.CreateTransactionInput{
+		Description:    description,
+		Amount:         value,
+		AssetCode:      asset,
+		IdempotencyKey: key,
+		Send: &models.SendInput{
+			Asset: asset,
+			Value: value,
+			Source: &models.SourceInput{From: []models.FromToInput{{
+				Account: source,
+				Amount:  models.AmountInput{Asset: asset, Value: value},
+			}}},
+			Distribute: &models.DistributeInput{To: []models.FromToInput{{
+				Account: destination,
+				Amount:  models.AmountInput{Asset: asset, Value: value},
+			}}},
+		},
+		Metadata: metadata,
+	}
+}
@@ -0,0 +1,125 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/rounding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCrossCurrencyTransfer(t *testing.T) {
+	transfer, err := BuildCrossCurrencyTransfer(CrossCurrencyParams{
+		ID:               "fx-1",
+		SourceAlias:      "customer_usd",
+		DestinationAlias: "customer_brl",
+		FromAsset:        "USD",
+		ToAsset:          "BRL",
+		Amount:           "100.00",
+		Rate:             "5.4321",
+		ToScale:          2,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "543.21", transfer.ConvertedAmount)
+
+	sell := transfer.Sell
+	require.NoError(t, sell.Validate())
+	assert.Equal(t, "USD", sell.AssetCode)
+	assert.Equal(t, "100", sell.Amount)
+	assert.Equal(t, "customer_usd", sell.Send.Source.From[0].Account)
+	assert.Equal(t, "fx_conversion_usd", sell.Send.Distribute.To[0].Account)
+	assert.Equal(t, "fx-1-sell", sell.IdempotencyKey)
+
+	buy := transfer.Buy
+	require.NoError(t, buy.Validate())
+	assert.Equal(t, "BRL", buy.AssetCode)
+	assert.Equal(t, "543.21", buy.Send.Value)
+	assert.Equal(t, "fx_conversion_brl", buy.Send.Source.From[0].Account)
+	assert.Equal(t, "customer_brl", buy.Send.Distribute.To[0].Account)
+
+	assert.Equal(t, sell.Metadata["fx_id"], buy.Metadata["fx_id"])
+	assert.Equal(t, "USD/BRL", buy.Metadata["fx_pair"])
+	assert.Equal(t, "5.4321", buy.Metadata["fx_rate"])
+}
+
+func TestBuildCrossCurrencyTransfer_TruncatesToScale(t *testing.T) {
+	transfer, err := BuildCrossCurrencyTransfer(CrossCurrencyParams{
+		ID: "fx-2", SourceAlias: "a_usd", DestinationAlias: "a_jpy",
+		FromAsset: "USD", ToAsset: "JPY", Amount: "1.99", Rate: "149.87", ToScale: 0,
+		DestinationConversionAlias: "desk_jpy",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "298", transfer.ConvertedAmount)
+	assert.Equal(t, "desk_jpy", transfer.Buy.Send.Source.From[0].Account)
+}
+
+func TestBuildCrossCurrencyTransfer_RoundingPolicy(t *testing.T) {
+	params := CrossCurrencyParams{
+		ID: "fx-3", SourceAlias: "a_usd", DestinationAlias: "a_eur",
+		FromAsset: "USD", ToAsset: "EUR", Amount: "2.5", Rate: "1.1", ToScale: 1,
+	}
+
+	tests := []struct {
+		policy    rounding.Policy
+		converted string
+	}{
+		{"", "2.7"},
+		{rounding.PolicyTruncate, "2.7"},
+		{rounding.PolicyHalfEven, "2.8"},
+	}
+
+	for _, tt := range tests {
+		params.Rounding = tt.policy
+
+		transfer, err := BuildCrossCurrencyTransfer(params)
+		require.NoError(t, err)
+		assert.Equal(t, tt.converted, transfer.ConvertedAmount, "policy %q", tt.policy)
+	}
+
+	params.Rounding = rounding.PolicyError
+
+	_, err := BuildCrossCurrencyTransfer(params)
+	require.ErrorIs(t, err, rounding.ErrExceedsScale)
+}
+
+func TestBuildCrossCurrencyTransfer_Errors(t *testing.T) {
+	base := CrossCurrencyParams{
+		ID: "fx", SourceAlias: "a", DestinationAlias: "b",
+		FromAsset: "USD", ToAsset: "EUR", Amount: "10", Rate: "0.9", ToScale: 2,
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(p *CrossCurrencyParams)
+	}{
+		{"missing id", func(p *CrossCurrencyParams) { p.ID = "" }},
+		{"missing asset", func(p *CrossCurrencyParams) { p.ToAsset = "" }},
+		{"same asset", func(p *CrossCurrencyParams) { p.ToAsset = "usd" }},
+		{"invalid amount", func(p *CrossCurrencyParams) { p.Amount = "ten" }},
+		{"zero amount", func(p *CrossCurrencyParams) { p.Amount = "0" }},
+		{"negative rate", func(p *CrossCurrencyParams) { p.Rate = "-1" }},
+		{"negative scale", func(p *CrossCurrencyParams) { p.ToScale = -1 }},
+		{"rounds to zero", func(p *CrossCurrencyParams) { p.Amount = "0.001" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := base
+			tt.mutate(&p)
+
+			_, err := BuildCrossCurrencyTransfer(p)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestExternalFundingInput(t *testing.T) {
+	in := ExternalFundingInput("EUR", "1000.00", "fx_conversion_eur", "fund-1")
+
+	require.NoError(t, in.Validate())
+	assert.Equal(t, "@external/EUR", in.Send.Source.From[0].Account)
+	assert.Equal(t, "fx_conversion_eur", in.Send.Distribute.To[0].Account)
+	assert.Equal(t, "fund-1", in.IdempotencyKey)
+}
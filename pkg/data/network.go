@@ -0,0 +1,337 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// NetworkTopology selects the shape of a generated counterparty network.
+type NetworkTopology string
+
+const (
+	// TopologyHubAndSpoke connects every account to one of a few hub accounts
+	// (e.g., a payment processor or a treasury account), in both directions.
+	TopologyHubAndSpoke NetworkTopology = "hub_and_spoke"
+
+	// TopologySmallWorld builds a Watts-Strogatz graph: a ring where each account
+	// transacts with its nearest neighbors and a share of links is rewired to
+	// random accounts, producing tight clusters joined by a few shortcuts.
+	TopologySmallWorld NetworkTopology = "small_world"
+
+	// TopologyBipartite splits accounts into merchants and customers; customers
+	// pay merchants, with a few popular merchants receiving most of the payments.
+	TopologyBipartite NetworkTopology = "bipartite"
+)
+
+// Counterparty roles assigned to network nodes.
+const (
+	RoleHub      = "hub"
+	RoleSpoke    = "spoke"
+	RolePeer     = "peer"
+	RoleMerchant = "merchant"
+	RoleCustomer = "customer"
+)
+
+// NetworkConfig configures counterparty network generation. Zero values fall
+// back to the defaults documented on each field.
+type NetworkConfig struct {
+	// Topology is the network shape (required)
+	Topology NetworkTopology
+
+	// Hubs is the number of hub accounts for hub-and-spoke networks (default 1)
+	Hubs int
+
+	// Neighbors is the number of ring neighbors of each account in small-world
+	// networks; odd values are rounded down (default 4)
+	Neighbors int
+
+	// RewireProbability is the probability of rewiring a ring link to a random
+	// account in small-world networks (default 0.1)
+	RewireProbability float64
+
+	// MerchantRatio is the share of accounts acting as merchants in bipartite
+	// networks (default 0.2)
+	MerchantRatio float64
+
+	// EdgesPerCustomer is the number of distinct merchants each customer pays in
+	// bipartite networks (default 3)
+	EdgesPerCustomer int
+
+	// Seed makes generation reproducible; zero uses the current time
+	Seed int64
+}
+
+// CounterpartyEdge is a directed transfer relationship between two account aliases.
+type CounterpartyEdge struct {
+	Source      string
+	Destination string
+}
+
+// CounterpartyNetwork is a directed graph of account-to-account transfer relationships.
+type CounterpartyNetwork struct {
+	Topology NetworkTopology
+	Nodes    []string
+	Edges    []CounterpartyEdge
+
+	// Roles maps each alias to its role in the topology (hub, spoke, peer, merchant, customer)
+	Roles map[string]string
+}
+
+// GenerateCounterpartyNetwork builds a transfer graph over the given account
+// aliases. Aliases must be unique and at least two are required.
+//
+// Example:
+//
+//	network, err := data.GenerateCounterpartyNetwork(aliases, data.NetworkConfig{
+//	    Topology: data.TopologyBipartite,
+//	    Seed:     42,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	for _, edge := range network.Edges {
+//	    fmt.Println(edge.Source, "->", edge.Destination)
+//	}
+func GenerateCounterpartyNetwork(aliases []string, cfg NetworkConfig) (*CounterpartyNetwork, error) {
+	if len(aliases) < 2 {
+		return nil, errors.New("at least two accounts are required to build a network")
+	}
+
+	seen := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		if alias == "" {
+			return nil, errors.New("account alias cannot be empty")
+		}
+
+		if seen[alias] {
+			return nil, fmt.Errorf("duplicate account alias: %s", alias)
+		}
+
+		seen[alias] = true
+	}
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	// #nosec G404 - non-cryptographic PRNG is intentional for reproducible demo data
+	r := rand.New(rand.NewSource(seed))
+
+	network := &CounterpartyNetwork{
+		Topology: cfg.Topology,
+		Nodes:    append([]string(nil), aliases...),
+		Roles:    make(map[string]string, len(aliases)),
+	}
+
+	switch cfg.Topology {
+	case TopologyHubAndSpoke:
+		buildHubAndSpoke(network, cfg, r)
+	case TopologySmallWorld:
+		buildSmallWorld(network, cfg, r)
+	case TopologyBipartite:
+		buildBipartite(network, cfg, r)
+	default:
+		return nil, fmt.Errorf("unsupported network topology: %q (use %s, %s or %s)",
+			cfg.Topology, TopologyHubAndSpoke, TopologySmallWorld, TopologyBipartite)
+	}
+
+	return network, nil
+}
+
+// OutDegree returns the number of edges leaving each alias.
+func (n *CounterpartyNetwork) OutDegree() map[string]int {
+	degree := make(map[string]int, len(n.Nodes))
+	for _, e := range n.Edges {
+		degree[e.Source]++
+	}
+
+	return degree
+}
+
+// InDegree returns the number of edges arriving at each alias.
+func (n *CounterpartyNetwork) InDegree() map[string]int {
+	degree := make(map[string]int, len(n.Nodes))
+	for _, e := range n.Edges {
+		degree[e.Destination]++
+	}
+
+	return degree
+}
+
+// TransferPatterns builds one transfer DSL pattern per edge. amount is called for
+// each edge and returns the amount to transfer; idempotency keys are derived from
+// keyPrefix and the edge position.
+func (n *CounterpartyNetwork) TransferPatterns(asset string, amount func(edge CounterpartyEdge) int, keyPrefix string) []TransactionPattern {
+	patterns := make([]TransactionPattern, 0, len(n.Edges))
+
+	for i, e := range n.Edges {
+		p := TransferPattern(asset, amount(e), e.Source, e.Destination, fmt.Sprintf("%s-%05d", keyPrefix, i), "")
+		if p.Metadata != nil {
+			p.Metadata["topology"] = string(n.Topology)
+		}
+
+		patterns = append(patterns, p)
+	}
+
+	return patterns
+}
+
+// addEdge appends a directed edge
+func (n *CounterpartyNetwork) addEdge(source, destination string) {
+	n.Edges = append(n.Edges, CounterpartyEdge{Source: source, Destination: destination})
+}
+
+// buildHubAndSpoke links every spoke to a hub in both directions and chains the hubs.
+func buildHubAndSpoke(n *CounterpartyNetwork, cfg NetworkConfig, r *rand.Rand) {
+	hubs := cfg.Hubs
+	if hubs <= 0 {
+		hubs = 1
+	}
+
+	if hubs >= len(n.Nodes) {
+		hubs = len(n.Nodes) - 1
+	}
+
+	for i, alias := range n.Nodes {
+		if i < hubs {
+			n.Roles[alias] = RoleHub
+		} else {
+			n.Roles[alias] = RoleSpoke
+		}
+	}
+
+	for i := 1; i < hubs; i++ {
+		n.addEdge(n.Nodes[i-1], n.Nodes[i])
+	}
+
+	for _, spoke := range n.Nodes[hubs:] {
+		hub := n.Nodes[r.Intn(hubs)]
+		n.addEdge(spoke, hub)
+		n.addEdge(hub, spoke)
+	}
+}
+
+// buildSmallWorld builds a Watts-Strogatz ring lattice with random rewiring.
+func buildSmallWorld(n *CounterpartyNetwork, cfg NetworkConfig, r *rand.Rand) {
+	size := len(n.Nodes)
+
+	k := cfg.Neighbors
+	if k <= 0 {
+		k = 4
+	}
+
+	if k > size-1 {
+		k = size - 1
+	}
+
+	half := k / 2
+	if half == 0 {
+		half = 1
+	}
+
+	p := cfg.RewireProbability
+	if p <= 0 {
+		p = 0.1
+	}
+
+	for _, alias := range n.Nodes {
+		n.Roles[alias] = RolePeer
+	}
+
+	linked := make(map[[2]int]bool, size*half)
+	link := func(a, b int) {
+		key := [2]int{min(a, b), max(a, b)}
+		if a == b || linked[key] {
+			return
+		}
+
+		linked[key] = true
+
+		// Orientation is random so money flows both ways around the ring
+		if r.Intn(2) == 0 {
+			a, b = b, a
+		}
+
+		n.addEdge(n.Nodes[a], n.Nodes[b])
+	}
+
+	for i := 0; i < size; i++ {
+		for j := 1; j <= half; j++ {
+			target := (i + j) % size
+			if r.Float64() < p {
+				target = r.Intn(size)
+			}
+
+			link(i, target)
+		}
+	}
+}
+
+// buildBipartite links customers to merchants with a skewed popularity so a few
+// merchants receive most of the payments.
+func buildBipartite(n *CounterpartyNetwork, cfg NetworkConfig, r *rand.Rand) {
+	ratio := cfg.MerchantRatio
+	if ratio <= 0 || ratio >= 1 {
+		ratio = 0.2
+	}
+
+	merchants := int(float64(len(n.Nodes)) * ratio)
+	if merchants < 1 {
+		merchants = 1
+	}
+
+	if merchants >= len(n.Nodes) {
+		merchants = len(n.Nodes) - 1
+	}
+
+	perCustomer := cfg.EdgesPerCustomer
+	if perCustomer <= 0 {
+		perCustomer = 3
+	}
+
+	if perCustomer > merchants {
+		perCustomer = merchants
+	}
+
+	// Zipf-like weights: merchant i is chosen proportionally to 1/(i+1)
+	weights := make([]float64, merchants)
+	total := 0.0
+
+	for i := range weights {
+		weights[i] = 1 / float64(i+1)
+		total += weights[i]
+	}
+
+	for i, alias := range n.Nodes {
+		if i < merchants {
+			n.Roles[alias] = RoleMerchant
+		} else {
+			n.Roles[alias] = RoleCustomer
+		}
+	}
+
+	for _, customer := range n.Nodes[merchants:] {
+		chosen := make(map[int]bool, perCustomer)
+
+		for len(chosen) < perCustomer {
+			pick := r.Float64() * total
+			m := 0
+
+			for ; m < merchants-1; m++ {
+				pick -= weights[m]
+				if pick < 0 {
+					break
+				}
+			}
+
+			if chosen[m] {
+				continue
+			}
+
+			chosen[m] = true
+			n.addEdge(customer, n.Nodes[m])
+		}
+	}
+}
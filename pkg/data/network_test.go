@@ -0,0 +1,130 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func networkAliases(n int) []string {
+	aliases := make([]string, n)
+	for i := range aliases {
+		aliases[i] = fmt.Sprintf("@acct_%03d", i)
+	}
+
+	return aliases
+}
+
+func TestGenerateCounterpartyNetwork_Errors(t *testing.T) {
+	_, err := GenerateCounterpartyNetwork(networkAliases(1), NetworkConfig{Topology: TopologySmallWorld})
+	assert.Error(t, err)
+
+	_, err = GenerateCounterpartyNetwork([]string{"@a", "@a"}, NetworkConfig{Topology: TopologySmallWorld})
+	assert.ErrorContains(t, err, "duplicate")
+
+	_, err = GenerateCounterpartyNetwork([]string{"@a", ""}, NetworkConfig{Topology: TopologySmallWorld})
+	assert.Error(t, err)
+
+	_, err = GenerateCounterpartyNetwork(networkAliases(5), NetworkConfig{Topology: "mesh"})
+	assert.ErrorContains(t, err, "unsupported network topology")
+}
+
+func TestGenerateCounterpartyNetwork_HubAndSpoke(t *testing.T) {
+	aliases := networkAliases(20)
+
+	network, err := GenerateCounterpartyNetwork(aliases, NetworkConfig{Topology: TopologyHubAndSpoke, Hubs: 2, Seed: 1})
+	require.NoError(t, err)
+
+	// one edge between the hubs plus two edges per spoke
+	assert.Len(t, network.Edges, 1+2*18)
+	assert.Equal(t, RoleHub, network.Roles[aliases[0]])
+	assert.Equal(t, RoleHub, network.Roles[aliases[1]])
+	assert.Equal(t, RoleSpoke, network.Roles[aliases[5]])
+
+	out := network.OutDegree()
+	for _, spoke := range aliases[2:] {
+		assert.Equal(t, 1, out[spoke])
+	}
+
+	for _, e := range network.Edges {
+		assert.True(t, network.Roles[e.Source] == RoleHub || network.Roles[e.Destination] == RoleHub,
+			"edge %s -> %s does not touch a hub", e.Source, e.Destination)
+	}
+}
+
+func TestGenerateCounterpartyNetwork_SmallWorld(t *testing.T) {
+	aliases := networkAliases(30)
+
+	network, err := GenerateCounterpartyNetwork(aliases, NetworkConfig{Topology: TopologySmallWorld, Neighbors: 4, Seed: 7})
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, network.Edges)
+	assert.LessOrEqual(t, len(network.Edges), 30*2)
+
+	pairs := map[[2]string]bool{}
+
+	for _, e := range network.Edges {
+		assert.NotEqual(t, e.Source, e.Destination)
+
+		key := [2]string{min(e.Source, e.Destination), max(e.Source, e.Destination)}
+		assert.False(t, pairs[key], "duplicate link %v", key)
+		pairs[key] = true
+	}
+
+	for _, alias := range aliases {
+		assert.Equal(t, RolePeer, network.Roles[alias])
+	}
+}
+
+func TestGenerateCounterpartyNetwork_Bipartite(t *testing.T) {
+	aliases := networkAliases(50)
+
+	network, err := GenerateCounterpartyNetwork(aliases, NetworkConfig{
+		Topology:         TopologyBipartite,
+		MerchantRatio:    0.2,
+		EdgesPerCustomer: 3,
+		Seed:             3,
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, network.Edges, 40*3)
+
+	for _, e := range network.Edges {
+		assert.Equal(t, RoleCustomer, network.Roles[e.Source])
+		assert.Equal(t, RoleMerchant, network.Roles[e.Destination])
+	}
+
+	// the most popular merchant receives more payments than the least popular one
+	in := network.InDegree()
+	assert.Greater(t, in[aliases[0]], in[aliases[9]])
+}
+
+func TestGenerateCounterpartyNetwork_Reproducible(t *testing.T) {
+	cfg := NetworkConfig{Topology: TopologySmallWorld, RewireProbability: 0.5, Seed: 99}
+
+	first, err := GenerateCounterpartyNetwork(networkAliases(25), cfg)
+	require.NoError(t, err)
+
+	second, err := GenerateCounterpartyNetwork(networkAliases(25), cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, first.Edges, second.Edges)
+}
+
+func TestCounterpartyNetwork_TransferPatterns(t *testing.T) {
+	network, err := GenerateCounterpartyNetwork(networkAliases(6), NetworkConfig{Topology: TopologyHubAndSpoke, Seed: 1})
+	require.NoError(t, err)
+
+	patterns := network.TransferPatterns("USD", func(CounterpartyEdge) int { return 10 }, "net")
+	require.Len(t, patterns, len(network.Edges))
+
+	for i, p := range patterns {
+		require.NoError(t, ValidateTransactionPattern(p))
+		assert.Equal(t, fmt.Sprintf("net-%05d", i), p.IdempotencyKey)
+		assert.Contains(t, p.DSLTemplate, network.Edges[i].Source)
+		assert.Contains(t, p.DSLTemplate, network.Edges[i].Destination)
+		assert.Equal(t, string(TopologyHubAndSpoke), p.Metadata["topology"])
+	}
+}
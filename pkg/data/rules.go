@@ -0,0 +1,104 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+)
+
+// currencyMinorUnits lists the ISO 4217 minor units for currencies commonly used in demo data.
+var currencyMinorUnits = map[string]int{
+	"ARS": 2, "AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0, "CNY": 2,
+	"COP": 2, "EUR": 2, "GBP": 2, "INR": 2, "JOD": 3, "JPY": 0, "KRW": 0, "KWD": 3,
+	"MXN": 2, "OMR": 3, "PEN": 2, "PYG": 0, "TND": 3, "USD": 2, "UYU": 2, "VND": 0,
+}
+
+// CurrencyMinorUnits returns the ISO 4217 minor units (decimal places) of a currency
+// and whether the currency is known.
+func CurrencyMinorUnits(code string) (int, bool) {
+	units, ok := currencyMinorUnits[strings.ToUpper(code)]
+	return units, ok
+}
+
+// AssetScaleMatchesCurrencyRule requires currency assets to use the ISO 4217 minor
+// units of their code as scale (2 for USD, 0 for JPY, 3 for KWD).
+// Currencies missing from the built-in table are not checked.
+func AssetScaleMatchesCurrencyRule() validation.Rule[AssetTemplate] {
+	return validation.Rule[AssetTemplate]{
+		Name:        "asset-scale-matches-currency",
+		Description: "currency asset scale must match the ISO 4217 minor units",
+		When: func(t AssetTemplate) bool {
+			return strings.EqualFold(t.Type, "currency")
+		},
+		Check: func(t AssetTemplate) *validation.FieldError {
+			units, ok := CurrencyMinorUnits(t.Code)
+			if !ok || units == t.Scale {
+				return nil
+			}
+
+			return validation.BuildFieldError("scale", t.Scale,
+				fmt.Sprintf("%s uses %d decimal places, got scale %d", t.Code, units, t.Scale)).
+				WithSuggestions(fmt.Sprintf("Set the scale of %s to %d", t.Code, units))
+		},
+	}
+}
+
+// AccountTypeRequiresPortfolioRule requires accounts of the given types to reference
+// a portfolio. Type comparison is case-insensitive.
+func AccountTypeRequiresPortfolioRule(types ...string) validation.Rule[AccountTemplate] {
+	return validation.Rule[AccountTemplate]{
+		Name:        "account-type-requires-portfolio",
+		Description: fmt.Sprintf("accounts of type %s must have a portfolio", strings.Join(types, ", ")),
+		When: func(t AccountTemplate) bool {
+			return containsFold(types, t.Type)
+		},
+		Check: func(t AccountTemplate) *validation.FieldError {
+			if t.PortfolioID != nil && *t.PortfolioID != "" {
+				return nil
+			}
+
+			return validation.BuildFieldError("portfolioId", nil,
+				fmt.Sprintf("Accounts of type %s must belong to a portfolio", t.Type)).
+				WithSuggestions("Set PortfolioID on the account template")
+		},
+	}
+}
+
+// AccountTypeRequiresAccountTypeKeyRule requires every account to link to an account
+// type key, which transaction routes rely on for validation.
+func AccountTypeRequiresAccountTypeKeyRule() validation.Rule[AccountTemplate] {
+	return validation.Rule[AccountTemplate]{
+		Name:        "account-requires-account-type-key",
+		Description: "accounts must reference an account type key",
+		Check: func(t AccountTemplate) *validation.FieldError {
+			if t.AccountTypeKey != nil && *t.AccountTypeKey != "" {
+				return nil
+			}
+
+			return validation.BuildFieldError("accountTypeKey", nil, "Account type key is required").
+				WithSuggestions("Set AccountTypeKey to one of the generated account type keys (e.g., 'CHECKING')")
+		},
+	}
+}
+
+// DefaultAssetRules returns the cross-field rules applied to asset templates.
+func DefaultAssetRules() *validation.RuleSet[AssetTemplate] {
+	return validation.NewRuleSet(AssetScaleMatchesCurrencyRule())
+}
+
+// DefaultAccountRules returns the cross-field rules applied to account templates.
+func DefaultAccountRules() *validation.RuleSet[AccountTemplate] {
+	return validation.NewRuleSet(AccountTypeRequiresAccountTypeKeyRule())
+}
+
+// containsFold reports whether values contains s, ignoring case.
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+
+	return false
+}
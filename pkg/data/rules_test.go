@@ -0,0 +1,67 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetScaleMatchesCurrencyRule(t *testing.T) {
+	rules := DefaultAssetRules()
+
+	tests := []struct {
+		name    string
+		asset   AssetTemplate
+		wantErr bool
+	}{
+		{"USD scale 2", AssetTemplate{Type: "currency", Code: "USD", Scale: 2}, false},
+		{"JPY scale 0", AssetTemplate{Type: "currency", Code: "JPY", Scale: 0}, false},
+		{"USD scale 3", AssetTemplate{Type: "currency", Code: "USD", Scale: 3}, true},
+		{"KWD scale 2", AssetTemplate{Type: "Currency", Code: "KWD", Scale: 2}, true},
+		{"unknown currency", AssetTemplate{Type: "currency", Code: "XYZ", Scale: 5}, false},
+		{"crypto not checked", AssetTemplate{Type: "crypto", Code: "BTC", Scale: 8}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := rules.Evaluate(tt.asset)
+			assert.Equal(t, tt.wantErr, errs.HasErrors())
+
+			if tt.wantErr {
+				assert.Equal(t, "asset-scale-matches-currency", errs.GetFieldErrors()[0].Code)
+			}
+		})
+	}
+}
+
+func TestAccountTypeRequiresPortfolioRule(t *testing.T) {
+	rule := AccountTypeRequiresPortfolioRule("deposit", "savings")
+	require.NotNil(t, rule.When)
+
+	assert.False(t, rule.When(AccountTemplate{Type: "marketplace"}))
+	assert.True(t, rule.When(AccountTemplate{Type: "Deposit"}))
+
+	assert.NotNil(t, rule.Check(AccountTemplate{Type: "deposit"}))
+	assert.Nil(t, rule.Check(AccountTemplate{Type: "deposit", PortfolioID: StrPtr("p-1")}))
+}
+
+func TestDefaultRulesAcceptBuiltInTemplates(t *testing.T) {
+	assetReport := DefaultAssetRules().EvaluateAll(AllAssetTemplates())
+	assert.True(t, assetReport.Valid(), assetReport.Error())
+
+	accountReport := DefaultAccountRules().EvaluateAll(AllAccountTemplates())
+	assert.True(t, accountReport.Valid(), accountReport.Error())
+
+	report := DefaultAccountRules().EvaluateAll([]AccountTemplate{{Name: "No key"}})
+	assert.Equal(t, []int{0}, report.InvalidIndices())
+}
+
+func TestCurrencyMinorUnits(t *testing.T) {
+	units, ok := CurrencyMinorUnits("bhd")
+	assert.True(t, ok)
+	assert.Equal(t, 3, units)
+
+	_, ok = CurrencyMinorUnits("POINTS")
+	assert.False(t, ok)
+}
@@ -0,0 +1,248 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// StressMode selects how a cohort of accounts is put under pressure.
+type StressMode string
+
+const (
+	// StressVelocity sends many small transactions per minute from each account
+	// of the cohort, to exercise velocity limits and hot-account contention.
+	StressVelocity StressMode = "velocity"
+
+	// StressNearLimit drains each account of the cohort to a configured share of
+	// its balance or limit, optionally followed by one transaction exceeding it.
+	StressNearLimit StressMode = "near_limit"
+)
+
+// StressCohort describes a group of accounts sharing the same stress parameters.
+type StressCohort struct {
+	// Name identifies the cohort in plans and reports (required)
+	Name string
+
+	// Mode is the stress pattern applied to the cohort (required)
+	Mode StressMode
+
+	// Accounts lists the aliases of the accounts in the cohort (required)
+	Accounts []string
+
+	// Duration is the time window over which transactions are spread (required)
+	Duration time.Duration
+
+	// TransactionsPerMinute is the target rate per account in velocity mode
+	TransactionsPerMinute int
+
+	// MinAmount and MaxAmount bound velocity transaction amounts in major units
+	// (defaults 1 and 10)
+	MinAmount float64
+	MaxAmount float64
+
+	// Limit is the balance or limit of each account in minor units, in near-limit mode
+	Limit int64
+
+	// Utilization is the share of Limit consumed in near-limit mode, in (0, 1]
+	// (default 0.95)
+	Utilization float64
+
+	// Transactions is the number of transactions used to reach the target
+	// utilization in near-limit mode (default 10)
+	Transactions int
+
+	// ExceedLimit adds one final transaction per account that goes past Limit
+	// in near-limit mode, to exercise insufficient-funds handling
+	ExceedLimit bool
+}
+
+// StressTransaction is a single scheduled transaction of a stress plan.
+type StressTransaction struct {
+	Cohort  string
+	Mode    StressMode
+	Account string
+
+	// Amount is expressed in minor units of the asset
+	Amount int64
+
+	// At is the offset from the start of the run at which to submit
+	At time.Duration
+
+	// ExceedsLimit marks the transaction expected to overdraw the account
+	ExceedsLimit bool
+}
+
+// Validate checks that the cohort is complete and consistent with its mode.
+func (c StressCohort) Validate() error {
+	if c.Name == "" {
+		return errors.New("cohort name is required")
+	}
+
+	if len(c.Accounts) == 0 {
+		return fmt.Errorf("cohort %s has no accounts", c.Name)
+	}
+
+	if c.Duration <= 0 {
+		return fmt.Errorf("cohort %s duration must be positive", c.Name)
+	}
+
+	switch c.Mode {
+	case StressVelocity:
+		if c.TransactionsPerMinute <= 0 {
+			return fmt.Errorf("cohort %s transactions per minute must be positive", c.Name)
+		}
+
+		if c.MinAmount < 0 || c.MaxAmount < 0 {
+			return fmt.Errorf("cohort %s amounts cannot be negative", c.Name)
+		}
+	case StressNearLimit:
+		if c.Limit <= 0 {
+			return fmt.Errorf("cohort %s limit must be positive", c.Name)
+		}
+
+		if c.Utilization < 0 || c.Utilization > 1 {
+			return fmt.Errorf("cohort %s utilization must be between 0 and 1", c.Name)
+		}
+
+		if c.Transactions < 0 {
+			return fmt.Errorf("cohort %s transactions cannot be negative", c.Name)
+		}
+	default:
+		return fmt.Errorf("cohort %s has unsupported stress mode %q (use %s or %s)", c.Name, c.Mode, StressVelocity, StressNearLimit)
+	}
+
+	return nil
+}
+
+// BuildStressPlan schedules the transactions of every cohort and returns them
+// ordered by submission time. amounts drives velocity amounts and scale is the
+// asset scale used to convert them to minor units.
+//
+// Example:
+//
+//	plan, err := data.BuildStressPlan([]data.StressCohort{{
+//	    Name:                  "hot-merchants",
+//	    Mode:                  data.StressVelocity,
+//	    Accounts:              []string{"@merchant_1", "@merchant_2"},
+//	    Duration:              time.Minute,
+//	    TransactionsPerMinute: 120,
+//	}}, data.NewAmountGenerator(42), 2)
+func BuildStressPlan(cohorts []StressCohort, amounts *AmountGenerator, scale int) ([]StressTransaction, error) {
+	if amounts == nil {
+		amounts = NewAmountGenerator(0)
+	}
+
+	var plan []StressTransaction
+
+	for _, cohort := range cohorts {
+		if err := cohort.Validate(); err != nil {
+			return nil, err
+		}
+
+		switch cohort.Mode {
+		case StressVelocity:
+			plan = append(plan, velocityPlan(cohort, amounts, scale)...)
+		case StressNearLimit:
+			plan = append(plan, nearLimitPlan(cohort)...)
+		}
+	}
+
+	sort.SliceStable(plan, func(i, j int) bool { return plan[i].At < plan[j].At })
+
+	return plan, nil
+}
+
+// velocityPlan spreads TransactionsPerMinute transactions per account evenly
+// over the cohort duration, staggering accounts so they do not fire together.
+func velocityPlan(c StressCohort, amounts *AmountGenerator, scale int) []StressTransaction {
+	minAmount, maxAmount := c.MinAmount, c.MaxAmount
+	if minAmount == 0 && maxAmount == 0 {
+		minAmount, maxAmount = 1, 10
+	}
+
+	interval := time.Minute / time.Duration(c.TransactionsPerMinute)
+	count := int(c.Duration / interval)
+
+	if count == 0 {
+		count = 1
+	}
+
+	plan := make([]StressTransaction, 0, count*len(c.Accounts))
+
+	for a, account := range c.Accounts {
+		offset := interval * time.Duration(a) / time.Duration(len(c.Accounts))
+
+		for i := 0; i < count; i++ {
+			amount := amounts.Uniform(minAmount, maxAmount, scale)
+			if amount <= 0 {
+				amount = 1
+			}
+
+			plan = append(plan, StressTransaction{
+				Cohort:  c.Name,
+				Mode:    c.Mode,
+				Account: account,
+				Amount:  amount,
+				At:      offset + interval*time.Duration(i),
+			})
+		}
+	}
+
+	return plan
+}
+
+// nearLimitPlan splits Limit*Utilization into equal transactions per account,
+// assigning the rounding remainder to the last one so the target is hit exactly.
+func nearLimitPlan(c StressCohort) []StressTransaction {
+	utilization := c.Utilization
+	if utilization == 0 {
+		utilization = 0.95
+	}
+
+	count := c.Transactions
+	if count == 0 {
+		count = 10
+	}
+
+	target := max(int64(float64(c.Limit)*utilization), 1)
+	if target < int64(count) {
+		count = int(target)
+	}
+
+	step := c.Duration / time.Duration(count+1)
+	plan := make([]StressTransaction, 0, (count+1)*len(c.Accounts))
+
+	for _, account := range c.Accounts {
+		share := target / int64(count)
+
+		for i := 0; i < count; i++ {
+			amount := share
+			if i == count-1 {
+				amount = target - share*int64(count-1)
+			}
+
+			plan = append(plan, StressTransaction{
+				Cohort:  c.Name,
+				Mode:    c.Mode,
+				Account: account,
+				Amount:  amount,
+				At:      step * time.Duration(i),
+			})
+		}
+
+		if c.ExceedLimit {
+			plan = append(plan, StressTransaction{
+				Cohort:       c.Name,
+				Mode:         c.Mode,
+				Account:      account,
+				Amount:       c.Limit - target + 1,
+				At:           step * time.Duration(count),
+				ExceedsLimit: true,
+			})
+		}
+	}
+
+	return plan
+}
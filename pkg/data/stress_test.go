@@ -0,0 +1,121 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStressCohortValidate(t *testing.T) {
+	valid := StressCohort{Name: "hot", Mode: StressVelocity, Accounts: []string{"@a"}, Duration: time.Minute, TransactionsPerMinute: 60}
+	require.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name   string
+		mutate func(c *StressCohort)
+	}{
+		{"missing name", func(c *StressCohort) { c.Name = "" }},
+		{"no accounts", func(c *StressCohort) { c.Accounts = nil }},
+		{"no duration", func(c *StressCohort) { c.Duration = 0 }},
+		{"no rate", func(c *StressCohort) { c.TransactionsPerMinute = 0 }},
+		{"negative amount", func(c *StressCohort) { c.MinAmount = -1 }},
+		{"unknown mode", func(c *StressCohort) { c.Mode = "burst" }},
+		{"near limit without limit", func(c *StressCohort) { c.Mode = StressNearLimit }},
+		{"utilization above one", func(c *StressCohort) { c.Mode = StressNearLimit; c.Limit = 100; c.Utilization = 1.5 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid
+			tt.mutate(&c)
+			assert.Error(t, c.Validate())
+		})
+	}
+}
+
+func TestBuildStressPlan_Velocity(t *testing.T) {
+	plan, err := BuildStressPlan([]StressCohort{{
+		Name:                  "hot",
+		Mode:                  StressVelocity,
+		Accounts:              []string{"@a", "@b"},
+		Duration:              30 * time.Second,
+		TransactionsPerMinute: 120,
+		MinAmount:             1,
+		MaxAmount:             5,
+	}}, NewAmountGenerator(1), 2)
+	require.NoError(t, err)
+
+	// 120 tx/min over 30s is 60 transactions per account
+	require.Len(t, plan, 120)
+
+	perAccount := map[string]int{}
+
+	for i, tx := range plan {
+		perAccount[tx.Account]++
+
+		assert.Equal(t, "hot", tx.Cohort)
+		assert.GreaterOrEqual(t, tx.Amount, int64(100))
+		assert.LessOrEqual(t, tx.Amount, int64(500))
+		assert.Less(t, tx.At, 30*time.Second)
+
+		if i > 0 {
+			assert.GreaterOrEqual(t, tx.At, plan[i-1].At, "plan must be ordered by time")
+		}
+	}
+
+	assert.Equal(t, map[string]int{"@a": 60, "@b": 60}, perAccount)
+}
+
+func TestBuildStressPlan_NearLimit(t *testing.T) {
+	plan, err := BuildStressPlan([]StressCohort{{
+		Name:         "drain",
+		Mode:         StressNearLimit,
+		Accounts:     []string{"@a"},
+		Duration:     10 * time.Second,
+		Limit:        10_000,
+		Utilization:  0.9,
+		Transactions: 7,
+		ExceedLimit:  true,
+	}}, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, plan, 8)
+
+	var total int64
+
+	for _, tx := range plan[:7] {
+		assert.False(t, tx.ExceedsLimit)
+		total += tx.Amount
+	}
+
+	assert.Equal(t, int64(9_000), total)
+
+	last := plan[7]
+	assert.True(t, last.ExceedsLimit)
+	assert.Greater(t, total+last.Amount, int64(10_000))
+}
+
+func TestBuildStressPlan_NearLimitSmallLimit(t *testing.T) {
+	plan, err := BuildStressPlan([]StressCohort{{
+		Name:     "tiny",
+		Mode:     StressNearLimit,
+		Accounts: []string{"@a"},
+		Duration: time.Second,
+		Limit:    3,
+	}}, nil, 2)
+	require.NoError(t, err)
+
+	var total int64
+	for _, tx := range plan {
+		assert.Positive(t, tx.Amount)
+		total += tx.Amount
+	}
+
+	assert.Equal(t, int64(2), total)
+}
+
+func TestBuildStressPlan_InvalidCohort(t *testing.T) {
+	_, err := BuildStressPlan([]StressCohort{{Name: "bad"}}, nil, 2)
+	assert.Error(t, err)
+}
@@ -0,0 +1,216 @@
+// Package envdetect detects where the program runs, such as a Kubernetes
+// namespace, a cloud region or a local container, from the environment
+// variables and files that the platforms set. It makes no network calls, so
+// it is fast and safe to run at startup.
+//
+// The result fills the OpenTelemetry resource attributes of the SDK (see
+// observability.WithDetectedResource) and selects the default environment
+// when MIDAZ_ENVIRONMENT is unset (see config.WithDetectedEnvironment).
+//
+// Example:
+//
+//	info := envdetect.Detect()
+//	if env, reason := info.Environment(); env != "" {
+//	    log.Printf("running in %s (%s)", env, reason)
+//	}
+package envdetect
+
+import (
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// Environment names returned by Info.Environment, the values of the
+// config.Environment constants.
+const (
+	EnvironmentLocal       = "local"
+	EnvironmentDevelopment = "development"
+	EnvironmentProduction  = "production"
+)
+
+// Well-known files read by Detect.
+const (
+	namespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+	dockerFile    = "/.dockerenv"
+	podmanFile    = "/run/.containerenv"
+)
+
+// Info describes where the program runs. Fields that could not be detected
+// are empty.
+type Info struct {
+	// Kubernetes is true inside a Kubernetes pod
+	Kubernetes bool `json:"kubernetes"`
+
+	// Namespace, Pod and Node locate the pod. The namespace comes from
+	// POD_NAMESPACE or the service account, the pod from POD_NAME or the host
+	// name and the node from NODE_NAME, set through the downward API.
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	Node      string `json:"node,omitempty"`
+
+	// Container is the container runtime outside Kubernetes: "docker" or "podman"
+	Container string `json:"container,omitempty"`
+
+	// CloudProvider is "aws", "gcp" or "azure"
+	CloudProvider string `json:"cloudProvider,omitempty"`
+
+	// CloudPlatform is the service running the program, in the OpenTelemetry
+	// form, e.g. "aws_lambda" or "gcp_cloud_run"
+	CloudPlatform string `json:"cloudPlatform,omitempty"`
+
+	// CloudRegion is the region of the cloud provider, e.g. "us-east-1"
+	CloudRegion string `json:"cloudRegion,omitempty"`
+}
+
+// Detect inspects the current process.
+func Detect() Info {
+	return detect(os.Getenv, os.ReadFile)
+}
+
+// detect inspects the environment through getenv and readFile.
+func detect(getenv func(string) string, readFile func(string) ([]byte, error)) Info {
+	var info Info
+
+	exists := func(path string) bool {
+		_, err := readFile(path)
+		return err == nil
+	}
+
+	if getenv("KUBERNETES_SERVICE_HOST") != "" {
+		info.Kubernetes = true
+		info.Namespace = first(getenv("POD_NAMESPACE"), readTrimmed(readFile, namespaceFile))
+		info.Pod = first(getenv("POD_NAME"), getenv("HOSTNAME"))
+		info.Node = getenv("NODE_NAME")
+	} else {
+		switch {
+		case exists(dockerFile):
+			info.Container = "docker"
+		case exists(podmanFile):
+			info.Container = "podman"
+		}
+	}
+
+	detectCloud(&info, getenv)
+
+	return info
+}
+
+// detectCloud fills the cloud fields from the variables set by the managed
+// runtimes of each provider.
+func detectCloud(info *Info, getenv func(string) string) {
+	switch {
+	case getenv("AWS_LAMBDA_FUNCTION_NAME") != "":
+		info.CloudProvider, info.CloudPlatform = "aws", "aws_lambda"
+	case getenv("ECS_CONTAINER_METADATA_URI_V4") != "" || getenv("ECS_CONTAINER_METADATA_URI") != "":
+		info.CloudProvider, info.CloudPlatform = "aws", "aws_ecs"
+	case getenv("K_SERVICE") != "":
+		info.CloudProvider, info.CloudPlatform = "gcp", "gcp_cloud_run"
+	case getenv("FUNCTION_TARGET") != "":
+		info.CloudProvider, info.CloudPlatform = "gcp", "gcp_cloud_functions"
+	case getenv("GAE_SERVICE") != "":
+		info.CloudProvider, info.CloudPlatform = "gcp", "gcp_app_engine"
+	case getenv("CONTAINER_APP_NAME") != "":
+		info.CloudProvider, info.CloudPlatform = "azure", "azure_container_apps"
+	case getenv("WEBSITE_SITE_NAME") != "":
+		info.CloudProvider, info.CloudPlatform = "azure", "azure_app_service"
+	}
+
+	// AWS_REGION is set by the AWS runtimes, unlike AWS_DEFAULT_REGION
+	// which is often set on workstations
+	if region := getenv("AWS_REGION"); region != "" && (info.CloudProvider == "" || info.CloudProvider == "aws") {
+		info.CloudProvider, info.CloudRegion = "aws", region
+	}
+
+	switch info.CloudProvider {
+	case "gcp":
+		info.CloudRegion = first(getenv("GOOGLE_CLOUD_REGION"), getenv("FUNCTION_REGION"))
+	case "azure":
+		info.CloudRegion = getenv("REGION_NAME")
+	}
+}
+
+// Environment returns the Midaz environment suggested by the detection,
+// with the reason, or "" when nothing points to one:
+//   - a Kubernetes namespace named like prod, production, prd or live
+//     selects production, and one named like dev, staging, qa, test, uat or
+//     sandbox selects development, e.g. "payments-staging";
+//   - a container outside Kubernetes and any cloud selects local, as it
+//     usually comes from docker compose on a workstation.
+func (i Info) Environment() (name, reason string) {
+	if i.Kubernetes {
+		if env := namespaceEnvironment(i.Namespace); env != "" {
+			return env, "kubernetes namespace " + i.Namespace
+		}
+
+		return "", ""
+	}
+
+	if i.Container != "" && i.CloudProvider == "" {
+		return EnvironmentLocal, i.Container + " container"
+	}
+
+	return "", ""
+}
+
+// namespaceEnvironment matches the words of a namespace name against the
+// usual environment names.
+func namespaceEnvironment(namespace string) string {
+	words := strings.FieldsFunc(strings.ToLower(namespace), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+
+	for _, w := range words {
+		switch w {
+		case "prod", "production", "prd", "live":
+			return EnvironmentProduction
+		case "dev", "development", "staging", "stage", "stg", "qa", "test", "uat", "sandbox":
+			return EnvironmentDevelopment
+		}
+	}
+
+	return ""
+}
+
+// Attributes returns the detected fields as OpenTelemetry resource
+// attributes, such as k8s.namespace.name and cloud.region.
+func (i Info) Attributes() []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+
+	add := func(key attribute.Key, value string) {
+		if value != "" {
+			attrs = append(attrs, key.String(value))
+		}
+	}
+
+	add(semconv.K8SNamespaceNameKey, i.Namespace)
+	add(semconv.K8SPodNameKey, i.Pod)
+	add(semconv.K8SNodeNameKey, i.Node)
+	add(semconv.ContainerRuntimeKey, i.Container)
+	add(semconv.CloudProviderKey, i.CloudProvider)
+	add(semconv.CloudPlatformKey, i.CloudPlatform)
+	add(semconv.CloudRegionKey, i.CloudRegion)
+
+	return attrs
+}
+
+func readTrimmed(readFile func(string) ([]byte, error), path string) string {
+	data, err := readFile(path)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(data))
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,143 @@
+package envdetect
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// fake returns the getenv and readFile of a fake environment.
+func fake(env map[string]string, files map[string]string) (func(string) string, func(string) ([]byte, error)) {
+	getenv := func(name string) string { return env[name] }
+	readFile := func(path string) ([]byte, error) {
+		data, ok := files[path]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+
+		return []byte(data), nil
+	}
+
+	return getenv, readFile
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		files   map[string]string
+		info    Info
+		envName string
+		reason  string
+	}{
+		{
+			name: "bare host",
+		},
+		{
+			name:    "docker compose",
+			files:   map[string]string{dockerFile: ""},
+			info:    Info{Container: "docker"},
+			envName: EnvironmentLocal,
+			reason:  "docker container",
+		},
+		{
+			name:    "podman",
+			files:   map[string]string{podmanFile: ""},
+			info:    Info{Container: "podman"},
+			envName: EnvironmentLocal,
+			reason:  "podman container",
+		},
+		{
+			name:    "kubernetes from the service account",
+			env:     map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "HOSTNAME": "api-7d9f"},
+			files:   map[string]string{namespaceFile: "payments-prod\n", dockerFile: ""},
+			info:    Info{Kubernetes: true, Namespace: "payments-prod", Pod: "api-7d9f"},
+			envName: EnvironmentProduction,
+			reason:  "kubernetes namespace payments-prod",
+		},
+		{
+			name: "kubernetes from the downward API on EKS",
+			env: map[string]string{
+				"KUBERNETES_SERVICE_HOST": "10.0.0.1", "POD_NAMESPACE": "Ledger_Staging", "POD_NAME": "api-1",
+				"NODE_NAME": "node-a", "AWS_REGION": "sa-east-1",
+			},
+			info: Info{
+				Kubernetes: true, Namespace: "Ledger_Staging", Pod: "api-1", Node: "node-a",
+				CloudProvider: "aws", CloudRegion: "sa-east-1",
+			},
+			envName: EnvironmentDevelopment,
+			reason:  "kubernetes namespace Ledger_Staging",
+		},
+		{
+			name: "kubernetes namespace without environment",
+			env:  map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "POD_NAMESPACE": "payments"},
+			info: Info{Kubernetes: true, Namespace: "payments"},
+		},
+		{
+			name:  "aws lambda",
+			env:   map[string]string{"AWS_LAMBDA_FUNCTION_NAME": "sync", "AWS_REGION": "us-east-1"},
+			files: map[string]string{dockerFile: ""},
+			info:  Info{Container: "docker", CloudProvider: "aws", CloudPlatform: "aws_lambda", CloudRegion: "us-east-1"},
+		},
+		{
+			name: "aws ecs",
+			env:  map[string]string{"ECS_CONTAINER_METADATA_URI_V4": "http://169.254.170.2/v4/x"},
+			info: Info{CloudProvider: "aws", CloudPlatform: "aws_ecs"},
+		},
+		{
+			name: "gcp cloud run",
+			env:  map[string]string{"K_SERVICE": "api", "GOOGLE_CLOUD_REGION": "europe-west1", "AWS_REGION": "us-east-1"},
+			info: Info{CloudProvider: "gcp", CloudPlatform: "gcp_cloud_run", CloudRegion: "europe-west1"},
+		},
+		{
+			name: "azure app service",
+			env:  map[string]string{"WEBSITE_SITE_NAME": "api", "REGION_NAME": "brazilsouth"},
+			info: Info{CloudProvider: "azure", CloudPlatform: "azure_app_service", CloudRegion: "brazilsouth"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			info := detect(fake(tc.env, tc.files))
+			assert.Equal(t, tc.info, info)
+
+			envName, reason := info.Environment()
+			assert.Equal(t, tc.envName, envName)
+			assert.Equal(t, tc.reason, reason)
+		})
+	}
+}
+
+func TestNamespaceEnvironment(t *testing.T) {
+	tests := map[string]string{
+		"prod":           EnvironmentProduction,
+		"midaz-live":     EnvironmentProduction,
+		"team.prd.v2":    EnvironmentProduction,
+		"dev":            EnvironmentDevelopment,
+		"qa-ledger":      EnvironmentDevelopment,
+		"uat":            EnvironmentDevelopment,
+		"production-api": EnvironmentProduction,
+		"product":        "",
+		"devops":         "",
+		"":               "",
+	}
+
+	for namespace, expected := range tests {
+		assert.Equal(t, expected, namespaceEnvironment(namespace), namespace)
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	info := Info{Kubernetes: true, Namespace: "payments-prod", Pod: "api-1", CloudProvider: "aws", CloudRegion: "us-east-1"}
+
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("k8s.namespace.name", "payments-prod"),
+		attribute.String("k8s.pod.name", "api-1"),
+		attribute.String("cloud.provider", "aws"),
+		attribute.String("cloud.region", "us-east-1"),
+	}, info.Attributes())
+
+	assert.Empty(t, Info{}.Attributes())
+}
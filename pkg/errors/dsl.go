@@ -0,0 +1,162 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Midaz error codes of DSL transactions that fail to compile.
+var dslSyntaxAPICodes = map[string]bool{
+	"0017": true, // invalid script format
+	"0048": true, // invalid DSL file format
+	"0049": true, // empty DSL file
+}
+
+// Midaz error codes of transactions that violate their accounting routes.
+var routeViolationAPICodes = map[string]bool{
+	"0114": true, // transaction route not informed
+	"0116": true, // operation routes count mismatch
+	"0117": true, // operation route not found in the transaction route
+	"0118": true, // alias does not match the route rule
+	"0119": true, // account type does not match the route rule
+	"0150": true, // route not bidirectional
+	"0151": true, // missing counterpart
+	"0152": true, // direction route mismatch
+	"0153": true, // no source for action
+	"0154": true, // no destination for action
+	"0157": true, // no routes for action
+}
+
+// DSLDiagnostic is a problem the server found compiling a DSL transaction.
+type DSLDiagnostic struct {
+	// Line is the 1-based line of the problem
+	Line int
+	// Column is the 0-based column of the problem
+	Column int
+	// Message describes the problem, e.g. "mismatched input 'x' expecting ')'"
+	Message string
+}
+
+// String returns the diagnostic as "line L:C: message".
+func (d DSLDiagnostic) String() string {
+	return fmt.Sprintf("line %d:%d: %s", d.Line, d.Column, d.Message)
+}
+
+// DSLSyntaxError holds the diagnostics of a DSL transaction that failed to
+// compile. It is the underlying error of an *Error with CodeDSLSyntax.
+type DSLSyntaxError struct {
+	Diagnostics []DSLDiagnostic
+}
+
+// Error implements the error interface.
+func (e *DSLSyntaxError) Error() string {
+	lines := make([]string, len(e.Diagnostics))
+	for i, d := range e.Diagnostics {
+		lines[i] = d.String()
+	}
+
+	return "invalid DSL: " + strings.Join(lines, "; ")
+}
+
+// DSLDiagnostics returns the diagnostics of a DSL syntax error, nil if err
+// has none.
+//
+// Example:
+//
+//	for _, d := range errors.DSLDiagnostics(err) {
+//	    fmt.Printf("%s:%d:%d: %s\n", file, d.Line, d.Column, d.Message)
+//	}
+func DSLDiagnostics(err error) []DSLDiagnostic {
+	var dslErr *DSLSyntaxError
+	if errors.As(err, &dslErr) {
+		return dslErr.Diagnostics
+	}
+
+	return nil
+}
+
+// IsDSLSyntaxError checks if an error is a DSL transaction that failed to compile.
+func IsDSLSyntaxError(err error) bool {
+	var mdzErr *Error
+	if errors.As(err, &mdzErr) {
+		return mdzErr.Code == CodeDSLSyntax
+	}
+
+	return false
+}
+
+// IsRouteViolationError checks if an error is a transaction violating its accounting routes.
+func IsRouteViolationError(err error) bool {
+	var mdzErr *Error
+	if errors.As(err, &mdzErr) {
+		return mdzErr.Code == CodeRouteViolation
+	}
+
+	return false
+}
+
+// applyAPICode refines err with the Midaz error code of the response. DSL
+// and route failures get the category of ErrDSLSyntax and ErrRouteViolation
+// whatever their HTTP status, so errors.Is matches them.
+func applyAPICode(err *Error, apiCode string) {
+	switch {
+	case dslSyntaxAPICodes[apiCode]:
+		err.Category, err.Code = ErrDSLSyntax.Category, CodeDSLSyntax
+
+		if diagnostics := parseDSLDiagnostics(err.Message); len(diagnostics) > 0 {
+			dslErr := &DSLSyntaxError{Diagnostics: diagnostics}
+			err.Message = dslErr.Error()
+			err.Err = dslErr
+		}
+	case routeViolationAPICodes[apiCode]:
+		err.Category, err.Code = ErrRouteViolation.Category, CodeRouteViolation
+	}
+}
+
+// dslDiagnosticStart matches the start of a compile error in the message of
+// a DSL failure, which lists them as "{line column message source}".
+var dslDiagnosticStart = regexp.MustCompile(`\{(\d+) (\d+) `)
+
+// parseDSLDiagnostics extracts the compile errors listed in message.
+func parseDSLDiagnostics(message string) []DSLDiagnostic {
+	var diagnostics []DSLDiagnostic
+
+	for offset := 0; offset < len(message); {
+		loc := dslDiagnosticStart.FindStringSubmatchIndex(message[offset:])
+		if loc == nil {
+			break
+		}
+
+		start := offset + loc[1]
+
+		// Messages may hold braces, e.g. "expecting {'a', 'b'}"
+		end, depth := start, 1
+		for ; end < len(message) && depth > 0; end++ {
+			switch message[end] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+		}
+
+		if depth > 0 {
+			break
+		}
+
+		line, _ := strconv.Atoi(message[offset+loc[2] : offset+loc[3]])   //nolint:errcheck // matched digits
+		column, _ := strconv.Atoi(message[offset+loc[4] : offset+loc[5]]) //nolint:errcheck // matched digits
+
+		diagnostics = append(diagnostics, DSLDiagnostic{
+			Line:    line,
+			Column:  column,
+			Message: strings.TrimSpace(message[start : end-1]),
+		})
+		offset = end
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,60 @@
+package errors_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorFromHTTPResponse_DSLSyntax(t *testing.T) {
+	message := "The submitted DSL file [{1 12 mismatched input 'x' expecting {'(', ':'} } {3 0 extraneous input ')' expecting <EOF> }] is in an incorrect format."
+
+	err := sdkerrors.ErrorFromHTTPResponse(http.StatusBadRequest, "req-1", message, "0048", "transaction", "")
+
+	assert.True(t, sdkerrors.IsDSLSyntaxError(err))
+	assert.True(t, sdkerrors.IsValidationError(err))
+	require.ErrorIs(t, err, sdkerrors.ErrDSLSyntax)
+
+	diagnostics := sdkerrors.DSLDiagnostics(err)
+	assert.Equal(t, []sdkerrors.DSLDiagnostic{
+		{Line: 1, Column: 12, Message: "mismatched input 'x' expecting {'(', ':'}"},
+		{Line: 3, Column: 0, Message: "extraneous input ')' expecting <EOF>"},
+	}, diagnostics)
+	assert.Contains(t, err.Error(), "line 1:12: mismatched input 'x'")
+
+	var dslErr *sdkerrors.DSLSyntaxError
+	require.ErrorAs(t, err, &dslErr)
+	assert.Len(t, dslErr.Diagnostics, 2)
+}
+
+func TestErrorFromHTTPResponse_DSLSyntaxWithoutDiagnostics(t *testing.T) {
+	err := sdkerrors.ErrorFromHTTPResponse(http.StatusConflict, "req-1", "The script provided in your request is invalid.", "0017", "transaction", "")
+
+	assert.True(t, sdkerrors.IsDSLSyntaxError(err))
+	assert.Equal(t, sdkerrors.CategoryValidation, sdkerrors.GetErrorCategory(err))
+	assert.Nil(t, sdkerrors.DSLDiagnostics(err))
+	assert.Contains(t, err.Error(), "The script provided in your request is invalid.")
+}
+
+func TestErrorFromHTTPResponse_RouteViolation(t *testing.T) {
+	err := sdkerrors.ErrorFromHTTPResponse(http.StatusUnprocessableEntity, "req-1", "The operation alias '@a' does not match the expected alias '@b'.", "0118", "transaction", "")
+
+	assert.True(t, sdkerrors.IsRouteViolationError(err))
+	assert.False(t, sdkerrors.IsDSLSyntaxError(err))
+	require.ErrorIs(t, err, sdkerrors.ErrRouteViolation)
+}
+
+func TestErrorFromHTTPResponse_OtherCodes(t *testing.T) {
+	err := sdkerrors.ErrorFromHTTPResponse(http.StatusBadRequest, "req-1", "bad request", "0047", "", "")
+
+	assert.False(t, sdkerrors.IsDSLSyntaxError(err))
+	assert.False(t, sdkerrors.IsRouteViolationError(err))
+
+	var mdzErr *sdkerrors.Error
+	require.True(t, errors.As(err, &mdzErr))
+	assert.Equal(t, sdkerrors.CodeValidation, mdzErr.Code)
+}
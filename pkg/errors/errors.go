@@ -54,6 +54,12 @@ const (
 
 	// CodeNetwork indicates a network-related error
 	CodeNetwork ErrorCode = "network_error"
+
+	// CodeDSLSyntax indicates a DSL transaction that failed to compile
+	CodeDSLSyntax ErrorCode = "dsl_syntax_error"
+
+	// CodeRouteViolation indicates a transaction that violates its accounting routes
+	CodeRouteViolation ErrorCode = "route_violation"
 )
 
 // ErrorCategory represents the general category of an error
@@ -117,6 +123,8 @@ var (
 	ErrTimeout             = &Error{Category: CategoryTimeout, Code: CodeTimeout, Message: "timeout"}
 	ErrCancellation        = &Error{Category: CategoryCancellation, Code: CodeCancellation, Message: "operation cancelled"}
 	ErrInternal            = &Error{Category: CategoryInternal, Code: CodeInternal, Message: "internal error"}
+	ErrDSLSyntax           = &Error{Category: CategoryValidation, Code: CodeDSLSyntax, Message: "DSL syntax error"}
+	ErrRouteViolation      = &Error{Category: CategoryUnprocessable, Code: CodeRouteViolation, Message: "route violation"}
 )
 
 // Error represents a standardized error in the Midaz SDK.
@@ -1075,8 +1083,10 @@ var httpErrorMappings = map[int]httpErrorMapping{
 	http.StatusServiceUnavailable:  {CategoryNetwork, CodeInternal, false},
 }
 
-// ErrorFromHTTPResponse creates an appropriate error based on the HTTP response
-func ErrorFromHTTPResponse(statusCode int, requestID, message, _, entityType, resourceID string) error {
+// ErrorFromHTTPResponse creates an appropriate error based on the HTTP response.
+// The Midaz error code apiCode refines the error of DSL and route failures,
+// see CodeDSLSyntax and CodeRouteViolation.
+func ErrorFromHTTPResponse(statusCode int, requestID, message, apiCode, entityType, resourceID string) error {
 	mapping, ok := httpErrorMappings[statusCode]
 	if !ok {
 		mapping = httpErrorMapping{CategoryInternal, CodeInternal, false}
@@ -1095,6 +1105,8 @@ func ErrorFromHTTPResponse(statusCode int, requestID, message, _, entityType, re
 		err.ResourceID = resourceID
 	}
 
+	applyAPICode(err, apiCode)
+
 	return err
 }
 
@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"errors"
+)
+
+// CategorizedError is a member of a joined error with its category.
+type CategorizedError struct {
+	Err      error
+	Category ErrorCategory
+}
+
+// Join returns an error wrapping the non-nil errs, nil if there are none.
+// A single error is returned unchanged; otherwise the result is an
+// errors.Join error, so errors.Is and errors.As match each member.
+func Join(errs ...error) error {
+	var nonNil []error
+
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	default:
+		return errors.Join(nonNil...)
+	}
+}
+
+// Errors returns the members of err: the errors joined by errors.Join, Join
+// or any error with an Unwrap() []error method, flattened, including joined
+// errors wrapped with fmt.Errorf and %w. An error that joins nothing is its
+// own only member, and a nil error has none.
+//
+// Example:
+//
+//	for _, member := range errors.Errors(err) {
+//	    log.Printf("%s: %v", errors.GetErrorCategory(member), member)
+//	}
+func Errors(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		var members []error
+		for _, member := range e.Unwrap() {
+			members = append(members, Errors(member)...)
+		}
+
+		return members
+	case interface{ Unwrap() error }:
+		// A wrapped joined error is enumerated through the wrapper
+		if members := Errors(e.Unwrap()); len(members) > 1 {
+			return members
+		}
+	}
+
+	return []error{err}
+}
+
+// CategorizedErrors returns the members of err, as returned by Errors, with
+// their category.
+func CategorizedErrors(err error) []CategorizedError {
+	members := Errors(err)
+	out := make([]CategorizedError, len(members))
+
+	for i, member := range members {
+		out[i] = CategorizedError{Err: member, Category: GetErrorCategory(member)}
+	}
+
+	return out
+}
@@ -0,0 +1,66 @@
+package errors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoin(t *testing.T) {
+	notFound := sdkerrors.NewNotFoundError("GetLedger", "ledger", "ledger-1", nil)
+	invalid := sdkerrors.NewValidationError("CreateAccount", "alias is required", nil)
+
+	assert.NoError(t, sdkerrors.Join())
+	assert.NoError(t, sdkerrors.Join(nil, nil))
+	assert.Same(t, notFound, sdkerrors.Join(nil, notFound))
+
+	err := sdkerrors.Join(notFound, nil, invalid)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, notFound)
+	assert.ErrorIs(t, err, invalid)
+
+	var target *sdkerrors.Error
+	require.ErrorAs(t, err, &target)
+	assert.Same(t, notFound, target)
+}
+
+func TestErrors(t *testing.T) {
+	notFound := sdkerrors.NewNotFoundError("GetLedger", "ledger", "ledger-1", nil)
+	invalid := sdkerrors.NewValidationError("CreateAccount", "alias is required", nil)
+	timeout := sdkerrors.NewTimeoutError("CreateAsset", "deadline exceeded", nil)
+	wrapped := fmt.Errorf("step: %w", invalid)
+
+	tests := []struct {
+		name string
+		err  error
+		want []error
+	}{
+		{name: "nil", err: nil, want: nil},
+		{name: "single error", err: invalid, want: []error{invalid}},
+		{name: "wrapped single error is kept whole", err: wrapped, want: []error{wrapped}},
+		{name: "joined", err: errors.Join(notFound, invalid), want: []error{notFound, invalid}},
+		{name: "nested and wrapped", err: fmt.Errorf("provision: %w", errors.Join(notFound, errors.Join(invalid, timeout))), want: []error{notFound, invalid, timeout}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, sdkerrors.Errors(tc.err))
+		})
+	}
+}
+
+func TestCategorizedErrors(t *testing.T) {
+	err := sdkerrors.Join(
+		sdkerrors.NewNotFoundError("GetLedger", "ledger", "ledger-1", nil),
+		sdkerrors.NewRateLimitError("CreateAccount", "too many requests", nil),
+	)
+
+	members := sdkerrors.CategorizedErrors(err)
+	require.Len(t, members, 2)
+	assert.Equal(t, sdkerrors.CategoryNotFound, members[0].Category)
+	assert.Equal(t, sdkerrors.CategoryLimitExceeded, members[1].Category)
+}
@@ -0,0 +1,175 @@
+// Package eventlog appends structured NDJSON events, one per created entity or
+// transaction, to a writer as they happen.
+//
+// Unlike end-of-run reports, the log is written incrementally: each event is a
+// single write of one JSON line, so it can be tailed in real time (tail -f, jq)
+// and survives a crash of the process before report generation.
+//
+// The log travels in the context, so the generator and batch subsystems pick it
+// up without changes to their signatures:
+//
+//	f, _ := os.Create("events.ndjson")
+//	ctx = eventlog.WithLog(ctx, eventlog.New(f))
+//
+//	orgs, err := generator.NewOrganizationGenerator(e, obs).GenerateBatch(ctx, 10)
+//	results, err := transaction.BatchTransactions(ctx, c, orgID, ledgerID, inputs, nil)
+package eventlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Entity types of events.
+const (
+	EntityOrganization     = "organization"
+	EntityLedger           = "ledger"
+	EntityAsset            = "asset"
+	EntityAccountType      = "account_type"
+	EntityAccount          = "account"
+	EntityPortfolio        = "portfolio"
+	EntitySegment          = "segment"
+	EntityOperationRoute   = "operation_route"
+	EntityTransactionRoute = "transaction_route"
+	EntityTransaction      = "transaction"
+)
+
+// Outcomes of events.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Event records the creation of an entity or transaction.
+type Event struct {
+	Time           time.Time      `json:"time"`
+	Source         string         `json:"source"`
+	Entity         string         `json:"entity"`
+	ID             string         `json:"id,omitempty"`
+	OrganizationID string         `json:"organizationId,omitempty"`
+	LedgerID       string         `json:"ledgerId,omitempty"`
+	StartedAt      time.Time      `json:"startedAt"`
+	DurationMs     float64        `json:"durationMs"`
+	Outcome        string         `json:"outcome"`
+	Error          string         `json:"error,omitempty"`
+	Attributes     map[string]any `json:"attributes,omitempty"`
+}
+
+// Log writes events to a writer as NDJSON. It is safe for concurrent use.
+type Log struct {
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+	now func() time.Time
+}
+
+// New creates a Log writing to w. Events are written unbuffered; wrap w in a
+// bufio.Writer only if losing the last events on a crash is acceptable.
+func New(w io.Writer) *Log {
+	return &Log{w: w, now: time.Now}
+}
+
+// Record appends an event. Time defaults to now, Outcome to success unless
+// Error is set. After the first write error, events are dropped and Err
+// reports the error.
+func (l *Log) Record(ev Event) error {
+	if l == nil {
+		return nil
+	}
+
+	if ev.Time.IsZero() {
+		ev.Time = l.now().UTC()
+	}
+
+	if ev.Outcome == "" {
+		ev.Outcome = OutcomeSuccess
+		if ev.Error != "" {
+			ev.Outcome = OutcomeFailure
+		}
+	}
+
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err != nil {
+		return l.err
+	}
+
+	if _, err := l.w.Write(line); err != nil {
+		l.err = fmt.Errorf("failed to write event: %w", err)
+		return l.err
+	}
+
+	return nil
+}
+
+// Err returns the first write error of the log, if any.
+func (l *Log) Err() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.err
+}
+
+type contextKeyLog struct{}
+
+// WithLog returns a context carrying l.
+func WithLog(ctx context.Context, l *Log) context.Context {
+	if l == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, contextKeyLog{}, l)
+}
+
+// FromContext returns the log carried by ctx, or nil.
+func FromContext(ctx context.Context) *Log {
+	l, _ := ctx.Value(contextKeyLog{}).(*Log) //nolint:errcheck // a missing log is reported as nil
+
+	return l
+}
+
+// Record appends ev to the log carried by ctx, if any. Write errors are kept
+// by the log rather than failing the operation being recorded.
+func Record(ctx context.Context, ev Event) {
+	_ = FromContext(ctx).Record(ev) //nolint:errcheck // reported by Log.Err
+}
+
+// Done records the outcome of an operation started at started.
+func Done(ctx context.Context, source, entity, id, orgID, ledgerID string, started time.Time, err error) {
+	l := FromContext(ctx)
+	if l == nil {
+		return
+	}
+
+	ev := Event{
+		Source:         source,
+		Entity:         entity,
+		ID:             id,
+		OrganizationID: orgID,
+		LedgerID:       ledgerID,
+		StartedAt:      started.UTC(),
+		DurationMs:     float64(l.now().Sub(started).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		ev.Error = err.Error()
+	}
+
+	_ = l.Record(ev) //nolint:errcheck // reported by Log.Err
+}
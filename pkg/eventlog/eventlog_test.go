@@ -0,0 +1,116 @@
+package eventlog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) []Event {
+	t.Helper()
+
+	var events []Event
+
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var ev Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev))
+
+		events = append(events, ev)
+	}
+
+	return events
+}
+
+func TestLogRecord(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(&buf)
+	l.now = func() time.Time { return time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	require.NoError(t, l.Record(Event{Source: "test", Entity: EntityAccount, ID: "a1"}))
+	require.NoError(t, l.Record(Event{Source: "test", Entity: EntityAccount, Error: "boom"}))
+
+	events := decode(t, &buf)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "a1", events[0].ID)
+	assert.Equal(t, OutcomeSuccess, events[0].Outcome)
+	assert.Equal(t, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), events[0].Time)
+	assert.Equal(t, OutcomeFailure, events[1].Outcome)
+	assert.Equal(t, "boom", events[1].Error)
+}
+
+func TestLogConcurrentWritesKeepLinesIntact(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(&buf)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_ = l.Record(Event{Entity: EntityTransaction, Attributes: map[string]any{"payload": "xxxxxxxxxxxxxxxxxxxxxxxx"}})
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Len(t, decode(t, &buf), 50)
+}
+
+type failingWriter struct{ writes int }
+
+func (w *failingWriter) Write([]byte) (int, error) {
+	w.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestLogKeepsFirstWriteError(t *testing.T) {
+	w := &failingWriter{}
+	l := New(w)
+
+	require.Error(t, l.Record(Event{}))
+	require.Error(t, l.Record(Event{}))
+
+	assert.Equal(t, 1, w.writes, "events are dropped after a write error")
+	assert.ErrorContains(t, l.Err(), "disk full")
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Nil(t, FromContext(ctx))
+	assert.NotPanics(t, func() {
+		Record(ctx, Event{})
+		Done(ctx, "test", EntityLedger, "l1", "o1", "", time.Now(), nil)
+	})
+
+	var buf bytes.Buffer
+
+	ctx = WithLog(ctx, New(&buf))
+	Done(ctx, "test", EntityLedger, "", "o1", "", time.Now().Add(-time.Second), errors.New("conflict"))
+
+	events := decode(t, &buf)
+	require.Len(t, events, 1)
+	assert.Equal(t, EntityLedger, events[0].Entity)
+	assert.Equal(t, "o1", events[0].OrganizationID)
+	assert.Equal(t, OutcomeFailure, events[0].Outcome)
+	assert.GreaterOrEqual(t, events[0].DurationMs, 1000.0)
+
+	var nilLog *Log
+	assert.NoError(t, nilLog.Record(Event{}))
+	assert.NoError(t, nilLog.Err())
+}
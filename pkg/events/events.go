@@ -0,0 +1,138 @@
+// Package events delivers SDK lifecycle events to the people operating an
+// integration.
+//
+// Some SDK components can report noteworthy conditions as events: a circuit
+// breaker opening, a retry giving up, an integrity check finding drift, a batch
+// finishing. An event is sent to a Sink, which can post it to a Slack channel,
+// to any webhook or to a log.
+//
+// Example:
+//
+//	sink := events.Multi(
+//	    events.NewLogSink(log.Default()),
+//	    events.Only(events.NewSlackSink(os.Getenv("SLACK_WEBHOOK_URL")),
+//	        events.CircuitBreakerOpened, events.IntegrityDrift),
+//	)
+//
+//	cb := concurrent.NewCircuitBreakerNamed("midaz", 5, 2, 30*time.Second).WithEvents(sink)
+//	checker := integrity.NewChecker(entity).WithEvents(sink)
+//	err := retry.Do(ctx, fn, retry.WithEvents(sink))
+//
+// The package only depends on the standard library so that any SDK package can
+// emit events.
+package events
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// Type identifies the kind of an event.
+type Type string
+
+// Event types emitted by the SDK.
+const (
+	// CircuitBreakerOpened is emitted when a circuit breaker opens (see concurrent.CircuitBreaker)
+	CircuitBreakerOpened Type = "circuit_breaker.opened"
+
+	// RetryExhausted is emitted when retry.Do gives up on a failing operation
+	RetryExhausted Type = "retry.exhausted"
+
+	// IntegrityDrift is emitted when an integrity report finds overdrawn accounts
+	// or failed rules (see integrity.Checker)
+	IntegrityDrift Type = "integrity.drift"
+
+	// BatchFinished is emitted when a transaction batch completes (see transaction.BatchOptions)
+	BatchFinished Type = "batch.finished"
+)
+
+// Severity tells how urgent an event is.
+type Severity string
+
+// Event severities.
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Event is a noteworthy condition reported by the SDK.
+type Event struct {
+	Type     Type      `json:"type"`
+	Severity Severity  `json:"severity"`
+	Time     time.Time `json:"time"`
+
+	// Source names what the event is about, such as a circuit breaker name or a ledger ID
+	Source  string `json:"source,omitempty"`
+	Message string `json:"message"`
+
+	// Attributes holds the details of the event, such as counts or the last error
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// Sink receives events.
+type Sink interface {
+	// Send delivers the event. It should return once the event is delivered
+	// or ctx is done.
+	Send(ctx context.Context, e Event) error
+}
+
+// SinkFunc adapts a function to the Sink interface.
+type SinkFunc func(ctx context.Context, e Event) error
+
+// Send calls f.
+func (f SinkFunc) Send(ctx context.Context, e Event) error {
+	return f(ctx, e)
+}
+
+// Emit sends the event to sink, setting its time when it is zero. It is a
+// no-op for a nil sink.
+//
+// Delivery is best effort: errors of the sink are dropped so that a failing
+// notification never fails the operation reporting it. Wrap the sink in a
+// SinkFunc to observe them.
+func Emit(ctx context.Context, sink Sink, e Event) {
+	if sink == nil {
+		return
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	_ = sink.Send(ctx, e)
+}
+
+// Multi returns a sink sending every event to each of the sinks, in order. It
+// returns the errors of all the sinks that failed.
+func Multi(sinks ...Sink) Sink {
+	return SinkFunc(func(ctx context.Context, e Event) error {
+		var errs []error
+
+		for _, s := range sinks {
+			if s == nil {
+				continue
+			}
+
+			if err := s.Send(ctx, e); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		return errors.Join(errs...)
+	})
+}
+
+// Only returns a sink forwarding the events of the given types to sink and
+// dropping the others.
+func Only(sink Sink, types ...Type) Sink {
+	return SinkFunc(func(ctx context.Context, e Event) error {
+		if !slices.Contains(types, e.Type) {
+			return nil
+		}
+
+		return sink.Send(ctx, e)
+	})
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var opened = Event{
+	Type:       CircuitBreakerOpened,
+	Severity:   SeverityError,
+	Time:       time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+	Source:     "midaz",
+	Message:    "circuit 'midaz' opened after 5 failures",
+	Attributes: map[string]any{"failures": 5},
+}
+
+func TestWebhookSink(t *testing.T) {
+	var (
+		received Event
+		auth     string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL).WithHeader("Authorization", "Bearer token")
+	require.NoError(t, sink.Send(context.Background(), opened))
+
+	assert.Equal(t, "Bearer token", auth)
+	assert.Equal(t, opened.Type, received.Type)
+	assert.Equal(t, opened.Time, received.Time)
+	assert.InDelta(t, 5, received.Attributes["failures"], 0)
+}
+
+func TestWebhookSinkStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := NewWebhookSink(server.URL).Send(context.Background(), opened)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "502")
+}
+
+func TestSlackSink(t *testing.T) {
+	var payload map[string]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+	}))
+	defer server.Close()
+
+	require.NoError(t, NewSlackSink(server.URL).Send(context.Background(), opened))
+
+	assert.Equal(t, "*[error] circuit_breaker.opened* `midaz`\ncircuit 'midaz' opened after 5 failures\n• failures: 5", payload["text"])
+}
+
+func TestLogSink(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, NewLogSink(log.New(&buf, "", 0)).Send(context.Background(), opened))
+
+	assert.Equal(t, "event circuit_breaker.opened severity=error source=\"midaz\" failures=5: circuit 'midaz' opened after 5 failures\n", buf.String())
+}
+
+func TestEmit(t *testing.T) {
+	var received []Event
+
+	record := SinkFunc(func(_ context.Context, e Event) error {
+		received = append(received, e)
+		return nil
+	})
+	failing := SinkFunc(func(context.Context, Event) error { return errors.New("unreachable") })
+
+	sink := Multi(failing, Only(record, BatchFinished), nil)
+
+	Emit(context.Background(), sink, opened)
+	Emit(context.Background(), sink, Event{Type: BatchFinished, Message: "done"})
+	Emit(context.Background(), nil, opened)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, BatchFinished, received[0].Type)
+	assert.False(t, received[0].Time.IsZero())
+
+	assert.EqualError(t, sink.Send(context.Background(), opened), "unreachable")
+}
@@ -0,0 +1,161 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds the delivery of an event by the webhook sinks when no
+// HTTP client is configured.
+const DefaultTimeout = 10 * time.Second
+
+// WebhookSink posts each event as JSON to a URL.
+type WebhookSink struct {
+	url     string
+	client  *http.Client
+	headers http.Header
+}
+
+// NewWebhookSink returns a sink posting events as JSON to url.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{url: url, client: &http.Client{Timeout: DefaultTimeout}, headers: http.Header{}}
+}
+
+// WithHTTPClient sets the HTTP client used to post events.
+func (s *WebhookSink) WithHTTPClient(c *http.Client) *WebhookSink {
+	if c != nil {
+		s.client = c
+	}
+
+	return s
+}
+
+// WithHeader adds a header to every request, such as an authorization token.
+func (s *WebhookSink) WithHeader(key, value string) *WebhookSink {
+	s.headers.Add(key, value)
+	return s
+}
+
+// Send posts the event. Any status outside 2xx is an error.
+func (s *WebhookSink) Send(ctx context.Context, e Event) error {
+	return post(ctx, s.client, s.url, s.headers, e)
+}
+
+// SlackSink posts each event as a message to a Slack incoming webhook.
+type SlackSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewSlackSink returns a sink posting events to a Slack incoming webhook URL.
+func NewSlackSink(webhookURL string) *SlackSink {
+	return &SlackSink{url: webhookURL, client: &http.Client{Timeout: DefaultTimeout}}
+}
+
+// WithHTTPClient sets the HTTP client used to post events.
+func (s *SlackSink) WithHTTPClient(c *http.Client) *SlackSink {
+	if c != nil {
+		s.client = c
+	}
+
+	return s
+}
+
+// Send posts the event as a Slack message.
+func (s *SlackSink) Send(ctx context.Context, e Event) error {
+	return post(ctx, s.client, s.url, nil, map[string]string{"text": slackText(e)})
+}
+
+// slackText formats an event as Slack mrkdwn.
+func slackText(e Event) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "*[%s] %s*", e.Severity, e.Type)
+
+	if e.Source != "" {
+		fmt.Fprintf(&b, " `%s`", e.Source)
+	}
+
+	b.WriteString("\n" + e.Message)
+
+	for _, k := range slices.Sorted(maps.Keys(e.Attributes)) {
+		fmt.Fprintf(&b, "\n• %s: %v", k, e.Attributes[k])
+	}
+
+	return b.String()
+}
+
+// Logger is the minimal logger used by LogSink, satisfied by log.Logger.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// LogSink writes each event as a line to a logger.
+type LogSink struct {
+	logger Logger
+}
+
+// NewLogSink returns a sink writing events to logger.
+func NewLogSink(logger Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+// Send logs the event. It never fails.
+func (s *LogSink) Send(_ context.Context, e Event) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "event %s severity=%s", e.Type, e.Severity)
+
+	if e.Source != "" {
+		fmt.Fprintf(&b, " source=%q", e.Source)
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(e.Attributes)) {
+		fmt.Fprintf(&b, " %s=%v", k, e.Attributes[k])
+	}
+
+	s.logger.Printf("%s: %s", b.String(), e.Message)
+
+	return nil
+}
+
+// post sends body as JSON to url.
+func post(ctx context.Context, client *http.Client, url string, headers http.Header, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create event request: %w", err)
+	}
+
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("event endpoint returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,301 @@
+package export
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/shopspring/decimal"
+)
+
+// ActivityOptions configures an activity heatmap.
+type ActivityOptions struct {
+	// Start and End bound the creation time of the transactions counted (End
+	// exclusive). Both are required.
+	Start time.Time
+	End   time.Time
+
+	// Location is the time zone of the hours of the heatmap
+	// Default is UTC
+	Location *time.Location
+
+	// Top keeps the given number of most active accounts
+	// Default is 0 (every account)
+	Top int
+}
+
+// ActivityRow is the hourly activity of an account in an asset.
+type ActivityRow struct {
+	Account string `json:"account"`
+	Asset   string `json:"asset"`
+
+	// Counts and Volumes hold, for each hour of the heatmap, the number of
+	// transactions moving the account and the amount they moved, debits and
+	// credits alike
+	Counts  []int             `json:"counts"`
+	Volumes []decimal.Decimal `json:"volumes"`
+
+	TotalCount  int             `json:"totalCount"`
+	TotalVolume decimal.Decimal `json:"totalVolume"`
+}
+
+// ActivityHeatmap holds the activity of the accounts of a ledger hour by hour,
+// for capacity planning and finding hot accounts.
+type ActivityHeatmap struct {
+	OrganizationID string    `json:"organizationId"`
+	LedgerID       string    `json:"ledgerId"`
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+
+	// Hours are the starts of the hours of the heatmap, the columns of its
+	// matrices
+	Hours []time.Time `json:"hours"`
+
+	// Transactions counts the transactions of the range, and HourTotals the
+	// transactions of each hour
+	Transactions int   `json:"transactions"`
+	HourTotals   []int `json:"hourTotals"`
+
+	// Accounts are the rows of the heatmap, most active first
+	Accounts []ActivityRow `json:"accounts"`
+}
+
+// activityKey identifies a row of the heatmap.
+type activityKey struct {
+	account, asset string
+}
+
+// activityBuilder accumulates transactions into a heatmap.
+type activityBuilder struct {
+	heatmap  *ActivityHeatmap
+	location *time.Location
+	rows     map[activityKey]*ActivityRow
+}
+
+// newActivityBuilder returns a builder of the heatmap of the range of opts.
+func newActivityBuilder(opts ActivityOptions) (*activityBuilder, error) {
+	if opts.Start.IsZero() || opts.End.IsZero() || !opts.End.After(opts.Start) {
+		return nil, errors.New("a time range with start before end is required")
+	}
+
+	location := opts.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	h := &ActivityHeatmap{Start: opts.Start, End: opts.End}
+	for hour := startOfHour(opts.Start, location); hour.Before(opts.End); hour = hour.Add(time.Hour) {
+		h.Hours = append(h.Hours, hour)
+	}
+
+	h.HourTotals = make([]int, len(h.Hours))
+
+	return &activityBuilder{heatmap: h, location: location, rows: map[activityKey]*ActivityRow{}}, nil
+}
+
+// startOfHour returns the start of the hour of t in location.
+func startOfHour(t time.Time, location *time.Location) time.Time {
+	local := t.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, location)
+}
+
+// add counts tx, when created in the range of the heatmap.
+func (b *activityBuilder) add(tx models.Transaction) {
+	h := b.heatmap
+	if tx.DeletedAt != nil || tx.CreatedAt.Before(h.Start) || !tx.CreatedAt.Before(h.End) {
+		return
+	}
+
+	hour := int(startOfHour(tx.CreatedAt, b.location).Sub(h.Hours[0]) / time.Hour)
+	if hour < 0 || hour >= len(h.Hours) {
+		return
+	}
+
+	h.Transactions++
+	h.HourTotals[hour]++
+
+	for key, amount := range transactionLegs(tx) {
+		row, ok := b.rows[key]
+		if !ok {
+			row = &ActivityRow{Account: key.account, Asset: key.asset, Counts: make([]int, len(h.Hours)), Volumes: make([]decimal.Decimal, len(h.Hours))}
+			b.rows[key] = row
+		}
+
+		row.Counts[hour]++
+		row.Volumes[hour] = row.Volumes[hour].Add(amount)
+		row.TotalCount++
+		row.TotalVolume = row.TotalVolume.Add(amount)
+	}
+}
+
+// transactionLegs returns the amount tx moved per account and asset, from its
+// operations, or from its source and destination aliases and its amount when
+// it carries no operations.
+func transactionLegs(tx models.Transaction) map[activityKey]decimal.Decimal {
+	legs := map[activityKey]decimal.Decimal{}
+
+	for _, op := range tx.Operations {
+		account := op.AccountAlias
+		if account == "" {
+			account = op.AccountID
+		}
+
+		amount := decimal.Zero
+		if op.Amount.Value != nil {
+			amount = *op.Amount.Value
+		}
+
+		key := activityKey{account: account, asset: op.AssetCode}
+		legs[key] = legs[key].Add(amount)
+	}
+
+	if len(tx.Operations) > 0 {
+		return legs
+	}
+
+	amount, err := decimal.NewFromString(tx.Amount)
+	if err != nil {
+		amount = decimal.Zero
+	}
+
+	for _, account := range slices.Concat(tx.Source, tx.Destination) {
+		legs[activityKey{account: account, asset: tx.AssetCode}] = amount
+	}
+
+	return legs
+}
+
+// build returns the heatmap, most active accounts first, keeping the top ones.
+func (b *activityBuilder) build(top int) *ActivityHeatmap {
+	h := b.heatmap
+
+	h.Accounts = make([]ActivityRow, 0, len(b.rows))
+	for _, row := range b.rows {
+		h.Accounts = append(h.Accounts, *row)
+	}
+
+	slices.SortFunc(h.Accounts, func(x, y ActivityRow) int {
+		if c := cmp.Compare(y.TotalCount, x.TotalCount); c != 0 {
+			return c
+		}
+
+		if c := y.TotalVolume.Cmp(x.TotalVolume); c != 0 {
+			return c
+		}
+
+		return cmp.Or(cmp.Compare(x.Account, y.Account), cmp.Compare(x.Asset, y.Asset))
+	})
+
+	if top > 0 && len(h.Accounts) > top {
+		h.Accounts = h.Accounts[:top]
+	}
+
+	return h
+}
+
+// BuildActivityHeatmap returns the hourly activity of the accounts moved by
+// txs between opts.Start and opts.End, from transactions of any source such
+// as an export. An account is counted once per transaction moving it, with
+// the amount of its operations, or the amount of the transaction when it
+// carries none. Deleted transactions are skipped.
+func BuildActivityHeatmap(txs []models.Transaction, opts ActivityOptions) (*ActivityHeatmap, error) {
+	b, err := newActivityBuilder(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tx := range txs {
+		b.add(tx)
+	}
+
+	return b.build(opts.Top), nil
+}
+
+// ActivityHeatmap lists the transactions of a ledger created between
+// opts.Start and opts.End and returns the hourly activity of their accounts,
+// see BuildActivityHeatmap. Transactions are streamed page by page.
+//
+// Example of the busiest accounts of a week, for a heatmap in a notebook:
+//
+//	heatmap, err := export.NewExporter(client.Entity).ActivityHeatmap(ctx, orgID, ledgerID, export.ActivityOptions{
+//	    Start: weekStart,
+//	    End:   weekStart.AddDate(0, 0, 7),
+//	    Top:   50,
+//	})
+//	if err == nil {
+//	    err = heatmap.WriteCSV(f)
+//	}
+func (x *Exporter) ActivityHeatmap(ctx context.Context, orgID, ledgerID string, opts ActivityOptions) (*ActivityHeatmap, error) {
+	if x.e == nil || x.e.Transactions == nil {
+		return nil, errors.New("transactions service not initialized")
+	}
+
+	b, err := newActivityBuilder(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// the API filters by date, the exact bounds are applied by the builder
+	startDate, endDate := opts.Start.UTC().Format(time.DateOnly), opts.End.UTC().Format(time.DateOnly)
+
+	err = observability.WithSpan(ctx, x.obs, "ActivityHeatmap", func(ctx context.Context) error {
+		return paginate(ctx, x.pageSize, func(page *models.ListOptions) (*models.ListResponse[models.Transaction], error) {
+			return x.e.Transactions.ListTransactions(ctx, orgID, ledgerID, page.WithDateRange(startDate, endDate).WithOrderDirection(models.SortAscending))
+		}, func(tx models.Transaction) error {
+			b.add(tx)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	h := b.build(opts.Top)
+	h.OrganizationID, h.LedgerID = orgID, ledgerID
+
+	return h, nil
+}
+
+// WriteJSON writes the heatmap as indented JSON, its matrices as one array
+// per account.
+func (h *ActivityHeatmap) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(h)
+}
+
+// WriteCSV writes the heatmap as CSV in long form, one line per account,
+// asset and hour with activity: account, asset_code, hour (RFC 3339), count
+// and volume.
+func (h *ActivityHeatmap) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"account", "asset_code", "hour", "count", "volume"}); err != nil {
+		return err
+	}
+
+	for _, row := range h.Accounts {
+		for i, count := range row.Counts {
+			if count == 0 {
+				continue
+			}
+
+			if err := cw.Write([]string{row.Account, row.Asset, h.Hours[i].Format(time.RFC3339), strconv.Itoa(count), row.Volumes[i].String()}); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
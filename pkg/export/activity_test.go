@@ -0,0 +1,111 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func activityTransfer(id string, at time.Time, ops ...models.Operation) models.Transaction {
+	return models.Transaction{ID: id, AssetCode: "USD", Amount: "10", Source: []string{"@alice"}, Destination: []string{"@bob"}, Operations: ops, CreatedAt: at}
+}
+
+func TestBuildActivityHeatmap(t *testing.T) {
+	start := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+	deleted := start
+
+	txs := []models.Transaction{
+		activityTransfer("tx-1", start, operation("DEBIT", "@alice", "100"), operation("CREDIT", "@bob", "60"), operation("CREDIT", "@carol", "40")),
+		activityTransfer("tx-2", start.Add(10*time.Minute)),
+		activityTransfer("tx-3", start.Add(2*time.Hour)),
+		activityTransfer("tx-4", start.Add(-time.Minute)),
+		activityTransfer("tx-5", start.Add(3*time.Hour)),
+		activityTransfer("tx-6", start.Add(time.Hour)),
+	}
+	txs[5].DeletedAt = &deleted
+
+	heatmap, err := BuildActivityHeatmap(txs, ActivityOptions{Start: start, End: start.Add(3 * time.Hour)})
+	require.NoError(t, err)
+
+	require.Len(t, heatmap.Hours, 4, "from the hour of the start to the hour of the end")
+	assert.Equal(t, time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC), heatmap.Hours[0])
+	assert.Equal(t, 3, heatmap.Transactions, "out of range and deleted transactions are skipped")
+	assert.Equal(t, []int{2, 0, 1, 0}, heatmap.HourTotals)
+
+	require.Len(t, heatmap.Accounts, 3)
+
+	alice := heatmap.Accounts[0]
+	assert.Equal(t, "@alice", alice.Account)
+	assert.Equal(t, "USD", alice.Asset)
+	assert.Equal(t, []int{2, 0, 1, 0}, alice.Counts)
+	assert.Equal(t, "110", alice.Volumes[0].String())
+	assert.Equal(t, 3, alice.TotalCount)
+	assert.Equal(t, "120", alice.TotalVolume.String())
+
+	assert.Equal(t, "@bob", heatmap.Accounts[1].Account, "ties are broken by volume")
+	assert.Equal(t, "@carol", heatmap.Accounts[2].Account)
+
+	top, err := BuildActivityHeatmap(txs, ActivityOptions{Start: start, End: start.Add(3 * time.Hour), Top: 1})
+	require.NoError(t, err)
+	require.Len(t, top.Accounts, 1)
+	assert.Equal(t, "@alice", top.Accounts[0].Account)
+
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+	local, err := BuildActivityHeatmap(txs, ActivityOptions{Start: start, End: start.Add(time.Hour), Location: saoPaulo})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 6, 1, 6, 0, 0, 0, saoPaulo), local.Hours[0])
+
+	_, err = BuildActivityHeatmap(txs, ActivityOptions{Start: start})
+	assert.Error(t, err)
+}
+
+func TestActivityHeatmapWrite(t *testing.T) {
+	start := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	heatmap, err := BuildActivityHeatmap([]models.Transaction{
+		activityTransfer("tx-1", start.Add(time.Minute)),
+		activityTransfer("tx-2", start.Add(time.Hour)),
+	}, ActivityOptions{Start: start, End: start.Add(2 * time.Hour)})
+	require.NoError(t, err)
+
+	var csv bytes.Buffer
+	require.NoError(t, heatmap.WriteCSV(&csv))
+	assert.Equal(t, "account,asset_code,hour,count,volume\n"+
+		"@alice,USD,2025-06-01T09:00:00Z,1,10\n"+
+		"@alice,USD,2025-06-01T10:00:00Z,1,10\n"+
+		"@bob,USD,2025-06-01T09:00:00Z,1,10\n"+
+		"@bob,USD,2025-06-01T10:00:00Z,1,10\n", csv.String())
+
+	var out bytes.Buffer
+	require.NoError(t, heatmap.WriteJSON(&out))
+
+	var decoded ActivityHeatmap
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, heatmap.HourTotals, decoded.HourTotals)
+	assert.Equal(t, []int{1, 1}, decoded.Accounts[0].Counts)
+	assert.Equal(t, "10", decoded.Accounts[0].Volumes[1].String())
+}
+
+func TestExporterActivityHeatmap(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	fake := &fakeMidaz{}
+
+	for i := range 5 {
+		fake.transactions = append(fake.transactions, activityTransfer("tx", start.Add(time.Duration(i)*time.Hour)))
+	}
+
+	heatmap, err := NewExporter(fake.entity()).WithPageSize(2).ActivityHeatmap(context.Background(), "org", "ledger", ActivityOptions{Start: start, End: start.Add(24 * time.Hour)})
+	require.NoError(t, err)
+
+	assert.Equal(t, "org", heatmap.OrganizationID)
+	assert.Equal(t, "ledger", heatmap.LedgerID)
+	assert.Len(t, heatmap.Hours, 24)
+	assert.Equal(t, 5, heatmap.Transactions, "every page is read")
+	assert.Equal(t, 5, heatmap.Accounts[0].TotalCount)
+}
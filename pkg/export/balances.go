@@ -0,0 +1,354 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/shopspring/decimal"
+)
+
+// KindBalance identifies the records of a balance snapshot.
+const KindBalance Kind = "balances"
+
+// BalanceSnapshotFile is the name of the snapshot description written next to
+// the balance records.
+const BalanceSnapshotFile = "balance_snapshot.json"
+
+const (
+	defaultSnapshotWorkers  = 8
+	defaultSnapshotRechecks = 3
+)
+
+// BalanceTotal aggregates the balances of an asset.
+type BalanceTotal struct {
+	Balances  int             `json:"balances"`
+	Available decimal.Decimal `json:"available"`
+	OnHold    decimal.Decimal `json:"onHold"`
+}
+
+// BalanceSnapshot holds the balances of every account of a ledger, captured as
+// close to a point in time as the API allows.
+//
+// Balances are read between TakenAt and CompletedAt. Balances updated after
+// TakenAt are read again until two consecutive reads return the same version;
+// accounts still moving after the last recheck are listed in Unsettled and make
+// the snapshot inconsistent.
+type BalanceSnapshot struct {
+	SchemaVersion  int                     `json:"schemaVersion"`
+	OrganizationID string                  `json:"organizationId"`
+	LedgerID       string                  `json:"ledgerId"`
+	TakenAt        time.Time               `json:"takenAt"`
+	CompletedAt    time.Time               `json:"completedAt"`
+	Consistent     bool                    `json:"consistent"`
+	Accounts       int                     `json:"accounts"`
+	Rechecked      int                     `json:"rechecked"`
+	Unsettled      []string                `json:"unsettled,omitempty"`
+	Totals         map[string]BalanceTotal `json:"totals"`
+	Format         Format                  `json:"format,omitempty"`
+	File           string                  `json:"file,omitempty"`
+
+	// Balances are written to the snapshot file, not to its description
+	Balances []models.Balance `json:"-"`
+}
+
+// Write writes the balances to dir as KindBalance records in format, together
+// with the snapshot description in BalanceSnapshotFile.
+func (s *BalanceSnapshot) Write(dir string, format Format) error {
+	return s.WriteFS(fsutil.Dir(dir), format)
+}
+
+// WriteFS is Write writing the files at the root of fsys.
+func (s *BalanceSnapshot) WriteFS(fsys fsutil.FS, format Format) error {
+	name := fileName(KindBalance, format)
+
+	w, err := CreateRecordWriter(fsys, name, format)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range s.Balances {
+		data, err := json.Marshal(b)
+		if err != nil {
+			_ = w.Close()
+			return fmt.Errorf("failed to encode balance %s: %w", b.ID, err)
+		}
+
+		rec := Record{SchemaVersion: SchemaVersion, Kind: KindBalance, ID: b.ID, LedgerID: s.LedgerID, Data: data}
+		if err := w.Write(rec); err != nil {
+			_ = w.Close()
+			return fmt.Errorf("failed to write balance %s: %w", b.ID, err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	s.Format = format
+	s.File = name
+
+	raw, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode balance snapshot: %w", err)
+	}
+
+	if err := fsutil.WriteFile(fsys, BalanceSnapshotFile, raw); err != nil {
+		return fmt.Errorf("failed to write balance snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// BalanceSnapshotter captures the balances of a ledger for end-of-day processing.
+type BalanceSnapshotter struct {
+	e        *entities.Entity
+	workers  int
+	rechecks int
+	pageSize int
+	obs      observability.Provider
+	now      func() time.Time
+}
+
+// NewBalanceSnapshotter creates a BalanceSnapshotter fetching 8 accounts in
+// parallel and rechecking moving balances up to 3 times.
+func NewBalanceSnapshotter(e *entities.Entity) *BalanceSnapshotter {
+	return &BalanceSnapshotter{
+		e:        e,
+		workers:  defaultSnapshotWorkers,
+		rechecks: defaultSnapshotRechecks,
+		pageSize: models.MaxLimit,
+		now:      time.Now,
+	}
+}
+
+// WithWorkers sets the number of accounts whose balances are fetched in parallel.
+func (s *BalanceSnapshotter) WithWorkers(workers int) *BalanceSnapshotter {
+	if workers > 0 {
+		s.workers = workers
+	}
+
+	return s
+}
+
+// WithMaxRechecks sets how many times balances updated during the capture are
+// read again. Zero disables rechecks.
+func (s *BalanceSnapshotter) WithMaxRechecks(rechecks int) *BalanceSnapshotter {
+	if rechecks >= 0 {
+		s.rechecks = rechecks
+	}
+
+	return s
+}
+
+// WithPageSize sets the number of items requested per list call, capped at models.MaxLimit.
+func (s *BalanceSnapshotter) WithPageSize(size int) *BalanceSnapshotter {
+	if size > 0 {
+		s.pageSize = min(size, models.MaxLimit)
+	}
+
+	return s
+}
+
+// WithObservability sets the observability provider for tracing.
+func (s *BalanceSnapshotter) WithObservability(obs observability.Provider) *BalanceSnapshotter {
+	s.obs = obs
+	return s
+}
+
+// Capture lists the accounts of a ledger and fetches their balances in parallel.
+// Accounts whose balances were updated after the capture started are fetched
+// again until their versions settle or the recheck budget is spent.
+func (s *BalanceSnapshotter) Capture(ctx context.Context, orgID, ledgerID string) (*BalanceSnapshot, error) {
+	if s.e == nil || s.e.Accounts == nil || s.e.Balances == nil {
+		return nil, errors.New("accounts and balances services are required")
+	}
+
+	if orgID == "" || ledgerID == "" {
+		return nil, errors.New("organization and ledger IDs are required")
+	}
+
+	var snap *BalanceSnapshot
+
+	err := observability.WithSpan(ctx, s.obs, "Export.BalanceSnapshot", func(ctx context.Context) error {
+		var err error
+		snap, err = s.capture(ctx, orgID, ledgerID)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return snap, nil
+}
+
+// Export captures a snapshot and writes it to dir in format.
+func (s *BalanceSnapshotter) Export(ctx context.Context, orgID, ledgerID, dir string, format Format) (*BalanceSnapshot, error) {
+	if format != FormatNDJSON && format != FormatParquet {
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+
+	snap, err := s.Capture(ctx, orgID, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := snap.Write(dir, format); err != nil {
+		return nil, err
+	}
+
+	return snap, nil
+}
+
+func (s *BalanceSnapshotter) capture(ctx context.Context, orgID, ledgerID string) (*BalanceSnapshot, error) {
+	takenAt := s.now().UTC()
+
+	var accountIDs []string
+
+	err := paginate(ctx, s.pageSize, func(opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+		return s.e.Accounts.ListAccounts(ctx, orgID, ledgerID, opts)
+	}, func(a models.Account) error {
+		accountIDs = append(accountIDs, a.ID)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	balances, err := s.fetch(ctx, orgID, ledgerID, accountIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// balances updated after the capture started may have moved while other
+	// accounts were read
+	var moving []string
+
+	for _, id := range accountIDs {
+		if updatedSince(balances[id], takenAt) {
+			moving = append(moving, id)
+		}
+	}
+
+	rechecked := make(map[string]struct{})
+
+	for pass := 0; pass < s.rechecks && len(moving) > 0; pass++ {
+		latest, err := s.fetch(ctx, orgID, ledgerID, moving)
+		if err != nil {
+			return nil, err
+		}
+
+		var still []string
+
+		for _, id := range moving {
+			rechecked[id] = struct{}{}
+
+			if !sameVersions(balances[id], latest[id]) {
+				still = append(still, id)
+			}
+
+			balances[id] = latest[id]
+		}
+
+		moving = still
+	}
+
+	snap := &BalanceSnapshot{
+		SchemaVersion:  SchemaVersion,
+		OrganizationID: orgID,
+		LedgerID:       ledgerID,
+		TakenAt:        takenAt,
+		Consistent:     len(moving) == 0,
+		Accounts:       len(accountIDs),
+		Rechecked:      len(rechecked),
+		Unsettled:      moving,
+		Totals:         make(map[string]BalanceTotal),
+	}
+
+	for _, id := range accountIDs {
+		for _, b := range balances[id] {
+			snap.Balances = append(snap.Balances, b)
+
+			t := snap.Totals[b.AssetCode]
+			t.Balances++
+			t.Available = t.Available.Add(b.Available)
+			t.OnHold = t.OnHold.Add(b.OnHold)
+			snap.Totals[b.AssetCode] = t
+		}
+	}
+
+	sort.SliceStable(snap.Balances, func(i, j int) bool { return snap.Balances[i].ID < snap.Balances[j].ID })
+
+	snap.CompletedAt = s.now().UTC()
+
+	return snap, nil
+}
+
+// fetch lists the balances of accounts in parallel, keyed by account ID.
+func (s *BalanceSnapshotter) fetch(ctx context.Context, orgID, ledgerID string, accountIDs []string) (map[string][]models.Balance, error) {
+	results := concurrent.WorkerPool(ctx, accountIDs, func(ctx context.Context, accountID string) ([]models.Balance, error) {
+		var balances []models.Balance
+
+		err := paginate(ctx, s.pageSize, func(opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+			return s.e.Balances.ListAccountBalances(ctx, orgID, ledgerID, accountID, opts)
+		}, func(b models.Balance) error {
+			balances = append(balances, b)
+			return nil
+		})
+
+		return balances, err
+	}, concurrent.WithWorkers(s.workers))
+
+	out := make(map[string][]models.Balance, len(accountIDs))
+
+	for _, r := range results {
+		if r.Error != nil {
+			return nil, fmt.Errorf("failed to list balances of account %s: %w", r.Item, r.Error)
+		}
+
+		out[r.Item] = r.Value
+	}
+
+	return out, nil
+}
+
+// updatedSince reports whether any balance was updated after t.
+func updatedSince(balances []models.Balance, t time.Time) bool {
+	for _, b := range balances {
+		if b.UpdatedAt.After(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sameVersions reports whether two reads of an account returned the same balances
+// at the same versions.
+func sameVersions(a, b []models.Balance) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	versions := make(map[string]int64, len(a))
+	for _, bal := range a {
+		versions[bal.ID] = bal.Version
+	}
+
+	for _, bal := range b {
+		if v, ok := versions[bal.ID]; !ok || v != bal.Version {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,195 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBalances serves the balances of a ledger. Accounts listed in moving get a
+// new version on every read, updated at the current time.
+type fakeBalances struct {
+	entities.BalancesService
+
+	mu       sync.Mutex
+	balances map[string][]models.Balance
+	moving   map[string]int
+	reads    map[string]int
+	failFor  string
+}
+
+func newFakeBalances() *fakeBalances {
+	old := time.Now().Add(-time.Hour)
+	balance := func(id, account, asset, available string) models.Balance {
+		return models.Balance{
+			ID: id, AccountID: account, AssetCode: asset, Version: 1, UpdatedAt: old,
+			Available: decimal.RequireFromString(available), OnHold: decimal.Zero,
+		}
+	}
+
+	return &fakeBalances{
+		balances: map[string][]models.Balance{
+			"a1": {balance("b1", "a1", "USD", "100"), balance("b2", "a1", "BRL", "7")},
+			"a2": {balance("b3", "a2", "USD", "50")},
+			"a3": {balance("b4", "a3", "USD", "25")},
+		},
+		moving: map[string]int{},
+		reads:  map[string]int{},
+	}
+}
+
+func (f *fakeBalances) entity() *entities.Entity {
+	return &entities.Entity{Accounts: fakeSnapshotAccounts{}, Balances: f}
+}
+
+// fakeSnapshotAccounts lists the accounts served by fakeBalances.
+type fakeSnapshotAccounts struct {
+	entities.AccountsService
+}
+
+func (fakeSnapshotAccounts) ListAccounts(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+	accounts := []models.Account{{ID: "a1"}, {ID: "a2"}, {ID: "a3"}}
+	return fakePage(accounts, opts)
+}
+
+func (f *fakeBalances) ListAccountBalances(_ context.Context, _, _, accountID string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if accountID == f.failFor {
+		return nil, errors.New("unavailable")
+	}
+
+	f.reads[accountID]++
+
+	// moving accounts change for their first n reads
+	if f.reads[accountID] <= f.moving[accountID] {
+		for i := range f.balances[accountID] {
+			b := &f.balances[accountID][i]
+			b.Version++
+			b.Available = b.Available.Add(decimal.NewFromInt(1))
+			b.UpdatedAt = time.Now().Add(time.Second)
+		}
+	}
+
+	return fakePage(append([]models.Balance(nil), f.balances[accountID]...), opts)
+}
+
+func TestBalanceSnapshot_Stable(t *testing.T) {
+	f := newFakeBalances()
+
+	snap, err := NewBalanceSnapshotter(f.entity()).WithWorkers(2).Capture(context.Background(), "org", "ledger")
+	require.NoError(t, err)
+
+	assert.True(t, snap.Consistent)
+	assert.Equal(t, 3, snap.Accounts)
+	assert.Zero(t, snap.Rechecked)
+	assert.Len(t, snap.Balances, 4)
+	assert.Equal(t, "175", snap.Totals["USD"].Available.String())
+	assert.Equal(t, 3, snap.Totals["USD"].Balances)
+	assert.Equal(t, "7", snap.Totals["BRL"].Available.String())
+	assert.False(t, snap.CompletedAt.Before(snap.TakenAt))
+
+	for _, n := range f.reads {
+		assert.Equal(t, 1, n)
+	}
+}
+
+func TestBalanceSnapshot_RechecksMovingAccounts(t *testing.T) {
+	f := newFakeBalances()
+	f.moving["a2"] = 2 // changes on the first read and the first recheck, then settles
+
+	snap, err := NewBalanceSnapshotter(f.entity()).Capture(context.Background(), "org", "ledger")
+	require.NoError(t, err)
+
+	assert.True(t, snap.Consistent)
+	assert.Equal(t, 1, snap.Rechecked)
+	assert.Equal(t, 3, f.reads["a2"])
+	assert.Equal(t, 1, f.reads["a1"])
+	assert.Equal(t, "177", snap.Totals["USD"].Available.String(), "the settled value is kept")
+}
+
+func TestBalanceSnapshot_Unsettled(t *testing.T) {
+	f := newFakeBalances()
+	f.moving["a3"] = 100
+
+	snap, err := NewBalanceSnapshotter(f.entity()).WithMaxRechecks(2).Capture(context.Background(), "org", "ledger")
+	require.NoError(t, err)
+
+	assert.False(t, snap.Consistent)
+	assert.Equal(t, []string{"a3"}, snap.Unsettled)
+	assert.Equal(t, 3, f.reads["a3"])
+}
+
+func TestBalanceSnapshot_Errors(t *testing.T) {
+	f := newFakeBalances()
+	f.failFor = "a2"
+
+	_, err := NewBalanceSnapshotter(f.entity()).Capture(context.Background(), "org", "ledger")
+	assert.ErrorContains(t, err, "account a2")
+
+	_, err = NewBalanceSnapshotter(&entities.Entity{}).Capture(context.Background(), "org", "ledger")
+	assert.Error(t, err)
+
+	_, err = NewBalanceSnapshotter(f.entity()).Capture(context.Background(), "org", "")
+	assert.Error(t, err)
+}
+
+func TestBalanceSnapshot_Export(t *testing.T) {
+	for _, format := range []Format{FormatNDJSON, FormatParquet} {
+		t.Run(string(format), func(t *testing.T) {
+			dir := t.TempDir()
+
+			snap, err := NewBalanceSnapshotter(newFakeBalances().entity()).
+				Export(context.Background(), "org", "ledger", dir, format)
+			require.NoError(t, err)
+
+			raw, err := os.ReadFile(filepath.Join(dir, BalanceSnapshotFile))
+			require.NoError(t, err)
+
+			var described BalanceSnapshot
+			require.NoError(t, json.Unmarshal(raw, &described))
+			assert.Equal(t, snap.File, described.File)
+			assert.True(t, described.Consistent)
+			assert.Equal(t, "175", described.Totals["USD"].Available.String())
+
+			r, err := OpenRecordReader(filepath.Join(dir, described.File), format)
+			require.NoError(t, err)
+
+			defer r.Close()
+
+			var ids []string
+
+			for {
+				rec, err := r.Read()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+
+				require.NoError(t, err)
+				assert.Equal(t, KindBalance, rec.Kind)
+
+				var b models.Balance
+				require.NoError(t, rec.Decode(&b))
+				ids = append(ids, b.ID)
+			}
+
+			assert.Equal(t, []string{"b1", "b2", "b3", "b4"}, ids)
+		})
+	}
+
+	_, err := NewBalanceSnapshotter(newFakeBalances().entity()).Export(context.Background(), "org", "ledger", t.TempDir(), "csv")
+	assert.Error(t, err)
+}
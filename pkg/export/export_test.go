@@ -0,0 +1,436 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMidaz is an in-memory implementation of the services used by the exporter
+// and importer. Embedded interfaces satisfy the methods the tests do not need.
+type fakeMidaz struct {
+	entities.OrganizationsService
+	entities.LedgersService
+	entities.AssetsService
+	entities.AccountTypesService
+	entities.PortfoliosService
+	entities.SegmentsService
+	entities.AccountsService
+	entities.OperationRoutesService
+	entities.TransactionRoutesService
+	entities.TransactionsService
+
+	orgs         []models.Organization
+	ledgers      []models.Ledger
+	assets       []models.Asset
+	accountTypes []models.AccountType
+	portfolios   []models.Portfolio
+	segments     []models.Segment
+	accounts     []models.Account
+	opRoutes     []models.OperationRoute
+	txRoutes     []models.TransactionRoute
+	txInputs     []*models.CreateTransactionInput
+	transactions []models.Transaction
+}
+
+func (f *fakeMidaz) entity() *entities.Entity {
+	return &entities.Entity{
+		Organizations:     f,
+		Ledgers:           f,
+		Assets:            f,
+		AccountTypes:      f,
+		Portfolios:        f,
+		Segments:          f,
+		Accounts:          f,
+		OperationRoutes:   f,
+		TransactionRoutes: f,
+		Transactions:      f,
+	}
+}
+
+// fakePage returns the page of items selected by opts.
+func fakePage[T any](items []T, opts *models.ListOptions) (*models.ListResponse[T], error) {
+	start := min(opts.Offset, len(items))
+	end := min(start+opts.Limit, len(items))
+
+	return &models.ListResponse[T]{
+		Items:      items[start:end],
+		Pagination: models.Pagination{Limit: opts.Limit, Offset: start, Total: len(items)},
+	}, nil
+}
+
+func (f *fakeMidaz) GetOrganization(_ context.Context, id string) (*models.Organization, error) {
+	for _, o := range f.orgs {
+		if o.ID == id {
+			return &o, nil
+		}
+	}
+
+	return nil, fmt.Errorf("organization %s not found", id)
+}
+
+func (f *fakeMidaz) CreateOrganization(_ context.Context, in *models.CreateOrganizationInput) (*models.Organization, error) {
+	o := models.Organization{ID: uuid.NewString(), LegalName: in.LegalName, LegalDocument: in.LegalDocument, Metadata: in.Metadata}
+	f.orgs = append(f.orgs, o)
+
+	return &o, nil
+}
+
+func (f *fakeMidaz) ListLedgers(_ context.Context, _ string, opts *models.ListOptions) (*models.ListResponse[models.Ledger], error) {
+	return fakePage(f.ledgers, opts)
+}
+
+func (f *fakeMidaz) CreateLedger(_ context.Context, orgID string, in *models.CreateLedgerInput) (*models.Ledger, error) {
+	l := models.Ledger{ID: uuid.NewString(), OrganizationID: orgID, Name: in.Name}
+	f.ledgers = append(f.ledgers, l)
+
+	return &l, nil
+}
+
+func (f *fakeMidaz) ListAssets(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Asset], error) {
+	return fakePage(f.assets, opts)
+}
+
+func (f *fakeMidaz) CreateAsset(_ context.Context, _, ledgerID string, in *models.CreateAssetInput) (*models.Asset, error) {
+	a := models.Asset{ID: uuid.NewString(), LedgerID: ledgerID, Name: in.Name, Code: in.Code, Type: in.Type}
+	f.assets = append(f.assets, a)
+
+	return &a, nil
+}
+
+func (f *fakeMidaz) ListAccountTypes(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.AccountType], error) {
+	return fakePage(f.accountTypes, opts)
+}
+
+func (f *fakeMidaz) CreateAccountType(_ context.Context, _, _ string, in *models.CreateAccountTypeInput) (*models.AccountType, error) {
+	at := models.AccountType{ID: uuid.New(), Name: in.Name, KeyValue: in.KeyValue}
+	f.accountTypes = append(f.accountTypes, at)
+
+	return &at, nil
+}
+
+func (f *fakeMidaz) ListPortfolios(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Portfolio], error) {
+	return fakePage(f.portfolios, opts)
+}
+
+func (f *fakeMidaz) CreatePortfolio(_ context.Context, _, _ string, in *models.CreatePortfolioInput) (*models.Portfolio, error) {
+	p := models.Portfolio{ID: uuid.NewString(), Name: in.Name, EntityID: in.EntityID}
+	f.portfolios = append(f.portfolios, p)
+
+	return &p, nil
+}
+
+func (f *fakeMidaz) ListSegments(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Segment], error) {
+	return fakePage(f.segments, opts)
+}
+
+func (f *fakeMidaz) CreateSegment(_ context.Context, _, _ string, in *models.CreateSegmentInput) (*models.Segment, error) {
+	s := models.Segment{ID: uuid.NewString(), Name: in.Name}
+	f.segments = append(f.segments, s)
+
+	return &s, nil
+}
+
+func (f *fakeMidaz) ListAccounts(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+	return fakePage(f.accounts, opts)
+}
+
+func (f *fakeMidaz) CreateAccount(_ context.Context, _, _ string, in *models.CreateAccountInput) (*models.Account, error) {
+	a := models.Account{
+		ID:              uuid.NewString(),
+		Name:            in.Name,
+		AssetCode:       in.AssetCode,
+		Alias:           in.Alias,
+		Type:            in.Type,
+		ParentAccountID: in.ParentAccountID,
+		PortfolioID:     in.PortfolioID,
+		SegmentID:       in.SegmentID,
+	}
+	f.accounts = append(f.accounts, a)
+
+	return &a, nil
+}
+
+func (f *fakeMidaz) ListOperationRoutes(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.OperationRoute], error) {
+	return fakePage(f.opRoutes, opts)
+}
+
+func (f *fakeMidaz) CreateOperationRoute(_ context.Context, _, _ string, in *models.CreateOperationRouteInput) (*models.OperationRoute, error) {
+	r := models.OperationRoute{ID: uuid.New(), Title: in.Title, OperationType: in.OperationType, Account: in.Account}
+	f.opRoutes = append(f.opRoutes, r)
+
+	return &r, nil
+}
+
+func (f *fakeMidaz) ListTransactionRoutes(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.TransactionRoute], error) {
+	return fakePage(f.txRoutes, opts)
+}
+
+func (f *fakeMidaz) CreateTransactionRoute(_ context.Context, _, _ string, in *models.CreateTransactionRouteInput) (*models.TransactionRoute, error) {
+	r := models.TransactionRoute{ID: uuid.New(), Title: in.Title}
+	for _, id := range in.OperationRoutes {
+		r.OperationRoutes = append(r.OperationRoutes, models.OperationRoute{ID: id})
+	}
+
+	f.txRoutes = append(f.txRoutes, r)
+
+	return &r, nil
+}
+
+func (f *fakeMidaz) ListTransactions(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Transaction], error) {
+	return fakePage(f.transactions, opts)
+}
+
+func (f *fakeMidaz) CreateTransaction(_ context.Context, _, _ string, in *models.CreateTransactionInput) (*models.Transaction, error) {
+	f.txInputs = append(f.txInputs, in)
+	return &models.Transaction{ID: uuid.NewString()}, nil
+}
+
+func operation(kind, alias, value string) models.Operation {
+	v := decimal.RequireFromString(value)
+	return models.Operation{Type: kind, AccountAlias: alias, AssetCode: "USD", Amount: models.Amount{Value: &v}}
+}
+
+// sourceFixture builds an organization covering every kind of a dump.
+func sourceFixture() *fakeMidaz {
+	parentID := uuid.NewString()
+	portfolioID := uuid.NewString()
+	srcRoute := models.OperationRoute{ID: uuid.New(), Title: "src", OperationType: "source"}
+	dstRoute := models.OperationRoute{ID: uuid.New(), Title: "dst", OperationType: "destination"}
+
+	return &fakeMidaz{
+		orgs:         []models.Organization{{ID: "org-1", LegalName: "Acme"}},
+		ledgers:      []models.Ledger{{ID: "ledger-1", Name: "Main"}},
+		assets:       []models.Asset{{ID: "asset-1", Name: "Dollar", Code: "USD", Type: "currency"}},
+		accountTypes: []models.AccountType{{ID: uuid.New(), Name: "Checking", KeyValue: "checking"}},
+		portfolios:   []models.Portfolio{{ID: portfolioID, Name: "Retail"}},
+		segments:     []models.Segment{{ID: "segment-1", Name: "North"}},
+		accounts: []models.Account{
+			// the child is listed before its parent to exercise deferred imports
+			{ID: "child", Name: "Child", AssetCode: "USD", Type: "checking", ParentAccountID: &parentID, Alias: ptr("@child")},
+			{ID: parentID, Name: "Parent", AssetCode: "USD", Type: "checking", PortfolioID: &portfolioID, Alias: ptr("@parent")},
+			{ID: "external", Name: "External USD", AssetCode: "USD", Type: "external", Alias: ptr("@external/USD")},
+		},
+		opRoutes: []models.OperationRoute{srcRoute, dstRoute},
+		txRoutes: []models.TransactionRoute{{ID: uuid.New(), Title: "Payment", OperationRoutes: []models.OperationRoute{srcRoute, dstRoute}}},
+		transactions: []models.Transaction{
+			{
+				ID: "tx-1", AssetCode: "USD", Amount: "100", Status: models.Status{Code: "APPROVED"},
+				Operations: []models.Operation{
+					operation("DEBIT", "@external/USD", "100"),
+					operation("CREDIT", "@parent", "60"),
+					operation("CREDIT", "@child", "40"),
+				},
+			},
+			{
+				ID: "tx-2", AssetCode: "USD", Amount: "5", Status: models.Status{Code: "CANCELED"},
+				Operations: []models.Operation{operation("ON_HOLD", "@parent", "5"), operation("CREDIT", "@child", "5")},
+			},
+		},
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestRecordCodec_RoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatNDJSON, FormatParquet} {
+		t.Run(string(format), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), fileName(KindAccount, format))
+
+			w, err := NewRecordWriter(path, format)
+			require.NoError(t, err)
+
+			// more than one Parquet batch
+			const n = parquetBatchSize*2 + 7
+
+			for i := 0; i < n; i++ {
+				require.NoError(t, w.Write(Record{
+					SchemaVersion: SchemaVersion,
+					Kind:          KindAccount,
+					ID:            fmt.Sprintf("acc-%d", i),
+					LedgerID:      "ledger-1",
+					Data:          json.RawMessage(fmt.Sprintf(`{"id":"acc-%d"}`, i)),
+				}))
+			}
+
+			require.NoError(t, w.Close())
+
+			r, err := OpenRecordReader(path, format)
+			require.NoError(t, err)
+
+			defer r.Close()
+
+			for i := 0; i < n; i++ {
+				rec, err := r.Read()
+				require.NoError(t, err)
+				assert.Equal(t, fmt.Sprintf("acc-%d", i), rec.ID)
+				assert.Equal(t, KindAccount, rec.Kind)
+				assert.Equal(t, "ledger-1", rec.LedgerID)
+				assert.JSONEq(t, fmt.Sprintf(`{"id":"acc-%d"}`, i), string(rec.Data))
+			}
+
+			_, err = r.Read()
+			assert.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestReadManifest_SchemaVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, writeManifest(fsutil.Dir(dir), &Manifest{SchemaVersion: SchemaVersion + 1, Format: FormatNDJSON}))
+
+	_, err := ReadManifest(dir)
+	assert.ErrorIs(t, err, ErrUnsupportedSchema)
+
+	require.NoError(t, writeManifest(fsutil.Dir(dir), &Manifest{SchemaVersion: SchemaVersion, Format: "csv"}))
+
+	_, err = ReadManifest(dir)
+	assert.ErrorContains(t, err, "unsupported export format")
+
+	_, err = ReadManifest(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatNDJSON, FormatParquet} {
+		t.Run(string(format), func(t *testing.T) {
+			src := sourceFixture()
+			dir := t.TempDir()
+
+			manifest, err := NewExporter(src.entity()).WithFormat(format).WithPageSize(1).Export(context.Background(), "org-1", dir)
+			require.NoError(t, err)
+
+			assert.Equal(t, SchemaVersion, manifest.SchemaVersion)
+			assert.Equal(t, 3, manifest.Counts[KindAccount])
+			assert.Equal(t, 2, manifest.Counts[KindTransaction])
+
+			for _, kind := range DependencyOrder {
+				_, err := os.Stat(filepath.Join(dir, manifest.Files[kind]))
+				require.NoError(t, err, kind)
+			}
+
+			dst := &fakeMidaz{}
+
+			result, err := NewImporter(dst.entity()).Import(context.Background(), dir)
+			require.NoError(t, err)
+
+			assert.Equal(t, dst.orgs[0].ID, result.OrganizationID)
+			assert.Equal(t, 1, result.Created[KindLedger])
+			assert.Equal(t, 1, result.Created[KindAccountType])
+			assert.Equal(t, 2, result.Created[KindAccount])
+			assert.Equal(t, 1, result.Skipped[KindAccount], "external accounts are not imported")
+			assert.Equal(t, 1, result.Created[KindTransaction])
+			assert.Equal(t, 1, result.Skipped[KindTransaction], "cancelled transactions are not replayed")
+
+			// references point to the new IDs
+			newParent, ok := result.NewID(KindAccount, src.accounts[1].ID)
+			require.True(t, ok)
+
+			newChild, ok := result.NewID(KindAccount, "child")
+			require.True(t, ok)
+
+			for _, acc := range dst.accounts {
+				switch acc.ID {
+				case newChild:
+					require.NotNil(t, acc.ParentAccountID)
+					assert.Equal(t, newParent, *acc.ParentAccountID)
+				case newParent:
+					newPortfolio, _ := result.NewID(KindPortfolio, *src.accounts[1].PortfolioID)
+					assert.Equal(t, newPortfolio, *acc.PortfolioID)
+				}
+			}
+
+			require.Len(t, dst.txRoutes, 1)
+			for i, or := range dst.txRoutes[0].OperationRoutes {
+				want, _ := result.NewID(KindOperationRoute, src.opRoutes[i].ID.String())
+				assert.Equal(t, want, or.ID.String())
+			}
+
+			require.Len(t, dst.txInputs, 1)
+			tx := dst.txInputs[0]
+			assert.Equal(t, "import-tx-1", tx.IdempotencyKey)
+			assert.Equal(t, "100", tx.Send.Value)
+			assert.Len(t, tx.Send.Source.From, 1)
+			assert.Len(t, tx.Send.Distribute.To, 2)
+		})
+	}
+}
+
+func TestExportImportFS_InMemory(t *testing.T) {
+	for _, format := range []Format{FormatNDJSON, FormatParquet} {
+		t.Run(string(format), func(t *testing.T) {
+			fsys := fsutil.NewMapFS()
+
+			manifest, err := NewExporter(sourceFixture().entity()).WithFormat(format).ExportFS(context.Background(), "org-1", fsys)
+			require.NoError(t, err)
+			assert.Contains(t, fsys.Names(), ManifestFile)
+			assert.Contains(t, fsys.Names(), manifest.Files[KindAccount])
+
+			result, err := NewImporter((&fakeMidaz{}).entity()).ImportFS(context.Background(), fsys)
+			require.NoError(t, err)
+			assert.Equal(t, 2, result.Created[KindAccount])
+		})
+	}
+}
+
+func TestImportFS_RejectsPathsOutsideTheDump(t *testing.T) {
+	fsys := fsutil.NewMapFS()
+	require.NoError(t, writeManifest(fsys, &Manifest{
+		SchemaVersion: SchemaVersion,
+		Format:        FormatNDJSON,
+		Files:         map[Kind]string{KindOrganization: "../organizations.ndjson"},
+	}))
+
+	_, err := NewImporter((&fakeMidaz{}).entity()).ImportFS(context.Background(), fsys)
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+}
+
+func TestImporter_TargetOrganizationWithoutTransactions(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewExporter(sourceFixture().entity()).Export(context.Background(), "org-1", dir)
+	require.NoError(t, err)
+
+	dst := &fakeMidaz{}
+
+	result, err := NewImporter(dst.entity()).
+		WithTargetOrganization("existing-org").
+		WithoutTransactions().
+		Import(context.Background(), dir)
+	require.NoError(t, err)
+
+	assert.Empty(t, dst.orgs)
+	assert.Equal(t, "existing-org", result.OrganizationID)
+	assert.Empty(t, dst.txInputs)
+	assert.Equal(t, 1, result.Created[KindTransactionRoute])
+}
+
+func TestTransactionInputFromOperations(t *testing.T) {
+	pending := &models.Transaction{
+		ID: "tx-p", AssetCode: "USD", Status: models.Status{Code: "PENDING"},
+		Operations: []models.Operation{operation("ON_HOLD", "@a", "7.5"), operation("CREDIT", "@b", "7.5")},
+	}
+
+	input, ok := TransactionInputFromOperations(pending)
+	require.True(t, ok)
+	assert.True(t, input.Pending)
+	assert.Equal(t, "7.5", input.Send.Value)
+	assert.Equal(t, "@a", input.Send.Source.From[0].Account)
+
+	_, ok = TransactionInputFromOperations(&models.Transaction{Operations: []models.Operation{operation("CREDIT", "@b", "1")}})
+	assert.False(t, ok)
+}
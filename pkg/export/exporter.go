@@ -0,0 +1,298 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+)
+
+// Exporter dumps the entities of an organization to a directory.
+type Exporter struct {
+	e        *entities.Entity
+	format   Format
+	pageSize int
+	obs      observability.Provider
+}
+
+// NewExporter creates an Exporter writing NDJSON files.
+func NewExporter(e *entities.Entity) *Exporter {
+	return &Exporter{e: e, format: FormatNDJSON, pageSize: models.MaxLimit}
+}
+
+// WithFormat sets the file format of the dump.
+func (x *Exporter) WithFormat(format Format) *Exporter {
+	x.format = format
+	return x
+}
+
+// WithPageSize sets the number of items requested per list call, capped at models.MaxLimit.
+func (x *Exporter) WithPageSize(size int) *Exporter {
+	if size > 0 {
+		x.pageSize = min(size, models.MaxLimit)
+	}
+
+	return x
+}
+
+// WithObservability sets the observability provider for tracing.
+func (x *Exporter) WithObservability(obs observability.Provider) *Exporter {
+	x.obs = obs
+	return x
+}
+
+// Export writes every entity of the organization to dir, creating it if needed,
+// and returns the manifest written alongside the data files.
+func (x *Exporter) Export(ctx context.Context, orgID, dir string) (*Manifest, error) {
+	return x.ExportFS(ctx, orgID, fsutil.Dir(dir))
+}
+
+// ExportFS is Export writing the dump at the root of fsys.
+func (x *Exporter) ExportFS(ctx context.Context, orgID string, fsys fsutil.FS) (*Manifest, error) {
+	if x.e == nil {
+		return nil, errors.New("entity not initialized")
+	}
+
+	if orgID == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	if x.format != FormatNDJSON && x.format != FormatParquet {
+		return nil, fmt.Errorf("unsupported export format %q", x.format)
+	}
+
+	manifest := &Manifest{
+		SchemaVersion:  SchemaVersion,
+		Format:         x.format,
+		OrganizationID: orgID,
+		ExportedAt:     time.Now().UTC(),
+		Counts:         make(map[Kind]int, len(DependencyOrder)),
+		Files:          make(map[Kind]string, len(DependencyOrder)),
+	}
+
+	writers := make(map[Kind]RecordWriter, len(DependencyOrder))
+
+	for _, kind := range DependencyOrder {
+		name := fileName(kind, x.format)
+
+		w, err := CreateRecordWriter(fsys, name, x.format)
+		if err != nil {
+			closeWriters(writers)
+			return nil, err
+		}
+
+		writers[kind] = w
+		manifest.Files[kind] = name
+	}
+
+	s := &exportSession{x: x, writers: writers, manifest: manifest}
+
+	err := observability.WithSpan(ctx, x.obs, "Export.Organization", func(ctx context.Context) error {
+		return s.exportOrganization(ctx, orgID)
+	})
+
+	if closeErr := closeWriters(writers); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeManifest(fsys, manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// exportSession holds the writers of a single export.
+type exportSession struct {
+	x        *Exporter
+	writers  map[Kind]RecordWriter
+	manifest *Manifest
+}
+
+// exportOrganization writes the organization and everything below it.
+func (s *exportSession) exportOrganization(ctx context.Context, orgID string) error {
+	e := s.x.e
+
+	if e.Organizations == nil || e.Ledgers == nil {
+		return errors.New("organizations and ledgers services are required")
+	}
+
+	org, err := e.Organizations.GetOrganization(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	if err := s.write(KindOrganization, org.ID, "", org); err != nil {
+		return err
+	}
+
+	return paginate(ctx, s.x.pageSize, func(opts *models.ListOptions) (*models.ListResponse[models.Ledger], error) {
+		return e.Ledgers.ListLedgers(ctx, orgID, opts)
+	}, func(ledger models.Ledger) error {
+		if err := s.write(KindLedger, ledger.ID, ledger.ID, ledger); err != nil {
+			return err
+		}
+
+		return s.exportLedger(ctx, orgID, ledger.ID)
+	})
+}
+
+// exportLedger writes every entity of a ledger, kind by kind.
+func (s *exportSession) exportLedger(ctx context.Context, orgID, ledgerID string) error {
+	e := s.x.e
+	size := s.x.pageSize
+
+	steps := []func() error{
+		func() error {
+			if e.Assets == nil {
+				return nil
+			}
+
+			return paginate(ctx, size, func(opts *models.ListOptions) (*models.ListResponse[models.Asset], error) {
+				return e.Assets.ListAssets(ctx, orgID, ledgerID, opts)
+			}, func(a models.Asset) error { return s.write(KindAsset, a.ID, ledgerID, a) })
+		},
+		func() error {
+			if e.AccountTypes == nil {
+				return nil
+			}
+
+			return paginate(ctx, size, func(opts *models.ListOptions) (*models.ListResponse[models.AccountType], error) {
+				return e.AccountTypes.ListAccountTypes(ctx, orgID, ledgerID, opts)
+			}, func(at models.AccountType) error { return s.write(KindAccountType, at.ID.String(), ledgerID, at) })
+		},
+		func() error {
+			if e.Portfolios == nil {
+				return nil
+			}
+
+			return paginate(ctx, size, func(opts *models.ListOptions) (*models.ListResponse[models.Portfolio], error) {
+				return e.Portfolios.ListPortfolios(ctx, orgID, ledgerID, opts)
+			}, func(p models.Portfolio) error { return s.write(KindPortfolio, p.ID, ledgerID, p) })
+		},
+		func() error {
+			if e.Segments == nil {
+				return nil
+			}
+
+			return paginate(ctx, size, func(opts *models.ListOptions) (*models.ListResponse[models.Segment], error) {
+				return e.Segments.ListSegments(ctx, orgID, ledgerID, opts)
+			}, func(sg models.Segment) error { return s.write(KindSegment, sg.ID, ledgerID, sg) })
+		},
+		func() error {
+			if e.Accounts == nil {
+				return nil
+			}
+
+			return paginate(ctx, size, func(opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+				return e.Accounts.ListAccounts(ctx, orgID, ledgerID, opts)
+			}, func(a models.Account) error { return s.write(KindAccount, a.ID, ledgerID, a) })
+		},
+		func() error {
+			if e.OperationRoutes == nil {
+				return nil
+			}
+
+			return paginate(ctx, size, func(opts *models.ListOptions) (*models.ListResponse[models.OperationRoute], error) {
+				return e.OperationRoutes.ListOperationRoutes(ctx, orgID, ledgerID, opts)
+			}, func(r models.OperationRoute) error { return s.write(KindOperationRoute, r.ID.String(), ledgerID, r) })
+		},
+		func() error {
+			if e.TransactionRoutes == nil {
+				return nil
+			}
+
+			return paginate(ctx, size, func(opts *models.ListOptions) (*models.ListResponse[models.TransactionRoute], error) {
+				return e.TransactionRoutes.ListTransactionRoutes(ctx, orgID, ledgerID, opts)
+			}, func(r models.TransactionRoute) error {
+				return s.write(KindTransactionRoute, r.ID.String(), ledgerID, r)
+			})
+		},
+		func() error {
+			if e.Transactions == nil {
+				return nil
+			}
+
+			// oldest first, so an import replays transactions in their original order
+			return paginate(ctx, size, func(opts *models.ListOptions) (*models.ListResponse[models.Transaction], error) {
+				return e.Transactions.ListTransactions(ctx, orgID, ledgerID, opts.WithOrderDirection(models.SortAscending))
+			}, func(t models.Transaction) error { return s.write(KindTransaction, t.ID, ledgerID, t) })
+		},
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return fmt.Errorf("ledger %s: %w", ledgerID, err)
+		}
+	}
+
+	return nil
+}
+
+// write encodes v as a record of kind.
+func (s *exportSession) write(kind Kind, id, ledgerID string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s %s: %w", kind, id, err)
+	}
+
+	rec := Record{SchemaVersion: SchemaVersion, Kind: kind, ID: id, LedgerID: ledgerID, Data: data}
+	if err := s.writers[kind].Write(rec); err != nil {
+		return fmt.Errorf("failed to write %s %s: %w", kind, id, err)
+	}
+
+	s.manifest.Counts[kind]++
+
+	return nil
+}
+
+// paginate calls fn for every item of every page returned by list.
+func paginate[T any](ctx context.Context, pageSize int, list func(opts *models.ListOptions) (*models.ListResponse[T], error), fn func(T) error) error {
+	opts := models.NewListOptions().WithLimit(pageSize)
+
+	for opts != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := list(opts)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range page.Items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+
+		if len(page.Items) == 0 {
+			return nil
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	return nil
+}
+
+// closeWriters closes every writer, returning the joined errors.
+func closeWriters(writers map[Kind]RecordWriter) error {
+	var errs []error
+
+	for _, w := range writers {
+		errs = append(errs, w.Close())
+	}
+
+	return errors.Join(errs...)
+}
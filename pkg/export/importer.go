@@ -0,0 +1,631 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// accountTypeExternal is the type of the accounts Midaz creates for each asset;
+// they are recreated with the assets and never imported.
+const accountTypeExternal = "external"
+
+// Importer recreates a dump in Midaz, honoring DependencyOrder.
+type Importer struct {
+	e                   *entities.Entity
+	obs                 observability.Provider
+	targetOrgID         string
+	includeTransactions bool
+}
+
+// NewImporter creates an Importer that creates a new organization from the dump,
+// including its transactions.
+func NewImporter(e *entities.Entity) *Importer {
+	return &Importer{e: e, includeTransactions: true}
+}
+
+// WithTargetOrganization imports into an existing organization instead of
+// creating the one of the dump.
+func (im *Importer) WithTargetOrganization(orgID string) *Importer {
+	im.targetOrgID = orgID
+	return im
+}
+
+// WithoutTransactions imports the structure of the dump (ledgers, assets,
+// accounts, routes) and skips its transactions.
+func (im *Importer) WithoutTransactions() *Importer {
+	im.includeTransactions = false
+	return im
+}
+
+// WithObservability sets the observability provider for tracing.
+func (im *Importer) WithObservability(obs observability.Provider) *Importer {
+	im.obs = obs
+	return im
+}
+
+// ImportResult summarizes an import.
+type ImportResult struct {
+	// OrganizationID is the organization the dump was imported into
+	OrganizationID string
+
+	// Created and Skipped count records per kind
+	Created map[Kind]int
+	Skipped map[Kind]int
+
+	// IDs maps, per kind, the IDs of the dump to the IDs created by the import
+	IDs map[Kind]map[string]string
+}
+
+// NewID returns the ID created for the record of kind with ID oldID.
+func (r *ImportResult) NewID(kind Kind, oldID string) (string, bool) {
+	id, ok := r.IDs[kind][oldID]
+	return id, ok
+}
+
+// Import reads the dump in dir and creates its entities. It stops at the first
+// error and returns the partial result, so callers can report or clean up what
+// was created.
+func (im *Importer) Import(ctx context.Context, dir string) (*ImportResult, error) {
+	return im.ImportFS(ctx, os.DirFS(dir))
+}
+
+// ImportFS is Import reading the dump at the root of fsys. The files named by
+// the manifest are opened in fsys, so a dump can't make the import read
+// files outside of it.
+func (im *Importer) ImportFS(ctx context.Context, fsys fs.FS) (*ImportResult, error) {
+	if im.e == nil {
+		return nil, errors.New("entity not initialized")
+	}
+
+	manifest, err := ReadManifestFS(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &importSession{
+		im:       im,
+		manifest: manifest,
+		catalog:  entities.NewAccountTypeCatalogCache(im.e.AccountTypes, -1),
+		result: &ImportResult{
+			OrganizationID: im.targetOrgID,
+			Created:        make(map[Kind]int),
+			Skipped:        make(map[Kind]int),
+			IDs:            make(map[Kind]map[string]string),
+		},
+	}
+
+	err = observability.WithSpan(ctx, im.obs, "Import.Organization", func(ctx context.Context) error {
+		for _, kind := range DependencyOrder {
+			if kind == KindTransaction && !im.includeTransactions {
+				continue
+			}
+
+			if err := s.importKind(ctx, fsys, kind); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return s.result, err
+}
+
+// importSession holds the state of a single import.
+type importSession struct {
+	im       *Importer
+	manifest *Manifest
+	catalog  *entities.AccountTypeCatalogCache
+	result   *ImportResult
+
+	// deferred holds accounts whose parent account was not imported yet
+	deferred []Record
+}
+
+// importKind imports every record of one kind.
+func (s *importSession) importKind(ctx context.Context, fsys fs.FS, kind Kind) error {
+	name, ok := s.manifest.Files[kind]
+	if !ok || name == "" {
+		return nil
+	}
+
+	r, err := OpenRecordReaderFS(fsys, name, s.manifest.Format)
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	for {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if rec.SchemaVersion > SchemaVersion {
+			return fmt.Errorf("%w: %s record %s has version %d", ErrUnsupportedSchema, kind, rec.ID, rec.SchemaVersion)
+		}
+
+		if err := s.importRecord(ctx, kind, rec); err != nil {
+			return fmt.Errorf("failed to import %s %s: %w", kind, rec.ID, err)
+		}
+	}
+
+	if kind == KindAccount {
+		return s.importDeferredAccounts(ctx)
+	}
+
+	return nil
+}
+
+// importRecord creates the entity of a record and records its new ID.
+func (s *importSession) importRecord(ctx context.Context, kind Kind, rec Record) error {
+	if kind == KindOrganization {
+		return s.importOrganization(ctx, rec)
+	}
+
+	orgID := s.result.OrganizationID
+	if orgID == "" {
+		return errors.New("dump has no organization record")
+	}
+
+	if kind == KindLedger {
+		return s.importLedger(ctx, orgID, rec)
+	}
+
+	ledgerID, ok := s.result.NewID(KindLedger, rec.LedgerID)
+	if !ok {
+		return fmt.Errorf("ledger %s was not imported", rec.LedgerID)
+	}
+
+	var (
+		newID string
+		err   error
+	)
+
+	switch kind {
+	case KindAsset:
+		newID, err = s.importAsset(ctx, orgID, ledgerID, rec)
+	case KindAccountType:
+		newID, err = s.importAccountType(ctx, orgID, ledgerID, rec)
+	case KindPortfolio:
+		newID, err = s.importPortfolio(ctx, orgID, ledgerID, rec)
+	case KindSegment:
+		newID, err = s.importSegment(ctx, orgID, ledgerID, rec)
+	case KindAccount:
+		newID, err = s.importAccount(ctx, orgID, ledgerID, rec)
+	case KindOperationRoute:
+		newID, err = s.importOperationRoute(ctx, orgID, ledgerID, rec)
+	case KindTransactionRoute:
+		newID, err = s.importTransactionRoute(ctx, orgID, ledgerID, rec)
+	case KindTransaction:
+		newID, err = s.importTransaction(ctx, orgID, ledgerID, rec)
+	default:
+		return fmt.Errorf("unknown record kind %q", kind)
+	}
+
+	if errors.Is(err, errDeferred) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.record(kind, rec.ID, newID)
+
+	return nil
+}
+
+// record stores the outcome of a record; an empty newID means it was skipped.
+func (s *importSession) record(kind Kind, oldID, newID string) {
+	if newID == "" {
+		s.result.Skipped[kind]++
+		return
+	}
+
+	if s.result.IDs[kind] == nil {
+		s.result.IDs[kind] = make(map[string]string)
+	}
+
+	s.result.IDs[kind][oldID] = newID
+	s.result.Created[kind]++
+}
+
+func (s *importSession) importOrganization(ctx context.Context, rec Record) error {
+	if s.im.targetOrgID != "" {
+		s.result.IDs[KindOrganization] = map[string]string{rec.ID: s.im.targetOrgID}
+		s.result.Skipped[KindOrganization]++
+
+		return nil
+	}
+
+	if s.result.OrganizationID != "" {
+		return errors.New("dump has more than one organization")
+	}
+
+	var org models.Organization
+	if err := rec.Decode(&org); err != nil {
+		return err
+	}
+
+	input := models.NewCreateOrganizationInput(org.LegalName)
+	input.DoingBusinessAs = org.DoingBusinessAs
+	input.LegalDocument = org.LegalDocument
+	input.Address = org.Address
+	input.Status = org.Status
+	input.Metadata = org.Metadata
+
+	created, err := s.im.e.Organizations.CreateOrganization(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	s.result.OrganizationID = created.ID
+	s.record(KindOrganization, rec.ID, created.ID)
+
+	return nil
+}
+
+func (s *importSession) importLedger(ctx context.Context, orgID string, rec Record) error {
+	var ledger models.Ledger
+	if err := rec.Decode(&ledger); err != nil {
+		return err
+	}
+
+	input := &models.CreateLedgerInput{}
+	input.Name = ledger.Name
+	input.Status = ledger.Status
+	input.Metadata = ledger.Metadata
+	input.Settings = ledger.Settings
+
+	created, err := s.im.e.Ledgers.CreateLedger(ctx, orgID, input)
+	if err != nil {
+		return err
+	}
+
+	s.record(KindLedger, rec.ID, created.ID)
+
+	return nil
+}
+
+func (s *importSession) importAsset(ctx context.Context, orgID, ledgerID string, rec Record) (string, error) {
+	var asset models.Asset
+	if err := rec.Decode(&asset); err != nil {
+		return "", err
+	}
+
+	input := &models.CreateAssetInput{}
+	input.Name = asset.Name
+	input.Type = asset.Type
+	input.Code = asset.Code
+	input.Status = asset.Status
+	input.Metadata = asset.Metadata
+
+	created, err := s.im.e.Assets.CreateAsset(ctx, orgID, ledgerID, input)
+	if err != nil {
+		return "", err
+	}
+
+	return created.ID, nil
+}
+
+func (s *importSession) importAccountType(ctx context.Context, orgID, ledgerID string, rec Record) (string, error) {
+	var at models.AccountType
+	if err := rec.Decode(&at); err != nil {
+		return "", err
+	}
+
+	input := models.NewCreateAccountTypeInput(at.Name, at.KeyValue).WithDescription(at.Description).WithMetadata(at.Metadata)
+
+	created, _, err := s.catalog.EnsureAccountType(ctx, orgID, ledgerID, input)
+	if err != nil {
+		return "", err
+	}
+
+	return created.ID.String(), nil
+}
+
+func (s *importSession) importPortfolio(ctx context.Context, orgID, ledgerID string, rec Record) (string, error) {
+	var p models.Portfolio
+	if err := rec.Decode(&p); err != nil {
+		return "", err
+	}
+
+	input := &models.CreatePortfolioInput{}
+	input.EntityID = p.EntityID
+	input.Name = p.Name
+	input.Status = p.Status
+	input.Metadata = p.Metadata
+
+	created, err := s.im.e.Portfolios.CreatePortfolio(ctx, orgID, ledgerID, input)
+	if err != nil {
+		return "", err
+	}
+
+	return created.ID, nil
+}
+
+func (s *importSession) importSegment(ctx context.Context, orgID, ledgerID string, rec Record) (string, error) {
+	var sg models.Segment
+	if err := rec.Decode(&sg); err != nil {
+		return "", err
+	}
+
+	input := &models.CreateSegmentInput{}
+	input.Name = sg.Name
+	input.Status = sg.Status
+	input.Metadata = sg.Metadata
+
+	created, err := s.im.e.Segments.CreateSegment(ctx, orgID, ledgerID, input)
+	if err != nil {
+		return "", err
+	}
+
+	return created.ID, nil
+}
+
+// importAccount creates an account, deferring it when its parent account comes
+// later in the dump.
+func (s *importSession) importAccount(ctx context.Context, orgID, ledgerID string, rec Record) (string, error) {
+	var acc models.Account
+	if err := rec.Decode(&acc); err != nil {
+		return "", err
+	}
+
+	if strings.EqualFold(acc.Type, accountTypeExternal) {
+		return "", nil
+	}
+
+	input := &models.CreateAccountInput{
+		Name:      acc.Name,
+		EntityID:  acc.EntityID,
+		AssetCode: acc.AssetCode,
+		Status:    acc.Status,
+		Alias:     acc.Alias,
+		Type:      acc.Type,
+		Metadata:  acc.Metadata,
+	}
+
+	var err error
+
+	if input.PortfolioID, err = s.mapOptional(KindPortfolio, acc.PortfolioID); err != nil {
+		return "", err
+	}
+
+	if input.SegmentID, err = s.mapOptional(KindSegment, acc.SegmentID); err != nil {
+		return "", err
+	}
+
+	if acc.ParentAccountID != nil && *acc.ParentAccountID != "" {
+		parent, ok := s.result.NewID(KindAccount, *acc.ParentAccountID)
+		if !ok {
+			s.deferred = append(s.deferred, rec)
+			return "", errDeferred
+		}
+
+		input.ParentAccountID = &parent
+	}
+
+	created, err := s.im.e.Accounts.CreateAccount(ctx, orgID, ledgerID, input)
+	if err != nil {
+		return "", err
+	}
+
+	return created.ID, nil
+}
+
+// errDeferred marks an account postponed until its parent is imported.
+var errDeferred = errors.New("deferred")
+
+// importDeferredAccounts retries accounts waiting for their parents until no
+// progress is made.
+func (s *importSession) importDeferredAccounts(ctx context.Context) error {
+	for len(s.deferred) > 0 {
+		pending := s.deferred
+		s.deferred = nil
+
+		for _, rec := range pending {
+			ledgerID, _ := s.result.NewID(KindLedger, rec.LedgerID)
+
+			newID, err := s.importAccount(ctx, s.result.OrganizationID, ledgerID, rec)
+			if errors.Is(err, errDeferred) {
+				continue
+			}
+
+			if err != nil {
+				return fmt.Errorf("failed to import %s %s: %w", KindAccount, rec.ID, err)
+			}
+
+			s.record(KindAccount, rec.ID, newID)
+		}
+
+		if len(s.deferred) == len(pending) {
+			return fmt.Errorf("%d accounts reference parent accounts missing from the dump", len(s.deferred))
+		}
+	}
+
+	return nil
+}
+
+func (s *importSession) importOperationRoute(ctx context.Context, orgID, ledgerID string, rec Record) (string, error) {
+	var route models.OperationRoute
+	if err := rec.Decode(&route); err != nil {
+		return "", err
+	}
+
+	input := models.NewCreateOperationRouteInput(route.Title, route.Description, route.OperationType).WithMetadata(route.Metadata)
+	input.Account = route.Account
+	input.AccountingEntries = route.AccountingEntries
+
+	created, err := s.im.e.OperationRoutes.CreateOperationRoute(ctx, orgID, ledgerID, input)
+	if err != nil {
+		return "", err
+	}
+
+	return created.ID.String(), nil
+}
+
+func (s *importSession) importTransactionRoute(ctx context.Context, orgID, ledgerID string, rec Record) (string, error) {
+	var route models.TransactionRoute
+	if err := rec.Decode(&route); err != nil {
+		return "", err
+	}
+
+	ids := make([]uuid.UUID, 0, len(route.OperationRoutes))
+
+	for _, or := range route.OperationRoutes {
+		newID, ok := s.result.NewID(KindOperationRoute, or.ID.String())
+		if !ok {
+			return "", fmt.Errorf("operation route %s was not imported", or.ID)
+		}
+
+		id, err := uuid.Parse(newID)
+		if err != nil {
+			return "", fmt.Errorf("invalid operation route ID %q: %w", newID, err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	input := &models.CreateTransactionRouteInput{}
+	input.Title = route.Title
+	input.Description = route.Description
+	input.Metadata = route.Metadata
+	input.OperationRoutes = ids
+
+	created, err := s.im.e.TransactionRoutes.CreateTransactionRoute(ctx, orgID, ledgerID, input)
+	if err != nil {
+		return "", err
+	}
+
+	return created.ID.String(), nil
+}
+
+// importTransaction replays a transaction from its operations. Cancelled and
+// failed transactions are skipped; the idempotency key is derived from the
+// original ID so an interrupted import can be run again.
+func (s *importSession) importTransaction(ctx context.Context, orgID, ledgerID string, rec Record) (string, error) {
+	var tx models.Transaction
+	if err := rec.Decode(&tx); err != nil {
+		return "", err
+	}
+
+	input, ok := TransactionInputFromOperations(&tx)
+	if !ok {
+		return "", nil
+	}
+
+	input.IdempotencyKey = "import-" + tx.ID
+
+	if routeID, ok := s.result.NewID(KindTransactionRoute, input.Route); ok {
+		input.Route = routeID
+	}
+
+	created, err := s.im.e.Transactions.CreateTransaction(ctx, orgID, ledgerID, input)
+	if err != nil {
+		return "", err
+	}
+
+	return created.ID, nil
+}
+
+// mapOptional maps an optional reference to an imported entity.
+func (s *importSession) mapOptional(kind Kind, oldID *string) (*string, error) {
+	if oldID == nil || *oldID == "" {
+		return nil, nil
+	}
+
+	newID, ok := s.result.NewID(kind, *oldID)
+	if !ok {
+		return nil, fmt.Errorf("%s %s was not imported", kind, *oldID)
+	}
+
+	return &newID, nil
+}
+
+// TransactionInputFromOperations rebuilds the input of a transaction from its
+// operations: debits become sources and credits destinations, keyed by account
+// alias. It returns false for transactions that should not be replayed, such as
+// cancelled or failed ones, or ones without both sides.
+func TransactionInputFromOperations(tx *models.Transaction) (*models.CreateTransactionInput, bool) {
+	switch strings.ToLower(tx.Status.Code) {
+	case models.TransactionStatusCancelled, "canceled", models.TransactionStatusFailed:
+		return nil, false
+	}
+
+	var debits, holds, credits []models.FromToInput
+
+	for _, op := range tx.Operations {
+		if op.Amount.Value == nil || op.AccountAlias == "" {
+			continue
+		}
+
+		leg := models.FromToInput{
+			Account:     op.AccountAlias,
+			Amount:      models.AmountInput{Asset: op.AssetCode, Value: op.Amount.Value.String()},
+			Description: op.Description,
+		}
+
+		switch strings.ToUpper(op.Type) {
+		case "DEBIT":
+			debits = append(debits, leg)
+		case "ON_HOLD":
+			holds = append(holds, leg)
+		case "CREDIT":
+			credits = append(credits, leg)
+		}
+	}
+
+	// pending transactions only hold funds on the source side
+	sources := debits
+	if len(sources) == 0 {
+		sources = holds
+	}
+
+	if len(sources) == 0 || len(credits) == 0 {
+		return nil, false
+	}
+
+	total := decimal.Zero
+
+	for _, leg := range sources {
+		v, err := decimal.NewFromString(leg.Amount.Value)
+		if err != nil {
+			return nil, false
+		}
+
+		total = total.Add(v)
+	}
+
+	return &models.CreateTransactionInput{
+		Description: tx.Description,
+		Amount:      total.String(),
+		AssetCode:   tx.AssetCode,
+		Pending:     tx.Pending || strings.EqualFold(tx.Status.Code, models.TransactionStatusPending),
+		Route:       tx.Route,
+		ExternalID:  tx.ExternalID,
+		Metadata:    tx.Metadata,
+		Send: &models.SendInput{
+			Asset:      tx.AssetCode,
+			Value:      total.String(),
+			Source:     &models.SourceInput{From: sources},
+			Distribute: &models.DistributeInput{To: credits},
+		},
+	}, true
+}
@@ -1,6 +1,8 @@
 package format
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -151,6 +153,49 @@ func ParseISO(s string) (time.Time, error) {
 	return time.Parse(DateFormat, s)
 }
 
+// timestampLayouts are the layouts accepted by ParseTimestamp with a zone.
+// Fractional seconds are optional in each of them.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999 Z07:00",
+	"2006-01-02 15:04:05.999999999 Z0700",
+}
+
+// localTimestampLayouts are the layouts accepted by ParseTimestamp without a
+// zone, read as UTC.
+var localTimestampLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	DateFormat,
+}
+
+// ParseTimestamp parses a timestamp more tolerantly than ParseISO, accepting
+// the RFC 3339 variants found in API responses: a space instead of the "T",
+// lower-case "t" and "z", offsets without a colon or minutes, and timestamps
+// or dates without a zone, which are read as UTC.
+func ParseTimestamp(s string) (time.Time, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	for _, layout := range localTimestampLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", s)
+}
+
 // Backward compatibility aliases - deprecated, use the shorter names instead
 
 // FormatISO is deprecated, use ISO instead.
@@ -161,3 +161,37 @@ func TestParseISO(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTimestamp(t *testing.T) {
+	utc := time.Date(2025, 4, 2, 15, 4, 5, 0, time.UTC)
+	saoPaulo := time.Date(2025, 4, 2, 12, 4, 5, 0, time.FixedZone("", -3*3600))
+
+	testCases := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"2025-04-02T15:04:05Z", utc},
+		{"2025-04-02T15:04:05.5Z", utc.Add(500 * time.Millisecond)},
+		{"2025-04-02t15:04:05z", utc},
+		{"2025-04-02 15:04:05Z", utc},
+		{"2025-04-02T12:04:05-03:00", saoPaulo},
+		{"2025-04-02T12:04:05-0300", saoPaulo},
+		{"2025-04-02T12:04:05-03", saoPaulo},
+		{"2025-04-02 12:04:05 -03:00", saoPaulo},
+		{"2025-04-02T15:04:05", utc},
+		{"2025-04-02 15:04:05.000000", utc},
+		{"2025-04-02T15:04", utc.Add(-5 * time.Second)},
+		{"2025-04-02", time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			result, err := format.ParseTimestamp(tc.input)
+			require.NoError(t, err)
+			assert.True(t, tc.expected.Equal(result), "got %s", result)
+		})
+	}
+
+	_, err := format.ParseTimestamp("02/04/2025")
+	assert.ErrorContains(t, err, `unrecognized timestamp "02/04/2025"`)
+}
@@ -0,0 +1,243 @@
+// Package fsutil provides the file systems the SDK writes its outputs to:
+// exports, closing artifacts, reports and checkpoints.
+//
+// An FS is an io/fs.FS that can also create files. Names are slash-separated
+// and relative, as in io/fs, whatever the operating system, so the same code
+// writes "eod/2025-01-31/report.json" on Linux, macOS and Windows. Names that
+// would escape the file system, or that are not portable, such as those with
+// a backslash or a colon, are rejected with fs.ErrInvalid.
+//
+// Two implementations are provided:
+//
+//   - Dir writes to a directory of the operating system
+//   - MapFS keeps the files in memory, for tests and for containers with a
+//     read-only root filesystem that upload their outputs elsewhere
+//
+// Files are replaced atomically: a reader, or a crash, sees either the old
+// or the new content, never a partial file.
+//
+// Example:
+//
+//	fsys := fsutil.Dir(os.Getenv("OUTPUT_DIR"))
+//	manifest, err := export.NewExporter(client.Entity).ExportFS(ctx, orgID, fsys)
+package fsutil
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing/fstest"
+)
+
+// FS is a file system the SDK can read from and write to.
+type FS interface {
+	fs.FS
+
+	// Create returns a writer for the file name, creating its parent
+	// directories. The file is only created, or replaced, when the writer is
+	// closed.
+	Create(name string) (io.WriteCloser, error)
+}
+
+// WriteFile replaces the file name of fsys with data.
+func WriteFile(fsys FS, name string, data []byte) error {
+	w, err := fsys.Create(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		_ = w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// CheckName returns an fs.ErrInvalid error unless name is a valid, portable
+// file name: slash-separated, relative, without "." or ".." elements, and
+// without the characters Windows doesn't allow in names.
+func CheckName(op, name string) error {
+	if !fs.ValidPath(name) || name == "." || strings.ContainsAny(name, `\:*?"<>|`) {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	return nil
+}
+
+// dirFS is the FS of a directory of the operating system.
+type dirFS struct {
+	fs.FS
+	dir string
+}
+
+// Dir returns the FS of the directory dir, created with its first file.
+// Files are written with mode 0600, as they may hold account data.
+func Dir(dir string) FS {
+	return &dirFS{FS: os.DirFS(dir), dir: dir}
+}
+
+// Create implements FS.
+func (d *dirFS) Create(name string) (io.WriteCloser, error) {
+	if err := CheckName("create", name); err != nil {
+		return nil, err
+	}
+
+	target := filepath.Join(d.dir, filepath.FromSlash(name))
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return nil, err
+	}
+
+	return createAtomic(target)
+}
+
+// atomicFile is a temporary file renamed to its target when closed.
+type atomicFile struct {
+	*os.File
+	target string
+	closed bool
+}
+
+// Close implements io.Closer.
+func (f *atomicFile) Close() error {
+	if f.closed {
+		return nil
+	}
+
+	f.closed = true
+
+	err := f.File.Close()
+	if err == nil {
+		err = os.Rename(f.File.Name(), f.target)
+	}
+
+	if err != nil {
+		_ = os.Remove(f.File.Name())
+	}
+
+	return err
+}
+
+// createAtomic returns an atomicFile replacing target.
+func createAtomic(target string) (*atomicFile, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+
+	return &atomicFile{File: tmp, target: target}, nil
+}
+
+// WriteFileAtomic replaces the file at path with data, with mode 0600, through
+// a temporary file of the same directory, so that a crash leaves either the
+// old or the new content. The directory of path must exist. It is meant for
+// the checkpoints and reports written to a path of the operating system,
+// which is not checked with CheckName.
+func WriteFileAtomic(path string, data []byte) error {
+	f, err := createAtomic(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// MapFS is an FS keeping its files in memory. It is safe for concurrent use;
+// the zero value is an empty file system.
+type MapFS struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+// NewMapFS returns an empty MapFS.
+func NewMapFS() *MapFS {
+	return &MapFS{}
+}
+
+// Open implements fs.FS.
+func (m *MapFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot := make(fstest.MapFS, len(m.files))
+	for file, data := range m.files {
+		snapshot[file] = &fstest.MapFile{Data: data, Mode: 0o600}
+	}
+
+	return snapshot.Open(name)
+}
+
+// Create implements FS.
+func (m *MapFS) Create(name string) (io.WriteCloser, error) {
+	if err := CheckName("create", name); err != nil {
+		return nil, err
+	}
+
+	return &mapFile{m: m, name: name}, nil
+}
+
+// Names returns the names of the files, sorted.
+func (m *MapFS) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// mapFile buffers the content of a file of a MapFS until it is closed.
+type mapFile struct {
+	bytes.Buffer
+	m      *MapFS
+	name   string
+	closed bool
+}
+
+// Close implements io.Closer.
+func (f *mapFile) Close() error {
+	if f.closed {
+		return nil
+	}
+
+	f.closed = true
+
+	f.m.mu.Lock()
+	defer f.m.mu.Unlock()
+
+	if f.m.files == nil {
+		f.m.files = make(map[string][]byte)
+	}
+
+	for dir := path.Dir(f.name); dir != "."; dir = path.Dir(dir) {
+		if _, ok := f.m.files[dir]; ok {
+			return &fs.PathError{Op: "create", Path: f.name, Err: errors.New("parent is a file")}
+		}
+	}
+
+	f.m.files[f.name] = bytes.Clone(f.Bytes())
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package fsutil
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDir(t *testing.T) {
+	dir := t.TempDir()
+	fsys := Dir(dir)
+
+	require.NoError(t, WriteFile(fsys, "eod/2025-01-31/report.json", []byte("v1")))
+	require.NoError(t, WriteFile(fsys, "eod/2025-01-31/report.json", []byte("v2")))
+
+	raw, err := fs.ReadFile(fsys, "eod/2025-01-31/report.json")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(raw))
+
+	raw, err = os.ReadFile(filepath.Join(dir, "eod", "2025-01-31", "report.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(raw))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "eod", "2025-01-31"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files must not be left behind")
+}
+
+func TestDir_NotWrittenUntilClosed(t *testing.T) {
+	fsys := Dir(t.TempDir())
+
+	w, err := fsys.Create("report.json")
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("partial"))
+	require.NoError(t, err)
+
+	_, err = fs.Stat(fsys, "report.json")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close(), "Close must be idempotent")
+
+	_, err = fs.Stat(fsys, "report.json")
+	assert.NoError(t, err)
+}
+
+func TestCheckName(t *testing.T) {
+	for _, name := range []string{"report.json", "eod/2025-01-31/report.json"} {
+		assert.NoError(t, CheckName("create", name), name)
+	}
+
+	for _, name := range []string{"", ".", "../report.json", "/etc/passwd", `eod\report.json`, "c:report.json", "a/./b", "report?.json"} {
+		err := CheckName("create", name)
+		assert.True(t, errors.Is(err, fs.ErrInvalid), "%q: %v", name, err)
+	}
+}
+
+func TestCreate_RejectsInvalidNames(t *testing.T) {
+	for _, fsys := range []FS{Dir(t.TempDir()), NewMapFS()} {
+		_, err := fsys.Create("../escape.json")
+		assert.True(t, errors.Is(err, fs.ErrInvalid), "%T: %v", fsys, err)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	require.NoError(t, WriteFileAtomic(path, []byte(`{"page":1}`)))
+	require.NoError(t, WriteFileAtomic(path, []byte(`{"page":2}`)))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"page":2}`, string(raw))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	err = WriteFileAtomic(filepath.Join(filepath.Dir(path), "missing", "checkpoint.json"), nil)
+	assert.True(t, errors.Is(err, fs.ErrNotExist), "the directory must exist: %v", err)
+}
+
+func TestMapFS(t *testing.T) {
+	fsys := NewMapFS()
+
+	require.NoError(t, WriteFile(fsys, "b/report.json", []byte("b")))
+	require.NoError(t, WriteFile(fsys, "a.json", []byte("a")))
+
+	assert.Equal(t, []string{"a.json", "b/report.json"}, fsys.Names())
+
+	raw, err := fs.ReadFile(fsys, "b/report.json")
+	require.NoError(t, err)
+	assert.Equal(t, "b", string(raw))
+
+	entries, err := fs.ReadDir(fsys, "b")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "report.json", entries[0].Name())
+
+	_, err = fsys.Open("../a.json")
+	assert.True(t, errors.Is(err, fs.ErrInvalid))
+
+	err = WriteFile(fsys, "a.json/c", []byte("c"))
+	assert.Error(t, err, "a file cannot be the parent of another")
+}
+
+func TestMapFS_ZeroValue(t *testing.T) {
+	var fsys MapFS
+
+	_, err := fsys.Open("missing")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	require.NoError(t, WriteFile(&fsys, "x", []byte("x")))
+	assert.Equal(t, []string{"x"}, fsys.Names())
+}
@@ -3,14 +3,17 @@ package generator
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/stats"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
 )
 
 type accountGenerator struct {
@@ -42,7 +45,24 @@ func (g *accountGenerator) Generate(ctx context.Context, orgID, ledgerID, assetC
 	g.applyTemplateFields(in, t)
 	g.setupAccountTypeMetadata(in, t)
 
-	return g.createAccount(ctx, orgID, ledgerID, in)
+	policies := getAliasPolicies(ctx)
+	if policies == nil || in.Alias == nil {
+		return g.createAccount(ctx, orgID, ledgerID, in)
+	}
+
+	req := validation.AliasRequest{OrganizationID: orgID, LedgerID: ledgerID, AccountType: t.Type, Alias: *in.Alias}
+	if err := policies.Claim(req); err != nil {
+		recordEvent(ctx, eventlog.EntityAccount, "", orgID, ledgerID, time.Now(), err)
+
+		return nil, err
+	}
+
+	acc, err := g.createAccount(ctx, orgID, ledgerID, in)
+	if err != nil {
+		policies.Release(req)
+	}
+
+	return acc, err
 }
 
 // validateInputs validates the required inputs for account generation
@@ -128,6 +148,8 @@ func (*accountGenerator) applyInferredAccountTypeKey(in *models.CreateAccountInp
 func (g *accountGenerator) createAccount(ctx context.Context, orgID, ledgerID string, in *models.CreateAccountInput) (*models.Account, error) {
 	var out *models.Account
 
+	started := time.Now()
+
 	err := observability.WithSpan(ctx, g.obs, "GenerateAccount", func(ctx context.Context) error {
 		return executeWithCircuitBreaker(ctx, func() error {
 			return retry.DoWithContext(ctx, func() error {
@@ -143,9 +165,13 @@ func (g *accountGenerator) createAccount(ctx context.Context, orgID, ledgerID st
 		})
 	})
 	if err != nil {
+		recordEvent(ctx, eventlog.EntityAccount, "", orgID, ledgerID, started, err)
+
 		return nil, err
 	}
 
+	recordEvent(ctx, eventlog.EntityAccount, out.ID, orgID, ledgerID, started, nil)
+
 	return out, nil
 }
 
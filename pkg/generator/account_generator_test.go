@@ -10,6 +10,7 @@ import (
 	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -50,6 +51,10 @@ func (*mockAccountsService) GetAccountByAlias(_ context.Context, _, _, _ string)
 	return nil, errors.New("mock: GetAccountByAlias not implemented")
 }
 
+func (*mockAccountsService) FindByAlias(_ context.Context, _, _, _ string) (*models.Account, error) {
+	return nil, errors.New("mock: FindByAlias not implemented")
+}
+
 func (*mockAccountsService) GetAccountByAliasPath(_ context.Context, _, _, _ string) (*models.Account, error) {
 	return nil, errors.New("mock: GetAccountByAliasPath not implemented")
 }
@@ -236,6 +241,56 @@ func TestAccountGenerator_Generate_WithAlias(t *testing.T) {
 	assert.NotNil(t, capturedInput)
 }
 
+func TestAccountGenerator_Generate_WithAliasPolicies(t *testing.T) {
+	var created atomic.Int32
+
+	fail := false
+	mockSvc := &mockAccountsService{
+		createFunc: func(_ context.Context, _, _ string, input *models.CreateAccountInput) (*models.Account, error) {
+			if fail {
+				return nil, errors.New("account creation failed")
+			}
+
+			created.Add(1)
+
+			return &models.Account{ID: "acc-" + *input.Alias}, nil
+		},
+	}
+
+	policies := validation.NewAliasPolicies()
+	require.NoError(t, policies.Register("org-123", validation.AliasPolicy{
+		TypePrefixes: map[string]string{"deposit": "dep_"},
+		UniqueWithin: validation.AliasScopeOrganization,
+	}))
+
+	ctx := WithAliasPolicies(context.Background(), policies)
+	gen := NewAccountGenerator(&entities.Entity{Accounts: mockSvc}, nil)
+
+	generate := func(ledgerID, alias string) error {
+		_, err := gen.Generate(ctx, "org-123", ledgerID, "USD", data.AccountTemplate{Name: alias, Type: "deposit", Alias: &alias})
+		return err
+	}
+
+	require.NoError(t, generate("ledger-1", "dep_alice"))
+
+	var fieldErrs *validation.FieldErrors
+
+	err := generate("ledger-1", "alice")
+	require.ErrorAs(t, err, &fieldErrs)
+	assert.Equal(t, "prefix", fieldErrs.Errors[0].Constraint)
+
+	err = generate("ledger-2", "dep_alice")
+	require.ErrorAs(t, err, &fieldErrs)
+	assert.Equal(t, "unique", fieldErrs.Errors[0].Constraint)
+	assert.Equal(t, int32(1), created.Load(), "violations fail before creation")
+
+	fail = true
+	require.Error(t, generate("ledger-1", "dep_bob"))
+
+	fail = false
+	require.NoError(t, generate("ledger-1", "dep_bob"), "failed creations release their alias")
+}
+
 func TestAccountGenerator_Generate_WithParentAccount(t *testing.T) {
 	var capturedInput *models.CreateAccountInput
 
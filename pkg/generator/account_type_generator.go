@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
 )
@@ -31,6 +33,8 @@ func (g *accountTypeGenerator) Generate(ctx context.Context, orgID, ledgerID str
 
 	var out *models.AccountType
 
+	started := time.Now()
+
 	err := observability.WithSpan(ctx, g.obs, "GenerateAccountType", func(ctx context.Context) error {
 		return executeWithCircuitBreaker(ctx, func() error {
 			return retry.DoWithContext(ctx, func() error {
@@ -46,9 +50,13 @@ func (g *accountTypeGenerator) Generate(ctx context.Context, orgID, ledgerID str
 		})
 	})
 	if err != nil {
+		recordEvent(ctx, eventlog.EntityAccountType, "", orgID, ledgerID, started, err)
+
 		return nil, err
 	}
 
+	recordEvent(ctx, eventlog.EntityAccountType, out.ID.String(), orgID, ledgerID, started, nil)
+
 	return out, nil
 }
 
@@ -3,10 +3,12 @@ package generator
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
 )
@@ -36,6 +38,13 @@ func (g *assetGenerator) Generate(ctx context.Context, ledgerID string, template
 		return nil, errors.New("organization id missing in context for asset creation")
 	}
 
+	if getIfNotExists(ctx) {
+		existing, err := g.findAsset(ctx, orgID, ledgerID, template)
+		if err != nil || existing != nil {
+			return existing, err
+		}
+	}
+
 	input := models.NewCreateAssetInput(template.Name, template.Code).
 		WithType(template.Type).
 		WithStatus(models.NewStatus(models.StatusActive)).
@@ -43,6 +52,8 @@ func (g *assetGenerator) Generate(ctx context.Context, ledgerID string, template
 
 	var out *models.Asset
 
+	started := time.Now()
+
 	err := observability.WithSpan(ctx, g.obs, "GenerateAsset", func(ctx context.Context) error {
 		return executeWithCircuitBreaker(ctx, func() error {
 			return retry.DoWithContext(ctx, func() error {
@@ -58,9 +69,13 @@ func (g *assetGenerator) Generate(ctx context.Context, ledgerID string, template
 		})
 	})
 	if err != nil {
+		recordEvent(ctx, eventlog.EntityAsset, "", orgID, ledgerID, started, err)
+
 		return nil, err
 	}
 
+	recordEvent(ctx, eventlog.EntityAsset, out.ID, orgID, ledgerID, started, nil)
+
 	return out, nil
 }
 
@@ -14,6 +14,7 @@ import (
 
 type mockAssetsService struct {
 	createFunc func(ctx context.Context, orgID, ledgerID string, input *models.CreateAssetInput) (*models.Asset, error)
+	listFunc   func(ctx context.Context, orgID, ledgerID string, opts *models.ListOptions) (*models.ListResponse[models.Asset], error)
 }
 
 func (m *mockAssetsService) CreateAsset(ctx context.Context, orgID, ledgerID string, input *models.CreateAssetInput) (*models.Asset, error) {
@@ -28,10 +29,18 @@ func (*mockAssetsService) GetAsset(_ context.Context, _, _, _ string) (*models.A
 	return nil, errors.New("mock: GetAsset not implemented")
 }
 
-func (*mockAssetsService) ListAssets(_ context.Context, _, _ string, _ *models.ListOptions) (*models.ListResponse[models.Asset], error) {
+func (m *mockAssetsService) ListAssets(ctx context.Context, orgID, ledgerID string, opts *models.ListOptions) (*models.ListResponse[models.Asset], error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, orgID, ledgerID, opts)
+	}
+
 	return nil, errors.New("mock: ListAssets not implemented")
 }
 
+func (*mockAssetsService) FindByCode(_ context.Context, _, _, _ string) (*models.Asset, error) {
+	return nil, errors.New("mock: FindByCode not implemented")
+}
+
 func (*mockAssetsService) UpdateAsset(_ context.Context, _, _, _ string, _ *models.UpdateAssetInput) (*models.Asset, error) {
 	return nil, errors.New("mock: UpdateAsset not implemented")
 }
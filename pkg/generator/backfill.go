@@ -0,0 +1,540 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+)
+
+// BackfillKind is the kind of entity created by a phase of a backfill plan.
+type BackfillKind string
+
+const (
+	// BackfillOrganizations creates organizations.
+	BackfillOrganizations BackfillKind = "organizations"
+	// BackfillLedgers creates ledgers.
+	BackfillLedgers BackfillKind = "ledgers"
+	// BackfillAccounts creates accounts.
+	BackfillAccounts BackfillKind = "accounts"
+	// BackfillTransactions creates transactions.
+	BackfillTransactions BackfillKind = "transactions"
+)
+
+const (
+	// DefaultBackfillRequestsPerSecond is the sustained write rate assumed when no hint is given.
+	DefaultBackfillRequestsPerSecond = 50
+
+	// DefaultBackfillConcurrency is the number of batches in flight assumed when no hint is given.
+	DefaultBackfillConcurrency = 8
+
+	// DefaultBackfillBatchSize is the number of items per batch when no hint is given.
+	DefaultBackfillBatchSize = 100
+
+	// DefaultBackfillPhaseDuration is the planned duration of a full-rate phase.
+	DefaultBackfillPhaseDuration = 5 * time.Minute
+
+	// DefaultBackfillMaxErrorRate is the failure ratio of a phase above which
+	// the following phases are slowed down.
+	DefaultBackfillMaxErrorRate = 0.05
+
+	// minBackfillRateScale bounds the slowdown after failing phases
+	minBackfillRateScale = 0.125
+
+	// maxBackfillFailures caps the failures kept in the progress
+	maxBackfillFailures = 100
+
+	// backfillCheckpointInterval is the minimum time between two checkpoints within a phase
+	backfillCheckpointInterval = 5 * time.Second
+)
+
+// backfillWarmup lists the fractions of the full rate of the warmup phases
+// that precede the accounts and transactions at full rate.
+var backfillWarmup = []float64{0.25, 0.5}
+
+// ErrBackfillCheckpointMismatch is returned when the checkpoint file belongs to another plan.
+var ErrBackfillCheckpointMismatch = errors.New("checkpoint belongs to another backfill plan")
+
+// BackfillTargets is the size of the dataset to create.
+type BackfillTargets struct {
+	Organizations int `json:"organizations"`
+
+	// LedgersPerOrganization defaults to 1
+	LedgersPerOrganization int `json:"ledgersPerOrganization"`
+
+	// Accounts and Transactions are totals across all ledgers
+	Accounts     int `json:"accounts"`
+	Transactions int `json:"transactions"`
+}
+
+// CapacityHints describe what the backend sustains. Zero fields take the
+// DefaultBackfill* values.
+type CapacityHints struct {
+	// RequestsPerSecond is the sustained write rate, one request per entity
+	RequestsPerSecond int
+
+	// Concurrency is the number of batches in flight
+	Concurrency int
+
+	// BatchSize is the number of accounts or transactions per batch, the unit
+	// of work handed to the handlers
+	BatchSize int
+
+	// PhaseDuration is the planned duration of a full-rate phase; a checkpoint
+	// is always written at the end of a phase
+	PhaseDuration time.Duration
+
+	// MaxErrorRate is the failure ratio of a phase above which the rate and
+	// concurrency of the following phases are halved
+	MaxErrorRate float64
+
+	// NoWarmup starts accounts and transactions at full rate instead of
+	// ramping up through a quarter and half of it
+	NoWarmup bool
+}
+
+// withDefaults returns the hints with defaults for the zero fields.
+func (h CapacityHints) withDefaults() CapacityHints {
+	if h.RequestsPerSecond <= 0 {
+		h.RequestsPerSecond = DefaultBackfillRequestsPerSecond
+	}
+
+	if h.Concurrency <= 0 {
+		h.Concurrency = DefaultBackfillConcurrency
+	}
+
+	if h.BatchSize <= 0 {
+		h.BatchSize = DefaultBackfillBatchSize
+	}
+
+	if h.PhaseDuration <= 0 {
+		h.PhaseDuration = DefaultBackfillPhaseDuration
+	}
+
+	if h.MaxErrorRate <= 0 {
+		h.MaxErrorRate = DefaultBackfillMaxErrorRate
+	}
+
+	return h
+}
+
+// BackfillPhase is a step of a backfill plan: Count entities of Kind, from
+// index Start, created in batches of BatchSize at up to RatePerSecond.
+type BackfillPhase struct {
+	Name          string        `json:"name"`
+	Kind          BackfillKind  `json:"kind"`
+	Start         int           `json:"start"`
+	Count         int           `json:"count"`
+	BatchSize     int           `json:"batchSize"`
+	Concurrency   int           `json:"concurrency"`
+	RatePerSecond float64       `json:"ratePerSecond"`
+	Estimated     time.Duration `json:"estimated"`
+}
+
+// BackfillPlan is the phased execution plan of a backfill.
+type BackfillPlan struct {
+	Targets   BackfillTargets `json:"targets"`
+	Hints     CapacityHints   `json:"hints"`
+	Phases    []BackfillPhase `json:"phases"`
+	Estimated time.Duration   `json:"estimated"`
+}
+
+// PlanBackfill derives a phased plan creating targets within the capacity of
+// the backend: organizations, then ledgers, then accounts, then transactions,
+// the latter two split into phases of about hints.PhaseDuration, after a
+// warmup at a quarter and half of the full rate that lets a cold backend
+// scale up before it is loaded.
+//
+// Example:
+//
+//	plan, err := generator.PlanBackfill(
+//	    generator.BackfillTargets{Organizations: 10, Accounts: 100_000, Transactions: 10_000_000},
+//	    generator.CapacityHints{RequestsPerSecond: 500, Concurrency: 32},
+//	)
+func PlanBackfill(targets BackfillTargets, hints CapacityHints) (*BackfillPlan, error) {
+	if targets.LedgersPerOrganization == 0 {
+		targets.LedgersPerOrganization = 1
+	}
+
+	switch {
+	case targets.Organizations < 0 || targets.LedgersPerOrganization < 0 || targets.Accounts < 0 || targets.Transactions < 0:
+		return nil, errors.New("backfill targets cannot be negative")
+	case targets.Accounts > 0 && targets.Organizations == 0:
+		return nil, errors.New("accounts need at least one organization")
+	case targets.Transactions > 0 && targets.Accounts == 0:
+		return nil, errors.New("transactions need at least one account")
+	}
+
+	hints = hints.withDefaults()
+	plan := &BackfillPlan{Targets: targets, Hints: hints}
+	rate := float64(hints.RequestsPerSecond)
+
+	plan.addPhases(BackfillOrganizations, targets.Organizations, 1, rate, false)
+	plan.addPhases(BackfillLedgers, targets.Organizations*targets.LedgersPerOrganization, 1, rate, false)
+	plan.addPhases(BackfillAccounts, targets.Accounts, hints.BatchSize, rate, !hints.NoWarmup)
+	plan.addPhases(BackfillTransactions, targets.Transactions, hints.BatchSize, rate, !hints.NoWarmup)
+
+	for _, phase := range plan.Phases {
+		plan.Estimated += phase.Estimated
+	}
+
+	return plan, nil
+}
+
+// addPhases appends the phases creating count entities of kind.
+func (p *BackfillPlan) addPhases(kind BackfillKind, count, batchSize int, rate float64, warmup bool) {
+	start, number := 0, 0
+
+	add := func(n int, rate float64) {
+		n = min(n, count-start)
+		if n <= 0 {
+			return
+		}
+
+		number++
+		p.Phases = append(p.Phases, BackfillPhase{
+			Name:          fmt.Sprintf("%s-%d", kind, number),
+			Kind:          kind,
+			Start:         start,
+			Count:         n,
+			BatchSize:     batchSize,
+			Concurrency:   min(p.Hints.Concurrency, (n+batchSize-1)/batchSize),
+			RatePerSecond: rate,
+			Estimated:     time.Duration(float64(n) / rate * float64(time.Second)),
+		})
+		start += n
+	}
+
+	// phases are whole batches, so that a batch never spans two phases
+	phaseSize := func(rate float64, d time.Duration) int {
+		n := int(math.Ceil(rate * d.Seconds()))
+		return max(batchSize, (n+batchSize-1)/batchSize*batchSize)
+	}
+
+	if warmup {
+		for _, fraction := range backfillWarmup {
+			add(phaseSize(rate*fraction, p.Hints.PhaseDuration/4), rate*fraction)
+		}
+	}
+
+	for start < count {
+		add(phaseSize(rate, p.Hints.PhaseDuration), rate)
+	}
+}
+
+// BackfillBatch is a unit of work of a backfill: Count entities of Kind, from
+// index Start within the kind.
+type BackfillBatch struct {
+	Phase string
+	Kind  BackfillKind
+	Start int
+	Count int
+}
+
+// BackfillFunc creates the entities of a batch. Batches interrupted by a
+// crash are run again on resume, so it must be idempotent, e.g. by deriving
+// idempotency keys from the indexes of the entities.
+type BackfillFunc func(ctx context.Context, batch BackfillBatch) error
+
+// BackfillHandlers create the entities of a backfill, one per kind. Index i
+// of a kind is the i-th entity of that kind in the whole dataset: ledger i
+// belongs to organization i / LedgersPerOrganization, and handlers spread
+// accounts and transactions over the ledgers and accounts.
+type BackfillHandlers struct {
+	Organizations BackfillFunc
+	Ledgers       BackfillFunc
+	Accounts      BackfillFunc
+	Transactions  BackfillFunc
+}
+
+// handler returns the handler of kind.
+func (h BackfillHandlers) handler(kind BackfillKind) BackfillFunc {
+	switch kind {
+	case BackfillOrganizations:
+		return h.Organizations
+	case BackfillLedgers:
+		return h.Ledgers
+	case BackfillAccounts:
+		return h.Accounts
+	case BackfillTransactions:
+		return h.Transactions
+	}
+
+	return nil
+}
+
+// BackfillFailure is a batch whose handler failed.
+type BackfillFailure struct {
+	Phase string       `json:"phase"`
+	Kind  BackfillKind `json:"kind"`
+	Start int          `json:"start"`
+	Count int          `json:"count"`
+	Error string       `json:"error"`
+}
+
+// BackfillProgress is the state of a backfill, saved as its checkpoint.
+type BackfillProgress struct {
+	// Targets and Hints identify the plan of the checkpoint
+	Targets BackfillTargets `json:"targets"`
+	Hints   CapacityHints   `json:"hints"`
+
+	// Phase is the index of the current phase, and Processed the number of its
+	// entities whose batches, and those of all the entities before, returned
+	Phase     int `json:"phase"`
+	Processed int `json:"processed"`
+
+	// Created counts the entities of each kind in succeeded batches; batches
+	// run again on resume are counted again
+	Created map[BackfillKind]int `json:"created"`
+
+	// Failed counts the entities of failed batches, the first of which are in Failures
+	Failed   int               `json:"failed"`
+	Failures []BackfillFailure `json:"failures,omitempty"`
+
+	// RateScale is the slowdown applied after phases with too many failures
+	RateScale float64 `json:"rateScale"`
+
+	Completed bool      `json:"completed"`
+	Resumed   bool      `json:"-"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// RunBackfillPlan runs the phases of plan in order, the batches of a phase
+// concurrently under its rate and concurrency. When a phase fails more than
+// plan.Hints.MaxErrorRate of its entities, the rate and concurrency of the
+// following phases are halved, down to an eighth.
+//
+// With a checkpoint file, the progress is saved during each phase and at its
+// end, and a run of the same plan with the same file resumes from it; a
+// completed checkpoint makes RunBackfillPlan return immediately, and a
+// checkpoint of another plan is rejected with ErrBackfillCheckpointMismatch. Failed batches are recorded in
+// the progress and don't stop the run. RunBackfillPlan returns an error when
+// a handler is missing, the checkpoint can't be read or written or ctx is
+// done; the progress up to that point is returned with it.
+//
+// Example:
+//
+//	progress, err := generator.RunBackfillPlan(ctx, plan, generator.BackfillHandlers{
+//	    Organizations: createOrgs,
+//	    Ledgers:       createLedgers,
+//	    Accounts:      createAccounts,
+//	    Transactions:  createTransactions,
+//	}, "./backfill-10m.json")
+func RunBackfillPlan(ctx context.Context, plan *BackfillPlan, handlers BackfillHandlers, checkpointFile string) (*BackfillProgress, error) {
+	if plan == nil {
+		return nil, errors.New("backfill plan is required")
+	}
+
+	for _, phase := range plan.Phases {
+		if handlers.handler(phase.Kind) == nil {
+			return nil, fmt.Errorf("no backfill handler for %s", phase.Kind)
+		}
+	}
+
+	progress, err := loadBackfillCheckpoint(checkpointFile, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	for !progress.Completed {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+
+		if progress.Phase >= len(plan.Phases) {
+			progress.Completed = true
+			break
+		}
+
+		r := &backfillRun{plan: plan, progress: progress, checkpointFile: checkpointFile}
+		if err := r.runPhase(ctx, plan.Phases[progress.Phase], handlers.handler(plan.Phases[progress.Phase].Kind)); err != nil {
+			return progress, err
+		}
+	}
+
+	return progress, saveBackfillCheckpoint(checkpointFile, progress)
+}
+
+// backfillRun runs a phase, updating the shared progress.
+type backfillRun struct {
+	plan           *BackfillPlan
+	progress       *BackfillProgress
+	checkpointFile string
+
+	mu       sync.Mutex
+	done     []bool
+	next     int
+	failed   int
+	saveErr  error
+	lastSave time.Time
+}
+
+// runPhase runs the batches of phase from the processed ones, then moves the progress to the next phase.
+func (r *backfillRun) runPhase(ctx context.Context, phase BackfillPhase, handle BackfillFunc) error {
+	scale := r.progress.RateScale
+	rate := phase.RatePerSecond * scale
+	workers := max(1, int(float64(phase.Concurrency)*scale))
+
+	batches := (phase.Count + phase.BatchSize - 1) / phase.BatchSize
+	r.done = make([]bool, batches)
+	r.next = r.progress.Processed / phase.BatchSize
+	r.lastSave = time.Now()
+
+	first := r.next
+
+	var (
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, workers)
+		stop error
+	)
+
+	start := time.Now()
+
+	for i := first; i < batches && stop == nil; i++ {
+		offset := i * phase.BatchSize
+		at := start.Add(time.Duration(float64(offset-first*phase.BatchSize) / rate * float64(time.Second)))
+
+		if stop = waitUntil(ctx, at); stop != nil {
+			break
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			stop = ctx.Err()
+			continue
+		}
+
+		batch := BackfillBatch{Phase: phase.Name, Kind: phase.Kind, Start: phase.Start + offset, Count: min(phase.BatchSize, phase.Count-offset)}
+
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			r.finish(i, batch, handle(ctx, batch), phase)
+		}(i)
+	}
+
+	wg.Wait()
+
+	if stop != nil {
+		// batches interrupted by the cancellation are run again on resume
+		return errors.Join(stop, r.saveErr)
+	}
+
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+
+	if phase.Count > 0 && float64(r.failed)/float64(phase.Count) > r.plan.Hints.MaxErrorRate {
+		r.progress.RateScale = max(minBackfillRateScale, r.progress.RateScale/2)
+	}
+
+	r.progress.Phase++
+	r.progress.Processed = 0
+
+	return saveBackfillCheckpoint(r.checkpointFile, r.progress)
+}
+
+// finish records the outcome of batch i, advancing the processed entities
+// over the batches that returned in order.
+func (r *backfillRun) finish(i int, batch BackfillBatch, err error, phase BackfillPhase) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// a batch canceled with the run is neither created nor failed: it runs again on resume
+	if err != nil && errors.Is(err, context.Canceled) {
+		return
+	}
+
+	if err != nil {
+		r.failed += batch.Count
+		r.progress.Failed += batch.Count
+
+		if len(r.progress.Failures) < maxBackfillFailures {
+			r.progress.Failures = append(r.progress.Failures, BackfillFailure{Phase: batch.Phase, Kind: batch.Kind, Start: batch.Start, Count: batch.Count, Error: err.Error()})
+		}
+	} else {
+		r.progress.Created[batch.Kind] += batch.Count
+	}
+
+	r.done[i] = true
+	for r.next < len(r.done) && r.done[r.next] {
+		r.next++
+	}
+
+	r.progress.Processed = min(r.next*phase.BatchSize, phase.Count)
+
+	if r.checkpointFile != "" && time.Since(r.lastSave) >= backfillCheckpointInterval {
+		r.lastSave = time.Now()
+		if err := saveBackfillCheckpoint(r.checkpointFile, r.progress); err != nil && r.saveErr == nil {
+			r.saveErr = err
+		}
+	}
+}
+
+// loadBackfillCheckpoint reads the checkpoint file, or starts a new progress.
+func loadBackfillCheckpoint(path string, plan *BackfillPlan) (*BackfillProgress, error) {
+	progress := &BackfillProgress{Targets: plan.Targets, Hints: plan.Hints, Created: map[BackfillKind]int{}, RateScale: 1}
+
+	if path == "" {
+		return progress, nil
+	}
+
+	raw, err := os.ReadFile(path) // #nosec G304 -- path chosen by the caller
+	if errors.Is(err, os.ErrNotExist) {
+		return progress, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, progress); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+
+	if progress.Targets != plan.Targets || progress.Hints != plan.Hints {
+		return nil, fmt.Errorf("%w: targets %+v, hints %+v", ErrBackfillCheckpointMismatch, progress.Targets, progress.Hints)
+	}
+
+	if progress.Created == nil {
+		progress.Created = map[BackfillKind]int{}
+	}
+
+	if progress.RateScale <= 0 {
+		progress.RateScale = 1
+	}
+
+	progress.Resumed = true
+
+	return progress, nil
+}
+
+// saveBackfillCheckpoint writes the progress to the checkpoint file, replacing it atomically.
+func saveBackfillCheckpoint(path string, progress *BackfillProgress) error {
+	progress.UpdatedAt = time.Now().UTC()
+
+	if path == "" {
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	if err := fsutil.WriteFileAtomic(path, raw); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanBackfill(t *testing.T) {
+	plan, err := PlanBackfill(
+		BackfillTargets{Organizations: 2, LedgersPerOrganization: 3, Accounts: 1_000, Transactions: 100_000},
+		CapacityHints{RequestsPerSecond: 100, Concurrency: 4, BatchSize: 50, PhaseDuration: time.Minute},
+	)
+	require.NoError(t, err)
+
+	counts := map[BackfillKind]int{}
+	order := []BackfillKind{}
+
+	for _, phase := range plan.Phases {
+		if len(order) == 0 || order[len(order)-1] != phase.Kind {
+			order = append(order, phase.Kind)
+		}
+
+		assert.Equal(t, counts[phase.Kind], phase.Start, "phases of a kind must be contiguous")
+		counts[phase.Kind] += phase.Count
+		assert.LessOrEqual(t, phase.Concurrency, 4)
+		assert.LessOrEqual(t, phase.RatePerSecond, 100.0)
+	}
+
+	assert.Equal(t, []BackfillKind{BackfillOrganizations, BackfillLedgers, BackfillAccounts, BackfillTransactions}, order)
+	assert.Equal(t, map[BackfillKind]int{BackfillOrganizations: 2, BackfillLedgers: 6, BackfillAccounts: 1_000, BackfillTransactions: 100_000}, counts)
+
+	// transactions warm up at a quarter and half of the rate, then run in one-minute phases
+	var txPhases []BackfillPhase
+	for _, phase := range plan.Phases {
+		if phase.Kind == BackfillTransactions {
+			txPhases = append(txPhases, phase)
+		}
+	}
+
+	require.Greater(t, len(txPhases), 3)
+	assert.InDelta(t, 25.0, txPhases[0].RatePerSecond, 0.001)
+	assert.InDelta(t, 50.0, txPhases[1].RatePerSecond, 0.001)
+	assert.InDelta(t, 100.0, txPhases[2].RatePerSecond, 0.001)
+	assert.Equal(t, 6_000, txPhases[2].Count)
+	assert.Equal(t, "transactions-1", txPhases[0].Name)
+	assert.Zero(t, txPhases[0].Count%50, "phases must be whole batches")
+
+	assert.Greater(t, plan.Estimated, 1000*time.Second)
+}
+
+func TestPlanBackfillNoWarmup(t *testing.T) {
+	plan, err := PlanBackfill(BackfillTargets{Organizations: 1, Accounts: 10, Transactions: 10}, CapacityHints{NoWarmup: true})
+	require.NoError(t, err)
+
+	require.Len(t, plan.Phases, 4)
+	assert.Equal(t, 1, plan.Phases[1].Count, "ledgers default to one per organization")
+
+	for _, phase := range plan.Phases {
+		assert.InDelta(t, float64(DefaultBackfillRequestsPerSecond), phase.RatePerSecond, 0.001)
+	}
+}
+
+func TestPlanBackfillInvalidTargets(t *testing.T) {
+	for _, targets := range []BackfillTargets{
+		{Organizations: -1},
+		{Accounts: 10},
+		{Organizations: 1, Transactions: 10},
+	} {
+		_, err := PlanBackfill(targets, CapacityHints{})
+		assert.Error(t, err, "%+v", targets)
+	}
+}
+
+// recordingHandler counts the entities of each kind it creates.
+type recordingHandler struct {
+	mu      sync.Mutex
+	created map[BackfillKind]map[int]int
+}
+
+func (h *recordingHandler) handle(ctx context.Context, batch BackfillBatch) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.created == nil {
+		h.created = map[BackfillKind]map[int]int{}
+	}
+
+	if h.created[batch.Kind] == nil {
+		h.created[batch.Kind] = map[int]int{}
+	}
+
+	for i := batch.Start; i < batch.Start+batch.Count; i++ {
+		h.created[batch.Kind][i]++
+	}
+
+	return ctx.Err()
+}
+
+func fastBackfillPlan(t *testing.T) *BackfillPlan {
+	t.Helper()
+
+	plan, err := PlanBackfill(
+		BackfillTargets{Organizations: 2, Accounts: 40, Transactions: 400},
+		CapacityHints{RequestsPerSecond: 100_000, Concurrency: 4, BatchSize: 10, PhaseDuration: time.Millisecond},
+	)
+	require.NoError(t, err)
+
+	return plan
+}
+
+func TestRunBackfillPlan(t *testing.T) {
+	plan := fastBackfillPlan(t)
+	h := &recordingHandler{}
+	handlers := BackfillHandlers{Organizations: h.handle, Ledgers: h.handle, Accounts: h.handle, Transactions: h.handle}
+	checkpoint := filepath.Join(t.TempDir(), "backfill.json")
+
+	progress, err := RunBackfillPlan(context.Background(), plan, handlers, checkpoint)
+	require.NoError(t, err)
+
+	assert.True(t, progress.Completed)
+	assert.Equal(t, map[BackfillKind]int{BackfillOrganizations: 2, BackfillLedgers: 2, BackfillAccounts: 40, BackfillTransactions: 400}, progress.Created)
+	assert.Len(t, h.created[BackfillTransactions], 400)
+
+	for i, n := range h.created[BackfillTransactions] {
+		assert.Equal(t, 1, n, "transaction %d created %d times", i, n)
+	}
+
+	// a completed checkpoint makes the run a no-op
+	again := &recordingHandler{}
+	progress, err = RunBackfillPlan(context.Background(), plan, BackfillHandlers{Organizations: again.handle, Ledgers: again.handle, Accounts: again.handle, Transactions: again.handle}, checkpoint)
+	require.NoError(t, err)
+	assert.True(t, progress.Resumed)
+	assert.Empty(t, again.created)
+}
+
+func TestRunBackfillPlanResumes(t *testing.T) {
+	plan := fastBackfillPlan(t)
+	checkpoint := filepath.Join(t.TempDir(), "backfill.json")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &recordingHandler{}
+
+	var accounts atomic.Int32
+
+	interrupted := BackfillHandlers{
+		Organizations: h.handle,
+		Ledgers:       h.handle,
+		Accounts: func(ctx context.Context, batch BackfillBatch) error {
+			if accounts.Add(1) == 2 {
+				cancel()
+			}
+
+			return h.handle(ctx, batch)
+		},
+		Transactions: h.handle,
+	}
+
+	progress, err := RunBackfillPlan(ctx, plan, interrupted, checkpoint)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, progress.Completed)
+	assert.Empty(t, h.created[BackfillTransactions])
+
+	progress, err = RunBackfillPlan(context.Background(), plan, BackfillHandlers{Organizations: h.handle, Ledgers: h.handle, Accounts: h.handle, Transactions: h.handle}, checkpoint)
+	require.NoError(t, err)
+	assert.True(t, progress.Resumed)
+	assert.True(t, progress.Completed)
+
+	assert.Equal(t, 1, h.created[BackfillOrganizations][0], "completed phases must not run again")
+	assert.Len(t, h.created[BackfillAccounts], 40)
+	assert.Len(t, h.created[BackfillTransactions], 400)
+}
+
+func TestRunBackfillPlanSlowsDownOnFailures(t *testing.T) {
+	plan := fastBackfillPlan(t)
+	errBackend := errors.New("backend overloaded")
+	h := &recordingHandler{}
+
+	progress, err := RunBackfillPlan(context.Background(), plan, BackfillHandlers{
+		Organizations: h.handle,
+		Ledgers:       h.handle,
+		Accounts:      func(context.Context, BackfillBatch) error { return errBackend },
+		Transactions:  h.handle,
+	}, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, 40, progress.Failed)
+	assert.Len(t, progress.Failures, 4)
+	assert.Equal(t, BackfillAccounts, progress.Failures[0].Kind)
+	assert.Less(t, progress.RateScale, 1.0)
+	assert.GreaterOrEqual(t, progress.RateScale, minBackfillRateScale)
+	assert.Len(t, h.created[BackfillTransactions], 400)
+}
+
+func TestRunBackfillPlanCheckpointMismatch(t *testing.T) {
+	plan := fastBackfillPlan(t)
+	h := &recordingHandler{}
+	handlers := BackfillHandlers{Organizations: h.handle, Ledgers: h.handle, Accounts: h.handle, Transactions: h.handle}
+	checkpoint := filepath.Join(t.TempDir(), "backfill.json")
+
+	_, err := RunBackfillPlan(context.Background(), plan, handlers, checkpoint)
+	require.NoError(t, err)
+
+	other, err := PlanBackfill(BackfillTargets{Organizations: 3}, CapacityHints{})
+	require.NoError(t, err)
+
+	_, err = RunBackfillPlan(context.Background(), other, handlers, checkpoint)
+	assert.ErrorIs(t, err, ErrBackfillCheckpointMismatch)
+}
+
+func TestRunBackfillPlanMissingHandler(t *testing.T) {
+	_, err := RunBackfillPlan(context.Background(), fastBackfillPlan(t), BackfillHandlers{}, "")
+	assert.Error(t, err)
+}
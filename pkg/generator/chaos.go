@@ -0,0 +1,433 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/shopspring/decimal"
+)
+
+// ChaosKind identifies the type of failure injected into generated data.
+type ChaosKind string
+
+const (
+	// ChaosInvalidTransaction marks a transaction deliberately made invalid
+	// (unknown account, unbalanced legs or malformed DSL).
+	ChaosInvalidTransaction ChaosKind = "invalid_transaction"
+
+	// ChaosDuplicateIdempotencyKey marks a transaction that reuses the idempotency
+	// key of an earlier transaction in the same batch.
+	ChaosDuplicateIdempotencyKey ChaosKind = "duplicate_idempotency_key"
+
+	// ChaosOutOfOrder marks a transaction submitted or committed out of its generation order.
+	ChaosOutOfOrder ChaosKind = "out_of_order"
+)
+
+// ChaosMetadataKey is the metadata key set on every transaction altered by the
+// chaos injector, so reconciliation tooling can tell injected failures apart.
+const ChaosMetadataKey = "chaos_injection"
+
+// chaosMissingAliasPrefix prefixes the aliases used for unknown accounts.
+const chaosMissingAliasPrefix = "@chaos_missing_"
+
+// ChaosConfig configures failure injection for generated transactions.
+// Each value is a percentage between 0 and 100 of the transactions affected.
+// A zero value disables the corresponding injection.
+type ChaosConfig struct {
+	// InvalidTransactionPercent is the share of transactions made invalid
+	InvalidTransactionPercent float64
+
+	// DuplicateIdempotencyPercent is the share of transactions reusing an earlier idempotency key
+	DuplicateIdempotencyPercent float64
+
+	// OutOfOrderPercent is the share of transactions submitted or committed out of order
+	OutOfOrderPercent float64
+
+	// Seed makes injection reproducible; zero uses a fixed default seed
+	Seed int64
+}
+
+// Enabled reports whether any failure injection is configured.
+func (c ChaosConfig) Enabled() bool {
+	return c.InvalidTransactionPercent > 0 || c.DuplicateIdempotencyPercent > 0 || c.OutOfOrderPercent > 0
+}
+
+// Validate checks that all percentages are between 0 and 100.
+func (c ChaosConfig) Validate() error {
+	percents := []struct {
+		name  string
+		value float64
+	}{
+		{"invalid transaction", c.InvalidTransactionPercent},
+		{"duplicate idempotency", c.DuplicateIdempotencyPercent},
+		{"out of order", c.OutOfOrderPercent},
+	}
+
+	for _, p := range percents {
+		if p.value < 0 || p.value > 100 {
+			return fmt.Errorf("%s percentage must be between 0 and 100, got %.2f", p.name, p.value)
+		}
+	}
+
+	return nil
+}
+
+// ChaosEvent records a single injected failure.
+type ChaosEvent struct {
+	// Index is the position of the affected item in the input slice
+	Index int
+
+	// Kind is the type of failure injected
+	Kind ChaosKind
+
+	// Detail describes the mutation applied
+	Detail string
+}
+
+// ChaosReport lists the failures injected by a ChaosInjector.
+type ChaosReport struct {
+	Events []ChaosEvent
+}
+
+// Count returns the number of events of the given kind.
+func (r ChaosReport) Count(kind ChaosKind) int {
+	n := 0
+
+	for _, e := range r.Events {
+		if e.Kind == kind {
+			n++
+		}
+	}
+
+	return n
+}
+
+// ChaosInjector deliberately corrupts generated transactions to exercise error
+// handling and reconciliation in downstream systems. Inputs are never modified
+// in place; altered items are copies tagged with ChaosMetadataKey.
+//
+// A ChaosInjector is safe for concurrent use.
+type ChaosInjector struct {
+	cfg ChaosConfig
+
+	mu     sync.Mutex
+	rng    *rand.Rand
+	events []ChaosEvent
+}
+
+// NewChaosInjector creates a ChaosInjector from cfg.
+//
+// Example:
+//
+//	inj, err := generator.NewChaosInjector(generator.ChaosConfig{
+//	    InvalidTransactionPercent:   5,
+//	    DuplicateIdempotencyPercent: 2,
+//	    OutOfOrderPercent:           10,
+//	    Seed:                        42,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	ctx = generator.WithChaos(ctx, inj)
+func NewChaosInjector(cfg ChaosConfig) (*ChaosInjector, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = 1
+	}
+
+	// #nosec G404 - chaos injection only needs reproducible pseudo-randomness
+	return &ChaosInjector{cfg: cfg, rng: rand.New(rand.NewSource(seed))}, nil
+}
+
+// Config returns the injector configuration.
+func (c *ChaosInjector) Config() ChaosConfig {
+	return c.cfg
+}
+
+// Report returns the failures injected so far.
+func (c *ChaosInjector) Report() ChaosReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return ChaosReport{Events: append([]ChaosEvent(nil), c.events...)}
+}
+
+// ApplyToInputs returns a copy of inputs with failures injected: invalid
+// transactions, duplicated idempotency keys and a shuffled submission order.
+// An item receives at most one of the first two failures. Nil inputs are passed through untouched.
+func (c *ChaosInjector) ApplyToInputs(inputs []*models.CreateTransactionInput) []*models.CreateTransactionInput {
+	out := make([]*models.CreateTransactionInput, len(inputs))
+	copy(out, inputs)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, input := range out {
+		if input == nil {
+			continue
+		}
+
+		if c.roll(c.cfg.InvalidTransactionPercent) {
+			clone := cloneTransactionInput(input)
+			detail := c.corruptInput(clone, i)
+			tagChaos(&clone.Metadata, ChaosInvalidTransaction)
+			out[i] = clone
+			c.record(i, ChaosInvalidTransaction, detail)
+
+			continue
+		}
+
+		if i > 0 && c.roll(c.cfg.DuplicateIdempotencyPercent) {
+			j := c.rng.Intn(i)
+			if out[j] == nil || out[j].IdempotencyKey == "" {
+				continue
+			}
+
+			clone := cloneTransactionInput(out[i])
+			clone.IdempotencyKey = out[j].IdempotencyKey
+			tagChaos(&clone.Metadata, ChaosDuplicateIdempotencyKey)
+			out[i] = clone
+			c.record(i, ChaosDuplicateIdempotencyKey, fmt.Sprintf("reuses idempotency key of item %d", j))
+		}
+	}
+
+	return reorder(out, c.shuffle(len(out)))
+}
+
+// ApplyToPatterns returns a copy of patterns with failures injected: DSL that
+// the server rejects, duplicated idempotency keys and a shuffled submission order.
+// Altered patterns still pass data.ValidateTransactionPattern so they reach the API.
+func (c *ChaosInjector) ApplyToPatterns(patterns []data.TransactionPattern) []data.TransactionPattern {
+	out := make([]data.TransactionPattern, len(patterns))
+	copy(out, patterns)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range out {
+		if c.roll(c.cfg.InvalidTransactionPercent) {
+			detail := c.corruptDSL(&out[i], i)
+			out[i].Metadata = cloneMetadata(out[i].Metadata)
+			tagChaos(&out[i].Metadata, ChaosInvalidTransaction)
+			c.record(i, ChaosInvalidTransaction, detail)
+
+			continue
+		}
+
+		if i > 0 && c.roll(c.cfg.DuplicateIdempotencyPercent) {
+			j := c.rng.Intn(i)
+			if out[j].IdempotencyKey == "" {
+				continue
+			}
+
+			out[i].IdempotencyKey = out[j].IdempotencyKey
+			out[i].Metadata = cloneMetadata(out[i].Metadata)
+			tagChaos(&out[i].Metadata, ChaosDuplicateIdempotencyKey)
+			c.record(i, ChaosDuplicateIdempotencyKey, fmt.Sprintf("reuses idempotency key of item %d", j))
+		}
+	}
+
+	return reorder(out, c.shuffle(len(out)))
+}
+
+// CommitOrder returns the order in which n pending transactions should be
+// committed: positions selected by OutOfOrderPercent are swapped with a later
+// position, the rest keep their generation order.
+func (c *ChaosInjector) CommitOrder(n int) []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.shuffle(n)
+}
+
+// CommitAll commits pending transactions in order. When a ChaosInjector is
+// stored in ctx, part of the commits are issued out of order. Every commit is
+// attempted; failures are aggregated in the returned error.
+func CommitAll(ctx context.Context, lc TransactionLifecycle, txIDs []string) error {
+	if lc == nil {
+		return errors.New("transaction lifecycle is required")
+	}
+
+	order := make([]int, len(txIDs))
+	for i := range order {
+		order[i] = i
+	}
+
+	if inj := getChaos(ctx); inj != nil {
+		order = inj.CommitOrder(len(txIDs))
+	}
+
+	var errs []error
+
+	for _, i := range order {
+		if err := lc.Commit(ctx, txIDs[i]); err != nil {
+			errs = append(errs, fmt.Errorf("commit %s: %w", txIDs[i], err))
+		}
+	}
+
+	return errorsJoin(errs...)
+}
+
+// roll reports whether an event with the given percentage happens. Callers hold c.mu.
+func (c *ChaosInjector) roll(percent float64) bool {
+	return percent > 0 && c.rng.Float64()*100 < percent
+}
+
+// record appends an event. Callers hold c.mu.
+func (c *ChaosInjector) record(index int, kind ChaosKind, detail string) {
+	c.events = append(c.events, ChaosEvent{Index: index, Kind: kind, Detail: detail})
+}
+
+// shuffle builds an order of n items where positions selected by
+// OutOfOrderPercent are swapped with a later position, recording every moved
+// item. Callers hold c.mu.
+func (c *ChaosInjector) shuffle(n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	for i := 0; i < n-1; i++ {
+		if c.roll(c.cfg.OutOfOrderPercent) {
+			j := i + 1 + c.rng.Intn(n-i-1)
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	for i, from := range order {
+		if i != from {
+			c.record(from, ChaosOutOfOrder, fmt.Sprintf("moved from position %d to %d", from, i))
+		}
+	}
+
+	return order
+}
+
+// corruptInput makes a transaction input invalid and describes the change.
+// Callers hold c.mu.
+func (c *ChaosInjector) corruptInput(input *models.CreateTransactionInput, index int) string {
+	missing := fmt.Sprintf("%s%d", chaosMissingAliasPrefix, index)
+
+	if input.Send != nil && input.Send.Distribute != nil && len(input.Send.Distribute.To) > 0 {
+		to := &input.Send.Distribute.To[0]
+
+		if value, err := decimal.NewFromString(to.Amount.Value); err == nil && c.rng.Intn(2) == 0 {
+			to.Amount.Value = value.Mul(decimal.NewFromInt(2)).String()
+			return "doubled first destination amount (unbalanced legs)"
+		}
+
+		to.Account = missing
+
+		return "first destination points to unknown account " + missing
+	}
+
+	if len(input.Operations) > 0 {
+		input.Operations[0].AccountID = strings.TrimPrefix(missing, "@")
+		input.Operations[0].AccountAlias = nil
+
+		return "first operation points to unknown account " + missing
+	}
+
+	input.AssetCode = "CHAOS"
+
+	return "asset code replaced with unknown asset CHAOS"
+}
+
+// corruptDSL makes a DSL pattern invalid for the server and describes the change.
+// Callers hold c.mu.
+func (c *ChaosInjector) corruptDSL(p *data.TransactionPattern, index int) string {
+	if idx := strings.Index(p.DSLTemplate, "@"); idx >= 0 && c.rng.Intn(2) == 0 {
+		end := idx + 1
+		for end < len(p.DSLTemplate) && isDSLAliasChar(p.DSLTemplate[end]) {
+			end++
+		}
+
+		missing := fmt.Sprintf("%s%d", chaosMissingAliasPrefix, index)
+		p.DSLTemplate = p.DSLTemplate[:idx] + missing + p.DSLTemplate[end:]
+
+		return "first alias replaced with unknown account " + missing
+	}
+
+	p.DSLTemplate += "\n)"
+
+	return "unbalanced parenthesis appended to DSL"
+}
+
+// isDSLAliasChar reports whether b may appear in a DSL account alias.
+func isDSLAliasChar(b byte) bool {
+	return b == '_' || b == '-' || b == ':' || b == '/' ||
+		(b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// reorder returns items arranged so that position i holds items[order[i]].
+func reorder[T any](items []T, order []int) []T {
+	if len(order) != len(items) {
+		return items
+	}
+
+	out := make([]T, len(items))
+	for i, from := range order {
+		out[i] = items[from]
+	}
+
+	return out
+}
+
+// cloneTransactionInput copies the parts of an input the injector may modify.
+func cloneTransactionInput(input *models.CreateTransactionInput) *models.CreateTransactionInput {
+	clone := *input
+	clone.Metadata = cloneMetadata(input.Metadata)
+
+	if input.Send != nil {
+		send := *input.Send
+
+		if input.Send.Source != nil {
+			send.Source = &models.SourceInput{From: append([]models.FromToInput(nil), input.Send.Source.From...)}
+		}
+
+		if input.Send.Distribute != nil {
+			send.Distribute = &models.DistributeInput{To: append([]models.FromToInput(nil), input.Send.Distribute.To...)}
+		}
+
+		clone.Send = &send
+	}
+
+	if input.Operations != nil {
+		clone.Operations = append([]models.CreateOperationInput(nil), input.Operations...)
+	}
+
+	return &clone
+}
+
+// cloneMetadata returns a shallow copy of metadata.
+func cloneMetadata(metadata map[string]any) map[string]any {
+	if metadata == nil {
+		return nil
+	}
+
+	out := make(map[string]any, len(metadata))
+	for k, v := range metadata {
+		out[k] = v
+	}
+
+	return out
+}
+
+// tagChaos records the injected failure kind in metadata.
+func tagChaos(metadata *map[string]any, kind ChaosKind) {
+	if *metadata == nil {
+		*metadata = map[string]any{}
+	}
+
+	(*metadata)[ChaosMetadataKey] = string(kind)
+}
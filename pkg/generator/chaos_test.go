@@ -0,0 +1,256 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newChaosInputs(n int) []*models.CreateTransactionInput {
+	inputs := make([]*models.CreateTransactionInput, n)
+
+	for i := range inputs {
+		inputs[i] = &models.CreateTransactionInput{
+			Amount:         "10.00",
+			AssetCode:      "USD",
+			IdempotencyKey: fmt.Sprintf("key-%d", i),
+			Send: &models.SendInput{
+				Asset: "USD",
+				Value: "10.00",
+				Source: &models.SourceInput{From: []models.FromToInput{{
+					Account: "@external/USD",
+					Amount:  models.AmountInput{Asset: "USD", Value: "10.00"},
+				}}},
+				Distribute: &models.DistributeInput{To: []models.FromToInput{{
+					Account: fmt.Sprintf("@acct_%d", i),
+					Amount:  models.AmountInput{Asset: "USD", Value: "10.00"},
+				}}},
+			},
+			Metadata: map[string]any{"index": i},
+		}
+	}
+
+	return inputs
+}
+
+func TestChaosConfig_Validate(t *testing.T) {
+	assert.NoError(t, ChaosConfig{}.Validate())
+	assert.NoError(t, ChaosConfig{InvalidTransactionPercent: 100}.Validate())
+	assert.Error(t, ChaosConfig{DuplicateIdempotencyPercent: -1}.Validate())
+	assert.Error(t, ChaosConfig{OutOfOrderPercent: 101}.Validate())
+
+	_, err := NewChaosInjector(ChaosConfig{InvalidTransactionPercent: 150})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid transaction")
+}
+
+func TestChaosInjector_ApplyToInputs_Disabled(t *testing.T) {
+	inj, err := NewChaosInjector(ChaosConfig{})
+	require.NoError(t, err)
+
+	inputs := newChaosInputs(10)
+	out := inj.ApplyToInputs(inputs)
+
+	assert.Equal(t, inputs, out)
+	assert.Empty(t, inj.Report().Events)
+}
+
+func TestChaosInjector_ApplyToInputs_Invalid(t *testing.T) {
+	inj, err := NewChaosInjector(ChaosConfig{InvalidTransactionPercent: 100, Seed: 3})
+	require.NoError(t, err)
+
+	inputs := newChaosInputs(20)
+	out := inj.ApplyToInputs(inputs)
+	require.Len(t, out, 20)
+
+	report := inj.Report()
+	assert.Equal(t, 20, report.Count(ChaosInvalidTransaction))
+
+	for i, input := range out {
+		assert.Equal(t, string(ChaosInvalidTransaction), input.Metadata[ChaosMetadataKey])
+
+		to := input.Send.Distribute.To[0]
+		unbalanced := to.Amount.Value != input.Send.Value
+		unknown := strings.HasPrefix(to.Account, chaosMissingAliasPrefix)
+		assert.True(t, unbalanced || unknown, "item %d was not corrupted", i)
+
+		// originals are untouched
+		assert.Equal(t, "10.00", inputs[i].Send.Distribute.To[0].Amount.Value)
+		assert.Equal(t, fmt.Sprintf("@acct_%d", i), inputs[i].Send.Distribute.To[0].Account)
+		assert.NotContains(t, inputs[i].Metadata, ChaosMetadataKey)
+	}
+}
+
+func TestChaosInjector_ApplyToInputs_DuplicateKeys(t *testing.T) {
+	inj, err := NewChaosInjector(ChaosConfig{DuplicateIdempotencyPercent: 100, Seed: 5})
+	require.NoError(t, err)
+
+	inputs := newChaosInputs(10)
+	out := inj.ApplyToInputs(inputs)
+
+	report := inj.Report()
+	assert.Equal(t, 9, report.Count(ChaosDuplicateIdempotencyKey))
+
+	// the first item can never duplicate an earlier one
+	assert.Equal(t, "key-0", out[0].IdempotencyKey)
+
+	for _, e := range report.Events {
+		assert.NotEqual(t, inputs[e.Index].IdempotencyKey, out[e.Index].IdempotencyKey)
+		assert.Equal(t, string(ChaosDuplicateIdempotencyKey), out[e.Index].Metadata[ChaosMetadataKey])
+	}
+}
+
+func TestChaosInjector_ApplyToInputs_OutOfOrder(t *testing.T) {
+	inj, err := NewChaosInjector(ChaosConfig{OutOfOrderPercent: 50, Seed: 11})
+	require.NoError(t, err)
+
+	inputs := newChaosInputs(50)
+	out := inj.ApplyToInputs(inputs)
+	require.Len(t, out, 50)
+
+	assert.ElementsMatch(t, inputs, out)
+	assert.NotEqual(t, inputs, out)
+	assert.Positive(t, inj.Report().Count(ChaosOutOfOrder))
+}
+
+func TestChaosInjector_Reproducible(t *testing.T) {
+	cfg := ChaosConfig{InvalidTransactionPercent: 30, DuplicateIdempotencyPercent: 30, OutOfOrderPercent: 30, Seed: 42}
+
+	first, err := NewChaosInjector(cfg)
+	require.NoError(t, err)
+
+	second, err := NewChaosInjector(cfg)
+	require.NoError(t, err)
+
+	first.ApplyToInputs(newChaosInputs(30))
+	second.ApplyToInputs(newChaosInputs(30))
+
+	assert.Equal(t, first.Report(), second.Report())
+}
+
+func TestChaosInjector_ApplyToPatterns(t *testing.T) {
+	inj, err := NewChaosInjector(ChaosConfig{InvalidTransactionPercent: 100, Seed: 9})
+	require.NoError(t, err)
+
+	patterns := make([]data.TransactionPattern, 10)
+	for i := range patterns {
+		patterns[i] = data.TransferPattern("USD", 100, "@alice", "@bob", fmt.Sprintf("key-%d", i), "")
+	}
+
+	out := inj.ApplyToPatterns(patterns)
+	require.Len(t, out, 10)
+
+	for i, p := range out {
+		require.NoError(t, data.ValidateTransactionPattern(p), "item %d must still pass local validation", i)
+		assert.NotEqual(t, patterns[i].DSLTemplate, p.DSLTemplate)
+		assert.Equal(t, string(ChaosInvalidTransaction), p.Metadata[ChaosMetadataKey])
+		assert.NotContains(t, patterns[i].Metadata, ChaosMetadataKey)
+	}
+}
+
+func TestTransactionGenerator_GenerateBatch_WithChaos(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		dsls []string
+	)
+
+	mockSvc := &mockTransactionsService{
+		createWithDSLFunc: func(_ context.Context, _, _ string, dsl []byte) (*models.Transaction, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			dsls = append(dsls, string(dsl))
+
+			if strings.Contains(string(dsl), chaosMissingAliasPrefix) || strings.HasSuffix(string(dsl), "\n)") {
+				return nil, errors.New("invalid transaction")
+			}
+
+			return &models.Transaction{ID: "tx"}, nil
+		},
+	}
+
+	inj, err := NewChaosInjector(ChaosConfig{InvalidTransactionPercent: 100, Seed: 1})
+	require.NoError(t, err)
+
+	gen := NewTransactionGenerator(&entities.Entity{Transactions: mockSvc}, nil)
+	ctx := WithChaos(WithWorkers(context.Background(), 2), inj)
+
+	patterns := []data.TransactionPattern{
+		data.TransferPattern("USD", 100, "@alice", "@bob", "key-1", ""),
+		data.TransferPattern("USD", 200, "@alice", "@bob", "key-2", ""),
+	}
+
+	results, err := gen.GenerateBatch(ctx, "org-123", "ledger-123", patterns, 0)
+	require.Error(t, err)
+	assert.Empty(t, results)
+	assert.Len(t, dsls, 2)
+}
+
+func TestCommitAll(t *testing.T) {
+	newLifecycle := func(committed *[]string) TransactionLifecycle {
+		var mu sync.Mutex
+
+		return NewTransactionLifecycle(&entities.Entity{Transactions: &mockTransactionsService{
+			commitFunc: func(_ context.Context, _, _, txID string) (*models.Transaction, error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				*committed = append(*committed, txID)
+
+				if txID == "fail" {
+					return nil, errors.New("boom")
+				}
+
+				return &models.Transaction{ID: txID}, nil
+			},
+		}}, nil)
+	}
+
+	ids := make([]string, 20)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("tx-%02d", i)
+	}
+
+	baseCtx := WithLedgerID(WithOrgID(context.Background(), "org-123"), "ledger-123")
+
+	t.Run("in order without chaos", func(t *testing.T) {
+		var committed []string
+
+		require.NoError(t, CommitAll(baseCtx, newLifecycle(&committed), ids))
+		assert.Equal(t, ids, committed)
+	})
+
+	t.Run("out of order with chaos", func(t *testing.T) {
+		var committed []string
+
+		inj, err := NewChaosInjector(ChaosConfig{OutOfOrderPercent: 50, Seed: 2})
+		require.NoError(t, err)
+
+		require.NoError(t, CommitAll(WithChaos(baseCtx, inj), newLifecycle(&committed), ids))
+		assert.ElementsMatch(t, ids, committed)
+		assert.NotEqual(t, ids, committed)
+		assert.Positive(t, inj.Report().Count(ChaosOutOfOrder))
+	})
+
+	t.Run("aggregates failures", func(t *testing.T) {
+		var committed []string
+
+		err := CommitAll(baseCtx, newLifecycle(&committed), []string{"tx-1", "fail", "tx-2"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "commit fail")
+		assert.Len(t, committed, 3)
+	})
+
+	t.Run("nil lifecycle", func(t *testing.T) {
+		assert.Error(t, CommitAll(baseCtx, nil, ids))
+	})
+}
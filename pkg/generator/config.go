@@ -3,6 +3,8 @@ package generator
 import (
 	"runtime"
 	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/rounding"
 )
 
 // GeneratorConfig defines scale, performance, and data pattern options
@@ -34,6 +36,11 @@ type GeneratorConfig struct {
 	AccountTypes        []string // checking, savings, credit, expense
 	AssetTypes          []string // currency, crypto, points
 
+	// RoundingPolicy brings generated amounts and currency conversions to
+	// asset scales: half_even or truncate. Empty keeps the defaults of the
+	// data package helpers.
+	RoundingPolicy rounding.Policy
+
 	// Idempotency & tracking
 	EnableIdempotency bool
 	UseExternalIDs    bool
@@ -43,6 +50,9 @@ type GeneratorConfig struct {
 	CircuitBreakerFailureThreshold int
 	CircuitBreakerSuccessThreshold int
 	CircuitBreakerOpenTimeout      time.Duration
+
+	// Failure injection (disabled by default)
+	Chaos ChaosConfig
 }
 
 // DefaultConfig returns a sensible baseline configuration suitable for
@@ -90,6 +100,7 @@ func (dst *GeneratorConfig) WithOverrides(src GeneratorConfig) {
 	dst.applyPatternOverrides(src)
 	dst.applyTrackingOverrides(src)
 	dst.applyCircuitBreakerOverrides(src)
+	dst.applyChaosOverrides(src)
 }
 
 // applyScaleOverrides applies scale-related configuration overrides
@@ -157,6 +168,10 @@ func (dst *GeneratorConfig) applyPatternOverrides(src GeneratorConfig) {
 	if len(src.AssetTypes) > 0 {
 		dst.AssetTypes = append([]string{}, src.AssetTypes...)
 	}
+
+	if src.RoundingPolicy != "" {
+		dst.RoundingPolicy = src.RoundingPolicy
+	}
 }
 
 // applyTrackingOverrides applies idempotency and tracking configuration overrides
@@ -184,6 +199,25 @@ func (dst *GeneratorConfig) applyCircuitBreakerOverrides(src GeneratorConfig) {
 	}
 }
 
+// applyChaosOverrides applies failure injection configuration overrides
+func (dst *GeneratorConfig) applyChaosOverrides(src GeneratorConfig) {
+	if src.Chaos.InvalidTransactionPercent > 0 {
+		dst.Chaos.InvalidTransactionPercent = src.Chaos.InvalidTransactionPercent
+	}
+
+	if src.Chaos.DuplicateIdempotencyPercent > 0 {
+		dst.Chaos.DuplicateIdempotencyPercent = src.Chaos.DuplicateIdempotencyPercent
+	}
+
+	if src.Chaos.OutOfOrderPercent > 0 {
+		dst.Chaos.OutOfOrderPercent = src.Chaos.OutOfOrderPercent
+	}
+
+	if src.Chaos.Seed != 0 {
+		dst.Chaos.Seed = src.Chaos.Seed
+	}
+}
+
 func maxInt(a, b int) int {
 	if a > b {
 		return a
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/rounding"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -105,12 +106,14 @@ func TestGeneratorConfig_WithOverrides(t *testing.T) {
 				TransactionPatterns: []string{"custom_pattern"},
 				AccountTypes:        []string{"custom_type"},
 				AssetTypes:          []string{"custom_asset"},
+				RoundingPolicy:      rounding.PolicyHalfEven,
 			},
 			check: func(t *testing.T, result GeneratorConfig) {
 				t.Helper()
 				assert.Equal(t, []string{"custom_pattern"}, result.TransactionPatterns)
 				assert.Equal(t, []string{"custom_type"}, result.AccountTypes)
 				assert.Equal(t, []string{"custom_asset"}, result.AssetTypes)
+				assert.Equal(t, rounding.PolicyHalfEven, result.RoundingPolicy)
 			},
 		},
 		{
@@ -489,6 +492,28 @@ func TestGeneratorConfig_ApplyCircuitBreakerOverrides(t *testing.T) {
 	}
 }
 
+func TestGeneratorConfig_ApplyChaosOverrides(t *testing.T) {
+	t.Run("Non-zero values override", func(t *testing.T) {
+		cfg := GeneratorConfig{Chaos: ChaosConfig{InvalidTransactionPercent: 1, Seed: 7}}
+		cfg.applyChaosOverrides(GeneratorConfig{Chaos: ChaosConfig{
+			InvalidTransactionPercent:   5,
+			DuplicateIdempotencyPercent: 2,
+			OutOfOrderPercent:           10,
+		}})
+
+		assert.Equal(t, ChaosConfig{
+			InvalidTransactionPercent:   5,
+			DuplicateIdempotencyPercent: 2,
+			OutOfOrderPercent:           10,
+			Seed:                        7,
+		}, cfg.Chaos)
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		assert.False(t, DefaultConfig().Chaos.Enabled())
+	})
+}
+
 func TestMaxInt(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+)
+
+// EvolutionEventFunc applies a single event of an evolution plan to the ledger.
+type EvolutionEventFunc func(ctx context.Context, day data.EvolutionDay, event data.EvolutionEvent) error
+
+// EvolutionHandlers apply the events of an evolution plan, one per event kind.
+// Events of a kind without a handler are skipped.
+type EvolutionHandlers struct {
+	Onboard EvolutionEventFunc
+	Payment EvolutionEventFunc
+	Churn   EvolutionEventFunc
+}
+
+// EvolutionDayResult aggregates the outcome of a simulated day.
+type EvolutionDayResult struct {
+	Day             int
+	Date            time.Time
+	ActiveCustomers int
+
+	Onboarded int
+	Payments  int
+	Churned   int
+	Failed    int
+
+	// Errors holds the errors of failed events
+	Errors []error
+}
+
+// EvolutionSummary is the outcome of an evolution run, per simulated day.
+type EvolutionSummary struct {
+	Days    []EvolutionDayResult
+	Elapsed time.Duration
+}
+
+// RunEvolutionPlan applies the days of plan in order, one every dayDuration
+// of real time; a zero dayDuration runs the days back to back. Within a day,
+// onboardings complete before payments start and churn runs last, so new
+// customers are funded before they pay and leaving customers pay first. Events
+// of a phase run concurrently, bounded by the worker count stored in ctx (see
+// WithWorkers). It returns the context error if the run is cancelled; the
+// summary covers the days applied until then.
+//
+// Example:
+//
+//	summary, err := generator.RunEvolutionPlan(ctx, plan, 10*time.Second, generator.EvolutionHandlers{
+//	    Onboard: createAndFundCustomer,
+//	    Payment: submitPayment,
+//	    Churn:   cashOutAndClose,
+//	})
+func RunEvolutionPlan(ctx context.Context, plan []data.EvolutionDay, dayDuration time.Duration, handlers EvolutionHandlers) (*EvolutionSummary, error) {
+	if handlers.Onboard == nil && handlers.Payment == nil && handlers.Churn == nil {
+		return nil, errors.New("at least one evolution handler is required")
+	}
+
+	summary := &EvolutionSummary{}
+	start := time.Now()
+
+	for i, day := range plan {
+		if err := waitUntil(ctx, start.Add(dayDuration*time.Duration(i))); err != nil {
+			summary.Elapsed = time.Since(start)
+			return summary, err
+		}
+
+		result := EvolutionDayResult{Day: day.Day, Date: day.Date, ActiveCustomers: day.ActiveCustomers}
+
+		for _, phase := range []struct {
+			kind    data.EvolutionEventKind
+			handler EvolutionEventFunc
+			count   *int
+		}{
+			{data.EvolutionOnboard, handlers.Onboard, &result.Onboarded},
+			{data.EvolutionPayment, handlers.Payment, &result.Payments},
+			{data.EvolutionChurn, handlers.Churn, &result.Churned},
+		} {
+			if phase.handler == nil {
+				continue
+			}
+
+			runEvolutionPhase(ctx, day, phase.kind, phase.handler, phase.count, &result)
+		}
+
+		summary.Days = append(summary.Days, result)
+
+		if err := ctx.Err(); err != nil {
+			summary.Elapsed = time.Since(start)
+			return summary, err
+		}
+	}
+
+	summary.Elapsed = time.Since(start)
+
+	return summary, nil
+}
+
+// runEvolutionPhase applies the events of kind of a day concurrently and
+// records their outcome in result.
+func runEvolutionPhase(ctx context.Context, day data.EvolutionDay, kind data.EvolutionEventKind, handler EvolutionEventFunc, succeeded *int, result *EvolutionDayResult) {
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, getWorkers(ctx))
+	)
+
+	for _, event := range day.Events {
+		if event.Kind != kind {
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+
+		wg.Add(1)
+
+		go func(event data.EvolutionEvent) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := handler(ctx, day, event)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, err)
+
+				return
+			}
+
+			*succeeded++
+		}(event)
+	}
+
+	wg.Wait()
+}
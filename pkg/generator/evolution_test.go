@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunEvolutionPlan(t *testing.T) {
+	plan := []data.EvolutionDay{
+		{Day: 0, ActiveCustomers: 2, Events: []data.EvolutionEvent{
+			{Kind: data.EvolutionOnboard, Customer: "@new"},
+			{Kind: data.EvolutionPayment, Customer: "@new", Counterparty: "@shop", Amount: 10},
+			{Kind: data.EvolutionPayment, Customer: "@old", Counterparty: "@shop", Amount: 20},
+			{Kind: data.EvolutionChurn, Customer: "@old"},
+		}},
+		{Day: 1, ActiveCustomers: 1, Events: []data.EvolutionEvent{
+			{Kind: data.EvolutionPayment, Customer: "@new", Counterparty: "@shop", Amount: 99},
+		}},
+	}
+
+	var (
+		mu    sync.Mutex
+		order []data.EvolutionEventKind
+	)
+
+	record := func(fail bool) EvolutionEventFunc {
+		return func(_ context.Context, _ data.EvolutionDay, event data.EvolutionEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			order = append(order, event.Kind)
+
+			if fail && event.Amount == 99 {
+				return errors.New("insufficient funds")
+			}
+
+			return nil
+		}
+	}
+
+	summary, err := RunEvolutionPlan(WithWorkers(context.Background(), 4), plan, 0, EvolutionHandlers{
+		Onboard: record(false),
+		Payment: record(true),
+		Churn:   record(false),
+	})
+	require.NoError(t, err)
+	require.Len(t, summary.Days, 2)
+
+	assert.Equal(t, []data.EvolutionEventKind{
+		data.EvolutionOnboard, data.EvolutionPayment, data.EvolutionPayment, data.EvolutionChurn, data.EvolutionPayment,
+	}, order, "phases run in order")
+
+	first := summary.Days[0]
+	assert.Equal(t, 1, first.Onboarded)
+	assert.Equal(t, 2, first.Payments)
+	assert.Equal(t, 1, first.Churned)
+	assert.Equal(t, 2, first.ActiveCustomers)
+
+	second := summary.Days[1]
+	assert.Equal(t, 0, second.Payments)
+	assert.Equal(t, 1, second.Failed)
+	require.Len(t, second.Errors, 1)
+}
+
+func TestRunEvolutionPlan_Cancel(t *testing.T) {
+	plan := []data.EvolutionDay{{Day: 0}, {Day: 1}, {Day: 2}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	summary, err := RunEvolutionPlan(ctx, plan, time.Hour, EvolutionHandlers{
+		Payment: func(context.Context, data.EvolutionDay, data.EvolutionEvent) error { return nil },
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, summary.Days, 1, "only the first day runs before the deadline")
+}
+
+func TestRunEvolutionPlan_NoHandlers(t *testing.T) {
+	_, err := RunEvolutionPlan(context.Background(), nil, 0, EvolutionHandlers{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"context"
+	"strings"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
+)
+
+// existingPageSize is the page size of the lookups of WithIfNotExists.
+const existingPageSize = 100
+
+// findExisting pages through list and returns the first item matching match,
+// or nil if there is none.
+func findExisting[T any](ctx context.Context, list func(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[T], error), match func(*T) bool) (*T, error) {
+	opts := models.NewListOptions().WithLimit(existingPageSize)
+
+	for opts != nil {
+		var page *models.ListResponse[T]
+
+		err := retry.DoWithContext(ctx, func() error {
+			resp, err := list(ctx, opts)
+			if err != nil {
+				return err
+			}
+
+			page = resp
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if page == nil {
+			return nil, nil
+		}
+
+		for i := range page.Items {
+			if match(&page.Items[i]) {
+				return &page.Items[i], nil
+			}
+		}
+
+		if len(page.Items) == 0 {
+			return nil, nil
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	return nil, nil
+}
+
+// findOrganization returns the organization created from template by a previous run, if any.
+func (g *orgGenerator) findOrganization(ctx context.Context, template data.OrgTemplate) (*models.Organization, error) {
+	return findExisting(ctx, g.e.Organizations.ListOrganizations, func(org *models.Organization) bool {
+		if template.TaxID != "" {
+			return org.LegalDocument == template.TaxID
+		}
+
+		return strings.EqualFold(org.LegalName, template.LegalName)
+	})
+}
+
+// findLedger returns the ledger of the organization created from template by a previous run, if any.
+func (g *ledgerGenerator) findLedger(ctx context.Context, orgID string, template data.LedgerTemplate) (*models.Ledger, error) {
+	return findExisting(ctx, func(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Ledger], error) {
+		return g.e.Ledgers.ListLedgers(ctx, orgID, opts)
+	}, func(ledger *models.Ledger) bool {
+		return ledger.Name == template.Name
+	})
+}
+
+// findAsset returns the asset of the ledger created from template by a previous run, if any.
+func (g *assetGenerator) findAsset(ctx context.Context, orgID, ledgerID string, template data.AssetTemplate) (*models.Asset, error) {
+	return findExisting(ctx, func(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Asset], error) {
+		return g.e.Assets.ListAssets(ctx, orgID, ledgerID, opts)
+	}, func(asset *models.Asset) bool {
+		return strings.EqualFold(asset.Code, template.Code)
+	})
+}
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedList serves items in pages of the requested limit, by offset.
+func pagedList[T any](items []T, calls *int) func(opts *models.ListOptions) (*models.ListResponse[T], error) {
+	return func(opts *models.ListOptions) (*models.ListResponse[T], error) {
+		*calls++
+
+		end := min(opts.Offset+opts.Limit, len(items))
+		start := min(opts.Offset, end)
+
+		return &models.ListResponse[T]{
+			Items:      items[start:end],
+			Pagination: models.Pagination{Limit: opts.Limit, Offset: opts.Offset, Total: len(items)},
+		}, nil
+	}
+}
+
+func TestOrgGenerator_Generate_IfNotExists(t *testing.T) {
+	orgs := make([]models.Organization, existingPageSize+1)
+	for i := range orgs {
+		orgs[i] = models.Organization{ID: "org-old", LegalName: "Other", LegalDocument: "00-0000000"}
+	}
+
+	orgs[existingPageSize] = models.Organization{ID: "org-existing", LegalName: "Acme", LegalDocument: "12-3456789"}
+
+	calls, created := 0, 0
+	list := pagedList(orgs, &calls)
+	mockSvc := &mockOrganizationsService{
+		createFunc: func(context.Context, *models.CreateOrganizationInput) (*models.Organization, error) {
+			created++
+			return &models.Organization{ID: "org-new"}, nil
+		},
+		listFunc: func(_ context.Context, opts *models.ListOptions) (*models.ListResponse[models.Organization], error) {
+			return list(opts)
+		},
+	}
+
+	gen := NewOrganizationGenerator(&entities.Entity{Organizations: mockSvc}, nil)
+	ctx := WithIfNotExists(context.Background(), true)
+
+	t.Run("existing organization is reused", func(t *testing.T) {
+		org, err := gen.Generate(ctx, data.OrgTemplate{LegalName: "Acme Renamed", TaxID: "12-3456789"})
+		require.NoError(t, err)
+		assert.Equal(t, "org-existing", org.ID)
+		assert.Equal(t, 2, calls, "the lookup should page through the organizations")
+		assert.Zero(t, created)
+	})
+
+	t.Run("missing organization is created", func(t *testing.T) {
+		org, err := gen.Generate(ctx, data.OrgTemplate{LegalName: "Acme", TaxID: "98-7654321"})
+		require.NoError(t, err)
+		assert.Equal(t, "org-new", org.ID)
+		assert.Equal(t, 1, created)
+	})
+
+	t.Run("legal name is the key without a document", func(t *testing.T) {
+		org, err := gen.Generate(ctx, data.OrgTemplate{LegalName: "acme"})
+		require.NoError(t, err)
+		assert.Equal(t, "org-existing", org.ID)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		calls = 0
+
+		org, err := gen.Generate(context.Background(), data.OrgTemplate{LegalName: "Acme", TaxID: "12-3456789"})
+		require.NoError(t, err)
+		assert.Equal(t, "org-new", org.ID)
+		assert.Zero(t, calls)
+	})
+}
+
+func TestLedgerGenerator_Generate_IfNotExists(t *testing.T) {
+	var listedOrg string
+
+	mockSvc := &mockLedgersService{
+		listFunc: func(_ context.Context, orgID string, _ *models.ListOptions) (*models.ListResponse[models.Ledger], error) {
+			listedOrg = orgID
+			return &models.ListResponse[models.Ledger]{Items: []models.Ledger{{ID: "ledger-existing", Name: "Operations"}}}, nil
+		},
+	}
+
+	gen := NewLedgerGenerator(&entities.Entity{Ledgers: mockSvc}, nil, "")
+	ctx := WithIfNotExists(context.Background(), true)
+
+	ledger, err := gen.Generate(ctx, "org-1", data.LedgerTemplate{Name: "Operations"})
+	require.NoError(t, err)
+	assert.Equal(t, "ledger-existing", ledger.ID)
+	assert.Equal(t, "org-1", listedOrg)
+
+	ledger, err = gen.Generate(ctx, "org-1", data.LedgerTemplate{Name: "Settlement"})
+	require.NoError(t, err)
+	assert.Equal(t, "ledger-123", ledger.ID)
+}
+
+func TestAssetGenerator_Generate_IfNotExists(t *testing.T) {
+	mockSvc := &mockAssetsService{
+		listFunc: func(context.Context, string, string, *models.ListOptions) (*models.ListResponse[models.Asset], error) {
+			return &models.ListResponse[models.Asset]{Items: []models.Asset{{ID: "asset-existing", Code: "USD"}}}, nil
+		},
+	}
+
+	gen := NewAssetGenerator(&entities.Entity{Assets: mockSvc}, nil)
+	ctx := WithIfNotExists(WithOrgID(context.Background(), "org-1"), true)
+
+	asset, err := gen.Generate(ctx, "ledger-1", data.AssetTemplate{Name: "US Dollar", Code: "usd", Type: "currency"})
+	require.NoError(t, err)
+	assert.Equal(t, "asset-existing", asset.ID)
+
+	asset, err = gen.Generate(ctx, "ledger-1", data.AssetTemplate{Name: "Euro", Code: "EUR", Type: "currency"})
+	require.NoError(t, err)
+	assert.Equal(t, "asset-123", asset.ID)
+}
+
+func TestGenerate_IfNotExists_LookupError(t *testing.T) {
+	errList := errors.New("list failed")
+	mockSvc := &mockAssetsService{
+		listFunc: func(context.Context, string, string, *models.ListOptions) (*models.ListResponse[models.Asset], error) {
+			return nil, errList
+		},
+	}
+
+	gen := NewAssetGenerator(&entities.Entity{Assets: mockSvc}, nil)
+	ctx := WithIfNotExists(WithOrgID(context.Background(), "org-1"), true)
+
+	_, err := gen.Generate(ctx, "ledger-1", data.AssetTemplate{Name: "US Dollar", Code: "USD", Type: "currency"})
+	assert.ErrorIs(t, err, errList)
+}
@@ -69,4 +69,6 @@ type OperationRouteGenerator interface {
 type TransactionRouteGenerator interface {
 	Generate(ctx context.Context, orgID, ledgerID string, input *models.CreateTransactionRouteInput) (*models.TransactionRoute, error)
 	GenerateDefaults(ctx context.Context, orgID, ledgerID string, opRoutes []*models.OperationRoute) ([]*models.TransactionRoute, error)
+	ValidationSuite(txRoutes []*models.TransactionRoute, opRoutes []*models.OperationRoute, opts RouteValidationOptions) ([]RouteCase, error)
+	RunValidationSuite(ctx context.Context, orgID, ledgerID string, cases []RouteCase) (*RouteValidationReport, error)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/stats"
@@ -46,12 +47,21 @@ func (g *ledgerGenerator) Generate(ctx context.Context, orgID string, template d
 		return nil, errors.New("organization id is required")
 	}
 
+	if getIfNotExists(ctx) {
+		existing, err := g.findLedger(ctx, orgID, template)
+		if err != nil || existing != nil {
+			return existing, err
+		}
+	}
+
 	input := models.NewCreateLedgerInput(template.Name).
 		WithStatus(template.Status).
 		WithMetadata(template.Metadata)
 
 	var out *models.Ledger
 
+	started := time.Now()
+
 	err := observability.WithSpan(ctx, g.obs, "GenerateLedger", func(ctx context.Context) error {
 		return executeWithCircuitBreaker(ctx, func() error {
 			return retry.DoWithContext(ctx, func() error {
@@ -67,9 +77,13 @@ func (g *ledgerGenerator) Generate(ctx context.Context, orgID string, template d
 		})
 	})
 	if err != nil {
+		recordEvent(ctx, eventlog.EntityLedger, "", orgID, "", started, err)
+
 		return nil, err
 	}
 
+	recordEvent(ctx, eventlog.EntityLedger, out.ID, orgID, "", started, nil)
+
 	return out, nil
 }
 
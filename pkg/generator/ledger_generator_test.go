@@ -1,7 +1,9 @@
 package generator
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"strconv"
 	"sync/atomic"
@@ -10,6 +12,7 @@ import (
 	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -403,3 +406,23 @@ func TestLedgerGenerator_GenerateForOrg_AllErrors(t *testing.T) {
 	require.Error(t, err)
 	assert.Empty(t, results)
 }
+
+func TestLedgerGenerator_Generate_RecordsEvent(t *testing.T) {
+	var buf bytes.Buffer
+
+	ctx := eventlog.WithLog(context.Background(), eventlog.New(&buf))
+	gen := NewLedgerGenerator(&entities.Entity{Ledgers: &mockLedgersService{}}, nil, "")
+
+	_, err := gen.Generate(ctx, "org-123", data.LedgerTemplate{Name: "Test Ledger"})
+	require.NoError(t, err)
+
+	var ev eventlog.Event
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &ev))
+
+	assert.Equal(t, "generator", ev.Source)
+	assert.Equal(t, eventlog.EntityLedger, ev.Entity)
+	assert.Equal(t, "ledger-123", ev.ID)
+	assert.Equal(t, "org-123", ev.OrganizationID)
+	assert.Equal(t, eventlog.OutcomeSuccess, ev.Outcome)
+	assert.False(t, ev.StartedAt.IsZero())
+}
@@ -0,0 +1,207 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
+)
+
+// MultiCurrencyHolder groups the accounts an owner holds in several assets.
+// Midaz accounts hold a single asset, so a multi-currency position is one
+// account per asset sharing the same portfolio and entity.
+type MultiCurrencyHolder struct {
+	// Accounts maps asset codes to the holder account in that asset
+	Accounts map[string]*models.Account
+}
+
+// Alias returns the alias of the holder account in asset, or "" if there is none.
+func (h *MultiCurrencyHolder) Alias(asset string) string {
+	if h == nil {
+		return ""
+	}
+
+	acc, ok := h.Accounts[strings.ToUpper(asset)]
+	if !ok || acc == nil {
+		return ""
+	}
+
+	if alias := models.GetAccountAlias(*acc); alias != "" {
+		return alias
+	}
+
+	return acc.ID
+}
+
+// MultiCurrencyGenerator creates multi-currency holders and conversion accounts
+// and submits cross-currency flows between them.
+type MultiCurrencyGenerator struct {
+	accGen AccountGenerator
+	e      *entities.Entity
+	obs    observability.Provider
+}
+
+// NewMultiCurrencyGenerator creates a MultiCurrencyGenerator. Accounts are created
+// through accGen and conversions are submitted with the transactions service of e.
+func NewMultiCurrencyGenerator(accGen AccountGenerator, e *entities.Entity, obs observability.Provider) *MultiCurrencyGenerator {
+	return &MultiCurrencyGenerator{accGen: accGen, e: e, obs: obs}
+}
+
+// GenerateHolder creates one account per asset from template. When the template
+// has an alias, each account gets the alias suffixed with the lowercase asset
+// code (e.g., "customer_1_usd"); every account is tagged with its asset and the
+// base alias in metadata.
+func (g *MultiCurrencyGenerator) GenerateHolder(ctx context.Context, orgID, ledgerID string, template data.AccountTemplate, assets []string) (*MultiCurrencyHolder, error) {
+	if g.accGen == nil {
+		return nil, errors.New("account generator not initialized")
+	}
+
+	if len(assets) == 0 {
+		return nil, errors.New("at least one asset is required")
+	}
+
+	holder := &MultiCurrencyHolder{Accounts: make(map[string]*models.Account, len(assets))}
+
+	for _, asset := range assets {
+		code := strings.ToUpper(asset)
+		if _, dup := holder.Accounts[code]; dup {
+			continue
+		}
+
+		t := template
+		t.Metadata = mergeMetadata(template.Metadata, map[string]any{"holder_asset": code})
+
+		if template.Alias != nil && *template.Alias != "" {
+			alias := fmt.Sprintf("%s_%s", *template.Alias, strings.ToLower(code))
+			t.Alias = &alias
+			t.Metadata["holder_alias"] = *template.Alias
+		}
+
+		acc, err := g.accGen.Generate(ctx, orgID, ledgerID, code, t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s account: %w", code, err)
+		}
+
+		holder.Accounts[code] = acc
+	}
+
+	return holder, nil
+}
+
+// GenerateConversionAccounts creates one conversion account per asset, aliased
+// with data.ConversionAccountAlias. Conversion accounts pay out the target asset
+// of every conversion, so fund them before converting.
+func (g *MultiCurrencyGenerator) GenerateConversionAccounts(ctx context.Context, orgID, ledgerID string, assets []string) (map[string]*models.Account, error) {
+	if g.accGen == nil {
+		return nil, errors.New("account generator not initialized")
+	}
+
+	out := make(map[string]*models.Account, len(assets))
+
+	for _, asset := range assets {
+		code := strings.ToUpper(asset)
+		if _, dup := out[code]; dup {
+			continue
+		}
+
+		alias := data.ConversionAccountAlias(code)
+
+		acc, err := g.accGen.Generate(ctx, orgID, ledgerID, code, data.AccountTemplate{
+			Name:     fmt.Sprintf("FX Conversion %s", code),
+			Type:     "deposit",
+			Status:   models.NewStatus(models.StatusActive),
+			Alias:    &alias,
+			Metadata: map[string]any{"role": "fx_conversion", "asset": code},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s conversion account: %w", code, err)
+		}
+
+		out[code] = acc
+	}
+
+	return out, nil
+}
+
+// Convert submits both legs of a cross-currency transfer built with
+// data.BuildCrossCurrencyTransfer. The buy leg is only submitted once the sell
+// leg succeeds; if the buy leg fails, the returned transfer still reports the
+// sell transaction so callers can reverse it.
+func (g *MultiCurrencyGenerator) Convert(ctx context.Context, orgID, ledgerID string, params data.CrossCurrencyParams) (*CrossCurrencyResult, error) {
+	if g.e == nil || g.e.Transactions == nil {
+		return nil, errors.New("entity transactions service not initialized")
+	}
+
+	transfer, err := data.BuildCrossCurrencyTransfer(params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CrossCurrencyResult{ConvertedAmount: transfer.ConvertedAmount}
+
+	err = observability.WithSpan(ctx, g.obs, "MultiCurrency.Convert", func(ctx context.Context) error {
+		sell, err := g.submit(ctx, orgID, ledgerID, transfer.Sell)
+		if err != nil {
+			return fmt.Errorf("sell leg failed: %w", err)
+		}
+
+		result.Sell = sell
+
+		buy, err := g.submit(ctx, orgID, ledgerID, transfer.Buy)
+		if err != nil {
+			return fmt.Errorf("buy leg failed: %w", err)
+		}
+
+		result.Buy = buy
+
+		return nil
+	})
+
+	return result, err
+}
+
+// CrossCurrencyResult holds the transactions created by a conversion.
+type CrossCurrencyResult struct {
+	Sell            *models.Transaction
+	Buy             *models.Transaction
+	ConvertedAmount string
+}
+
+// submit creates a transaction with idempotency, circuit breaker and retries.
+func (g *MultiCurrencyGenerator) submit(ctx context.Context, orgID, ledgerID string, input *models.CreateTransactionInput) (*models.Transaction, error) {
+	ctx = entities.WithIdempotencyKey(ctx, input.IdempotencyKey)
+
+	var out *models.Transaction
+
+	started := time.Now()
+
+	err := executeWithCircuitBreaker(ctx, func() error {
+		return retry.DoWithContext(ctx, func() error {
+			tx, err := g.e.Transactions.CreateTransaction(ctx, orgID, ledgerID, input)
+			if err != nil {
+				return err
+			}
+
+			out = tx
+
+			return nil
+		})
+	})
+
+	id := ""
+	if out != nil {
+		id = out.ID
+	}
+
+	recordEvent(ctx, eventlog.EntityTransaction, id, orgID, ledgerID, started, err)
+
+	return out, err
+}
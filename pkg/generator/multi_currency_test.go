@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func aliasingAccountGenerator() *mockAccountGenerator {
+	return &mockAccountGenerator{
+		generateFunc: func(_ context.Context, _, _, assetCode string, t data.AccountTemplate) (*models.Account, error) {
+			return &models.Account{ID: "acc-" + *t.Alias, Alias: t.Alias, AssetCode: assetCode, Metadata: t.Metadata}, nil
+		},
+	}
+}
+
+func TestMultiCurrencyGenerator_GenerateHolder(t *testing.T) {
+	gen := NewMultiCurrencyGenerator(aliasingAccountGenerator(), nil, nil)
+
+	holder, err := gen.GenerateHolder(context.Background(), "org", "ledger", data.AccountTemplate{
+		Name:  "Customer 1",
+		Type:  "deposit",
+		Alias: data.StrPtr("customer_1"),
+	}, []string{"USD", "brl", "USD"})
+	require.NoError(t, err)
+
+	require.Len(t, holder.Accounts, 2)
+	assert.Equal(t, "customer_1_usd", holder.Alias("USD"))
+	assert.Equal(t, "customer_1_brl", holder.Alias("brl"))
+	assert.Equal(t, "BRL", holder.Accounts["BRL"].AssetCode)
+	assert.Equal(t, "customer_1", holder.Accounts["BRL"].Metadata["holder_alias"])
+	assert.Empty(t, holder.Alias("EUR"))
+}
+
+func TestMultiCurrencyGenerator_GenerateHolder_Errors(t *testing.T) {
+	_, err := NewMultiCurrencyGenerator(nil, nil, nil).GenerateHolder(context.Background(), "org", "ledger", data.AccountTemplate{}, []string{"USD"})
+	assert.Error(t, err)
+
+	_, err = NewMultiCurrencyGenerator(aliasingAccountGenerator(), nil, nil).GenerateHolder(context.Background(), "org", "ledger", data.AccountTemplate{}, nil)
+	assert.Error(t, err)
+
+	failing := &mockAccountGenerator{generateFunc: func(context.Context, string, string, string, data.AccountTemplate) (*models.Account, error) {
+		return nil, errors.New("boom")
+	}}
+	_, err = NewMultiCurrencyGenerator(failing, nil, nil).GenerateHolder(context.Background(), "org", "ledger", data.AccountTemplate{}, []string{"USD"})
+	assert.ErrorContains(t, err, "USD")
+}
+
+func TestMultiCurrencyGenerator_GenerateConversionAccounts(t *testing.T) {
+	gen := NewMultiCurrencyGenerator(aliasingAccountGenerator(), nil, nil)
+
+	accounts, err := gen.GenerateConversionAccounts(context.Background(), "org", "ledger", []string{"usd", "EUR"})
+	require.NoError(t, err)
+
+	require.Len(t, accounts, 2)
+	assert.Equal(t, "fx_conversion_usd", models.GetAccountAlias(*accounts["USD"]))
+	assert.Equal(t, "fx_conversion_eur", models.GetAccountAlias(*accounts["EUR"]))
+}
+
+func TestMultiCurrencyGenerator_Convert(t *testing.T) {
+	params := data.CrossCurrencyParams{
+		ID: "fx-1", SourceAlias: "c_usd", DestinationAlias: "c_eur",
+		FromAsset: "USD", ToAsset: "EUR", Amount: "10", Rate: "0.9", ToScale: 2,
+	}
+
+	t.Run("submits both legs", func(t *testing.T) {
+		var assets []string
+
+		svc := &mockTransactionsService{createFunc: func(_ context.Context, _, _ string, in *models.CreateTransactionInput) (*models.Transaction, error) {
+			assets = append(assets, in.AssetCode)
+			return &models.Transaction{ID: in.IdempotencyKey}, nil
+		}}
+
+		gen := NewMultiCurrencyGenerator(nil, &entities.Entity{Transactions: svc}, nil)
+
+		result, err := gen.Convert(context.Background(), "org", "ledger", params)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"USD", "EUR"}, assets)
+		assert.Equal(t, "fx-1-sell", result.Sell.ID)
+		assert.Equal(t, "fx-1-buy", result.Buy.ID)
+		assert.Equal(t, "9.00", result.ConvertedAmount)
+	})
+
+	t.Run("reports sell leg when buy fails", func(t *testing.T) {
+		svc := &mockTransactionsService{createFunc: func(_ context.Context, _, _ string, in *models.CreateTransactionInput) (*models.Transaction, error) {
+			if in.AssetCode == "EUR" {
+				return nil, errors.New("insufficient funds")
+			}
+
+			return &models.Transaction{ID: in.IdempotencyKey}, nil
+		}}
+
+		gen := NewMultiCurrencyGenerator(nil, &entities.Entity{Transactions: svc}, nil)
+
+		result, err := gen.Convert(context.Background(), "org", "ledger", params)
+		require.ErrorContains(t, err, "buy leg failed")
+		require.NotNil(t, result.Sell)
+		assert.Nil(t, result.Buy)
+	})
+
+	t.Run("not initialized", func(t *testing.T) {
+		_, err := NewMultiCurrencyGenerator(nil, nil, nil).Convert(context.Background(), "org", "ledger", params)
+		assert.Error(t, err)
+	})
+}
@@ -3,9 +3,11 @@ package generator
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
 )
@@ -33,6 +35,8 @@ func (g *operationRouteGenerator) Generate(ctx context.Context, orgID, ledgerID
 
 	var out *models.OperationRoute
 
+	started := time.Now()
+
 	err := observability.WithSpan(ctx, g.obs, "GenerateOperationRoute", func(ctx context.Context) error {
 		return executeWithCircuitBreaker(ctx, func() error {
 			return retry.DoWithContext(ctx, func() error {
@@ -48,63 +52,54 @@ func (g *operationRouteGenerator) Generate(ctx context.Context, orgID, ledgerID
 		})
 	})
 	if err != nil {
+		recordEvent(ctx, eventlog.EntityOperationRoute, "", orgID, ledgerID, started, err)
+
 		return nil, err
 	}
 
+	recordEvent(ctx, eventlog.EntityOperationRoute, out.ID.String(), orgID, ledgerID, started, nil)
+
 	return out, nil
 }
 
 // GenerateDefaults creates a minimal set of operation routes for common flows.
 func (g *operationRouteGenerator) GenerateDefaults(ctx context.Context, orgID, ledgerID string) ([]*models.OperationRoute, error) {
-	out := make([]*models.OperationRoute, 0, 5)
-
-	// Source: Customer (CHECKING)
-	srcCustomer := models.NewCreateOperationRouteInput(
-		"Source: Customer (CHECKING)",
-		"Allows checking-type customer accounts as source",
-		string(models.OperationRouteInputTypeSource),
-	).WithAccountTypes([]string{"CHECKING"}).WithMetadata(map[string]any{"role": "customer", "route": "source_checking"})
-
-	// Source: Merchant (CHECKING)
-	srcMerchant := models.NewCreateOperationRouteInput(
-		"Source: Merchant (CHECKING)",
-		"Allows checking-type merchant accounts as source (refund)",
-		string(models.OperationRouteInputTypeSource),
-	).WithAccountTypes([]string{"CHECKING"}).WithMetadata(map[string]any{"role": "merchant", "route": "source_checking_merchant"})
-
-	// Destination: Merchant (CHECKING)
-	dstMerchant := models.NewCreateOperationRouteInput(
-		"Destination: Merchant (CHECKING)",
-		"Allows checking-type merchant accounts as destination",
-		string(models.OperationRouteInputTypeDestination),
-	).WithAccountTypes([]string{"CHECKING"}).WithMetadata(map[string]any{"role": "merchant", "route": "dest_checking"})
-
-	// Destination: Platform Fee (alias)
-	dstPlatformFee := models.NewCreateOperationRouteInput(
-		"Destination: Platform Fee (alias)",
-		"Routes to platform fee account by alias",
-		string(models.OperationRouteInputTypeDestination),
-	).WithMetadata(map[string]any{"role": "internal", "route": "dest_platform_fee"})
-	dstPlatformFee = models.WithCreateOperationRouteAccountAlias(dstPlatformFee, "platform_fee")
-
-	// Destination: Settlement Pool (alias)
-	dstSettlement := models.NewCreateOperationRouteInput(
-		"Destination: Settlement Pool (alias)",
-		"Routes to settlement pool account by alias",
-		string(models.OperationRouteInputTypeDestination),
-	).WithMetadata(map[string]any{"role": "internal", "route": "dest_settlement"})
-	dstSettlement = models.WithCreateOperationRouteAccountAlias(dstSettlement, "settlement_pool")
-
-	// Destination: Customer (CHECKING) for refunds
-	dstCustomer := models.NewCreateOperationRouteInput(
-		"Destination: Customer (CHECKING)",
-		"Allows checking-type customer accounts as destination (refund)",
-		string(models.OperationRouteInputTypeDestination),
-	).WithAccountTypes([]string{"CHECKING"}).WithMetadata(map[string]any{"role": "customer", "route": "dest_checking_customer"})
-
-	templates := []*models.CreateOperationRouteInput{srcCustomer, srcMerchant, dstMerchant, dstPlatformFee, dstSettlement, dstCustomer}
-	for _, tpl := range templates {
-		or, err := g.Generate(ctx, orgID, ledgerID, tpl)
+	builders := []*models.OperationRouteBuilder{
+		models.NewOperationRouteBuilder("Source: Customer (CHECKING)").
+			Description("Allows checking-type customer accounts as source").
+			Source().AccountTypes(AccountTypeKeyChecking).
+			Metadata("role", "customer").Metadata("route", "source_checking"),
+		models.NewOperationRouteBuilder("Source: Merchant (CHECKING)").
+			Description("Allows checking-type merchant accounts as source (refund)").
+			Source().AccountTypes(AccountTypeKeyChecking).
+			Metadata("role", "merchant").Metadata("route", "source_checking_merchant"),
+		models.NewOperationRouteBuilder("Destination: Merchant (CHECKING)").
+			Description("Allows checking-type merchant accounts as destination").
+			Destination().AccountTypes(AccountTypeKeyChecking).
+			Metadata("role", "merchant").Metadata("route", "dest_checking"),
+		models.NewOperationRouteBuilder("Destination: Platform Fee (alias)").
+			Description("Routes to platform fee account by alias").
+			Destination().AccountAlias("platform_fee").
+			Metadata("role", "internal").Metadata("route", "dest_platform_fee"),
+		models.NewOperationRouteBuilder("Destination: Settlement Pool (alias)").
+			Description("Routes to settlement pool account by alias").
+			Destination().AccountAlias("settlement_pool").
+			Metadata("role", "internal").Metadata("route", "dest_settlement"),
+		models.NewOperationRouteBuilder("Destination: Customer (CHECKING)").
+			Description("Allows checking-type customer accounts as destination (refund)").
+			Destination().AccountTypes(AccountTypeKeyChecking).
+			Metadata("role", "customer").Metadata("route", "dest_checking_customer"),
+	}
+
+	out := make([]*models.OperationRoute, 0, len(builders))
+
+	for _, b := range builders {
+		input, err := b.Build()
+		if err != nil {
+			return nil, err
+		}
+
+		or, err := g.Generate(ctx, orgID, ledgerID, input)
 		if err != nil {
 			return nil, err
 		}
@@ -3,8 +3,11 @@ package generator
 import (
 	"context"
 	"runtime"
+	"time"
 
 	conc "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
 )
 
 // context keys
@@ -12,6 +15,9 @@ type (
 	contextKeyWorkers        struct{}
 	contextKeyCircuitBreaker struct{}
 	contextKeyOrgLocale      struct{}
+	contextKeyChaos          struct{}
+	contextKeyIfNotExists    struct{}
+	contextKeyAliasPolicies  struct{}
 )
 
 // WithWorkers stores a preferred worker count in context for batch generation.
@@ -88,3 +94,70 @@ func getOrgLocale(ctx context.Context) string {
 
 	return "us"
 }
+
+// WithChaos stores a chaos injector in context; batch generation and CommitAll
+// use it to inject failures into the transactions they submit.
+func WithChaos(ctx context.Context, inj *ChaosInjector) context.Context {
+	if inj == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, contextKeyChaos{}, inj)
+}
+
+func getChaos(ctx context.Context) *ChaosInjector {
+	if inj, ok := ctx.Value(contextKeyChaos{}).(*ChaosInjector); ok {
+		return inj
+	}
+
+	return nil
+}
+
+// WithIfNotExists makes generators look for an existing entity before creating
+// one, so that rerunning a scenario reuses the organizations, ledgers and
+// assets of the previous run instead of failing or duplicating them. Existing
+// entities are matched by deterministic keys: organizations by legal document
+// (or legal name when the template has none), ledgers by name within their
+// organization and assets by code within their ledger. They are returned
+// unchanged and not recorded in the event log.
+//
+// The lookup and the creation are not atomic: concurrent runs of the same
+// scenario can still create duplicates.
+func WithIfNotExists(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, contextKeyIfNotExists{}, enabled)
+}
+
+func getIfNotExists(ctx context.Context) bool {
+	enabled, _ := ctx.Value(contextKeyIfNotExists{}).(bool) //nolint:errcheck // missing key means disabled
+
+	return enabled
+}
+
+// WithAliasPolicies makes generators check the alias of every account they
+// create against the alias policies of its organization, claiming it so that
+// aliases repeated within the policy's uniqueness scope are rejected. An
+// account whose alias violates the policy fails before any request is sent.
+func WithAliasPolicies(ctx context.Context, policies *validation.AliasPolicies) context.Context {
+	if policies == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, contextKeyAliasPolicies{}, policies)
+}
+
+func getAliasPolicies(ctx context.Context) *validation.AliasPolicies {
+	if policies, ok := ctx.Value(contextKeyAliasPolicies{}).(*validation.AliasPolicies); ok {
+		return policies
+	}
+
+	return nil
+}
+
+// eventSource identifies generator events in the event log.
+const eventSource = "generator"
+
+// recordEvent appends the outcome of a generator call to the event log carried
+// by ctx, if any (see eventlog.WithLog).
+func recordEvent(ctx context.Context, entity, id, orgID, ledgerID string, started time.Time, err error) {
+	eventlog.Done(ctx, eventSource, entity, id, orgID, ledgerID, started, err)
+}
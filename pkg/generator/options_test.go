@@ -414,6 +414,12 @@ func TestWithLedgerID(t *testing.T) {
 	}
 }
 
+func TestWithIfNotExists(t *testing.T) {
+	assert.False(t, getIfNotExists(context.Background()))
+	assert.True(t, getIfNotExists(WithIfNotExists(context.Background(), true)))
+	assert.False(t, getIfNotExists(WithIfNotExists(WithIfNotExists(context.Background(), true), false)))
+}
+
 func TestContextChaining(t *testing.T) {
 	t.Run("Multiple context values can be chained", func(t *testing.T) {
 		ctx := context.Background()
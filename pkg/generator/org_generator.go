@@ -12,6 +12,7 @@ import (
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/stats"
@@ -43,6 +44,13 @@ func (g *orgGenerator) Generate(ctx context.Context, template data.OrgTemplate)
 		return nil, errors.New("entity organizations service not initialized")
 	}
 
+	if getIfNotExists(ctx) {
+		existing, err := g.findOrganization(ctx, template)
+		if err != nil || existing != nil {
+			return existing, err
+		}
+	}
+
 	input := models.NewCreateOrganizationInput(template.LegalName).
 		WithDoingBusinessAs(template.TradeName).
 		WithLegalDocument(template.TaxID).
@@ -52,6 +60,8 @@ func (g *orgGenerator) Generate(ctx context.Context, template data.OrgTemplate)
 
 	var out *models.Organization
 
+	started := time.Now()
+
 	err := observability.WithSpan(ctx, g.obs, "GenerateOrganization", func(ctx context.Context) error {
 		// Respect retry + circuit breaker options from context if present
 		return executeWithCircuitBreaker(ctx, func() error {
@@ -68,9 +78,13 @@ func (g *orgGenerator) Generate(ctx context.Context, template data.OrgTemplate)
 		})
 	})
 	if err != nil {
+		recordEvent(ctx, eventlog.EntityOrganization, "", "", "", started, err)
+
 		return nil, err
 	}
 
+	recordEvent(ctx, eventlog.EntityOrganization, out.ID, out.ID, "", started, nil)
+
 	return out, nil
 }
 
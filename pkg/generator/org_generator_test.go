@@ -256,6 +256,7 @@ func TestOrgGenerator_GenerateBatch_WithLocale(t *testing.T) {
 
 type mockOrganizationsService struct {
 	createFunc func(ctx context.Context, input *models.CreateOrganizationInput) (*models.Organization, error)
+	listFunc   func(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Organization], error)
 }
 
 func (m *mockOrganizationsService) CreateOrganization(ctx context.Context, input *models.CreateOrganizationInput) (*models.Organization, error) {
@@ -270,10 +271,18 @@ func (*mockOrganizationsService) GetOrganization(_ context.Context, _ string) (*
 	return nil, errors.New("mock: GetOrganization not implemented")
 }
 
-func (*mockOrganizationsService) ListOrganizations(_ context.Context, _ *models.ListOptions) (*models.ListResponse[models.Organization], error) {
+func (m *mockOrganizationsService) ListOrganizations(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Organization], error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, opts)
+	}
+
 	return nil, errors.New("mock: ListOrganizations not implemented")
 }
 
+func (*mockOrganizationsService) FindByLegalDocument(_ context.Context, _ string) (*models.Organization, error) {
+	return nil, errors.New("mock: FindByLegalDocument not implemented")
+}
+
 func (*mockOrganizationsService) UpdateOrganization(_ context.Context, _ string, _ *models.UpdateOrganizationInput) (*models.Organization, error) {
 	return nil, errors.New("mock: UpdateOrganization not implemented")
 }
@@ -3,9 +3,11 @@ package generator
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
 )
@@ -37,6 +39,8 @@ func (g *portfolioGenerator) Generate(ctx context.Context, orgID, ledgerID, name
 
 	var out *models.Portfolio
 
+	started := time.Now()
+
 	err := observability.WithSpan(ctx, g.obs, "GeneratePortfolio", func(ctx context.Context) error {
 		return executeWithCircuitBreaker(ctx, func() error {
 			return retry.DoWithContext(ctx, func() error {
@@ -52,8 +56,12 @@ func (g *portfolioGenerator) Generate(ctx context.Context, orgID, ledgerID, name
 		})
 	})
 	if err != nil {
+		recordEvent(ctx, eventlog.EntityPortfolio, "", orgID, ledgerID, started, err)
+
 		return nil, err
 	}
 
+	recordEvent(ctx, eventlog.EntityPortfolio, out.ID, orgID, ledgerID, started, nil)
+
 	return out, nil
 }
@@ -0,0 +1,481 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultRouteValidationAmount is the value sent by each source of the sample
+// transactions of a route validation suite.
+const DefaultRouteValidationAmount = "1.00"
+
+// ErrNoRouteAccount is returned by ValidationSuite when no account of
+// RouteValidationOptions.Accounts satisfies the account rule of an operation route.
+var ErrNoRouteAccount = errors.New("no account satisfies the operation route")
+
+// RouteValidationOptions configures the sample transactions of a route validation suite.
+type RouteValidationOptions struct {
+	// Asset is the asset code of the sample transactions.
+	Asset string
+
+	// Amount is the value sent by each source; defaults to DefaultRouteValidationAmount.
+	Amount string
+
+	// Accounts lists account aliases by account type key. Accounts satisfying
+	// the rules of the operation routes fill the legs of the expected-pass
+	// transactions; the others replace them in the expected-fail ones. Source
+	// accounts must be funded, the mismatching ones included, so that the
+	// expected-fail transactions can only be rejected by the route.
+	Accounts map[string][]string
+}
+
+// RouteCase is a sample transaction of a route validation suite.
+type RouteCase struct {
+	// Name describes the case, e.g. "Payment Flow: Destination: Platform Fee (alias) rejects @merchant_1".
+	Name string
+
+	// Route is the title of the transaction route under test.
+	Route string
+
+	// OperationRoute is the title of the operation route whose account rule
+	// the case violates, empty for expected-pass cases.
+	OperationRoute string
+
+	// ExpectAccepted tells whether the transaction should be accepted.
+	ExpectAccepted bool
+
+	// Input is the transaction to submit.
+	Input *models.CreateTransactionInput
+}
+
+// RouteCaseResult is the outcome of a RouteCase.
+type RouteCaseResult struct {
+	Case RouteCase
+
+	// Accepted tells whether the transaction was created.
+	Accepted bool
+
+	// Rejected tells whether the transaction was refused as invalid. A
+	// transaction refused for insufficient balance is not Rejected, since
+	// that says nothing about the route.
+	Rejected bool
+
+	// TransactionID is the ID of the created transaction, if any.
+	TransactionID string
+
+	// Err is the error returned when creating the transaction, if any.
+	Err error
+}
+
+// Passed tells whether the transaction behaved as the case expects.
+func (r RouteCaseResult) Passed() bool {
+	if r.Case.ExpectAccepted {
+		return r.Accepted
+	}
+
+	return r.Rejected
+}
+
+// RouteValidationReport holds the results of a route validation suite, in the order of its cases.
+type RouteValidationReport struct {
+	Results []RouteCaseResult
+}
+
+// Failures returns the results that didn't behave as expected.
+func (r *RouteValidationReport) Failures() []RouteCaseResult {
+	var out []RouteCaseResult
+
+	for _, res := range r.Results {
+		if !res.Passed() {
+			out = append(out, res)
+		}
+	}
+
+	return out
+}
+
+// OK tells whether every case behaved as expected.
+func (r *RouteValidationReport) OK() bool {
+	return len(r.Failures()) == 0
+}
+
+// ValidationSuite builds sample transactions checking that the transaction
+// routes behave as intended: for each route, one transaction whose legs use
+// accounts satisfying the rules of its operation routes, expected to pass,
+// and for each operation route with an account rule, the same transaction
+// with that leg moved to an account violating the rule, expected to fail.
+// Operation routes violated by no account of opts.Accounts get no
+// expected-fail case.
+//
+// The operation routes of txRoutes are looked up by ID in opRoutes, as
+// returned by OperationRouteGenerator.GenerateDefaults, so that their rules
+// are known even when the created transaction routes only carry their IDs.
+func (*transactionRouteGenerator) ValidationSuite(txRoutes []*models.TransactionRoute, opRoutes []*models.OperationRoute, opts RouteValidationOptions) ([]RouteCase, error) {
+	if strings.TrimSpace(opts.Asset) == "" {
+		return nil, errors.New("route validation asset is required")
+	}
+
+	if opts.Amount == "" {
+		opts.Amount = DefaultRouteValidationAmount
+	}
+
+	amount, err := decimal.NewFromString(opts.Amount)
+	if err != nil || !amount.IsPositive() {
+		return nil, fmt.Errorf("invalid route validation amount %q", opts.Amount)
+	}
+
+	byID := make(map[string]*models.OperationRoute, len(opRoutes))
+	for _, or := range opRoutes {
+		if or != nil {
+			byID[or.ID.String()] = or
+		}
+	}
+
+	var cases []RouteCase
+
+	for _, tr := range txRoutes {
+		if tr == nil {
+			continue
+		}
+
+		routeCases, err := routeCases(tr, byID, opts, amount)
+		if err != nil {
+			return nil, err
+		}
+
+		cases = append(cases, routeCases...)
+	}
+
+	return cases, nil
+}
+
+// RunValidationSuite submits the cases in order and reports how each
+// behaved. Each case is submitted once, without retries or the circuit
+// breaker, since rejections are an expected outcome. It returns an error only
+// if the transactions service is missing or ctx is done.
+func (g *transactionRouteGenerator) RunValidationSuite(ctx context.Context, orgID, ledgerID string, cases []RouteCase) (*RouteValidationReport, error) {
+	if g.e == nil || g.e.Transactions == nil {
+		return nil, errors.New("entity transactions service not initialized")
+	}
+
+	report := &RouteValidationReport{Results: make([]RouteCaseResult, 0, len(cases))}
+
+	err := observability.WithSpan(ctx, g.obs, "ValidateTransactionRoutes", func(ctx context.Context) error {
+		for _, c := range cases {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			report.Results = append(report.Results, g.runRouteCase(ctx, orgID, ledgerID, c))
+		}
+
+		return nil
+	})
+
+	return report, err
+}
+
+// runRouteCase submits the transaction of a case.
+func (g *transactionRouteGenerator) runRouteCase(ctx context.Context, orgID, ledgerID string, c RouteCase) RouteCaseResult {
+	result := RouteCaseResult{Case: c}
+	started := time.Now()
+
+	if c.Input.IdempotencyKey != "" {
+		ctx = entities.WithIdempotencyKey(ctx, c.Input.IdempotencyKey)
+	}
+
+	tx, err := g.e.Transactions.CreateTransaction(ctx, orgID, ledgerID, c.Input)
+
+	id := ""
+	if tx != nil {
+		id = tx.ID
+	}
+
+	recordEvent(ctx, eventlog.EntityTransaction, id, orgID, ledgerID, started, err)
+
+	result.Err = err
+	result.Accepted = err == nil
+	result.Rejected = isRouteRejection(err)
+	result.TransactionID = id
+
+	return result
+}
+
+// isRouteRejection tells whether err refuses a transaction as invalid, as
+// opposed to failing for reasons unrelated to its content.
+func isRouteRejection(err error) bool {
+	if err == nil || sdkerrors.IsInsufficientBalanceError(err) {
+		return false
+	}
+
+	switch code := sdkerrors.GetStatusCode(err); code {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	default:
+		return code >= http.StatusBadRequest && code < http.StatusInternalServerError
+	}
+}
+
+// routeLeg is a leg of a sample transaction and the operation route it goes through.
+type routeLeg struct {
+	route   *models.OperationRoute
+	account string
+}
+
+// routeCases builds the cases of a transaction route.
+func routeCases(tr *models.TransactionRoute, byID map[string]*models.OperationRoute, opts RouteValidationOptions, amount decimal.Decimal) ([]RouteCase, error) {
+	var sources, destinations []routeLeg
+
+	for i := range tr.OperationRoutes {
+		or := &tr.OperationRoutes[i]
+		if full, ok := byID[or.ID.String()]; ok {
+			or = full
+		}
+
+		switch {
+		case or.OperationType == string(models.OperationRouteTypeSource),
+			or.OperationType != string(models.OperationRouteTypeDestination) && len(sources) == 0:
+			sources = append(sources, routeLeg{route: or})
+		default:
+			destinations = append(destinations, routeLeg{route: or})
+		}
+	}
+
+	if len(sources) == 0 || len(destinations) == 0 {
+		return nil, fmt.Errorf("transaction route %q needs source and destination operation routes", tr.Title)
+	}
+
+	// The legs use distinct accounts where the rules allow, so that no transaction moves funds to its own source
+	used := make(map[string]bool)
+
+	for _, legs := range [][]routeLeg{sources, destinations} {
+		for i := range legs {
+			account, ok := matchingAccount(legs[i].route.Account, opts.Accounts, used)
+			if !ok {
+				return nil, fmt.Errorf("%w %q of transaction route %q", ErrNoRouteAccount, legs[i].route.Title, tr.Title)
+			}
+
+			legs[i].account = account
+			used[account] = true
+		}
+	}
+
+	cases := []RouteCase{{
+		Name:           tr.Title + ": accepts matching accounts",
+		Route:          tr.Title,
+		ExpectAccepted: true,
+		Input:          routeTransaction(tr, sources, destinations, opts.Asset, amount),
+	}}
+
+	for side, legs := range [][]routeLeg{sources, destinations} {
+		for i, leg := range legs {
+			if leg.route.Account == nil {
+				continue
+			}
+
+			account, ok := mismatchingAccount(leg.route.Account, opts.Accounts, used)
+			if !ok {
+				continue
+			}
+
+			mutated := slices.Clone(legs)
+			mutated[i].account = account
+
+			src, dst := mutated, destinations
+			if side == 1 {
+				src, dst = sources, mutated
+			}
+
+			cases = append(cases, RouteCase{
+				Name:           fmt.Sprintf("%s: %s rejects %s", tr.Title, leg.route.Title, account),
+				Route:          tr.Title,
+				OperationRoute: leg.route.Title,
+				Input:          routeTransaction(tr, src, dst, opts.Asset, amount),
+			})
+		}
+	}
+
+	return cases, nil
+}
+
+// routeTransaction builds a transaction through tr where each source sends
+// amount and the destinations share the total, the first one receiving any
+// rounding remainder.
+func routeTransaction(tr *models.TransactionRoute, sources, destinations []routeLeg, asset string, amount decimal.Decimal) *models.CreateTransactionInput {
+	total := amount.Mul(decimal.NewFromInt(int64(len(sources))))
+	share := total.Div(decimal.NewFromInt(int64(len(destinations)))).Truncate(max(-amount.Exponent(), 0))
+
+	from := make([]models.FromToInput, len(sources))
+	for i, leg := range sources {
+		from[i] = models.FromToInput{
+			Account: leg.account,
+			Amount:  models.AmountInput{Asset: asset, Value: amount.String()},
+			Route:   leg.route.ID.String(),
+		}
+	}
+
+	to := make([]models.FromToInput, len(destinations))
+	for i, leg := range destinations {
+		value := share
+		if i == 0 {
+			value = total.Sub(share.Mul(decimal.NewFromInt(int64(len(destinations) - 1))))
+		}
+
+		to[i] = models.FromToInput{
+			Account: leg.account,
+			Amount:  models.AmountInput{Asset: asset, Value: value.String()},
+			Route:   leg.route.ID.String(),
+		}
+	}
+
+	return &models.CreateTransactionInput{
+		Description: "Route validation: " + tr.Title,
+		Amount:      total.String(),
+		AssetCode:   asset,
+		Route:       tr.ID.String(),
+		Send: &models.SendInput{
+			Asset:      asset,
+			Value:      total.String(),
+			Source:     &models.SourceInput{From: from},
+			Distribute: &models.DistributeInput{To: to},
+		},
+		Metadata: map[string]any{"route_validation": true, "route": tr.Title},
+	}
+}
+
+// matchingAccount returns an account satisfying rule, preferring one not used
+// yet. An alias rule is satisfied by its alias only.
+func matchingAccount(rule *models.AccountRule, accounts map[string][]string, used map[string]bool) (string, bool) {
+	if rule != nil && rule.RuleType == models.AccountRuleTypeAlias {
+		values := ruleValues(rule)
+		if len(values) == 0 {
+			return "", false
+		}
+
+		return values[0], true
+	}
+
+	var fallback string
+
+	for _, key := range sortedKeys(accounts) {
+		if !accountTypeAllowed(rule, key) {
+			continue
+		}
+
+		for _, alias := range accounts[key] {
+			if !used[alias] {
+				return alias, true
+			}
+
+			if fallback == "" {
+				fallback = alias
+			}
+		}
+	}
+
+	return fallback, fallback != ""
+}
+
+// mismatchingAccount returns an account violating rule, preferring one not used by the other legs.
+func mismatchingAccount(rule *models.AccountRule, accounts map[string][]string, used map[string]bool) (string, bool) {
+	var fallback string
+
+	for _, key := range sortedKeys(accounts) {
+		for _, alias := range accounts[key] {
+			if accountSatisfies(rule, key, alias) {
+				continue
+			}
+
+			if !used[alias] {
+				return alias, true
+			}
+
+			if fallback == "" {
+				fallback = alias
+			}
+		}
+	}
+
+	return fallback, fallback != ""
+}
+
+// accountSatisfies tells whether the account alias of type key satisfies rule.
+func accountSatisfies(rule *models.AccountRule, key, alias string) bool {
+	if rule == nil {
+		return true
+	}
+
+	if rule.RuleType == models.AccountRuleTypeAlias {
+		for _, v := range ruleValues(rule) {
+			if strings.TrimPrefix(v, "@") == strings.TrimPrefix(alias, "@") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return accountTypeAllowed(rule, key)
+}
+
+// accountTypeAllowed tells whether accounts of type key satisfy an account type rule.
+func accountTypeAllowed(rule *models.AccountRule, key string) bool {
+	if rule == nil || rule.RuleType != models.AccountRuleTypeAccountType {
+		return true
+	}
+
+	for _, v := range ruleValues(rule) {
+		if strings.EqualFold(v, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ruleValues returns the values of an account rule, which hold a string for
+// alias rules and a list for account type rules, decoded as []any from JSON.
+func ruleValues(rule *models.AccountRule) []string {
+	switch v := rule.ValidIf.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+
+		return out
+	default:
+		return nil
+	}
+}
+
+// sortedKeys returns the account type keys in a stable order.
+func sortedKeys(accounts map[string][]string) []string {
+	keys := make([]string, 0, len(accounts))
+	for k := range accounts {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
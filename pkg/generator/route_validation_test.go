@@ -0,0 +1,225 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testOperationRoute(title, operationType string, rule *models.AccountRule) *models.OperationRoute {
+	return &models.OperationRoute{ID: uuid.New(), Title: title, OperationType: operationType, Account: rule}
+}
+
+func testPaymentRoutes() ([]*models.TransactionRoute, []*models.OperationRoute) {
+	src := testOperationRoute("Source: Customer (CHECKING)", "source",
+		&models.AccountRule{RuleType: models.AccountRuleTypeAccountType, ValidIf: []any{AccountTypeKeyChecking}})
+	merchant := testOperationRoute("Destination: Merchant (CHECKING)", "destination",
+		&models.AccountRule{RuleType: models.AccountRuleTypeAccountType, ValidIf: []string{AccountTypeKeyChecking}})
+	fee := testOperationRoute("Destination: Platform Fee (alias)", "destination",
+		&models.AccountRule{RuleType: models.AccountRuleTypeAlias, ValidIf: "@platform_fee"})
+
+	// The created transaction route only carries the IDs of its operation routes
+	tr := &models.TransactionRoute{
+		ID:    uuid.New(),
+		Title: "Payment Flow",
+		OperationRoutes: []models.OperationRoute{
+			{ID: src.ID}, {ID: merchant.ID}, {ID: fee.ID},
+		},
+	}
+
+	return []*models.TransactionRoute{tr}, []*models.OperationRoute{src, merchant, fee}
+}
+
+func testRouteAccounts() map[string][]string {
+	return map[string][]string{
+		AccountTypeKeyChecking: {"@customer_1", "@merchant_1", "@platform_fee"},
+		AccountTypeKeySavings:  {"@savings_1"},
+	}
+}
+
+func TestTransactionRouteGenerator_ValidationSuite(t *testing.T) {
+	gen := NewTransactionRouteGenerator(nil, nil)
+	txRoutes, opRoutes := testPaymentRoutes()
+
+	cases, err := gen.ValidationSuite(txRoutes, opRoutes, RouteValidationOptions{
+		Asset:    "USD",
+		Amount:   "1.00",
+		Accounts: testRouteAccounts(),
+	})
+	require.NoError(t, err)
+	require.Len(t, cases, 4)
+
+	pass := cases[0]
+	assert.True(t, pass.ExpectAccepted)
+	assert.Equal(t, "Payment Flow", pass.Route)
+	assert.Equal(t, txRoutes[0].ID.String(), pass.Input.Route)
+	require.NoError(t, pass.Input.Validate())
+
+	from := pass.Input.Send.Source.From
+	to := pass.Input.Send.Distribute.To
+
+	require.Len(t, from, 1)
+	require.Len(t, to, 2)
+	assert.Equal(t, "@customer_1", from[0].Account)
+	assert.Equal(t, opRoutes[0].ID.String(), from[0].Route)
+	assert.Equal(t, "@merchant_1", to[0].Account)
+	assert.Equal(t, "@platform_fee", to[1].Account)
+	assert.Equal(t, "0.5", to[0].Amount.Value)
+	assert.Equal(t, "0.5", to[1].Amount.Value)
+
+	for _, c := range cases[1:] {
+		assert.False(t, c.ExpectAccepted, c.Name)
+		assert.NotEmpty(t, c.OperationRoute, c.Name)
+		assert.Equal(t, pass.Input.Route, c.Input.Route, c.Name)
+	}
+
+	// Each expected-fail case moves a single leg to an account violating its rule
+	assert.Equal(t, "@savings_1", cases[1].Input.Send.Source.From[0].Account)
+	assert.Equal(t, "@merchant_1", cases[1].Input.Send.Distribute.To[0].Account)
+	assert.Equal(t, "@savings_1", cases[2].Input.Send.Distribute.To[0].Account)
+	assert.Equal(t, "@customer_1", cases[3].Input.Send.Source.From[0].Account)
+	assert.Equal(t, "@savings_1", cases[3].Input.Send.Distribute.To[1].Account)
+	assert.Equal(t, "Destination: Platform Fee (alias)", cases[3].OperationRoute)
+}
+
+func TestTransactionRouteGenerator_ValidationSuite_SplitsRemainder(t *testing.T) {
+	src := testOperationRoute("Source", "source", nil)
+	dests := []*models.OperationRoute{
+		testOperationRoute("Destination A", "destination", nil),
+		testOperationRoute("Destination B", "destination", nil),
+		testOperationRoute("Destination C", "destination", nil),
+	}
+	tr := &models.TransactionRoute{ID: uuid.New(), Title: "Split", OperationRoutes: []models.OperationRoute{*src, *dests[0], *dests[1], *dests[2]}}
+
+	cases, err := NewTransactionRouteGenerator(nil, nil).ValidationSuite([]*models.TransactionRoute{tr}, nil, RouteValidationOptions{
+		Asset:    "USD",
+		Amount:   "1.00",
+		Accounts: map[string][]string{AccountTypeKeyChecking: {"@a", "@b", "@c", "@d"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, cases, 1, "routes without account rules have no expected-fail cases")
+
+	var values []string
+	for _, to := range cases[0].Input.Send.Distribute.To {
+		values = append(values, to.Amount.Value)
+	}
+
+	assert.Equal(t, []string{"0.34", "0.33", "0.33"}, values)
+}
+
+func TestTransactionRouteGenerator_ValidationSuite_Errors(t *testing.T) {
+	gen := NewTransactionRouteGenerator(nil, nil)
+	txRoutes, opRoutes := testPaymentRoutes()
+
+	t.Run("missing asset", func(t *testing.T) {
+		_, err := gen.ValidationSuite(txRoutes, opRoutes, RouteValidationOptions{Accounts: testRouteAccounts()})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid amount", func(t *testing.T) {
+		_, err := gen.ValidationSuite(txRoutes, opRoutes, RouteValidationOptions{Asset: "USD", Amount: "-1", Accounts: testRouteAccounts()})
+		assert.Error(t, err)
+	})
+
+	t.Run("no matching account", func(t *testing.T) {
+		_, err := gen.ValidationSuite(txRoutes, opRoutes, RouteValidationOptions{
+			Asset:    "USD",
+			Accounts: map[string][]string{AccountTypeKeySavings: {"@savings_1"}},
+		})
+		assert.ErrorIs(t, err, ErrNoRouteAccount)
+	})
+
+	t.Run("no destination", func(t *testing.T) {
+		tr := &models.TransactionRoute{Title: "Broken", OperationRoutes: []models.OperationRoute{{ID: opRoutes[0].ID}}}
+		_, err := gen.ValidationSuite([]*models.TransactionRoute{tr}, opRoutes, RouteValidationOptions{Asset: "USD", Accounts: testRouteAccounts()})
+		assert.Error(t, err)
+	})
+}
+
+func TestTransactionRouteGenerator_RunValidationSuite(t *testing.T) {
+	txRoutes, opRoutes := testPaymentRoutes()
+
+	// The fake ledger enforces the rules of the source and merchant routes but not the platform fee alias
+	mockTx := &mockTransactionsService{
+		createFunc: func(_ context.Context, _, _ string, input *models.CreateTransactionInput) (*models.Transaction, error) {
+			if input.Send.Source.From[0].Account == "@savings_1" || input.Send.Distribute.To[0].Account == "@savings_1" {
+				return nil, sdkerrors.NewValidationError("CreateTransaction", "account type not allowed by route", nil)
+			}
+
+			return &models.Transaction{ID: "tx-" + input.Send.Distribute.To[1].Account}, nil
+		},
+	}
+
+	gen := NewTransactionRouteGenerator(&entities.Entity{Transactions: mockTx}, nil)
+
+	cases, err := gen.ValidationSuite(txRoutes, opRoutes, RouteValidationOptions{Asset: "USD", Accounts: testRouteAccounts()})
+	require.NoError(t, err)
+
+	report, err := gen.RunValidationSuite(context.Background(), "org", "ledger", cases)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 4)
+
+	assert.True(t, report.Results[0].Passed())
+	assert.Equal(t, "tx-@platform_fee", report.Results[0].TransactionID)
+	assert.True(t, report.Results[1].Passed())
+	assert.True(t, report.Results[2].Passed())
+	assert.False(t, report.Results[3].Passed())
+	assert.True(t, report.Results[3].Accepted)
+
+	assert.False(t, report.OK())
+
+	failures := report.Failures()
+	require.Len(t, failures, 1)
+	assert.Equal(t, "Destination: Platform Fee (alias)", failures[0].Case.OperationRoute)
+}
+
+func TestTransactionRouteGenerator_RunValidationSuite_Outcomes(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		expect bool
+		passed bool
+	}{
+		{"accepted as expected", nil, true, true},
+		{"rejected as expected", sdkerrors.NewValidationError("CreateTransaction", "invalid route", nil), false, true},
+		{"rejected unexpectedly", sdkerrors.NewValidationError("CreateTransaction", "invalid route", nil), true, false},
+		{"insufficient balance is inconclusive", sdkerrors.NewInsufficientBalanceError("CreateTransaction", "acc", nil), false, false},
+		{"server error is inconclusive", errors.New("connection reset"), false, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockTx := &mockTransactionsService{
+				createFunc: func(context.Context, string, string, *models.CreateTransactionInput) (*models.Transaction, error) {
+					if tc.err != nil {
+						return nil, tc.err
+					}
+
+					return &models.Transaction{ID: "tx"}, nil
+				},
+			}
+
+			gen := NewTransactionRouteGenerator(&entities.Entity{Transactions: mockTx}, nil)
+			c := RouteCase{Name: tc.name, ExpectAccepted: tc.expect, Input: &models.CreateTransactionInput{}}
+
+			report, err := gen.RunValidationSuite(context.Background(), "org", "ledger", []RouteCase{c})
+			require.NoError(t, err)
+			assert.Equal(t, tc.passed, report.Results[0].Passed())
+			assert.Equal(t, tc.passed, report.OK())
+		})
+	}
+}
+
+func TestTransactionRouteGenerator_RunValidationSuite_NilService(t *testing.T) {
+	gen := NewTransactionRouteGenerator(&entities.Entity{}, nil)
+
+	_, err := gen.RunValidationSuite(context.Background(), "org", "ledger", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+)
+
+// StressSubmitFunc submits a single transaction of a stress plan.
+type StressSubmitFunc func(ctx context.Context, tx data.StressTransaction) error
+
+// StressCohortResult aggregates the outcome of a cohort in a stress run.
+type StressCohortResult struct {
+	Submitted int
+	Succeeded int
+	Failed    int
+
+	// RejectedOverLimit counts over-limit transactions rejected as expected
+	RejectedOverLimit int
+
+	// AcceptedOverLimit counts over-limit transactions that were accepted,
+	// meaning the limit was not enforced
+	AcceptedOverLimit int
+
+	// Errors holds the errors of failed transactions that were expected to succeed
+	Errors []error
+}
+
+// StressSummary is the outcome of a stress run, per cohort.
+type StressSummary struct {
+	Cohorts map[string]*StressCohortResult
+	Elapsed time.Duration
+}
+
+// RunStressPlan submits the transactions of plan at their scheduled offsets from
+// the start of the run. Submissions run concurrently, bounded by the worker count
+// stored in ctx (see WithWorkers), so slow responses do not delay the schedule.
+// It returns the context error if the run is cancelled before all transactions
+// are submitted; the summary covers what was submitted until then.
+//
+// Example:
+//
+//	summary, err := generator.RunStressPlan(ctx, plan, func(ctx context.Context, tx data.StressTransaction) error {
+//	    _, err := client.Entity.Transactions.CreateTransaction(ctx, orgID, ledgerID, toInput(tx))
+//	    return err
+//	})
+func RunStressPlan(ctx context.Context, plan []data.StressTransaction, submit StressSubmitFunc) (*StressSummary, error) {
+	if submit == nil {
+		return nil, errors.New("stress submit function is required")
+	}
+
+	summary := &StressSummary{Cohorts: make(map[string]*StressCohortResult)}
+	start := time.Now()
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, getWorkers(ctx))
+		stop error
+	)
+
+	for _, tx := range plan {
+		if stop = waitUntil(ctx, start.Add(tx.At)); stop != nil {
+			break
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			stop = ctx.Err()
+		}
+
+		if stop != nil {
+			break
+		}
+
+		wg.Add(1)
+
+		go func(tx data.StressTransaction) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := submit(ctx, tx)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			summary.record(tx, err)
+		}(tx)
+	}
+
+	wg.Wait()
+
+	summary.Elapsed = time.Since(start)
+
+	return summary, stop
+}
+
+// record accounts for the outcome of a submitted transaction
+func (s *StressSummary) record(tx data.StressTransaction, err error) {
+	result, ok := s.Cohorts[tx.Cohort]
+	if !ok {
+		result = &StressCohortResult{}
+		s.Cohorts[tx.Cohort] = result
+	}
+
+	result.Submitted++
+
+	switch {
+	case tx.ExceedsLimit && err != nil:
+		result.RejectedOverLimit++
+	case tx.ExceedsLimit:
+		result.AcceptedOverLimit++
+	case err != nil:
+		result.Failed++
+		result.Errors = append(result.Errors, err)
+	default:
+		result.Succeeded++
+	}
+}
+
+// waitUntil blocks until deadline or context cancellation.
+func waitUntil(ctx context.Context, deadline time.Time) error {
+	d := time.Until(deadline)
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunStressPlan(t *testing.T) {
+	plan := []data.StressTransaction{
+		{Cohort: "hot", Account: "@a", Amount: 100, At: 0},
+		{Cohort: "hot", Account: "@a", Amount: 100, At: 5 * time.Millisecond},
+		{Cohort: "hot", Account: "@b", Amount: 100, At: 10 * time.Millisecond},
+		{Cohort: "drain", Account: "@c", Amount: 900, At: 0},
+		{Cohort: "drain", Account: "@c", Amount: 200, At: 15 * time.Millisecond, ExceedsLimit: true},
+		{Cohort: "drain", Account: "@d", Amount: 200, At: 15 * time.Millisecond, ExceedsLimit: true},
+	}
+
+	summary, err := RunStressPlan(context.Background(), plan, func(_ context.Context, tx data.StressTransaction) error {
+		if tx.Account == "@b" || tx.Account == "@c" && tx.ExceedsLimit {
+			return errors.New("rejected")
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	hot := summary.Cohorts["hot"]
+	require.NotNil(t, hot)
+	assert.Equal(t, 3, hot.Submitted)
+	assert.Equal(t, 2, hot.Succeeded)
+	assert.Equal(t, 1, hot.Failed)
+	assert.Len(t, hot.Errors, 1)
+
+	drain := summary.Cohorts["drain"]
+	require.NotNil(t, drain)
+	assert.Equal(t, 1, drain.Succeeded)
+	assert.Equal(t, 1, drain.RejectedOverLimit)
+	assert.Equal(t, 1, drain.AcceptedOverLimit)
+
+	assert.GreaterOrEqual(t, summary.Elapsed, 15*time.Millisecond)
+}
+
+func TestRunStressPlan_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	plan := []data.StressTransaction{
+		{Cohort: "hot", Account: "@a", At: 0},
+		{Cohort: "hot", Account: "@a", At: time.Hour},
+	}
+
+	var calls atomic.Int32
+
+	summary, err := RunStressPlan(ctx, plan, func(context.Context, data.StressTransaction) error {
+		calls.Add(1)
+		return nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, 1, summary.Cohorts["hot"].Submitted)
+}
+
+func TestRunStressPlan_NilSubmit(t *testing.T) {
+	_, err := RunStressPlan(context.Background(), nil, nil)
+	assert.Error(t, err)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/data"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/stats"
@@ -50,6 +51,8 @@ func (g *transactionGenerator) GenerateWithDSL(ctx context.Context, orgID, ledge
 		ctx = entities.WithIdempotencyKey(ctx, pattern.IdempotencyKey)
 	}
 
+	started := time.Now()
+
 	err := observability.WithSpan(ctx, g.obs, "GenerateTransactionDSL", func(ctx context.Context) error {
 		return executeWithCircuitBreaker(ctx, func() error {
 			return retry.DoWithContext(ctx, func() error {
@@ -66,9 +69,13 @@ func (g *transactionGenerator) GenerateWithDSL(ctx context.Context, orgID, ledge
 		})
 	})
 	if err != nil {
+		recordEvent(ctx, eventlog.EntityTransaction, "", orgID, ledgerID, started, err)
+
 		return nil, err
 	}
 
+	recordEvent(ctx, eventlog.EntityTransaction, out.ID, orgID, ledgerID, started, nil)
+
 	return out, nil
 }
 
@@ -104,6 +111,7 @@ func collectBatchResults(results []concurrent.Result[int, *models.Transaction])
 }
 
 // GenerateBatch submits a list of DSL patterns with a target TPS throttle.
+// When a ChaosInjector is stored in ctx, failures are injected into the patterns first.
 func (g *transactionGenerator) GenerateBatch(ctx context.Context, orgID, ledgerID string, patterns []data.TransactionPattern, tps float64) ([]*models.Transaction, error) {
 	if len(patterns) == 0 {
 		return []*models.Transaction{}, nil
@@ -114,6 +122,10 @@ func (g *transactionGenerator) GenerateBatch(ctx context.Context, orgID, ledgerI
 		timer = g.mc.NewTimer(ctx, "transactions.batch.dsl", "transactions")
 	}
 
+	if inj := getChaos(ctx); inj != nil {
+		patterns = inj.ApplyToPatterns(patterns)
+	}
+
 	counter := stats.NewCounter()
 
 	tick, stopTicker := setupThrottleTicker(tps)
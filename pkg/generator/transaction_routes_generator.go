@@ -3,9 +3,11 @@ package generator
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
 )
@@ -33,6 +35,8 @@ func (g *transactionRouteGenerator) Generate(ctx context.Context, orgID, ledgerI
 
 	var out *models.TransactionRoute
 
+	started := time.Now()
+
 	err := observability.WithSpan(ctx, g.obs, "GenerateTransactionRoute", func(ctx context.Context) error {
 		return executeWithCircuitBreaker(ctx, func() error {
 			return retry.DoWithContext(ctx, func() error {
@@ -48,14 +52,19 @@ func (g *transactionRouteGenerator) Generate(ctx context.Context, orgID, ledgerI
 		})
 	})
 	if err != nil {
+		recordEvent(ctx, eventlog.EntityTransactionRoute, "", orgID, ledgerID, started, err)
+
 		return nil, err
 	}
 
+	recordEvent(ctx, eventlog.EntityTransactionRoute, out.ID.String(), orgID, ledgerID, started, nil)
+
 	return out, nil
 }
 
 // GenerateDefaults creates default transaction routes for common flows.
-// Requires the operation routes (by ID) already created via OperationRouteGenerator.
+// Requires the operation routes (by ID) already created via OperationRouteGenerator;
+// flows missing their source or every destination route are skipped.
 func (g *transactionRouteGenerator) GenerateDefaults(ctx context.Context, orgID, ledgerID string, opRoutes []*models.OperationRoute) ([]*models.TransactionRoute, error) {
 	// Map titles for convenience
 	byTitle := map[string]string{}
@@ -63,80 +72,56 @@ func (g *transactionRouteGenerator) GenerateDefaults(ctx context.Context, orgID,
 		byTitle[or.Title] = or.ID.String()
 	}
 
-	routes := make([]*models.TransactionRoute, 0, 3)
-
-	// Payment: Customer Source (CHECKING) -> Merchant Dest (CHECKING) + Platform Fee Dest
-	payOps := []string{}
-	srcCustomerID, srcOk := byTitle["Source: Customer (CHECKING)"]
-
-	if srcOk {
-		payOps = append(payOps, srcCustomerID)
-	}
-
-	dstMerchantID, dstMerchantOk := byTitle["Destination: Merchant (CHECKING)"]
-	if dstMerchantOk {
-		payOps = append(payOps, dstMerchantID)
+	flows := []struct {
+		title        string
+		description  string
+		pattern      string
+		source       string
+		destinations []string
+	}{
+		// Payment: Customer Source (CHECKING) -> Merchant Dest (CHECKING) + Platform Fee Dest
+		{"Payment Flow", "Customer pays merchant with platform fee", "payment",
+			"Source: Customer (CHECKING)", []string{"Destination: Merchant (CHECKING)", "Destination: Platform Fee (alias)"}},
+		// Refund: Merchant Source (CHECKING) -> Customer Dest (CHECKING)
+		{"Refund Flow", "Merchant refunds customer", "refund",
+			"Source: Merchant (CHECKING)", []string{"Destination: Customer (CHECKING)"}},
+		// Transfer: Checking -> Checking (generic)
+		{"Transfer Flow", "Internal transfer between checking accounts", "transfer",
+			"Source: Customer (CHECKING)", []string{"Destination: Customer (CHECKING)"}},
 	}
 
-	dstPlatformID, dstPlatformOk := byTitle["Destination: Platform Fee (alias)"]
-	if dstPlatformOk {
-		payOps = append(payOps, dstPlatformID)
-	}
-
-	if len(payOps) >= 2 { // at least source + one dest
-		input := models.NewCreateTransactionRouteInput("Payment Flow", "Customer pays merchant with platform fee", payOps).
-			WithMetadata(map[string]any{"pattern": "payment"})
+	routes := make([]*models.TransactionRoute, 0, len(flows))
 
-		tr, err := g.Generate(ctx, orgID, ledgerID, input)
-		if err != nil {
-			return nil, err
+	for _, f := range flows {
+		srcID, ok := byTitle[f.source]
+		if !ok {
+			continue
 		}
 
-		routes = append(routes, tr)
-	}
+		b := models.NewTransactionRouteBuilder(f.title).
+			Description(f.description).
+			Metadata("pattern", f.pattern).
+			Source(srcID)
 
-	// Refund: Merchant Source (CHECKING) -> Customer Dest (CHECKING)
-	refundOps := []string{}
-	refundMerchantID, refundMerchantOk := byTitle["Source: Merchant (CHECKING)"]
+		dests := 0
 
-	if refundMerchantOk {
-		refundOps = append(refundOps, refundMerchantID)
-	}
+		for _, title := range f.destinations {
+			if id, ok := byTitle[title]; ok {
+				b.Destination(id)
 
-	refundCustomerID, refundCustomerOk := byTitle["Destination: Customer (CHECKING)"]
-	if refundCustomerOk {
-		refundOps = append(refundOps, refundCustomerID)
-	}
+				dests++
+			}
+		}
 
-	if len(refundOps) >= 2 {
-		input := models.NewCreateTransactionRouteInput("Refund Flow", "Merchant refunds customer", refundOps).
-			WithMetadata(map[string]any{"pattern": "refund"})
+		if dests == 0 {
+			continue
+		}
 
-		tr, err := g.Generate(ctx, orgID, ledgerID, input)
+		input, err := b.Build()
 		if err != nil {
 			return nil, err
 		}
 
-		routes = append(routes, tr)
-	}
-
-	// Transfer: Checking -> Checking (generic)
-	transferOps := []string{}
-	transferSrcID, transferSrcOk := byTitle["Source: Customer (CHECKING)"]
-
-	if transferSrcOk {
-		transferOps = append(transferOps, transferSrcID)
-	}
-
-	transferDstID, transferDstOk := byTitle["Destination: Customer (CHECKING)"]
-	if transferDstOk {
-		transferOps = append(transferOps, transferDstID)
-	}
-
-	if len(transferOps) >= 2 {
-		input := models.NewCreateTransactionRouteInput("Transfer Flow", "Internal transfer between checking accounts", transferOps).
-			WithMetadata(map[string]any{"pattern": "transfer"})
-
 		tr, err := g.Generate(ctx, orgID, ledgerID, input)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,82 @@
+// Package idgen generates the IDs and idempotency keys the SDK creates on the
+// client side.
+//
+// The default generator makes UUIDv7s: their leading bits are a millisecond
+// timestamp, so keys generated one after another sort together and keep the
+// indexes they are stored in (idempotency keys on the Midaz side, outbox
+// entries on the client side) compact under high write volumes. Within a
+// millisecond, the IDs of a process still increase.
+//
+// Applications with their own ID scheme plug it in with SetDefault, or pass a
+// Generator to the components that accept one:
+//
+//	idgen.SetDefault(idgen.GeneratorFunc(func() string {
+//	    return "acme-" + ulid.Make().String()
+//	}))
+package idgen
+
+import (
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// Generator makes unique IDs. Implementations must be safe for concurrent use.
+type Generator interface {
+	NewID() string
+}
+
+// GeneratorFunc adapts a function to a Generator.
+type GeneratorFunc func() string
+
+// NewID calls f.
+func (f GeneratorFunc) NewID() string {
+	return f()
+}
+
+var (
+	// V7 generates time-ordered UUIDv7s; it is the default generator
+	V7 Generator = GeneratorFunc(NewV7)
+
+	// V4 generates random UUIDv4s
+	V4 Generator = GeneratorFunc(NewV4)
+)
+
+// NewV7 returns a new time-ordered UUIDv7 string.
+func NewV7() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// NewV4 returns a new random UUIDv4 string.
+func NewV4() string {
+	return uuid.NewString()
+}
+
+// holder wraps the default generator for atomic.Pointer
+type holder struct{ Generator }
+
+var defaultGenerator atomic.Pointer[holder]
+
+func init() {
+	defaultGenerator.Store(&holder{V7})
+}
+
+// Default returns the generator used by the SDK when none is given.
+func Default() Generator {
+	return defaultGenerator.Load().Generator
+}
+
+// SetDefault replaces the generator used by the SDK when none is given; nil
+// restores V7. It is meant to be called once at startup.
+func SetDefault(g Generator) {
+	if g == nil {
+		g = V7
+	}
+
+	defaultGenerator.Store(&holder{g})
+}
+
+// New returns an ID from the default generator.
+func New() string {
+	return Default().NewID()
+}
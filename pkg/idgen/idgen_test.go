@@ -0,0 +1,51 @@
+package idgen
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewV7(t *testing.T) {
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = NewV7()
+	}
+
+	parsed, err := uuid.Parse(ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), parsed.Version())
+
+	assert.True(t, slices.IsSorted(ids), "UUIDv7s of a process are time-ordered")
+	assert.Len(t, slices.Compact(slices.Clone(ids)), len(ids))
+}
+
+func TestNewV4(t *testing.T) {
+	parsed, err := uuid.Parse(NewV4())
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(4), parsed.Version())
+}
+
+func TestSetDefault(t *testing.T) {
+	t.Cleanup(func() { SetDefault(nil) })
+
+	parsed, err := uuid.Parse(New())
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), parsed.Version(), "the default generator makes UUIDv7s")
+
+	SetDefault(GeneratorFunc(func() string { return "fixed" }))
+	assert.Equal(t, "fixed", New())
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() { _ = New() })
+	}
+	wg.Wait()
+
+	SetDefault(nil)
+	assert.NotEqual(t, "fixed", New())
+}
@@ -2,13 +2,18 @@ package integrity
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/artifact"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/events"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/kvstore"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
 	"github.com/shopspring/decimal"
 )
@@ -32,6 +37,10 @@ type BalanceTotals struct {
 type Report struct {
 	LedgerID      string
 	TotalsByAsset map[string]*BalanceTotals
+	// Scan describes the scan that produced the report
+	Scan ScanProgress
+	// Rules holds the results of the custom rules (see WithRules)
+	Rules []RuleResult
 }
 
 // Checker provides data integrity checks and balance verification.
@@ -41,6 +50,19 @@ type Checker struct {
 	obs observability.Provider
 	// Optional delay between account lookups to avoid overwhelming services on large ledgers
 	sleepBetweenAccountLookups time.Duration
+	// Number of accounts looked up in parallel (1 when unset)
+	concurrency int
+	// Optional state file making scans incremental
+	stateFile string
+	// Optional store and key of the state, instead of the state file
+	stateStore kvstore.Interface
+	stateKey   string
+	// Optional callback receiving the progress after each page
+	onProgress func(ScanProgress)
+	// Custom rules evaluated during scans
+	rules []Rule
+	// Optional sink notified when a report finds drift
+	events events.Sink
 }
 
 // NewChecker creates a new Checker.
@@ -52,6 +74,13 @@ func (c *Checker) WithObservability(obs observability.Provider) *Checker {
 	return c
 }
 
+// WithEvents sets a sink receiving an events.IntegrityDrift event for each
+// report with overdrawn internal accounts or failed rules (see Report.Drift).
+func (c *Checker) WithEvents(sink events.Sink) *Checker {
+	c.events = sink
+	return c
+}
+
 // WithAccountLookupDelay sets an optional delay inserted before each account lookup.
 // Useful to rate-limit calls when processing very large ledgers.
 func (c *Checker) WithAccountLookupDelay(d time.Duration) *Checker {
@@ -70,25 +99,33 @@ func (c *Checker) WithAccountLookupDelay(d time.Duration) *Checker {
 }
 
 // GenerateLedgerReport aggregates balances and performs lightweight double-entry checks.
+// Aliases come from the balances, or from their accounts, which are looked up
+// in parallel (see WithConcurrency) and only once per account; with a state
+// file, only the accounts unknown to the previous scan are (see WithStateFile).
 func (c *Checker) GenerateLedgerReport(ctx context.Context, orgID, ledgerID string) (*Report, error) {
 	if c.e == nil || c.e.Balances == nil || c.e.Accounts == nil {
 		return nil, errors.New("entities not initialized for integrity checks")
 	}
 
+	previous, err := c.loadState(ctx, orgID, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+
 	c.logDebug("Starting ledger integrity report generation for ledger %q", ledgerID)
 
-	totals := map[string]*BalanceTotals{}
-	accountAliasCache := map[string]string{}
+	scan := newLedgerScan(orgID, ledgerID, previous)
+	scan.rules = beginRules(c.rules)
 
 	var report *Report
 
-	err := observability.WithSpan(ctx, c.obs, "GenerateLedgerReport", func(ctx context.Context) error {
-		if err := c.processBalances(ctx, orgID, ledgerID, totals, accountAliasCache); err != nil {
+	err = observability.WithSpan(ctx, c.obs, "GenerateLedgerReport", func(ctx context.Context) error {
+		if err := c.processBalances(ctx, orgID, ledgerID, scan); err != nil {
 			c.logError("Failed to process balances for ledger %q: %v", ledgerID, err)
 			return err
 		}
 
-		report = &Report{LedgerID: ledgerID, TotalsByAsset: totals}
+		report = &Report{LedgerID: ledgerID, TotalsByAsset: scan.totals, Scan: scan.progress, Rules: ruleResults(c.rules, scan.rules)}
 
 		return nil
 	})
@@ -96,13 +133,79 @@ func (c *Checker) GenerateLedgerReport(ctx context.Context, orgID, ledgerID stri
 		return nil, err
 	}
 
-	c.logInfo("Completed ledger integrity report for ledger %q: %d assets processed", ledgerID, len(totals))
+	if err := c.saveState(ctx, scan.next); err != nil {
+		return nil, err
+	}
+
+	for _, result := range report.Rules {
+		if !result.Passed {
+			c.logWarn("Integrity rule %q failed for ledger %q: %d violations", result.Name, ledgerID, result.ViolationCount)
+		}
+	}
+
+	c.logInfo("Completed ledger integrity report for ledger %q: %d assets processed, %d of %d balances changed",
+		ledgerID, len(scan.totals), scan.progress.Changed, scan.progress.Balances)
+
+	c.emitDrift(ctx, orgID, report)
 
 	return report, nil
 }
 
+// Drift lists the problems found by the report: the overdrawn accounts, except
+// the external ones which are negative by design, and the failed rules.
+func (r *Report) Drift() []string {
+	var drift []string
+
+	assets := make([]string, 0, len(r.TotalsByAsset))
+	for asset := range r.TotalsByAsset {
+		assets = append(assets, asset)
+	}
+
+	sort.Strings(assets)
+
+	for _, asset := range assets {
+		for _, account := range r.TotalsByAsset[asset].Overdrawn {
+			if !strings.HasPrefix(account, "@external/") {
+				drift = append(drift, fmt.Sprintf("account %s is overdrawn in %s", account, asset))
+			}
+		}
+	}
+
+	for _, result := range r.Rules {
+		if !result.Passed {
+			drift = append(drift, fmt.Sprintf("rule %q failed with %d violations", result.Name, result.ViolationCount))
+		}
+	}
+
+	return drift
+}
+
+// emitDrift sends an events.IntegrityDrift event when the report found drift.
+func (c *Checker) emitDrift(ctx context.Context, orgID string, report *Report) {
+	if c.events == nil {
+		return
+	}
+
+	drift := report.Drift()
+	if len(drift) == 0 {
+		return
+	}
+
+	events.Emit(ctx, c.events, events.Event{
+		Type:     events.IntegrityDrift,
+		Severity: events.SeverityError,
+		Source:   report.LedgerID,
+		Message:  fmt.Sprintf("integrity report for ledger %s found %d problems: %s", report.LedgerID, len(drift), strings.Join(drift, "; ")),
+		Attributes: map[string]any{
+			"organization_id": orgID,
+			"ledger_id":       report.LedgerID,
+			"problems":        len(drift),
+		},
+	})
+}
+
 // processBalances processes all balances with pagination
-func (c *Checker) processBalances(ctx context.Context, orgID, ledgerID string, totals map[string]*BalanceTotals, accountAliasCache map[string]string) error {
+func (c *Checker) processBalances(ctx context.Context, orgID, ledgerID string, scan *ledgerScan) error {
 	opts := models.NewListOptions().WithLimit(100)
 
 	for {
@@ -111,12 +214,17 @@ func (c *Checker) processBalances(ctx context.Context, orgID, ledgerID string, t
 			return err
 		}
 
+		if err := c.resolveAliases(ctx, orgID, ledgerID, resp.Items, scan); err != nil {
+			return err
+		}
+
 		for _, b := range resp.Items {
-			if err := c.processBalance(ctx, orgID, ledgerID, b, totals, accountAliasCache); err != nil {
-				return err
-			}
+			c.processBalance(scan, b)
 		}
 
+		scan.progress.Pages++
+		c.reportProgress(scan)
+
 		if resp.Pagination.NextCursor == "" {
 			break
 		}
@@ -127,20 +235,27 @@ func (c *Checker) processBalances(ctx context.Context, orgID, ledgerID string, t
 	return nil
 }
 
-// processBalance processes a single balance entry
-func (c *Checker) processBalance(ctx context.Context, orgID, ledgerID string, b models.Balance, totals map[string]*BalanceTotals, accountAliasCache map[string]string) error {
-	t := c.getOrCreateBalanceTotals(totals, b.AssetCode)
+// processBalance processes a single balance entry, whose alias is resolved
+func (c *Checker) processBalance(scan *ledgerScan, b models.Balance) {
+	t := c.getOrCreateBalanceTotals(scan.totals, b.AssetCode)
 	c.updateBalanceTotals(t, b)
 
-	alias, err := c.getAccountAlias(ctx, orgID, ledgerID, b.AccountID, accountAliasCache)
-	if err != nil {
-		return err
-	}
+	alias := scan.aliases[b.AccountID]
 
 	c.updateInternalNetTotal(t, b, alias)
 	c.checkForOverdraft(t, b, alias)
 
-	return nil
+	for _, rule := range scan.rules {
+		rule.Observe(RuleEntry{Balance: b, Alias: alias})
+	}
+
+	scan.progress.Balances++
+	if scan.changed(b) {
+		scan.progress.Changed++
+	}
+
+	scan.next.Aliases[b.AccountID] = alias
+	scan.next.Versions[b.ID] = b.Version
 }
 
 // getOrCreateBalanceTotals gets or creates BalanceTotals for an asset
@@ -161,22 +276,6 @@ func (*Checker) updateBalanceTotals(t *BalanceTotals, b models.Balance) {
 	t.TotalOnHold = t.TotalOnHold.Add(b.OnHold)
 }
 
-// getAccountAlias gets the account alias with caching and optional throttling
-func (c *Checker) getAccountAlias(ctx context.Context, orgID, ledgerID, accountID string, accountAliasCache map[string]string) (string, error) {
-	if alias, ok := accountAliasCache[accountID]; ok {
-		return alias, nil
-	}
-
-	alias, err := c.fetchAccountAlias(ctx, orgID, ledgerID, accountID)
-	if err != nil {
-		return "", err
-	}
-
-	accountAliasCache[accountID] = alias
-
-	return alias, nil
-}
-
 // fetchAccountAlias fetches the account alias from the API with throttling.
 func (c *Checker) fetchAccountAlias(ctx context.Context, orgID, ledgerID, accountID string) (string, error) {
 	if err := c.waitForThrottling(ctx); err != nil {
@@ -288,3 +387,29 @@ func (r *Report) ToSummaryMap() map[string]map[string]any {
 
 	return out
 }
+
+// SaveTo saves the report as JSON to a file://, s3:// or gs:// destination (see
+// the artifact package). A destination ending with "/" gets a timestamped
+// "integrity-<ledger>-*.json" name unless opts sets one.
+func (r *Report) SaveTo(ctx context.Context, uri string, opts *artifact.Options) (*artifact.Result, error) {
+	o := artifact.Options{}
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Name == "" {
+		o.Name = fmt.Sprintf("integrity-%s-%s.json", r.LedgerID, time.Now().UTC().Format("20060102T150405Z"))
+	}
+
+	data, err := json.MarshalIndent(map[string]any{
+		"ledgerId": r.LedgerID,
+		"assets":   r.ToSummaryMap(),
+		"scan":     r.Scan,
+		"rules":    r.Rules,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode integrity report: %w", err)
+	}
+
+	return artifact.Save(ctx, uri, data, &o)
+}
@@ -3,11 +3,14 @@ package integrity
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/events"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -177,6 +180,14 @@ func (s *testBalancesService) ListBalancesByExternalCode(ctx context.Context, or
 	return nil, errors.New("mock: ListBalancesByExternalCode not implemented")
 }
 
+func (*testBalancesService) GetMany(_ context.Context, _, _ string, _ []string, _ *entities.GetManyOptions) (map[string]*entities.AccountBalances, error) {
+	return nil, errors.New("mock: GetMany not implemented")
+}
+
+func (*testBalancesService) AsOf(_ context.Context, _, _, _ string, _ time.Time) ([]models.BalanceHistory, error) {
+	return nil, errors.New("mock: AsOf not implemented")
+}
+
 // testAccountsService implements entities.AccountsService for testing
 type testAccountsService struct {
 	listAccountsFn              func(ctx context.Context, orgID, ledgerID string, _ *models.ListOptions) (*models.ListResponse[models.Account], error)
@@ -216,6 +227,10 @@ func (s *testAccountsService) GetAccountByAlias(ctx context.Context, orgID, ledg
 	return nil, errors.New("mock: GetAccountByAlias not implemented")
 }
 
+func (*testAccountsService) FindByAlias(_ context.Context, _, _, _ string) (*models.Account, error) {
+	return nil, errors.New("mock: FindByAlias not implemented")
+}
+
 func (s *testAccountsService) CreateAccount(ctx context.Context, orgID, ledgerID string, input *models.CreateAccountInput) (*models.Account, error) {
 	if s.createAccountFn != nil {
 		return s.createAccountFn(ctx, orgID, ledgerID, input)
@@ -706,6 +721,51 @@ func TestGenerateLedgerReport_OverdrawnAccount(t *testing.T) {
 	assert.NotEmpty(t, obs.logger.warnCalls)
 }
 
+func TestGenerateLedgerReport_DriftEvent(t *testing.T) {
+	balances := []models.Balance{
+		createTestBalance("account-1", "USD", -500, 0),
+		createTestBalance("account-2", "USD", -1000, 0),
+		createTestBalance("account-3", "USD", 1500, 0),
+	}
+
+	mockBalances := &testBalancesService{
+		listBalancesFn: func(_ context.Context, _, _ string, _ *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+			return &models.ListResponse[models.Balance]{Items: balances}, nil
+		},
+	}
+
+	aliases := map[string]string{"account-1": "@user/overdrawn", "account-2": "@external/USD", "account-3": "@user/healthy"}
+	mockAccounts := &testAccountsService{
+		getAccountFn: func(_ context.Context, _, _, id string) (*models.Account, error) {
+			return createTestAccount(id, ptr(aliases[id])), nil
+		},
+	}
+
+	var received []events.Event
+
+	checker := NewChecker(&entities.Entity{Accounts: mockAccounts, Balances: mockBalances}).
+		WithEvents(events.SinkFunc(func(_ context.Context, e events.Event) error {
+			received = append(received, e)
+			return nil
+		}))
+
+	report, err := checker.GenerateLedgerReport(context.Background(), "org-1", "ledger-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"account @user/overdrawn is overdrawn in USD"}, report.Drift())
+	require.Len(t, received, 1)
+	assert.Equal(t, events.IntegrityDrift, received[0].Type)
+	assert.Equal(t, "ledger-1", received[0].Source)
+	assert.Equal(t, 1, received[0].Attributes["problems"])
+
+	// Without drift nothing is sent
+	balances = balances[1:]
+
+	_, err = checker.GenerateLedgerReport(context.Background(), "org-1", "ledger-1")
+	require.NoError(t, err)
+	assert.Len(t, received, 1)
+}
+
 func TestGenerateLedgerReport_AccountWithNoAlias(t *testing.T) {
 	balances := []models.Balance{
 		createTestBalance("account-1", "USD", -500, 0), // Overdrawn, no alias
@@ -1105,6 +1165,30 @@ func TestReportToSummaryMap_NilOverdrawn(t *testing.T) {
 	assert.Nil(t, usd["overdrawnAccounts"])
 }
 
+func TestReportSaveTo(t *testing.T) {
+	report := &Report{
+		LedgerID: "ledger-1",
+		TotalsByAsset: map[string]*BalanceTotals{
+			"USD": {Asset: "USD", Accounts: 2, TotalAvailable: decimal.NewFromInt(10), InternalNetTotal: decimal.Zero},
+		},
+	}
+
+	dir := t.TempDir()
+
+	result, err := report.SaveTo(context.Background(), "file://"+filepath.ToSlash(dir)+"/", nil)
+	require.NoError(t, err)
+	assert.Len(t, result.SHA256, 64)
+
+	matches, err := filepath.Glob(filepath.Join(dir, "integrity-ledger-1-*.json"))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+
+	data, err := os.ReadFile(matches[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"ledgerId": "ledger-1"`)
+	assert.Contains(t, string(data), `"totalAvailable": "10"`)
+}
+
 // -----------------------------------------------------------------------------
 // BalanceTotals Type Tests
 // -----------------------------------------------------------------------------
@@ -0,0 +1,261 @@
+package integrity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultDuplicateWindow is the default time within which identical
+// transactions are reported as probable duplicates.
+const DefaultDuplicateWindow = 10 * time.Second
+
+// DuplicateOptions configures FindDuplicateTransactions.
+type DuplicateOptions struct {
+	// Start and End bound the creation time of the transactions scanned
+	// (End exclusive). Both are required.
+	Start time.Time
+	End   time.Time
+
+	// Within is the time within which identical transactions are probable
+	// duplicates. Defaults to DefaultDuplicateWindow.
+	Within time.Duration
+
+	// IgnoreMetadataKeys are left out of the metadata fingerprint, e.g. keys
+	// holding request IDs or timestamps that differ between retries
+	IgnoreMetadataKeys []string
+}
+
+// DuplicateGroup is a set of identical transactions created close together.
+type DuplicateGroup struct {
+	// Fingerprint identifies the source, destination, asset, amount and
+	// metadata shared by the transactions
+	Fingerprint string `json:"fingerprint"`
+
+	Source      []string `json:"source"`
+	Destination []string `json:"destination"`
+	AssetCode   string   `json:"assetCode"`
+	Amount      string   `json:"amount"`
+
+	// TransactionIDs lists the transactions, oldest first
+	TransactionIDs []string `json:"transactionIds"`
+
+	First time.Time `json:"first"`
+	Last  time.Time `json:"last"`
+}
+
+// DuplicateReport lists the probable duplicate transactions of a ledger.
+type DuplicateReport struct {
+	LedgerID string    `json:"ledgerId"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+
+	// Scanned counts the transactions created in the time range
+	Scanned int `json:"scanned"`
+
+	Groups []DuplicateGroup `json:"groups,omitempty"`
+}
+
+// FindDuplicateTransactions scans the transactions of a ledger created
+// between opts.Start and opts.End for probable duplicates: transactions with
+// the same source and destination accounts, asset, amount and metadata,
+// created within opts.Within of the previous one.
+//
+// Example of an idempotency incident investigation:
+//
+//	report, err := integrity.NewChecker(client.Entity).FindDuplicateTransactions(ctx, orgID, ledgerID, integrity.DuplicateOptions{
+//	    Start:              incidentStart,
+//	    End:                incidentEnd,
+//	    Within:             30 * time.Second,
+//	    IgnoreMetadataKeys: []string{"requestId"},
+//	})
+func (c *Checker) FindDuplicateTransactions(ctx context.Context, orgID, ledgerID string, opts DuplicateOptions) (*DuplicateReport, error) {
+	if c.e == nil || c.e.Transactions == nil {
+		return nil, errors.New("transactions service not initialized")
+	}
+
+	if opts.Start.IsZero() || opts.End.IsZero() || !opts.End.After(opts.Start) {
+		return nil, errors.New("a time range with start before end is required")
+	}
+
+	report := &DuplicateReport{LedgerID: ledgerID, Start: opts.Start, End: opts.End}
+
+	var txs []models.Transaction
+
+	err := observability.WithSpan(ctx, c.obs, "FindDuplicateTransactions", func(ctx context.Context) error {
+		return c.listTransactions(ctx, orgID, ledgerID, opts.Start, opts.End, func(tx models.Transaction) {
+			txs = append(txs, tx)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Scanned = len(txs)
+	report.Groups = FindDuplicates(txs, opts)
+
+	c.logInfo("Scanned %d transactions of ledger %q for duplicates: %d groups found", report.Scanned, ledgerID, len(report.Groups))
+
+	return report, nil
+}
+
+// listTransactions calls fn for the transactions created in [start, end)
+func (c *Checker) listTransactions(ctx context.Context, orgID, ledgerID string, start, end time.Time, fn func(models.Transaction)) error {
+	// the API filters by date, the exact bounds are applied here
+	startDate, endDate := start.UTC().Format(time.DateOnly), end.UTC().Format(time.DateOnly)
+	opts := models.NewListOptions().WithLimit(models.MaxLimit).WithDateRange(startDate, endDate).WithOrderDirection(models.SortAscending)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := c.e.Transactions.ListTransactions(ctx, orgID, ledgerID, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list transactions: %w", err)
+		}
+
+		for _, tx := range page.Items {
+			if !tx.CreatedAt.Before(start) && tx.CreatedAt.Before(end) {
+				fn(tx)
+			}
+		}
+
+		next := page.Pagination.NextPageOptions()
+		if next == nil || len(page.Items) == 0 {
+			return nil
+		}
+
+		// next page options only carry the position
+		opts = next.WithDateRange(startDate, endDate).WithOrderDirection(models.SortAscending)
+	}
+}
+
+// FindDuplicates groups identical transactions created within opts.Within of
+// the previous one; opts.Start and opts.End are ignored. Deleted transactions
+// are skipped. It works on transactions from any source, such as an export.
+func FindDuplicates(txs []models.Transaction, opts DuplicateOptions) []DuplicateGroup {
+	within := opts.Within
+	if within <= 0 {
+		within = DefaultDuplicateWindow
+	}
+
+	sorted := make([]models.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if tx.DeletedAt == nil {
+			sorted = append(sorted, tx)
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+
+	// open holds the last group of each fingerprint
+	open := map[string]*DuplicateGroup{}
+
+	var groups []*DuplicateGroup
+
+	for _, tx := range sorted {
+		source, destination := transactionAccounts(tx)
+		fingerprint := duplicateFingerprint(tx, source, destination, opts.IgnoreMetadataKeys)
+
+		if g, ok := open[fingerprint]; ok && tx.CreatedAt.Sub(g.Last) <= within {
+			g.TransactionIDs = append(g.TransactionIDs, tx.ID)
+			g.Last = tx.CreatedAt
+
+			continue
+		}
+
+		g := &DuplicateGroup{
+			Fingerprint:    fingerprint,
+			Source:         source,
+			Destination:    destination,
+			AssetCode:      tx.AssetCode,
+			Amount:         tx.Amount,
+			TransactionIDs: []string{tx.ID},
+			First:          tx.CreatedAt,
+			Last:           tx.CreatedAt,
+		}
+		open[fingerprint] = g
+		groups = append(groups, g)
+	}
+
+	var duplicates []DuplicateGroup
+
+	for _, g := range groups {
+		if len(g.TransactionIDs) > 1 {
+			duplicates = append(duplicates, *g)
+		}
+	}
+
+	return duplicates
+}
+
+// transactionAccounts returns the sorted source and destination accounts of
+// tx, from its operations when the transaction doesn't list them
+func transactionAccounts(tx models.Transaction) (source, destination []string) {
+	source = append(source, tx.Source...)
+	destination = append(destination, tx.Destination...)
+
+	if len(source) == 0 && len(destination) == 0 {
+		for _, op := range tx.Operations {
+			account := op.AccountAlias
+			if account == "" {
+				account = op.AccountID
+			}
+
+			switch strings.ToUpper(op.Type) {
+			case "DEBIT":
+				source = append(source, account)
+			case "CREDIT":
+				destination = append(destination, account)
+			}
+		}
+	}
+
+	sort.Strings(source)
+	sort.Strings(destination)
+
+	return source, destination
+}
+
+// duplicateFingerprint hashes the accounts, asset, amount and metadata of tx.
+// Amounts are compared as numbers, so "100" and "100.00" match.
+func duplicateFingerprint(tx models.Transaction, source, destination, ignoreKeys []string) string {
+	amount := tx.Amount
+	if d, err := decimal.NewFromString(tx.Amount); err == nil {
+		amount = d.String()
+	}
+
+	metadata := make(map[string]any, len(tx.Metadata))
+	for k, v := range tx.Metadata {
+		metadata[k] = v
+	}
+
+	for _, k := range ignoreKeys {
+		delete(metadata, k)
+	}
+
+	// json.Marshal sorts map keys, so equal metadata encodes the same
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		encoded = []byte(fmt.Sprint(metadata))
+	}
+
+	h := sha256.New()
+	for _, part := range []string{strings.Join(source, ","), strings.Join(destination, ","), tx.AssetCode, amount, string(encoded)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
@@ -0,0 +1,120 @@
+package integrity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type duplicateTransactionsService struct {
+	entities.TransactionsService
+
+	transactions []models.Transaction
+	pages        []*models.ListOptions
+}
+
+func (s *duplicateTransactionsService) ListTransactions(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Transaction], error) {
+	s.pages = append(s.pages, opts)
+
+	start := min(opts.Offset, len(s.transactions))
+	end := min(start+2, len(s.transactions))
+
+	return &models.ListResponse[models.Transaction]{
+		Items:      s.transactions[start:end],
+		Pagination: models.Pagination{Limit: 2, Offset: start, Total: len(s.transactions)},
+	}, nil
+}
+
+func transfer(id, from, to, amount string, at time.Time, metadata map[string]any) models.Transaction {
+	return models.Transaction{
+		ID:          id,
+		Source:      []string{from},
+		Destination: []string{to},
+		AssetCode:   "USD",
+		Amount:      amount,
+		Metadata:    metadata,
+		CreatedAt:   at,
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	deleted := base
+
+	txs := []models.Transaction{
+		transfer("tx-1", "@alice", "@bob", "100", base, map[string]any{"order": "42", "requestId": "a"}),
+		transfer("tx-2", "@alice", "@bob", "100.00", base.Add(4*time.Second), map[string]any{"order": "42", "requestId": "b"}),
+		transfer("tx-3", "@alice", "@bob", "100", base.Add(12*time.Second), map[string]any{"order": "42", "requestId": "c"}),
+		transfer("tx-4", "@alice", "@bob", "100", base.Add(5*time.Minute), map[string]any{"order": "42"}),
+		transfer("tx-5", "@alice", "@bob", "100", base.Add(time.Second), map[string]any{"order": "43"}),
+		transfer("tx-6", "@alice", "@carol", "100", base.Add(time.Second), map[string]any{"order": "42"}),
+		transfer("tx-7", "@alice", "@bob", "100", base.Add(2*time.Second), map[string]any{"order": "42"}),
+	}
+	txs[6].DeletedAt = &deleted
+
+	groups := FindDuplicates(txs, DuplicateOptions{IgnoreMetadataKeys: []string{"requestId"}})
+	require.Len(t, groups, 1)
+
+	g := groups[0]
+	assert.Equal(t, []string{"tx-1", "tx-2", "tx-3"}, g.TransactionIDs, "chained within 10s of the previous one")
+	assert.Equal(t, []string{"@alice"}, g.Source)
+	assert.Equal(t, []string{"@bob"}, g.Destination)
+	assert.Equal(t, base, g.First)
+	assert.Equal(t, base.Add(12*time.Second), g.Last)
+
+	assert.Empty(t, FindDuplicates(txs, DuplicateOptions{}), "request IDs differ when not ignored")
+	assert.Len(t, FindDuplicates(txs, DuplicateOptions{Within: 5 * time.Second, IgnoreMetadataKeys: []string{"requestId"}})[0].TransactionIDs, 2)
+}
+
+func TestFindDuplicates_AccountsFromOperations(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ops := []models.Operation{{Type: "CREDIT", AccountAlias: "@bob"}, {Type: "DEBIT", AccountAlias: "@alice"}}
+
+	txs := []models.Transaction{
+		{ID: "tx-1", AssetCode: "USD", Amount: "5", Operations: ops, CreatedAt: base},
+		{ID: "tx-2", AssetCode: "USD", Amount: "5", Operations: ops, CreatedAt: base.Add(time.Second)},
+	}
+
+	groups := FindDuplicates(txs, DuplicateOptions{})
+	require.Len(t, groups, 1)
+	assert.Equal(t, []string{"@alice"}, groups[0].Source)
+	assert.Equal(t, []string{"@bob"}, groups[0].Destination)
+}
+
+func TestFindDuplicateTransactions(t *testing.T) {
+	base := time.Date(2025, 6, 1, 23, 59, 0, 0, time.UTC)
+	svc := &duplicateTransactionsService{transactions: []models.Transaction{
+		transfer("tx-0", "@alice", "@bob", "100", base.Add(-time.Hour), nil),
+		transfer("tx-1", "@alice", "@bob", "100", base, nil),
+		transfer("tx-2", "@alice", "@bob", "100", base.Add(5*time.Second), nil),
+		transfer("tx-3", "@dave", "@bob", "1", base.Add(10*time.Second), nil),
+		transfer("tx-4", "@dave", "@bob", "1", base.Add(2*time.Minute), nil),
+	}}
+
+	checker := NewChecker(&entities.Entity{Transactions: svc})
+
+	report, err := checker.FindDuplicateTransactions(context.Background(), "org-1", "ledger-1", DuplicateOptions{
+		Start: base,
+		End:   base.Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Scanned, "transactions outside the range are skipped")
+	require.Len(t, report.Groups, 1)
+	assert.Equal(t, []string{"tx-1", "tx-2"}, report.Groups[0].TransactionIDs)
+
+	require.Len(t, svc.pages, 3)
+	assert.Equal(t, "2025-06-01", svc.pages[0].StartDate)
+	assert.Equal(t, "2025-06-02", svc.pages[2].EndDate, "later pages keep the date range")
+
+	_, err = checker.FindDuplicateTransactions(context.Background(), "org-1", "ledger-1", DuplicateOptions{Start: base, End: base})
+	require.Error(t, err)
+
+	_, err = NewChecker(&entities.Entity{}).FindDuplicateTransactions(context.Background(), "org-1", "ledger-1", DuplicateOptions{Start: base, End: base.Add(time.Minute)})
+	require.Error(t, err)
+}
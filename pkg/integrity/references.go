@@ -0,0 +1,314 @@
+package integrity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+)
+
+// DefaultMaxDangling is the number of dangling references a ReferenceReport
+// keeps when ReferenceOptions.MaxDangling is zero.
+const DefaultMaxDangling = 1000
+
+// Kinds of entity holding a reference, see DanglingReference.
+const (
+	ReferenceFromAccount     = "account"
+	ReferenceFromTransaction = "transaction"
+	ReferenceFromOperation   = "operation"
+)
+
+// ReferenceOptions configures CheckReferences.
+type ReferenceOptions struct {
+	// Start and End bound the creation time of the transactions checked (End
+	// exclusive), such as the time of a bulk import. Transactions are only
+	// checked when both are set.
+	Start time.Time
+	End   time.Time
+
+	// MaxDangling bounds the dangling references kept in
+	// ReferenceReport.Dangling, so that a badly broken ledger can't exhaust
+	// memory; the others are only counted. Zero keeps DefaultMaxDangling, a
+	// negative value keeps them all.
+	MaxDangling int
+}
+
+// DanglingReference is a reference to an entity that doesn't exist in the
+// ledger.
+type DanglingReference struct {
+	// From is the kind of entity holding the reference, such as
+	// ReferenceFromAccount, and ID its ID
+	From string `json:"from"`
+	ID   string `json:"id"`
+
+	// Field is the field holding the reference, such as "portfolioId"
+	Field string `json:"field"`
+
+	// Target is the missing entity referenced
+	Target string `json:"target"`
+}
+
+// String describes the reference, e.g. "account acc-1 portfolioId: pf-9 not found".
+func (d DanglingReference) String() string {
+	return fmt.Sprintf("%s %s %s: %s not found", d.From, d.ID, d.Field, d.Target)
+}
+
+// ReferenceReport lists the dangling references of a ledger.
+type ReferenceReport struct {
+	LedgerID string `json:"ledgerId"`
+
+	// Accounts and Transactions count the entities checked
+	Accounts     int `json:"accounts"`
+	Transactions int `json:"transactions"`
+
+	// AccountTypesChecked is false when the ledger defines no account types,
+	// so the types of its accounts weren't checked
+	AccountTypesChecked bool `json:"accountTypesChecked"`
+
+	// Dangling describes the dangling references, at most
+	// ReferenceOptions.MaxDangling of them (DefaultMaxDangling by default),
+	// which bounds its memory to a few hundred bytes per reference kept;
+	// DanglingCount counts them all
+	Dangling      []DanglingReference `json:"dangling,omitempty"`
+	DanglingCount int                 `json:"danglingCount"`
+
+	Elapsed time.Duration `json:"elapsed"`
+
+	// maxDangling bounds Dangling, negative for no bound
+	maxDangling int
+}
+
+// OK reports whether no dangling reference was found.
+func (r *ReferenceReport) OK() bool {
+	return r.DanglingCount == 0
+}
+
+// String summarizes the report, e.g. "2 dangling references in 120 accounts
+// and 3000 transactions".
+func (r *ReferenceReport) String() string {
+	return fmt.Sprintf("%d dangling references in %d accounts and %d transactions", r.DanglingCount, r.Accounts, r.Transactions)
+}
+
+// add records a dangling reference, keeping the first maxDangling.
+func (r *ReferenceReport) add(ref DanglingReference) {
+	r.DanglingCount++
+
+	if r.maxDangling < 0 || len(r.Dangling) < r.maxDangling {
+		r.Dangling = append(r.Dangling, ref)
+	}
+}
+
+// CheckReferences verifies the links between the entities of a ledger, such
+// as after a bulk import: the portfolio, segment, parent account and account
+// type of each account, and the transaction route of each transaction
+// created between opts.Start and opts.End and the operation routes of its
+// operations, when the listing carries them, must exist in the ledger.
+// Account types are only checked when the ledger defines some, since Midaz
+// doesn't require them. The report keeps opts.MaxDangling dangling
+// references and counts the others.
+//
+// Example:
+//
+//	report, err := integrity.NewChecker(client.Entity).CheckReferences(ctx, orgID, ledgerID, integrity.ReferenceOptions{
+//	    Start: importStart,
+//	    End:   time.Now(),
+//	})
+//	if err == nil && !report.OK() {
+//	    for _, ref := range report.Dangling {
+//	        log.Print(ref)
+//	    }
+//	}
+func (c *Checker) CheckReferences(ctx context.Context, orgID, ledgerID string, opts ReferenceOptions) (*ReferenceReport, error) {
+	if c.e == nil || c.e.Accounts == nil || c.e.Portfolios == nil || c.e.Segments == nil || c.e.AccountTypes == nil {
+		return nil, errors.New("accounts, portfolios, segments and account types services not initialized")
+	}
+
+	checkTransactions := !opts.Start.IsZero() && !opts.End.IsZero()
+	if checkTransactions && !opts.End.After(opts.Start) {
+		return nil, errors.New("a time range with start before end is required")
+	}
+
+	if checkTransactions && (c.e.Transactions == nil || c.e.TransactionRoutes == nil || c.e.OperationRoutes == nil) {
+		return nil, errors.New("transactions and routes services not initialized")
+	}
+
+	started := time.Now()
+	maxDangling := opts.MaxDangling
+	if maxDangling == 0 {
+		maxDangling = DefaultMaxDangling
+	}
+
+	report := &ReferenceReport{LedgerID: ledgerID, maxDangling: maxDangling}
+
+	err := observability.WithSpan(ctx, c.obs, "CheckReferences", func(ctx context.Context) error {
+		if err := c.checkAccountReferences(ctx, orgID, ledgerID, report); err != nil {
+			return err
+		}
+
+		if checkTransactions {
+			return c.checkTransactionReferences(ctx, orgID, ledgerID, opts, report)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(report.Dangling, func(i, j int) bool {
+		a, b := report.Dangling[i], report.Dangling[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+
+		return a.ID < b.ID
+	})
+
+	report.Elapsed = time.Since(started)
+
+	c.logInfo("Checked the references of %d accounts and %d transactions of ledger %q: %d dangling", report.Accounts, report.Transactions, ledgerID, report.DanglingCount)
+
+	return report, nil
+}
+
+// checkAccountReferences checks the references of the accounts of a ledger.
+func (c *Checker) checkAccountReferences(ctx context.Context, orgID, ledgerID string, report *ReferenceReport) error {
+	portfolios, err := listKeys(ctx, "portfolios", func(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Portfolio], error) {
+		return c.e.Portfolios.ListPortfolios(ctx, orgID, ledgerID, opts)
+	}, func(p models.Portfolio) string { return p.ID })
+	if err != nil {
+		return err
+	}
+
+	segments, err := listKeys(ctx, "segments", func(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Segment], error) {
+		return c.e.Segments.ListSegments(ctx, orgID, ledgerID, opts)
+	}, func(s models.Segment) string { return s.ID })
+	if err != nil {
+		return err
+	}
+
+	accountTypes, err := listKeys(ctx, "account types", func(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.AccountType], error) {
+		return c.e.AccountTypes.ListAccountTypes(ctx, orgID, ledgerID, opts)
+	}, func(t models.AccountType) string { return t.KeyValue })
+	if err != nil {
+		return err
+	}
+
+	report.AccountTypesChecked = len(accountTypes) > 0
+
+	// Only the IDs of the accounts and the parents to check are kept, not
+	// the accounts, as a parent may be listed after its children
+	accountIDs := make(map[string]bool)
+
+	var parents []DanglingReference
+
+	if err := listPages(ctx, "accounts", func(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+		return c.e.Accounts.ListAccounts(ctx, orgID, ledgerID, opts)
+	}, func(a models.Account) {
+		report.Accounts++
+		accountIDs[a.ID] = true
+
+		dangling := func(field, target string) {
+			report.add(DanglingReference{From: ReferenceFromAccount, ID: a.ID, Field: field, Target: target})
+		}
+
+		if a.PortfolioID != nil && *a.PortfolioID != "" && !portfolios[*a.PortfolioID] {
+			dangling("portfolioId", *a.PortfolioID)
+		}
+
+		if a.SegmentID != nil && *a.SegmentID != "" && !segments[*a.SegmentID] {
+			dangling("segmentId", *a.SegmentID)
+		}
+
+		if a.ParentAccountID != nil && *a.ParentAccountID != "" {
+			parents = append(parents, DanglingReference{From: ReferenceFromAccount, ID: a.ID, Field: "parentAccountId", Target: *a.ParentAccountID})
+		}
+
+		if report.AccountTypesChecked && a.Type != "" && !accountTypes[a.Type] {
+			dangling("type", a.Type)
+		}
+	}); err != nil {
+		return err
+	}
+
+	for _, ref := range parents {
+		if !accountIDs[ref.Target] {
+			report.add(ref)
+		}
+	}
+
+	return nil
+}
+
+// checkTransactionReferences checks the routes of the transactions of a
+// ledger created in the time range of opts, and of their operations.
+func (c *Checker) checkTransactionReferences(ctx context.Context, orgID, ledgerID string, opts ReferenceOptions, report *ReferenceReport) error {
+	transactionRoutes, err := listKeys(ctx, "transaction routes", func(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.TransactionRoute], error) {
+		return c.e.TransactionRoutes.ListTransactionRoutes(ctx, orgID, ledgerID, opts)
+	}, func(r models.TransactionRoute) string { return r.ID.String() })
+	if err != nil {
+		return err
+	}
+
+	operationRoutes, err := listKeys(ctx, "operation routes", func(ctx context.Context, opts *models.ListOptions) (*models.ListResponse[models.OperationRoute], error) {
+		return c.e.OperationRoutes.ListOperationRoutes(ctx, orgID, ledgerID, opts)
+	}, func(r models.OperationRoute) string { return r.ID.String() })
+	if err != nil {
+		return err
+	}
+
+	return c.listTransactions(ctx, orgID, ledgerID, opts.Start, opts.End, func(tx models.Transaction) {
+		report.Transactions++
+
+		if tx.Route != "" && !transactionRoutes[tx.Route] {
+			report.add(DanglingReference{From: ReferenceFromTransaction, ID: tx.ID, Field: "route", Target: tx.Route})
+		}
+
+		for _, op := range tx.Operations {
+			if op.Route != "" && !operationRoutes[op.Route] {
+				report.add(DanglingReference{From: ReferenceFromOperation, ID: op.ID, Field: "route", Target: op.Route})
+			}
+		}
+	})
+}
+
+// listKeys returns the keys of every entity of a list, read by key.
+func listKeys[T any](ctx context.Context, what string, list func(context.Context, *models.ListOptions) (*models.ListResponse[T], error), key func(T) string) (map[string]bool, error) {
+	keys := make(map[string]bool)
+
+	err := listPages(ctx, what, list, func(item T) { keys[key(item)] = true })
+
+	return keys, err
+}
+
+// listPages calls fn for every entity of a list.
+func listPages[T any](ctx context.Context, what string, list func(context.Context, *models.ListOptions) (*models.ListResponse[T], error), fn func(T)) error {
+	opts := models.NewListOptions().WithLimit(models.MaxLimit)
+
+	for opts != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := list(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", what, err)
+		}
+
+		for _, item := range page.Items {
+			fn(item)
+		}
+
+		if len(page.Items) == 0 {
+			return nil
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	return nil
+}
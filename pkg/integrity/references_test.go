@@ -0,0 +1,179 @@
+package integrity
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pageOf returns the page of items requested by opts.
+func pageOf[T any](items []T, opts *models.ListOptions) *models.ListResponse[T] {
+	start := min(opts.Offset, len(items))
+	end := min(start+opts.Limit, len(items))
+
+	return &models.ListResponse[T]{
+		Items:      items[start:end],
+		Pagination: models.Pagination{Limit: opts.Limit, Offset: opts.Offset, Total: len(items)},
+	}
+}
+
+type referenceAccounts struct {
+	entities.AccountsService
+	items []models.Account
+}
+
+func (s *referenceAccounts) ListAccounts(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+	return pageOf(s.items, opts), nil
+}
+
+type referencePortfolios struct {
+	entities.PortfoliosService
+	items []models.Portfolio
+}
+
+func (s *referencePortfolios) ListPortfolios(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Portfolio], error) {
+	return pageOf(s.items, opts), nil
+}
+
+type referenceSegments struct {
+	entities.SegmentsService
+	items []models.Segment
+}
+
+func (s *referenceSegments) ListSegments(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Segment], error) {
+	return pageOf(s.items, opts), nil
+}
+
+type referenceAccountTypes struct {
+	entities.AccountTypesService
+	items []models.AccountType
+}
+
+func (s *referenceAccountTypes) ListAccountTypes(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.AccountType], error) {
+	return pageOf(s.items, opts), nil
+}
+
+type referenceTransactionRoutes struct {
+	entities.TransactionRoutesService
+	items []models.TransactionRoute
+}
+
+func (s *referenceTransactionRoutes) ListTransactionRoutes(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.TransactionRoute], error) {
+	return pageOf(s.items, opts), nil
+}
+
+type referenceOperationRoutes struct {
+	entities.OperationRoutesService
+	items []models.OperationRoute
+}
+
+func (s *referenceOperationRoutes) ListOperationRoutes(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.OperationRoute], error) {
+	return pageOf(s.items, opts), nil
+}
+
+func TestCheckReferences(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	transactionRoute, operationRoute := uuid.New(), uuid.New()
+
+	routed := transfer("tx-1", "@alice", "@bob", "10", at, nil)
+	routed.Route = transactionRoute.String()
+	routed.Operations = []models.Operation{{ID: "op-1", Route: operationRoute.String()}, {ID: "op-2", Route: "missing-op-route"}}
+
+	unrouted := transfer("tx-2", "@alice", "@bob", "10", at, nil)
+	unrouted.Route = "missing-route"
+
+	e := &entities.Entity{
+		Accounts: &referenceAccounts{items: []models.Account{
+			{ID: "acc-1", PortfolioID: ptr("pf-1"), SegmentID: ptr("seg-1"), Type: "deposit"},
+			{ID: "acc-2", PortfolioID: ptr("pf-9"), ParentAccountID: ptr("acc-1"), Type: "loan"},
+			{ID: "acc-3", SegmentID: ptr("seg-9"), ParentAccountID: ptr("acc-9")},
+		}},
+		Portfolios:        &referencePortfolios{items: []models.Portfolio{{ID: "pf-1"}}},
+		Segments:          &referenceSegments{items: []models.Segment{{ID: "seg-1"}}},
+		AccountTypes:      &referenceAccountTypes{items: []models.AccountType{{KeyValue: "deposit"}}},
+		TransactionRoutes: &referenceTransactionRoutes{items: []models.TransactionRoute{{ID: transactionRoute}}},
+		OperationRoutes:   &referenceOperationRoutes{items: []models.OperationRoute{{ID: operationRoute}}},
+		Transactions:      &duplicateTransactionsService{transactions: []models.Transaction{routed, unrouted}},
+	}
+
+	report, err := NewChecker(e).CheckReferences(context.Background(), "org", "ledger", ReferenceOptions{Start: at.Add(-time.Hour), End: at.Add(time.Hour)})
+	require.NoError(t, err)
+
+	assert.False(t, report.OK())
+	assert.Equal(t, 3, report.Accounts)
+	assert.Equal(t, 2, report.Transactions)
+	assert.True(t, report.AccountTypesChecked)
+
+	var got []string
+	for _, d := range report.Dangling {
+		got = append(got, d.String())
+	}
+
+	assert.Equal(t, []string{
+		"account acc-2 portfolioId: pf-9 not found",
+		"account acc-2 type: loan not found",
+		"account acc-3 segmentId: seg-9 not found",
+		"account acc-3 parentAccountId: acc-9 not found",
+		"operation op-2 route: missing-op-route not found",
+		"transaction tx-2 route: missing-route not found",
+	}, got)
+	assert.Equal(t, "6 dangling references in 3 accounts and 2 transactions", report.String())
+	assert.Equal(t, 6, report.DanglingCount)
+}
+
+func TestCheckReferences_BoundsDangling(t *testing.T) {
+	accounts := make([]models.Account, DefaultMaxDangling+5)
+	for i := range accounts {
+		accounts[i] = models.Account{ID: fmt.Sprintf("acc-%d", i), PortfolioID: ptr("missing")}
+	}
+
+	e := &entities.Entity{
+		Accounts:     &referenceAccounts{items: accounts},
+		Portfolios:   &referencePortfolios{},
+		Segments:     &referenceSegments{},
+		AccountTypes: &referenceAccountTypes{},
+	}
+
+	report, err := NewChecker(e).CheckReferences(context.Background(), "org", "ledger", ReferenceOptions{})
+	require.NoError(t, err)
+	assert.Len(t, report.Dangling, DefaultMaxDangling)
+	assert.Equal(t, DefaultMaxDangling+5, report.DanglingCount)
+	assert.False(t, report.OK())
+
+	report, err = NewChecker(e).CheckReferences(context.Background(), "org", "ledger", ReferenceOptions{MaxDangling: 10})
+	require.NoError(t, err)
+	assert.Len(t, report.Dangling, 10)
+	assert.Equal(t, DefaultMaxDangling+5, report.DanglingCount)
+
+	report, err = NewChecker(e).CheckReferences(context.Background(), "org", "ledger", ReferenceOptions{MaxDangling: -1})
+	require.NoError(t, err)
+	assert.Len(t, report.Dangling, DefaultMaxDangling+5, "a negative bound keeps them all")
+}
+
+func TestCheckReferences_WithoutAccountTypesOrTransactions(t *testing.T) {
+	e := &entities.Entity{
+		Accounts:     &referenceAccounts{items: []models.Account{{ID: "acc-1", Type: "deposit"}}},
+		Portfolios:   &referencePortfolios{},
+		Segments:     &referenceSegments{},
+		AccountTypes: &referenceAccountTypes{},
+	}
+
+	report, err := NewChecker(e).CheckReferences(context.Background(), "org", "ledger", ReferenceOptions{})
+	require.NoError(t, err)
+	assert.True(t, report.OK(), "types aren't checked when the ledger defines none")
+	assert.False(t, report.AccountTypesChecked)
+	assert.Zero(t, report.Transactions)
+
+	_, err = NewChecker(e).CheckReferences(context.Background(), "org", "ledger", ReferenceOptions{Start: time.Now(), End: time.Now().Add(time.Hour)})
+	require.Error(t, err, "checking transactions needs their services")
+
+	_, err = NewChecker(&entities.Entity{}).CheckReferences(context.Background(), "org", "ledger", ReferenceOptions{})
+	require.Error(t, err)
+}
@@ -0,0 +1,236 @@
+package integrity
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+)
+
+// maxRuleViolations bounds the violations kept per rule result.
+const maxRuleViolations = 100
+
+// RuleEntry is a balance seen by a scan, with the alias of its account.
+type RuleEntry struct {
+	Balance models.Balance
+	Alias   string
+}
+
+// Account returns the alias of the account of the entry, or its ID when it
+// has no alias.
+func (e RuleEntry) Account() string {
+	if e.Alias != "" {
+		return e.Alias
+	}
+
+	return e.Balance.AccountID
+}
+
+// RuleResult is the outcome of a rule for a scan.
+type RuleResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+
+	// Checked counts the balances the rule applied to
+	Checked int `json:"checked"`
+
+	// Violations describes the failures, up to 100; ViolationCount counts
+	// them all
+	Violations     []string `json:"violations,omitempty"`
+	ViolationCount int      `json:"violationCount"`
+}
+
+// RuleEvaluator evaluates a rule over the balances of one scan.
+type RuleEvaluator interface {
+	// Observe is called for each balance of the ledger
+	Observe(entry RuleEntry)
+
+	// Result is called once, after the last balance
+	Result() RuleResult
+}
+
+// Rule is a custom integrity check registered with WithRules. Each scan gets
+// a new evaluator from Begin, so rules may keep state across balances without
+// leaking it between scans.
+type Rule interface {
+	Name() string
+	Begin() RuleEvaluator
+}
+
+// WithRules registers custom rules evaluated during each scan, in addition to
+// the built-in checks. Their results are listed in Report.Rules in the order
+// of registration. Rules run on the goroutine of the scan and need no locking.
+//
+// Example:
+//
+//	checker := integrity.NewChecker(client.Entity).WithRules(
+//	    integrity.NoNegativeBalances("no negative customer account", func(e integrity.RuleEntry) bool {
+//	        return strings.HasPrefix(e.Alias, "@customer/")
+//	    }),
+//	    integrity.RatioAtMost("fees within 2% of customer funds", isFeeAccount, isCustomerAccount, decimal.RequireFromString("0.02")),
+//	)
+func (c *Checker) WithRules(rules ...Rule) *Checker {
+	c.rules = append(c.rules, rules...)
+	return c
+}
+
+// RulesPassed reports whether every custom rule of the report passed.
+func (r *Report) RulesPassed() bool {
+	for _, result := range r.Rules {
+		if !result.Passed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// beginRules starts the evaluation of rules for a scan
+func beginRules(rules []Rule) []RuleEvaluator {
+	evaluators := make([]RuleEvaluator, 0, len(rules))
+	for _, rule := range rules {
+		evaluators = append(evaluators, rule.Begin())
+	}
+
+	return evaluators
+}
+
+// ruleResults collects the results of the rules of a scan, named after them
+func ruleResults(rules []Rule, evaluators []RuleEvaluator) []RuleResult {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	results := make([]RuleResult, len(rules))
+	for i, rule := range rules {
+		results[i] = evaluators[i].Result()
+		results[i].Name = rule.Name()
+	}
+
+	return results
+}
+
+// violations accumulates the failures of a rule
+type violations struct {
+	checked int
+	items   []string
+	count   int
+}
+
+func (v *violations) add(format string, args ...any) {
+	v.count++
+
+	if len(v.items) < maxRuleViolations {
+		v.items = append(v.items, fmt.Sprintf(format, args...))
+	}
+}
+
+func (v *violations) result() RuleResult {
+	return RuleResult{Passed: v.count == 0, Checked: v.checked, Violations: v.items, ViolationCount: v.count}
+}
+
+// balanceRule is a Rule checking each balance on its own
+type balanceRule struct {
+	name  string
+	check func(RuleEntry) error
+}
+
+// ForEachBalance returns a rule checking each balance on its own: every error
+// returned by check is a violation of the balance's account.
+func ForEachBalance(name string, check func(entry RuleEntry) error) Rule {
+	return &balanceRule{name: name, check: check}
+}
+
+func (r *balanceRule) Name() string { return r.name }
+
+func (r *balanceRule) Begin() RuleEvaluator { return &balanceEvaluator{check: r.check} }
+
+type balanceEvaluator struct {
+	violations
+	check func(RuleEntry) error
+}
+
+func (e *balanceEvaluator) Observe(entry RuleEntry) {
+	e.checked++
+
+	if err := e.check(entry); err != nil {
+		e.add("%s (%s): %v", entry.Account(), entry.Balance.AssetCode, err)
+	}
+}
+
+func (e *balanceEvaluator) Result() RuleResult { return e.result() }
+
+// NoNegativeBalances returns a rule failing for each balance matched by match
+// (every balance when nil) with a negative available amount.
+func NoNegativeBalances(name string, match func(entry RuleEntry) bool) Rule {
+	return ForEachBalance(name, func(entry RuleEntry) error {
+		if match != nil && !match(entry) {
+			return nil
+		}
+
+		if entry.Balance.Available.IsNegative() {
+			return fmt.Errorf("available %s is negative", entry.Balance.Available)
+		}
+
+		return nil
+	})
+}
+
+// ratioRule is a Rule bounding the share of the balances of some accounts
+type ratioRule struct {
+	name        string
+	part, whole func(RuleEntry) bool
+	max         decimal.Decimal
+}
+
+// RatioAtMost returns a rule requiring, for each asset, the available amounts
+// of the balances matched by part to sum to at most max times those matched
+// by whole, e.g. fee accounts holding at most 2% (max 0.02) of the customer
+// funds. Assets without balances matched by part are not checked.
+func RatioAtMost(name string, part, whole func(entry RuleEntry) bool, maxRatio decimal.Decimal) Rule {
+	return &ratioRule{name: name, part: part, whole: whole, max: maxRatio}
+}
+
+func (r *ratioRule) Name() string { return r.name }
+
+func (r *ratioRule) Begin() RuleEvaluator {
+	return &ratioEvaluator{rule: r, parts: map[string]decimal.Decimal{}, wholes: map[string]decimal.Decimal{}}
+}
+
+type ratioEvaluator struct {
+	violations
+	rule          *ratioRule
+	parts, wholes map[string]decimal.Decimal
+}
+
+func (e *ratioEvaluator) Observe(entry RuleEntry) {
+	asset := entry.Balance.AssetCode
+
+	if e.rule.part(entry) {
+		e.checked++
+		e.parts[asset] = e.parts[asset].Add(entry.Balance.Available)
+	}
+
+	if e.rule.whole(entry) {
+		e.wholes[asset] = e.wholes[asset].Add(entry.Balance.Available)
+	}
+}
+
+func (e *ratioEvaluator) Result() RuleResult {
+	assets := make([]string, 0, len(e.parts))
+	for asset := range e.parts {
+		assets = append(assets, asset)
+	}
+
+	sort.Strings(assets)
+
+	for _, asset := range assets {
+		part, limit := e.parts[asset], e.wholes[asset].Mul(e.rule.max)
+		if part.GreaterThan(limit) {
+			e.add("%s: %s exceeds %s (%s of %s)", asset, part, limit, e.rule.max, e.wholes[asset])
+		}
+	}
+
+	return e.result()
+}
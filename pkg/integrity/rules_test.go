@@ -0,0 +1,95 @@
+package integrity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func aliasedBalance(alias, asset string, available int64) models.Balance {
+	b := createTestBalance(strings.TrimPrefix(alias, "@"), asset, available, 0)
+	b.Alias = alias
+
+	return b
+}
+
+func TestGenerateLedgerReport_Rules(t *testing.T) {
+	balances := []models.Balance{
+		aliasedBalance("@customer/alice", "USD", 1000),
+		aliasedBalance("@customer/bob", "USD", -50),
+		aliasedBalance("@fees/usd", "USD", 30),
+		aliasedBalance("@customer/carol", "BRL", 500),
+		aliasedBalance("@fees/brl", "BRL", 5),
+		aliasedBalance("@treasury", "USD", -10),
+	}
+
+	isCustomer := func(e RuleEntry) bool { return strings.HasPrefix(e.Alias, "@customer/") }
+	isFee := func(e RuleEntry) bool { return strings.HasPrefix(e.Alias, "@fees/") }
+
+	checker := NewChecker(&entities.Entity{
+		Accounts: &testAccountsService{},
+		Balances: pagedBalances(func() []models.Balance { return balances }),
+	}).WithRules(
+		NoNegativeBalances("no negative customer account", isCustomer),
+		RatioAtMost("fees within 2% of customer funds", isFee, isCustomer, decimal.RequireFromString("0.02")),
+		ForEachBalance("known assets", func(e RuleEntry) error {
+			if e.Balance.AssetCode != "USD" && e.Balance.AssetCode != "BRL" {
+				return errors.New("unexpected asset")
+			}
+
+			return nil
+		}),
+	)
+
+	report, err := checker.GenerateLedgerReport(context.Background(), "org-1", "ledger-1")
+	require.NoError(t, err)
+	require.Len(t, report.Rules, 3)
+	assert.False(t, report.RulesPassed())
+
+	negative := report.Rules[0]
+	assert.Equal(t, "no negative customer account", negative.Name)
+	assert.False(t, negative.Passed)
+	assert.Equal(t, 6, negative.Checked)
+	assert.Equal(t, []string{"@customer/bob (USD): available -50 is negative"}, negative.Violations, "only customer accounts are checked")
+
+	ratio := report.Rules[1]
+	assert.False(t, ratio.Passed)
+	assert.Equal(t, 2, ratio.Checked)
+	assert.Equal(t, []string{"USD: 30 exceeds 19 (0.02 of 950)"}, ratio.Violations, "BRL fees are within 2%")
+
+	assert.True(t, report.Rules[2].Passed)
+	assert.Equal(t, 0, report.Rules[2].ViolationCount)
+
+	// every scan starts from a fresh evaluator
+	report, err = checker.GenerateLedgerReport(context.Background(), "org-1", "ledger-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Rules[0].ViolationCount)
+	assert.Equal(t, 2, report.Rules[1].Checked)
+}
+
+func TestRuleResult_ViolationsAreCapped(t *testing.T) {
+	evaluator := NoNegativeBalances("negative", nil).Begin()
+
+	for i := range maxRuleViolations + 5 {
+		evaluator.Observe(RuleEntry{Balance: createTestBalance(fmt.Sprintf("account-%d", i), "USD", -1, 0)})
+	}
+
+	result := evaluator.Result()
+	assert.Len(t, result.Violations, maxRuleViolations)
+	assert.Equal(t, maxRuleViolations+5, result.ViolationCount)
+	assert.Equal(t, "account-0 (USD): available -1 is negative", result.Violations[0], "accounts without alias are named by ID")
+}
+
+func TestReportRulesPassed(t *testing.T) {
+	assert.True(t, (&Report{}).RulesPassed())
+	assert.True(t, (&Report{Rules: []RuleResult{{Name: "a", Passed: true}}}).RulesPassed())
+	assert.False(t, (&Report{Rules: []RuleResult{{Name: "a", Passed: true}, {Name: "b"}}}).RulesPassed())
+}
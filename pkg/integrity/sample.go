@@ -0,0 +1,399 @@
+package integrity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultSampleConfidence is the default confidence level of the bound of a
+// sampling audit.
+const DefaultSampleConfidence = 0.95
+
+// SampleOptions configures SampleAudit.
+type SampleOptions struct {
+	// Start and End bound the creation time of the transactions audited
+	// (End exclusive). Both are required.
+	Start time.Time
+	End   time.Time
+
+	// Percent is the share of the transactions audited, in (0, 100]
+	Percent float64
+
+	// Seed varies the transactions sampled. A transaction is sampled by a
+	// hash of its ID and the seed, so a run can be reproduced and the
+	// transactions created after it don't change the sample of a period
+	Seed uint64
+
+	// Confidence is the confidence level of SampleReport.UpperBound, in
+	// (0, 1). Defaults to DefaultSampleConfidence.
+	Confidence float64
+}
+
+// SampleDiscrepancy is a sampled transaction whose operations don't match it.
+type SampleDiscrepancy struct {
+	TransactionID string `json:"transactionId"`
+
+	// Problems describes each check failed, e.g. "operation op-1 moved the
+	// balance of @alice by -90, expected -100"
+	Problems []string `json:"problems"`
+}
+
+// SampleReport is the outcome of a sampling audit of a ledger.
+type SampleReport struct {
+	LedgerID string    `json:"ledgerId"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Percent  float64   `json:"percent"`
+	Seed     uint64    `json:"seed"`
+
+	// Population counts the transactions created in the time range, and
+	// Sampled those audited
+	Population int `json:"population"`
+	Sampled    int `json:"sampled"`
+
+	// Operations counts the operations checked, and Fetched the sampled
+	// transactions read again because the listing carried no operations
+	Operations int `json:"operations"`
+	Fetched    int `json:"fetched"`
+
+	Discrepancies []SampleDiscrepancy `json:"discrepancies,omitempty"`
+
+	// DiscrepancyRate is the share of the sampled transactions with a
+	// discrepancy, and UpperBound the share of the population that may have
+	// one at the Confidence level
+	Confidence      float64 `json:"confidence"`
+	DiscrepancyRate float64 `json:"discrepancyRate"`
+	UpperBound      float64 `json:"upperBound"`
+
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// OK reports whether no sampled transaction has a discrepancy.
+func (r *SampleReport) OK() bool {
+	return len(r.Discrepancies) == 0
+}
+
+// EstimatedDiscrepancies returns the number of transactions of the population
+// that may have a discrepancy at the Confidence level.
+func (r *SampleReport) EstimatedDiscrepancies() int {
+	return int(math.Ceil(r.UpperBound*float64(r.Population) - 1e-9))
+}
+
+// String summarizes the report, e.g. "0 discrepancies in 120 of 12000
+// transactions: at most 2.46% (296) at 95% confidence".
+func (r *SampleReport) String() string {
+	return fmt.Sprintf("%d discrepancies in %d of %d transactions: at most %.2f%% (%d) at %s%% confidence",
+		len(r.Discrepancies), r.Sampled, r.Population, r.UpperBound*100, r.EstimatedDiscrepancies(),
+		strconv.FormatFloat(r.Confidence*100, 'f', -1, 64))
+}
+
+// SampleAudit audits a share of the transactions of a ledger created between
+// opts.Start and opts.End, much cheaper than reconciling every balance. The
+// transactions are listed, opts.Percent of them are sampled, and the
+// operations the API returns for each are cross-checked against it: debits
+// and credits balance per asset and add up to the amount of the transaction,
+// their accounts are those of the transaction, and each operation moved the
+// balance of its account by the delta its type and amount imply. The report
+// bounds the share of the whole period that may have a discrepancy, at
+// opts.Confidence. Transactions are read again in parallel when the listing
+// carries no operations (see WithConcurrency).
+//
+// Example of a nightly audit of 1% of the day:
+//
+//	report, err := integrity.NewChecker(client.Entity).SampleAudit(ctx, orgID, ledgerID, integrity.SampleOptions{
+//	    Start:   day,
+//	    End:     day.Add(24 * time.Hour),
+//	    Percent: 1,
+//	})
+//	if err == nil {
+//	    log.Print(report)
+//	}
+func (c *Checker) SampleAudit(ctx context.Context, orgID, ledgerID string, opts SampleOptions) (*SampleReport, error) {
+	if c.e == nil || c.e.Transactions == nil {
+		return nil, errors.New("transactions service not initialized")
+	}
+
+	if opts.Start.IsZero() || opts.End.IsZero() || !opts.End.After(opts.Start) {
+		return nil, errors.New("a time range with start before end is required")
+	}
+
+	if opts.Percent <= 0 || opts.Percent > 100 {
+		return nil, fmt.Errorf("sample percent must be in (0, 100], got %v", opts.Percent)
+	}
+
+	if opts.Confidence == 0 {
+		opts.Confidence = DefaultSampleConfidence
+	}
+
+	if opts.Confidence <= 0 || opts.Confidence >= 1 {
+		return nil, fmt.Errorf("sample confidence must be in (0, 1), got %v", opts.Confidence)
+	}
+
+	startedAt := time.Now()
+	report := &SampleReport{LedgerID: ledgerID, Start: opts.Start, End: opts.End, Percent: opts.Percent, Seed: opts.Seed, Confidence: opts.Confidence}
+
+	err := observability.WithSpan(ctx, c.obs, "SampleAudit", func(ctx context.Context) error {
+		var sample []models.Transaction
+
+		err := c.listTransactions(ctx, orgID, ledgerID, opts.Start, opts.End, func(tx models.Transaction) {
+			report.Population++
+
+			if tx.DeletedAt == nil && sampled(opts.Seed, tx.ID, opts.Percent) {
+				sample = append(sample, tx)
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		return c.auditSample(ctx, orgID, ledgerID, sample, report)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Sampled > 0 {
+		report.DiscrepancyRate = float64(len(report.Discrepancies)) / float64(report.Sampled)
+	}
+
+	report.UpperBound = discrepancyUpperBound(len(report.Discrepancies), report.Sampled, report.Population, opts.Confidence)
+	report.Elapsed = time.Since(startedAt)
+
+	c.logInfo("Audited %d of %d transactions of ledger %q: %d discrepancies", report.Sampled, report.Population, ledgerID, len(report.Discrepancies))
+
+	return report, nil
+}
+
+// auditSample checks the operations of the sampled transactions, reading
+// again those listed without operations
+func (c *Checker) auditSample(ctx context.Context, orgID, ledgerID string, sample []models.Transaction, report *SampleReport) error {
+	results := concurrent.WorkerPool(ctx, sample, func(ctx context.Context, tx models.Transaction) (models.Transaction, error) {
+		if len(tx.Operations) > 0 {
+			return tx, nil
+		}
+
+		fetched, err := c.e.Transactions.GetTransaction(ctx, orgID, ledgerID, tx.ID)
+		if err != nil {
+			return tx, fmt.Errorf("failed to get transaction %s: %w", tx.ID, err)
+		}
+
+		return *fetched, nil
+	}, concurrent.WithWorkers(max(c.concurrency, 1)))
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+
+	for _, r := range results {
+		if r.Error != nil {
+			return r.Error
+		}
+
+		if len(r.Item.Operations) == 0 {
+			report.Fetched++
+		}
+
+		report.Sampled++
+		report.Operations += len(r.Value.Operations)
+
+		if problems := AuditTransaction(r.Value); len(problems) > 0 {
+			report.Discrepancies = append(report.Discrepancies, SampleDiscrepancy{TransactionID: r.Value.ID, Problems: problems})
+		}
+	}
+
+	return nil
+}
+
+// AuditTransaction cross-checks a transaction with its operations, returning
+// the problems found; see SampleAudit for the checks. Balance deltas are
+// checked on the total of the available and on-hold amounts: a debit lowers
+// it by the amount of the operation, a credit raises it, and a hold or a
+// release leaves it unchanged. Operations without the balances before and
+// after them skip that check.
+func AuditTransaction(tx models.Transaction) []string {
+	if len(tx.Operations) == 0 {
+		return []string{"transaction has no operations"}
+	}
+
+	var problems []string
+
+	debits, credits := map[string]decimal.Decimal{}, map[string]decimal.Decimal{}
+	sources, destinations := accountSet(tx.Source), accountSet(tx.Destination)
+
+	for _, op := range tx.Operations {
+		account := op.AccountAlias
+		if account == "" {
+			account = op.AccountID
+		}
+
+		if op.TransactionID != "" && op.TransactionID != tx.ID {
+			problems = append(problems, fmt.Sprintf("operation %s belongs to transaction %s", op.ID, op.TransactionID))
+		}
+
+		if op.Amount.Value == nil {
+			problems = append(problems, fmt.Sprintf("operation %s of %s has no amount", op.ID, account))
+			continue
+		}
+
+		amount := *op.Amount.Value
+		opType := strings.ToUpper(op.Type)
+
+		switch opType {
+		case string(models.OperationTypeDebit):
+			debits[op.AssetCode] = debits[op.AssetCode].Add(amount)
+
+			if len(sources) > 0 && op.AccountAlias != "" && !sources[op.AccountAlias] {
+				problems = append(problems, fmt.Sprintf("operation %s debits %s, not a source of the transaction", op.ID, account))
+			}
+		case string(models.OperationTypeCredit):
+			credits[op.AssetCode] = credits[op.AssetCode].Add(amount)
+
+			if len(destinations) > 0 && op.AccountAlias != "" && !destinations[op.AccountAlias] {
+				problems = append(problems, fmt.Sprintf("operation %s credits %s, not a destination of the transaction", op.ID, account))
+			}
+		}
+
+		if expected, ok := expectedDelta(opType, amount); ok && !op.Balance.IsEmpty() && !op.BalanceAfter.IsEmpty() {
+			if delta := balanceTotal(op.BalanceAfter).Sub(balanceTotal(op.Balance)); !delta.Equal(expected) {
+				problems = append(problems, fmt.Sprintf("operation %s moved the balance of %s by %s, expected %s", op.ID, account, delta, expected))
+			}
+		}
+	}
+
+	for _, asset := range sortedAssets(debits, credits) {
+		if debit, credit := debits[asset], credits[asset]; !debit.Equal(credit) {
+			problems = append(problems, fmt.Sprintf("%s debits total %s but credits total %s", asset, debit, credit))
+		}
+	}
+
+	if amount, err := decimal.NewFromString(tx.Amount); err == nil {
+		if debit, ok := debits[tx.AssetCode]; ok && !debit.Equal(amount) {
+			problems = append(problems, fmt.Sprintf("%s debits total %s but the transaction amount is %s", tx.AssetCode, debit, amount))
+		}
+	}
+
+	return problems
+}
+
+// expectedDelta returns how an operation of the given type moves the total of
+// its balance, false for an unknown type
+func expectedDelta(opType string, amount decimal.Decimal) (decimal.Decimal, bool) {
+	switch opType {
+	case string(models.OperationTypeDebit):
+		return amount.Neg(), true
+	case string(models.OperationTypeCredit):
+		return amount, true
+	case "ON_HOLD", "RELEASE":
+		return decimal.Zero, true
+	default:
+		return decimal.Zero, false
+	}
+}
+
+// balanceTotal returns the available and on-hold amounts of b
+func balanceTotal(b models.OperationBalance) decimal.Decimal {
+	total := decimal.Zero
+
+	if b.Available != nil {
+		total = total.Add(*b.Available)
+	}
+
+	if b.OnHold != nil {
+		total = total.Add(*b.OnHold)
+	}
+
+	return total
+}
+
+// accountSet returns the accounts as a set
+func accountSet(accounts []string) map[string]bool {
+	set := make(map[string]bool, len(accounts))
+	for _, a := range accounts {
+		set[a] = true
+	}
+
+	return set
+}
+
+// sortedAssets returns the assets of the debits and credits, sorted
+func sortedAssets(debits, credits map[string]decimal.Decimal) []string {
+	assets := make([]string, 0, len(debits)+len(credits))
+
+	for asset := range debits {
+		assets = append(assets, asset)
+	}
+
+	for asset := range credits {
+		if _, ok := debits[asset]; !ok {
+			assets = append(assets, asset)
+		}
+	}
+
+	sort.Strings(assets)
+
+	return assets
+}
+
+// sampled reports whether the transaction falls in the sample: the hash of
+// its ID and the seed, as a fraction, is below percent
+func sampled(seed uint64, id string, percent float64) bool {
+	if percent >= 100 {
+		return true
+	}
+
+	var prefix [8]byte
+
+	binary.BigEndian.PutUint64(prefix[:], seed)
+
+	h := sha256.New()
+	h.Write(prefix[:])
+	h.Write([]byte(id))
+
+	fraction := float64(binary.BigEndian.Uint64(h.Sum(nil))>>11) / (1 << 53)
+
+	return fraction < percent/100
+}
+
+// discrepancyUpperBound returns the one-sided Wilson score upper bound of the
+// share of a population of size population with a discrepancy, given
+// discrepancies found among n sampled, at the confidence level. The bound is
+// narrowed by the finite population correction, down to the observed rate
+// when the whole population is sampled.
+func discrepancyUpperBound(discrepancies, n, population int, confidence float64) float64 {
+	if n == 0 {
+		return 1
+	}
+
+	p := float64(discrepancies) / float64(n)
+
+	if n >= population {
+		return p
+	}
+
+	z := math.Sqrt2 * math.Erfinv(2*confidence-1)
+	if population > 1 {
+		z *= math.Sqrt(float64(population-n) / float64(population-1))
+	}
+
+	nf := float64(n)
+	z2 := z * z
+
+	bound := (p + z2/(2*nf) + z*math.Sqrt(p*(1-p)/nf+z2/(4*nf*nf))) / (1 + z2/nf)
+
+	return math.Min(bound, 1)
+}
@@ -0,0 +1,152 @@
+package integrity
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleTransactionsService lists transactions without their operations and
+// returns them in full by ID.
+type sampleTransactionsService struct {
+	duplicateTransactionsService
+
+	full map[string]models.Transaction
+	gets atomic.Int64
+}
+
+func (s *sampleTransactionsService) GetTransaction(_ context.Context, _, _, id string) (*models.Transaction, error) {
+	s.gets.Add(1)
+
+	tx := s.full[id]
+
+	return &tx, nil
+}
+
+func amountOf(value int64) models.Amount {
+	d := decimal.NewFromInt(value)
+	return models.Amount{Value: &d}
+}
+
+func balanceOf(available, onHold int64) models.OperationBalance {
+	a, h := decimal.NewFromInt(available), decimal.NewFromInt(onHold)
+	return models.OperationBalance{Available: &a, OnHold: &h}
+}
+
+// auditedTransfer returns a transfer of amount from @alice to @bob with its operations.
+func auditedTransfer(id string, amount int64, at time.Time) models.Transaction {
+	tx := transfer(id, "@alice", "@bob", fmt.Sprint(amount), at, nil)
+	tx.Operations = []models.Operation{
+		{ID: id + "-d", TransactionID: id, Type: "DEBIT", AssetCode: "USD", AccountAlias: "@alice", Amount: amountOf(amount), Balance: balanceOf(1000, 0), BalanceAfter: balanceOf(1000-amount, 0)},
+		{ID: id + "-c", TransactionID: id, Type: "CREDIT", AssetCode: "USD", AccountAlias: "@bob", Amount: amountOf(amount), Balance: balanceOf(0, 5), BalanceAfter: balanceOf(amount, 5)},
+	}
+
+	return tx
+}
+
+func TestAuditTransaction(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Empty(t, AuditTransaction(auditedTransfer("tx-1", 100, at)))
+	assert.Equal(t, []string{"transaction has no operations"}, AuditTransaction(transfer("tx-1", "@alice", "@bob", "100", at, nil)))
+
+	tx := auditedTransfer("tx-1", 100, at)
+	tx.Operations[0].BalanceAfter = balanceOf(910, 0)
+	tx.Operations[1].Amount = amountOf(90)
+	tx.Operations[1].AccountAlias = "@carol"
+
+	assert.Equal(t, []string{
+		"operation tx-1-d moved the balance of @alice by -90, expected -100",
+		"operation tx-1-c credits @carol, not a destination of the transaction",
+		"operation tx-1-c moved the balance of @carol by 100, expected 90",
+		"USD debits total 100 but credits total 90",
+	}, AuditTransaction(tx))
+
+	hold := auditedTransfer("tx-2", 100, at)
+	hold.Amount = "50"
+	hold.Operations[0].Type = "ON_HOLD"
+	hold.Operations[0].BalanceAfter = balanceOf(900, 100)
+	hold.Operations = append(hold.Operations, models.Operation{ID: "tx-2-x", TransactionID: "tx-9", Type: "DEBIT", AssetCode: "USD", AccountAlias: "@alice", Amount: amountOf(100)})
+
+	assert.Equal(t, []string{
+		"operation tx-2-x belongs to transaction tx-9",
+		"USD debits total 100 but the transaction amount is 50",
+	}, AuditTransaction(hold), "a hold leaves the total unchanged")
+}
+
+func TestSampleAudit(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	service := &sampleTransactionsService{full: map[string]models.Transaction{}}
+
+	for i := range 200 {
+		tx := auditedTransfer(fmt.Sprintf("tx-%03d", i), int64(i+1), base.Add(time.Duration(i)*time.Second))
+		if i%50 == 7 {
+			tx.Operations[1].Amount = amountOf(int64(i))
+		}
+
+		service.full[tx.ID] = tx
+
+		if i%2 == 0 {
+			tx.Operations = nil
+		}
+
+		service.transactions = append(service.transactions, tx)
+	}
+
+	checker := NewChecker(&entities.Entity{Transactions: service}).WithConcurrency(4)
+
+	report, err := checker.SampleAudit(context.Background(), "org", "ledger", SampleOptions{Start: base, End: base.Add(time.Hour), Percent: 100})
+	require.NoError(t, err)
+
+	assert.Equal(t, 200, report.Population)
+	assert.Equal(t, 200, report.Sampled)
+	assert.Equal(t, 400, report.Operations)
+	assert.Equal(t, 100, report.Fetched)
+	assert.Equal(t, int64(100), service.gets.Load(), "only the transactions listed without operations are read again")
+	require.Len(t, report.Discrepancies, 4)
+	assert.Equal(t, "tx-007", report.Discrepancies[0].TransactionID)
+	assert.InDelta(t, 0.02, report.DiscrepancyRate, 1e-9)
+	assert.InDelta(t, 0.02, report.UpperBound, 1e-9, "a full scan bounds nothing more than what it found")
+	assert.Equal(t, 4, report.EstimatedDiscrepancies())
+	assert.False(t, report.OK())
+
+	sample, err := checker.SampleAudit(context.Background(), "org", "ledger", SampleOptions{Start: base, End: base.Add(time.Hour), Percent: 10, Seed: 3})
+	require.NoError(t, err)
+
+	expected := 0
+	for _, tx := range service.transactions {
+		if sampled(3, tx.ID, 10) {
+			expected++
+		}
+	}
+
+	assert.Equal(t, 200, sample.Population)
+	assert.Equal(t, expected, sample.Sampled)
+	assert.Greater(t, sample.Sampled, 5)
+	assert.Less(t, sample.Sampled, 40)
+	assert.Greater(t, sample.UpperBound, sample.DiscrepancyRate)
+	assert.Contains(t, sample.String(), fmt.Sprintf("in %d of 200 transactions", sample.Sampled))
+	assert.Contains(t, sample.String(), "at 95% confidence")
+
+	again, err := checker.SampleAudit(context.Background(), "org", "ledger", SampleOptions{Start: base, End: base.Add(time.Hour), Percent: 10, Seed: 3})
+	require.NoError(t, err)
+	assert.Equal(t, sample.Discrepancies, again.Discrepancies, "the same seed samples the same transactions")
+
+	_, err = checker.SampleAudit(context.Background(), "org", "ledger", SampleOptions{Start: base, End: base.Add(time.Hour)})
+	assert.Error(t, err, "a percent is required")
+}
+
+func TestDiscrepancyUpperBound(t *testing.T) {
+	assert.InDelta(t, 0.0263, discrepancyUpperBound(0, 100, 1_000_000, 0.95), 1e-4)
+	assert.InDelta(t, 0.0134, discrepancyUpperBound(0, 100, 200, 0.95), 1e-4, "a sample of half the population bounds tighter")
+	assert.InDelta(t, 0.1, discrepancyUpperBound(10, 100, 100, 0.95), 1e-9)
+	assert.Equal(t, 1.0, discrepancyUpperBound(0, 0, 100, 0.95))
+}
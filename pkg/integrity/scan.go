@@ -0,0 +1,278 @@
+package integrity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/kvstore"
+)
+
+// ErrStateMismatch is returned when the state file belongs to another ledger.
+var ErrStateMismatch = errors.New("integrity state belongs to another ledger")
+
+// ScanProgress describes a ledger scan, reported after each page of balances
+// and recorded in the Report.
+type ScanProgress struct {
+	// Pages and Balances count the pages and balances read
+	Pages    int `json:"pages"`
+	Balances int `json:"balances"`
+
+	// Changed counts the balances created or updated since the checkpoint of
+	// the state file; every balance is changed in a scan without one
+	Changed int `json:"changed"`
+
+	// AccountLookups counts the accounts fetched to resolve their aliases
+	AccountLookups int `json:"accountLookups"`
+
+	// Incremental reports whether the scan started from a checkpoint, taken
+	// at Since
+	Incremental bool      `json:"incremental"`
+	Since       time.Time `json:"since,omitzero"`
+
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// ScanState is the checkpoint of the last complete scan of a ledger, saved
+// to the state file of incremental scans.
+type ScanState struct {
+	OrganizationID string `json:"organizationId"`
+	LedgerID       string `json:"ledgerId"`
+
+	// CheckpointAt is the start of the scan; balances updated after it are
+	// changed for the next one
+	CheckpointAt time.Time `json:"checkpointAt"`
+
+	// Aliases maps the IDs of the accounts seen to their aliases, which
+	// aren't looked up again
+	Aliases map[string]string `json:"aliases"`
+
+	// Versions maps the IDs of the balances seen to their versions
+	Versions map[string]int64 `json:"versions"`
+}
+
+// ledgerScan is the state of a GenerateLedgerReport call
+type ledgerScan struct {
+	startedAt time.Time
+	totals    map[string]*BalanceTotals
+
+	// aliases caches the aliases of accounts, seeded from the previous state
+	aliases map[string]string
+
+	previous *ScanState
+	next     *ScanState
+	progress ScanProgress
+
+	// rules evaluates the custom rules of the checker
+	rules []RuleEvaluator
+}
+
+// newLedgerScan starts a scan of a ledger from the previous state, if any
+func newLedgerScan(orgID, ledgerID string, previous *ScanState) *ledgerScan {
+	s := &ledgerScan{
+		startedAt: time.Now().UTC(),
+		totals:    map[string]*BalanceTotals{},
+		aliases:   map[string]string{},
+		previous:  previous,
+	}
+
+	s.next = &ScanState{
+		OrganizationID: orgID,
+		LedgerID:       ledgerID,
+		CheckpointAt:   s.startedAt,
+		Aliases:        map[string]string{},
+		Versions:       map[string]int64{},
+	}
+
+	if previous != nil {
+		for id, alias := range previous.Aliases {
+			s.aliases[id] = alias
+		}
+
+		s.progress.Incremental = true
+		s.progress.Since = previous.CheckpointAt
+	}
+
+	return s
+}
+
+// changed reports whether b was created or updated since the previous checkpoint
+func (s *ledgerScan) changed(b models.Balance) bool {
+	if s.previous == nil {
+		return true
+	}
+
+	version, ok := s.previous.Versions[b.ID]
+
+	return !ok || version != b.Version || b.UpdatedAt.After(s.previous.CheckpointAt)
+}
+
+// WithConcurrency sets the number of accounts looked up in parallel to
+// resolve their aliases (1 by default). The account lookup delay applies to
+// each worker.
+func (c *Checker) WithConcurrency(workers int) *Checker {
+	if workers > 0 {
+		c.concurrency = workers
+	}
+
+	return c
+}
+
+// WithStateFile makes the scans incremental: the aliases and balance
+// versions seen by a complete scan are saved to path, and the next scan only
+// looks up the accounts missing from it. Balances are still read
+// in full, so the totals cover the whole ledger. The file belongs to a single
+// ledger; scanning another one with it fails with ErrStateMismatch.
+//
+// Example of a nightly check:
+//
+//	report, err := integrity.NewChecker(client.Entity).
+//	    WithConcurrency(16).
+//	    WithStateFile("./integrity-" + ledgerID + ".json").
+//	    WithProgress(func(p integrity.ScanProgress) { log.Printf("%d balances, %d changed", p.Balances, p.Changed) }).
+//	    GenerateLedgerReport(ctx, orgID, ledgerID)
+func (c *Checker) WithStateFile(path string) *Checker {
+	c.stateFile = path
+	return c
+}
+
+// WithStateStore is WithStateFile with the state saved under key of store,
+// for checks whose state is kept in a shared store such as Redis.
+func (c *Checker) WithStateStore(store kvstore.Interface, key string) *Checker {
+	c.stateStore, c.stateKey = store, key
+	return c
+}
+
+// WithProgress sets a callback receiving the progress of scans after each
+// page of balances. It is called from the goroutine running the scan.
+func (c *Checker) WithProgress(fn func(ScanProgress)) *Checker {
+	c.onProgress = fn
+	return c
+}
+
+// resolveAliases looks up, in parallel, the accounts of items whose aliases
+// are neither in the balance nor cached
+func (c *Checker) resolveAliases(ctx context.Context, orgID, ledgerID string, items []models.Balance, s *ledgerScan) error {
+	var missing []string
+
+	queued := map[string]struct{}{}
+
+	for _, b := range items {
+		if b.Alias != "" {
+			s.aliases[b.AccountID] = b.Alias
+			continue
+		}
+
+		if _, ok := s.aliases[b.AccountID]; ok {
+			continue
+		}
+
+		if _, ok := queued[b.AccountID]; !ok {
+			queued[b.AccountID] = struct{}{}
+			missing = append(missing, b.AccountID)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	results := concurrent.WorkerPool(ctx, missing, func(ctx context.Context, accountID string) (string, error) {
+		return c.fetchAccountAlias(ctx, orgID, ledgerID, accountID)
+	}, concurrent.WithWorkers(max(c.concurrency, 1)))
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		if r.Error != nil {
+			return r.Error
+		}
+
+		s.aliases[r.Item] = r.Value
+	}
+
+	s.progress.AccountLookups += len(missing)
+
+	return nil
+}
+
+// reportProgress passes the progress of s to the progress callback, if any
+func (c *Checker) reportProgress(s *ledgerScan) {
+	s.progress.Elapsed = time.Since(s.startedAt)
+
+	if c.onProgress != nil {
+		c.onProgress(s.progress)
+	}
+}
+
+// loadState reads the saved state, returning nil when there is none
+func (c *Checker) loadState(ctx context.Context, orgID, ledgerID string) (*ScanState, error) {
+	var (
+		raw []byte
+		err error
+	)
+
+	switch {
+	case c.stateStore != nil:
+		raw, err = c.stateStore.Get(ctx, c.stateKey)
+		if errors.Is(err, kvstore.ErrNotFound) {
+			return nil, nil //nolint:nilnil // the first scan has no checkpoint
+		}
+	case c.stateFile != "":
+		raw, err = os.ReadFile(c.stateFile)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil //nolint:nilnil // the first scan has no checkpoint
+		}
+	default:
+		return nil, nil //nolint:nilnil // no saved state means a full scan
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read integrity state: %w", err)
+	}
+
+	var state ScanState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse integrity state: %w", err)
+	}
+
+	if state.OrganizationID != orgID || state.LedgerID != ledgerID {
+		return nil, fmt.Errorf("%w: %s/%s", ErrStateMismatch, state.OrganizationID, state.LedgerID)
+	}
+
+	return &state, nil
+}
+
+// saveState writes the state, replacing it atomically
+func (c *Checker) saveState(ctx context.Context, state *ScanState) error {
+	if c.stateStore == nil && c.stateFile == "" {
+		return nil
+	}
+
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode integrity state: %w", err)
+	}
+
+	if c.stateStore != nil {
+		if err := c.stateStore.Put(ctx, c.stateKey, raw); err != nil {
+			return fmt.Errorf("failed to write integrity state: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := fsutil.WriteFileAtomic(c.stateFile, raw); err != nil {
+		return fmt.Errorf("failed to write integrity state: %w", err)
+	}
+
+	return nil
+}
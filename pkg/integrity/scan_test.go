@@ -0,0 +1,223 @@
+package integrity
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/kvstore"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedBalances serves balances two per page
+func pagedBalances(balances func() []models.Balance) *testBalancesService {
+	return &testBalancesService{
+		listBalancesFn: func(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+			items := balances()
+
+			start := 0
+			if opts != nil && opts.Cursor != "" {
+				_, _ = fmt.Sscanf(opts.Cursor, "page-%d", &start)
+			}
+
+			end := min(start+2, len(items))
+			resp := &models.ListResponse[models.Balance]{Items: items[start:end]}
+
+			if end < len(items) {
+				resp.Pagination.NextCursor = fmt.Sprintf("page-%d", end)
+			}
+
+			return resp, nil
+		},
+	}
+}
+
+func TestGenerateLedgerReport_Concurrency(t *testing.T) {
+	balances := make([]models.Balance, 0, 20)
+	for i := range 20 {
+		balances = append(balances, createTestBalance(fmt.Sprintf("account-%d", i), "USD", 10, 0))
+	}
+
+	var active, peak, lookups atomic.Int32
+
+	mockAccounts := &testAccountsService{
+		getAccountFn: func(_ context.Context, _, _, id string) (*models.Account, error) {
+			lookups.Add(1)
+
+			n := active.Add(1)
+			defer active.Add(-1)
+
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+
+			time.Sleep(5 * time.Millisecond)
+
+			return createTestAccount(id, ptr("@"+id)), nil
+		},
+	}
+
+	var pages []ScanProgress
+
+	report, err := NewChecker(&entities.Entity{
+		Accounts: mockAccounts,
+		Balances: &testBalancesService{listBalancesFn: func(_ context.Context, _, _ string, _ *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+			return &models.ListResponse[models.Balance]{Items: balances}, nil
+		}},
+	}).WithConcurrency(4).WithProgress(func(p ScanProgress) { pages = append(pages, p) }).
+		GenerateLedgerReport(context.Background(), "org-1", "ledger-1")
+
+	require.NoError(t, err)
+	assert.True(t, report.TotalsByAsset["USD"].TotalAvailable.Equal(decimal.NewFromInt(200)))
+	assert.Equal(t, int32(20), lookups.Load())
+	assert.Greater(t, peak.Load(), int32(1), "accounts are looked up in parallel")
+	assert.LessOrEqual(t, peak.Load(), int32(4))
+
+	require.Len(t, pages, 1)
+	assert.Equal(t, 20, pages[0].Balances)
+	assert.Equal(t, 20, pages[0].AccountLookups)
+	assert.Equal(t, 20, report.Scan.Changed)
+	assert.False(t, report.Scan.Incremental)
+}
+
+func TestGenerateLedgerReport_AliasFromBalance(t *testing.T) {
+	b := createTestBalance("account-1", "USD", -5, 0)
+	b.Alias = "@external/USD"
+
+	report, err := NewChecker(&entities.Entity{
+		Accounts: &testAccountsService{getAccountFn: func(_ context.Context, _, _, _ string) (*models.Account, error) {
+			return nil, errAccountNotFound
+		}},
+		Balances: pagedBalances(func() []models.Balance { return []models.Balance{b} }),
+	}).GenerateLedgerReport(context.Background(), "org-1", "ledger-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Scan.AccountLookups)
+	assert.True(t, report.TotalsByAsset["USD"].InternalNetTotal.IsZero(), "external accounts are excluded")
+	assert.Equal(t, []string{"@external/USD"}, report.TotalsByAsset["USD"].Overdrawn)
+}
+
+func TestGenerateLedgerReport_Incremental(t *testing.T) {
+	var mu sync.Mutex
+
+	balances := []models.Balance{
+		createTestBalance("account-1", "USD", 100, 0),
+		createTestBalance("account-2", "USD", 200, 0),
+		createTestBalance("account-3", "USD", 300, 0),
+	}
+
+	var lookedUp []string
+
+	checker := NewChecker(&entities.Entity{
+		Accounts: &testAccountsService{getAccountFn: func(_ context.Context, _, _, id string) (*models.Account, error) {
+			mu.Lock()
+			lookedUp = append(lookedUp, id)
+			mu.Unlock()
+
+			return createTestAccount(id, ptr("@"+id)), nil
+		}},
+		Balances: pagedBalances(func() []models.Balance { return balances }),
+	}).WithStateFile(filepath.Join(t.TempDir(), "state.json"))
+
+	first, err := checker.GenerateLedgerReport(context.Background(), "org-1", "ledger-1")
+	require.NoError(t, err)
+	assert.Equal(t, ScanProgress{Pages: 2, Balances: 3, Changed: 3, AccountLookups: 3}, withoutElapsed(first.Scan))
+
+	balances[1].Version = 2
+	balances[1].Available = decimal.NewFromInt(250)
+	balances = append(balances, createTestBalance("account-4", "USD", 400, 0))
+	lookedUp = nil
+
+	second, err := checker.GenerateLedgerReport(context.Background(), "org-1", "ledger-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"account-4"}, lookedUp, "only accounts unknown to the checkpoint are looked up")
+	assert.Equal(t, 2, second.Scan.Changed)
+	assert.Equal(t, 4, second.Scan.Balances)
+	assert.True(t, second.Scan.Incremental)
+	assert.True(t, first.Scan.Since.IsZero())
+	assert.False(t, second.Scan.Since.IsZero())
+	assert.True(t, second.TotalsByAsset["USD"].TotalAvailable.Equal(decimal.NewFromInt(1050)), "totals cover the whole ledger")
+}
+
+func TestGenerateLedgerReport_StateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	balances := pagedBalances(func() []models.Balance { return []models.Balance{createTestBalance("account-1", "USD", 1, 0)} })
+
+	failing := NewChecker(&entities.Entity{
+		Accounts: &testAccountsService{getAccountFn: func(_ context.Context, _, _, _ string) (*models.Account, error) {
+			return nil, errAccountNotFound
+		}},
+		Balances: balances,
+	}).WithStateFile(path)
+
+	_, err := failing.GenerateLedgerReport(context.Background(), "org-1", "ledger-1")
+	require.Error(t, err)
+
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist, "failed scans leave no checkpoint")
+
+	checker := NewChecker(&entities.Entity{
+		Accounts: &testAccountsService{getAccountFn: func(_ context.Context, _, _, id string) (*models.Account, error) {
+			return createTestAccount(id, nil), nil
+		}},
+		Balances: balances,
+	}).WithStateFile(path)
+
+	_, err = checker.GenerateLedgerReport(context.Background(), "org-1", "ledger-1")
+	require.NoError(t, err)
+
+	_, err = checker.GenerateLedgerReport(context.Background(), "org-1", "ledger-2")
+	require.ErrorIs(t, err, ErrStateMismatch)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err = checker.GenerateLedgerReport(context.Background(), "org-1", "ledger-1")
+	require.Error(t, err)
+}
+
+func TestGenerateLedgerReport_StateStore(t *testing.T) {
+	store := kvstore.NewMemory()
+	balances := pagedBalances(func() []models.Balance { return []models.Balance{createTestBalance("account-1", "USD", 1, 0)} })
+
+	checker := NewChecker(&entities.Entity{
+		Accounts: &testAccountsService{getAccountFn: func(_ context.Context, _, _, id string) (*models.Account, error) {
+			return createTestAccount(id, nil), nil
+		}},
+		Balances: balances,
+	}).WithStateStore(store, "integrity/ledger-1")
+
+	first, err := checker.GenerateLedgerReport(context.Background(), "org-1", "ledger-1")
+	require.NoError(t, err)
+	assert.False(t, first.Scan.Incremental)
+
+	var state ScanState
+
+	found, err := kvstore.GetJSON(context.Background(), store, "integrity/ledger-1", &state)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "ledger-1", state.LedgerID)
+
+	second, err := checker.GenerateLedgerReport(context.Background(), "org-1", "ledger-1")
+	require.NoError(t, err)
+	assert.True(t, second.Scan.Incremental)
+
+	_, err = checker.GenerateLedgerReport(context.Background(), "org-1", "ledger-2")
+	require.ErrorIs(t, err, ErrStateMismatch)
+}
+
+func withoutElapsed(p ScanProgress) ScanProgress {
+	p.Elapsed = 0
+	return p
+}
@@ -0,0 +1,306 @@
+// Package journal turns Midaz transactions into a classic double-entry
+// journal: one line per movement, with its date, debit account, credit
+// account, amount and memo, as accountants expect.
+//
+// A Midaz transaction has debit operations on its source accounts and credit
+// operations on its destination accounts, any number of each. The journal
+// pairs them in order, splitting amounts where needed, so that a transaction
+// paying 100 from one account into two accounts of 60 and 40 becomes two
+// lines. The debit and credit sides follow the Midaz operation types: the
+// debit account is the one the funds leave.
+//
+// Transactions without both debits and credits, such as pending ones that
+// only hold funds, and cancelled or failed ones, have no journal lines.
+//
+// Example:
+//
+//	lines, err := journal.NewBuilder(client.Entity).
+//	    Build(ctx, orgID, ledgerID, models.NewListOptions().WithDateRange("2026-09-01", "2026-09-30"))
+//	if err != nil {
+//	    return err
+//	}
+//
+//	err = journal.WriteCSV(f, lines)
+package journal
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+)
+
+// ErrUnbalanced is returned for a transaction whose debits and credits of an
+// asset don't add up to the same amount.
+var ErrUnbalanced = errors.New("transaction debits and credits don't balance")
+
+// CSVHeader is the header row written by WriteCSV.
+var CSVHeader = []string{
+	"date", "transaction_id", "debit_account", "credit_account", "amount", "asset_code", "memo",
+	"debit_chart_of_accounts", "credit_chart_of_accounts",
+}
+
+// Line is a journal line: amount of asset moved from DebitAccount to CreditAccount.
+type Line struct {
+	Date          time.Time       `json:"date"`
+	TransactionID string          `json:"transactionId"`
+	DebitAccount  string          `json:"debitAccount"`
+	CreditAccount string          `json:"creditAccount"`
+	Amount        decimal.Decimal `json:"amount"`
+	Asset         string          `json:"asset"`
+	Memo          string          `json:"memo,omitempty"`
+
+	// DebitChartOfAccounts and CreditChartOfAccounts are the chart of
+	// accounts codes of the operations, when set
+	DebitChartOfAccounts  string `json:"debitChartOfAccounts,omitempty"`
+	CreditChartOfAccounts string `json:"creditChartOfAccounts,omitempty"`
+}
+
+// leg is the remaining amount of an operation to pair.
+type leg struct {
+	op     models.Operation
+	amount decimal.Decimal
+}
+
+// FromTransaction returns the journal lines of tx, built from its operations.
+// It returns ErrUnbalanced when its debits and credits don't match.
+func FromTransaction(tx *models.Transaction) ([]Line, error) {
+	switch strings.ToLower(tx.Status.Code) {
+	case models.TransactionStatusCancelled, "canceled", models.TransactionStatusFailed:
+		return nil, nil
+	}
+
+	var (
+		assets  []string
+		debits  = map[string][]*leg{}
+		credits = map[string][]*leg{}
+	)
+
+	for _, op := range tx.Operations {
+		if op.Amount.Value == nil {
+			continue
+		}
+
+		var side map[string][]*leg
+
+		switch strings.ToUpper(op.Type) {
+		case string(models.OperationTypeDebit):
+			side = debits
+		case string(models.OperationTypeCredit):
+			side = credits
+		default:
+			continue
+		}
+
+		if _, ok := debits[op.AssetCode]; !ok {
+			if _, ok := credits[op.AssetCode]; !ok {
+				assets = append(assets, op.AssetCode)
+			}
+		}
+
+		side[op.AssetCode] = append(side[op.AssetCode], &leg{op: op, amount: op.Amount.Value.Abs()})
+	}
+
+	var lines []Line
+
+	for _, asset := range assets {
+		assetLines, err := pair(tx, asset, debits[asset], credits[asset])
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, assetLines...)
+	}
+
+	return lines, nil
+}
+
+// pair matches the debits and credits of an asset in order.
+func pair(tx *models.Transaction, asset string, debits, credits []*leg) ([]Line, error) {
+	if len(debits) == 0 || len(credits) == 0 {
+		return nil, nil
+	}
+
+	var lines []Line
+
+	d, c := 0, 0
+
+	for d < len(debits) && c < len(credits) {
+		debit, credit := debits[d], credits[c]
+		amount := decimal.Min(debit.amount, credit.amount)
+
+		if amount.IsPositive() {
+			lines = append(lines, line(tx, asset, debit.op, credit.op, amount))
+		}
+
+		debit.amount = debit.amount.Sub(amount)
+		credit.amount = credit.amount.Sub(amount)
+
+		if debit.amount.IsZero() {
+			d++
+		}
+
+		if credit.amount.IsZero() {
+			c++
+		}
+	}
+
+	remaining := decimal.Zero
+	for _, l := range append(debits[d:], credits[c:]...) {
+		remaining = remaining.Add(l.amount)
+	}
+
+	if !remaining.IsZero() {
+		return nil, fmt.Errorf("%w: transaction %s, asset %s, %s left unpaired", ErrUnbalanced, tx.ID, asset, remaining)
+	}
+
+	return lines, nil
+}
+
+// line returns the journal line moving amount between two operations.
+func line(tx *models.Transaction, asset string, debit, credit models.Operation, amount decimal.Decimal) Line {
+	memo := tx.Description
+	if memo == "" {
+		memo = debit.Description
+	}
+
+	date := tx.CreatedAt
+	if date.IsZero() {
+		date = debit.CreatedAt
+	}
+
+	return Line{
+		Date:                  date,
+		TransactionID:         tx.ID,
+		DebitAccount:          accountName(debit),
+		CreditAccount:         accountName(credit),
+		Amount:                amount,
+		Asset:                 asset,
+		Memo:                  memo,
+		DebitChartOfAccounts:  debit.ChartOfAccounts,
+		CreditChartOfAccounts: credit.ChartOfAccounts,
+	}
+}
+
+// accountName returns the alias of the account of op, or its ID.
+func accountName(op models.Operation) string {
+	if op.AccountAlias != "" {
+		return op.AccountAlias
+	}
+
+	return op.AccountID
+}
+
+// WriteCSV writes lines to w as CSV, after CSVHeader. Dates are RFC 3339 in UTC.
+func WriteCSV(w io.Writer, lines []Line) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(CSVHeader); err != nil {
+		return err
+	}
+
+	for _, l := range lines {
+		err := cw.Write([]string{
+			l.Date.UTC().Format(time.RFC3339),
+			l.TransactionID,
+			l.DebitAccount,
+			l.CreditAccount,
+			l.Amount.String(),
+			l.Asset,
+			l.Memo,
+			l.DebitChartOfAccounts,
+			l.CreditChartOfAccounts,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// Builder builds the journal of a ledger from its transactions.
+type Builder struct {
+	transactions entities.TransactionsReader
+	pageSize     int
+}
+
+// NewBuilder creates a Builder reading transactions through e.
+func NewBuilder(e *entities.Entity) *Builder {
+	b := &Builder{pageSize: models.MaxLimit}
+
+	if e != nil {
+		b.transactions = e.Transactions
+	}
+
+	return b
+}
+
+// WithPageSize sets the number of transactions listed per request.
+func (b *Builder) WithPageSize(size int) *Builder {
+	if size > 0 {
+		b.pageSize = size
+	}
+
+	return b
+}
+
+// Build returns the journal lines of the transactions of a ledger listed with
+// opts, such as a date range (optional, pass nil for all of them). The
+// transactions listed without their operations are fetched one by one.
+func (b *Builder) Build(ctx context.Context, orgID, ledgerID string, opts *models.ListOptions) ([]Line, error) {
+	if b.transactions == nil {
+		return nil, errors.New("entities not initialized for journals")
+	}
+
+	page := models.NewListOptions()
+	if opts != nil {
+		copied := *opts
+		page = &copied
+	}
+
+	page.Limit = b.pageSize
+
+	var lines []Line
+
+	for {
+		resp, err := b.transactions.ListTransactions(ctx, orgID, ledgerID, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range resp.Items {
+			tx := &resp.Items[i]
+
+			if len(tx.Operations) == 0 {
+				if tx, err = b.transactions.GetTransaction(ctx, orgID, ledgerID, tx.ID); err != nil {
+					return nil, err
+				}
+			}
+
+			txLines, err := FromTransaction(tx)
+			if err != nil {
+				return nil, err
+			}
+
+			lines = append(lines, txLines...)
+		}
+
+		if len(resp.Items) == 0 || !resp.Pagination.HasNextPage() {
+			return lines, nil
+		}
+
+		// keep the filters of opts, which NextPageOptions drops
+		next := resp.Pagination.NextPageOptions()
+		page.Cursor, page.Offset = next.Cursor, next.Offset
+	}
+}
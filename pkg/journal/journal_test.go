@@ -0,0 +1,187 @@
+package journal
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var booked = time.Date(2026, 9, 1, 10, 30, 0, 0, time.UTC)
+
+func operation(typ, alias, asset, value string) models.Operation {
+	amount := decimal.RequireFromString(value)
+
+	return models.Operation{
+		Type:         typ,
+		AccountID:    "id-" + alias,
+		AccountAlias: alias,
+		AssetCode:    asset,
+		Amount:       models.Amount{Value: &amount},
+		CreatedAt:    booked,
+	}
+}
+
+func transaction(id, status string, ops ...models.Operation) models.Transaction {
+	return models.Transaction{
+		ID:          id,
+		Description: "transaction " + id,
+		Status:      models.Status{Code: status},
+		Operations:  ops,
+		CreatedAt:   booked,
+	}
+}
+
+func TestFromTransaction(t *testing.T) {
+	tx := transaction("tx-1", models.TransactionStatusCompleted,
+		operation("DEBIT", "@alice", "BRL", "70"),
+		operation("DEBIT", "@bob", "BRL", "30"),
+		operation("CREDIT", "@shop", "BRL", "60"),
+		operation("CREDIT", "@fees", "BRL", "40"),
+		operation("ON_HOLD", "@alice", "BRL", "5"),
+		operation("DEBIT", "@alice", "USD", "1.5"),
+		operation("CREDIT", "", "USD", "1.5"),
+	)
+	tx.Operations[3].ChartOfAccounts = "4100"
+
+	lines, err := FromTransaction(&tx)
+	require.NoError(t, err)
+	require.Len(t, lines, 4)
+
+	expected := [][3]string{
+		{"@alice", "@shop", "60"},
+		{"@alice", "@fees", "10"},
+		{"@bob", "@fees", "30"},
+		{"@alice", "id-", "1.5"},
+	}
+
+	for i, e := range expected {
+		assert.Equal(t, e[0], lines[i].DebitAccount, "line %d", i)
+		assert.Equal(t, e[1], lines[i].CreditAccount, "line %d", i)
+		assert.Equal(t, e[2], lines[i].Amount.String(), "line %d", i)
+		assert.Equal(t, "transaction tx-1", lines[i].Memo)
+	}
+
+	assert.Equal(t, "4100", lines[1].CreditChartOfAccounts)
+	assert.Equal(t, "USD", lines[3].Asset)
+}
+
+func TestFromTransactionSkipped(t *testing.T) {
+	for _, status := range []string{models.TransactionStatusCancelled, "CANCELED", models.TransactionStatusFailed} {
+		tx := transaction("tx", status, operation("DEBIT", "@a", "BRL", "1"), operation("CREDIT", "@b", "BRL", "1"))
+
+		lines, err := FromTransaction(&tx)
+		require.NoError(t, err)
+		assert.Empty(t, lines, status)
+	}
+
+	// A pending transaction only holds funds
+	tx := transaction("tx", models.TransactionStatusPending, operation("ON_HOLD", "@a", "BRL", "1"))
+
+	lines, err := FromTransaction(&tx)
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+}
+
+func TestFromTransactionUnbalanced(t *testing.T) {
+	tx := transaction("tx", models.TransactionStatusCompleted,
+		operation("DEBIT", "@a", "BRL", "10"),
+		operation("CREDIT", "@b", "BRL", "9"),
+	)
+
+	_, err := FromTransaction(&tx)
+	require.ErrorIs(t, err, ErrUnbalanced)
+}
+
+func TestWriteCSV(t *testing.T) {
+	lines := []Line{{
+		Date:          booked,
+		TransactionID: "tx-1",
+		DebitAccount:  "@alice",
+		CreditAccount: "@shop",
+		Amount:        decimal.RequireFromString("12.34"),
+		Asset:         "BRL",
+		Memo:          "coffee, to go",
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, lines))
+
+	assert.Equal(t, "date,transaction_id,debit_account,credit_account,amount,asset_code,memo,debit_chart_of_accounts,credit_chart_of_accounts\n"+
+		"2026-09-01T10:30:00Z,tx-1,@alice,@shop,12.34,BRL,\"coffee, to go\",,\n", buf.String())
+}
+
+// fakeTransactions pages transactions with a cursor, without their operations.
+type fakeTransactions struct {
+	entities.TransactionsService
+
+	transactions []models.Transaction
+	requests     []models.ListOptions
+}
+
+func (f *fakeTransactions) ListTransactions(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Transaction], error) {
+	f.requests = append(f.requests, *opts)
+
+	start := 0
+	if opts.Cursor != "" {
+		start, _ = strconv.Atoi(opts.Cursor)
+	}
+
+	end := min(start+opts.Limit, len(f.transactions))
+
+	resp := &models.ListResponse[models.Transaction]{Pagination: models.Pagination{Limit: opts.Limit}}
+	for _, tx := range f.transactions[start:end] {
+		tx.Operations = nil
+		resp.Items = append(resp.Items, tx)
+	}
+
+	if end < len(f.transactions) {
+		resp.Pagination.NextCursor = strconv.Itoa(end)
+	}
+
+	return resp, nil
+}
+
+func (f *fakeTransactions) GetTransaction(_ context.Context, _, _, id string) (*models.Transaction, error) {
+	for _, tx := range f.transactions {
+		if tx.ID == id {
+			return &tx, nil
+		}
+	}
+
+	return nil, assert.AnError
+}
+
+func TestBuild(t *testing.T) {
+	txs := &fakeTransactions{transactions: []models.Transaction{
+		transaction("tx-1", models.TransactionStatusCompleted, operation("DEBIT", "@a", "BRL", "1"), operation("CREDIT", "@b", "BRL", "1")),
+		transaction("tx-2", models.TransactionStatusFailed, operation("DEBIT", "@a", "BRL", "2"), operation("CREDIT", "@b", "BRL", "2")),
+		transaction("tx-3", models.TransactionStatusCompleted, operation("DEBIT", "@b", "BRL", "3"), operation("CREDIT", "@c", "BRL", "3")),
+	}}
+
+	opts := models.NewListOptions().WithDateRange("2026-09-01", "2026-09-30")
+
+	lines, err := NewBuilder(&entities.Entity{Transactions: txs}).WithPageSize(2).Build(context.Background(), "org", "ledger", opts)
+	require.NoError(t, err)
+
+	require.Len(t, lines, 2)
+	assert.Equal(t, "tx-1", lines[0].TransactionID)
+	assert.Equal(t, "tx-3", lines[1].TransactionID)
+	assert.Equal(t, "@c", lines[1].CreditAccount)
+
+	// The date range is kept on every page
+	require.Len(t, txs.requests, 2)
+	assert.Equal(t, "2026-09-01", txs.requests[1].StartDate)
+	assert.Equal(t, "2", txs.requests[1].Cursor)
+	assert.Empty(t, opts.Cursor, "the options passed are not modified")
+
+	_, err = NewBuilder(nil).Build(context.Background(), "org", "ledger", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,94 @@
+package kafkasource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// SchemaVersion is the version of the command schema read by DecodeCommand.
+const SchemaVersion = 1
+
+// Command is a transaction command, the JSON value of a Kafka message:
+//
+//	{
+//	  "schemaVersion": 1,
+//	  "commandId": "payment-8841",
+//	  "organizationId": "...",
+//	  "ledgerId": "...",
+//	  "transaction": { "send": { ... } }
+//	}
+type Command struct {
+	SchemaVersion int `json:"schemaVersion"`
+
+	// CommandID identifies the command for idempotency; when empty, the
+	// topic, partition and offset of the message are used
+	CommandID string `json:"commandId,omitempty"`
+
+	OrganizationID string                         `json:"organizationId"`
+	LedgerID       string                         `json:"ledgerId"`
+	Transaction    *models.CreateTransactionInput `json:"transaction"`
+}
+
+// DecodeCommand decodes a Command and checks it against the schema: known
+// fields only, the current schema version, an organization ID, a ledger ID
+// and a valid transaction. It returns a validation error otherwise.
+func DecodeCommand(value []byte) (*Command, error) {
+	const operation = "DecodeCommand"
+
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.DisallowUnknownFields()
+
+	var command Command
+	if err := decoder.Decode(&command); err != nil {
+		return nil, sdkerrors.NewValidationError(operation, "invalid command JSON", err)
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, sdkerrors.NewValidationError(operation, "invalid command JSON: data after the command", nil)
+	}
+
+	if command.SchemaVersion != SchemaVersion {
+		return nil, sdkerrors.NewValidationError(operation, fmt.Sprintf("unsupported schema version %d, expected %d", command.SchemaVersion, SchemaVersion), nil)
+	}
+
+	if command.OrganizationID == "" {
+		return nil, sdkerrors.NewMissingParameterError(operation, "organizationId")
+	}
+
+	if command.LedgerID == "" {
+		return nil, sdkerrors.NewMissingParameterError(operation, "ledgerId")
+	}
+
+	if command.Transaction == nil {
+		return nil, sdkerrors.NewMissingParameterError(operation, "transaction")
+	}
+
+	if err := command.Transaction.Validate(); err != nil {
+		return nil, sdkerrors.NewValidationError(operation, "invalid transaction", err)
+	}
+
+	return &command, nil
+}
+
+// IdempotencyKey returns the idempotency key of the transaction of the
+// command: its own, or one derived from the command ID.
+func (c *Command) IdempotencyKey(prefix string) string {
+	if c.Transaction != nil && c.Transaction.IdempotencyKey != "" {
+		return c.Transaction.IdempotencyKey
+	}
+
+	return prefix + "-" + c.CommandID
+}
+
+// transactionInput returns a copy of the transaction with its idempotency key set.
+func (c *Command) transactionInput(prefix string) *models.CreateTransactionInput {
+	input := *c.Transaction
+	input.IdempotencyKey = c.IdempotencyKey(prefix)
+
+	return &input
+}
@@ -0,0 +1,429 @@
+// Package kafkasource submits the transaction commands of a Kafka topic to
+// Midaz.
+//
+// The package has no Kafka client dependency: a Connector reads through the
+// Reader interface, fetching messages and committing their offsets, which
+// takes a few lines to implement over any client. With
+// github.com/segmentio/kafka-go:
+//
+//	type kafkaReader struct{ r *kafka.Reader }
+//
+//	func (k kafkaReader) FetchMessage(ctx context.Context) (kafkasource.Message, error) {
+//	    m, err := k.r.FetchMessage(ctx)
+//	    return kafkasource.Message{Topic: m.Topic, Partition: m.Partition, Offset: m.Offset, Key: m.Key, Value: m.Value}, err
+//	}
+//
+//	func (k kafkaReader) CommitMessages(ctx context.Context, msgs ...kafkasource.Message) error {
+//	    for _, m := range msgs {
+//	        if err := k.r.CommitMessages(ctx, kafka.Message{Topic: m.Topic, Partition: m.Partition, Offset: m.Offset}); err != nil {
+//	            return err
+//	        }
+//	    }
+//	    return nil
+//	}
+//
+// Each message holds a Command as JSON, checked against the command schema
+// by DecodeCommand. The Connector submits the commands in batches with
+// transaction.BatchTransactions, keyed by an idempotency key derived from the
+// command, so that a command delivered twice, such as after a crash between
+// its submission and its commit, creates a single transaction. Offsets are
+// committed only once the transactions of their messages are settled: created,
+// already created, or rejected for good by Midaz. A transient failure stops
+// the Connector before the offset of the failed message is committed, so that
+// the message is delivered again.
+package kafkasource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/transaction"
+)
+
+// Message is a Kafka message.
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+}
+
+// Reader reads the messages of a Kafka consumer group.
+type Reader interface {
+	// FetchMessage returns the next message, blocking until one is available
+	// or ctx is done.
+	FetchMessage(ctx context.Context) (Message, error)
+
+	// CommitMessages commits the offsets of msgs.
+	CommitMessages(ctx context.Context, msgs ...Message) error
+}
+
+// SubmitError is returned by Connector.Run when a command failed with a
+// transient error; its message and the following ones of its partition are
+// not committed.
+type SubmitError struct {
+	Message Message
+	Err     error
+}
+
+// Error implements the error interface.
+func (e *SubmitError) Error() string {
+	return fmt.Sprintf("submitting %s/%d@%d: %v", e.Message.Topic, e.Message.Partition, e.Message.Offset, e.Err)
+}
+
+// Unwrap returns the error of the submission.
+func (e *SubmitError) Unwrap() error {
+	return e.Err
+}
+
+// Outcome is how a command was settled.
+type Outcome string
+
+const (
+	// OutcomeCreated means the transaction was created
+	OutcomeCreated Outcome = "created"
+
+	// OutcomeDuplicate means the transaction was created by an earlier delivery
+	// of the command, as reported by an idempotency error
+	OutcomeDuplicate Outcome = "duplicate"
+
+	// OutcomeInvalid means the message is not a valid command; it is not submitted
+	OutcomeInvalid Outcome = "invalid"
+
+	// OutcomeRejected means Midaz rejected the transaction for good
+	OutcomeRejected Outcome = "rejected"
+)
+
+// Result is the settlement of a message, reported before its offset is committed.
+type Result struct {
+	Message Message
+	Outcome Outcome
+
+	// Command is nil for an invalid message
+	Command *Command
+
+	// TransactionID is the ID of the created transaction
+	TransactionID string
+
+	// Err is the decoding error of an invalid message, or the error of Midaz
+	// for a duplicate or rejected command
+	Err error
+}
+
+// Options configures a Connector.
+type Options struct {
+	// BatchSize is the maximum number of messages submitted together
+	// Default is 100 if not specified
+	BatchSize int
+	// FlushInterval is the longest time a partial batch waits for more messages
+	// Default is 1s if not specified
+	FlushInterval time.Duration
+	// Batch configures the submission of each batch; StopOnError is ignored
+	// Default is transaction.DefaultBatchOptions()
+	Batch *transaction.BatchOptions
+	// KeyPrefix prefixes the idempotency keys derived from the commands
+	// Default is "kafka" if not specified
+	KeyPrefix string
+	// Validate checks the decoded commands further, such as against allowed ledgers;
+	// an error makes the message invalid
+	Validate func(*Command) error
+	// IsPermanent reports whether a submission error rejects the command for good
+	// Default is IsPermanentError
+	IsPermanent func(error) bool
+	// OnResult receives the settlement of each message, before its offset is committed;
+	// invalid and rejected messages should be dead-lettered here
+	OnResult func(Result)
+}
+
+// DefaultOptions returns the default connector options
+func DefaultOptions() *Options {
+	return &Options{
+		BatchSize:     100,
+		FlushInterval: time.Second,
+		Batch:         transaction.DefaultBatchOptions(),
+		KeyPrefix:     "kafka",
+		IsPermanent:   IsPermanentError,
+	}
+}
+
+// IsPermanentError reports whether err rejects a transaction for good: a
+// validation, business rule or conflict error, which submitting the command
+// again can't fix.
+func IsPermanentError(err error) bool {
+	return sdkerrors.IsValidationError(err) ||
+		sdkerrors.IsInsufficientBalanceError(err) ||
+		sdkerrors.IsAccountEligibilityError(err) ||
+		sdkerrors.IsAssetMismatchError(err) ||
+		sdkerrors.IsNotFoundError(err) ||
+		sdkerrors.IsConflictError(err)
+}
+
+// Connector submits the transaction commands read from a Kafka topic.
+type Connector struct {
+	reader  Reader
+	client  *client.Client
+	options *Options
+}
+
+// New returns a Connector reading commands from reader and submitting them
+// through midazClient.
+//
+// Parameters:
+//   - reader: The Kafka reader, committing offsets of a consumer group
+//   - midazClient: The Midaz SDK client
+//   - options: Options of the Connector (optional, pass nil for defaults)
+//
+// Returns:
+//   - A Connector, started with Run
+func New(reader Reader, midazClient *client.Client, options *Options) *Connector {
+	return &Connector{reader: reader, client: midazClient, options: normalizeOptions(options)}
+}
+
+// normalizeOptions fills the unset options with their defaults.
+func normalizeOptions(options *Options) *Options {
+	defaults := DefaultOptions()
+	if options == nil {
+		return defaults
+	}
+
+	normalized := *options
+
+	if normalized.BatchSize < 1 {
+		normalized.BatchSize = defaults.BatchSize
+	}
+
+	if normalized.FlushInterval <= 0 {
+		normalized.FlushInterval = defaults.FlushInterval
+	}
+
+	if normalized.Batch == nil {
+		normalized.Batch = defaults.Batch
+	}
+
+	batch := *normalized.Batch
+	batch.StopOnError = false
+	normalized.Batch = &batch
+
+	if normalized.KeyPrefix == "" {
+		normalized.KeyPrefix = defaults.KeyPrefix
+	}
+
+	if normalized.IsPermanent == nil {
+		normalized.IsPermanent = defaults.IsPermanent
+	}
+
+	return &normalized
+}
+
+// Run consumes the topic until ctx is cancelled, returning ctx.Err(), or a
+// command fails with a transient error, returning a *SubmitError once the
+// messages settled before it are committed. Restart it to resume from the
+// last committed offsets.
+func (c *Connector) Run(ctx context.Context) error {
+	defer lifecycle.Track("kafkasource.Connector.Run")()
+
+	for {
+		messages, err := c.fetch(ctx)
+		if len(messages) > 0 {
+			if processErr := c.process(ctx, messages); processErr != nil {
+				return processErr
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// fetch returns the next batch of messages: up to BatchSize of them, or
+// those fetched within FlushInterval of the first one. The error is non-nil
+// when the reader failed or ctx is done.
+func (c *Connector) fetch(ctx context.Context) ([]Message, error) {
+	first, err := c.reader.FetchMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []Message{first}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, c.options.FlushInterval)
+	defer cancel()
+
+	for len(messages) < c.options.BatchSize {
+		message, err := c.reader.FetchMessage(fetchCtx)
+		if err != nil {
+			if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+				return messages, nil
+			}
+
+			return messages, err
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+// pendingCommand is a valid command of a batch with its message.
+type pendingCommand struct {
+	index   int
+	command *Command
+}
+
+// ledgerKey identifies the ledger of a command.
+type ledgerKey struct {
+	orgID    string
+	ledgerID string
+}
+
+// process submits the commands of messages and commits the offsets of the
+// settled ones.
+func (c *Connector) process(ctx context.Context, messages []Message) error {
+	results := make([]*Result, len(messages))
+
+	var (
+		ledgers []ledgerKey
+		pending = map[ledgerKey][]pendingCommand{}
+	)
+
+	for i, message := range messages {
+		command, err := c.decode(message)
+		if err != nil {
+			results[i] = &Result{Message: message, Outcome: OutcomeInvalid, Err: err}
+			continue
+		}
+
+		key := ledgerKey{orgID: command.OrganizationID, ledgerID: command.LedgerID}
+		if _, ok := pending[key]; !ok {
+			ledgers = append(ledgers, key)
+		}
+
+		pending[key] = append(pending[key], pendingCommand{index: i, command: command})
+	}
+
+	var (
+		failure     *SubmitError
+		failedIndex int
+	)
+
+	for _, key := range ledgers {
+		commands := pending[key]
+		inputs := make([]*models.CreateTransactionInput, len(commands))
+
+		for n, p := range commands {
+			inputs[n] = p.command.transactionInput(c.options.KeyPrefix)
+		}
+
+		batchResults, _ := transaction.BatchTransactions(ctx, c.client, key.orgID, key.ledgerID, inputs, c.options.Batch)
+
+		for n, p := range commands {
+			result := &Result{Message: messages[p.index], Command: p.command}
+
+			switch err := batchResults[n].Error; {
+			case err == nil:
+				result.Outcome = OutcomeCreated
+				result.TransactionID = batchResults[n].TransactionID
+			case sdkerrors.IsIdempotencyError(err):
+				result.Outcome = OutcomeDuplicate
+				result.Err = err
+			case c.options.IsPermanent(err):
+				result.Outcome = OutcomeRejected
+				result.Err = err
+			default:
+				if failure == nil || p.index < failedIndex {
+					failure = &SubmitError{Message: messages[p.index], Err: err}
+					failedIndex = p.index
+				}
+
+				continue
+			}
+
+			results[p.index] = result
+		}
+	}
+
+	var settled []Message
+
+	for _, i := range settledIndexes(messages, results) {
+		settled = append(settled, messages[i])
+
+		if c.options.OnResult != nil {
+			c.options.OnResult(*results[i])
+		}
+	}
+
+	if len(settled) > 0 {
+		if err := c.reader.CommitMessages(ctx, settled...); err != nil {
+			return fmt.Errorf("committing offsets: %w", err)
+		}
+	}
+
+	if failure != nil {
+		return failure
+	}
+
+	return nil
+}
+
+// decode decodes and validates the command of message.
+func (c *Connector) decode(message Message) (*Command, error) {
+	command, err := DecodeCommand(message.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	if command.CommandID == "" {
+		command.CommandID = messageID(message)
+	}
+
+	if c.options.Validate != nil {
+		if err := c.options.Validate(command); err != nil {
+			return nil, err
+		}
+	}
+
+	return command, nil
+}
+
+// settledIndexes returns the positions of the messages that can be
+// committed: in each partition, those before the first unsettled one.
+func settledIndexes(messages []Message, results []*Result) []int {
+	type partition struct {
+		topic     string
+		partition int
+	}
+
+	blocked := map[partition]bool{}
+
+	var settled []int
+
+	for i, message := range messages {
+		p := partition{topic: message.Topic, partition: message.Partition}
+		if blocked[p] {
+			continue
+		}
+
+		if results[i] == nil {
+			blocked[p] = true
+			continue
+		}
+
+		settled = append(settled, i)
+	}
+
+	return settled
+}
+
+// messageID identifies a message by its position in the topic.
+func messageID(message Message) string {
+	return fmt.Sprintf("%s-%d-%d", message.Topic, message.Partition, message.Offset)
+}
@@ -0,0 +1,240 @@
+package kafkasource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReader serves messages then blocks until the context is done.
+type fakeReader struct {
+	mu        sync.Mutex
+	messages  []Message
+	committed []Message
+	commitErr error
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (Message, error) {
+	r.mu.Lock()
+
+	if len(r.messages) > 0 {
+		message := r.messages[0]
+		r.messages = r.messages[1:]
+		r.mu.Unlock()
+
+		return message, nil
+	}
+
+	r.mu.Unlock()
+	<-ctx.Done()
+
+	return Message{}, ctx.Err()
+}
+
+func (r *fakeReader) CommitMessages(_ context.Context, msgs ...Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.commitErr != nil {
+		return r.commitErr
+	}
+
+	r.committed = append(r.committed, msgs...)
+
+	return nil
+}
+
+func (r *fakeReader) offsets() []int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var offsets []int64
+	for _, m := range r.committed {
+		offsets = append(offsets, m.Offset)
+	}
+
+	return offsets
+}
+
+// keyedTransactions fails the transactions of some idempotency keys.
+type keyedTransactions struct {
+	entities.TransactionsService
+
+	mu    sync.Mutex
+	keys  []string
+	fails map[string]error
+}
+
+func (k *keyedTransactions) CreateTransaction(_ context.Context, _, _ string, input *models.CreateTransactionInput) (*models.Transaction, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys = append(k.keys, input.IdempotencyKey)
+
+	if err := k.fails[input.IdempotencyKey]; err != nil {
+		return nil, err
+	}
+
+	return &models.Transaction{ID: "tx-" + input.IdempotencyKey}, nil
+}
+
+func commandMessage(t *testing.T, partition int, offset int64, commandID string) Message {
+	t.Helper()
+
+	value, err := json.Marshal(Command{
+		SchemaVersion:  SchemaVersion,
+		CommandID:      commandID,
+		OrganizationID: "org",
+		LedgerID:       "ledger",
+		Transaction: &models.CreateTransactionInput{Amount: "1", AssetCode: "USD", Send: &models.SendInput{
+			Asset:      "USD",
+			Value:      "1",
+			Source:     &models.SourceInput{From: []models.FromToInput{{Account: "@external/USD", Amount: models.AmountInput{Asset: "USD", Value: "1"}}}},
+			Distribute: &models.DistributeInput{To: []models.FromToInput{{Account: "@a", Amount: models.AmountInput{Asset: "USD", Value: "1"}}}},
+		}},
+	})
+	require.NoError(t, err)
+
+	return Message{Topic: "payments", Partition: partition, Offset: offset, Value: value}
+}
+
+func testOptions(results *[]Result) *Options {
+	batch := transaction.DefaultBatchOptions()
+	batch.RetryCount = 0
+
+	return &Options{
+		BatchSize:     10,
+		FlushInterval: 20 * time.Millisecond,
+		Batch:         batch,
+		OnResult:      func(r Result) { *results = append(*results, r) },
+	}
+}
+
+func TestDecodeCommand(t *testing.T) {
+	valid := commandMessage(t, 0, 0, "c-1").Value
+
+	command, err := DecodeCommand(valid)
+	require.NoError(t, err)
+	assert.Equal(t, "c-1", command.CommandID)
+	assert.Equal(t, "kafka-c-1", command.IdempotencyKey("kafka"))
+
+	tests := map[string]string{
+		"not JSON":        `{`,
+		"unknown field":   `{"schemaVersion":1,"organizationId":"org","ledgerId":"ledger","transaction":{},"extra":true}`,
+		"trailing data":   string(valid) + `{}`,
+		"wrong version":   `{"schemaVersion":2,"organizationId":"org","ledgerId":"ledger","transaction":{}}`,
+		"no organization": `{"schemaVersion":1,"ledgerId":"ledger","transaction":{}}`,
+		"no transaction":  `{"schemaVersion":1,"organizationId":"org","ledgerId":"ledger"}`,
+		"bad transaction": `{"schemaVersion":1,"organizationId":"org","ledgerId":"ledger","transaction":{}}`,
+	}
+
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := DecodeCommand([]byte(value))
+			require.Error(t, err)
+			assert.True(t, sdkerrors.IsValidationError(err))
+		})
+	}
+}
+
+func TestConnectorCommitsSettledMessages(t *testing.T) {
+	idempotent := &sdkerrors.Error{Category: sdkerrors.CategoryConflict, Code: sdkerrors.CodeIdempotency, Message: "already processed"}
+
+	txs := &keyedTransactions{fails: map[string]error{"kafka-c-3": idempotent}}
+	reader := &fakeReader{messages: []Message{
+		commandMessage(t, 0, 1, "c-1"),
+		{Topic: "payments", Partition: 0, Offset: 2, Value: []byte(`not json`)},
+		commandMessage(t, 0, 3, "c-3"),
+		commandMessage(t, 0, 4, ""),
+	}}
+
+	var results []Result
+
+	connector := New(reader, &client.Client{Entity: &entities.Entity{Transactions: txs}}, testOptions(&results))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, connector.Run(ctx), context.DeadlineExceeded)
+
+	assert.Equal(t, []int64{1, 2, 3, 4}, reader.offsets())
+	assert.ElementsMatch(t, []string{"kafka-c-1", "kafka-c-3", "kafka-payments-0-4"}, txs.keys)
+
+	require.Len(t, results, 4)
+	assert.Equal(t, OutcomeCreated, results[0].Outcome)
+	assert.Equal(t, "tx-kafka-c-1", results[0].TransactionID)
+	assert.Equal(t, OutcomeInvalid, results[1].Outcome)
+	assert.Error(t, results[1].Err)
+	assert.Equal(t, OutcomeDuplicate, results[2].Outcome)
+	assert.Equal(t, OutcomeCreated, results[3].Outcome)
+}
+
+func TestConnectorStopsOnTransientFailure(t *testing.T) {
+	txs := &keyedTransactions{fails: map[string]error{
+		"kafka-c-2": sdkerrors.NewNetworkError("CreateTransaction", errors.New("connection reset")),
+		"kafka-c-5": sdkerrors.NewInsufficientBalanceError("CreateTransaction", "@a", errors.New("no funds")),
+	}}
+	reader := &fakeReader{messages: []Message{
+		commandMessage(t, 0, 1, "c-1"),
+		commandMessage(t, 0, 2, "c-2"),
+		commandMessage(t, 0, 3, "c-3"),
+		commandMessage(t, 1, 4, "c-4"),
+		commandMessage(t, 1, 5, "c-5"),
+	}}
+
+	var results []Result
+
+	connector := New(reader, &client.Client{Entity: &entities.Entity{Transactions: txs}}, testOptions(&results))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := connector.Run(ctx)
+
+	var submitErr *SubmitError
+	require.ErrorAs(t, err, &submitErr)
+	assert.Equal(t, int64(2), submitErr.Message.Offset)
+	assert.True(t, sdkerrors.IsNetworkError(err))
+
+	// Partition 0 stops before the failed message, partition 1 goes on
+	assert.Equal(t, []int64{1, 4, 5}, reader.offsets())
+
+	require.Len(t, results, 3)
+	assert.Equal(t, OutcomeRejected, results[2].Outcome)
+	assert.True(t, sdkerrors.IsInsufficientBalanceError(results[2].Err))
+}
+
+func TestConnectorValidateAndCommitError(t *testing.T) {
+	txs := &keyedTransactions{}
+	reader := &fakeReader{messages: []Message{commandMessage(t, 0, 1, "c-1")}, commitErr: errors.New("broker down")}
+
+	var results []Result
+
+	options := testOptions(&results)
+	options.Validate = func(c *Command) error {
+		if c.LedgerID != "allowed" {
+			return errors.New("ledger not allowed")
+		}
+
+		return nil
+	}
+
+	err := New(reader, &client.Client{Entity: &entities.Entity{Transactions: txs}}, options).Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broker down")
+
+	assert.Empty(t, txs.keys)
+	require.Len(t, results, 1)
+	assert.Equal(t, OutcomeInvalid, results[0].Outcome)
+}
@@ -0,0 +1,125 @@
+package kvstore
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+)
+
+// fileSuffix ends the names of the files of a File store; temporary files
+// don't have it
+const fileSuffix = ".kv"
+
+// File is an Interface keeping each key in a file of a directory. Writes
+// replace files atomically, so a crash leaves either the old or the new
+// value. File names encode the keys, which may hold any character.
+type File struct {
+	dir string
+}
+
+// NewFile creates a File store in dir, creating the directory if needed.
+func NewFile(dir string) (*File, error) {
+	if dir == "" {
+		return nil, errors.New("kvstore: directory is required")
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("kvstore: failed to create directory: %w", err)
+	}
+
+	return &File{dir: dir}, nil
+}
+
+// Get implements Interface.
+func (f *File) Get(_ context.Context, key string) ([]byte, error) {
+	value, err := os.ReadFile(f.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("kvstore: failed to read %q: %w", key, err)
+	}
+
+	return value, nil
+}
+
+// Put implements Interface.
+func (f *File) Put(_ context.Context, key string, value []byte) error {
+	if err := fsutil.WriteFileAtomic(f.path(key), value); err != nil {
+		return fmt.Errorf("kvstore: failed to write %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// Delete implements Interface.
+func (f *File) Delete(_ context.Context, key string) error {
+	if err := os.Remove(f.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("kvstore: failed to delete %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// Scan implements Interface.
+func (f *File) Scan(ctx context.Context, prefix string, fn func(key string, value []byte) error) error {
+	files, err := os.ReadDir(f.dir)
+	if err != nil {
+		return fmt.Errorf("kvstore: failed to list %s: %w", f.dir, err)
+	}
+
+	var keys []string
+
+	for _, file := range files {
+		name, ok := strings.CutSuffix(file.Name(), fileSuffix)
+		if !ok || file.IsDir() {
+			continue
+		}
+
+		key, err := base64.RawURLEncoding.DecodeString(name)
+		if err != nil {
+			continue
+		}
+
+		if strings.HasPrefix(string(key), prefix) {
+			keys = append(keys, string(key))
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		value, err := f.Get(ctx, key)
+		if errors.Is(err, ErrNotFound) {
+			// deleted since the listing
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// path returns the file of key
+func (f *File) path(key string) string {
+	return filepath.Join(f.dir, base64.RawURLEncoding.EncodeToString([]byte(key))+fileSuffix)
+}
@@ -0,0 +1,76 @@
+// Package kvstore is the persistence abstraction shared by the stateful
+// components of the SDK: outbox entries, checkpoints of long scans and jobs,
+// and saved pagination cursors.
+//
+// An Interface stores byte values under string keys. Three implementations
+// are provided:
+//
+//   - Memory keeps the values in memory, for tests and short-lived tools
+//   - File keeps one file per key in a directory, for single-host jobs
+//   - Redis keeps the values in a Redis server, shared by several hosts
+//
+// Other backends, such as a SQL table, only need the four methods.
+//
+// Example:
+//
+//	store, err := kvstore.NewFile("./state")
+//	...
+//	progress, err := backfill.New(client.Entity).
+//	    WithCheckpointStore(store, "backfill/accounting-code").
+//	    Run(ctx, orgID, ledgerID, update)
+package kvstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned by Get for a missing key.
+var ErrNotFound = errors.New("kvstore: key not found")
+
+// Interface is a key-value store. Implementations are safe for concurrent use.
+type Interface interface {
+	// Get returns the value of key, or ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Put sets the value of key, replacing any previous one
+	Put(ctx context.Context, key string, value []byte) error
+
+	// Delete removes key; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+
+	// Scan calls fn for each key starting with prefix, in key order, and
+	// stops at the first error returned by fn
+	Scan(ctx context.Context, prefix string, fn func(key string, value []byte) error) error
+}
+
+// GetJSON decodes the JSON value of key into v. It returns false, and no
+// error, when the key is missing.
+func GetJSON(ctx context.Context, store Interface, key string, v any) (bool, error) {
+	raw, err := store.Get(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return false, fmt.Errorf("failed to decode %q: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// PutJSON stores the JSON encoding of v under key.
+func PutJSON(ctx context.Context, store Interface, key string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %q: %w", key, err)
+	}
+
+	return store.Put(ctx, key, raw)
+}
@@ -0,0 +1,304 @@
+package kvstore
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore runs the behavior shared by every implementation
+func testStore(t *testing.T, store Interface) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	_, err := store.Get(ctx, "missing")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, store.Put(ctx, "outbox/b", []byte("2")))
+	require.NoError(t, store.Put(ctx, "outbox/a", []byte("1")))
+	require.NoError(t, store.Put(ctx, "checkpoint/x*y", []byte("cp")))
+	require.NoError(t, store.Put(ctx, "outbox/a", []byte("1bis")))
+
+	value, err := store.Get(ctx, "outbox/a")
+	require.NoError(t, err)
+	assert.Equal(t, "1bis", string(value))
+
+	value, err = store.Get(ctx, "checkpoint/x*y")
+	require.NoError(t, err)
+	assert.Equal(t, "cp", string(value))
+
+	var keys []string
+
+	require.NoError(t, store.Scan(ctx, "outbox/", func(key string, value []byte) error {
+		keys = append(keys, key+"="+string(value))
+		return nil
+	}))
+	assert.Equal(t, []string{"outbox/a=1bis", "outbox/b=2"}, keys)
+
+	stop := errors.New("stop")
+	calls := 0
+
+	err = store.Scan(ctx, "", func(string, []byte) error {
+		calls++
+		return stop
+	})
+	require.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+
+	require.NoError(t, store.Delete(ctx, "outbox/a"))
+	require.NoError(t, store.Delete(ctx, "outbox/a"))
+
+	_, err = store.Get(ctx, "outbox/a")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	type state struct {
+		Offset int `json:"offset"`
+	}
+
+	found, err := GetJSON(ctx, store, "state", &state{})
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, PutJSON(ctx, store, "state", state{Offset: 42}))
+
+	var loaded state
+
+	found, err = GetJSON(ctx, store, "state", &loaded)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 42, loaded.Offset)
+}
+
+func TestMemory(t *testing.T) {
+	testStore(t, NewMemory())
+}
+
+func TestFile(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewFile(dir)
+	require.NoError(t, err)
+
+	testStore(t, store)
+
+	// values survive a new store on the same directory
+	reopened, err := NewFile(dir)
+	require.NoError(t, err)
+
+	value, err := reopened.Get(context.Background(), "checkpoint/x*y")
+	require.NoError(t, err)
+	assert.Equal(t, "cp", string(value))
+
+	_, err = NewFile("")
+	assert.Error(t, err)
+}
+
+func TestRedis(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+
+	store, err := NewRedis(RedisOptions{Addr: server.addr, Password: "secret", DB: 2, Prefix: "midaz:"})
+	require.NoError(t, err)
+
+	defer store.Close()
+
+	testStore(t, store)
+
+	server.mu.Lock()
+	assert.Contains(t, server.data, "midaz:checkpoint/x*y")
+	assert.Equal(t, "2", server.db)
+	server.mu.Unlock()
+
+	// the store reconnects after losing its connection
+	server.dropConnections()
+
+	value, err := store.Get(context.Background(), "outbox/b")
+	if err != nil {
+		value, err = store.Get(context.Background(), "outbox/b")
+	}
+
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(value))
+
+	_, err = NewRedis(RedisOptions{})
+	assert.Error(t, err)
+}
+
+func TestRedisAuthFailure(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+
+	store, err := NewRedis(RedisOptions{Addr: server.addr, Password: "wrong"})
+	require.NoError(t, err)
+
+	defer store.Close()
+
+	_, err = store.Get(context.Background(), "key")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AUTH")
+}
+
+// fakeRedis serves the commands used by Redis from memory
+type fakeRedis struct {
+	addr     string
+	password string
+
+	mu    sync.Mutex
+	data  map[string]string
+	db    string
+	conns []net.Conn
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	f := &fakeRedis{addr: listener.Addr().String(), password: password, data: map[string]string{}}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			f.mu.Lock()
+			f.conns = append(f.conns, conn)
+			f.mu.Unlock()
+
+			go f.serve(conn)
+		}
+	}()
+
+	return f
+}
+
+func (f *fakeRedis) dropConnections() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, conn := range f.conns {
+		_ = conn.Close()
+	}
+
+	f.conns = nil
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	authenticated := f.password == ""
+
+	for {
+		reply, err := readReply(reader)
+		if err != nil {
+			return
+		}
+
+		items, _ := reply.([]any)
+		args := make([]string, len(items))
+
+		for i, item := range items {
+			b, _ := item.([]byte)
+			args[i] = string(b)
+		}
+
+		var out string
+
+		switch {
+		case len(args) == 0:
+			out = "-ERR empty command\r\n"
+		case args[0] == "AUTH":
+			authenticated = args[1] == f.password
+			if authenticated {
+				out = "+OK\r\n"
+			} else {
+				out = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			out = "-NOAUTH authentication required\r\n"
+		default:
+			out = f.exec(args)
+		}
+
+		if _, err := conn.Write([]byte(out)); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) exec(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch args[0] {
+	case "SELECT":
+		f.db = args[1]
+		return "+OK\r\n"
+	case "GET":
+		value, ok := f.data[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+
+		return bulk(value)
+	case "SET":
+		f.data[args[1]] = args[2]
+		return "+OK\r\n"
+	case "DEL":
+		_, ok := f.data[args[1]]
+		delete(f.data, args[1])
+
+		if ok {
+			return ":1\r\n"
+		}
+
+		return ":0\r\n"
+	case "SCAN":
+		// one key per call, to exercise the cursor
+		var keys []string
+
+		// patterns are an escaped prefix followed by *
+		prefix := strings.NewReplacer(`\*`, "*", `\?`, "?", `\[`, "[", `\]`, "]", `\\`, `\`).
+			Replace(strings.TrimSuffix(args[3], "*"))
+
+		for key := range f.data {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+
+		sort.Strings(keys)
+
+		cursor, _ := strconv.Atoi(args[1])
+		if cursor >= len(keys) {
+			return "*2\r\n" + bulk("0") + "*0\r\n"
+		}
+
+		next := strconv.Itoa(cursor + 1)
+		if cursor+1 >= len(keys) {
+			next = "0"
+		}
+
+		return "*2\r\n" + bulk(next) + "*1\r\n" + bulk(keys[cursor])
+	default:
+		return "-ERR unknown command " + strings.ToLower(args[0]) + "\r\n"
+	}
+}
+
+func bulk(s string) string {
+	return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+}
@@ -0,0 +1,83 @@
+package kvstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Memory is an Interface kept in memory.
+type Memory struct {
+	mu     sync.RWMutex
+	values map[string][]byte
+}
+
+// NewMemory creates an empty Memory store.
+func NewMemory() *Memory {
+	return &Memory{values: map[string][]byte{}}
+}
+
+// Get implements Interface.
+func (m *Memory) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	value, ok := m.values[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return append([]byte(nil), value...), nil
+}
+
+// Put implements Interface.
+func (m *Memory) Put(_ context.Context, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[key] = append([]byte(nil), value...)
+
+	return nil
+}
+
+// Delete implements Interface.
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.values, key)
+
+	return nil
+}
+
+// Scan implements Interface. fn runs on a snapshot, so it may modify the store.
+func (m *Memory) Scan(ctx context.Context, prefix string, fn func(key string, value []byte) error) error {
+	m.mu.RLock()
+
+	keys := make([]string, 0, len(m.values))
+	values := make(map[string][]byte)
+
+	for key, value := range m.values {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+			values[key] = append([]byte(nil), value...)
+		}
+	}
+
+	m.mu.RUnlock()
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(key, values[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,337 @@
+package kvstore
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRedisDialTimeout bounds the connection to a Redis server when the
+// context has no deadline.
+const DefaultRedisDialTimeout = 5 * time.Second
+
+// RedisOptions configures a Redis store.
+type RedisOptions struct {
+	// Addr is the host:port of the server
+	Addr string
+
+	// Password authenticates the connection when set
+	Password string
+
+	// DB selects the database number
+	DB int
+
+	// Prefix namespaces the keys of the store, such as "midaz:"
+	Prefix string
+
+	// DialTimeout bounds the connection; DefaultRedisDialTimeout when zero
+	DialTimeout time.Duration
+}
+
+// Redis is an Interface kept in a Redis server. It speaks the Redis protocol
+// over a single connection, serialized and reopened after a failure, which
+// suits the low rate of checkpoint and outbox writes without a client
+// library dependency.
+type Redis struct {
+	opts RedisOptions
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedis creates a Redis store. The connection is opened on first use.
+func NewRedis(opts RedisOptions) (*Redis, error) {
+	if opts.Addr == "" {
+		return nil, errors.New("kvstore: redis address is required")
+	}
+
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = DefaultRedisDialTimeout
+	}
+
+	return &Redis{opts: opts}, nil
+}
+
+// Close closes the connection to the server.
+func (r *Redis) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn == nil {
+		return nil
+	}
+
+	err := r.conn.Close()
+	r.conn, r.reader = nil, nil
+
+	return err
+}
+
+// Get implements Interface.
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := r.do(ctx, "GET", r.opts.Prefix+key)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply == nil {
+		return nil, ErrNotFound
+	}
+
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("kvstore: unexpected redis reply %T to GET", reply)
+	}
+
+	return value, nil
+}
+
+// Put implements Interface.
+func (r *Redis) Put(ctx context.Context, key string, value []byte) error {
+	_, err := r.do(ctx, "SET", r.opts.Prefix+key, string(value))
+	return err
+}
+
+// Delete implements Interface.
+func (r *Redis) Delete(ctx context.Context, key string) error {
+	_, err := r.do(ctx, "DEL", r.opts.Prefix+key)
+	return err
+}
+
+// Scan implements Interface. Keys are listed with SCAN, so the store may be
+// written meanwhile; a key deleted during the scan is skipped.
+func (r *Redis) Scan(ctx context.Context, prefix string, fn func(key string, value []byte) error) error {
+	pattern := escapeGlob(r.opts.Prefix+prefix) + "*"
+	seen := map[string]struct{}{}
+	cursor := "0"
+
+	for {
+		reply, err := r.do(ctx, "SCAN", cursor, "MATCH", pattern, "COUNT", "100")
+		if err != nil {
+			return err
+		}
+
+		parts, ok := reply.([]any)
+		if !ok || len(parts) != 2 {
+			return fmt.Errorf("kvstore: unexpected redis reply %T to SCAN", reply)
+		}
+
+		next, _ := parts[0].([]byte)
+		keys, _ := parts[1].([]any)
+
+		for _, k := range keys {
+			if name, ok := k.([]byte); ok {
+				seen[strings.TrimPrefix(string(name), r.opts.Prefix)] = struct{}{}
+			}
+		}
+
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			break
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, err := r.Get(ctx, key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// do sends a command and reads its reply: nil, []byte, int64 or []any
+func (r *Redis) do(ctx context.Context, args ...string) (any, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if r.conn == nil {
+		if err := r.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := r.roundTrip(ctx, args)
+	if err != nil {
+		var redisErr redisError
+		if !errors.As(err, &redisErr) {
+			// the connection state is unknown; reopen it on next use
+			_ = r.conn.Close()
+			r.conn, r.reader = nil, nil
+		}
+
+		return nil, fmt.Errorf("kvstore: redis %s: %w", args[0], err)
+	}
+
+	return reply, nil
+}
+
+// connect opens the connection and authenticates it
+func (r *Redis) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: r.opts.DialTimeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", r.opts.Addr)
+	if err != nil {
+		return fmt.Errorf("kvstore: failed to connect to redis: %w", err)
+	}
+
+	r.conn, r.reader = conn, bufio.NewReader(conn)
+
+	var setup [][]string
+	if r.opts.Password != "" {
+		setup = append(setup, []string{"AUTH", r.opts.Password})
+	}
+
+	if r.opts.DB != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(r.opts.DB)})
+	}
+
+	for _, args := range setup {
+		if _, err := r.roundTrip(ctx, args); err != nil {
+			_ = conn.Close()
+			r.conn, r.reader = nil, nil
+
+			return fmt.Errorf("kvstore: redis %s: %w", args[0], err)
+		}
+	}
+
+	return nil
+}
+
+// roundTrip writes a command on the connection and reads its reply
+func (r *Redis) roundTrip(ctx context.Context, args []string) (any, error) {
+	// no deadline clears the one of the previous command
+	deadline, _ := ctx.Deadline()
+
+	if err := r.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	if _, err := io.WriteString(r.conn, b.String()); err != nil {
+		return nil, err
+	}
+
+	return readReply(r.reader)
+}
+
+// redisError is an error reply of the server; the connection stays usable
+type redisError string
+
+// Error implements the error interface
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// readReply reads a reply of the Redis protocol
+func readReply(reader *bufio.Reader) (any, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty redis reply")
+	}
+
+	kind, payload := line[0], line[1:]
+
+	switch kind {
+	case '+':
+		return []byte(payload), nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		if n < 0 {
+			return nil, nil //nolint:nilnil // a missing value
+		}
+
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		if n < 0 {
+			return nil, nil //nolint:nilnil // a missing array
+		}
+
+		items := make([]any, n)
+
+		for i := range items {
+			if items[i], err = readReply(reader); err != nil {
+				return nil, err
+			}
+		}
+
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unexpected redis reply %q", line)
+	}
+}
+
+// escapeGlob escapes the characters special to the MATCH pattern of SCAN
+func escapeGlob(s string) string {
+	var b strings.Builder
+
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,105 @@
+// Package lifecycle keeps track of the background goroutines started by the
+// SDK, so that services embedding it can check that nothing is left running
+// after shutdown.
+//
+// Each component of the SDK running a goroutine beyond the call that started
+// it registers a Task with Track for as long as the goroutine runs, and
+// offers a Close or Stop method ending it: rate limiters, spinners, the
+// ingestor, the shadow requests and balance loads of an Entity, and the
+// watchers and pollers run with go, like MaintenanceSchedule.Watch.
+//
+//	defer func() {
+//	    if tasks := client.ActiveBackgroundTasks(); len(tasks) > 0 {
+//	        log.Printf("background tasks left running: %v", tasks)
+//	    }
+//	}()
+package lifecycle
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Task is a background goroutine of the SDK.
+type Task struct {
+	// ID tells apart the tasks of the same name
+	ID uint64 `json:"id"`
+
+	// Name is the component running the task, e.g. "concurrent.RateLimiter"
+	Name string `json:"name"`
+
+	// Started is when the task was registered
+	Started time.Time `json:"started"`
+}
+
+// String returns the name and ID of the task.
+func (t Task) String() string {
+	return fmt.Sprintf("%s#%d", t.Name, t.ID)
+}
+
+var (
+	mu     sync.Mutex
+	tasks  = make(map[uint64]Task)
+	nextID atomic.Uint64
+)
+
+// Track registers a running task named name and returns the function to call
+// when it ends. The returned function may be called more than once.
+//
+//	done := lifecycle.Track("concurrent.RateLimiter")
+//	go func() {
+//	    defer done()
+//	    ...
+//	}()
+func Track(name string) (done func()) {
+	task := Task{ID: nextID.Add(1), Name: name, Started: time.Now()}
+
+	mu.Lock()
+	tasks[task.ID] = task
+	mu.Unlock()
+
+	return func() {
+		mu.Lock()
+		delete(tasks, task.ID)
+		mu.Unlock()
+	}
+}
+
+// Active returns the tasks running, oldest first.
+func Active() []Task {
+	mu.Lock()
+	active := slices.Collect(maps.Values(tasks))
+	mu.Unlock()
+
+	slices.SortFunc(active, func(a, b Task) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return active
+}
+
+// Count returns the number of tasks running with the given name, or of all
+// tasks when name is empty.
+func Count(name string) int {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if name == "" {
+		return len(tasks)
+	}
+
+	n := 0
+
+	for _, task := range tasks {
+		if task.Name == name {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,36 @@
+package lifecycle
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrack(t *testing.T) {
+	base := Count("")
+
+	doneA := Track("test.A")
+	doneB := Track("test.B")
+	doneA2 := Track("test.A")
+
+	assert.Equal(t, base+3, Count(""))
+	assert.Equal(t, 2, Count("test.A"))
+
+	active := Active()
+	require.Len(t, active, base+3)
+
+	last := active[len(active)-3:]
+	assert.Equal(t, []string{"test.A", "test.B", "test.A"}, []string{last[0].Name, last[1].Name, last[2].Name}, "oldest first")
+	assert.NotEqual(t, last[0].ID, last[2].ID)
+	assert.Equal(t, "test.B#"+strconv.FormatUint(last[1].ID, 10), last[1].String())
+
+	doneA()
+	doneA()
+	assert.Equal(t, 1, Count("test.A"), "done may be called again")
+
+	doneB()
+	doneA2()
+	assert.Equal(t, base, Count(""))
+}
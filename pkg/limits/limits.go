@@ -0,0 +1,435 @@
+// Package limits tracks the debit volume of accounts against daily and
+// monthly limits, such as spending budgets, and raises events when an account
+// nears or exceeds its limit.
+//
+// A Tracker adds up the debit operations of each limited account over the
+// current day or month. It is fed with operations, either loaded from the
+// ledger when it starts or observed as they happen, such as from an event
+// stream or after each transaction. Operations are counted once, so the same
+// operation can be loaded and observed.
+//
+// Events are raised when the volume of a window crosses the warning ratio of
+// a limit (near limit) and the limit itself (over limit), once per window,
+// through the OnEvent callback and the midaz.sdk.limit.events metric.
+//
+// Example:
+//
+//	tracker, err := limits.NewTracker(
+//	    limits.Limit{AccountID: accountID, Asset: "BRL", Period: limits.Daily, Amount: decimal.NewFromInt(5000)},
+//	    limits.Limit{AccountID: accountID, Asset: "BRL", Period: limits.Monthly, Amount: decimal.NewFromInt(50000)},
+//	)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	tracker.OnEvent(func(e limits.Event) {
+//	    log.Printf("%s %s limit of %s: %s used of %s", e.State, e.Limit.Period, e.Limit.AccountID, e.Used, e.Limit.Amount)
+//	})
+//
+//	_, err = tracker.Load(ctx, client.Entity, orgID, ledgerID, time.Now())
+//	...
+//	tracker.Observe(operations...)
+package limits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Period is the window a limit applies to.
+type Period string
+
+// Supported periods.
+const (
+	Daily   Period = "daily"
+	Monthly Period = "monthly"
+)
+
+// State is the position of the volume of a window against its limit.
+type State string
+
+// States of a limit.
+const (
+	StateOK        State = "ok"
+	StateNearLimit State = "near_limit"
+	StateOverLimit State = "over_limit"
+)
+
+// DefaultWarnRatio is the share of a limit from which the volume is near it.
+var DefaultWarnRatio = decimal.RequireFromString("0.8")
+
+// Metric and attribute names of the events.
+const (
+	MetricEvents = "midaz.sdk.limit.events"
+
+	KeyPeriod = "midaz.limit.period"
+	KeyState  = "midaz.limit.state"
+	KeyAsset  = "midaz.limit.asset"
+)
+
+// Limit caps the debit volume of an account in an asset over a period.
+type Limit struct {
+	AccountID string          `json:"accountId"`
+	Asset     string          `json:"asset"`
+	Period    Period          `json:"period"`
+	Amount    decimal.Decimal `json:"amount"`
+
+	// WarnRatio is the share of Amount from which the volume is near the
+	// limit, the tracker's warning ratio when zero
+	WarnRatio decimal.Decimal `json:"warnRatio,omitzero"`
+}
+
+// Event reports that the volume of a window reached a state.
+type Event struct {
+	Limit Limit `json:"limit"`
+	State State `json:"state"`
+
+	// WindowStart is the start of the day or month of the volume
+	WindowStart time.Time       `json:"windowStart"`
+	Used        decimal.Decimal `json:"used"`
+
+	// OperationID is the operation that crossed the threshold
+	OperationID string    `json:"operationId"`
+	Time        time.Time `json:"time"`
+}
+
+// Usage is the volume of a limit in its current window.
+type Usage struct {
+	Limit       Limit           `json:"limit"`
+	WindowStart time.Time       `json:"windowStart"`
+	Used        decimal.Decimal `json:"used"`
+	Remaining   decimal.Decimal `json:"remaining"`
+	State       State           `json:"state"`
+}
+
+// window is the volume of a limit in a window.
+type window struct {
+	start time.Time
+	used  decimal.Decimal
+	state State
+	seen  map[string]struct{}
+}
+
+// Tracker tracks the debit volume of accounts against their limits. It is
+// safe for concurrent use.
+type Tracker struct {
+	mu        sync.Mutex
+	limits    []Limit
+	windows   []window
+	warnRatio decimal.Decimal
+	location  *time.Location
+	pageSize  int
+	onEvent   func(Event)
+	events    metric.Float64Counter
+}
+
+// NewTracker creates a Tracker of limits, with days and months in UTC. It
+// returns an error for invalid limits.
+func NewTracker(limits ...Limit) (*Tracker, error) {
+	var errs []error
+
+	for i, l := range limits {
+		switch {
+		case l.AccountID == "":
+			errs = append(errs, fmt.Errorf("limit %d: account ID is required", i+1))
+		case l.Asset == "":
+			errs = append(errs, fmt.Errorf("limit %d: asset is required", i+1))
+		case l.Period != Daily && l.Period != Monthly:
+			errs = append(errs, fmt.Errorf("limit %d: unsupported period %q", i+1, l.Period))
+		case !l.Amount.IsPositive():
+			errs = append(errs, fmt.Errorf("limit %d: amount must be positive", i+1))
+		case l.WarnRatio.IsNegative() || l.WarnRatio.GreaterThan(decimal.NewFromInt(1)):
+			errs = append(errs, fmt.Errorf("limit %d: warning ratio must be between 0 and 1", i+1))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid limits: %w", errors.Join(errs...))
+	}
+
+	return &Tracker{
+		limits:    limits,
+		windows:   make([]window, len(limits)),
+		warnRatio: DefaultWarnRatio,
+		location:  time.UTC,
+		pageSize:  models.MaxLimit,
+	}, nil
+}
+
+// WithWarnRatio sets the share of a limit from which the volume is near it,
+// for the limits without their own.
+func (t *Tracker) WithWarnRatio(ratio decimal.Decimal) *Tracker {
+	if ratio.IsPositive() && ratio.LessThanOrEqual(decimal.NewFromInt(1)) {
+		t.warnRatio = ratio
+	}
+
+	return t
+}
+
+// WithLocation sets the time zone of the days and months.
+func (t *Tracker) WithLocation(location *time.Location) *Tracker {
+	if location != nil {
+		t.location = location
+	}
+
+	return t
+}
+
+// WithPageSize sets the number of operations listed per request by Load.
+func (t *Tracker) WithPageSize(size int) *Tracker {
+	if size > 0 {
+		t.pageSize = size
+	}
+
+	return t
+}
+
+// WithObservability counts the events in the MetricEvents counter of obs,
+// by period, state and asset.
+func (t *Tracker) WithObservability(obs observability.Provider) *Tracker {
+	if obs == nil || !obs.IsEnabled() {
+		return t
+	}
+
+	counter, err := obs.Meter().Float64Counter(MetricEvents,
+		metric.WithDescription("Number of accounts reaching a near-limit or over-limit state"))
+	if err == nil {
+		t.events = counter
+	}
+
+	return t
+}
+
+// OnEvent sets the function called with each event. It is called while the
+// tracker is locked, so it must not call the tracker.
+func (t *Tracker) OnEvent(fn func(Event)) *Tracker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onEvent = fn
+
+	return t
+}
+
+// Observe adds the debit operations of the limited accounts to their volume
+// and returns the events they raise. Operations before the current window of
+// a limit, and operations already counted, are ignored.
+func (t *Tracker) Observe(operations ...models.Operation) []Event {
+	return t.observe(time.Time{}, operations)
+}
+
+// observe adds operations, leaving out of each limit those before its window
+// at the time at, when set.
+func (t *Tracker) observe(at time.Time, operations []models.Operation) []Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var events []Event
+
+	for _, op := range operations {
+		if models.OperationType(op.Type) != models.OperationTypeDebit || op.Amount.Value == nil {
+			continue
+		}
+
+		for i, l := range t.limits {
+			if l.AccountID != op.AccountID || l.Asset != op.AssetCode {
+				continue
+			}
+
+			if !at.IsZero() && op.CreatedAt.Before(t.windowStart(l.Period, at)) {
+				continue
+			}
+
+			if e, ok := t.add(i, op); ok {
+				events = append(events, e)
+				t.emit(e)
+			}
+		}
+	}
+
+	return events
+}
+
+// add adds op to the volume of limit i, returning the event it raises.
+func (t *Tracker) add(i int, op models.Operation) (Event, bool) {
+	l, w := t.limits[i], &t.windows[i]
+
+	start := t.windowStart(l.Period, op.CreatedAt)
+
+	switch {
+	case w.start.IsZero() || start.After(w.start):
+		*w = window{start: start, state: StateOK, seen: map[string]struct{}{}}
+	case start.Before(w.start):
+		return Event{}, false
+	}
+
+	if op.ID != "" {
+		if _, ok := w.seen[op.ID]; ok {
+			return Event{}, false
+		}
+
+		w.seen[op.ID] = struct{}{}
+	}
+
+	w.used = w.used.Add(op.Amount.Value.Abs())
+
+	state := t.state(l, w.used)
+	if state == w.state || state == StateOK {
+		return Event{}, false
+	}
+
+	w.state = state
+
+	return Event{Limit: l, State: state, WindowStart: w.start, Used: w.used, OperationID: op.ID, Time: op.CreatedAt}, true
+}
+
+// state returns the state of used against limit l.
+func (t *Tracker) state(l Limit, used decimal.Decimal) State {
+	ratio := l.WarnRatio
+	if ratio.IsZero() {
+		ratio = t.warnRatio
+	}
+
+	switch {
+	case used.GreaterThan(l.Amount):
+		return StateOverLimit
+	case used.GreaterThanOrEqual(l.Amount.Mul(ratio)):
+		return StateNearLimit
+	default:
+		return StateOK
+	}
+}
+
+// emit reports an event to the callback and the metrics.
+func (t *Tracker) emit(e Event) {
+	if t.onEvent != nil {
+		t.onEvent(e)
+	}
+
+	if t.events != nil {
+		t.events.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String(KeyPeriod, string(e.Limit.Period)),
+			attribute.String(KeyState, string(e.State)),
+			attribute.String(KeyAsset, e.Limit.Asset),
+		))
+	}
+}
+
+// windowStart returns the start of the day or month of at.
+func (t *Tracker) windowStart(period Period, at time.Time) time.Time {
+	at = at.In(t.location)
+
+	if period == Monthly {
+		return time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, t.location)
+	}
+
+	return time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, t.location)
+}
+
+// Usage returns the volume of every limit in its window at the time at, in
+// the order of the limits.
+func (t *Tracker) Usage(at time.Time) []Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := make([]Usage, len(t.limits))
+
+	for i, l := range t.limits {
+		u := Usage{Limit: l, WindowStart: t.windowStart(l.Period, at), State: StateOK}
+
+		if w := t.windows[i]; w.start.Equal(u.WindowStart) {
+			u.Used, u.State = w.used, t.state(l, w.used)
+		}
+
+		u.Remaining = decimal.Max(l.Amount.Sub(u.Used), decimal.Zero)
+		usage[i] = u
+	}
+
+	return usage
+}
+
+// Load lists the operations of the limited accounts in the current windows
+// at the time at and observes them, oldest first, returning the events they
+// raise.
+func (t *Tracker) Load(ctx context.Context, e *entities.Entity, orgID, ledgerID string, at time.Time) ([]Event, error) {
+	if e == nil || e.Operations == nil {
+		return nil, errors.New("entities not initialized for limits")
+	}
+
+	from := map[string]time.Time{}
+
+	for _, l := range t.limits {
+		start := t.windowStart(l.Period, at)
+		if current, ok := from[l.AccountID]; !ok || start.Before(current) {
+			from[l.AccountID] = start
+		}
+	}
+
+	accountIDs := make([]string, 0, len(from))
+	for accountID := range from {
+		accountIDs = append(accountIDs, accountID)
+	}
+
+	sort.Strings(accountIDs)
+
+	var events []Event
+
+	for _, accountID := range accountIDs {
+		operations, err := t.listOperations(ctx, e.Operations, orgID, ledgerID, accountID, from[accountID], at)
+		if err != nil {
+			return events, fmt.Errorf("listing operations of account %s: %w", accountID, err)
+		}
+
+		events = append(events, t.observe(at, operations)...)
+	}
+
+	return events, nil
+}
+
+// listOperations lists the operations of an account created in [from, to], oldest first.
+func (t *Tracker) listOperations(ctx context.Context, svc entities.OperationsReader, orgID, ledgerID, accountID string, from, to time.Time) ([]models.Operation, error) {
+	// the date range of the API is in UTC days: list the days covering the
+	// windows, then keep the operations within them
+	startDate := from.UTC().Format("2006-01-02")
+	endDate := to.UTC().Format("2006-01-02")
+
+	var operations []models.Operation
+
+	opts := models.NewListOptions().WithLimit(t.pageSize)
+
+	for opts != nil {
+		page, err := svc.ListOperations(ctx, orgID, ledgerID, accountID, opts.WithDateRange(startDate, endDate))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, op := range page.Items {
+			if !op.CreatedAt.Before(from) && !op.CreatedAt.After(to) {
+				operations = append(operations, op)
+			}
+		}
+
+		if len(page.Items) == 0 {
+			break
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	sort.SliceStable(operations, func(i, j int) bool {
+		return operations[i].CreatedAt.Before(operations[j].CreatedAt)
+	})
+
+	return operations, nil
+}
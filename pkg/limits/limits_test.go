@@ -0,0 +1,182 @@
+package limits
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func debit(id, accountID, value, at string) models.Operation {
+	amount := decimal.RequireFromString(value)
+
+	createdAt, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		panic(err)
+	}
+
+	return models.Operation{
+		ID:        id,
+		Type:      string(models.OperationTypeDebit),
+		AccountID: accountID,
+		AssetCode: "BRL",
+		Amount:    models.Amount{Value: &amount},
+		CreatedAt: createdAt,
+	}
+}
+
+func testTracker(t *testing.T) *Tracker {
+	t.Helper()
+
+	tracker, err := NewTracker(
+		Limit{AccountID: "acc", Asset: "BRL", Period: Daily, Amount: decimal.NewFromInt(100)},
+		Limit{AccountID: "acc", Asset: "BRL", Period: Monthly, Amount: decimal.NewFromInt(1000), WarnRatio: decimal.RequireFromString("0.5")},
+	)
+	require.NoError(t, err)
+
+	return tracker
+}
+
+func TestObserve(t *testing.T) {
+	tracker := testTracker(t)
+
+	var received []Event
+	tracker.OnEvent(func(e Event) { received = append(received, e) })
+
+	credit := debit("c1", "acc", "500", "2026-09-01T09:00:00Z")
+	credit.Type = string(models.OperationTypeCredit)
+
+	events := tracker.Observe(
+		credit,
+		debit("o1", "acc", "70", "2026-09-01T10:00:00Z"),
+		debit("o2", "other", "500", "2026-09-01T10:00:00Z"),
+		debit("o3", "acc", "-10", "2026-09-01T11:00:00Z"),
+		debit("o3", "acc", "-10", "2026-09-01T11:00:00Z"),
+		debit("o4", "acc", "25", "2026-09-01T12:00:00Z"),
+	)
+
+	require.Len(t, events, 2)
+	assert.Equal(t, events, received)
+
+	assert.Equal(t, Daily, events[0].Limit.Period)
+	assert.Equal(t, StateNearLimit, events[0].State)
+	assert.Equal(t, "80", events[0].Used.String())
+	assert.Equal(t, "o3", events[0].OperationID)
+
+	assert.Equal(t, StateOverLimit, events[1].State)
+	assert.Equal(t, "105", events[1].Used.String())
+	assert.Equal(t, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), events[1].WindowStart)
+
+	// A new day starts a new daily window, while the month keeps adding up
+	events = tracker.Observe(
+		debit("o5", "acc", "400", "2026-09-02T08:00:00Z"),
+		debit("o0", "acc", "1", "2026-09-01T23:00:00Z"),
+	)
+	require.Len(t, events, 2)
+	assert.Equal(t, Daily, events[0].Limit.Period)
+	assert.Equal(t, StateOverLimit, events[0].State)
+	assert.Equal(t, Monthly, events[1].Limit.Period)
+	assert.Equal(t, StateNearLimit, events[1].State)
+	assert.Equal(t, "505", events[1].Used.String())
+
+	usage := tracker.Usage(time.Date(2026, 9, 2, 18, 0, 0, 0, time.UTC))
+	assert.Equal(t, "400", usage[0].Used.String())
+	assert.Equal(t, "0", usage[0].Remaining.String())
+	assert.Equal(t, "506", usage[1].Used.String(), "o0 is too late for its day, not for its month")
+
+	usage = tracker.Usage(time.Date(2026, 9, 3, 0, 0, 0, 0, time.UTC))
+	assert.True(t, usage[0].Used.IsZero())
+	assert.Equal(t, StateOK, usage[0].State)
+}
+
+func TestLocation(t *testing.T) {
+	tracker := testTracker(t)
+	tracker.WithLocation(time.FixedZone("BRT", -3*60*60))
+
+	// 01:00 UTC is still the previous day in Brasília
+	tracker.Observe(debit("o1", "acc", "90", "2026-09-02T01:00:00Z"))
+
+	usage := tracker.Usage(time.Date(2026, 9, 1, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, "90", usage[0].Used.String())
+}
+
+func TestNewTrackerErrors(t *testing.T) {
+	_, err := NewTracker(
+		Limit{Asset: "BRL", Period: Daily, Amount: decimal.NewFromInt(1)},
+		Limit{AccountID: "acc", Asset: "BRL", Period: "weekly", Amount: decimal.NewFromInt(1)},
+		Limit{AccountID: "acc", Asset: "BRL", Period: Daily},
+		Limit{AccountID: "acc", Asset: "BRL", Period: Daily, Amount: decimal.NewFromInt(1), WarnRatio: decimal.NewFromInt(2)},
+	)
+	require.Error(t, err)
+
+	for _, expected := range []string{"limit 1: account ID", "limit 2: unsupported period", "limit 3: amount", "limit 4: warning ratio"} {
+		assert.Contains(t, err.Error(), expected)
+	}
+}
+
+type fakeOperations struct {
+	entities.OperationsService
+
+	operations []models.Operation
+	requests   []models.ListOptions
+}
+
+func (f *fakeOperations) ListOperations(_ context.Context, _, _, accountID string, opts *models.ListOptions) (*models.ListResponse[models.Operation], error) {
+	f.requests = append(f.requests, *opts)
+
+	var ops []models.Operation
+
+	for _, op := range f.operations {
+		if op.AccountID == accountID {
+			ops = append(ops, op)
+		}
+	}
+
+	start := min(opts.Offset, len(ops))
+	end := min(start+opts.Limit, len(ops))
+
+	return &models.ListResponse[models.Operation]{
+		Items:      ops[start:end],
+		Pagination: models.Pagination{Limit: opts.Limit, Offset: start, Total: len(ops)},
+	}, nil
+}
+
+func TestLoad(t *testing.T) {
+	ops := &fakeOperations{operations: []models.Operation{
+		debit("o3", "acc", "90", "2026-09-15T10:00:00Z"),
+		debit("o1", "acc", "300", "2026-09-01T10:00:00Z"),
+		debit("o2", "acc", "300", "2026-09-14T10:00:00Z"),
+		debit("o4", "acc", "1", "2026-09-15T18:00:00Z"),
+	}}
+
+	tracker := testTracker(t).WithPageSize(3)
+	at := time.Date(2026, 9, 15, 12, 0, 0, 0, time.UTC)
+
+	events, err := tracker.Load(context.Background(), &entities.Entity{Operations: ops}, "org", "ledger", at)
+	require.NoError(t, err)
+
+	require.Len(t, events, 2)
+	assert.Equal(t, Monthly, events[0].Limit.Period)
+	assert.Equal(t, "o2", events[0].OperationID)
+	assert.Equal(t, Daily, events[1].Limit.Period)
+	assert.Equal(t, StateNearLimit, events[1].State)
+
+	require.Len(t, ops.requests, 2)
+	assert.Equal(t, "2026-09-01", ops.requests[0].StartDate)
+	assert.Equal(t, "2026-09-15", ops.requests[1].EndDate)
+
+	usage := tracker.Usage(at)
+	assert.Equal(t, "90", usage[0].Used.String(), "operations after the load time are left out")
+	assert.Equal(t, "690", usage[1].Used.String())
+
+	// Observing a loaded operation again doesn't count it twice
+	assert.Empty(t, tracker.Observe(ops.operations[0]))
+
+	_, err = tracker.Load(context.Background(), nil, "org", "ledger", at)
+	require.Error(t, err)
+}
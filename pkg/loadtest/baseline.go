@@ -0,0 +1,233 @@
+// Package loadtest compares load test runs against a stored baseline so CI
+// pipelines can fail on performance regressions.
+//
+// A baseline records the throughput, error rate and p95 latency of a reference
+// run. AssertBaseline measures the batch results of a new run and returns a
+// *RegressionError listing every metric that degraded beyond its tolerance:
+//
+//	results, _ := transaction.BatchTransactions(ctx, c, orgID, ledgerID, inputs, nil)
+//
+//	err := loadtest.AssertBaseline(results, "perf/baseline.json", loadtest.DefaultTolerances())
+//	if err != nil {
+//	    fmt.Fprintln(os.Stderr, err)
+//	}
+//
+//	os.Exit(loadtest.ExitCode(err))
+package loadtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/transaction"
+)
+
+// Exit codes returned by ExitCode.
+const (
+	ExitOK         = 0
+	ExitRegression = 1
+	ExitError      = 2
+)
+
+// ErrRegression matches any *RegressionError with errors.Is.
+var ErrRegression = errors.New("performance regression")
+
+// Measurement holds the metrics compared against a baseline.
+type Measurement struct {
+	// TPS is the number of successful transactions per second
+	TPS float64 `json:"tps"`
+	// ErrorRate is the share of failed transactions, between 0 and 1
+	ErrorRate float64 `json:"errorRate"`
+	// P95Latency is the 95th percentile of the transaction durations
+	P95Latency time.Duration `json:"p95Latency"`
+	// Transactions is the number of transactions measured
+	Transactions int `json:"transactions"`
+}
+
+// Baseline is a reference measurement stored in a baseline file.
+type Baseline struct {
+	Measurement
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// Tolerances are the degradations allowed before a metric counts as a regression.
+// A zero tolerance allows no degradation at all; a negative one disables the check.
+type Tolerances struct {
+	// TPSDrop is the allowed relative drop in throughput, e.g. 0.1 for 10%
+	TPSDrop float64
+	// ErrorRateIncrease is the allowed absolute increase of the error rate, e.g. 0.01 for one point
+	ErrorRateIncrease float64
+	// P95LatencyIncrease is the allowed relative increase of the p95 latency, e.g. 0.2 for 20%
+	P95LatencyIncrease float64
+}
+
+// DefaultTolerances allows a 10% throughput drop, one point of error rate and a
+// 20% p95 latency increase, absorbing the usual noise of shared CI runners.
+func DefaultTolerances() Tolerances {
+	return Tolerances{TPSDrop: 0.1, ErrorRateIncrease: 0.01, P95LatencyIncrease: 0.2}
+}
+
+// Violation describes a metric that regressed.
+type Violation struct {
+	Metric   string  `json:"metric"`
+	Baseline float64 `json:"baseline"`
+	Actual   float64 `json:"actual"`
+	// Limit is the worst value the tolerance allowed
+	Limit float64 `json:"limit"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %.4g (baseline %.4g, limit %.4g)", v.Metric, v.Actual, v.Baseline, v.Limit)
+}
+
+// RegressionError is returned by AssertBaseline when a run regressed.
+type RegressionError struct {
+	Baseline   Baseline
+	Actual     Measurement
+	Violations []Violation
+}
+
+func (e *RegressionError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+
+	return fmt.Sprintf("%s: %s", ErrRegression, strings.Join(parts, "; "))
+}
+
+// Is reports whether target is ErrRegression.
+func (*RegressionError) Is(target error) bool {
+	return target == ErrRegression
+}
+
+// ExitCode maps the error of AssertBaseline to a process exit code: ExitOK when
+// nil, ExitRegression on regression and ExitError on any other failure, such as
+// a missing baseline file.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrRegression):
+		return ExitRegression
+	default:
+		return ExitError
+	}
+}
+
+// Measure computes the metrics of batch results. Throughput is the one reported
+// by transaction.GetBatchSummary.
+func Measure(results []transaction.BatchResult) Measurement {
+	summary := transaction.GetBatchSummary(results)
+
+	m := Measurement{TPS: summary.TransactionsPerSecond, Transactions: summary.TotalTransactions}
+	if summary.TotalTransactions > 0 {
+		m.ErrorRate = float64(summary.ErrorCount) / float64(summary.TotalTransactions)
+	}
+
+	durations := make([]time.Duration, len(results))
+	for i, r := range results {
+		durations[i] = r.Duration
+	}
+
+	m.P95Latency = percentile(durations, 0.95)
+
+	return m
+}
+
+// percentile returns the nearest-rank percentile p of durations.
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(p*float64(len(sorted))+0.5) - 1
+
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
+
+// AssertBaseline compares results against the baseline stored in baselineFile.
+// It returns a *RegressionError when a metric degraded beyond tolerances and
+// another error when the baseline cannot be read.
+func AssertBaseline(results []transaction.BatchResult, baselineFile string, tolerances Tolerances) error {
+	baseline, err := ReadBaseline(baselineFile)
+	if err != nil {
+		return err
+	}
+
+	return Compare(*baseline, Measure(results), tolerances)
+}
+
+// Compare checks actual against baseline.
+func Compare(baseline Baseline, actual Measurement, tolerances Tolerances) error {
+	var violations []Violation
+
+	if tolerances.TPSDrop >= 0 {
+		limit := baseline.TPS * (1 - tolerances.TPSDrop)
+		if actual.TPS < limit {
+			violations = append(violations, Violation{Metric: "tps", Baseline: baseline.TPS, Actual: actual.TPS, Limit: limit})
+		}
+	}
+
+	if tolerances.ErrorRateIncrease >= 0 {
+		limit := baseline.ErrorRate + tolerances.ErrorRateIncrease
+		if actual.ErrorRate > limit {
+			violations = append(violations, Violation{Metric: "error_rate", Baseline: baseline.ErrorRate, Actual: actual.ErrorRate, Limit: limit})
+		}
+	}
+
+	if tolerances.P95LatencyIncrease >= 0 {
+		limit := baseline.P95Latency.Seconds() * (1 + tolerances.P95LatencyIncrease)
+		if actual.P95Latency.Seconds() > limit {
+			violations = append(violations, Violation{
+				Metric: "p95_latency_seconds", Baseline: baseline.P95Latency.Seconds(), Actual: actual.P95Latency.Seconds(), Limit: limit,
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &RegressionError{Baseline: baseline, Actual: actual, Violations: violations}
+	}
+
+	return nil
+}
+
+// ReadBaseline reads a baseline file.
+func ReadBaseline(path string) (*Baseline, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- path is chosen by the caller
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(raw, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+
+	return &b, nil
+}
+
+// WriteBaseline stores the measurement of results as the baseline in path,
+// typically after an accepted run on the main branch.
+func WriteBaseline(results []transaction.BatchResult, path string) (*Baseline, error) {
+	b := &Baseline{Measurement: Measure(results), RecordedAt: time.Now().UTC()}
+
+	raw, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode baseline: %w", err)
+	}
+
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write baseline: %w", err)
+	}
+
+	return b, nil
+}
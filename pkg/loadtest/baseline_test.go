@@ -0,0 +1,96 @@
+package loadtest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// run returns n results of the given duration, the last failed ones failing.
+func run(n, failed int, duration time.Duration) []transaction.BatchResult {
+	results := make([]transaction.BatchResult, n)
+	for i := range results {
+		results[i] = transaction.BatchResult{Index: i, Duration: duration}
+		if i >= n-failed {
+			results[i].Error = errors.New("failed")
+		}
+	}
+
+	return results
+}
+
+func TestMeasure(t *testing.T) {
+	results := run(100, 2, 10*time.Millisecond)
+	for i := 0; i < 5; i++ {
+		results[i].Duration = time.Second
+	}
+
+	m := Measure(results)
+
+	assert.Equal(t, 100, m.Transactions)
+	assert.InDelta(t, 0.02, m.ErrorRate, 1e-9)
+	assert.Equal(t, 10*time.Millisecond, m.P95Latency)
+	assert.Greater(t, m.TPS, 0.0)
+
+	results[5].Duration = time.Second
+	assert.Equal(t, time.Second, Measure(results).P95Latency)
+
+	assert.Equal(t, Measurement{}, Measure(nil))
+}
+
+func TestAssertBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+
+	baseline, err := WriteBaseline(run(100, 0, 10*time.Millisecond), path)
+	require.NoError(t, err)
+	assert.False(t, baseline.RecordedAt.IsZero())
+
+	// the same run passes
+	require.NoError(t, AssertBaseline(run(100, 0, 10*time.Millisecond), path, DefaultTolerances()))
+	assert.Equal(t, ExitOK, ExitCode(nil))
+
+	// slower and failing transactions regress
+	err = AssertBaseline(run(100, 5, 20*time.Millisecond), path, DefaultTolerances())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrRegression)
+	assert.Equal(t, ExitRegression, ExitCode(err))
+
+	var regression *RegressionError
+	require.ErrorAs(t, err, &regression)
+
+	metrics := make([]string, 0, len(regression.Violations))
+	for _, v := range regression.Violations {
+		metrics = append(metrics, v.Metric)
+	}
+
+	assert.Equal(t, []string{"tps", "error_rate", "p95_latency_seconds"}, metrics)
+	assert.Contains(t, err.Error(), "error_rate: 0.05")
+
+	// disabled checks are skipped
+	err = AssertBaseline(run(100, 0, 20*time.Millisecond), path, Tolerances{TPSDrop: -1, ErrorRateIncrease: 0, P95LatencyIncrease: -1})
+	assert.NoError(t, err)
+}
+
+func TestAssertBaselineMissingFile(t *testing.T) {
+	err := AssertBaseline(run(1, 0, time.Millisecond), filepath.Join(t.TempDir(), "missing.json"), DefaultTolerances())
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.Equal(t, ExitError, ExitCode(err))
+}
+
+func TestCompareWithinTolerance(t *testing.T) {
+	baseline := Baseline{Measurement: Measurement{TPS: 100, ErrorRate: 0.01, P95Latency: 100 * time.Millisecond}}
+
+	actual := Measurement{TPS: 91, ErrorRate: 0.019, P95Latency: 119 * time.Millisecond}
+	assert.NoError(t, Compare(baseline, actual, DefaultTolerances()))
+
+	actual.TPS = 89
+	assert.ErrorIs(t, Compare(baseline, actual, DefaultTolerances()), ErrRegression)
+}
@@ -0,0 +1,481 @@
+// Package manifest records what a generation or load test run was made of, so
+// its results can be reproduced and audited.
+//
+// A Manifest captures the SDK and Go versions, the versions reported by the
+// backend services, the run configuration with secrets redacted, the seeds and
+// templates used, and the git blob SHAs of the scenario files. Seal computes a
+// fingerprint over everything that influences the generated data, so two runs
+// with the same fingerprint are expected to produce the same data:
+//
+//	m := manifest.New("nightly-load")
+//	m.WithConfig("sdk", manifest.SDKConfig(cfg)).
+//	    WithSeed("generation", gcfg.GenerationSeed).
+//	    WithTemplates("accounts", data.AllAccountTemplates())
+//
+//	if err := m.AddScenarioFiles("scenarios/nightly.yaml"); err != nil {
+//	    return err
+//	}
+//
+//	report.Manifest = m.Seal()
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1" // #nosec G505 -- git object names are SHA-1 digests
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/config"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/security"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/version"
+)
+
+// Redacted replaces the values of sensitive configuration keys.
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are the key fragments whose values are never recorded.
+var sensitiveKeys = []string{"secret", "password", "passwd", "token", "credential", "authorization", "apikey", "api_key", "privatekey", "private_key"}
+
+// SDK identifies the SDK and toolchain that ran.
+type SDK struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Language  string `json:"language"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Environment describes the machine that ran.
+type Environment struct {
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Hostname string `json:"hostname,omitempty"`
+	NumCPU   int    `json:"numCpu"`
+}
+
+// ScenarioFile identifies a scenario file by content.
+type ScenarioFile struct {
+	Path string `json:"path"`
+	// GitSHA is the git blob SHA of the content, as shown by git hash-object
+	GitSHA string `json:"gitSha"`
+	SHA256 string `json:"sha256"`
+	// Commit is the HEAD commit of the repository containing the file, if any
+	Commit string `json:"commit,omitempty"`
+}
+
+// Manifest describes a generation or load test run.
+type Manifest struct {
+	RunID       string            `json:"runId"`
+	Name        string            `json:"name"`
+	StartedAt   time.Time         `json:"startedAt"`
+	SDK         SDK               `json:"sdk"`
+	Environment Environment       `json:"environment"`
+	Backend     map[string]string `json:"backend,omitempty"`
+	Config      map[string]any    `json:"config,omitempty"`
+	Seeds       map[string]int64  `json:"seeds,omitempty"`
+	// Templates maps template sets to the SHA-256 of their JSON encoding
+	Templates map[string]string `json:"templates,omitempty"`
+	Scenarios []ScenarioFile    `json:"scenarios,omitempty"`
+	// Fingerprint is set by Seal
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// New creates a manifest for a run started now, filled with the SDK and
+// environment information.
+func New(name string) *Manifest {
+	hostname, _ := os.Hostname() //nolint:errcheck // the hostname is informational
+
+	return &Manifest{
+		RunID:     newRunID(),
+		Name:      name,
+		StartedAt: time.Now().UTC(),
+		SDK: SDK{
+			Name:      version.SDKName,
+			Version:   version.Version,
+			Language:  version.SDKLanguage,
+			GoVersion: runtime.Version(),
+		},
+		Environment: Environment{
+			OS:       runtime.GOOS,
+			Arch:     runtime.GOARCH,
+			Hostname: hostname,
+			NumCPU:   runtime.NumCPU(),
+		},
+	}
+}
+
+// WithConfig records the configuration v under name, with sensitive values
+// redacted. v must be JSON-encodable; values that are not are recorded as the
+// encoding error so the run is not aborted.
+func (m *Manifest) WithConfig(name string, v any) *Manifest {
+	if m.Config == nil {
+		m.Config = map[string]any{}
+	}
+
+	sanitized, err := Sanitize(v)
+	if err != nil {
+		sanitized = map[string]any{"error": err.Error()}
+	}
+
+	m.Config[name] = sanitized
+
+	return m
+}
+
+// WithSeed records a random seed.
+func (m *Manifest) WithSeed(name string, seed int64) *Manifest {
+	if m.Seeds == nil {
+		m.Seeds = map[string]int64{}
+	}
+
+	m.Seeds[name] = seed
+
+	return m
+}
+
+// WithTemplates records the digest of a template set.
+func (m *Manifest) WithTemplates(name string, templates any) *Manifest {
+	if m.Templates == nil {
+		m.Templates = map[string]string{}
+	}
+
+	raw, err := json.Marshal(templates)
+	if err != nil {
+		m.Templates[name] = "error: " + err.Error()
+		return m
+	}
+
+	sum := sha256.Sum256(raw)
+	m.Templates[name] = hex.EncodeToString(sum[:])
+
+	return m
+}
+
+// WithBackendVersion records the version of a backend service.
+func (m *Manifest) WithBackendVersion(service, v string) *Manifest {
+	if m.Backend == nil {
+		m.Backend = map[string]string{}
+	}
+
+	m.Backend[service] = v
+
+	return m
+}
+
+// AddScenarioFiles records the content digests of scenario files and the HEAD
+// commit of the git repository containing each of them.
+func (m *Manifest) AddScenarioFiles(paths ...string) error {
+	for _, path := range paths {
+		content, err := os.ReadFile(path) // #nosec G304 -- path is chosen by the caller
+		if err != nil {
+			return fmt.Errorf("failed to read scenario file: %w", err)
+		}
+
+		sum := sha256.Sum256(content)
+		m.Scenarios = append(m.Scenarios, ScenarioFile{
+			Path:   filepath.ToSlash(path),
+			GitSHA: GitBlobSHA(content),
+			SHA256: hex.EncodeToString(sum[:]),
+			Commit: gitHead(filepath.Dir(path)),
+		})
+	}
+
+	return nil
+}
+
+// Seal computes the fingerprint of the manifest and returns m.
+//
+// The fingerprint covers the SDK version, backend versions, configuration,
+// seeds, templates and scenario contents. Run ID, start time, toolchain and
+// machine are excluded: they do not change the generated data.
+func (m *Manifest) Seal() *Manifest {
+	if m == nil {
+		return nil
+	}
+
+	scenarios := make([]string, len(m.Scenarios))
+	for i, s := range m.Scenarios {
+		scenarios[i] = s.GitSHA
+	}
+
+	sort.Strings(scenarios)
+
+	// maps are encoded with sorted keys, so the encoding is deterministic
+	raw, err := json.Marshal(struct {
+		SDK       string            `json:"sdk"`
+		Backend   map[string]string `json:"backend"`
+		Config    map[string]any    `json:"config"`
+		Seeds     map[string]int64  `json:"seeds"`
+		Templates map[string]string `json:"templates"`
+		Scenarios []string          `json:"scenarios"`
+	}{m.SDK.Version, m.Backend, m.Config, m.Seeds, m.Templates, scenarios})
+	if err != nil {
+		return m
+	}
+
+	sum := sha256.Sum256(raw)
+	m.Fingerprint = hex.EncodeToString(sum[:])
+
+	return m
+}
+
+// ToJSON returns the JSON-encoded manifest.
+func (m *Manifest) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// Save writes the manifest as JSON to path.
+func (m *Manifest) Save(path string) error {
+	raw, err := m.ToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	return os.WriteFile(path, raw, 0o600)
+}
+
+// Sanitize returns the JSON form of v with the values of sensitive keys
+// replaced by Redacted and passwords removed from URLs.
+func Sanitize(v any) (any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	var out any
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	return redact(out), nil
+}
+
+func redact(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			if isSensitive(k) {
+				if item != nil && item != "" {
+					val[k] = Redacted
+				}
+
+				continue
+			}
+
+			val[k] = redact(item)
+		}
+
+		return val
+	case []any:
+		for i, item := range val {
+			val[i] = redact(item)
+		}
+
+		return val
+	case string:
+		return redactURL(val)
+	default:
+		return v
+	}
+}
+
+func isSensitive(key string) bool {
+	k := strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactURL removes the password of URLs with user information.
+func redactURL(s string) string {
+	if !strings.Contains(s, "://") || !strings.Contains(s, "@") {
+		return s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+
+	return u.String()
+}
+
+// SDKConfig returns the reproducibility-relevant settings of an SDK
+// configuration. The HTTP client and observability provider are left out and
+// the client secret is redacted.
+func SDKConfig(cfg *config.Config) map[string]any {
+	if cfg == nil {
+		return nil
+	}
+
+	urls := make(map[string]string, len(cfg.ServiceURLs))
+	for service, u := range cfg.ServiceURLs {
+		urls[string(service)] = redactURL(u)
+	}
+
+	out := map[string]any{
+		"environment":       string(cfg.Environment),
+		"serviceUrls":       urls,
+		"timeout":           cfg.Timeout.String(),
+		"userAgent":         cfg.UserAgent,
+		"maxRetries":        cfg.MaxRetries,
+		"retryWaitMin":      cfg.RetryWaitMin.String(),
+		"retryWaitMax":      cfg.RetryWaitMax.String(),
+		"enableRetries":     cfg.EnableRetries,
+		"enableIdempotency": cfg.EnableIdempotency,
+		"tenantId":          cfg.TenantID,
+		"accessManager": map[string]any{
+			"enabled":  cfg.AccessManager.Enabled,
+			"address":  cfg.AccessManager.Address,
+			"clientId": cfg.AccessManager.ClientID,
+		},
+	}
+
+	if cfg.AccessManager.ClientSecret != "" {
+		out["accessManager"].(map[string]any)["clientSecret"] = Redacted
+	}
+
+	return out
+}
+
+// FetchBackendVersion reads the version reported by a Midaz service at
+// <baseURL>/version. A nil httpClient uses a client with a 10 second timeout.
+func FetchBackendVersion(ctx context.Context, httpClient *http.Client, baseURL string) (string, error) {
+	endpoint := strings.TrimRight(baseURL, "/")
+	endpoint = strings.TrimSuffix(endpoint, "/v1") + "/version"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create version request: %w", err)
+	}
+
+	if err := security.ValidateOutboundRequest(req); err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent())
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	resp, err := httpClient.Do(req) // #nosec G704 -- request URL validated via security.ValidateOutboundRequest
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch backend version: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return "", fmt.Errorf("failed to read backend version: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("backend version request to %s failed with %s", endpoint, resp.Status)
+	}
+
+	var payload struct {
+		Version string `json:"version"`
+	}
+
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Version == "" {
+		return "", fmt.Errorf("backend at %s reported no version", endpoint)
+	}
+
+	return payload.Version, nil
+}
+
+// GitBlobSHA returns the git object name of a blob with the given content.
+func GitBlobSHA(content []byte) string {
+	h := sha1.New() // #nosec G401 -- git object names are SHA-1 digests
+	_, _ = fmt.Fprintf(h, "blob %d\x00", len(content))
+	_, _ = h.Write(content)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// gitHead returns the HEAD commit of the git repository containing dir, read
+// from the repository files so no git binary is needed. It returns "" outside
+// a repository.
+func gitHead(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		gitDir := filepath.Join(abs, ".git")
+		if info, err := os.Stat(gitDir); err == nil && info.IsDir() {
+			return resolveHead(gitDir)
+		}
+
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return ""
+		}
+
+		abs = parent
+	}
+}
+
+func resolveHead(gitDir string) string {
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD")) // #nosec G304 -- path inside the repository
+	if err != nil {
+		return ""
+	}
+
+	ref, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: ")
+	if !ok {
+		// detached HEAD
+		return strings.TrimSpace(string(head))
+	}
+
+	if sha, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(ref))); err == nil { // #nosec G304 -- path inside the repository
+		return strings.TrimSpace(string(sha))
+	}
+
+	packed, err := os.ReadFile(filepath.Join(gitDir, "packed-refs")) // #nosec G304 -- path inside the repository
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(packed), "\n") {
+		if sha, name, ok := strings.Cut(strings.TrimSpace(line), " "); ok && name == ref {
+			return sha
+		}
+	}
+
+	return ""
+}
+
+func newRunID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+
+	return time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(b)
+}
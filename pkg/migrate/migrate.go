@@ -0,0 +1,449 @@
+// Package migrate moves the balances and history of a legacy core into a
+// Midaz ledger, reading them from any SQL database.
+//
+// A Migration reads opening balances and historical postings with queries
+// whose columns are mapped by name, turns each balance into a transaction
+// funding the account from an external account and each posting into a
+// transfer, and submits them with transaction.BatchTransactions. It then
+// checks the ledger with the integrity checker: the change of the internal
+// total of each asset must equal the one expected from the transactions it
+// created, and any difference is reported as drift.
+//
+//	migration := migrate.New(c, orgID, ledgerID, "core-cutover-2026").
+//	    WithBalances(db, migrate.BalanceMapping{
+//	        Query:   "SELECT alias, currency, balance FROM accounts WHERE closed = false",
+//	        Account: "alias", Asset: "currency", Amount: "balance",
+//	    }).
+//	    WithPostings(db, migrate.PostingMapping{
+//	        Query: "SELECT id, debit_alias, credit_alias, currency, amount, posted_at FROM postings ORDER BY posted_at",
+//	        ID:    "id", Source: "debit_alias", Destination: "credit_alias",
+//	        Asset: "currency", Amount: "amount", Date: "posted_at",
+//	    })
+//
+//	report, err := migration.Run(ctx)
+//
+// The idempotency key of each transaction is derived from the run ID and the
+// source record, so a migration interrupted or partially failed can be run
+// again with the same run ID: the transactions already created are not
+// duplicated; they are counted as already migrated.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/integrity"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/transaction"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Record kinds, in Failure.Kind.
+const (
+	KindOpeningBalance = "opening-balance"
+	KindPosting        = "posting"
+)
+
+// keyNamespace derives the idempotency keys of the migrated records.
+var keyNamespace = uuid.MustParse("5b0e5f52-6c1e-4c43-9d3e-2f5b8f1f7a10")
+
+// Failure is a source record that was not migrated.
+type Failure struct {
+	Kind string `json:"kind"`
+
+	// SourceID is the posting ID, or the account and asset of a balance
+	SourceID string `json:"sourceId"`
+	Error    string `json:"error"`
+}
+
+// AssetDrift compares the change of the internal total of an asset in the
+// ledger with the one expected from the transactions created by the run, or
+// from every source record in a dry run.
+type AssetDrift struct {
+	Asset    string          `json:"asset"`
+	Expected decimal.Decimal `json:"expected"`
+	Actual   decimal.Decimal `json:"actual"`
+
+	// Drift is Actual minus Expected
+	Drift decimal.Decimal `json:"drift"`
+}
+
+// Report is the outcome of a Migration.
+type Report struct {
+	RunID  string `json:"runId"`
+	DryRun bool   `json:"dryRun"`
+
+	OpeningBalances int `json:"openingBalances"`
+	Postings        int `json:"postings"`
+	Submitted       int `json:"submitted"`
+
+	// AlreadyMigrated counts the records whose transaction was created by an
+	// earlier run with the same run ID
+	AlreadyMigrated int `json:"alreadyMigrated"`
+
+	// Skipped counts the zero opening balances, which need no transaction
+	Skipped  int       `json:"skipped"`
+	Failures []Failure `json:"failures,omitempty"`
+
+	// Assets holds the drift of each asset, empty when the migration was not verified
+	Assets []AssetDrift `json:"assets,omitempty"`
+}
+
+// Balanced reports whether every record was migrated and no asset drifted.
+func (r *Report) Balanced() bool {
+	if len(r.Failures) > 0 {
+		return false
+	}
+
+	for _, asset := range r.Assets {
+		if !asset.Drift.IsZero() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Migration migrates balances and postings from SQL sources into a ledger.
+type Migration struct {
+	client   *client.Client
+	orgID    string
+	ledgerID string
+	runID    string
+
+	balancesDB Querier
+	balances   *BalanceMapping
+	postingsDB Querier
+	postings   *PostingMapping
+
+	batch          *transaction.BatchOptions
+	openingAccount func(asset string) string
+	dryRun         bool
+	verify         bool
+}
+
+// New returns a Migration into the ledger ledgerID of orgID. runID names the
+// migration; running it again with the same runID resumes it without
+// duplicating the transactions already created.
+func New(midazClient *client.Client, orgID, ledgerID, runID string) *Migration {
+	return &Migration{
+		client:   midazClient,
+		orgID:    orgID,
+		ledgerID: ledgerID,
+		runID:    runID,
+		batch:    transaction.DefaultBatchOptions(),
+		verify:   true,
+		openingAccount: func(asset string) string {
+			return "@external/" + asset
+		},
+	}
+}
+
+// WithBalances reads the opening balances from db with mapping.
+func (m *Migration) WithBalances(db Querier, mapping BalanceMapping) *Migration {
+	m.balancesDB, m.balances = db, &mapping
+	return m
+}
+
+// WithPostings reads the historical postings from db with mapping. They are
+// submitted after the opening balances, concurrently unless the batch options
+// set a Concurrency of 1, which keeps the order of the query.
+func (m *Migration) WithPostings(db Querier, mapping PostingMapping) *Migration {
+	m.postingsDB, m.postings = db, &mapping
+	return m
+}
+
+// WithBatchOptions sets the options of the submissions; their StopOnError is
+// ignored.
+func (m *Migration) WithBatchOptions(options *transaction.BatchOptions) *Migration {
+	if options != nil {
+		m.batch = options
+	}
+
+	return m
+}
+
+// WithOpeningAccount sets the account funding the opening balances of an
+// asset, "@external/<asset>" by default.
+func (m *Migration) WithOpeningAccount(fn func(asset string) string) *Migration {
+	if fn != nil {
+		m.openingAccount = fn
+	}
+
+	return m
+}
+
+// WithDryRun makes Run read and check the source without submitting
+// anything, to preview the records and the expected totals.
+func (m *Migration) WithDryRun(dryRun bool) *Migration {
+	m.dryRun = dryRun
+	return m
+}
+
+// WithVerification sets whether Run checks the ledger totals with the
+// integrity checker after the submissions; it does by default.
+func (m *Migration) WithVerification(verify bool) *Migration {
+	m.verify = verify
+	return m
+}
+
+// migrationRecord is a source record and its transaction.
+type migrationRecord struct {
+	kind     string
+	sourceID string
+	asset    string
+
+	// delta is the expected change of the internal total of the asset
+	delta decimal.Decimal
+	input *models.CreateTransactionInput
+}
+
+// Run reads the sources, submits the transactions and verifies the ledger.
+// It returns an error when a source can't be read or the ledger can't be
+// checked; records that fail are reported in the Report.
+func (m *Migration) Run(ctx context.Context) (*Report, error) {
+	if m.balances == nil && m.postings == nil {
+		return nil, errors.New("migration has no source: set WithBalances or WithPostings")
+	}
+
+	report := &Report{RunID: m.runID, DryRun: m.dryRun}
+
+	openings, err := m.openingRecords(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	postings, err := m.postingRecords(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := map[string]decimal.Decimal{}
+
+	if m.dryRun {
+		for _, record := range slices.Concat(openings, postings) {
+			expected[record.asset] = expected[record.asset].Add(record.delta)
+		}
+
+		report.Assets = driftOf(expected, nil)
+
+		return report, nil
+	}
+
+	var before *integrity.Report
+
+	if m.verify {
+		if before, err = m.checkLedger(ctx); err != nil {
+			return nil, fmt.Errorf("checking ledger before migration: %w", err)
+		}
+	}
+
+	m.submit(ctx, openings, report, expected)
+	m.submit(ctx, postings, report, expected)
+
+	if !m.verify {
+		return report, nil
+	}
+
+	after, err := m.checkLedger(ctx)
+	if err != nil {
+		return report, fmt.Errorf("checking ledger after migration: %w", err)
+	}
+
+	actual := map[string]decimal.Decimal{}
+
+	for asset, totals := range after.TotalsByAsset {
+		actual[asset] = totals.InternalNetTotal
+	}
+
+	for asset, totals := range before.TotalsByAsset {
+		actual[asset] = actual[asset].Sub(totals.InternalNetTotal)
+	}
+
+	report.Assets = driftOf(expected, actual)
+
+	return report, nil
+}
+
+// openingRecords reads the opening balances into records.
+func (m *Migration) openingRecords(ctx context.Context, report *Report) ([]migrationRecord, error) {
+	if m.balances == nil {
+		return nil, nil
+	}
+
+	balances, err := readBalances(ctx, m.balancesDB, *m.balances)
+	if err != nil {
+		return nil, fmt.Errorf("reading opening balances: %w", err)
+	}
+
+	report.OpeningBalances = len(balances)
+
+	records := make([]migrationRecord, 0, len(balances))
+
+	for _, b := range balances {
+		if b.Amount.IsZero() {
+			report.Skipped++
+			continue
+		}
+
+		sourceID := b.Account + "/" + b.Asset
+		from, to := m.openingAccount(b.Asset), b.Account
+
+		if b.Amount.IsNegative() {
+			from, to = to, from
+		}
+
+		input := transferInput(b.Asset, b.Amount.Abs(), from, to).
+			WithDescription("Opening balance").
+			WithMetadata(map[string]any{"migrationRunId": m.runID, "migrationKind": KindOpeningBalance})
+		input.IdempotencyKey = m.key(KindOpeningBalance, sourceID)
+
+		records = append(records, migrationRecord{kind: KindOpeningBalance, sourceID: sourceID, asset: b.Asset, delta: internalDelta(from, to, b.Amount.Abs()), input: input})
+	}
+
+	return records, nil
+}
+
+// postingRecords reads the postings into records.
+func (m *Migration) postingRecords(ctx context.Context, report *Report) ([]migrationRecord, error) {
+	if m.postings == nil {
+		return nil, nil
+	}
+
+	postings, err := readPostings(ctx, m.postingsDB, *m.postings)
+	if err != nil {
+		return nil, fmt.Errorf("reading postings: %w", err)
+	}
+
+	report.Postings = len(postings)
+
+	records := make([]migrationRecord, 0, len(postings))
+
+	for _, p := range postings {
+		if !p.Amount.IsPositive() {
+			report.Failures = append(report.Failures, Failure{Kind: KindPosting, SourceID: p.ID, Error: fmt.Sprintf("amount must be positive, got %s", p.Amount)})
+			continue
+		}
+
+		metadata := map[string]any{"migrationRunId": m.runID, "migrationKind": KindPosting, "migrationSourceId": p.ID}
+		if p.Date != "" {
+			metadata["migrationSourceDate"] = p.Date
+		}
+
+		input := transferInput(p.Asset, p.Amount, p.Source, p.Destination).WithMetadata(metadata).WithExternalID(p.ID)
+		input.Description = p.Description
+		input.IdempotencyKey = m.key(KindPosting, p.ID)
+
+		records = append(records, migrationRecord{kind: KindPosting, sourceID: p.ID, asset: p.Asset, delta: internalDelta(p.Source, p.Destination, p.Amount), input: input})
+	}
+
+	return records, nil
+}
+
+// submit submits the transactions of records, reports their failures and
+// adds the changes of the created ones to expected.
+func (m *Migration) submit(ctx context.Context, records []migrationRecord, report *Report, expected map[string]decimal.Decimal) {
+	if len(records) == 0 {
+		return
+	}
+
+	options := *m.batch
+	options.StopOnError = false
+
+	inputs := make([]*models.CreateTransactionInput, len(records))
+	for i, record := range records {
+		inputs[i] = record.input
+	}
+
+	results, _ := transaction.BatchTransactions(ctx, m.client, m.orgID, m.ledgerID, inputs, &options)
+
+	for i, result := range results {
+		record := records[i]
+
+		switch {
+		case result.Error == nil:
+			report.Submitted++
+			expected[record.asset] = expected[record.asset].Add(record.delta)
+		case sdkerrors.IsIdempotencyError(result.Error):
+			report.AlreadyMigrated++
+		default:
+			report.Failures = append(report.Failures, Failure{Kind: record.kind, SourceID: record.sourceID, Error: result.Error.Error()})
+		}
+	}
+}
+
+// checkLedger returns the integrity report of the ledger.
+func (m *Migration) checkLedger(ctx context.Context) (*integrity.Report, error) {
+	return integrity.NewChecker(m.client.Entity).GenerateLedgerReport(ctx, m.orgID, m.ledgerID)
+}
+
+// key returns the idempotency key of a source record.
+func (m *Migration) key(kind, sourceID string) string {
+	return uuid.NewSHA1(keyNamespace, []byte(m.runID+"\x00"+kind+"\x00"+sourceID)).String()
+}
+
+// transferInput returns a transaction moving amount of asset from one account to another.
+func transferInput(asset string, amount decimal.Decimal, from, to string) *models.CreateTransactionInput {
+	return models.NewCreateTransactionInputFromDecimal(asset, amount).
+		WithSend(models.NewSendInput(asset, amount,
+			[]models.FromToInput{models.NewFromToInput(from, asset, amount)},
+			[]models.FromToInput{models.NewFromToInput(to, asset, amount)}))
+}
+
+// internalDelta returns the change of the internal total of an asset when
+// amount moves from one account to another.
+func internalDelta(from, to string, amount decimal.Decimal) decimal.Decimal {
+	delta := decimal.Zero
+
+	if isExternal(from) {
+		delta = delta.Add(amount)
+	}
+
+	if isExternal(to) {
+		delta = delta.Sub(amount)
+	}
+
+	return delta
+}
+
+// isExternal reports whether account is an external account, outside the internal totals.
+func isExternal(account string) bool {
+	return strings.HasPrefix(account, "@external/")
+}
+
+// driftOf compares the expected and actual changes of each asset, sorted by asset.
+func driftOf(expected, actual map[string]decimal.Decimal) []AssetDrift {
+	assets := make([]string, 0, len(expected))
+	for asset := range expected {
+		assets = append(assets, asset)
+	}
+
+	for asset := range actual {
+		if _, ok := expected[asset]; !ok {
+			assets = append(assets, asset)
+		}
+	}
+
+	slices.Sort(assets)
+
+	drifts := make([]AssetDrift, 0, len(assets))
+
+	for _, asset := range assets {
+		drift := AssetDrift{Asset: asset, Expected: expected[asset]}
+
+		if actual != nil {
+			drift.Actual = actual[asset]
+			drift.Drift = drift.Actual.Sub(drift.Expected)
+		}
+
+		drifts = append(drifts, drift)
+	}
+
+	return drifts
+}
@@ -0,0 +1,327 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/transaction"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// table is the result of a query of the fake database.
+type table struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+// fakeDB is a database/sql connector serving preset tables by query.
+type fakeDB map[string]table
+
+func (db fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{db: db}, nil }
+func (db fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	t, ok := c.db[query]
+	if !ok {
+		return nil, fmt.Errorf("unknown query %q", query)
+	}
+
+	return fakeStmt{table: t}, nil
+}
+
+func (fakeConn) Close() error              { return nil }
+func (fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type fakeStmt struct{ table table }
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{table: s.table}, nil
+}
+
+type fakeRows struct {
+	table table
+	next  int
+}
+
+func (r *fakeRows) Columns() []string { return r.table.columns }
+func (*fakeRows) Close() error        { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next == len(r.table.rows) {
+		return io.EOF
+	}
+
+	copy(dest, r.table.rows[r.next])
+	r.next++
+
+	return nil
+}
+
+// fakeLedger applies transactions to in-memory balances, keyed by alias and
+// asset, and reports them through the balances service.
+type fakeLedger struct {
+	entities.TransactionsService
+	entities.BalancesService
+
+	mu       sync.Mutex
+	balances map[[2]string]decimal.Decimal
+	keys     map[string]bool
+	inputs   []*models.CreateTransactionInput
+
+	// rejected accounts fail the transactions moving to them
+	rejected map[string]bool
+	// leaky accounts accept the transactions moving to them without crediting them
+	leaky map[string]bool
+}
+
+func newFakeLedger() *fakeLedger {
+	return &fakeLedger{balances: map[[2]string]decimal.Decimal{}, keys: map[string]bool{}, rejected: map[string]bool{}, leaky: map[string]bool{}}
+}
+
+func (l *fakeLedger) CreateTransaction(_ context.Context, _, _ string, input *models.CreateTransactionInput) (*models.Transaction, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.keys[input.IdempotencyKey] {
+		return nil, &sdkerrors.Error{Category: sdkerrors.CategoryConflict, Code: sdkerrors.CodeIdempotency, Message: "already processed"}
+	}
+
+	from, to := input.Send.Source.From[0].Account, input.Send.Distribute.To[0].Account
+	if l.rejected[to] {
+		return nil, sdkerrors.NewValidationError("CreateTransaction", "account is closed", nil)
+	}
+
+	amount, err := decimal.NewFromString(input.Send.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	l.keys[input.IdempotencyKey] = true
+	l.inputs = append(l.inputs, input)
+
+	asset := input.Send.Asset
+	l.balances[[2]string{from, asset}] = l.balances[[2]string{from, asset}].Sub(amount)
+
+	if !l.leaky[to] {
+		l.balances[[2]string{to, asset}] = l.balances[[2]string{to, asset}].Add(amount)
+	}
+
+	return &models.Transaction{ID: fmt.Sprintf("tx-%d", len(l.inputs))}, nil
+}
+
+func (l *fakeLedger) ListBalances(context.Context, string, string, *models.ListOptions) (*models.ListResponse[models.Balance], error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	resp := &models.ListResponse[models.Balance]{}
+
+	for key, amount := range l.balances {
+		id := key[0] + "/" + key[1]
+		resp.Items = append(resp.Items, models.Balance{ID: id, AccountID: id, Alias: key[0], AssetCode: key[1], Available: amount})
+	}
+
+	return resp, nil
+}
+
+// noAccounts has no accounts to look up: the balances hold their aliases.
+type noAccounts struct{ entities.AccountsService }
+
+func (l *fakeLedger) client() *client.Client {
+	return &client.Client{Entity: &entities.Entity{Transactions: l, Balances: l, Accounts: noAccounts{}}}
+}
+
+var (
+	balancesMapping = BalanceMapping{Query: "balances", Account: "alias", Asset: "currency", Amount: "balance"}
+	postingsMapping = PostingMapping{
+		Query: "postings", ID: "id", Source: "debit", Destination: "credit",
+		Asset: "currency", Amount: "amount", Description: "memo", Date: "posted_at",
+	}
+)
+
+func legacyDB() *sql.DB {
+	return sql.OpenDB(fakeDB{
+		"balances": {
+			columns: []string{"alias", "currency", "balance"},
+			rows: [][]driver.Value{
+				{"@alice", "USD", "100.50"},
+				{"@bob", "USD", int64(40)},
+				{"@carol", "USD", "0"},
+				{"@dave", "BRL", []byte("-12.5")},
+			},
+		},
+		"postings": {
+			columns: []string{"id", "debit", "credit", "currency", "amount", "memo", "posted_at"},
+			rows: [][]driver.Value{
+				{"p-1", "@alice", "@bob", "USD", "10", "rent", "2026-01-02"},
+				{"p-2", "@external/USD", "@bob", "USD", float64(5.25), nil, nil},
+				{"p-3", "@bob", "@alice", "USD", "-1", nil, nil},
+			},
+		},
+	})
+}
+
+func testMigration(ledger *fakeLedger, db *sql.DB) *Migration {
+	batch := transaction.DefaultBatchOptions()
+	batch.RetryCount = 0
+	batch.Concurrency = 1
+
+	return New(ledger.client(), "org", "ledger", "run-1").
+		WithBalances(db, balancesMapping).
+		WithPostings(db, postingsMapping).
+		WithBatchOptions(batch)
+}
+
+func TestMigrationRun(t *testing.T) {
+	ledger := newFakeLedger()
+	db := legacyDB()
+
+	report, err := testMigration(ledger, db).Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.OpeningBalances)
+	assert.Equal(t, 3, report.Postings)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 5, report.Submitted)
+
+	// The negative posting is not submitted
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, Failure{Kind: KindPosting, SourceID: "p-3", Error: "amount must be positive, got -1"}, report.Failures[0])
+	assert.False(t, report.Balanced())
+
+	assert.Equal(t, []string{"BRL -12.5 -12.5 0", "USD 145.75 145.75 0"}, driftStrings(report.Assets))
+
+	assert.True(t, ledger.balances[[2]string{"@alice", "USD"}].Equal(decimal.RequireFromString("90.5")))
+	assert.True(t, ledger.balances[[2]string{"@bob", "USD"}].Equal(decimal.RequireFromString("55.25")))
+	assert.True(t, ledger.balances[[2]string{"@dave", "BRL"}].Equal(decimal.RequireFromString("-12.5")))
+
+	// A negative opening balance moves funds out to the opening account
+	dave := ledger.inputs[2]
+	assert.Equal(t, "@dave", dave.Send.Source.From[0].Account)
+	assert.Equal(t, "@external/BRL", dave.Send.Distribute.To[0].Account)
+	assert.Equal(t, KindOpeningBalance, dave.Metadata["migrationKind"])
+
+	rent := ledger.inputs[3]
+	assert.Equal(t, "rent", rent.Description)
+	assert.Equal(t, "p-1", rent.ExternalID)
+	assert.Equal(t, "2026-01-02", rent.Metadata["migrationSourceDate"])
+	assert.Equal(t, "run-1", rent.Metadata["migrationRunId"])
+}
+
+func TestMigrationResume(t *testing.T) {
+	ledger := newFakeLedger()
+	ledger.rejected["@bob"] = true
+
+	db := legacyDB()
+
+	first, err := testMigration(ledger, db).Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, first.Submitted)
+	assert.Len(t, first.Failures, 4)
+
+	keys := make([]string, 0, len(ledger.inputs))
+	for _, input := range ledger.inputs {
+		keys = append(keys, input.IdempotencyKey)
+	}
+
+	ledger.rejected = map[string]bool{}
+
+	second, err := testMigration(ledger, db).Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, second.Submitted)
+	assert.Equal(t, 2, second.AlreadyMigrated)
+	assert.Len(t, second.Failures, 1)
+
+	for _, asset := range second.Assets {
+		assert.True(t, asset.Drift.IsZero(), asset.Asset)
+	}
+
+	// The keys derive from the run and the records only
+	assert.Equal(t, keys, []string{ledger.inputs[0].IdempotencyKey, ledger.inputs[1].IdempotencyKey})
+	assert.Len(t, ledger.inputs, 5)
+
+	other := New(ledger.client(), "org", "ledger", "run-2")
+	assert.NotEqual(t, other.key(KindPosting, "p-1"), testMigration(ledger, db).key(KindPosting, "p-1"))
+}
+
+func TestMigrationDrift(t *testing.T) {
+	ledger := newFakeLedger()
+	ledger.leaky["@bob"] = true
+
+	report, err := testMigration(ledger, legacyDB()).Run(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Assets, 2)
+	usd := report.Assets[1]
+	assert.Equal(t, "USD", usd.Asset)
+	assert.True(t, usd.Drift.Equal(decimal.RequireFromString("-55.25")), usd.Drift.String())
+	assert.False(t, report.Balanced())
+}
+
+func TestMigrationDryRun(t *testing.T) {
+	ledger := newFakeLedger()
+
+	report, err := testMigration(ledger, legacyDB()).WithDryRun(true).Run(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	assert.Zero(t, report.Submitted)
+	assert.Empty(t, ledger.inputs)
+
+	require.Len(t, report.Assets, 2)
+	assert.True(t, report.Assets[1].Expected.Equal(decimal.RequireFromString("145.75")))
+}
+
+func TestMigrationSourceErrors(t *testing.T) {
+	ledger := newFakeLedger()
+	db := legacyDB()
+
+	_, err := New(ledger.client(), "org", "ledger", "run-1").Run(context.Background())
+	require.Error(t, err)
+
+	tests := map[string]BalanceMapping{
+		"unknown query":   {Query: "accounts", Account: "alias", Asset: "currency", Amount: "balance"},
+		"missing column":  {Query: "balances", Account: "alias", Asset: "asset", Amount: "balance"},
+		"unmapped column": {Query: "balances", Account: "alias", Asset: "currency"},
+		"invalid amount":  {Query: "balances", Account: "alias", Asset: "currency", Amount: "alias"},
+	}
+
+	for name, mapping := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(ledger.client(), "org", "ledger", "run-1").WithBalances(db, mapping).Run(context.Background())
+			require.Error(t, err)
+			assert.Empty(t, ledger.inputs)
+		})
+	}
+}
+
+// driftStrings formats drifts as "asset expected actual drift".
+func driftStrings(drifts []AssetDrift) []string {
+	formatted := make([]string, 0, len(drifts))
+	for _, d := range drifts {
+		formatted = append(formatted, fmt.Sprintf("%s %s %s %s", d.Asset, d.Expected, d.Actual, d.Drift))
+	}
+
+	return formatted
+}
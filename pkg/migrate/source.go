@@ -0,0 +1,241 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Querier runs the queries of the mappings; *sql.DB, *sql.Conn and *sql.Tx
+// implement it.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// BalanceMapping maps the rows of a query to opening balances, one per
+// account and asset. The fields other than Query and Args name the columns
+// of the query.
+type BalanceMapping struct {
+	Query string
+	Args  []any
+
+	// Account is the column of the account alias, or ID, in the ledger (required)
+	Account string
+	// Asset is the column of the asset code (required)
+	Asset string
+	// Amount is the column of the balance, as a decimal number (required)
+	Amount string
+}
+
+// PostingMapping maps the rows of a query to historical postings, each
+// moving an amount from a source account to a destination account. The
+// fields other than Query and Args name the columns of the query.
+type PostingMapping struct {
+	Query string
+	Args  []any
+
+	// ID is the column of the identifier of the posting in the source system (required)
+	ID string
+	// Source and Destination are the columns of the account aliases, or IDs (required)
+	Source      string
+	Destination string
+	// Asset is the column of the asset code (required)
+	Asset string
+	// Amount is the column of the posted amount, a positive decimal number (required)
+	Amount string
+	// Description is the column of the description (optional)
+	Description string
+	// Date is the column of the posting date, recorded in the metadata (optional)
+	Date string
+}
+
+// OpeningBalance is a balance read from the source.
+type OpeningBalance struct {
+	Account string
+	Asset   string
+	Amount  decimal.Decimal
+}
+
+// Posting is a historical posting read from the source.
+type Posting struct {
+	ID          string
+	Source      string
+	Destination string
+	Asset       string
+	Amount      decimal.Decimal
+	Description string
+	Date        string
+}
+
+// readRows calls fn with the columns of each row of query, by name.
+func readRows(ctx context.Context, db Querier, query string, args []any, fn func(row map[string]any) error) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("running source query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("reading source columns: %w", err)
+	}
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return fmt.Errorf("reading source row: %w", err)
+		}
+
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading source rows: %w", err)
+	}
+
+	return nil
+}
+
+// readBalances reads the opening balances of mapping.
+func readBalances(ctx context.Context, db Querier, mapping BalanceMapping) ([]OpeningBalance, error) {
+	var balances []OpeningBalance
+
+	err := readRows(ctx, db, mapping.Query, mapping.Args, func(row map[string]any) error {
+		var (
+			balance OpeningBalance
+			err     error
+		)
+
+		if balance.Account, err = requiredColumn(row, mapping.Account); err != nil {
+			return err
+		}
+
+		if balance.Asset, err = requiredColumn(row, mapping.Asset); err != nil {
+			return err
+		}
+
+		if balance.Amount, err = amountColumn(row, mapping.Amount); err != nil {
+			return err
+		}
+
+		balances = append(balances, balance)
+
+		return nil
+	})
+
+	return balances, err
+}
+
+// readPostings reads the postings of mapping.
+func readPostings(ctx context.Context, db Querier, mapping PostingMapping) ([]Posting, error) {
+	var postings []Posting
+
+	err := readRows(ctx, db, mapping.Query, mapping.Args, func(row map[string]any) error {
+		var (
+			posting Posting
+			err     error
+		)
+
+		for column, field := range map[string]*string{
+			mapping.ID:          &posting.ID,
+			mapping.Source:      &posting.Source,
+			mapping.Destination: &posting.Destination,
+			mapping.Asset:       &posting.Asset,
+		} {
+			if *field, err = requiredColumn(row, column); err != nil {
+				return err
+			}
+		}
+
+		if posting.Amount, err = amountColumn(row, mapping.Amount); err != nil {
+			return err
+		}
+
+		if mapping.Description != "" {
+			posting.Description = columnString(row[mapping.Description])
+		}
+
+		if mapping.Date != "" {
+			posting.Date = columnString(row[mapping.Date])
+		}
+
+		postings = append(postings, posting)
+
+		return nil
+	})
+
+	return postings, err
+}
+
+// requiredColumn returns the value of a column that must be set.
+func requiredColumn(row map[string]any, column string) (string, error) {
+	if column == "" {
+		return "", fmt.Errorf("mapping is missing a required column")
+	}
+
+	value, ok := row[column]
+	if !ok {
+		return "", fmt.Errorf("source query has no column %q", column)
+	}
+
+	s := columnString(value)
+	if s == "" {
+		return "", fmt.Errorf("column %q is empty", column)
+	}
+
+	return s, nil
+}
+
+// amountColumn returns the value of a column holding an amount.
+func amountColumn(row map[string]any, column string) (decimal.Decimal, error) {
+	s, err := requiredColumn(row, column)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	amount, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("column %q: invalid amount %q", column, s)
+	}
+
+	return amount, nil
+}
+
+// columnString converts a value scanned by database/sql to a string.
+func columnString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
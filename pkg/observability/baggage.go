@@ -0,0 +1,121 @@
+package observability
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// DefaultBaggageMaxBytes is the default size cap of the baggage header sent
+// with outgoing requests, the limit recommended by the W3C Baggage spec.
+const DefaultBaggageMaxBytes = 8192
+
+// BaggagePolicy controls the baggage propagated with outgoing requests.
+type BaggagePolicy struct {
+	// AllowedKeys are the baggage keys propagated. A key ending with "*"
+	// matches every key with that prefix. Nil propagates every key, empty
+	// propagates none.
+	AllowedKeys []string
+
+	// MaxBytes caps the size of the baggage header; members that don't fit,
+	// taken in key order, are left out. Zero means no cap.
+	MaxBytes int
+}
+
+// allows reports whether the policy propagates key.
+func (p BaggagePolicy) allows(key string) bool {
+	if p.AllowedKeys == nil {
+		return true
+	}
+
+	for _, allowed := range p.AllowedKeys {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter returns the baggage of b the policy propagates.
+func (p BaggagePolicy) Filter(b baggage.Baggage) baggage.Baggage {
+	members := b.Members()
+	sort.Slice(members, func(i, j int) bool { return members[i].Key() < members[j].Key() })
+
+	kept := make([]baggage.Member, 0, len(members))
+	size := 0
+
+	for _, m := range members {
+		if !p.allows(m.Key()) {
+			continue
+		}
+
+		// Members are joined with commas
+		n := len(m.String())
+		if len(kept) > 0 {
+			n++
+		}
+
+		if p.MaxBytes > 0 && size+n > p.MaxBytes {
+			continue
+		}
+
+		kept = append(kept, m)
+		size += n
+	}
+
+	filtered, err := baggage.New(kept...)
+	if err != nil {
+		return baggage.Baggage{}
+	}
+
+	return filtered
+}
+
+// baggagePropagator injects the baggage a BaggagePolicy allows and extracts
+// incoming baggage unchanged.
+type baggagePropagator struct {
+	policy BaggagePolicy
+}
+
+// NewBaggagePropagator returns a W3C baggage propagator that applies policy
+// to the baggage it injects.
+func NewBaggagePropagator(policy BaggagePolicy) propagation.TextMapPropagator {
+	return baggagePropagator{policy: policy}
+}
+
+// Inject implements propagation.TextMapPropagator.
+func (b baggagePropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	filtered := b.policy.Filter(baggage.FromContext(ctx))
+	propagation.Baggage{}.Inject(baggage.ContextWithBaggage(ctx, filtered), carrier)
+}
+
+// Extract implements propagation.TextMapPropagator.
+func (b baggagePropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return propagation.Baggage{}.Extract(ctx, carrier)
+}
+
+// Fields implements propagation.TextMapPropagator.
+func (b baggagePropagator) Fields() []string {
+	return propagation.Baggage{}.Fields()
+}
+
+// PropagatorOf returns the propagator of provider for outgoing requests, by
+// default trace context and the baggage allowed by DefaultBaggageMaxBytes.
+func PropagatorOf(provider Provider) propagation.TextMapPropagator {
+	if p, ok := provider.(*MidazProvider); ok {
+		return p.Propagator()
+	}
+
+	return propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		NewBaggagePropagator(BaggagePolicy{MaxBytes: DefaultBaggageMaxBytes}),
+	)
+}
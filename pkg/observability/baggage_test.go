@@ -0,0 +1,111 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func testBaggage(t *testing.T, kv ...string) baggage.Baggage {
+	t.Helper()
+
+	members := make([]baggage.Member, 0, len(kv)/2)
+
+	for i := 0; i < len(kv); i += 2 {
+		m, err := baggage.NewMember(kv[i], kv[i+1])
+		require.NoError(t, err)
+
+		members = append(members, m)
+	}
+
+	b, err := baggage.New(members...)
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestBaggagePolicy_Filter(t *testing.T) {
+	b := testBaggage(t, "tenant", "acme", "internal.correlation", "abc123", "midaz.request", "r-1", "midaz.flow", "f-1")
+
+	tests := []struct {
+		name   string
+		policy BaggagePolicy
+		want   []string
+	}{
+		{name: "nil allowlist keeps everything", policy: BaggagePolicy{}, want: []string{"internal.correlation", "midaz.flow", "midaz.request", "tenant"}},
+		{name: "empty allowlist keeps nothing", policy: BaggagePolicy{AllowedKeys: []string{}}, want: nil},
+		{name: "exact and prefix keys", policy: BaggagePolicy{AllowedKeys: []string{"tenant", "midaz.*"}}, want: []string{"midaz.flow", "midaz.request", "tenant"}},
+		{name: "size cap drops what doesn't fit", policy: BaggagePolicy{AllowedKeys: []string{"midaz.*", "tenant"}, MaxBytes: len("midaz.flow=f-1,tenant=acme")}, want: []string{"midaz.flow", "tenant"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered := tc.policy.Filter(b)
+
+			var keys []string
+			for _, m := range filtered.Members() {
+				keys = append(keys, m.Key())
+			}
+
+			assert.ElementsMatch(t, tc.want, keys)
+
+			if tc.policy.MaxBytes > 0 {
+				assert.LessOrEqual(t, len(filtered.String()), tc.policy.MaxBytes)
+			}
+		})
+	}
+}
+
+func TestBaggagePropagator(t *testing.T) {
+	ctx := baggage.ContextWithBaggage(context.Background(), testBaggage(t, "tenant", "acme", "secret", "s3cr3t"))
+	carrier := propagation.MapCarrier{}
+
+	NewBaggagePropagator(BaggagePolicy{AllowedKeys: []string{"tenant"}}).Inject(ctx, carrier)
+	assert.Equal(t, "tenant=acme", carrier.Get("baggage"))
+
+	extracted := NewBaggagePropagator(BaggagePolicy{AllowedKeys: []string{}}).Extract(context.Background(), propagation.MapCarrier{"baggage": "secret=s3cr3t"})
+	assert.Equal(t, "s3cr3t", baggage.FromContext(extracted).Member("secret").Value(), "incoming baggage is not filtered")
+}
+
+func TestHTTPMiddleware_BaggagePolicy(t *testing.T) {
+	var header string
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("baggage")
+	}))
+	defer server.Close()
+
+	provider, err := New(context.Background(),
+		WithRegisterGlobally(false),
+		WithBaggageAllowlist("tenant"),
+		WithBaggageMaxBytes(1024),
+	)
+	require.NoError(t, err)
+
+	defer func() { _ = provider.Shutdown(context.Background()) }() //nolint:errcheck // test cleanup
+
+	client := &http.Client{Transport: NewHTTPMiddleware(provider)(http.DefaultTransport)}
+
+	ctx := baggage.ContextWithBaggage(context.Background(), testBaggage(t, "tenant", "acme", "internal.correlation", "abc123"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "tenant=acme", header)
+	assert.False(t, strings.Contains(header, "correlation"))
+}
+
+func TestWithBaggageMaxBytes_Invalid(t *testing.T) {
+	_, err := New(context.Background(), WithBaggageMaxBytes(-1))
+	assert.Error(t, err)
+}
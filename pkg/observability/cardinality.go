@@ -0,0 +1,207 @@
+package observability
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// CardinalityPolicy is what a CardinalityGuard does with the values of an attribute.
+type CardinalityPolicy int
+
+const (
+	// CardinalityKeep records the value as is, subject to MaxValuesPerKey
+	CardinalityKeep CardinalityPolicy = iota
+
+	// CardinalityDrop removes the attribute
+	CardinalityDrop
+
+	// CardinalityHash replaces the value with one of HashBuckets buckets
+	CardinalityHash
+
+	// CardinalityTemplate replaces ID segments of a path or URL, such as UUIDs,
+	// numbers and aliases, with ":id" and removes the query string
+	CardinalityTemplate
+)
+
+const (
+	// OverflowValue replaces the values of an attribute past MaxValuesPerKey
+	OverflowValue = "__overflow__"
+
+	// DefaultMaxValuesPerKey is the distinct values recorded per attribute by the default config
+	DefaultMaxValuesPerKey = 100
+
+	// DefaultHashBuckets is the number of buckets of CardinalityHash when unset
+	DefaultHashBuckets = 64
+
+	templateID = ":id"
+)
+
+// CardinalityConfig configures a CardinalityGuard.
+type CardinalityConfig struct {
+	// Policies sets the policy of attribute keys; keys not listed are kept
+	Policies map[string]CardinalityPolicy
+
+	// MaxValuesPerKey caps the distinct values recorded per attribute key after
+	// the policy is applied; later values become OverflowValue. Zero means no cap.
+	MaxValuesPerKey int
+
+	// HashBuckets is the number of values CardinalityHash maps to, DefaultHashBuckets if zero
+	HashBuckets int
+}
+
+// DefaultCardinalityConfig drops account and resource IDs, templates request
+// paths and caps every attribute at DefaultMaxValuesPerKey values.
+func DefaultCardinalityConfig() CardinalityConfig {
+	return CardinalityConfig{
+		Policies: map[string]CardinalityPolicy{
+			KeyAccountID:    CardinalityDrop,
+			KeyResourceID:   CardinalityDrop,
+			KeyResourceType: CardinalityTemplate,
+			KeyHTTPPath:     CardinalityTemplate,
+		},
+		MaxValuesPerKey: DefaultMaxValuesPerKey,
+		HashBuckets:     DefaultHashBuckets,
+	}
+}
+
+// CardinalityGuard bounds the label cardinality of emitted metrics, as a
+// label per account or per request URL creates a time series each and can
+// overwhelm the metrics backend. It is safe for concurrent use; a nil guard
+// leaves attributes unchanged.
+type CardinalityGuard struct {
+	config CardinalityConfig
+
+	mu   sync.Mutex
+	seen map[attribute.Key]map[attribute.Value]struct{}
+}
+
+// NewCardinalityGuard creates a CardinalityGuard with the given config.
+func NewCardinalityGuard(config CardinalityConfig) *CardinalityGuard {
+	if config.HashBuckets <= 0 {
+		config.HashBuckets = DefaultHashBuckets
+	}
+
+	return &CardinalityGuard{
+		config: config,
+		seen:   make(map[attribute.Key]map[attribute.Value]struct{}),
+	}
+}
+
+// Apply returns attrs with the policies and the value cap applied. attrs is
+// not modified.
+func (g *CardinalityGuard) Apply(attrs []attribute.KeyValue) []attribute.KeyValue {
+	if g == nil || len(attrs) == 0 {
+		return attrs
+	}
+
+	out := make([]attribute.KeyValue, 0, len(attrs))
+
+	for _, kv := range attrs {
+		switch g.config.Policies[string(kv.Key)] {
+		case CardinalityDrop:
+			continue
+		case CardinalityHash:
+			kv = kv.Key.String(g.bucket(kv.Value.Emit()))
+		case CardinalityTemplate:
+			kv = kv.Key.String(templatePath(kv.Value.Emit()))
+		case CardinalityKeep:
+		}
+
+		out = append(out, g.capped(kv))
+	}
+
+	return out
+}
+
+// capped replaces the value of kv with OverflowValue once its key has
+// MaxValuesPerKey other values.
+func (g *CardinalityGuard) capped(kv attribute.KeyValue) attribute.KeyValue {
+	if g.config.MaxValuesPerKey <= 0 {
+		return kv
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	values := g.seen[kv.Key]
+	if values == nil {
+		values = make(map[attribute.Value]struct{})
+		g.seen[kv.Key] = values
+	}
+
+	if _, ok := values[kv.Value]; ok {
+		return kv
+	}
+
+	if len(values) >= g.config.MaxValuesPerKey {
+		return kv.Key.String(OverflowValue)
+	}
+
+	values[kv.Value] = struct{}{}
+
+	return kv
+}
+
+func (g *CardinalityGuard) bucket(value string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(value)) //nolint:errcheck // hash writes never fail
+
+	return fmt.Sprintf("bucket-%d", h.Sum32()%uint32(g.config.HashBuckets)) //nolint:gosec // HashBuckets is positive
+}
+
+// templatePath replaces the ID segments of a path or URL with ":id".
+func templatePath(path string) string {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isIDSegment(segment) {
+			segments[i] = templateID
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func isIDSegment(segment string) bool {
+	if segment == "" {
+		return false
+	}
+
+	if strings.HasPrefix(segment, "@") {
+		return true
+	}
+
+	if _, err := uuid.Parse(segment); err == nil {
+		return true
+	}
+
+	for _, r := range segment {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cardinalityGuarded is implemented by providers with a CardinalityGuard.
+type cardinalityGuarded interface {
+	CardinalityGuard() *CardinalityGuard
+}
+
+// guardOf returns the CardinalityGuard of provider, nil if it has none.
+func guardOf(provider Provider) *CardinalityGuard {
+	if g, ok := provider.(cardinalityGuarded); ok {
+		return g.CardinalityGuard()
+	}
+
+	return nil
+}
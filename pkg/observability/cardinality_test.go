@@ -0,0 +1,167 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func attrValue(attrs []attribute.KeyValue, key string) (string, bool) {
+	for _, kv := range attrs {
+		if string(kv.Key) == key {
+			return kv.Value.Emit(), true
+		}
+	}
+
+	return "", false
+}
+
+func TestCardinalityGuard_Policies(t *testing.T) {
+	guard := NewCardinalityGuard(CardinalityConfig{
+		Policies: map[string]CardinalityPolicy{
+			KeyAccountID:      CardinalityDrop,
+			KeyOrganizationID: CardinalityHash,
+			KeyResourceType:   CardinalityTemplate,
+		},
+		HashBuckets: 8,
+	})
+
+	attrs := []attribute.KeyValue{
+		attribute.String(KeyAccountID, "acc-1"),
+		attribute.String(KeyOrganizationID, "org-1"),
+		attribute.String(KeyResourceType, "https://api.example.com/v1/organizations/0195f1a8-27c6-7c44-92e3-3d5a8f7b1c2e/ledgers/42/accounts/alias/@customer_1?limit=10"),
+		attribute.Int(KeyHTTPStatus, 200),
+	}
+
+	out := guard.Apply(attrs)
+
+	_, ok := attrValue(out, KeyAccountID)
+	assert.False(t, ok, "account IDs are dropped")
+
+	org, _ := attrValue(out, KeyOrganizationID)
+	assert.Regexp(t, `^bucket-[0-7]$`, org)
+
+	again, _ := attrValue(guard.Apply(attrs), KeyOrganizationID)
+	assert.Equal(t, org, again, "hashing is stable")
+
+	resource, _ := attrValue(out, KeyResourceType)
+	assert.Equal(t, "https://api.example.com/v1/organizations/:id/ledgers/:id/accounts/alias/:id", resource)
+
+	status, _ := attrValue(out, KeyHTTPStatus)
+	assert.Equal(t, "200", status)
+
+	assert.Equal(t, "acc-1", attrs[0].Value.Emit(), "the input is not modified")
+}
+
+func TestCardinalityGuard_MaxValuesPerKey(t *testing.T) {
+	guard := NewCardinalityGuard(CardinalityConfig{MaxValuesPerKey: 2})
+
+	var wg sync.WaitGroup
+
+	for i := range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			guard.Apply([]attribute.KeyValue{attribute.String(KeyLedgerID, fmt.Sprintf("ledger-%d", i))})
+		}()
+	}
+
+	wg.Wait()
+
+	seen := map[string]bool{}
+
+	for i := range 10 {
+		v, _ := attrValue(guard.Apply([]attribute.KeyValue{attribute.String(KeyLedgerID, fmt.Sprintf("ledger-%d", i))}), KeyLedgerID)
+		seen[v] = true
+	}
+
+	assert.Len(t, seen, 3, "two values and the overflow value")
+	assert.True(t, seen[OverflowValue])
+}
+
+func TestCardinalityGuard_Nil(t *testing.T) {
+	var guard *CardinalityGuard
+
+	attrs := []attribute.KeyValue{attribute.String(KeyAccountID, "acc-1")}
+	assert.Equal(t, attrs, guard.Apply(attrs))
+}
+
+func TestDefaultConfig_CardinalityGuard(t *testing.T) {
+	provider, err := New(context.Background())
+	require.NoError(t, err)
+
+	guard := guardOf(provider)
+	require.NotNil(t, guard)
+
+	_, ok := attrValue(guard.Apply([]attribute.KeyValue{attribute.String(KeyAccountID, "acc-1")}), KeyAccountID)
+	assert.False(t, ok)
+
+	provider, err = New(context.Background(), WithCardinalityGuard(nil))
+	require.NoError(t, err)
+	assert.Nil(t, guardOf(provider))
+}
+
+// meterProvider is a Provider recording metrics into a manual reader.
+type meterProvider struct {
+	meter metric.Meter
+	guard *CardinalityGuard
+}
+
+func (p *meterProvider) Tracer() trace.Tracer                { return tracenoop.NewTracerProvider().Tracer("test") }
+func (p *meterProvider) Meter() metric.Meter                 { return p.meter }
+func (p *meterProvider) Logger() Logger                      { return NewNoopLogger() }
+func (p *meterProvider) Shutdown(context.Context) error      { return nil }
+func (p *meterProvider) IsEnabled() bool                     { return true }
+func (p *meterProvider) CardinalityGuard() *CardinalityGuard { return p.guard }
+
+func TestMetricsCollector_CardinalityGuard(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := &meterProvider{
+		meter: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"),
+		guard: NewCardinalityGuard(DefaultCardinalityConfig()),
+	}
+
+	collector, err := NewMetricsCollector(provider)
+	require.NoError(t, err)
+
+	for _, id := range []string{"0195f1a8-27c6-7c44-92e3-3d5a8f7b1c2e", "0195f1a8-27c6-7c44-92e3-3d5a8f7b1c2f"} {
+		collector.RecordRequest(context.Background(), "GET", "/v1/organizations/"+id+"/ledgers", 200, time.Millisecond,
+			attribute.String(KeyAccountID, id))
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	var total *metricdata.Sum[float64]
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == MetricRequestTotal {
+				sum, ok := m.Data.(metricdata.Sum[float64])
+				require.True(t, ok)
+
+				total = &sum
+			}
+		}
+	}
+
+	require.NotNil(t, total)
+	require.Len(t, total.DataPoints, 1, "both requests share one series")
+	assert.InDelta(t, 2, total.DataPoints[0].Value, 0)
+
+	resource, _ := total.DataPoints[0].Attributes.Value(KeyResourceType)
+	assert.Equal(t, "/v1/organizations/:id/ledgers", resource.AsString())
+	assert.False(t, total.DataPoints[0].Attributes.HasValue(KeyAccountID))
+}
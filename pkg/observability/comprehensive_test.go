@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/envdetect"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/attribute"
@@ -498,6 +499,24 @@ func TestWithAttributes(t *testing.T) {
 	assert.Len(t, config.Attributes, 3)
 }
 
+func TestApplyDetectedResource(t *testing.T) {
+	config := DefaultConfig()
+
+	applyDetectedResource(config, envdetect.Info{Kubernetes: true, Namespace: "ledger-dev", CloudProvider: "gcp"})
+	assert.Equal(t, "development", config.Environment)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("k8s.namespace.name", "ledger-dev"),
+		attribute.String("cloud.provider", "gcp"),
+	}, config.Attributes)
+
+	// Without a hint the environment is kept
+	config = DefaultConfig()
+
+	applyDetectedResource(config, envdetect.Info{CloudProvider: "aws", CloudRegion: "us-east-1"})
+	assert.Equal(t, "production", config.Environment)
+	assert.Len(t, config.Attributes, 2)
+}
+
 // =============================================================================
 // Provider Tests
 // =============================================================================
@@ -271,8 +271,7 @@ func (m *httpMiddleware) addRequestHeaders(span trace.Span, req *http.Request) {
 func (m *httpMiddleware) injectTraceContext(ctx context.Context, req *http.Request, span trace.Span) *http.Request {
 	// Inject trace context into request headers
 	carrier := propagation.HeaderCarrier(req.Header)
-	propagator := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
-	propagator.Inject(ctx, carrier)
+	PropagatorOf(m.provider).Inject(ctx, carrier)
 
 	// Update request with trace context
 	return req.WithContext(httptrace.WithClientTrace(ctx, m.createClientTrace(span)))
@@ -7,6 +7,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	sdkresource "go.opentelemetry.io/otel/sdk/resource"
@@ -89,7 +90,7 @@ type Logger interface {
 
 // LoggerImpl is the standard implementation of the Logger interface
 type LoggerImpl struct {
-	level    LogLevel
+	level    *atomic.Int32 // shared with the loggers derived by With, see SetLevel
 	output   io.Writer
 	fields   map[string]any
 	exitFunc func(int) // Injectable exit function for testing. If nil, Fatal just logs without exiting.
@@ -110,8 +111,11 @@ func NewLogger(level LogLevel, output io.Writer, resource *sdkresource.Resource)
 		}
 	}
 
+	lvl := new(atomic.Int32)
+	lvl.Store(int32(level)) //nolint:gosec // log levels are small
+
 	return &LoggerImpl{
-		level:    level,
+		level:    lvl,
 		output:   output,
 		fields:   fields,
 		exitFunc: nil, // Library code should not call os.Exit; callers can set this if needed
@@ -125,9 +129,20 @@ func (l *LoggerImpl) SetExitFunc(exitFunc func(int)) {
 	l.exitFunc = exitFunc
 }
 
+// SetLevel changes the minimum level of the logger and of every logger
+// derived from it with With, WithContext or WithSpan.
+func (l *LoggerImpl) SetLevel(level LogLevel) {
+	l.level.Store(int32(level)) //nolint:gosec // log levels are small
+}
+
+// Level returns the minimum level of the logger.
+func (l *LoggerImpl) Level() LogLevel {
+	return LogLevel(l.level.Load())
+}
+
 // log logs a message at the specified level
 func (l *LoggerImpl) log(level LogLevel, msg string) {
-	if level < l.level {
+	if level < l.Level() {
 		return
 	}
 
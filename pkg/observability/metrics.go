@@ -13,6 +13,10 @@ import (
 type MetricsCollector struct {
 	provider Provider
 
+	// guard overrides the CardinalityGuard of the provider when guardSet
+	guard    *CardinalityGuard
+	guardSet bool
+
 	// Counters
 	requestCounter metric.Float64Counter
 	errorCounter   metric.Float64Counter
@@ -104,6 +108,22 @@ func NewMetricsCollector(provider Provider) (*MetricsCollector, error) {
 	}, nil
 }
 
+// SetCardinalityGuard replaces the guard applied to the attributes of the
+// recorded metrics, by default the one of the provider. A nil guard disables it.
+func (m *MetricsCollector) SetCardinalityGuard(guard *CardinalityGuard) {
+	m.guard = guard
+	m.guardSet = true
+}
+
+// cardinalityGuard returns the guard applied to the recorded attributes.
+func (m *MetricsCollector) cardinalityGuard() *CardinalityGuard {
+	if m.guardSet {
+		return m.guard
+	}
+
+	return guardOf(m.provider)
+}
+
 // RecordRequest records a request with its result and duration
 func (m *MetricsCollector) RecordRequest(ctx context.Context, operation, resourceType string, statusCode int, duration time.Duration, attrs ...attribute.KeyValue) {
 	// If provider is not enabled, do nothing
@@ -121,7 +141,7 @@ func (m *MetricsCollector) RecordRequest(ctx context.Context, operation, resourc
 	)
 
 	// Combine with additional attributes
-	allAttrs := append(baseAttrs, attrs...)
+	allAttrs := m.cardinalityGuard().Apply(append(baseAttrs, attrs...))
 
 	// Record request
 	m.requestCounter.Add(ctx, 1, metric.WithAttributes(allAttrs...))
@@ -155,7 +175,7 @@ func (m *MetricsCollector) RecordBatchRequest(ctx context.Context, operation, re
 	)
 
 	// Combine with additional attributes
-	allAttrs := append(baseAttrs, attrs...)
+	allAttrs := m.cardinalityGuard().Apply(append(baseAttrs, attrs...))
 
 	// Record batch size
 	m.requestBatchSize.Record(ctx, int64(batchSize), metric.WithAttributes(allAttrs...))
@@ -181,7 +201,7 @@ func (m *MetricsCollector) RecordRetry(ctx context.Context, operation, resourceT
 	)
 
 	// Combine with additional attributes
-	allAttrs := append(baseAttrs, attrs...)
+	allAttrs := m.cardinalityGuard().Apply(append(baseAttrs, attrs...))
 
 	// Record retry
 	m.retryCounter.Add(ctx, 1, metric.WithAttributes(allAttrs...))
@@ -7,9 +7,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/envdetect"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/version"
+	"go.opentelemetry.io/contrib/propagators/b3"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -114,11 +117,29 @@ type Config struct {
 	// Headers to extract for trace context propagation
 	PropagationHeaders []string
 
+	// B3Propagation adds B3 headers to the W3C trace context, injecting and
+	// extracting both; ignored when Propagators is set
+	B3Propagation bool
+
+	// BaggageAllowlist are the baggage keys propagated with outgoing requests,
+	// see BaggagePolicy. Nil propagates every key.
+	BaggageAllowlist []string
+
+	// BaggageMaxBytes caps the size of the outgoing baggage header; zero means no cap
+	BaggageMaxBytes int
+
 	// RegisterGlobally controls whether to register providers as global OpenTelemetry providers.
 	// When true (default), providers are registered globally via otel.Set*Provider calls.
 	// When false, providers are only available via this MidazProvider instance, avoiding
 	// conflicts when multiple SDK instances are used in the same process.
 	RegisterGlobally bool
+
+	// CardinalityGuard bounds the label cardinality of metrics. DefaultConfig
+	// sets one with DefaultCardinalityConfig; WithCardinalityGuard(nil) disables it.
+	CardinalityGuard *CardinalityGuard
+
+	// SpanEnricher is called for every span started through the provider, see WithSpanEnricher
+	SpanEnricher SpanEnricher
 }
 
 // EnabledComponents controls which observability components are enabled
@@ -255,6 +276,26 @@ func WithAttributes(attrs ...attribute.KeyValue) Option {
 	}
 }
 
+// WithDetectedResource adds to all telemetry the resource attributes of where
+// the program runs, such as k8s.namespace.name, cloud.provider and
+// cloud.region, and sets the environment when the detection suggests one,
+// see envdetect.Detect. Options applied after it, such as WithEnvironment,
+// override the detected environment.
+func WithDetectedResource() Option {
+	return func(c *Config) error {
+		applyDetectedResource(c, envdetect.Detect())
+		return nil
+	}
+}
+
+func applyDetectedResource(c *Config, info envdetect.Info) {
+	c.Attributes = append(c.Attributes, info.Attributes()...)
+
+	if env, _ := info.Environment(); env != "" {
+		c.Environment = env
+	}
+}
+
 // WithPropagators sets the propagators for context propagation
 func WithPropagators(propagators ...propagation.TextMapPropagator) Option {
 	return func(c *Config) error {
@@ -281,6 +322,42 @@ func WithPropagationHeaders(headers ...string) Option {
 	}
 }
 
+// WithW3CAndB3Propagation propagates trace context in both the W3C
+// traceparent header and the multi-header B3 format (X-B3-TraceId and
+// related headers), for environments where upstream services still emit B3.
+// Incoming requests are read from either format, including single-header B3.
+func WithW3CAndB3Propagation() Option {
+	return func(c *Config) error {
+		c.B3Propagation = true
+		return nil
+	}
+}
+
+// WithBaggageAllowlist restricts the baggage propagated with outgoing requests
+// to the given keys, so internal correlation IDs don't leak to third parties.
+// A key ending with "*" allows every key with that prefix; no keys propagates
+// no baggage.
+func WithBaggageAllowlist(keys ...string) Option {
+	return func(c *Config) error {
+		c.BaggageAllowlist = append([]string{}, keys...)
+		return nil
+	}
+}
+
+// WithBaggageMaxBytes caps the size of the outgoing baggage header. Zero
+// removes the cap.
+func WithBaggageMaxBytes(maxBytes int) Option {
+	return func(c *Config) error {
+		if maxBytes < 0 {
+			return fmt.Errorf("baggage max bytes must not be negative, got %d", maxBytes)
+		}
+
+		c.BaggageMaxBytes = maxBytes
+
+		return nil
+	}
+}
+
 // WithRegisterGlobally controls whether to register providers as global OpenTelemetry providers.
 // When true (default), providers are registered globally via otel.Set*Provider calls.
 // When false, providers are only available via this MidazProvider instance, avoiding
@@ -293,6 +370,15 @@ func WithRegisterGlobally(register bool) Option {
 	}
 }
 
+// WithCardinalityGuard sets the guard bounding the label cardinality of
+// metrics, replacing the default one. A nil guard disables it.
+func WithCardinalityGuard(guard *CardinalityGuard) Option {
+	return func(c *Config) error {
+		c.CardinalityGuard = guard
+		return nil
+	}
+}
+
 // WithHighTracingSampling sets a high trace sampling rate (0.5) for development environments
 func WithHighTracingSampling() Option {
 	return WithTraceSampleRate(0.5)
@@ -361,13 +447,20 @@ func DefaultConfig() *Config {
 			"x-correlation-id",
 		},
 		RegisterGlobally: true,
+		BaggageMaxBytes:  DefaultBaggageMaxBytes,
+		CardinalityGuard: NewCardinalityGuard(DefaultCardinalityConfig()),
 	}
 }
 
 // MidazProvider is the main implementation of the Provider interface
 // It provides access to OpenTelemetry tracing, metrics, and logging
 type MidazProvider struct {
+	// mu guards the fields changed by Update and Shutdown
+	mu sync.RWMutex
+
 	config            *Config
+	resource          *sdkresource.Resource
+	sampler           *ratioSampler
 	tracerProvider    *sdktrace.TracerProvider
 	meterProvider     *sdkmetric.MeterProvider
 	logger            Logger
@@ -397,6 +490,7 @@ func New(ctx context.Context, opts ...Option) (Provider, error) {
 
 	// Create a resource with service information
 	res := provider.createResource()
+	provider.resource = res
 
 	// Initialize tracing if enabled
 	if config.EnabledComponents.Tracing {
@@ -484,9 +578,29 @@ func NewWithConfig(ctx context.Context, config *Config) (Provider, error) {
 		opts = append(opts, WithPropagationHeaders(config.PropagationHeaders...))
 	}
 
+	if config.B3Propagation {
+		opts = append(opts, WithW3CAndB3Propagation())
+	}
+
 	// Always set RegisterGlobally
 	opts = append(opts, WithRegisterGlobally(config.RegisterGlobally))
 
+	if config.CardinalityGuard != nil {
+		opts = append(opts, WithCardinalityGuard(config.CardinalityGuard))
+	}
+
+	if config.BaggageAllowlist != nil {
+		opts = append(opts, WithBaggageAllowlist(config.BaggageAllowlist...))
+	}
+
+	if config.BaggageMaxBytes > 0 {
+		opts = append(opts, WithBaggageMaxBytes(config.BaggageMaxBytes))
+	}
+
+	if config.SpanEnricher != nil {
+		opts = append(opts, WithSpanEnricher(config.SpanEnricher))
+	}
+
 	return New(ctx, opts...)
 }
 
@@ -516,6 +630,10 @@ func (p *MidazProvider) createResource() *sdkresource.Resource {
 
 // initTracing initializes OpenTelemetry tracing
 func (p *MidazProvider) initTracing(ctx context.Context, res *sdkresource.Resource) error {
+	if p.sampler == nil {
+		p.sampler = newRatioSampler(p.config.TraceSampleRate)
+	}
+
 	var exporter *otlptrace.Exporter
 
 	var err error
@@ -541,7 +659,7 @@ func (p *MidazProvider) initTracing(ctx context.Context, res *sdkresource.Resour
 	p.tracerProvider = sdktrace.NewTracerProvider(
 		sdktrace.WithBatcher(exporter),
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(p.config.TraceSampleRate)),
+		sdktrace.WithSampler(p.sampler),
 	)
 
 	// Set the global trace provider only if RegisterGlobally is true
@@ -615,32 +733,56 @@ func (p *MidazProvider) setupPropagation() {
 		return
 	}
 
-	// Set up propagators if provided, otherwise use defaults
+	otel.SetTextMapPropagator(p.propagator())
+}
+
+// propagator returns the configured propagators if any, otherwise W3C trace
+// context, B3 if enabled, and the baggage the baggage policy allows.
+func (p *MidazProvider) propagator() propagation.TextMapPropagator {
 	if len(p.config.Propagators) > 0 {
-		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-			p.config.Propagators...,
-		))
-	} else {
-		// Use default propagators
-		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-			propagation.TraceContext{},
-			propagation.Baggage{},
-		))
+		return propagation.NewCompositeTextMapPropagator(p.config.Propagators...)
+	}
+
+	propagators := []propagation.TextMapPropagator{propagation.TraceContext{}}
+	if p.config.B3Propagation {
+		propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
 	}
+
+	propagators = append(propagators, NewBaggagePropagator(p.baggagePolicy()))
+
+	return propagation.NewCompositeTextMapPropagator(propagators...)
+}
+
+// Propagator returns the propagator used for outgoing requests.
+func (p *MidazProvider) Propagator() propagation.TextMapPropagator {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.propagator()
 }
 
 // Tracer returns a tracer for creating spans
 func (p *MidazProvider) Tracer() trace.Tracer {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	if !p.enabled || !p.config.EnabledComponents.Tracing {
 		// Return a no-op tracer if tracing is disabled
 		return noop.NewTracerProvider().Tracer("")
 	}
 
+	if p.config.SpanEnricher != nil {
+		return enrichingTracer{Tracer: p.tracer, enrich: p.config.SpanEnricher}
+	}
+
 	return p.tracer
 }
 
 // Meter returns a meter for creating metrics
 func (p *MidazProvider) Meter() metric.Meter {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	if !p.enabled || !p.config.EnabledComponents.Metrics || p.meter == nil {
 		// Return the default global meter if metrics are disabled
 		return otel.GetMeterProvider().Meter("")
@@ -651,6 +793,9 @@ func (p *MidazProvider) Meter() metric.Meter {
 
 // Logger returns a logger
 func (p *MidazProvider) Logger() Logger {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	if !p.enabled || !p.config.EnabledComponents.Logging {
 		// Return a no-op logger if logging is disabled
 		return NewNoopLogger()
@@ -661,6 +806,9 @@ func (p *MidazProvider) Logger() Logger {
 
 // Shutdown gracefully shuts down the provider and all its components
 func (p *MidazProvider) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if !p.enabled {
 		return nil
 	}
@@ -683,8 +831,31 @@ func (p *MidazProvider) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// BaggagePolicy returns the policy applied to the baggage of outgoing requests.
+func (p *MidazProvider) BaggagePolicy() BaggagePolicy {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.baggagePolicy()
+}
+
+func (p *MidazProvider) baggagePolicy() BaggagePolicy {
+	return BaggagePolicy{AllowedKeys: p.config.BaggageAllowlist, MaxBytes: p.config.BaggageMaxBytes}
+}
+
+// CardinalityGuard returns the guard applied to metric attributes, nil if none.
+func (p *MidazProvider) CardinalityGuard() *CardinalityGuard {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.config.CardinalityGuard
+}
+
 // IsEnabled returns true if observability is enabled
 func (p *MidazProvider) IsEnabled() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	return p.enabled
 }
 
@@ -725,7 +896,7 @@ func RecordMetric(ctx context.Context, provider Provider, name string, value flo
 		return
 	}
 
-	counter.Add(ctx, value, metric.WithAttributes(attrs...))
+	counter.Add(ctx, value, metric.WithAttributes(guardOf(provider).Apply(attrs)...))
 }
 
 // RecordDuration records a duration metric using the provided meter
@@ -743,7 +914,7 @@ func RecordDuration(ctx context.Context, provider Provider, name string, start t
 		return
 	}
 
-	histogram.Record(ctx, duration, metric.WithAttributes(attrs...))
+	histogram.Record(ctx, duration, metric.WithAttributes(guardOf(provider).Apply(attrs)...))
 }
 
 // ExtractContext extracts context from HTTP headers for distributed tracing
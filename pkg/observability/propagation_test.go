@@ -0,0 +1,64 @@
+package observability
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithW3CAndB3Propagation(t *testing.T) {
+	p, _ := newReloadProvider(t, WithW3CAndB3Propagation(), WithTraceSampleRate(1))
+	propagator := PropagatorOf(p)
+
+	ctx, span := p.Tracer().Start(context.Background(), "outgoing")
+	defer span.End()
+
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+
+	sc := span.SpanContext()
+	assert.Contains(t, carrier.Get("traceparent"), sc.TraceID().String())
+	assert.Equal(t, sc.TraceID().String(), carrier.Get("x-b3-traceid"))
+	assert.Equal(t, sc.SpanID().String(), carrier.Get("x-b3-spanid"))
+	assert.Equal(t, "1", carrier.Get("x-b3-sampled"))
+
+	tests := []struct {
+		name    string
+		carrier propagation.MapCarrier
+	}{
+		{name: "w3c", carrier: propagation.MapCarrier{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}},
+		{name: "b3 multiple headers", carrier: propagation.MapCarrier{
+			"x-b3-traceid": "4bf92f3577b34da6a3ce929d0e0e4736",
+			"x-b3-spanid":  "00f067aa0ba902b7",
+			"x-b3-sampled": "1",
+		}},
+		{name: "b3 single header", carrier: propagation.MapCarrier{"b3": "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			extracted := trace.SpanContextFromContext(propagator.Extract(context.Background(), tc.carrier))
+			require.True(t, extracted.IsValid())
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", extracted.TraceID().String())
+			assert.Equal(t, "00f067aa0ba902b7", extracted.SpanID().String())
+			assert.True(t, extracted.IsSampled())
+		})
+	}
+}
+
+func TestDefaultPropagation_NoB3(t *testing.T) {
+	p, _ := newReloadProvider(t)
+
+	ctx, span := p.Tracer().Start(context.Background(), "outgoing")
+	defer span.End()
+
+	carrier := propagation.MapCarrier{}
+	PropagatorOf(p).Inject(ctx, carrier)
+
+	assert.NotEmpty(t, carrier.Get("traceparent"))
+	assert.Empty(t, carrier.Get("x-b3-traceid"))
+}
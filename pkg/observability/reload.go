@@ -0,0 +1,279 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ErrNotReloadable is returned by Update for options changing settings that
+// are fixed once the provider is created, such as the service name or the
+// collector endpoint.
+var ErrNotReloadable = errors.New("setting cannot be changed at runtime")
+
+// Environment variables read by OptionsFromEnv.
+const (
+	EnvTraceSampleRate = "MIDAZ_OTEL_TRACE_SAMPLE_RATE"
+	EnvLogLevel        = "MIDAZ_OTEL_LOG_LEVEL"
+	EnvTracing         = "MIDAZ_OTEL_TRACING"
+	EnvMetrics         = "MIDAZ_OTEL_METRICS"
+	EnvLogging         = "MIDAZ_OTEL_LOGGING"
+)
+
+// ratioSampler samples a ratio of the traces that can be changed while spans
+// are being started.
+type ratioSampler struct {
+	current atomic.Pointer[sdktrace.Sampler]
+}
+
+func newRatioSampler(rate float64) *ratioSampler {
+	s := &ratioSampler{}
+	s.set(rate)
+
+	return s
+}
+
+func (s *ratioSampler) set(rate float64) {
+	sampler := sdktrace.TraceIDRatioBased(rate)
+	s.current.Store(&sampler)
+}
+
+// ShouldSample implements sdktrace.Sampler.
+func (s *ratioSampler) ShouldSample(params sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return (*s.current.Load()).ShouldSample(params)
+}
+
+// Description implements sdktrace.Sampler.
+func (s *ratioSampler) Description() string {
+	return (*s.current.Load()).Description()
+}
+
+// fixed returns the settings of c that Update cannot change.
+func (c *Config) fixed() Config {
+	return Config{
+		ServiceName:        c.ServiceName,
+		ServiceVersion:     c.ServiceVersion,
+		SDKVersion:         c.SDKVersion,
+		Environment:        c.Environment,
+		CollectorEndpoint:  c.CollectorEndpoint,
+		LogOutput:          c.LogOutput,
+		Attributes:         c.Attributes,
+		Propagators:        c.Propagators,
+		PropagationHeaders: c.PropagationHeaders,
+		B3Propagation:      c.B3Propagation,
+		BaggageAllowlist:   c.BaggageAllowlist,
+		BaggageMaxBytes:    c.BaggageMaxBytes,
+		RegisterGlobally:   c.RegisterGlobally,
+	}
+}
+
+// Update applies opts to the running provider, so verbosity can be raised
+// during an incident without recreating the client. The trace sample rate,
+// the log level, the enabled components, the cardinality guard and the span
+// enricher can be changed; options changing other settings fail with
+// ErrNotReloadable and nothing is applied.
+//
+// Enabling a component that was disabled at creation initializes it. The
+// MetricsCollector of a client keeps the instruments it was created with, so
+// enable metrics at creation to record the SDK request metrics.
+//
+// Example:
+//
+//	provider := client.GetObservabilityProvider().(*observability.MidazProvider)
+//	err := provider.Update(
+//	    observability.WithLogLevel(observability.DebugLevel),
+//	    observability.WithTraceSampleRate(1.0),
+//	)
+func (p *MidazProvider) Update(opts ...Option) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.enabled {
+		return errors.New("observability provider is shut down")
+	}
+
+	next := *p.config
+	for _, opt := range opts {
+		if err := opt(&next); err != nil {
+			return fmt.Errorf("failed to apply option: %w", err)
+		}
+	}
+
+	if !reflect.DeepEqual(p.config.fixed(), next.fixed()) {
+		return fmt.Errorf("failed to update observability: %w", ErrNotReloadable)
+	}
+
+	previous := p.config
+	p.config = &next
+
+	if err := p.initMissing(); err != nil {
+		p.config = previous
+		return err
+	}
+
+	if p.sampler != nil {
+		p.sampler.set(next.TraceSampleRate)
+	}
+
+	if logger, ok := p.logger.(*LoggerImpl); ok {
+		logger.SetLevel(next.LogLevel)
+	}
+
+	return nil
+}
+
+// initMissing initializes the enabled components that were disabled at creation.
+func (p *MidazProvider) initMissing() error {
+	if p.config.EnabledComponents.Tracing && p.tracerProvider == nil {
+		if err := p.initTracing(context.Background(), p.resource); err != nil {
+			return fmt.Errorf("failed to initialize tracing: %w", err)
+		}
+	}
+
+	if p.config.EnabledComponents.Metrics && p.meterProvider == nil {
+		if err := p.initMetrics(context.Background(), p.resource); err != nil {
+			return fmt.Errorf("failed to initialize metrics: %w", err)
+		}
+	}
+
+	if p.config.EnabledComponents.Logging && p.logger == nil {
+		if err := p.initLogging(p.resource); err != nil {
+			return fmt.Errorf("failed to initialize logging: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Config returns a copy of the current configuration of the provider.
+func (p *MidazProvider) Config() Config {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return *p.config
+}
+
+// ReloadOnSignal calls load and applies the options it returns with Update
+// each time the process receives one of signals, SIGHUP if none are given,
+// until ctx is done. Under GOOS=js, which has no signals, it only waits for
+// ctx when none are given. Errors of load and Update are logged and leave the
+// configuration unchanged. OptionsFromEnv is a ready-made load function.
+//
+// Example:
+//
+//	// kill -HUP <pid> after exporting MIDAZ_OTEL_LOG_LEVEL=debug
+//	go provider.ReloadOnSignal(ctx, observability.OptionsFromEnv)
+func (p *MidazProvider) ReloadOnSignal(ctx context.Context, load func() ([]Option, error), signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = defaultReloadSignals
+	}
+
+	defer lifecycle.Track("observability.ReloadOnSignal")()
+
+	if len(signals) == 0 {
+		// signal.Notify without signals would relay all of them
+		<-ctx.Done()
+		return
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+
+	defer signal.Stop(ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+			p.reload(load)
+		}
+	}
+}
+
+func (p *MidazProvider) reload(load func() ([]Option, error)) {
+	opts, err := load()
+	if err == nil {
+		err = p.Update(opts...)
+	}
+
+	if err != nil {
+		p.Logger().Errorf("Failed to reload observability configuration: %v", err)
+		return
+	}
+
+	p.Logger().Infof("Reloaded observability configuration")
+}
+
+// OptionsFromEnv returns the options set by the MIDAZ_OTEL_* environment
+// variables: EnvTraceSampleRate (0.0 to 1.0), EnvLogLevel (debug, info, warn,
+// error or fatal) and EnvTracing, EnvMetrics and EnvLogging (true or false).
+// Unset variables leave the matching setting unchanged.
+func OptionsFromEnv() ([]Option, error) {
+	var opts []Option
+
+	if v := os.Getenv(EnvTraceSampleRate); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvTraceSampleRate, err)
+		}
+
+		opts = append(opts, WithTraceSampleRate(rate))
+	}
+
+	if v := os.Getenv(EnvLogLevel); v != "" {
+		level, err := ParseLogLevel(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvLogLevel, err)
+		}
+
+		opts = append(opts, WithLogLevel(level))
+	}
+
+	for env, set := range map[string]func(*EnabledComponents, bool){
+		EnvTracing: func(c *EnabledComponents, on bool) { c.Tracing = on },
+		EnvMetrics: func(c *EnabledComponents, on bool) { c.Metrics = on },
+		EnvLogging: func(c *EnabledComponents, on bool) { c.Logging = on },
+	} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", env, err)
+		}
+
+		opts = append(opts, func(c *Config) error {
+			set(&c.EnabledComponents, on)
+			return nil
+		})
+	}
+
+	return opts, nil
+}
+
+// ParseLogLevel parses a log level name such as "debug" or "WARN".
+func ParseLogLevel(name string) (LogLevel, error) {
+	for level := DebugLevel; level <= FatalLevel; level++ {
+		if strings.EqualFold(name, level.String()) {
+			return level, nil
+		}
+	}
+
+	if strings.EqualFold(name, "warning") {
+		return WarnLevel, nil
+	}
+
+	return InfoLevel, fmt.Errorf("unknown log level %q", name)
+}
@@ -0,0 +1,8 @@
+//go:build js
+
+package observability
+
+import "os"
+
+// defaultReloadSignals is empty under GOOS=js, which has no signals.
+var defaultReloadSignals []os.Signal
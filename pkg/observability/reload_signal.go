@@ -0,0 +1,11 @@
+//go:build !js
+
+package observability
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultReloadSignals are the signals ReloadOnSignal listens to by default.
+var defaultReloadSignals = []os.Signal{syscall.SIGHUP}
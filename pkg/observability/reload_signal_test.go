@@ -0,0 +1,54 @@
+//go:build !js
+
+package observability
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMidazProvider_ReloadOnSignal(t *testing.T) {
+	// Keep the default action of SIGHUP, terminating the process, away from the test
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGHUP)
+
+	defer signal.Stop(guard)
+
+	p, out := newReloadProvider(t, WithLogLevel(InfoLevel))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		p.ReloadOnSignal(ctx, func() ([]Option, error) {
+			return []Option{WithLogLevel(DebugLevel)}, nil
+		})
+	}()
+
+	self, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+
+	if err := self.Signal(syscall.SIGHUP); err != nil {
+		cancel()
+		t.Skipf("signals not supported: %v", err)
+	}
+
+	// The first signal may arrive before ReloadOnSignal listens
+	require.Eventually(t, func() bool {
+		_ = self.Signal(syscall.SIGHUP) //nolint:errcheck // checked above
+		return p.Config().LogLevel == DebugLevel
+	}, 5*time.Second, 20*time.Millisecond)
+
+	assert.Contains(t, out.String(), "Reloaded observability configuration")
+
+	cancel()
+	<-done
+}
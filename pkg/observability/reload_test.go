@@ -0,0 +1,133 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes and reads.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func newReloadProvider(t *testing.T, opts ...Option) (*MidazProvider, *syncBuffer) {
+	t.Helper()
+
+	out := &syncBuffer{}
+
+	provider, err := New(context.Background(), append([]Option{WithLogOutput(out), WithRegisterGlobally(false)}, opts...)...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) }) //nolint:errcheck // test cleanup
+
+	p, ok := provider.(*MidazProvider)
+	require.True(t, ok)
+
+	return p, out
+}
+
+func TestMidazProvider_Update_LogLevel(t *testing.T) {
+	p, out := newReloadProvider(t, WithLogLevel(InfoLevel))
+
+	derived := p.Logger().With(map[string]any{"component": "test"})
+
+	derived.Debug("hidden")
+	assert.NotContains(t, out.String(), "hidden")
+
+	require.NoError(t, p.Update(WithLogLevel(DebugLevel)))
+
+	derived.Debug("visible")
+	assert.Contains(t, out.String(), "visible", "loggers derived before the update follow it")
+	assert.Equal(t, DebugLevel, p.Config().LogLevel)
+}
+
+func TestMidazProvider_Update_SampleRate(t *testing.T) {
+	p, _ := newReloadProvider(t, WithTraceSampleRate(0))
+
+	_, span := p.Tracer().Start(context.Background(), "before")
+	assert.False(t, span.SpanContext().IsSampled())
+	span.End()
+
+	require.NoError(t, p.Update(WithTraceSampleRate(1)))
+
+	_, span = p.Tracer().Start(context.Background(), "after")
+	assert.True(t, span.SpanContext().IsSampled())
+	span.End()
+}
+
+func TestMidazProvider_Update_Components(t *testing.T) {
+	p, out := newReloadProvider(t, WithComponentEnabled(false, false, false))
+
+	assert.IsType(t, &NoopLogger{}, p.Logger())
+	_, span := p.Tracer().Start(context.Background(), "untraced")
+	assert.False(t, span.SpanContext().IsValid())
+
+	require.NoError(t, p.Update(WithComponentEnabled(true, false, true)))
+
+	p.Logger().Info("logging enabled")
+	assert.Contains(t, out.String(), "logging enabled")
+
+	_, span = p.Tracer().Start(context.Background(), "traced")
+	assert.True(t, span.SpanContext().IsValid())
+	span.End()
+
+	require.NoError(t, p.Update(WithComponentEnabled(false, false, false)))
+	assert.IsType(t, &NoopLogger{}, p.Logger())
+}
+
+func TestMidazProvider_Update_Rejected(t *testing.T) {
+	p, _ := newReloadProvider(t, WithLogLevel(InfoLevel))
+
+	err := p.Update(WithLogLevel(DebugLevel), WithServiceName("renamed"))
+	require.ErrorIs(t, err, ErrNotReloadable)
+	assert.Equal(t, InfoLevel, p.Config().LogLevel, "nothing is applied")
+
+	require.Error(t, p.Update(WithTraceSampleRate(2)))
+
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.Error(t, p.Update(WithLogLevel(DebugLevel)))
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	t.Setenv(EnvTraceSampleRate, "0.5")
+	t.Setenv(EnvLogLevel, "warning")
+	t.Setenv(EnvMetrics, "false")
+
+	opts, err := OptionsFromEnv()
+	require.NoError(t, err)
+
+	config := DefaultConfig()
+	for _, opt := range opts {
+		require.NoError(t, opt(config))
+	}
+
+	assert.InDelta(t, 0.5, config.TraceSampleRate, 0)
+	assert.Equal(t, WarnLevel, config.LogLevel)
+	assert.False(t, config.EnabledComponents.Metrics)
+	assert.True(t, config.EnabledComponents.Tracing, "unset variables are left unchanged")
+
+	t.Setenv(EnvLogLevel, "loud")
+
+	_, err = OptionsFromEnv()
+	assert.Error(t, err)
+}
@@ -0,0 +1,39 @@
+package observability
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanEnricher is called for every span started through a provider, with the
+// context of the new span and the span name as operation, so applications can
+// attach domain attributes such as a tenant or product to every SDK span.
+// It is only called for recording spans and must be safe for concurrent use.
+type SpanEnricher func(ctx context.Context, operation string, span trace.Span)
+
+// WithSpanEnricher sets the function enriching every span started through the
+// provider. A nil enricher disables it. It can be changed with Update.
+func WithSpanEnricher(enricher SpanEnricher) Option {
+	return func(c *Config) error {
+		c.SpanEnricher = enricher
+		return nil
+	}
+}
+
+// enrichingTracer is a tracer calling a SpanEnricher for the spans it starts.
+type enrichingTracer struct {
+	trace.Tracer
+
+	enrich SpanEnricher
+}
+
+// Start implements trace.Tracer.
+func (t enrichingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx, span := t.Tracer.Start(ctx, name, opts...)
+	if span.IsRecording() {
+		t.enrich(ctx, name, span)
+	}
+
+	return ctx, span
+}
@@ -0,0 +1,52 @@
+package observability
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithSpanEnricher(t *testing.T) {
+	enricher := func(ctx context.Context, operation string, span trace.Span) {
+		assert.Equal(t, span.SpanContext(), trace.SpanContextFromContext(ctx), "the context holds the new span")
+		span.SetAttributes(attribute.String("tenant", "acme"), attribute.String("operation", operation))
+	}
+
+	p, _ := newReloadProvider(t, WithFullTracingSampling(), WithSpanEnricher(enricher))
+
+	_, span := p.Tracer().Start(context.Background(), "HTTP GET /v1/organizations")
+	defer span.End()
+
+	ro, ok := span.(sdktrace.ReadOnlySpan)
+	require.True(t, ok)
+	assert.Contains(t, ro.Attributes(), attribute.String("tenant", "acme"))
+	assert.Contains(t, ro.Attributes(), attribute.String("operation", "HTTP GET /v1/organizations"))
+}
+
+func TestWithSpanEnricher_Update(t *testing.T) {
+	calls := 0
+	p, _ := newReloadProvider(t, WithTraceSampleRate(0))
+
+	require.NoError(t, p.Update(WithSpanEnricher(func(context.Context, string, trace.Span) { calls++ })))
+
+	_, span := p.Tracer().Start(context.Background(), "unsampled")
+	span.End()
+	assert.Equal(t, 0, calls, "spans that aren't recorded are not enriched")
+
+	require.NoError(t, p.Update(WithTraceSampleRate(1)))
+
+	_, span = p.Tracer().Start(context.Background(), "sampled")
+	span.End()
+	assert.Equal(t, 1, calls)
+
+	require.NoError(t, p.Update(WithSpanEnricher(nil)))
+
+	_, span = p.Tracer().Start(context.Background(), "plain")
+	span.End()
+	assert.Equal(t, 1, calls)
+}
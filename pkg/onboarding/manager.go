@@ -0,0 +1,234 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+)
+
+// Manager writes onboarding records to organizations and accounts and lists
+// them by stage.
+type Manager struct {
+	e     *entities.Entity
+	rules *validation.RuleSet[Record]
+	now   func() time.Time
+}
+
+// NewManager creates a Manager checking records with DefaultRules.
+func NewManager(e *entities.Entity) *Manager {
+	return &Manager{e: e, rules: DefaultRules(), now: time.Now}
+}
+
+// WithRules adds rules checked on every record written, after the others.
+func (m *Manager) WithRules(rules ...validation.Rule[Record]) *Manager {
+	for _, rule := range rules {
+		m.rules.Add(rule)
+	}
+
+	return m
+}
+
+// WithRuleSet replaces the rules checked on every record written, including
+// the default ones.
+func (m *Manager) WithRuleSet(rules *validation.RuleSet[Record]) *Manager {
+	if rules != nil {
+		m.rules = rules
+	}
+
+	return m
+}
+
+// Validate checks the record against the rules of the manager. It returns a
+// validation error of the errors package wrapping the *validation.FieldErrors
+// of the rules failed.
+func (m *Manager) Validate(r Record) error {
+	fieldErrs := m.rules.Evaluate(r)
+	if !fieldErrs.HasErrors() {
+		return nil
+	}
+
+	return errors.NewValidationError("onboarding", fmt.Sprintf("invalid onboarding record %s", r), fieldErrs)
+}
+
+// SetOrganization validates the record and writes it to the metadata of the
+// organization, together with r.Metadata, keeping the rest of its metadata.
+func (m *Manager) SetOrganization(ctx context.Context, orgID string, r Record) (*models.Organization, error) {
+	if m.e == nil || m.e.Organizations == nil {
+		return nil, errors.NewValidationError("onboarding", "organizations service not initialized", nil)
+	}
+
+	org, err := m.e.Organizations.GetOrganization(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization %s: %w", orgID, err)
+	}
+
+	metadata, err := m.prepare(org.Metadata, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.e.Organizations.UpdateOrganization(ctx, orgID, models.NewUpdateOrganizationInput().WithUpdateMetadata(metadata))
+}
+
+// SetAccount validates the record and writes it to the metadata of the
+// account, together with r.Metadata, keeping the rest of its metadata.
+func (m *Manager) SetAccount(ctx context.Context, orgID, ledgerID, accountID string, r Record) (*models.Account, error) {
+	if m.e == nil || m.e.Accounts == nil {
+		return nil, errors.NewValidationError("onboarding", "accounts service not initialized", nil)
+	}
+
+	account, err := m.e.Accounts.GetAccount(ctx, orgID, ledgerID, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account %s: %w", accountID, err)
+	}
+
+	metadata, err := m.prepare(account.Metadata, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.e.Accounts.UpdateAccount(ctx, orgID, ledgerID, accountID, models.NewUpdateAccountInput().WithMetadata(metadata))
+}
+
+// prepare stamps the record, checks it against the metadata it is merged
+// into and returns the merged metadata.
+func (m *Manager) prepare(current map[string]any, r Record) (map[string]any, error) {
+	r.UpdatedAt = m.now().UTC().Truncate(time.Second)
+
+	merged := make(map[string]any, len(current)+len(r.Metadata)+len(recordKeys))
+	for k, v := range current {
+		if !recordKeys[k] {
+			merged[k] = v
+		}
+	}
+
+	for k, v := range r.Metadata {
+		if !recordKeys[k] {
+			merged[k] = v
+		}
+	}
+
+	r.Metadata = merged
+
+	if err := m.Validate(r); err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]any, len(merged)+len(recordKeys))
+	for k, v := range merged {
+		metadata[k] = v
+	}
+
+	for k, v := range r.ToMetadata() {
+		metadata[k] = v
+	}
+
+	return metadata, nil
+}
+
+// OrganizationsInStage lists the organizations at the given onboarding stage,
+// filtered by the API on their metadata.
+func (m *Manager) OrganizationsInStage(ctx context.Context, stage Stage) ([]models.Organization, error) {
+	if m.e == nil || m.e.Organizations == nil {
+		return nil, errors.NewValidationError("onboarding", "organizations service not initialized", nil)
+	}
+
+	opts := models.ListOrganizations().FilterMetadata(MetadataStage, string(stage)).Limit(models.MaxLimit).Options()
+
+	orgs, err := listAll(ctx, opts, func(opts *models.ListOptions) (*models.ListResponse[models.Organization], error) {
+		return m.e.Organizations.ListOrganizations(ctx, opts)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	return slices.DeleteFunc(orgs, func(org models.Organization) bool {
+		return !inStage(org.Metadata, stage)
+	}), nil
+}
+
+// AccountsInStage lists the accounts of a ledger at the given onboarding
+// stage, filtered by the API on their metadata.
+func (m *Manager) AccountsInStage(ctx context.Context, orgID, ledgerID string, stage Stage) ([]models.Account, error) {
+	if m.e == nil || m.e.Accounts == nil {
+		return nil, errors.NewValidationError("onboarding", "accounts service not initialized", nil)
+	}
+
+	opts := models.ListAccounts().FilterMetadata(MetadataStage, string(stage)).Limit(models.MaxLimit).Options()
+
+	accounts, err := listAll(ctx, opts, func(opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+		return m.e.Accounts.ListAccounts(ctx, orgID, ledgerID, opts)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	return slices.DeleteFunc(accounts, func(account models.Account) bool {
+		return !inStage(account.Metadata, stage)
+	}), nil
+}
+
+// inStage reports whether the metadata holds a record at the stage, checked
+// again in case the API ignores the metadata filter.
+func inStage(metadata map[string]any, stage Stage) bool {
+	value, _ := metadata[MetadataStage].(string)
+	return Stage(value) == stage
+}
+
+// OrganizationRecord reads the onboarding record of an organization, false
+// when it has none.
+func OrganizationRecord(org *models.Organization) (Record, bool) {
+	if org == nil {
+		return Record{}, false
+	}
+
+	return FromMetadata(org.Metadata)
+}
+
+// AccountRecord reads the onboarding record of an account, false when it has
+// none.
+func AccountRecord(account *models.Account) (Record, bool) {
+	if account == nil {
+		return Record{}, false
+	}
+
+	return FromMetadata(account.Metadata)
+}
+
+// listAll returns the items of every page from opts on. The metadata filter
+// is carried to the next pages.
+func listAll[T any](ctx context.Context, opts *models.ListOptions, list func(*models.ListOptions) (*models.ListResponse[T], error)) ([]T, error) {
+	filters := opts.Filters
+
+	var items []T
+
+	for opts != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, err := list(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, page.Items...)
+
+		if len(page.Items) == 0 {
+			break
+		}
+
+		opts = page.Pagination.NextPageOptions()
+		if opts != nil {
+			opts.WithFilters(filters)
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,171 @@
+// Package onboarding keeps the KYC and onboarding state of organizations and
+// accounts in their metadata, so the ledger holds it next to the entities it
+// is about.
+//
+// A Record carries the stage of the onboarding, the status of that stage, the
+// references of the documents collected (IDs in a document store, never the
+// documents themselves) and the reviewer. The Manager writes records to
+// organizations and accounts, checking the fields each stage requires with a
+// validation.RuleSet, and lists the entities at a stage:
+//
+//	m := onboarding.NewManager(client.Entity)
+//
+//	_, err := m.SetAccount(ctx, orgID, ledgerID, accountID, onboarding.Record{
+//	    Stage:     onboarding.StageReview,
+//	    Status:    onboarding.StatusPending,
+//	    Documents: []string{"doc-passport-123", "doc-proof-of-address-456"},
+//	    Reviewer:  "ana@example.com",
+//	})
+//
+//	pending, err := m.AccountsInStage(ctx, orgID, ledgerID, onboarding.StageReview)
+//
+// Records are stored under the Metadata* keys; other metadata of the entity
+// is kept as it is.
+package onboarding
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Metadata keys of a record.
+const (
+	MetadataStage     = "onboardingStage"
+	MetadataStatus    = "onboardingStatus"
+	MetadataDocuments = "onboardingDocuments"
+	MetadataReviewer  = "onboardingReviewer"
+	MetadataNote      = "onboardingNote"
+	MetadataUpdatedAt = "onboardingUpdatedAt"
+)
+
+// Stage is a step of the onboarding.
+type Stage string
+
+// Onboarding stages, in order.
+const (
+	// StageApplication collects the details of the applicant
+	StageApplication Stage = "application"
+	// StageDocuments collects the documents of the applicant
+	StageDocuments Stage = "documents"
+	// StageReview is the review of the application by a reviewer
+	StageReview Stage = "review"
+	// StageCompleted closes the onboarding, approved or rejected
+	StageCompleted Stage = "completed"
+)
+
+// Stages are the onboarding stages, in order.
+var Stages = []Stage{StageApplication, StageDocuments, StageReview, StageCompleted}
+
+// Status is the outcome of the current stage.
+type Status string
+
+// Stage statuses.
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+)
+
+// recordKeys are the metadata keys of a record.
+var recordKeys = map[string]bool{
+	MetadataStage: true, MetadataStatus: true, MetadataDocuments: true,
+	MetadataReviewer: true, MetadataNote: true, MetadataUpdatedAt: true,
+}
+
+// documentSeparator joins the document references in metadata, which holds
+// no lists.
+const documentSeparator = ","
+
+// Record is the onboarding state of an organization or an account.
+type Record struct {
+	Stage  Stage
+	Status Status
+
+	// Documents are references to the documents collected, such as the keys
+	// of a document store. They can't contain commas
+	Documents []string
+
+	// Reviewer identifies who reviews or reviewed the application
+	Reviewer string
+
+	// Note explains the status, such as the reason of a rejection
+	Note string
+
+	// UpdatedAt is when the record was last written, set by the Manager
+	UpdatedAt time.Time
+
+	// Metadata is the rest of the metadata of the entity, for rules on the
+	// fields of an application (see RequireMetadata). The Manager merges it
+	// into the metadata of the entity when writing the record
+	Metadata map[string]any
+}
+
+// ToMetadata returns the metadata keys of the record. Empty fields are set to
+// nil, removing the keys of an earlier record.
+func (r Record) ToMetadata() map[string]any {
+	metadata := map[string]any{
+		MetadataStage:     string(r.Stage),
+		MetadataStatus:    string(r.Status),
+		MetadataDocuments: nil,
+		MetadataReviewer:  nil,
+		MetadataNote:      nil,
+		MetadataUpdatedAt: nil,
+	}
+
+	if len(r.Documents) > 0 {
+		metadata[MetadataDocuments] = strings.Join(r.Documents, documentSeparator)
+	}
+
+	if r.Reviewer != "" {
+		metadata[MetadataReviewer] = r.Reviewer
+	}
+
+	if r.Note != "" {
+		metadata[MetadataNote] = r.Note
+	}
+
+	if !r.UpdatedAt.IsZero() {
+		metadata[MetadataUpdatedAt] = r.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+
+	return metadata
+}
+
+// FromMetadata reads the record in the metadata of an entity, false when it
+// has none.
+func FromMetadata(metadata map[string]any) (Record, bool) {
+	stage, _ := metadata[MetadataStage].(string)
+	if stage == "" {
+		return Record{}, false
+	}
+
+	r := Record{Stage: Stage(stage), Metadata: make(map[string]any, len(metadata))}
+
+	status, _ := metadata[MetadataStatus].(string)
+	r.Status = Status(status)
+
+	if documents, _ := metadata[MetadataDocuments].(string); documents != "" {
+		r.Documents = strings.Split(documents, documentSeparator)
+	}
+
+	r.Reviewer, _ = metadata[MetadataReviewer].(string)
+	r.Note, _ = metadata[MetadataNote].(string)
+
+	if updatedAt, _ := metadata[MetadataUpdatedAt].(string); updatedAt != "" {
+		r.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	}
+
+	for k, v := range metadata {
+		if !recordKeys[k] {
+			r.Metadata[k] = v
+		}
+	}
+
+	return r, true
+}
+
+// String returns the stage and status, e.g. "review/pending".
+func (r Record) String() string {
+	return fmt.Sprintf("%s/%s", r.Stage, r.Status)
+}
@@ -0,0 +1,258 @@
+package onboarding
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func page[T any](items []T, opts *models.ListOptions) *models.ListResponse[T] {
+	start := min(opts.Offset, len(items))
+	end := min(start+opts.Limit, len(items))
+
+	return &models.ListResponse[T]{
+		Items:      items[start:end],
+		Pagination: models.Pagination{Limit: opts.Limit, Offset: start, Total: len(items)},
+	}
+}
+
+type fakeOrganizations struct {
+	entities.OrganizationsService
+
+	orgs    []models.Organization
+	updates []map[string]any
+	filters []map[string]string
+}
+
+func (f *fakeOrganizations) GetOrganization(_ context.Context, id string) (*models.Organization, error) {
+	for _, org := range f.orgs {
+		if org.ID == id {
+			return &org, nil
+		}
+	}
+
+	return nil, errors.NewNotFoundError("GetOrganization", "organization", id, nil)
+}
+
+func (f *fakeOrganizations) UpdateOrganization(_ context.Context, id string, input *models.UpdateOrganizationInput) (*models.Organization, error) {
+	f.updates = append(f.updates, input.Metadata)
+
+	for i := range f.orgs {
+		if f.orgs[i].ID == id {
+			f.orgs[i].Metadata = input.Metadata
+			org := f.orgs[i]
+
+			return &org, nil
+		}
+	}
+
+	return nil, errors.NewNotFoundError("UpdateOrganization", "organization", id, nil)
+}
+
+func (f *fakeOrganizations) ListOrganizations(_ context.Context, opts *models.ListOptions) (*models.ListResponse[models.Organization], error) {
+	f.filters = append(f.filters, opts.Filters)
+	return page(f.orgs, opts), nil
+}
+
+type fakeAccounts struct {
+	entities.AccountsService
+
+	accounts []models.Account
+	updates  []map[string]any
+}
+
+func (f *fakeAccounts) GetAccount(_ context.Context, _, _, id string) (*models.Account, error) {
+	for _, a := range f.accounts {
+		if a.ID == id {
+			return &a, nil
+		}
+	}
+
+	return nil, errors.NewNotFoundError("GetAccount", "account", id, nil)
+}
+
+func (f *fakeAccounts) UpdateAccount(_ context.Context, _, _, id string, input *models.UpdateAccountInput) (*models.Account, error) {
+	f.updates = append(f.updates, input.Metadata)
+
+	for i := range f.accounts {
+		if f.accounts[i].ID == id {
+			f.accounts[i].Metadata = input.Metadata
+			a := f.accounts[i]
+
+			return &a, nil
+		}
+	}
+
+	return nil, errors.NewNotFoundError("UpdateAccount", "account", id, nil)
+}
+
+func (f *fakeAccounts) ListAccounts(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+	return page(f.accounts, opts), nil
+}
+
+var now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestManager(e *entities.Entity) *Manager {
+	m := NewManager(e)
+	m.now = func() time.Time { return now }
+
+	return m
+}
+
+func TestRecordMetadata(t *testing.T) {
+	r := Record{
+		Stage:     StageReview,
+		Status:    StatusPending,
+		Documents: []string{"doc-1", "doc-2"},
+		Reviewer:  "ana@example.com",
+		UpdatedAt: now,
+	}
+
+	metadata := r.ToMetadata()
+	assert.Equal(t, "review", metadata[MetadataStage])
+	assert.Equal(t, "doc-1,doc-2", metadata[MetadataDocuments])
+	assert.Equal(t, "2025-06-01T12:00:00Z", metadata[MetadataUpdatedAt])
+	assert.Contains(t, metadata, MetadataNote, "empty fields remove earlier values")
+	assert.Nil(t, metadata[MetadataNote])
+
+	metadata["taxId"] = "123"
+
+	read, ok := FromMetadata(metadata)
+	require.True(t, ok)
+	assert.Equal(t, r.Stage, read.Stage)
+	assert.Equal(t, r.Status, read.Status)
+	assert.Equal(t, r.Documents, read.Documents)
+	assert.Equal(t, r.Reviewer, read.Reviewer)
+	assert.True(t, r.UpdatedAt.Equal(read.UpdatedAt))
+	assert.Equal(t, map[string]any{"taxId": "123"}, read.Metadata)
+	assert.Equal(t, "review/pending", read.String())
+
+	_, ok = FromMetadata(map[string]any{"taxId": "123"})
+	assert.False(t, ok)
+}
+
+func TestDefaultRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		record Record
+		field  string
+	}{
+		{"application", Record{Stage: StageApplication, Status: StatusPending}, ""},
+		{"unknown stage", Record{Stage: "kyc", Status: StatusPending}, MetadataStage},
+		{"unknown status", Record{Stage: StageApplication, Status: "done"}, MetadataStatus},
+		{"documents without documents", Record{Stage: StageDocuments, Status: StatusPending}, MetadataDocuments},
+		{"document with comma", Record{Stage: StageDocuments, Status: StatusPending, Documents: []string{"a,b"}}, MetadataDocuments},
+		{"review without reviewer", Record{Stage: StageReview, Status: StatusPending, Documents: []string{"doc-1"}}, MetadataReviewer},
+		{"review", Record{Stage: StageReview, Status: StatusPending, Documents: []string{"doc-1"}, Reviewer: "ana"}, ""},
+		{"completed pending", Record{Stage: StageCompleted, Status: StatusPending, Documents: []string{"doc-1"}, Reviewer: "ana"}, MetadataStatus},
+		{"rejected without note", Record{Stage: StageCompleted, Status: StatusRejected, Documents: []string{"doc-1"}, Reviewer: "ana"}, MetadataNote},
+		{"rejected", Record{Stage: StageCompleted, Status: StatusRejected, Documents: []string{"doc-1"}, Reviewer: "ana", Note: "expired passport"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := DefaultRules().Evaluate(tt.record)
+			if tt.field == "" {
+				assert.False(t, errs.HasErrors(), errs)
+				return
+			}
+
+			require.True(t, errs.HasErrors())
+			assert.Equal(t, tt.field, errs.Errors[0].Field)
+		})
+	}
+}
+
+func TestManager_SetAccount(t *testing.T) {
+	accounts := &fakeAccounts{accounts: []models.Account{
+		{ID: "acc-1", Metadata: map[string]any{"taxId": "123", MetadataNote: "stale"}},
+	}}
+	m := newTestManager(&entities.Entity{Accounts: accounts}).WithRules(RequireMetadata(StageReview, "taxId", "country"))
+
+	review := Record{Stage: StageReview, Status: StatusPending, Documents: []string{"doc-1"}, Reviewer: "ana"}
+
+	_, err := m.SetAccount(context.Background(), "org", "ledger", "acc-1", review)
+	require.Error(t, err)
+	assert.True(t, errors.IsValidationError(err))
+
+	var fieldErrs *validation.FieldErrors
+	require.True(t, stderrors.As(err, &fieldErrs))
+	assert.Equal(t, "metadata.country", fieldErrs.Errors[0].Field)
+	assert.Empty(t, accounts.updates, "invalid records are not written")
+
+	review.Metadata = map[string]any{"country": "BR"}
+
+	account, err := m.SetAccount(context.Background(), "org", "ledger", "acc-1", review)
+	require.NoError(t, err)
+	require.Len(t, accounts.updates, 1)
+
+	assert.Equal(t, "123", account.Metadata["taxId"], "other metadata is kept")
+	assert.Equal(t, "BR", account.Metadata["country"])
+	assert.Nil(t, account.Metadata[MetadataNote], "the note of the earlier record is removed")
+
+	r, ok := AccountRecord(account)
+	require.True(t, ok)
+	assert.Equal(t, StageReview, r.Stage)
+	assert.Equal(t, "ana", r.Reviewer)
+	assert.True(t, now.Equal(r.UpdatedAt))
+
+	_, err = m.SetAccount(context.Background(), "org", "ledger", "missing", review)
+	assert.True(t, errors.IsNotFoundError(err))
+}
+
+func TestManager_SetOrganization(t *testing.T) {
+	orgs := &fakeOrganizations{orgs: []models.Organization{{ID: "org-1"}}}
+	m := newTestManager(&entities.Entity{Organizations: orgs})
+
+	org, err := m.SetOrganization(context.Background(), "org-1", Record{Stage: StageApplication, Status: StatusPending})
+	require.NoError(t, err)
+
+	r, ok := OrganizationRecord(org)
+	require.True(t, ok)
+	assert.Equal(t, StageApplication, r.Stage)
+
+	_, err = newTestManager(&entities.Entity{}).SetOrganization(context.Background(), "org-1", r)
+	assert.Error(t, err)
+}
+
+func TestManager_InStage(t *testing.T) {
+	stage := func(s Stage) map[string]any { return Record{Stage: s, Status: StatusPending}.ToMetadata() }
+
+	orgs := &fakeOrganizations{}
+	accounts := &fakeAccounts{}
+
+	for i := range 150 {
+		s := StageApplication
+		if i%3 == 0 {
+			s = StageReview
+		}
+
+		orgs.orgs = append(orgs.orgs, models.Organization{ID: string(rune('a' + i%26)), Metadata: stage(s)})
+		accounts.accounts = append(accounts.accounts, models.Account{Metadata: stage(s)})
+	}
+
+	accounts.accounts = append(accounts.accounts, models.Account{Metadata: map[string]any{"taxId": "123"}})
+
+	m := newTestManager(&entities.Entity{Organizations: orgs, Accounts: accounts})
+
+	inReview, err := m.OrganizationsInStage(context.Background(), StageReview)
+	require.NoError(t, err)
+	assert.Len(t, inReview, 50, "entities of other stages are dropped when the API ignores the filter")
+	assert.Len(t, orgs.filters, 2, "every page is read")
+
+	for _, filters := range orgs.filters {
+		assert.Equal(t, string(StageReview), filters["metadata."+MetadataStage], "the filter is kept on every page")
+	}
+
+	inApplication, err := m.AccountsInStage(context.Background(), "org", "ledger", StageApplication)
+	require.NoError(t, err)
+	assert.Len(t, inApplication, 100)
+}
@@ -0,0 +1,163 @@
+package onboarding
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+)
+
+// DefaultRules returns the rules checked by a new Manager:
+//
+//   - the stage and the status are known ones
+//   - document references are neither empty nor contain commas
+//   - the documents, review and completed stages require a document
+//   - the review and completed stages require a reviewer
+//   - the completed stage is approved or rejected, not pending
+//   - a rejection requires a note
+func DefaultRules() *validation.RuleSet[Record] {
+	return validation.NewRuleSet(
+		KnownStageRule(),
+		DocumentReferencesRule(),
+		RequireDocumentsRule(StageDocuments, StageReview, StageCompleted),
+		RequireReviewerRule(StageReview, StageCompleted),
+		CompletedDecisionRule(),
+		RejectionNoteRule(),
+	)
+}
+
+// KnownStageRule requires one of Stages and one of the statuses.
+func KnownStageRule() validation.Rule[Record] {
+	return validation.Rule[Record]{
+		Name:        "onboarding-known-stage",
+		Description: "onboarding stage and status must be known ones",
+		Check: func(r Record) *validation.FieldError {
+			if !slices.Contains(Stages, r.Stage) {
+				return validation.BuildFieldError(MetadataStage, string(r.Stage), fmt.Sprintf("unknown onboarding stage %q", r.Stage))
+			}
+
+			switch r.Status {
+			case StatusPending, StatusApproved, StatusRejected:
+				return nil
+			default:
+				return validation.BuildFieldError(MetadataStatus, string(r.Status), fmt.Sprintf("unknown onboarding status %q", r.Status))
+			}
+		},
+	}
+}
+
+// DocumentReferencesRule rejects empty document references and those
+// containing commas, which separate them in metadata.
+func DocumentReferencesRule() validation.Rule[Record] {
+	return validation.Rule[Record]{
+		Name:        "onboarding-document-references",
+		Description: "document references must be non-empty and contain no commas",
+		Check: func(r Record) *validation.FieldError {
+			for _, ref := range r.Documents {
+				if strings.TrimSpace(ref) == "" || strings.Contains(ref, documentSeparator) {
+					return validation.BuildFieldError(MetadataDocuments, ref, "")
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// RequireDocumentsRule requires a document reference at the given stages.
+func RequireDocumentsRule(stages ...Stage) validation.Rule[Record] {
+	return validation.Rule[Record]{
+		Name:        "onboarding-documents-required",
+		Description: "at least one document is required at this stage",
+		When:        atStages(stages),
+		Check: func(r Record) *validation.FieldError {
+			if len(r.Documents) == 0 {
+				return validation.BuildFieldError(MetadataDocuments, nil, "")
+			}
+
+			return nil
+		},
+	}
+}
+
+// RequireReviewerRule requires a reviewer at the given stages.
+func RequireReviewerRule(stages ...Stage) validation.Rule[Record] {
+	return validation.Rule[Record]{
+		Name:        "onboarding-reviewer-required",
+		Description: "a reviewer is required at this stage",
+		When:        atStages(stages),
+		Check: func(r Record) *validation.FieldError {
+			if strings.TrimSpace(r.Reviewer) == "" {
+				return validation.BuildFieldError(MetadataReviewer, r.Reviewer, "")
+			}
+
+			return nil
+		},
+	}
+}
+
+// CompletedDecisionRule requires a completed onboarding to be approved or rejected.
+func CompletedDecisionRule() validation.Rule[Record] {
+	return validation.Rule[Record]{
+		Name:        "onboarding-completed-decision",
+		Description: "a completed onboarding must be approved or rejected",
+		When:        atStages([]Stage{StageCompleted}),
+		Check: func(r Record) *validation.FieldError {
+			if r.Status == StatusPending {
+				return validation.BuildFieldError(MetadataStatus, string(r.Status), "")
+			}
+
+			return nil
+		},
+	}
+}
+
+// RejectionNoteRule requires a note explaining a rejection.
+func RejectionNoteRule() validation.Rule[Record] {
+	return validation.Rule[Record]{
+		Name:        "onboarding-rejection-note",
+		Description: "a rejection must be explained in a note",
+		When:        func(r Record) bool { return r.Status == StatusRejected },
+		Check: func(r Record) *validation.FieldError {
+			if strings.TrimSpace(r.Note) == "" {
+				return validation.BuildFieldError(MetadataNote, r.Note, "")
+			}
+
+			return nil
+		},
+	}
+}
+
+// RequireMetadata requires the given metadata keys of the entity to be set at
+// a stage, such as the tax ID of an applicant before the review.
+//
+// Example:
+//
+//	m := onboarding.NewManager(client.Entity).WithRules(
+//	    onboarding.RequireMetadata(onboarding.StageReview, "taxId", "country"),
+//	)
+func RequireMetadata(stage Stage, keys ...string) validation.Rule[Record] {
+	return validation.Rule[Record]{
+		Name:        "onboarding-" + string(stage) + "-metadata-required",
+		Description: fmt.Sprintf("metadata %s is required at the %s stage", strings.Join(keys, ", "), stage),
+		When:        atStages([]Stage{stage}),
+		Check: func(r Record) *validation.FieldError {
+			for _, key := range keys {
+				if value, ok := r.Metadata[key]; !ok || value == nil || value == "" {
+					return validation.BuildFieldError("metadata."+key, value,
+						fmt.Sprintf("metadata %s is required at the %s stage", key, stage))
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// atStages returns a When function matching the records at the given stages.
+func atStages(stages []Stage) func(Record) bool {
+	return func(r Record) bool {
+		return slices.Contains(stages, r.Stage)
+	}
+}
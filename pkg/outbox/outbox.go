@@ -0,0 +1,383 @@
+// Package outbox submits transactions through a local outbox, so that a
+// payment accepted by a service survives a crash before it reaches Midaz.
+//
+// Publish persists the intended transaction to a Store, in the same place the
+// service keeps its own state, and returns. A Publisher running in the
+// background submits the pending entries, retries the ones failing with
+// transient errors after a backoff, and marks each entry submitted or failed.
+//
+// Every entry carries an idempotency key, persisted with it and sent with each
+// attempt. A crash between the submission and its completion being recorded
+// makes the entry be submitted again on restart, and Midaz returns the
+// transaction already created for the key instead of creating another: at
+// least once delivery with an idempotent receiver, exactly once in effect.
+//
+// Only one Publisher should process a Store at a time.
+//
+// Example:
+//
+//	store, err := outbox.NewSQLStore(db, outbox.DialectPostgres, "payment_outbox")
+//	if err := store.CreateTable(ctx); err != nil { ... }
+//
+//	publisher := outbox.New(store, client.Entity.Transactions).
+//	    WithOnResult(func(e outbox.Entry) { log.Printf("%s: %s %s", e.ID, e.Status, e.LastError) })
+//	go publisher.Run(ctx)
+//
+//	entry, err := publisher.Publish(ctx, orgID, ledgerID, input)
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/idgen"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+)
+
+// Defaults of a Publisher.
+const (
+	DefaultPollInterval   = time.Second
+	DefaultBatchSize      = 100
+	DefaultMaxAttempts    = 10
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = 5 * time.Minute
+)
+
+// ErrNotFound is returned by a Store for an unknown entry.
+var ErrNotFound = errors.New("outbox entry not found")
+
+// Status is the state of an outbox entry.
+type Status string
+
+const (
+	// StatusPending marks an entry waiting for its first or next attempt
+	StatusPending Status = "pending"
+
+	// StatusSubmitted marks an entry whose transaction was created
+	StatusSubmitted Status = "submitted"
+
+	// StatusFailed marks an entry rejected by Midaz or out of attempts
+	StatusFailed Status = "failed"
+)
+
+// Entry is a transaction in the outbox.
+type Entry struct {
+	ID             string                         `json:"id"`
+	OrganizationID string                         `json:"organizationId"`
+	LedgerID       string                         `json:"ledgerId"`
+	Input          *models.CreateTransactionInput `json:"input"`
+
+	// IdempotencyKey is sent with every attempt, so that a transaction is
+	// created once however many times it is submitted
+	IdempotencyKey string `json:"idempotencyKey"`
+
+	Status Status `json:"status"`
+
+	// Attempts counts the submissions; NextAttemptAt is when a pending entry
+	// is due
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+
+	// LastError is the error of the last failed attempt
+	LastError string `json:"lastError,omitempty"`
+
+	// TransactionID is the ID of the created transaction, once submitted
+	TransactionID string `json:"transactionId,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Store persists the outbox entries. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Add persists a new entry
+	Add(ctx context.Context, entry Entry) error
+
+	// Get returns an entry, or ErrNotFound
+	Get(ctx context.Context, id string) (Entry, error)
+
+	// Due returns up to limit pending entries due at now, oldest first
+	Due(ctx context.Context, now time.Time, limit int) ([]Entry, error)
+
+	// Update replaces an existing entry
+	Update(ctx context.Context, entry Entry) error
+}
+
+// Publisher submits the entries of an outbox.
+type Publisher struct {
+	store        Store
+	transactions entities.TransactionsService
+
+	pollInterval   time.Duration
+	batchSize      int
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	onResult       func(Entry)
+	ids            idgen.Generator
+
+	// wake makes Run process the entries published since its last pass
+	wake chan struct{}
+	now  func() time.Time
+}
+
+// New creates a Publisher submitting the entries of store with transactions.
+func New(store Store, transactions entities.TransactionsService) *Publisher {
+	return &Publisher{
+		store:          store,
+		transactions:   transactions,
+		pollInterval:   DefaultPollInterval,
+		batchSize:      DefaultBatchSize,
+		maxAttempts:    DefaultMaxAttempts,
+		initialBackoff: DefaultInitialBackoff,
+		maxBackoff:     DefaultMaxBackoff,
+		wake:           make(chan struct{}, 1),
+		now:            time.Now,
+	}
+}
+
+// WithPollInterval sets how often Run looks for due entries when no entry is
+// published.
+func (p *Publisher) WithPollInterval(interval time.Duration) *Publisher {
+	if interval > 0 {
+		p.pollInterval = interval
+	}
+
+	return p
+}
+
+// WithBatchSize sets the number of due entries read from the store at once.
+func (p *Publisher) WithBatchSize(size int) *Publisher {
+	if size > 0 {
+		p.batchSize = size
+	}
+
+	return p
+}
+
+// WithMaxAttempts sets the number of submissions of an entry before it is
+// marked failed.
+func (p *Publisher) WithMaxAttempts(attempts int) *Publisher {
+	if attempts > 0 {
+		p.maxAttempts = attempts
+	}
+
+	return p
+}
+
+// WithBackoff sets the delay before the second attempt of an entry, doubled
+// for each following attempt up to maxDelay.
+func (p *Publisher) WithBackoff(initial, maxDelay time.Duration) *Publisher {
+	if initial > 0 {
+		p.initialBackoff = initial
+	}
+
+	if maxDelay > 0 {
+		p.maxBackoff = maxDelay
+	}
+
+	return p
+}
+
+// WithIDGenerator sets the generator of the entry IDs, which also make the
+// idempotency keys of the transactions published without one. The default,
+// idgen.Default, makes time-ordered UUIDv7s that keep the store index
+// compact.
+func (p *Publisher) WithIDGenerator(gen idgen.Generator) *Publisher {
+	p.ids = gen
+
+	return p
+}
+
+// WithOnResult sets a callback receiving each entry after an attempt: submitted,
+// failed, or pending again with its next attempt scheduled.
+func (p *Publisher) WithOnResult(fn func(Entry)) *Publisher {
+	p.onResult = fn
+	return p
+}
+
+// Publish persists a transaction to the outbox and returns its entry. The
+// transaction is submitted later by Run or Process; once Publish returns, it
+// is no longer lost if the process stops. The idempotency key of input is
+// used when set, otherwise one is derived from the entry ID.
+func (p *Publisher) Publish(ctx context.Context, orgID, ledgerID string, input *models.CreateTransactionInput) (Entry, error) {
+	if orgID == "" || ledgerID == "" {
+		return Entry{}, errors.New("organization ID and ledger ID are required")
+	}
+
+	if input == nil {
+		return Entry{}, errors.New("transaction input is required")
+	}
+
+	if err := input.Validate(); err != nil {
+		return Entry{}, fmt.Errorf("invalid transaction input: %w", err)
+	}
+
+	now := p.now().UTC()
+	ids := p.ids
+	if ids == nil {
+		ids = idgen.Default()
+	}
+
+	id := ids.NewID()
+
+	key := input.IdempotencyKey
+	if key == "" {
+		key = "outbox-" + id
+	}
+
+	entry := Entry{
+		ID:             id,
+		OrganizationID: orgID,
+		LedgerID:       ledgerID,
+		Input:          input,
+		IdempotencyKey: key,
+		Status:         StatusPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := p.store.Add(ctx, entry); err != nil {
+		return Entry{}, fmt.Errorf("failed to persist outbox entry: %w", err)
+	}
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+
+	return entry, nil
+}
+
+// Run processes the due entries until ctx is cancelled, right after each
+// Publish and every poll interval otherwise. Failing submissions are
+// recorded in their entries; Run stops only on store errors and returns the
+// error of ctx once cancelled.
+func (p *Publisher) Run(ctx context.Context) error {
+	defer lifecycle.Track("outbox.Publisher.Run")()
+
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Process(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-p.wake:
+		}
+	}
+}
+
+// Process submits the entries due now, one batch after another, and returns
+// the number of entries attempted. It suits services that drive the outbox
+// from their own scheduler instead of Run.
+func (p *Publisher) Process(ctx context.Context) (int, error) {
+	if p.transactions == nil {
+		return 0, errors.New("transactions service not initialized")
+	}
+
+	processed := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return processed, err
+		}
+
+		due, err := p.store.Due(ctx, p.now().UTC(), p.batchSize)
+		if err != nil {
+			return processed, fmt.Errorf("failed to read outbox: %w", err)
+		}
+
+		for _, entry := range due {
+			if err := p.submit(ctx, entry); err != nil {
+				return processed, err
+			}
+
+			processed++
+		}
+
+		// entries retried in this pass are not due again before their backoff
+		if len(due) < p.batchSize {
+			return processed, nil
+		}
+	}
+}
+
+// submit attempts an entry and records the outcome.
+func (p *Publisher) submit(ctx context.Context, entry Entry) error {
+	tx, err := p.transactions.CreateTransaction(entities.WithIdempotencyKey(ctx, entry.IdempotencyKey), entry.OrganizationID, entry.LedgerID, entry.Input)
+
+	// a cancelled submission may or may not have reached Midaz; the entry
+	// stays pending and its idempotency key settles it on the next attempt
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	now := p.now().UTC()
+	entry.Attempts++
+	entry.UpdatedAt = now
+
+	switch {
+	case err == nil:
+		entry.Status = StatusSubmitted
+		entry.LastError = ""
+
+		if tx != nil {
+			entry.TransactionID = tx.ID
+		}
+	case isRetryable(err) && entry.Attempts < p.maxAttempts:
+		entry.LastError = err.Error()
+		entry.NextAttemptAt = now.Add(p.backoff(entry.Attempts))
+	default:
+		entry.Status = StatusFailed
+		entry.LastError = err.Error()
+	}
+
+	if err := p.store.Update(ctx, entry); err != nil {
+		return fmt.Errorf("failed to update outbox entry %s: %w", entry.ID, err)
+	}
+
+	if p.onResult != nil {
+		p.onResult(entry)
+	}
+
+	return nil
+}
+
+// backoff returns the delay after the given number of attempts
+func (p *Publisher) backoff(attempts int) time.Duration {
+	delay := p.initialBackoff
+
+	for i := 1; i < attempts && delay < p.maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.maxBackoff)
+}
+
+// isRetryable reports whether a submission may succeed later. An idempotency
+// conflict means an earlier attempt with the same key is still being
+// processed.
+func isRetryable(err error) bool {
+	if sdkerrors.IsRateLimitError(err) || sdkerrors.IsNetworkError(err) ||
+		sdkerrors.IsTimeoutError(err) || sdkerrors.IsIdempotencyError(err) {
+		return true
+	}
+
+	var apiErr *sdkerrors.Error
+
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= http.StatusInternalServerError
+}
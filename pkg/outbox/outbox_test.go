@@ -0,0 +1,293 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/idgen"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/kvstore"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// midazServer answers transaction creations with the statuses of responses
+// in turn, then with 201, and records the idempotency keys received
+type midazServer struct {
+	mu        sync.Mutex
+	responses []int
+	keys      []string
+}
+
+func (m *midazServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.keys = append(m.keys, r.Header.Get("X-Idempotency"))
+
+	status := http.StatusCreated
+	if len(m.responses) > 0 {
+		status, m.responses = m.responses[0], m.responses[1:]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if status == http.StatusCreated {
+		_, _ = w.Write([]byte(`{"id":"tx-1","status":{"code":"APPROVED"}}`))
+		return
+	}
+
+	_, _ = w.Write([]byte(`{"code":"0001","message":"failure"}`))
+}
+
+func (m *midazServer) idempotencyKeys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]string(nil), m.keys...)
+}
+
+func newTestPublisher(t *testing.T, responses ...int) (*Publisher, *MemoryStore, *midazServer, *time.Time) {
+	t.Helper()
+	t.Setenv("MIDAZ_ENABLE_RETRIES", "false")
+
+	server := &midazServer{responses: responses}
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+
+	entity, err := entities.New(srv.URL, entities.WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+
+	p := New(store, entity.Transactions).WithBackoff(time.Second, 4*time.Second)
+	p.now = func() time.Time { return now }
+
+	return p, store, server, &now
+}
+
+func transferInput() *models.CreateTransactionInput {
+	return models.NewCreateTransactionInput("USD", "100").WithSend(models.NewSendInput("USD", decimal.NewFromInt(100),
+		[]models.FromToInput{{Account: "@alice", Amount: models.AmountInput{Asset: "USD", Value: "100"}}},
+		[]models.FromToInput{{Account: "@bob", Amount: models.AmountInput{Asset: "USD", Value: "100"}}}))
+}
+
+func TestPublishAndProcess(t *testing.T) {
+	p, store, server, _ := newTestPublisher(t)
+
+	var results []Entry
+
+	p.WithOnResult(func(e Entry) { results = append(results, e) })
+
+	entry, err := p.Publish(context.Background(), "org-1", "ledger-1", transferInput())
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, entry.Status)
+	assert.Equal(t, "outbox-"+entry.ID, entry.IdempotencyKey)
+
+	processed, err := p.Process(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	stored, err := store.Get(context.Background(), entry.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSubmitted, stored.Status)
+	assert.Equal(t, "tx-1", stored.TransactionID)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, []string{entry.IdempotencyKey}, server.idempotencyKeys())
+	require.Len(t, results, 1)
+
+	processed, err = p.Process(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, processed, "submitted entries are not submitted again")
+}
+
+func TestProcessRetriesTransientFailures(t *testing.T) {
+	p, store, server, now := newTestPublisher(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+	input := transferInput()
+	input.IdempotencyKey = "payment-42"
+
+	entry, err := p.Publish(context.Background(), "org-1", "ledger-1", input)
+	require.NoError(t, err)
+
+	_, err = p.Process(context.Background())
+	require.NoError(t, err)
+
+	stored, _ := store.Get(context.Background(), entry.ID)
+	assert.Equal(t, StatusPending, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.NotEmpty(t, stored.LastError)
+	assert.Equal(t, now.Add(time.Second), stored.NextAttemptAt)
+
+	processed, err := p.Process(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, processed, "the entry waits for its backoff")
+
+	*now = now.Add(time.Second)
+	_, err = p.Process(context.Background())
+	require.NoError(t, err)
+
+	stored, _ = store.Get(context.Background(), entry.ID)
+	assert.Equal(t, 2*time.Second, stored.NextAttemptAt.Sub(*now), "the backoff doubles")
+
+	*now = now.Add(2 * time.Second)
+	_, err = p.Process(context.Background())
+	require.NoError(t, err)
+
+	stored, _ = store.Get(context.Background(), entry.ID)
+	assert.Equal(t, StatusSubmitted, stored.Status)
+	assert.Equal(t, 3, stored.Attempts)
+	assert.Empty(t, stored.LastError)
+	assert.Equal(t, []string{"payment-42", "payment-42", "payment-42"}, server.idempotencyKeys())
+}
+
+func TestProcessMarksFailures(t *testing.T) {
+	p, store, _, now := newTestPublisher(t, http.StatusBadRequest, http.StatusBadGateway, http.StatusBadGateway)
+	p.WithMaxAttempts(2)
+
+	rejected, err := p.Publish(context.Background(), "org-1", "ledger-1", transferInput())
+	require.NoError(t, err)
+
+	_, err = p.Process(context.Background())
+	require.NoError(t, err)
+
+	stored, _ := store.Get(context.Background(), rejected.ID)
+	assert.Equal(t, StatusFailed, stored.Status, "client errors are not retried")
+
+	exhausted, err := p.Publish(context.Background(), "org-1", "ledger-1", transferInput())
+	require.NoError(t, err)
+
+	for range 2 {
+		_, err = p.Process(context.Background())
+		require.NoError(t, err)
+
+		*now = now.Add(time.Minute)
+	}
+
+	stored, _ = store.Get(context.Background(), exhausted.ID)
+	assert.Equal(t, StatusFailed, stored.Status)
+	assert.Equal(t, 2, stored.Attempts)
+}
+
+func TestPublishValidation(t *testing.T) {
+	p, store, _, _ := newTestPublisher(t)
+
+	_, err := p.Publish(context.Background(), "", "ledger-1", transferInput())
+	require.Error(t, err)
+
+	_, err = p.Publish(context.Background(), "org-1", "ledger-1", models.NewCreateTransactionInput("USD", "0"))
+	require.Error(t, err)
+
+	assert.Empty(t, store.Entries(), "invalid transactions are not persisted")
+}
+
+func TestPublishIDGenerator(t *testing.T) {
+	p, _, _, _ := newTestPublisher(t)
+
+	entry, err := p.Publish(context.Background(), "org-1", "ledger-1", transferInput())
+	require.NoError(t, err)
+
+	id, err := uuid.Parse(entry.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), id.Version(), "entry IDs are time-ordered by default")
+
+	p.WithIDGenerator(idgen.GeneratorFunc(func() string { return "entry-1" }))
+
+	entry, err = p.Publish(context.Background(), "org-1", "ledger-1", transferInput())
+	require.NoError(t, err)
+	assert.Equal(t, "entry-1", entry.ID)
+	assert.Equal(t, "outbox-entry-1", entry.IdempotencyKey)
+}
+
+func TestRun(t *testing.T) {
+	p, store, _, _ := newTestPublisher(t)
+	p.WithPollInterval(time.Hour)
+
+	submitted := make(chan Entry, 1)
+	p.WithOnResult(func(e Entry) { submitted <- e })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- p.Run(ctx) }()
+
+	entry, err := p.Publish(context.Background(), "org-1", "ledger-1", transferInput())
+	require.NoError(t, err)
+
+	select {
+	case e := <-submitted:
+		assert.Equal(t, entry.ID, e.ID)
+		assert.Equal(t, StatusSubmitted, e.Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the published entry was not submitted")
+	}
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	assert.Len(t, store.Entries(), 1)
+}
+
+func TestSQLStore(t *testing.T) {
+	_, err := NewSQLStore(nil, DialectPostgres, "outbox")
+	require.Error(t, err)
+
+	db := &sql.DB{}
+
+	_, err = NewSQLStore(db, "oracle", "outbox")
+	require.Error(t, err)
+
+	_, err = NewSQLStore(db, DialectPostgres, "outbox; DROP TABLE users")
+	require.Error(t, err)
+
+	postgres, err := NewSQLStore(db, DialectPostgres, "payments.outbox")
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE payments.outbox SET status = $1 WHERE id = $2", postgres.rebind("UPDATE payments.outbox SET status = ? WHERE id = ?"))
+
+	sqlite, err := NewSQLStore(db, DialectSQLite, "outbox")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT 1 WHERE id = ?", sqlite.rebind("SELECT 1 WHERE id = ?"))
+}
+
+func TestKVStore(t *testing.T) {
+	p, _, _, _ := newTestPublisher(t)
+	ctx := context.Background()
+
+	kv := kvstore.NewMemory()
+	store := NewKVStore(kv)
+	p.store = store
+
+	first, err := p.Publish(ctx, "org", "ledger", transferInput())
+	require.NoError(t, err)
+
+	require.Error(t, store.Add(ctx, first))
+
+	processed, err := p.Process(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	entry, err := store.Get(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSubmitted, entry.Status)
+	assert.Equal(t, "tx-1", entry.TransactionID)
+	assert.Equal(t, "100", entry.Input.Send.Value)
+
+	due, err := store.Due(ctx, p.now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	require.NoError(t, store.Delete(ctx, first.ID))
+
+	_, err = store.Get(ctx, first.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, store.Update(ctx, entry), ErrNotFound)
+}
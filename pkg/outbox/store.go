@@ -0,0 +1,428 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/kvstore"
+)
+
+// MemoryStore is a Store kept in memory. It doesn't survive a restart and
+// suits tests and tools; services use a SQLStore or a KVStore.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]Entry{}}
+}
+
+// Add implements Store.
+func (s *MemoryStore) Add(_ context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[entry.ID]; ok {
+		return fmt.Errorf("outbox entry %s already exists", entry.ID)
+	}
+
+	s.entries[entry.ID] = entry
+
+	return nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, id string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[id]
+	if !ok {
+		return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	return entry, nil
+}
+
+// Due implements Store.
+func (s *MemoryStore) Due(_ context.Context, now time.Time, limit int) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []Entry
+
+	for _, entry := range s.entries {
+		if entry.Status == StatusPending && !entry.NextAttemptAt.After(now) {
+			due = append(due, entry)
+		}
+	}
+
+	sortEntries(due)
+
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	return due, nil
+}
+
+// Update implements Store.
+func (s *MemoryStore) Update(_ context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[entry.ID]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, entry.ID)
+	}
+
+	s.entries[entry.ID] = entry
+
+	return nil
+}
+
+// Entries returns every entry, oldest first.
+func (s *MemoryStore) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CreatedAt.Before(entries[j].CreatedAt) })
+
+	return entries
+}
+
+// kvPrefix prefixes the keys of the entries of a KVStore
+const kvPrefix = "outbox/"
+
+// KVStore is a Store kept in a kvstore.Interface, such as a Redis or file
+// store shared with the other stateful components of a service. Due scans
+// every entry, so services with large outboxes should prefer a SQLStore, or
+// delete the submitted entries they no longer need with Delete.
+type KVStore struct {
+	kv kvstore.Interface
+
+	// mu makes Add and Update atomic checks of existence
+	mu sync.Mutex
+}
+
+// NewKVStore creates a KVStore keeping its entries under "outbox/" in kv.
+func NewKVStore(kv kvstore.Interface) *KVStore {
+	return &KVStore{kv: kv}
+}
+
+// Add implements Store.
+func (s *KVStore) Add(ctx context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.kv.Get(ctx, kvPrefix+entry.ID); err == nil {
+		return fmt.Errorf("outbox entry %s already exists", entry.ID)
+	} else if !errors.Is(err, kvstore.ErrNotFound) {
+		return err
+	}
+
+	return kvstore.PutJSON(ctx, s.kv, kvPrefix+entry.ID, entry)
+}
+
+// Get implements Store.
+func (s *KVStore) Get(ctx context.Context, id string) (Entry, error) {
+	var entry Entry
+
+	found, err := kvstore.GetJSON(ctx, s.kv, kvPrefix+id, &entry)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if !found {
+		return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	return entry, nil
+}
+
+// Due implements Store.
+func (s *KVStore) Due(ctx context.Context, now time.Time, limit int) ([]Entry, error) {
+	var due []Entry
+
+	err := s.kv.Scan(ctx, kvPrefix, func(key string, value []byte) error {
+		var entry Entry
+		if err := json.Unmarshal(value, &entry); err != nil {
+			return fmt.Errorf("failed to decode outbox entry %s: %w", key, err)
+		}
+
+		if entry.Status == StatusPending && !entry.NextAttemptAt.After(now) {
+			due = append(due, entry)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortEntries(due)
+
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	return due, nil
+}
+
+// Update implements Store.
+func (s *KVStore) Update(ctx context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.Get(ctx, entry.ID); err != nil {
+		return err
+	}
+
+	return kvstore.PutJSON(ctx, s.kv, kvPrefix+entry.ID, entry)
+}
+
+// Delete removes an entry, typically once submitted.
+func (s *KVStore) Delete(ctx context.Context, id string) error {
+	return s.kv.Delete(ctx, kvPrefix+id)
+}
+
+// Dialect is the SQL flavor of a SQLStore database.
+type Dialect string
+
+const (
+	// DialectPostgres numbers its placeholders: $1, $2...
+	DialectPostgres Dialect = "postgres"
+
+	// DialectMySQL uses ? placeholders
+	DialectMySQL Dialect = "mysql"
+
+	// DialectSQLite uses ? placeholders
+	DialectSQLite Dialect = "sqlite"
+)
+
+// tableName matches the table names accepted by NewSQLStore, optionally
+// qualified by a schema
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// sqlColumns are the columns of the outbox table, in scan order
+const sqlColumns = "id, organization_id, ledger_id, input, idempotency_key, status, attempts, next_attempt_at, last_error, transaction_id, created_at, updated_at"
+
+// SQLStore is a Store kept in a table of a SQL database, through any
+// database/sql driver. Times are stored as Unix milliseconds so that the
+// table is portable across databases.
+type SQLStore struct {
+	db      *sql.DB
+	dialect Dialect
+	table   string
+}
+
+// NewSQLStore creates a SQLStore on table, which CreateTable creates.
+func NewSQLStore(db *sql.DB, dialect Dialect, table string) (*SQLStore, error) {
+	if db == nil {
+		return nil, errors.New("database is required")
+	}
+
+	switch dialect {
+	case DialectPostgres, DialectMySQL, DialectSQLite:
+	default:
+		return nil, fmt.Errorf("unsupported SQL dialect %q", dialect)
+	}
+
+	if !tableName.MatchString(table) {
+		return nil, fmt.Errorf("invalid outbox table name %q", table)
+	}
+
+	return &SQLStore{db: db, dialect: dialect, table: table}, nil
+}
+
+// CreateTable creates the outbox table and its index of due entries when
+// they don't exist.
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	index := strings.ReplaceAll(s.table, ".", "_") + "_due"
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS ` + s.table + ` (
+			id VARCHAR(64) NOT NULL PRIMARY KEY,
+			organization_id VARCHAR(64) NOT NULL,
+			ledger_id VARCHAR(64) NOT NULL,
+			input TEXT NOT NULL,
+			idempotency_key VARCHAR(255) NOT NULL,
+			status VARCHAR(16) NOT NULL,
+			attempts INTEGER NOT NULL,
+			next_attempt_at BIGINT NOT NULL,
+			last_error TEXT NOT NULL,
+			transaction_id VARCHAR(64) NOT NULL,
+			created_at BIGINT NOT NULL,
+			updated_at BIGINT NOT NULL
+		)`,
+	}
+
+	// MySQL has no IF NOT EXISTS for indexes
+	if s.dialect != DialectMySQL {
+		statements = append(statements, `CREATE INDEX IF NOT EXISTS `+index+` ON `+s.table+` (status, next_attempt_at)`)
+	}
+
+	for _, statement := range statements {
+		if _, err := s.db.ExecContext(ctx, statement); err != nil { // #nosec G202 -- the table name is validated by NewSQLStore
+			return fmt.Errorf("failed to create outbox table: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Add implements Store.
+func (s *SQLStore) Add(ctx context.Context, entry Entry) error {
+	input, err := json.Marshal(entry.Input)
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction input: %w", err)
+	}
+
+	query := s.rebind(`INSERT INTO ` + s.table + ` (` + sqlColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+
+	_, err = s.db.ExecContext(ctx, query, // #nosec G202 -- the table name is validated by NewSQLStore
+		entry.ID, entry.OrganizationID, entry.LedgerID, string(input), entry.IdempotencyKey, string(entry.Status),
+		entry.Attempts, millis(entry.NextAttemptAt), entry.LastError, entry.TransactionID,
+		millis(entry.CreatedAt), millis(entry.UpdatedAt))
+
+	return err
+}
+
+// Get implements Store.
+func (s *SQLStore) Get(ctx context.Context, id string) (Entry, error) {
+	query := s.rebind(`SELECT ` + sqlColumns + ` FROM ` + s.table + ` WHERE id = ?`)
+
+	entry, err := scanEntry(s.db.QueryRowContext(ctx, query, id)) // #nosec G202 -- the table name is validated by NewSQLStore
+	if errors.Is(err, sql.ErrNoRows) {
+		return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	return entry, err
+}
+
+// Due implements Store.
+func (s *SQLStore) Due(ctx context.Context, now time.Time, limit int) ([]Entry, error) {
+	query := s.rebind(`SELECT ` + sqlColumns + ` FROM ` + s.table +
+		` WHERE status = ? AND next_attempt_at <= ? ORDER BY created_at, id LIMIT ` + strconv.Itoa(max(limit, 1)))
+
+	rows, err := s.db.QueryContext(ctx, query, string(StatusPending), millis(now)) // #nosec G202 -- the table name is validated by NewSQLStore
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []Entry
+
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		due = append(due, entry)
+	}
+
+	return due, rows.Err()
+}
+
+// Update implements Store.
+func (s *SQLStore) Update(ctx context.Context, entry Entry) error {
+	query := s.rebind(`UPDATE ` + s.table +
+		` SET status = ?, attempts = ?, next_attempt_at = ?, last_error = ?, transaction_id = ?, updated_at = ? WHERE id = ?`)
+
+	result, err := s.db.ExecContext(ctx, query, // #nosec G202 -- the table name is validated by NewSQLStore
+		string(entry.Status), entry.Attempts, millis(entry.NextAttemptAt), entry.LastError, entry.TransactionID,
+		millis(entry.UpdatedAt), entry.ID)
+	if err != nil {
+		return err
+	}
+
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, entry.ID)
+	}
+
+	return nil
+}
+
+// rebind rewrites the ? placeholders of query for the dialect of the store
+func (s *SQLStore) rebind(query string) string {
+	if s.dialect != DialectPostgres {
+		return query
+	}
+
+	var b strings.Builder
+
+	n := 0
+
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// scanEntry reads an entry from a row of sqlColumns
+func scanEntry(row interface{ Scan(dest ...any) error }) (Entry, error) {
+	var (
+		entry                               Entry
+		input, status                       string
+		nextAttemptAt, createdAt, updatedAt int64
+	)
+
+	err := row.Scan(&entry.ID, &entry.OrganizationID, &entry.LedgerID, &input, &entry.IdempotencyKey, &status,
+		&entry.Attempts, &nextAttemptAt, &entry.LastError, &entry.TransactionID, &createdAt, &updatedAt)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry.Input = &models.CreateTransactionInput{}
+	if err := json.Unmarshal([]byte(input), entry.Input); err != nil {
+		return Entry{}, fmt.Errorf("failed to decode transaction input of outbox entry %s: %w", entry.ID, err)
+	}
+
+	entry.Status = Status(status)
+	entry.NextAttemptAt = time.UnixMilli(nextAttemptAt).UTC()
+	entry.CreatedAt = time.UnixMilli(createdAt).UTC()
+	entry.UpdatedAt = time.UnixMilli(updatedAt).UTC()
+
+	return entry, nil
+}
+
+// sortEntries orders entries oldest first
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+		}
+
+		return entries[i].ID < entries[j].ID
+	})
+}
+
+// millis returns t in Unix milliseconds
+func millis(t time.Time) int64 {
+	return t.UnixMilli()
+}
@@ -0,0 +1,127 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/kvstore"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
+)
+
+// PageError is returned when a page can't be fetched. Checkpoint holds the
+// options of the failed page, so a long export can resume from it later with
+// WithPageOptions instead of starting over.
+type PageError struct {
+	// Page is the number of the failed page, starting at 1
+	Page int
+
+	// Checkpoint holds the options to fetch the failed page again
+	Checkpoint PageOptions
+
+	// Err is the error of the last attempt
+	Err error
+}
+
+// Error implements the error interface
+func (e *PageError) Error() string {
+	if e.Checkpoint.Cursor != "" {
+		return fmt.Sprintf("failed to fetch page %d (cursor %q): %v", e.Page, e.Checkpoint.Cursor, e.Err)
+	}
+
+	return fmt.Sprintf("failed to fetch page %d (offset %d): %v", e.Page, e.Checkpoint.Offset, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
+
+// Checkpoint returns the options to resume a pagination that failed with err.
+//
+// Example:
+//
+//	if checkpoint, ok := pagination.Checkpoint(paginator.Err()); ok {
+//	    err := pagination.SaveCheckpoint(ctx, store, "export/transactions", checkpoint)
+//	    // later: pagination.LoadCheckpoint, then pagination.WithPageOptions(checkpoint)
+//	}
+func Checkpoint(err error) (PageOptions, bool) {
+	var pageErr *PageError
+	if !errors.As(err, &pageErr) {
+		return PageOptions{}, false
+	}
+
+	return pageErr.Checkpoint, true
+}
+
+// SaveCheckpoint stores the options of a page under key of store, so that a
+// pagination can resume from it in another run or on another host.
+func SaveCheckpoint(ctx context.Context, store kvstore.Interface, key string, options PageOptions) error {
+	if err := kvstore.PutJSON(ctx, store, key, options); err != nil {
+		return fmt.Errorf("failed to save pagination checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// LoadCheckpoint returns the page options saved under key of store by
+// SaveCheckpoint. It returns false, and no error, when none was saved.
+func LoadCheckpoint(ctx context.Context, store kvstore.Interface, key string) (PageOptions, bool, error) {
+	var options PageOptions
+
+	found, err := kvstore.GetJSON(ctx, store, key, &options)
+	if err != nil {
+		return PageOptions{}, false, fmt.Errorf("failed to load pagination checkpoint: %w", err)
+	}
+
+	return options, found, nil
+}
+
+// WithPageRetry retries a failed page fetch before giving up on the
+// pagination, so a single flaky page doesn't abort a long export. Only the
+// failed page is fetched again. Without options the retry package defaults
+// apply; errors are retried as decided by retry.IsRetryableError.
+func WithPageRetry(options ...retry.Option) PaginatorOption {
+	return func(o *PaginatorOptions) error {
+		retryOptions := retry.DefaultOptions()
+		for _, option := range options {
+			if err := option(retryOptions); err != nil {
+				return fmt.Errorf("invalid page retry option: %w", err)
+			}
+		}
+
+		o.RetryPages = true
+		o.PageRetryOptions = options
+
+		return nil
+	}
+}
+
+// fetchPage fetches the page at the current options, retrying it when page
+// retry is enabled. Failures are returned as a *PageError.
+func (p *defaultPaginator[T]) fetchPage(ctx context.Context, page int) (*PageResult[T], error) {
+	options := p.options
+
+	var result *PageResult[T]
+
+	fetch := func() error {
+		var err error
+
+		result, err = p.fetcher(ctx, options)
+
+		return err
+	}
+
+	var err error
+	if p.retryPages {
+		err = retry.Do(ctx, fetch, p.pageRetryOptions...)
+	} else {
+		err = fetch()
+	}
+
+	if err != nil {
+		return nil, &PageError{Page: page, Checkpoint: options, Err: err}
+	}
+
+	return result, nil
+}
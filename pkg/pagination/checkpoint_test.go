@@ -0,0 +1,144 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/kvstore"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
+)
+
+// flakyFetcher serves pages of 2 items and fails the requests listed in failures.
+type flakyFetcher struct {
+	calls    int
+	failures map[int]error
+	offsets  []int
+}
+
+func (f *flakyFetcher) fetch(_ context.Context, options PageOptions) (*PageResult[int], error) {
+	f.calls++
+	f.offsets = append(f.offsets, options.Offset)
+
+	if err, ok := f.failures[f.calls]; ok {
+		return nil, err
+	}
+
+	return &PageResult[int]{
+		Items:   []int{options.Offset, options.Offset + 1},
+		HasMore: options.Offset < 4,
+	}, nil
+}
+
+func TestWithPageRetry(t *testing.T) {
+	fetcher := &flakyFetcher{failures: map[int]error{2: errors.New("service unavailable")}}
+
+	paginator, err := NewPaginator(fetcher.fetch,
+		WithLimit(2),
+		WithPageRetry(retry.WithInitialDelay(time.Millisecond), retry.WithJitterFactor(0)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create paginator: %v", err)
+	}
+
+	items, err := paginator.All(context.Background())
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+
+	if len(items) != 6 {
+		t.Errorf("Expected 6 items, got %d", len(items))
+	}
+
+	expected := []int{0, 2, 2, 4}
+	if len(fetcher.offsets) != len(expected) {
+		t.Fatalf("Expected fetches at offsets %v, got %v", expected, fetcher.offsets)
+	}
+
+	for i, offset := range expected {
+		if fetcher.offsets[i] != offset {
+			t.Errorf("Expected fetches at offsets %v, got %v", expected, fetcher.offsets)
+			break
+		}
+	}
+}
+
+func TestPageErrorCheckpoint(t *testing.T) {
+	permanent := errors.New("service unavailable")
+	fetcher := &flakyFetcher{failures: map[int]error{2: permanent, 3: permanent}}
+
+	paginator, err := NewPaginator(fetcher.fetch,
+		WithLimit(2),
+		WithPageRetry(retry.WithMaxRetries(1), retry.WithInitialDelay(time.Millisecond)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create paginator: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if !paginator.Next(ctx) {
+		t.Fatalf("Expected first page, got %v", paginator.Err())
+	}
+
+	if paginator.Next(ctx) {
+		t.Fatal("Expected second page to fail")
+	}
+
+	if !errors.Is(paginator.Err(), permanent) {
+		t.Errorf("Expected error %v, got %v", permanent, paginator.Err())
+	}
+
+	var pageErr *PageError
+	if !errors.As(paginator.Err(), &pageErr) || pageErr.Page != 2 {
+		t.Fatalf("Expected a PageError for page 2, got %v", paginator.Err())
+	}
+
+	checkpoint, ok := Checkpoint(paginator.Err())
+	if !ok || checkpoint.Offset != 2 || checkpoint.Limit != 2 {
+		t.Fatalf("Expected checkpoint at offset 2, got %+v", checkpoint)
+	}
+
+	// resume from the checkpoint once the service is back
+	resumed, err := NewPaginator(fetcher.fetch, WithPageOptions(checkpoint))
+	if err != nil {
+		t.Fatalf("Failed to create paginator: %v", err)
+	}
+
+	items, err := resumed.All(ctx)
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+
+	if len(items) != 4 || items[0] != 2 {
+		t.Errorf("Expected items from offset 2, got %v", items)
+	}
+
+	if _, ok := Checkpoint(errors.New("other")); ok {
+		t.Error("Expected no checkpoint for an unrelated error")
+	}
+}
+
+func TestSaveAndLoadCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	store := kvstore.NewMemory()
+
+	if _, found, err := LoadCheckpoint(ctx, store, "export"); err != nil || found {
+		t.Fatalf("Expected no checkpoint, got found=%v err=%v", found, err)
+	}
+
+	saved := PageOptions{Limit: 50, Cursor: "abc", Filters: map[string]string{"status": "ACTIVE"}}
+	if err := SaveCheckpoint(ctx, store, "export", saved); err != nil {
+		t.Fatalf("SaveCheckpoint failed: %v", err)
+	}
+
+	loaded, found, err := LoadCheckpoint(ctx, store, "export")
+	if err != nil || !found {
+		t.Fatalf("Expected a checkpoint, got found=%v err=%v", found, err)
+	}
+
+	if loaded.Limit != 50 || loaded.Cursor != "abc" || loaded.Filters["status"] != "ACTIVE" {
+		t.Errorf("Expected %+v, got %+v", saved, loaded)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/retry"
 )
 
 // MaxPaginationLimit is the maximum allowed limit for pagination requests.
@@ -46,6 +48,12 @@ type PaginatorOptions struct {
 
 	// Default limit when not specified
 	DefaultLimit int
+
+	// RetryPages retries a failed page fetch, see WithPageRetry
+	RetryPages bool
+
+	// PageRetryOptions configure the retries of a failed page fetch
+	PageRetryOptions []retry.Option
 }
 
 // PageResult represents a single page of results
@@ -71,7 +79,8 @@ type Paginator[T any] interface {
 	// PageInfo returns information about the current page
 	PageInfo() PageInfo
 
-	// All retrieves all remaining items across multiple pages
+	// All retrieves all remaining items across multiple pages, holding them
+	// all in memory; use ForEach or CollectAtMost for large lists
 	All(ctx context.Context) ([]T, error)
 
 	// ForEach iterates through all items across pages
@@ -93,16 +102,18 @@ type PageInfo struct {
 
 // defaultPaginator is the default implementation of Paginator
 type defaultPaginator[T any] struct {
-	fetcher       PageFetcher[T]
-	currentPage   *PageResult[T]
-	options       PageOptions
-	pageNumber    int
-	totalItems    int
-	err           error
-	observer      Observer
-	operationName string
-	entityType    string
-	mu            sync.Mutex
+	fetcher          PageFetcher[T]
+	currentPage      *PageResult[T]
+	options          PageOptions
+	pageNumber       int
+	totalItems       int
+	err              error
+	observer         Observer
+	operationName    string
+	entityType       string
+	retryPages       bool
+	pageRetryOptions []retry.Option
+	mu               sync.Mutex
 }
 
 // DefaultPaginatorOptions returns the default options for a paginator
@@ -264,12 +275,14 @@ func NewPaginator[T any](
 
 	// Create and return the paginator
 	return &defaultPaginator[T]{
-		fetcher:       fetcher,
-		options:       opts.PageOptions,
-		pageNumber:    0,
-		observer:      opts.Observer,
-		operationName: opts.OperationName,
-		entityType:    opts.EntityType,
+		fetcher:          fetcher,
+		options:          opts.PageOptions,
+		pageNumber:       0,
+		observer:         opts.Observer,
+		operationName:    opts.OperationName,
+		entityType:       opts.EntityType,
+		retryPages:       opts.RetryPages,
+		pageRetryOptions: opts.PageRetryOptions,
 	}, nil
 }
 
@@ -339,7 +352,7 @@ func (p *defaultPaginator[T]) Next(ctx context.Context) bool {
 	// Fetch the next page
 	var err error
 
-	p.currentPage, err = p.fetcher(ctx, p.options)
+	p.currentPage, err = p.fetchPage(ctx, p.pageNumber+1)
 
 	// Record pagination metrics
 	duration := time.Since(start)
@@ -438,8 +451,8 @@ func (p *defaultPaginator[T]) All(ctx context.Context) ([]T, error) {
 
 	// Fetch all remaining pages directly
 	// We're already under lock so we can use our own fetcher
-	for {
-		pageResult, err := p.fetcher(ctx, p.options)
+	for page := p.pageNumber + 1; ; page++ {
+		pageResult, err := p.fetchPage(ctx, page)
 		if err != nil {
 			return allItems, err
 		}
@@ -611,7 +624,8 @@ func (p *defaultPaginator[T]) Concurrent(ctx context.Context, workers int, fn fu
 	return collectWorkerError(errCh)
 }
 
-// CollectAll is a shortcut function to create a Paginator and collect all items
+// CollectAll is a shortcut function to create a Paginator and collect all items.
+// Its memory grows with the list; use CollectAtMost to bound it.
 func CollectAll[T any](
 	ctx context.Context,
 	operationName string,
@@ -634,3 +648,49 @@ func CollectAll[T any](
 
 	return paginator.All(ctx)
 }
+
+// ErrTooManyItems is returned by CollectAtMost when the list holds more items
+// than allowed.
+var ErrTooManyItems = errors.New("pagination: list holds more items than allowed")
+
+// CollectAtMost collects the items of a list like CollectAll, up to maxItems.
+// If the list holds more, it stops fetching and returns the first maxItems
+// items with ErrTooManyItems, so that an unexpectedly large list fails
+// instead of exhausting memory. A maxItems below 1 is an error.
+//
+// Memory ceiling: maxItems items plus one page.
+func CollectAtMost[T any](
+	ctx context.Context,
+	operationName string,
+	entityType string,
+	fetcher PageFetcher[T],
+	options PageOptions,
+	maxItems int,
+) ([]T, error) {
+	if maxItems < 1 {
+		return []T{}, fmt.Errorf("maxItems must be positive, got %d", maxItems)
+	}
+
+	paginator, err := NewPaginator(fetcher,
+		WithOperationName(operationName),
+		WithEntityType(entityType),
+		WithPageOptions(options),
+	)
+	if err != nil {
+		return []T{}, err
+	}
+
+	items := []T{}
+
+	err = paginator.ForEach(ctx, func(item T) error {
+		if len(items) == maxItems {
+			return fmt.Errorf("%w: more than %d %s", ErrTooManyItems, maxItems, entityType)
+		}
+
+		items = append(items, item)
+
+		return nil
+	})
+
+	return items, err
+}
@@ -254,3 +254,41 @@ func TestCollectAll(t *testing.T) {
 		t.Errorf("Expected %d items, got %d", totalItems, len(allItems))
 	}
 }
+
+func TestCollectAtMost(t *testing.T) {
+	pages := [][]string{
+		{"item1", "item2", "item3"},
+		{"item4", "item5", "item6"},
+		{"item7", "item8", "item9"},
+	}
+
+	ctx := context.Background()
+
+	items, err := CollectAtMost(ctx, "TestOperation", "items", newMockFetcher(pages, 9).fetch, PageOptions{Limit: 3}, 9)
+	if err != nil {
+		t.Fatalf("CollectAtMost failed: %v", err)
+	}
+
+	if len(items) != 9 {
+		t.Errorf("Expected 9 items, got %d", len(items))
+	}
+
+	fetcher := newMockFetcher(pages, 9)
+
+	items, err = CollectAtMost(ctx, "TestOperation", "items", fetcher.fetch, PageOptions{Limit: 3}, 4)
+	if !errors.Is(err, ErrTooManyItems) {
+		t.Fatalf("Expected ErrTooManyItems, got %v", err)
+	}
+
+	if len(items) != 4 || items[3] != "item4" {
+		t.Errorf("Expected the first 4 items, got %v", items)
+	}
+
+	if fetcher.callCount != 2 {
+		t.Errorf("Expected the fetch to stop after 2 pages, got %d", fetcher.callCount)
+	}
+
+	if _, err := CollectAtMost(ctx, "TestOperation", "items", newMockFetcher(pages, 9).fetch, PageOptions{Limit: 3}, 0); err == nil {
+		t.Error("Expected an error for a zero maxItems")
+	}
+}
@@ -89,6 +89,15 @@ type TransportConfig struct {
 	// connection. If zero, keep-alives are enabled if supported
 	// by the protocol and operating system.
 	KeepAlive time.Duration
+
+	// Resolver looks up host names, e.g. against the DNS server of a
+	// split-horizon network. Nil uses the system resolver.
+	Resolver *net.Resolver
+
+	// FallbackDelay is how long a dual-stack dial waits for the IPv6
+	// connection before racing an IPv4 one (happy eyeballs). Zero uses the
+	// Go default of 300ms; negative disables the fallback.
+	FallbackDelay time.Duration
 }
 
 // TransportOption defines a function that configures a TransportConfig
@@ -303,8 +312,10 @@ func newTransportWithConfig(config *TransportConfig) *http.Transport {
 	return &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
-			Timeout:   config.DialTimeout,
-			KeepAlive: config.KeepAlive,
+			Timeout:       config.DialTimeout,
+			KeepAlive:     config.KeepAlive,
+			Resolver:      config.Resolver,
+			FallbackDelay: config.FallbackDelay,
 		}).DialContext,
 		MaxIdleConns:          config.MaxIdleConns,
 		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
@@ -0,0 +1,139 @@
+package performance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// DefaultMaxRedirects is the number of redirects followed by the net/http default policy.
+const DefaultMaxRedirects = 10
+
+// ErrRedirectNotAllowed is returned, wrapped, by requests stopped by a RedirectPolicy.
+var ErrRedirectNotAllowed = errors.New("redirect not allowed")
+
+// RedirectPolicy decides whether a client follows a redirect, with the
+// semantics of http.Client.CheckRedirect: req is the upcoming request and via
+// the requests made so far, oldest first. Returning http.ErrUseLastResponse
+// returns the redirect response itself instead of an error.
+type RedirectPolicy func(req *http.Request, via []*http.Request) error
+
+// NoRedirects returns the redirect responses to the caller instead of
+// following them, so that an unexpected redirect from a proxy or a mesh
+// sidecar surfaces as a 3xx status rather than a silent hop.
+func NoRedirects() RedirectPolicy {
+	return func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+}
+
+// LimitRedirects follows at most max redirects.
+func LimitRedirects(maxRedirects int) RedirectPolicy {
+	return func(_ *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			return fmt.Errorf("%w: stopped after %d redirects", ErrRedirectNotAllowed, maxRedirects)
+		}
+
+		return nil
+	}
+}
+
+// SameHostRedirects follows at most max redirects, and only to the host and
+// scheme of the original request; a redirect elsewhere, such as from HTTPS
+// to HTTP or to another service, fails with ErrRedirectNotAllowed.
+func SameHostRedirects(maxRedirects int) RedirectPolicy {
+	limit := LimitRedirects(maxRedirects)
+
+	return func(req *http.Request, via []*http.Request) error {
+		if err := limit(req, via); err != nil {
+			return err
+		}
+
+		if len(via) > 0 {
+			origin := via[0].URL
+			if req.URL.Scheme != origin.Scheme || req.URL.Host != origin.Host {
+				return fmt.Errorf("%w: %s redirects to %s://%s", ErrRedirectNotAllowed, origin.Host, req.URL.Scheme, req.URL.Host)
+			}
+		}
+
+		return nil
+	}
+}
+
+// WithRedirectPolicy sets the redirect policy of the HTTP client. A nil
+// policy restores the net/http default of following up to DefaultMaxRedirects.
+//
+// Example use case: Calling Midaz through a gateway that must never bounce requests elsewhere:
+//
+//	client, err := performance.NewClient(performance.WithRedirectPolicy(performance.SameHostRedirects(3)))
+func WithRedirectPolicy(policy RedirectPolicy) HTTPClientOption {
+	return func(c *http.Client) error {
+		c.CheckRedirect = policy
+		return nil
+	}
+}
+
+// WithResolver sets the resolver used to look up host names instead of the
+// system resolver, e.g. a resolver bound to the DNS server of a split-horizon
+// network or a service mesh.
+func WithResolver(resolver *net.Resolver) TransportOption {
+	return func(c *TransportConfig) error {
+		c.Resolver = resolver
+		return nil
+	}
+}
+
+// WithDNSServer resolves host names with the DNS server at address
+// ("host:port"), bypassing /etc/resolv.conf and the system resolver. The
+// lookups use the pure Go resolver and time out after timeout; zero uses
+// DefaultDNSTimeout.
+func WithDNSServer(address string, timeout time.Duration) TransportOption {
+	return func(c *TransportConfig) error {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return fmt.Errorf("invalid DNS server address %q: %w", address, err)
+		}
+
+		if timeout < 0 {
+			return fmt.Errorf("DNS timeout must be non-negative, got %v", timeout)
+		}
+
+		c.Resolver = NewDNSResolver(address, timeout)
+
+		return nil
+	}
+}
+
+// DefaultDNSTimeout bounds the connection to the DNS server of NewDNSResolver.
+const DefaultDNSTimeout = 5 * time.Second
+
+// NewDNSResolver returns a resolver querying the DNS server at address
+// ("host:port") over the network the lookup asks for (UDP, or TCP for large
+// answers).
+func NewDNSResolver(address string, timeout time.Duration) *net.Resolver {
+	if timeout <= 0 {
+		timeout = DefaultDNSTimeout
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{Timeout: timeout}
+			return d.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// WithFallbackDelay tunes happy eyeballs: how long a dial to a dual-stack
+// host waits for the IPv6 connection before starting an IPv4 one in parallel.
+// Zero uses the Go default of 300ms; a negative delay disables the fallback,
+// so that the addresses are tried in order, which keeps connections on the
+// address family a mesh or a firewall expects.
+func WithFallbackDelay(d time.Duration) TransportOption {
+	return func(c *TransportConfig) error {
+		c.FallbackDelay = d
+		return nil
+	}
+}
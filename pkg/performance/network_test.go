@@ -0,0 +1,151 @@
+package performance
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRedirectPolicies(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer other.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hop1":
+			http.Redirect(w, r, "/hop2", http.StatusFound)
+		case "/hop2":
+			http.Redirect(w, r, "/done", http.StatusFound)
+		case "/away":
+			http.Redirect(w, r, other.URL+"/done", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+
+	get := func(t *testing.T, policy RedirectPolicy, path string) (*http.Response, error) {
+		t.Helper()
+
+		client, err := NewClient(WithRedirectPolicy(policy))
+		if err != nil {
+			t.Fatalf("NewClient returned error: %v", err)
+		}
+
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+path, http.NoBody)
+
+		resp, err := client.Do(req)
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		return resp, err
+	}
+
+	t.Run("NoRedirects", func(t *testing.T) {
+		resp, err := get(t, NoRedirects(), "/hop1")
+		if err != nil {
+			t.Fatalf("Expected the redirect response, got %v", err)
+		}
+
+		if resp.StatusCode != http.StatusFound {
+			t.Errorf("Expected status 302, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("LimitRedirects", func(t *testing.T) {
+		if _, err := get(t, LimitRedirects(2), "/hop1"); err != nil {
+			t.Errorf("Expected 2 redirects to be followed, got %v", err)
+		}
+
+		if _, err := get(t, LimitRedirects(1), "/hop1"); !errors.Is(err, ErrRedirectNotAllowed) {
+			t.Errorf("Expected ErrRedirectNotAllowed, got %v", err)
+		}
+	})
+
+	t.Run("SameHostRedirects", func(t *testing.T) {
+		if _, err := get(t, SameHostRedirects(5), "/hop1"); err != nil {
+			t.Errorf("Expected same-host redirects to be followed, got %v", err)
+		}
+
+		if _, err := get(t, SameHostRedirects(5), "/away"); !errors.Is(err, ErrRedirectNotAllowed) {
+			t.Errorf("Expected a cross-host redirect to fail, got %v", err)
+		}
+	})
+
+	t.Run("DefaultPolicy", func(t *testing.T) {
+		resp, err := get(t, nil, "/away")
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected the default policy to follow redirects, got %v", err)
+		}
+	})
+}
+
+func TestResolverOptions(t *testing.T) {
+	resolver := &net.Resolver{PreferGo: true}
+
+	config, err := NewTransportConfig(WithResolver(resolver), WithFallbackDelay(-1))
+	if err != nil {
+		t.Fatalf("NewTransportConfig returned error: %v", err)
+	}
+
+	if config.Resolver != resolver {
+		t.Error("Expected the custom resolver to be set")
+	}
+
+	if config.FallbackDelay != -1 {
+		t.Errorf("Expected FallbackDelay=-1, got %v", config.FallbackDelay)
+	}
+
+	if _, err := NewTransportConfig(WithDNSServer("10.0.0.2", 0)); err == nil {
+		t.Error("Expected an error for a DNS server without port")
+	}
+
+	if _, err := NewTransportConfig(WithDNSServer("10.0.0.2:53", -time.Second)); err == nil {
+		t.Error("Expected an error for a negative DNS timeout")
+	}
+
+	config, err = NewTransportConfig(WithDNSServer("10.0.0.2:53", time.Second))
+	if err != nil {
+		t.Fatalf("NewTransportConfig returned error: %v", err)
+	}
+
+	if config.Resolver == nil || !config.Resolver.PreferGo {
+		t.Error("Expected a pure Go resolver for the DNS server")
+	}
+}
+
+func TestDNSResolverQueriesServer(t *testing.T) {
+	// a DNS server that never answers: the lookup must go to it and time out
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("UDP not available: %v", err)
+	}
+	defer conn.Close()
+
+	queried := make(chan struct{}, 1)
+
+	go func() {
+		buf := make([]byte, 512)
+		if _, _, err := conn.ReadFrom(buf); err == nil {
+			queried <- struct{}{}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	_, _ = NewDNSResolver(conn.LocalAddr().String(), time.Second).LookupHost(ctx, "midaz.internal")
+
+	select {
+	case <-queried:
+	case <-time.After(time.Second):
+		t.Error("Expected the lookup to query the custom DNS server")
+	}
+}
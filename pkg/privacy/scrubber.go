@@ -0,0 +1,450 @@
+// Package privacy provides utilities to honor data subject requests, such as
+// GDPR erasure, against the metadata stored in Midaz.
+//
+// Ledger records themselves are immutable, but the free-form metadata attached
+// to organizations, accounts and transactions often carries personal data. The
+// Scrubber finds every entity whose metadata identifies a subject and redacts,
+// removes, hashes or replaces the configured keys through update calls, returning
+// an audit report of what was changed. The report never includes the original
+// values.
+//
+// Example:
+//
+//	report, err := privacy.NewScrubber(client.Entity).
+//	    WithRules(
+//	        privacy.Redact("email"),
+//	        privacy.Remove("phone"),
+//	        privacy.Hash("customerId", salt),
+//	    ).
+//	    Scrub(ctx, orgID, privacy.Subject{Key: "customerId", Value: "c-123"})
+package privacy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+)
+
+// RedactedValue is the value written by the redact action.
+const RedactedValue = "[REDACTED]"
+
+// Action is what a rule does to a metadata key.
+type Action string
+
+const (
+	// ActionRedact overwrites the value with RedactedValue
+	ActionRedact Action = "redact"
+
+	// ActionRemove sends the key as null so it is dropped from the metadata
+	ActionRemove Action = "remove"
+
+	// ActionHash replaces the value with a salted SHA-256 digest, keeping records
+	// of the same subject correlatable without exposing the value
+	ActionHash Action = "hash"
+
+	// ActionReplace overwrites the value with a fixed replacement
+	ActionReplace Action = "replace"
+)
+
+// Kind identifies the entity type of a change.
+type Kind string
+
+// Entity kinds visited by the Scrubber.
+const (
+	KindOrganization Kind = "organization"
+	KindAccount      Kind = "account"
+	KindTransaction  Kind = "transaction"
+)
+
+// Rule rewrites a single metadata key.
+type Rule struct {
+	Key         string
+	Action      Action
+	Replacement any
+	Salt        string
+}
+
+// Redact returns a rule overwriting key with RedactedValue.
+func Redact(key string) Rule { return Rule{Key: key, Action: ActionRedact} }
+
+// Remove returns a rule removing key from the metadata.
+func Remove(key string) Rule { return Rule{Key: key, Action: ActionRemove} }
+
+// Hash returns a rule replacing key with the hex SHA-256 digest of salt and the value.
+func Hash(key, salt string) Rule { return Rule{Key: key, Action: ActionHash, Salt: salt} }
+
+// Replace returns a rule overwriting key with replacement.
+func Replace(key string, replacement any) Rule {
+	return Rule{Key: key, Action: ActionReplace, Replacement: replacement}
+}
+
+// apply returns the rewritten value of the rule's key.
+func (r Rule) apply(value any) any {
+	switch r.Action {
+	case ActionRemove:
+		return nil
+	case ActionHash:
+		sum := sha256.Sum256([]byte(r.Salt + fmt.Sprint(value)))
+		return hex.EncodeToString(sum[:])
+	case ActionReplace:
+		return r.Replacement
+	default:
+		return RedactedValue
+	}
+}
+
+// validate checks the rule is usable.
+func (r Rule) validate() error {
+	if r.Key == "" {
+		return errors.New("rule key is required")
+	}
+
+	switch r.Action {
+	case ActionRedact, ActionRemove, ActionHash, ActionReplace:
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q for key %q", r.Action, r.Key)
+	}
+}
+
+// Subject identifies the data subject: entities whose metadata holds Value under
+// Key belong to the subject.
+type Subject struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// matches reports whether metadata belongs to the subject.
+func (s Subject) matches(metadata map[string]any) bool {
+	v, ok := metadata[s.Key]
+	return ok && v != nil && fmt.Sprint(v) == s.Value
+}
+
+// Change is a metadata key rewritten on an entity. Original values are never
+// recorded.
+type Change struct {
+	Kind     Kind   `json:"kind"`
+	ID       string `json:"id"`
+	LedgerID string `json:"ledgerId,omitempty"`
+	Key      string `json:"key"`
+	Action   Action `json:"action"`
+	Applied  bool   `json:"applied"`
+}
+
+// Failure is an entity the Scrubber could not update.
+type Failure struct {
+	Kind     Kind   `json:"kind"`
+	ID       string `json:"id"`
+	LedgerID string `json:"ledgerId,omitempty"`
+	Error    string `json:"error"`
+}
+
+// Report is the audit trail of a scrub.
+type Report struct {
+	OrganizationID string    `json:"organizationId"`
+	SubjectKey     string    `json:"subjectKey"`
+	DryRun         bool      `json:"dryRun"`
+	StartedAt      time.Time `json:"startedAt"`
+	FinishedAt     time.Time `json:"finishedAt"`
+	Matched        int       `json:"matched"`
+	Changes        []Change  `json:"changes"`
+	Failures       []Failure `json:"failures,omitempty"`
+}
+
+// Count returns the number of changes recorded for kind.
+func (r *Report) Count(kind Kind) int {
+	n := 0
+
+	for _, c := range r.Changes {
+		if c.Kind == kind {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Scrubber rewrites the metadata of every entity belonging to a subject.
+type Scrubber struct {
+	e       *entities.Entity
+	rules   []Rule
+	ledgers []string
+	dryRun  bool
+	obs     observability.Provider
+	now     func() time.Time
+}
+
+// NewScrubber creates a Scrubber with no rules.
+func NewScrubber(e *entities.Entity) *Scrubber {
+	return &Scrubber{e: e, now: time.Now}
+}
+
+// WithRules adds rules applied to every matching entity.
+func (s *Scrubber) WithRules(rules ...Rule) *Scrubber {
+	s.rules = append(s.rules, rules...)
+	return s
+}
+
+// WithLedgers limits the scrub to the given ledgers. By default every ledger of
+// the organization is visited.
+func (s *Scrubber) WithLedgers(ledgerIDs ...string) *Scrubber {
+	s.ledgers = append(s.ledgers, ledgerIDs...)
+	return s
+}
+
+// WithDryRun reports the changes without issuing update calls.
+func (s *Scrubber) WithDryRun(dryRun bool) *Scrubber {
+	s.dryRun = dryRun
+	return s
+}
+
+// WithObservability sets the observability provider for tracing.
+func (s *Scrubber) WithObservability(obs observability.Provider) *Scrubber {
+	s.obs = obs
+	return s
+}
+
+// Scrub applies the rules to the organization, accounts and transactions whose
+// metadata identifies subject. Entities that fail to update are recorded in the
+// report and do not stop the scrub; errors listing entities do.
+func (s *Scrubber) Scrub(ctx context.Context, orgID string, subject Subject) (*Report, error) {
+	if s.e == nil || s.e.Organizations == nil || s.e.Ledgers == nil {
+		return nil, errors.New("entities not initialized for metadata scrubbing")
+	}
+
+	if orgID == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	if subject.Key == "" || subject.Value == "" {
+		return nil, errors.New("subject key and value are required")
+	}
+
+	if len(s.rules) == 0 {
+		return nil, errors.New("at least one rule is required")
+	}
+
+	for _, r := range s.rules {
+		if err := r.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &Report{
+		OrganizationID: orgID,
+		SubjectKey:     subject.Key,
+		DryRun:         s.dryRun,
+		StartedAt:      s.now().UTC(),
+		Changes:        []Change{},
+	}
+
+	err := observability.WithSpan(ctx, s.obs, "Privacy.Scrub", func(ctx context.Context) error {
+		return s.scrubOrganization(ctx, orgID, subject, report)
+	})
+
+	report.FinishedAt = s.now().UTC()
+
+	if err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// target is an entity belonging to the subject.
+type target struct {
+	kind     Kind
+	id       string
+	ledgerID string
+	metadata map[string]any
+	update   func(ctx context.Context, metadata map[string]any) error
+}
+
+func (s *Scrubber) scrubOrganization(ctx context.Context, orgID string, subject Subject, report *Report) error {
+	org, err := s.e.Organizations.GetOrganization(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	if subject.matches(org.Metadata) {
+		s.apply(ctx, report, target{
+			kind:     KindOrganization,
+			id:       org.ID,
+			metadata: org.Metadata,
+			update: func(ctx context.Context, metadata map[string]any) error {
+				input := models.NewUpdateOrganizationInput().WithLegalName(org.LegalName).WithUpdateMetadata(metadata)
+				_, err := s.e.Organizations.UpdateOrganization(ctx, org.ID, input)
+
+				return err
+			},
+		})
+	}
+
+	ledgerIDs := s.ledgers
+	if len(ledgerIDs) == 0 {
+		err := paginate(ctx, nil, func(opts *models.ListOptions) (*models.ListResponse[models.Ledger], error) {
+			return s.e.Ledgers.ListLedgers(ctx, orgID, opts)
+		}, func(l models.Ledger) { ledgerIDs = append(ledgerIDs, l.ID) })
+		if err != nil {
+			return fmt.Errorf("failed to list ledgers: %w", err)
+		}
+	}
+
+	for _, ledgerID := range ledgerIDs {
+		// targets are collected before updating: rewriting the subject key would
+		// otherwise shift the filtered pages still to be read
+		targets, err := s.collectLedger(ctx, orgID, ledgerID, subject)
+		if err != nil {
+			return fmt.Errorf("ledger %s: %w", ledgerID, err)
+		}
+
+		for _, t := range targets {
+			s.apply(ctx, report, t)
+		}
+	}
+
+	return nil
+}
+
+// collectLedger returns the accounts and transactions of a ledger belonging to subject.
+func (s *Scrubber) collectLedger(ctx context.Context, orgID, ledgerID string, subject Subject) ([]target, error) {
+	var targets []target
+
+	// the metadata filter narrows the listing server side; matches are still
+	// checked locally
+	filter := map[string]string{"metadata." + subject.Key: subject.Value}
+
+	if s.e.Accounts != nil {
+		err := paginate(ctx, filter, func(opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+			return s.e.Accounts.ListAccounts(ctx, orgID, ledgerID, opts)
+		}, func(a models.Account) {
+			if !subject.matches(a.Metadata) {
+				return
+			}
+
+			targets = append(targets, target{
+				kind:     KindAccount,
+				id:       a.ID,
+				ledgerID: ledgerID,
+				metadata: a.Metadata,
+				update: func(ctx context.Context, metadata map[string]any) error {
+					input := &models.UpdateAccountInput{Name: a.Name, Status: a.Status, Metadata: metadata}
+					_, err := s.e.Accounts.UpdateAccount(ctx, orgID, ledgerID, a.ID, input)
+
+					return err
+				},
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+	}
+
+	if s.e.Transactions != nil {
+		err := paginate(ctx, filter, func(opts *models.ListOptions) (*models.ListResponse[models.Transaction], error) {
+			return s.e.Transactions.ListTransactions(ctx, orgID, ledgerID, opts)
+		}, func(t models.Transaction) {
+			if !subject.matches(t.Metadata) {
+				return
+			}
+
+			targets = append(targets, target{
+				kind:     KindTransaction,
+				id:       t.ID,
+				ledgerID: ledgerID,
+				metadata: t.Metadata,
+				update: func(ctx context.Context, metadata map[string]any) error {
+					input := models.NewUpdateTransactionInput().WithMetadata(metadata)
+					_, err := s.e.Transactions.UpdateTransaction(ctx, orgID, ledgerID, t.ID, input)
+
+					return err
+				},
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list transactions: %w", err)
+		}
+	}
+
+	return targets, nil
+}
+
+// apply rewrites the metadata of t and records the outcome in report.
+func (s *Scrubber) apply(ctx context.Context, report *Report, t target) {
+	report.Matched++
+
+	metadata := make(map[string]any, len(t.metadata))
+	for k, v := range t.metadata {
+		metadata[k] = v
+	}
+
+	var changes []Change
+
+	for _, r := range s.rules {
+		v, ok := t.metadata[r.Key]
+		if !ok {
+			continue
+		}
+
+		metadata[r.Key] = r.apply(v)
+		changes = append(changes, Change{Kind: t.kind, ID: t.id, LedgerID: t.ledgerID, Key: r.Key, Action: r.Action})
+	}
+
+	if len(changes) == 0 {
+		return
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+
+	if !s.dryRun {
+		if err := t.update(ctx, metadata); err != nil {
+			report.Failures = append(report.Failures, Failure{Kind: t.kind, ID: t.id, LedgerID: t.ledgerID, Error: err.Error()})
+			report.Changes = append(report.Changes, changes...)
+
+			return
+		}
+
+		for i := range changes {
+			changes[i].Applied = true
+		}
+	}
+
+	report.Changes = append(report.Changes, changes...)
+}
+
+// paginate calls fn for every item of every page returned by list.
+func paginate[T any](ctx context.Context, filters map[string]string, list func(opts *models.ListOptions) (*models.ListResponse[T], error), fn func(T)) error {
+	opts := models.NewListOptions().WithLimit(models.MaxLimit)
+
+	for opts != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// next page options do not carry filters over
+		page, err := list(opts.WithFilters(filters))
+		if err != nil {
+			return err
+		}
+
+		for _, item := range page.Items {
+			fn(item)
+		}
+
+		if len(page.Items) == 0 {
+			return nil
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeServices struct {
+	entities.OrganizationsService
+	entities.LedgersService
+	entities.AccountsService
+	entities.TransactionsService
+
+	org          models.Organization
+	accounts     []models.Account
+	transactions []models.Transaction
+	filters      []map[string]string
+	failAccount  string
+
+	orgUpdates     map[string]map[string]any
+	accountUpdates map[string]*models.UpdateAccountInput
+	txUpdates      map[string]map[string]any
+}
+
+func newFakeServices() *fakeServices {
+	return &fakeServices{
+		org: models.Organization{ID: "org-1", LegalName: "Acme", Metadata: map[string]any{"customerId": "c-1", "email": "ceo@acme.test"}},
+		accounts: []models.Account{
+			{ID: "acc-1", Name: "Checking", Metadata: map[string]any{"customerId": "c-1", "email": "a@b.test", "phone": "555", "tier": "gold"}},
+			{ID: "acc-2", Name: "Other", Metadata: map[string]any{"customerId": "c-2", "email": "x@y.test"}},
+			{ID: "acc-3", Name: "Savings", Metadata: map[string]any{"customerId": "c-1"}},
+		},
+		transactions: []models.Transaction{
+			{ID: "tx-1", Metadata: map[string]any{"customerId": "c-1", "email": "a@b.test"}},
+			{ID: "tx-2", Metadata: nil},
+		},
+		orgUpdates:     map[string]map[string]any{},
+		accountUpdates: map[string]*models.UpdateAccountInput{},
+		txUpdates:      map[string]map[string]any{},
+	}
+}
+
+func (f *fakeServices) entity() *entities.Entity {
+	return &entities.Entity{Organizations: f, Ledgers: f, Accounts: f, Transactions: f}
+}
+
+func page[T any](items []T, opts *models.ListOptions) *models.ListResponse[T] {
+	start := min(opts.Offset, len(items))
+	end := min(start+opts.Limit, len(items))
+
+	return &models.ListResponse[T]{
+		Items:      items[start:end],
+		Pagination: models.Pagination{Limit: opts.Limit, Offset: start, Total: len(items)},
+	}
+}
+
+func (f *fakeServices) GetOrganization(_ context.Context, _ string) (*models.Organization, error) {
+	return &f.org, nil
+}
+
+func (f *fakeServices) UpdateOrganization(_ context.Context, id string, input *models.UpdateOrganizationInput) (*models.Organization, error) {
+	f.orgUpdates[id] = input.Metadata
+	return &f.org, nil
+}
+
+func (*fakeServices) ListLedgers(_ context.Context, _ string, opts *models.ListOptions) (*models.ListResponse[models.Ledger], error) {
+	return page([]models.Ledger{{ID: "ledger-1"}}, opts), nil
+}
+
+func (f *fakeServices) ListAccounts(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+	f.filters = append(f.filters, opts.Filters)
+	return page(f.accounts, opts), nil
+}
+
+func (f *fakeServices) UpdateAccount(_ context.Context, _, _, id string, input *models.UpdateAccountInput) (*models.Account, error) {
+	if id == f.failAccount {
+		return nil, errors.New("boom")
+	}
+
+	f.accountUpdates[id] = input
+
+	return &models.Account{ID: id}, nil
+}
+
+func (f *fakeServices) ListTransactions(_ context.Context, _, _ string, opts *models.ListOptions) (*models.ListResponse[models.Transaction], error) {
+	return page(f.transactions, opts), nil
+}
+
+func (f *fakeServices) UpdateTransaction(_ context.Context, _, _, id string, input any) (*models.Transaction, error) {
+	f.txUpdates[id] = input.(*models.UpdateTransactionInput).Metadata
+	return &models.Transaction{ID: id}, nil
+}
+
+var subject = Subject{Key: "customerId", Value: "c-1"}
+
+func TestScrub_RewritesMatchingEntities(t *testing.T) {
+	f := newFakeServices()
+
+	report, err := NewScrubber(f.entity()).
+		WithRules(Redact("email"), Remove("phone"), Hash("customerId", "salt")).
+		Scrub(context.Background(), "org-1", subject)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Matched)
+	assert.Equal(t, 2, report.Count(KindOrganization))
+	assert.Equal(t, 4, report.Count(KindAccount))
+	assert.Equal(t, 2, report.Count(KindTransaction))
+	assert.Empty(t, report.Failures)
+
+	for _, c := range report.Changes {
+		assert.True(t, c.Applied)
+	}
+
+	acc := f.accountUpdates["acc-1"]
+	require.NotNil(t, acc)
+	assert.Equal(t, "Checking", acc.Name, "the update keeps the account name")
+	assert.Equal(t, RedactedValue, acc.Metadata["email"])
+	assert.Contains(t, acc.Metadata, "phone")
+	assert.Nil(t, acc.Metadata["phone"])
+	assert.Equal(t, "gold", acc.Metadata["tier"], "keys without rules are preserved")
+	assert.Len(t, acc.Metadata["customerId"], 64)
+
+	// hashing is deterministic across entities of the same subject
+	assert.Equal(t, acc.Metadata["customerId"], f.accountUpdates["acc-3"].Metadata["customerId"])
+	assert.Equal(t, acc.Metadata["customerId"], f.txUpdates["tx-1"]["customerId"])
+
+	assert.NotContains(t, f.accountUpdates, "acc-2")
+	assert.NotContains(t, f.txUpdates, "tx-2")
+	assert.Equal(t, RedactedValue, f.orgUpdates["org-1"]["email"])
+
+	// the source metadata is not mutated
+	assert.Equal(t, "a@b.test", f.accounts[0].Metadata["email"])
+	assert.Equal(t, "c-1", f.filters[0]["metadata.customerId"])
+}
+
+func TestScrub_DryRun(t *testing.T) {
+	f := newFakeServices()
+
+	report, err := NewScrubber(f.entity()).
+		WithRules(Redact("email")).
+		WithDryRun(true).
+		Scrub(context.Background(), "org-1", subject)
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	assert.Len(t, report.Changes, 3)
+
+	for _, c := range report.Changes {
+		assert.False(t, c.Applied)
+	}
+
+	assert.Empty(t, f.orgUpdates)
+	assert.Empty(t, f.accountUpdates)
+	assert.Empty(t, f.txUpdates)
+}
+
+func TestScrub_RecordsFailures(t *testing.T) {
+	f := newFakeServices()
+	f.failAccount = "acc-1"
+
+	report, err := NewScrubber(f.entity()).
+		WithRules(Replace("email", "erased@example.com")).
+		WithLedgers("ledger-1").
+		Scrub(context.Background(), "org-1", subject)
+	require.NoError(t, err)
+
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, "acc-1", report.Failures[0].ID)
+	assert.Equal(t, "boom", report.Failures[0].Error)
+
+	for _, c := range report.Changes {
+		assert.Equal(t, c.ID != "acc-1", c.Applied, c.ID)
+	}
+
+	assert.Equal(t, "erased@example.com", f.txUpdates["tx-1"]["email"])
+}
+
+func TestScrub_Validation(t *testing.T) {
+	f := newFakeServices()
+	ctx := context.Background()
+
+	_, err := NewScrubber(nil).WithRules(Redact("email")).Scrub(ctx, "org-1", subject)
+	assert.Error(t, err)
+
+	_, err = NewScrubber(f.entity()).Scrub(ctx, "org-1", subject)
+	assert.ErrorContains(t, err, "at least one rule")
+
+	_, err = NewScrubber(f.entity()).WithRules(Redact("email")).Scrub(ctx, "org-1", Subject{Key: "customerId"})
+	assert.ErrorContains(t, err, "subject")
+
+	_, err = NewScrubber(f.entity()).WithRules(Rule{Key: "email", Action: "shred"}).Scrub(ctx, "org-1", subject)
+	assert.ErrorContains(t, err, "unknown action")
+}
@@ -0,0 +1,170 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bar rendering defaults.
+const (
+	// DefaultBarWidth is the number of cells of the bar
+	DefaultBarWidth = 30
+
+	// DefaultRefreshInterval is the minimum delay between two redraws on a terminal
+	DefaultRefreshInterval = 100 * time.Millisecond
+
+	// DefaultLogStep is the share of the total, in percent, between two lines off a terminal
+	DefaultLogStep = 10
+)
+
+// Bar shows the progress of a known number of items, such as the
+// transactions of a batch, with the rate and estimated time left. It is safe
+// for concurrent use, so it can be fed from transaction.BatchOptions.OnProgress.
+type Bar struct {
+	mu       sync.Mutex
+	w        io.Writer
+	label    string
+	total    int
+	current  int
+	width    int
+	terminal bool
+	started  time.Time
+	drawn    time.Time
+	logged   int
+	finished bool
+	now      func() time.Time
+}
+
+// NewBar returns a bar of total items writing to w.
+func NewBar(w io.Writer, total int, label string) *Bar {
+	return &Bar{w: w, label: label, total: max(total, 0), width: DefaultBarWidth, terminal: IsTerminal(w), logged: -1, now: time.Now}
+}
+
+// WithWidth sets the number of cells of the bar.
+func (b *Bar) WithWidth(width int) *Bar {
+	if width > 0 {
+		b.width = width
+	}
+
+	return b
+}
+
+// WithTerminal overrides the terminal detection: with true the bar is redrawn
+// in place, with false a line is printed every DefaultLogStep percent.
+func (b *Bar) WithTerminal(terminal bool) *Bar {
+	b.terminal = terminal
+	return b
+}
+
+// Add advances the bar by n items.
+func (b *Bar) Add(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.set(b.current + n)
+}
+
+// Set sets the number of items done. Batch callbacks may report completions
+// out of order, so a lower count than the current one is ignored.
+func (b *Bar) Set(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n > b.current {
+		b.set(n)
+	}
+}
+
+// set updates the count and redraws when due; b.mu must be held.
+func (b *Bar) set(n int) {
+	if b.finished {
+		return
+	}
+
+	now := b.now()
+	if b.started.IsZero() {
+		b.started = now
+	}
+
+	b.current = min(max(n, 0), b.total)
+
+	if b.terminal {
+		if b.current == b.total || now.Sub(b.drawn) >= DefaultRefreshInterval {
+			b.drawn = now
+			fmt.Fprint(b.w, clearLine+b.render(now))
+		}
+
+		return
+	}
+
+	if b.total == 0 {
+		return
+	}
+
+	step := b.current * 100 / b.total / DefaultLogStep
+	if step > b.logged {
+		b.logged = step
+		fmt.Fprintln(b.w, b.render(now))
+	}
+}
+
+// Finish draws the final state of the bar and ends its line. It returns the
+// time elapsed since the first update.
+func (b *Bar) Finish() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.finished {
+		return 0
+	}
+
+	b.finished = true
+
+	now := b.now()
+	if b.started.IsZero() {
+		b.started = now
+	}
+
+	if b.terminal {
+		fmt.Fprintln(b.w, clearLine+b.render(now))
+	} else if b.current*100/max(b.total, 1)/DefaultLogStep > b.logged || b.total == 0 {
+		fmt.Fprintln(b.w, b.render(now))
+	}
+
+	return now.Sub(b.started)
+}
+
+// render formats the bar line; b.mu must be held.
+func (b *Bar) render(now time.Time) string {
+	ratio := 1.0
+	if b.total > 0 {
+		ratio = float64(b.current) / float64(b.total)
+	}
+
+	filled := int(ratio * float64(b.width))
+
+	var cells string
+
+	switch {
+	case filled >= b.width:
+		cells = strings.Repeat("=", b.width)
+	default:
+		cells = strings.Repeat("=", filled) + ">" + strings.Repeat(" ", b.width-filled-1)
+	}
+
+	line := fmt.Sprintf("%s [%s] %d/%d %3.0f%%", b.label, cells, b.current, b.total, ratio*100)
+
+	elapsed := now.Sub(b.started)
+	line += " " + formatDuration(elapsed)
+
+	if b.current > 0 && b.current < b.total && elapsed > 0 {
+		rate := float64(b.current) / elapsed.Seconds()
+		left := time.Duration(float64(b.total-b.current) / rate * float64(time.Second))
+		line += fmt.Sprintf(" %.1f/s eta %s", rate, formatDuration(left))
+	}
+
+	return line
+}
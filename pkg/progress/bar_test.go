@@ -0,0 +1,57 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBarLines(t *testing.T) {
+	var buf bytes.Buffer
+
+	bar := NewBar(&buf, 20, "Transactions").WithWidth(10)
+	bar.now = fakeClock(time.Second)
+
+	for range 20 {
+		bar.Add(1)
+	}
+
+	// Completions reported out of order don't move the bar back
+	bar.Set(5)
+	bar.Finish()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 11, "one line every 10%%, from 0 to 100%%")
+
+	assert.Equal(t, "Transactions [=>        ] 2/20  10% 1s 2.0/s eta 9s", lines[1])
+	assert.Equal(t, "Transactions [==========] 20/20 100% 19s", lines[10])
+}
+
+func TestBarTerminal(t *testing.T) {
+	var buf bytes.Buffer
+
+	bar := NewBar(&buf, 4, "Accounts").WithWidth(4).WithTerminal(true)
+	bar.now = fakeClock(50 * time.Millisecond)
+
+	bar.Add(1)
+	bar.Add(1) // within the refresh interval, not drawn
+	bar.Add(2)
+	assert.Equal(t, 150*time.Millisecond, bar.Finish())
+
+	assert.Equal(t, clearLine+"Accounts [=>  ] 1/4  25% 0s"+
+		clearLine+"Accounts [====] 4/4 100% 100ms"+
+		clearLine+"Accounts [====] 4/4 100% 150ms\n", buf.String())
+}
+
+func TestBarEmpty(t *testing.T) {
+	var buf bytes.Buffer
+
+	bar := NewBar(&buf, 0, "Nothing")
+	bar.now = fakeClock(time.Second)
+	bar.Finish()
+
+	assert.Equal(t, "Nothing [==============================] 0/0 100% 0s\n", buf.String())
+}
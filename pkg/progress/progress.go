@@ -0,0 +1,66 @@
+// Package progress provides small terminal helpers for command-line tools
+// built on the SDK: a spinner for work of unknown length, a progress bar for
+// batches and a step timer recording how long each stage of a run took.
+//
+// On a terminal the spinner and the bar redraw a single line; on any other
+// writer, such as a CI log or a file, they print plain lines at a low rate
+// instead, so output stays readable when redirected.
+//
+// Example:
+//
+//	steps := progress.NewSteps(os.Stdout)
+//
+//	err := steps.Run("setup", func() error {
+//	    spinner := progress.NewSpinner(os.Stdout, "Creating organization").Start()
+//	    org, err := client.Entity.Organizations.CreateOrganization(ctx, input)
+//	    if err != nil {
+//	        spinner.Fail("Organization not created")
+//	        return err
+//	    }
+//	    spinner.Stop("Created organization " + org.ID)
+//	    return nil
+//	})
+//
+//	bar := progress.NewBar(os.Stdout, len(inputs), "Transactions")
+//	options.OnProgress = func(completed, _ int, _ transaction.BatchResult) { bar.Set(completed) }
+//	results, err := transaction.BatchTransactions(ctx, client, orgID, ledgerID, inputs, options)
+//	bar.Finish()
+//
+//	steps.WriteSummary(os.Stdout)
+package progress
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// clearLine moves the cursor to the start of the line and erases it.
+const clearLine = "\r\033[K"
+
+// IsTerminal reports whether w is a terminal, on which output can be redrawn.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// formatDuration rounds d for display.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	default:
+		return d.Round(time.Second).String()
+	}
+}
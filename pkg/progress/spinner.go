@@ -0,0 +1,165 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+)
+
+// spinnerFrames are the frames drawn in turn by a spinner.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// DefaultSpinnerInterval is the delay between two frames of a spinner.
+const DefaultSpinnerInterval = 100 * time.Millisecond
+
+// Spinner shows that work of unknown length is under way, with its message
+// and elapsed time. It is safe for concurrent use.
+type Spinner struct {
+	mu       sync.Mutex
+	w        io.Writer
+	message  string
+	terminal bool
+	interval time.Duration
+	frame    int
+	started  time.Time
+	done     chan struct{}
+	stopped  chan struct{}
+	now      func() time.Time
+}
+
+// NewSpinner returns a spinner writing to w. It draws nothing until started.
+func NewSpinner(w io.Writer, message string) *Spinner {
+	return &Spinner{w: w, message: message, terminal: IsTerminal(w), interval: DefaultSpinnerInterval, now: time.Now}
+}
+
+// WithInterval sets the delay between two frames.
+func (s *Spinner) WithInterval(d time.Duration) *Spinner {
+	if d > 0 {
+		s.interval = d
+	}
+
+	return s
+}
+
+// WithTerminal overrides the terminal detection: with true the spinner is
+// redrawn in place, with false its messages are printed as lines.
+func (s *Spinner) WithTerminal(terminal bool) *Spinner {
+	s.terminal = terminal
+	return s
+}
+
+// Start starts the spinner. Off a terminal it prints the message once.
+func (s *Spinner) Start() *Spinner {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done != nil {
+		return s
+	}
+
+	s.started = s.now()
+	s.done = make(chan struct{})
+	s.stopped = make(chan struct{})
+
+	if !s.terminal {
+		fmt.Fprintf(s.w, "%s...\n", s.message)
+		close(s.stopped)
+
+		return s
+	}
+
+	s.draw()
+
+	go s.spin(lifecycle.Track("progress.Spinner"))
+
+	return s
+}
+
+func (s *Spinner) spin(done func()) {
+	defer close(s.stopped)
+	defer done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.frame = (s.frame + 1) % len(spinnerFrames)
+			s.draw()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// draw redraws the spinner line; s.mu must be held.
+func (s *Spinner) draw() {
+	fmt.Fprintf(s.w, "%s%s %s (%s)", clearLine, spinnerFrames[s.frame], s.message, formatDuration(s.now().Sub(s.started)))
+}
+
+// Update replaces the message of the spinner.
+func (s *Spinner) Update(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.message = message
+
+	if s.terminal && s.done != nil {
+		s.draw()
+	}
+}
+
+// Stop stops the spinner and prints message as a success, with the elapsed
+// time. It returns the elapsed time.
+func (s *Spinner) Stop(message string) time.Duration {
+	return s.finish("✓", message)
+}
+
+// Fail stops the spinner and prints message as a failure, with the elapsed
+// time. It returns the elapsed time.
+func (s *Spinner) Fail(message string) time.Duration {
+	return s.finish("✗", message)
+}
+
+func (s *Spinner) finish(mark, message string) time.Duration {
+	s.mu.Lock()
+
+	if s.done == nil {
+		s.started = s.now()
+		s.done = make(chan struct{})
+		s.stopped = make(chan struct{})
+		close(s.stopped)
+	}
+
+	select {
+	case <-s.done:
+		// already stopped
+		s.mu.Unlock()
+		return 0
+	default:
+		close(s.done)
+	}
+
+	s.mu.Unlock()
+	<-s.stopped
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elapsed := s.now().Sub(s.started)
+
+	prefix := ""
+	if s.terminal {
+		prefix = clearLine
+	}
+
+	fmt.Fprintf(s.w, "%s%s %s (%s)\n", prefix, mark, message, formatDuration(elapsed))
+
+	return elapsed
+}
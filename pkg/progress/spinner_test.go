@@ -0,0 +1,67 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer is a bytes.Buffer safe for the spinner goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestSpinnerLines(t *testing.T) {
+	var buf bytes.Buffer
+
+	spinner := NewSpinner(&buf, "Creating accounts")
+	spinner.now = fakeClock(250 * time.Millisecond)
+
+	spinner.Start()
+	spinner.Update("Creating accounts: 10 of 20")
+	assert.Equal(t, 250*time.Millisecond, spinner.Stop("Created 20 accounts"))
+
+	// Stopping again does nothing
+	assert.Zero(t, spinner.Fail("ignored"))
+
+	assert.Equal(t, "Creating accounts...\n✓ Created 20 accounts (250ms)\n", buf.String())
+}
+
+func TestSpinnerTerminal(t *testing.T) {
+	var buf syncBuffer
+
+	tasks := lifecycle.Count("progress.Spinner")
+	spinner := NewSpinner(&buf, "Waiting").WithTerminal(true).WithInterval(time.Millisecond).Start()
+
+	assert.Eventually(t, func() bool { return strings.Contains(buf.String(), spinnerFrames[1]) }, time.Second, time.Millisecond)
+
+	assert.Equal(t, tasks+1, lifecycle.Count("progress.Spinner"))
+
+	spinner.Fail("Gave up")
+	assert.Equal(t, tasks, lifecycle.Count("progress.Spinner"), "Fail ends the spinner goroutine")
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, clearLine+spinnerFrames[0]+" Waiting"))
+	assert.Contains(t, out, clearLine+"✗ Gave up (")
+	assert.True(t, strings.HasSuffix(out, ")\n"))
+}
@@ -0,0 +1,157 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Step is a timed stage of a run.
+type Step struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Err      error         `json:"-"`
+}
+
+// Steps records the duration of the stages of a run. It is safe for
+// concurrent use, so stages running in parallel can record themselves.
+type Steps struct {
+	mu    sync.Mutex
+	w     io.Writer
+	steps []Step
+	index map[string]int
+	now   func() time.Time
+}
+
+// NewSteps returns a step timer announcing each step run on w. With a nil
+// writer steps are only recorded.
+func NewSteps(w io.Writer) *Steps {
+	return &Steps{w: w, index: make(map[string]int), now: time.Now}
+}
+
+// Run runs fn as the named step, prints its outcome and records its duration.
+// It returns the error of fn.
+func (s *Steps) Run(name string, fn func() error) error {
+	s.printf("→ %s\n", name)
+
+	start := s.now()
+	err := fn()
+	d := s.now().Sub(start)
+
+	s.record(Step{Name: name, Duration: d, Err: err})
+
+	if err != nil {
+		s.printf("✗ %s failed after %s: %v\n", name, formatDuration(d), err)
+	} else {
+		s.printf("✓ %s (%s)\n", name, formatDuration(d))
+	}
+
+	return err
+}
+
+// Start starts timing the named step and returns the function ending it,
+// which records the step with the given error and returns its duration. Use
+// it for steps that don't fit in a function:
+//
+//	done := steps.Start("ledger_" + ledgerID + "_accounts")
+//	// ...
+//	done(nil)
+func (s *Steps) Start(name string) func(err error) time.Duration {
+	start := s.now()
+
+	return func(err error) time.Duration {
+		d := s.now().Sub(start)
+		s.record(Step{Name: name, Duration: d, Err: err})
+
+		return d
+	}
+}
+
+// Record records the duration of a step timed elsewhere. Recording a step
+// again replaces its duration and keeps its position.
+func (s *Steps) Record(name string, d time.Duration) {
+	s.record(Step{Name: name, Duration: d})
+}
+
+func (s *Steps) record(step Step) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i, ok := s.index[step.Name]; ok {
+		s.steps[i] = step
+		return
+	}
+
+	s.index[step.Name] = len(s.steps)
+	s.steps = append(s.steps, step)
+}
+
+// Steps returns the recorded steps in the order they were first recorded.
+func (s *Steps) Steps() []Step {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Step(nil), s.steps...)
+}
+
+// Timings returns the duration of each step as a string, the form used by
+// transaction.Report.StepTimings.
+func (s *Steps) Timings() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	timings := make(map[string]string, len(s.steps))
+	for _, step := range s.steps {
+		timings[step.Name] = step.Duration.String()
+	}
+
+	return timings
+}
+
+// Total returns the sum of the step durations. Steps run in parallel are all
+// counted.
+func (s *Steps) Total() time.Duration {
+	var total time.Duration
+	for _, step := range s.Steps() {
+		total += step.Duration
+	}
+
+	return total
+}
+
+// WriteSummary writes a table of the steps, their durations and share of the
+// total, followed by the total.
+func (s *Steps) WriteSummary(w io.Writer) error {
+	steps := s.Steps()
+	total := s.Total()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "step\tduration\tshare")
+
+	for _, step := range steps {
+		share := 0.0
+		if total > 0 {
+			share = float64(step.Duration) / float64(total) * 100
+		}
+
+		status := ""
+		if step.Err != nil {
+			status = " (failed)"
+		}
+
+		fmt.Fprintf(tw, "%s%s\t%s\t%.1f%%\n", step.Name, status, formatDuration(step.Duration), share)
+	}
+
+	fmt.Fprintf(tw, "total\t%s\n", formatDuration(total))
+
+	return tw.Flush()
+}
+
+func (s *Steps) printf(format string, args ...any) {
+	if s.w != nil {
+		fmt.Fprintf(s.w, format, args...)
+	}
+}
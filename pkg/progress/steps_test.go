@@ -0,0 +1,72 @@
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock returns a clock advancing by step on each reading.
+func fakeClock(step time.Duration) func() time.Time {
+	var (
+		mu  sync.Mutex
+		now = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	return func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now = now.Add(step)
+
+		return now
+	}
+}
+
+func TestSteps(t *testing.T) {
+	var buf bytes.Buffer
+
+	steps := NewSteps(&buf)
+	steps.now = fakeClock(time.Second)
+
+	require.NoError(t, steps.Run("setup", func() error { return nil }))
+	require.EqualError(t, steps.Run("accounts", func() error { return errors.New("boom") }), "boom")
+
+	done := steps.Start("batch")
+	assert.Equal(t, time.Second, done(nil))
+
+	steps.Record("report", 500*time.Millisecond)
+	steps.Record("setup", 2*time.Second)
+
+	assert.Equal(t, "→ setup\n✓ setup (1s)\n→ accounts\n✗ accounts failed after 1s: boom\n", buf.String())
+
+	recorded := steps.Steps()
+	require.Len(t, recorded, 4)
+	assert.Equal(t, "setup", recorded[0].Name)
+	assert.Error(t, recorded[1].Err)
+
+	assert.Equal(t, map[string]string{"setup": "2s", "accounts": "1s", "batch": "1s", "report": "500ms"}, steps.Timings())
+	assert.Equal(t, 4500*time.Millisecond, steps.Total())
+
+	buf.Reset()
+	require.NoError(t, steps.WriteSummary(&buf))
+
+	assert.Equal(t, "step               duration  share\n"+
+		"setup              2s        44.4%\n"+
+		"accounts (failed)  1s        22.2%\n"+
+		"batch              1s        22.2%\n"+
+		"report             500ms     11.1%\n"+
+		"total              4.5s\n", buf.String())
+}
+
+func TestStepsWithoutWriter(t *testing.T) {
+	steps := NewSteps(nil)
+
+	require.NoError(t, steps.Run("quiet", func() error { return nil }))
+	assert.Len(t, steps.Steps(), 1)
+}
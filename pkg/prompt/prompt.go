@@ -0,0 +1,336 @@
+// Package prompt asks the questions of interactive command-line tools built on
+// the SDK, with defaults, validation and a non-interactive mode for scripts
+// and CI.
+//
+// The default of each question comes, in order, from an environment variable,
+// from a defaults file and from the code. When the tool is not run
+// interactively, the questions are not asked and their defaults are used.
+//
+// Example:
+//
+//	defaults, err := prompt.LoadDefaults("defaults.json")
+//	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+//	    log.Fatal(err)
+//	}
+//
+//	p := prompt.Stdio().WithEnvPrefix("DEMO_").WithDefaults(defaults)
+//
+//	orgs := p.Int("ORGS", "Organizations to create", 2, prompt.IntRange(1, 100))
+//	locale := p.Choice("LOCALE", "Organization locale", "us", "us", "br")
+//	if p.Bool("RUN_BATCH", "Run the transaction batch?", true) {
+//	    // ...
+//	}
+//
+//	if err := p.Err(); err != nil {
+//	    log.Fatalf("invalid configuration: %v", err)
+//	}
+package prompt
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultNonInteractiveEnv is the environment variable which, when set to a
+// true value, disables the questions.
+const DefaultNonInteractiveEnv = "MIDAZ_NON_INTERACTIVE"
+
+// Validator checks an answer. Its error is shown to the user, who is asked
+// again.
+type Validator[T any] func(T) error
+
+// Prompter asks questions on a reader and a writer.
+type Prompter struct {
+	in          *bufio.Reader
+	out         io.Writer
+	interactive bool
+	envPrefix   string
+	defaults    map[string]string
+	errs        []error
+}
+
+// New returns a prompter reading the answers from in and writing the
+// questions to out. It is interactive when in is a terminal and neither
+// DefaultNonInteractiveEnv nor CI is set to a true value.
+func New(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{
+		in:          bufio.NewReader(in),
+		out:         out,
+		interactive: IsTerminal(in) && !envTrue(DefaultNonInteractiveEnv) && !envTrue("CI"),
+	}
+}
+
+// Stdio returns a prompter on the standard input and output.
+func Stdio() *Prompter {
+	return New(os.Stdin, os.Stdout)
+}
+
+// WithInteractive overrides the detection of the interactive mode, for
+// instance from a command-line flag.
+func (p *Prompter) WithInteractive(interactive bool) *Prompter {
+	p.interactive = interactive
+	return p
+}
+
+// WithNonInteractiveEnv disables the questions when the named environment
+// variable is set to a true value, in addition to DefaultNonInteractiveEnv.
+func (p *Prompter) WithNonInteractiveEnv(name string) *Prompter {
+	if envTrue(name) {
+		p.interactive = false
+	}
+
+	return p
+}
+
+// WithEnvPrefix sets the prefix of the environment variables holding the
+// defaults: with prefix "DEMO_", the default of key "ORGS" is read from
+// DEMO_ORGS.
+func (p *Prompter) WithEnvPrefix(prefix string) *Prompter {
+	p.envPrefix = prefix
+	return p
+}
+
+// WithDefaults sets the defaults by key, such as the ones of LoadDefaults.
+// Environment variables take precedence over them.
+func (p *Prompter) WithDefaults(defaults map[string]string) *Prompter {
+	p.defaults = defaults
+	return p
+}
+
+// Interactive reports whether the questions are asked.
+func (p *Prompter) Interactive() bool {
+	return p.interactive
+}
+
+// Err returns the problems met so far: invalid defaults from the environment
+// or the defaults file, and defaults failing validation in non-interactive
+// mode, where nobody can fix them.
+func (p *Prompter) Err() error {
+	return errors.Join(p.errs...)
+}
+
+// String asks a question answered by text. Key names the environment
+// variable and defaults entry overriding def, and can be empty.
+func (p *Prompter) String(key, question, def string, validators ...Validator[string]) string {
+	return ask(p, key, question, def, formatString, parseString, validators)
+}
+
+// Int asks a question answered by an integer.
+func (p *Prompter) Int(key, question string, def int, validators ...Validator[int]) int {
+	return ask(p, key, question, def, strconv.Itoa, strconv.Atoi, validators)
+}
+
+// Float asks a question answered by a number.
+func (p *Prompter) Float(key, question string, def float64, validators ...Validator[float64]) float64 {
+	return ask(p, key, question, def, formatFloat, parseFloat, validators)
+}
+
+// Bool asks a yes or no question.
+func (p *Prompter) Bool(key, question string, def bool) bool {
+	return ask(p, key, question, def, formatBool, parseBool, nil)
+}
+
+// Choice asks a question answered by one of choices, compared without case.
+// The answer is returned as spelled in choices.
+func (p *Prompter) Choice(key, question, def string, choices ...string) string {
+	parse := func(s string) (string, error) {
+		for _, c := range choices {
+			if strings.EqualFold(s, c) {
+				return c, nil
+			}
+		}
+
+		return "", fmt.Errorf("%q is not one of %s", s, strings.Join(choices, ", "))
+	}
+
+	return ask(p, key, fmt.Sprintf("%s (%s)", question, strings.Join(choices, "|")), def, formatString, parse,
+		[]Validator[string]{OneOf(choices...)})
+}
+
+// ask resolves the default of a question, then asks it until the answer
+// parses and is valid. An unreadable input ends the questions with the
+// default.
+func ask[T any](p *Prompter, key, question string, def T, format func(T) string, parse func(string) (T, error), validators []Validator[T]) T {
+	def = resolve(p, key, def, parse)
+
+	if !p.interactive {
+		if err := validate(def, validators); err != nil {
+			p.errs = append(p.errs, fmt.Errorf("%s: %w", label(key, question), err))
+		}
+
+		return def
+	}
+
+	for {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, format(def))
+
+		line, err := p.in.ReadString('\n')
+		if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+			// Nothing more to read: keep the defaults from now on
+			p.interactive = false
+			fmt.Fprintln(p.out)
+
+			return def
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return def
+		}
+
+		value, err := parse(line)
+		if err == nil {
+			err = validate(value, validators)
+		}
+
+		if err == nil {
+			return value
+		}
+
+		fmt.Fprintf(p.out, "Invalid answer: %v\n", err)
+	}
+}
+
+// resolve returns the default of key from the environment, then from the
+// defaults, then def. Values that don't parse are recorded and skipped.
+func resolve[T any](p *Prompter, key string, def T, parse func(string) (T, error)) T {
+	if key == "" {
+		return def
+	}
+
+	env := p.envPrefix + key
+	if v := strings.TrimSpace(os.Getenv(env)); v != "" {
+		value, err := parse(v)
+		if err == nil {
+			return value
+		}
+
+		p.errs = append(p.errs, fmt.Errorf("environment variable %s: %w", env, err))
+	}
+
+	if v := strings.TrimSpace(p.defaults[key]); v != "" {
+		value, err := parse(v)
+		if err == nil {
+			return value
+		}
+
+		p.errs = append(p.errs, fmt.Errorf("default %s: %w", key, err))
+	}
+
+	return def
+}
+
+func validate[T any](value T, validators []Validator[T]) error {
+	for _, v := range validators {
+		if err := v(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// label names a question in errors.
+func label(key, question string) string {
+	if key != "" {
+		return key
+	}
+
+	return question
+}
+
+func formatString(s string) string { return s }
+
+func parseString(s string) (string, error) { return s, nil }
+
+func formatFloat(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+func parseFloat(s string) (float64, error) { return strconv.ParseFloat(s, 64) }
+
+func formatBool(b bool) string {
+	if b {
+		return "Y/n"
+	}
+
+	return "y/N"
+}
+
+// parseBool accepts y, yes, n and no besides the forms of strconv.ParseBool.
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "y", "yes":
+		return true, nil
+	case "n", "no":
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("%q is not yes or no", s)
+	}
+
+	return b, nil
+}
+
+// envTrue reports whether the named environment variable holds a true value.
+func envTrue(name string) bool {
+	b, err := parseBool(strings.TrimSpace(os.Getenv(name)))
+	return err == nil && b
+}
+
+// IsTerminal reports whether r is a terminal.
+func IsTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// LoadDefaults reads the defaults of the questions from a JSON object of
+// scalar values by key, such as {"ORGS": 2, "LOCALE": "br"}.
+func LoadDefaults(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path chosen by the operator
+	if err != nil {
+		return nil, fmt.Errorf("failed to read defaults: %w", err)
+	}
+
+	return ParseDefaults(data)
+}
+
+// ParseDefaults parses defaults in the format of LoadDefaults.
+func ParseDefaults(data []byte) (map[string]string, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid defaults: %w", err)
+	}
+
+	defaults := make(map[string]string, len(raw))
+
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			defaults[key] = v
+		case float64:
+			defaults[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			defaults[key] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("invalid defaults: %s must be a string, a number or a boolean", key)
+		}
+	}
+
+	return defaults, nil
+}
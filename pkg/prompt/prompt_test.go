@@ -0,0 +1,92 @@
+package prompt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func interactive(input string) (*Prompter, *bytes.Buffer) {
+	var out bytes.Buffer
+	return New(strings.NewReader(input), &out).WithInteractive(true), &out
+}
+
+func TestAsk(t *testing.T) {
+	p, out := interactive("\nabc\n150\n42\nmaybe\nyes\nBR\n")
+
+	assert.Equal(t, "USD", p.String("", "Asset code", "USD"))
+	assert.Equal(t, 42, p.Int("", "Organizations", 2, IntRange(1, 100)))
+	assert.True(t, p.Bool("", "Run batch?", false))
+	assert.Equal(t, "br", p.Choice("", "Locale", "us", "us", "br"))
+	require.NoError(t, p.Err())
+
+	assert.Equal(t, "Asset code [USD]: "+
+		"Organizations [2]: Invalid answer: strconv.Atoi: parsing \"abc\": invalid syntax\n"+
+		"Organizations [2]: Invalid answer: 150 is not between 1 and 100\n"+
+		"Organizations [2]: "+
+		"Run batch? [y/N]: Invalid answer: \"maybe\" is not yes or no\n"+
+		"Run batch? [y/N]: "+
+		"Locale (us|br) [us]: ", out.String())
+}
+
+func TestAskEndOfInput(t *testing.T) {
+	p, _ := interactive("7")
+
+	// A last line without a newline is still an answer
+	assert.Equal(t, 7, p.Int("", "Accounts", 50))
+
+	// Then the defaults are used
+	assert.Equal(t, 20, p.Int("", "Transactions", 20))
+	assert.False(t, p.Interactive())
+}
+
+func TestDefaults(t *testing.T) {
+	t.Setenv("DEMO_ORGS", "5")
+	t.Setenv("DEMO_BATCH", "many")
+
+	p, out := interactive("")
+	p.WithEnvPrefix("DEMO_").WithDefaults(map[string]string{"ORGS": "3", "BATCH": "25", "LOCALE": "BR", "RATE": "0.5"}).
+		WithInteractive(false)
+
+	assert.Equal(t, 5, p.Int("ORGS", "Organizations", 2), "the environment comes first")
+	assert.Equal(t, 25, p.Int("BATCH", "Batch size", 50), "then the defaults file")
+	assert.Equal(t, "br", p.Choice("LOCALE", "Locale", "us", "us", "br"))
+	assert.InDelta(t, 0.5, p.Float("RATE", "Rate", 0, FloatRange(0, 1)), 0)
+	assert.Equal(t, "", p.String("NAME", "Name", "", NotEmpty))
+	assert.Empty(t, out.String(), "nothing is asked")
+
+	err := p.Err()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "environment variable DEMO_BATCH")
+	assert.Contains(t, err.Error(), "NAME: an answer is required")
+}
+
+func TestNonInteractiveDetection(t *testing.T) {
+	// Readers other than terminals are not interactive
+	assert.False(t, New(strings.NewReader("y\n"), &bytes.Buffer{}).Interactive())
+
+	t.Setenv("DEMO_NON_INTERACTIVE", "1")
+
+	p, _ := interactive("y\n")
+	assert.False(t, p.WithNonInteractiveEnv("DEMO_NON_INTERACTIVE").Interactive())
+}
+
+func TestLoadDefaults(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "defaults.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"ORGS": 2, "RUN_BATCH": true, "LOCALE": "br", "RATE": 0.25}`), 0o600))
+
+	defaults, err := LoadDefaults(file)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ORGS": "2", "RUN_BATCH": "true", "LOCALE": "br", "RATE": "0.25"}, defaults)
+
+	_, err = ParseDefaults([]byte(`{"ORGS": [1]}`))
+	require.Error(t, err)
+
+	_, err = LoadDefaults(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,51 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NotEmpty rejects blank answers. Use it on questions without a default.
+func NotEmpty(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return errors.New("an answer is required")
+	}
+
+	return nil
+}
+
+// OneOf accepts the given values, compared without case.
+func OneOf(values ...string) Validator[string] {
+	return func(s string) error {
+		for _, v := range values {
+			if strings.EqualFold(s, v) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%q is not one of %s", s, strings.Join(values, ", "))
+	}
+}
+
+// IntRange accepts integers between lo and hi, inclusive.
+func IntRange(lo, hi int) Validator[int] {
+	return func(n int) error {
+		if n < lo || n > hi {
+			return fmt.Errorf("%d is not between %d and %d", n, lo, hi)
+		}
+
+		return nil
+	}
+}
+
+// FloatRange accepts numbers between lo and hi, inclusive.
+func FloatRange(lo, hi float64) Validator[float64] {
+	return func(f float64) error {
+		if f < lo || f > hi {
+			return fmt.Errorf("%v is not between %v and %v", f, lo, hi)
+		}
+
+		return nil
+	}
+}
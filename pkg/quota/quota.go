@@ -0,0 +1,462 @@
+// Package quota partitions the capacity of a shared Midaz deployment between
+// the tenants of a platform, so that one tenant can't exhaust it for the
+// others.
+//
+// An Enforcer given to the client with client.WithTenantQuotas checks every
+// call against the Budget of its tenant before any request is sent: the calls
+// per second, and the transactions created per day. A call over budget fails
+// with an *ExceededError, matched by errors.Is with ErrQuotaExceeded:
+//
+//	enforcer := quota.NewEnforcer().
+//	    WithBudget("tenant-a", quota.Budget{MaxTPS: 50, MaxDailyTransactions: 100000}).
+//	    WithDefaultBudget(quota.Budget{MaxTPS: 10, MaxDailyTransactions: 10000}).
+//	    WithObservability(provider)
+//
+//	c, err := client.New(client.UseEntityAPI(), client.WithTenantQuotas(enforcer))
+//	...
+//	_, err = c.Entity.Transactions.CreateTransaction(entities.WithTenantID(ctx, "tenant-a"), orgID, ledgerID, input)
+//	var exceeded *quota.ExceededError
+//	if errors.As(err, &exceeded) {
+//	    log.Printf("tenant %s over its %s quota, retry in %s", exceeded.Tenant, exceeded.Kind, exceeded.RetryAfter)
+//	}
+//
+// The tenant of a call is its X-Tenant-ID header, set with entities.WithTenantID
+// or the client default. Budgets are enforced by this process only; processes
+// sharing a deployment each need their share of the capacity.
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/observability"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/usage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Metric names and attribute keys of the quota metrics.
+const (
+	MetricAdmitted = "midaz.sdk.quota.admitted"
+	MetricRejected = "midaz.sdk.quota.rejected"
+
+	KeyTenant = "midaz.quota.tenant"
+	KeyKind   = "midaz.quota.kind"
+)
+
+// Budget is the share of the capacity of a tenant. Zero fields are unbounded.
+type Budget struct {
+	// MaxTPS bounds the calls per second of the tenant, all operations alike
+	MaxTPS float64
+
+	// Burst is the number of calls allowed at once over MaxTPS
+	// Default is MaxTPS rounded up
+	Burst int
+
+	// MaxDailyTransactions bounds the transactions created per day by the
+	// tenant, days starting at midnight in the location of the Enforcer
+	MaxDailyTransactions int
+
+	// Wait makes the calls over MaxTPS wait for their turn instead of failing,
+	// when it comes before the deadline of their context
+	Wait bool
+}
+
+// burst returns the capacity of the token bucket of the budget.
+func (b Budget) burst() float64 {
+	if b.Burst > 0 {
+		return float64(b.Burst)
+	}
+
+	return math.Max(1, math.Ceil(b.MaxTPS))
+}
+
+// Kind is the budget a call exceeded.
+type Kind string
+
+// Budget kinds.
+const (
+	KindTPS               Kind = "tps"
+	KindDailyTransactions Kind = "daily_transactions"
+)
+
+// ErrQuotaExceeded is the sentinel matched by errors.Is for an ExceededError.
+var ErrQuotaExceeded = errors.New("tenant quota exceeded")
+
+// ExceededError is returned for a call over the budget of its tenant. The
+// call is rejected before any request is sent.
+type ExceededError struct {
+	Tenant string
+	Kind   Kind
+
+	// Limit is the bound exceeded, in calls per second or transactions per day
+	Limit float64
+
+	// RetryAfter is when the budget allows the call again
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *ExceededError) Error() string {
+	tenant := e.Tenant
+	if tenant == "" {
+		tenant = "(none)"
+	}
+
+	return fmt.Sprintf("%s: tenant %s over %s limit of %g, retry after %s", ErrQuotaExceeded, tenant, e.Kind, e.Limit, e.RetryAfter.Round(time.Millisecond))
+}
+
+// Is reports whether target is ErrQuotaExceeded.
+func (*ExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// Usage is the use of the budget of a tenant.
+type Usage struct {
+	Tenant string `json:"tenant"`
+	Budget Budget `json:"budget"`
+
+	// Transactions counts the transactions created today
+	Transactions int `json:"transactions"`
+
+	// Admitted and Rejected count the calls since the Enforcer was created,
+	// Rejected by kind
+	Admitted int64          `json:"admitted"`
+	Rejected map[Kind]int64 `json:"rejected,omitempty"`
+}
+
+// tenantState is the use of the budget of a tenant.
+type tenantState struct {
+	tokens   float64
+	refilled time.Time
+
+	day          time.Time
+	transactions int
+
+	admitted int64
+	rejected map[Kind]int64
+}
+
+// Enforcer enforces the budgets of tenants. It is safe for concurrent use.
+type Enforcer struct {
+	mu            sync.Mutex
+	budgets       map[string]Budget
+	defaultBudget *Budget
+	location      *time.Location
+	tenants       map[string]*tenantState
+	now           func() time.Time
+
+	admittedCounter metric.Int64Counter
+	rejectedCounter metric.Int64Counter
+}
+
+// NewEnforcer returns an Enforcer without budgets, admitting every call,
+// with days in UTC.
+func NewEnforcer() *Enforcer {
+	return &Enforcer{
+		budgets:  map[string]Budget{},
+		location: time.UTC,
+		tenants:  map[string]*tenantState{},
+		now:      time.Now,
+	}
+}
+
+// WithBudget sets the budget of a tenant.
+func (e *Enforcer) WithBudget(tenant string, budget Budget) *Enforcer {
+	e.SetBudget(tenant, budget)
+
+	return e
+}
+
+// WithDefaultBudget sets the budget of the tenants without their own,
+// including the calls without a tenant.
+func (e *Enforcer) WithDefaultBudget(budget Budget) *Enforcer {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.defaultBudget = &budget
+
+	return e
+}
+
+// WithLocation sets the time zone of the days of MaxDailyTransactions.
+func (e *Enforcer) WithLocation(location *time.Location) *Enforcer {
+	if location != nil {
+		e.location = location
+	}
+
+	return e
+}
+
+// WithObservability counts the calls admitted and rejected in the
+// MetricAdmitted and MetricRejected counters of obs, by tenant, and by kind
+// for the rejected ones.
+func (e *Enforcer) WithObservability(obs observability.Provider) *Enforcer {
+	if obs == nil || !obs.IsEnabled() {
+		return e
+	}
+
+	admitted, err := obs.Meter().Int64Counter(MetricAdmitted,
+		metric.WithDescription("Number of calls admitted by the tenant quotas"))
+	if err == nil {
+		e.admittedCounter = admitted
+	}
+
+	rejected, err := obs.Meter().Int64Counter(MetricRejected,
+		metric.WithDescription("Number of calls rejected by the tenant quotas"))
+	if err == nil {
+		e.rejectedCounter = rejected
+	}
+
+	return e
+}
+
+// SetBudget sets the budget of a tenant while calls are made, such as when a
+// tenant changes plan. The use of the budget so far is kept.
+func (e *Enforcer) SetBudget(tenant string, budget Budget) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.budgets[tenant] = budget
+}
+
+// budget returns the budget of a tenant, false when it is unbounded.
+func (e *Enforcer) budget(tenant string) (Budget, bool) {
+	if b, ok := e.budgets[tenant]; ok {
+		return b, true
+	}
+
+	if e.defaultBudget != nil {
+		return *e.defaultBudget, true
+	}
+
+	return Budget{}, false
+}
+
+// state returns the state of a tenant, created with a full token bucket.
+func (e *Enforcer) state(tenant string, budget Budget, now time.Time) *tenantState {
+	s, ok := e.tenants[tenant]
+	if !ok {
+		s = &tenantState{tokens: budget.burst(), refilled: now, rejected: map[Kind]int64{}}
+		e.tenants[tenant] = s
+	}
+
+	return s
+}
+
+// startOfDay returns the start of the day of t in the location of the enforcer.
+func (e *Enforcer) startOfDay(t time.Time) time.Time {
+	local := t.In(e.location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, e.location)
+}
+
+// transactionEndpoints are the last segments of the paths creating transactions.
+var transactionEndpoints = []string{"json", "dsl", "inflow", "outflow", "annotation"}
+
+// IsTransactionCall reports whether a request creates a transaction, counted
+// against MaxDailyTransactions.
+func IsTransactionCall(method, requestURL string) bool {
+	if method != http.MethodPost {
+		return false
+	}
+
+	segments := strings.Split(usage.Operation(method, requestURL), "/")
+	n := len(segments)
+
+	return n >= 2 && segments[n-2] == "transactions" && slices.Contains(transactionEndpoints, segments[n-1])
+}
+
+// Permit is a call admitted by an Enforcer. Done must be called when the call
+// ends.
+type Permit struct {
+	e           *Enforcer
+	tenant      string
+	transaction bool
+	day         time.Time
+}
+
+// Admit checks a call of tenant against its budget. It returns an
+// *ExceededError when the call is over budget, or the error of ctx when it
+// ends while the call waits for its turn.
+func (e *Enforcer) Admit(ctx context.Context, tenant, method, requestURL string) (*Permit, error) {
+	transaction := IsTransactionCall(method, requestURL)
+
+	e.mu.Lock()
+
+	budget, ok := e.budget(tenant)
+	if !ok {
+		e.mu.Unlock()
+		return &Permit{}, nil
+	}
+
+	now := e.now()
+	s := e.state(tenant, budget, now)
+
+	day := e.startOfDay(now)
+	if !s.day.Equal(day) {
+		s.day, s.transactions = day, 0
+	}
+
+	if transaction && budget.MaxDailyTransactions > 0 && s.transactions >= budget.MaxDailyTransactions {
+		err := &ExceededError{Tenant: tenant, Kind: KindDailyTransactions, Limit: float64(budget.MaxDailyTransactions), RetryAfter: day.AddDate(0, 0, 1).Sub(now)}
+		e.reject(ctx, s, err)
+		e.mu.Unlock()
+
+		return nil, err
+	}
+
+	wait, exceeded := e.takeToken(ctx, s, tenant, budget, now)
+	if exceeded != nil {
+		e.reject(ctx, s, exceeded)
+		e.mu.Unlock()
+
+		return nil, exceeded
+	}
+
+	permit := &Permit{e: e, tenant: tenant, transaction: transaction && budget.MaxDailyTransactions > 0, day: day}
+	if permit.transaction {
+		s.transactions++
+	}
+
+	s.admitted++
+	e.mu.Unlock()
+
+	if e.admittedCounter != nil {
+		e.admittedCounter.Add(ctx, 1, metric.WithAttributes(attribute.String(KeyTenant, tenant)))
+	}
+
+	if wait > 0 {
+		if err := sleep(ctx, wait); err != nil {
+			if permit.transaction {
+				permit.release()
+			}
+
+			return nil, err
+		}
+	}
+
+	return permit, nil
+}
+
+// takeToken takes a token from the bucket of the tenant, returning how long
+// the call waits for it, or an *ExceededError when it can't. The enforcer is
+// locked.
+func (e *Enforcer) takeToken(ctx context.Context, s *tenantState, tenant string, budget Budget, now time.Time) (time.Duration, *ExceededError) {
+	if budget.MaxTPS <= 0 {
+		return 0, nil
+	}
+
+	if elapsed := now.Sub(s.refilled).Seconds(); elapsed > 0 {
+		s.tokens = math.Min(budget.burst(), s.tokens+elapsed*budget.MaxTPS)
+	}
+
+	s.refilled = now
+
+	if s.tokens >= 1 {
+		s.tokens--
+		return 0, nil
+	}
+
+	wait := time.Duration((1 - s.tokens) / budget.MaxTPS * float64(time.Second))
+
+	if budget.Wait {
+		deadline, ok := ctx.Deadline()
+		if !ok || now.Add(wait).Before(deadline) {
+			// the token is taken ahead, the bucket refills it meanwhile
+			s.tokens--
+			return wait, nil
+		}
+	}
+
+	return 0, &ExceededError{Tenant: tenant, Kind: KindTPS, Limit: budget.MaxTPS, RetryAfter: wait}
+}
+
+// reject counts a rejected call. The enforcer is locked.
+func (e *Enforcer) reject(ctx context.Context, s *tenantState, err *ExceededError) {
+	s.rejected[err.Kind]++
+
+	if e.rejectedCounter != nil {
+		e.rejectedCounter.Add(ctx, 1, metric.WithAttributes(
+			attribute.String(KeyTenant, err.Tenant),
+			attribute.String(KeyKind, string(err.Kind)),
+		))
+	}
+}
+
+// sleep waits for d or the end of ctx.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Done ends the call of the permit with the HTTP status of its last response,
+// 0 when none was received, and its error. A transaction the API rejected
+// with a 4xx status was not created and is given back to the daily budget;
+// one failing otherwise, such as on a timeout, may have been and stays
+// counted.
+func (p *Permit) Done(statusCode int, err error) {
+	if p == nil || !p.transaction || err == nil || statusCode < 400 || statusCode >= 500 {
+		return
+	}
+
+	p.release()
+}
+
+// release gives the transaction of the permit back to the daily budget.
+func (p *Permit) release() {
+	p.e.mu.Lock()
+	defer p.e.mu.Unlock()
+
+	if s, ok := p.e.tenants[p.tenant]; ok && s.day.Equal(p.day) && s.transactions > 0 {
+		s.transactions--
+	}
+
+	p.transaction = false
+}
+
+// Usage returns the use of the budget of the tenants that made calls, sorted
+// by tenant.
+func (e *Enforcer) Usage() []Usage {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	today := e.startOfDay(e.now())
+	usages := make([]Usage, 0, len(e.tenants))
+
+	for tenant, s := range e.tenants {
+		u := Usage{Tenant: tenant, Admitted: s.admitted}
+		u.Budget, _ = e.budget(tenant)
+
+		if s.day.Equal(today) {
+			u.Transactions = s.transactions
+		}
+
+		for kind, n := range s.rejected {
+			if u.Rejected == nil {
+				u.Rejected = map[Kind]int64{}
+			}
+
+			u.Rejected[kind] = n
+		}
+
+		usages = append(usages, u)
+	}
+
+	slices.SortFunc(usages, func(x, y Usage) int { return strings.Compare(x.Tenant, y.Tenant) })
+
+	return usages
+}
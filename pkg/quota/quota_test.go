@@ -0,0 +1,122 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	createURL = "https://midaz.example.com/v1/organizations/org-1/ledgers/ledger-1/transactions/json"
+	listURL   = "https://midaz.example.com/v1/organizations/org-1/ledgers/ledger-1/accounts"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestEnforcer(start time.Time) (*Enforcer, *fakeClock) {
+	clock := &fakeClock{t: start}
+	e := NewEnforcer()
+	e.now = clock.now
+
+	return e, clock
+}
+
+func TestIsTransactionCall(t *testing.T) {
+	assert.True(t, IsTransactionCall(http.MethodPost, createURL))
+	assert.True(t, IsTransactionCall(http.MethodPost, "http://localhost:3001/v1/organizations/o/ledgers/l/transactions/inflow"))
+	assert.False(t, IsTransactionCall(http.MethodGet, createURL))
+	assert.False(t, IsTransactionCall(http.MethodPost, listURL))
+	assert.False(t, IsTransactionCall(http.MethodPost, "http://localhost:3001/v1/organizations/o/ledgers/l/transactions/tx-1/commit"))
+}
+
+func TestEnforcer_TPS(t *testing.T) {
+	ctx := context.Background()
+	e, clock := newTestEnforcer(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+	e.WithBudget("tenant-a", Budget{MaxTPS: 2})
+
+	for range 2 {
+		_, err := e.Admit(ctx, "tenant-a", http.MethodGet, listURL)
+		require.NoError(t, err, "the burst defaults to MaxTPS")
+	}
+
+	_, err := e.Admit(ctx, "tenant-a", http.MethodGet, listURL)
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+
+	var exceeded *ExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, "tenant-a", exceeded.Tenant)
+	assert.Equal(t, KindTPS, exceeded.Kind)
+	assert.Equal(t, 500*time.Millisecond, exceeded.RetryAfter)
+
+	_, err = e.Admit(ctx, "tenant-b", http.MethodGet, listURL)
+	assert.NoError(t, err, "tenants without a budget are unbounded")
+
+	clock.t = clock.t.Add(500 * time.Millisecond)
+
+	_, err = e.Admit(ctx, "tenant-a", http.MethodGet, listURL)
+	assert.NoError(t, err, "the bucket refills at MaxTPS")
+}
+
+func TestEnforcer_TPSWait(t *testing.T) {
+	e := NewEnforcer().WithDefaultBudget(Budget{MaxTPS: 100, Burst: 1, Wait: true})
+
+	_, err := e.Admit(context.Background(), "", http.MethodGet, listURL)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = e.Admit(context.Background(), "", http.MethodGet, listURL)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 5*time.Millisecond, "the call waits for its turn")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	_, err = e.Admit(ctx, "", http.MethodGet, listURL)
+	require.ErrorIs(t, err, ErrQuotaExceeded, "calls whose turn comes after their deadline fail at once")
+}
+
+func TestEnforcer_DailyTransactions(t *testing.T) {
+	ctx := context.Background()
+	e, clock := newTestEnforcer(time.Date(2025, 6, 1, 23, 0, 0, 0, time.UTC))
+	e.WithBudget("tenant-a", Budget{MaxDailyTransactions: 2})
+
+	first, err := e.Admit(ctx, "tenant-a", http.MethodPost, createURL)
+	require.NoError(t, err)
+	first.Done(http.StatusCreated, nil)
+
+	rejected, err := e.Admit(ctx, "tenant-a", http.MethodPost, createURL)
+	require.NoError(t, err)
+	rejected.Done(http.StatusBadRequest, errors.New("insufficient funds"))
+
+	timedOut, err := e.Admit(ctx, "tenant-a", http.MethodPost, createURL)
+	require.NoError(t, err, "a transaction rejected by the API is given back")
+	timedOut.Done(0, context.DeadlineExceeded)
+
+	_, err = e.Admit(ctx, "tenant-a", http.MethodGet, listURL)
+	require.NoError(t, err, "only transactions count against the daily budget")
+
+	_, err = e.Admit(ctx, "tenant-a", http.MethodPost, createURL)
+
+	var exceeded *ExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, KindDailyTransactions, exceeded.Kind)
+	assert.Equal(t, time.Hour, exceeded.RetryAfter, "until the next day")
+
+	usage := e.Usage()
+	require.Len(t, usage, 1)
+	assert.Equal(t, 2, usage[0].Transactions)
+	assert.Equal(t, int64(4), usage[0].Admitted)
+	assert.Equal(t, int64(1), usage[0].Rejected[KindDailyTransactions])
+
+	clock.t = clock.t.Add(time.Hour)
+
+	_, err = e.Admit(ctx, "tenant-a", http.MethodPost, createURL)
+	assert.NoError(t, err, "the budget is renewed every day")
+}
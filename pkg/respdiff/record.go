@@ -0,0 +1,180 @@
+package respdiff
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Request is a recorded API request.
+type Request struct {
+	// Service is the service the request goes to, a key of Target.BaseURLs
+	// such as "onboarding" or "transaction"
+	Service string `json:"service"`
+	Method  string `json:"method"`
+	// Path is the path and query of the request relative to the base URL of
+	// the service, e.g. "/organizations?limit=10"
+	Path string `json:"path"`
+	// Body is the JSON body of the request, if any
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// Recorder records the requests sent through its transport. Headers are not
+// recorded, so the recording holds no credentials. It is safe for concurrent
+// use.
+type Recorder struct {
+	baseURLs map[string]string
+	writes   bool
+
+	mu       sync.Mutex
+	requests []Request
+}
+
+// NewRecorder creates a recorder of the requests to the services of
+// baseURLs, such as config.Config.GetBaseURLs(). Requests to other URLs are
+// not recorded.
+//
+// Parameters:
+//   - baseURLs: The base URL of each service
+//   - includeWrites: Whether to record the requests other than GET; replaying
+//     them creates or changes resources on both targets
+//
+// Returns:
+//   - *Recorder: A recorder with no requests
+func NewRecorder(baseURLs map[string]string, includeWrites bool) *Recorder {
+	return &Recorder{baseURLs: baseURLs, writes: includeWrites}
+}
+
+// Transport returns a transport recording the requests sent through base,
+// or through http.DefaultTransport if base is nil.
+func (r *Recorder) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &recordingTransport{base: base, recorder: r}
+}
+
+// Requests returns the recorded requests, in the order they were sent.
+func (r *Recorder) Requests() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Request(nil), r.requests...)
+}
+
+// Save writes the recorded requests to w as JSON lines, the format read by Load.
+func (r *Recorder) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+
+	for _, req := range r.Requests() {
+		if err := enc.Encode(req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Load reads requests saved by Recorder.Save: one JSON request per line,
+// skipping blank lines.
+func Load(rd io.Reader) ([]Request, error) {
+	var requests []Request
+
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var req Request
+		if err := json.Unmarshal(text, &req); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if req.Service == "" || req.Method == "" {
+			return nil, fmt.Errorf("line %d: service and method are required", line)
+		}
+
+		requests = append(requests, req)
+	}
+
+	return requests, scanner.Err()
+}
+
+// record adds req if it goes to a known service.
+func (r *Recorder) record(req *http.Request) error {
+	if req.Method != http.MethodGet && !r.writes {
+		return nil
+	}
+
+	service, path, ok := r.match(req.URL.String())
+	if !ok {
+		return nil
+	}
+
+	recorded := Request{Service: service, Method: req.Method, Path: path}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+
+		if err != nil {
+			return err
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		if len(body) > 0 {
+			recorded.Body = body
+		}
+	}
+
+	r.mu.Lock()
+	r.requests = append(r.requests, recorded)
+	r.mu.Unlock()
+
+	return nil
+}
+
+// match returns the service of a URL and the path relative to its base URL,
+// preferring the longest base URL.
+func (r *Recorder) match(rawURL string) (service, path string, ok bool) {
+	longest := -1
+
+	for name, base := range r.baseURLs {
+		base = strings.TrimSuffix(base, "/")
+		if base == "" || len(base) <= longest {
+			continue
+		}
+
+		if rest, found := strings.CutPrefix(rawURL, base); found && (rest == "" || rest[0] == '/' || rest[0] == '?') {
+			service, path, ok, longest = name, rest, true, len(base)
+		}
+	}
+
+	return service, path, ok
+}
+
+// recordingTransport records the requests of a Recorder.
+type recordingTransport struct {
+	base     http.RoundTripper
+	recorder *Recorder
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.recorder.record(req); err != nil {
+		return nil, err
+	}
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,226 @@
+package respdiff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds each request of a target without an HTTP client.
+const DefaultTimeout = 30 * time.Second
+
+// Target is a backend deployment to replay requests against.
+type Target struct {
+	// Name identifies the target in the report, e.g. "v3.3"
+	Name string
+	// BaseURLs maps the services of the requests to the base URLs of the target
+	BaseURLs map[string]string
+	// Headers are added to every request, e.g. Authorization or X-Tenant-ID
+	Headers map[string]string
+	// HTTPClient sends the requests
+	// Default is a client with a DefaultTimeout timeout
+	HTTPClient *http.Client
+}
+
+// Options configures Replay
+type Options struct {
+	// IgnoreFields are the fields left out of the comparison, see Compare
+	// Default is DefaultIgnoreFields if nil
+	IgnoreFields []string
+	// OnResult is called with the result of each request, in order
+	OnResult func(Result)
+}
+
+// Result is the outcome of a replayed request.
+type Result struct {
+	Request Request `json:"request"`
+	// StatusA and StatusB are the status codes of the targets, 0 when a
+	// request got no response
+	StatusA int `json:"statusA"`
+	StatusB int `json:"statusB"`
+	// Differences are the differences between the response bodies, compared
+	// only when the status codes match
+	Differences []Difference `json:"differences,omitempty"`
+	// Error is the error of a request that got no response
+	Error string `json:"error,omitempty"`
+}
+
+// Matched reports whether both targets answered the same.
+func (r Result) Matched() bool {
+	return r.Error == "" && r.StatusA == r.StatusB && len(r.Differences) == 0
+}
+
+// Report is the outcome of Replay.
+type Report struct {
+	A string `json:"a"`
+	B string `json:"b"`
+	// Results holds the result of each request, in order
+	Results []Result `json:"results"`
+	// Matched, Diverged and Failed count the results: failed requests got
+	// no response from a target
+	Matched  int `json:"matched"`
+	Diverged int `json:"diverged"`
+	Failed   int `json:"failed"`
+}
+
+// OK reports whether every request matched.
+func (r *Report) OK() bool {
+	return r.Diverged == 0 && r.Failed == 0
+}
+
+// WriteText writes the divergent and failed requests with their differences,
+// followed by the counts.
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	for i, result := range r.Results {
+		if result.Matched() {
+			continue
+		}
+
+		fmt.Fprintf(&b, "#%d %s %s %s\n", i+1, result.Request.Method, result.Request.Service, result.Request.Path)
+
+		switch {
+		case result.Error != "":
+			fmt.Fprintf(&b, "  error: %s\n", result.Error)
+		case result.StatusA != result.StatusB:
+			fmt.Fprintf(&b, "  status: %s %d, %s %d\n", r.A, result.StatusA, r.B, result.StatusB)
+		}
+
+		for _, d := range result.Differences {
+			fmt.Fprintf(&b, "  %s\n", d)
+		}
+	}
+
+	fmt.Fprintf(&b, "%d requests: %d matched, %d diverged, %d failed\n", len(r.Results), r.Matched, r.Diverged, r.Failed)
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// Replay sends each request to target a, then to target b, in order, and
+// compares the responses. Requests with a body are replayed too, so replay
+// recordings with writes against disposable environments only.
+//
+// Returns:
+//   - The report of the requests replayed until ctx was done
+//   - An error if a target has no name or lacks the base URL of a service
+//     of the requests, or ctx.Err() if ctx was done before the end
+func Replay(ctx context.Context, requests []Request, a, b Target, opts *Options) (*Report, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	for _, target := range []Target{a, b} {
+		if err := target.validate(requests); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &Report{A: a.Name, B: b.Name, Results: make([]Result, 0, len(requests))}
+
+	for _, req := range requests {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		result := replay(ctx, req, a, b, opts.IgnoreFields)
+
+		switch {
+		case result.Error != "":
+			report.Failed++
+		case result.Matched():
+			report.Matched++
+		default:
+			report.Diverged++
+		}
+
+		report.Results = append(report.Results, result)
+
+		if opts.OnResult != nil {
+			opts.OnResult(result)
+		}
+	}
+
+	return report, nil
+}
+
+// validate checks that the target can serve the requests.
+func (t Target) validate(requests []Request) error {
+	if t.Name == "" {
+		return errors.New("target name is required")
+	}
+
+	for _, req := range requests {
+		if t.BaseURLs[req.Service] == "" {
+			return fmt.Errorf("target %s has no base URL for service %q", t.Name, req.Service)
+		}
+	}
+
+	return nil
+}
+
+// replay sends a request to both targets and compares the responses.
+func replay(ctx context.Context, req Request, a, b Target, ignoreFields []string) Result {
+	result := Result{Request: req}
+
+	statusA, bodyA, errA := a.send(ctx, req)
+	statusB, bodyB, errB := b.send(ctx, req)
+	result.StatusA, result.StatusB = statusA, statusB
+
+	if err := errors.Join(errA, errB); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if statusA == statusB {
+		result.Differences = Compare(bodyA, bodyB, ignoreFields)
+	}
+
+	return result
+}
+
+// send sends a request to the target and reads the response.
+func (t Target) send(ctx context.Context, req Request) (int, []byte, error) {
+	var body io.Reader
+	if len(req.Body) > 0 {
+		body = bytes.NewReader(req.Body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, strings.TrimSuffix(t.BaseURLs[req.Service], "/")+req.Path, body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s: %w", t.Name, err)
+	}
+
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	for k, v := range t.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	client := t.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s: %w", t.Name, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("%s: %w", t.Name, err)
+	}
+
+	return resp.StatusCode, data, nil
+}
@@ -0,0 +1,135 @@
+package respdiff
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func backend(version string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(body)
+		case r.URL.Path == "/v1/organizations" && version == "new":
+			_, _ = w.Write([]byte(`{"items":[{"id":"1","legalName":"Acme","createdAt":"now"}],"limit":10}`))
+		case r.URL.Path == "/v1/organizations":
+			_, _ = w.Write([]byte(`{"items":[{"id":"1","legalName":"Acme","createdAt":"then"}],"limit":10,"page":1}`))
+		case r.URL.Path == "/v1/ledgers" && version == "new":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			_, _ = w.Write([]byte(`{"items":[]}`))
+		}
+	}))
+}
+
+func TestRecorder(t *testing.T) {
+	srv := backend("old")
+	defer srv.Close()
+
+	recorder := NewRecorder(map[string]string{"onboarding": srv.URL + "/v1", "transaction": srv.URL + "/v1/tx"}, true)
+	client := &http.Client{Transport: recorder.Transport(srv.Client().Transport)}
+
+	resp, err := client.Get(srv.URL + "/v1/organizations?limit=10")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	resp, err = client.Post(srv.URL+"/v1/tx/transactions", "application/json", strings.NewReader(`{"amount":"1"}`))
+	require.NoError(t, err)
+
+	echoed, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.JSONEq(t, `{"amount":"1"}`, string(echoed), "the body still reaches the server")
+
+	resp, err = client.Get(srv.URL + "/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	requests := recorder.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, Request{Service: "onboarding", Method: http.MethodGet, Path: "/organizations?limit=10"}, requests[0])
+	assert.Equal(t, "transaction", requests[1].Service, "the longest base URL wins")
+	assert.Equal(t, "/transactions", requests[1].Path)
+
+	var saved bytes.Buffer
+	require.NoError(t, recorder.Save(&saved))
+
+	loaded, err := Load(&saved)
+	require.NoError(t, err)
+	assert.Equal(t, requests, loaded)
+
+	_, err = Load(strings.NewReader("{\"service\":\"onboarding\"}\n"))
+	assert.ErrorContains(t, err, "line 1")
+
+	readOnly := NewRecorder(map[string]string{"onboarding": srv.URL}, false)
+	_ = readOnly.record(httptest.NewRequest(http.MethodPost, srv.URL+"/organizations", nil))
+	assert.Empty(t, readOnly.Requests())
+}
+
+func TestReplay(t *testing.T) {
+	oldSrv, newSrv := backend("old"), backend("new")
+	defer oldSrv.Close()
+	defer newSrv.Close()
+
+	requests := []Request{
+		{Service: "onboarding", Method: http.MethodGet, Path: "/organizations"},
+		{Service: "onboarding", Method: http.MethodGet, Path: "/ledgers"},
+		{Service: "onboarding", Method: http.MethodPost, Path: "/assets", Body: []byte(`{"code":"USD"}`)},
+	}
+
+	var seen int
+
+	report, err := Replay(context.Background(), requests,
+		Target{Name: "old", BaseURLs: map[string]string{"onboarding": oldSrv.URL + "/v1"}},
+		Target{Name: "new", BaseURLs: map[string]string{"onboarding": newSrv.URL + "/v1/"}, Headers: map[string]string{"Authorization": "Bearer x"}},
+		&Options{OnResult: func(Result) { seen++ }})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, seen)
+	assert.False(t, report.OK())
+	assert.Equal(t, 1, report.Matched)
+	assert.Equal(t, 2, report.Diverged)
+
+	assert.Equal(t, []Difference{{Path: "page", Kind: KindRemoved, A: jsonNumber("1")}}, report.Results[0].Differences,
+		"createdAt is ignored by default")
+	assert.Equal(t, http.StatusNotFound, report.Results[1].StatusB)
+	assert.True(t, report.Results[2].Matched())
+
+	var text bytes.Buffer
+	require.NoError(t, report.WriteText(&text))
+	assert.Contains(t, text.String(), "#1 GET onboarding /organizations\n  page: removed 1\n")
+	assert.Contains(t, text.String(), "status: old 200, new 404")
+	assert.Contains(t, text.String(), "3 requests: 1 matched, 2 diverged, 0 failed")
+}
+
+func TestReplayValidation(t *testing.T) {
+	requests := []Request{{Service: "transaction", Method: http.MethodGet, Path: "/"}}
+	target := Target{Name: "a", BaseURLs: map[string]string{"onboarding": "http://a"}}
+
+	_, err := Replay(context.Background(), requests, target, target, nil)
+	assert.ErrorContains(t, err, `no base URL for service "transaction"`)
+
+	_, err = Replay(context.Background(), nil, Target{}, target, nil)
+	assert.ErrorContains(t, err, "name")
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	down := Target{Name: "down", BaseURLs: map[string]string{"transaction": closed.URL}}
+
+	report, err := Replay(context.Background(), requests, down, down, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Failed)
+	assert.Contains(t, report.Results[0].Error, "down:")
+}
@@ -0,0 +1,244 @@
+// Package respdiff verifies SDK and backend upgrades by replaying a recorded
+// set of API requests against two Midaz deployments, such as the current
+// version and the candidate, and diffing their responses structurally.
+//
+// A Recorder captures the requests of a client through its transport and
+// saves them as JSON lines; Replay sends them to both targets in order and
+// reports, for each request, the status codes and the JSON fields that
+// differ, ignoring volatile fields such as timestamps:
+//
+//	recorder := respdiff.NewRecorder(cfg.GetBaseURLs(), false)
+//	c, _ := client.New(client.WithHTTPClient(&http.Client{Transport: recorder.Transport(nil)}), ...)
+//	// ... run the workload ...
+//	_ = recorder.Save(file)
+//
+//	requests, _ := respdiff.Load(file)
+//	report, err := respdiff.Replay(ctx, requests,
+//	    respdiff.Target{Name: "v3.3", BaseURLs: current, Headers: auth},
+//	    respdiff.Target{Name: "v3.4", BaseURLs: candidate, Headers: auth}, nil)
+//	report.WriteText(os.Stdout)
+//
+// Compare diffs two response bodies on their own.
+package respdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultIgnoreFields are the volatile fields left out of the comparison
+// when no fields are given: timestamps that differ between any two runs.
+var DefaultIgnoreFields = []string{"createdAt", "updatedAt", "deletedAt"}
+
+// Kind is the kind of a Difference
+type Kind string
+
+const (
+	// KindChanged is a value that differs between the responses
+	KindChanged Kind = "changed"
+	// KindAdded is a field or element only present in the second response
+	KindAdded Kind = "added"
+	// KindRemoved is a field or element only present in the first response
+	KindRemoved Kind = "removed"
+	// KindType is a value whose JSON type differs between the responses
+	KindType Kind = "type"
+)
+
+// Difference is a value that differs between two responses.
+type Difference struct {
+	// Path locates the value, e.g. "items[0].status.code", or "$" for the whole body
+	Path string `json:"path"`
+	Kind Kind   `json:"kind"`
+	// A and B are the values in the first and second response; the missing
+	// one is nil for added and removed values
+	A any `json:"a,omitempty"`
+	B any `json:"b,omitempty"`
+}
+
+// String formats the difference, e.g. `items[0].status.code: changed "ACTIVE" -> "BLOCKED"`.
+func (d Difference) String() string {
+	switch d.Kind {
+	case KindAdded:
+		return fmt.Sprintf("%s: added %s", d.Path, formatValue(d.B))
+	case KindRemoved:
+		return fmt.Sprintf("%s: removed %s", d.Path, formatValue(d.A))
+	default:
+		return fmt.Sprintf("%s: %s %s -> %s", d.Path, d.Kind, formatValue(d.A), formatValue(d.B))
+	}
+}
+
+// Compare returns the differences between two response bodies, in path
+// order. JSON bodies are compared structurally: field order and formatting
+// don't matter, and numbers are compared as written, without float rounding.
+// Other bodies are compared as text, yielding at most one difference at "$".
+//
+// A field is ignored when its name, or its path with indices written [*]
+// (e.g. "items[*].balance.available"), is in ignoreFields; nil ignores
+// DefaultIgnoreFields.
+func Compare(a, b []byte, ignoreFields []string) []Difference {
+	if ignoreFields == nil {
+		ignoreFields = DefaultIgnoreFields
+	}
+
+	va, errA := decode(a)
+	vb, errB := decode(b)
+
+	if errA != nil || errB != nil {
+		ta, tb := string(bytes.TrimSpace(a)), string(bytes.TrimSpace(b))
+		if ta == tb {
+			return nil
+		}
+
+		return []Difference{{Path: "$", Kind: KindChanged, A: ta, B: tb}}
+	}
+
+	c := &comparer{ignore: make(map[string]bool, len(ignoreFields))}
+	for _, field := range ignoreFields {
+		c.ignore[field] = true
+	}
+
+	c.compare("", "", va, vb)
+
+	return c.differences
+}
+
+// decode parses a JSON body, keeping numbers as json.Number.
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if dec.More() {
+		return nil, errors.New("trailing data after JSON value")
+	}
+
+	return v, nil
+}
+
+// comparer accumulates the differences of a comparison.
+type comparer struct {
+	ignore      map[string]bool
+	differences []Difference
+}
+
+// compare diffs a and b at path; pattern is the path with indices written [*].
+func (c *comparer) compare(path, pattern string, a, b any) {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok {
+			c.add(path, KindType, a, b)
+			return
+		}
+
+		for _, key := range unionKeys(a, b) {
+			keyPath, keyPattern := joinPath(path, key), joinPath(pattern, key)
+			if c.ignore[key] || c.ignore[keyPattern] {
+				continue
+			}
+
+			va, inA := a[key]
+			vb, inB := b[key]
+
+			switch {
+			case !inB:
+				c.add(keyPath, KindRemoved, va, nil)
+			case !inA:
+				c.add(keyPath, KindAdded, nil, vb)
+			default:
+				c.compare(keyPath, keyPattern, va, vb)
+			}
+		}
+	case []any:
+		b, ok := b.([]any)
+		if !ok {
+			c.add(path, KindType, a, b)
+			return
+		}
+
+		for i := range max(len(a), len(b)) {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+
+			switch {
+			case i >= len(b):
+				c.add(elemPath, KindRemoved, a[i], nil)
+			case i >= len(a):
+				c.add(elemPath, KindAdded, nil, b[i])
+			default:
+				c.compare(elemPath, pattern+"[*]", a[i], b[i])
+			}
+		}
+	default:
+		switch {
+		case reflect.TypeOf(a) != reflect.TypeOf(b):
+			c.add(path, KindType, a, b)
+		case a != b:
+			c.add(path, KindChanged, a, b)
+		}
+	}
+}
+
+func (c *comparer) add(path string, kind Kind, a, b any) {
+	if path == "" {
+		path = "$"
+	}
+
+	c.differences = append(c.differences, Difference{Path: path, Kind: kind, A: a, B: b})
+}
+
+func unionKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// plainKey matches keys that can be written after a dot in a path.
+var plainKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+func joinPath(path, key string) string {
+	if !plainKey.MatchString(key) {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// formatValue formats a value for Difference.String, shortening long ones.
+func formatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	s := string(data)
+	if len(s) > 80 {
+		s = s[:77] + "..."
+	}
+
+	return strings.ReplaceAll(s, "\n", " ")
+}
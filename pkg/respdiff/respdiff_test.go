@@ -0,0 +1,45 @@
+package respdiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	a := []byte(`{"id":"1","amount":"10.50","scale":2,"status":{"code":"ACTIVE"},"tags":["a","b"],"old":true,"updatedAt":"2026-01-01"}`)
+	b := []byte(`{"tags":["a"],"status":{"code":"BLOCKED"},"scale":"2","amount":"10.50","id":"1","new":1,"updatedAt":"2026-02-01"}`)
+
+	differences := Compare(a, b, nil)
+
+	require.Len(t, differences, 5)
+	assert.Equal(t, Difference{Path: "new", Kind: KindAdded, B: jsonNumber("1")}, differences[0])
+	assert.Equal(t, Difference{Path: "old", Kind: KindRemoved, A: true}, differences[1])
+	assert.Equal(t, KindType, differences[2].Kind)
+	assert.Equal(t, "scale", differences[2].Path)
+	assert.Equal(t, `status.code: changed "ACTIVE" -> "BLOCKED"`, differences[3].String())
+	assert.Equal(t, `tags[1]: removed "b"`, differences[4].String())
+}
+
+func TestCompareIgnoreFields(t *testing.T) {
+	a := []byte(`{"items":[{"id":"1","balance":{"available":"1"}}],"total":1}`)
+	b := []byte(`{"items":[{"id":"2","balance":{"available":"2"}}],"total":1}`)
+
+	assert.Empty(t, Compare(a, b, []string{"id", "items[*].balance.available"}))
+	assert.Len(t, Compare(a, b, []string{}), 2, "an empty list ignores nothing")
+	assert.Empty(t, Compare([]byte(`{"a": 1}`), []byte(`{ "a":1 }`), nil))
+}
+
+func TestCompareText(t *testing.T) {
+	assert.Empty(t, Compare([]byte("ok\n"), []byte("ok"), nil))
+
+	differences := Compare([]byte("ok"), []byte(`{"ok":true}`), nil)
+	require.Len(t, differences, 1)
+	assert.Equal(t, "$", differences[0].Path)
+}
+
+func jsonNumber(s string) any {
+	v, _ := decode([]byte(s))
+	return v
+}
@@ -0,0 +1,140 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDeadlineWouldBeExceeded is the sentinel matched by errors.Is when a retry
+// was skipped because it could not complete before the context deadline.
+var ErrDeadlineWouldBeExceeded = errors.New("retry skipped: context deadline would be exceeded")
+
+// DeadlineExceededError is returned when the next retry attempt was skipped
+// because the backoff delay plus the expected attempt latency would not fit
+// in the time remaining before the context deadline.
+//
+// Instead of sleeping until the deadline fires, the retry loop gives up
+// immediately so callers keep their remaining budget for fallbacks.
+//
+// Example:
+//
+//	err := retry.Do(ctx, fn, retry.WithExpectedLatency(200*time.Millisecond))
+//
+//	var deadlineErr *retry.DeadlineExceededError
+//	if errors.As(err, &deadlineErr) {
+//	    log.Printf("gave up after attempt %d, %v left", deadlineErr.Attempt, deadlineErr.Remaining)
+//	}
+type DeadlineExceededError struct {
+	// Attempt is the zero-based attempt that produced LastErr.
+	Attempt int
+
+	// Delay is the backoff delay that would have been waited before retrying.
+	Delay time.Duration
+
+	// ExpectedLatency is the configured expected duration of a single attempt.
+	ExpectedLatency time.Duration
+
+	// Remaining is the time that was left before the context deadline.
+	Remaining time.Duration
+
+	// LastErr is the error returned by the last executed attempt.
+	LastErr error
+}
+
+// Error implements the error interface.
+func (e *DeadlineExceededError) Error() string {
+	msg := fmt.Sprintf("%s: attempt %d needs %v backoff + %v expected latency, %v remaining",
+		ErrDeadlineWouldBeExceeded.Error(), e.Attempt, e.Delay, e.ExpectedLatency, e.Remaining)
+
+	if e.LastErr != nil {
+		msg += ": " + e.LastErr.Error()
+	}
+
+	return msg
+}
+
+// Unwrap returns the error of the last executed attempt.
+func (e *DeadlineExceededError) Unwrap() error {
+	return e.LastErr
+}
+
+// Is reports whether target is ErrDeadlineWouldBeExceeded or
+// context.DeadlineExceeded. Retries running out the deadline returned
+// context.DeadlineExceeded before they were truncated, so existing checks
+// for it keep matching.
+func (*DeadlineExceededError) Is(target error) bool {
+	return target == ErrDeadlineWouldBeExceeded || target == context.DeadlineExceeded
+}
+
+// IsDeadlineExceededError reports whether err, or any error it wraps, is a
+// DeadlineExceededError.
+func IsDeadlineExceededError(err error) bool {
+	return errors.Is(err, ErrDeadlineWouldBeExceeded)
+}
+
+// WithExpectedLatency returns an Option that sets the expected duration of a
+// single attempt. Before each retry the context deadline is compared against
+// the backoff delay plus this latency; if the retry cannot finish in time a
+// DeadlineExceededError is returned immediately. The value must be non-negative.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+//	defer cancel()
+//
+//	err := retry.Do(ctx, callAPI, retry.WithExpectedLatency(300*time.Millisecond))
+func WithExpectedLatency(latency time.Duration) Option {
+	return func(o *Options) error {
+		if latency < 0 {
+			return fmt.Errorf("expectedLatency must be non-negative, got %v", latency)
+		}
+
+		o.ExpectedLatency = latency
+
+		return nil
+	}
+}
+
+// WithHTTPExpectedLatency returns an HTTPOption that sets the expected duration
+// of a single HTTP attempt used for deadline-aware retry truncation.
+// The value must be non-negative.
+//
+// Example:
+//
+//	resp, err := retry.DoHTTPRequest(ctx, client, req, retry.WithHTTPExpectedLatency(250*time.Millisecond))
+func WithHTTPExpectedLatency(latency time.Duration) HTTPOption {
+	return func(o *HTTPOptions) error {
+		if latency < 0 {
+			return fmt.Errorf("expectedLatency must be non-negative, got %v", latency)
+		}
+
+		o.ExpectedLatency = latency
+
+		return nil
+	}
+}
+
+// checkDeadline returns a DeadlineExceededError when waiting delay and then
+// running an attempt of expectedLatency would outlast the context deadline.
+// It returns nil when the context has no deadline.
+func checkDeadline(ctx context.Context, attempt int, delay, expectedLatency time.Duration, lastErr error) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+
+	remaining := time.Until(deadline)
+	if delay+expectedLatency < remaining {
+		return nil
+	}
+
+	return &DeadlineExceededError{
+		Attempt:         attempt,
+		Delay:           delay,
+		ExpectedLatency: expectedLatency,
+		Remaining:       remaining,
+		LastErr:         lastErr,
+	}
+}
@@ -0,0 +1,173 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestDo_DeadlineTruncation tests that a retry which cannot finish before the
+// context deadline is skipped with a typed error instead of sleeping.
+func TestDo_DeadlineTruncation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	callCount := 0
+	lastErr := errors.New("temporary error: connection refused")
+
+	fn := func() error {
+		callCount++
+		return lastErr
+	}
+
+	start := time.Now()
+	err := Do(ctx, fn,
+		WithMaxRetries(5),
+		WithInitialDelay(50*time.Millisecond),
+		WithJitterFactor(0),
+		WithExpectedLatency(80*time.Millisecond),
+	)
+	elapsed := time.Since(start)
+
+	if callCount != 1 {
+		t.Fatalf("Expected 1 call, got: %d", callCount)
+	}
+
+	if !errors.Is(err, ErrDeadlineWouldBeExceeded) {
+		t.Fatalf("Expected ErrDeadlineWouldBeExceeded, got: %v", err)
+	}
+
+	if !errors.Is(err, lastErr) {
+		t.Fatalf("Expected error to wrap the last attempt error, got: %v", err)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the error to match context.DeadlineExceeded, got: %v", err)
+	}
+
+	var deadlineErr *DeadlineExceededError
+	if !errors.As(err, &deadlineErr) {
+		t.Fatalf("Expected *DeadlineExceededError, got: %T", err)
+	}
+
+	if deadlineErr.Attempt != 0 || deadlineErr.Delay != 50*time.Millisecond || deadlineErr.ExpectedLatency != 80*time.Millisecond {
+		t.Fatalf("Unexpected error fields: %+v", deadlineErr)
+	}
+
+	if elapsed > 50*time.Millisecond {
+		t.Fatalf("Expected immediate return, took %v", elapsed)
+	}
+}
+
+// TestDo_DeadlineAllowsRetry tests that retries still happen when they fit
+// within the remaining deadline budget.
+func TestDo_DeadlineAllowsRetry(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	callCount := 0
+
+	fn := func() error {
+		callCount++
+		if callCount < 2 {
+			return errors.New("temporary error: connection refused")
+		}
+
+		return nil
+	}
+
+	err := Do(ctx, fn,
+		WithMaxRetries(3),
+		WithInitialDelay(1*time.Millisecond),
+		WithExpectedLatency(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if callCount != 2 {
+		t.Fatalf("Expected 2 calls, got: %d", callCount)
+	}
+}
+
+// TestDo_NoDeadline tests that contexts without a deadline are unaffected.
+func TestDo_NoDeadline(t *testing.T) {
+	callCount := 0
+
+	fn := func() error {
+		callCount++
+		return errors.New("temporary error: connection refused")
+	}
+
+	err := Do(context.Background(), fn,
+		WithMaxRetries(2),
+		WithInitialDelay(1*time.Millisecond),
+		WithExpectedLatency(time.Hour),
+	)
+
+	if IsDeadlineExceededError(err) {
+		t.Fatalf("Did not expect deadline error without a deadline, got: %v", err)
+	}
+
+	if callCount != 3 {
+		t.Fatalf("Expected 3 calls, got: %d", callCount)
+	}
+}
+
+// TestWithExpectedLatency_Validation tests option validation
+func TestWithExpectedLatency_Validation(t *testing.T) {
+	opts := DefaultOptions()
+	if err := WithExpectedLatency(-time.Second)(opts); err == nil {
+		t.Fatal("Expected error for negative latency")
+	}
+
+	if err := WithExpectedLatency(time.Second)(opts); err != nil || opts.ExpectedLatency != time.Second {
+		t.Fatalf("Expected latency to be set, got %v (err %v)", opts.ExpectedLatency, err)
+	}
+
+	httpOpts := DefaultHTTPOptions()
+	if err := WithHTTPExpectedLatency(-time.Second)(httpOpts); err == nil {
+		t.Fatal("Expected error for negative HTTP latency")
+	}
+
+	if err := WithHTTPExpectedLatency(time.Second)(httpOpts); err != nil || httpOpts.ExpectedLatency != time.Second {
+		t.Fatalf("Expected HTTP latency to be set, got %v (err %v)", httpOpts.ExpectedLatency, err)
+	}
+}
+
+// TestDoHTTPRequest_DeadlineTruncation tests deadline truncation on the HTTP path.
+func TestDoHTTPRequest_DeadlineTruncation(t *testing.T) {
+	callCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		callCount++
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	_, err = DoHTTPRequest(ctx, server.Client(), req,
+		WithHTTPMaxRetries(3),
+		WithHTTPInitialDelay(150*time.Millisecond),
+		WithHTTPJitterFactor(0),
+		WithHTTPExpectedLatency(100*time.Millisecond),
+	)
+
+	if !IsDeadlineExceededError(err) {
+		t.Fatalf("Expected deadline error, got: %v", err)
+	}
+
+	if callCount != 1 {
+		t.Fatalf("Expected 1 call, got: %d", callCount)
+	}
+}
@@ -59,6 +59,10 @@ type HTTPOptions struct {
 
 	// JitterFactor is the amount of jitter to add to the delay (0.0-1.0)
 	JitterFactor float64
+
+	// ExpectedLatency is the expected duration of a single attempt. A retry is
+	// skipped when its delay plus this latency would outlast the context deadline.
+	ExpectedLatency time.Duration
 }
 
 // DefaultHTTPOptions returns the default HTTP retry options.
@@ -561,6 +565,15 @@ func (r *httpRetryState) waitForRetry(attempt int) error {
 	})
 	delay = addJitter(delay, r.options.JitterFactor)
 
+	lastErr := r.lastErr
+	if lastErr == nil && r.lastStatusCode != 0 {
+		lastErr = fmt.Errorf("HTTP request failed with status %d", r.lastStatusCode)
+	}
+
+	if err := checkDeadline(r.ctx, attempt, delay, r.options.ExpectedLatency, lastErr); err != nil {
+		return err
+	}
+
 	// Use time.NewTimer instead of time.After to allow proper cleanup
 	// and avoid potential timer leaks when context is cancelled
 	timer := time.NewTimer(delay)
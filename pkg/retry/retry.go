@@ -61,6 +61,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/events"
 )
 
 // Options configures the retry behavior
@@ -90,6 +92,14 @@ type Options struct {
 
 	// JitterFactor is the amount of jitter to add to the delay (0.0-1.0)
 	JitterFactor float64
+
+	// ExpectedLatency is the expected duration of a single attempt. A retry is
+	// skipped when its delay plus this latency would outlast the context deadline.
+	ExpectedLatency time.Duration
+
+	// Events receives an events.RetryExhausted event when the retries are
+	// exhausted or skipped for lack of time before the context deadline
+	Events events.Sink
 }
 
 // DefaultRetryableErrors is a list of common error strings that should trigger a retry
@@ -302,6 +312,19 @@ func WithNoRetry() Option {
 	}
 }
 
+// WithEvents returns an Option that sends an events.RetryExhausted event to
+// sink each time the operation is given up on after retrying.
+//
+// Example:
+//
+//	err := retry.Do(ctx, submitPayment, retry.WithEvents(events.NewLogSink(log.Default())))
+func WithEvents(sink events.Sink) Option {
+	return func(o *Options) error {
+		o.Events = sink
+		return nil
+	}
+}
+
 // contextKey is a type for context keys specific to this package
 type contextKey string
 
@@ -418,6 +441,12 @@ func doWithOptions(ctx context.Context, fn func() error, options *Options) error
 		// Add jitter to avoid thundering herd
 		delayWithJitter := addJitter(delay, options.JitterFactor)
 
+		// Give up now if the retry cannot complete before the context deadline
+		if deadlineErr := checkDeadline(ctx, attempt, delayWithJitter, options.ExpectedLatency, err); deadlineErr != nil {
+			emitExhausted(ctx, options, attempt+1, "deadline", deadlineErr)
+			return deadlineErr
+		}
+
 		// Wait for the calculated delay or until context is done
 		timer := time.NewTimer(delayWithJitter)
 		select {
@@ -429,7 +458,28 @@ func doWithOptions(ctx context.Context, fn func() error, options *Options) error
 	}
 
 	// Return the last error
-	return fmt.Errorf("operation failed after %d retries: %w", options.MaxRetries, err)
+	err = fmt.Errorf("operation failed after %d retries: %w", options.MaxRetries, err)
+	emitExhausted(ctx, options, options.MaxRetries+1, "max_retries", err)
+
+	return err
+}
+
+// emitExhausted sends an events.RetryExhausted event when an events sink is configured.
+func emitExhausted(ctx context.Context, options *Options, attempts int, reason string, err error) {
+	if options.Events == nil || options.MaxRetries == 0 {
+		return
+	}
+
+	events.Emit(context.WithoutCancel(ctx), options.Events, events.Event{
+		Type:     events.RetryExhausted,
+		Severity: events.SeverityWarning,
+		Message:  fmt.Sprintf("operation given up after %d attempts: %v", attempts, err),
+		Attributes: map[string]any{
+			"attempts":    attempts,
+			"max_retries": options.MaxRetries,
+			"reason":      reason,
+		},
+	})
 }
 
 // IsRetryableError checks if an error is retryable based on the provided options
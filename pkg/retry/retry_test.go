@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/events"
 )
 
 // TestDo_Success tests successful execution with no retries
@@ -604,6 +606,38 @@ func TestHTTPOptionHelpers(t *testing.T) {
 	}
 }
 
+// TestDo_EmitsExhaustedEvent tests that giving up sends an event to the sink
+func TestDo_EmitsExhaustedEvent(t *testing.T) {
+	var received []events.Event
+
+	sink := events.SinkFunc(func(_ context.Context, e events.Event) error {
+		received = append(received, e)
+		return nil
+	})
+
+	err := Do(context.Background(), func() error { return errors.New("service unavailable") },
+		WithMaxRetries(2), WithInitialDelay(time.Millisecond), WithEvents(sink))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if len(received) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(received))
+	}
+
+	if received[0].Type != events.RetryExhausted || received[0].Attributes["attempts"] != 3 {
+		t.Errorf("unexpected event: %+v", received[0])
+	}
+
+	// Errors that are not retried don't exhaust any budget
+	_ = Do(context.Background(), func() error { return errors.New("invalid input") },
+		WithInitialDelay(time.Millisecond), WithEvents(sink))
+
+	if len(received) != 1 {
+		t.Errorf("expected no event for a non-retryable error, got %d events", len(received))
+	}
+}
+
 // mockHTTPError is a mock error that implements StatusCode() for testing
 type mockHTTPError struct {
 	statusCode int
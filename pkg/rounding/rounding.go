@@ -0,0 +1,157 @@
+// Package rounding decides what happens to amounts with more decimal places
+// than their asset scale allows: they are rejected, rounded half to even or
+// truncated, as selected by a Policy.
+package rounding
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Policy selects how amounts exceeding an asset scale are handled.
+type Policy string
+
+const (
+	// PolicyError rejects amounts with more decimal places than the scale.
+	// It is the policy of an empty Policy.
+	PolicyError Policy = "error"
+
+	// PolicyHalfEven rounds amounts to the scale, halves going to the even
+	// digit (banker's rounding), so rounding many amounts adds no bias
+	PolicyHalfEven Policy = "half_even"
+
+	// PolicyTruncate drops the decimal places beyond the scale, rounding
+	// towards zero
+	PolicyTruncate Policy = "truncate"
+)
+
+// IsValid reports whether p is one of the known policies.
+func (p Policy) IsValid() bool {
+	switch p {
+	case PolicyError, PolicyHalfEven, PolicyTruncate:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParsePolicy parses a policy name, ignoring case. Dashes are accepted in
+// place of underscores, and half_even may be spelled round_half_even.
+//
+// Example:
+//
+//	policy, err := rounding.ParsePolicy(os.Getenv("ROUNDING_POLICY"))
+func ParsePolicy(s string) (Policy, error) {
+	name := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "-", "_")
+	if name == "round_half_even" {
+		name = string(PolicyHalfEven)
+	}
+
+	if p := Policy(name); p.IsValid() {
+		return p, nil
+	}
+
+	return "", fmt.Errorf("invalid rounding policy %q: must be one of error, half_even, truncate", s)
+}
+
+// ErrExceedsScale is the sentinel matched by errors.Is for a ScaleError.
+var ErrExceedsScale = errors.New("amount exceeds asset scale")
+
+// ScaleError is returned under PolicyError for an amount with more decimal
+// places than its scale allows.
+type ScaleError struct {
+	// Amount is the rejected amount
+	Amount string
+
+	// Places is the number of significant decimal places of Amount
+	Places int32
+
+	// Scale is the number of decimal places allowed
+	Scale int32
+}
+
+// Error implements the error interface.
+func (e *ScaleError) Error() string {
+	return fmt.Sprintf("%s: %s has %d decimal places, scale is %d", ErrExceedsScale, e.Amount, e.Places, e.Scale)
+}
+
+// Is reports whether target is ErrExceedsScale.
+func (*ScaleError) Is(target error) bool {
+	return target == ErrExceedsScale
+}
+
+// Apply returns amount at scale decimal places under policy p. Amounts that
+// fit the scale are returned unchanged whatever the policy; trailing zeros
+// don't count as decimal places.
+//
+// Example:
+//
+//	value, err := rounding.PolicyHalfEven.Apply(decimal.RequireFromString("10.125"), 2) // 10.12
+func (p Policy) Apply(amount decimal.Decimal, scale int32) (decimal.Decimal, error) {
+	if scale < 0 {
+		return decimal.Zero, fmt.Errorf("scale must be non-negative, got %d", scale)
+	}
+
+	places := Places(amount)
+	if places <= scale {
+		return amount, nil
+	}
+
+	switch p {
+	case PolicyHalfEven:
+		return amount.RoundBank(scale), nil
+	case PolicyTruncate:
+		return amount.Truncate(scale), nil
+	case PolicyError, "":
+		return decimal.Zero, &ScaleError{Amount: amount.String(), Places: places, Scale: scale}
+	default:
+		return decimal.Zero, fmt.Errorf("invalid rounding policy %q", p)
+	}
+}
+
+// Format applies p to amount and formats the result with exactly scale
+// decimal places, as amounts are sent to the Midaz API.
+func (p Policy) Format(amount decimal.Decimal, scale int32) (string, error) {
+	value, err := p.Apply(amount, scale)
+	if err != nil {
+		return "", err
+	}
+
+	return value.StringFixed(scale), nil
+}
+
+// Minor applies p to amount and returns it in minor units of the scale,
+// e.g. 1050 for 10.50 at scale 2.
+func (p Policy) Minor(amount decimal.Decimal, scale int32) (int64, error) {
+	value, err := p.Apply(amount, scale)
+	if err != nil {
+		return 0, err
+	}
+
+	minor := value.Shift(scale)
+	if minor.GreaterThan(decimal.NewFromInt(math.MaxInt64)) || minor.LessThan(decimal.NewFromInt(math.MinInt64)) {
+		return 0, fmt.Errorf("amount %s overflows minor units at scale %d", amount, scale)
+	}
+
+	return minor.IntPart(), nil
+}
+
+// Places returns the number of significant decimal places of amount,
+// ignoring trailing zeros.
+func Places(amount decimal.Decimal) int32 {
+	exp := amount.Exponent()
+	if exp >= 0 || amount.IsZero() {
+		return 0
+	}
+
+	places := -exp
+	for places > 0 && amount.Truncate(places-1).Equal(amount) {
+		places--
+	}
+
+	return places
+}
@@ -0,0 +1,104 @@
+package rounding
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := map[string]Policy{
+		"error":           PolicyError,
+		"HALF_EVEN":       PolicyHalfEven,
+		"half-even":       PolicyHalfEven,
+		"round-half-even": PolicyHalfEven,
+		" truncate ":      PolicyTruncate,
+	}
+
+	for input, expected := range tests {
+		policy, err := ParsePolicy(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, policy, input)
+	}
+
+	_, err := ParsePolicy("ceiling")
+	require.Error(t, err)
+
+	_, err = ParsePolicy("")
+	require.Error(t, err)
+}
+
+func TestPolicyApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   Policy
+		amount   string
+		scale    int32
+		expected string
+	}{
+		{"fits scale", PolicyError, "10.5", 2, "10.5"},
+		{"trailing zeros fit", PolicyError, "10.5000", 2, "10.5"},
+		{"half even to even", PolicyHalfEven, "10.125", 2, "10.12"},
+		{"half even to odd", PolicyHalfEven, "10.135", 2, "10.14"},
+		{"half even above half", PolicyHalfEven, "10.1251", 2, "10.13"},
+		{"truncate", PolicyTruncate, "10.129", 2, "10.12"},
+		{"truncate negative", PolicyTruncate, "-10.129", 2, "-10.12"},
+		{"scale zero", PolicyHalfEven, "2.5", 0, "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.policy.Apply(decimal.RequireFromString(tt.amount), tt.scale)
+			require.NoError(t, err)
+			assert.True(t, decimal.RequireFromString(tt.expected).Equal(value), "got %s", value)
+		})
+	}
+}
+
+func TestPolicyApply_Errors(t *testing.T) {
+	for _, policy := range []Policy{PolicyError, ""} {
+		_, err := policy.Apply(decimal.RequireFromString("10.125"), 2)
+		require.ErrorIs(t, err, ErrExceedsScale)
+
+		var scaleErr *ScaleError
+		require.True(t, errors.As(err, &scaleErr))
+		assert.Equal(t, "10.125", scaleErr.Amount)
+		assert.Equal(t, int32(3), scaleErr.Places)
+		assert.Equal(t, int32(2), scaleErr.Scale)
+	}
+
+	_, err := PolicyTruncate.Apply(decimal.RequireFromString("1"), -1)
+	require.Error(t, err)
+
+	_, err = Policy("ceiling").Apply(decimal.RequireFromString("10.125"), 2)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrExceedsScale)
+}
+
+func TestPolicyFormatAndMinor(t *testing.T) {
+	formatted, err := PolicyHalfEven.Format(decimal.RequireFromString("3.14159"), 4)
+	require.NoError(t, err)
+	assert.Equal(t, "3.1416", formatted)
+
+	formatted, err = PolicyError.Format(decimal.RequireFromString("7"), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "7.00", formatted)
+
+	minor, err := PolicyTruncate.Minor(decimal.RequireFromString("10.509"), 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1050), minor)
+
+	_, err = PolicyTruncate.Minor(decimal.RequireFromString("1e30"), 2)
+	require.Error(t, err)
+}
+
+func TestPlaces(t *testing.T) {
+	assert.Equal(t, int32(0), Places(decimal.RequireFromString("100")))
+	assert.Equal(t, int32(0), Places(decimal.RequireFromString("1.000")))
+	assert.Equal(t, int32(0), Places(decimal.RequireFromString("0.00")))
+	assert.Equal(t, int32(2), Places(decimal.RequireFromString("1.050")))
+	assert.Equal(t, int32(8), Places(decimal.RequireFromString("0.00000001")))
+}
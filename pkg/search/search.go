@@ -0,0 +1,309 @@
+// Package search finds the accounts, transactions and portfolios tagged with
+// metadata across the ledgers of an organization, such as everything tagged
+// invoice=INV-123, in one call.
+//
+// A search lists each kind of entity of each ledger with the metadata as a
+// filter, concurrently, and merges the results by kind. The metadata is also
+// checked on the items returned, so a backend ignoring a filter doesn't widen
+// the results.
+//
+// Example:
+//
+//	results, err := search.NewSearcher(client.Entity).Search(ctx, search.Query{
+//	    OrganizationID: orgID,
+//	    Metadata:       map[string]string{"invoice": "INV-123"},
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//
+//	for _, tx := range results.Transactions {
+//	    fmt.Println(tx.LedgerID, tx.ID)
+//	}
+package search
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// DefaultConcurrency is the number of list calls a Searcher runs at once
+// unless set with WithConcurrency.
+const DefaultConcurrency = 8
+
+// Kind is a kind of entity searched.
+type Kind string
+
+const (
+	KindAccount     Kind = "account"
+	KindTransaction Kind = "transaction"
+	KindPortfolio   Kind = "portfolio"
+)
+
+// AllKinds are the kinds searched by a Query without Kinds.
+var AllKinds = []Kind{KindAccount, KindTransaction, KindPortfolio}
+
+// Query selects the entities to find.
+type Query struct {
+	OrganizationID string
+
+	// LedgerIDs are the ledgers searched; empty searches every ledger of
+	// the organization
+	LedgerIDs []string
+
+	// Metadata are the metadata values the entities must all have. Required
+	Metadata map[string]string
+
+	// Kinds are the kinds of entity searched; empty searches AllKinds
+	Kinds []Kind
+
+	// Limit caps the entities of each kind found in each ledger; zero finds
+	// them all
+	Limit int
+}
+
+// Results are the entities found, by kind, ledger by ledger in the order of
+// Query.LedgerIDs or of the ledger list, and within a ledger in the order
+// the API lists them.
+type Results struct {
+	Accounts     []models.Account
+	Transactions []models.Transaction
+	Portfolios   []models.Portfolio
+}
+
+// Len returns the number of entities found.
+func (r *Results) Len() int {
+	return len(r.Accounts) + len(r.Transactions) + len(r.Portfolios)
+}
+
+// Searcher runs searches through the services of an Entity.
+type Searcher struct {
+	e           *entities.Entity
+	concurrency int
+}
+
+// NewSearcher creates a Searcher running DefaultConcurrency list calls at once.
+func NewSearcher(e *entities.Entity) *Searcher {
+	return &Searcher{e: e, concurrency: DefaultConcurrency}
+}
+
+// WithConcurrency sets the number of list calls run at once, from 1.
+func (s *Searcher) WithConcurrency(n int) *Searcher {
+	if n > 0 {
+		s.concurrency = n
+	}
+
+	return s
+}
+
+// ledgerResults are the entities found in a ledger.
+type ledgerResults struct {
+	accounts     []models.Account
+	transactions []models.Transaction
+	portfolios   []models.Portfolio
+}
+
+// Search finds the entities matching q. It returns an error, and no results,
+// if q is invalid or a list call fails; the other calls are then cancelled.
+func (s *Searcher) Search(ctx context.Context, q Query) (*Results, error) {
+	if err := s.check(q); err != nil {
+		return nil, err
+	}
+
+	kinds := q.Kinds
+	if len(kinds) == 0 {
+		kinds = AllKinds
+	}
+
+	ledgerIDs := q.LedgerIDs
+	if len(ledgerIDs) == 0 {
+		ledgers, err := listAll(ctx, models.ListLedgers().Limit(models.MaxLimit).Options(), 0, nil,
+			func(opts *models.ListOptions) (*models.ListResponse[models.Ledger], error) {
+				return s.e.Ledgers.ListLedgers(ctx, q.OrganizationID, opts)
+			})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the ledgers of organization %s: %w", q.OrganizationID, err)
+		}
+
+		for _, l := range ledgers {
+			ledgerIDs = append(ledgerIDs, l.ID)
+		}
+	}
+
+	found := make([]ledgerResults, len(ledgerIDs))
+
+	g, gctx := concurrent.NewGroup(ctx)
+	g.SetLimit(s.concurrency)
+
+	for i, ledgerID := range ledgerIDs {
+		for _, kind := range kinds {
+			g.Go(func() error {
+				return s.searchKind(gctx, q, ledgerID, kind, &found[i])
+			})
+		}
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	results := &Results{}
+
+	for _, r := range found {
+		results.Accounts = append(results.Accounts, r.accounts...)
+		results.Transactions = append(results.Transactions, r.transactions...)
+		results.Portfolios = append(results.Portfolios, r.portfolios...)
+	}
+
+	return results, nil
+}
+
+// check validates the query against the services of the Entity.
+func (s *Searcher) check(q Query) error {
+	if q.OrganizationID == "" {
+		return errors.NewMissingParameterError("Search", "organizationID")
+	}
+
+	if len(q.Metadata) == 0 {
+		return errors.NewMissingParameterError("Search", "metadata")
+	}
+
+	for _, kind := range q.Kinds {
+		if !slices.Contains(AllKinds, kind) {
+			return errors.NewInvalidInputError("Search", fmt.Errorf("unknown kind %q", kind))
+		}
+	}
+
+	if len(q.LedgerIDs) == 0 && s.e.Ledgers == nil {
+		return errors.NewMissingParameterError("Search", "ledgers service")
+	}
+
+	return nil
+}
+
+// searchKind lists the entities of a kind of a ledger matching q into r. Each
+// call of the search writes a different field of r.
+func (s *Searcher) searchKind(ctx context.Context, q Query, ledgerID string, kind Kind, r *ledgerResults) error {
+	var err error
+
+	switch kind {
+	case KindAccount:
+		if s.e.Accounts == nil {
+			return errors.NewMissingParameterError("Search", "accounts service")
+		}
+
+		query := models.ListAccounts().Limit(models.MaxLimit)
+		for k, v := range q.Metadata {
+			query.FilterMetadata(k, v)
+		}
+
+		r.accounts, err = listAll(ctx, query.Options(), q.Limit,
+			func(a models.Account) map[string]any { return a.Metadata },
+			func(opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+				return s.e.Accounts.ListAccounts(ctx, q.OrganizationID, ledgerID, opts)
+			})
+	case KindTransaction:
+		if s.e.Transactions == nil {
+			return errors.NewMissingParameterError("Search", "transactions service")
+		}
+
+		query := models.ListTransactions().Limit(models.MaxLimit)
+		for k, v := range q.Metadata {
+			query.FilterMetadata(k, v)
+		}
+
+		r.transactions, err = listAll(ctx, query.Options(), q.Limit,
+			func(t models.Transaction) map[string]any { return t.Metadata },
+			func(opts *models.ListOptions) (*models.ListResponse[models.Transaction], error) {
+				return s.e.Transactions.ListTransactions(ctx, q.OrganizationID, ledgerID, opts)
+			})
+	case KindPortfolio:
+		if s.e.Portfolios == nil {
+			return errors.NewMissingParameterError("Search", "portfolios service")
+		}
+
+		query := models.ListPortfolios().Limit(models.MaxLimit)
+		for k, v := range q.Metadata {
+			query.FilterMetadata(k, v)
+		}
+
+		r.portfolios, err = listAll(ctx, query.Options(), q.Limit,
+			func(p models.Portfolio) map[string]any { return p.Metadata },
+			func(opts *models.ListOptions) (*models.ListResponse[models.Portfolio], error) {
+				return s.e.Portfolios.ListPortfolios(ctx, q.OrganizationID, ledgerID, opts)
+			})
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to search the %ss of ledger %s: %w", kind, ledgerID, err)
+	}
+
+	return nil
+}
+
+// listAll returns the items of every page from opts on whose metadata, read
+// by metadata unless nil, matches the filters of opts, up to limit items
+// unless zero. The filters are carried to the next pages.
+func listAll[T any](ctx context.Context, opts *models.ListOptions, limit int, metadata func(T) map[string]any, list func(*models.ListOptions) (*models.ListResponse[T], error)) ([]T, error) {
+	filters := opts.Filters
+
+	var items []T
+
+	for opts != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, err := list(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			if metadata != nil && !matches(metadata(item), filters) {
+				continue
+			}
+
+			items = append(items, item)
+
+			if limit > 0 && len(items) == limit {
+				return items, nil
+			}
+		}
+
+		if len(page.Items) == 0 {
+			break
+		}
+
+		opts = page.Pagination.NextPageOptions()
+		if opts != nil {
+			opts.WithFilters(filters)
+		}
+	}
+
+	return items, nil
+}
+
+// matches reports whether metadata has the values of the metadata filters.
+func matches(metadata map[string]any, filters map[string]string) bool {
+	for field, want := range filters {
+		key, ok := strings.CutPrefix(field, "metadata.")
+		if !ok {
+			continue
+		}
+
+		got, ok := metadata[key]
+		if !ok || fmt.Sprint(got) != want {
+			return false
+		}
+	}
+
+	return true
+}
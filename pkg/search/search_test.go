@@ -0,0 +1,150 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// page returns the items of ledgerID matching the metadata filters of opts,
+// MaxLimit at a time; unfiltered tells it to ignore the filters.
+func page[T any](items map[string][]T, ledgerID string, opts *models.ListOptions, metadata func(T) map[string]any, unfiltered bool) *models.ListResponse[T] {
+	var matched []T
+
+	for _, item := range items[ledgerID] {
+		if unfiltered || matches(metadata(item), opts.Filters) {
+			matched = append(matched, item)
+		}
+	}
+
+	return &models.ListResponse[T]{Items: matched, Pagination: models.Pagination{Limit: opts.Limit, Total: len(matched)}}
+}
+
+type fakeLedgers struct {
+	entities.LedgersService
+}
+
+func (fakeLedgers) ListLedgers(_ context.Context, _ string, opts *models.ListOptions) (*models.ListResponse[models.Ledger], error) {
+	return &models.ListResponse[models.Ledger]{
+		Items:      []models.Ledger{{ID: "ledger-1"}, {ID: "ledger-2"}},
+		Pagination: models.Pagination{Limit: opts.Limit, Total: 2},
+	}, nil
+}
+
+type fakeAccounts struct {
+	entities.AccountsService
+	items map[string][]models.Account
+	calls atomic.Int32
+}
+
+func (f *fakeAccounts) ListAccounts(_ context.Context, _, ledgerID string, opts *models.ListOptions) (*models.ListResponse[models.Account], error) {
+	f.calls.Add(1)
+	return page(f.items, ledgerID, opts, func(a models.Account) map[string]any { return a.Metadata }, true), nil
+}
+
+type fakeTransactions struct {
+	entities.TransactionsService
+	items map[string][]models.Transaction
+	err   error
+}
+
+func (f *fakeTransactions) ListTransactions(_ context.Context, _, ledgerID string, opts *models.ListOptions) (*models.ListResponse[models.Transaction], error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return page(f.items, ledgerID, opts, func(t models.Transaction) map[string]any { return t.Metadata }, false), nil
+}
+
+type fakePortfolios struct {
+	entities.PortfoliosService
+	items map[string][]models.Portfolio
+}
+
+func (f *fakePortfolios) ListPortfolios(_ context.Context, _, ledgerID string, opts *models.ListOptions) (*models.ListResponse[models.Portfolio], error) {
+	return page(f.items, ledgerID, opts, func(p models.Portfolio) map[string]any { return p.Metadata }, false), nil
+}
+
+func newTestEntity() (*entities.Entity, *fakeAccounts, *fakeTransactions) {
+	invoice := map[string]any{"invoice": "INV-123", "amount": 10}
+	other := map[string]any{"invoice": "INV-999"}
+
+	accounts := &fakeAccounts{items: map[string][]models.Account{
+		"ledger-1": {{ID: "acc-1", Metadata: invoice}, {ID: "acc-2", Metadata: other}},
+		"ledger-2": {{ID: "acc-3", Metadata: invoice}},
+	}}
+	transactions := &fakeTransactions{items: map[string][]models.Transaction{
+		"ledger-2": {{ID: "tx-1", Metadata: invoice}, {ID: "tx-2"}},
+	}}
+	portfolios := &fakePortfolios{items: map[string][]models.Portfolio{
+		"ledger-1": {{ID: "pf-1", Metadata: invoice}},
+	}}
+
+	return &entities.Entity{
+		Ledgers:      fakeLedgers{},
+		Accounts:     accounts,
+		Transactions: transactions,
+		Portfolios:   portfolios,
+	}, accounts, transactions
+}
+
+func ids[T any](items []T, id func(T) string) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = id(item)
+	}
+
+	return out
+}
+
+func TestSearch(t *testing.T) {
+	e, accounts, _ := newTestEntity()
+
+	results, err := NewSearcher(e).Search(context.Background(), Query{
+		OrganizationID: "org",
+		Metadata:       map[string]string{"invoice": "INV-123"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"acc-1", "acc-3"}, ids(results.Accounts, func(a models.Account) string { return a.ID }),
+		"the metadata is checked on the items returned, in ledger order")
+	assert.Equal(t, []string{"tx-1"}, ids(results.Transactions, func(tx models.Transaction) string { return tx.ID }))
+	assert.Equal(t, []string{"pf-1"}, ids(results.Portfolios, func(p models.Portfolio) string { return p.ID }))
+	assert.Equal(t, 4, results.Len())
+	assert.Equal(t, int32(2), accounts.calls.Load())
+
+	results, err = NewSearcher(e).WithConcurrency(1).Search(context.Background(), Query{
+		OrganizationID: "org",
+		LedgerIDs:      []string{"ledger-1"},
+		Metadata:       map[string]string{"invoice": "INV-123", "amount": "10"},
+		Kinds:          []Kind{KindAccount},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acc-1"}, ids(results.Accounts, func(a models.Account) string { return a.ID }))
+	assert.Empty(t, results.Transactions)
+}
+
+func TestSearch_Errors(t *testing.T) {
+	e, _, transactions := newTestEntity()
+	s := NewSearcher(e)
+
+	_, err := s.Search(context.Background(), Query{OrganizationID: "org"})
+	assert.True(t, sdkerrors.IsValidationError(err), "metadata is required")
+
+	_, err = s.Search(context.Background(), Query{OrganizationID: "org", Metadata: map[string]string{"a": "b"}, Kinds: []Kind{"segment"}})
+	assert.True(t, sdkerrors.IsValidationError(err))
+
+	transactions.err = errors.New("unavailable")
+
+	results, err := s.Search(context.Background(), Query{OrganizationID: "org", Metadata: map[string]string{"invoice": "INV-123"}})
+	require.ErrorIs(t, err, transactions.err)
+	assert.Nil(t, results)
+	assert.Contains(t, err.Error(), "transactions of ledger")
+}
@@ -0,0 +1,759 @@
+package smoketest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Amounts moved by the transactions capability: a deposit into the source
+// account, then a transfer from it to the destination account
+const (
+	depositAmount  = "100"
+	transferAmount = "40"
+)
+
+// balancePolls bounds the reads of a balance waiting for a transaction to
+// be applied, balancePollInterval apart
+const (
+	balancePolls        = 10
+	balancePollInterval = 300 * time.Millisecond
+)
+
+// capabilitySpec describes a capability: the capabilities it depends on,
+// the resources it needs from them, and its checks
+type capabilitySpec struct {
+	name      string
+	dependsOn []string
+	needs     []string
+	run       func(r *runner, ctx context.Context, result *CapabilityResult)
+}
+
+// capabilitySpecs lists the capabilities in run order
+var capabilitySpecs = []capabilitySpec{
+	{name: CapabilityOrganizations, run: (*runner).organizations},
+	{name: CapabilityLedgers, dependsOn: []string{CapabilityOrganizations}, needs: []string{"organization"}, run: (*runner).ledgers},
+	{name: CapabilityAssets, dependsOn: []string{CapabilityLedgers}, needs: []string{"ledger"}, run: (*runner).assets},
+	{name: CapabilityAccountTypes, dependsOn: []string{CapabilityLedgers}, needs: []string{"ledger"}, run: (*runner).accountTypes},
+	{name: CapabilityAccounts, dependsOn: []string{CapabilityAssets}, needs: []string{"asset"}, run: (*runner).accounts},
+	{name: CapabilityPortfolios, dependsOn: []string{CapabilityLedgers}, needs: []string{"ledger"}, run: (*runner).portfolios},
+	{name: CapabilitySegments, dependsOn: []string{CapabilityLedgers}, needs: []string{"ledger"}, run: (*runner).segments},
+	{name: CapabilityOperationRoutes, dependsOn: []string{CapabilityLedgers}, needs: []string{"ledger"}, run: (*runner).operationRoutes},
+	{name: CapabilityTransactionRoutes, dependsOn: []string{CapabilityOperationRoutes}, needs: []string{"operation routes"}, run: (*runner).transactionRoutes},
+	{name: CapabilityTransactions, dependsOn: []string{CapabilityAccounts}, needs: []string{"accounts"}, run: (*runner).transactions},
+	{name: CapabilityOperations, dependsOn: []string{CapabilityTransactions}, needs: []string{"transaction"}, run: (*runner).operations},
+	{name: CapabilityBalances, dependsOn: []string{CapabilityTransactions}, needs: []string{"transaction"}, run: (*runner).balances},
+}
+
+// cleanupStep deletes a resource created by the test
+type cleanupStep struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// runner holds the resources created by a smoke test
+type runner struct {
+	c      *client.Client
+	opts   *Options
+	suffix string
+
+	orgID, ledgerID     string
+	assetCreated        bool
+	source, destination *models.Account
+	operationRouteIDs   []string
+	transactionID       string
+	cleanups            []cleanupStep
+}
+
+func newRunner(c *client.Client, opts *Options) *runner {
+	return &runner{c: c, opts: opts, suffix: uuid.NewString()[:8]}
+}
+
+// missing returns the first of the resources that was not created
+func (r *runner) missing(needs []string) string {
+	for _, need := range needs {
+		var ok bool
+
+		switch need {
+		case "organization":
+			ok = r.orgID != ""
+		case "ledger":
+			ok = r.ledgerID != ""
+		case "asset":
+			ok = r.assetCreated
+		case "accounts":
+			ok = r.source != nil && r.destination != nil
+		case "operation routes":
+			ok = len(r.operationRouteIDs) > 0
+		case "transaction":
+			ok = r.transactionID != ""
+		}
+
+		if !ok {
+			return "a created " + need
+		}
+	}
+
+	return ""
+}
+
+// check runs fn as a check of result, bounded by the check timeout, and
+// reports whether it passed
+func (r *runner) check(ctx context.Context, result *CapabilityResult, name string, fn func(ctx context.Context) error) bool {
+	checkCtx, cancel := ctx, context.CancelFunc(func() {})
+	if r.opts.CheckTimeout > 0 {
+		checkCtx, cancel = context.WithTimeout(ctx, r.opts.CheckTimeout)
+	}
+
+	start := time.Now()
+	err := fn(checkCtx)
+
+	cancel()
+
+	check := Check{Name: name, Status: StatusPassed, Duration: time.Since(start)}
+	if err != nil {
+		check.Status, check.Error = StatusFailed, err.Error()
+
+		var apiErr *sdkerrors.Error
+		if errors.As(err, &apiErr) {
+			check.StatusCode = apiErr.StatusCode
+		}
+	}
+
+	r.record(result, check)
+
+	return err == nil
+}
+
+// skip records checks not run because an earlier check failed
+func (r *runner) skip(result *CapabilityResult, names ...string) {
+	for _, name := range names {
+		r.record(result, Check{Name: name, Status: StatusSkipped, Error: "not run after a failed check"})
+	}
+}
+
+func (r *runner) record(result *CapabilityResult, check Check) {
+	result.Checks = append(result.Checks, check)
+
+	if r.opts.OnCheck != nil {
+		r.opts.OnCheck(result.Name, check)
+	}
+}
+
+// onCleanup registers the deletion of a created resource
+func (r *runner) onCleanup(name string, fn func(ctx context.Context) error) {
+	r.cleanups = append(r.cleanups, cleanupStep{name: name, fn: fn})
+}
+
+// cleanup deletes the created resources, newest first
+func (r *runner) cleanup(ctx context.Context) []Check {
+	if !r.opts.Cleanup {
+		return nil
+	}
+
+	result := &CapabilityResult{Name: "cleanup"}
+
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.check(ctx, result, r.cleanups[i].name, r.cleanups[i].fn)
+	}
+
+	return result.Checks
+}
+
+// metadata tags the resources created by the test
+func (r *runner) metadata() map[string]any {
+	return map[string]any{"smokeTest": r.suffix}
+}
+
+func (r *runner) organizations(ctx context.Context, result *CapabilityResult) {
+	service := r.c.Entity.Organizations
+
+	created := r.check(ctx, result, "create", func(ctx context.Context) error {
+		// a legal document unique to the run, as organizations may not share one
+		legalDocument := strconv.FormatInt(time.Now().UnixNano()%1e14, 10)
+
+		org, err := service.CreateOrganization(ctx, models.NewCreateOrganizationInput("Smoke Test "+r.suffix).
+			WithLegalDocument(legalDocument).
+			WithAddress(models.Address{Country: "US"}).
+			WithStatus(models.NewStatus("ACTIVE")).
+			WithMetadata(r.metadata()))
+		if err != nil {
+			return err
+		}
+
+		if org.ID == "" {
+			return errors.New("no ID returned")
+		}
+
+		r.orgID = org.ID
+		r.onCleanup("delete organization", func(ctx context.Context) error {
+			return service.DeleteOrganization(ctx, org.ID)
+		})
+
+		return nil
+	})
+	if !created {
+		r.skip(result, "get", "list", "update")
+		return
+	}
+
+	r.check(ctx, result, "get", func(ctx context.Context) error {
+		org, err := service.GetOrganization(ctx, r.orgID)
+		if err != nil {
+			return err
+		}
+
+		return expectID(org.ID, r.orgID)
+	})
+
+	r.check(ctx, result, "list", func(ctx context.Context) error {
+		_, err := service.ListOrganizations(ctx, models.NewListOptions().WithLimit(10))
+		return err
+	})
+
+	r.check(ctx, result, "update", func(ctx context.Context) error {
+		_, err := service.UpdateOrganization(ctx, r.orgID,
+			models.NewUpdateOrganizationInput().WithUpdateMetadata(map[string]any{"smokeTest": r.suffix, "updated": true}))
+
+		return err
+	})
+}
+
+func (r *runner) ledgers(ctx context.Context, result *CapabilityResult) {
+	service := r.c.Entity.Ledgers
+
+	created := r.check(ctx, result, "create", func(ctx context.Context) error {
+		ledger, err := service.CreateLedger(ctx, r.orgID, models.NewCreateLedgerInput("Smoke Test Ledger").WithMetadata(r.metadata()))
+		if err != nil {
+			return err
+		}
+
+		if ledger.ID == "" {
+			return errors.New("no ID returned")
+		}
+
+		r.ledgerID = ledger.ID
+		r.onCleanup("delete ledger", func(ctx context.Context) error {
+			return service.DeleteLedger(ctx, r.orgID, ledger.ID)
+		})
+
+		return nil
+	})
+	if !created {
+		r.skip(result, "get", "list", "update")
+		return
+	}
+
+	r.check(ctx, result, "get", func(ctx context.Context) error {
+		ledger, err := service.GetLedger(ctx, r.orgID, r.ledgerID)
+		if err != nil {
+			return err
+		}
+
+		return expectID(ledger.ID, r.ledgerID)
+	})
+
+	r.check(ctx, result, "list", func(ctx context.Context) error {
+		return expectListed(service.ListLedgers(ctx, r.orgID, nil))
+	})
+
+	r.check(ctx, result, "update", func(ctx context.Context) error {
+		_, err := service.UpdateLedger(ctx, r.orgID, r.ledgerID,
+			models.NewUpdateLedgerInput().WithMetadata(map[string]any{"smokeTest": r.suffix, "updated": true}))
+
+		return err
+	})
+}
+
+func (r *runner) assets(ctx context.Context, result *CapabilityResult) {
+	service := r.c.Entity.Assets
+
+	var assetID string
+
+	created := r.check(ctx, result, "create", func(ctx context.Context) error {
+		asset, err := service.CreateAsset(ctx, r.orgID, r.ledgerID,
+			models.NewCreateAssetInput("Smoke Test "+r.opts.AssetCode, r.opts.AssetCode).WithType("currency").WithMetadata(r.metadata()))
+		if err != nil {
+			return err
+		}
+
+		assetID, r.assetCreated = asset.ID, true
+		r.onCleanup("delete asset", func(ctx context.Context) error {
+			return service.DeleteAsset(ctx, r.orgID, r.ledgerID, asset.ID)
+		})
+
+		return nil
+	})
+	if !created {
+		r.skip(result, "get", "list")
+		return
+	}
+
+	r.check(ctx, result, "get", func(ctx context.Context) error {
+		asset, err := service.GetAsset(ctx, r.orgID, r.ledgerID, assetID)
+		if err != nil {
+			return err
+		}
+
+		if asset.Code != r.opts.AssetCode {
+			return fmt.Errorf("expected asset code %s, got %s", r.opts.AssetCode, asset.Code)
+		}
+
+		return nil
+	})
+
+	r.check(ctx, result, "list", func(ctx context.Context) error {
+		return expectListed(service.ListAssets(ctx, r.orgID, r.ledgerID, nil))
+	})
+}
+
+func (r *runner) accountTypes(ctx context.Context, result *CapabilityResult) {
+	service := r.c.Entity.AccountTypes
+
+	var accountTypeID string
+
+	created := r.check(ctx, result, "create", func(ctx context.Context) error {
+		accountType, err := service.CreateAccountType(ctx, r.orgID, r.ledgerID,
+			models.NewCreateAccountTypeInput("Smoke Test", "SMOKE_"+r.suffix).WithMetadata(r.metadata()))
+		if err != nil {
+			return err
+		}
+
+		accountTypeID = accountType.ID.String()
+		r.onCleanup("delete account type", func(ctx context.Context) error {
+			return service.DeleteAccountType(ctx, r.orgID, r.ledgerID, accountTypeID)
+		})
+
+		return nil
+	})
+	if !created {
+		r.skip(result, "get", "list")
+		return
+	}
+
+	r.check(ctx, result, "get", func(ctx context.Context) error {
+		accountType, err := service.GetAccountType(ctx, r.orgID, r.ledgerID, accountTypeID)
+		if err != nil {
+			return err
+		}
+
+		return expectID(accountType.ID.String(), accountTypeID)
+	})
+
+	r.check(ctx, result, "list", func(ctx context.Context) error {
+		return expectListed(service.ListAccountTypes(ctx, r.orgID, r.ledgerID, nil))
+	})
+}
+
+func (r *runner) accounts(ctx context.Context, result *CapabilityResult) {
+	service := r.c.Entity.Accounts
+
+	create := func(ctx context.Context, role string) (*models.Account, error) {
+		account, err := service.CreateAccount(ctx, r.orgID, r.ledgerID,
+			models.NewCreateAccountInput("Smoke Test "+role, r.opts.AssetCode, "deposit").
+				WithAlias("smoke-"+r.suffix+"-"+role).
+				WithMetadata(r.metadata()))
+		if err != nil {
+			return nil, err
+		}
+
+		if account.ID == "" {
+			return nil, errors.New("no ID returned")
+		}
+
+		r.onCleanup("delete account "+role, func(ctx context.Context) error {
+			return service.DeleteAccount(ctx, r.orgID, r.ledgerID, account.ID)
+		})
+
+		return account, nil
+	}
+
+	created := r.check(ctx, result, "create", func(ctx context.Context) error {
+		source, err := create(ctx, "source")
+		if err != nil {
+			return err
+		}
+
+		destination, err := create(ctx, "destination")
+		if err != nil {
+			return err
+		}
+
+		r.source, r.destination = source, destination
+
+		return nil
+	})
+	if !created {
+		r.skip(result, "get", "get by alias", "list", "update")
+		return
+	}
+
+	r.check(ctx, result, "get", func(ctx context.Context) error {
+		account, err := service.GetAccount(ctx, r.orgID, r.ledgerID, r.source.ID)
+		if err != nil {
+			return err
+		}
+
+		return expectID(account.ID, r.source.ID)
+	})
+
+	r.check(ctx, result, "get by alias", func(ctx context.Context) error {
+		account, err := service.GetAccountByAlias(ctx, r.orgID, r.ledgerID, models.GetAccountAlias(*r.destination))
+		if err != nil {
+			return err
+		}
+
+		return expectID(account.ID, r.destination.ID)
+	})
+
+	r.check(ctx, result, "list", func(ctx context.Context) error {
+		return expectListed(service.ListAccounts(ctx, r.orgID, r.ledgerID, nil))
+	})
+
+	r.check(ctx, result, "update", func(ctx context.Context) error {
+		_, err := service.UpdateAccount(ctx, r.orgID, r.ledgerID, r.source.ID,
+			models.NewUpdateAccountInput().WithMetadata(map[string]any{"smokeTest": r.suffix, "updated": true}))
+
+		return err
+	})
+}
+
+func (r *runner) portfolios(ctx context.Context, result *CapabilityResult) {
+	service := r.c.Entity.Portfolios
+
+	var portfolioID string
+
+	created := r.check(ctx, result, "create", func(ctx context.Context) error {
+		portfolio, err := service.CreatePortfolio(ctx, r.orgID, r.ledgerID,
+			models.NewCreatePortfolioInput("smoke-"+r.suffix, "Smoke Test Portfolio").WithMetadata(r.metadata()))
+		if err != nil {
+			return err
+		}
+
+		portfolioID = portfolio.ID
+		r.onCleanup("delete portfolio", func(ctx context.Context) error {
+			return service.DeletePortfolio(ctx, r.orgID, r.ledgerID, portfolio.ID)
+		})
+
+		return nil
+	})
+	if !created {
+		r.skip(result, "get", "list")
+		return
+	}
+
+	r.check(ctx, result, "get", func(ctx context.Context) error {
+		portfolio, err := service.GetPortfolio(ctx, r.orgID, r.ledgerID, portfolioID)
+		if err != nil {
+			return err
+		}
+
+		return expectID(portfolio.ID, portfolioID)
+	})
+
+	r.check(ctx, result, "list", func(ctx context.Context) error {
+		return expectListed(service.ListPortfolios(ctx, r.orgID, r.ledgerID, nil))
+	})
+}
+
+func (r *runner) segments(ctx context.Context, result *CapabilityResult) {
+	service := r.c.Entity.Segments
+
+	var segmentID string
+
+	created := r.check(ctx, result, "create", func(ctx context.Context) error {
+		segment, err := service.CreateSegment(ctx, r.orgID, r.ledgerID,
+			models.NewCreateSegmentInput("Smoke Test "+r.suffix).WithMetadata(r.metadata()))
+		if err != nil {
+			return err
+		}
+
+		segmentID = segment.ID
+		r.onCleanup("delete segment", func(ctx context.Context) error {
+			return service.DeleteSegment(ctx, r.orgID, r.ledgerID, segment.ID)
+		})
+
+		return nil
+	})
+	if !created {
+		r.skip(result, "get", "list")
+		return
+	}
+
+	r.check(ctx, result, "get", func(ctx context.Context) error {
+		segment, err := service.GetSegment(ctx, r.orgID, r.ledgerID, segmentID)
+		if err != nil {
+			return err
+		}
+
+		return expectID(segment.ID, segmentID)
+	})
+
+	r.check(ctx, result, "list", func(ctx context.Context) error {
+		return expectListed(service.ListSegments(ctx, r.orgID, r.ledgerID, nil))
+	})
+}
+
+func (r *runner) operationRoutes(ctx context.Context, result *CapabilityResult) {
+	service := r.c.Entity.OperationRoutes
+
+	create := func(ctx context.Context, input *models.CreateOperationRouteInput) error {
+		route, err := service.CreateOperationRoute(ctx, r.orgID, r.ledgerID, input.WithMetadata(r.metadata()))
+		if err != nil {
+			return err
+		}
+
+		id := route.ID.String()
+		r.operationRouteIDs = append(r.operationRouteIDs, id)
+		r.onCleanup("delete operation route", func(ctx context.Context) error {
+			return service.DeleteOperationRoute(ctx, r.orgID, r.ledgerID, id)
+		})
+
+		return nil
+	}
+
+	created := r.check(ctx, result, "create", func(ctx context.Context) error {
+		err := create(ctx, models.NewCreateOperationRouteInput("Smoke Test Source", "Smoke test source route", "source").
+			WithAccountAlias("@external/"+r.opts.AssetCode))
+		if err != nil {
+			return err
+		}
+
+		return create(ctx, models.NewCreateOperationRouteInput("Smoke Test Destination", "Smoke test destination route", "destination").
+			WithAccountTypes([]string{"deposit"}))
+	})
+	if !created {
+		r.operationRouteIDs = nil
+
+		r.skip(result, "get", "list")
+
+		return
+	}
+
+	r.check(ctx, result, "get", func(ctx context.Context) error {
+		route, err := service.GetOperationRoute(ctx, r.orgID, r.ledgerID, r.operationRouteIDs[0])
+		if err != nil {
+			return err
+		}
+
+		return expectID(route.ID.String(), r.operationRouteIDs[0])
+	})
+
+	r.check(ctx, result, "list", func(ctx context.Context) error {
+		return expectListed(service.ListOperationRoutes(ctx, r.orgID, r.ledgerID, nil))
+	})
+}
+
+func (r *runner) transactionRoutes(ctx context.Context, result *CapabilityResult) {
+	service := r.c.Entity.TransactionRoutes
+
+	var routeID string
+
+	created := r.check(ctx, result, "create", func(ctx context.Context) error {
+		route, err := service.CreateTransactionRoute(ctx, r.orgID, r.ledgerID,
+			models.NewCreateTransactionRouteInput("Smoke Test", "Smoke test transaction route", r.operationRouteIDs).WithMetadata(r.metadata()))
+		if err != nil {
+			return err
+		}
+
+		routeID = route.ID.String()
+		r.onCleanup("delete transaction route", func(ctx context.Context) error {
+			return service.DeleteTransactionRoute(ctx, r.orgID, r.ledgerID, routeID)
+		})
+
+		return nil
+	})
+	if !created {
+		r.skip(result, "get", "list")
+		return
+	}
+
+	r.check(ctx, result, "get", func(ctx context.Context) error {
+		route, err := service.GetTransactionRoute(ctx, r.orgID, r.ledgerID, routeID)
+		if err != nil {
+			return err
+		}
+
+		return expectID(route.ID.String(), routeID)
+	})
+
+	r.check(ctx, result, "list", func(ctx context.Context) error {
+		return expectListed(service.ListTransactionRoutes(ctx, r.orgID, r.ledgerID, nil))
+	})
+}
+
+func (r *runner) transactions(ctx context.Context, result *CapabilityResult) {
+	service := r.c.Entity.Transactions
+
+	deposited := r.check(ctx, result, "deposit", func(ctx context.Context) error {
+		_, err := service.CreateTransaction(ctx, r.orgID, r.ledgerID,
+			r.transfer("@external/"+r.opts.AssetCode, models.GetAccountAlias(*r.source), depositAmount))
+
+		return err
+	})
+	if !deposited {
+		r.skip(result, "transfer", "get", "list")
+		return
+	}
+
+	transferred := r.check(ctx, result, "transfer", func(ctx context.Context) error {
+		tx, err := service.CreateTransaction(ctx, r.orgID, r.ledgerID,
+			r.transfer(models.GetAccountAlias(*r.source), models.GetAccountAlias(*r.destination), transferAmount))
+		if err != nil {
+			return err
+		}
+
+		if tx.ID == "" {
+			return errors.New("no ID returned")
+		}
+
+		r.transactionID = tx.ID
+
+		return nil
+	})
+	if !transferred {
+		r.skip(result, "get", "list")
+		return
+	}
+
+	r.check(ctx, result, "get", func(ctx context.Context) error {
+		tx, err := service.GetTransaction(ctx, r.orgID, r.ledgerID, r.transactionID)
+		if err != nil {
+			return err
+		}
+
+		return expectID(tx.ID, r.transactionID)
+	})
+
+	r.check(ctx, result, "list", func(ctx context.Context) error {
+		return expectListed(service.ListTransactions(ctx, r.orgID, r.ledgerID, nil))
+	})
+}
+
+// transfer returns the input of a transaction moving amount between two aliases
+func (r *runner) transfer(from, to, amount string) *models.CreateTransactionInput {
+	asset := r.opts.AssetCode
+	value := models.AmountInput{Asset: asset, Value: amount}
+
+	return &models.CreateTransactionInput{
+		Description: "Smoke test " + r.suffix,
+		Amount:      amount,
+		AssetCode:   asset,
+		Metadata:    r.metadata(),
+		Send: &models.SendInput{
+			Asset:      asset,
+			Value:      amount,
+			Source:     &models.SourceInput{From: []models.FromToInput{{Account: from, AccountAlias: from, Amount: value}}},
+			Distribute: &models.DistributeInput{To: []models.FromToInput{{Account: to, AccountAlias: to, Amount: value}}},
+		},
+	}
+}
+
+func (r *runner) operations(ctx context.Context, result *CapabilityResult) {
+	service := r.c.Entity.Operations
+
+	var operationID string
+
+	listed := r.check(ctx, result, "list", func(ctx context.Context) error {
+		page, err := service.ListOperations(ctx, r.orgID, r.ledgerID, r.destination.ID, nil)
+		if err != nil {
+			return err
+		}
+
+		if len(page.Items) == 0 {
+			return errors.New("no operation listed for the destination account")
+		}
+
+		operationID = page.Items[0].ID
+
+		return nil
+	})
+	if !listed {
+		r.skip(result, "get")
+		return
+	}
+
+	r.check(ctx, result, "get", func(ctx context.Context) error {
+		operation, err := service.GetOperation(ctx, r.orgID, r.ledgerID, r.destination.ID, operationID)
+		if err != nil {
+			return err
+		}
+
+		return expectID(operation.ID, operationID)
+	})
+}
+
+func (r *runner) balances(ctx context.Context, result *CapabilityResult) {
+	service := r.c.Entity.Balances
+
+	r.check(ctx, result, "account balance", func(ctx context.Context) error {
+		expected := decimal.RequireFromString(transferAmount)
+
+		var available decimal.Decimal
+
+		// balances may be applied shortly after the transaction is accepted
+		for attempt := range balancePolls {
+			page, err := service.ListAccountBalances(ctx, r.orgID, r.ledgerID, r.destination.ID, nil)
+			if err != nil {
+				return err
+			}
+
+			available = decimal.Zero
+			for _, balance := range page.Items {
+				if balance.AssetCode == r.opts.AssetCode {
+					available = available.Add(balance.Available)
+				}
+			}
+
+			if available.Equal(expected) || attempt == balancePolls-1 {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(balancePollInterval):
+			}
+		}
+
+		if !available.Equal(expected) {
+			return fmt.Errorf("expected %s %s available on the destination account, got %s", expected, r.opts.AssetCode, available)
+		}
+
+		return nil
+	})
+
+	r.check(ctx, result, "list", func(ctx context.Context) error {
+		return expectListed(service.ListBalances(ctx, r.orgID, r.ledgerID, nil))
+	})
+}
+
+// expectID checks that a resource read back is the one created
+func expectID(got, want string) error {
+	if got != want {
+		return fmt.Errorf("expected ID %s, got %q", want, got)
+	}
+
+	return nil
+}
+
+// expectListed checks that a list holds at least the resource created
+func expectListed[T any](page *models.ListResponse[T], err error) error {
+	if err != nil {
+		return err
+	}
+
+	if len(page.Items) == 0 {
+		return errors.New("created resource not listed")
+	}
+
+	return nil
+}
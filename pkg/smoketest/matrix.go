@@ -0,0 +1,329 @@
+package smoketest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	sdkerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// Compatibility is how a backend handles a feature.
+type Compatibility string
+
+const (
+	// CompatibilitySupported marks a feature whose checks all passed
+	CompatibilitySupported Compatibility = "supported"
+
+	// CompatibilityUnsupported marks a feature the backend doesn't serve: its
+	// endpoints answer 404, 405 or 501
+	CompatibilityUnsupported Compatibility = "unsupported"
+
+	// CompatibilityChanged marks a feature served with another behavior: its
+	// calls are rejected or answered differently than the SDK expects
+	CompatibilityChanged Compatibility = "changed"
+
+	// CompatibilityUntested marks a feature not run because a feature it
+	// depends on failed
+	CompatibilityUntested Compatibility = "untested"
+)
+
+// Backend is a Midaz deployment of a compatibility matrix.
+type Backend struct {
+	// Name identifies the backend in the report, such as "staging"
+	Name string `json:"name"`
+
+	// Version is the Midaz version of the backend, such as "3.4.1"
+	Version string `json:"version,omitempty"`
+
+	// Client is configured for the backend
+	Client *client.Client `json:"-"`
+}
+
+// label returns the name of the backend with its version
+func (b Backend) label() string {
+	if b.Version == "" {
+		return b.Name
+	}
+
+	return b.Name + " (" + b.Version + ")"
+}
+
+// Probe is a feature check beyond the smoke test, such as a newer endpoint
+// or a behavior the SDK relies on. A nil error means supported.
+type Probe struct {
+	Name string
+	Run  func(ctx context.Context, c *client.Client) error
+}
+
+// MatrixOptions configures a compatibility matrix.
+type MatrixOptions struct {
+	// Smoke configures the smoke test run on each backend; DefaultOptions
+	// when nil
+	Smoke *Options
+
+	// Probes are run on each backend after the smoke test
+	Probes []Probe
+}
+
+// FeatureResult is the compatibility of a feature on a backend.
+type FeatureResult struct {
+	Compatibility Compatibility `json:"compatibility"`
+
+	// Details lists the failed checks as "check: error"
+	Details []string `json:"details,omitempty"`
+}
+
+// FeatureCompatibility is the compatibility of a capability or probe on each
+// backend, keyed by backend name.
+type FeatureCompatibility struct {
+	Feature  string                   `json:"feature"`
+	Backends map[string]FeatureResult `json:"backends"`
+}
+
+// CompatibilityReport is the outcome of a compatibility matrix.
+type CompatibilityReport struct {
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"duration"`
+
+	// Backends are the backends in matrix order; the first is the baseline
+	// of Regressions
+	Backends []Backend `json:"backends"`
+
+	// Features holds the capabilities in run order, then the probes
+	Features []FeatureCompatibility `json:"features"`
+
+	// SmokeReports are the smoke test reports, keyed by backend name
+	SmokeReports map[string]*Report `json:"smokeReports"`
+}
+
+// RunMatrix runs the smoke test and the probes against each backend, one
+// after another, and reports the compatibility of each feature per backend.
+// It returns an error only for invalid backends or a cancelled context.
+func RunMatrix(ctx context.Context, backends []Backend, opts *MatrixOptions) (*CompatibilityReport, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("at least one backend is required")
+	}
+
+	seen := map[string]bool{}
+
+	for _, backend := range backends {
+		if backend.Name == "" || backend.Client == nil {
+			return nil, errors.New("backends require a name and a client")
+		}
+
+		if seen[backend.Name] {
+			return nil, fmt.Errorf("duplicate backend %q", backend.Name)
+		}
+
+		seen[backend.Name] = true
+	}
+
+	if opts == nil {
+		opts = &MatrixOptions{}
+	}
+
+	smoke := opts.Smoke
+	if smoke == nil {
+		smoke = DefaultOptions()
+	}
+
+	report := &CompatibilityReport{
+		StartedAt:    time.Now().UTC(),
+		Backends:     backends,
+		SmokeReports: map[string]*Report{},
+	}
+
+	defer func() { report.Duration = time.Since(report.StartedAt) }()
+
+	features := map[string]int{}
+
+	// feature returns the results of a feature by backend, registering it
+	feature := func(name string) map[string]FeatureResult {
+		if i, ok := features[name]; ok {
+			return report.Features[i].Backends
+		}
+
+		features[name] = len(report.Features)
+		report.Features = append(report.Features, FeatureCompatibility{Feature: name, Backends: map[string]FeatureResult{}})
+
+		return report.Features[features[name]].Backends
+	}
+
+	// register the features up front, so that their order doesn't depend on
+	// the backend that ran them first
+	selected, err := resolveCapabilities(smoke.Capabilities)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, spec := range capabilitySpecs {
+		if selected[spec.name] {
+			feature(spec.name)
+		}
+	}
+
+	for _, probe := range opts.Probes {
+		feature(probe.Name)
+	}
+
+	for _, backend := range backends {
+		smokeReport, err := Run(ctx, backend.Client, smoke)
+		if smokeReport != nil {
+			report.SmokeReports[backend.Name] = smokeReport
+
+			for _, capability := range smokeReport.Capabilities {
+				feature(capability.Name)[backend.Name] = capabilityCompatibility(capability)
+			}
+		}
+
+		if err != nil {
+			return report, fmt.Errorf("smoke test of %s: %w", backend.Name, err)
+		}
+
+		for _, probe := range opts.Probes {
+			feature(probe.Name)[backend.Name] = runProbe(ctx, backend.Client, probe, smoke.CheckTimeout)
+		}
+	}
+
+	return report, ctx.Err()
+}
+
+// runProbe runs a probe, bounded by timeout when positive
+func runProbe(ctx context.Context, c *client.Client, probe Probe, timeout time.Duration) FeatureResult {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := probe.Run(ctx, c)
+	if err == nil {
+		return FeatureResult{Compatibility: CompatibilitySupported}
+	}
+
+	var apiErr *sdkerrors.Error
+	if errors.As(err, &apiErr) && isUnsupportedStatus(apiErr.StatusCode) {
+		return FeatureResult{Compatibility: CompatibilityUnsupported, Details: []string{err.Error()}}
+	}
+
+	return FeatureResult{Compatibility: CompatibilityChanged, Details: []string{err.Error()}}
+}
+
+// capabilityCompatibility classifies the checks of a capability: a call
+// rejected as unknown makes it unsupported, any other failure changed
+func capabilityCompatibility(capability CapabilityResult) FeatureResult {
+	if capability.Status == StatusSkipped {
+		return FeatureResult{Compatibility: CompatibilityUntested, Details: []string{capability.SkipReason}}
+	}
+
+	result := FeatureResult{Compatibility: CompatibilitySupported}
+
+	for _, check := range capability.Checks {
+		if check.Status != StatusFailed {
+			continue
+		}
+
+		result.Details = append(result.Details, check.Name+": "+check.Error)
+
+		switch {
+		case !isUnsupportedStatus(check.StatusCode):
+			result.Compatibility = CompatibilityChanged
+		case result.Compatibility == CompatibilitySupported:
+			result.Compatibility = CompatibilityUnsupported
+		}
+	}
+
+	return result
+}
+
+// isUnsupportedStatus reports whether an HTTP status means the endpoint or
+// method doesn't exist on the backend
+func isUnsupportedStatus(status int) bool {
+	return status == http.StatusNotFound || status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented
+}
+
+// Regression is a feature supported by the baseline backend and not by
+// another one.
+type Regression struct {
+	Feature       string        `json:"feature"`
+	Backend       string        `json:"backend"`
+	Compatibility Compatibility `json:"compatibility"`
+	Details       []string      `json:"details,omitempty"`
+}
+
+// Regressions returns the features supported by the first backend, the
+// baseline, that another backend doesn't support the same way. Comparing a
+// deployed version to a candidate tells whether the SDK can move to it.
+func (r *CompatibilityReport) Regressions() []Regression {
+	if len(r.Backends) == 0 {
+		return nil
+	}
+
+	baseline := r.Backends[0].Name
+
+	var regressions []Regression
+
+	for _, feature := range r.Features {
+		if feature.Backends[baseline].Compatibility != CompatibilitySupported {
+			continue
+		}
+
+		for _, backend := range r.Backends[1:] {
+			result, ok := feature.Backends[backend.Name]
+			if !ok || result.Compatibility == CompatibilitySupported {
+				continue
+			}
+
+			regressions = append(regressions, Regression{
+				Feature:       feature.Feature,
+				Backend:       backend.Name,
+				Compatibility: result.Compatibility,
+				Details:       result.Details,
+			})
+		}
+	}
+
+	return regressions
+}
+
+// Table renders the matrix as text: a row per feature and a column per
+// backend.
+func (r *CompatibilityReport) Table() string {
+	var b strings.Builder
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+
+	header := []string{"FEATURE"}
+	for _, backend := range r.Backends {
+		header = append(header, backend.label())
+	}
+
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	for _, feature := range r.Features {
+		row := []string{feature.Feature}
+
+		for _, backend := range r.Backends {
+			result, ok := feature.Backends[backend.Name]
+			if !ok {
+				row = append(row, "-")
+				continue
+			}
+
+			row = append(row, string(result.Compatibility))
+		}
+
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	_ = w.Flush()
+
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package smoketest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMatrix(t *testing.T) {
+	baseline := newFakeMidaz(t)
+	candidate := newFakeMidaz(t, "GET /segments 404", "POST /assets 400")
+
+	probes := []Probe{
+		{Name: "list-organizations", Run: func(ctx context.Context, c *client.Client) error {
+			_, err := c.Entity.Organizations.ListOrganizations(ctx, models.NewListOptions())
+			return err
+		}},
+		{Name: "strict-validation", Run: func(_ context.Context, c *client.Client) error {
+			if c == candidate {
+				return errors.New("unexpected response shape")
+			}
+
+			return nil
+		}},
+	}
+
+	report, err := RunMatrix(context.Background(), []Backend{
+		{Name: "production", Version: "3.3.0", Client: baseline},
+		{Name: "candidate", Version: "3.4.0", Client: candidate},
+	}, &MatrixOptions{Probes: probes})
+	require.NoError(t, err)
+
+	require.Len(t, report.Features, len(capabilitySpecs)+len(probes))
+	assert.Equal(t, CapabilityOrganizations, report.Features[0].Feature)
+	assert.Equal(t, "strict-validation", report.Features[len(report.Features)-1].Feature)
+	assert.True(t, report.SmokeReports["production"].OK())
+
+	compatibility := map[string]Compatibility{}
+
+	for _, feature := range report.Features {
+		assert.Equal(t, CompatibilitySupported, feature.Backends["production"].Compatibility, feature.Feature)
+		compatibility[feature.Feature] = feature.Backends["candidate"].Compatibility
+	}
+
+	assert.Equal(t, CompatibilitySupported, compatibility[CapabilityLedgers])
+	assert.Equal(t, CompatibilityUnsupported, compatibility[CapabilitySegments])
+	assert.Equal(t, CompatibilityChanged, compatibility[CapabilityAssets])
+	assert.Equal(t, CompatibilityUntested, compatibility[CapabilityAccounts])
+	assert.Equal(t, CompatibilitySupported, compatibility["list-organizations"])
+	assert.Equal(t, CompatibilityChanged, compatibility["strict-validation"])
+
+	regressions := report.Regressions()
+
+	var features []string
+	for _, regression := range regressions {
+		assert.Equal(t, "candidate", regression.Backend)
+		features = append(features, regression.Feature)
+	}
+
+	assert.Equal(t, []string{
+		CapabilityAssets, CapabilityAccounts, CapabilitySegments, CapabilityTransactions,
+		CapabilityOperations, CapabilityBalances, "strict-validation",
+	}, features)
+	assert.Contains(t, regressions[0].Details[0], "create:")
+
+	table := report.Table()
+	assert.Contains(t, table, "FEATURE")
+	assert.Contains(t, table, "candidate (3.4.0)")
+	assert.Regexp(t, `segments\s+supported\s+unsupported`, table)
+
+	raw, err := json.Marshal(report)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), `"version":"3.4.0"`)
+}
+
+func TestRunMatrixValidation(t *testing.T) {
+	c := newFakeMidaz(t)
+
+	_, err := RunMatrix(context.Background(), nil, nil)
+	require.Error(t, err)
+
+	_, err = RunMatrix(context.Background(), []Backend{{Name: "a", Client: c}, {Name: "a", Client: c}}, nil)
+	require.Error(t, err)
+
+	_, err = RunMatrix(context.Background(), []Backend{{Name: "a"}}, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,304 @@
+// Package smoketest verifies a Midaz environment end to end, typically after
+// an upgrade.
+//
+// Run creates a throwaway organization with a ledger, an asset, accounts,
+// portfolios, segments and routes, moves funds between the accounts, reads
+// everything back through the entity APIs, and deletes what it created. Each
+// API is a capability, reported as passed, failed or skipped with the
+// outcome and duration of each call:
+//
+//	report, err := smoketest.Run(ctx, midazClient, nil)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, capability := range report.Capabilities {
+//	    log.Printf("%-20s %s", capability.Name, capability.Status)
+//	}
+//
+//	if !report.OK() {
+//	    log.Fatalf("smoke test failed: %v", report.Failures())
+//	}
+//
+// A capability whose resources could not be created makes the capabilities
+// depending on it skipped rather than failed, so that the report points at
+// the first broken API.
+//
+// RunMatrix runs the smoke test, and optional probes of further features,
+// against several backends, such as the deployed Midaz version and the
+// candidate of an upgrade, and reports each feature as supported,
+// unsupported or changed per backend:
+//
+//	matrix, err := smoketest.RunMatrix(ctx, []smoketest.Backend{
+//	    {Name: "production", Version: "3.3.0", Client: current},
+//	    {Name: "candidate", Version: "3.4.0", Client: candidate},
+//	}, nil)
+//	fmt.Print(matrix.Table())
+//	for _, r := range matrix.Regressions() { ... }
+package smoketest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+)
+
+// Capabilities exercised by Run, in the order they run.
+const (
+	CapabilityOrganizations     = "organizations"
+	CapabilityLedgers           = "ledgers"
+	CapabilityAssets            = "assets"
+	CapabilityAccountTypes      = "account-types"
+	CapabilityAccounts          = "accounts"
+	CapabilityPortfolios        = "portfolios"
+	CapabilitySegments          = "segments"
+	CapabilityOperationRoutes   = "operation-routes"
+	CapabilityTransactionRoutes = "transaction-routes"
+	CapabilityTransactions      = "transactions"
+	CapabilityOperations        = "operations"
+	CapabilityBalances          = "balances"
+)
+
+// DefaultCheckTimeout bounds each API call of a smoke test.
+const DefaultCheckTimeout = 30 * time.Second
+
+// Status is the outcome of a check or a capability.
+type Status string
+
+const (
+	// StatusPassed marks a check that succeeded, or a capability whose checks all did
+	StatusPassed Status = "passed"
+
+	// StatusFailed marks a check that failed, or a capability with a failed check
+	StatusFailed Status = "failed"
+
+	// StatusSkipped marks a check or capability not run because what it needs is missing
+	StatusSkipped Status = "skipped"
+)
+
+// Options configures a smoke test.
+type Options struct {
+	// Capabilities limits the test to these capabilities and those they
+	// depend on; all capabilities when empty
+	Capabilities []string
+
+	// AssetCode is the code of the asset created for the test
+	AssetCode string
+
+	// Cleanup deletes the resources created by the test once done
+	Cleanup bool
+
+	// CheckTimeout bounds each API call
+	CheckTimeout time.Duration
+
+	// OnCheck, when set, receives each check as it completes
+	OnCheck func(capability string, check Check)
+}
+
+// DefaultOptions returns the options used when Run receives nil: every
+// capability, a USD asset, and cleanup.
+func DefaultOptions() *Options {
+	return &Options{
+		AssetCode:    "USD",
+		Cleanup:      true,
+		CheckTimeout: DefaultCheckTimeout,
+	}
+}
+
+// Check is a single API call of a smoke test.
+type Check struct {
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+
+	// StatusCode is the HTTP status of a failed API call, when it got a response
+	StatusCode int `json:"statusCode,omitempty"`
+}
+
+// CapabilityResult is the outcome of the checks of a capability.
+type CapabilityResult struct {
+	Name   string  `json:"name"`
+	Status Status  `json:"status"`
+	Checks []Check `json:"checks"`
+
+	// SkipReason explains a skipped capability
+	SkipReason string `json:"skipReason,omitempty"`
+}
+
+// Report is the outcome of a smoke test.
+type Report struct {
+	StartedAt    time.Time          `json:"startedAt"`
+	Duration     time.Duration      `json:"duration"`
+	Capabilities []CapabilityResult `json:"capabilities"`
+
+	// Cleanup holds the deletions of the created resources. Their failures
+	// don't fail the report, since they leave only test data behind.
+	Cleanup []Check `json:"cleanup,omitempty"`
+
+	// OrganizationID is the organization created for the test
+	OrganizationID string `json:"organizationId,omitempty"`
+}
+
+// OK reports whether no capability failed or was skipped.
+func (r *Report) OK() bool {
+	for _, capability := range r.Capabilities {
+		if capability.Status != StatusPassed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Failures returns the failed checks as "capability/check: error", and the
+// skipped capabilities as "capability: reason".
+func (r *Report) Failures() []string {
+	var failures []string
+
+	for _, capability := range r.Capabilities {
+		if capability.Status == StatusSkipped {
+			failures = append(failures, capability.Name+": "+capability.SkipReason)
+			continue
+		}
+
+		for _, check := range capability.Checks {
+			if check.Status == StatusFailed {
+				failures = append(failures, capability.Name+"/"+check.Name+": "+check.Error)
+			}
+		}
+	}
+
+	return failures
+}
+
+// Capability returns the result of a capability, if it ran.
+func (r *Report) Capability(name string) (CapabilityResult, bool) {
+	for _, capability := range r.Capabilities {
+		if capability.Name == name {
+			return capability, true
+		}
+	}
+
+	return CapabilityResult{}, false
+}
+
+// Run runs the smoke test against the environment of c and returns its
+// report. Failing APIs are recorded in the report; Run returns an error only
+// for invalid options or a cancelled context, with the report so far.
+func Run(ctx context.Context, c *client.Client, opts *Options) (*Report, error) {
+	if c == nil || c.Entity == nil {
+		return nil, errors.New("client not initialized")
+	}
+
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+
+	selected, err := resolveCapabilities(opts.Capabilities)
+	if err != nil {
+		return nil, err
+	}
+
+	r := newRunner(c, opts)
+	report := &Report{StartedAt: time.Now().UTC()}
+
+	defer func() { report.Duration = time.Since(report.StartedAt) }()
+
+	for _, spec := range capabilitySpecs {
+		if !selected[spec.name] {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			report.Cleanup = r.cleanup(context.WithoutCancel(ctx))
+			return report, err
+		}
+
+		result := CapabilityResult{Name: spec.name}
+
+		if missing := r.missing(spec.needs); missing != "" {
+			result.Status = StatusSkipped
+			result.SkipReason = "requires " + missing
+		} else {
+			spec.run(r, ctx, &result)
+			result.Status = capabilityStatus(result.Checks)
+		}
+
+		report.Capabilities = append(report.Capabilities, result)
+	}
+
+	report.OrganizationID = r.orgID
+	report.Cleanup = r.cleanup(ctx)
+
+	return report, ctx.Err()
+}
+
+// resolveCapabilities returns the capabilities to run: the requested ones and
+// those they depend on
+func resolveCapabilities(requested []string) (map[string]bool, error) {
+	selected := map[string]bool{}
+
+	if len(requested) == 0 {
+		for _, spec := range capabilitySpecs {
+			selected[spec.name] = true
+		}
+
+		return selected, nil
+	}
+
+	var include func(name string) error
+
+	include = func(name string) error {
+		idx := slices.IndexFunc(capabilitySpecs, func(s capabilitySpec) bool { return s.name == name })
+		if idx < 0 {
+			return fmt.Errorf("unknown smoke test capability %q", name)
+		}
+
+		if selected[name] {
+			return nil
+		}
+
+		selected[name] = true
+
+		for _, dependency := range capabilitySpecs[idx].dependsOn {
+			if err := include(dependency); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, name := range requested {
+		if err := include(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return selected, nil
+}
+
+// capabilityStatus is failed when a check failed, skipped when none ran
+func capabilityStatus(checks []Check) Status {
+	ran := false
+
+	for _, check := range checks {
+		switch check.Status {
+		case StatusFailed:
+			return StatusFailed
+		case StatusPassed:
+			ran = true
+		}
+	}
+
+	if !ran {
+		return StatusSkipped
+	}
+
+	return StatusPassed
+}
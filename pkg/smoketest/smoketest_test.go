@@ -0,0 +1,294 @@
+package smoketest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMidaz is an in-memory REST server storing the resources posted to
+// each collection, applying transactions to the balances of account aliases
+type fakeMidaz struct {
+	mu          sync.Mutex
+	collections map[string][]map[string]any
+	balances    map[string]decimal.Decimal
+
+	// fail makes the requests "METHOD /suffix" answer 500, or the status
+	// following them, as in "GET /segments 404"
+	fail []string
+}
+
+func newFakeMidaz(t *testing.T, fail ...string) *client.Client {
+	t.Helper()
+	t.Setenv("MIDAZ_ENABLE_RETRIES", "false")
+
+	fake := &fakeMidaz{collections: map[string][]map[string]any{}, balances: map[string]decimal.Decimal{}, fail: fail}
+
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	entity, err := entities.New(srv.URL, entities.WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+
+	return &client.Client{Entity: entity}
+}
+
+func (f *fakeMidaz) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p := strings.TrimSuffix(r.URL.Path, "/")
+
+	for _, failing := range f.fail {
+		fields := strings.Fields(failing)
+
+		status := http.StatusInternalServerError
+		if len(fields) == 3 {
+			status, _ = strconv.Atoi(fields[2])
+		}
+
+		if r.Method == fields[0] && strings.HasSuffix(p, fields[1]) {
+			writeJSON(w, status, map[string]any{"code": "0000", "message": "boom"})
+			return
+		}
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		body["id"] = uuid.NewString()
+
+		// transaction routes answer with their operation routes expanded
+		if routes, ok := body["operationRoutes"].([]any); ok {
+			for i, id := range routes {
+				routes[i] = map[string]any{"id": id}
+			}
+		}
+
+		if strings.HasSuffix(p, "/transactions/json") {
+			f.applyTransaction(strings.TrimSuffix(p, "/transactions/json"), body)
+			p = strings.TrimSuffix(p, "/json")
+		}
+
+		f.collections[p] = append(f.collections[p], body)
+		writeJSON(w, http.StatusCreated, body)
+	case http.MethodGet:
+		f.get(w, p, r.URL.Query().Get("alias"))
+	case http.MethodPatch:
+		if item := f.find(p); item != nil {
+			writeJSON(w, http.StatusOK, item)
+			return
+		}
+
+		writeJSON(w, http.StatusNotFound, map[string]any{"code": "0007", "message": "not found"})
+	case http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeMidaz) get(w http.ResponseWriter, p, alias string) {
+	switch {
+	case alias != "":
+		var items []map[string]any
+
+		for _, account := range f.collections[p] {
+			if account["alias"] == alias {
+				items = append(items, account)
+			}
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{"items": items})
+
+		return
+	case strings.HasSuffix(p, "/balances"):
+		writeJSON(w, http.StatusOK, map[string]any{"items": f.accountBalances(p)})
+		return
+	case f.collections[p] != nil:
+		writeJSON(w, http.StatusOK, map[string]any{"items": f.collections[p]})
+		return
+	default:
+		if item := f.find(p); item != nil {
+			writeJSON(w, http.StatusOK, item)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusNotFound, map[string]any{"code": "0007", "message": "not found"})
+}
+
+// find returns the item of the collection of p with the ID ending p
+func (f *fakeMidaz) find(p string) map[string]any {
+	for _, item := range f.collections[path.Dir(p)] {
+		if item["id"] == path.Base(p) {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// accountBalances lists the balances of the accounts of a ledger, or of the
+// account of an account balances path
+func (f *fakeMidaz) accountBalances(p string) []map[string]any {
+	ledger, accountID, _ := strings.Cut(strings.TrimSuffix(p, "/balances"), "/accounts/")
+
+	var items []map[string]any
+
+	for _, account := range f.collections[ledger+"/accounts"] {
+		if accountID != "" && account["id"] != accountID {
+			continue
+		}
+
+		alias, _ := account["alias"].(string)
+		items = append(items, map[string]any{
+			"id": uuid.NewString(), "accountId": account["id"], "alias": alias,
+			"assetCode": account["assetCode"], "available": f.balances[alias].String(), "onHold": "0",
+		})
+	}
+
+	return items
+}
+
+// applyTransaction moves the amount of a transaction between aliases and
+// records an operation for each destination account
+func (f *fakeMidaz) applyTransaction(ledger string, body map[string]any) {
+	send, _ := body["send"].(map[string]any)
+	value := decimal.RequireFromString(send["value"].(string))
+
+	legs := func(side, key string) []any {
+		m, _ := send[side].(map[string]any)
+		l, _ := m[key].([]any)
+
+		return l
+	}
+
+	for _, leg := range legs("source", "from") {
+		alias := leg.(map[string]any)["accountAlias"].(string)
+		f.balances[alias] = f.balances[alias].Sub(value)
+	}
+
+	for _, leg := range legs("distribute", "to") {
+		alias := leg.(map[string]any)["accountAlias"].(string)
+		f.balances[alias] = f.balances[alias].Add(value)
+
+		for _, account := range f.collections[ledger+"/accounts"] {
+			if account["alias"] == alias {
+				operations := ledger + "/accounts/" + account["id"].(string) + "/operations"
+				f.collections[operations] = append(f.collections[operations], map[string]any{"id": uuid.NewString(), "type": "CREDIT"})
+			}
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestRun(t *testing.T) {
+	c := newFakeMidaz(t)
+
+	var checks []string
+
+	opts := DefaultOptions()
+	opts.OnCheck = func(capability string, check Check) { checks = append(checks, capability+"/"+check.Name) }
+
+	report, err := Run(context.Background(), c, opts)
+	require.NoError(t, err)
+
+	assert.True(t, report.OK(), "failures: %v", report.Failures())
+	assert.Empty(t, report.Failures())
+	assert.NotEmpty(t, report.OrganizationID)
+	require.Len(t, report.Capabilities, len(capabilitySpecs))
+
+	for _, capability := range report.Capabilities {
+		assert.Equal(t, StatusPassed, capability.Status, capability.Name)
+	}
+
+	balances, ok := report.Capability(CapabilityBalances)
+	require.True(t, ok)
+	assert.Equal(t, "account balance", balances.Checks[0].Name)
+
+	assert.Contains(t, checks, "transactions/transfer")
+	assert.Contains(t, checks, "cleanup/delete organization")
+
+	require.NotEmpty(t, report.Cleanup)
+	assert.Equal(t, "delete transaction route", report.Cleanup[0].Name)
+	assert.Equal(t, "delete organization", report.Cleanup[len(report.Cleanup)-1].Name)
+
+	for _, check := range report.Cleanup {
+		assert.Equal(t, StatusPassed, check.Status, check.Name)
+	}
+}
+
+func TestRunReportsFailuresAndSkipsDependents(t *testing.T) {
+	c := newFakeMidaz(t, "POST /assets", "GET /segments")
+
+	opts := DefaultOptions()
+	opts.Cleanup = false
+
+	report, err := Run(context.Background(), c, opts)
+	require.NoError(t, err)
+	assert.False(t, report.OK())
+	assert.Empty(t, report.Cleanup)
+
+	statuses := map[string]Status{}
+	for _, capability := range report.Capabilities {
+		statuses[capability.Name] = capability.Status
+	}
+
+	assert.Equal(t, StatusPassed, statuses[CapabilityLedgers])
+	assert.Equal(t, StatusFailed, statuses[CapabilityAssets])
+	assert.Equal(t, StatusSkipped, statuses[CapabilityAccounts])
+	assert.Equal(t, StatusSkipped, statuses[CapabilityTransactions])
+	assert.Equal(t, StatusFailed, statuses[CapabilitySegments], "a failed list fails the capability")
+	assert.Equal(t, StatusPassed, statuses[CapabilityPortfolios])
+
+	assets, _ := report.Capability(CapabilityAssets)
+	assert.Equal(t, []Status{StatusFailed, StatusSkipped, StatusSkipped},
+		[]Status{assets.Checks[0].Status, assets.Checks[1].Status, assets.Checks[2].Status})
+
+	accounts, _ := report.Capability(CapabilityAccounts)
+	assert.Equal(t, "requires a created asset", accounts.SkipReason)
+
+	failures := report.Failures()
+	assert.Contains(t, failures, "accounts: requires a created asset")
+	assert.Len(t, failures, 6)
+}
+
+func TestRunCapabilitySelection(t *testing.T) {
+	c := newFakeMidaz(t)
+
+	report, err := Run(context.Background(), c, &Options{AssetCode: "BRL", Capabilities: []string{CapabilityAccounts}})
+	require.NoError(t, err)
+	assert.True(t, report.OK(), "failures: %v", report.Failures())
+
+	var names []string
+	for _, capability := range report.Capabilities {
+		names = append(names, capability.Name)
+	}
+
+	assert.Equal(t, []string{CapabilityOrganizations, CapabilityLedgers, CapabilityAssets, CapabilityAccounts}, names)
+
+	_, err = Run(context.Background(), c, &Options{Capabilities: []string{"reports"}})
+	require.Error(t, err)
+
+	_, err = Run(context.Background(), nil, nil)
+	require.Error(t, err)
+}
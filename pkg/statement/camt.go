@@ -0,0 +1,204 @@
+package statement
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CamtNamespace is the XML namespace of the camt.053 messages the XML follows.
+const CamtNamespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"
+
+// camtDateTime is the layout of the ISO 20022 date times.
+const camtDateTime = "2006-01-02T15:04:05.000Z07:00"
+
+// Write writes the document to w in format.
+func (d *Document) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatXML:
+		return d.WriteXML(w)
+	case FormatJSON:
+		return d.WriteJSON(w)
+	case FormatOFX:
+		return d.WriteOFX(w)
+	case FormatQIF:
+		return d.WriteQIF(w)
+	default:
+		return fmt.Errorf("unsupported statement format %q", format)
+	}
+}
+
+// WriteJSON writes the document to w as indented JSON.
+func (d *Document) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(d)
+}
+
+// WriteXML writes the document to w as camt.053-like XML.
+func (d *Document) WriteXML(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(d.camt()); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+type camtDocument struct {
+	XMLName   xml.Name      `xml:"Document"`
+	Namespace string        `xml:"xmlns,attr"`
+	Message   camtBkToCstmr `xml:"BkToCstmrStmt"`
+}
+
+type camtBkToCstmr struct {
+	GroupHeader camtGroupHeader `xml:"GrpHdr"`
+	Statements  []camtStatement `xml:"Stmt"`
+}
+
+type camtGroupHeader struct {
+	MessageID string `xml:"MsgId"`
+	CreatedAt string `xml:"CreDtTm"`
+}
+
+type camtStatement struct {
+	ID        string        `xml:"Id"`
+	CreatedAt string        `xml:"CreDtTm"`
+	Period    camtPeriod    `xml:"FrToDt"`
+	Account   camtAccount   `xml:"Acct"`
+	Balances  []camtBalance `xml:"Bal"`
+	Summary   camtSummary   `xml:"TxsSummry"`
+	Entries   []camtEntry   `xml:"Ntry"`
+}
+
+type camtPeriod struct {
+	From string `xml:"FrDtTm"`
+	To   string `xml:"ToDtTm"`
+}
+
+type camtAccount struct {
+	ID       string `xml:"Id>Othr>Id"`
+	Currency string `xml:"Ccy"`
+	Name     string `xml:"Nm,omitempty"`
+	Alias    string `xml:"Prxy>Id,omitempty"`
+}
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtBalance struct {
+	Type      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount    camtAmount `xml:"Amt"`
+	Indicator Indicator  `xml:"CdtDbtInd"`
+	Date      string     `xml:"Dt>DtTm"`
+}
+
+type camtTotals struct {
+	Count int    `xml:"NbOfNtries"`
+	Sum   string `xml:"Sum"`
+}
+
+type camtSummary struct {
+	Entries      int       `xml:"TtlNtries>NbOfNtries"`
+	Sum          string    `xml:"TtlNtries>Sum"`
+	NetAmount    string    `xml:"TtlNtries>TtlNetNtry>Amt"`
+	NetIndicator Indicator `xml:"TtlNtries>TtlNetNtry>CdtDbtInd"`
+
+	Credits camtTotals `xml:"TtlCdtNtries"`
+	Debits  camtTotals `xml:"TtlDbtNtries"`
+}
+
+type camtEntry struct {
+	Reference     string     `xml:"NtryRef"`
+	Amount        camtAmount `xml:"Amt"`
+	Indicator     Indicator  `xml:"CdtDbtInd"`
+	Status        string     `xml:"Sts>Cd"`
+	BookingDate   string     `xml:"BookgDt>DtTm"`
+	ServicerRef   string     `xml:"AcctSvcrRef"`
+	TransactionID string     `xml:"NtryDtls>TxDtls>Refs>TxId"`
+	Remittance    string     `xml:"NtryDtls>TxDtls>RmtInf>Ustrd,omitempty"`
+}
+
+// camt maps the document to its XML elements.
+func (d *Document) camt() camtDocument {
+	doc := camtDocument{
+		Namespace: CamtNamespace,
+		Message: camtBkToCstmr{
+			GroupHeader: camtGroupHeader{MessageID: d.MessageID, CreatedAt: camtTime(d.CreatedAt)},
+		},
+	}
+
+	for _, s := range d.Statements {
+		stmt := camtStatement{
+			ID:        s.ID,
+			CreatedAt: camtTime(s.CreatedAt),
+			Period:    camtPeriod{From: camtTime(s.From), To: camtTime(s.To)},
+			Account:   camtAccount{ID: s.AccountID, Currency: s.Currency, Name: s.AccountName, Alias: s.AccountAlias},
+			Summary: camtSummary{
+				Entries:      s.Summary.Entries,
+				Sum:          camtDecimal(s.Summary.Sum),
+				NetAmount:    camtDecimal(s.Summary.NetAmount),
+				NetIndicator: s.Summary.NetIndicator,
+				Credits:      camtTotals{Count: s.Summary.Credits, Sum: camtDecimal(s.Summary.CreditSum)},
+				Debits:       camtTotals{Count: s.Summary.Debits, Sum: camtDecimal(s.Summary.DebitSum)},
+			},
+		}
+
+		for _, b := range s.Balances {
+			stmt.Balances = append(stmt.Balances, camtBalance{
+				Type:      b.Type,
+				Amount:    camtAmount{Currency: s.Currency, Value: camtDecimal(b.Amount)},
+				Indicator: b.Indicator,
+				Date:      camtTime(b.Date),
+			})
+		}
+
+		for _, e := range s.Entries {
+			stmt.Entries = append(stmt.Entries, camtEntry{
+				Reference:     e.Reference,
+				Amount:        camtAmount{Currency: s.Currency, Value: camtDecimal(e.Amount)},
+				Indicator:     e.Indicator,
+				Status:        e.Status,
+				BookingDate:   camtTime(e.BookingDate),
+				ServicerRef:   e.Reference,
+				TransactionID: e.TransactionID,
+				Remittance:    e.Description,
+			})
+		}
+
+		doc.Message.Statements = append(doc.Message.Statements, stmt)
+	}
+
+	return doc
+}
+
+// camtTime formats a time as an ISO 20022 date time, in UTC.
+func camtTime(t time.Time) string {
+	return t.UTC().Format(camtDateTime)
+}
+
+// camtDecimal formats an amount with at least two decimals, as banks expect.
+func camtDecimal(d decimal.Decimal) string {
+	places := 0
+	if _, fraction, ok := strings.Cut(d.String(), "."); ok {
+		places = len(fraction)
+	}
+
+	return d.StringFixed(int32(max(2, places)))
+}
@@ -0,0 +1,213 @@
+package statement
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OFXBankID identifies Midaz as the financial institution of OFX statements.
+const OFXBankID = "MIDAZ"
+
+// ofxHeader is the header of OFX 1.02 files, the SGML version every
+// accounting tool imports.
+const ofxHeader = "OFXHEADER:100\r\n" +
+	"DATA:OFXSGML\r\n" +
+	"VERSION:102\r\n" +
+	"SECURITY:NONE\r\n" +
+	"ENCODING:USASCII\r\n" +
+	"CHARSET:1252\r\n" +
+	"COMPRESSION:NONE\r\n" +
+	"OLDFILEUID:NONE\r\n" +
+	"NEWFILEUID:NONE\r\n" +
+	"\r\n"
+
+// ofxNameLength is the longest payee name OFX 1.02 accepts.
+const ofxNameLength = 32
+
+// ofxEscaper escapes the characters OFX reserves.
+var ofxEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", " ", "\n", " ")
+
+// WriteOFX writes the document to w as an OFX 1.02 bank statement response,
+// one statement per account. Amounts are signed: debits are negative. The
+// ledger balance is left out for accounts without activity in the period,
+// whose balances are unknown.
+func (d *Document) WriteOFX(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	o := &ofxWriter{w: bw}
+
+	o.raw(ofxHeader)
+	o.open("OFX")
+	o.open("SIGNONMSGSRSV1")
+	o.open("SONRS")
+	o.status()
+	o.leaf("DTSERVER", ofxTime(d.CreatedAt))
+	o.leaf("LANGUAGE", "ENG")
+	o.close("SONRS")
+	o.close("SIGNONMSGSRSV1")
+
+	o.open("BANKMSGSRSV1")
+
+	for _, s := range d.Statements {
+		o.open("STMTTRNRS")
+		o.leaf("TRNUID", s.ID)
+		o.status()
+		o.open("STMTRS")
+		o.leaf("CURDEF", s.Currency)
+
+		o.open("BANKACCTFROM")
+		o.leaf("BANKID", OFXBankID)
+		o.leaf("ACCTID", s.AccountID)
+		o.leaf("ACCTTYPE", "CHECKING")
+		o.close("BANKACCTFROM")
+
+		o.open("BANKTRANLIST")
+		o.leaf("DTSTART", ofxTime(s.From))
+		o.leaf("DTEND", ofxTime(s.To))
+
+		for _, e := range s.Entries {
+			trnType := "CREDIT"
+			if e.Indicator == Debit {
+				trnType = "DEBIT"
+			}
+
+			o.open("STMTTRN")
+			o.leaf("TRNTYPE", trnType)
+			o.leaf("DTPOSTED", ofxTime(e.BookingDate))
+			o.leaf("TRNAMT", signedAmount(e.Amount, e.Indicator))
+			o.leaf("FITID", e.Reference)
+
+			if e.Description != "" {
+				o.leaf("NAME", strings.TrimSpace(truncate(e.Description, ofxNameLength)))
+			}
+
+			o.leaf("MEMO", e.TransactionID)
+			o.close("STMTTRN")
+		}
+
+		o.close("BANKTRANLIST")
+
+		if closing, ok := s.balance(BalanceClosingBooked); ok {
+			o.open("LEDGERBAL")
+			o.leaf("BALAMT", signedAmount(closing.Amount, closing.Indicator))
+			o.leaf("DTASOF", ofxTime(closing.Date))
+			o.close("LEDGERBAL")
+		}
+
+		o.close("STMTRS")
+		o.close("STMTTRNRS")
+	}
+
+	o.close("BANKMSGSRSV1")
+	o.close("OFX")
+
+	if o.err != nil {
+		return o.err
+	}
+
+	return bw.Flush()
+}
+
+// WriteQIF writes the document to w as a QIF bank file. With several
+// statements, each one is preceded by an account header naming its account,
+// so the tools import them into separate accounts. Dates are in the US
+// format the tools expect, MM/DD/YYYY.
+func (d *Document) WriteQIF(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	for _, s := range d.Statements {
+		if len(d.Statements) > 1 {
+			name := s.AccountAlias
+			if name == "" {
+				name = s.AccountID
+			}
+
+			fmt.Fprintf(bw, "!Account\nN%s\nTBank\n^\n", qifText(name))
+		}
+
+		bw.WriteString("!Type:Bank\n")
+
+		for _, e := range s.Entries {
+			fmt.Fprintf(bw, "D%s\nT%s\n", e.BookingDate.UTC().Format("01/02/2006"), signedAmount(e.Amount, e.Indicator))
+
+			if e.Description != "" {
+				fmt.Fprintf(bw, "P%s\n", qifText(e.Description))
+			}
+
+			fmt.Fprintf(bw, "M%s\n^\n", qifText(e.TransactionID))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// balance returns the balance of type typ.
+func (s *Statement) balance(typ string) (Balance, bool) {
+	for _, b := range s.Balances {
+		if b.Type == typ {
+			return b, true
+		}
+	}
+
+	return Balance{}, false
+}
+
+// ofxWriter writes OFX SGML elements, keeping the first write error.
+type ofxWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (o *ofxWriter) raw(s string) {
+	if o.err == nil {
+		_, o.err = o.w.WriteString(s)
+	}
+}
+
+func (o *ofxWriter) open(tag string)  { o.raw("<" + tag + ">\r\n") }
+func (o *ofxWriter) close(tag string) { o.raw("</" + tag + ">\r\n") }
+
+func (o *ofxWriter) leaf(tag, value string) {
+	o.raw("<" + tag + ">" + ofxEscaper.Replace(value) + "</" + tag + ">\r\n")
+}
+
+// status writes the successful status of a response.
+func (o *ofxWriter) status() {
+	o.open("STATUS")
+	o.leaf("CODE", "0")
+	o.leaf("SEVERITY", "INFO")
+	o.close("STATUS")
+}
+
+// ofxTime formats a time as an OFX date time, in GMT.
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
+
+// signedAmount formats an amount negated for debits, with two decimals at least.
+func signedAmount(amount decimal.Decimal, indicator Indicator) string {
+	if indicator == Debit {
+		amount = amount.Neg()
+	}
+
+	return camtDecimal(amount)
+}
+
+// qifText flattens a value to a single QIF line.
+func qifText(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// truncate cuts s to n characters.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+
+	return string(runes[:n])
+}
@@ -0,0 +1,75 @@
+package statement
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDocument() *Document {
+	booked := time.Date(2026, 9, 1, 10, 30, 0, 0, time.UTC)
+
+	return &Document{
+		MessageID: "STMT-ledger-20260901-20260903",
+		CreatedAt: time.Date(2026, 9, 3, 6, 0, 0, 0, time.UTC),
+		Statements: []Statement{
+			{
+				ID: "checking-20260901", From: from, To: to,
+				AccountID: "checking", AccountAlias: "@checking", Currency: "USD",
+				Balances: []Balance{
+					{Type: BalanceOpeningBooked, Amount: decimal.NewFromInt(100), Indicator: Credit, Date: from},
+					{Type: BalanceClosingBooked, Amount: decimal.RequireFromString("80.5"), Indicator: Credit, Date: to},
+				},
+				Entries: []Entry{
+					{Reference: "o1", TransactionID: "tx-1", Amount: decimal.RequireFromString("19.5"), Indicator: Debit, BookingDate: booked, Description: "Coffee & snacks for the <sales> team offsite"},
+					{Reference: "o2", TransactionID: "tx-2", Amount: decimal.NewFromInt(0), Indicator: Credit, BookingDate: booked},
+				},
+			},
+			{ID: "idle-20260901", From: from, To: to, AccountID: "idle", Currency: "USD"},
+		},
+	}
+}
+
+func TestWriteOFX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testDocument().Write(&buf, FormatOFX))
+
+	ofx := buf.String()
+	assert.True(t, strings.HasPrefix(ofx, "OFXHEADER:100\r\nDATA:OFXSGML\r\n"))
+	assert.Contains(t, ofx, "<DTSERVER>20260903060000.000[0:GMT]</DTSERVER>")
+	assert.Contains(t, ofx, "<TRNUID>checking-20260901</TRNUID>")
+	assert.Contains(t, ofx, "<CURDEF>USD</CURDEF>")
+	assert.Contains(t, ofx, "<BANKID>MIDAZ</BANKID>\r\n<ACCTID>checking</ACCTID>")
+	assert.Contains(t, ofx, "<TRNTYPE>DEBIT</TRNTYPE>\r\n<DTPOSTED>20260901103000.000[0:GMT]</DTPOSTED>\r\n<TRNAMT>-19.50</TRNAMT>\r\n<FITID>o1</FITID>")
+	assert.Contains(t, ofx, "<NAME>Coffee &amp; snacks for the &lt;sales&gt;</NAME>")
+	assert.Contains(t, ofx, "<TRNAMT>0.00</TRNAMT>")
+	assert.Contains(t, ofx, "<LEDGERBAL>\r\n<BALAMT>80.50</BALAMT>\r\n<DTASOF>20260903000000.000[0:GMT]</DTASOF>")
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("<LEDGERBAL>")), "the idle account has no ledger balance")
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("<STMTTRNRS>")))
+	assert.Contains(t, ofx, "</BANKMSGSRSV1>\r\n</OFX>\r\n")
+}
+
+func TestWriteQIF(t *testing.T) {
+	doc := testDocument()
+
+	var buf bytes.Buffer
+	require.NoError(t, doc.Write(&buf, FormatQIF))
+
+	assert.Equal(t, "!Account\nN@checking\nTBank\n^\n"+
+		"!Type:Bank\n"+
+		"D09/01/2026\nT-19.50\nPCoffee & snacks for the <sales> team offsite\nMtx-1\n^\n"+
+		"D09/01/2026\nT0.00\nMtx-2\n^\n"+
+		"!Account\nNidle\nTBank\n^\n"+
+		"!Type:Bank\n", buf.String())
+
+	doc.Statements = doc.Statements[:1]
+
+	buf.Reset()
+	require.NoError(t, doc.WriteQIF(&buf))
+	assert.True(t, strings.HasPrefix(buf.String(), "!Type:Bank\n"))
+}
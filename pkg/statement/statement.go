@@ -0,0 +1,392 @@
+// Package statement renders the activity of Midaz accounts as bank
+// statements, in a structure inspired by the ISO 20022 camt.053 "Bank to
+// Customer Statement" message, for downstream banking systems that expect
+// standard statement formats.
+//
+// A statement covers one account over a period: its opening and closing
+// booked balances, a summary of its credits and debits, and one entry per
+// debit or credit operation. Booked balances are the available plus on-hold
+// amounts, so holds and their releases move no money and have no entry.
+// Statements are written as camt.053-like XML or as JSON, and as OFX or QIF
+// files for the accounting tools of small businesses.
+//
+// Example:
+//
+//	doc, err := statement.NewExporter(client.Entity).
+//	    Export(ctx, orgID, ledgerID, from, to, f, statement.FormatXML, accountID)
+//
+// The XML follows the element names and nesting of camt.053.001.08 for the
+// parts Midaz has data for; it is not validated against the ISO schema.
+package statement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+)
+
+// Format is the encoding of a statement document.
+type Format string
+
+// Supported formats.
+const (
+	FormatXML  Format = "xml"
+	FormatJSON Format = "json"
+	FormatOFX  Format = "ofx"
+	FormatQIF  Format = "qif"
+)
+
+// Indicator tells whether an amount is a credit or a debit, with the ISO 20022 codes.
+type Indicator string
+
+// Credit and debit indicators.
+const (
+	Credit Indicator = "CRDT"
+	Debit  Indicator = "DBIT"
+)
+
+// Balance types, with the ISO 20022 codes.
+const (
+	BalanceOpeningBooked = "OPBD"
+	BalanceClosingBooked = "CLBD"
+)
+
+// EntryStatusBooked is the status of the entries: Midaz operations are booked.
+const EntryStatusBooked = "BOOK"
+
+// ErrInconsistent is returned when the entries of a statement don't explain
+// the change between its opening and closing balances.
+var ErrInconsistent = errors.New("statement entries don't match its balances")
+
+// Balance is a booked balance of a statement.
+type Balance struct {
+	// Type is BalanceOpeningBooked or BalanceClosingBooked
+	Type      string          `json:"type"`
+	Amount    decimal.Decimal `json:"amount"`
+	Indicator Indicator       `json:"indicator"`
+	Date      time.Time       `json:"date"`
+}
+
+// Entry is an operation of the account.
+type Entry struct {
+	// Reference is the operation ID
+	Reference     string          `json:"reference"`
+	TransactionID string          `json:"transactionId"`
+	Amount        decimal.Decimal `json:"amount"`
+	Indicator     Indicator       `json:"indicator"`
+	Status        string          `json:"status"`
+	BookingDate   time.Time       `json:"bookingDate"`
+	Description   string          `json:"description,omitempty"`
+}
+
+// Summary totals the entries of a statement.
+type Summary struct {
+	Entries int             `json:"entries"`
+	Sum     decimal.Decimal `json:"sum"`
+
+	// NetAmount and NetIndicator are the credits minus the debits
+	NetAmount    decimal.Decimal `json:"netAmount"`
+	NetIndicator Indicator       `json:"netIndicator"`
+
+	Credits   int             `json:"credits"`
+	CreditSum decimal.Decimal `json:"creditSum"`
+	Debits    int             `json:"debits"`
+	DebitSum  decimal.Decimal `json:"debitSum"`
+}
+
+// Statement is the activity of an account over a period.
+type Statement struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// From and To bound the period, From included and To excluded
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	AccountID    string `json:"accountId"`
+	AccountAlias string `json:"accountAlias,omitempty"`
+	AccountName  string `json:"accountName,omitempty"`
+	Currency     string `json:"currency"`
+
+	// Balances holds the opening and closing booked balances, empty when the
+	// account had no operation in the period
+	Balances []Balance `json:"balances"`
+	Summary  Summary   `json:"summary"`
+	Entries  []Entry   `json:"entries"`
+}
+
+// Document is a statement message, grouping the statements of several accounts.
+type Document struct {
+	MessageID  string      `json:"messageId"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	Statements []Statement `json:"statements"`
+}
+
+// Exporter builds statements from the operations of accounts.
+type Exporter struct {
+	accounts   entities.AccountsReader
+	operations entities.OperationsReader
+	pageSize   int
+	now        func() time.Time
+}
+
+// NewExporter creates an Exporter reading accounts and operations through e.
+func NewExporter(e *entities.Entity) *Exporter {
+	x := &Exporter{pageSize: models.MaxLimit, now: time.Now}
+
+	if e != nil {
+		x.accounts, x.operations = e.Accounts, e.Operations
+	}
+
+	return x
+}
+
+// WithPageSize sets the number of operations listed per request.
+func (x *Exporter) WithPageSize(size int) *Exporter {
+	if size > 0 {
+		x.pageSize = size
+	}
+
+	return x
+}
+
+// Export generates the statements of accountIDs over [from, to) and writes
+// them to w in format.
+func (x *Exporter) Export(ctx context.Context, orgID, ledgerID string, from, to time.Time, w io.Writer, format Format, accountIDs ...string) (*Document, error) {
+	doc, err := x.Generate(ctx, orgID, ledgerID, from, to, accountIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := doc.Write(w, format); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// Generate builds the statements of accountIDs over [from, to). It returns
+// ErrInconsistent when the operations listed for an account don't add up to
+// the change of its balances.
+func (x *Exporter) Generate(ctx context.Context, orgID, ledgerID string, from, to time.Time, accountIDs ...string) (*Document, error) {
+	if x.accounts == nil || x.operations == nil {
+		return nil, errors.New("entities not initialized for statements")
+	}
+
+	if len(accountIDs) == 0 {
+		return nil, errors.New("at least one account is required")
+	}
+
+	if !from.Before(to) {
+		return nil, fmt.Errorf("statement period is empty: %s to %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	now := x.now().UTC()
+	doc := &Document{
+		MessageID: fmt.Sprintf("STMT-%s-%s-%s", ledgerID, from.UTC().Format("20060102"), to.UTC().Format("20060102")),
+		CreatedAt: now,
+	}
+
+	for _, accountID := range accountIDs {
+		stmt, err := x.statement(ctx, orgID, ledgerID, accountID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("statement of account %s: %w", accountID, err)
+		}
+
+		stmt.CreatedAt = now
+		doc.Statements = append(doc.Statements, *stmt)
+	}
+
+	return doc, nil
+}
+
+// statement builds the statement of an account.
+func (x *Exporter) statement(ctx context.Context, orgID, ledgerID, accountID string, from, to time.Time) (*Statement, error) {
+	account, err := x.accounts.GetAccount(ctx, orgID, ledgerID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	operations, err := x.listOperations(ctx, orgID, ledgerID, accountID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := &Statement{
+		ID:          fmt.Sprintf("%s-%s", accountID, from.UTC().Format("20060102")),
+		From:        from,
+		To:          to,
+		AccountID:   accountID,
+		AccountName: account.Name,
+		Currency:    account.AssetCode,
+		Balances:    []Balance{},
+		Entries:     []Entry{},
+	}
+
+	if account.Alias != nil {
+		stmt.AccountAlias = *account.Alias
+	}
+
+	net := decimal.Zero
+
+	for _, op := range operations {
+		indicator, ok := indicatorOf(op.Type)
+		if !ok || op.Amount.Value == nil {
+			continue
+		}
+
+		amount := op.Amount.Value.Abs()
+		stmt.Entries = append(stmt.Entries, Entry{
+			Reference:     op.ID,
+			TransactionID: op.TransactionID,
+			Amount:        amount,
+			Indicator:     indicator,
+			Status:        EntryStatusBooked,
+			BookingDate:   op.CreatedAt,
+			Description:   op.Description,
+		})
+
+		s := &stmt.Summary
+		s.Entries++
+		s.Sum = s.Sum.Add(amount)
+
+		if indicator == Credit {
+			s.Credits++
+			s.CreditSum = s.CreditSum.Add(amount)
+			net = net.Add(amount)
+		} else {
+			s.Debits++
+			s.DebitSum = s.DebitSum.Add(amount)
+			net = net.Sub(amount)
+		}
+	}
+
+	stmt.Summary.NetAmount, stmt.Summary.NetIndicator = signed(net)
+
+	opening, closing, ok := bookedBalances(operations)
+	if !ok {
+		return stmt, nil
+	}
+
+	if !closing.Sub(opening).Equal(net) {
+		return nil, fmt.Errorf("%w: opening %s, closing %s, net entries %s", ErrInconsistent, opening, closing, net)
+	}
+
+	stmt.Balances = append(stmt.Balances, balanceOf(BalanceOpeningBooked, opening, from), balanceOf(BalanceClosingBooked, closing, to))
+
+	return stmt, nil
+}
+
+// listOperations lists the operations of an account created in [from, to),
+// oldest first.
+func (x *Exporter) listOperations(ctx context.Context, orgID, ledgerID, accountID string, from, to time.Time) ([]models.Operation, error) {
+	// the date range of the API is inclusive and in days: list the days
+	// covering the period, then keep the operations within it
+	startDate := from.UTC().Format("2006-01-02")
+	endDate := to.Add(-time.Nanosecond).UTC().Format("2006-01-02")
+
+	var operations []models.Operation
+
+	opts := models.NewListOptions().WithLimit(x.pageSize)
+
+	for opts != nil {
+		page, err := x.operations.ListOperations(ctx, orgID, ledgerID, accountID, opts.WithDateRange(startDate, endDate))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, op := range page.Items {
+			if !op.CreatedAt.Before(from) && op.CreatedAt.Before(to) {
+				operations = append(operations, op)
+			}
+		}
+
+		if len(page.Items) == 0 {
+			break
+		}
+
+		opts = page.Pagination.NextPageOptions()
+	}
+
+	sort.SliceStable(operations, func(i, j int) bool {
+		return operations[i].CreatedAt.Before(operations[j].CreatedAt)
+	})
+
+	return operations, nil
+}
+
+// bookedBalances returns the booked balance of the account before its first
+// operation and after its last one, adding up its balances. ok is false when
+// the operations carry no balances.
+func bookedBalances(operations []models.Operation) (opening, closing decimal.Decimal, ok bool) {
+	seen := map[string]bool{}
+	last := map[string]models.OperationBalance{}
+
+	for _, op := range operations {
+		if op.Balance.IsEmpty() || op.BalanceAfter.IsEmpty() {
+			return decimal.Zero, decimal.Zero, false
+		}
+
+		if !seen[op.BalanceID] {
+			seen[op.BalanceID] = true
+			opening = opening.Add(booked(op.Balance))
+		}
+
+		last[op.BalanceID] = op.BalanceAfter
+	}
+
+	for _, balance := range last {
+		closing = closing.Add(booked(balance))
+	}
+
+	return opening, closing, len(operations) > 0
+}
+
+// booked returns the available plus on-hold amount of a balance.
+func booked(b models.OperationBalance) decimal.Decimal {
+	total := decimal.Zero
+
+	if b.Available != nil {
+		total = total.Add(*b.Available)
+	}
+
+	if b.OnHold != nil {
+		total = total.Add(*b.OnHold)
+	}
+
+	return total
+}
+
+// indicatorOf maps an operation type to its indicator; holds and releases have none.
+func indicatorOf(operationType string) (Indicator, bool) {
+	switch models.OperationType(operationType) {
+	case models.OperationTypeCredit:
+		return Credit, true
+	case models.OperationTypeDebit:
+		return Debit, true
+	default:
+		return "", false
+	}
+}
+
+// signed splits an amount into its absolute value and indicator.
+func signed(amount decimal.Decimal) (decimal.Decimal, Indicator) {
+	if amount.IsNegative() {
+		return amount.Neg(), Debit
+	}
+
+	return amount, Credit
+}
+
+// balanceOf returns a balance of type typ.
+func balanceOf(typ string, amount decimal.Decimal, date time.Time) Balance {
+	abs, indicator := signed(amount)
+	return Balance{Type: typ, Amount: abs, Indicator: indicator, Date: date}
+}
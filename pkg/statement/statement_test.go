@@ -0,0 +1,202 @@
+package statement
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAccounts struct {
+	entities.AccountsService
+}
+
+func (fakeAccounts) GetAccount(_ context.Context, _, _, id string) (*models.Account, error) {
+	alias := "@" + id
+	return &models.Account{ID: id, Name: "Account " + id, AssetCode: "BRL", Alias: &alias}, nil
+}
+
+// fakeOperations pages the operations of each account with a cursor.
+type fakeOperations struct {
+	entities.OperationsService
+
+	operations map[string][]models.Operation
+	requests   []models.ListOptions
+}
+
+func (f *fakeOperations) ListOperations(_ context.Context, _, _, accountID string, opts *models.ListOptions) (*models.ListResponse[models.Operation], error) {
+	f.requests = append(f.requests, *opts)
+
+	start := 0
+	if opts.Cursor != "" {
+		start, _ = strconv.Atoi(opts.Cursor)
+	}
+
+	ops := f.operations[accountID]
+	end := min(start+opts.Limit, len(ops))
+
+	resp := &models.ListResponse[models.Operation]{Items: ops[start:end], Pagination: models.Pagination{Limit: opts.Limit}}
+	if end < len(ops) {
+		resp.Pagination.NextCursor = strconv.Itoa(end)
+	}
+
+	return resp, nil
+}
+
+func amount(s string) *decimal.Decimal {
+	d := decimal.RequireFromString(s)
+	return &d
+}
+
+func operation(id, typ, value, at string, before, after [2]string) models.Operation {
+	createdAt, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		panic(err)
+	}
+
+	return models.Operation{
+		ID:            id,
+		TransactionID: "tx-" + id,
+		Type:          typ,
+		Description:   "operation " + id,
+		AssetCode:     "BRL",
+		Amount:        models.Amount{Value: amount(value)},
+		Balance:       models.OperationBalance{Available: amount(before[0]), OnHold: amount(before[1])},
+		BalanceAfter:  models.OperationBalance{Available: amount(after[0]), OnHold: amount(after[1])},
+		BalanceID:     "b1",
+		CreatedAt:     createdAt,
+	}
+}
+
+var (
+	from = time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	to   = time.Date(2026, 9, 3, 0, 0, 0, 0, time.UTC)
+)
+
+func testExporter(ops *fakeOperations) *Exporter {
+	x := NewExporter(&entities.Entity{Accounts: fakeAccounts{}, Operations: ops}).WithPageSize(2)
+	x.now = func() time.Time { return time.Date(2026, 9, 3, 6, 0, 0, 0, time.UTC) }
+
+	return x
+}
+
+func checkingOperations() []models.Operation {
+	return []models.Operation{
+		operation("o0", "CREDIT", "5", "2026-08-31T23:00:00Z", [2]string{"95", "0"}, [2]string{"100", "0"}),
+		operation("o3", "DEBIT", "20", "2026-09-02T09:00:00Z", [2]string{"130.5", "20"}, [2]string{"130.5", "0"}),
+		operation("o1", "CREDIT", "50.5", "2026-09-01T10:00:00Z", [2]string{"100", "0"}, [2]string{"150.5", "0"}),
+		operation("o2", "ON_HOLD", "20", "2026-09-01T12:00:00Z", [2]string{"150.5", "0"}, [2]string{"130.5", "20"}),
+		operation("o4", "CREDIT", "1", "2026-09-03T00:00:00Z", [2]string{"130.5", "0"}, [2]string{"131.5", "0"}),
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	ops := &fakeOperations{operations: map[string][]models.Operation{"checking": checkingOperations()}}
+
+	doc, err := testExporter(ops).Generate(context.Background(), "org", "ledger", from, to, "checking")
+	require.NoError(t, err)
+
+	assert.Equal(t, "STMT-ledger-20260901-20260903", doc.MessageID)
+	require.Len(t, doc.Statements, 1)
+
+	stmt := doc.Statements[0]
+	assert.Equal(t, "checking-20260901", stmt.ID)
+	assert.Equal(t, "@checking", stmt.AccountAlias)
+	assert.Equal(t, "BRL", stmt.Currency)
+
+	require.Len(t, stmt.Entries, 2)
+	assert.Equal(t, "o1", stmt.Entries[0].Reference)
+	assert.Equal(t, Credit, stmt.Entries[0].Indicator)
+	assert.Equal(t, "o3", stmt.Entries[1].Reference)
+	assert.Equal(t, Debit, stmt.Entries[1].Indicator)
+	assert.Equal(t, EntryStatusBooked, stmt.Entries[1].Status)
+
+	assert.Equal(t, 2, stmt.Summary.Entries)
+	assert.Equal(t, "70.5", stmt.Summary.Sum.String())
+	assert.Equal(t, "30.5", stmt.Summary.NetAmount.String())
+	assert.Equal(t, Credit, stmt.Summary.NetIndicator)
+	assert.Equal(t, 1, stmt.Summary.Debits)
+
+	require.Len(t, stmt.Balances, 2)
+	assert.Equal(t, BalanceOpeningBooked, stmt.Balances[0].Type)
+	assert.Equal(t, "100", stmt.Balances[0].Amount.String())
+	assert.Equal(t, BalanceClosingBooked, stmt.Balances[1].Type)
+	assert.Equal(t, "130.5", stmt.Balances[1].Amount.String())
+
+	// The days covering the period are listed, page by page
+	require.Len(t, ops.requests, 3)
+	assert.Equal(t, "2026-09-01", ops.requests[0].StartDate)
+	assert.Equal(t, "2026-09-02", ops.requests[2].EndDate)
+	assert.Equal(t, "4", ops.requests[2].Cursor)
+}
+
+func TestGenerateErrors(t *testing.T) {
+	ops := &fakeOperations{operations: map[string][]models.Operation{"checking": checkingOperations()}}
+	x := testExporter(ops)
+	ctx := context.Background()
+
+	// o3 ends on a balance its amount doesn't lead to, as if an operation were missing
+	ops.operations["checking"][1].BalanceAfter.Available = amount("120.5")
+
+	_, err := x.Generate(ctx, "org", "ledger", from, to, "checking")
+	require.ErrorIs(t, err, ErrInconsistent)
+
+	_, err = x.Generate(ctx, "org", "ledger", from, to)
+	require.Error(t, err)
+
+	_, err = x.Generate(ctx, "org", "ledger", to, from, "checking")
+	require.Error(t, err)
+
+	_, err = NewExporter(nil).Generate(ctx, "org", "ledger", from, to, "checking")
+	require.Error(t, err)
+}
+
+func TestWrite(t *testing.T) {
+	ops := &fakeOperations{operations: map[string][]models.Operation{
+		"external": {
+			operation("e1", "DEBIT", "50", "2026-09-01T10:00:00Z", [2]string{"-100", "0"}, [2]string{"-150", "0"}),
+		},
+		"idle": nil,
+	}}
+
+	var buf bytes.Buffer
+
+	doc, err := testExporter(ops).Export(context.Background(), "org", "ledger", from, to, &buf, FormatXML, "external", "idle")
+	require.NoError(t, err)
+
+	xml := buf.String()
+	assert.Contains(t, xml, `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08">`)
+	assert.Contains(t, xml, "<MsgId>STMT-ledger-20260901-20260903</MsgId>")
+	assert.Contains(t, xml, "<Cd>OPBD</Cd>")
+	assert.Contains(t, xml, `<Amt Ccy="BRL">100.00</Amt>`)
+	assert.Contains(t, xml, `<Amt Ccy="BRL">150.00</Amt>`)
+	assert.Contains(t, xml, "<CdtDbtInd>DBIT</CdtDbtInd>")
+	assert.Contains(t, xml, "<TxId>tx-e1</TxId>")
+	assert.Contains(t, xml, "<Ustrd>operation e1</Ustrd>")
+	assert.Contains(t, xml, "<BookgDt>\n          <DtTm>2026-09-01T10:00:00.000Z</DtTm>")
+
+	// An account without activity has no balances nor entries
+	idle := doc.Statements[1]
+	assert.Empty(t, idle.Balances)
+	assert.Empty(t, idle.Entries)
+	assert.Equal(t, "0", idle.Summary.NetAmount.String())
+
+	buf.Reset()
+	require.NoError(t, doc.Write(&buf, FormatJSON))
+
+	var decoded Document
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, doc.MessageID, decoded.MessageID)
+	assert.Equal(t, Debit, decoded.Statements[0].Balances[1].Indicator)
+	assert.True(t, decoded.Statements[0].Balances[1].Amount.Equal(decimal.NewFromInt(150)))
+
+	assert.Error(t, doc.Write(&buf, Format("pdf")))
+}
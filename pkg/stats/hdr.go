@@ -0,0 +1,425 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// Histogram is a High Dynamic Range (HDR) histogram: it records integer values
+// between a lowest and a highest trackable value with a fixed number of
+// significant decimal digits, using a fixed amount of memory whatever the
+// number of recorded values. It is safe for concurrent use.
+//
+// The layout and the percentile output follow HdrHistogram, so the files
+// written by WritePercentileDistribution can be loaded into the standard HDR
+// plotting and comparison tools.
+type Histogram struct {
+	mu sync.Mutex
+
+	lowest  int64
+	highest int64
+	sigfigs int
+
+	unitMagnitude               int
+	subBucketHalfCountMagnitude int
+	subBucketCount              int
+	subBucketHalfCount          int
+	subBucketMask               int64
+	bucketCount                 int
+
+	counts []int64
+	total  int64
+	min    int64
+	max    int64
+}
+
+// NewHistogram creates a histogram tracking values in [lowest, highest] with
+// sigfigs significant decimal digits (1 to 5). lowest must be at least 1 and
+// highest at least twice lowest.
+func NewHistogram(lowest, highest int64, sigfigs int) (*Histogram, error) {
+	if lowest < 1 {
+		return nil, errors.New("lowest trackable value must be at least 1")
+	}
+
+	if highest < 2*lowest {
+		return nil, errors.New("highest trackable value must be at least twice the lowest")
+	}
+
+	if sigfigs < 1 || sigfigs > 5 {
+		return nil, errors.New("significant figures must be between 1 and 5")
+	}
+
+	largestSingleUnit := 2 * int64(math.Pow10(sigfigs))
+	subBucketCountMagnitude := int(math.Ceil(math.Log2(float64(largestSingleUnit))))
+	subBucketHalfCountMagnitude := max(subBucketCountMagnitude, 1) - 1
+	unitMagnitude := int(math.Floor(math.Log2(float64(lowest))))
+	subBucketCount := 1 << (subBucketHalfCountMagnitude + 1)
+
+	// buckets needed so that the top of the last one covers highest
+	smallestUntrackable := int64(subBucketCount) << unitMagnitude
+	bucketCount := 1
+
+	for smallestUntrackable <= highest {
+		if smallestUntrackable > math.MaxInt64/2 {
+			bucketCount++
+			break
+		}
+
+		smallestUntrackable <<= 1
+		bucketCount++
+	}
+
+	return &Histogram{
+		lowest:                      lowest,
+		highest:                     highest,
+		sigfigs:                     sigfigs,
+		unitMagnitude:               unitMagnitude,
+		subBucketHalfCountMagnitude: subBucketHalfCountMagnitude,
+		subBucketCount:              subBucketCount,
+		subBucketHalfCount:          subBucketCount / 2,
+		subBucketMask:               int64(subBucketCount-1) << unitMagnitude,
+		bucketCount:                 bucketCount,
+		counts:                      make([]int64, (bucketCount+1)*(subBucketCount/2)),
+	}, nil
+}
+
+// NewLatencyHistogram creates a histogram of durations in microseconds, from
+// 1µs to 1h with 3 significant digits, the usual setup for request latencies.
+func NewLatencyHistogram() *Histogram {
+	h, _ := NewHistogram(1, time.Hour.Microseconds(), 3) //nolint:errcheck // constant, valid arguments
+
+	return h
+}
+
+// Record adds a value. Values outside the trackable range are rejected.
+func (h *Histogram) Record(value int64) error {
+	return h.RecordN(value, 1)
+}
+
+// RecordN adds count occurrences of a value.
+func (h *Histogram) RecordN(value, count int64) error {
+	if value < 0 || value > h.highest {
+		return fmt.Errorf("value %d is outside the trackable range [0, %d]", value, h.highest)
+	}
+
+	if count <= 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.counts[h.countsIndexFor(value)] += count
+
+	if h.total == 0 || value < h.min {
+		h.min = value
+	}
+
+	if value > h.max {
+		h.max = value
+	}
+
+	h.total += count
+
+	return nil
+}
+
+// RecordDuration adds a duration in microseconds, clamped to the trackable
+// range so that outliers are kept at the highest value instead of lost.
+func (h *Histogram) RecordDuration(d time.Duration) {
+	_ = h.Record(min(max(d.Microseconds(), 0), h.highest)) //nolint:errcheck // clamped into range
+}
+
+// Merge adds the values of other, which must track the same range.
+func (h *Histogram) Merge(other *Histogram) error {
+	if other.lowest != h.lowest || other.highest != h.highest || other.sigfigs != h.sigfigs {
+		return errors.New("histograms track different ranges")
+	}
+
+	other.mu.Lock()
+	counts := append([]int64(nil), other.counts...)
+	total, minValue, maxValue := other.total, other.min, other.max
+	other.mu.Unlock()
+
+	if total == 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, c := range counts {
+		h.counts[i] += c
+	}
+
+	if h.total == 0 || minValue < h.min {
+		h.min = minValue
+	}
+
+	h.max = max(h.max, maxValue)
+	h.total += total
+
+	return nil
+}
+
+// TotalCount returns the number of recorded values.
+func (h *Histogram) TotalCount() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.total
+}
+
+// Min returns the smallest recorded value, zero when empty.
+func (h *Histogram) Min() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.total == 0 {
+		return 0
+	}
+
+	return h.lowestEquivalentValue(h.min)
+}
+
+// Max returns the largest recorded value at the histogram precision, zero when empty.
+func (h *Histogram) Max() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.total == 0 {
+		return 0
+	}
+
+	return h.highestEquivalentValue(h.max)
+}
+
+// Mean returns the mean of the recorded values.
+func (h *Histogram) Mean() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.mean()
+}
+
+// StdDev returns the standard deviation of the recorded values.
+func (h *Histogram) StdDev() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.stdDev()
+}
+
+// ValueAtPercentile returns the value below which the given percentage of the
+// recorded values fall, e.g. 99.9 for p999.
+func (h *Histogram) ValueAtPercentile(percentile float64) int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.total == 0 {
+		return 0
+	}
+
+	percentile = min(max(percentile, 0), 100)
+	countAtPercentile := max(int64(percentile/100*float64(h.total)+0.5), 1)
+
+	var seen int64
+
+	for i, c := range h.counts {
+		seen += c
+		if seen >= countAtPercentile {
+			value := h.valueFromCountsIndex(i)
+			if percentile == 0 {
+				return h.lowestEquivalentValue(value)
+			}
+
+			return h.highestEquivalentValue(value)
+		}
+	}
+
+	return 0
+}
+
+// WritePercentileDistribution writes the percentile distribution in the
+// HdrHistogram text format (.hgrm). Values are divided by scale, e.g. 1000 to
+// write microseconds as milliseconds; ticksPerHalfDistance sets the number of
+// rows per halving of the remaining distance to 100% (5 is the HdrHistogram
+// default).
+func (h *Histogram) WritePercentileDistribution(w io.Writer, ticksPerHalfDistance int, scale float64) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if scale <= 0 {
+		scale = 1
+	}
+
+	if ticksPerHalfDistance <= 0 {
+		ticksPerHalfDistance = 5
+	}
+
+	if _, err := fmt.Fprintf(w, "%12s %14s %10s %14s\n\n", "Value", "Percentile", "TotalCount", "1/(1-Percentile)"); err != nil {
+		return err
+	}
+
+	for _, row := range h.percentileRows(ticksPerHalfDistance) {
+		var err error
+
+		if row.percentile < 100 {
+			_, err = fmt.Fprintf(w, "%12.3f %2.12f %10d %14.2f\n",
+				float64(row.value)/scale, row.percentile/100, row.count, 1/(1-row.percentile/100))
+		} else {
+			_, err = fmt.Fprintf(w, "%12.3f %2.12f %10d\n", float64(row.value)/scale, row.percentile/100, row.count)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	maxValue := 0.0
+	if h.total > 0 {
+		maxValue = float64(h.highestEquivalentValue(h.max)) / scale
+	}
+
+	_, err := fmt.Fprintf(w, "#[Mean    = %12.3f, StdDeviation   = %12.3f]\n#[Max     = %12.3f, Total count    = %12d]\n#[Buckets = %12d, SubBuckets     = %12d]\n",
+		h.mean()/scale, h.stdDev()/scale, maxValue, h.total, h.bucketCount, h.subBucketCount)
+
+	return err
+}
+
+// percentileRow is a row of the percentile distribution.
+type percentileRow struct {
+	value      int64
+	percentile float64
+	count      int64
+}
+
+// percentileRows walks the recorded values like the HdrHistogram percentile
+// iterator: the reporting step halves every time the remaining distance to
+// 100% halves, and the distribution ends with a row at 100%.
+func (h *Histogram) percentileRows(ticksPerHalfDistance int) []percentileRow {
+	if h.total == 0 {
+		return nil
+	}
+
+	var (
+		rows  []percentileRow
+		seen  int64
+		level float64
+	)
+
+	for i, c := range h.counts {
+		if c == 0 {
+			continue
+		}
+
+		seen += c
+		value := h.highestEquivalentValue(h.valueFromCountsIndex(i))
+
+		for float64(seen)*100/float64(h.total) >= level {
+			rows = append(rows, percentileRow{value: value, percentile: level, count: seen})
+
+			if seen == h.total {
+				return append(rows, percentileRow{value: value, percentile: 100, count: seen})
+			}
+
+			ticks := float64(ticksPerHalfDistance) * math.Pow(2, math.Floor(math.Log2(100/(100-level)))+1)
+			level += 100 / ticks
+		}
+	}
+
+	return rows
+}
+
+func (h *Histogram) mean() float64 {
+	if h.total == 0 {
+		return 0
+	}
+
+	sum := 0.0
+
+	for i, c := range h.counts {
+		if c > 0 {
+			sum += float64(h.medianEquivalentValue(h.valueFromCountsIndex(i))) * float64(c)
+		}
+	}
+
+	return sum / float64(h.total)
+}
+
+func (h *Histogram) stdDev() float64 {
+	if h.total == 0 {
+		return 0
+	}
+
+	mean := h.mean()
+	sum := 0.0
+
+	for i, c := range h.counts {
+		if c > 0 {
+			d := float64(h.medianEquivalentValue(h.valueFromCountsIndex(i))) - mean
+			sum += d * d * float64(c)
+		}
+	}
+
+	return math.Sqrt(sum / float64(h.total))
+}
+
+func (h *Histogram) bucketIndex(value int64) int {
+	pow2Ceiling := 64 - bits.LeadingZeros64(uint64(value|h.subBucketMask))
+
+	return pow2Ceiling - h.unitMagnitude - (h.subBucketHalfCountMagnitude + 1)
+}
+
+func (h *Histogram) subBucketIndex(value int64, bucket int) int {
+	return int(value >> uint(bucket+h.unitMagnitude))
+}
+
+func (h *Histogram) countsIndexFor(value int64) int {
+	bucket := h.bucketIndex(value)
+	sub := h.subBucketIndex(value, bucket)
+
+	return (bucket+1)<<uint(h.subBucketHalfCountMagnitude) + (sub - h.subBucketHalfCount)
+}
+
+func (h *Histogram) valueFromCountsIndex(index int) int64 {
+	bucket := (index >> uint(h.subBucketHalfCountMagnitude)) - 1
+	sub := (index & (h.subBucketHalfCount - 1)) + h.subBucketHalfCount
+
+	if bucket < 0 {
+		sub -= h.subBucketHalfCount
+		bucket = 0
+	}
+
+	return int64(sub) << uint(bucket+h.unitMagnitude)
+}
+
+func (h *Histogram) equivalentRange(value int64) int64 {
+	bucket := h.bucketIndex(value)
+	sub := h.subBucketIndex(value, bucket)
+
+	if sub >= h.subBucketCount {
+		bucket++
+	}
+
+	return 1 << uint(h.unitMagnitude+bucket)
+}
+
+func (h *Histogram) lowestEquivalentValue(value int64) int64 {
+	bucket := h.bucketIndex(value)
+
+	return int64(h.subBucketIndex(value, bucket)) << uint(bucket+h.unitMagnitude)
+}
+
+func (h *Histogram) highestEquivalentValue(value int64) int64 {
+	return h.lowestEquivalentValue(value) + h.equivalentRange(value) - 1
+}
+
+func (h *Histogram) medianEquivalentValue(value int64) int64 {
+	return h.lowestEquivalentValue(value) + h.equivalentRange(value)>>1
+}
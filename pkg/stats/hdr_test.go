@@ -0,0 +1,154 @@
+package stats
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHistogram_InvalidArguments(t *testing.T) {
+	_, err := NewHistogram(0, 1000, 3)
+	assert.Error(t, err)
+
+	_, err = NewHistogram(10, 15, 3)
+	assert.Error(t, err)
+
+	_, err = NewHistogram(1, 1000, 6)
+	assert.Error(t, err)
+}
+
+func TestHistogram_Percentiles(t *testing.T) {
+	h, err := NewHistogram(1, 3_600_000_000, 3)
+	require.NoError(t, err)
+
+	for v := int64(1); v <= 10_000; v++ {
+		require.NoError(t, h.Record(v))
+	}
+
+	assert.Equal(t, int64(10_000), h.TotalCount())
+	assert.Equal(t, int64(1), h.Min())
+	assert.InDelta(t, 10_000, h.Max(), 10)
+	assert.InDelta(t, 5000.5, h.Mean(), 5)
+	assert.InDelta(t, 2886.9, h.StdDev(), 5)
+
+	for _, tt := range []struct {
+		percentile float64
+		want       int64
+	}{
+		{50, 5000},
+		{90, 9000},
+		{99, 9900},
+		{99.9, 9990},
+		{100, 10_000},
+	} {
+		// 3 significant digits: within 0.1%
+		assert.InDelta(t, tt.want, h.ValueAtPercentile(tt.percentile), float64(tt.want)/1000+1, "p%v", tt.percentile)
+	}
+
+	assert.Equal(t, int64(1), h.ValueAtPercentile(0))
+}
+
+func TestHistogram_RecordOutOfRange(t *testing.T) {
+	h, err := NewHistogram(1, 1000, 2)
+	require.NoError(t, err)
+
+	assert.Error(t, h.Record(-1))
+	assert.Error(t, h.Record(1_000_000))
+	assert.Zero(t, h.TotalCount())
+
+	lat := NewLatencyHistogram()
+	lat.RecordDuration(2 * time.Hour)
+	lat.RecordDuration(-time.Second)
+	assert.Equal(t, int64(2), lat.TotalCount(), "durations are clamped, not lost")
+	assert.InDelta(t, time.Hour.Microseconds(), lat.Max(), float64(time.Hour.Microseconds())/1000)
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	a, b := NewLatencyHistogram(), NewLatencyHistogram()
+	a.RecordDuration(time.Millisecond)
+	b.RecordDuration(time.Second)
+	b.RecordDuration(time.Second)
+
+	require.NoError(t, a.Merge(b))
+	assert.Equal(t, int64(3), a.TotalCount())
+	assert.Equal(t, int64(1000), a.Min())
+	assert.InDelta(t, 1_000_000, a.ValueAtPercentile(50), 1000)
+
+	other, err := NewHistogram(1, 1000, 3)
+	require.NoError(t, err)
+	assert.Error(t, a.Merge(other))
+}
+
+func TestHistogram_ConcurrentRecord(t *testing.T) {
+	h := NewLatencyHistogram()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				h.RecordDuration(time.Duration(j) * time.Microsecond)
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, int64(8000), h.TotalCount())
+}
+
+func TestHistogram_WritePercentileDistribution(t *testing.T) {
+	h := NewLatencyHistogram()
+	for i := 1; i <= 1000; i++ {
+		h.RecordDuration(time.Duration(i) * time.Millisecond)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, h.WritePercentileDistribution(&buf, 5, 1000))
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	assert.Equal(t, "       Value     Percentile TotalCount 1/(1-Percentile)", lines[0])
+	assert.Empty(t, lines[1])
+
+	first := strings.Fields(lines[2])
+	assert.Equal(t, []string{"1.000", "0.000000000000", "1", "1.00"}, first)
+
+	last := strings.Fields(lines[len(lines)-4])
+	require.Len(t, last, 3, "the 100%% row has no inverted percentile")
+	assert.Equal(t, "1.000000000000", last[1])
+	assert.Equal(t, "1000", last[2])
+
+	footer := strings.Join(lines[len(lines)-3:], "\n")
+	assert.Contains(t, footer, "#[Mean    =      500.")
+	assert.Contains(t, footer, "Total count    =         1000]")
+	assert.Contains(t, footer, "#[Buckets =           22, SubBuckets     =         2048]")
+
+	// percentiles increase, and so do values and counts
+	prev := -1.0
+
+	for _, line := range lines[2 : len(lines)-3] {
+		fields := strings.Fields(line)
+		require.GreaterOrEqual(t, len(fields), 3, line)
+
+		var p float64
+		_, err := fmt.Sscan(fields[1], &p)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, p, prev, line)
+		prev = p
+	}
+}
+
+func TestHistogram_WritePercentileDistributionEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewLatencyHistogram().WritePercentileDistribution(&buf, 0, 0))
+	assert.Contains(t, buf.String(), "Total count    =            0]")
+}
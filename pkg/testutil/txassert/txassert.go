@@ -0,0 +1,446 @@
+// Package txassert provides test assertions for code that produces Midaz
+// transactions, and gold-file fixtures to keep expected transactions as
+// reviewed text.
+//
+// Assertions take a testing.TB, report failures with t.Errorf and return
+// whether they passed, like testify's assert package:
+//
+//	func TestPayroll(t *testing.T) {
+//	    input := payroll.BuildTransaction(employee)
+//
+//	    txassert.AssertBalanced(t, input)
+//	    txassert.AssertGolden(t, "payroll", input)
+//	}
+//
+// Golden files live under testdata/ next to the test. Run the tests with
+// MIDAZ_UPDATE_GOLDEN=1 to write them from the current output, then review the
+// diff before committing.
+package txassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+	"github.com/shopspring/decimal"
+)
+
+// EnvUpdateGolden is the environment variable that makes AssertGolden and
+// AssertGoldenDSL write golden files instead of comparing against them.
+const EnvUpdateGolden = "MIDAZ_UPDATE_GOLDEN"
+
+// GoldenDir is the directory, relative to the test, holding golden files.
+const GoldenDir = "testdata"
+
+var hundred = decimal.NewFromInt(100)
+
+// AssertBalanced checks that the sources and destinations of a transaction
+// input both add up to its total, per asset.
+//
+// Send-based inputs are checked against Send.Value; operation-based inputs
+// against Amount, DEBIT operations being the sources and CREDIT operations the
+// destinations.
+func AssertBalanced(t testing.TB, input *models.CreateTransactionInput) bool {
+	t.Helper()
+
+	if input == nil {
+		t.Errorf("txassert: transaction input is nil")
+		return false
+	}
+
+	legs := input.GetTransferLegs()
+
+	total, err := decimal.NewFromString(legs.Value)
+	if err != nil {
+		t.Errorf("txassert: transaction value %q is not a decimal", legs.Value)
+		return false
+	}
+
+	sources, ok := legAmounts(t, legs.Asset, legs.Sources)
+	ok = ok && checkSide(t, "sources", legs.Asset, total, sources)
+
+	destinations, destinationsOK := legAmounts(t, legs.Asset, legs.Destinations)
+
+	return destinationsOK && checkSide(t, "destinations", legs.Asset, total, destinations) && ok
+}
+
+// AssertDSLBalanced checks that the sources and destinations of a DSL input
+// both add up to its send value. Shares are taken from the send value and
+// :remaining entries receive what the other entries of their side leave.
+func AssertDSLBalanced(t testing.TB, input *models.TransactionDSLInput) bool {
+	t.Helper()
+
+	if input == nil || input.Send == nil {
+		t.Errorf("txassert: DSL input has no send")
+		return false
+	}
+
+	send := input.Send
+
+	total, err := decimal.NewFromString(send.Value)
+	if err != nil {
+		t.Errorf("txassert: send value %q is not a decimal", send.Value)
+		return false
+	}
+
+	var from, to []models.DSLFromTo
+
+	if send.Source != nil {
+		from = send.Source.From
+	}
+
+	if send.Distribute != nil {
+		to = send.Distribute.To
+	}
+
+	sources, ok := dslAmounts(t, send.Asset, total, from)
+	ok = ok && checkSide(t, "sources", send.Asset, total, sources)
+
+	destinations, destinationsOK := dslAmounts(t, send.Asset, total, to)
+
+	return destinationsOK && checkSide(t, "destinations", send.Asset, total, destinations) && ok
+}
+
+// AssertDebitsEqualCredits checks that the DEBIT and CREDIT operations of a
+// transaction move the same amount of each asset.
+func AssertDebitsEqualCredits(t testing.TB, tx *models.Transaction) bool {
+	t.Helper()
+
+	if tx == nil {
+		t.Errorf("txassert: transaction is nil")
+		return false
+	}
+
+	if len(tx.Operations) == 0 {
+		t.Errorf("txassert: transaction %s has no operations", tx.ID)
+		return false
+	}
+
+	debits := make(map[string]decimal.Decimal)
+	credits := make(map[string]decimal.Decimal)
+
+	for i, op := range tx.Operations {
+		if op.Amount.Value == nil {
+			t.Errorf("txassert: operation %d (%s) has no amount", i, op.ID)
+			return false
+		}
+
+		switch models.OperationType(strings.ToUpper(op.Type)) {
+		case models.OperationTypeDebit:
+			debits[op.AssetCode] = debits[op.AssetCode].Add(*op.Amount.Value)
+		case models.OperationTypeCredit:
+			credits[op.AssetCode] = credits[op.AssetCode].Add(*op.Amount.Value)
+		default:
+			t.Errorf("txassert: operation %d (%s) has unknown type %q", i, op.ID, op.Type)
+			return false
+		}
+	}
+
+	ok := true
+
+	for _, asset := range assetCodes(debits, credits) {
+		if !debits[asset].Equal(credits[asset]) {
+			t.Errorf("txassert: %s debits %s != credits %s", asset, debits[asset], credits[asset])
+
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// AssertGolden compares the JSON payload of v with testdata/<name>.golden.json.
+// Transaction inputs are compared as the request body the SDK sends for them;
+// other values as their JSON encoding. Keys listed in ignore, as dotted paths
+// such as "metadata.requestId", are removed from both sides first, for values
+// that change between runs.
+func AssertGolden(t testing.TB, name string, v any, ignore ...string) bool {
+	t.Helper()
+
+	got, err := payloadJSON(v, ignore)
+	if err != nil {
+		t.Errorf("txassert: failed to encode %s: %v", name, err)
+		return false
+	}
+
+	return compareGolden(t, filepath.Join(GoldenDir, name+".golden.json"), got)
+}
+
+// AssertGoldenDSL compares the DSL text of a DSL input with testdata/<name>.gold.
+func AssertGoldenDSL(t testing.TB, name string, input *models.TransactionDSLInput) bool {
+	t.Helper()
+
+	text, err := input.ToDSL()
+	if err != nil {
+		t.Errorf("txassert: failed to serialize %s: %v", name, err)
+		return false
+	}
+
+	return compareGolden(t, filepath.Join(GoldenDir, name+".gold"), []byte(text))
+}
+
+// LoadGold parses the DSL file testdata/<name>.gold as a transaction fixture,
+// failing the test if it can't be read or parsed.
+func LoadGold(t testing.TB, name string) *models.TransactionDSLInput {
+	t.Helper()
+
+	path := filepath.Join(GoldenDir, name+".gold")
+
+	content, err := os.ReadFile(path) // #nosec G304 -- test fixture path
+	if err != nil {
+		t.Fatalf("txassert: failed to read %s: %v", path, err)
+	}
+
+	input, err := models.ParseTransactionDSL(string(content))
+	if err != nil {
+		t.Fatalf("txassert: failed to parse %s: %v", path, err)
+	}
+
+	return input
+}
+
+// compareGolden compares got with the golden file, or writes it when updating.
+func compareGolden(t testing.TB, path string, got []byte) bool {
+	t.Helper()
+
+	if os.Getenv(EnvUpdateGolden) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatalf("txassert: failed to create %s: %v", filepath.Dir(path), err)
+		}
+
+		if err := os.WriteFile(path, got, 0o600); err != nil {
+			t.Fatalf("txassert: failed to write %s: %v", path, err)
+		}
+
+		return true
+	}
+
+	want, err := os.ReadFile(path) // #nosec G304 -- test fixture path
+	if err != nil {
+		t.Errorf("txassert: failed to read %s: %v (run with %s=1 to create it)", path, err, EnvUpdateGolden)
+		return false
+	}
+
+	if !bytes.Equal(bytes.TrimSpace(want), bytes.TrimSpace(got)) {
+		t.Errorf("txassert: %s differs from the golden file (run with %s=1 to update it)\n%s",
+			path, EnvUpdateGolden, lineDiff(string(want), string(got)))
+
+		return false
+	}
+
+	return true
+}
+
+// payloadJSON encodes v as indented JSON with sorted keys and the ignored paths removed.
+func payloadJSON(v any, ignore []string) ([]byte, error) {
+	switch input := v.(type) {
+	case *models.CreateTransactionInput:
+		v = input.ToLibTransaction()
+	case *models.TransactionDSLInput:
+		v = input.ToTransactionMap()
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// decoding into any sorts map keys on encoding and normalizes numbers
+	var generic any
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	for _, path := range ignore {
+		removePath(generic, strings.Split(path, "."))
+	}
+
+	out, err := json.MarshalIndent(generic, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(out, '\n'), nil
+}
+
+// removePath deletes the key at path, descending into objects and into every element of arrays.
+func removePath(v any, path []string) {
+	switch node := v.(type) {
+	case map[string]any:
+		if len(path) == 1 {
+			delete(node, path[0])
+			return
+		}
+
+		if child, ok := node[path[0]]; ok {
+			removePath(child, path[1:])
+		}
+	case []any:
+		for _, item := range node {
+			removePath(item, path)
+		}
+	}
+}
+
+// lineDiff lists the lines that differ between want and got.
+func lineDiff(want, got string) string {
+	wantLines := strings.Split(strings.TrimSpace(want), "\n")
+	gotLines := strings.Split(strings.TrimSpace(got), "\n")
+
+	var b strings.Builder
+
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+
+		if w != g {
+			fmt.Fprintf(&b, "line %d:\n  - %s\n  + %s\n", i+1, w, g)
+		}
+	}
+
+	return b.String()
+}
+
+// legAmounts parses the amounts of transfer legs.
+func legAmounts(t testing.TB, asset string, legs []validation.TransferLeg) ([]decimal.Decimal, bool) {
+	t.Helper()
+
+	amounts := make([]decimal.Decimal, 0, len(legs))
+
+	for _, leg := range legs {
+		if leg.Asset != "" && asset != "" && leg.Asset != asset {
+			t.Errorf("txassert: leg of %s uses asset %s, transaction uses %s", leg.Account, leg.Asset, asset)
+			return nil, false
+		}
+
+		value, err := decimal.NewFromString(leg.Value)
+		if err != nil {
+			t.Errorf("txassert: amount %q of %s is not a decimal", leg.Value, leg.Account)
+			return nil, false
+		}
+
+		amounts = append(amounts, value)
+	}
+
+	return amounts, true
+}
+
+// dslAmounts resolves the amounts of the entries of one side of a DSL send.
+func dslAmounts(t testing.TB, asset string, total decimal.Decimal, entries []models.DSLFromTo) ([]decimal.Decimal, bool) {
+	t.Helper()
+
+	amounts := make([]decimal.Decimal, 0, len(entries))
+	remaining := -1
+	assigned := decimal.Zero
+
+	for i, entry := range entries {
+		var amount decimal.Decimal
+
+		switch {
+		case entry.Amount != nil:
+			if entry.Amount.Asset != "" && entry.Amount.Asset != asset {
+				t.Errorf("txassert: amount of %s uses asset %s, send uses %s", entry.Account, entry.Amount.Asset, asset)
+				return nil, false
+			}
+
+			value, err := decimal.NewFromString(entry.Amount.Value)
+			if err != nil {
+				t.Errorf("txassert: amount %q of %s is not a decimal", entry.Amount.Value, entry.Account)
+				return nil, false
+			}
+
+			amount = value
+		case entry.Share != nil:
+			amount = total.Mul(decimal.NewFromInt(entry.Share.Percentage)).Div(hundred)
+			if entry.Share.PercentageOfPercentage != 0 {
+				amount = amount.Mul(decimal.NewFromInt(entry.Share.PercentageOfPercentage)).Div(hundred)
+			}
+		case entry.Remaining != "":
+			if remaining >= 0 {
+				t.Errorf("txassert: %s and %s both take the remaining amount", entries[remaining].Account, entry.Account)
+				return nil, false
+			}
+
+			remaining = i
+
+			continue
+		default:
+			t.Errorf("txassert: %s has no amount, share or remaining", entry.Account)
+			return nil, false
+		}
+
+		assigned = assigned.Add(amount)
+		amounts = append(amounts, amount)
+	}
+
+	if remaining >= 0 {
+		amounts = append(amounts, total.Sub(assigned))
+	}
+
+	return amounts, true
+}
+
+// checkSide reports a side whose amounts don't add up to the total.
+func checkSide(t testing.TB, side, asset string, total decimal.Decimal, amounts []decimal.Decimal) bool {
+	t.Helper()
+
+	if len(amounts) == 0 {
+		t.Errorf("txassert: transaction has no %s", side)
+		return false
+	}
+
+	sum := decimal.Zero
+
+	for _, amount := range amounts {
+		if amount.IsNegative() {
+			t.Errorf("txassert: %s include a negative amount %s %s", side, amount, asset)
+			return false
+		}
+
+		sum = sum.Add(amount)
+	}
+
+	if !sum.Equal(total) {
+		t.Errorf("txassert: %s add up to %s %s, transaction total is %s", side, sum, asset, total)
+		return false
+	}
+
+	return true
+}
+
+// assetCodes returns the sorted asset codes of the maps.
+func assetCodes(maps ...map[string]decimal.Decimal) []string {
+	seen := make(map[string]bool)
+
+	for _, m := range maps {
+		for asset := range m {
+			seen[asset] = true
+		}
+	}
+
+	codes := make([]string, 0, len(seen))
+	for asset := range seen {
+		codes = append(codes, asset)
+	}
+
+	sort.Strings(codes)
+
+	return codes
+}
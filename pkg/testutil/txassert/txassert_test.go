@@ -0,0 +1,125 @@
+package txassert
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder captures the failures reported by an assertion.
+type recorder struct {
+	testing.TB
+
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func sendInput(value, source string, destinations ...string) *models.CreateTransactionInput {
+	input := &models.CreateTransactionInput{
+		Send: &models.SendInput{
+			Asset:      "USD",
+			Value:      value,
+			Source:     &models.SourceInput{},
+			Distribute: &models.DistributeInput{},
+		},
+	}
+
+	input.Send.Source.From = []models.FromToInput{{Account: "@a", Amount: models.AmountInput{Asset: "USD", Value: source}}}
+
+	for i, amount := range destinations {
+		input.Send.Distribute.To = append(input.Send.Distribute.To, models.FromToInput{
+			Account: fmt.Sprintf("@b%d", i),
+			Amount:  models.AmountInput{Asset: "USD", Value: amount},
+		})
+	}
+
+	return input
+}
+
+func TestAssertBalanced(t *testing.T) {
+	assert.True(t, AssertBalanced(t, sendInput("100.00", "100", "60", "40.00")))
+
+	r := &recorder{TB: t}
+	assert.False(t, AssertBalanced(r, sendInput("100", "100", "60", "30")))
+	assert.Equal(t, []string{"txassert: destinations add up to 90 USD, transaction total is 100"}, r.errors)
+
+	r = &recorder{TB: t}
+	assert.False(t, AssertBalanced(r, sendInput("100", "90", "abc")))
+	assert.Len(t, r.errors, 2)
+
+	operations := &models.CreateTransactionInput{
+		Amount:    "10",
+		AssetCode: "BRL",
+		Operations: []models.CreateOperationInput{
+			{Type: "DEBIT", AccountID: "a", Amount: "10", AssetCode: "BRL"},
+			{Type: "CREDIT", AccountID: "b", Amount: "10", AssetCode: "BRL"},
+		},
+	}
+	assert.True(t, AssertBalanced(t, operations))
+}
+
+func TestAssertDSLBalanced(t *testing.T) {
+	input := LoadGold(t, "payment")
+	assert.True(t, AssertDSLBalanced(t, input))
+
+	input.Send.Distribute.To[0].Share.Percentage = 110
+
+	r := &recorder{TB: t}
+	assert.False(t, AssertDSLBalanced(r, input))
+	assert.Equal(t, []string{"txassert: destinations include a negative amount -10 USD"}, r.errors)
+}
+
+func TestAssertDebitsEqualCredits(t *testing.T) {
+	amount := func(v int64) models.Amount {
+		d := decimal.NewFromInt(v)
+		return models.Amount{Value: &d}
+	}
+
+	tx := &models.Transaction{
+		ID: "tx-1",
+		Operations: []models.Operation{
+			{Type: "DEBIT", AssetCode: "USD", Amount: amount(100)},
+			{Type: "CREDIT", AssetCode: "USD", Amount: amount(70)},
+			{Type: "CREDIT", AssetCode: "USD", Amount: amount(30)},
+		},
+	}
+	assert.True(t, AssertDebitsEqualCredits(t, tx))
+
+	tx.Operations = append(tx.Operations, models.Operation{Type: "CREDIT", AssetCode: "BRL", Amount: amount(5)})
+
+	r := &recorder{TB: t}
+	assert.False(t, AssertDebitsEqualCredits(r, tx))
+	assert.Equal(t, []string{"txassert: BRL debits 0 != credits 5"}, r.errors)
+}
+
+func TestAssertGolden(t *testing.T) {
+	input := sendInput("100", "100", "100")
+	input.Description = "payment"
+	input.Metadata = map[string]any{"requestId": "volatile", "invoice": "42"}
+
+	assert.True(t, AssertGolden(t, "send", input, "metadata.requestId"))
+	assert.True(t, AssertGoldenDSL(t, "payment", LoadGold(t, "payment")))
+
+	// the failure checks below must compare even when updating golden files
+	t.Setenv(EnvUpdateGolden, "")
+
+	input.Send.Distribute.To[0].Account = "@other"
+
+	r := &recorder{TB: t}
+	assert.False(t, AssertGolden(r, "send", input, "metadata.requestId"))
+	require.Len(t, r.errors, 1)
+	assert.Contains(t, r.errors[0], `+           "accountAlias": "@other"`)
+
+	r = &recorder{TB: t}
+	assert.False(t, AssertGolden(r, "missing", input))
+	assert.Contains(t, r.errors[0], EnvUpdateGolden)
+}
@@ -0,0 +1,370 @@
+package transaction
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"sort"
+	"strings"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+)
+
+// Metadata keys of the transactions merged by AggregateTransfers
+const (
+	// AggregatedCountKey holds the number of transfers merged
+	AggregatedCountKey = "aggregatedCount"
+	// AggregatedAmountsKey holds the amount of each transfer merged
+	AggregatedAmountsKey = "aggregatedAmounts"
+	// AggregatedDescriptionsKey holds the description of each transfer merged
+	AggregatedDescriptionsKey = "aggregatedDescriptions"
+	// AggregatedIdempotencyKeysKey holds the idempotency key of each transfer merged
+	AggregatedIdempotencyKeysKey = "aggregatedIdempotencyKeys"
+	// AggregatedExternalIDsKey holds the external ID of each transfer merged
+	AggregatedExternalIDsKey = "aggregatedExternalIds"
+)
+
+// AggregateOptions configures the pre-aggregation of transfers
+type AggregateOptions struct {
+	// MaxTransfers caps the transfers merged into one transaction, which
+	// bounds the size of its itemized metadata
+	// Default is 50 if not specified
+	MaxTransfers int
+	// Description is the description of the merged transactions
+	// Default is "Aggregated transfers" if not specified
+	Description string
+}
+
+// DefaultAggregateOptions returns the default aggregation options
+func DefaultAggregateOptions() *AggregateOptions {
+	return &AggregateOptions{
+		MaxTransfers: 50,
+		Description:  "Aggregated transfers",
+	}
+}
+
+// Aggregation is the outcome of AggregateTransfers
+type Aggregation struct {
+	// Inputs are the transactions to submit: the merged transfers and the
+	// inputs that could not be merged, in the order of their first input
+	Inputs []*models.CreateTransactionInput
+	// Members holds, for each of Inputs, the positions of the original
+	// inputs it carries
+	Members [][]int
+}
+
+// Saved returns the number of requests removed by the aggregation.
+func (a *Aggregation) Saved() int {
+	saved := 0
+	for _, members := range a.Members {
+		saved += len(members) - 1
+	}
+
+	return saved
+}
+
+// Expand maps the results of the submitted Inputs back to the original
+// inputs: each original input gets the result of the transaction that
+// carried it, with its own Index.
+func (a *Aggregation) Expand(results []BatchResult) []BatchResult {
+	total := 0
+	for _, members := range a.Members {
+		total += len(members)
+	}
+
+	expanded := make([]BatchResult, total)
+
+	for i, members := range a.Members {
+		if i >= len(results) {
+			break
+		}
+
+		for _, index := range members {
+			expanded[index] = results[i]
+			expanded[index].Index = index
+		}
+	}
+
+	return expanded
+}
+
+// aggregateKey identifies the transfers that can be merged together.
+type aggregateKey struct {
+	from, to, asset, chartOfAccounts, route string
+}
+
+// AggregateTransfers merges the transfers between the same source and
+// destination accounts in the same asset into a single transaction whose
+// amount is their sum, cutting the number of requests of high-frequency
+// micro-transactions. The merged transaction itemizes the transfers in its
+// metadata under the Aggregated*Key keys, and keeps the metadata entries
+// shared by all of them.
+//
+// Only simple transfers are merged: a Send with one source and one
+// destination that only carry an account and an amount, not pending, with no
+// template or operations. Transfers must also share their chart of accounts
+// group and route. Other inputs are left as they are. When every merged
+// transfer has an idempotency key, the merged transaction gets a key derived
+// from them, so submitting the same transfers again is safe.
+//
+// Merging trades per-transfer history for throughput: Midaz records one
+// transaction, and a failure fails every transfer it carries.
+func AggregateTransfers(inputs []*models.CreateTransactionInput, opts *AggregateOptions) *Aggregation {
+	opts = normalizeAggregateOptions(opts)
+
+	aggregation := &Aggregation{}
+	open := make(map[aggregateKey]int)
+
+	for i, input := range inputs {
+		key, ok := transferKey(input)
+		if !ok {
+			aggregation.Inputs = append(aggregation.Inputs, input)
+			aggregation.Members = append(aggregation.Members, []int{i})
+
+			continue
+		}
+
+		group, found := open[key]
+		if !found || len(aggregation.Members[group]) >= opts.MaxTransfers {
+			group = len(aggregation.Members)
+			open[key] = group
+
+			aggregation.Inputs = append(aggregation.Inputs, input)
+			aggregation.Members = append(aggregation.Members, nil)
+		}
+
+		aggregation.Members[group] = append(aggregation.Members[group], i)
+	}
+
+	for group, members := range aggregation.Members {
+		if len(members) > 1 {
+			aggregation.Inputs[group] = mergeTransfers(inputs, members, opts)
+		}
+	}
+
+	return aggregation
+}
+
+// normalizeAggregateOptions fills unset aggregation options with their defaults.
+func normalizeAggregateOptions(opts *AggregateOptions) *AggregateOptions {
+	defaults := DefaultAggregateOptions()
+	if opts == nil {
+		return defaults
+	}
+
+	normalized := *opts
+	if normalized.MaxTransfers < 1 {
+		normalized.MaxTransfers = defaults.MaxTransfers
+	}
+
+	if normalized.Description == "" {
+		normalized.Description = defaults.Description
+	}
+
+	return &normalized
+}
+
+// transferKey returns the key of a transfer that can be merged.
+func transferKey(input *models.CreateTransactionInput) (aggregateKey, bool) {
+	if input == nil || input.Send == nil || input.Send.Source == nil || input.Send.Distribute == nil ||
+		input.Pending || input.Template != "" || len(input.Operations) > 0 {
+		return aggregateKey{}, false
+	}
+
+	if len(input.Send.Source.From) != 1 || len(input.Send.Distribute.To) != 1 {
+		return aggregateKey{}, false
+	}
+
+	from, to := input.Send.Source.From[0], input.Send.Distribute.To[0]
+	if !plainLeg(from) || !plainLeg(to) {
+		return aggregateKey{}, false
+	}
+
+	if _, err := decimal.NewFromString(input.Send.Value); err != nil {
+		return aggregateKey{}, false
+	}
+
+	return aggregateKey{
+		from:            from.Account,
+		to:              to.Account,
+		asset:           input.Send.Asset,
+		chartOfAccounts: input.ChartOfAccountsGroupName,
+		route:           input.Route,
+	}, true
+}
+
+// plainLeg reports whether a source or destination only carries an account
+// and an amount, so that merging loses nothing.
+func plainLeg(leg models.FromToInput) bool {
+	return leg.Account != "" && leg.Route == "" && leg.Description == "" && leg.ChartOfAccounts == "" &&
+		leg.AccountAlias == "" && len(leg.Metadata) == 0
+}
+
+// mergeTransfers builds the transaction carrying the transfers at members.
+func mergeTransfers(inputs []*models.CreateTransactionInput, members []int, opts *AggregateOptions) *models.CreateTransactionInput {
+	first := inputs[members[0]]
+
+	var (
+		total        decimal.Decimal
+		amounts      = make([]any, len(members))
+		descriptions = make([]any, len(members))
+		keys         = make([]any, len(members))
+		externalIDs  = make([]any, len(members))
+		allKeys      = true
+		anyExternal  = false
+	)
+
+	for n, index := range members {
+		input := inputs[index]
+		amount, _ := decimal.NewFromString(input.Send.Value)
+		total = total.Add(amount)
+
+		amounts[n] = input.Send.Value
+		descriptions[n] = input.Description
+		keys[n] = input.IdempotencyKey
+		externalIDs[n] = input.ExternalID
+
+		allKeys = allKeys && input.IdempotencyKey != ""
+		anyExternal = anyExternal || input.ExternalID != ""
+	}
+
+	metadata := sharedMetadata(inputs, members)
+	metadata[AggregatedCountKey] = len(members)
+	metadata[AggregatedAmountsKey] = amounts
+	metadata[AggregatedDescriptionsKey] = descriptions
+
+	if allKeys {
+		metadata[AggregatedIdempotencyKeysKey] = keys
+	}
+
+	if anyExternal {
+		metadata[AggregatedExternalIDsKey] = externalIDs
+	}
+
+	var idempotencyKey string
+	if allKeys {
+		idempotencyKey = "aggregate-" + membersDigest(inputs, members)
+	}
+
+	amount := total.String()
+	asset := first.Send.Asset
+
+	return &models.CreateTransactionInput{
+		Description:              opts.Description,
+		Amount:                   amount,
+		AssetCode:                asset,
+		Metadata:                 metadata,
+		IdempotencyKey:           idempotencyKey,
+		ChartOfAccountsGroupName: first.ChartOfAccountsGroupName,
+		Route:                    first.Route,
+		Send: &models.SendInput{
+			Asset: asset,
+			Value: amount,
+			Source: &models.SourceInput{
+				From: []models.FromToInput{{Account: first.Send.Source.From[0].Account, Amount: models.AmountInput{Asset: asset, Value: amount}}},
+			},
+			Distribute: &models.DistributeInput{
+				To: []models.FromToInput{{Account: first.Send.Distribute.To[0].Account, Amount: models.AmountInput{Asset: asset, Value: amount}}},
+			},
+		},
+	}
+}
+
+// sharedMetadata returns the metadata entries equal in every member.
+func sharedMetadata(inputs []*models.CreateTransactionInput, members []int) map[string]any {
+	shared := make(map[string]any)
+
+	for k, v := range inputs[members[0]].Metadata {
+		same := true
+
+		for _, index := range members[1:] {
+			other, ok := inputs[index].Metadata[k]
+			if !ok || !reflect.DeepEqual(v, other) {
+				same = false
+				break
+			}
+		}
+
+		if same {
+			shared[k] = v
+		}
+	}
+
+	return shared
+}
+
+// membersDigest identifies the transfers of a merged transaction by their
+// idempotency keys, regardless of their order.
+func membersDigest(inputs []*models.CreateTransactionInput, members []int) string {
+	keys := make([]string, len(members))
+	for n, index := range members {
+		keys[n] = inputs[index].IdempotencyKey
+	}
+
+	sort.Strings(keys)
+
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// batchAggregated runs BatchTransactions on the aggregated inputs and maps the
+// results back to the inputs.
+func batchAggregated(
+	ctx context.Context,
+	midazClient *client.Client,
+	orgID, ledgerID string,
+	inputs []*models.CreateTransactionInput,
+	options *BatchOptions,
+) ([]BatchResult, error) {
+	submitOptions := *options
+	submitOptions.Aggregate = nil
+
+	return submitAggregated(inputs, options.Aggregate, func(aggregated []*models.CreateTransactionInput) ([]BatchResult, error) {
+		return BatchTransactions(ctx, midazClient, orgID, ledgerID, aggregated, &submitOptions)
+	})
+}
+
+// submitAggregated aggregates inputs, submits them with submit and maps the
+// results back to the inputs.
+func submitAggregated(
+	inputs []*models.CreateTransactionInput,
+	opts *AggregateOptions,
+	submit func([]*models.CreateTransactionInput) ([]BatchResult, error),
+) ([]BatchResult, error) {
+	aggregation := AggregateTransfers(inputs, opts)
+
+	results, err := submit(aggregation.Inputs)
+	results = aggregation.Expand(results)
+
+	if batchErr := newBatchError(results); batchErr != nil {
+		return results, batchErr
+	}
+
+	return results, err
+}
+
+// batchFairAggregated runs BatchTransactionsFair on the aggregated inputs of
+// each ledger and maps the results back to the inputs.
+func batchFairAggregated(ctx context.Context, midazClient *client.Client, batches []LedgerBatch, options *BatchOptions) ([][]BatchResult, error) {
+	aggregations := make([]*Aggregation, len(batches))
+	aggregated := make([]LedgerBatch, len(batches))
+
+	for i, b := range batches {
+		aggregations[i] = AggregateTransfers(b.Inputs, options.Aggregate)
+		aggregated[i] = LedgerBatch{OrganizationID: b.OrganizationID, LedgerID: b.LedgerID, Inputs: aggregations[i].Inputs}
+	}
+
+	submitOptions := *options
+	submitOptions.Aggregate = nil
+
+	results, err := BatchTransactionsFair(ctx, midazClient, aggregated, &submitOptions)
+	for i := range results {
+		results[i] = aggregations[i].Expand(results[i])
+	}
+
+	return results, err
+}
@@ -0,0 +1,128 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func transferInput(from, to, asset, amount, key string) *models.CreateTransactionInput {
+	return &models.CreateTransactionInput{
+		Description:    "transfer " + key,
+		Amount:         amount,
+		AssetCode:      asset,
+		IdempotencyKey: key,
+		Metadata:       map[string]any{"channel": "app", "key": key},
+		Send: &models.SendInput{
+			Asset: asset,
+			Value: amount,
+			Source: &models.SourceInput{
+				From: []models.FromToInput{{Account: from, Amount: models.AmountInput{Asset: asset, Value: amount}}},
+			},
+			Distribute: &models.DistributeInput{
+				To: []models.FromToInput{{Account: to, Amount: models.AmountInput{Asset: asset, Value: amount}}},
+			},
+		},
+	}
+}
+
+func TestAggregateTransfers(t *testing.T) {
+	pending := transferInput("@a", "@b", "USD", "5", "k4")
+	pending.Pending = true
+
+	inputs := []*models.CreateTransactionInput{
+		transferInput("@a", "@b", "USD", "1.5", "k0"),
+		transferInput("@a", "@c", "USD", "2", "k1"),
+		transferInput("@a", "@b", "USD", "2.25", "k2"),
+		transferInput("@a", "@b", "BRL", "3", "k3"),
+		pending,
+		transferInput("@a", "@b", "USD", "0.25", "k5"),
+	}
+
+	aggregation := AggregateTransfers(inputs, nil)
+
+	require.Len(t, aggregation.Inputs, 4)
+	assert.Equal(t, [][]int{{0, 2, 5}, {1}, {3}, {4}}, aggregation.Members)
+	assert.Equal(t, 2, aggregation.Saved())
+	assert.Same(t, inputs[1], aggregation.Inputs[1], "single transfers are left as they are")
+	assert.Same(t, pending, aggregation.Inputs[3])
+
+	merged := aggregation.Inputs[0]
+	assert.Equal(t, "Aggregated transfers", merged.Description)
+	assert.Equal(t, "4", merged.Amount)
+	assert.Equal(t, "4", merged.Send.Value)
+	assert.Equal(t, "4", merged.Send.Source.From[0].Amount.Value)
+	assert.Equal(t, "@a", merged.Send.Source.From[0].Account)
+	assert.Equal(t, "@b", merged.Send.Distribute.To[0].Account)
+	assert.Equal(t, "app", merged.Metadata["channel"], "shared metadata is kept")
+	assert.NotContains(t, merged.Metadata, "key")
+	assert.Equal(t, 3, merged.Metadata[AggregatedCountKey])
+	assert.Equal(t, []any{"1.5", "2.25", "0.25"}, merged.Metadata[AggregatedAmountsKey])
+	assert.Equal(t, []any{"k0", "k2", "k5"}, merged.Metadata[AggregatedIdempotencyKeysKey])
+	assert.NotContains(t, merged.Metadata, AggregatedExternalIDsKey)
+
+	// the same transfers in another order yield the same key
+	reordered := AggregateTransfers([]*models.CreateTransactionInput{inputs[5], inputs[2], inputs[0]}, nil)
+	require.Len(t, reordered.Inputs, 1)
+	assert.Equal(t, merged.IdempotencyKey, reordered.Inputs[0].IdempotencyKey)
+}
+
+func TestAggregateTransfersLimits(t *testing.T) {
+	var inputs []*models.CreateTransactionInput
+	for range 5 {
+		inputs = append(inputs, transferInput("@a", "@b", "USD", "1", ""))
+	}
+
+	aggregation := AggregateTransfers(inputs, &AggregateOptions{MaxTransfers: 2, Description: "sweep"})
+
+	assert.Equal(t, [][]int{{0, 1}, {2, 3}, {4}}, aggregation.Members)
+	assert.Equal(t, "sweep", aggregation.Inputs[0].Description)
+	assert.Empty(t, aggregation.Inputs[0].IdempotencyKey, "transfers without keys get a generated one on submission")
+
+	routed := transferInput("@a", "@b", "USD", "1", "r")
+	routed.Send.Source.From[0].Route = "route"
+	invalid := transferInput("@a", "@b", "USD", "abc", "x")
+
+	aggregation = AggregateTransfers([]*models.CreateTransactionInput{routed, inputs[0], invalid, nil}, nil)
+	assert.Equal(t, [][]int{{0}, {1}, {2}, {3}}, aggregation.Members)
+}
+
+func TestBatchTransactionsAggregate(t *testing.T) {
+	txs := &recordingTransactions{}
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+
+	inputs := []*models.CreateTransactionInput{
+		transferInput("@a", "@b", "USD", "1", "k0"),
+		transferInput("@a", "@c", "USD", "1", "k1"),
+		transferInput("@a", "@b", "USD", "1", "k2"),
+	}
+
+	options := DefaultBatchOptions()
+	options.Aggregate = DefaultAggregateOptions()
+
+	results, err := BatchTransactions(context.Background(), midazClient, "org", "ledger", inputs, options)
+	require.NoError(t, err)
+
+	require.Len(t, txs.inputs, 2)
+	require.Len(t, results, 3)
+
+	for i, r := range results {
+		assert.Equal(t, i, r.Index)
+	}
+
+	assert.Equal(t, results[0].TransactionID, results[2].TransactionID)
+	assert.Equal(t, "k1", results[1].TransactionID)
+
+	fair, err := BatchTransactionsFair(context.Background(), midazClient, []LedgerBatch{
+		{OrganizationID: "org", LedgerID: "l1", Inputs: inputs},
+	}, options)
+	require.NoError(t, err)
+	require.Len(t, fair[0], 3)
+	assert.Len(t, txs.inputs, 4)
+	assert.Equal(t, fair[0][0].TransactionID, fair[0][2].TransactionID)
+}
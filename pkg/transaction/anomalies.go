@@ -0,0 +1,116 @@
+package transaction
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/anomaly"
+)
+
+// inputAmount returns the amount of a transaction input, from its send
+// structure or its Amount field.
+func inputAmount(input *models.CreateTransactionInput) string {
+	if input == nil {
+		return ""
+	}
+
+	if input.Send != nil && input.Send.Value != "" {
+		return input.Send.Value
+	}
+
+	return input.Amount
+}
+
+// ObservationOf returns the anomaly observation of a batch transaction, keyed
+// by its account (its first source other than an @external one, or else its
+// first destination) and timed by its completion. Use it to feed a detector
+// live from BatchOptions.OnProgress.
+//
+// Example:
+//
+//	detector := anomaly.New(anomaly.Config{})
+//	opts.OnProgress = func(_, _ int, result transaction.BatchResult) {
+//	    for _, a := range detector.Observe(transaction.ObservationOf(inputs[result.Index], result)) {
+//	        log.Printf("anomaly on %s: %s", a.Key, a.Metric)
+//	    }
+//	}
+func ObservationOf(input *models.CreateTransactionInput, result BatchResult) anomaly.Observation {
+	o := anomaly.Observation{
+		Key:    accountKey(input),
+		Failed: result.Error != nil,
+		Ref:    result.TransactionID,
+	}
+
+	if !result.StartedAt.IsZero() {
+		o.Time = result.StartedAt.Add(result.Duration)
+	}
+
+	if o.Ref == "" {
+		o.Ref = fmt.Sprintf("input #%d", result.Index)
+	}
+
+	o.Amount, _ = strconv.ParseFloat(inputAmount(input), 64) //nolint:errcheck // unparsable amounts count as zero
+
+	return o
+}
+
+// DetectAnomalies runs an anomaly detector over the results of a batch, in
+// completion order, and returns the anomalies found. inputs are the inputs
+// of the batch, indexed like the results.
+func DetectAnomalies(inputs []*models.CreateTransactionInput, results []BatchResult, cfg anomaly.Config) []anomaly.Anomaly {
+	detector := anomaly.New(cfg)
+
+	for _, r := range sortedByCompletion(results) {
+		var input *models.CreateTransactionInput
+		if r.Index >= 0 && r.Index < len(inputs) {
+			input = inputs[r.Index]
+		}
+
+		detector.Observe(ObservationOf(input, r))
+	}
+
+	detector.Flush()
+
+	return detector.Anomalies()
+}
+
+// sortedByCompletion returns a copy of results ordered by completion time;
+// results without timestamps keep their order, first.
+func sortedByCompletion(results []BatchResult) []BatchResult {
+	sorted := append([]BatchResult(nil), results...)
+
+	completion := func(r BatchResult) time.Time {
+		if r.StartedAt.IsZero() {
+			return time.Time{}
+		}
+
+		return r.StartedAt.Add(r.Duration)
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool { return completion(sorted[i]).Before(completion(sorted[j])) })
+
+	return sorted
+}
+
+// writeHTMLAnomaliesSection writes the anomalies of the report as a table.
+func (r *GenerationReport) writeHTMLAnomaliesSection(b *strings.Builder) {
+	if len(r.Anomalies) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(b, "<div class=\"section\"><h2>Anomalies</h2><table><thead>")
+	_, _ = fmt.Fprintf(b, "<tr><th>Time</th><th>Account</th><th>Metric</th><th>Value</th><th>Expected</th><th>z-score</th><th>Transaction</th></tr></thead><tbody>")
+
+	for _, a := range r.Anomalies {
+		_, _ = fmt.Fprintf(b, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%.2f</td><td>%.2f</td><td>%.1f</td><td>%s</td></tr>",
+			formatSeen(a.Time), html.EscapeString(a.Key), html.EscapeString(string(a.Metric)),
+			a.Value, a.Expected, a.ZScore, html.EscapeString(a.Ref))
+	}
+
+	_, _ = fmt.Fprintf(b, "</tbody></table></div>")
+}
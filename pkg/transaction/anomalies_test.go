@@ -0,0 +1,70 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/anomaly"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sendInput(from, amount string) *models.CreateTransactionInput {
+	return &models.CreateTransactionInput{
+		Send: &models.SendInput{
+			Asset:      "USD",
+			Value:      amount,
+			Source:     &models.SourceInput{From: []models.FromToInput{{Account: from}}},
+			Distribute: &models.DistributeInput{To: []models.FromToInput{{Account: "@merchant"}}},
+		},
+	}
+}
+
+func TestObservationOf(t *testing.T) {
+	started := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	o := ObservationOf(sendInput("@alice", "12.50"), BatchResult{Index: 3, StartedAt: started, Duration: time.Second, Error: errors.New("boom")})
+	assert.Equal(t, anomaly.Observation{Time: started.Add(time.Second), Key: "@alice", Amount: 12.5, Failed: true, Ref: "input #3"}, o)
+
+	o = ObservationOf(sendInput("@external/USD", "7"), BatchResult{TransactionID: "tx-1"})
+	assert.Equal(t, "@merchant", o.Key, "external sources are skipped")
+	assert.Equal(t, "tx-1", o.Ref)
+	assert.True(t, o.Time.IsZero())
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var (
+		inputs  []*models.CreateTransactionInput
+		results []BatchResult
+	)
+
+	for i := range 30 {
+		amount := "100"
+		if i == 25 {
+			amount = "25000"
+		}
+
+		inputs = append(inputs, sendInput("@alice", amount))
+		results = append(results, BatchResult{Index: i, TransactionID: fmt.Sprintf("tx-%d", i), StartedAt: base.Add(time.Duration(i) * time.Second)})
+	}
+
+	// completion order differs from input order
+	results[0], results[29] = results[29], results[0]
+
+	anomalies := DetectAnomalies(inputs, results, anomaly.Config{})
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, anomaly.MetricAmount, anomalies[0].Metric)
+	assert.Equal(t, "tx-25", anomalies[0].Ref)
+
+	report := NewGenerationReport(results, "", nil)
+	report.Anomalies = anomalies
+
+	html := string(report.ToHTML())
+	assert.Contains(t, html, "<h2>Anomalies</h2>")
+	assert.Contains(t, html, "tx-25")
+}
@@ -6,6 +6,7 @@ package transaction
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sync"
 	"time"
 
@@ -13,7 +14,9 @@ import (
 	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
-	"github.com/google/uuid"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/events"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/idgen"
 )
 
 // BatchResult represents the result of a transaction in a batch operation
@@ -26,6 +29,19 @@ type BatchResult struct {
 	Error error
 	// Duration is how long it took to process this transaction
 	Duration time.Duration
+	// StartedAt is when processing of this transaction started, zero when unknown
+	StartedAt time.Time
+	// Deduplicated reports that the transaction was skipped because an earlier
+	// run confirmed it, see BatchOptions.Dedup; TransactionID and StartedAt are
+	// those of that run
+	Deduplicated bool
+	// Verified reports that the transaction was read back and matched its
+	// input, see BatchOptions.Verify
+	Verified bool
+	// VerifyError is why a transaction created couldn't be verified: a
+	// *VerificationError listing what differs from its input, or the error
+	// reading it back. It doesn't make the transaction fail
+	VerifyError error
 }
 
 // BatchOptions configures the behavior of batch operations
@@ -45,14 +61,62 @@ type BatchOptions struct {
 	// OnProgress is a callback function that receives progress updates
 	// Called after each transaction is processed
 	OnProgress func(completed, total int, result BatchResult)
+	// PreserveOrder delivers the results to OnProgress in input order, one call
+	// at a time, holding back those that complete ahead of an earlier one; the
+	// results of transactions never started are skipped. The returned results
+	// are in input order either way
+	// Default is false (OnProgress is called as transactions complete, possibly concurrently)
+	PreserveOrder bool
 	// IdempotencyKeyPrefix is a prefix to add to generated idempotency keys
 	// Default is "batch" if not specified
 	IdempotencyKeyPrefix string
 	// StopOnError determines if the batch processing should stop on the first error
 	// Default is false (continue processing even if some transactions fail)
 	StopOnError bool
+	// MaxInFlightPerLedger caps the transactions of a ledger in flight in BatchTransactionsFair
+	// Default is 0 (no cap beyond Concurrency)
+	MaxInFlightPerLedger int
+	// MetadataTemplate is applied to the metadata of each transaction before it is sent,
+	// ahead of any template of the client. Default is nil (no template)
+	MetadataTemplate *entities.MetadataTemplate
+	// Events receives an events.BatchFinished event when the batch completes
+	// Default is nil (no event)
+	Events events.Sink
+	// Aggregate merges the transfers between the same accounts in the same asset
+	// into one transaction before they are sent, see AggregateTransfers. Results
+	// are still reported per input, while OnProgress and Events count the
+	// transactions sent. Default is nil (no aggregation)
+	Aggregate *AggregateOptions
+	// Dedup skips the inputs confirmed by an earlier run within a time window,
+	// such as a failed cron job run again, see DeduplicateInputs. It applies
+	// before Aggregate. Default is nil (no deduplication)
+	Dedup *DedupOptions
+	// Verify reads each transaction created back from the ledger and compares
+	// its asset, amount and accounts with its input, see VerifyTransaction. The
+	// outcome is recorded in BatchResult.Verified and BatchResult.VerifyError,
+	// apart from Error. Default is false (no verification)
+	Verify bool
+	// OnCancel is what happens to the transactions in flight when the context
+	// is cancelled; transactions not started are never sent either way
+	// Default is CancelAbort
+	OnCancel CancelMode
 }
 
+// CancelMode is what a batch does with the transactions in flight when its
+// context is cancelled.
+type CancelMode int
+
+const (
+	// CancelAbort cancels the requests in flight with the context, so the
+	// batch returns promptly. A cancelled transaction may still have been
+	// created by the backend; resubmitting it with the same idempotency key
+	// is safe.
+	CancelAbort CancelMode = iota
+	// CancelDrain lets the transactions in flight complete their current
+	// attempt, without retries, and report their actual outcome.
+	CancelDrain
+)
+
 // DefaultBatchOptions returns the default batch processing options
 func DefaultBatchOptions() *BatchOptions {
 	return &BatchOptions{
@@ -77,11 +141,27 @@ func DefaultBatchOptions() *BatchOptions {
 //
 // Returns:
 //   - A slice of BatchResult containing the result of each transaction
-//   - An error if the batch operation couldn't be started
+//   - A *BatchError if any transaction failed or was not started, carrying
+//     the results and counts
+//
+// Cancelling ctx stops the batch promptly: no further transaction is started,
+// the transactions in flight are aborted or drained according to
+// options.OnCancel, and the partial results are returned. Each transaction
+// not started keeps a zero StartedAt and gets a cancellation error of the
+// errors package (category errors.CategoryCancellation), so
+// errors.Is(err, context.Canceled) holds for the returned error and
+// BatchError.Skipped lists them for resubmission.
 //
 // The function ensures idempotency by generating unique keys for each transaction
 // if they don't already have one. Results are returned in the same order as inputs,
 // regardless of the order in which transactions are processed.
+//
+// During a maintenance window of the client (see client.WithMaintenanceSchedule)
+// the batch pauses, and resumes when the window ends.
+//
+// The results hold one BatchResult per input. To report on large batches
+// without keeping them again, add them to a ReportBuilder from
+// options.OnProgress.
 func BatchTransactions(
 	ctx context.Context,
 	midazClient *client.Client,
@@ -89,8 +169,18 @@ func BatchTransactions(
 	inputs []*models.CreateTransactionInput,
 	options *BatchOptions,
 ) ([]BatchResult, error) {
+	ctx = entities.WithMaintenanceWait(ctx)
 	options = normalizeOptions(options)
+	if options.Dedup != nil {
+		return batchDeduplicated(ctx, midazClient, orgID, ledgerID, inputs, options)
+	}
+
+	if options.Aggregate != nil {
+		return batchAggregated(ctx, midazClient, orgID, ledgerID, inputs, options)
+	}
+
 	results := make([]BatchResult, len(inputs))
+	startedAt := time.Now()
 
 	processor := &batchProcessor{
 		ctx:      ctx,
@@ -102,7 +192,48 @@ func BatchTransactions(
 		results:  results,
 	}
 
-	return processor.execute()
+	results, err := processor.execute()
+	processor.flushProgress()
+	processor.markCancelled()
+	emitBatchFinished(ctx, options, ledgerID, results, time.Since(startedAt))
+
+	if batchErr := newBatchError(results); batchErr != nil {
+		return results, batchErr
+	}
+
+	return results, err
+}
+
+// emitBatchFinished sends an events.BatchFinished event when an events sink is configured.
+func emitBatchFinished(ctx context.Context, options *BatchOptions, source string, results []BatchResult, elapsed time.Duration) {
+	if options.Events == nil {
+		return
+	}
+
+	counts := newBatchError(results)
+	if counts == nil {
+		counts = &BatchError{Total: len(results), SuccessCount: len(results)}
+	}
+
+	severity := events.SeverityInfo
+	if counts.ErrorCount > 0 || counts.SkippedCount > 0 {
+		severity = events.SeverityWarning
+	}
+
+	events.Emit(context.WithoutCancel(ctx), options.Events, events.Event{
+		Type:     events.BatchFinished,
+		Severity: severity,
+		Source:   source,
+		Message: fmt.Sprintf("batch finished in %v: %d of %d transactions created, %d failed, %d not started",
+			elapsed.Round(time.Millisecond), counts.SuccessCount, counts.Total, counts.ErrorCount, counts.SkippedCount),
+		Attributes: map[string]any{
+			"total":       counts.Total,
+			"succeeded":   counts.SuccessCount,
+			"failed":      counts.ErrorCount,
+			"not_started": counts.SkippedCount,
+			"duration":    elapsed.String(),
+		},
+	})
 }
 
 // normalizeOptions ensures options are valid.
@@ -127,6 +258,15 @@ type batchProcessor struct {
 	inputs   []*models.CreateTransactionInput
 	options  *BatchOptions
 	results  []BatchResult
+	progress progressOrderer
+}
+
+// progressOrderer holds back the results completed ahead of an earlier one,
+// for BatchOptions.PreserveOrder.
+type progressOrderer struct {
+	mu      sync.Mutex
+	next    int
+	pending map[int]BatchResult
 }
 
 // execute runs the batch processing logic.
@@ -136,10 +276,13 @@ func (bp *batchProcessor) execute() ([]BatchResult, error) {
 	semaphore := make(chan struct{}, bp.options.Concurrency)
 	errChan := make(chan error, 1)
 
-	for i := 0; i < len(bp.inputs); i += bp.options.BatchSize {
+	for i := 0; i < len(bp.inputs) && bp.ctx.Err() == nil; i += bp.options.BatchSize {
 		end := bp.calculateBatchEnd(i)
 
 		if err := bp.processBatch(i, end, &wg, semaphore, errChan); err != nil {
+			// Let the transactions in flight finish writing their results
+			wg.Wait()
+
 			return bp.results, err
 		}
 	}
@@ -168,7 +311,9 @@ func (bp *batchProcessor) processBatch(start, end int, wg *sync.WaitGroup, semap
 			}
 		}
 
-		bp.startTransactionWorker(j, wg, semaphore, errChan)
+		if !bp.startTransactionWorker(j, wg, semaphore, errChan) {
+			return nil
+		}
 	}
 
 	return nil
@@ -184,9 +329,20 @@ func (*batchProcessor) checkForEarlyError(errChan chan error) error {
 	}
 }
 
-// startTransactionWorker starts a worker goroutine to process a transaction.
-func (bp *batchProcessor) startTransactionWorker(index int, wg *sync.WaitGroup, semaphore chan struct{}, errChan chan error) {
-	semaphore <- struct{}{}
+// startTransactionWorker starts a worker goroutine to process a transaction,
+// once a worker is free. It returns false, starting nothing, if the context is
+// cancelled first.
+func (bp *batchProcessor) startTransactionWorker(index int, wg *sync.WaitGroup, semaphore chan struct{}, errChan chan error) bool {
+	select {
+	case semaphore <- struct{}{}:
+	case <-bp.ctx.Done():
+		return false
+	}
+
+	if bp.ctx.Err() != nil {
+		<-semaphore
+		return false
+	}
 
 	wg.Add(1)
 
@@ -202,6 +358,8 @@ func (bp *batchProcessor) startTransactionWorker(index int, wg *sync.WaitGroup,
 			}
 		}
 	}(index)
+
+	return true
 }
 
 // processTransaction processes a single transaction with retries.
@@ -210,10 +368,24 @@ func (bp *batchProcessor) processTransaction(index int) error {
 	input := bp.inputs[index]
 
 	bp.ensureIdempotencyKey(input, index)
-	tx, err := bp.executeWithRetries(input)
+
+	var tx *models.Transaction
+
+	err := bp.applyMetadataTemplate(input)
+	if err == nil {
+		tx, err = bp.executeWithRetries(input)
+	}
 
 	result := bp.createResult(index, tx, err, time.Since(startTime))
+	result.StartedAt = startTime
+
+	if err == nil && tx != nil && bp.options.Verify {
+		result.VerifyError = bp.verify(input, tx)
+		result.Verified = result.VerifyError == nil
+	}
+
 	bp.results[index] = result
+	bp.recordEvent(input, result, startTime)
 	bp.callProgressCallback(index, result)
 
 	return err
@@ -222,25 +394,57 @@ func (bp *batchProcessor) processTransaction(index int) error {
 // ensureIdempotencyKey ensures the transaction has an idempotency key.
 func (bp *batchProcessor) ensureIdempotencyKey(input *models.CreateTransactionInput, index int) {
 	if input.IdempotencyKey == "" {
-		input.IdempotencyKey = fmt.Sprintf("%s-%s-%d", bp.options.IdempotencyKeyPrefix, uuid.New().String(), index)
+		input.IdempotencyKey = fmt.Sprintf("%s-%s-%d", bp.options.IdempotencyKeyPrefix, idgen.New(), index)
 	}
 }
 
+// applyMetadataTemplate applies the batch metadata template to the transaction, once for all its attempts.
+func (bp *batchProcessor) applyMetadataTemplate(input *models.CreateTransactionInput) error {
+	if bp.options.MetadataTemplate == nil {
+		return nil
+	}
+
+	metadata, err := bp.options.MetadataTemplate.Apply("BatchTransactions", bp.orgID, bp.ledgerID, input, input.Metadata)
+	if err != nil {
+		return errors.NewValidationError("BatchTransactions", "invalid metadata template", err)
+	}
+
+	input.Metadata = metadata
+
+	return nil
+}
+
+// callContext returns the context of the requests of the batch, not
+// cancelled with it in CancelDrain mode.
+func (bp *batchProcessor) callContext() context.Context {
+	if bp.options.OnCancel == CancelDrain {
+		return context.WithoutCancel(bp.ctx)
+	}
+
+	return bp.ctx
+}
+
 // executeWithRetries executes a transaction with retry logic.
 func (bp *batchProcessor) executeWithRetries(input *models.CreateTransactionInput) (*models.Transaction, error) {
 	var tx *models.Transaction
 
 	var err error
 
+	callCtx := bp.callContext()
+
 	for attempt := 0; attempt <= bp.options.RetryCount; attempt++ {
 		if attempt > 0 {
 			if waitErr := bp.waitForRetry(attempt); waitErr != nil {
+				if bp.options.OnCancel == CancelDrain {
+					return tx, err
+				}
+
 				return nil, waitErr
 			}
 		}
 
 		// Inject idempotency key into context so HTTP layer can add header
-		ctx := entities.WithIdempotencyKey(bp.ctx, input.IdempotencyKey)
+		ctx := entities.WithIdempotencyKey(callCtx, input.IdempotencyKey)
 		tx, err = bp.client.Entity.Transactions.CreateTransaction(ctx, bp.orgID, bp.ledgerID, input)
 
 		if err == nil || !isRetryableError(err) {
@@ -251,6 +455,20 @@ func (bp *batchProcessor) executeWithRetries(input *models.CreateTransactionInpu
 	return tx, err
 }
 
+// verify reads the transaction created back and compares it with its input.
+func (bp *batchProcessor) verify(input *models.CreateTransactionInput, tx *models.Transaction) error {
+	fetched, err := bp.client.Entity.Transactions.GetTransaction(bp.callContext(), bp.orgID, bp.ledgerID, tx.ID)
+	if err != nil {
+		return fmt.Errorf("failed to read transaction %s back for verification: %w", tx.ID, err)
+	}
+
+	if mismatches := VerifyTransaction(input, fetched); len(mismatches) > 0 {
+		return &VerificationError{TransactionID: tx.ID, Mismatches: mismatches}
+	}
+
+	return nil
+}
+
 // waitForRetry implements exponential backoff for retries.
 func (bp *batchProcessor) waitForRetry(attempt int) error {
 	backoffFactor := bp.calculateBackoffFactor(attempt)
@@ -301,10 +519,101 @@ func (*batchProcessor) createResult(index int, tx *models.Transaction, err error
 	return result
 }
 
-// callProgressCallback calls the progress callback if configured.
+// recordEvent appends the result to the event log carried by the context, if any.
+func (bp *batchProcessor) recordEvent(input *models.CreateTransactionInput, result BatchResult, startTime time.Time) {
+	if eventlog.FromContext(bp.ctx) == nil {
+		return
+	}
+
+	ev := eventlog.Event{
+		Source:         "batch",
+		Entity:         eventlog.EntityTransaction,
+		ID:             result.TransactionID,
+		OrganizationID: bp.orgID,
+		LedgerID:       bp.ledgerID,
+		StartedAt:      startTime.UTC(),
+		DurationMs:     float64(result.Duration.Microseconds()) / 1000,
+		Attributes:     map[string]any{"index": result.Index, "idempotencyKey": input.IdempotencyKey},
+	}
+
+	if account := accountKey(input); account != "" {
+		ev.Attributes["account"] = account
+	}
+
+	if amount := inputAmount(input); amount != "" {
+		ev.Attributes["amount"] = amount
+	}
+
+	if result.Error != nil {
+		ev.Error = result.Error.Error()
+	}
+
+	eventlog.Record(bp.ctx, ev)
+}
+
+// markCancelled gives the transactions not started before the context was
+// cancelled a cancellation error, leaving their StartedAt zero.
+func (bp *batchProcessor) markCancelled() {
+	if bp.ctx.Err() == nil {
+		return
+	}
+
+	for i := range bp.results {
+		if bp.results[i].StartedAt.IsZero() && bp.results[i].Error == nil {
+			bp.results[i].Index = i
+			bp.results[i].Error = errors.NewCancellationError("BatchTransactions", context.Cause(bp.ctx))
+		}
+	}
+}
+
+// callProgressCallback calls the progress callback if configured, in input
+// order with PreserveOrder.
 func (bp *batchProcessor) callProgressCallback(index int, result BatchResult) {
-	if bp.options.OnProgress != nil {
+	if bp.options.OnProgress == nil {
+		return
+	}
+
+	if !bp.options.PreserveOrder {
 		bp.options.OnProgress(index+1, len(bp.inputs), result)
+		return
+	}
+
+	p := &bp.progress
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pending == nil {
+		p.pending = make(map[int]BatchResult)
+	}
+
+	p.pending[index] = result
+
+	for {
+		next, ok := p.pending[p.next]
+		if !ok {
+			return
+		}
+
+		delete(p.pending, p.next)
+		p.next++
+		bp.options.OnProgress(p.next, len(bp.inputs), next)
+	}
+}
+
+// flushProgress delivers the results held back by PreserveOrder behind
+// transactions that were never started, in input order.
+func (bp *batchProcessor) flushProgress() {
+	p := &bp.progress
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for ; p.next < len(bp.inputs) && len(p.pending) > 0; p.next++ {
+		if result, ok := p.pending[p.next]; ok {
+			delete(p.pending, p.next)
+			bp.options.OnProgress(p.next+1, len(bp.inputs), result)
+		}
 	}
 }
 
@@ -329,6 +638,14 @@ type BatchSummary struct {
 	SuccessCount int
 	// Number of failed transactions
 	ErrorCount int
+	// Number of transactions skipped as confirmed by an earlier run, counted
+	// in SuccessCount
+	DeduplicatedCount int
+	// Number of transactions verified against their input, see BatchOptions.Verify
+	VerifiedCount int
+	// Number of transactions created whose verification failed, counted in
+	// SuccessCount
+	VerificationFailureCount int
 	// Percentage of successful transactions
 	SuccessRate float64
 	// Total duration of the batch operation
@@ -343,50 +660,92 @@ type BatchSummary struct {
 
 // GetBatchSummary analyzes batch results and returns a summary
 func GetBatchSummary(results []BatchResult) BatchSummary {
-	total := len(results)
-	successCount := 0
-	errorCount := 0
-	totalDuration := time.Duration(0)
-	errorCategories := make(map[string]int)
+	var counter batchCounter
 
 	for _, result := range results {
-		totalDuration += result.Duration
+		counter.add(result)
+	}
 
-		if result.Error == nil {
-			successCount++
-		} else {
-			errorCount++
+	return counter.summary()
+}
 
-			// Categorize errors
-			category := errors.GetErrorCategory(result.Error)
-			errorCategories[string(category)]++
-		}
+// batchCounter accumulates a BatchSummary one result at a time.
+type batchCounter struct {
+	total                    int
+	successCount             int
+	errorCount               int
+	deduplicatedCount        int
+	verifiedCount            int
+	verificationFailureCount int
+	totalDuration            time.Duration
+	errorCategories          map[string]int
+}
+
+// add counts a result.
+func (c *batchCounter) add(result BatchResult) {
+	c.total++
+	c.totalDuration += result.Duration
+
+	if result.Deduplicated {
+		c.deduplicatedCount++
+	}
+
+	if result.Verified {
+		c.verifiedCount++
+	}
+
+	if result.VerifyError != nil {
+		c.verificationFailureCount++
+	}
+
+	if result.Error == nil {
+		c.successCount++
+		return
+	}
+
+	c.errorCount++
+
+	if c.errorCategories == nil {
+		c.errorCategories = make(map[string]int)
 	}
 
+	// Categorize errors
+	category := errors.GetErrorCategory(result.Error)
+	c.errorCategories[string(category)]++
+}
+
+// summary returns the summary of the results counted.
+func (c *batchCounter) summary() BatchSummary {
 	var successRate float64
-	if total > 0 {
-		successRate = float64(successCount) / float64(total) * 100
+	if c.total > 0 {
+		successRate = float64(c.successCount) / float64(c.total) * 100
 	}
 
 	var avgDuration time.Duration
-	if total > 0 {
-		avgDuration = totalDuration / time.Duration(total)
+	if c.total > 0 {
+		avgDuration = c.totalDuration / time.Duration(c.total)
 	}
 
 	var tps float64
-	if totalDuration > 0 {
-		tps = float64(successCount) / totalDuration.Seconds()
+	if c.totalDuration > 0 {
+		tps = float64(c.successCount) / c.totalDuration.Seconds()
 	}
 
+	errorCategories := make(map[string]int, len(c.errorCategories))
+	maps.Copy(errorCategories, c.errorCategories)
+
 	return BatchSummary{
-		TotalTransactions:     total,
-		SuccessCount:          successCount,
-		ErrorCount:            errorCount,
-		SuccessRate:           successRate,
-		TotalDuration:         totalDuration,
-		AverageDuration:       avgDuration,
-		TransactionsPerSecond: tps,
-		ErrorCategories:       errorCategories,
+		TotalTransactions:        c.total,
+		SuccessCount:             c.successCount,
+		ErrorCount:               c.errorCount,
+		DeduplicatedCount:        c.deduplicatedCount,
+		VerifiedCount:            c.verifiedCount,
+		VerificationFailureCount: c.verificationFailureCount,
+		SuccessRate:              successRate,
+		TotalDuration:            c.totalDuration,
+		AverageDuration:          avgDuration,
+		TransactionsPerSecond:    tps,
+		ErrorCategories:          errorCategories,
 	}
 }
 
@@ -0,0 +1,134 @@
+package transaction
+
+import (
+	"fmt"
+)
+
+// BatchError is returned by BatchTransactions when some transactions of the
+// batch failed or were never started, after StopOnError stopped the batch or
+// its context was cancelled. It carries the
+// result of every transaction, so callers can handle the failures from the
+// error alone:
+//
+//	results, err := transaction.BatchTransactions(ctx, c, orgID, ledgerID, inputs, nil)
+//
+//	var batchErr *transaction.BatchError
+//	if errors.As(err, &batchErr) {
+//	    for _, r := range batchErr.Retryable() {
+//	        retry = append(retry, inputs[r.Index])
+//	    }
+//	}
+//
+// errors.Is and errors.As match the errors of the failed transactions.
+type BatchError struct {
+	// Results is the result of each transaction, in input order
+	Results []BatchResult
+	// Total is the number of transactions in the batch
+	Total int
+	// SuccessCount is the number of transactions created
+	SuccessCount int
+	// ErrorCount is the number of transactions started that failed
+	ErrorCount int
+	// SkippedCount is the number of transactions not started, after StopOnError
+	// stopped the batch or its context was cancelled
+	SkippedCount int
+}
+
+// newBatchError returns the BatchError of results, or nil if every
+// transaction succeeded. Results of transactions never started get their
+// Index set.
+func newBatchError(results []BatchResult) *BatchError {
+	e := &BatchError{Results: results, Total: len(results)}
+
+	for i := range results {
+		switch {
+		case results[i].StartedAt.IsZero():
+			results[i].Index = i
+			e.SkippedCount++
+		case results[i].Error != nil:
+			e.ErrorCount++
+		default:
+			e.SuccessCount++
+		}
+	}
+
+	if e.ErrorCount == 0 && e.SkippedCount == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	msg := fmt.Sprintf("%d of %d transactions failed", e.ErrorCount, e.Total)
+	if e.SkippedCount > 0 {
+		msg += fmt.Sprintf(", %d not started", e.SkippedCount)
+	}
+
+	if failed := e.Failed(); len(failed) > 0 {
+		return fmt.Sprintf("%s; first error at index %d: %v", msg, failed[0].Index, failed[0].Error)
+	}
+
+	for _, r := range e.Skipped() {
+		if r.Error != nil {
+			return fmt.Sprintf("%s: %v", msg, r.Error)
+		}
+	}
+
+	return msg
+}
+
+// Unwrap returns the errors of the failed transactions and the cancellation
+// errors of those not started because the context was cancelled.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, e.ErrorCount)
+
+	for _, r := range e.Results {
+		if r.Error != nil {
+			errs = append(errs, r.Error)
+		}
+	}
+
+	return errs
+}
+
+// Failed returns the results of the transactions started that failed.
+func (e *BatchError) Failed() []BatchResult {
+	return e.filter(func(r BatchResult) bool { return r.Error != nil && !r.StartedAt.IsZero() })
+}
+
+// Retryable returns the results of the transactions worth submitting again:
+// those that failed with an error the batch itself retries, such as a
+// timeout, a network error, a rate limit or a server error, and those never
+// started. Their
+// inputs keep the idempotency keys of the first attempt, so resubmitting
+// them can't create duplicates.
+func (e *BatchError) Retryable() []BatchResult {
+	return e.filter(func(r BatchResult) bool {
+		if r.StartedAt.IsZero() {
+			return true
+		}
+
+		return isRetryableError(r.Error)
+	})
+}
+
+// Skipped returns the results of the transactions not started, after
+// StopOnError stopped the batch or its context was cancelled. The latter
+// carry a cancellation error.
+func (e *BatchError) Skipped() []BatchResult {
+	return e.filter(func(r BatchResult) bool { return r.StartedAt.IsZero() })
+}
+
+func (e *BatchError) filter(keep func(BatchResult) bool) []BatchResult {
+	var out []BatchResult
+
+	for _, r := range e.Results {
+		if keep(r) {
+			out = append(out, r)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,164 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	pkgerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingTransactions fails the transactions whose idempotency key has an error set.
+type failingTransactions struct {
+	entities.TransactionsService
+
+	errs map[string]error
+}
+
+func (f *failingTransactions) CreateTransaction(_ context.Context, _, _ string, input *models.CreateTransactionInput) (*models.Transaction, error) {
+	if err := f.errs[input.IdempotencyKey]; err != nil {
+		return nil, err
+	}
+
+	return &models.Transaction{ID: input.IdempotencyKey}, nil
+}
+
+func TestBatchTransactions_BatchError(t *testing.T) {
+	rejected := pkgerrors.ErrorFromHTTPResponse(http.StatusBadRequest, "req-1", "bad request", "", "transaction", "")
+	unavailable := pkgerrors.NewNetworkError("CreateTransaction", errors.New("connection reset"))
+
+	txs := &failingTransactions{errs: map[string]error{"k1": rejected, "k3": unavailable}}
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+
+	inputs := []*models.CreateTransactionInput{{IdempotencyKey: "k0"}, {IdempotencyKey: "k1"}, {IdempotencyKey: "k2"}, {IdempotencyKey: "k3"}}
+
+	results, err := BatchTransactions(context.Background(), midazClient, "org", "ledger", inputs, &BatchOptions{Concurrency: 2, BatchSize: 10})
+	require.Error(t, err)
+	assert.Len(t, results, 4)
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+
+	assert.Equal(t, 4, batchErr.Total)
+	assert.Equal(t, 2, batchErr.SuccessCount)
+	assert.Equal(t, 2, batchErr.ErrorCount)
+	assert.Zero(t, batchErr.SkippedCount)
+	assert.Contains(t, err.Error(), "2 of 4 transactions failed")
+
+	failed := batchErr.Failed()
+	require.Len(t, failed, 2)
+	assert.Equal(t, 1, failed[0].Index)
+	assert.Equal(t, 3, failed[1].Index)
+
+	retryable := batchErr.Retryable()
+	require.Len(t, retryable, 1)
+	assert.Equal(t, 3, retryable[0].Index)
+
+	require.ErrorIs(t, err, rejected)
+	assert.Equal(t, pkgerrors.CategoryValidation, pkgerrors.GetErrorCategory(batchErr.Failed()[0].Error))
+}
+
+func TestBatchTransactions_BatchErrorSkipped(t *testing.T) {
+	txs := &failingTransactions{errs: map[string]error{"k0": errors.New("rejected")}}
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+
+	inputs := []*models.CreateTransactionInput{{IdempotencyKey: "k0"}, {IdempotencyKey: "k1"}, {IdempotencyKey: "k2"}}
+
+	_, err := BatchTransactions(context.Background(), midazClient, "org", "ledger", inputs, &BatchOptions{Concurrency: 1, BatchSize: 1, StopOnError: true})
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+
+	assert.Equal(t, 1, batchErr.ErrorCount)
+	assert.Equal(t, batchErr.Total, batchErr.SuccessCount+batchErr.ErrorCount+batchErr.SkippedCount)
+
+	for _, r := range batchErr.Skipped() {
+		assert.Positive(t, r.Index, "skipped results keep their position")
+		assert.Contains(t, batchErr.Retryable(), r)
+	}
+}
+
+func TestBatchTransactions_NoBatchErrorOnSuccess(t *testing.T) {
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: &failingTransactions{}}}
+
+	results, err := BatchTransactions(context.Background(), midazClient, "org", "ledger", []*models.CreateTransactionInput{{}, {}}, nil)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+}
+
+// cancellingTransactions cancels the batch context on the first call, then
+// takes 50ms unless its own context is cancelled.
+type cancellingTransactions struct {
+	entities.TransactionsService
+
+	cancel context.CancelFunc
+}
+
+func (c *cancellingTransactions) CreateTransaction(ctx context.Context, _, _ string, input *models.CreateTransactionInput) (*models.Transaction, error) {
+	c.cancel()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(50 * time.Millisecond):
+		return &models.Transaction{ID: input.IdempotencyKey}, nil
+	}
+}
+
+func TestBatchTransactions_Cancellation(t *testing.T) {
+	for _, mode := range []CancelMode{CancelAbort, CancelDrain} {
+		ctx, cancel := context.WithCancel(context.Background())
+		midazClient := &client.Client{Entity: &entities.Entity{Transactions: &cancellingTransactions{cancel: cancel}}}
+
+		inputs := []*models.CreateTransactionInput{{IdempotencyKey: "k0"}, {IdempotencyKey: "k1"}, {IdempotencyKey: "k2"}}
+
+		results, err := BatchTransactions(ctx, midazClient, "org", "ledger", inputs, &BatchOptions{Concurrency: 1, BatchSize: 2, RetryCount: 2, OnCancel: mode})
+		require.Len(t, results, 3)
+		require.ErrorIs(t, err, context.Canceled)
+
+		var batchErr *BatchError
+		require.ErrorAs(t, err, &batchErr)
+		assert.Equal(t, 2, batchErr.SkippedCount)
+
+		for _, r := range batchErr.Skipped() {
+			assert.True(t, r.StartedAt.IsZero())
+			assert.Equal(t, pkgerrors.CategoryCancellation, pkgerrors.GetErrorCategory(r.Error))
+			assert.Contains(t, batchErr.Retryable(), r)
+		}
+
+		assert.False(t, results[0].StartedAt.IsZero())
+
+		if mode == CancelDrain {
+			assert.NoError(t, results[0].Error, "the transaction in flight completes")
+			assert.Equal(t, "k0", results[0].TransactionID)
+			assert.Equal(t, 1, batchErr.SuccessCount)
+		} else {
+			assert.ErrorIs(t, results[0].Error, context.Canceled, "the transaction in flight is aborted")
+			assert.Equal(t, 1, batchErr.ErrorCount)
+		}
+	}
+}
+
+func TestBatchTransactionsFair_Cancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: &cancellingTransactions{cancel: cancel}}}
+
+	batches := []LedgerBatch{
+		{LedgerID: "a", Inputs: []*models.CreateTransactionInput{{IdempotencyKey: "a0"}, {IdempotencyKey: "a1"}}},
+		{LedgerID: "b", Inputs: []*models.CreateTransactionInput{{IdempotencyKey: "b0"}}},
+	}
+
+	results, err := BatchTransactionsFair(ctx, midazClient, batches, &BatchOptions{Concurrency: 1, OnCancel: CancelDrain})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.NoError(t, results[0][0].Error)
+	assert.True(t, pkgerrors.IsCancellationError(results[0][1].Error))
+	assert.True(t, pkgerrors.IsCancellationError(results[1][0].Error))
+	assert.True(t, results[1][0].StartedAt.IsZero())
+}
@@ -1,13 +1,20 @@
 package transaction
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
 	pkgerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/eventlog"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/events"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -673,6 +680,45 @@ func TestBatchProcessorEnsureIdempotencyKey(t *testing.T) {
 	})
 }
 
+// TestBatchProcessorApplyMetadataTemplate tests the applyMetadataTemplate method
+func TestBatchProcessorApplyMetadataTemplate(t *testing.T) {
+	t.Run("adds the template values", func(t *testing.T) {
+		bp := &batchProcessor{
+			orgID:    "org-1",
+			ledgerID: "ledger-1",
+			options: &BatchOptions{
+				MetadataTemplate: entities.NewMetadataTemplate().WithValue("team", "payments").WithSequence("sequence"),
+			},
+		}
+
+		input := &models.CreateTransactionInput{Metadata: map[string]any{"team": "treasury"}}
+
+		require.NoError(t, bp.applyMetadataTemplate(input))
+		assert.Equal(t, map[string]any{"team": "treasury", "sequence": uint64(1)}, input.Metadata)
+	})
+
+	t.Run("leaves the metadata without a template", func(t *testing.T) {
+		bp := &batchProcessor{options: &BatchOptions{}}
+
+		input := &models.CreateTransactionInput{}
+
+		require.NoError(t, bp.applyMetadataTemplate(input))
+		assert.Nil(t, input.Metadata)
+	})
+
+	t.Run("returns the template errors", func(t *testing.T) {
+		bp := &batchProcessor{
+			options: &BatchOptions{
+				MetadataTemplate: entities.NewMetadataTemplate().WithComputed("broken", func(entities.MetadataCall) (any, error) {
+					return nil, errors.New("no value")
+				}),
+			},
+		}
+
+		assert.Error(t, bp.applyMetadataTemplate(&models.CreateTransactionInput{}))
+	})
+}
+
 // TestBatchProcessorEmptyInputs tests batch processing with empty inputs
 func TestBatchProcessorEmptyInputs(t *testing.T) {
 	bp := &batchProcessor{
@@ -764,3 +810,115 @@ func TestErrorCategorizationInSummary(t *testing.T) {
 		})
 	}
 }
+
+// TestBatchTransactionsRecordsEvents tests that results are appended to the event log in context
+func TestBatchTransactionsRecordsEvents(t *testing.T) {
+	var buf bytes.Buffer
+
+	txs := &recordingTransactions{}
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+	ctx := eventlog.WithLog(context.Background(), eventlog.New(&buf))
+
+	inputs := []*models.CreateTransactionInput{{IdempotencyKey: "k1"}, {IdempotencyKey: "k2"}}
+
+	_, err := BatchTransactions(ctx, midazClient, "org", "ledger", inputs, nil)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var ev eventlog.Event
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &ev))
+
+	assert.Equal(t, "batch", ev.Source)
+	assert.Equal(t, eventlog.EntityTransaction, ev.Entity)
+	assert.Equal(t, "ledger", ev.LedgerID)
+	assert.Equal(t, eventlog.OutcomeSuccess, ev.Outcome)
+	assert.Contains(t, []string{"k1", "k2"}, ev.ID)
+}
+
+// TestBatchTransactionsEmitsFinishedEvent tests that the events sink is notified once the batch completes
+func TestBatchTransactionsEmitsFinishedEvent(t *testing.T) {
+	var received []events.Event
+
+	options := DefaultBatchOptions()
+	options.Events = events.SinkFunc(func(_ context.Context, e events.Event) error {
+		received = append(received, e)
+		return nil
+	})
+
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: &recordingTransactions{}}}
+	inputs := []*models.CreateTransactionInput{{IdempotencyKey: "k1"}, {IdempotencyKey: "k2"}}
+
+	_, err := BatchTransactions(context.Background(), midazClient, "org", "ledger", inputs, options)
+	require.NoError(t, err)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, events.BatchFinished, received[0].Type)
+	assert.Equal(t, events.SeverityInfo, received[0].Severity)
+	assert.Equal(t, "ledger", received[0].Source)
+	assert.Equal(t, 2, received[0].Attributes["succeeded"])
+	assert.Equal(t, 0, received[0].Attributes["failed"])
+}
+
+// slowTransactions delays each transaction by the duration set for its idempotency key.
+type slowTransactions struct {
+	entities.TransactionsService
+
+	delays map[string]time.Duration
+}
+
+func (s *slowTransactions) CreateTransaction(_ context.Context, _, _ string, input *models.CreateTransactionInput) (*models.Transaction, error) {
+	time.Sleep(s.delays[input.IdempotencyKey])
+	return &models.Transaction{ID: input.IdempotencyKey}, nil
+}
+
+func TestBatchTransactionsPreserveOrder(t *testing.T) {
+	txs := &slowTransactions{delays: map[string]time.Duration{"k0": 40 * time.Millisecond, "k2": 20 * time.Millisecond}}
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+
+	inputs := []*models.CreateTransactionInput{{IdempotencyKey: "k0"}, {IdempotencyKey: "k1"}, {IdempotencyKey: "k2"}, {IdempotencyKey: "k3"}}
+
+	var (
+		delivered []string
+		completed []int
+	)
+
+	results, err := BatchTransactions(context.Background(), midazClient, "org", "ledger", inputs, &BatchOptions{
+		Concurrency:   4,
+		BatchSize:     4,
+		PreserveOrder: true,
+		OnProgress: func(n, _ int, result BatchResult) {
+			delivered = append(delivered, result.TransactionID)
+			completed = append(completed, n)
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"k0", "k1", "k2", "k3"}, delivered)
+	assert.Equal(t, []int{1, 2, 3, 4}, completed)
+
+	for i, r := range results {
+		assert.Equal(t, i, r.Index)
+		assert.Equal(t, inputs[i].IdempotencyKey, r.TransactionID)
+	}
+}
+
+func TestBatchTransactionsPreserveOrderFlush(t *testing.T) {
+	var delivered []int
+
+	bp := &batchProcessor{
+		inputs: make([]*models.CreateTransactionInput, 4),
+		options: &BatchOptions{PreserveOrder: true, OnProgress: func(n, _ int, result BatchResult) {
+			delivered = append(delivered, result.Index)
+			assert.Equal(t, result.Index+1, n)
+		}},
+	}
+
+	bp.callProgressCallback(3, BatchResult{Index: 3})
+	bp.callProgressCallback(1, BatchResult{Index: 1})
+	assert.Empty(t, delivered, "results wait for index 0")
+
+	bp.flushProgress()
+	assert.Equal(t, []int{1, 3}, delivered, "the results behind a transaction never started are delivered at the end")
+}
@@ -0,0 +1,318 @@
+package transaction
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/kvstore"
+)
+
+// DefaultDedupWindow is how long a confirmed submission is remembered by
+// DedupOptions without a Window.
+const DefaultDedupWindow = time.Hour
+
+// DefaultDedupPrefix namespaces the keys of DedupOptions without a Prefix.
+const DefaultDedupPrefix = "dedup/"
+
+// DedupOptions configures the deduplication of submissions across runs, see
+// BatchOptions.Dedup.
+type DedupOptions struct {
+	// Store keeps the confirmed submissions, e.g. a kvstore.File or a
+	// kvstore.Redis shared by the runs of a job. Required
+	Store kvstore.Interface
+	// Window is how long a confirmed submission is remembered
+	// Default is DefaultDedupWindow
+	Window time.Duration
+	// Prefix namespaces the keys of Store, e.g. per job
+	// Default is DefaultDedupPrefix
+	Prefix string
+	// OnSummary receives the inputs skipped, before the others are submitted
+	OnSummary func(DedupSummary)
+}
+
+// DedupSummary lists the inputs of a run skipped because an earlier run
+// confirmed them within the window.
+type DedupSummary struct {
+	// Total is the number of inputs of the run
+	Total int
+	// Skipped are the inputs skipped, in input order
+	Skipped []DedupSkip
+}
+
+// DedupSkip is an input skipped by the deduplication.
+type DedupSkip struct {
+	// Index is the position of the input
+	Index int
+	// Key is the deduplication key of the input, see DedupKeys
+	Key string
+	// TransactionID is the transaction created by the earlier run
+	TransactionID string
+	// ConfirmedAt is when the earlier run confirmed the transaction
+	ConfirmedAt time.Time
+}
+
+// dedupRecord is a confirmed submission kept in the store.
+type dedupRecord struct {
+	TransactionID string    `json:"transactionId"`
+	StartedAt     time.Time `json:"startedAt"`
+	ConfirmedAt   time.Time `json:"confirmedAt"`
+}
+
+// Deduplication is the outcome of DeduplicateInputs: the inputs still to
+// submit and the results of those skipped.
+type Deduplication struct {
+	// Inputs are the inputs still to submit, in input order
+	Inputs []*models.CreateTransactionInput
+	// Summary lists the inputs skipped
+	Summary DedupSummary
+
+	keys    []string
+	indices []int // position in the run of each of Inputs
+	skipped []BatchResult
+	opts    DedupOptions
+}
+
+// DedupKeys returns the deduplication key of each input in the ledger. An
+// input with an idempotency key is identified by it. Others are identified
+// by their fingerprint (see models.Fingerprint), so that inputs built in a
+// different order or with differently formatted amounts still match, and by
+// their occurrence among identical inputs, so that a run submitting the same
+// transfer twice has it deduplicated twice by the next run, not once.
+func DedupKeys(orgID, ledgerID string, inputs []*models.CreateTransactionInput) []string {
+	keys := make([]string, len(inputs))
+	occurrences := make(map[string]int)
+
+	for i, input := range inputs {
+		h := sha256.New()
+		fmt.Fprintf(h, "%s\x00%s\x00", orgID, ledgerID)
+
+		if input != nil && input.IdempotencyKey != "" {
+			fmt.Fprintf(h, "key\x00%s", input.IdempotencyKey)
+		} else {
+			id := models.Fingerprint(input)
+			fmt.Fprintf(h, "content\x00%s\x00%d", id, occurrences[id])
+			occurrences[id]++
+		}
+
+		keys[i] = hex.EncodeToString(h.Sum(nil))
+	}
+
+	return keys
+}
+
+// DeduplicateInputs skips the inputs that an earlier run confirmed within
+// opts.Window, as recorded in opts.Store by Deduplication.Record, and drops
+// the expired records. Inputs without an idempotency key get one derived
+// from their deduplication key, so that resubmitting one whose confirmation
+// wasn't recorded, e.g. after a crash, is still deduplicated by the backend.
+//
+// Returns an error, submitting nothing, if opts.Store is nil or fails.
+func DeduplicateInputs(ctx context.Context, orgID, ledgerID string, inputs []*models.CreateTransactionInput, opts DedupOptions) (*Deduplication, error) {
+	if opts.Store == nil {
+		return nil, errors.New("dedup store is required")
+	}
+
+	if opts.Window <= 0 {
+		opts.Window = DefaultDedupWindow
+	}
+
+	if opts.Prefix == "" {
+		opts.Prefix = DefaultDedupPrefix
+	}
+
+	now := time.Now()
+
+	records, err := loadDedupRecords(ctx, opts, now)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Deduplication{Summary: DedupSummary{Total: len(inputs)}, opts: opts}
+
+	for i, key := range DedupKeys(orgID, ledgerID, inputs) {
+		if record, ok := records[opts.Prefix+key]; ok {
+			d.skipped = append(d.skipped, BatchResult{
+				Index:         i,
+				TransactionID: record.TransactionID,
+				StartedAt:     record.StartedAt,
+				Deduplicated:  true,
+			})
+			d.Summary.Skipped = append(d.Summary.Skipped, DedupSkip{Index: i, Key: key, TransactionID: record.TransactionID, ConfirmedAt: record.ConfirmedAt})
+
+			continue
+		}
+
+		if inputs[i] != nil && inputs[i].IdempotencyKey == "" {
+			inputs[i].IdempotencyKey = "dedup-" + key[:40]
+		}
+
+		d.Inputs = append(d.Inputs, inputs[i])
+		d.keys = append(d.keys, key)
+		d.indices = append(d.indices, i)
+	}
+
+	return d, nil
+}
+
+// loadDedupRecords returns the records of the window by key, deleting the
+// expired ones.
+func loadDedupRecords(ctx context.Context, opts DedupOptions, now time.Time) (map[string]dedupRecord, error) {
+	records := make(map[string]dedupRecord)
+
+	var expired []string
+
+	err := opts.Store.Scan(ctx, opts.Prefix, func(key string, value []byte) error {
+		var record dedupRecord
+		if err := json.Unmarshal(value, &record); err != nil || now.Sub(record.ConfirmedAt) >= opts.Window {
+			expired = append(expired, key)
+			return nil
+		}
+
+		records[key] = record
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dedup records: %w", err)
+	}
+
+	for _, key := range expired {
+		if err := opts.Store.Delete(ctx, key); err != nil {
+			return nil, fmt.Errorf("failed to delete expired dedup record: %w", err)
+		}
+	}
+
+	return records, nil
+}
+
+// Record stores the confirmation of the submitted inputs that succeeded,
+// given their results in the order of Inputs.
+func (d *Deduplication) Record(ctx context.Context, results []BatchResult) error {
+	now := time.Now().UTC()
+
+	for i, result := range results {
+		if i >= len(d.keys) || result.Error != nil || result.StartedAt.IsZero() || result.TransactionID == "" {
+			continue
+		}
+
+		record := dedupRecord{TransactionID: result.TransactionID, StartedAt: result.StartedAt, ConfirmedAt: now}
+		if err := kvstore.PutJSON(ctx, d.opts.Store, d.opts.Prefix+d.keys[i], record); err != nil {
+			return fmt.Errorf("failed to record confirmed transaction %s: %w", result.TransactionID, err)
+		}
+	}
+
+	return nil
+}
+
+// Expand maps the results of Inputs back to the inputs of the run, adding
+// the results of the inputs skipped.
+func (d *Deduplication) Expand(results []BatchResult) []BatchResult {
+	expanded := make([]BatchResult, d.Summary.Total)
+
+	for _, skipped := range d.skipped {
+		expanded[skipped.Index] = skipped
+	}
+
+	for i, result := range results {
+		if i >= len(d.indices) {
+			break
+		}
+
+		result.Index = d.indices[i]
+		expanded[d.indices[i]] = result
+	}
+
+	return expanded
+}
+
+// submitDeduplicated deduplicates inputs, submits the others with submit,
+// records their confirmations and maps the results back to the inputs.
+func submitDeduplicated(
+	ctx context.Context,
+	orgID, ledgerID string,
+	inputs []*models.CreateTransactionInput,
+	opts *DedupOptions,
+	submit func([]*models.CreateTransactionInput) ([]BatchResult, error),
+) ([]BatchResult, error) {
+	d, err := DeduplicateInputs(ctx, orgID, ledgerID, inputs, *opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.OnSummary != nil {
+		opts.OnSummary(d.Summary)
+	}
+
+	var results []BatchResult
+	if len(d.Inputs) > 0 {
+		results, err = submit(d.Inputs)
+	}
+
+	recordErr := d.Record(context.WithoutCancel(ctx), results)
+	results = d.Expand(results)
+
+	if batchErr := newBatchError(results); batchErr != nil {
+		err = batchErr
+	}
+
+	return results, errors.Join(err, recordErr)
+}
+
+// batchDeduplicated runs BatchTransactions on the inputs not confirmed by an
+// earlier run.
+func batchDeduplicated(
+	ctx context.Context,
+	midazClient *client.Client,
+	orgID, ledgerID string,
+	inputs []*models.CreateTransactionInput,
+	options *BatchOptions,
+) ([]BatchResult, error) {
+	submitOptions := *options
+	submitOptions.Dedup = nil
+
+	return submitDeduplicated(ctx, orgID, ledgerID, inputs, options.Dedup, func(remaining []*models.CreateTransactionInput) ([]BatchResult, error) {
+		return BatchTransactions(ctx, midazClient, orgID, ledgerID, remaining, &submitOptions)
+	})
+}
+
+// batchFairDeduplicated runs BatchTransactionsFair on the inputs of each
+// ledger not confirmed by an earlier run.
+func batchFairDeduplicated(ctx context.Context, midazClient *client.Client, batches []LedgerBatch, options *BatchOptions) ([][]BatchResult, error) {
+	dedups := make([]*Deduplication, len(batches))
+	remaining := make([]LedgerBatch, len(batches))
+
+	for i, b := range batches {
+		d, err := DeduplicateInputs(ctx, b.OrganizationID, b.LedgerID, b.Inputs, *options.Dedup)
+		if err != nil {
+			return nil, err
+		}
+
+		if options.Dedup.OnSummary != nil {
+			options.Dedup.OnSummary(d.Summary)
+		}
+
+		dedups[i] = d
+		remaining[i] = LedgerBatch{OrganizationID: b.OrganizationID, LedgerID: b.LedgerID, Inputs: d.Inputs}
+	}
+
+	submitOptions := *options
+	submitOptions.Dedup = nil
+
+	results, err := BatchTransactionsFair(ctx, midazClient, remaining, &submitOptions)
+
+	var recordErr error
+
+	for i := range results {
+		recordErr = errors.Join(recordErr, dedups[i].Record(context.WithoutCancel(ctx), results[i]))
+		results[i] = dedups[i].Expand(results[i])
+	}
+
+	return results, errors.Join(err, recordErr)
+}
@@ -0,0 +1,138 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	pkgerrors "github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/kvstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTransactionsDedup(t *testing.T) {
+	store := kvstore.NewMemory()
+	rejected := pkgerrors.ErrorFromHTTPResponse(http.StatusBadRequest, "req-1", "bad request", "", "transaction", "")
+
+	inputs := func() []*models.CreateTransactionInput {
+		return []*models.CreateTransactionInput{{IdempotencyKey: "k0"}, {IdempotencyKey: "k1"}, {IdempotencyKey: "k2"}}
+	}
+
+	failing := &failingTransactions{errs: map[string]error{"k1": rejected}}
+	options := &BatchOptions{Concurrency: 2, BatchSize: 10, Dedup: &DedupOptions{Store: store}}
+
+	_, err := BatchTransactions(context.Background(), &client.Client{Entity: &entities.Entity{Transactions: failing}}, "org", "ledger", inputs(), options)
+	require.Error(t, err)
+
+	var summary DedupSummary
+
+	options.Dedup.OnSummary = func(s DedupSummary) { summary = s }
+
+	txs := &recordingTransactions{}
+
+	results, err := BatchTransactions(context.Background(), &client.Client{Entity: &entities.Entity{Transactions: txs}}, "org", "ledger", inputs(), options)
+	require.NoError(t, err)
+
+	require.Len(t, txs.inputs, 1, "only the input that failed is submitted again")
+	assert.Equal(t, "k1", txs.inputs[0].IdempotencyKey)
+
+	assert.Equal(t, 3, summary.Total)
+	require.Len(t, summary.Skipped, 2)
+	assert.Equal(t, 0, summary.Skipped[0].Index)
+	assert.Equal(t, "k2", summary.Skipped[1].TransactionID)
+	assert.False(t, summary.Skipped[1].ConfirmedAt.IsZero())
+
+	require.Len(t, results, 3)
+
+	for i, r := range results {
+		assert.Equal(t, i, r.Index)
+		assert.NoError(t, r.Error)
+	}
+
+	assert.True(t, results[0].Deduplicated)
+	assert.Equal(t, "k0", results[0].TransactionID)
+	assert.False(t, results[1].Deduplicated)
+
+	batchSummary := GetBatchSummary(results)
+	assert.Equal(t, 3, batchSummary.SuccessCount)
+	assert.Equal(t, 2, batchSummary.DeduplicatedCount)
+
+	// Another ledger is not deduplicated against this one
+	_, err = BatchTransactions(context.Background(), &client.Client{Entity: &entities.Entity{Transactions: txs}}, "org", "other", inputs(), options)
+	require.NoError(t, err)
+	assert.Len(t, txs.inputs, 4)
+}
+
+func TestDeduplicateInputsWithoutKeys(t *testing.T) {
+	store := kvstore.NewMemory()
+
+	inputs := func() []*models.CreateTransactionInput {
+		return []*models.CreateTransactionInput{
+			transferInput("@a", "@b", "USD", "1", ""),
+			transferInput("@a", "@b", "USD", "1", ""),
+			transferInput("@a", "@c", "USD", "1", ""),
+		}
+	}
+
+	first := inputs()
+	keys := DedupKeys("org", "ledger", first)
+	assert.NotEqual(t, keys[0], keys[1], "identical inputs are told apart by occurrence")
+	assert.Equal(t, keys, DedupKeys("org", "ledger", inputs()))
+
+	d, err := DeduplicateInputs(context.Background(), "org", "ledger", first, DedupOptions{Store: store})
+	require.NoError(t, err)
+	require.Len(t, d.Inputs, 3)
+	assert.NotEmpty(t, first[0].IdempotencyKey, "a deterministic idempotency key is set")
+	assert.NotEqual(t, first[0].IdempotencyKey, first[1].IdempotencyKey)
+
+	require.NoError(t, d.Record(context.Background(), []BatchResult{
+		{TransactionID: "t0", StartedAt: time.Now()},
+		{Error: errors.New("rejected"), StartedAt: time.Now()},
+	}))
+
+	d, err = DeduplicateInputs(context.Background(), "org", "ledger", inputs(), DedupOptions{Store: store})
+	require.NoError(t, err)
+	require.Len(t, d.Inputs, 2)
+	assert.Equal(t, first[1].IdempotencyKey, d.Inputs[0].IdempotencyKey)
+
+	expanded := d.Expand([]BatchResult{{TransactionID: "t1"}, {TransactionID: "t2"}})
+	assert.Equal(t, []string{"t0", "t1", "t2"}, []string{expanded[0].TransactionID, expanded[1].TransactionID, expanded[2].TransactionID})
+	assert.Equal(t, 2, expanded[2].Index)
+}
+
+func TestDedupKeysMatchFingerprint(t *testing.T) {
+	reformatted := transferInput("@a", "@b", "usd", "1.00", "")
+
+	assert.Equal(t,
+		DedupKeys("org", "ledger", []*models.CreateTransactionInput{transferInput("@a", "@b", "USD", "1", "")}),
+		DedupKeys("org", "ledger", []*models.CreateTransactionInput{reformatted}),
+		"inputs with the same fingerprint have the same key")
+}
+
+func TestDeduplicateInputsWindow(t *testing.T) {
+	store := kvstore.NewMemory()
+	inputs := []*models.CreateTransactionInput{{IdempotencyKey: "k0"}}
+	key := DefaultDedupPrefix + DedupKeys("org", "ledger", inputs)[0]
+
+	require.NoError(t, kvstore.PutJSON(context.Background(), store, key, dedupRecord{TransactionID: "t0", ConfirmedAt: time.Now().Add(-2 * time.Minute)}))
+
+	d, err := DeduplicateInputs(context.Background(), "org", "ledger", inputs, DedupOptions{Store: store, Window: 5 * time.Minute})
+	require.NoError(t, err)
+	assert.Empty(t, d.Inputs)
+
+	d, err = DeduplicateInputs(context.Background(), "org", "ledger", inputs, DedupOptions{Store: store, Window: time.Minute})
+	require.NoError(t, err)
+	assert.Len(t, d.Inputs, 1, "confirmations older than the window are ignored")
+
+	_, err = store.Get(context.Background(), key)
+	assert.ErrorIs(t, err, kvstore.ErrNotFound, "and dropped")
+
+	_, err = DeduplicateInputs(context.Background(), "org", "ledger", inputs, DedupOptions{})
+	assert.Error(t, err)
+}
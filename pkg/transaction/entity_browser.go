@@ -0,0 +1,165 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ReportEntity is a row of the entity browser of the HTML report.
+type ReportEntity struct {
+	// Kind is the kind of entity, such as "account" or "ledger"
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+
+	// Alias and Type are only known for accounts recorded with AddAccount
+	Alias string `json:"alias,omitempty"`
+	Type  string `json:"type,omitempty"`
+
+	// Balance is the balance sampled for the account in the data summary of
+	// the report, formatted as sorted key=value pairs
+	Balance string `json:"balance,omitempty"`
+}
+
+// AddAccount records a created account with its alias and type, which the
+// entity browser of the HTML report shows next to its ID. Empty alias and
+// type are not recorded.
+func (ids *ReportEntityIDs) AddAccount(id, alias, accountType string) {
+	ids.AccountIDs = append(ids.AccountIDs, id)
+
+	if alias != "" {
+		if ids.AccountAliases == nil {
+			ids.AccountAliases = make(map[string]string)
+		}
+
+		ids.AccountAliases[id] = alias
+	}
+
+	if accountType != "" {
+		if ids.AccountTypes == nil {
+			ids.AccountTypes = make(map[string]string)
+		}
+
+		ids.AccountTypes[id] = accountType
+	}
+}
+
+// Browse lists the entities of the report, in creation order within each
+// kind, with the aliases and types of the accounts and the balances sampled
+// in the data summary (keyed by alias, or by ID for accounts without one).
+// It returns nil when the report has no entity IDs.
+func (r *GenerationReport) Browse() []ReportEntity {
+	if r.Entities == nil {
+		return nil
+	}
+
+	ids := r.Entities.IDs
+	kinds := []struct {
+		kind string
+		ids  []string
+	}{
+		{"organization", ids.OrganizationIDs},
+		{"ledger", ids.LedgerIDs},
+		{"asset", ids.AssetIDs},
+		{"account", ids.AccountIDs},
+		{"portfolio", ids.PortfolioIDs},
+		{"segment", ids.SegmentIDs},
+		{"transaction", ids.TransactionIDs},
+	}
+
+	var balances map[string]map[string]any
+	if r.DataSummary != nil {
+		balances = r.DataSummary.BalanceSummaries
+	}
+
+	var entities []ReportEntity
+
+	for _, k := range kinds {
+		for _, id := range k.ids {
+			entity := ReportEntity{Kind: k.kind, ID: id}
+
+			if k.kind == "account" {
+				entity.Alias = ids.AccountAliases[id]
+				entity.Type = ids.AccountTypes[id]
+
+				key := entity.Alias
+				if key == "" {
+					key = id
+				}
+
+				entity.Balance = formatBalanceSummary(balances[key])
+			}
+
+			entities = append(entities, entity)
+		}
+	}
+
+	return entities
+}
+
+// formatBalanceSummary formats a balance summary as sorted key=value pairs.
+func formatBalanceSummary(summary map[string]any) string {
+	if len(summary) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(summary))
+	for k := range summary {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, summary[k])
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// writeHTMLEntityBrowserSection writes a searchable table of the entities of
+// the run. The entities are embedded as JSON and rendered by a small inline
+// script filtering them as the reviewer types, so large datasets stay cheap
+// to open.
+func (r *GenerationReport) writeHTMLEntityBrowserSection(b *strings.Builder) {
+	entities := r.Browse()
+	if len(entities) == 0 {
+		return
+	}
+
+	// json.Marshal escapes <, > and &, so the payload can't close the script element
+	payload, err := json.Marshal(entities)
+	if err != nil {
+		return
+	}
+
+	_, _ = fmt.Fprintf(b, "<div class=\"section\"><h2>Entity Browser</h2>")
+	_, _ = fmt.Fprintf(b, "<p><input type=\"search\" id=\"entity-search\" placeholder=\"Filter by kind, ID, alias, type or balance\" size=\"48\"> <span class=\"muted\" id=\"entity-count\"></span></p>")
+	_, _ = fmt.Fprintf(b, "<table><thead><tr><th>Kind</th><th>ID</th><th>Alias</th><th>Type</th><th>Balance (sampled)</th></tr></thead><tbody id=\"entity-rows\"></tbody></table>")
+	_, _ = fmt.Fprintf(b, "<script type=\"application/json\" id=\"entity-data\">%s</script>", payload)
+	_, _ = fmt.Fprintf(b, "<script>%s</script></div>", entityBrowserScript)
+}
+
+// entityBrowserScript renders the rows of the entity browser matching every
+// word of the search box, up to a page of them.
+const entityBrowserScript = `(function(){
+var entities=JSON.parse(document.getElementById('entity-data').textContent);
+var rows=document.getElementById('entity-rows'),count=document.getElementById('entity-count'),search=document.getElementById('entity-search');
+var fields=['kind','id','alias','type','balance'],LIMIT=500;
+entities.forEach(function(e){e._text=fields.map(function(f){return (e[f]||'').toLowerCase();}).join(' ');});
+function render(){
+var words=search.value.toLowerCase().split(/\s+/).filter(Boolean);
+var matched=entities.filter(function(e){return words.every(function(w){return e._text.indexOf(w)>=0;});});
+rows.textContent='';
+matched.slice(0,LIMIT).forEach(function(e){
+var tr=document.createElement('tr');
+fields.forEach(function(f){var td=document.createElement('td');td.textContent=e[f]||'';tr.appendChild(td);});
+rows.appendChild(tr);
+});
+count.textContent=matched.length+' of '+entities.length+' entities'+(matched.length>LIMIT?' (first '+LIMIT+' shown, refine the filter)':'');
+}
+search.addEventListener('input',render);
+render();
+})();`
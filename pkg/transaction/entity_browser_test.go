@@ -0,0 +1,63 @@
+package transaction
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerationReportBrowse(t *testing.T) {
+	ids := ReportEntityIDs{
+		OrganizationIDs: []string{"org-1"},
+		LedgerIDs:       []string{"ledger-1"},
+		TransactionIDs:  []string{"tx-1"},
+	}
+	ids.AddAccount("acc-1", "@alice", "deposit")
+	ids.AddAccount("acc-2", "", "")
+
+	report := &GenerationReport{
+		Entities: &ReportEntities{IDs: ids},
+		DataSummary: &ReportDataSummary{BalanceSummaries: map[string]map[string]any{
+			"@alice": {"asset": "USD", "available": "10.50"},
+			"acc-2":  {"asset": "BRL", "available": "3"},
+		}},
+	}
+
+	entities := report.Browse()
+	require.Len(t, entities, 5)
+
+	assert.Equal(t, ReportEntity{Kind: "organization", ID: "org-1"}, entities[0])
+	assert.Equal(t, ReportEntity{Kind: "ledger", ID: "ledger-1"}, entities[1])
+	assert.Equal(t, ReportEntity{Kind: "account", ID: "acc-1", Alias: "@alice", Type: "deposit", Balance: "asset=USD available=10.50"}, entities[2])
+	assert.Equal(t, ReportEntity{Kind: "account", ID: "acc-2", Balance: "asset=BRL available=3"}, entities[3], "balances of accounts without alias are keyed by ID")
+	assert.Equal(t, ReportEntity{Kind: "transaction", ID: "tx-1"}, entities[4])
+
+	assert.Nil(t, (&GenerationReport{}).Browse())
+}
+
+func TestGenerationReportEntityBrowserHTML(t *testing.T) {
+	ids := ReportEntityIDs{}
+	ids.AddAccount("acc-1", "</script><b>x", "deposit")
+
+	report := &GenerationReport{GeneratedAt: time.Now().UTC(), Entities: &ReportEntities{IDs: ids}}
+	html := string(report.ToHTML())
+
+	assert.Contains(t, html, "Entity Browser")
+	assert.Contains(t, html, `id="entity-search"`)
+	assert.NotContains(t, html, "</script><b>x", "aliases can't close the script element")
+
+	m := regexp.MustCompile(`<script type="application/json" id="entity-data">(.*?)</script>`).FindStringSubmatch(html)
+	require.Len(t, m, 2)
+
+	var entities []ReportEntity
+	require.NoError(t, json.Unmarshal([]byte(m[1]), &entities))
+	require.Len(t, entities, 1)
+	assert.Equal(t, "</script><b>x", entities[0].Alias)
+
+	empty := &GenerationReport{GeneratedAt: time.Now().UTC(), Entities: &ReportEntities{}}
+	assert.NotContains(t, string(empty.ToHTML()), "entity-data")
+}
@@ -0,0 +1,185 @@
+package transaction
+
+import (
+	stderrors "errors"
+	"fmt"
+	"html"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// maxErrorExamples bounds the examples kept per error group.
+const maxErrorExamples = 3
+
+// ReportErrorExample points at a failed transaction of the run.
+type ReportErrorExample struct {
+	// Index is the position of the transaction input in the batch
+	Index     int    `json:"index"`
+	RequestID string `json:"requestId,omitempty"`
+	Message   string `json:"message"`
+}
+
+// ReportErrorGroup aggregates the failed transactions sharing a category and
+// a code.
+type ReportErrorGroup struct {
+	// Category is the transaction error category (see errors.CategorizeTransactionError)
+	Category string `json:"category"`
+
+	// Code is the error code returned by the API, or HTTP_<status> when the
+	// error only has a status; empty when neither is known
+	Code string `json:"code,omitempty"`
+
+	Count int `json:"count"`
+
+	// FirstSeen and LastSeen are the completion times of the first and last
+	// failures, zero when the results carry no timestamps
+	FirstSeen time.Time `json:"firstSeen,omitzero"`
+	LastSeen  time.Time `json:"lastSeen,omitzero"`
+
+	// Examples lists up to three failed transactions of the group
+	Examples []ReportErrorExample `json:"examples"`
+}
+
+// BuildErrorBreakdown groups the failed results by transaction error category
+// and code, most frequent first. Errors are classified by type, status and
+// code rather than by matching their messages.
+//
+// Example:
+//
+//	for _, g := range transaction.BuildErrorBreakdown(results) {
+//	    fmt.Printf("%s/%s: %d (e.g. input #%d)\n", g.Category, g.Code, g.Count, g.Examples[0].Index)
+//	}
+func BuildErrorBreakdown(results []BatchResult) []ReportErrorGroup {
+	var breakdown errorBreakdown
+
+	for _, result := range results {
+		breakdown.add(result)
+	}
+
+	return breakdown.groups()
+}
+
+// errorBreakdown accumulates the error groups of BuildErrorBreakdown one
+// result at a time.
+type errorBreakdown map[[2]string]*ReportErrorGroup
+
+// add adds a result to its group when it failed.
+func (b *errorBreakdown) add(result BatchResult) {
+	if result.Error == nil {
+		return
+	}
+
+	if *b == nil {
+		*b = make(errorBreakdown)
+	}
+
+	category := errors.CategorizeTransactionError(result.Error)
+	code, requestID := errorCodeAndRequest(result.Error)
+	key := [2]string{category, code}
+
+	g, ok := (*b)[key]
+	if !ok {
+		g = &ReportErrorGroup{Category: category, Code: code}
+		(*b)[key] = g
+	}
+
+	g.Count++
+
+	if !result.StartedAt.IsZero() {
+		seen := result.StartedAt.Add(result.Duration).UTC()
+		if g.FirstSeen.IsZero() || seen.Before(g.FirstSeen) {
+			g.FirstSeen = seen
+		}
+
+		if seen.After(g.LastSeen) {
+			g.LastSeen = seen
+		}
+	}
+
+	if len(g.Examples) < maxErrorExamples {
+		g.Examples = append(g.Examples, ReportErrorExample{Index: result.Index, RequestID: requestID, Message: result.Error.Error()})
+	}
+}
+
+// groups returns copies of the groups, most frequent first.
+func (b errorBreakdown) groups() []ReportErrorGroup {
+	breakdown := make([]ReportErrorGroup, 0, len(b))
+	for _, g := range b {
+		group := *g
+		group.Examples = slices.Clone(g.Examples)
+		breakdown = append(breakdown, group)
+	}
+
+	sort.Slice(breakdown, func(i, j int) bool {
+		a, b := breakdown[i], breakdown[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+
+		return a.Code < b.Code
+	})
+
+	return breakdown
+}
+
+// errorCodeAndRequest returns the code and the request ID of err.
+func errorCodeAndRequest(err error) (code, requestID string) {
+	var mdzErr *errors.Error
+	if stderrors.As(err, &mdzErr) {
+		requestID = mdzErr.RequestID
+
+		switch {
+		case mdzErr.Code != "":
+			return string(mdzErr.Code), requestID
+		case mdzErr.StatusCode != 0:
+			return fmt.Sprintf("HTTP_%d", mdzErr.StatusCode), requestID
+		}
+	}
+
+	return errors.GetErrorDetails(err).Code, requestID
+}
+
+// writeHTMLErrorBreakdownSection writes the error breakdown as a table.
+func (r *GenerationReport) writeHTMLErrorBreakdownSection(b *strings.Builder) {
+	if len(r.ErrorBreakdown) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(b, "<div class=\"section\"><h2>Errors by Category</h2><table><thead>")
+	_, _ = fmt.Fprintf(b, "<tr><th>Category</th><th>Code</th><th>Count</th><th>First seen</th><th>Last seen</th><th>Examples</th></tr></thead><tbody>")
+
+	for _, g := range r.ErrorBreakdown {
+		examples := make([]string, 0, len(g.Examples))
+		for _, ex := range g.Examples {
+			ref := fmt.Sprintf("input #%d", ex.Index)
+			if ex.RequestID != "" {
+				ref += ", request " + ex.RequestID
+			}
+
+			examples = append(examples, fmt.Sprintf("<div><code>%s</code> %s</div>", html.EscapeString(ref), html.EscapeString(ex.Message)))
+		}
+
+		_, _ = fmt.Fprintf(b, "<tr><td>%s</td><td>%s</td><td>%d</td><td>%s</td><td>%s</td><td>%s</td></tr>",
+			html.EscapeString(g.Category), html.EscapeString(g.Code), g.Count,
+			formatSeen(g.FirstSeen), formatSeen(g.LastSeen), strings.Join(examples, ""))
+	}
+
+	_, _ = fmt.Fprintf(b, "</tbody></table></div>")
+}
+
+// formatSeen formats an occurrence time for the HTML report.
+func formatSeen(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.Format(time.RFC3339)
+}
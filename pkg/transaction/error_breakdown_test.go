@@ -0,0 +1,72 @@
+package transaction
+
+import (
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildErrorBreakdown(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	insufficient := func() error {
+		err := errors.NewInsufficientBalanceError("CreateTransaction", "acc-1", nil)
+		err.RequestID = "req-1"
+
+		return err
+	}
+
+	results := []BatchResult{
+		{Index: 0},
+		{Index: 1, Error: insufficient(), StartedAt: base.Add(2 * time.Second), Duration: time.Second},
+		{Index: 2, Error: insufficient(), StartedAt: base, Duration: time.Second},
+		{Index: 3, Error: insufficient()},
+		{Index: 4, Error: insufficient()},
+		{Index: 5, Error: errors.NewNotFoundError("CreateTransaction", "account", "acc-9", nil)},
+		{Index: 6, Error: stderrors.New("connection reset")},
+	}
+
+	breakdown := BuildErrorBreakdown(results)
+	require.Len(t, breakdown, 3)
+
+	top := breakdown[0]
+	assert.Equal(t, "insufficient_balance", top.Category)
+	assert.Equal(t, string(errors.CodeInsufficientBalance), top.Code)
+	assert.Equal(t, 4, top.Count)
+	assert.Equal(t, base.Add(time.Second), top.FirstSeen)
+	assert.Equal(t, base.Add(3*time.Second), top.LastSeen)
+	require.Len(t, top.Examples, maxErrorExamples)
+	assert.Equal(t, 1, top.Examples[0].Index)
+	assert.Equal(t, "req-1", top.Examples[0].RequestID)
+
+	assert.Equal(t, "not_found", breakdown[2].Category)
+	assert.Equal(t, string(errors.CodeNotFound), breakdown[2].Code)
+	assert.True(t, breakdown[2].FirstSeen.IsZero())
+
+	assert.Empty(t, breakdown[1].Code, "plain errors have no code")
+
+	assert.Empty(t, BuildErrorBreakdown([]BatchResult{{Index: 0}}))
+}
+
+func TestGenerationReportErrorBreakdown(t *testing.T) {
+	report := NewGenerationReport([]BatchResult{
+		{Index: 7, Error: errors.NewValidationError("CreateTransaction", "amount <script>", nil)},
+	}, "", nil)
+
+	require.Len(t, report.ErrorBreakdown, 1)
+
+	data, err := report.ToJSON(false)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"errorBreakdown":[{"category":"validation"`)
+	assert.NotContains(t, string(data), "firstSeen", "unknown times are omitted")
+
+	html := string(report.ToHTML())
+	assert.Contains(t, html, "Errors by Category")
+	assert.Contains(t, html, "input #7")
+	assert.Contains(t, html, "&lt;script&gt;")
+	assert.NotContains(t, html, "amount <script>")
+}
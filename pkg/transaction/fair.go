@@ -0,0 +1,286 @@
+package transaction
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+)
+
+// LedgerBatch is the transactions of one ledger in a multi-ledger batch.
+type LedgerBatch struct {
+	OrganizationID string
+	LedgerID       string
+	Inputs         []*models.CreateTransactionInput
+}
+
+// BatchTransactionsFair processes the transactions of several ledgers with a
+// single pool of options.Concurrency workers, interleaving them fairly instead
+// of finishing one ledger before starting the next: workers take transactions
+// from the ledgers in turn, and within a ledger from its accounts in turn, so
+// that no ledger or account becomes a hot partition on the backend while the
+// others wait. The transactions of an account are started in input order.
+//
+// Set options.MaxInFlightPerLedger to also cap the transactions of a ledger
+// in flight, so that a slow ledger can't hold every worker. The account of a
+// transaction is its first source account other than an @external one, or
+// else its first destination account.
+//
+// Retries, idempotency keys, the event log, StopOnError and cancellation
+// behave as in BatchTransactions, a cancelled batch returning a cancellation
+// error; OnProgress receives the number of transactions completed across all
+// ledgers and the result, whose Index is the position of the transaction in
+// its ledger batch. With PreserveOrder, the results of each ledger batch reach
+// OnProgress in its input order.
+//
+// Returns the results of each ledger batch, in the order of batches and of
+// their inputs.
+func BatchTransactionsFair(ctx context.Context, midazClient *client.Client, batches []LedgerBatch, options *BatchOptions) ([][]BatchResult, error) {
+	options = normalizeOptions(options)
+	if options.Dedup != nil {
+		return batchFairDeduplicated(ctx, midazClient, batches, options)
+	}
+
+	if options.Aggregate != nil {
+		return batchFairAggregated(ctx, midazClient, batches, options)
+	}
+
+	results := make([][]BatchResult, len(batches))
+	startedAt := time.Now()
+	scheduler := &fairScheduler{maxPerLedger: options.MaxInFlightPerLedger}
+	scheduler.cond = sync.NewCond(&scheduler.mu)
+
+	total := 0
+	for _, b := range batches {
+		total += len(b.Inputs)
+	}
+
+	var completed atomic.Int64
+
+	ledgerOptions := *options
+	if options.OnProgress != nil {
+		ledgerOptions.OnProgress = func(_, _ int, result BatchResult) {
+			options.OnProgress(int(completed.Add(1)), total, result)
+		}
+	}
+
+	processors := make([]*batchProcessor, len(batches))
+
+	for i, b := range batches {
+		results[i] = make([]BatchResult, len(b.Inputs))
+		processor := &batchProcessor{
+			ctx:      ctx,
+			client:   midazClient,
+			orgID:    b.OrganizationID,
+			ledgerID: b.LedgerID,
+			inputs:   b.Inputs,
+			options:  &ledgerOptions,
+			results:  results[i],
+		}
+
+		processors[i] = processor
+		scheduler.add(processor)
+	}
+
+	// Wake the workers waiting for room in flight as soon as ctx is done
+	stopOnCancel := context.AfterFunc(ctx, scheduler.stop)
+	defer stopOnCancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for range min(options.Concurrency, total) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				ledger, index, ok := scheduler.take()
+				if !ok {
+					return
+				}
+
+				if ctx.Err() != nil {
+					scheduler.done(ledger)
+					return
+				}
+
+				err := ledger.processor.processTransaction(index)
+				scheduler.done(ledger)
+
+				if err != nil && options.StopOnError {
+					errOnce.Do(func() { firstErr = err })
+					scheduler.stop()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for _, processor := range processors {
+		processor.flushProgress()
+		processor.markCancelled()
+	}
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = errors.NewCancellationError("BatchTransactionsFair", context.Cause(ctx))
+	}
+
+	if options.Events != nil {
+		var all []BatchResult
+		for _, r := range results {
+			all = append(all, r...)
+		}
+
+		emitBatchFinished(ctx, options, "", all, time.Since(startedAt))
+	}
+
+	return results, firstErr
+}
+
+// fairLedger is the pending transactions of a ledger, queued per account.
+type fairLedger struct {
+	processor *batchProcessor
+	accounts  [][]int
+	next      int
+	pending   int
+	inFlight  int
+}
+
+// pop returns the next transaction of the ledger, from the next account with pending transactions.
+func (l *fairLedger) pop() int {
+	for len(l.accounts[l.next]) == 0 {
+		l.next = (l.next + 1) % len(l.accounts)
+	}
+
+	queue := l.accounts[l.next]
+	l.accounts[l.next] = queue[1:]
+	l.next = (l.next + 1) % len(l.accounts)
+	l.pending--
+
+	return queue[0]
+}
+
+// fairScheduler hands out the transactions of the ledgers in turn.
+type fairScheduler struct {
+	mu           sync.Mutex
+	cond         *sync.Cond
+	ledgers      []*fairLedger
+	next         int
+	pending      int
+	maxPerLedger int
+	stopped      bool
+}
+
+// add queues the transactions of a ledger, grouped by account in input order.
+func (s *fairScheduler) add(processor *batchProcessor) {
+	ledger := &fairLedger{processor: processor, pending: len(processor.inputs)}
+	positions := make(map[string]int)
+
+	for i, input := range processor.inputs {
+		key := accountKey(input)
+
+		pos, ok := positions[key]
+		if !ok {
+			pos = len(ledger.accounts)
+			positions[key] = pos
+			ledger.accounts = append(ledger.accounts, nil)
+		}
+
+		ledger.accounts[pos] = append(ledger.accounts[pos], i)
+	}
+
+	s.ledgers = append(s.ledgers, ledger)
+	s.pending += ledger.pending
+}
+
+// take returns the next transaction to process, from the next ledger that has
+// pending transactions and room in flight, waiting for room if needed. It
+// returns false once all transactions are taken or the scheduler is stopped.
+func (s *fairScheduler) take() (*fairLedger, int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		if s.stopped || s.pending == 0 {
+			return nil, 0, false
+		}
+
+		for i := range s.ledgers {
+			ledger := s.ledgers[(s.next+i)%len(s.ledgers)]
+			if ledger.pending == 0 || (s.maxPerLedger > 0 && ledger.inFlight >= s.maxPerLedger) {
+				continue
+			}
+
+			s.next = (s.next + i + 1) % len(s.ledgers)
+			s.pending--
+			ledger.inFlight++
+
+			return ledger, ledger.pop(), true
+		}
+
+		s.cond.Wait()
+	}
+}
+
+// done releases the room in flight of a processed transaction.
+func (s *fairScheduler) done(ledger *fairLedger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ledger.inFlight--
+	s.cond.Broadcast()
+}
+
+// stop makes take return false, leaving the pending transactions unprocessed.
+func (s *fairScheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+	s.cond.Broadcast()
+}
+
+// accountKey returns the account a transaction is scheduled by: its first
+// source account other than an @external one, or else its first destination.
+func accountKey(input *models.CreateTransactionInput) string {
+	if input == nil {
+		return ""
+	}
+
+	if send := input.Send; send != nil {
+		if send.Source != nil {
+			for _, from := range send.Source.From {
+				if !strings.HasPrefix(from.Account, "@external/") {
+					return from.Account
+				}
+			}
+		}
+
+		if send.Distribute != nil && len(send.Distribute.To) > 0 {
+			return send.Distribute.To[0].Account
+		}
+	}
+
+	for _, op := range input.Operations {
+		if op.AccountAlias != nil && *op.AccountAlias != "" {
+			return *op.AccountAlias
+		}
+
+		if op.AccountID != "" {
+			return op.AccountID
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,164 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderedTransactions records the ledger and account of each transaction, in call order.
+type orderedTransactions struct {
+	entities.TransactionsService
+
+	mu       sync.Mutex
+	calls    []string
+	inFlight map[string]int
+	peak     map[string]int
+	delay    time.Duration
+	fail     string
+}
+
+func (o *orderedTransactions) CreateTransaction(_ context.Context, _, ledgerID string, input *models.CreateTransactionInput) (*models.Transaction, error) {
+	o.mu.Lock()
+	o.calls = append(o.calls, ledgerID+":"+accountKey(input))
+	o.inFlight[ledgerID]++
+	o.peak[ledgerID] = max(o.peak[ledgerID], o.inFlight[ledgerID])
+	o.mu.Unlock()
+
+	time.Sleep(o.delay)
+
+	o.mu.Lock()
+	o.inFlight[ledgerID]--
+	o.mu.Unlock()
+
+	if o.fail != "" && accountKey(input) == o.fail {
+		return nil, errors.New("rejected")
+	}
+
+	return &models.Transaction{ID: input.IdempotencyKey}, nil
+}
+
+func newOrderedTransactions() *orderedTransactions {
+	return &orderedTransactions{inFlight: map[string]int{}, peak: map[string]int{}}
+}
+
+// deposits returns n deposits into each account, account by account.
+func deposits(n int, accounts ...string) []*models.CreateTransactionInput {
+	var inputs []*models.CreateTransactionInput
+
+	for _, account := range accounts {
+		for range n {
+			inputs = append(inputs, &models.CreateTransactionInput{Send: &models.SendInput{
+				Asset:      "USD",
+				Value:      "1",
+				Source:     &models.SourceInput{From: []models.FromToInput{{Account: "@external/USD"}}},
+				Distribute: &models.DistributeInput{To: []models.FromToInput{{Account: account}}},
+			}})
+		}
+	}
+
+	return inputs
+}
+
+func TestBatchTransactionsFairInterleavesLedgersAndAccounts(t *testing.T) {
+	txs := newOrderedTransactions()
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+
+	batches := []LedgerBatch{
+		{OrganizationID: "org", LedgerID: "l1", Inputs: deposits(2, "@a", "@b")},
+		{OrganizationID: "org", LedgerID: "l2", Inputs: deposits(2, "@c")},
+	}
+
+	results, err := BatchTransactionsFair(context.Background(), midazClient, batches, &BatchOptions{Concurrency: 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"l1:@a", "l2:@c", "l1:@b", "l2:@c", "l1:@a", "l1:@b"}, txs.calls)
+
+	require.Len(t, results, 2)
+	require.Len(t, results[0], 4)
+	require.Len(t, results[1], 2)
+
+	for i, r := range results[0] {
+		assert.Equal(t, i, r.Index)
+		assert.NoError(t, r.Error)
+		assert.NotEmpty(t, r.TransactionID)
+	}
+}
+
+func TestBatchTransactionsFairCapsLedgerInFlight(t *testing.T) {
+	txs := newOrderedTransactions()
+	txs.delay = 5 * time.Millisecond
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+
+	batches := []LedgerBatch{
+		{LedgerID: "hot", Inputs: deposits(20, "@a", "@b", "@c")},
+		{LedgerID: "cold", Inputs: deposits(2, "@d")},
+	}
+
+	var progress atomic.Int32
+
+	_, err := BatchTransactionsFair(context.Background(), midazClient, batches, &BatchOptions{
+		Concurrency:          8,
+		MaxInFlightPerLedger: 3,
+		OnProgress: func(completed, total int, _ BatchResult) {
+			progress.Add(1)
+			assert.Equal(t, 62, total)
+			assert.LessOrEqual(t, completed, total)
+		},
+	})
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, txs.peak["hot"], 3)
+	assert.Len(t, txs.calls, 62)
+	assert.Equal(t, int32(62), progress.Load())
+
+	// the cold ledger is served before the hot one is drained
+	last := 0
+	for i, call := range txs.calls {
+		if call == "cold:@d" {
+			last = i
+		}
+	}
+
+	assert.Less(t, last, 10)
+}
+
+func TestBatchTransactionsFairStopOnError(t *testing.T) {
+	txs := newOrderedTransactions()
+	txs.fail = "@b"
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+
+	batches := []LedgerBatch{{LedgerID: "l1", Inputs: deposits(10, "@a", "@b")}}
+
+	_, err := BatchTransactionsFair(context.Background(), midazClient, batches, &BatchOptions{Concurrency: 1, StopOnError: true})
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"l1:@a", "l1:@b"}, txs.calls)
+}
+
+func TestBatchTransactionsFairEmpty(t *testing.T) {
+	results, err := BatchTransactionsFair(context.Background(), &client.Client{}, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestAccountKey(t *testing.T) {
+	alias := "@op"
+
+	assert.Equal(t, "@a", accountKey(&models.CreateTransactionInput{Send: &models.SendInput{
+		Source:     &models.SourceInput{From: []models.FromToInput{{Account: "@external/USD"}, {Account: "@a"}}},
+		Distribute: &models.DistributeInput{To: []models.FromToInput{{Account: "@b"}}},
+	}}))
+	assert.Equal(t, "@b", accountKey(deposits(1, "@b")[0]))
+	assert.Equal(t, "@op", accountKey(&models.CreateTransactionInput{Operations: []models.CreateOperationInput{{AccountAlias: &alias}}}))
+	assert.Empty(t, accountKey(&models.CreateTransactionInput{}))
+}
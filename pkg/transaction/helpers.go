@@ -13,27 +13,55 @@ import (
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
-	"github.com/google/uuid"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/idgen"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/rounding"
+	"github.com/shopspring/decimal"
 )
 
-// formatAmount converts an int64 amount with scale to a decimal string
+// formatAmount converts an int64 amount with scale to a decimal string. The
+// conversion is exact for any scale; a negative scale is treated as zero.
 func formatAmount(amount int64, scale int64) string {
-	if scale == 0 {
+	if scale <= 0 {
 		return strconv.FormatInt(amount, 10)
 	}
 
-	divisor := int64(math.Pow10(int(scale)))
-	whole := amount / divisor
-	fractional := amount % divisor
+	places := int32(min(scale, math.MaxInt32))
 
-	if fractional == 0 {
-		return strconv.FormatInt(whole, 10)
+	value := decimal.New(amount, -places)
+	if value.IsInteger() {
+		return value.String()
 	}
 
-	// Format with proper decimal places
-	formatStr := fmt.Sprintf("%%.%df", scale)
+	return value.StringFixed(places)
+}
 
-	return fmt.Sprintf(formatStr, float64(amount)/float64(divisor))
+// ParseAmount converts a decimal amount such as "10.50" to the fixed-point
+// integer the helpers of this package take, bringing an amount with more
+// decimal places than scale to it under policy.
+//
+// Parameters:
+//   - value: The decimal amount
+//   - scale: The scale/precision of the asset (e.g., 2 for cents)
+//   - policy: How an amount exceeding scale is handled (empty rejects it)
+//
+// Returns:
+//   - The amount as a fixed-point integer, e.g. 1050 for "10.50" with scale 2
+//   - An error wrapping rounding.ErrExceedsScale if policy rejects the amount
+//
+// Example:
+//
+//	amount, err := transaction.ParseAmount("10.505", 2, rounding.PolicyHalfEven) // 1050
+func ParseAmount(value string, scale int64, policy rounding.Policy) (int64, error) {
+	amount, err := decimal.NewFromString(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", value, err)
+	}
+
+	if scale < 0 || scale > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid scale %d", scale)
+	}
+
+	return policy.Minor(amount, int32(scale))
 }
 
 // TransferOptions provides configuration options for transfer transactions
@@ -58,7 +86,7 @@ func DefaultTransferOptions() *TransferOptions {
 		Description:    "Transfer between accounts",
 		Metadata:       map[string]any{"source": "go-sdk-transaction-helper"},
 		Pending:        false,
-		IdempotencyKey: uuid.New().String(),
+		IdempotencyKey: idgen.New(),
 	}
 }
 
@@ -88,6 +116,42 @@ func Transfer(
 	scale int64,
 	assetCode string,
 	opts *TransferOptions,
+) (*models.Transaction, error) {
+	return transfer(ctx, entity, orgID, ledgerID, fromAccountID, toAccountID, formatAmount(amount, scale), assetCode, opts)
+}
+
+// TransferDecimal is Transfer with a decimal amount, such as
+// decimal.RequireFromString("10.50"), sent as is: unlike fixed-point integers,
+// decimal amounts can't overflow whatever the scale of the asset.
+//
+// Example:
+//
+//	tx, err := transaction.TransferDecimal(ctx, entity, orgID, ledgerID, fromID, toID, decimal.RequireFromString("0.000000000000000001"), "ETH", nil)
+func TransferDecimal(
+	ctx context.Context,
+	entity *entities.Entity,
+	orgID, ledgerID string,
+	fromAccountID, toAccountID string,
+	amount decimal.Decimal,
+	assetCode string,
+	opts *TransferOptions,
+) (*models.Transaction, error) {
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("transfer amount must be positive, got %s", amount)
+	}
+
+	return transfer(ctx, entity, orgID, ledgerID, fromAccountID, toAccountID, models.FormatDecimal(amount), assetCode, opts)
+}
+
+// transfer creates a transfer transaction of amountStr, a decimal string
+func transfer(
+	ctx context.Context,
+	entity *entities.Entity,
+	orgID, ledgerID string,
+	fromAccountID, toAccountID string,
+	amountStr string,
+	assetCode string,
+	opts *TransferOptions,
 ) (*models.Transaction, error) {
 	// Use default options if none provided
 	if opts == nil {
@@ -97,12 +161,9 @@ func Transfer(
 	// Ensure idempotency key is set
 	idempotencyKey := opts.IdempotencyKey
 	if idempotencyKey == "" {
-		idempotencyKey = uuid.New().String()
+		idempotencyKey = idgen.New()
 	}
 
-	// Convert amount to string with scale
-	amountStr := formatAmount(amount, scale)
-
 	// Create the transaction input
 	transferInput := &models.CreateTransactionInput{
 		Description:              opts.Description,
@@ -174,7 +235,7 @@ func DefaultDepositOptions() *DepositOptions {
 		Description:       "Deposit from external source",
 		Metadata:          map[string]any{"source": "go-sdk-transaction-helper", "type": "deposit"},
 		Pending:           false,
-		IdempotencyKey:    uuid.New().String(),
+		IdempotencyKey:    idgen.New(),
 		ExternalAccountID: "", // Will be auto-generated based on asset code
 	}
 }
@@ -204,6 +265,42 @@ func Deposit(
 	scale int64,
 	assetCode string,
 	opts *DepositOptions,
+) (*models.Transaction, error) {
+	return deposit(ctx, entity, orgID, ledgerID, toAccountID, formatAmount(amount, scale), assetCode, opts)
+}
+
+// DepositDecimal is Deposit with a decimal amount, such as
+// decimal.RequireFromString("10.50"), sent as is: unlike fixed-point integers,
+// decimal amounts can't overflow whatever the scale of the asset.
+//
+// Example:
+//
+//	tx, err := transaction.DepositDecimal(ctx, entity, orgID, ledgerID, accountID, decimal.RequireFromString("10.50"), "USD", nil)
+func DepositDecimal(
+	ctx context.Context,
+	entity *entities.Entity,
+	orgID, ledgerID string,
+	toAccountID string,
+	amount decimal.Decimal,
+	assetCode string,
+	opts *DepositOptions,
+) (*models.Transaction, error) {
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("deposit amount must be positive, got %s", amount)
+	}
+
+	return deposit(ctx, entity, orgID, ledgerID, toAccountID, models.FormatDecimal(amount), assetCode, opts)
+}
+
+// deposit creates a deposit transaction of amountStr, a decimal string
+func deposit(
+	ctx context.Context,
+	entity *entities.Entity,
+	orgID, ledgerID string,
+	toAccountID string,
+	amountStr string,
+	assetCode string,
+	opts *DepositOptions,
 ) (*models.Transaction, error) {
 	// Use default options if none provided
 	if opts == nil {
@@ -213,7 +310,7 @@ func Deposit(
 	// Ensure idempotency key is set
 	idempotencyKey := opts.IdempotencyKey
 	if idempotencyKey == "" {
-		idempotencyKey = uuid.New().String()
+		idempotencyKey = idgen.New()
 	}
 
 	// Generate external account ID if not specified
@@ -222,9 +319,6 @@ func Deposit(
 		externalAccountID = fmt.Sprintf("@external/%s", assetCode)
 	}
 
-	// Convert amount to string with scale
-	amountStr := formatAmount(amount, scale)
-
 	// Create the transaction input
 	depositInput := &models.CreateTransactionInput{
 		Description:              opts.Description,
@@ -296,7 +390,7 @@ func DefaultWithdrawalOptions() *WithdrawalOptions {
 		Description:       "Withdrawal to external destination",
 		Metadata:          map[string]any{"source": "go-sdk-transaction-helper", "type": "withdrawal"},
 		Pending:           false,
-		IdempotencyKey:    uuid.New().String(),
+		IdempotencyKey:    idgen.New(),
 		ExternalAccountID: "", // Will be auto-generated based on asset code
 	}
 }
@@ -326,6 +420,42 @@ func Withdrawal(
 	scale int64,
 	assetCode string,
 	opts *WithdrawalOptions,
+) (*models.Transaction, error) {
+	return withdrawal(ctx, entity, orgID, ledgerID, fromAccountID, formatAmount(amount, scale), assetCode, opts)
+}
+
+// WithdrawalDecimal is Withdrawal with a decimal amount, such as
+// decimal.RequireFromString("10.50"), sent as is: unlike fixed-point integers,
+// decimal amounts can't overflow whatever the scale of the asset.
+//
+// Example:
+//
+//	tx, err := transaction.WithdrawalDecimal(ctx, entity, orgID, ledgerID, accountID, decimal.RequireFromString("10.50"), "USD", nil)
+func WithdrawalDecimal(
+	ctx context.Context,
+	entity *entities.Entity,
+	orgID, ledgerID string,
+	fromAccountID string,
+	amount decimal.Decimal,
+	assetCode string,
+	opts *WithdrawalOptions,
+) (*models.Transaction, error) {
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("withdrawal amount must be positive, got %s", amount)
+	}
+
+	return withdrawal(ctx, entity, orgID, ledgerID, fromAccountID, models.FormatDecimal(amount), assetCode, opts)
+}
+
+// withdrawal creates a withdrawal transaction of amountStr, a decimal string
+func withdrawal(
+	ctx context.Context,
+	entity *entities.Entity,
+	orgID, ledgerID string,
+	fromAccountID string,
+	amountStr string,
+	assetCode string,
+	opts *WithdrawalOptions,
 ) (*models.Transaction, error) {
 	// Use default options if none provided
 	if opts == nil {
@@ -335,7 +465,7 @@ func Withdrawal(
 	// Ensure idempotency key is set
 	idempotencyKey := opts.IdempotencyKey
 	if idempotencyKey == "" {
-		idempotencyKey = uuid.New().String()
+		idempotencyKey = idgen.New()
 	}
 
 	// Generate external account ID if not specified
@@ -344,9 +474,6 @@ func Withdrawal(
 		externalAccountID = fmt.Sprintf("@external/%s", assetCode)
 	}
 
-	// Convert amount to string with scale
-	amountStr := formatAmount(amount, scale)
-
 	// Create the transaction input
 	withdrawalInput := &models.CreateTransactionInput{
 		Description:              opts.Description,
@@ -416,7 +543,7 @@ func DefaultMultiTransferOptions() *MultiTransferOptions {
 		Description:    "Multi-account transfer",
 		Metadata:       map[string]any{"source": "go-sdk-transaction-helper", "type": "multi-transfer"},
 		Pending:        false,
-		IdempotencyKey: uuid.New().String(),
+		IdempotencyKey: idgen.New(),
 	}
 }
 
@@ -485,7 +612,7 @@ func resolveMultiTransferOptions(opts *MultiTransferOptions) (*MultiTransferOpti
 
 	idempotencyKey := opts.IdempotencyKey
 	if idempotencyKey == "" {
-		idempotencyKey = uuid.New().String()
+		idempotencyKey = idgen.New()
 	}
 
 	return opts, idempotencyKey
@@ -615,7 +742,7 @@ func CreateFromTemplate(
 
 	// Ensure idempotency key is set
 	if idempotencyKey == "" {
-		idempotencyKey = uuid.New().String()
+		idempotencyKey = idgen.New()
 	}
 
 	// Merge metadata
@@ -1,9 +1,13 @@
 package transaction
 
 import (
+	"context"
+	"math"
 	"testing"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/rounding"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -88,6 +92,24 @@ func TestFormatAmount(t *testing.T) {
 			scale:    6,
 			expected: "123.456789",
 		},
+		{
+			name:     "max int64 keeps every digit",
+			amount:   math.MaxInt64,
+			scale:    18,
+			expected: "9.223372036854775807",
+		},
+		{
+			name:     "scale beyond int64 powers of ten",
+			amount:   1,
+			scale:    20,
+			expected: "0.00000000000000000001",
+		},
+		{
+			name:     "negative amount",
+			amount:   -1050,
+			scale:    2,
+			expected: "-10.50",
+		},
 	}
 
 	for _, tt := range tests {
@@ -99,6 +121,53 @@ func TestFormatAmount(t *testing.T) {
 }
 
 // TestDefaultTransferOptions tests the default transfer options
+func TestDecimalHelpersRejectNonPositiveAmounts(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := TransferDecimal(ctx, nil, "org", "ledger", "from", "to", decimal.Zero, "USD", nil)
+	require.ErrorContains(t, err, "transfer amount must be positive")
+
+	_, err = DepositDecimal(ctx, nil, "org", "ledger", "to", decimal.NewFromInt(-1), "USD", nil)
+	require.ErrorContains(t, err, "deposit amount must be positive")
+
+	_, err = WithdrawalDecimal(ctx, nil, "org", "ledger", "from", decimal.Zero, "USD", nil)
+	require.ErrorContains(t, err, "withdrawal amount must be positive")
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		scale    int64
+		policy   rounding.Policy
+		expected int64
+	}{
+		{"fits scale", "10.5", 2, "", 1050},
+		{"trailing zeros", "10.500", 2, rounding.PolicyError, 1050},
+		{"half even down", "10.505", 2, rounding.PolicyHalfEven, 1050},
+		{"half even up", "10.515", 2, rounding.PolicyHalfEven, 1052},
+		{"truncate", "10.519", 2, rounding.PolicyTruncate, 1051},
+		{"truncate negative", "-10.519", 2, rounding.PolicyTruncate, -1051},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := ParseAmount(tt.value, tt.scale, tt.policy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, amount)
+		})
+	}
+
+	_, err := ParseAmount("10.505", 2, "")
+	require.ErrorIs(t, err, rounding.ErrExceedsScale)
+
+	_, err = ParseAmount("ten", 2, rounding.PolicyTruncate)
+	require.Error(t, err)
+
+	_, err = ParseAmount("10", -1, rounding.PolicyTruncate)
+	require.Error(t, err)
+}
+
 func TestDefaultTransferOptions(t *testing.T) {
 	opts := DefaultTransferOptions()
 
@@ -0,0 +1,448 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+)
+
+var (
+	// ErrIngestorFull is returned by Ingestor.TryPush when the buffer is full.
+	ErrIngestorFull = errors.New("ingestor buffer full")
+
+	// ErrIngestorClosed is returned when pushing to a closed Ingestor.
+	ErrIngestorClosed = errors.New("ingestor closed")
+
+	// ErrInvalidIngestItem is returned when pushing an IngestItem without an
+	// organization ID, a ledger ID or a transaction.
+	ErrInvalidIngestItem = errors.New("ingest item requires an organization ID, a ledger ID and a transaction")
+)
+
+// IngestItem is a transaction pushed into an Ingestor.
+type IngestItem struct {
+	OrganizationID string                         `json:"organizationId"`
+	LedgerID       string                         `json:"ledgerId"`
+	Input          *models.CreateTransactionInput `json:"transaction"`
+}
+
+// validate checks that the item can be submitted.
+func (item IngestItem) validate() error {
+	if item.OrganizationID == "" || item.LedgerID == "" || item.Input == nil {
+		return ErrInvalidIngestItem
+	}
+
+	return nil
+}
+
+// IngestResult is the outcome of an IngestItem.
+type IngestResult struct {
+	Item   IngestItem
+	Result BatchResult
+
+	// Lag is the time the item waited in the buffer before its batch was submitted
+	Lag time.Duration
+}
+
+// IngestStats is a snapshot of the state of an Ingestor.
+type IngestStats struct {
+	// Queued counts the items waiting in the buffer
+	Queued int
+
+	// InFlight counts the items of the batch being submitted
+	InFlight int
+
+	Accepted  int64
+	Rejected  int64
+	Submitted int64
+	Failed    int64
+
+	// Lag is the time the oldest item of the last batch waited before submission
+	Lag time.Duration
+}
+
+// IngestorOptions configures an Ingestor.
+type IngestorOptions struct {
+	// BufferSize is the number of items buffered before Push blocks
+	// Default is 1000 if not specified
+	BufferSize int
+	// BatchSize is the maximum number of items submitted together
+	// Default is 100 if not specified
+	BatchSize int
+	// FlushInterval is the longest time a partial batch waits for more items
+	// Default is 1s if not specified
+	FlushInterval time.Duration
+	// Limiter paces the submission of the items; it is not stopped by the Ingestor
+	// Default is nil (no rate limit)
+	Limiter concurrent.Limiter
+	// Batch configures the submission of each batch: concurrency, retries and idempotency keys.
+	// Set Batch.Aggregate to merge the transfers between the same accounts received
+	// within a FlushInterval window into one transaction
+	// Default is DefaultBatchOptions()
+	Batch *BatchOptions
+	// OnResult receives the outcome of each item, from the goroutine of the Ingestor
+	OnResult func(IngestResult)
+}
+
+// DefaultIngestorOptions returns the default ingestor options
+func DefaultIngestorOptions() *IngestorOptions {
+	return &IngestorOptions{
+		BufferSize:    1000,
+		BatchSize:     100,
+		FlushInterval: time.Second,
+		Batch:         DefaultBatchOptions(),
+	}
+}
+
+// queuedItem is an IngestItem with the time it was accepted.
+type queuedItem struct {
+	item     IngestItem
+	accepted time.Time
+}
+
+// Ingestor decouples the systems producing transactions from their submission
+// to Midaz. Producers push transactions into a bounded buffer, directly with
+// Push, from a channel with Consume or over HTTP with Handler; the Ingestor
+// submits them in batches with BatchTransactionsFair, paced by a limiter and
+// retried according to the batch options, and reports the outcome of each one
+// to OnResult.
+//
+// When the buffer is full, Push blocks and TryPush and the HTTP handler
+// reject the transaction, which pushes back on the producers instead of
+// growing the memory without bound. Stats reports the buffer depth and the lag
+// of the submissions.
+//
+//	ingestor := transaction.NewIngestor(ctx, c, nil)
+//	defer ingestor.Close(context.Background())
+//
+//	err := ingestor.Push(ctx, transaction.IngestItem{OrganizationID: orgID, LedgerID: ledgerID, Input: input})
+type Ingestor struct {
+	client  *client.Client
+	options *IngestorOptions
+	queue   chan queuedItem
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+
+	inFlight  atomic.Int64
+	accepted  atomic.Int64
+	rejected  atomic.Int64
+	submitted atomic.Int64
+	failed    atomic.Int64
+	lag       atomic.Int64
+}
+
+// NewIngestor returns a running Ingestor submitting to midazClient until ctx
+// is cancelled or Close is called.
+//
+// Parameters:
+//   - ctx: Context of the submissions; cancelling it stops the Ingestor without draining the buffer
+//   - midazClient: The Midaz SDK client
+//   - options: Options of the Ingestor (optional, pass nil for defaults)
+//
+// Returns:
+//   - A running Ingestor
+func NewIngestor(ctx context.Context, midazClient *client.Client, options *IngestorOptions) *Ingestor {
+	options = normalizeIngestorOptions(options)
+
+	i := &Ingestor{
+		client:  midazClient,
+		options: options,
+		queue:   make(chan queuedItem, options.BufferSize),
+		done:    make(chan struct{}),
+	}
+
+	go i.run(ctx, lifecycle.Track("transaction.Ingestor"))
+
+	return i
+}
+
+// normalizeIngestorOptions fills the unset options with their defaults.
+func normalizeIngestorOptions(options *IngestorOptions) *IngestorOptions {
+	defaults := DefaultIngestorOptions()
+	if options == nil {
+		return defaults
+	}
+
+	normalized := *options
+
+	if normalized.BufferSize < 1 {
+		normalized.BufferSize = defaults.BufferSize
+	}
+
+	if normalized.BatchSize < 1 {
+		normalized.BatchSize = defaults.BatchSize
+	}
+
+	if normalized.FlushInterval <= 0 {
+		normalized.FlushInterval = defaults.FlushInterval
+	}
+
+	if normalized.Batch == nil {
+		normalized.Batch = defaults.Batch
+	}
+
+	return &normalized
+}
+
+// Push adds item to the buffer, blocking while the buffer is full.
+func (i *Ingestor) Push(ctx context.Context, item IngestItem) error {
+	if err := item.validate(); err != nil {
+		i.rejected.Add(1)
+		return err
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if i.closed {
+		i.rejected.Add(1)
+		return ErrIngestorClosed
+	}
+
+	select {
+	case i.queue <- queuedItem{item: item, accepted: time.Now()}:
+		i.accepted.Add(1)
+		return nil
+	case <-ctx.Done():
+		i.rejected.Add(1)
+		return ctx.Err()
+	case <-i.done:
+		i.rejected.Add(1)
+		return ErrIngestorClosed
+	}
+}
+
+// TryPush adds item to the buffer, or returns ErrIngestorFull when it is full.
+func (i *Ingestor) TryPush(item IngestItem) error {
+	if err := item.validate(); err != nil {
+		i.rejected.Add(1)
+		return err
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if i.closed || i.stopped() {
+		i.rejected.Add(1)
+		return ErrIngestorClosed
+	}
+
+	select {
+	case i.queue <- queuedItem{item: item, accepted: time.Now()}:
+		i.accepted.Add(1)
+		return nil
+	default:
+		i.rejected.Add(1)
+		return ErrIngestorFull
+	}
+}
+
+// Consume pushes the items received from items until it is closed, ctx is
+// cancelled or Push fails, blocking on a full buffer so that the producer of
+// the channel is slowed down.
+func (i *Ingestor) Consume(ctx context.Context, items <-chan IngestItem) error {
+	for {
+		select {
+		case item, ok := <-items:
+			if !ok {
+				return nil
+			}
+
+			if err := i.Push(ctx, item); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Handler returns an HTTP handler accepting an IngestItem as a JSON POST
+// body. It answers 202 Accepted once the item is buffered, 429 Too Many
+// Requests with a Retry-After header when the buffer is full, and 503 Service
+// Unavailable once the Ingestor is closed.
+func (i *Ingestor) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		var item IngestItem
+		if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+			http.Error(w, "invalid transaction: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		switch err := i.TryPush(item); {
+		case err == nil:
+			w.WriteHeader(http.StatusAccepted)
+		case errors.Is(err, ErrInvalidIngestItem):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrIngestorFull):
+			w.Header().Set("Retry-After", strconv.Itoa(max(1, int(i.options.FlushInterval.Round(time.Second)/time.Second))))
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		default:
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// stopped reports whether the Ingestor has stopped submitting.
+func (i *Ingestor) stopped() bool {
+	select {
+	case <-i.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stats returns a snapshot of the state of the Ingestor.
+func (i *Ingestor) Stats() IngestStats {
+	return IngestStats{
+		Queued:    len(i.queue),
+		InFlight:  int(i.inFlight.Load()),
+		Accepted:  i.accepted.Load(),
+		Rejected:  i.rejected.Load(),
+		Submitted: i.submitted.Load(),
+		Failed:    i.failed.Load(),
+		Lag:       time.Duration(i.lag.Load()),
+	}
+}
+
+// Close stops accepting items and waits until the buffered ones are
+// submitted, or ctx is done.
+func (i *Ingestor) Close(ctx context.Context) error {
+	i.mu.Lock()
+	if !i.closed {
+		i.closed = true
+		close(i.queue)
+	}
+	i.mu.Unlock()
+
+	select {
+	case <-i.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run collects the buffered items into batches and submits them, calling
+// done when it exits.
+func (i *Ingestor) run(ctx context.Context, done func()) {
+	defer close(i.done)
+	defer done()
+
+	ticker := time.NewTicker(i.options.FlushInterval)
+	defer ticker.Stop()
+
+	pending := make([]queuedItem, 0, i.options.BatchSize)
+
+	for {
+		select {
+		case queued, ok := <-i.queue:
+			if !ok {
+				i.submit(ctx, pending)
+				return
+			}
+
+			pending = append(pending, queued)
+			if len(pending) >= i.options.BatchSize {
+				i.submit(ctx, pending)
+				pending = pending[:0]
+			}
+		case <-ticker.C:
+			i.submit(ctx, pending)
+			pending = pending[:0]
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// submit sends a batch of items, grouped by ledger, and reports their results.
+func (i *Ingestor) submit(ctx context.Context, pending []queuedItem) {
+	if len(pending) == 0 {
+		return
+	}
+
+	i.inFlight.Store(int64(len(pending)))
+	defer i.inFlight.Store(0)
+
+	var (
+		batches  []LedgerBatch
+		items    [][]queuedItem
+		ledgerOf = map[[2]string]int{}
+	)
+
+	for n, queued := range pending {
+		if i.options.Limiter != nil {
+			if err := i.options.Limiter.Wait(ctx); err != nil {
+				i.fail(pending[n:], err)
+				return
+			}
+		}
+
+		key := [2]string{queued.item.OrganizationID, queued.item.LedgerID}
+
+		index, ok := ledgerOf[key]
+		if !ok {
+			index = len(batches)
+			ledgerOf[key] = index
+
+			batches = append(batches, LedgerBatch{OrganizationID: key[0], LedgerID: key[1]})
+			items = append(items, nil)
+		}
+
+		batches[index].Inputs = append(batches[index].Inputs, queued.item.Input)
+		items[index] = append(items[index], queued)
+	}
+
+	submittedAt := time.Now()
+	i.lag.Store(int64(submittedAt.Sub(pending[0].accepted)))
+
+	results, _ := BatchTransactionsFair(ctx, i.client, batches, i.options.Batch)
+
+	for b, ledgerResults := range results {
+		for n, result := range ledgerResults {
+			i.submitted.Add(1)
+
+			if result.Error != nil {
+				i.failed.Add(1)
+			}
+
+			if i.options.OnResult != nil {
+				queued := items[b][n]
+				i.options.OnResult(IngestResult{Item: queued.item, Result: result, Lag: submittedAt.Sub(queued.accepted)})
+			}
+		}
+	}
+}
+
+// fail reports the items that could not be submitted as failed with err.
+func (i *Ingestor) fail(pending []queuedItem, err error) {
+	now := time.Now()
+
+	for _, queued := range pending {
+		i.failed.Add(1)
+
+		if i.options.OnResult != nil {
+			i.options.OnResult(IngestResult{Item: queued.item, Result: BatchResult{Index: -1, Error: err}, Lag: now.Sub(queued.accepted)})
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ingestResults collects the results reported by an Ingestor.
+type ingestResults struct {
+	mu      sync.Mutex
+	results []IngestResult
+}
+
+func (r *ingestResults) add(result IngestResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.results = append(r.results, result)
+}
+
+func (r *ingestResults) list() []IngestResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]IngestResult(nil), r.results...)
+}
+
+func TestIngestorSubmitsInBatches(t *testing.T) {
+	tasks := lifecycle.Count("transaction.Ingestor")
+	txs := newOrderedTransactions()
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+	results := &ingestResults{}
+
+	ingestor := NewIngestor(context.Background(), midazClient, &IngestorOptions{
+		BatchSize:     3,
+		FlushInterval: time.Hour,
+		OnResult:      results.add,
+	})
+
+	ctx := context.Background()
+
+	for n, input := range deposits(2, "@a", "@b") {
+		ledgerID := "l1"
+		if n%2 == 1 {
+			ledgerID = "l2"
+		}
+
+		require.NoError(t, ingestor.Push(ctx, IngestItem{OrganizationID: "org", LedgerID: ledgerID, Input: input}))
+	}
+
+	require.NoError(t, ingestor.Close(ctx))
+	assert.Equal(t, tasks, lifecycle.Count("transaction.Ingestor"), "Close ends the ingestor goroutine")
+
+	got := results.list()
+	require.Len(t, got, 4)
+
+	for _, result := range got {
+		assert.NoError(t, result.Result.Error)
+		assert.NotEmpty(t, result.Result.TransactionID)
+		assert.GreaterOrEqual(t, result.Lag, time.Duration(0))
+	}
+
+	stats := ingestor.Stats()
+	assert.Equal(t, int64(4), stats.Accepted)
+	assert.Equal(t, int64(4), stats.Submitted)
+	assert.Zero(t, stats.Failed)
+	assert.Zero(t, stats.Queued)
+
+	assert.ErrorIs(t, ingestor.Push(ctx, IngestItem{OrganizationID: "org", LedgerID: "l1", Input: deposits(1, "@a")[0]}), ErrIngestorClosed)
+}
+
+func TestIngestorFlushesPartialBatches(t *testing.T) {
+	txs := newOrderedTransactions()
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+	submitted := make(chan IngestResult, 1)
+
+	ingestor := NewIngestor(context.Background(), midazClient, &IngestorOptions{
+		BatchSize:     100,
+		FlushInterval: 10 * time.Millisecond,
+		OnResult:      func(result IngestResult) { submitted <- result },
+	})
+	defer func() { _ = ingestor.Close(context.Background()) }()
+
+	require.NoError(t, ingestor.TryPush(IngestItem{OrganizationID: "org", LedgerID: "l1", Input: deposits(1, "@a")[0]}))
+
+	select {
+	case result := <-submitted:
+		assert.NoError(t, result.Result.Error)
+	case <-time.After(5 * time.Second):
+		t.Fatal("partial batch was not flushed")
+	}
+}
+
+func TestIngestorBackpressure(t *testing.T) {
+	txs := newOrderedTransactions()
+	txs.delay = 200 * time.Millisecond
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+
+	ingestor := NewIngestor(context.Background(), midazClient, &IngestorOptions{BufferSize: 1, BatchSize: 1, FlushInterval: time.Hour})
+	defer func() { _ = ingestor.Close(context.Background()) }()
+
+	item := IngestItem{OrganizationID: "org", LedgerID: "l1", Input: deposits(1, "@a")[0]}
+
+	require.NoError(t, ingestor.TryPush(item))
+
+	// The first item is submitted slowly, the second one fills the buffer
+	require.Eventually(t, func() bool { return ingestor.Stats().InFlight == 1 }, 5*time.Second, time.Millisecond)
+	require.NoError(t, ingestor.TryPush(item))
+
+	assert.ErrorIs(t, ingestor.TryPush(item), ErrIngestorFull)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, ingestor.Push(ctx, item), context.DeadlineExceeded)
+	assert.Equal(t, int64(2), ingestor.Stats().Rejected)
+
+	assert.ErrorIs(t, ingestor.TryPush(IngestItem{OrganizationID: "org"}), ErrInvalidIngestItem)
+}
+
+func TestIngestorConsume(t *testing.T) {
+	txs := newOrderedTransactions()
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+	results := &ingestResults{}
+
+	ingestor := NewIngestor(context.Background(), midazClient, &IngestorOptions{OnResult: results.add})
+
+	items := make(chan IngestItem)
+
+	go func() {
+		defer close(items)
+
+		for _, input := range deposits(3, "@a") {
+			items <- IngestItem{OrganizationID: "org", LedgerID: "l1", Input: input}
+		}
+	}()
+
+	require.NoError(t, ingestor.Consume(context.Background(), items))
+	require.NoError(t, ingestor.Close(context.Background()))
+
+	assert.Len(t, results.list(), 3)
+}
+
+func TestIngestorHandler(t *testing.T) {
+	txs := newOrderedTransactions()
+	txs.delay = 200 * time.Millisecond
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+
+	ingestor := NewIngestor(context.Background(), midazClient, &IngestorOptions{BufferSize: 1, BatchSize: 1, FlushInterval: time.Hour})
+	handler := ingestor.Handler()
+
+	post := func(item any) *httptest.ResponseRecorder {
+		body, err := json.Marshal(item)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/ingest", bytes.NewReader(body)))
+
+		return rec
+	}
+
+	item := IngestItem{OrganizationID: "org", LedgerID: "l1", Input: deposits(1, "@a")[0]}
+
+	assert.Equal(t, http.StatusAccepted, post(item).Code)
+	require.Eventually(t, func() bool { return ingestor.Stats().InFlight == 1 }, 5*time.Second, time.Millisecond)
+	assert.Equal(t, http.StatusAccepted, post(item).Code)
+
+	full := post(item)
+	assert.Equal(t, http.StatusTooManyRequests, full.Code)
+	assert.NotEmpty(t, full.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusBadRequest, post(map[string]string{"organizationId": "org"}).Code)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ingest", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	require.NoError(t, ingestor.Close(context.Background()))
+	assert.Equal(t, http.StatusServiceUnavailable, post(item).Code)
+}
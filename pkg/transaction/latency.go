@@ -0,0 +1,46 @@
+package transaction
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/stats"
+)
+
+// LatencyHistogram records the durations of results into an HDR histogram in
+// microseconds (see stats.NewLatencyHistogram). Histograms of several runs can
+// be merged or compared percentile by percentile.
+func LatencyHistogram(results []BatchResult) *stats.Histogram {
+	h := stats.NewLatencyHistogram()
+
+	for _, result := range results {
+		h.RecordDuration(result.Duration)
+	}
+
+	return h
+}
+
+// ToHGRM returns the latency distribution of the transactions in the
+// HdrHistogram percentile format (.hgrm), with values in milliseconds, for
+// the standard HDR plotting and comparison tools.
+func (r *GenerationReport) ToHGRM() []byte {
+	var b bytes.Buffer
+
+	// writes to a bytes.Buffer don't fail
+	_ = LatencyHistogram(r.Results).WritePercentileDistribution(&b, 5, 1000) //nolint:errcheck
+
+	return b.Bytes()
+}
+
+// SaveHGRM writes the latency distribution of the transactions to a .hgrm file.
+func (r *GenerationReport) SaveHGRM(path string) error {
+	return fsutil.WriteFileAtomic(path, r.ToHGRM())
+}
+
+// WriteHGRM writes the latency distribution of the transactions to w in the
+// .hgrm format.
+func (r *GenerationReport) WriteHGRM(w io.Writer) error {
+	_, err := w.Write(r.ToHGRM())
+	return err
+}
@@ -0,0 +1,53 @@
+package transaction
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	results := make([]BatchResult, 0, 100)
+	for i := 1; i <= 100; i++ {
+		results = append(results, BatchResult{Index: i, Duration: time.Duration(i) * time.Millisecond})
+	}
+
+	h := LatencyHistogram(results)
+	assert.Equal(t, int64(100), h.TotalCount())
+	assert.InDelta(t, 50_000, h.ValueAtPercentile(50), 50)
+	assert.InDelta(t, 99_000, h.ValueAtPercentile(99), 100)
+
+	assert.Zero(t, LatencyHistogram(nil).TotalCount())
+}
+
+func TestGenerationReportHGRM(t *testing.T) {
+	report := NewGenerationReport([]BatchResult{
+		{Index: 0, Duration: 10 * time.Millisecond},
+		{Index: 1, Duration: 20 * time.Millisecond},
+	}, "", nil)
+
+	hgrm := string(report.ToHGRM())
+	assert.True(t, strings.HasPrefix(hgrm, "       Value     Percentile TotalCount 1/(1-Percentile)"))
+	assert.Contains(t, hgrm, "Total count    =            2]")
+
+	dir := filepath.ToSlash(t.TempDir())
+	_, err := report.SaveTo(context.Background(), "file://"+dir+"/latency.hgrm", nil)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "latency.hgrm"))
+	require.NoError(t, err)
+	assert.Equal(t, hgrm, string(data))
+
+	path := filepath.Join(t.TempDir(), "run.hgrm")
+	require.NoError(t, report.SaveHGRM(path))
+
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, hgrm, string(data))
+}
@@ -0,0 +1,351 @@
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+)
+
+// DefaultMetricsNamespace prefixes the metric names of a generation report
+const DefaultMetricsNamespace = "midaz_loadtest"
+
+// MetricsOptions configures the metrics exported from a generation report
+type MetricsOptions struct {
+	// Namespace prefixes every metric name
+	// Default is DefaultMetricsNamespace if not specified
+	Namespace string
+	// Labels are added to every sample, e.g. the CI job, branch or commit
+	Labels map[string]string
+}
+
+// PushOptions configures pushing report metrics to a Prometheus Pushgateway
+type PushOptions struct {
+	// Job is the job name of the pushed group
+	// Default is "midaz_loadtest" if not specified
+	Job string
+	// Grouping adds labels to the group key, e.g. {"instance": "ci-runner-1"}
+	Grouping map[string]string
+	// HTTPClient sends the push request
+	// Default is a client with a 30 second timeout if not specified
+	HTTPClient *http.Client
+	// Metrics configures the pushed metrics (optional, pass nil for defaults)
+	Metrics *MetricsOptions
+}
+
+// metricSample is a single sample of a metric family.
+type metricSample struct {
+	suffix string
+	labels [][2]string
+	value  float64
+}
+
+// metricFamily is a named metric with its type, help and samples.
+type metricFamily struct {
+	name    string
+	kind    string
+	help    string
+	samples []metricSample
+}
+
+// ToOpenMetrics returns the report metrics in the OpenMetrics text format,
+// terminated by the "# EOF" marker.
+func (r *GenerationReport) ToOpenMetrics(opts *MetricsOptions) []byte {
+	return writeMetrics(r.metricFamilies(opts), metricsLabels(opts), true)
+}
+
+// ToPrometheusText returns the report metrics in the Prometheus text exposition
+// format (version 0.0.4), as accepted by the Pushgateway.
+func (r *GenerationReport) ToPrometheusText(opts *MetricsOptions) []byte {
+	return writeMetrics(r.metricFamilies(opts), metricsLabels(opts), false)
+}
+
+// SaveOpenMetrics writes the report metrics to a file in the OpenMetrics text format.
+func (r *GenerationReport) SaveOpenMetrics(path string, opts *MetricsOptions) error {
+	return fsutil.WriteFileAtomic(path, r.ToOpenMetrics(opts))
+}
+
+// WriteOpenMetrics writes the report metrics to w in the OpenMetrics text format.
+func (r *GenerationReport) WriteOpenMetrics(w io.Writer, opts *MetricsOptions) error {
+	_, err := w.Write(r.ToOpenMetrics(opts))
+	return err
+}
+
+// PushMetrics pushes the report metrics to the Prometheus Pushgateway at
+// gatewayURL, replacing the metrics previously pushed for the same group.
+func (r *GenerationReport) PushMetrics(ctx context.Context, gatewayURL string, opts *PushOptions) error {
+	if opts == nil {
+		opts = &PushOptions{}
+	}
+
+	endpoint, err := pushURL(gatewayURL, opts.Job, opts.Grouping)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(r.ToPrometheusText(opts.Metrics)))
+	if err != nil {
+		return fmt.Errorf("failed to create push request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("pushgateway returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// pushURL builds the Pushgateway URL of a group. Label values that cannot be
+// used as path segments are base64 encoded as the Pushgateway API expects.
+func pushURL(gatewayURL, job string, grouping map[string]string) (string, error) {
+	if gatewayURL == "" {
+		return "", fmt.Errorf("pushgateway URL is required")
+	}
+
+	if job == "" {
+		job = DefaultMetricsNamespace
+	}
+
+	b := &strings.Builder{}
+	b.WriteString(strings.TrimRight(gatewayURL, "/"))
+	b.WriteString("/metrics")
+
+	segment := func(name, value string) {
+		if value == "" || strings.Contains(value, "/") {
+			fmt.Fprintf(b, "/%s@base64/%s", name, base64.RawURLEncoding.EncodeToString([]byte(value)))
+			return
+		}
+
+		fmt.Fprintf(b, "/%s/%s", name, url.PathEscape(value))
+	}
+
+	segment("job", job)
+
+	for _, name := range sortedKeys(grouping) {
+		segment(name, grouping[name])
+	}
+
+	if _, err := url.ParseRequestURI(b.String()); err != nil {
+		return "", fmt.Errorf("invalid pushgateway URL: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// metricFamilies derives the metric families of the report.
+func (r *GenerationReport) metricFamilies(opts *MetricsOptions) []metricFamily {
+	ns := DefaultMetricsNamespace
+	if opts != nil && opts.Namespace != "" {
+		ns = opts.Namespace
+	}
+
+	s := r.Summary
+	families := []metricFamily{
+		{
+			name: ns + "_transactions", kind: "counter", help: "Transactions processed by the run, by result.",
+			samples: []metricSample{
+				{suffix: "_total", labels: [][2]string{{"result", "success"}}, value: float64(s.SuccessCount)},
+				{suffix: "_total", labels: [][2]string{{"result", "error"}}, value: float64(s.ErrorCount)},
+			},
+		},
+		{name: ns + "_success_ratio", kind: "gauge", help: "Share of successful transactions, between 0 and 1.", samples: []metricSample{{value: s.SuccessRate / 100}}},
+		{name: ns + "_transactions_per_second", kind: "gauge", help: "Successful transactions per second.", samples: []metricSample{{value: s.TransactionsPerSecond}}},
+		r.durationFamily(ns),
+		{name: ns + "_report_timestamp_seconds", kind: "gauge", help: "Time the report was generated.", samples: []metricSample{{value: float64(r.GeneratedAt.UnixMilli()) / 1000}}},
+	}
+
+	if len(s.ErrorCategories) > 0 {
+		f := metricFamily{name: ns + "_errors", kind: "counter", help: "Failed transactions by error category."}
+		for _, category := range sortedKeys(s.ErrorCategories) {
+			f.samples = append(f.samples, metricSample{suffix: "_total", labels: [][2]string{{"category", category}}, value: float64(s.ErrorCategories[category])})
+		}
+
+		families = append(families, f)
+	}
+
+	if len(r.StepTimings) > 0 {
+		f := metricFamily{name: ns + "_step_duration_seconds", kind: "gauge", help: "Duration of each step of the run."}
+		for _, step := range sortedKeys(r.StepTimings) {
+			// timings are human-friendly strings; skip the ones that are not durations
+			if d, err := time.ParseDuration(r.StepTimings[step]); err == nil {
+				f.samples = append(f.samples, metricSample{labels: [][2]string{{"step", step}}, value: d.Seconds()})
+			}
+		}
+
+		if len(f.samples) > 0 {
+			families = append(families, f)
+		}
+	}
+
+	if r.Entities != nil {
+		c := r.Entities.Counts
+		f := metricFamily{name: ns + "_entities", kind: "gauge", help: "Entities created by the run, by kind."}
+
+		for _, e := range []struct {
+			kind  string
+			count int
+		}{
+			{"organizations", c.Organizations},
+			{"ledgers", c.Ledgers},
+			{"assets", c.Assets},
+			{"accounts", c.Accounts},
+			{"portfolios", c.Portfolios},
+			{"segments", c.Segments},
+			{"transactions", c.Transactions},
+		} {
+			f.samples = append(f.samples, metricSample{labels: [][2]string{{"kind", e.kind}}, value: float64(e.count)})
+		}
+
+		families = append(families, f)
+	}
+
+	if r.APIStats != nil {
+		families = append(families, metricFamily{
+			name: ns + "_api_calls", kind: "counter", help: "API calls made by the run.",
+			samples: []metricSample{{suffix: "_total", value: float64(r.APIStats.APICalls)}},
+		})
+	}
+
+	return families
+}
+
+// durationFamily summarizes the transaction durations of the report.
+func (r *GenerationReport) durationFamily(ns string) metricFamily {
+	durations := make([]float64, len(r.Results))
+	sum := 0.0
+
+	for i, result := range r.Results {
+		durations[i] = result.Duration.Seconds()
+		sum += durations[i]
+	}
+
+	sort.Float64s(durations)
+
+	f := metricFamily{name: ns + "_transaction_duration_seconds", kind: "summary", help: "Duration of the transactions of the run."}
+
+	for _, q := range []float64{0.5, 0.9, 0.99} {
+		value := 0.0
+		if len(durations) > 0 {
+			value = durations[min(int(q*float64(len(durations))), len(durations)-1)]
+		}
+
+		f.samples = append(f.samples, metricSample{labels: [][2]string{{"quantile", strconv.FormatFloat(q, 'f', -1, 64)}}, value: value})
+	}
+
+	f.samples = append(f.samples,
+		metricSample{suffix: "_sum", value: sum},
+		metricSample{suffix: "_count", value: float64(len(durations))},
+	)
+
+	return f
+}
+
+// metricsLabels returns the constant labels of opts, sorted by name.
+func metricsLabels(opts *MetricsOptions) [][2]string {
+	if opts == nil {
+		return nil
+	}
+
+	labels := make([][2]string, 0, len(opts.Labels))
+	for _, name := range sortedKeys(opts.Labels) {
+		labels = append(labels, [2]string{name, opts.Labels[name]})
+	}
+
+	return labels
+}
+
+// writeMetrics encodes families in the OpenMetrics or Prometheus text format.
+// OpenMetrics names counter families without their "_total" suffix and ends
+// the exposition with "# EOF".
+func writeMetrics(families []metricFamily, constLabels [][2]string, openMetrics bool) []byte {
+	b := &bytes.Buffer{}
+
+	for _, f := range families {
+		name := f.name
+		if f.kind == "counter" && !openMetrics {
+			name += "_total"
+		}
+
+		fmt.Fprintf(b, "# HELP %s %s\n", name, escapeHelp(f.help))
+		fmt.Fprintf(b, "# TYPE %s %s\n", name, f.kind)
+
+		for _, s := range f.samples {
+			b.WriteString(f.name + s.suffix)
+			writeLabels(b, append(append([][2]string{}, constLabels...), s.labels...))
+			b.WriteString(" " + strconv.FormatFloat(s.value, 'g', -1, 64) + "\n")
+		}
+	}
+
+	if openMetrics {
+		b.WriteString("# EOF\n")
+	}
+
+	return b.Bytes()
+}
+
+// writeLabels writes a label set, if any.
+func writeLabels(b *bytes.Buffer, labels [][2]string) {
+	if len(labels) == 0 {
+		return
+	}
+
+	b.WriteByte('{')
+
+	for i, l := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		fmt.Fprintf(b, "%s=\"%s\"", l[0], escapeLabelValue(l[1]))
+	}
+
+	b.WriteByte('}')
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
+
+func escapeHelp(v string) string {
+	return helpEscaper.Replace(v)
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,122 @@
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func metricsReport() *GenerationReport {
+	results := make([]BatchResult, 0, 10)
+	for i := 1; i <= 10; i++ {
+		var err error
+		if i == 10 {
+			err = errors.New("boom")
+		}
+
+		results = append(results, BatchResult{Index: i, Duration: time.Duration(i) * 100 * time.Millisecond, Error: err})
+	}
+
+	report := NewGenerationReport(results, "test", nil)
+	report.GeneratedAt = time.Unix(1700000000, 0).UTC()
+	report.StepTimings = map[string]string{"accounts": "1.5s", "transactions": "2m0s", "notes": "n/a"}
+	report.Entities = &ReportEntities{Counts: ReportEntityCounts{Organizations: 1, Accounts: 20}}
+	report.APIStats = &ReportAPIStats{APICalls: 42}
+
+	return report
+}
+
+func TestToOpenMetrics(t *testing.T) {
+	out := string(metricsReport().ToOpenMetrics(&MetricsOptions{Labels: map[string]string{"branch": `feat/"x"`}}))
+
+	assert.True(t, strings.HasSuffix(out, "# EOF\n"))
+	assert.Contains(t, out, "# TYPE midaz_loadtest_transactions counter\n")
+	assert.Contains(t, out, `midaz_loadtest_transactions_total{branch="feat/\"x\"",result="success"} 9`)
+	assert.Contains(t, out, `midaz_loadtest_transactions_total{branch="feat/\"x\"",result="error"} 1`)
+	assert.Contains(t, out, `midaz_loadtest_success_ratio{branch="feat/\"x\""} 0.9`)
+	assert.Contains(t, out, `midaz_loadtest_transaction_duration_seconds{branch="feat/\"x\"",quantile="0.5"} 0.6`)
+	assert.Contains(t, out, `midaz_loadtest_transaction_duration_seconds{branch="feat/\"x\"",quantile="0.99"} 1`)
+	assert.Contains(t, out, `midaz_loadtest_transaction_duration_seconds_count{branch="feat/\"x\""} 10`)
+	assert.Contains(t, out, `midaz_loadtest_step_duration_seconds{branch="feat/\"x\"",step="transactions"} 120`)
+	assert.NotContains(t, out, `step="notes"`)
+	assert.Contains(t, out, `midaz_loadtest_entities{branch="feat/\"x\"",kind="accounts"} 20`)
+	assert.Contains(t, out, `midaz_loadtest_api_calls_total{branch="feat/\"x\""} 42`)
+	assert.Contains(t, out, `midaz_loadtest_report_timestamp_seconds{branch="feat/\"x\""} 1.7e+09`)
+	assert.Contains(t, out, "midaz_loadtest_errors_total{")
+}
+
+func TestToPrometheusText(t *testing.T) {
+	out := string(metricsReport().ToPrometheusText(&MetricsOptions{Namespace: "ci"}))
+
+	assert.NotContains(t, out, "# EOF")
+	assert.Contains(t, out, "# TYPE ci_transactions_total counter\n")
+	assert.Contains(t, out, `ci_transactions_total{result="success"} 9`)
+	assert.Contains(t, out, "ci_api_calls_total 42\n")
+}
+
+func TestSaveOpenMetrics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.txt")
+
+	require.NoError(t, metricsReport().SaveOpenMetrics(path, nil))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "midaz_loadtest_transactions_total")
+
+	var buf bytes.Buffer
+
+	require.NoError(t, metricsReport().WriteOpenMetrics(&buf, nil))
+	assert.Equal(t, string(data), buf.String())
+}
+
+func TestPushMetrics(t *testing.T) {
+	var (
+		method, path, contentType string
+		body                      []byte
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, contentType = r.Method, r.URL.EscapedPath(), r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	err := metricsReport().PushMetrics(context.Background(), server.URL+"/", &PushOptions{
+		Job:      "perf",
+		Grouping: map[string]string{"instance": "runner 1", "branch": "feat/x"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/metrics/job/perf/branch@base64/ZmVhdC94/instance/runner%201", path)
+	assert.Contains(t, contentType, "version=0.0.4")
+	assert.Contains(t, string(body), "midaz_loadtest_transactions_total")
+	assert.NotContains(t, string(body), "# EOF")
+}
+
+func TestPushMetricsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad metrics", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := metricsReport().PushMetrics(context.Background(), server.URL, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad metrics")
+
+	err = metricsReport().PushMetrics(context.Background(), "", nil)
+	assert.ErrorContains(t, err, "URL is required")
+}
@@ -0,0 +1,392 @@
+package transaction
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+)
+
+// NettingMode selects how obligations are offset against each other
+type NettingMode string
+
+const (
+	// NettingMultilateral nets every party against the whole set, settling each
+	// party's net position with the fewest transfers the greedy matching finds
+	NettingMultilateral NettingMode = "multilateral"
+
+	// NettingBilateral only offsets obligations between the same pair of parties
+	NettingBilateral NettingMode = "bilateral"
+)
+
+// Obligation is an amount a party owes another
+type Obligation struct {
+	// From is the account (alias or ID) of the debtor
+	From string
+	// To is the account (alias or ID) of the creditor
+	To string
+	// AssetCode is the asset of the obligation; obligations only net within an asset
+	AssetCode string
+	// Amount owed, must be positive
+	Amount decimal.Decimal
+}
+
+// Settlement is a transfer that settles the net position between two parties
+type Settlement struct {
+	From      string
+	To        string
+	AssetCode string
+	Amount    decimal.Decimal
+}
+
+// NetOptions configures settlement netting
+type NetOptions struct {
+	// Mode is the netting mode
+	// Default is NettingMultilateral if not specified
+	Mode NettingMode
+	// Description is the description of the settlement transactions
+	// Default is "Net settlement" if not specified
+	Description string
+	// Metadata is added to every settlement transaction
+	Metadata map[string]any
+	// ChartOfAccountsGroupName specifies the chart of accounts group to use
+	ChartOfAccountsGroupName string
+	// IdempotencyKeyPrefix prefixes the settlement idempotency keys, which are
+	// derived from the obligations so resubmitting the same set is safe
+	// Default is "netting" if not specified
+	IdempotencyKeyPrefix string
+	// Batch configures the submission of the settlements (optional, pass nil for defaults)
+	Batch *BatchOptions
+}
+
+// DefaultNetOptions returns the default netting options
+func DefaultNetOptions() *NetOptions {
+	return &NetOptions{
+		Mode:                 NettingMultilateral,
+		Description:          "Net settlement",
+		IdempotencyKeyPrefix: "netting",
+	}
+}
+
+// NetResult reports the outcome of a netting run
+type NetResult struct {
+	// Settlements are the transfers that replace the obligations
+	Settlements []Settlement
+	// Results holds the batch result of each settlement, in the same order
+	Results []BatchResult
+	// Obligations is the number of obligations netted
+	Obligations int
+	// GrossVolume is the sum of the obligations per asset
+	GrossVolume map[string]decimal.Decimal
+	// NetVolume is the sum of the settlements per asset
+	NetVolume map[string]decimal.Decimal
+}
+
+// Reduction returns the share of the gross volume of an asset removed by
+// netting, between 0 and 1
+func (r *NetResult) Reduction(assetCode string) float64 {
+	gross := r.GrossVolume[assetCode]
+	if !gross.IsPositive() {
+		return 0
+	}
+
+	return decimal.NewFromInt(1).Sub(r.NetVolume[assetCode].Div(gross)).InexactFloat64()
+}
+
+// NetObligations offsets obligations into settlements without submitting them.
+// Settlements are sorted by asset, debtor and creditor so the same obligations
+// always produce the same settlements.
+func NetObligations(obligations []Obligation, mode NettingMode) ([]Settlement, error) {
+	if mode == "" {
+		mode = NettingMultilateral
+	}
+
+	if mode != NettingMultilateral && mode != NettingBilateral {
+		return nil, fmt.Errorf("unknown netting mode %q", mode)
+	}
+
+	for i, o := range obligations {
+		if err := validateObligation(o); err != nil {
+			return nil, fmt.Errorf("obligation %d: %w", i, err)
+		}
+	}
+
+	var settlements []Settlement
+	if mode == NettingBilateral {
+		settlements = netBilateral(obligations)
+	} else {
+		settlements = netMultilateral(obligations)
+	}
+
+	sort.Slice(settlements, func(i, j int) bool {
+		a, b := settlements[i], settlements[j]
+		if a.AssetCode != b.AssetCode {
+			return a.AssetCode < b.AssetCode
+		}
+
+		if a.From != b.From {
+			return a.From < b.From
+		}
+
+		return a.To < b.To
+	})
+
+	return settlements, nil
+}
+
+// Net nets obligations into settlements and submits them as a batch
+//
+// Parameters:
+//   - ctx: Context for the request, which can be used for cancellation and timeout
+//   - midazClient: The Midaz SDK client
+//   - orgID: The organization ID
+//   - ledgerID: The ledger ID
+//   - obligations: The obligations to settle
+//   - opts: Options to configure netting (optional, pass nil for defaults)
+//
+// Returns:
+//   - The settlements with their batch results and the gross and net volumes
+//   - An error if the obligations are invalid or the batch couldn't be submitted,
+//     or a *BatchError if some settlements failed
+//
+// Settlement idempotency keys are derived from the obligations, so submitting
+// the same obligations again does not settle them twice.
+func Net(
+	ctx context.Context,
+	midazClient *client.Client,
+	orgID, ledgerID string,
+	obligations []Obligation,
+	opts *NetOptions,
+) (*NetResult, error) {
+	opts = normalizeNetOptions(opts)
+
+	settlements, err := NetObligations(obligations, opts.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NetResult{
+		Settlements: settlements,
+		Obligations: len(obligations),
+		GrossVolume: make(map[string]decimal.Decimal),
+		NetVolume:   make(map[string]decimal.Decimal),
+	}
+
+	for _, o := range obligations {
+		result.GrossVolume[o.AssetCode] = result.GrossVolume[o.AssetCode].Add(o.Amount)
+	}
+
+	for _, s := range settlements {
+		result.NetVolume[s.AssetCode] = result.NetVolume[s.AssetCode].Add(s.Amount)
+	}
+
+	if len(settlements) == 0 {
+		return result, nil
+	}
+
+	if midazClient == nil || midazClient.Entity == nil {
+		return result, errors.New("midaz client is required to submit settlements")
+	}
+
+	keyPrefix := fmt.Sprintf("%s-%s", opts.IdempotencyKeyPrefix, obligationsDigest(obligations, opts.Mode))
+	inputs := make([]*models.CreateTransactionInput, len(settlements))
+
+	for i, s := range settlements {
+		inputs[i] = settlementInput(s, opts, fmt.Sprintf("%s-%d", keyPrefix, i))
+	}
+
+	result.Results, err = BatchTransactions(ctx, midazClient, orgID, ledgerID, inputs, opts.Batch)
+
+	return result, err
+}
+
+// normalizeNetOptions fills unset netting options with their defaults.
+func normalizeNetOptions(opts *NetOptions) *NetOptions {
+	defaults := DefaultNetOptions()
+	if opts == nil {
+		return defaults
+	}
+
+	normalized := *opts
+	if normalized.Mode == "" {
+		normalized.Mode = defaults.Mode
+	}
+
+	if normalized.Description == "" {
+		normalized.Description = defaults.Description
+	}
+
+	if normalized.IdempotencyKeyPrefix == "" {
+		normalized.IdempotencyKeyPrefix = defaults.IdempotencyKeyPrefix
+	}
+
+	return &normalized
+}
+
+// validateObligation checks a single obligation.
+func validateObligation(o Obligation) error {
+	switch {
+	case o.From == "" || o.To == "":
+		return errors.New("debtor and creditor are required")
+	case o.From == o.To:
+		return fmt.Errorf("debtor and creditor must differ, got %s", o.From)
+	case o.AssetCode == "":
+		return errors.New("asset code is required")
+	case !o.Amount.IsPositive():
+		return fmt.Errorf("amount must be positive, got %s", o.Amount)
+	}
+
+	return nil
+}
+
+// netBilateral offsets the obligations of each pair of parties.
+func netBilateral(obligations []Obligation) []Settlement {
+	type pair struct{ asset, a, b string }
+
+	// positive balances flow from a to b, with a < b
+	balances := make(map[pair]decimal.Decimal)
+
+	for _, o := range obligations {
+		if o.From < o.To {
+			p := pair{o.AssetCode, o.From, o.To}
+			balances[p] = balances[p].Add(o.Amount)
+		} else {
+			p := pair{o.AssetCode, o.To, o.From}
+			balances[p] = balances[p].Sub(o.Amount)
+		}
+	}
+
+	settlements := make([]Settlement, 0, len(balances))
+
+	for p, amount := range balances {
+		switch {
+		case amount.IsPositive():
+			settlements = append(settlements, Settlement{From: p.a, To: p.b, AssetCode: p.asset, Amount: amount})
+		case amount.IsNegative():
+			settlements = append(settlements, Settlement{From: p.b, To: p.a, AssetCode: p.asset, Amount: amount.Neg()})
+		}
+	}
+
+	return settlements
+}
+
+// position is the net amount a party owes (positive) or is owed (negative).
+type position struct {
+	party  string
+	amount decimal.Decimal
+}
+
+// netMultilateral computes each party's net position per asset and matches the
+// largest debtors with the largest creditors, which settles n parties with at
+// most n-1 transfers.
+func netMultilateral(obligations []Obligation) []Settlement {
+	positions := make(map[string]map[string]decimal.Decimal)
+
+	for _, o := range obligations {
+		if positions[o.AssetCode] == nil {
+			positions[o.AssetCode] = make(map[string]decimal.Decimal)
+		}
+
+		positions[o.AssetCode][o.From] = positions[o.AssetCode][o.From].Add(o.Amount)
+		positions[o.AssetCode][o.To] = positions[o.AssetCode][o.To].Sub(o.Amount)
+	}
+
+	var settlements []Settlement
+
+	for asset, parties := range positions {
+		var debtors, creditors []position
+
+		for party, amount := range parties {
+			switch {
+			case amount.IsPositive():
+				debtors = append(debtors, position{party, amount})
+			case amount.IsNegative():
+				creditors = append(creditors, position{party, amount.Neg()})
+			}
+		}
+
+		sortPositions(debtors)
+		sortPositions(creditors)
+
+		for i, j := 0, 0; i < len(debtors) && j < len(creditors); {
+			amount := decimal.Min(debtors[i].amount, creditors[j].amount)
+			settlements = append(settlements, Settlement{From: debtors[i].party, To: creditors[j].party, AssetCode: asset, Amount: amount})
+
+			debtors[i].amount = debtors[i].amount.Sub(amount)
+			creditors[j].amount = creditors[j].amount.Sub(amount)
+
+			if debtors[i].amount.IsZero() {
+				i++
+			}
+
+			if creditors[j].amount.IsZero() {
+				j++
+			}
+		}
+	}
+
+	return settlements
+}
+
+// sortPositions orders positions by decreasing amount, then by party.
+func sortPositions(positions []position) {
+	sort.Slice(positions, func(i, j int) bool {
+		if c := positions[i].amount.Cmp(positions[j].amount); c != 0 {
+			return c > 0
+		}
+
+		return positions[i].party < positions[j].party
+	})
+}
+
+// obligationsDigest identifies a set of obligations regardless of their order.
+func obligationsDigest(obligations []Obligation, mode NettingMode) string {
+	lines := make([]string, len(obligations))
+	for i, o := range obligations {
+		lines[i] = strings.Join([]string{o.AssetCode, o.From, o.To, o.Amount.String()}, "|")
+	}
+
+	sort.Strings(lines)
+
+	sum := sha256.Sum256([]byte(string(mode) + "\n" + strings.Join(lines, "\n")))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// settlementInput builds the transaction of a settlement.
+func settlementInput(s Settlement, opts *NetOptions, idempotencyKey string) *models.CreateTransactionInput {
+	amount := s.Amount.String()
+
+	metadata := make(map[string]any, len(opts.Metadata)+1)
+	for k, v := range opts.Metadata {
+		metadata[k] = v
+	}
+
+	metadata["nettingMode"] = string(opts.Mode)
+
+	return &models.CreateTransactionInput{
+		Description:              opts.Description,
+		Amount:                   amount,
+		AssetCode:                s.AssetCode,
+		Metadata:                 metadata,
+		IdempotencyKey:           idempotencyKey,
+		ChartOfAccountsGroupName: opts.ChartOfAccountsGroupName,
+		Send: &models.SendInput{
+			Asset: s.AssetCode,
+			Value: amount,
+			Source: &models.SourceInput{
+				From: []models.FromToInput{{Account: s.From, Amount: models.AmountInput{Asset: s.AssetCode, Value: amount}}},
+			},
+			Distribute: &models.DistributeInput{
+				To: []models.FromToInput{{Account: s.To, Amount: models.AmountInput{Asset: s.AssetCode, Value: amount}}},
+			},
+		},
+	}
+}
@@ -0,0 +1,164 @@
+package transaction
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingTransactions struct {
+	entities.TransactionsService
+
+	mu     sync.Mutex
+	inputs []*models.CreateTransactionInput
+}
+
+func (r *recordingTransactions) CreateTransaction(_ context.Context, _, _ string, input *models.CreateTransactionInput) (*models.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.inputs = append(r.inputs, input)
+
+	return &models.Transaction{ID: input.IdempotencyKey}, nil
+}
+
+func obligation(from, to, asset, amount string) Obligation {
+	return Obligation{From: from, To: to, AssetCode: asset, Amount: decimal.RequireFromString(amount)}
+}
+
+func TestNetObligationsMultilateral(t *testing.T) {
+	// A owes B 100, B owes C 100, C owes A 50: A pays C 50
+	settlements, err := NetObligations([]Obligation{
+		obligation("@a", "@b", "USD", "100"),
+		obligation("@b", "@c", "USD", "100"),
+		obligation("@c", "@a", "USD", "50"),
+		obligation("@a", "@b", "BRL", "10"),
+		obligation("@b", "@a", "BRL", "10"),
+	}, NettingMultilateral)
+	require.NoError(t, err)
+
+	require.Len(t, settlements, 1)
+	assert.Equal(t, "@a", settlements[0].From)
+	assert.Equal(t, "@c", settlements[0].To)
+	assert.Equal(t, "50", settlements[0].Amount.String())
+}
+
+func TestNetObligationsBilateral(t *testing.T) {
+	settlements, err := NetObligations([]Obligation{
+		obligation("@a", "@b", "USD", "100"),
+		obligation("@b", "@a", "USD", "30.5"),
+		obligation("@b", "@c", "USD", "100"),
+		obligation("@c", "@a", "USD", "50"),
+	}, NettingBilateral)
+	require.NoError(t, err)
+
+	require.Len(t, settlements, 3)
+	assert.Equal(t, "@a", settlements[0].From)
+	assert.Equal(t, "@b", settlements[0].To)
+	assert.Equal(t, "69.5", settlements[0].Amount.String())
+	assert.Equal(t, "@b", settlements[1].From)
+	assert.Equal(t, "@c", settlements[2].From)
+}
+
+func TestNetObligationsBalancesPositions(t *testing.T) {
+	obligations := []Obligation{
+		obligation("@a", "@d", "USD", "70"),
+		obligation("@b", "@d", "USD", "20"),
+		obligation("@b", "@e", "USD", "35"),
+		obligation("@c", "@a", "USD", "15"),
+		obligation("@e", "@c", "USD", "5"),
+	}
+
+	settlements, err := NetObligations(obligations, "")
+	require.NoError(t, err)
+
+	net := map[string]decimal.Decimal{}
+	for _, o := range obligations {
+		net[o.From] = net[o.From].Add(o.Amount)
+		net[o.To] = net[o.To].Sub(o.Amount)
+	}
+
+	for _, s := range settlements {
+		net[s.From] = net[s.From].Sub(s.Amount)
+		net[s.To] = net[s.To].Add(s.Amount)
+	}
+
+	for party, amount := range net {
+		assert.True(t, amount.IsZero(), "party %s is left with %s", party, amount)
+	}
+
+	assert.LessOrEqual(t, len(settlements), 4, "five parties settle with at most four transfers")
+}
+
+func TestNetObligationsValidation(t *testing.T) {
+	_, err := NetObligations([]Obligation{obligation("@a", "@a", "USD", "1")}, NettingMultilateral)
+	assert.ErrorContains(t, err, "must differ")
+
+	_, err = NetObligations([]Obligation{obligation("@a", "@b", "USD", "0")}, NettingMultilateral)
+	assert.ErrorContains(t, err, "positive")
+
+	_, err = NetObligations([]Obligation{obligation("@a", "@b", "", "1")}, NettingMultilateral)
+	assert.ErrorContains(t, err, "asset code")
+
+	_, err = NetObligations(nil, "trilateral")
+	assert.ErrorContains(t, err, "unknown netting mode")
+}
+
+func TestNet(t *testing.T) {
+	txs := &recordingTransactions{}
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+
+	obligations := []Obligation{
+		obligation("@a", "@b", "USD", "100"),
+		obligation("@b", "@c", "USD", "100"),
+		obligation("@c", "@a", "USD", "50"),
+	}
+
+	result, err := Net(context.Background(), midazClient, "org", "ledger", obligations, &NetOptions{Metadata: map[string]any{"cycle": "d1"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.Obligations)
+	assert.Equal(t, "250", result.GrossVolume["USD"].String())
+	assert.Equal(t, "50", result.NetVolume["USD"].String())
+	assert.InDelta(t, 0.8, result.Reduction("USD"), 1e-9)
+	assert.Zero(t, result.Reduction("EUR"))
+
+	require.Len(t, result.Results, 1)
+	require.NoError(t, result.Results[0].Error)
+	require.Len(t, txs.inputs, 1)
+
+	input := txs.inputs[0]
+	assert.Equal(t, "Net settlement", input.Description)
+	assert.Equal(t, "50", input.Amount)
+	assert.Equal(t, "@a", input.Send.Source.From[0].Account)
+	assert.Equal(t, "@c", input.Send.Distribute.To[0].Account)
+	assert.Equal(t, "d1", input.Metadata["cycle"])
+	assert.Equal(t, "multilateral", input.Metadata["nettingMode"])
+	assert.Equal(t, input.IdempotencyKey, result.Results[0].TransactionID)
+
+	// the same obligations in another order yield the same keys
+	reordered := []Obligation{obligations[2], obligations[0], obligations[1]}
+	_, err = Net(context.Background(), midazClient, "org", "ledger", reordered, nil)
+	require.NoError(t, err)
+	require.Len(t, txs.inputs, 2)
+	assert.Equal(t, txs.inputs[0].IdempotencyKey, txs.inputs[1].IdempotencyKey)
+}
+
+func TestNetWithoutClient(t *testing.T) {
+	result, err := Net(context.Background(), nil, "org", "ledger", []Obligation{
+		obligation("@a", "@b", "USD", "10"),
+		obligation("@b", "@a", "USD", "10"),
+	}, nil)
+	require.NoError(t, err, "fully offset obligations need no submission")
+	assert.Empty(t, result.Settlements)
+
+	_, err = Net(context.Background(), nil, "org", "ledger", []Obligation{obligation("@a", "@b", "USD", "10")}, nil)
+	assert.Error(t, err)
+}
@@ -1,11 +1,21 @@
 package transaction
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
+	"html"
+	"io"
+	"path"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/anomaly"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/artifact"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/fsutil"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/manifest"
 )
 
 // ReportEntities contains counts and identifiers for created entities.
@@ -34,6 +44,11 @@ type ReportEntityIDs struct {
 	PortfolioIDs    []string `json:"portfolioIds,omitempty"`
 	SegmentIDs      []string `json:"segmentIds,omitempty"`
 	TransactionIDs  []string `json:"transactionIds,omitempty"`
+
+	// AccountAliases and AccountTypes map account IDs to their aliases and
+	// types, for the entity browser of the HTML report (see AddAccount)
+	AccountAliases map[string]string `json:"accountAliases,omitempty"`
+	AccountTypes   map[string]string `json:"accountTypes,omitempty"`
 }
 
 // ReportAPIStats captures minimal API usage information.
@@ -62,6 +77,15 @@ type GenerationReport struct {
 	Entities              *ReportEntities    `json:"entities,omitempty"`
 	APIStats              *ReportAPIStats    `json:"apiStats,omitempty"`
 	DataSummary           *ReportDataSummary `json:"dataSummary,omitempty"`
+	// ErrorBreakdown groups the failed transactions by category and code
+	ErrorBreakdown []ReportErrorGroup `json:"errorBreakdown,omitempty"`
+	// Manifest records what the run was made of, to reproduce and audit it
+	Manifest *manifest.Manifest `json:"manifest,omitempty"`
+	// Anomalies lists the outliers flagged in the run (see DetectAnomalies)
+	Anomalies []anomaly.Anomaly `json:"anomalies,omitempty"`
+	// VerificationFailures lists the transactions created that didn't match
+	// their input (see BatchOptions.Verify)
+	VerificationFailures []ReportVerificationFailure `json:"verificationFailures,omitempty"`
 }
 
 // NewGenerationReport creates a report from batch results.
@@ -70,6 +94,72 @@ func NewGenerationReport(results []BatchResult, notes string, additional map[str
 		GeneratedAt:           time.Now().UTC(),
 		Summary:               GetBatchSummary(results),
 		Results:               results,
+		ErrorBreakdown:        BuildErrorBreakdown(results),
+		VerificationFailures:  BuildVerificationFailures(results),
+		Notes:                 notes,
+		AdditionalInformation: additional,
+	}
+}
+
+// ReportBuilder builds a GenerationReport from batch results added one at a
+// time, such as from BatchOptions.OnProgress, for runs too large to keep
+// every result. The summary and the error breakdown cover all the results
+// added, while the report keeps at most maxResults of them in Results and in
+// VerificationFailures, the first added: its memory is bounded by
+// maxResults results plus a few examples per error group. It is safe for
+// concurrent use.
+//
+// Example:
+//
+//	builder := transaction.NewReportBuilder(1000)
+//	options.OnProgress = func(_, _ int, result transaction.BatchResult) { builder.Add(result) }
+//
+//	_, err := transaction.BatchTransactions(ctx, c, orgID, ledgerID, inputs, options)
+//	report := builder.Report("nightly import", nil)
+type ReportBuilder struct {
+	mu         sync.Mutex
+	maxResults int
+	counter    batchCounter
+	breakdown  errorBreakdown
+	results    []BatchResult
+	failures   []ReportVerificationFailure
+}
+
+// NewReportBuilder returns a ReportBuilder keeping at most maxResults
+// results; a negative value keeps them all, like NewGenerationReport.
+func NewReportBuilder(maxResults int) *ReportBuilder {
+	return &ReportBuilder{maxResults: maxResults}
+}
+
+// Add adds a batch result to the report.
+func (b *ReportBuilder) Add(result BatchResult) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.counter.add(result)
+	b.breakdown.add(result)
+
+	if b.maxResults < 0 || len(b.results) < b.maxResults {
+		b.results = append(b.results, result)
+	}
+
+	if failure, ok := verificationFailureOf(result); ok && (b.maxResults < 0 || len(b.failures) < b.maxResults) {
+		b.failures = append(b.failures, failure)
+	}
+}
+
+// Report returns the report of the results added so far. Its Results are in
+// the order the results were added.
+func (b *ReportBuilder) Report(notes string, additional map[string]any) *GenerationReport {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return &GenerationReport{
+		GeneratedAt:           time.Now().UTC(),
+		Summary:               b.counter.summary(),
+		Results:               slices.Clone(b.results),
+		ErrorBreakdown:        b.breakdown.groups(),
+		VerificationFailures:  slices.Clone(b.failures),
 		Notes:                 notes,
 		AdditionalInformation: additional,
 	}
@@ -84,14 +174,27 @@ func (r *GenerationReport) ToJSON(pretty bool) ([]byte, error) {
 	return json.Marshal(r)
 }
 
-// SaveJSON writes the report to a file in JSON format.
+// SaveJSON writes the report to a file in JSON format, replacing it atomically.
 func (r *GenerationReport) SaveJSON(path string, pretty bool) error {
 	data, err := r.ToJSON(pretty)
 	if err != nil {
 		return err
 	}
 	// Restrict permissions to owner read/write as report can include IDs.
-	return os.WriteFile(path, data, 0o600)
+	return fsutil.WriteFileAtomic(path, data)
+}
+
+// WriteJSON writes the report to w in JSON format, e.g. to stdout or to a
+// file of an fsutil.FS.
+func (r *GenerationReport) WriteJSON(w io.Writer, pretty bool) error {
+	data, err := r.ToJSON(pretty)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+
+	return err
 }
 
 // writeHTMLHeader writes the HTML header and styles to the builder.
@@ -140,6 +243,12 @@ func (r *GenerationReport) writeHTMLSummarySection(b *strings.Builder) {
 	_, _ = fmt.Fprintf(b, "<tr><th>Success</th><td>%d</td></tr>", r.Summary.SuccessCount)
 	_, _ = fmt.Fprintf(b, "<tr><th>Errors</th><td>%d</td></tr>", r.Summary.ErrorCount)
 	_, _ = fmt.Fprintf(b, "<tr><th>Success Rate</th><td>%.1f%%</td></tr>", r.Summary.SuccessRate)
+
+	if r.Summary.VerifiedCount > 0 || r.Summary.VerificationFailureCount > 0 {
+		_, _ = fmt.Fprintf(b, "<tr><th>Verified</th><td>%d</td></tr>", r.Summary.VerifiedCount)
+		_, _ = fmt.Fprintf(b, "<tr><th>Verification Failures</th><td>%d</td></tr>", r.Summary.VerificationFailureCount)
+	}
+
 	_, _ = fmt.Fprintf(b, "<tr><th>TPS</th><td>%.2f</td></tr>", r.Summary.TransactionsPerSecond)
 	_, _ = fmt.Fprintf(b, "</tbody></table></div>")
 }
@@ -167,7 +276,7 @@ func (r *GenerationReport) writeHTMLEntitiesSection(b *strings.Builder) {
 		len(ids.AccountIDs) + len(ids.PortfolioIDs) + len(ids.SegmentIDs) + len(ids.TransactionIDs)
 
 	if totalIDs > 0 {
-		_, _ = fmt.Fprintf(b, "<p class=\"muted\">IDs captured (truncated for brevity).</p>")
+		_, _ = fmt.Fprintf(b, "<p class=\"muted\">IDs captured: search them in the entity browser below.</p>")
 	}
 
 	_, _ = fmt.Fprintf(b, "</div>")
@@ -200,19 +309,104 @@ func (r *GenerationReport) writeHTMLDataSummarySection(b *strings.Builder) {
 	writeHTMLMapSection(b, "Asset Usage", r.DataSummary.AssetUsage)
 }
 
-// SaveHTML writes a minimal HTML report for quick viewing.
-// This is intentionally dependency-free (no templates) to avoid adding heavy deps.
+// writeHTMLManifestSection writes the run manifest section.
+func (r *GenerationReport) writeHTMLManifestSection(b *strings.Builder) {
+	m := r.Manifest
+	if m == nil {
+		return
+	}
+
+	_, _ = fmt.Fprintf(b, "<div class=\"section\"><h2>Run Manifest</h2><table><tbody>")
+	_, _ = fmt.Fprintf(b, "<tr><th>Run ID</th><td><code>%s</code></td></tr>", html.EscapeString(m.RunID))
+	_, _ = fmt.Fprintf(b, "<tr><th>Fingerprint</th><td><code>%s</code></td></tr>", html.EscapeString(m.Fingerprint))
+	_, _ = fmt.Fprintf(b, "<tr><th>SDK</th><td>%s %s (%s)</td></tr>", html.EscapeString(m.SDK.Name), html.EscapeString(m.SDK.Version), html.EscapeString(m.SDK.GoVersion))
+
+	for _, service := range sortedKeys(m.Backend) {
+		_, _ = fmt.Fprintf(b, "<tr><th>Backend %s</th><td>%s</td></tr>", html.EscapeString(service), html.EscapeString(m.Backend[service]))
+	}
+
+	for _, name := range sortedKeys(m.Seeds) {
+		_, _ = fmt.Fprintf(b, "<tr><th>Seed %s</th><td>%d</td></tr>", html.EscapeString(name), m.Seeds[name])
+	}
+
+	for _, s := range m.Scenarios {
+		_, _ = fmt.Fprintf(b, "<tr><th>Scenario %s</th><td><code>%s</code></td></tr>", html.EscapeString(s.Path), html.EscapeString(s.GitSHA))
+	}
+
+	_, _ = fmt.Fprintf(b, "</tbody></table></div>")
+}
+
+// SaveHTML writes a minimal HTML report for quick viewing, replacing the file
+// atomically.
 func (r *GenerationReport) SaveHTML(path string) error {
+	return fsutil.WriteFileAtomic(path, r.ToHTML())
+}
+
+// WriteHTML writes the HTML report to w.
+func (r *GenerationReport) WriteHTML(w io.Writer) error {
+	_, err := w.Write(r.ToHTML())
+	return err
+}
+
+// ToHTML returns a minimal HTML rendering of the report.
+// This is intentionally dependency-free (no templates) to avoid adding heavy deps.
+func (r *GenerationReport) ToHTML() []byte {
 	b := &strings.Builder{}
 
 	writeHTMLHeader(b, r.GeneratedAt)
 	r.writeHTMLSummarySection(b)
+	r.writeHTMLTimelineSection(b)
+	r.writeHTMLErrorBreakdownSection(b)
+	r.writeHTMLVerificationSection(b)
+	r.writeHTMLAnomaliesSection(b)
 	writeHTMLStringMapSection(b, "Step Durations", r.StepTimings)
 	r.writeHTMLEntitiesSection(b)
+	r.writeHTMLEntityBrowserSection(b)
 	r.writeHTMLAPIStatsSection(b)
 	r.writeHTMLDataSummarySection(b)
+	r.writeHTMLManifestSection(b)
 
 	_, _ = fmt.Fprintf(b, "</body></html>")
 
-	return os.WriteFile(path, []byte(b.String()), 0o600)
+	return []byte(b.String())
+}
+
+// SaveTo saves the report to a file://, s3:// or gs:// destination (see the
+// artifact package). The format follows the extension of the destination:
+// ".html" for HTML, ".prom" or ".txt" for OpenMetrics, ".hgrm" for the HDR
+// latency distribution and JSON otherwise. A
+// destination ending with "/" gets a timestamped "generation-report-*.json" name
+// unless opts sets one.
+func (r *GenerationReport) SaveTo(ctx context.Context, uri string, opts *artifact.Options) (*artifact.Result, error) {
+	o := artifact.Options{}
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Name == "" {
+		o.Name = "generation-report-" + r.GeneratedAt.UTC().Format("20060102T150405Z") + ".json"
+	}
+
+	name := uri
+	if strings.HasSuffix(uri, "/") {
+		name = o.Name
+	}
+
+	var data []byte
+
+	switch strings.ToLower(path.Ext(name)) {
+	case ".html":
+		data = r.ToHTML()
+	case ".prom", ".txt":
+		data = r.ToOpenMetrics(nil)
+	case ".hgrm":
+		data = r.ToHGRM()
+	default:
+		var err error
+		if data, err = r.ToJSON(true); err != nil {
+			return nil, err
+		}
+	}
+
+	return artifact.Save(ctx, uri, data, &o)
 }
@@ -1,13 +1,17 @@
 package transaction
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/manifest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -184,6 +188,44 @@ func TestNewGenerationReport(t *testing.T) {
 	})
 }
 
+// TestReportBuilder tests that a ReportBuilder matches NewGenerationReport while bounding the results kept
+func TestReportBuilder(t *testing.T) {
+	results := []BatchResult{
+		{Index: 0, TransactionID: "tx-1", Duration: 100 * time.Millisecond, Verified: true},
+		{Index: 1, Error: errors.New("error"), Duration: 50 * time.Millisecond},
+		{Index: 2, TransactionID: "tx-3", Duration: 80 * time.Millisecond, VerifyError: errors.New("not found")},
+		{Index: 3, Error: errors.New("error"), Duration: 20 * time.Millisecond},
+		{Index: 4, TransactionID: "tx-5", Duration: 10 * time.Millisecond, VerifyError: errors.New("not found")},
+	}
+
+	builder := NewReportBuilder(2)
+
+	var wg sync.WaitGroup
+	for _, result := range results {
+		wg.Go(func() { builder.Add(result) })
+	}
+
+	wg.Wait()
+
+	report := builder.Report("notes", map[string]any{"extra": "data"})
+	full := NewGenerationReport(results, "notes", nil)
+
+	assert.Equal(t, full.Summary, report.Summary)
+	assert.Len(t, report.ErrorBreakdown, 1)
+	assert.Equal(t, full.ErrorBreakdown[0].Count, report.ErrorBreakdown[0].Count)
+	assert.Len(t, report.Results, 2, "the results kept are bounded")
+	assert.Len(t, report.VerificationFailures, 2)
+	assert.Equal(t, "data", report.AdditionalInformation["extra"])
+
+	all := NewReportBuilder(-1)
+	for _, result := range results {
+		all.Add(result)
+	}
+
+	assert.Equal(t, results, all.Report("", nil).Results)
+	assert.Empty(t, NewReportBuilder(0).Report("", nil).Results)
+}
+
 // TestGenerationReportToJSON tests the ToJSON method
 func TestGenerationReportToJSON(t *testing.T) {
 	t.Run("compact JSON", func(t *testing.T) {
@@ -760,3 +802,48 @@ func TestGenerationReportGeneratedAtUTC(t *testing.T) {
 	_, offset := report.GeneratedAt.Zone()
 	assert.Equal(t, 0, offset)
 }
+
+// TestGenerationReportSaveTo tests saving the report to a destination URI
+func TestGenerationReportSaveTo(t *testing.T) {
+	report := NewGenerationReport([]BatchResult{{Index: 0, TransactionID: "tx-1"}}, "nightly", nil)
+	dir := filepath.ToSlash(t.TempDir())
+
+	result, err := report.SaveTo(context.Background(), "file://"+dir+"/", nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(result.URI, ".json"))
+	assert.Contains(t, result.URI, "generation-report-")
+
+	result, err = report.SaveTo(context.Background(), "file://"+dir+"/report.html", nil)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "report.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "<html>")
+	assert.Equal(t, int64(len(data)), result.Size)
+
+	_, err = report.SaveTo(context.Background(), "file://"+dir+"/metrics.prom", nil)
+	require.NoError(t, err)
+
+	data, err = os.ReadFile(filepath.Join(dir, "metrics.prom"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# EOF")
+}
+
+func TestGenerationReportManifest(t *testing.T) {
+	report := NewGenerationReport(nil, "nightly", nil)
+	report.Manifest = manifest.New("nightly").
+		WithSeed("generation", 42).
+		WithBackendVersion("transaction", "3.4.0").
+		WithConfig("demo", map[string]any{"password": "hunter2"}).
+		Seal()
+
+	data, err := report.ToJSON(false)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"fingerprint":"`+report.Manifest.Fingerprint+`"`)
+	assert.NotContains(t, string(data), "hunter2")
+
+	html := string(report.ToHTML())
+	assert.Contains(t, html, "Run Manifest")
+	assert.Contains(t, html, "<tr><th>Seed generation</th><td>42</td></tr>")
+	assert.Contains(t, html, "<tr><th>Backend transaction</th><td>3.4.0</td></tr>")
+}
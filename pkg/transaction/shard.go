@@ -0,0 +1,207 @@
+package transaction
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
+)
+
+// ShardOptions configures the sharded submission of transactions
+type ShardOptions struct {
+	// Shards is the number of shards, each served by a single worker
+	// Default is 16 if not specified
+	Shards int
+	// Limits paces each shard independently, under the key ShardKey(shard),
+	// so per-shard overrides and a ceiling shared by all shards can be set on
+	// the group; it is not stopped by the submission
+	// Default is nil (no rate limit)
+	Limits *concurrent.RateLimiterGroup
+	// Batch configures retries, idempotency keys, StopOnError, aggregation and
+	// events; Concurrency and BatchSize are ignored, as each shard has one worker
+	// Default is DefaultBatchOptions()
+	Batch *BatchOptions
+}
+
+// DefaultShardOptions returns the default sharding options
+func DefaultShardOptions() *ShardOptions {
+	return &ShardOptions{
+		Shards: 16,
+		Batch:  DefaultBatchOptions(),
+	}
+}
+
+// ShardOf returns the shard of a transaction among shards, from a hash of
+// the account it is scheduled by: its first source account other than an
+// @external one, or else its first destination.
+func ShardOf(input *models.CreateTransactionInput, shards int) int {
+	if shards <= 1 {
+		return 0
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(accountKey(input)))
+
+	return int(h.Sum32() % uint32(shards))
+}
+
+// ShardKey returns the key of a shard in ShardOptions.Limits, e.g. "shard-3".
+func ShardKey(shard int) string {
+	return "shard-" + strconv.Itoa(shard)
+}
+
+// BatchTransactionsSharded submits transactions through shards matching the
+// partitioning of the backend by account: transactions are hashed by source
+// account into ShardOptions.Shards shards, each served by a single worker
+// with its own rate limit. The transactions of an account are therefore sent
+// one at a time, in input order, and a hot account only slows down its own
+// shard instead of contending with the others across partitions.
+//
+// Parameters:
+//   - ctx: Context for the request, which can be used for cancellation and timeout
+//   - midazClient: The Midaz SDK client
+//   - orgID: The organization ID
+//   - ledgerID: The ledger ID
+//   - inputs: The transaction inputs to process
+//   - opts: Options to configure sharding (optional, pass nil for defaults)
+//
+// Returns:
+//   - A slice of BatchResult containing the result of each transaction, in input order
+//   - A *BatchError if any transaction failed or was not started; transactions
+//     of a shard whose rate limit wait failed, e.g. on cancellation, are not started
+//
+// OnProgress receives the number of transactions completed across shards.
+// With StopOnError, a failure stops every shard after its transaction in flight.
+// Cancelling ctx stops every shard as in BatchTransactions.
+func BatchTransactionsSharded(
+	ctx context.Context,
+	midazClient *client.Client,
+	orgID, ledgerID string,
+	inputs []*models.CreateTransactionInput,
+	opts *ShardOptions,
+) ([]BatchResult, error) {
+	opts = normalizeShardOptions(opts)
+	options := opts.Batch
+
+	if options.Dedup != nil {
+		submitOptions := *options
+		submitOptions.Dedup = nil
+
+		shardOptions := *opts
+		shardOptions.Batch = &submitOptions
+
+		return submitDeduplicated(ctx, orgID, ledgerID, inputs, options.Dedup, func(remaining []*models.CreateTransactionInput) ([]BatchResult, error) {
+			return BatchTransactionsSharded(ctx, midazClient, orgID, ledgerID, remaining, &shardOptions)
+		})
+	}
+
+	if options.Aggregate != nil {
+		submitOptions := *options
+		submitOptions.Aggregate = nil
+
+		shardOptions := *opts
+		shardOptions.Batch = &submitOptions
+
+		return submitAggregated(inputs, options.Aggregate, func(aggregated []*models.CreateTransactionInput) ([]BatchResult, error) {
+			return BatchTransactionsSharded(ctx, midazClient, orgID, ledgerID, aggregated, &shardOptions)
+		})
+	}
+
+	results := make([]BatchResult, len(inputs))
+	startedAt := time.Now()
+
+	var completed atomic.Int64
+
+	shardBatchOptions := *options
+	if options.OnProgress != nil {
+		shardBatchOptions.OnProgress = func(_, _ int, result BatchResult) {
+			options.OnProgress(int(completed.Add(1)), len(inputs), result)
+		}
+	}
+
+	processor := &batchProcessor{
+		ctx:      ctx,
+		client:   midazClient,
+		orgID:    orgID,
+		ledgerID: ledgerID,
+		inputs:   inputs,
+		options:  &shardBatchOptions,
+		results:  results,
+	}
+
+	shards := make([][]int, opts.Shards)
+	for i, input := range inputs {
+		shard := ShardOf(input, opts.Shards)
+		shards[shard] = append(shards[shard], i)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		stopped  atomic.Bool
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for shard, queue := range shards {
+		if len(queue) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for _, index := range queue {
+				if stopped.Load() || ctx.Err() != nil {
+					return
+				}
+
+				if opts.Limits != nil {
+					if err := opts.Limits.Wait(ctx, ShardKey(shard)); err != nil {
+						return
+					}
+				}
+
+				if err := processor.processTransaction(index); err != nil && options.StopOnError {
+					errOnce.Do(func() { firstErr = err })
+					stopped.Store(true)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	processor.flushProgress()
+	processor.markCancelled()
+	emitBatchFinished(ctx, options, ledgerID, results, time.Since(startedAt))
+
+	if batchErr := newBatchError(results); batchErr != nil {
+		return results, batchErr
+	}
+
+	return results, firstErr
+}
+
+// normalizeShardOptions fills unset sharding options with their defaults.
+func normalizeShardOptions(opts *ShardOptions) *ShardOptions {
+	defaults := DefaultShardOptions()
+	if opts == nil {
+		return defaults
+	}
+
+	normalized := *opts
+	if normalized.Shards < 1 {
+		normalized.Shards = defaults.Shards
+	}
+
+	normalized.Batch = normalizeOptions(normalized.Batch)
+
+	return &normalized
+}
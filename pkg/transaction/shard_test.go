@@ -0,0 +1,100 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/concurrent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardOf(t *testing.T) {
+	a := transferInput("@a", "@b", "USD", "1", "")
+	external := transferInput("@external/USD", "@a", "USD", "1", "")
+
+	assert.Equal(t, ShardOf(a, 8), ShardOf(transferInput("@a", "@c", "BRL", "2", ""), 8))
+	assert.Equal(t, ShardOf(a, 8), ShardOf(external, 8), "@external sources are sharded by destination")
+	assert.Zero(t, ShardOf(a, 1))
+	assert.Equal(t, "shard-3", ShardKey(3))
+
+	seen := map[int]bool{}
+	for i := range 64 {
+		seen[ShardOf(transferInput(fmt.Sprintf("@acc-%d", i), "@b", "USD", "1", ""), 4)] = true
+	}
+
+	assert.Len(t, seen, 4, "accounts spread over every shard")
+}
+
+func TestBatchTransactionsSharded(t *testing.T) {
+	txs := &recordingTransactions{}
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+
+	var inputs []*models.CreateTransactionInput
+	for i := range 40 {
+		inputs = append(inputs, transferInput(fmt.Sprintf("@acc-%d", i%5), "@b", "USD", "1", fmt.Sprintf("k%02d", i)))
+	}
+
+	limits := concurrent.NewRateLimiterGroup(1000, 1000)
+	defer limits.Stop()
+
+	var (
+		mu       sync.Mutex
+		progress []int
+	)
+
+	results, err := BatchTransactionsSharded(context.Background(), midazClient, "org", "ledger", inputs, &ShardOptions{
+		Shards: 4,
+		Limits: limits,
+		Batch: &BatchOptions{OnProgress: func(completed, _ int, _ BatchResult) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			progress = append(progress, completed)
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 40)
+
+	for i, r := range results {
+		assert.Equal(t, i, r.Index)
+		assert.Equal(t, inputs[i].IdempotencyKey, r.TransactionID)
+	}
+
+	// the transactions of each account are sent in input order
+	last := map[string]string{}
+	for _, input := range txs.inputs {
+		account := input.Send.Source.From[0].Account
+		assert.Less(t, last[account], input.IdempotencyKey)
+		last[account] = input.IdempotencyKey
+	}
+
+	assert.NotEmpty(t, limits.Keys())
+	assert.Len(t, progress, 40)
+}
+
+func TestBatchTransactionsShardedStopOnError(t *testing.T) {
+	txs := &failingTransactions{errs: map[string]error{"k0": errors.New("rejected")}}
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+
+	var inputs []*models.CreateTransactionInput
+	for i := range 5 {
+		inputs = append(inputs, transferInput("@a", "@b", "USD", "1", fmt.Sprintf("k%d", i)))
+	}
+
+	results, err := BatchTransactionsSharded(context.Background(), midazClient, "org", "ledger", inputs, &ShardOptions{
+		Batch: &BatchOptions{StopOnError: true},
+	})
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 1, batchErr.ErrorCount)
+	assert.Equal(t, 4, batchErr.SkippedCount, "later transactions of the account are not sent")
+	assert.Error(t, results[0].Error)
+}
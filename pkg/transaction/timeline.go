@@ -0,0 +1,223 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxTimelineBuckets bounds the number of buckets of a timeline.
+const maxTimelineBuckets = 1000
+
+// timelineBucketSizes are the bucket sizes picked automatically, smallest first.
+var timelineBucketSizes = []time.Duration{time.Second, 10 * time.Second, time.Minute, 10 * time.Minute, time.Hour}
+
+// TimelineBucket aggregates the transactions of a run that completed within a
+// time bucket.
+type TimelineBucket struct {
+	Start        time.Time `json:"start"`
+	Transactions int       `json:"transactions"`
+	Errors       int       `json:"errors"`
+	// TPS is the number of successful transactions per second
+	TPS float64 `json:"tps"`
+	// ErrorRate is the percentage of failed transactions
+	ErrorRate float64       `json:"errorRate"`
+	P50       time.Duration `json:"p50"`
+	P90       time.Duration `json:"p90"`
+	P99       time.Duration `json:"p99"`
+}
+
+// BuildTimeline groups results by completion time into buckets of the given
+// size, so that ramp-up, degradation and error bursts hidden by the aggregate
+// summary show up. A bucket of zero picks the smallest of 1s, 10s, 1m, 10m and
+// 1h giving at most 600 buckets; larger buckets are used when needed to stay
+// within 1000 buckets. Buckets without transactions are kept, and results
+// without a StartedAt time are skipped.
+//
+// Example:
+//
+//	for _, b := range transaction.BuildTimeline(results, time.Second) {
+//	    fmt.Printf("%s tps=%.1f p99=%s errors=%.1f%%\n", b.Start.Format(time.TimeOnly), b.TPS, b.P99, b.ErrorRate)
+//	}
+func BuildTimeline(results []BatchResult, bucket time.Duration) []TimelineBucket {
+	var first, last time.Time
+
+	timed := make([]BatchResult, 0, len(results))
+
+	for _, result := range results {
+		if result.StartedAt.IsZero() {
+			continue
+		}
+
+		end := result.StartedAt.Add(result.Duration)
+		if len(timed) == 0 || end.Before(first) {
+			first = end
+		}
+
+		if len(timed) == 0 || end.After(last) {
+			last = end
+		}
+
+		timed = append(timed, result)
+	}
+
+	if len(timed) == 0 {
+		return nil
+	}
+
+	span := last.Sub(first)
+	bucket = timelineBucketSize(span, bucket)
+	start := first.Truncate(bucket)
+
+	buckets := make([]TimelineBucket, int(last.Sub(start)/bucket)+1)
+	durations := make([][]time.Duration, len(buckets))
+
+	for i := range buckets {
+		buckets[i].Start = start.Add(time.Duration(i) * bucket)
+	}
+
+	for _, result := range timed {
+		i := int(result.StartedAt.Add(result.Duration).Sub(start) / bucket)
+
+		buckets[i].Transactions++
+		durations[i] = append(durations[i], result.Duration)
+
+		if result.Error != nil {
+			buckets[i].Errors++
+		}
+	}
+
+	for i := range buckets {
+		b := &buckets[i]
+		if b.Transactions == 0 {
+			continue
+		}
+
+		b.TPS = float64(b.Transactions-b.Errors) / bucket.Seconds()
+		b.ErrorRate = float64(b.Errors) / float64(b.Transactions) * 100
+
+		sort.Slice(durations[i], func(x, y int) bool { return durations[i][x] < durations[i][y] })
+		b.P50 = durationPercentile(durations[i], 0.5)
+		b.P90 = durationPercentile(durations[i], 0.9)
+		b.P99 = durationPercentile(durations[i], 0.99)
+	}
+
+	return buckets
+}
+
+// timelineBucketSize returns the bucket size for a timeline spanning span.
+func timelineBucketSize(span, bucket time.Duration) time.Duration {
+	if bucket <= 0 {
+		for _, size := range timelineBucketSizes {
+			bucket = size
+			if span/size < 600 {
+				break
+			}
+		}
+	}
+
+	for span/bucket >= maxTimelineBuckets {
+		bucket *= 2
+	}
+
+	return bucket
+}
+
+// durationPercentile returns the q-quantile of sorted durations.
+func durationPercentile(sorted []time.Duration, q float64) time.Duration {
+	return sorted[min(int(q*float64(len(sorted))), len(sorted)-1)]
+}
+
+// timelinePoint is a bucket as embedded in the HTML report, with durations in
+// milliseconds.
+type timelinePoint struct {
+	T         int64   `json:"t"`
+	TPS       float64 `json:"tps"`
+	ErrorRate float64 `json:"errorRate"`
+	P50       float64 `json:"p50"`
+	P90       float64 `json:"p90"`
+	P99       float64 `json:"p99"`
+}
+
+// writeHTMLTimelineSection writes charts of throughput, latency percentiles and
+// error rate over time. The buckets are embedded as JSON and drawn by a small
+// inline script, so the report stays a single dependency-free file.
+func (r *GenerationReport) writeHTMLTimelineSection(b *strings.Builder) {
+	timeline := BuildTimeline(r.Results, 0)
+	if len(timeline) < 2 {
+		// a single bucket says no more than the summary
+		return
+	}
+
+	points := make([]timelinePoint, len(timeline))
+	for i, bucket := range timeline {
+		points[i] = timelinePoint{
+			T:         bucket.Start.UnixMilli(),
+			TPS:       bucket.TPS,
+			ErrorRate: bucket.ErrorRate,
+			P50:       float64(bucket.P50.Microseconds()) / 1000,
+			P90:       float64(bucket.P90.Microseconds()) / 1000,
+			P99:       float64(bucket.P99.Microseconds()) / 1000,
+		}
+	}
+
+	// json.Marshal escapes <, > and &, so the payload can't close the script element
+	payload, err := json.Marshal(points)
+	if err != nil {
+		return
+	}
+
+	bucket := timeline[1].Start.Sub(timeline[0].Start)
+
+	_, _ = fmt.Fprintf(b, "<div class=\"section\"><h2>Over Time</h2>")
+	_, _ = fmt.Fprintf(b, "<p class=\"muted\">Transactions grouped by completion time in %s buckets. Hover a chart for values.</p>", bucket)
+	_, _ = fmt.Fprintf(b, "<h3>Throughput (successful TPS)</h3><div class=\"chart\" id=\"timeline-tps\"></div>")
+	_, _ = fmt.Fprintf(b, "<h3>Latency percentiles (ms)</h3><div class=\"chart\" id=\"timeline-latency\"></div>")
+	_, _ = fmt.Fprintf(b, "<h3>Error rate (%%)</h3><div class=\"chart\" id=\"timeline-errors\"></div>")
+	_, _ = fmt.Fprintf(b, "<script type=\"application/json\" id=\"timeline-data\">%s</script>", payload)
+	_, _ = fmt.Fprintf(b, "<script>%s</script></div>", timelineScript)
+}
+
+// timelineScript draws the charts of the timeline section as SVG line charts
+// with a hover tooltip.
+const timelineScript = `(function(){
+var points=JSON.parse(document.getElementById('timeline-data').textContent);
+var charts=[
+{id:'timeline-tps',unit:' tps',series:[{key:'tps',label:'TPS',color:'#2b7bb9'}]},
+{id:'timeline-latency',unit:' ms',series:[{key:'p50',label:'p50',color:'#2ca02c'},{key:'p90',label:'p90',color:'#ff7f0e'},{key:'p99',label:'p99',color:'#d62728'}]},
+{id:'timeline-errors',unit:'%',series:[{key:'errorRate',label:'errors',color:'#d62728'}]}
+];
+var W=720,H=220,P=48,NS='http://www.w3.org/2000/svg',n=points.length;
+function el(name,attrs,parent){var e=document.createElementNS(NS,name);for(var k in attrs){e.setAttribute(k,attrs[k]);}parent.appendChild(e);return e;}
+function clock(t){return new Date(t).toISOString().substr(11,8);}
+function num(v){return Math.round(v*100)/100;}
+charts.forEach(function(c){
+var box=document.getElementById(c.id);if(!box){return;}
+var max=0;c.series.forEach(function(s){points.forEach(function(p){max=Math.max(max,p[s.key]);});});
+if(max===0){max=1;}
+function x(i){return P+(W-2*P)*i/(n-1);}
+function y(v){return H-P-(H-2*P)*v/max;}
+var svg=el('svg',{width:W,height:H,viewBox:'0 0 '+W+' '+H},box);
+el('line',{x1:P,y1:H-P,x2:W-P,y2:H-P,stroke:'#999'},svg);
+el('line',{x1:P,y1:P,x2:P,y2:H-P,stroke:'#999'},svg);
+el('text',{x:P-4,y:P+4,'text-anchor':'end','font-size':10},svg).textContent=num(max)+c.unit;
+el('text',{x:P-4,y:H-P,'text-anchor':'end','font-size':10},svg).textContent='0';
+el('text',{x:P,y:H-P+14,'font-size':10},svg).textContent=clock(points[0].t);
+el('text',{x:W-P,y:H-P+14,'text-anchor':'end','font-size':10},svg).textContent=clock(points[n-1].t)+' UTC';
+c.series.forEach(function(s){
+el('polyline',{fill:'none',stroke:s.color,'stroke-width':1.5,points:points.map(function(p,i){return x(i)+','+y(p[s.key]);}).join(' ')},svg);
+});
+var cursor=el('line',{y1:P,y2:H-P,stroke:'#555','stroke-dasharray':'3,3',visibility:'hidden'},svg);
+var tip=el('text',{x:P+4,y:P-8,'font-size':11},svg);
+var area=el('rect',{x:P,y:P,width:W-2*P,height:H-2*P,fill:'transparent'},svg);
+area.addEventListener('mousemove',function(e){
+var r=svg.getBoundingClientRect(),mx=(e.clientX-r.left)*W/r.width;
+var i=Math.max(0,Math.min(n-1,Math.round((mx-P)/(W-2*P)*(n-1)))),p=points[i];
+cursor.setAttribute('x1',x(i));cursor.setAttribute('x2',x(i));cursor.setAttribute('visibility','visible');
+tip.textContent=clock(p.t)+'  '+c.series.map(function(s){return s.label+' '+num(p[s.key])+c.unit;}).join('  ');
+});
+area.addEventListener('mouseleave',function(){cursor.setAttribute('visibility','hidden');tip.textContent='';});
+});
+})();`
@@ -0,0 +1,85 @@
+package transaction
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTimeline(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(offset, duration time.Duration, err error) BatchResult {
+		return BatchResult{StartedAt: base.Add(offset), Duration: duration, Error: err}
+	}
+
+	results := []BatchResult{
+		at(0, 100*time.Millisecond, nil),
+		at(200*time.Millisecond, 300*time.Millisecond, nil),
+		at(500*time.Millisecond, 600*time.Millisecond, errors.New("boom")), // completes in the second bucket
+		at(3*time.Second, 2*time.Second, nil),                              // completes in the sixth bucket
+		{Duration: time.Second},                                            // no timestamp
+	}
+
+	timeline := BuildTimeline(results, time.Second)
+	require.Len(t, timeline, 6, "empty buckets are kept")
+
+	assert.Equal(t, base, timeline[0].Start)
+	assert.Equal(t, 2, timeline[0].Transactions)
+	assert.InDelta(t, 2.0, timeline[0].TPS, 1e-9)
+	assert.Equal(t, 300*time.Millisecond, timeline[0].P50)
+	assert.Equal(t, 300*time.Millisecond, timeline[0].P99)
+
+	assert.Equal(t, 1, timeline[1].Errors)
+	assert.InDelta(t, 100.0, timeline[1].ErrorRate, 1e-9)
+	assert.Zero(t, timeline[1].TPS)
+
+	assert.Zero(t, timeline[3].Transactions)
+	assert.Equal(t, base.Add(5*time.Second), timeline[5].Start)
+	assert.Equal(t, 2*time.Second, timeline[5].P90)
+}
+
+func TestBuildTimeline_BucketSize(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	results := []BatchResult{{StartedAt: base}, {StartedAt: base.Add(30 * time.Minute)}}
+
+	auto := BuildTimeline(results, 0)
+	require.Len(t, auto, 181, "10s buckets for half an hour")
+	assert.Equal(t, 10*time.Second, auto[1].Start.Sub(auto[0].Start))
+
+	capped := BuildTimeline(results, time.Millisecond)
+	assert.LessOrEqual(t, len(capped), maxTimelineBuckets)
+
+	assert.Nil(t, BuildTimeline([]BatchResult{{Duration: time.Second}}, 0))
+}
+
+func TestGenerationReportTimelineHTML(t *testing.T) {
+	base := time.Now().UTC()
+	results := make([]BatchResult, 0, 30)
+
+	for i := 0; i < 30; i++ {
+		results = append(results, BatchResult{StartedAt: base.Add(time.Duration(i) * 100 * time.Millisecond), Duration: 50 * time.Millisecond})
+	}
+
+	report := NewGenerationReport(results, "", nil)
+	html := string(report.ToHTML())
+
+	assert.Contains(t, html, `id="timeline-tps"`)
+	assert.Contains(t, html, `id="timeline-latency"`)
+	assert.Contains(t, html, `id="timeline-errors"`)
+
+	m := regexp.MustCompile(`<script type="application/json" id="timeline-data">(.*?)</script>`).FindStringSubmatch(html)
+	require.Len(t, m, 2)
+
+	var points []timelinePoint
+	require.NoError(t, json.Unmarshal([]byte(m[1]), &points))
+	assert.GreaterOrEqual(t, len(points), 3)
+	assert.InDelta(t, 50.0, points[0].P50, 1e-9)
+
+	single := NewGenerationReport(results[:1], "", nil)
+	assert.NotContains(t, string(single.ToHTML()), "timeline-data", "no chart for a single bucket")
+}
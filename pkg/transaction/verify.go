@@ -0,0 +1,263 @@
+package transaction
+
+import (
+	stderrors "errors"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+	"github.com/shopspring/decimal"
+)
+
+// VerificationMismatch is a field of a created transaction that differs from
+// its input.
+type VerificationMismatch struct {
+	// Field names what differs, such as "amount" or "source @alice"
+	Field    string `json:"field"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// String returns the mismatch as "field: expected x, got y".
+func (m VerificationMismatch) String() string {
+	return fmt.Sprintf("%s: expected %s, got %s", m.Field, m.Expected, m.Actual)
+}
+
+// VerificationError reports a created transaction that doesn't match its
+// input, see BatchOptions.Verify.
+type VerificationError struct {
+	TransactionID string
+	Mismatches    []VerificationMismatch
+}
+
+// Error implements the error interface.
+func (e *VerificationError) Error() string {
+	parts := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		parts[i] = m.String()
+	}
+
+	return fmt.Sprintf("transaction %s does not match its input: %s", e.TransactionID, strings.Join(parts, "; "))
+}
+
+// VerifyTransaction compares a transaction read back from the ledger with the
+// input it was created from, returning what differs: the asset, the total
+// amount, and the source and destination accounts with the amounts the input
+// gives them. Accounts are matched by alias or ID against the operations of
+// the transaction, or by alias against its Source and Destination when it
+// carries no operations. It returns nil when the transaction matches.
+//
+// Example:
+//
+//	tx, err := client.Entity.Transactions.GetTransaction(ctx, orgID, ledgerID, id)
+//	if err == nil {
+//	    for _, m := range transaction.VerifyTransaction(input, tx) {
+//	        log.Printf("transaction %s: %s", id, m)
+//	    }
+//	}
+func VerifyTransaction(input *models.CreateTransactionInput, tx *models.Transaction) []VerificationMismatch {
+	legs := input.GetTransferLegs()
+
+	var mismatches []VerificationMismatch
+
+	if legs.Asset != "" && !strings.EqualFold(legs.Asset, tx.AssetCode) {
+		mismatches = append(mismatches, VerificationMismatch{Field: "assetCode", Expected: legs.Asset, Actual: tx.AssetCode})
+	}
+
+	if expected, err := decimal.NewFromString(legs.Value); err == nil {
+		actual, err := decimal.NewFromString(tx.Amount)
+		if err != nil || !actual.Equal(expected) {
+			mismatches = append(mismatches, VerificationMismatch{Field: "amount", Expected: expected.String(), Actual: orNone(tx.Amount)})
+		}
+	}
+
+	mismatches = append(mismatches, verifyLegs("source", legs.Sources, string(models.OperationTypeDebit), tx.Source, tx.Operations)...)
+	mismatches = append(mismatches, verifyLegs("destination", legs.Destinations, string(models.OperationTypeCredit), tx.Destination, tx.Operations)...)
+
+	return mismatches
+}
+
+// verifyLegs compares the legs of one side of an input with the operations of
+// the given type, or with the aliases listed on that side of the transaction.
+func verifyLegs(side string, legs []validation.TransferLeg, opType string, aliases []string, operations []models.Operation) []VerificationMismatch {
+	expected := make(map[string]decimal.Decimal, len(legs))
+	valued := make(map[string]bool, len(legs))
+	order := make([]string, 0, len(legs))
+
+	for _, leg := range legs {
+		if leg.Account == "" {
+			continue
+		}
+
+		if _, ok := expected[leg.Account]; !ok {
+			expected[leg.Account] = decimal.Zero
+			order = append(order, leg.Account)
+		}
+
+		if value, err := decimal.NewFromString(leg.Value); err == nil {
+			expected[leg.Account] = expected[leg.Account].Add(value)
+			valued[leg.Account] = true
+		}
+	}
+
+	actual := make(map[string]decimal.Decimal)
+
+	var extra []string
+
+	found := func(account string) {
+		if _, ok := actual[account]; ok {
+			return
+		}
+
+		actual[account] = decimal.Zero
+		if _, ok := expected[account]; !ok {
+			extra = append(extra, account)
+		}
+	}
+
+	byOperations := false
+
+	for _, op := range operations {
+		if !strings.EqualFold(op.Type, opType) {
+			continue
+		}
+
+		byOperations = true
+
+		account := op.AccountAlias
+		if _, ok := expected[account]; !ok && op.AccountID != "" {
+			if _, ok := expected[op.AccountID]; ok || account == "" {
+				account = op.AccountID
+			}
+		}
+
+		found(account)
+
+		if op.Amount.Value != nil {
+			actual[account] = actual[account].Add(*op.Amount.Value)
+		}
+	}
+
+	if !byOperations {
+		if len(aliases) == 0 {
+			return nil
+		}
+
+		for _, alias := range aliases {
+			found(alias)
+		}
+	}
+
+	var mismatches []VerificationMismatch
+
+	for _, account := range order {
+		// Without operations only the aliases of the transaction are known
+		if !byOperations && !strings.HasPrefix(account, "@") {
+			continue
+		}
+
+		got, ok := actual[account]
+
+		switch {
+		case !ok:
+			mismatches = append(mismatches, VerificationMismatch{Field: side + " " + account, Expected: describeLeg(expected[account], valued[account]), Actual: "none"})
+		case byOperations && valued[account] && !got.Equal(expected[account]):
+			mismatches = append(mismatches, VerificationMismatch{Field: side + " " + account, Expected: expected[account].String(), Actual: got.String()})
+		}
+	}
+
+	for _, account := range extra {
+		mismatches = append(mismatches, VerificationMismatch{Field: side + " " + account, Expected: "none", Actual: describeLeg(actual[account], byOperations)})
+	}
+
+	return mismatches
+}
+
+// describeLeg returns the amount of a leg, or "present" when it has none.
+func describeLeg(amount decimal.Decimal, valued bool) string {
+	if valued {
+		return amount.String()
+	}
+
+	return "present"
+}
+
+// orNone returns s, or "none" when s is empty.
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+
+	return s
+}
+
+// ReportVerificationFailure is a transaction of the run that couldn't be
+// verified, see BatchOptions.Verify.
+type ReportVerificationFailure struct {
+	// Index is the position of the transaction input in the batch
+	Index         int    `json:"index"`
+	TransactionID string `json:"transactionId"`
+	Message       string `json:"message"`
+	// Mismatches lists what differs from the input, empty when the
+	// transaction couldn't be read back
+	Mismatches []VerificationMismatch `json:"mismatches,omitempty"`
+}
+
+// BuildVerificationFailures lists the results whose verification failed, in
+// input order.
+func BuildVerificationFailures(results []BatchResult) []ReportVerificationFailure {
+	var failures []ReportVerificationFailure
+
+	for _, result := range results {
+		if failure, ok := verificationFailureOf(result); ok {
+			failures = append(failures, failure)
+		}
+	}
+
+	return failures
+}
+
+// verificationFailureOf describes the verification failure of a result, and
+// reports whether its verification failed.
+func verificationFailureOf(result BatchResult) (ReportVerificationFailure, bool) {
+	if result.VerifyError == nil {
+		return ReportVerificationFailure{}, false
+	}
+
+	failure := ReportVerificationFailure{Index: result.Index, TransactionID: result.TransactionID, Message: result.VerifyError.Error()}
+
+	var verr *VerificationError
+	if stderrors.As(result.VerifyError, &verr) {
+		failure.Mismatches = verr.Mismatches
+	}
+
+	return failure, true
+}
+
+// writeHTMLVerificationSection writes the verification failures as a table.
+func (r *GenerationReport) writeHTMLVerificationSection(b *strings.Builder) {
+	if len(r.VerificationFailures) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(b, "<div class=\"section\"><h2>Verification Failures</h2><table><thead>")
+	_, _ = fmt.Fprintf(b, "<tr><th>Input</th><th>Transaction</th><th>Mismatches</th></tr></thead><tbody>")
+
+	for _, f := range r.VerificationFailures {
+		details := make([]string, 0, len(f.Mismatches))
+		for _, m := range f.Mismatches {
+			details = append(details, "<div>"+html.EscapeString(m.String())+"</div>")
+		}
+
+		if len(details) == 0 {
+			details = append(details, html.EscapeString(f.Message))
+		}
+
+		_, _ = fmt.Fprintf(b, "<tr><td>#%d</td><td><code>%s</code></td><td>%s</td></tr>",
+			f.Index, html.EscapeString(f.TransactionID), strings.Join(details, ""))
+	}
+
+	_, _ = fmt.Fprintf(b, "</tbody></table></div>")
+}
@@ -0,0 +1,166 @@
+package transaction
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"testing"
+
+	client "github.com/LerianStudio/midaz-sdk-golang/v2"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/entities"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ledgerTransactions creates transactions and reads them back as the ledger
+// recorded them, altered by tamper.
+type ledgerTransactions struct {
+	entities.TransactionsService
+
+	tamper func(tx *models.Transaction) error
+
+	mu      sync.Mutex
+	created map[string]*models.CreateTransactionInput
+}
+
+func (l *ledgerTransactions) CreateTransaction(_ context.Context, _, _ string, input *models.CreateTransactionInput) (*models.Transaction, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.created == nil {
+		l.created = make(map[string]*models.CreateTransactionInput)
+	}
+
+	l.created[input.IdempotencyKey] = input
+
+	return &models.Transaction{ID: input.IdempotencyKey}, nil
+}
+
+func (l *ledgerTransactions) GetTransaction(_ context.Context, _, _, id string) (*models.Transaction, error) {
+	l.mu.Lock()
+	input := l.created[id]
+	l.mu.Unlock()
+
+	tx := recordedTransaction(id, input)
+	if l.tamper != nil {
+		if err := l.tamper(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
+// recordedTransaction returns the transaction a ledger records for a transfer input.
+func recordedTransaction(id string, input *models.CreateTransactionInput) *models.Transaction {
+	legs := input.GetTransferLegs()
+	tx := &models.Transaction{ID: id, Amount: legs.Value + ".00", AssetCode: legs.Asset}
+
+	for _, leg := range legs.Sources {
+		value := decimal.RequireFromString(leg.Value)
+		tx.Source = append(tx.Source, leg.Account)
+		tx.Operations = append(tx.Operations, models.Operation{Type: "DEBIT", AccountID: "id-" + leg.Account, AccountAlias: leg.Account, Amount: models.Amount{Value: &value}})
+	}
+
+	for _, leg := range legs.Destinations {
+		value := decimal.RequireFromString(leg.Value)
+		tx.Destination = append(tx.Destination, leg.Account)
+		tx.Operations = append(tx.Operations, models.Operation{Type: "CREDIT", AccountID: "id-" + leg.Account, AccountAlias: leg.Account, Amount: models.Amount{Value: &value}})
+	}
+
+	return tx
+}
+
+func TestVerifyTransaction(t *testing.T) {
+	input := transferInput("@a", "@b", "USD", "50", "key-1")
+
+	assert.Empty(t, VerifyTransaction(input, recordedTransaction("tx", input)), "amounts are compared as decimals")
+
+	tx := recordedTransaction("tx", input)
+	tx.Amount = "40"
+	tx.Operations[1].AccountAlias = "@c"
+	tx.Operations[1].AccountID = "id-@c"
+
+	assert.Equal(t, []VerificationMismatch{
+		{Field: "amount", Expected: "50", Actual: "40"},
+		{Field: "destination @b", Expected: "50", Actual: "none"},
+		{Field: "destination @c", Expected: "none", Actual: "50"},
+	}, VerifyTransaction(input, tx))
+
+	t.Run("accounts by ID", func(t *testing.T) {
+		byID := transferInput("id-@a", "@b", "USD", "50", "key-2")
+
+		assert.Empty(t, VerifyTransaction(byID, recordedTransaction("tx", input)))
+	})
+
+	t.Run("without operations", func(t *testing.T) {
+		tx := recordedTransaction("tx", input)
+		tx.Operations = nil
+		tx.AssetCode = "BRL"
+		tx.Source = []string{"@z"}
+
+		assert.Equal(t, []VerificationMismatch{
+			{Field: "assetCode", Expected: "USD", Actual: "BRL"},
+			{Field: "source @a", Expected: "50", Actual: "none"},
+			{Field: "source @z", Expected: "none", Actual: "present"},
+		}, VerifyTransaction(input, tx))
+	})
+}
+
+func TestBatchTransactionsVerify(t *testing.T) {
+	txs := &ledgerTransactions{tamper: func(tx *models.Transaction) error {
+		switch tx.ID {
+		case "key-1":
+			value := decimal.NewFromInt(49)
+			tx.Operations[1].Amount.Value = &value
+		case "key-2":
+			return errors.NewNotFoundError("GetTransaction", "transaction", tx.ID, nil)
+		}
+
+		return nil
+	}}
+	midazClient := &client.Client{Entity: &entities.Entity{Transactions: txs}}
+
+	inputs := []*models.CreateTransactionInput{
+		transferInput("@a", "@b", "USD", "50", "key-0"),
+		transferInput("@a", "@b", "USD", "50", "key-1"),
+		transferInput("@a", "@b", "USD", "50", "key-2"),
+	}
+
+	options := DefaultBatchOptions()
+	options.Verify = true
+
+	results, err := BatchTransactions(context.Background(), midazClient, "org", "ledger", inputs, options)
+	require.NoError(t, err, "verification failures don't fail the batch")
+
+	assert.True(t, results[0].Verified)
+	require.NoError(t, results[0].VerifyError)
+
+	var verr *VerificationError
+	require.True(t, stderrors.As(results[1].VerifyError, &verr))
+	assert.False(t, results[1].Verified)
+	assert.Equal(t, []VerificationMismatch{{Field: "destination @b", Expected: "50", Actual: "49"}}, verr.Mismatches)
+	assert.Contains(t, verr.Error(), "transaction key-1 does not match its input")
+
+	assert.True(t, errors.IsNotFoundError(results[2].VerifyError))
+	assert.NoError(t, results[2].Error)
+
+	summary := GetBatchSummary(results)
+	assert.Equal(t, 3, summary.SuccessCount)
+	assert.Equal(t, 1, summary.VerifiedCount)
+	assert.Equal(t, 2, summary.VerificationFailureCount)
+
+	report := NewGenerationReport(results, "", nil)
+	require.Len(t, report.VerificationFailures, 2)
+	assert.Equal(t, 1, report.VerificationFailures[0].Index)
+	assert.Equal(t, "key-1", report.VerificationFailures[0].TransactionID)
+	assert.Len(t, report.VerificationFailures[0].Mismatches, 1)
+	assert.Empty(t, report.VerificationFailures[1].Mismatches)
+
+	page := string(report.ToHTML())
+	assert.Contains(t, page, "<tr><th>Verification Failures</th><td>2</td></tr>")
+	assert.Contains(t, page, "destination @b: expected 50, got 49")
+}
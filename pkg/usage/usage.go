@@ -0,0 +1,189 @@
+// Package usage tallies the Midaz API operations an application calls through
+// the SDK, so that platform teams can see the integration surface of an
+// application before a breaking change.
+//
+// A Recorder is opt-in: nothing is recorded unless one is given to the client
+// with client.WithUsageRecorder. It keeps counts in memory and never sends
+// them anywhere. Operations are recorded as the HTTP method and the path
+// template of the endpoint, with the IDs, aliases and codes of the path
+// replaced by {id}; query strings and payloads are not recorded.
+//
+//	recorder := usage.NewRecorder()
+//	c, err := client.New(client.UseEntityAPI(), client.WithUsageRecorder(recorder))
+//	...
+//	_ = recorder.WriteJSON(os.Stdout)
+package usage
+
+import (
+	"cmp"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Placeholder replaces the variable segments of an operation path.
+const Placeholder = "{id}"
+
+// collections are the path segments followed by an identifier.
+var collections = map[string]bool{
+	"organizations": true, "ledgers": true, "accounts": true, "account-types": true,
+	"assets": true, "asset-rates": true, "balances": true, "operations": true,
+	"portfolios": true, "segments": true, "transactions": true,
+	"transaction-routes": true, "operation-routes": true,
+	"alias": true, "external": true, "from": true,
+}
+
+// fixedSegments are the path segments following a collection that are not
+// identifiers.
+var fixedSegments = map[string]bool{
+	"metrics": true, "alias": true, "external": true, "from": true,
+	"balances": true, "operations": true, "history": true, "annotation": true,
+	"json": true, "dsl": true, "inflow": true, "outflow": true,
+}
+
+// Operation returns the operation of a request: its method and the path
+// template of its URL, such as "GET /organizations/{id}/ledgers/{id}/accounts".
+// The part of the path before /organizations, such as a version prefix, is
+// dropped so that the operations of every service base URL compare equal.
+func Operation(method, requestURL string) string {
+	p := requestURL
+
+	if u, err := url.Parse(requestURL); err == nil {
+		p = u.Path
+	}
+
+	if i := strings.Index(p, "/organizations"); i > 0 {
+		p = p[i:]
+	}
+
+	segments := strings.Split(strings.TrimSuffix(p, "/"), "/")
+
+	for i := 1; i < len(segments); i++ {
+		if collections[segments[i-1]] && segments[i] != "" && !fixedSegments[segments[i]] {
+			segments[i] = Placeholder
+		}
+	}
+
+	return method + " " + strings.Join(segments, "/")
+}
+
+// OperationStats is the usage of an operation.
+type OperationStats struct {
+	Operation string `json:"operation"`
+	Calls     int64  `json:"calls"`
+
+	// Failures counts the calls that returned an error
+	Failures int64 `json:"failures"`
+
+	// StatusCodes counts the calls by HTTP status; calls without a response
+	// are not counted
+	StatusCodes map[int]int64 `json:"statusCodes,omitempty"`
+
+	FirstCall time.Time `json:"firstCall"`
+	LastCall  time.Time `json:"lastCall"`
+}
+
+// Report is a snapshot of a Recorder.
+type Report struct {
+	// Since is when the Recorder was created or last reset
+	Since       time.Time `json:"since"`
+	GeneratedAt time.Time `json:"generatedAt"`
+
+	// Operations holds the recorded operations, the most called first
+	Operations []OperationStats `json:"operations"`
+}
+
+// Recorder tallies the operations called through the SDK. It is safe for
+// concurrent use.
+type Recorder struct {
+	mu         sync.Mutex
+	since      time.Time
+	operations map[string]*OperationStats
+	now        func() time.Time
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	r := &Recorder{now: time.Now}
+	r.Reset()
+
+	return r
+}
+
+// Record counts a call of the request method and URL, answered with
+// statusCode (0 when no response was received).
+func (r *Recorder) Record(method, requestURL string, statusCode int, failed bool) {
+	operation := Operation(method, requestURL)
+	now := r.now().UTC()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.operations[operation]
+	if !ok {
+		stats = &OperationStats{Operation: operation, FirstCall: now}
+		r.operations[operation] = stats
+	}
+
+	stats.Calls++
+	stats.LastCall = now
+
+	if failed {
+		stats.Failures++
+	}
+
+	if statusCode > 0 {
+		if stats.StatusCodes == nil {
+			stats.StatusCodes = map[int]int64{}
+		}
+
+		stats.StatusCodes[statusCode]++
+	}
+}
+
+// Report returns a snapshot of the recorded operations.
+func (r *Recorder) Report() Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := Report{Since: r.since, GeneratedAt: r.now().UTC(), Operations: make([]OperationStats, 0, len(r.operations))}
+
+	for _, stats := range r.operations {
+		snapshot := *stats
+		snapshot.StatusCodes = maps.Clone(stats.StatusCodes)
+
+		report.Operations = append(report.Operations, snapshot)
+	}
+
+	slices.SortFunc(report.Operations, func(a, b OperationStats) int {
+		if c := cmp.Compare(b.Calls, a.Calls); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Operation, b.Operation)
+	})
+
+	return report
+}
+
+// WriteJSON writes the Report of the Recorder to w as indented JSON.
+func (r *Recorder) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(r.Report())
+}
+
+// Reset forgets the recorded operations.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.since = r.now().UTC()
+	r.operations = map[string]*OperationStats{}
+}
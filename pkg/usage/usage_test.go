@@ -0,0 +1,108 @@
+package usage
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		method string
+		url    string
+		want   string
+	}{
+		{http.MethodGet, "https://api.example.com/v1/organizations", "GET /organizations"},
+		{http.MethodGet, "https://api.example.com/v1/organizations/org-1/", "GET /organizations/{id}"},
+		{http.MethodGet, "http://localhost:3000/v1/organizations/metrics/count", "GET /organizations/metrics/count"},
+		{http.MethodGet, "http://localhost:3002/v1/organizations/org-1/ledgers/l-1/accounts?limit=10&alias=%40ana", "GET /organizations/{id}/ledgers/{id}/accounts"},
+		{http.MethodGet, "/organizations/o/ledgers/l/accounts/alias/@ana/balances", "GET /organizations/{id}/ledgers/{id}/accounts/alias/{id}/balances"},
+		{http.MethodGet, "/organizations/o/ledgers/l/accounts/external/USD/balances", "GET /organizations/{id}/ledgers/{id}/accounts/external/{id}/balances"},
+		{http.MethodGet, "/organizations/o/ledgers/l/accounts/a/balances/history?date=2024-01-01", "GET /organizations/{id}/ledgers/{id}/accounts/{id}/balances/history"},
+		{http.MethodPost, "/organizations/o/ledgers/l/transactions/json", "POST /organizations/{id}/ledgers/{id}/transactions/json"},
+		{http.MethodPost, "/organizations/o/ledgers/l/transactions/t-1/commit", "POST /organizations/{id}/ledgers/{id}/transactions/{id}/commit"},
+		{http.MethodGet, "/organizations/o/ledgers/l/accounts/a/operations/op-1", "GET /organizations/{id}/ledgers/{id}/accounts/{id}/operations/{id}"},
+		{http.MethodGet, "/organizations/o/ledgers/l/asset-rates/from/USD", "GET /organizations/{id}/ledgers/{id}/asset-rates/from/{id}"},
+		{http.MethodGet, "/version", "GET /version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, Operation(tt.method, tt.url))
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	r.Reset()
+
+	r.Record(http.MethodGet, "/organizations/o-1/ledgers", http.StatusOK, false)
+	now = now.Add(time.Minute)
+	r.Record(http.MethodGet, "/organizations/o-2/ledgers", http.StatusNotFound, true)
+	r.Record(http.MethodPost, "/organizations", 0, true)
+
+	report := r.Report()
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), report.Since)
+	require.Len(t, report.Operations, 2)
+
+	ledgers := report.Operations[0]
+	assert.Equal(t, "GET /organizations/{id}/ledgers", ledgers.Operation)
+	assert.Equal(t, int64(2), ledgers.Calls)
+	assert.Equal(t, int64(1), ledgers.Failures)
+	assert.Equal(t, map[int]int64{http.StatusOK: 1, http.StatusNotFound: 1}, ledgers.StatusCodes)
+	assert.Equal(t, report.Since, ledgers.FirstCall)
+	assert.Equal(t, now, ledgers.LastCall)
+
+	organizations := report.Operations[1]
+	assert.Equal(t, "POST /organizations", organizations.Operation)
+	assert.Nil(t, organizations.StatusCodes, "calls without a response have no status")
+
+	report.Operations[0].StatusCodes[http.StatusOK] = 99
+	assert.Equal(t, int64(1), r.Report().Operations[0].StatusCodes[http.StatusOK], "reports are snapshots")
+
+	r.Reset()
+	assert.Empty(t, r.Report().Operations)
+}
+
+func TestRecorder_WriteJSON(t *testing.T) {
+	r := NewRecorder()
+	r.Record(http.MethodGet, "/organizations/o-1", http.StatusOK, false)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteJSON(&buf))
+	assert.NotContains(t, buf.String(), "o-1")
+
+	var report Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	require.Len(t, report.Operations, 1)
+	assert.Equal(t, "GET /organizations/{id}", report.Operations[0].Operation)
+	assert.Equal(t, int64(1), report.Operations[0].StatusCodes[http.StatusOK])
+}
+
+func TestRecorder_Concurrent(t *testing.T) {
+	r := NewRecorder()
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Go(func() {
+			for range 100 {
+				r.Record(http.MethodGet, "/organizations", http.StatusOK, false)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int64(1000), r.Report().Operations[0].Calls)
+}
@@ -0,0 +1,203 @@
+package validation
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// AddressDataset provides reference data used for strict address validation:
+// ISO 3166-2 subdivision codes and postal code formats per country.
+//
+// Implementations only need to know about the countries they cover; lookups for
+// unknown countries report known=false and the corresponding check is skipped.
+type AddressDataset interface {
+	// LookupSubdivision reports whether subdivision is a valid ISO 3166-2 subdivision
+	// of country. Both the bare code ("SP") and the full code ("BR-SP") are accepted.
+	// known is false when the dataset has no subdivision data for the country.
+	LookupSubdivision(country, subdivision string) (valid, known bool)
+
+	// MatchPostalCode reports whether postalCode matches the country's postal format.
+	// known is false when the dataset has no postal format for the country.
+	MatchPostalCode(country, postalCode string) (valid, known bool)
+}
+
+// AddressCountryData is the reference data for a single country.
+type AddressCountryData struct {
+	// PostalCode is a regular expression matching valid postal codes (optional)
+	PostalCode string `json:"postalCode,omitempty"`
+
+	// Subdivisions lists the ISO 3166-2 subdivision codes without the country prefix (optional)
+	Subdivisions []string `json:"subdivisions,omitempty"`
+}
+
+// AddressData is the serializable form of an address dataset, keyed by
+// ISO 3166-1 alpha-2 country code.
+type AddressData struct {
+	Countries map[string]AddressCountryData `json:"countries"`
+}
+
+// staticAddressDataset is an AddressDataset backed by in-memory data.
+type staticAddressDataset struct {
+	subdivisions map[string]map[string]bool
+	postalCodes  map[string]*regexp.Regexp
+}
+
+//go:embed data/address_dataset.json
+var defaultAddressDatasetJSON []byte
+
+var (
+	defaultAddressDataset     AddressDataset
+	defaultAddressDatasetErr  error
+	defaultAddressDatasetOnce sync.Once
+)
+
+// DefaultAddressDataset returns the dataset embedded in the SDK. It covers the
+// subdivisions of AR, AU, BR, CA, DE, MX and US and the postal code formats of
+// the most common onboarding countries.
+func DefaultAddressDataset() AddressDataset {
+	defaultAddressDatasetOnce.Do(func() {
+		defaultAddressDataset, defaultAddressDatasetErr = LoadAddressDataset(bytes.NewReader(defaultAddressDatasetJSON))
+	})
+
+	if defaultAddressDatasetErr != nil {
+		// The embedded data is validated by tests; fall back to an empty dataset
+		// rather than failing validation calls.
+		return &staticAddressDataset{}
+	}
+
+	return defaultAddressDataset
+}
+
+// NewAddressDataset builds an AddressDataset from data. Country and subdivision
+// codes are matched case-insensitively. It returns an error if a postal code
+// pattern does not compile.
+//
+// Example:
+//
+//	dataset, err := validation.NewAddressDataset(validation.AddressData{
+//	    Countries: map[string]validation.AddressCountryData{
+//	        "BR": {PostalCode: `^\d{5}-\d{3}$`, Subdivisions: []string{"SP", "RJ"}},
+//	    },
+//	})
+func NewAddressDataset(data AddressData) (AddressDataset, error) {
+	dataset := &staticAddressDataset{
+		subdivisions: make(map[string]map[string]bool, len(data.Countries)),
+		postalCodes:  make(map[string]*regexp.Regexp, len(data.Countries)),
+	}
+
+	for country, countryData := range data.Countries {
+		key := strings.ToUpper(strings.TrimSpace(country))
+
+		if countryData.PostalCode != "" {
+			pattern, err := regexp.Compile(countryData.PostalCode)
+			if err != nil {
+				return nil, fmt.Errorf("invalid postal code pattern for %s: %w", key, err)
+			}
+
+			dataset.postalCodes[key] = pattern
+		}
+
+		if len(countryData.Subdivisions) > 0 {
+			codes := make(map[string]bool, len(countryData.Subdivisions))
+			for _, code := range countryData.Subdivisions {
+				codes[strings.ToUpper(strings.TrimSpace(code))] = true
+			}
+
+			dataset.subdivisions[key] = codes
+		}
+	}
+
+	return dataset, nil
+}
+
+// LoadAddressDataset reads an AddressData JSON document and builds a dataset from it.
+// The document has the same shape as the embedded default:
+//
+//	{"countries": {"BR": {"postalCode": "^\\d{5}-?\\d{3}$", "subdivisions": ["SP", "RJ"]}}}
+func LoadAddressDataset(r io.Reader) (AddressDataset, error) {
+	var data AddressData
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode address dataset: %w", err)
+	}
+
+	return NewAddressDataset(data)
+}
+
+// LookupSubdivision implements AddressDataset.
+func (d *staticAddressDataset) LookupSubdivision(country, subdivision string) (bool, bool) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+
+	codes, ok := d.subdivisions[country]
+	if !ok {
+		return false, false
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(subdivision))
+	code = strings.TrimPrefix(code, country+"-")
+
+	return codes[code], true
+}
+
+// MatchPostalCode implements AddressDataset.
+func (d *staticAddressDataset) MatchPostalCode(country, postalCode string) (bool, bool) {
+	pattern, ok := d.postalCodes[strings.ToUpper(strings.TrimSpace(country))]
+	if !ok {
+		return false, false
+	}
+
+	return pattern.MatchString(strings.TrimSpace(postalCode)), true
+}
+
+// EnhancedValidateAddressWithDataset runs the checks of EnhancedValidateAddress and,
+// for countries covered by dataset, also verifies that the state is a known
+// ISO 3166-2 subdivision and that the zip code matches the country's postal format.
+// A nil dataset uses DefaultAddressDataset.
+//
+// Example:
+//
+//	errs := validation.EnhancedValidateAddressWithDataset(address, "address", nil)
+//	if errs.HasErrors() {
+//	    log.Println(errs.Error())
+//	}
+func EnhancedValidateAddressWithDataset(address *Address, fieldPrefix string, dataset AddressDataset) *FieldErrors {
+	errs := EnhancedValidateAddress(address, fieldPrefix)
+	if address == nil || address.Country == "" {
+		return errs
+	}
+
+	if dataset == nil {
+		dataset = DefaultAddressDataset()
+	}
+
+	if address.State != "" && len(errs.GetErrorsForField(fieldPrefix+".state")) == 0 {
+		if valid, known := dataset.LookupSubdivision(address.Country, address.State); known && !valid {
+			errs.Add(fmt.Sprintf("%s.state", fieldPrefix), address.State,
+				fmt.Sprintf("State is not a valid ISO 3166-2 subdivision of %s", address.Country)).
+				WithConstraint("enumeration").
+				WithSuggestions(
+					"Use the ISO 3166-2 subdivision code without the country prefix (e.g., 'SP' for São Paulo)",
+					fmt.Sprintf("The full code form '%s-XX' is also accepted", strings.ToUpper(address.Country)),
+				)
+		}
+	}
+
+	if address.ZipCode != "" && len(errs.GetErrorsForField(fieldPrefix+".zipCode")) == 0 {
+		if valid, known := dataset.MatchPostalCode(address.Country, address.ZipCode); known && !valid {
+			errs.Add(fmt.Sprintf("%s.zipCode", fieldPrefix), address.ZipCode,
+				fmt.Sprintf("Zip code does not match the postal code format of %s", address.Country)).
+				WithConstraint("format").
+				WithSuggestions(
+					"Check the postal code for missing or extra digits",
+					"Use the postal code format used locally in the country",
+				)
+		}
+	}
+
+	return errs
+}
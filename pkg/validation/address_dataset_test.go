@@ -0,0 +1,148 @@
+package validation
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultAddressDatasetLoads(t *testing.T) {
+	dataset, err := LoadAddressDataset(bytes.NewReader(defaultAddressDatasetJSON))
+	require.NoError(t, err)
+	require.NotNil(t, dataset)
+
+	valid, known := DefaultAddressDataset().LookupSubdivision("BR", "SP")
+	assert.True(t, known)
+	assert.True(t, valid)
+}
+
+func TestStaticAddressDatasetLookups(t *testing.T) {
+	dataset := DefaultAddressDataset()
+
+	tests := []struct {
+		name        string
+		country     string
+		subdivision string
+		wantValid   bool
+		wantKnown   bool
+	}{
+		{"bare code", "BR", "SP", true, true},
+		{"full code", "BR", "BR-RJ", true, true},
+		{"lower case", "us", "ny", true, true},
+		{"unknown subdivision", "BR", "XX", false, true},
+		{"state name instead of code", "US", "New York", false, true},
+		{"country without data", "FR", "IDF", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, known := dataset.LookupSubdivision(tt.country, tt.subdivision)
+			assert.Equal(t, tt.wantValid, valid)
+			assert.Equal(t, tt.wantKnown, known)
+		})
+	}
+
+	postalTests := []struct {
+		country   string
+		code      string
+		wantValid bool
+		wantKnown bool
+	}{
+		{"BR", "01310-100", true, true},
+		{"BR", "01310100", true, true},
+		{"BR", "0131", false, true},
+		{"US", "12345-6789", true, true},
+		{"US", "1234", false, true},
+		{"CA", "k1a 0b1", true, true},
+		{"GB", "SW1A 1AA", true, true},
+		{"ZZ", "anything", false, false},
+	}
+
+	for _, tt := range postalTests {
+		t.Run(tt.country+"_"+tt.code, func(t *testing.T) {
+			valid, known := dataset.MatchPostalCode(tt.country, tt.code)
+			assert.Equal(t, tt.wantValid, valid)
+			assert.Equal(t, tt.wantKnown, known)
+		})
+	}
+}
+
+func TestNewAddressDatasetInvalidPattern(t *testing.T) {
+	_, err := NewAddressDataset(AddressData{
+		Countries: map[string]AddressCountryData{"BR": {PostalCode: "(["}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "BR")
+
+	_, err = LoadAddressDataset(strings.NewReader("not json"))
+	require.Error(t, err)
+}
+
+func TestEnhancedValidateAddressWithDataset(t *testing.T) {
+	validAddress := func() *Address {
+		return &Address{
+			Line1:   "Av. Paulista, 1000",
+			ZipCode: "01310-100",
+			City:    "São Paulo",
+			State:   "SP",
+			Country: "BR",
+		}
+	}
+
+	t.Run("valid address with default dataset", func(t *testing.T) {
+		errs := EnhancedValidateAddressWithDataset(validAddress(), "address", nil)
+		assert.False(t, errs.HasErrors(), errs.Error())
+	})
+
+	t.Run("invalid subdivision", func(t *testing.T) {
+		address := validAddress()
+		address.State = "São Paulo"
+
+		errs := EnhancedValidateAddressWithDataset(address, "address", nil)
+		stateErrs := errs.GetErrorsForField("address.state")
+		require.Len(t, stateErrs, 1)
+		assert.Equal(t, "enumeration", stateErrs[0].Constraint)
+	})
+
+	t.Run("invalid postal code", func(t *testing.T) {
+		address := validAddress()
+		address.ZipCode = "ABC"
+
+		errs := EnhancedValidateAddressWithDataset(address, "address", nil)
+		require.Len(t, errs.GetErrorsForField("address.zipCode"), 1)
+	})
+
+	t.Run("country not covered skips dataset checks", func(t *testing.T) {
+		address := validAddress()
+		address.Country = "FR"
+		address.State = "Île-de-France"
+		address.ZipCode = "75001"
+
+		errs := EnhancedValidateAddressWithDataset(address, "address", nil)
+		assert.False(t, errs.HasErrors(), errs.Error())
+	})
+
+	t.Run("custom dataset", func(t *testing.T) {
+		custom, err := NewAddressDataset(AddressData{
+			Countries: map[string]AddressCountryData{
+				"BR": {PostalCode: `^\d{5}-\d{3}$`, Subdivisions: []string{"RJ"}},
+			},
+		})
+		require.NoError(t, err)
+
+		address := validAddress()
+		address.ZipCode = "01310100"
+
+		errs := EnhancedValidateAddressWithDataset(address, "address", custom)
+		assert.Len(t, errs.GetErrorsForField("address.state"), 1)
+		assert.Len(t, errs.GetErrorsForField("address.zipCode"), 1)
+	})
+
+	t.Run("nil address", func(t *testing.T) {
+		errs := EnhancedValidateAddressWithDataset(nil, "address", nil)
+		assert.True(t, errs.HasErrors())
+	})
+}
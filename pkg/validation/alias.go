@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Account alias grammar enforced by the Midaz onboarding API.
+const (
+	// MaxAccountAliasLength is the maximum number of characters allowed in an account alias.
+	MaxAccountAliasLength = 100
+
+	// ExternalAccountAliasPrefix is reserved for system-managed external accounts
+	// and cannot appear in user-defined aliases.
+	ExternalAccountAliasPrefix = "@external/"
+)
+
+// accountAliasServerPattern mirrors the server-side alias character set (a-zA-Z0-9@:_-).
+var accountAliasServerPattern = regexp.MustCompile(`^[a-zA-Z0-9@:_-]+$`)
+
+// NormalizeAccountAlias returns the canonical form of an account alias: surrounding
+// whitespace is trimmed, inner whitespace runs are replaced with underscores and
+// letters are lower-cased. The result is not guaranteed to be valid; pass it to
+// ValidateAccountAlias to check.
+//
+// Example:
+//
+//	alias := validation.NormalizeAccountAlias("  Savings Account ")
+//	// alias == "savings_account"
+func NormalizeAccountAlias(alias string) string {
+	fields := strings.FieldsFunc(alias, unicode.IsSpace)
+
+	return strings.ToLower(strings.Join(fields, "_"))
+}
+
+// checkAccountAlias applies the server alias grammar and returns a field error
+// describing the first violation, or nil if the alias is valid.
+func checkAccountAlias(alias string) *FieldError {
+	if alias == "" {
+		return BuildFieldError("alias", alias, "Account alias cannot be empty").
+			WithConstraint("required").
+			WithSuggestions(GetCommonSuggestions("alias", alias, Required)...)
+	}
+
+	if strings.TrimSpace(alias) != alias || strings.IndexFunc(alias, unicode.IsSpace) >= 0 {
+		return BuildFieldError("alias", alias, "Account alias cannot contain whitespace").
+			WithConstraint("whitespace").
+			WithSuggestions(fmt.Sprintf("Use the normalized alias '%s'", NormalizeAccountAlias(alias)))
+	}
+
+	if length := len([]rune(alias)); length > MaxAccountAliasLength {
+		return BuildFieldError("alias", alias,
+			fmt.Sprintf("Account alias is %d characters long, maximum is %d", length, MaxAccountAliasLength)).
+			WithConstraint("max").
+			WithSuggestions(fmt.Sprintf("Shorten the alias to at most %d characters", MaxAccountAliasLength))
+	}
+
+	if strings.Contains(strings.ToLower(alias), ExternalAccountAliasPrefix) {
+		return BuildFieldError("alias", alias,
+			fmt.Sprintf("Account alias cannot contain the reserved prefix '%s'", ExternalAccountAliasPrefix)).
+			WithConstraint("reserved").
+			WithSuggestions(
+				"External accounts are created by the system and cannot be aliased manually",
+				"Remove the '@external/' segment from the alias",
+			)
+	}
+
+	if !accountAliasServerPattern.MatchString(alias) {
+		return BuildFieldError("alias", alias, "Invalid account alias format").
+			WithConstraint("format").
+			WithSuggestions(GetCommonSuggestions("alias", alias, Format)...)
+	}
+
+	return nil
+}
@@ -0,0 +1,292 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// AliasScope is the scope within which an AliasPolicy requires aliases to be unique.
+type AliasScope string
+
+const (
+	// AliasScopeLedger requires aliases to be unique within their ledger,
+	// as the Midaz API does
+	AliasScopeLedger AliasScope = "ledger"
+
+	// AliasScopeOrganization requires aliases to be unique across the
+	// ledgers of their organization
+	AliasScopeOrganization AliasScope = "organization"
+
+	// AliasScopeGlobal requires aliases to be unique across organizations
+	AliasScopeGlobal AliasScope = "global"
+)
+
+// AliasPolicy is an organization's naming convention for account aliases,
+// checked on top of the alias grammar of the Midaz API.
+type AliasPolicy struct {
+	// Prefixes are the prefixes aliases may start with (empty allows any)
+	Prefixes []string
+
+	// TypePrefixes maps an account type to the prefix the aliases of its
+	// accounts must start with, e.g. {"deposit": "dep_"}. Types are matched
+	// ignoring case.
+	TypePrefixes map[string]string
+
+	// Charset is the body of a regular expression character class listing
+	// the characters allowed in aliases, such as "a-z0-9_" (empty keeps the
+	// characters accepted by the API)
+	Charset string
+
+	// MaxLength bounds the length of aliases, in characters (zero keeps
+	// MaxAccountAliasLength)
+	MaxLength int
+
+	// UniqueWithin is the scope aliases must be unique in (empty leaves
+	// uniqueness to the API). Only aliases claimed through the same
+	// AliasPolicies are known.
+	UniqueWithin AliasScope
+}
+
+// AliasRequest is an alias about to be given to an account.
+type AliasRequest struct {
+	// OrganizationID and LedgerID locate the account
+	OrganizationID string
+	LedgerID       string
+
+	// AccountType is the type of the account, as given to its builder or template
+	AccountType string
+
+	// Alias is the alias to check
+	Alias string
+}
+
+// compiledAliasPolicy is an AliasPolicy with its charset compiled
+type compiledAliasPolicy struct {
+	AliasPolicy
+	charset *regexp.Regexp
+}
+
+// AliasPolicies holds the alias policies of organizations and the aliases
+// claimed under them, so builders and generators can report naming violations
+// before any account is created. It is safe for concurrent use.
+//
+// Example:
+//
+//	policies := validation.NewAliasPolicies()
+//	err := policies.Register("org-123", validation.AliasPolicy{
+//	    TypePrefixes: map[string]string{"deposit": "dep_"},
+//	    Charset:      "a-z0-9_",
+//	    UniqueWithin: validation.AliasScopeOrganization,
+//	})
+//
+//	err = policies.Claim(validation.AliasRequest{
+//	    OrganizationID: "org-123", LedgerID: "ledger-1", AccountType: "deposit", Alias: "dep_alice",
+//	})
+type AliasPolicies struct {
+	mu       sync.Mutex
+	policies map[string]*compiledAliasPolicy
+	claimed  map[string]struct{}
+}
+
+// NewAliasPolicies creates an empty set of alias policies.
+func NewAliasPolicies() *AliasPolicies {
+	return &AliasPolicies{
+		policies: make(map[string]*compiledAliasPolicy),
+		claimed:  make(map[string]struct{}),
+	}
+}
+
+// Register sets the alias policy of an organization, replacing any previous
+// one. An empty organization ID registers the default policy, applied to
+// organizations without their own.
+func (p *AliasPolicies) Register(organizationID string, policy AliasPolicy) error {
+	compiled := &compiledAliasPolicy{AliasPolicy: policy}
+
+	if policy.Charset != "" {
+		charset, err := regexp.Compile("^[" + policy.Charset + "]+$")
+		if err != nil {
+			return fmt.Errorf("invalid alias charset %q: %w", policy.Charset, err)
+		}
+
+		compiled.charset = charset
+	}
+
+	if policy.MaxLength < 0 {
+		return errors.New("alias max length cannot be negative")
+	}
+
+	switch policy.UniqueWithin {
+	case "", AliasScopeLedger, AliasScopeOrganization, AliasScopeGlobal:
+	default:
+		return fmt.Errorf("invalid alias uniqueness scope %q", policy.UniqueWithin)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.policies[organizationID] = compiled
+
+	return nil
+}
+
+// Check returns the violations of the alias of req against the API grammar
+// and the policy of its organization, including an alias already claimed in
+// the policy's uniqueness scope, or nil if there are none. It is safe to call
+// on nil AliasPolicies, which only check the API grammar.
+func (p *AliasPolicies) Check(req AliasRequest) error {
+	if p == nil {
+		return ValidateAccountAlias(req.Alias)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.check(req)
+}
+
+// Claim checks the alias of req like Check and, if it is valid, records it
+// as taken in the uniqueness scope of its organization's policy.
+func (p *AliasPolicies) Claim(req AliasRequest) error {
+	if p == nil {
+		return ValidateAccountAlias(req.Alias)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.check(req); err != nil {
+		return err
+	}
+
+	if key, ok := p.claimKey(req); ok {
+		p.claimed[key] = struct{}{}
+	}
+
+	return nil
+}
+
+// Release forgets an alias claimed with Claim, e.g. when the account it was
+// claimed for couldn't be created.
+func (p *AliasPolicies) Release(req AliasRequest) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.claimKey(req); ok {
+		delete(p.claimed, key)
+	}
+}
+
+// policyFor returns the policy of an organization, or the default one
+func (p *AliasPolicies) policyFor(organizationID string) *compiledAliasPolicy {
+	if policy, ok := p.policies[organizationID]; ok {
+		return policy
+	}
+
+	return p.policies[""]
+}
+
+// claimKey returns the key of the alias of req in the uniqueness scope of
+// its policy, or false if the policy doesn't require uniqueness
+func (p *AliasPolicies) claimKey(req AliasRequest) (string, bool) {
+	policy := p.policyFor(req.OrganizationID)
+	if policy == nil {
+		return "", false
+	}
+
+	switch policy.UniqueWithin {
+	case AliasScopeLedger:
+		return req.OrganizationID + "/" + req.LedgerID + "/" + req.Alias, true
+	case AliasScopeOrganization:
+		return req.OrganizationID + "//" + req.Alias, true
+	case AliasScopeGlobal:
+		return "//" + req.Alias, true
+	default:
+		return "", false
+	}
+}
+
+// check reports the violations of req; p.mu must be held
+func (p *AliasPolicies) check(req AliasRequest) error {
+	if err := checkAccountAlias(req.Alias); err != nil {
+		return err
+	}
+
+	policy := p.policyFor(req.OrganizationID)
+	if policy == nil {
+		return nil
+	}
+
+	errs := NewFieldErrors()
+	alias := req.Alias
+
+	if policy.MaxLength > 0 {
+		if length := len([]rune(alias)); length > policy.MaxLength {
+			errs.Add("alias", alias, fmt.Sprintf("Account alias is %d characters long, the policy allows at most %d", length, policy.MaxLength)).
+				WithConstraint("max").
+				WithSuggestions(fmt.Sprintf("Shorten the alias to at most %d characters", policy.MaxLength))
+		}
+	}
+
+	if policy.charset != nil && !policy.charset.MatchString(alias) {
+		errs.Add("alias", alias, fmt.Sprintf("Account alias has characters outside the policy charset [%s]", policy.Charset)).
+			WithConstraint("charset")
+	}
+
+	if len(policy.Prefixes) > 0 && !hasAnyPrefix(alias, policy.Prefixes) {
+		errs.Add("alias", alias, fmt.Sprintf("Account alias must start with one of: %s", strings.Join(policy.Prefixes, ", "))).
+			WithConstraint("prefix").
+			WithSuggestions(fmt.Sprintf("Use '%s%s'", policy.Prefixes[0], alias))
+	}
+
+	if prefix, ok := typePrefix(policy.TypePrefixes, req.AccountType); ok && !strings.HasPrefix(alias, prefix) {
+		errs.Add("alias", alias, fmt.Sprintf("Aliases of %s accounts must start with '%s'", req.AccountType, prefix)).
+			WithConstraint("prefix").
+			WithSuggestions(fmt.Sprintf("Use '%s%s'", prefix, alias))
+	}
+
+	if key, ok := p.claimKey(req); ok {
+		if _, taken := p.claimed[key]; taken {
+			errs.Add("alias", alias, fmt.Sprintf("Account alias is already used in this %s", policy.UniqueWithin)).
+				WithConstraint("unique")
+		}
+	}
+
+	if errs.HasErrors() {
+		return errs
+	}
+
+	return nil
+}
+
+// hasAnyPrefix reports whether s starts with one of prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// typePrefix returns the prefix required for accountType, ignoring case
+func typePrefix(prefixes map[string]string, accountType string) (string, bool) {
+	if accountType == "" {
+		return "", false
+	}
+
+	for t, prefix := range prefixes {
+		if strings.EqualFold(t, accountType) {
+			return prefix, true
+		}
+	}
+
+	return "", false
+}
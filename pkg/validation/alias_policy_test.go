@@ -0,0 +1,138 @@
+package validation_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func aliasConstraints(t *testing.T, err error) []string {
+	t.Helper()
+
+	var fieldErrs *validation.FieldErrors
+	if !assert.ErrorAs(t, err, &fieldErrs) {
+		return nil
+	}
+
+	constraints := make([]string, 0, len(fieldErrs.Errors))
+	for _, fe := range fieldErrs.Errors {
+		constraints = append(constraints, fe.Constraint)
+	}
+
+	return constraints
+}
+
+func TestAliasPolicies_Check(t *testing.T) {
+	policies := validation.NewAliasPolicies()
+	require.NoError(t, policies.Register("org-1", validation.AliasPolicy{
+		Prefixes:     []string{"acme_", "@acme_"},
+		TypePrefixes: map[string]string{"deposit": "acme_dep_"},
+		Charset:      "a-z0-9_@",
+		MaxLength:    20,
+	}))
+
+	check := func(accountType, alias string) error {
+		return policies.Check(validation.AliasRequest{OrganizationID: "org-1", LedgerID: "l", AccountType: accountType, Alias: alias})
+	}
+
+	require.NoError(t, check("deposit", "acme_dep_alice"))
+	require.NoError(t, check("", "@acme_treasury"))
+	require.NoError(t, check("DEPOSIT", "acme_dep_bob"), "account types match ignoring case")
+
+	assert.Equal(t, []string{"prefix"}, aliasConstraints(t, check("", "alice")))
+	assert.Equal(t, []string{"prefix"}, aliasConstraints(t, check("deposit", "acme_alice")))
+	assert.Equal(t, []string{"charset"}, aliasConstraints(t, check("", "acme_Alice")))
+	assert.Equal(t, []string{"max"}, aliasConstraints(t, check("", "acme_a_very_long_alias")))
+
+	var fieldErr *validation.FieldError
+
+	require.ErrorAs(t, check("", "acme alice"), &fieldErr, "the API grammar is checked first")
+	assert.Equal(t, "whitespace", fieldErr.Constraint)
+}
+
+func TestAliasPolicies_DefaultPolicy(t *testing.T) {
+	policies := validation.NewAliasPolicies()
+	require.NoError(t, policies.Register("", validation.AliasPolicy{Prefixes: []string{"std_"}}))
+	require.NoError(t, policies.Register("org-2", validation.AliasPolicy{Prefixes: []string{"two_"}}))
+
+	require.Error(t, policies.Check(validation.AliasRequest{OrganizationID: "org-1", Alias: "two_alice"}))
+	require.NoError(t, policies.Check(validation.AliasRequest{OrganizationID: "org-1", Alias: "std_alice"}))
+	require.NoError(t, policies.Check(validation.AliasRequest{OrganizationID: "org-2", Alias: "two_alice"}))
+
+	var nilPolicies *validation.AliasPolicies
+	require.NoError(t, nilPolicies.Check(validation.AliasRequest{Alias: "anything"}))
+	require.Error(t, nilPolicies.Claim(validation.AliasRequest{Alias: "@external/USD"}))
+}
+
+func TestAliasPolicies_Uniqueness(t *testing.T) {
+	tests := []struct {
+		scope         validation.AliasScope
+		sameOrgLedger bool
+		otherOrg      bool
+	}{
+		{validation.AliasScopeLedger, false, false},
+		{validation.AliasScopeOrganization, true, false},
+		{validation.AliasScopeGlobal, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.scope), func(t *testing.T) {
+			policies := validation.NewAliasPolicies()
+			require.NoError(t, policies.Register("", validation.AliasPolicy{UniqueWithin: tt.scope}))
+
+			first := validation.AliasRequest{OrganizationID: "org-1", LedgerID: "ledger-1", Alias: "alice"}
+			require.NoError(t, policies.Claim(first))
+			assert.Equal(t, []string{"unique"}, aliasConstraints(t, policies.Check(first)))
+
+			otherLedger := first
+			otherLedger.LedgerID = "ledger-2"
+			assert.Equal(t, tt.sameOrgLedger, policies.Check(otherLedger) != nil)
+
+			otherOrg := first
+			otherOrg.OrganizationID = "org-2"
+			assert.Equal(t, tt.otherOrg, policies.Check(otherOrg) != nil)
+
+			policies.Release(first)
+			require.NoError(t, policies.Check(first))
+		})
+	}
+}
+
+func TestAliasPolicies_ConcurrentClaims(t *testing.T) {
+	policies := validation.NewAliasPolicies()
+	require.NoError(t, policies.Register("", validation.AliasPolicy{UniqueWithin: validation.AliasScopeLedger}))
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		claimed int
+	)
+
+	for range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if policies.Claim(validation.AliasRequest{OrganizationID: "o", LedgerID: "l", Alias: "alice"}) == nil {
+				mu.Lock()
+				claimed++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, 1, claimed)
+}
+
+func TestAliasPolicies_RegisterErrors(t *testing.T) {
+	policies := validation.NewAliasPolicies()
+
+	require.Error(t, policies.Register("org", validation.AliasPolicy{Charset: "a-\\"}))
+	require.Error(t, policies.Register("org", validation.AliasPolicy{MaxLength: -1}))
+	require.Error(t, policies.Register("org", validation.AliasPolicy{UniqueWithin: "tenant"}))
+}
@@ -0,0 +1,388 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// TransferLeg is a single source or destination entry of a transaction,
+// as seen by batch pre-validation.
+type TransferLeg struct {
+	// Account is the account ID or alias the leg refers to
+	Account string
+
+	// Asset is the asset code of the leg amount
+	Asset string
+
+	// Value is the leg amount as a decimal string
+	Value string
+}
+
+// TransferLegs describes the money movement of a transaction: its total amount
+// and the legs it is split into.
+type TransferLegs struct {
+	// Asset is the asset code of the transaction total
+	Asset string
+
+	// Value is the transaction total as a decimal string
+	Value string
+
+	// Sources are the legs the amount is taken from
+	Sources []TransferLeg
+
+	// Destinations are the legs the amount is distributed to
+	Destinations []TransferLeg
+}
+
+// TransactionBatchItem is implemented by transaction inputs that can be
+// pre-validated as part of a batch. models.CreateTransactionInput implements it.
+type TransactionBatchItem interface {
+	// Validate runs the input's own validation rules
+	Validate() error
+
+	// GetIdempotencyKey returns the client-generated idempotency key, if any
+	GetIdempotencyKey() string
+
+	// GetTransferLegs returns the total amount and the source/destination legs
+	GetTransferLegs() TransferLegs
+}
+
+// BatchItemError holds every validation error found for one batch input.
+type BatchItemError struct {
+	// Index is the position of the input in the validated slice
+	Index int
+
+	// Errors are the field errors found for the input
+	Errors *FieldErrors
+}
+
+// Error implements the error interface for BatchItemError
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("item %d: %s", e.Index, strings.TrimSpace(e.Errors.Error()))
+}
+
+// BatchReport is the result of ValidateTransactionBatch. Items that are not
+// listed in Errors passed every check.
+type BatchReport struct {
+	// Total is the number of inputs that were validated
+	Total int
+
+	// Errors lists the invalid inputs in index order
+	Errors []*BatchItemError
+}
+
+// Valid returns true if every input in the batch passed validation
+func (r *BatchReport) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// InvalidIndices returns the indices of inputs that failed validation
+func (r *BatchReport) InvalidIndices() []int {
+	indices := make([]int, 0, len(r.Errors))
+	for _, itemErr := range r.Errors {
+		indices = append(indices, itemErr.Index)
+	}
+
+	return indices
+}
+
+// ValidIndices returns the indices of inputs that passed validation
+func (r *BatchReport) ValidIndices() []int {
+	invalid := make(map[int]bool, len(r.Errors))
+	for _, itemErr := range r.Errors {
+		invalid[itemErr.Index] = true
+	}
+
+	indices := make([]int, 0, r.Total-len(r.Errors))
+
+	for i := 0; i < r.Total; i++ {
+		if !invalid[i] {
+			indices = append(indices, i)
+		}
+	}
+
+	return indices
+}
+
+// ErrorFor returns the errors found for the input at index, or nil if it is valid
+func (r *BatchReport) ErrorFor(index int) *BatchItemError {
+	for _, itemErr := range r.Errors {
+		if itemErr.Index == index {
+			return itemErr
+		}
+	}
+
+	return nil
+}
+
+// Error implements the error interface for BatchReport
+func (r *BatchReport) Error() string {
+	if r.Valid() {
+		return ""
+	}
+
+	var builder strings.Builder
+
+	_, _ = fmt.Fprintf(&builder, "Batch validation failed for %d of %d items:\n", len(r.Errors), r.Total)
+
+	for _, itemErr := range r.Errors {
+		_, _ = fmt.Fprintf(&builder, "%s\n", itemErr.Error())
+	}
+
+	return builder.String()
+}
+
+// batchConfig holds the configuration for batch pre-validation
+type batchConfig struct {
+	assetScales map[string]int
+}
+
+// BatchOption configures ValidateTransactionBatch
+type BatchOption func(*batchConfig) error
+
+// WithAssetScale declares the number of decimal places allowed for an asset.
+// Amounts of that asset with more significant decimal places are reported.
+//
+// Example:
+//
+//	report := validation.ValidateTransactionBatch(inputs,
+//	    validation.WithAssetScale("USD", 2),
+//	    validation.WithAssetScale("BTC", 8))
+func WithAssetScale(asset string, scale int) BatchOption {
+	return func(c *batchConfig) error {
+		if asset == "" {
+			return fmt.Errorf("asset code cannot be empty")
+		}
+
+		if scale < 0 {
+			return fmt.Errorf("scale must be non-negative, got %d", scale)
+		}
+
+		c.assetScales[asset] = scale
+
+		return nil
+	}
+}
+
+// ValidateTransactionBatch pre-validates a slice of transaction inputs before
+// submission and returns a report indexed by input position. For every input it
+// checks the input's own rules, that the source and destination legs balance
+// against the transaction total, that every amount uses the transaction asset
+// and, when configured with WithAssetScale, the asset's scale. Idempotency keys
+// must be unique within the batch: the first occurrence is kept and later ones
+// are reported.
+//
+// An invalid option is reported as an error for every item, so a misconfigured
+// call never lets a batch through.
+//
+// Example:
+//
+//	report := validation.ValidateTransactionBatch(inputs, validation.WithAssetScale("USD", 2))
+//	if !report.Valid() {
+//	    log.Println(report.Error())
+//	}
+//
+//	// Submit only the rows that passed
+//	valid := validation.DropInvalid(inputs, report)
+func ValidateTransactionBatch[T TransactionBatchItem](inputs []T, opts ...BatchOption) *BatchReport {
+	report := &BatchReport{Total: len(inputs)}
+
+	config := &batchConfig{assetScales: map[string]int{}}
+
+	var optErr error
+
+	for _, opt := range opts {
+		if err := opt(config); err != nil {
+			optErr = err
+			break
+		}
+	}
+
+	seenKeys := make(map[string]int, len(inputs))
+
+	for i, input := range inputs {
+		errs := NewFieldErrors()
+
+		if optErr != nil {
+			errs.Add("options", nil, fmt.Sprintf("Invalid batch option: %v", optErr)).
+				WithConstraint("config")
+		} else {
+			validateBatchItem(errs, input, config)
+			checkDuplicateIdempotencyKey(errs, input, i, seenKeys)
+		}
+
+		if errs.HasErrors() {
+			report.Errors = append(report.Errors, &BatchItemError{Index: i, Errors: errs})
+		}
+	}
+
+	return report
+}
+
+// DropInvalid returns the inputs that passed validation, preserving their order.
+func DropInvalid[T any](inputs []T, report *BatchReport) []T {
+	if report == nil || report.Valid() {
+		return inputs
+	}
+
+	valid := make([]T, 0, len(inputs)-len(report.Errors))
+	for _, i := range report.ValidIndices() {
+		if i < len(inputs) {
+			valid = append(valid, inputs[i])
+		}
+	}
+
+	return valid
+}
+
+// validateBatchItem runs the per-input checks
+func validateBatchItem(errs *FieldErrors, input TransactionBatchItem, config *batchConfig) {
+	if isNilBatchItem(input) {
+		errs.Add("input", nil, "Transaction input cannot be nil").WithConstraint("required")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		errs.AddError(WrapError("input", nil, err).WithConstraint("valid"))
+	}
+
+	legs := input.GetTransferLegs()
+
+	total, ok := parseBatchAmount(errs, "value", legs.Value)
+	if !ok {
+		return
+	}
+
+	checkAssetScale(errs, "value", legs.Asset, total, config)
+
+	sourceTotal, sourcesOK := sumBatchLegs(errs, "source", legs.Asset, legs.Sources, config)
+	destTotal, destsOK := sumBatchLegs(errs, "destination", legs.Asset, legs.Destinations, config)
+
+	if sourcesOK && len(legs.Sources) > 0 && !sourceTotal.Equal(total) {
+		errs.Add("source", sourceTotal.String(),
+			fmt.Sprintf("Source amounts sum to %s but transaction value is %s", sourceTotal, total)).
+			WithConstraint("balance").
+			WithSuggestions(GetCommonSuggestions("source", sourceTotal.String(), Consistency)...)
+	}
+
+	if destsOK && len(legs.Destinations) > 0 && !destTotal.Equal(total) {
+		errs.Add("destination", destTotal.String(),
+			fmt.Sprintf("Destination amounts sum to %s but transaction value is %s", destTotal, total)).
+			WithConstraint("balance").
+			WithSuggestions(GetCommonSuggestions("destination", destTotal.String(), Consistency)...)
+	}
+}
+
+// sumBatchLegs validates each leg and returns the sum of their values.
+// The boolean result is false if any leg amount could not be parsed.
+func sumBatchLegs(errs *FieldErrors, side, asset string, legs []TransferLeg, config *batchConfig) (decimal.Decimal, bool) {
+	sum := decimal.Zero
+	ok := true
+
+	for i, leg := range legs {
+		field := fmt.Sprintf("%s[%d]", side, i)
+
+		if leg.Asset != "" && asset != "" && leg.Asset != asset {
+			errs.Add(field+".asset", leg.Asset,
+				fmt.Sprintf("Leg asset %s does not match transaction asset %s", leg.Asset, asset)).
+				WithConstraint("consistency")
+		}
+
+		value, parsed := parseBatchAmount(errs, field+".value", leg.Value)
+		if !parsed {
+			ok = false
+			continue
+		}
+
+		checkAssetScale(errs, field+".value", asset, value, config)
+
+		sum = sum.Add(value)
+	}
+
+	return sum, ok
+}
+
+// parseBatchAmount parses a positive decimal amount, recording an error if it is invalid
+func parseBatchAmount(errs *FieldErrors, field, value string) (decimal.Decimal, bool) {
+	amount, err := decimal.NewFromString(value)
+	if err != nil {
+		errs.Add(field, value, "Amount must be a decimal number").
+			WithConstraint("format").
+			WithSuggestions(GetCommonSuggestions(field, value, Format)...)
+
+		return decimal.Zero, false
+	}
+
+	if !amount.IsPositive() {
+		errs.Add(field, value, "Amount must be greater than zero").
+			WithConstraint("min")
+
+		return decimal.Zero, false
+	}
+
+	return amount, true
+}
+
+// checkAssetScale reports amounts with more decimal places than the asset allows
+func checkAssetScale(errs *FieldErrors, field, asset string, amount decimal.Decimal, config *batchConfig) {
+	scale, ok := config.assetScales[asset]
+	if !ok {
+		return
+	}
+
+	if places := significantDecimalPlaces(amount); places > scale {
+		errs.Add(field, amount.String(),
+			fmt.Sprintf("Amount has %d decimal places but %s allows at most %d", places, asset, scale)).
+			WithConstraint("scale").
+			WithSuggestions(fmt.Sprintf("Round the amount to %d decimal places", scale))
+	}
+}
+
+// significantDecimalPlaces returns the number of decimal places ignoring trailing zeros
+func significantDecimalPlaces(amount decimal.Decimal) int {
+	str := amount.String()
+
+	dot := strings.IndexByte(str, '.')
+	if dot < 0 {
+		return 0
+	}
+
+	return len(strings.TrimRight(str[dot+1:], "0"))
+}
+
+// checkDuplicateIdempotencyKey reports idempotency keys already used earlier in the batch
+func checkDuplicateIdempotencyKey(errs *FieldErrors, input TransactionBatchItem, index int, seen map[string]int) {
+	if isNilBatchItem(input) {
+		return
+	}
+
+	key := input.GetIdempotencyKey()
+	if key == "" {
+		return
+	}
+
+	if first, exists := seen[key]; exists {
+		errs.Add("idempotencyKey", key,
+			fmt.Sprintf("Idempotency key is already used by item %d in this batch", first)).
+			WithConstraint("unique").
+			WithSuggestions("Generate a unique idempotency key for every transaction in the batch")
+
+		return
+	}
+
+	seen[key] = index
+}
+
+// isNilBatchItem reports whether the interface is nil or holds a nil pointer
+func isNilBatchItem(input TransactionBatchItem) bool {
+	if input == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(input)
+
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
@@ -0,0 +1,154 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBatchInput(key, value string, sources, destinations map[string]string) *models.CreateTransactionInput {
+	send := &models.SendInput{
+		Asset:      "USD",
+		Value:      value,
+		Source:     &models.SourceInput{},
+		Distribute: &models.DistributeInput{},
+	}
+
+	for account, amount := range sources {
+		send.Source.From = append(send.Source.From, models.FromToInput{
+			Account: account,
+			Amount:  models.AmountInput{Asset: "USD", Value: amount},
+		})
+	}
+
+	for account, amount := range destinations {
+		send.Distribute.To = append(send.Distribute.To, models.FromToInput{
+			Account: account,
+			Amount:  models.AmountInput{Asset: "USD", Value: amount},
+		})
+	}
+
+	return &models.CreateTransactionInput{
+		AssetCode:      "USD",
+		Amount:         value,
+		IdempotencyKey: key,
+		Send:           send,
+	}
+}
+
+func TestValidateTransactionBatch(t *testing.T) {
+	t.Run("all valid", func(t *testing.T) {
+		inputs := []*models.CreateTransactionInput{
+			newBatchInput("k1", "100.00", map[string]string{"@a": "100.00"}, map[string]string{"@b": "60", "@c": "40"}),
+			newBatchInput("k2", "5", map[string]string{"@a": "5"}, map[string]string{"@b": "5"}),
+		}
+
+		report := validation.ValidateTransactionBatch(inputs)
+		assert.True(t, report.Valid())
+		assert.Equal(t, 2, report.Total)
+		assert.Empty(t, report.Error())
+		assert.Equal(t, []int{0, 1}, report.ValidIndices())
+		assert.Len(t, validation.DropInvalid(inputs, report), 2)
+	})
+
+	t.Run("unbalanced legs", func(t *testing.T) {
+		inputs := []*models.CreateTransactionInput{
+			newBatchInput("", "100", map[string]string{"@a": "90"}, map[string]string{"@b": "100"}),
+		}
+
+		report := validation.ValidateTransactionBatch(inputs)
+		require.False(t, report.Valid())
+
+		itemErr := report.ErrorFor(0)
+		require.NotNil(t, itemErr)
+
+		sourceErrs := itemErr.Errors.GetErrorsForField("source")
+		require.Len(t, sourceErrs, 1)
+		assert.Equal(t, "balance", sourceErrs[0].Constraint)
+		assert.Empty(t, itemErr.Errors.GetErrorsForField("destination"))
+	})
+
+	t.Run("duplicate idempotency keys", func(t *testing.T) {
+		inputs := []*models.CreateTransactionInput{
+			newBatchInput("dup", "10", map[string]string{"@a": "10"}, map[string]string{"@b": "10"}),
+			newBatchInput("other", "10", map[string]string{"@a": "10"}, map[string]string{"@b": "10"}),
+			newBatchInput("dup", "10", map[string]string{"@a": "10"}, map[string]string{"@b": "10"}),
+		}
+
+		report := validation.ValidateTransactionBatch(inputs)
+		assert.Equal(t, []int{2}, report.InvalidIndices())
+
+		keyErrs := report.ErrorFor(2).Errors.GetErrorsForField("idempotencyKey")
+		require.Len(t, keyErrs, 1)
+		assert.Contains(t, keyErrs[0].Message, "item 0")
+
+		valid := validation.DropInvalid(inputs, report)
+		require.Len(t, valid, 2)
+		assert.Equal(t, "other", valid[1].IdempotencyKey)
+	})
+
+	t.Run("asset scale", func(t *testing.T) {
+		inputs := []*models.CreateTransactionInput{
+			newBatchInput("", "10.500", map[string]string{"@a": "10.500"}, map[string]string{"@b": "10.5"}),
+			newBatchInput("", "10.125", map[string]string{"@a": "10.125"}, map[string]string{"@b": "10.125"}),
+		}
+
+		report := validation.ValidateTransactionBatch(inputs, validation.WithAssetScale("USD", 2))
+		assert.Equal(t, []int{1}, report.InvalidIndices())
+
+		scaleErrs := report.ErrorFor(1).Errors.GetErrorsForField("value")
+		require.Len(t, scaleErrs, 1)
+		assert.Equal(t, "scale", scaleErrs[0].Constraint)
+	})
+
+	t.Run("mismatched leg asset", func(t *testing.T) {
+		input := newBatchInput("", "10", map[string]string{"@a": "10"}, map[string]string{"@b": "10"})
+		input.Send.Distribute.To[0].Amount.Asset = "EUR"
+
+		report := validation.ValidateTransactionBatch([]*models.CreateTransactionInput{input})
+		require.False(t, report.Valid())
+		assert.NotEmpty(t, report.ErrorFor(0).Errors.GetErrorsForField("destination[0].asset"))
+	})
+
+	t.Run("invalid amount and nil input", func(t *testing.T) {
+		inputs := []*models.CreateTransactionInput{
+			newBatchInput("", "abc", map[string]string{"@a": "10"}, map[string]string{"@b": "10"}),
+			nil,
+		}
+
+		report := validation.ValidateTransactionBatch(inputs)
+		assert.Equal(t, []int{0, 1}, report.InvalidIndices())
+		assert.NotEmpty(t, report.ErrorFor(0).Errors.GetErrorsForField("value"))
+		assert.NotEmpty(t, report.ErrorFor(1).Errors.GetErrorsForField("input"))
+		assert.True(t, strings.HasPrefix(report.Error(), "Batch validation failed for 2 of 2 items"))
+		assert.Empty(t, validation.DropInvalid(inputs, report))
+	})
+
+	t.Run("invalid option fails every item", func(t *testing.T) {
+		inputs := []*models.CreateTransactionInput{
+			newBatchInput("", "10", map[string]string{"@a": "10"}, map[string]string{"@b": "10"}),
+		}
+
+		report := validation.ValidateTransactionBatch(inputs, validation.WithAssetScale("USD", -1))
+		assert.Equal(t, []int{0}, report.InvalidIndices())
+	})
+
+	t.Run("operations based input", func(t *testing.T) {
+		input := &models.CreateTransactionInput{
+			AssetCode: "USD",
+			Amount:    "50",
+			Operations: []models.CreateOperationInput{
+				{Type: "DEBIT", AccountID: "acc-1", Amount: "50", AssetCode: "USD"},
+				{Type: "CREDIT", AccountID: "acc-2", Amount: "30", AssetCode: "USD"},
+			},
+		}
+
+		report := validation.ValidateTransactionBatch([]*models.CreateTransactionInput{input})
+		require.False(t, report.Valid())
+		assert.NotEmpty(t, report.ErrorFor(0).Errors.GetErrorsForField("destination"))
+	})
+}
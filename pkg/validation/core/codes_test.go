@@ -0,0 +1,58 @@
+package core
+
+import (
+	"slices"
+	"testing"
+
+	midazutils "github.com/LerianStudio/midaz/v3/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCodesMatchBackend checks that the codes of this package are those
+// accepted by the Midaz backend.
+func TestCodesMatchBackend(t *testing.T) {
+	assert.True(t, slices.IsSorted(currencyCodes), "currencyCodes must be sorted for binary search")
+	assert.True(t, slices.IsSorted(countryCodes), "countryCodes must be sorted for binary search")
+
+	for _, code := range currencyCodes {
+		assert.NoError(t, midazutils.ValidateCurrency(code), code)
+	}
+
+	for _, code := range countryCodes {
+		assert.NoError(t, midazutils.ValidateCountryAddress(code), code)
+	}
+
+	for _, accountType := range accountTypes {
+		assert.NoError(t, midazutils.ValidateAccountType(accountType), accountType)
+	}
+
+	for _, assetType := range assetTypes {
+		assert.NoError(t, midazutils.ValidateType(assetType), assetType)
+	}
+
+	// And the other way round, over every two- and three-letter code
+	for _, code := range letterCodes() {
+		assert.Equal(t, midazutils.ValidateCurrency(code) == nil, isCurrencyCode(code), code)
+		assert.Equal(t, midazutils.ValidateCountryAddress(code) == nil, isCountryCode(code), code)
+	}
+
+	assert.False(t, isAccountType("checking"))
+	assert.False(t, isAssetType("stock"))
+}
+
+// letterCodes returns the codes of two and three uppercase letters.
+func letterCodes() []string {
+	var codes []string
+
+	for a := 'A'; a <= 'Z'; a++ {
+		for b := 'A'; b <= 'Z'; b++ {
+			codes = append(codes, string([]rune{a, b}))
+
+			for c := 'A'; c <= 'Z'; c++ {
+				codes = append(codes, string([]rune{a, b, c}))
+			}
+		}
+	}
+
+	return codes
+}
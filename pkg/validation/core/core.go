@@ -3,6 +3,10 @@
 // This package contains primitive validation functions that don't depend on
 // any model structures, making it usable by both the models package and the
 // validation package without creating circular dependencies.
+//
+// It only depends on the standard library, so it builds for every target,
+// including GOOS=js GOARCH=wasm and GOOS=wasip1, for browser-based tools and
+// edge functions validating input before it reaches the SDK client.
 package core
 
 import (
@@ -11,18 +15,36 @@ import (
 	"regexp"
 	"strings"
 	"time"
+)
 
-	midazutils "github.com/LerianStudio/midaz/v3/pkg/utils"
+// Metadata limits enforced by the Midaz backend.
+const (
+	// BackendMaxMetadataKeyLength is the maximum length of a metadata key
+	BackendMaxMetadataKeyLength = 100
+
+	// BackendMaxMetadataValueLength is the maximum length of a metadata string value
+	BackendMaxMetadataValueLength = 2000
 )
 
 // ValidationConfig represents options for the validation behavior
 type ValidationConfig struct {
-	// MaxMetadataSize defines the maximum size of metadata in bytes
+	// MaxMetadataSize defines the maximum size of metadata in bytes; zero means unlimited
 	MaxMetadataSize int
 
 	// MaxStringLength defines the maximum length for string fields in metadata
 	MaxStringLength int
 
+	// MaxMetadataKeys defines the maximum number of metadata keys; zero means unlimited
+	MaxMetadataKeys int
+
+	// MaxMetadataKeyLength defines the maximum length of a metadata key
+	MaxMetadataKeyLength int
+
+	// BackendMetadataValues validates metadata values with the rules of the
+	// Midaz backend: arrays of non-map values are allowed and numbers are
+	// not range-checked
+	BackendMetadataValues bool
+
 	// MaxAddressLineLength defines the maximum length for address lines
 	MaxAddressLineLength int
 
@@ -47,6 +69,7 @@ func DefaultValidationConfig() *ValidationConfig {
 	return &ValidationConfig{
 		MaxMetadataSize:      4096,
 		MaxStringLength:      256,
+		MaxMetadataKeyLength: 64,
 		MaxAddressLineLength: 100,
 		MaxZipCodeLength:     20,
 		MaxCityLength:        100,
@@ -81,6 +104,48 @@ func WithMaxStringLength(length int) ValidationOption {
 	}
 }
 
+// WithMaxMetadataKeys sets the maximum number of metadata keys
+func WithMaxMetadataKeys(count int) ValidationOption {
+	return func(c *ValidationConfig) error {
+		if count <= 0 {
+			return fmt.Errorf("max metadata keys must be positive, got %d", count)
+		}
+
+		c.MaxMetadataKeys = count
+
+		return nil
+	}
+}
+
+// WithMaxMetadataKeyLength sets the maximum length of a metadata key
+func WithMaxMetadataKeyLength(length int) ValidationOption {
+	return func(c *ValidationConfig) error {
+		if length <= 0 {
+			return fmt.Errorf("max metadata key length must be positive, got %d", length)
+		}
+
+		c.MaxMetadataKeyLength = length
+
+		return nil
+	}
+}
+
+// WithBackendMetadataLimits aligns the metadata limits with the ones the
+// Midaz backend enforces: keys of up to BackendMaxMetadataKeyLength
+// characters, string values of up to BackendMaxMetadataValueLength
+// characters, the backend's value rules and no total size limit. Options applied after it can tighten
+// them, such as WithMaxMetadataKeys to set a key budget.
+func WithBackendMetadataLimits() ValidationOption {
+	return func(c *ValidationConfig) error {
+		c.MaxMetadataKeyLength = BackendMaxMetadataKeyLength
+		c.MaxStringLength = BackendMaxMetadataValueLength
+		c.MaxMetadataSize = 0
+		c.BackendMetadataValues = true
+
+		return nil
+	}
+}
+
 // WithMaxAddressLineLength sets the maximum length for address lines
 func WithMaxAddressLineLength(length int) ValidationOption {
 	return func(c *ValidationConfig) error {
@@ -336,14 +401,9 @@ func ValidateAccountType(accountType string) error {
 		return errors.New("account type is required")
 	}
 
-	// Use commons.ValidateAccountType to ensure consistency with backend APIs
-	if err := midazutils.ValidateAccountType(accountType); err != nil {
-		// Convert the error to a more user-friendly message
-		// Create a list of valid types for the error message
-		validTypes := []string{"deposit", "savings", "loans", "marketplace", "creditCard"}
-
+	if !isAccountType(accountType) {
 		return fmt.Errorf("invalid account type: %s. Valid types are: %s",
-			accountType, strings.Join(validTypes, ", "))
+			accountType, strings.Join(accountTypes, ", "))
 	}
 
 	return nil
@@ -356,14 +416,9 @@ func ValidateAssetType(assetType string) error {
 		return errors.New("asset type is required")
 	}
 
-	// Use commons.ValidateType to ensure consistency with backend APIs
-	// Note: commons.ValidateType expects lowercase types, so we convert to lowercase
-	if err := midazutils.ValidateType(strings.ToLower(assetType)); err != nil {
-		// Create a list of valid types for the error message
-		validTypes := []string{"crypto", "currency", "commodity", "others"}
-
+	if !isAssetType(strings.ToLower(assetType)) {
 		return fmt.Errorf("invalid asset type: %s. Valid types are: %s",
-			assetType, strings.Join(validTypes, ", "))
+			assetType, strings.Join(assetTypes, ", "))
 	}
 
 	return nil
@@ -375,8 +430,7 @@ func ValidateCurrencyCode(code string) error {
 		return errors.New("currency code cannot be empty")
 	}
 
-	// Use commons.ValidateCurrency to ensure consistency with backend APIs
-	if err := midazutils.ValidateCurrency(code); err != nil {
+	if !isCurrencyCode(code) {
 		return fmt.Errorf("invalid currency code: %s", code)
 	}
 
@@ -389,8 +443,7 @@ func ValidateCountryCode(code string) error {
 		return errors.New("country code cannot be empty")
 	}
 
-	// Use commons.ValidateCountryAddress to ensure consistency with backend APIs
-	if err := midazutils.ValidateCountryAddress(code); err != nil {
+	if !isCountryCode(code) {
 		return fmt.Errorf("invalid country code: %s (must be a valid ISO 3166-1 alpha-2 code)", code)
 	}
 
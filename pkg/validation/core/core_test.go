@@ -623,6 +623,35 @@ func TestValidationConfig(t *testing.T) {
 		assert.Contains(t, err.Error(), "max state length must be positive")
 	})
 
+	t.Run("WithMaxMetadataKeys and WithMaxMetadataKeyLength", func(t *testing.T) {
+		config, err := core.NewValidationConfig(core.WithMaxMetadataKeys(20), core.WithMaxMetadataKeyLength(32))
+		require.NoError(t, err)
+		assert.Equal(t, 20, config.MaxMetadataKeys)
+		assert.Equal(t, 32, config.MaxMetadataKeyLength)
+	})
+
+	t.Run("WithMaxMetadataKeys error for zero", func(t *testing.T) {
+		_, err := core.NewValidationConfig(core.WithMaxMetadataKeys(0))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "max metadata keys must be positive")
+	})
+
+	t.Run("WithMaxMetadataKeyLength error for negative", func(t *testing.T) {
+		_, err := core.NewValidationConfig(core.WithMaxMetadataKeyLength(-1))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "max metadata key length must be positive")
+	})
+
+	t.Run("WithBackendMetadataLimits", func(t *testing.T) {
+		config, err := core.NewValidationConfig(core.WithBackendMetadataLimits(), core.WithMaxMetadataKeys(10))
+		require.NoError(t, err)
+		assert.Equal(t, core.BackendMaxMetadataKeyLength, config.MaxMetadataKeyLength)
+		assert.Equal(t, core.BackendMaxMetadataValueLength, config.MaxStringLength)
+		assert.Zero(t, config.MaxMetadataSize)
+		assert.True(t, config.BackendMetadataValues)
+		assert.Equal(t, 10, config.MaxMetadataKeys)
+	})
+
 	t.Run("WithStrictMode true", func(t *testing.T) {
 		config, err := core.NewValidationConfig(core.WithStrictMode(true))
 		require.NoError(t, err)
@@ -0,0 +1,250 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DocumentValidator validates a legal document number for a specific country.
+type DocumentValidator func(document string) error
+
+// einPattern matches a US Employer Identification Number with or without the hyphen.
+var einPattern = regexp.MustCompile(`^\d{2}-?\d{7}$`)
+
+// cnpjPattern matches an unformatted CNPJ. The first twelve positions may be
+// alphanumeric (the format adopted by the Brazilian Federal Revenue in 2026),
+// the two check digits are always numeric.
+var cnpjPattern = regexp.MustCompile(`^[0-9A-Z]{12}\d{2}$`)
+
+// cpfPattern matches an unformatted CPF.
+var cpfPattern = regexp.MustCompile(`^\d{11}$`)
+
+// documentValidators maps ISO 3166-1 alpha-2 country codes to their legal document validator.
+var documentValidators = map[string]DocumentValidator{
+	"BR": ValidateBrazilianDocument,
+	"US": ValidateEIN,
+}
+
+// stripDocumentFormatting removes the punctuation commonly used when writing document numbers.
+func stripDocumentFormatting(document string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '-', '/', ' ':
+			return -1
+		default:
+			return r
+		}
+	}, document)
+}
+
+// ValidateCPF checks a Brazilian individual taxpayer number (CPF), formatted
+// (123.456.789-09) or not (12345678909), including both check digits.
+//
+// Example:
+//
+//	if err := validation.ValidateCPF("529.982.247-25"); err != nil {
+//	    log.Fatal(err)
+//	}
+func ValidateCPF(document string) error {
+	if document == "" {
+		return errors.New("CPF cannot be empty")
+	}
+
+	digits := stripDocumentFormatting(document)
+	if !cpfPattern.MatchString(digits) {
+		return fmt.Errorf("invalid CPF format: %s (must have 11 digits)", document)
+	}
+
+	if strings.Count(digits, digits[:1]) == len(digits) {
+		return fmt.Errorf("invalid CPF: %s (repeated digits are not allowed)", document)
+	}
+
+	values := documentValues(digits)
+
+	if cpfCheckDigit(values[:9]) != values[9] || cpfCheckDigit(values[:10]) != values[10] {
+		return fmt.Errorf("invalid CPF: %s (check digits do not match)", document)
+	}
+
+	return nil
+}
+
+// ValidateCNPJ checks a Brazilian company registration number (CNPJ), formatted
+// (11.222.333/0001-81) or not (11222333000181), including both check digits.
+// Alphanumeric CNPJs are accepted.
+//
+// Example:
+//
+//	if err := validation.ValidateCNPJ("11.222.333/0001-81"); err != nil {
+//	    log.Fatal(err)
+//	}
+func ValidateCNPJ(document string) error {
+	if document == "" {
+		return errors.New("CNPJ cannot be empty")
+	}
+
+	digits := strings.ToUpper(stripDocumentFormatting(document))
+	if !cnpjPattern.MatchString(digits) {
+		return fmt.Errorf("invalid CNPJ format: %s (must have 14 characters)", document)
+	}
+
+	if strings.Count(digits, digits[:1]) == len(digits) {
+		return fmt.Errorf("invalid CNPJ: %s (repeated digits are not allowed)", document)
+	}
+
+	values := documentValues(digits)
+
+	if cnpjCheckDigit(values[:12]) != values[12] || cnpjCheckDigit(values[:13]) != values[13] {
+		return fmt.Errorf("invalid CNPJ: %s (check digits do not match)", document)
+	}
+
+	return nil
+}
+
+// ValidateBrazilianDocument validates a CPF or a CNPJ, choosing by length.
+func ValidateBrazilianDocument(document string) error {
+	if document == "" {
+		return errors.New("document cannot be empty")
+	}
+
+	switch len(stripDocumentFormatting(document)) {
+	case 11:
+		return ValidateCPF(document)
+	case 14:
+		return ValidateCNPJ(document)
+	default:
+		return fmt.Errorf("invalid Brazilian document: %s (must be a CPF with 11 digits or a CNPJ with 14 characters)", document)
+	}
+}
+
+// ValidateEIN checks the format of a US Employer Identification Number (NN-NNNNNNN).
+// The hyphen is optional.
+//
+// Example:
+//
+//	if err := validation.ValidateEIN("12-3456789"); err != nil {
+//	    log.Fatal(err)
+//	}
+func ValidateEIN(document string) error {
+	if document == "" {
+		return errors.New("EIN cannot be empty")
+	}
+
+	if !einPattern.MatchString(document) {
+		return fmt.Errorf("invalid EIN format: %s (must be NN-NNNNNNN)", document)
+	}
+
+	if strings.HasPrefix(document, "00") {
+		return fmt.Errorf("invalid EIN: %s (prefix 00 is not assigned)", document)
+	}
+
+	return nil
+}
+
+// ValidateLegalDocument validates a legal document number using the rules of the
+// given ISO 3166-1 alpha-2 country: CPF/CNPJ for BR and EIN for US. Countries
+// without a registered validator are accepted as long as the document is not empty.
+//
+// Example:
+//
+//	if err := validation.ValidateLegalDocument("BR", input.LegalDocument); err != nil {
+//	    return err
+//	}
+func ValidateLegalDocument(country, document string) error {
+	if strings.TrimSpace(document) == "" {
+		return errors.New("legal document cannot be empty")
+	}
+
+	validator, ok := documentValidators[strings.ToUpper(strings.TrimSpace(country))]
+	if !ok {
+		return nil
+	}
+
+	return validator(document)
+}
+
+// HasDocumentValidator reports whether a legal document validator exists for the country.
+func HasDocumentValidator(country string) bool {
+	_, ok := documentValidators[strings.ToUpper(strings.TrimSpace(country))]
+	return ok
+}
+
+// EnhancedValidateLegalDocument validates a legal document for a country and returns
+// a field-level error with suggestions when invalid.
+func EnhancedValidateLegalDocument(country, document string) *FieldError {
+	err := ValidateLegalDocument(country, document)
+	if err == nil {
+		return nil
+	}
+
+	fieldErr := BuildFieldError("legalDocument", document, err.Error())
+
+	if strings.TrimSpace(document) == "" {
+		return fieldErr.WithConstraint("required").
+			WithSuggestions(GetCommonSuggestions("legalDocument", document, Required)...)
+	}
+
+	switch strings.ToUpper(strings.TrimSpace(country)) {
+	case "BR":
+		fieldErr.WithSuggestions(
+			"Use a CPF (NNN.NNN.NNN-NN) for individuals or a CNPJ (NN.NNN.NNN/NNNN-NN) for companies",
+			"Check the last two digits, they are computed from the others",
+		)
+	case "US":
+		fieldErr.WithSuggestions("Use the EIN format NN-NNNNNNN (e.g., '12-3456789')")
+	}
+
+	return fieldErr.WithConstraint("format")
+}
+
+// documentValues converts document characters to their check-digit values
+// (digits map to 0-9, letters to their ASCII code minus 48).
+func documentValues(document string) []int {
+	values := make([]int, len(document))
+	for i := 0; i < len(document); i++ {
+		values[i] = int(document[i] - '0')
+	}
+
+	return values
+}
+
+// cpfCheckDigit computes the next CPF check digit for the given values.
+func cpfCheckDigit(values []int) int {
+	sum := 0
+	weight := len(values) + 1
+
+	for _, v := range values {
+		sum += v * weight
+		weight--
+	}
+
+	mod := (sum * 10) % 11
+	if mod == 10 {
+		return 0
+	}
+
+	return mod
+}
+
+// cnpjCheckDigit computes the next CNPJ check digit for the given values.
+func cnpjCheckDigit(values []int) int {
+	sum := 0
+	weight := 2
+
+	for i := len(values) - 1; i >= 0; i-- {
+		sum += values[i] * weight
+
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+
+	mod := sum % 11
+	if mod < 2 {
+		return 0
+	}
+
+	return 11 - mod
+}
@@ -0,0 +1,135 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCPF(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		wantErr  bool
+	}{
+		{"valid formatted", "529.982.247-25", false},
+		{"valid unformatted", "52998224725", false},
+		{"wrong check digit", "529.982.247-26", true},
+		{"repeated digits", "111.111.111-11", true},
+		{"too short", "5299822472", true},
+		{"letters", "5299822472A", true},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCPF(tt.document)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateCNPJ(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		wantErr  bool
+	}{
+		{"valid formatted", "11.222.333/0001-81", false},
+		{"valid unformatted", "11222333000181", false},
+		{"valid alphanumeric", "12.ABC.345/01DE-35", false},
+		{"valid alphanumeric lower case", "12abc34501de35", false},
+		{"wrong check digit", "11.222.333/0001-82", true},
+		{"repeated digits", "00000000000000", true},
+		{"letter in check digits", "12ABC34501DEA5", true},
+		{"too long", "112223330001811", true},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCNPJ(tt.document)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateEIN(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		wantErr  bool
+	}{
+		{"valid with hyphen", "12-3456789", false},
+		{"valid without hyphen", "123456789", false},
+		{"unassigned prefix", "00-1234567", true},
+		{"wrong grouping", "123-456789", true},
+		{"too short", "12-345678", true},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEIN(tt.document)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateLegalDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		country  string
+		document string
+		wantErr  bool
+	}{
+		{"BR CPF", "BR", "529.982.247-25", false},
+		{"BR CNPJ lower case country", "br", "11.222.333/0001-81", false},
+		{"BR invalid length", "BR", "12345", true},
+		{"US EIN", "US", "12-3456789", false},
+		{"US CNPJ rejected", "US", "11.222.333/0001-81", true},
+		{"country without validator", "FR", "FR12345678901", false},
+		{"empty document", "FR", " ", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLegalDocument(tt.country, tt.document)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	assert.True(t, HasDocumentValidator("br"))
+	assert.False(t, HasDocumentValidator("FR"))
+}
+
+func TestEnhancedValidateLegalDocument(t *testing.T) {
+	assert.Nil(t, EnhancedValidateLegalDocument("BR", "11.222.333/0001-81"))
+
+	err := EnhancedValidateLegalDocument("BR", "11.222.333/0001-00")
+	require.NotNil(t, err)
+	assert.Equal(t, "legalDocument", err.Field)
+	assert.Equal(t, "format", err.Constraint)
+	assert.NotEmpty(t, err.Suggestions)
+
+	err = EnhancedValidateLegalDocument("US", "")
+	require.NotNil(t, err)
+	assert.Equal(t, "required", err.Constraint)
+}
@@ -30,21 +30,9 @@ func EnhancedValidateAssetCode(assetCode string) *FieldError {
 }
 
 // EnhancedValidateAccountAlias checks if an account alias is valid and returns field-level errors
-// with suggestions when invalid.
+// with suggestions when invalid. It applies the same grammar as ValidateAccountAlias.
 func EnhancedValidateAccountAlias(alias string) *FieldError {
-	if alias == "" {
-		return BuildFieldError("alias", alias, "Account alias cannot be empty").
-			WithConstraint("required").
-			WithSuggestions(GetCommonSuggestions("alias", alias, Required)...)
-	}
-
-	if !accountAliasPattern.MatchString(alias) {
-		return BuildFieldError("alias", alias, "Invalid account alias format").
-			WithConstraint("format").
-			WithSuggestions(GetCommonSuggestions("alias", alias, Format)...)
-	}
-
-	return nil
+	return checkAccountAlias(alias)
 }
 
 // EnhancedValidateAssetType checks if an asset type is valid and returns field-level errors
@@ -659,7 +647,7 @@ func (v *transactionInputValidator) validateChartOfAccountsGroupName() {
 		return
 	}
 
-	if err := validateChartOfAccountsGroupName(groupName); err != nil {
+	if err := ValidateChartOfAccountsGroupName(groupName); err != nil {
 		v.errors.Add("chartOfAccountsGroupName", groupName, err.Error()).
 			WithConstraint("format").
 			WithSuggestions(
@@ -54,8 +54,10 @@ func TestEnhancedValidateAccountAlias(t *testing.T) {
 		{"Valid alias with hyphen", "savings-account", false},
 		{"Valid alias with numbers", "account123", false},
 		{"Empty alias", "", true},
-		{"Too long alias", "this_is_a_very_long_alias_that_exceeds_the_maximum_allowed_length_for_an_account_alias_in_the_system", true},
-		{"Invalid characters", "savings@account", true},
+		{"Valid alias with at sign", "@savings", false},
+		{"Too long alias", "this_is_a_very_long_alias_that_exceeds_the_maximum_allowed_length_for_an_account_alias_in_the_system_!", true},
+		{"Invalid characters", "savings#account", true},
+		{"Reserved prefix", "@external/BRL", true},
 	}
 
 	for _, tt := range tests {
@@ -129,6 +129,17 @@ func (fe *FieldErrors) Error() string {
 	return builder.String()
 }
 
+// Unwrap returns the field errors in the collection, so errors.As finds a
+// *FieldError and errors.Errors enumerates them.
+func (fe *FieldErrors) Unwrap() []error {
+	errs := make([]error, len(fe.Errors))
+	for i, err := range fe.Errors {
+		errs[i] = err
+	}
+
+	return errs
+}
+
 // GetFieldErrors returns all field errors in the collection
 func (fe *FieldErrors) GetFieldErrors() []*FieldError {
 	return fe.Errors
@@ -399,3 +399,16 @@ func TestWrapError(t *testing.T) {
 		assert.Nil(t, fieldErr.Value)
 	})
 }
+
+func TestFieldErrors_Unwrap(t *testing.T) {
+	fieldErrors := NewFieldErrors()
+	fieldErrors.Add("amount", "-1", "must be positive")
+	fieldErrors.Add("asset", "", "is required")
+
+	var err error = fieldErrors
+
+	var fieldErr *FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "amount", fieldErr.Field)
+	assert.True(t, errors.Is(err, fieldErrors.Errors[1]))
+}
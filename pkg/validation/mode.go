@@ -0,0 +1,50 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mode selects how the SDK handles requests failing client-side validation.
+// Moving a service from ModeOff to ModeLenient to ModeStrict tightens
+// validation incrementally: lenient mode surfaces the requests strict mode
+// would reject without breaking them.
+type Mode string
+
+const (
+	// ModeStrict rejects invalid requests before they are sent
+	ModeStrict Mode = "strict"
+
+	// ModeLenient reports invalid requests as warnings and sends them anyway
+	ModeLenient Mode = "lenient"
+
+	// ModeOff skips client-side validation, leaving it to the server
+	ModeOff Mode = "off"
+)
+
+// IsValid reports whether m is one of the known modes.
+func (m Mode) IsValid() bool {
+	switch m {
+	case ModeStrict, ModeLenient, ModeOff:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseMode returns the mode named s, ignoring case and surrounding spaces.
+//
+// Example:
+//
+//	mode, err := validation.ParseMode(os.Getenv("VALIDATION_MODE"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func ParseMode(s string) (Mode, error) {
+	mode := Mode(strings.ToLower(strings.TrimSpace(s)))
+	if !mode.IsValid() {
+		return "", fmt.Errorf("unknown validation mode %q (expected strict, lenient or off)", s)
+	}
+
+	return mode, nil
+}
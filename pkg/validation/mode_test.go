@@ -0,0 +1,30 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Mode
+	}{
+		{"strict", ModeStrict},
+		{" Lenient ", ModeLenient},
+		{"OFF", ModeOff},
+	}
+
+	for _, tt := range tests {
+		mode, err := ParseMode(tt.input)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, mode)
+		assert.True(t, mode.IsValid())
+	}
+
+	_, err := ParseMode("warn")
+	require.Error(t, err)
+	assert.False(t, Mode("").IsValid())
+}
@@ -0,0 +1,137 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule is a declarative constraint over a value of type T, typically spanning
+// several fields of an input or several related entities bundled together.
+//
+// When selects the values the rule applies to (nil means every value) and Check
+// returns a field error describing the violation, or nil if the value satisfies
+// the rule.
+//
+// Example:
+//
+//	requirePortfolio := validation.Rule[*models.CreateAccountInput]{
+//	    Name:        "deposit-requires-portfolio",
+//	    Description: "deposit accounts must belong to a portfolio",
+//	    When: func(in *models.CreateAccountInput) bool {
+//	        return in.Type == "deposit"
+//	    },
+//	    Check: func(in *models.CreateAccountInput) *validation.FieldError {
+//	        if in.PortfolioID == nil || *in.PortfolioID == "" {
+//	            return validation.BuildFieldError("portfolioId", nil, "deposit accounts must have a portfolio")
+//	        }
+//	        return nil
+//	    },
+//	}
+type Rule[T any] struct {
+	// Name identifies the rule; it is copied to FieldError.Code when the rule fails
+	Name string
+
+	// Description is a human-readable summary of the constraint
+	Description string
+
+	// When reports whether the rule applies to a value (optional)
+	When func(T) bool
+
+	// Check validates the value and returns a field error on violation
+	Check func(T) *FieldError
+}
+
+// RuleSet is an ordered collection of rules evaluated together.
+// A RuleSet is not safe for concurrent modification, but Evaluate may be called
+// concurrently once all rules are added.
+type RuleSet[T any] struct {
+	rules []Rule[T]
+}
+
+// NewRuleSet creates a rule set with the given rules.
+func NewRuleSet[T any](rules ...Rule[T]) *RuleSet[T] {
+	set := &RuleSet[T]{}
+	for _, rule := range rules {
+		set.Add(rule)
+	}
+
+	return set
+}
+
+// Add appends a rule to the set and returns the set for chaining.
+// Rules without a Check function are ignored.
+func (s *RuleSet[T]) Add(rule Rule[T]) *RuleSet[T] {
+	if rule.Check != nil {
+		s.rules = append(s.rules, rule)
+	}
+
+	return s
+}
+
+// Rules returns the rules in evaluation order.
+func (s *RuleSet[T]) Rules() []Rule[T] {
+	return append([]Rule[T](nil), s.rules...)
+}
+
+// Len returns the number of rules in the set.
+func (s *RuleSet[T]) Len() int {
+	return len(s.rules)
+}
+
+// Evaluate runs every applicable rule against value and collects the violations.
+// Each failing rule contributes one field error whose Code is the rule name and
+// whose Constraint defaults to "rule" when the check does not set one.
+func (s *RuleSet[T]) Evaluate(value T) *FieldErrors {
+	errs := NewFieldErrors()
+
+	for _, rule := range s.rules {
+		if rule.When != nil && !rule.When(value) {
+			continue
+		}
+
+		fieldErr := rule.Check(value)
+		if fieldErr == nil {
+			continue
+		}
+
+		if fieldErr.Code == "" {
+			fieldErr.Code = rule.Name
+		}
+
+		if fieldErr.Constraint == "" {
+			fieldErr.Constraint = "rule"
+		}
+
+		if fieldErr.Message == "" {
+			fieldErr.Message = ruleViolationMessage(rule)
+		}
+
+		errs.AddError(fieldErr)
+	}
+
+	return errs
+}
+
+// EvaluateAll runs the rule set over every value and returns a report indexed by
+// position, in the same shape as ValidateTransactionBatch, so invalid values can be
+// dropped with DropInvalid.
+func (s *RuleSet[T]) EvaluateAll(values []T) *BatchReport {
+	report := &BatchReport{Total: len(values)}
+
+	for i, value := range values {
+		if errs := s.Evaluate(value); errs.HasErrors() {
+			report.Errors = append(report.Errors, &BatchItemError{Index: i, Errors: errs})
+		}
+	}
+
+	return report
+}
+
+// ruleViolationMessage builds a default message for a failing rule
+func ruleViolationMessage[T any](rule Rule[T]) string {
+	if rule.Description != "" {
+		return fmt.Sprintf("Rule violated: %s", rule.Description)
+	}
+
+	return fmt.Sprintf("Rule %s violated", strings.TrimSpace(rule.Name))
+}
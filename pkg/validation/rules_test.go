@@ -0,0 +1,74 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func depositRequiresPortfolio() validation.Rule[*models.CreateAccountInput] {
+	return validation.Rule[*models.CreateAccountInput]{
+		Name:        "deposit-requires-portfolio",
+		Description: "deposit accounts must belong to a portfolio",
+		When: func(in *models.CreateAccountInput) bool {
+			return in.Type == "deposit"
+		},
+		Check: func(in *models.CreateAccountInput) *validation.FieldError {
+			if in.PortfolioID == nil || *in.PortfolioID == "" {
+				return validation.BuildFieldError("portfolioId", nil, "")
+			}
+
+			return nil
+		},
+	}
+}
+
+func TestRuleSetEvaluate(t *testing.T) {
+	set := validation.NewRuleSet(depositRequiresPortfolio())
+	set.Add(validation.Rule[*models.CreateAccountInput]{Name: "ignored without check"})
+	require.Equal(t, 1, set.Len())
+
+	portfolio := "portfolio-1"
+
+	t.Run("rule does not apply", func(t *testing.T) {
+		errs := set.Evaluate(&models.CreateAccountInput{Type: "savings"})
+		assert.False(t, errs.HasErrors())
+	})
+
+	t.Run("rule satisfied", func(t *testing.T) {
+		input := &models.CreateAccountInput{Type: "deposit"}
+		input.PortfolioID = &portfolio
+
+		errs := set.Evaluate(input)
+		assert.False(t, errs.HasErrors())
+	})
+
+	t.Run("rule violated", func(t *testing.T) {
+		errs := set.Evaluate(&models.CreateAccountInput{Type: "deposit"})
+		require.True(t, errs.HasErrors())
+
+		fieldErr := errs.GetFieldErrors()[0]
+		assert.Equal(t, "portfolioId", fieldErr.Field)
+		assert.Equal(t, "deposit-requires-portfolio", fieldErr.Code)
+		assert.Equal(t, "rule", fieldErr.Constraint)
+		assert.Contains(t, fieldErr.Message, "deposit accounts must belong to a portfolio")
+	})
+}
+
+func TestRuleSetEvaluateAll(t *testing.T) {
+	set := validation.NewRuleSet(depositRequiresPortfolio())
+
+	inputs := []*models.CreateAccountInput{
+		{Type: "deposit"},
+		{Type: "savings"},
+		{Type: "deposit"},
+	}
+
+	report := set.EvaluateAll(inputs)
+	assert.Equal(t, []int{0, 2}, report.InvalidIndices())
+	assert.Len(t, validation.DropInvalid(inputs, report), 1)
+	assert.Len(t, set.Rules(), 1)
+}
@@ -94,8 +94,8 @@ func getAssetCodeSuggestions(_ any) []string {
 
 func getAliasSuggestions(value any) []string {
 	return []string{
-		"Use alphanumeric characters with optional underscores or hyphens",
-		"Ensure length is between 1-50 characters",
+		"Use alphanumeric characters with optional '@', ':', underscores or hyphens",
+		"Ensure length is between 1-100 characters",
 		fmt.Sprintf("Current value '%v' may contain invalid characters", value),
 	}
 }
@@ -6,6 +6,7 @@
 // - Account alias and type validation
 // - Metadata validation
 // - Address validation
+// - Legal document validation (CPF, CNPJ, EIN)
 // - Date range validation
 //
 // These utilities help ensure that data is valid before sending it to the API,
@@ -31,12 +32,14 @@ package validation
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/validation/core"
-	midazutils "github.com/LerianStudio/midaz/v3/pkg/utils"
 )
 
 // Validator is a configurable validation instance that can be used to perform validations
@@ -252,21 +255,23 @@ func ValidateAssetCode(assetCode string) error {
 	return nil
 }
 
-// ValidateAccountAlias checks if an account alias is valid.
-// Account aliases should be alphanumeric with optional underscores and hyphens.
+// ValidateAccountAlias checks an account alias against the server's alias grammar
+// before it is sent to the API. Aliases must be 1-100 characters drawn from
+// letters, digits, '@', ':', '_' and '-', must not contain whitespace and must not
+// use the reserved '@external/' prefix.
+//
+// The returned error is a *FieldError whose Constraint identifies the violated
+// rule ("required", "whitespace", "max", "reserved" or "format") and whose
+// Suggestions describe how to fix the alias.
 //
 // Example:
 //
-//	if err := validation.ValidateAccountAlias("savings_account"); err != nil {
+//	if err := validation.ValidateAccountAlias("@treasury_checking"); err != nil {
 //	    log.Fatal(err)
 //	}
 func ValidateAccountAlias(alias string) error {
-	if alias == "" {
-		return errors.New("account alias cannot be empty")
-	}
-
-	if !accountAliasPattern.MatchString(alias) {
-		return fmt.Errorf("invalid account alias format: %s (must be alphanumeric with optional underscores and hyphens, max 50 chars)", alias)
+	if err := checkAccountAlias(alias); err != nil {
+		return err
 	}
 
 	return nil
@@ -311,12 +316,16 @@ func ValidateMetadata(metadata map[string]any) error {
 }
 
 // ValidateMetadata checks if transaction metadata is valid using this validator's configuration.
-// This method verifies that metadata values are of supported types.
+// This method verifies the number of keys, the keys, and that metadata values are of supported types.
 func (v *Validator) ValidateMetadata(metadata map[string]any) error {
 	if metadata == nil {
 		return nil
 	}
 
+	if err := v.validateMetadataKeyCount(metadata); err != nil {
+		return err
+	}
+
 	// Validate metadata keys and values
 	for key, value := range metadata {
 		if err := v.validateMetadataKey(key); err != nil {
@@ -332,14 +341,62 @@ func (v *Validator) ValidateMetadata(metadata map[string]any) error {
 	return v.validateMetadataSize(metadata)
 }
 
+// ValidateMetadataFields checks metadata like ValidateMetadata, but reports
+// every violation as a field error whose path starts with field, such as
+// "metadata.notes" for an oversized value of the "notes" key.
+//
+// Example:
+//
+//	validator, _ := validation.NewValidator(core.WithBackendMetadataLimits(), core.WithMaxMetadataKeys(20))
+//	if errs := validator.ValidateMetadataFields("metadata", metadata); errs.HasErrors() {
+//	    log.Fatal(errs)
+//	}
+func (v *Validator) ValidateMetadataFields(field string, metadata map[string]any) *FieldErrors {
+	fieldErrs := NewFieldErrors()
+
+	if metadata == nil {
+		return fieldErrs
+	}
+
+	if err := v.validateMetadataKeyCount(metadata); err != nil {
+		fieldErrs.Add(field, len(metadata), err.Error()).WithConstraint("maxKeys")
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(metadata)) {
+		if err := v.validateMetadataKey(key); err != nil {
+			fieldErrs.Add(field+"."+key, key, err.Error()).WithConstraint("key")
+			continue
+		}
+
+		if err := v.validateMetadataValue(key, metadata[key]); err != nil {
+			fieldErrs.Add(field+"."+key, metadata[key], err.Error()).WithConstraint("value")
+		}
+	}
+
+	if err := v.validateMetadataSize(metadata); err != nil {
+		fieldErrs.Add(field, nil, err.Error()).WithConstraint("maxSize")
+	}
+
+	return fieldErrs
+}
+
+// validateMetadataKeyCount validates the number of metadata keys
+func (v *Validator) validateMetadataKeyCount(metadata map[string]any) error {
+	if v.config.MaxMetadataKeys > 0 && len(metadata) > v.config.MaxMetadataKeys {
+		return fmt.Errorf("metadata has %d keys, exceeding the maximum of %d", len(metadata), v.config.MaxMetadataKeys)
+	}
+
+	return nil
+}
+
 // validateMetadataKey validates a single metadata key
-func (*Validator) validateMetadataKey(key string) error {
+func (v *Validator) validateMetadataKey(key string) error {
 	if key == "" {
 		return errors.New("metadata key cannot be empty")
 	}
 
-	if len(key) > 64 {
-		return fmt.Errorf("metadata key '%s' exceeds maximum length of 64 characters", key)
+	if utf8.RuneCountInString(key) > v.config.MaxMetadataKeyLength {
+		return fmt.Errorf("metadata key '%s' exceeds maximum length of %d characters", key, v.config.MaxMetadataKeyLength)
 	}
 
 	return nil
@@ -347,6 +404,10 @@ func (*Validator) validateMetadataKey(key string) error {
 
 // validateMetadataValue validates a single metadata value
 func (v *Validator) validateMetadataValue(key string, value any) error {
+	if v.config.BackendMetadataValues {
+		return v.validateBackendMetadataValue(key, value)
+	}
+
 	// Validate value type
 	if !v.isValidMetadataValueType(value) {
 		return fmt.Errorf("metadata value for key '%s' has unsupported type: %T (supported types: string, bool, int, float64, nil)", key, value)
@@ -378,8 +439,36 @@ func (v *Validator) validateMetadataValue(key string, value any) error {
 	return nil
 }
 
+// validateBackendMetadataValue validates a single metadata value with the
+// rules of the Midaz backend: scalars and arrays of them, with strings of
+// bounded length.
+func (v *Validator) validateBackendMetadataValue(key string, value any) error {
+	switch value := value.(type) {
+	case string:
+		if len(value) > v.config.MaxStringLength {
+			return fmt.Errorf("metadata string value for key '%s' exceeds maximum length of %d characters",
+				key, v.config.MaxStringLength)
+		}
+	case bool, int, int64, float32, float64, nil:
+	case []any:
+		for _, item := range value {
+			if err := v.validateBackendMetadataValue(key, item); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("metadata value for key '%s' has unsupported type: %T (supported types: string, bool, number, nil and arrays of them)", key, value)
+	}
+
+	return nil
+}
+
 // validateMetadataSize validates the total size of metadata
 func (v *Validator) validateMetadataSize(metadata map[string]any) error {
+	if v.config.MaxMetadataSize <= 0 {
+		return nil
+	}
+
 	totalSize := 0
 	for key, value := range metadata {
 		totalSize += len(key)
@@ -605,8 +694,15 @@ func validateOperation(op map[string]any, index int, transactionAssetCode string
 	return opErrors, valid
 }
 
-// validateChartOfAccountsGroupName validates the chart of accounts group name
-func validateChartOfAccountsGroupName(name string) error {
+// ValidateChartOfAccountsGroupName checks that a chart of accounts group name
+// is at most 100 alphanumeric, space, underscore or hyphen characters.
+//
+// Example:
+//
+//	if err := validation.ValidateChartOfAccountsGroupName("transfer-transactions"); err != nil {
+//	    log.Fatal(err)
+//	}
+func ValidateChartOfAccountsGroupName(name string) error {
 	if name == "" {
 		return errors.New("chart of accounts group name cannot be empty")
 	}
@@ -894,7 +990,7 @@ func validateChartOfAccountsGroupNameField(summary *Summary, input map[string]an
 		return
 	}
 
-	if err := validateChartOfAccountsGroupName(groupName); err != nil {
+	if err := ValidateChartOfAccountsGroupName(groupName); err != nil {
 		summary.AddError(err)
 	}
 }
@@ -919,69 +1015,23 @@ func validateMetadataField(summary *Summary, input map[string]any) {
 // ValidateAssetType validates if the asset type is one of the supported types
 // in the Midaz system.
 func ValidateAssetType(assetType string) error {
-	if assetType == "" {
-		return errors.New("asset type is required")
-	}
-
-	// Use commons.ValidateType to ensure consistency with backend APIs
-	// Note: commons.ValidateType expects lowercase types, so we convert to lowercase
-	if err := midazutils.ValidateType(strings.ToLower(assetType)); err != nil {
-		// Create a list of valid types for the error message
-		validTypes := []string{"crypto", "currency", "commodity", "others"}
-
-		return fmt.Errorf("invalid asset type: %s. Valid types are: %s",
-			assetType, strings.Join(validTypes, ", "))
-	}
-
-	return nil
+	return core.ValidateAssetType(assetType)
 }
 
 // ValidateAccountType validates if the account type is one of the supported types
 // in the Midaz system.
 func ValidateAccountType(accountType string) error {
-	if accountType == "" {
-		return errors.New("account type is required")
-	}
-
-	// Use commons.ValidateAccountType to ensure consistency with backend APIs
-	if err := midazutils.ValidateAccountType(accountType); err != nil {
-		// Convert the error to a more user-friendly message
-		// Create a list of valid types for the error message
-		validTypes := []string{"deposit", "savings", "loans", "marketplace", "creditCard"}
-
-		return fmt.Errorf("invalid account type: %s. Valid types are: %s",
-			accountType, strings.Join(validTypes, ", "))
-	}
-
-	return nil
+	return core.ValidateAccountType(accountType)
 }
 
 // ValidateCurrencyCode checks if the currency code is valid according to ISO 4217.
 func ValidateCurrencyCode(code string) error {
-	if code == "" {
-		return errors.New("currency code cannot be empty")
-	}
-
-	// Use commons.ValidateCurrency to ensure consistency with backend APIs
-	if err := midazutils.ValidateCurrency(code); err != nil {
-		return fmt.Errorf("invalid currency code: %s", code)
-	}
-
-	return nil
+	return core.ValidateCurrencyCode(code)
 }
 
 // ValidateCountryCode checks if the country code is valid according to ISO 3166-1 alpha-2.
 func ValidateCountryCode(code string) error {
-	if code == "" {
-		return errors.New("country code cannot be empty")
-	}
-
-	// Use commons.ValidateCountryAddress to ensure consistency with backend APIs
-	if err := midazutils.ValidateCountryAddress(code); err != nil {
-		return fmt.Errorf("invalid country code: %s (must be a valid ISO 3166-1 alpha-2 code)", code)
-	}
-
-	return nil
+	return core.ValidateCountryCode(code)
 }
 
 // Address is a simplified address structure for validation purposes.
@@ -1,6 +1,7 @@
 package validation_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -304,16 +305,18 @@ func TestValidateAssetCode(t *testing.T) {
 
 func TestValidateAccountAlias(t *testing.T) {
 	testCases := []struct {
-		name          string
-		alias         string
-		expectedError bool
-		errorContains string
+		name               string
+		alias              string
+		expectedError      bool
+		errorContains      string
+		expectedConstraint string
 	}{
 		{
-			name:          "Empty alias",
-			alias:         "",
-			expectedError: true,
-			errorContains: "account alias cannot be empty",
+			name:               "Empty alias",
+			alias:              "",
+			expectedError:      true,
+			errorContains:      "Account alias cannot be empty",
+			expectedConstraint: "required",
 		},
 		{
 			name:          "Valid alphanumeric alias",
@@ -336,22 +339,56 @@ func TestValidateAccountAlias(t *testing.T) {
 			expectedError: false,
 		},
 		{
-			name:          "Invalid alias with space",
-			alias:         "savings account",
-			expectedError: true,
-			errorContains: "invalid account alias format",
+			name:          "Valid alias with at sign and colon",
+			alias:         "@treasury:checking",
+			expectedError: false,
 		},
 		{
-			name:          "Invalid alias with special character",
-			alias:         "savings@account",
-			expectedError: true,
-			errorContains: "invalid account alias format",
+			name:          "Valid alias at maximum length",
+			alias:         strings.Repeat("a", validation.MaxAccountAliasLength),
+			expectedError: false,
 		},
 		{
-			name:          "Too long alias",
-			alias:         "this_is_a_very_long_account_alias_that_exceeds_the_maximum_allowed_length_of_fifty_characters_which_is_not_allowed",
-			expectedError: true,
-			errorContains: "invalid account alias format",
+			name:               "Invalid alias with space",
+			alias:              "savings account",
+			expectedError:      true,
+			errorContains:      "savings_account",
+			expectedConstraint: "whitespace",
+		},
+		{
+			name:               "Invalid alias with surrounding whitespace",
+			alias:              " savings ",
+			expectedError:      true,
+			errorContains:      "cannot contain whitespace",
+			expectedConstraint: "whitespace",
+		},
+		{
+			name:               "Invalid alias with special character",
+			alias:              "savings#account",
+			expectedError:      true,
+			errorContains:      "Invalid account alias format",
+			expectedConstraint: "format",
+		},
+		{
+			name:               "Reserved external prefix",
+			alias:              "@external/USD",
+			expectedError:      true,
+			errorContains:      "reserved prefix",
+			expectedConstraint: "reserved",
+		},
+		{
+			name:               "Reserved external prefix in any case",
+			alias:              "my@EXTERNAL/usd",
+			expectedError:      true,
+			errorContains:      "reserved prefix",
+			expectedConstraint: "reserved",
+		},
+		{
+			name:               "Too long alias",
+			alias:              strings.Repeat("a", validation.MaxAccountAliasLength+1),
+			expectedError:      true,
+			errorContains:      "maximum is 100",
+			expectedConstraint: "max",
 		},
 	}
 
@@ -364,6 +401,12 @@ func TestValidateAccountAlias(t *testing.T) {
 				if tc.errorContains != "" {
 					assert.Contains(t, err.Error(), tc.errorContains)
 				}
+
+				var fieldErr *validation.FieldError
+				require.ErrorAs(t, err, &fieldErr)
+				assert.Equal(t, "alias", fieldErr.Field)
+				assert.Equal(t, tc.expectedConstraint, fieldErr.Constraint)
+				assert.NotEmpty(t, fieldErr.Suggestions)
 			} else {
 				require.NoError(t, err)
 			}
@@ -371,6 +414,26 @@ func TestValidateAccountAlias(t *testing.T) {
 	}
 }
 
+func TestNormalizeAccountAlias(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"savings", "savings"},
+		{"  Savings  ", "savings"},
+		{"Savings Account", "savings_account"},
+		{"\tMain\n  Cash  Box ", "main_cash_box"},
+		{"@Treasury", "@treasury"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.expected, validation.NormalizeAccountAlias(tc.input))
+		})
+	}
+}
+
 func TestValidateTransactionCode(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -1112,12 +1175,12 @@ func TestValidateCreateTransactionInput(t *testing.T) {
 				"amount":     float64(1000),
 				"scale":      2,
 				"operations": []map[string]any{
-					{"type": "DEBIT", "account_id": "acc1", "account_alias": "invalid@alias", "amount": float64(1000)},
+					{"type": "DEBIT", "account_id": "acc1", "account_alias": "invalid#alias", "amount": float64(1000)},
 					{"type": "CREDIT", "account_id": "acc2", "amount": float64(1000)},
 				},
 			},
 			expectValid: false,
-			errContains: []string{"invalid account alias format"},
+			errContains: []string{"Invalid account alias format"},
 		},
 		{
 			name: "Mismatched operation asset code",
@@ -1765,3 +1828,42 @@ func TestValidatorMetadataNumericRanges(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateMetadataBudget(t *testing.T) {
+	validator, err := validation.NewValidator(core.WithBackendMetadataLimits(), core.WithMaxMetadataKeys(2))
+	require.NoError(t, err)
+
+	valid := map[string]any{
+		strings.Repeat("k", core.BackendMaxMetadataKeyLength): strings.Repeat("v", core.BackendMaxMetadataValueLength),
+		"tags": []any{"a", 1.5, true},
+	}
+	require.NoError(t, validator.ValidateMetadata(valid))
+	assert.False(t, validator.ValidateMetadataFields("metadata", valid).HasErrors())
+
+	err = validator.ValidateMetadata(map[string]any{"a": 1, "b": 2, "c": 3})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metadata has 3 keys, exceeding the maximum of 2")
+
+	fieldErrs := validator.ValidateMetadataFields("metadata", map[string]any{
+		"long": strings.Repeat("v", core.BackendMaxMetadataValueLength+1),
+		strings.Repeat("k", core.BackendMaxMetadataKeyLength+1): "v",
+		"nested": map[string]any{"a": 1},
+	})
+	require.True(t, fieldErrs.HasErrors())
+
+	fields := map[string]string{}
+	for _, fieldErr := range fieldErrs.GetFieldErrors() {
+		fields[fieldErr.Field] = fieldErr.Constraint
+	}
+
+	assert.Equal(t, map[string]string{
+		"metadata": "maxKeys",
+		"metadata." + strings.Repeat("k", core.BackendMaxMetadataKeyLength+1): "key",
+		"metadata.long":   "value",
+		"metadata.nested": "value",
+	}, fields)
+
+	// The default validator keeps its stricter limits
+	require.Error(t, validation.ValidateMetadata(map[string]any{"tags": []any{"a"}}))
+	require.Error(t, validation.ValidateMetadata(map[string]any{strings.Repeat("k", 65): "v"}))
+}
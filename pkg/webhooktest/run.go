@@ -0,0 +1,392 @@
+package webhooktest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/idgen"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/stats"
+)
+
+// Defaults of Options.
+const (
+	DefaultEvents        = 100
+	DefaultConcurrency   = 10
+	DefaultDuplicateRate = 0.1
+	DefaultTimeout       = 10 * time.Second
+)
+
+// Options configures Run.
+type Options struct {
+	// Secret is the key of the default HMACSigner. Required without Signer
+	Secret []byte
+	// Signer signs each delivery
+	// Default is HMACSigner(Secret)
+	Signer Signer
+	// Events is the number of distinct events to send
+	// Default is DefaultEvents
+	Events int
+	// Rate is the number of deliveries per second
+	// Default is 0 (as fast as Concurrency allows)
+	Rate float64
+	// Concurrency is the number of deliveries in flight
+	// Default is DefaultConcurrency
+	Concurrency int
+	// DuplicateRate is the share of events delivered a second time, shortly
+	// after the first, possibly while it is still in flight. A negative rate
+	// sends no duplicate
+	// Default is DefaultDuplicateRate
+	DuplicateRate float64
+	// Seed makes the choice of the duplicated events reproducible
+	Seed uint64
+	// Types are the types of the sample events, sent in turn
+	// Default is EventTypes
+	Types []EventType
+	// OrganizationID and LedgerID are set on the sample events
+	// Default is a random ID each
+	OrganizationID string
+	LedgerID       string
+	// Build returns the seq-th event to send, instead of a sample event, e.g.
+	// to replay events recorded in another environment
+	Build func(seq int) Event
+	// Applied returns how many times the handler applied an event, e.g. from
+	// the side effects it recorded. When set, every event acknowledged must
+	// have been applied exactly once
+	Applied func(eventID string) int
+	// HTTPClient sends the deliveries
+	// Default is an http.Client with a DefaultTimeout timeout
+	HTTPClient *http.Client
+}
+
+// Report is the outcome of Run.
+type Report struct {
+	// Events is the number of distinct events sent
+	Events int
+	// Deliveries is the number of requests sent, duplicates included
+	Deliveries int
+	// Duplicates is the number of second deliveries of an event
+	Duplicates int
+	// Acknowledged is the number of deliveries answered with a 2xx status
+	Acknowledged int
+	// StatusCodes counts the deliveries by response status
+	StatusCodes map[int]int
+	// Failures are the deliveries not acknowledged, in completion order
+	Failures []Failure
+	// Violations are the events not applied exactly once, see Options.Applied
+	Violations []Violation
+	// Latency holds the response times of the handler, in microseconds
+	Latency *stats.Histogram
+	// Elapsed is the duration of the run
+	Elapsed time.Duration
+}
+
+// Failure is a delivery the handler didn't acknowledge.
+type Failure struct {
+	EventID   string
+	Type      EventType
+	Duplicate bool
+	// StatusCode is the response status, zero when no response was received
+	StatusCode int
+	Err        error
+}
+
+// Error implements the error interface.
+func (f Failure) Error() string {
+	kind := "delivery"
+	if f.Duplicate {
+		kind = "duplicate delivery"
+	}
+
+	if f.Err != nil {
+		return fmt.Sprintf("%s of %s event %s failed: %v", kind, f.Type, f.EventID, f.Err)
+	}
+
+	return fmt.Sprintf("%s of %s event %s returned status %d", kind, f.Type, f.EventID, f.StatusCode)
+}
+
+// Violation is an event the handler applied other than once.
+type Violation struct {
+	EventID string
+	Type    EventType
+	Applied int
+}
+
+// Error implements the error interface.
+func (v Violation) Error() string {
+	return fmt.Sprintf("%s event %s applied %d times", v.Type, v.EventID, v.Applied)
+}
+
+// LatencyAt returns the response time below which the given percentage of
+// the deliveries were answered, e.g. 95.
+func (r *Report) LatencyAt(percentile float64) time.Duration {
+	return time.Duration(r.Latency.ValueAtPercentile(percentile)) * time.Microsecond
+}
+
+// Rate returns the number of deliveries per second achieved.
+func (r *Report) Rate() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+
+	return float64(r.Deliveries) / r.Elapsed.Seconds()
+}
+
+// OK reports whether every delivery was acknowledged and every event applied once.
+func (r *Report) OK() bool {
+	return len(r.Failures) == 0 && len(r.Violations) == 0
+}
+
+// Check returns the failures and violations of the run, and an error if the
+// p95 response time exceeds p95Budget, unless p95Budget is zero.
+func (r *Report) Check(p95Budget time.Duration) error {
+	errs := make([]error, 0, len(r.Failures)+len(r.Violations)+1)
+
+	for _, f := range r.Failures {
+		errs = append(errs, f)
+	}
+
+	for _, v := range r.Violations {
+		errs = append(errs, v)
+	}
+
+	if p95 := r.LatencyAt(95); p95Budget > 0 && p95 > p95Budget {
+		errs = append(errs, fmt.Errorf("p95 response time %s exceeds budget %s", p95, p95Budget))
+	}
+
+	return errors.Join(errs...)
+}
+
+// delivery is a request of the run.
+type delivery struct {
+	event     *Event
+	body      []byte
+	duplicate bool
+	order     float64
+}
+
+// Run sends opts.Events events to the webhook handler at url and reports how
+// it handled them. Cancelling ctx stops the run, returning the report of the
+// deliveries made with the error of ctx.
+func Run(ctx context.Context, url string, opts *Options) (*Report, error) {
+	o, err := normalizeOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, events, err := planDeliveries(o)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Events: len(events), StatusCodes: make(map[int]int), Latency: stats.NewLatencyHistogram()}
+	acknowledged := make(map[string]bool, len(events))
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, o.Concurrency)
+	)
+
+	var tick <-chan time.Time
+
+	if o.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / o.Rate))
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	started := time.Now()
+
+	for _, d := range plan {
+		if tick != nil {
+			select {
+			case <-ctx.Done():
+			case <-tick:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+
+			status, latency, err := deliver(ctx, o, url, d)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Deliveries++
+			if d.duplicate {
+				report.Duplicates++
+			}
+
+			if status != 0 {
+				report.StatusCodes[status]++
+				report.Latency.RecordDuration(latency)
+			}
+
+			if err == nil && status >= 200 && status < 300 {
+				report.Acknowledged++
+				acknowledged[d.event.ID] = true
+
+				return
+			}
+
+			report.Failures = append(report.Failures, Failure{
+				EventID: d.event.ID, Type: d.event.Type, Duplicate: d.duplicate, StatusCode: status, Err: err,
+			})
+		})
+	}
+
+	wg.Wait()
+
+	report.Elapsed = time.Since(started)
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	if o.Applied != nil {
+		for _, e := range events {
+			applied := o.Applied(e.ID)
+			if applied > 1 || (acknowledged[e.ID] && applied != 1) {
+				report.Violations = append(report.Violations, Violation{EventID: e.ID, Type: e.Type, Applied: applied})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// normalizeOptions returns a copy of opts with the defaults set.
+func normalizeOptions(opts *Options) (Options, error) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Signer == nil {
+		if len(o.Secret) == 0 {
+			return o, errors.New("webhook secret or signer is required")
+		}
+
+		o.Signer = HMACSigner(o.Secret)
+	}
+
+	if o.Events <= 0 {
+		o.Events = DefaultEvents
+	}
+
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultConcurrency
+	}
+
+	if o.DuplicateRate == 0 {
+		o.DuplicateRate = DefaultDuplicateRate
+	}
+
+	if len(o.Types) == 0 {
+		o.Types = EventTypes
+	}
+
+	if o.OrganizationID == "" {
+		o.OrganizationID = idgen.New()
+	}
+
+	if o.LedgerID == "" {
+		o.LedgerID = idgen.New()
+	}
+
+	if o.HTTPClient == nil {
+		o.HTTPClient = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	return o, nil
+}
+
+// planDeliveries builds the events and the order of their deliveries. A
+// duplicate follows its event within the next Concurrency deliveries.
+func planDeliveries(o Options) ([]delivery, []*Event, error) {
+	rng := rand.New(rand.NewPCG(o.Seed, o.Seed)) //nolint:gosec // test traffic, not security sensitive
+
+	events := make([]*Event, o.Events)
+	plan := make([]delivery, 0, o.Events)
+
+	for i := range o.Events {
+		var e Event
+		if o.Build != nil {
+			e = o.Build(i)
+		} else {
+			e = SampleEvent(o.Types[i%len(o.Types)], o.OrganizationID, o.LedgerID, i)
+		}
+
+		body, err := json.Marshal(e)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode event %s: %w", e.ID, err)
+		}
+
+		events[i] = &e
+		plan = append(plan, delivery{event: &e, body: body, order: float64(i)})
+
+		if rng.Float64() < o.DuplicateRate {
+			plan = append(plan, delivery{event: &e, body: body, duplicate: true, order: float64(i) + 1 + rng.Float64()*float64(o.Concurrency)})
+		}
+	}
+
+	slices.SortStableFunc(plan, func(a, b delivery) int {
+		switch {
+		case a.order < b.order:
+			return -1
+		case a.order > b.order:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return plan, events, nil
+}
+
+// deliver posts a delivery, returning the response status and time.
+func deliver(ctx context.Context, o Options, url string, d delivery) (int, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(d.body))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, d.event.ID)
+	req.Header.Set(HeaderEventType, string(d.event.Type))
+	o.Signer(req.Header, d.body, time.Now())
+
+	started := time.Now()
+
+	resp, err := o.HTTPClient.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+
+	latency := time.Since(started)
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	return resp.StatusCode, latency, nil
+}
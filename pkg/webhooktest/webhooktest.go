@@ -0,0 +1,206 @@
+// Package webhooktest exercises the webhook handler of an integration before
+// production traffic exists.
+//
+// Run synthesizes typed sample events, such as a transaction created or a
+// balance updated, signs them and posts them at a configurable rate to a
+// local handler. Some events are delivered twice, as a sender retrying after
+// a timeout would, and the report tells whether the handler acknowledged
+// every delivery, applied each event once and answered within its latency
+// budget:
+//
+//	applied := map[string]int{} // counted by the handler under test
+//
+//	report, err := webhooktest.Run(ctx, "http://localhost:8080/webhooks", &webhooktest.Options{
+//	    Secret:  []byte(os.Getenv("WEBHOOK_SECRET")),
+//	    Events:  500,
+//	    Rate:    50,
+//	    Applied: func(eventID string) int { return applied[eventID] },
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	if err := report.Check(200 * time.Millisecond); err != nil {
+//	    log.Fatalf("webhook handler not ready: %v", err)
+//	}
+//
+// Events are signed with HMAC-SHA256 over the timestamp and the body, see
+// Sign. Handlers can check the signature with VerifySignature; set
+// Options.Signer to match a handler verifying another scheme.
+package webhooktest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LerianStudio/midaz-sdk-golang/v2/models"
+	"github.com/LerianStudio/midaz-sdk-golang/v2/pkg/idgen"
+	"github.com/shopspring/decimal"
+)
+
+// EventType identifies the kind of a sample event.
+type EventType string
+
+// Sample event types.
+const (
+	// TransactionCreated carries a models.Transaction
+	TransactionCreated EventType = "transaction.created"
+	// AccountCreated carries a models.Account
+	AccountCreated EventType = "account.created"
+	// BalanceUpdated carries a models.Balance
+	BalanceUpdated EventType = "balance.updated"
+)
+
+// EventTypes are the sample event types, in the order Run cycles through them.
+var EventTypes = []EventType{TransactionCreated, AccountCreated, BalanceUpdated}
+
+// Headers set on every delivery by Run.
+const (
+	// HeaderSignature carries the signature of the delivery, see Sign
+	HeaderSignature = "X-Webhook-Signature"
+	// HeaderEventID carries the ID of the event, the same on every delivery of an event
+	HeaderEventID = "X-Webhook-Id"
+	// HeaderEventType carries the type of the event
+	HeaderEventType = "X-Webhook-Event"
+)
+
+// ErrInvalidSignature is returned by VerifySignature for a signature that
+// doesn't match the body or has expired.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Event is a webhook event sent to the handler under test.
+type Event struct {
+	// ID identifies the event; handlers deduplicate deliveries by it
+	ID             string    `json:"id"`
+	Type           EventType `json:"type"`
+	OccurredAt     time.Time `json:"occurredAt"`
+	OrganizationID string    `json:"organizationId"`
+	LedgerID       string    `json:"ledgerId"`
+	// Data is the resource the event is about, such as a *models.Transaction
+	Data any `json:"data"`
+}
+
+// SampleEvent returns an event of type t with sample data in the given
+// organization and ledger. seq varies the accounts and amounts of the data.
+func SampleEvent(t EventType, orgID, ledgerID string, seq int) Event {
+	now := time.Now().UTC()
+	alias := fmt.Sprintf("@customer_%d", seq%10)
+	amount := decimal.NewFromInt(int64(seq%100+1) * 100)
+
+	var data any
+
+	switch t {
+	case TransactionCreated:
+		data = &models.Transaction{
+			ID:             idgen.New(),
+			Amount:         amount.String(),
+			AssetCode:      "USD",
+			Status:         models.NewStatus(models.TransactionStatusCompleted),
+			Source:         []string{"@external/USD"},
+			Destination:    []string{alias},
+			OrganizationID: orgID,
+			LedgerID:       ledgerID,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+	case AccountCreated:
+		data = &models.Account{
+			ID:             idgen.New(),
+			Name:           "Customer " + strconv.Itoa(seq%10),
+			AssetCode:      "USD",
+			OrganizationID: orgID,
+			LedgerID:       ledgerID,
+			Status:         models.NewStatus(models.StatusActive),
+			Alias:          &alias,
+			Type:           "deposit",
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+	case BalanceUpdated:
+		data = &models.Balance{
+			ID:             idgen.New(),
+			OrganizationID: orgID,
+			LedgerID:       ledgerID,
+			AccountID:      idgen.New(),
+			Alias:          alias,
+			Key:            "default",
+			AssetCode:      "USD",
+			Available:      amount,
+			OnHold:         decimal.Zero,
+			Version:        int64(seq + 1),
+			AccountType:    "deposit",
+			AllowSending:   true,
+			AllowReceiving: true,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+	}
+
+	return Event{ID: idgen.New(), Type: t, OccurredAt: now, OrganizationID: orgID, LedgerID: ledgerID, Data: data}
+}
+
+// Signer signs the body of a delivery, setting its headers.
+type Signer func(header http.Header, body []byte, at time.Time)
+
+// HMACSigner returns the default Signer, setting HeaderSignature to
+// Sign(secret, at, body).
+func HMACSigner(secret []byte) Signer {
+	return func(header http.Header, body []byte, at time.Time) {
+		header.Set(HeaderSignature, Sign(secret, at, body))
+	}
+}
+
+// Sign returns the signature of a body sent at the given time, in the form
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<body>">".
+func Sign(secret []byte, at time.Time, body []byte) string {
+	ts := strconv.FormatInt(at.Unix(), 10)
+
+	return "t=" + ts + ",v1=" + signature(secret, ts, body)
+}
+
+// VerifySignature checks a signature made by Sign, rejecting those older
+// than tolerance, unless tolerance is zero.
+func VerifySignature(secret []byte, header string, body []byte, tolerance time.Duration) error {
+	var ts, sig string
+
+	for part := range strings.SplitSeq(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sig = value
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 && time.Since(time.Unix(unix, 0)).Abs() > tolerance {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(signature(secret, ts, body)), []byte(sig)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func signature(secret []byte, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,147 @@
+package webhooktest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// handler is a webhook handler under test, verifying signatures and counting
+// the events it applies.
+type handler struct {
+	secret     []byte
+	idempotent bool
+
+	mu      sync.Mutex
+	seen    map[string]bool
+	applied map[string]int
+	types   map[EventType]int
+}
+
+func newHandler(secret string, idempotent bool) *handler {
+	return &handler{secret: []byte(secret), idempotent: idempotent, seen: map[string]bool{}, applied: map[string]int{}, types: map[EventType]int{}}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	if err := VerifySignature(h.secret, r.Header.Get(HeaderSignature), body, time.Minute); err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil || e.ID != r.Header.Get(HeaderEventID) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.idempotent && h.seen[e.ID] {
+		return
+	}
+
+	h.seen[e.ID] = true
+	h.applied[e.ID]++
+	h.types[e.Type]++
+}
+
+func (h *handler) appliedCount(eventID string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.applied[eventID]
+}
+
+func TestRun(t *testing.T) {
+	h := newHandler("secret", true)
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	report, err := Run(context.Background(), srv.URL, &Options{
+		Secret:        []byte("secret"),
+		Events:        30,
+		DuplicateRate: 0.5,
+		Seed:          7,
+		Concurrency:   4,
+		Applied:       h.appliedCount,
+	})
+	require.NoError(t, err)
+
+	assert.True(t, report.OK(), "%v", report.Check(0))
+	assert.NoError(t, report.Check(time.Minute))
+	assert.Equal(t, 30, report.Events)
+	assert.Positive(t, report.Duplicates)
+	assert.Equal(t, 30+report.Duplicates, report.Deliveries)
+	assert.Equal(t, report.Deliveries, report.Acknowledged)
+	assert.Equal(t, map[int]int{http.StatusOK: report.Deliveries}, report.StatusCodes)
+	assert.Equal(t, int64(report.Deliveries), report.Latency.TotalCount())
+	assert.Positive(t, report.LatencyAt(95))
+	assert.Positive(t, report.Rate())
+
+	assert.Equal(t, map[EventType]int{TransactionCreated: 10, AccountCreated: 10, BalanceUpdated: 10}, h.types)
+}
+
+func TestRunIdempotencyViolation(t *testing.T) {
+	h := newHandler("secret", false)
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	report, err := Run(context.Background(), srv.URL, &Options{
+		Secret:        []byte("secret"),
+		Events:        20,
+		DuplicateRate: 1,
+		Applied:       h.appliedCount,
+	})
+	require.NoError(t, err)
+
+	assert.False(t, report.OK())
+	require.Len(t, report.Violations, 20)
+	assert.Equal(t, 2, report.Violations[0].Applied)
+	assert.Contains(t, report.Check(0).Error(), "applied 2 times")
+}
+
+func TestRunFailures(t *testing.T) {
+	srv := httptest.NewServer(newHandler("other", true))
+	defer srv.Close()
+
+	report, err := Run(context.Background(), srv.URL, &Options{Secret: []byte("secret"), Events: 5, DuplicateRate: -1, Rate: 1000})
+	require.NoError(t, err)
+
+	assert.Zero(t, report.Duplicates)
+	assert.Zero(t, report.Acknowledged)
+	require.Len(t, report.Failures, 5)
+	assert.Equal(t, http.StatusUnauthorized, report.Failures[0].StatusCode)
+	assert.Contains(t, report.Failures[0].Error(), "returned status 401")
+
+	_, err = Run(context.Background(), srv.URL, nil)
+	assert.Error(t, err, "a secret or a signer is required")
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	secret := []byte("secret")
+
+	signed := Sign(secret, time.Now(), body)
+	require.NoError(t, VerifySignature(secret, signed, body, time.Minute))
+
+	assert.ErrorIs(t, VerifySignature([]byte("other"), signed, body, 0), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature(secret, signed, []byte(`{"id":"2"}`), 0), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature(secret, "garbage", body, 0), ErrInvalidSignature)
+
+	old := Sign(secret, time.Now().Add(-time.Hour), body)
+	assert.ErrorIs(t, VerifySignature(secret, old, body, time.Minute), ErrInvalidSignature)
+	assert.NoError(t, VerifySignature(secret, old, body, 0))
+}